@@ -0,0 +1,45 @@
+#!/bin/sh
+# Joins this node to EdgeNet with the one-time join token of its node contribution, without
+# EdgeNet logging in to the node. The token is in the secret named in the status of the node
+# contribution, along with the command running this script:
+#
+#   curl -sfL <script URL> | sudo sh -s -- --server <registration server> --token <token>
+#
+# The node needs containerd, kubelet, and kubeadm of the version of the cluster beforehand.
+set -e
+
+server=""
+token=""
+while [ $# -gt 0 ]; do
+  case "$1" in
+    --server) server="$2"; shift 2 ;;
+    --token) token="$2"; shift 2 ;;
+    *) echo "unknown option $1" >&2; exit 2 ;;
+  esac
+done
+if [ -z "$server" ] || [ -z "$token" ]; then
+  echo "--server and --token are required" >&2
+  exit 2
+fi
+if [ "$(id -u)" -ne 0 ]; then
+  echo "the node joins as root, run the script with sudo" >&2
+  exit 1
+fi
+if ! command -v kubeadm > /dev/null; then
+  echo "kubeadm is not installed, install containerd, kubelet, and kubeadm first" >&2
+  exit 1
+fi
+
+# The token is only good once, the command holds a short-lived bootstrap token instead
+reply=$(curl -sf -X POST -H "Content-Type: application/json" -d "{\"token\": \"$token\"}" "$server/v1/nodecontributions/join") || {
+  echo "the join token was refused, it may be expired or already used" >&2
+  exit 1
+}
+command=$(echo "$reply" | sed -n 's/.*"command":"\([^"]*\)".*/\1/p')
+if [ -z "$command" ]; then
+  echo "no join command in the reply of $server" >&2
+  exit 1
+fi
+
+kubeadm reset -f
+$command
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/EdgeNet-project/edgenet/pkg/apidocs"
+	appsv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/apps/v1alpha"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	federationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/federation/v1alpha"
+	networkingv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/networking/v1alpha"
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// The API packages to document, relative to the APIs directory, with their examples
+var packages = []struct {
+	dir      string
+	examples func() []runtime.Object
+}{
+	{"apps/v1alpha", appsv1alpha.Examples},
+	{"core/v1alpha", corev1alpha.Examples},
+	{"federation/v1alpha", federationv1alpha.Examples},
+	{"networking/v1alpha", networkingv1alpha.Examples},
+	{"registration/v1alpha", registrationv1alpha.Examples},
+}
+
+// apidocs generates the API reference and the example manifests of every kind out of the Go
+// types, it is run by the build so that the reference is published along with the images.
+func main() {
+	apis := flag.String("apis", "pkg/apis", "Directory of the API packages")
+	out := flag.String("out", "docs/reference", "Directory to write the reference into")
+	flag.Parse()
+
+	index := &bytes.Buffer{}
+	fmt.Fprintf(index, "# API reference\n\n<!-- Generated by cmd/apidocs out of pkg/apis, do not edit -->\n\n")
+	for _, pkg := range packages {
+		group, err := apidocs.Parse(filepath.Join(*apis, pkg.dir))
+		if err != nil {
+			fail(err)
+		}
+		if err := group.SetExamples(pkg.examples()); err != nil {
+			fail(err)
+		}
+		reference := &bytes.Buffer{}
+		if err := group.Render(reference); err != nil {
+			fail(err)
+		}
+		fileName := fmt.Sprintf("%s_%s.md", strings.Split(group.Name, ".")[0], group.Version)
+		write(filepath.Join(*out, fileName), reference.Bytes())
+		fmt.Fprintf(index, "- [%s/%s](%s)\n", group.Name, group.Version, fileName)
+
+		for _, kind := range group.Kinds {
+			write(filepath.Join(*out, "examples", group.Name, group.Version, fmt.Sprintf("%s.yaml", strings.ToLower(kind.Name))), []byte(kind.Example))
+		}
+	}
+	write(filepath.Join(*out, "README.md"), index.Bytes())
+}
+
+func write(path string, content []byte) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		fail(err)
+	}
+	if err := ioutil.WriteFile(path, content, 0644); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
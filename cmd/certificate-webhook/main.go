@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+	"github.com/EdgeNet-project/edgenet/pkg/usercert"
+
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/klog/v2"
+)
+
+// Serves the admission webhook that rejects the requests made with a revoked client certificate,
+// the API server calls it over TLS
+func main() {
+	klog.InitFlags(nil)
+	address := flag.String("address", ":8443", "Address to serve the certificate webhook on.")
+	certFile := flag.String("tls-cert-file", "/etc/webhook/certs/tls.crt", "Path to the TLS certificate.")
+	keyFile := flag.String("tls-private-key-file", "/etc/webhook/certs/tls.key", "Path to the TLS private key.")
+	flag.Parse()
+
+	stopCh := signals.SetupSignalHandler()
+	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the clientset")
+		panic(err.Error())
+	}
+
+	// Only the namespace of the revocation list is watched
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeclientset, 0, kubeinformers.WithNamespace(usercert.RevocationList.Namespace))
+
+	webhook := usercert.NewWebhook(kubeInformerFactory.Core().V1().ConfigMaps())
+
+	kubeInformerFactory.Start(stopCh)
+	if err := webhook.WaitForCacheSync(stopCh); err != nil {
+		klog.Fatalf("Error running certificate webhook: %s", err.Error())
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/validate", webhook.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	klog.Fatal(http.ListenAndServeTLS(*address, *certFile, *keyFile, mux))
+}
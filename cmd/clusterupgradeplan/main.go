@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+
+	"k8s.io/klog/v2"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/controller/core/v1alpha/clusterupgradeplan"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+)
+
+func main() {
+	klog.InitFlags(nil)
+	flag.Parse()
+
+	stopCh := signals.SetupSignalHandler()
+	// TODO: Pass an argument to select using kubeconfig or service account for clients
+	// bootstrap.SetKubeConfig()
+	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the clientset")
+		panic(err.Error())
+	}
+	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the EdgeNet clientset")
+		panic(err.Error())
+	}
+	// Start the controller to provide the functionalities of clusterupgradeplan resource
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, 0)
+
+	controller := clusterupgradeplan.NewController(kubeclientset,
+		edgenetclientset,
+		edgenetInformerFactory.Core().V1alpha().ClusterUpgradePlans(),
+		edgenetInformerFactory.Core().V1alpha().NodeContributions())
+
+	edgenetInformerFactory.Start(stopCh)
+
+	if err = controller.Run(2, stopCh); err != nil {
+		klog.Fatalf("Error running controller: %s", err.Error())
+	}
+}
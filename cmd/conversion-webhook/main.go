@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+
+	"github.com/EdgeNet-project/edgenet/pkg/conversion"
+
+	"k8s.io/klog/v2"
+)
+
+// Serves the conversion webhook of the custom resources served in several versions, the API
+// server calls it over TLS
+func main() {
+	klog.InitFlags(nil)
+	address := flag.String("address", ":8443", "Address to serve the conversion webhook on.")
+	certFile := flag.String("tls-cert-file", "/etc/webhook/certs/tls.crt", "Path to the TLS certificate.")
+	keyFile := flag.String("tls-private-key-file", "/etc/webhook/certs/tls.key", "Path to the TLS private key.")
+	flag.Parse()
+
+	mux := http.NewServeMux()
+	mux.Handle("/convert", conversion.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	klog.Fatal(http.ListenAndServeTLS(*address, *certFile, *keyFile, mux))
+}
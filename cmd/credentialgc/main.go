@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/controller/core/v1alpha/credentialgc"
+	"github.com/EdgeNet-project/edgenet/pkg/credential"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+)
+
+func main() {
+	klog.InitFlags(nil)
+	resync := flag.Duration("resync", 10*time.Minute, "Interval at which the credentials are checked against the role bindings.")
+	flag.Parse()
+
+	stopCh := signals.SetupSignalHandler()
+	// TODO: Pass an argument to select using kubeconfig or service account for clients
+	// bootstrap.SetKubeConfig()
+	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the clientset")
+		panic(err.Error())
+	}
+	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the EdgeNet clientset")
+		panic(err.Error())
+	}
+	// Only the secrets holding the generated credentials are watched
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeclientset, *resync,
+		kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = credential.LabelCredential
+		}))
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, 0)
+
+	controller := credentialgc.NewController(kubeclientset,
+		kubeInformerFactory.Core().V1().Secrets(),
+		edgenetInformerFactory.Core().V1alpha().Tenants())
+
+	kubeInformerFactory.Start(stopCh)
+	edgenetInformerFactory.Start(stopCh)
+
+	if err = controller.Run(2, stopCh); err != nil {
+		klog.Fatalf("Error running controller: %s", err.Error())
+	}
+}
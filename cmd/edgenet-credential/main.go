@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/EdgeNet-project/edgenet/pkg/credential"
+)
+
+// This binary is run by kubectl as an exec credential plugin, it prints the credential of the
+// user on the standard output
+func main() {
+	home, _ := os.UserHomeDir()
+	server := flag.String("server", "", "URL of the registration server")
+	user := flag.String("user", "", "Name of the user in EdgeNet")
+	refreshToken := flag.String("refresh-token", filepath.Join(home, ".edgenet", "refresh-token"), "File holding the refresh token of the user")
+	cacheDir := flag.String("cache-dir", filepath.Join(home, ".kube", "cache", "edgenet"), "Directory where the tokens are cached until they expire")
+	flag.Parse()
+
+	if *server == "" || *user == "" {
+		fmt.Fprintln(os.Stderr, "--server and --user are required")
+		os.Exit(1)
+	}
+	plugin := credential.Plugin{Server: *server, User: *user, RefreshTokenPath: *refreshToken, CacheDir: *cacheDir}
+	execCredential, err := plugin.Credential()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Couldn't obtain a token from %s: %s\n", *server, err)
+		os.Exit(1)
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(execCredential); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+
+	"github.com/EdgeNet-project/edgenet/pkg/controller/registration/v1alpha/extensionrequest"
+
+	"k8s.io/klog/v2"
+)
+
+// Serves the admission webhook that rejects the extension requests made on behalf of another user
+// or changing their spec, the API server calls it over TLS
+func main() {
+	klog.InitFlags(nil)
+	address := flag.String("address", ":8443", "Address to serve the extension request webhook on.")
+	certFile := flag.String("tls-cert-file", "/etc/webhook/certs/tls.crt", "Path to the TLS certificate.")
+	keyFile := flag.String("tls-private-key-file", "/etc/webhook/certs/tls.key", "Path to the TLS private key.")
+	flag.Parse()
+
+	webhook := extensionrequest.NewWebhook()
+
+	mux := http.NewServeMux()
+	mux.Handle("/validate-extensionrequests", webhook.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	klog.Fatal(http.ListenAndServeTLS(*address, *certFile, *keyFile, mux))
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/controller/federation/v1alpha/federatedtenant"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+)
+
+func main() {
+	klog.InitFlags(nil)
+	stopCh := signals.SetupSignalHandler()
+	// TODO: Pass an argument to select using kubeconfig or service account for clients
+	// bootstrap.SetKubeConfig()
+	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the clientset")
+		panic(err.Error())
+	}
+	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the EdgeNet clientset")
+		panic(err.Error())
+	}
+	// Start the controller to provide the functionalities of federatedtenant resource,
+	// the resync period lets the workload clusters catch up with the manager cluster
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, time.Minute*5)
+
+	controller := federatedtenant.NewController(signals.ContextFor(stopCh),
+		kubeclientset,
+		edgenetclientset,
+		federatedtenant.NewClusterClient,
+		edgenetInformerFactory.Federation().V1alpha().FederatedTenants(),
+		edgenetInformerFactory.Core().V1alpha().Tenants())
+
+	edgenetInformerFactory.Start(stopCh)
+
+	if err = controller.Run(2, stopCh); err != nil {
+		klog.Fatalf("Error running controller: %s", err.Error())
+	}
+}
@@ -0,0 +1,393 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/backup"
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/cli"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	"github.com/EdgeNet-project/edgenet/pkg/signature"
+	"github.com/EdgeNet-project/edgenet/pkg/usercert"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const usage = `Usage: kubectl edgenet [--kubeconfig=PATH] COMMAND [OPTIONS]
+
+Commands:
+  request tenant NAME          Request a tenant, it awaits the approval of the administrators
+  approve request NAME         Approve a tenant request
+  list tenants                 List the tenants with their state
+  create subnamespace NAME     Create a workspace in the namespace of a tenant
+  get kubeconfig               Print the kubeconfig of a user for the current cluster
+  show quota TENANT            Show the quota usage of each namespace of a tenant
+  show tree NAMESPACE          Show the subnamespaces nested below a namespace as a tree
+  issue certificate USER       Issue a client certificate to a user of a tenant
+  list certificates TENANT     List the client certificates issued to the users of a tenant
+  revoke certificate NAME      Revoke a client certificate, such as one on a lost laptop
+  reissue certificate NAME     Revoke a client certificate and issue a new one with a fresh key
+  export backup FILE           Export the tenants, quotas, subnamespaces, requests, and node contributions
+  restore backup FILE          Restore the objects of a backup on the current cluster
+
+Run kubectl edgenet COMMAND --help for the options of a command.
+`
+
+// Installed in the PATH as kubectl-edgenet, kubectl runs it for kubectl edgenet. It runs with
+// the credentials of the kubeconfig.
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	bootstrap.SetKubeConfig()
+	args := flag.Args()
+	if len(args) < 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	var err error
+	switch command := strings.Join(args[:2], " "); command {
+	case "request tenant":
+		err = requestTenant(ctx, args[2:])
+	case "approve request":
+		err = approveRequest(ctx, args[2:])
+	case "list tenants":
+		err = listTenants(ctx, args[2:])
+	case "create subnamespace":
+		err = createSubNamespace(ctx, args[2:])
+	case "get kubeconfig":
+		err = getKubeconfig(args[2:])
+	case "show quota":
+		err = showQuota(ctx, args[2:])
+	case "show tree":
+		err = showTree(ctx, args[2:])
+	case "issue certificate":
+		err = issueCertificate(ctx, args[2:])
+	case "list certificates":
+		err = listCertificates(ctx, args[2:])
+	case "revoke certificate":
+		err = revokeCertificate(ctx, args[2:])
+	case "reissue certificate":
+		err = reissueCertificate(ctx, args[2:])
+	case "export backup":
+		err = exportBackup(ctx, args[2:])
+	case "restore backup":
+		err = restoreBackup(ctx, args[2:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", command)
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// parse parses the options of a command and returns its only argument, if it takes one
+func parse(flags *flag.FlagSet, args []string, argument string) string {
+	// The argument may come before the options as well as after them
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		flags.Parse(args[1:])
+		return args[0]
+	}
+	flags.Parse(args)
+	if argument == "" {
+		return ""
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "%s is required\n", argument)
+		flags.Usage()
+		os.Exit(2)
+	}
+	return flags.Arg(0)
+}
+
+func requestTenant(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("request tenant", flag.ExitOnError)
+	spec := registrationv1alpha.TenantRequestSpec{}
+	flags.StringVar(&spec.FullName, "full-name", "", "Full name of the tenant")
+	flags.StringVar(&spec.ShortName, "short-name", "", "Shortened name of the tenant")
+	flags.StringVar(&spec.URL, "url", "", "Website of the tenant")
+	flags.StringVar(&spec.Address.Street, "street", "", "Street of the tenant")
+	flags.StringVar(&spec.Address.ZIP, "zip", "", "ZIP code of the tenant")
+	flags.StringVar(&spec.Address.City, "city", "", "City of the tenant")
+	flags.StringVar(&spec.Address.Region, "region", "", "Region of the tenant")
+	flags.StringVar(&spec.Address.Country, "country", "", "Country of the tenant")
+	flags.StringVar(&spec.Contact.Handle, "handle", "", "Handle of the contact person")
+	flags.StringVar(&spec.Contact.FirstName, "first-name", "", "First name of the contact person")
+	flags.StringVar(&spec.Contact.LastName, "last-name", "", "Last name of the contact person")
+	flags.StringVar(&spec.Contact.Email, "email", "", "Email address of the contact person")
+	flags.StringVar(&spec.Contact.Phone, "phone", "", "Phone number of the contact person")
+	flags.BoolVar(&spec.ClusterNetworkPolicy, "cluster-network-policy", false, "Apply the cluster-level network policies to the tenant namespaces")
+	flags.StringVar(&spec.Invitation, "invitation", "", "Invitation token, when the cluster only takes tenant requests by invitation")
+	resources := flags.String("resources", "", "Requested resource allocation, such as cpu=8,memory=8Gi")
+	name := parse(flags, args, "tenant name")
+	if spec.FullName == "" || spec.Contact.Email == "" {
+		return fmt.Errorf("--full-name and --email are required")
+	}
+	if *resources != "" {
+		allocation, err := cli.ParseAllocation(strings.Split(*resources, ","))
+		if err != nil {
+			return err
+		}
+		spec.ResourceAllocation = allocation
+	}
+
+	_, edgenetclientset := clientsets()
+	if err := cli.RequestTenant(ctx, edgenetclientset, name, spec); err != nil {
+		return err
+	}
+	fmt.Printf("Tenant request %s made, it awaits the approval of the administrators\n", name)
+	return nil
+}
+
+func approveRequest(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("approve request", flag.ExitOnError)
+	approver := flags.String("approver", "", "Administrator who approves the request, the user of the current context if empty")
+	name := parse(flags, args, "tenant request name")
+	if *approver == "" {
+		config, err := loadKubeconfig()
+		if err != nil {
+			return err
+		}
+		if currentContext, ok := config.Contexts[config.CurrentContext]; ok {
+			*approver = currentContext.AuthInfo
+		}
+	}
+
+	_, edgenetclientset := clientsets()
+	if err := cli.ApproveRequest(ctx, edgenetclientset, name, *approver); err != nil {
+		return err
+	}
+	fmt.Printf("Tenant request %s approved by %s\n", name, *approver)
+	return nil
+}
+
+func listTenants(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("list tenants", flag.ExitOnError)
+	parse(flags, args, "")
+
+	_, edgenetclientset := clientsets()
+	return cli.ListTenants(ctx, edgenetclientset, os.Stdout)
+}
+
+func createSubNamespace(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("create subnamespace", flag.ExitOnError)
+	namespace := flags.String("n", "", "Namespace of the tenant to create the subnamespace in")
+	resources := flags.String("resources", "", "Resource allocation of the subnamespace, such as cpu=2,memory=2Gi")
+	ownerEmail := flags.String("owner", "", "Email address of the owner of the subnamespace, none if empty")
+	signingKey := flags.String("signing-key", "", "PEM file of the private key of the tenant to sign the subnamespace with, unsigned if empty")
+	keyName := flags.String("key-name", "", "Name the tenant lists the public key of the signing key under")
+	name := parse(flags, args, "subnamespace name")
+	if *namespace == "" || *resources == "" {
+		return fmt.Errorf("-n and --resources are required")
+	}
+	allocation, err := cli.ParseAllocation(strings.Split(*resources, ","))
+	if err != nil {
+		return err
+	}
+	var owner *corev1alpha.Contact
+	if *ownerEmail != "" {
+		owner = &corev1alpha.Contact{Email: *ownerEmail}
+	}
+	var signer *signature.Signer
+	if *signingKey != "" {
+		if *keyName == "" {
+			return fmt.Errorf("--key-name is required with --signing-key")
+		}
+		privateKeyPEM, err := ioutil.ReadFile(*signingKey)
+		if err != nil {
+			return err
+		}
+		if signer, err = signature.NewSigner(*keyName, privateKeyPEM); err != nil {
+			return err
+		}
+	}
+
+	_, edgenetclientset := clientsets()
+	if err := cli.CreateSubNamespace(ctx, edgenetclientset, *namespace, name, allocation, owner, signer); err != nil {
+		return err
+	}
+	fmt.Printf("Subnamespace %s created in %s\n", name, *namespace)
+	return nil
+}
+
+func getKubeconfig(args []string) error {
+	flags := flag.NewFlagSet("get kubeconfig", flag.ExitOnError)
+	user := flags.String("user", "", "Name of the user in EdgeNet")
+	server := flags.String("registration-server", "", "URL of the registration server that issues the tokens")
+	parse(flags, args, "")
+
+	config, err := loadKubeconfig()
+	if err != nil {
+		return err
+	}
+	kubeconfig, err := cli.Kubeconfig(config, *user, *server)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(kubeconfig)
+	return err
+}
+
+func showQuota(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("show quota", flag.ExitOnError)
+	tenant := parse(flags, args, "tenant name")
+
+	kubeclientset, _ := clientsets()
+	return cli.ShowQuota(ctx, kubeclientset, tenant, os.Stdout)
+}
+
+func showTree(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("show tree", flag.ExitOnError)
+	namespace := parse(flags, args, "namespace")
+
+	_, edgenetclientset := clientsets()
+	return cli.ShowTree(ctx, edgenetclientset, namespace, os.Stdout)
+}
+
+func issueCertificate(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("issue certificate", flag.ExitOnError)
+	namespace := flags.String("n", "", "Namespace of the tenant of the user")
+	user := parse(flags, args, "user name")
+	if *namespace == "" {
+		return fmt.Errorf("-n is required")
+	}
+
+	kubeclientset, _ := clientsets()
+	secret, err := usercert.Issue(ctx, kubeclientset, *namespace, user)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Certificate of %s issued in secret %s/%s\n", user, *namespace, secret.GetName())
+	return nil
+}
+
+func listCertificates(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("list certificates", flag.ExitOnError)
+	tenant := parse(flags, args, "tenant name")
+
+	kubeclientset, _ := clientsets()
+	return cli.ListCertificates(ctx, kubeclientset, tenant, os.Stdout)
+}
+
+func revokeCertificate(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("revoke certificate", flag.ExitOnError)
+	namespace := flags.String("n", "", "Namespace of the tenant of the certificate")
+	name := parse(flags, args, "certificate name")
+	if *namespace == "" {
+		return fmt.Errorf("-n is required")
+	}
+
+	kubeclientset, _ := clientsets()
+	if err := usercert.Revoke(ctx, kubeclientset, *namespace, name); err != nil {
+		return err
+	}
+	fmt.Printf("Certificate %s revoked\n", name)
+	return nil
+}
+
+func reissueCertificate(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("reissue certificate", flag.ExitOnError)
+	namespace := flags.String("n", "", "Namespace of the tenant of the certificate")
+	name := parse(flags, args, "certificate name")
+	if *namespace == "" {
+		return fmt.Errorf("-n is required")
+	}
+
+	kubeclientset, _ := clientsets()
+	secret, err := usercert.Reissue(ctx, kubeclientset, *namespace, name)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Certificate %s revoked and reissued in secret %s/%s\n", name, *namespace, secret.GetName())
+	return nil
+}
+
+func exportBackup(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("export backup", flag.ExitOnError)
+	file := parse(flags, args, "archive file")
+
+	archive, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	kubeclientset, edgenetclientset := clientsets()
+	if err := backup.Export(ctx, kubeclientset, edgenetclientset, archive); err != nil {
+		archive.Close()
+		os.Remove(file)
+		return err
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Backup exported to %s\n", file)
+	return nil
+}
+
+func restoreBackup(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("restore backup", flag.ExitOnError)
+	timeout := flags.Duration("timeout", 5*time.Minute, "Time to wait for each namespace to be created by the controllers")
+	file := parse(flags, args, "archive file")
+
+	archive, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+	kubeclientset, edgenetclientset := clientsets()
+	result, err := backup.Import(ctx, kubeclientset, edgenetclientset, archive, *timeout)
+	kinds := []string{}
+	for kind := range result.Restored {
+		kinds = append(kinds, kind)
+	}
+	for kind := range result.Skipped {
+		if _, ok := result.Restored[kind]; !ok {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		fmt.Printf("%d %s restored, %d skipped\n", result.Restored[kind], kind, result.Skipped[kind])
+	}
+	return err
+}
+
+// loadKubeconfig reads the kubeconfig with the certificate authorities inlined
+func loadKubeconfig() (*clientcmdapi.Config, error) {
+	config, err := clientcmd.LoadFromFile(flag.Lookup("kubeconfig").Value.String())
+	if err != nil {
+		return nil, err
+	}
+	if err := clientcmdapi.FlattenConfig(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// clientsets creates the clientsets with the credentials of the kubeconfig
+func clientsets() (*kubernetes.Clientset, *clientset.Clientset) {
+	kubeclientset, err := bootstrap.CreateClientset("kubeconfig")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("kubeconfig")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	return kubeclientset, edgenetclientset
+}
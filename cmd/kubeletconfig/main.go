@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/node/kubelet"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	"k8s.io/klog/v2"
+)
+
+// Runs on each node as a node agent, and restarts the kubelet with the settings of the node
+// configuration the node takes
+func main() {
+	klog.InitFlags(nil)
+	nodeName := flag.String("node", os.Getenv("NODE_NAME"), "Name of the node the agent runs on.")
+	flag.StringVar(&kubelet.ConfigPath, "kubelet-config", kubelet.ConfigPath, "Configuration file of the kubelet, as seen from the agent.")
+	flag.DurationVar(&kubelet.Interval, "interval", kubelet.Interval, "Time between two checks of the settings of the node.")
+	flag.Parse()
+	if *nodeName == "" {
+		klog.Fatal("The name of the node is missing, set it with --node or NODE_NAME")
+	}
+
+	stopCh := signals.SetupSignalHandler()
+	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the clientset")
+		panic(err.Error())
+	}
+
+	agent := kubelet.NewAgent(kubeclientset, *nodeName)
+	if err = agent.Run(stopCh); err != nil {
+		klog.Fatalf("Error running agent: %s", err.Error())
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+
+	"k8s.io/klog/v2"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/controller/core/v1/nodeagent"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+)
+
+func main() {
+	klog.InitFlags(nil)
+	flag.StringVar(&nodeagent.Release, "release", nodeagent.Release, "Release of EdgeNet, the agent images without a tag are upgraded along with it.")
+	flag.StringVar(&nodeagent.Namespace, "namespace", nodeagent.Namespace, "Namespace the agents run in.")
+	flag.StringVar(&nodeagent.CatalogPath, "catalog", "", "File of the node agent catalog, the built-in catalog is used when empty.")
+	flag.DurationVar(&nodeagent.Interval, "interval", nodeagent.Interval, "Time between two reconciles of the agents.")
+	flag.Parse()
+
+	stopCh := signals.SetupSignalHandler()
+	// TODO: Pass an argument to select using kubeconfig or service account for clients
+	// bootstrap.SetKubeConfig()
+	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the clientset")
+		panic(err.Error())
+	}
+
+	controller := nodeagent.NewController(kubeclientset)
+
+	if err = controller.Run(stopCh); err != nil {
+		klog.Fatalf("Error running controller: %s", err.Error())
+	}
+}
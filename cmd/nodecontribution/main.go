@@ -2,7 +2,6 @@ package main
 
 import (
 	"flag"
-	"log"
 	"time"
 
 	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
@@ -11,11 +10,16 @@ import (
 	"github.com/EdgeNet-project/edgenet/pkg/signals"
 
 	kubeinformers "k8s.io/client-go/informers"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 func main() {
 	klog.InitFlags(nil)
+	flag.DurationVar(&nodecontribution.NotReadyGracePeriod, "not-ready-grace-period", nodecontribution.NotReadyGracePeriod, "Time a node may stay not ready before it is reported down.")
+	flag.DurationVar(&nodecontribution.DigestInterval, "digest-interval", nodecontribution.DigestInterval, "Gather the nodes going down within the interval into a single email, the nodes are reported one by one if zero.")
+	flag.DurationVar(&nodecontribution.JoinTokenTTL, "join-token-ttl", nodecontribution.JoinTokenTTL, "Time the contributors have to run the bootstrap script with the join token of a node.")
+	flag.StringVar(&nodecontribution.RegistrationServer, "registration-server", nodecontribution.RegistrationServer, "URL of the registration API that exchanges the join tokens.")
+	flag.StringVar(&nodecontribution.JoinScriptURL, "join-script-url", nodecontribution.JoinScriptURL, "URL of the bootstrap script the contributors run with the join token.")
 	flag.Parse()
 
 	stopCh := signals.SetupSignalHandler()
@@ -23,12 +27,12 @@ func main() {
 	// bootstrap.SetKubeConfig()
 	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
 	if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't create the clientset")
 		panic(err.Error())
 	}
 	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("serviceaccount")
 	if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't create the EdgeNet clientset")
 		panic(err.Error())
 	}
 	// Start the controller to provide the functionalities of nodecontribution resource
@@ -21,7 +21,6 @@ package main
 
 import (
 	"flag"
-	"log"
 	"os"
 	"strings"
 	"time"
@@ -30,7 +29,7 @@ import (
 	"github.com/EdgeNet-project/edgenet/pkg/controller/core/v1/nodelabeler"
 	"github.com/EdgeNet-project/edgenet/pkg/signals"
 	kubeinformers "k8s.io/client-go/informers"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 func main() {
@@ -42,12 +41,12 @@ func main() {
 	// bootstrap.SetKubeConfig()
 	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
 	if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't create the clientset")
 		panic(err.Error())
 	}
 	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("serviceaccount")
 	if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't create the EdgeNet clientset")
 		panic(err.Error())
 	}
 
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/controller/networking/v1alpha/nodelink"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+)
+
+func main() {
+	klog.InitFlags(nil)
+	flag.DurationVar(&nodelink.Interval, "interval", nodelink.Interval, "Time between two measurements of the links.")
+	flag.IntVar(&nodelink.Probes, "probes", nodelink.Probes, "Number of probes sent at each measurement of a link.")
+	flag.IntVar(&nodelink.ProbePort, "port", nodelink.ProbePort, "Port of the other nodes the probes connect to.")
+	flag.DurationVar(&nodelink.ProbeTimeout, "timeout", nodelink.ProbeTimeout, "Time after which a probe is counted as lost.")
+	flag.Parse()
+
+	nodeName := strings.TrimSpace(os.Getenv("NODE_NAME"))
+	if nodeName == "" || nodelink.Probes < 1 {
+		klog.Fatal("The agent requires the NODE_NAME environment variable and at least one probe")
+	}
+
+	stopCh := signals.SetupSignalHandler()
+	// TODO: Pass an argument to select using kubeconfig or service account for clients
+	// bootstrap.SetKubeConfig()
+	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the clientset")
+		panic(err.Error())
+	}
+	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the EdgeNet clientset")
+		panic(err.Error())
+	}
+
+	controller := nodelink.NewController(kubeclientset, edgenetclientset, nodeName)
+
+	if err = controller.Run(stopCh); err != nil {
+		klog.Fatalf("Error running controller: %s", err.Error())
+	}
+}
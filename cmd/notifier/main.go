@@ -18,32 +18,45 @@ package main
 
 import (
 	"flag"
-	"log"
+	"net/http"
 	"time"
 
 	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
 	"github.com/EdgeNet-project/edgenet/pkg/controller/core/v1alpha/notifier"
 	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/mailer"
+	"github.com/EdgeNet-project/edgenet/pkg/metrics"
 	"github.com/EdgeNet-project/edgenet/pkg/signals"
 
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 func main() {
 	klog.InitFlags(nil)
+	metricsAddress := flag.String("metrics-address", ":9090", "Address to serve the delivery metrics on.")
+	// The admin endpoint resends emails, it is only reachable through a port forward by default
+	adminAddress := flag.String("admin-address", "127.0.0.1:9091", "Address to serve the failed deliveries on.")
 	flag.Parse()
 
+	go func() {
+		http.Handle("/metrics", metrics.Handler())
+		klog.Fatal(http.ListenAndServe(*metricsAddress, nil))
+	}()
+	go func() {
+		klog.Fatal(http.ListenAndServe(*adminAddress, mailer.AdminHandler()))
+	}()
+
 	stopCh := signals.SetupSignalHandler()
 	// TODO: Pass an argument to select using kubeconfig or service account for clients
 	// bootstrap.SetKubeConfig()
 	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
 	if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't create the clientset")
 		panic(err.Error())
 	}
 	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("serviceaccount")
 	if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't create the EdgeNet clientset")
 		panic(err.Error())
 	}
 
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/controller/core/v1alpha/operation"
+	"github.com/EdgeNet-project/edgenet/pkg/controller/core/v1alpha/tenant"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/index"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+func main() {
+	klog.InitFlags(nil)
+	flag.Parse()
+
+	stopCh := signals.SetupSignalHandler()
+	// TODO: Pass an argument to select using kubeconfig or service account for clients
+	// bootstrap.SetKubeConfig()
+	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the clientset")
+		panic(err.Error())
+	}
+	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the EdgeNet clientset")
+		panic(err.Error())
+	}
+	// Start the controller to provide the functionalities of operation resource
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, 0)
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeclientset, time.Second*30)
+	namespaceInformer := kubeInformerFactory.Core().V1().Namespaces().Informer()
+	index.AddTenantIndex(namespaceInformer)
+
+	// The workers carrying out the long-running operations by type
+	workers := map[string]operation.Worker{
+		tenant.OperationTeardown: tenant.TeardownWorker{Clientset: kubeclientset, EdgenetClientset: edgenetclientset, Namespaces: namespaceInformer.GetIndexer()},
+	}
+	controller := operation.NewController(kubeclientset,
+		edgenetclientset,
+		edgenetInformerFactory.Core().V1alpha().Operations(),
+		workers)
+
+	edgenetInformerFactory.Start(stopCh)
+	kubeInformerFactory.Start(stopCh)
+	if ok := cache.WaitForCacheSync(stopCh, namespaceInformer.HasSynced); !ok {
+		klog.Fatal("Failed to wait for the namespace cache to sync")
+	}
+
+	if err = controller.Run(2, stopCh); err != nil {
+		klog.Fatalf("Error running controller: %s", err.Error())
+	}
+}
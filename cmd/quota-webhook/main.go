@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/quota"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/klog/v2"
+)
+
+// Serves the admission webhook that rejects the pods beyond the quota of the tenants with a hard
+// block, the API server calls it over TLS
+func main() {
+	klog.InitFlags(nil)
+	address := flag.String("address", ":8443", "Address to serve the quota webhook on.")
+	certFile := flag.String("tls-cert-file", "/etc/webhook/certs/tls.crt", "Path to the TLS certificate.")
+	keyFile := flag.String("tls-private-key-file", "/etc/webhook/certs/tls.key", "Path to the TLS private key.")
+	flag.Parse()
+
+	stopCh := signals.SetupSignalHandler()
+	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the clientset")
+		panic(err.Error())
+	}
+	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the EdgeNet clientset")
+		panic(err.Error())
+	}
+
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeclientset, 0)
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, 0)
+
+	webhook := quota.NewWebhook(
+		kubeInformerFactory.Core().V1().Namespaces(),
+		kubeInformerFactory.Core().V1().Pods(),
+		edgenetInformerFactory.Core().V1alpha().TenantResourceQuotas(),
+	)
+
+	kubeInformerFactory.Start(stopCh)
+	edgenetInformerFactory.Start(stopCh)
+	if err := webhook.WaitForCacheSync(stopCh); err != nil {
+		klog.Fatalf("Error running quota webhook: %s", err.Error())
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/validate-pods", webhook.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	klog.Fatal(http.ListenAndServeTLS(*address, *certFile, *keyFile, mux))
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Compares the live RBAC of the tenants with the archetype that the controllers generate and
+// prints the migration plan, it runs with the credentials of the kubeconfig. After a template
+// change, -apply migrates all tenants in bulk. -snapshot prints the RBAC expected for a tenant
+// instead, which tells whom the tenant grants what when a user cannot access an object.
+func main() {
+	tenant := flag.String("tenant", "", "Tenant to compare, all tenants and the shared cluster roles if empty")
+	apply := flag.Bool("apply", false, "Carry out the migration plan")
+	snapshot := flag.Bool("snapshot", false, "Print the RBAC expected for the tenant as YAML")
+	bootstrap.SetKubeConfig()
+
+	kubeclientset, err := bootstrap.CreateClientset("kubeconfig")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("kubeconfig")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	access.Clientset = kubeclientset
+	access.EdgenetClientset = edgenetclientset
+	// An interrupt leaves the tenant in progress consistent before the tool exits
+	ctx := signals.ContextFor(signals.SetupSignalHandler())
+
+	if *snapshot {
+		if *tenant == "" {
+			fmt.Fprintln(os.Stderr, "-snapshot requires -tenant")
+			os.Exit(2)
+		}
+		tenantObj, err := edgenetclientset.CoreV1alpha().Tenants().Get(ctx, *tenant, metav1.GetOptions{})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		rendered, err := access.TenantSnapshot(ctx, tenantObj)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		if err := printSnapshot(rendered); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		return
+	}
+
+	var plans []access.Plan
+	if *tenant == "" {
+		plans, err = access.DiffAllTenants(ctx)
+	} else {
+		tenantObj, err := edgenetclientset.CoreV1alpha().Tenants().Get(ctx, *tenant, metav1.GetOptions{})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		plan, err := access.DiffTenantRBAC(ctx, tenantObj)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		plans = append(plans, plan)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	pending := 0
+	for _, plan := range plans {
+		tenantName := plan.Tenant
+		if tenantName == "" {
+			tenantName = "*"
+		}
+		for _, change := range plan.Changes {
+			fmt.Printf("%s\t%s\n", tenantName, change)
+		}
+		pending += len(plan.Changes)
+	}
+	if pending == 0 {
+		fmt.Println("RBAC of the tenants matches the archetype")
+		return
+	}
+	if !*apply {
+		os.Exit(1)
+	}
+	for _, plan := range plans {
+		if err := access.ApplyPlan(ctx, plan); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+	fmt.Printf("%d changes applied\n", pending)
+}
+
+// printSnapshot writes the objects of the snapshot as a stream of YAML documents
+func printSnapshot(snapshot access.Snapshot) error {
+	for i, object := range snapshot.Objects() {
+		switch typed := object.(type) {
+		case *rbacv1.ClusterRole:
+			typed.TypeMeta = metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"}
+		case *rbacv1.ClusterRoleBinding:
+			typed.TypeMeta = metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRoleBinding"}
+		case *rbacv1.RoleBinding:
+			typed.TypeMeta = metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "RoleBinding"}
+		}
+		out, err := yaml.Marshal(object)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Println("---")
+		}
+		fmt.Print(string(out))
+	}
+	return nil
+}
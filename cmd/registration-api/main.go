@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	"github.com/EdgeNet-project/edgenet/pkg/apiserver"
+	"github.com/EdgeNet-project/edgenet/pkg/approval"
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/node"
+
+	"k8s.io/klog/v2"
+)
+
+// Serves the registration API of the console, the console reaches the cluster through it instead
+// of holding cluster credentials
+func main() {
+	klog.InitFlags(nil)
+	address := flag.String("address", ":8080", "Address to serve the registration API on.")
+	verificationKeyPath := flag.String("verification-key", "", "Path to the key signing the email verification codes, required.")
+	flag.DurationVar(&apiserver.VerificationPeriod, "verification-period", apiserver.VerificationPeriod, "Time the email verification codes are valid.")
+	flag.DurationVar(&node.BootstrapTokenTTL, "bootstrap-token-ttl", node.BootstrapTokenTTL, "Time the bootstrap tokens of the join commands are valid.")
+	approvalKeyPath := flag.String("approval-key", "", "Path to the key verifying the callbacks of the external approval system, they are refused if empty.")
+	flag.DurationVar(&apiserver.SessionDuration, "session-duration", apiserver.SessionDuration, "Time the sessions of the web console last.")
+	flag.DurationVar(&apiserver.SessionTokenTTL, "session-token-ttl", apiserver.SessionTokenTTL, "Time the tokens of the sessions are valid.")
+	tlsCertPath := flag.String("tls-cert-file", "", "Certificate to serve the registration API over TLS with, it is served in clear if empty.")
+	tlsKeyPath := flag.String("tls-key-file", "", "Key of the TLS certificate.")
+	clientCAPath := flag.String("client-ca-file", "", "Certificate authority of the client certificates the users open sessions with, such as the one of the cluster.")
+	flag.Parse()
+
+	// The requests would await the approval with email addresses that nobody can verify
+	if *verificationKeyPath == "" {
+		klog.Fatal("The verification key is required, set --verification-key")
+	}
+	key, err := ioutil.ReadFile(*verificationKeyPath)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't read the verification key")
+		panic(err.Error())
+	}
+	if apiserver.VerificationKey = bytes.TrimSpace(key); len(apiserver.VerificationKey) == 0 {
+		klog.Fatal("The verification key is empty")
+	}
+
+	if *approvalKeyPath != "" {
+		if err := approval.ReadKey(*approvalKeyPath); err != nil {
+			klog.ErrorS(err, "Couldn't read the approval key")
+			panic(err.Error())
+		}
+	}
+
+	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the clientset")
+		panic(err.Error())
+	}
+	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the EdgeNet clientset")
+		panic(err.Error())
+	}
+	access.Clientset = kubeclientset
+	access.EdgenetClientset = edgenetclientset
+
+	server := &http.Server{Addr: *address, Handler: apiserver.Handler(kubeclientset, edgenetclientset)}
+	if *tlsCertPath == "" {
+		klog.Fatal(server.ListenAndServe())
+	}
+	if *clientCAPath != "" {
+		// The users without a certificate open their sessions with their OIDC token
+		clientCA, err := ioutil.ReadFile(*clientCAPath)
+		if err != nil {
+			klog.ErrorS(err, "Couldn't read the client certificate authority")
+			panic(err.Error())
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(clientCA) {
+			klog.Fatal("The client certificate authority holds no certificate")
+		}
+		server.TLSConfig = &tls.Config{ClientAuth: tls.VerifyClientCertIfGiven, ClientCAs: clientCAs}
+	}
+	klog.Fatal(server.ListenAndServeTLS(*tlsCertPath, *tlsKeyPath))
+}
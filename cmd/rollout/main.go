@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/config"
+	"github.com/EdgeNet-project/edgenet/pkg/rollout"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Rolls a template change out over the enabled tenants, a canary wave first and then the
+// following waves, it runs with the credentials of the kubeconfig. The rollout halts when
+// the probes fail for too many tenants.
+func main() {
+	template := flag.String("template", "", "Template to roll out, rbac or policies")
+	canary := flag.String("canary", "", "Comma separated tenants of the canary wave")
+	canarySize := flag.Int("canary-size", 1, "Number of tenants in the canary wave when -canary is empty")
+	waveSize := flag.Int("wave-size", 10, "Number of tenants in each wave after the canary")
+	interval := flag.Duration("interval", 30*time.Second, "Time to let a wave settle before it is probed")
+	maxFailureRatio := flag.Float64("max-failure-ratio", 0.1, "Ratio of failed tenants beyond which the rollout halts")
+	privateRanges := flag.String("private-ranges", strings.Join(access.PrivateRanges(), ","), "Comma separated IPv4 and IPv6 ranges left out of the external traffic of the policies")
+	bootstrap.SetKubeConfig()
+	if err := access.SetPrivateRanges(*privateRanges); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	kubeclientset, err := bootstrap.CreateClientset("kubeconfig")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("kubeconfig")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	access.Clientset = kubeclientset
+	access.EdgenetClientset = edgenetclientset
+	// An interrupt leaves the tenant in progress consistent before the tool exits
+	ctx := signals.ContextFor(signals.SetupSignalHandler())
+
+	var step rollout.Step
+	probes := []rollout.Probe{rollout.OwnerAccessProbe(kubeclientset)}
+	switch *template {
+	case "rbac":
+		// The cluster roles shared by all tenants cannot be rolled out gradually, they go first
+		plan, err := access.DiffClusterRoles(ctx)
+		if err == nil {
+			err = access.ApplyPlan(ctx, plan)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		step = rollout.RBACStep
+		probes = append(probes, rollout.RBACProbe)
+	case "policies":
+		clusterUID, err := config.ClusterUID(ctx, kubeclientset)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		step = rollout.PoliciesStep(clusterUID)
+	default:
+		fmt.Fprintln(os.Stderr, "template must be rbac or policies")
+		os.Exit(2)
+	}
+
+	tenantRaw, err := edgenetclientset.CoreV1alpha().Tenants().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	tenants := []corev1alpha.Tenant{}
+	for _, tenantRow := range tenantRaw.Items {
+		if tenantRow.Spec.Enabled {
+			tenants = append(tenants, tenantRow)
+		}
+	}
+	options := rollout.Options{CanarySize: *canarySize, WaveSize: *waveSize, Interval: *interval, MaxFailureRatio: *maxFailureRatio}
+	if *canary != "" {
+		options.Canary = strings.Split(*canary, ",")
+	}
+	report := rollout.Run(ctx, tenants, step, probes, options)
+	for _, result := range report.Results {
+		outcome := "ok"
+		if result.Err != nil {
+			outcome = result.Err.Error()
+		}
+		fmt.Printf("%d\t%s\t%s\n", result.Wave, result.Tenant, outcome)
+	}
+	if report.Halted {
+		fmt.Printf("Rollout halted: %s, %d tenants pending: %s\n", report.Reason, len(report.Pending), strings.Join(report.Pending, ","))
+		os.Exit(1)
+	}
+	fmt.Printf("Rolled out to %d tenants, %d failed\n", len(report.Results), len(report.Failed()))
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+
+	"k8s.io/klog/v2"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/controller/registration/v1alpha/roster"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+)
+
+func main() {
+	klog.InitFlags(nil)
+	cluster := roster.Cluster{}
+	flag.StringVar(&cluster.Name, "cluster-name", "edgenet", "Name of the cluster in the kubeconfigs of the students")
+	flag.StringVar(&cluster.Server, "cluster-server", "", "Public address of the API server, the kubeconfigs are not generated if empty")
+	caFile := flag.String("cluster-ca", "", "Certificate authority file of the API server")
+	flag.StringVar(&cluster.RegistrationServer, "registration-server", "", "URL of the registration server that issues the tokens")
+	flag.Parse()
+	if *caFile != "" {
+		ca, err := ioutil.ReadFile(*caFile)
+		if err != nil {
+			klog.ErrorS(err, "Couldn't read the certificate authority", "path", *caFile)
+			panic(err.Error())
+		}
+		cluster.CertificateAuthority = ca
+	}
+
+	stopCh := signals.SetupSignalHandler()
+	// TODO: Pass an argument to select using kubeconfig or service account for clients
+	// bootstrap.SetKubeConfig()
+	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the clientset")
+		panic(err.Error())
+	}
+	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the EdgeNet clientset")
+		panic(err.Error())
+	}
+	// Start the controller to provide the functionalities of roster resource
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, 0)
+
+	controller := roster.NewController(signals.ContextFor(stopCh),
+		kubeclientset,
+		edgenetclientset,
+		edgenetInformerFactory.Registration().V1alpha().Rosters(),
+		cluster)
+
+	edgenetInformerFactory.Start(stopCh)
+
+	if err = controller.Run(2, stopCh); err != nil {
+		klog.Fatalf("Error running controller: %s", err.Error())
+	}
+}
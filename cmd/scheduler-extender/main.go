@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/scheduler"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/klog/v2"
+)
+
+// Serves the scheduler extender the kube-scheduler calls to filter and score the nodes of the pods
+func main() {
+	klog.InitFlags(nil)
+	address := flag.String("address", ":8888", "Address to serve the scheduler extender on.")
+	flag.Parse()
+
+	stopCh := signals.SetupSignalHandler()
+	// TODO: Pass an argument to select using kubeconfig or service account for clients
+	// bootstrap.SetKubeConfig()
+	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the clientset")
+		panic(err.Error())
+	}
+	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the EdgeNet clientset")
+		panic(err.Error())
+	}
+
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeclientset, 0)
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, 0)
+
+	extender := scheduler.NewExtender(
+		kubeInformerFactory.Core().V1().Nodes(),
+		kubeInformerFactory.Core().V1().Namespaces(),
+		edgenetInformerFactory.Core().V1alpha().Tenants(),
+		edgenetInformerFactory.Core().V1alpha().NodePools(),
+		edgenetInformerFactory.Core().V1alpha().NodeContributions(),
+	)
+
+	kubeInformerFactory.Start(stopCh)
+	edgenetInformerFactory.Start(stopCh)
+	if err := extender.WaitForCacheSync(stopCh); err != nil {
+		klog.Fatalf("Error running scheduler extender: %s", err.Error())
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", extender.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	klog.Fatal(http.ListenAndServe(*address, mux))
+}
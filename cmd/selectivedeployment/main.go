@@ -18,7 +18,6 @@ package main
 
 import (
 	"flag"
-	"log"
 	"time"
 
 	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
@@ -27,7 +26,7 @@ import (
 	"github.com/EdgeNet-project/edgenet/pkg/signals"
 
 	kubeinformers "k8s.io/client-go/informers"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 func main() {
@@ -39,12 +38,12 @@ func main() {
 	// bootstrap.SetKubeConfig()
 	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
 	if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't create the clientset")
 		panic(err.Error())
 	}
 	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("serviceaccount")
 	if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't create the EdgeNet clientset")
 		panic(err.Error())
 	}
 	// Start the controller to provide the functionalities of selectivedeployment resource
@@ -59,7 +58,8 @@ func main() {
 		kubeInformerFactory.Apps().V1().StatefulSets(),
 		kubeInformerFactory.Batch().V1().Jobs(),
 		kubeInformerFactory.Batch().V1beta1().CronJobs(),
-		edgenetInformerFactory.Apps().V1alpha().SelectiveDeployments())
+		edgenetInformerFactory.Apps().V1alpha().SelectiveDeployments(),
+		edgenetInformerFactory.Networking().V1alpha().NodeLinks())
 
 	kubeInformerFactory.Start(stopCh)
 	edgenetInformerFactory.Start(stopCh)
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/controller/federation/v1alpha/selectivedeploymentanchor"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+)
+
+func main() {
+	klog.InitFlags(nil)
+	rollout := flag.Duration("rollout", 30*time.Second, "Interval between two collections of the rollout status in the workload clusters")
+	flag.Parse()
+
+	stopCh := signals.SetupSignalHandler()
+	// TODO: Pass an argument to select using kubeconfig or service account for clients
+	// bootstrap.SetKubeConfig()
+	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the clientset")
+		panic(err.Error())
+	}
+	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the EdgeNet clientset")
+		panic(err.Error())
+	}
+	// Start the controller to provide the functionalities of selectivedeploymentanchor resource
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, 0)
+
+	controller := selectivedeploymentanchor.NewController(signals.ContextFor(stopCh),
+		kubeclientset,
+		edgenetclientset,
+		selectivedeploymentanchor.NewClusterClient,
+		*rollout,
+		edgenetInformerFactory.Federation().V1alpha().SelectiveDeploymentAnchors(),
+		edgenetInformerFactory.Federation().V1alpha().Clusters(),
+		edgenetInformerFactory.Apps().V1alpha().SelectiveDeployments())
+
+	edgenetInformerFactory.Start(stopCh)
+
+	if err = controller.Run(2, stopCh); err != nil {
+		klog.Fatalf("Error running controller: %s", err.Error())
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/helm"
+	"github.com/EdgeNet-project/edgenet/pkg/simulation"
+)
+
+// Tells a tenant whether a manifest bundle fits in a namespace before applying it,
+// it runs with the credentials of the kubeconfig.
+func main() {
+	filename := flag.String("f", "-", "Manifest bundle to simulate, - reads the standard input")
+	namespace := flag.String("n", "", "Namespace the bundle would be applied to")
+	bootstrap.SetKubeConfig()
+	if *namespace == "" {
+		fmt.Fprintln(os.Stderr, "namespace is required")
+		os.Exit(2)
+	}
+
+	var manifest []byte
+	var err error
+	if *filename == "-" {
+		manifest, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		manifest, err = ioutil.ReadFile(*filename)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	objects, err := helm.Decode(manifest)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	kubeclientset, err := bootstrap.CreateClientset("kubeconfig")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	result, err := simulation.Simulate(context.Background(), kubeclientset, *namespace, objects)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if result.Fits() {
+		fmt.Printf("%d objects fit in namespace %s\n", len(objects), *namespace)
+		return
+	}
+	for _, blocker := range result.Blockers {
+		fmt.Printf("%s\t%s\t%s\n", blocker.Constraint, blocker.Object, blocker.Message)
+	}
+	os.Exit(1)
+}
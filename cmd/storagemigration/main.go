@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/conversion"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+)
+
+// Rewrites the objects of the custom resources in their storage version, so that an older version
+// can be removed from the definitions. It runs with the credentials of the kubeconfig, after the
+// storage version of the definitions is switched.
+func main() {
+	crds := flag.String("crds", "tenants.core.edgenet.io,subnamespaces.core.edgenet.io,tenantresourcequotas.core.edgenet.io", "Custom resource definitions to migrate, separated by commas")
+	bootstrap.SetKubeConfig()
+
+	dynamicclient, err := bootstrap.CreateDynamicClient("kubeconfig")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	ctx := signals.ContextFor(signals.SetupSignalHandler())
+
+	for _, crd := range strings.Split(*crds, ",") {
+		migrated, err := conversion.MigrateStorage(ctx, dynamicclient, crd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %d objects migrated before %v\n", crd, migrated, err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s: %d objects migrated\n", crd, migrated)
+	}
+}
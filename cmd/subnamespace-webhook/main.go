@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/hierarchy"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/klog/v2"
+)
+
+// Serves the admission webhook that rejects the subnamespaces nesting the workspaces too deep or
+// forming a cycle, the API server calls it over TLS
+func main() {
+	klog.InitFlags(nil)
+	address := flag.String("address", ":8443", "Address to serve the subnamespace webhook on.")
+	certFile := flag.String("tls-cert-file", "/etc/webhook/certs/tls.crt", "Path to the TLS certificate.")
+	keyFile := flag.String("tls-private-key-file", "/etc/webhook/certs/tls.key", "Path to the TLS private key.")
+	maxDepth := flag.Int("max-depth", hierarchy.DefaultMaxDepth, "Number of levels the workspaces nest below the core namespace of a tenant, unbounded if 0.")
+	flag.Parse()
+
+	stopCh := signals.SetupSignalHandler()
+	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the clientset")
+		panic(err.Error())
+	}
+
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeclientset, 0)
+
+	webhook := hierarchy.NewWebhook(kubeInformerFactory.Core().V1().Namespaces(), *maxDepth)
+
+	kubeInformerFactory.Start(stopCh)
+	if err := webhook.WaitForCacheSync(stopCh); err != nil {
+		klog.Fatalf("Error running subnamespace webhook: %s", err.Error())
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/validate-subnamespaces", webhook.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	klog.Fatal(http.ListenAndServeTLS(*address, *certFile, *keyFile, mux))
+}
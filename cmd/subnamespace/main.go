@@ -2,7 +2,6 @@ package main
 
 import (
 	"flag"
-	"log"
 	"time"
 
 	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
@@ -11,7 +10,7 @@ import (
 	"github.com/EdgeNet-project/edgenet/pkg/signals"
 
 	kubeinformers "k8s.io/client-go/informers"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 func main() {
@@ -23,19 +22,20 @@ func main() {
 	// bootstrap.SetKubeConfig()
 	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
 	if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't create the clientset")
 		panic(err.Error())
 	}
 	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("serviceaccount")
 	if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't create the EdgeNet clientset")
 		panic(err.Error())
 	}
 	// Start the controller to provide the functionalities of subnamespace resource
 	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeclientset, time.Second*30)
 	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, 0)
 
-	controller := subnamespace.NewController(kubeclientset,
+	controller := subnamespace.NewController(signals.ContextFor(stopCh),
+		kubeclientset,
 		edgenetclientset,
 		kubeInformerFactory.Rbac().V1().Roles(),
 		kubeInformerFactory.Rbac().V1().RoleBindings(),
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/controller/apps/v1alpha/tenantapp"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/helm"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	"k8s.io/klog/v2"
+)
+
+func main() {
+	klog.InitFlags(nil)
+	helmBinary := flag.String("helm", "/usr/local/bin/helm", "Path of the helm binary that renders the charts.")
+	flag.Parse()
+
+	stopCh := signals.SetupSignalHandler()
+	// TODO: Pass an argument to select using kubeconfig or service account for clients
+	// bootstrap.SetKubeConfig()
+	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the clientset")
+		panic(err.Error())
+	}
+	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the EdgeNet clientset")
+		panic(err.Error())
+	}
+	dynamicclient, err := bootstrap.CreateDynamicClient("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the dynamic client")
+		panic(err.Error())
+	}
+	// Start the controller to provide the functionalities of tenantapp resource
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, 0)
+
+	controller := tenantapp.NewController(kubeclientset,
+		edgenetclientset,
+		dynamicclient,
+		helm.CLI{Binary: *helmBinary},
+		edgenetInformerFactory.Apps().V1alpha().TenantApps())
+
+	edgenetInformerFactory.Start(stopCh)
+
+	if err = controller.Run(2, stopCh); err != nil {
+		klog.Fatalf("Error running controller: %s", err.Error())
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+
+	"k8s.io/klog/v2"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/controller/core/v1/tenantdns"
+	"github.com/EdgeNet-project/edgenet/pkg/dns"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+)
+
+func main() {
+	klog.InitFlags(nil)
+	var provider string
+	flag.StringVar(&tenantdns.Zone, "zone", tenantdns.Zone, "Zone of the cluster, each tenant gets a subdomain of it.")
+	flag.DurationVar(&tenantdns.Interval, "interval", tenantdns.Interval, "Time between two syncs of the zone.")
+	flag.StringVar(&provider, "provider", "namecheap", "DNS provider hosting the zone.")
+	flag.Parse()
+
+	stopCh := signals.SetupSignalHandler()
+	// TODO: Pass an argument to select using kubeconfig or service account for clients
+	// bootstrap.SetKubeConfig()
+	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the clientset")
+		panic(err.Error())
+	}
+
+	var zone dns.Provider
+	switch provider {
+	case "namecheap":
+		namecheapClient, _ := bootstrap.CreateNamecheapClient()
+		if zone, err = dns.NewNamecheap(namecheapClient, tenantdns.Zone); err != nil {
+			klog.Fatalf("Error creating the DNS provider: %s", err.Error())
+		}
+	default:
+		klog.Fatalf("Unknown DNS provider: %s", provider)
+	}
+
+	controller := tenantdns.NewController(kubeclientset, zone)
+
+	if err = controller.Run(stopCh); err != nil {
+		klog.Fatalf("Error running controller: %s", err.Error())
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/controller/core/v1alpha/tenantmigration"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	"k8s.io/klog/v2"
+)
+
+// Runs the tenant migration controller, and serves the admission webhook that rejects the
+// migrations that cannot run, the API server calls it over TLS
+func main() {
+	klog.InitFlags(nil)
+	address := flag.String("address", ":8443", "Address to serve the tenant migration webhook on.")
+	certFile := flag.String("tls-cert-file", "/etc/webhook/certs/tls.crt", "Path to the TLS certificate.")
+	keyFile := flag.String("tls-private-key-file", "/etc/webhook/certs/tls.key", "Path to the TLS private key.")
+	flag.Parse()
+
+	stopCh := signals.SetupSignalHandler()
+	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the clientset")
+		panic(err.Error())
+	}
+	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the EdgeNet clientset")
+		panic(err.Error())
+	}
+
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, 0)
+	controller := tenantmigration.NewController(kubeclientset,
+		edgenetclientset,
+		edgenetInformerFactory.Core().V1alpha().TenantMigrations())
+	webhook := tenantmigration.NewWebhook(
+		edgenetInformerFactory.Core().V1alpha().Tenants(),
+		edgenetInformerFactory.Core().V1alpha().TenantMigrations())
+
+	edgenetInformerFactory.Start(stopCh)
+	if err := webhook.WaitForCacheSync(stopCh); err != nil {
+		klog.Fatalf("Error running tenant migration webhook: %s", err.Error())
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/validate", webhook.Handler())
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	go func() {
+		klog.Fatal(http.ListenAndServeTLS(*address, *certFile, *keyFile, mux))
+	}()
+
+	if err = controller.Run(1, stopCh); err != nil {
+		klog.Fatalf("Error running controller: %s", err.Error())
+	}
+}
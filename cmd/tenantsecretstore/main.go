@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+
+	"k8s.io/klog/v2"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	"github.com/EdgeNet-project/edgenet/pkg/controller/core/v1alpha/tenantsecretstore"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+	"github.com/EdgeNet-project/edgenet/pkg/vault"
+)
+
+func main() {
+	klog.InitFlags(nil)
+	vaultAddress := flag.String("vault-address", "", "Address of the Vault server, such as https://vault.edge-net.io:8200, the secrets are not synced if empty.")
+	vaultCAPath := flag.String("vault-ca-file", "", "Certificate authority of the Vault server if the system does not trust its certificate.")
+	vaultTokenPath := flag.String("vault-token-file", "", "File holding the Vault token the controller manages the policies and the roles of the tenants with.")
+	flag.StringVar(&vault.AuthMount, "vault-auth-mount", vault.AuthMount, "Path the Kubernetes auth method is enabled at in Vault.")
+	flag.StringVar(&vault.KVMount, "vault-kv-mount", vault.KVMount, "Path the KV version 2 secrets engine holding the secrets of the tenants is enabled at in Vault.")
+	flag.StringVar(&vault.Prefix, "vault-prefix", vault.Prefix, "Prefix of the paths of the tenants in the secrets engine.")
+	flag.StringVar(&vault.Audience, "vault-audience", vault.Audience, "Audience of the service account tokens the tenants log in to Vault with.")
+	flag.DurationVar(&tenantsecretstore.RefreshInterval, "refresh-interval", tenantsecretstore.RefreshInterval, "Time after which the secrets are read again from Vault if the store does not set it.")
+	flag.Parse()
+	if err := vault.Configure(*vaultAddress, *vaultCAPath, *vaultTokenPath); err != nil {
+		klog.Fatalf("Invalid Vault configuration: %s", err.Error())
+	}
+
+	stopCh := signals.SetupSignalHandler()
+	// TODO: Pass an argument to select using kubeconfig or service account for clients
+	// bootstrap.SetKubeConfig()
+	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the clientset")
+		panic(err.Error())
+	}
+	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the EdgeNet clientset")
+		panic(err.Error())
+	}
+	// Start the controller to provide the functionalities of tenant secret store resource
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, 0)
+
+	controller := tenantsecretstore.NewController(signals.ContextFor(stopCh),
+		kubeclientset,
+		edgenetclientset,
+		edgenetInformerFactory.Core().V1alpha().TenantSecretStores(),
+		edgenetInformerFactory.Core().V1alpha().Tenants())
+
+	edgenetInformerFactory.Start(stopCh)
+
+	if err = controller.Run(2, stopCh); err != nil {
+		klog.Fatalf("Error running controller: %s", err.Error())
+	}
+}
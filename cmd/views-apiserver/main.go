@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"net/http"
+
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+	"github.com/EdgeNet-project/edgenet/pkg/views"
+
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/klog/v2"
+)
+
+// Serves the views.edgenet.io API group, the API server proxies the requests to it over TLS
+func main() {
+	klog.InitFlags(nil)
+	address := flag.String("address", ":8443", "Address to serve the views API on.")
+	certFile := flag.String("tls-cert-file", "/etc/webhook/certs/tls.crt", "Path to the TLS certificate.")
+	keyFile := flag.String("tls-private-key-file", "/etc/webhook/certs/tls.key", "Path to the TLS private key.")
+	flag.Parse()
+
+	stopCh := signals.SetupSignalHandler()
+	kubeclientset, err := bootstrap.CreateClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the clientset")
+		panic(err.Error())
+	}
+	edgenetclientset, err := bootstrap.CreateEdgeNetClientset("serviceaccount")
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create the EdgeNet clientset")
+		panic(err.Error())
+	}
+	// The front proxy authority is read once, the pods are restarted when the API server rotates it
+	requestHeader, err := views.LoadRequestHeader(context.Background(), kubeclientset)
+	if err != nil {
+		klog.Fatalf("Couldn't read the front proxy configuration: %s", err.Error())
+	}
+
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeclientset, 0)
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, 0)
+
+	server := views.NewServer(kubeclientset, requestHeader,
+		edgenetInformerFactory.Core().V1alpha().Tenants(),
+		edgenetInformerFactory.Core().V1alpha().NodeContributions(),
+		edgenetInformerFactory.Registration().V1alpha().RoleRequests(),
+		edgenetInformerFactory.Registration().V1alpha().ExtensionRequests(),
+		kubeInformerFactory.Core().V1().Namespaces(),
+		kubeInformerFactory.Core().V1().ResourceQuotas(),
+		kubeInformerFactory.Core().V1().Nodes(),
+	)
+
+	kubeInformerFactory.Start(stopCh)
+	edgenetInformerFactory.Start(stopCh)
+	if err := server.WaitForCacheSync(stopCh); err != nil {
+		klog.Fatalf("Error running views API server: %s", err.Error())
+	}
+
+	// The discovery is read without a client certificate, the views are refused without one
+	httpServer := &http.Server{Addr: *address, Handler: server.Handler(),
+		TLSConfig: &tls.Config{ClientAuth: tls.VerifyClientCertIfGiven, ClientCAs: requestHeader.ClientCAs}}
+	klog.Fatal(httpServer.ListenAndServeTLS(*certFile, *keyFile))
+}
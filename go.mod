@@ -4,10 +4,14 @@ go 1.15
 
 require (
 	github.com/billputer/go-namecheap v0.0.0-20191113012015-80fb801c9a11
+	github.com/go-logr/logr v0.4.0
 	github.com/google/uuid v1.1.2
+	github.com/lib/pq v1.9.0
+	github.com/prometheus/client_golang v1.9.0
 	github.com/savaki/geoip2 v0.0.0-20150727150920-9968b08fbf39
 	github.com/sirupsen/logrus v1.8.1
 	github.com/xhit/go-simple-mail/v2 v2.10.0
+	go.uber.org/zap v1.16.0
 	golang.org/x/crypto v0.0.0-20210503195802-e9a32991a82e
 	golang.zx2c4.com/wireguard/wgctrl v0.0.0-20210506160403-92e472f520a5
 	gopkg.in/yaml.v2 v2.4.0
@@ -15,8 +19,9 @@ require (
 	k8s.io/apimachinery v0.21.0
 	k8s.io/client-go v0.21.0
 	k8s.io/cluster-bootstrap v0.19.2
-	k8s.io/code-generator v0.21.0
-	k8s.io/klog v1.0.0
+	k8s.io/component-base v0.21.0
 	k8s.io/klog/v2 v2.8.0
 	sigs.k8s.io/cluster-api v0.3.10
+	sigs.k8s.io/controller-runtime v0.9.0-beta.5
+	sigs.k8s.io/yaml v1.2.0
 )
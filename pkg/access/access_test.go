@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
 	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
@@ -28,11 +29,13 @@ import (
 	"github.com/EdgeNet-project/edgenet/pkg/util"
 
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	testclient "k8s.io/client-go/kubernetes/fake"
+	kubescheme "k8s.io/client-go/kubernetes/scheme"
 )
 
 type TestGroup struct {
@@ -116,7 +119,9 @@ func (g *TestGroup) Init() {
 	g.tenantObj = tenantObj
 	g.tenantObj.Spec.Enabled = true
 	g.tenant = tenantObj
-	g.client = testclient.NewSimpleClientset()
+	client := testclient.NewSimpleClientset()
+	client.PrependReactor("patch", "*", util.ApplyReactor(client.Tracker(), kubescheme.Scheme))
+	g.client = client
 	g.edgenetclient = edgenettestclient.NewSimpleClientset()
 	Clientset = g.client
 	EdgenetClientset = g.edgenetclient
@@ -124,11 +129,11 @@ func (g *TestGroup) Init() {
 	g.client.CoreV1().Namespaces().Create(context.TODO(), &g.namespace, metav1.CreateOptions{})
 }
 
-func TestCreateClusterRoles(t *testing.T) {
+func TestReconcileClusterRoles(t *testing.T) {
 	g := TestGroup{}
 	g.Init()
 
-	err := CreateClusterRoles()
+	err := ReconcileClusterRoles(context.TODO())
 	util.OK(t, err)
 
 	cases := map[string]struct {
@@ -161,15 +166,15 @@ func TestCreateClusterRoles(t *testing.T) {
 		}
 		for k, tc := range cases {
 			t.Run(k, func(t *testing.T) {
-				CreateObjectSpecificRoleBinding(tc.tenant, tc.namespace, tc.roleName, tc.initialHandle, tc.email)
+				CreateObjectSpecificRoleBinding(context.TODO(), tc.tenant, tc.namespace, tc.roleName, tc.initialHandle, tc.email)
 				_, err := g.client.RbacV1().RoleBindings(tenant.GetName()).Get(context.TODO(), tc.expected, metav1.GetOptions{})
 				util.OK(t, err)
-				err = CreateObjectSpecificRoleBinding(tc.tenant, tc.namespace, tc.roleName, tc.initialHandle, tc.email)
+				err = CreateObjectSpecificRoleBinding(context.TODO(), tc.tenant, tc.namespace, tc.roleName, tc.initialHandle, tc.email)
 				util.OK(t, err)
 			})
 		}
 	})
-	err = CreateClusterRoles()
+	err = ReconcileClusterRoles(context.TODO())
 	util.OK(t, err)
 }
 
@@ -196,16 +201,24 @@ func TestCreateObjectSpecificClusterRole(t *testing.T) {
 	}
 	for k, tc := range cases {
 		t.Run(k, func(t *testing.T) {
-			CreateObjectSpecificClusterRole(tc.tenant.GetName(), tc.apiGroup, tc.resource, tc.resourceName, "name", tc.verbs, []metav1.OwnerReference{})
+			CreateObjectSpecificClusterRole(context.TODO(), tc.tenant.GetName(), tc.apiGroup, tc.resource, tc.resourceName, "name", tc.verbs, []metav1.OwnerReference{})
 			clusterRole, err := g.client.RbacV1().ClusterRoles().Get(context.TODO(), tc.expected, metav1.GetOptions{})
 			util.OK(t, err)
 			if err == nil {
 				util.Equals(t, tc.verbs, clusterRole.Rules[0].Verbs)
 			}
-			_, err = CreateObjectSpecificClusterRole(tc.tenant.GetName(), tc.apiGroup, tc.resource, tc.resourceName, "name", tc.verbs, []metav1.OwnerReference{})
+			_, err = CreateObjectSpecificClusterRole(context.TODO(), tc.tenant.GetName(), tc.apiGroup, tc.resource, tc.resourceName, "name", tc.verbs, []metav1.OwnerReference{})
 			util.OK(t, err)
 		})
 	}
+	t.Run("update", func(t *testing.T) {
+		// The rules of the existing cluster role follow the ones generated
+		_, err := CreateObjectSpecificClusterRole(context.TODO(), tenant1.GetName(), "core.edgenet.io", "tenants", tenant1.GetName(), "name", []string{"get"}, []metav1.OwnerReference{})
+		util.OK(t, err)
+		clusterRole, err := g.client.RbacV1().ClusterRoles().Get(context.TODO(), fmt.Sprintf("edgenet:%s:tenants:%s-name", tenant1.GetName(), tenant1.GetName()), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, []string{"get"}, clusterRole.Rules[0].Verbs)
+	})
 
 	t.Run("cluster role binding", func(t *testing.T) {
 		cases := map[string]struct {
@@ -222,10 +235,10 @@ func TestCreateObjectSpecificClusterRole(t *testing.T) {
 		for k, tc := range cases {
 			t.Run(k, func(t *testing.T) {
 				roleBindLabels := map[string]string{"edge-net.io/generated": "true", "edge-net.io/identity": "true"}
-				CreateObjectSpecificClusterRoleBinding(tc.roleName, tc.initialHandle, tc.email, roleBindLabels, []metav1.OwnerReference{})
+				CreateObjectSpecificClusterRoleBinding(context.TODO(), tc.roleName, tc.initialHandle, tc.email, roleBindLabels, []metav1.OwnerReference{})
 				_, err := g.client.RbacV1().ClusterRoleBindings().Get(context.TODO(), fmt.Sprintf("%s-%s", tc.roleName, tc.initialHandle), metav1.GetOptions{})
 				util.OK(t, err)
-				err = CreateObjectSpecificClusterRoleBinding(tc.roleName, tc.initialHandle, tc.email, roleBindLabels, []metav1.OwnerReference{})
+				err = CreateObjectSpecificClusterRoleBinding(context.TODO(), tc.roleName, tc.initialHandle, tc.email, roleBindLabels, []metav1.OwnerReference{})
 				util.OK(t, err)
 			})
 		}
@@ -242,7 +255,7 @@ func TestPermissionSystem(t *testing.T) {
 	collaborator := map[string]string{"InitialHandle": "tompublic", "Email": "tom.public@edge-net.org"}
 	admin := map[string]string{"InitialHandle": "joedoe", "Email": "joe.doe@edge-net.org"}
 
-	err := CreateClusterRoles()
+	err := ReconcileClusterRoles(context.TODO())
 	util.OK(t, err)
 	cases := map[string]struct {
 		expected string
@@ -258,39 +271,39 @@ func TestPermissionSystem(t *testing.T) {
 		})
 	}
 	t.Run("bind cluster role for tenant owner", func(t *testing.T) {
-		err = CreateObjectSpecificRoleBinding(tenant.GetName(), tenant.GetName(), "edgenet:tenant-owner", owner["InitialHandle"], owner["Email"])
+		err = CreateObjectSpecificRoleBinding(context.TODO(), tenant.GetName(), tenant.GetName(), "edgenet:tenant-owner", owner["InitialHandle"], owner["Email"])
 		util.OK(t, err)
 	})
 	t.Run("bind cluster role for tenant collaborator", func(t *testing.T) {
-		err = CreateObjectSpecificRoleBinding(tenant.GetName(), tenant.GetName(), "edgenet:tenant-collaborator", collaborator["InitialHandle"], collaborator["Email"])
+		err = CreateObjectSpecificRoleBinding(context.TODO(), tenant.GetName(), tenant.GetName(), "edgenet:tenant-collaborator", collaborator["InitialHandle"], collaborator["Email"])
 		util.OK(t, err)
 	})
 	t.Run("bind cluster role for tenant admin", func(t *testing.T) {
-		err = CreateObjectSpecificRoleBinding(tenant.GetName(), tenant.GetName(), "edgenet:tenant-admin", admin["InitialHandle"], admin["Email"])
+		err = CreateObjectSpecificRoleBinding(context.TODO(), tenant.GetName(), tenant.GetName(), "edgenet:tenant-admin", admin["InitialHandle"], admin["Email"])
 		util.OK(t, err)
 	})
 
 	t.Run("create owner specific tenant role", func(t *testing.T) {
-		CreateObjectSpecificClusterRole(tenant.GetName(), "core.edgenet.io", "tenants", tenant.GetName(), "owner", []string{"get", "update", "patch"}, []metav1.OwnerReference{})
+		CreateObjectSpecificClusterRole(context.TODO(), tenant.GetName(), "core.edgenet.io", "tenants", tenant.GetName(), "owner", []string{"get", "update", "patch"}, []metav1.OwnerReference{})
 		_, err := g.client.RbacV1().ClusterRoles().Get(context.TODO(), fmt.Sprintf("edgenet:%s:tenants:%s-owner", tenant.GetName(), tenant.GetName()), metav1.GetOptions{})
 		util.OK(t, err)
 	})
 	t.Run("create admin specific tenant role", func(t *testing.T) {
-		CreateObjectSpecificClusterRole(tenant.GetName(), "core.edgenet.io", "tenants", tenant.GetName(), "admin", []string{"get"}, []metav1.OwnerReference{})
+		CreateObjectSpecificClusterRole(context.TODO(), tenant.GetName(), "core.edgenet.io", "tenants", tenant.GetName(), "admin", []string{"get"}, []metav1.OwnerReference{})
 		_, err := g.client.RbacV1().ClusterRoles().Get(context.TODO(), fmt.Sprintf("edgenet:%s:tenants:%s-admin", tenant.GetName(), tenant.GetName()), metav1.GetOptions{})
 		util.OK(t, err)
 	})
 	t.Run("create owner role binding", func(t *testing.T) {
 		roleBindLabels := map[string]string{"edge-net.io/generated": "true", "edge-net.io/tenant": tenant.GetName(), "edge-net.io/identity": "true"}
 
-		CreateObjectSpecificClusterRoleBinding(fmt.Sprintf("edgenet:%s:tenants:%s-owner", tenant.GetName(), tenant.GetName()), owner["InitialHandle"], owner["Email"], roleBindLabels, []metav1.OwnerReference{})
+		CreateObjectSpecificClusterRoleBinding(context.TODO(), fmt.Sprintf("edgenet:%s:tenants:%s-owner", tenant.GetName(), tenant.GetName()), owner["InitialHandle"], owner["Email"], roleBindLabels, []metav1.OwnerReference{})
 		_, err := g.client.RbacV1().ClusterRoleBindings().Get(context.TODO(), fmt.Sprintf("edgenet:%s:tenants:%s-owner-%s", tenant.GetName(), tenant.GetName(), owner["InitialHandle"]), metav1.GetOptions{})
 		util.OK(t, err)
 	})
 	t.Run("create admin role binding", func(t *testing.T) {
 		roleBindLabels := map[string]string{"edge-net.io/generated": "true", "edge-net.io/tenant": tenant.GetName(), "edge-net.io/identity": "true"}
 
-		CreateObjectSpecificClusterRoleBinding(fmt.Sprintf("edgenet:%s:tenants:%s-admin", tenant.GetName(), tenant.GetName()), admin["InitialHandle"], admin["Email"], roleBindLabels, []metav1.OwnerReference{})
+		CreateObjectSpecificClusterRoleBinding(context.TODO(), fmt.Sprintf("edgenet:%s:tenants:%s-admin", tenant.GetName(), tenant.GetName()), admin["InitialHandle"], admin["Email"], roleBindLabels, []metav1.OwnerReference{})
 		_, err := g.client.RbacV1().ClusterRoleBindings().Get(context.TODO(), fmt.Sprintf("edgenet:%s:tenants:%s-admin-%s", tenant.GetName(), tenant.GetName(), admin["InitialHandle"]), metav1.GetOptions{})
 		util.OK(t, err)
 	})
@@ -316,7 +329,7 @@ func TestPermissionSystem(t *testing.T) {
 	}
 	for k, tc := range permissionCases {
 		t.Run(k, func(t *testing.T) {
-			authorized := CheckAuthorization(tc.namespace, tc.user["Email"], tc.resource, tc.resourceName, tc.scope)
+			authorized := CheckAuthorization(context.TODO(), tc.namespace, tc.user["Email"], tc.resource, tc.resourceName, tc.scope)
 			util.Equals(t, tc.expected, authorized)
 		})
 	}
@@ -334,7 +347,476 @@ func TestApplyTenantResourceQuota(t *testing.T) {
 			"memory": resource.MustParse("6Gi"),
 		},
 	}
-	ApplyTenantResourceQuota(g.tenantResourceQuotaObj.GetName(), nil, claim)
+	ApplyTenantResourceQuota(context.TODO(), g.tenantResourceQuotaObj.GetName(), nil, claim)
 	_, err = EdgenetClientset.CoreV1alpha().TenantResourceQuotas().Get(context.TODO(), g.tenantResourceQuotaObj.GetName(), metav1.GetOptions{})
 	util.OK(t, err)
 }
+
+func TestApplyBaselineClusterPolicies(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	err := ApplyBaselineClusterPolicies(context.TODO(), g.namespace.GetName(), g.tenant.GetName(), "tenant-uid", "cluster-uid", nil, nil)
+	util.OK(t, err)
+	networkPolicyRaw, err := Clientset.NetworkingV1().NetworkPolicies(g.namespace.GetName()).List(context.TODO(), metav1.ListOptions{})
+	util.OK(t, err)
+	util.Equals(t, 3, len(networkPolicyRaw.Items))
+	defaultDeny, err := Clientset.NetworkingV1().NetworkPolicies(g.namespace.GetName()).Get(context.TODO(), "default-deny-ingress", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, 0, len(defaultDeny.Spec.Ingress))
+	allowDNS, err := Clientset.NetworkingV1().NetworkPolicies(g.namespace.GetName()).Get(context.TODO(), "allow-dns-egress", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, int32(53), allowDNS.Spec.Egress[0].Ports[0].Port.IntVal)
+	allowTenant, err := Clientset.NetworkingV1().NetworkPolicies(g.namespace.GetName()).Get(context.TODO(), "allow-tenant-ingress", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, "0.0.0.0/0", allowTenant.Spec.Ingress[1].From[0].IPBlock.CIDR)
+	util.Equals(t, "::/0", allowTenant.Spec.Ingress[1].From[1].IPBlock.CIDR)
+	util.Equals(t, []string{"fc00::/7", "fe80::/10"}, allowTenant.Spec.Ingress[1].From[1].IPBlock.Except)
+
+	t.Run("reapply", func(t *testing.T) {
+		err := ApplyBaselineClusterPolicies(context.TODO(), g.namespace.GetName(), g.tenant.GetName(), "tenant-uid", "cluster-uid", nil, nil)
+		util.OK(t, err)
+	})
+	t.Run("remove", func(t *testing.T) {
+		err := RemoveBaselineClusterPolicies(context.TODO(), g.namespace.GetName())
+		util.OK(t, err)
+		networkPolicyRaw, err := Clientset.NetworkingV1().NetworkPolicies(g.namespace.GetName()).List(context.TODO(), metav1.ListOptions{})
+		util.OK(t, err)
+		util.Equals(t, 0, len(networkPolicyRaw.Items))
+	})
+}
+
+func TestSetPrivateRanges(t *testing.T) {
+	defer func(ipv4Ranges, ipv6Ranges []string) { PrivateIPv4Ranges, PrivateIPv6Ranges = ipv4Ranges, ipv6Ranges }(PrivateIPv4Ranges, PrivateIPv6Ranges)
+
+	util.OK(t, SetPrivateRanges("10.0.0.0/8, fd00::/8,100.64.0.0/10"))
+	util.Equals(t, []string{"10.0.0.0/8", "100.64.0.0/10"}, PrivateIPv4Ranges)
+	util.Equals(t, []string{"fd00::/8"}, PrivateIPv6Ranges)
+	util.Equals(t, []string{"10.0.0.0/8", "100.64.0.0/10", "fd00::/8"}, PrivateRanges())
+	peers := ExternalPeers()
+	util.Equals(t, 2, len(peers))
+	util.Equals(t, []string{"fd00::/8"}, peers[1].IPBlock.Except)
+
+	util.OK(t, SetPrivateRanges("192.168.0.0/16"))
+	util.Equals(t, []string{}, PrivateIPv6Ranges)
+	util.Assert(t, SetPrivateRanges("10.0.0.0") != nil, "Range without a prefix length accepted")
+}
+
+func TestPortRange(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	defer func(last, size int32) { NodePortLast, PortBlockSize = last, size }(NodePortLast, PortBlockSize)
+	NodePortLast, PortBlockSize = 30299, 100
+
+	first, err := AllocatePortRange(context.TODO(), "lip6")
+	util.OK(t, err)
+	util.Equals(t, PortRange{First: 30000, Last: 30099}, first)
+	second, err := AllocatePortRange(context.TODO(), "cslash")
+	util.OK(t, err)
+	util.Equals(t, PortRange{First: 30100, Last: 30199}, second)
+	again, err := AllocatePortRange(context.TODO(), "lip6")
+	util.OK(t, err)
+	util.Equals(t, first, again)
+
+	t.Run("network policy", func(t *testing.T) {
+		err := ApplyBaselineClusterPolicies(context.TODO(), g.namespace.GetName(), "lip6", "tenant-uid", "cluster-uid", nil, nil)
+		util.OK(t, err)
+		allowTenant, err := Clientset.NetworkingV1().NetworkPolicies(g.namespace.GetName()).Get(context.TODO(), "allow-tenant-ingress", metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, int32(30000), allowTenant.Spec.Ingress[1].Ports[0].Port.IntVal)
+		util.Equals(t, int32(30099), *allowTenant.Spec.Ingress[1].Ports[0].EndPort)
+	})
+	t.Run("release", func(t *testing.T) {
+		util.OK(t, ReleasePortRange(context.TODO(), "lip6"))
+		_, allocated, err := LookupPortRange(context.TODO(), "lip6")
+		util.OK(t, err)
+		util.Equals(t, false, allocated)
+		// The freed block is the first to be allocated again
+		portRange, err := AllocatePortRange(context.TODO(), "inria")
+		util.OK(t, err)
+		util.Equals(t, first, portRange)
+	})
+	t.Run("exhaustion", func(t *testing.T) {
+		tenant := g.tenantObj.DeepCopy()
+		for _, name := range []string{"cslash", "inria", "sorbonne"} {
+			tenant.SetName(name)
+			_, err := EdgenetClientset.CoreV1alpha().Tenants().Create(context.TODO(), tenant, metav1.CreateOptions{})
+			util.OK(t, err)
+		}
+		_, err := AllocatePortRange(context.TODO(), "sorbonne")
+		util.OK(t, err)
+		_, err = AllocatePortRange(context.TODO(), "lip6")
+		util.Assert(t, err != nil, "port range allocated beyond the node port range")
+		// The range of a tenant that is gone is reclaimed
+		util.OK(t, EdgenetClientset.CoreV1alpha().Tenants().Delete(context.TODO(), "cslash", metav1.DeleteOptions{}))
+		portRange, err := AllocatePortRange(context.TODO(), "lip6")
+		util.OK(t, err)
+		util.Equals(t, second, portRange)
+	})
+}
+
+func TestDiffTenantRBAC(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	tenant := g.tenantObj.DeepCopy()
+
+	plan, err := DiffClusterRoles(context.TODO())
+	util.OK(t, err)
+	util.Equals(t, 3, len(plan.Changes))
+	util.OK(t, ApplyPlan(context.TODO(), plan))
+	plan, err = DiffClusterRoles(context.TODO())
+	util.OK(t, err)
+	util.Assert(t, plan.Empty(), "cluster roles drift right after the migration")
+
+	plan, err = DiffTenantRBAC(context.TODO(), tenant)
+	util.OK(t, err)
+	// The owner role and its bindings, and the service bindings of the core namespace
+	util.Equals(t, 5, len(plan.Changes))
+	util.OK(t, ApplyPlan(context.TODO(), plan))
+	plan, err = DiffTenantRBAC(context.TODO(), tenant)
+	util.OK(t, err)
+	util.Assert(t, plan.Empty(), "tenant RBAC drifts right after the migration")
+
+	t.Run("template change", func(t *testing.T) {
+		ownerRole, err := g.client.RbacV1().ClusterRoles().Get(context.TODO(), "edgenet:tenant-owner", metav1.GetOptions{})
+		util.OK(t, err)
+		ownerRole.Rules = ownerRole.Rules[1:]
+		g.client.RbacV1().ClusterRoles().Update(context.TODO(), ownerRole, metav1.UpdateOptions{})
+		plan, err := DiffClusterRoles(context.TODO())
+		util.OK(t, err)
+		util.Equals(t, 1, len(plan.Changes))
+		util.Equals(t, Update, plan.Changes[0].Action)
+		util.OK(t, ApplyPlan(context.TODO(), plan))
+		ownerRole, _ = g.client.RbacV1().ClusterRoles().Get(context.TODO(), "edgenet:tenant-owner", metav1.GetOptions{})
+		util.Equals(t, tenantOwnerPolicyRules(), ownerRole.Rules)
+	})
+	t.Run("contact change", func(t *testing.T) {
+		formerHandle := tenant.Spec.Contact.Handle
+		tenant.Spec.Contact.Handle = "johnsmith"
+		tenant.Spec.Contact.Email = "john.smith@edge-net.org"
+		plan, err := DiffTenantRBAC(context.TODO(), tenant)
+		util.OK(t, err)
+		actions := map[string]string{}
+		for _, change := range plan.Changes {
+			actions[fmt.Sprintf("%s/%s", change.Kind, change.Object.GetName())] = change.Action
+		}
+		ownerRoleName := fmt.Sprintf("edgenet:%s:tenants:%s-owner", tenant.GetName(), tenant.GetName())
+		util.Equals(t, map[string]string{
+			fmt.Sprintf("ClusterRoleBinding/%s-johnsmith", ownerRoleName):        Add,
+			fmt.Sprintf("ClusterRoleBinding/%s-%s", ownerRoleName, formerHandle): Remove,
+			"RoleBinding/edgenet:tenant-owner":                                   Update,
+		}, actions)
+		util.OK(t, ApplyPlan(context.TODO(), plan))
+		_, err = g.client.RbacV1().ClusterRoleBindings().Get(context.TODO(), fmt.Sprintf("%s-%s", ownerRoleName, formerHandle), metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+		roleBinding, err := g.client.RbacV1().RoleBindings(tenant.GetName()).Get(context.TODO(), "edgenet:tenant-owner", metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, "john.smith@edge-net.org", roleBinding.Subjects[0].Name)
+	})
+	t.Run("members and workspaces", func(t *testing.T) {
+		tenant.Spec.Admins = []corev1alpha.Contact{{Handle: "janedoe", Email: "jane.doe@edge-net.org"}}
+		tenant.Spec.GroupBindings = []corev1alpha.GroupBinding{{Group: "lip6:faculty", Role: "admin"}}
+		workspace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "edgenet-lab",
+			Labels: map[string]string{"edge-net.io/tenant": tenant.GetName(), "edge-net.io/kind": "sub"}}}
+		_, err := g.client.CoreV1().Namespaces().Create(context.TODO(), workspace, metav1.CreateOptions{})
+		util.OK(t, err)
+		snapshot, err := TenantSnapshot(context.TODO(), tenant)
+		util.OK(t, err)
+		// The bindings of the contact, the administrator, the group, and the services in both namespaces
+		util.Equals(t, 7, len(snapshot.RoleBindings))
+		util.Equals(t, 9, len(snapshot.Objects()))
+
+		plan, err := DiffTenantRBAC(context.TODO(), tenant)
+		util.OK(t, err)
+		util.Equals(t, 4, len(plan.Changes))
+		util.OK(t, ApplyPlan(context.TODO(), plan))
+		plan, err = DiffTenantRBAC(context.TODO(), tenant)
+		util.OK(t, err)
+		util.Assert(t, plan.Empty(), "tenant RBAC drifts right after the migration")
+
+		tenant.Spec.Admins = nil
+		plan, err = DiffTenantRBAC(context.TODO(), tenant)
+		util.OK(t, err)
+		util.Equals(t, 1, len(plan.Changes))
+		util.Equals(t, Remove, plan.Changes[0].Action)
+		util.OK(t, ApplyPlan(context.TODO(), plan))
+	})
+	t.Run("disabled tenant", func(t *testing.T) {
+		tenant.Spec.Enabled = false
+		plan, err := DiffTenantRBAC(context.TODO(), tenant)
+		util.OK(t, err)
+		util.Assert(t, plan.Empty(), "plan of a disabled tenant")
+	})
+}
+
+func TestCatalog(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	defer SetCatalog(DefaultCatalog())
+
+	t.Run("template", func(t *testing.T) {
+		template, err := ReadCatalog("../../configs/clusterroles_template.yaml")
+		util.OK(t, err)
+		util.Equals(t, DefaultCatalog(), template)
+	})
+	t.Run("validation", func(t *testing.T) {
+		missing := DefaultCatalog()
+		missing.Roles = missing.Roles[1:]
+		util.Assert(t, SetCatalog(missing) != nil, "catalog without the owner role accepted")
+		duplicate := DefaultCatalog()
+		duplicate.Roles = append(duplicate.Roles, duplicate.Roles[0])
+		util.Assert(t, SetCatalog(duplicate) != nil, "catalog with a duplicate role accepted")
+		prefix := DefaultCatalog()
+		prefix.Roles = append(prefix.Roles, CatalogRole{Name: "cluster-admin"})
+		util.Assert(t, SetCatalog(prefix) != nil, "catalog overriding a system role accepted")
+	})
+
+	util.OK(t, ReconcileClusterRoles(context.TODO()))
+	t.Run("customization", func(t *testing.T) {
+		custom := DefaultCatalog()
+		viewerRules := []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"pods", "services"}, Verbs: []string{"get", "list", "watch"}}}
+		custom.Roles = append(custom.Roles, CatalogRole{Name: "edgenet:tenant-viewer", Rules: viewerRules})
+		custom.Roles[2].Rules = viewerRules
+		util.OK(t, SetCatalog(custom))
+		util.OK(t, ReconcileClusterRoles(context.TODO()))
+		viewerRole, err := g.client.RbacV1().ClusterRoles().Get(context.TODO(), "edgenet:tenant-viewer", metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, viewerRules, viewerRole.Rules)
+		collaboratorRole, err := g.client.RbacV1().ClusterRoles().Get(context.TODO(), TenantCollaboratorRole, metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, viewerRules, collaboratorRole.Rules)
+
+		util.OK(t, SetCatalog(DefaultCatalog()))
+		util.OK(t, ReconcileClusterRoles(context.TODO()))
+		_, err = g.client.RbacV1().ClusterRoles().Get(context.TODO(), "edgenet:tenant-viewer", metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+		collaboratorRole, err = g.client.RbacV1().ClusterRoles().Get(context.TODO(), TenantCollaboratorRole, metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, tenantCollaboratorPolicyRules(), collaboratorRole.Rules)
+	})
+	t.Run("drift", func(t *testing.T) {
+		ownerRole, err := g.client.RbacV1().ClusterRoles().Get(context.TODO(), TenantOwnerRole, metav1.GetOptions{})
+		util.OK(t, err)
+		ownerRole.Rules = append(ownerRole.Rules, rbacv1.PolicyRule{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}})
+		g.client.RbacV1().ClusterRoles().Update(context.TODO(), ownerRole, metav1.UpdateOptions{})
+		util.OK(t, ReconcileClusterRoles(context.TODO()))
+		ownerRole, err = g.client.RbacV1().ClusterRoles().Get(context.TODO(), TenantOwnerRole, metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, tenantOwnerPolicyRules(), ownerRole.Rules)
+	})
+}
+
+func TestReconcileObjectSpecificRBAC(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	tenant := g.tenantObj.DeepCopy()
+	g.edgenetclient.CoreV1alpha().Tenants().Create(context.TODO(), tenant, metav1.CreateOptions{})
+	g.edgenetclient.CoreV1alpha().TenantResourceQuotas().Create(context.TODO(), g.tenantResourceQuotaObj.DeepCopy(), metav1.CreateOptions{})
+
+	ownerRole, err := CreateObjectSpecificClusterRole(context.TODO(), tenant.GetName(), "core.edgenet.io", "tenants", tenant.GetName(), "owner", []string{"get", "update", "patch"}, []metav1.OwnerReference{})
+	util.OK(t, err)
+	util.OK(t, CreateObjectSpecificClusterRoleBinding(context.TODO(), ownerRole, tenant.Spec.Contact.Handle, tenant.Spec.Contact.Email, map[string]string{}, []metav1.OwnerReference{}))
+	quotaRole, err := CreateObjectSpecificClusterRole(context.TODO(), tenant.GetName(), "core.edgenet.io", "tenantresourcequotas", tenant.GetName(), "owner", []string{"get"}, []metav1.OwnerReference{})
+	util.OK(t, err)
+	util.OK(t, CreateObjectSpecificClusterRoleBinding(context.TODO(), quotaRole, tenant.Spec.Contact.Handle, tenant.Spec.Contact.Email, map[string]string{}, []metav1.OwnerReference{}))
+	ownerBinding := fmt.Sprintf("%s-%s", ownerRole, tenant.Spec.Contact.Handle)
+	quotaBinding := fmt.Sprintf("%s-%s", quotaRole, tenant.Spec.Contact.Handle)
+
+	plan, err := DiffObjectSpecificRBAC(context.TODO())
+	util.OK(t, err)
+	util.Assert(t, plan.Empty(), "object-specific RBAC drifts right after its creation")
+
+	t.Run("drift", func(t *testing.T) {
+		role, err := g.client.RbacV1().ClusterRoles().Get(context.TODO(), ownerRole, metav1.GetOptions{})
+		util.OK(t, err)
+		role.Rules[0].Verbs = []string{"*"}
+		g.client.RbacV1().ClusterRoles().Update(context.TODO(), role, metav1.UpdateOptions{})
+		binding, err := g.client.RbacV1().ClusterRoleBindings().Get(context.TODO(), ownerBinding, metav1.GetOptions{})
+		util.OK(t, err)
+		binding.Subjects = append(binding.Subjects, rbacv1.Subject{Kind: "User", Name: "joe.public@edge-net.org", APIGroup: "rbac.authorization.k8s.io"})
+		g.client.RbacV1().ClusterRoleBindings().Update(context.TODO(), binding, metav1.UpdateOptions{})
+
+		util.OK(t, ReconcileObjectSpecificRBAC(context.TODO()))
+		role, err = g.client.RbacV1().ClusterRoles().Get(context.TODO(), ownerRole, metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, []string{"get", "update", "patch"}, role.Rules[0].Verbs)
+		binding, err = g.client.RbacV1().ClusterRoleBindings().Get(context.TODO(), ownerBinding, metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, 1, len(binding.Subjects))
+		util.Equals(t, tenant.Spec.Contact.Email, binding.Subjects[0].Name)
+	})
+	t.Run("orphan", func(t *testing.T) {
+		g.edgenetclient.CoreV1alpha().TenantResourceQuotas().Delete(context.TODO(), g.tenantResourceQuotaObj.GetName(), metav1.DeleteOptions{})
+		util.OK(t, ReconcileObjectSpecificRBAC(context.TODO()))
+		_, err := g.client.RbacV1().ClusterRoles().Get(context.TODO(), quotaRole, metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+		_, err = g.client.RbacV1().ClusterRoleBindings().Get(context.TODO(), quotaBinding, metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+		_, err = g.client.RbacV1().ClusterRoles().Get(context.TODO(), ownerRole, metav1.GetOptions{})
+		util.OK(t, err)
+	})
+	t.Run("former pattern", func(t *testing.T) {
+		role, err := g.client.RbacV1().ClusterRoles().Get(context.TODO(), ownerRole, metav1.GetOptions{})
+		util.OK(t, err)
+		former := role.DeepCopy()
+		former.SetName(fmt.Sprintf("%s-%s-owner", tenant.GetName(), tenant.GetName()))
+		former.SetResourceVersion("")
+		g.client.RbacV1().ClusterRoles().Create(context.TODO(), former, metav1.CreateOptions{})
+		util.OK(t, ReconcileObjectSpecificRBAC(context.TODO()))
+		_, err = g.client.RbacV1().ClusterRoles().Get(context.TODO(), former.GetName(), metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+	})
+	t.Run("removed tenant", func(t *testing.T) {
+		g.edgenetclient.CoreV1alpha().Tenants().Delete(context.TODO(), tenant.GetName(), metav1.DeleteOptions{})
+		util.OK(t, ReconcileObjectSpecificRBAC(context.TODO()))
+		_, err := g.client.RbacV1().ClusterRoles().Get(context.TODO(), ownerRole, metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+		_, err = g.client.RbacV1().ClusterRoleBindings().Get(context.TODO(), ownerBinding, metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+	})
+}
+
+func TestSyncGroupRoleBindings(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	tenant := g.tenantObj.DeepCopy()
+	tenant.Spec.GroupBindings = []corev1alpha.GroupBinding{
+		{Group: "lip6:faculty", Role: "owner"},
+		{Group: "lip6/students", Role: "collaborator"},
+	}
+
+	util.OK(t, SyncGroupRoleBindings(context.TODO(), tenant))
+	roleBindingRaw, err := g.client.RbacV1().RoleBindings(tenant.GetName()).List(context.TODO(), metav1.ListOptions{})
+	util.OK(t, err)
+	util.Equals(t, 2, len(roleBindingRaw.Items))
+	bindings := map[string]string{}
+	for _, roleBindingRow := range roleBindingRaw.Items {
+		util.Equals(t, "Group", roleBindingRow.Subjects[0].Kind)
+		bindings[roleBindingRow.Subjects[0].Name] = roleBindingRow.RoleRef.Name
+	}
+	util.Equals(t, map[string]string{"lip6:faculty": TenantOwnerRole, "lip6/students": TenantCollaboratorRole}, bindings)
+
+	t.Run("membership change", func(t *testing.T) {
+		tenant.Spec.GroupBindings = []corev1alpha.GroupBinding{{Group: "lip6:faculty", Role: "admin"}}
+		util.OK(t, SyncGroupRoleBindings(context.TODO(), tenant))
+		roleBindingRaw, err := g.client.RbacV1().RoleBindings(tenant.GetName()).List(context.TODO(), metav1.ListOptions{})
+		util.OK(t, err)
+		util.Equals(t, 1, len(roleBindingRaw.Items))
+		util.Equals(t, TenantAdminRole, roleBindingRaw.Items[0].RoleRef.Name)
+	})
+	t.Run("invalid role", func(t *testing.T) {
+		tenant.Spec.GroupBindings = []corev1alpha.GroupBinding{{Group: "lip6:faculty", Role: "admin"}, {Group: "lip6:staff", Role: "root"}}
+		util.Assert(t, SyncGroupRoleBindings(context.TODO(), tenant) != nil, "invalid role accepted")
+		roleBindingRaw, err := g.client.RbacV1().RoleBindings(tenant.GetName()).List(context.TODO(), metav1.ListOptions{})
+		util.OK(t, err)
+		util.Equals(t, 1, len(roleBindingRaw.Items))
+	})
+	t.Run("other bindings", func(t *testing.T) {
+		util.OK(t, CreateObjectSpecificRoleBinding(context.TODO(), tenant.GetName(), tenant.GetName(), TenantOwnerRole, tenant.Spec.Contact.Handle, tenant.Spec.Contact.Email))
+		tenant.Spec.GroupBindings = nil
+		util.OK(t, SyncGroupRoleBindings(context.TODO(), tenant))
+		roleBindingRaw, err := g.client.RbacV1().RoleBindings(tenant.GetName()).List(context.TODO(), metav1.ListOptions{})
+		util.OK(t, err)
+		util.Equals(t, 1, len(roleBindingRaw.Items))
+		util.Equals(t, "User", roleBindingRaw.Items[0].Subjects[0].Kind)
+	})
+}
+
+func TestSyncMemberRoleBindings(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	tenant := g.tenantObj.DeepCopy()
+	tenant.Spec.Owners = []corev1alpha.Contact{
+		{Handle: "johndoe", Email: "John.Doe@edge-net.org"},
+		{Handle: "janedoe", Email: "jane.doe@edge-net.org"},
+	}
+	tenant.Spec.Admins = []corev1alpha.Contact{{Handle: "joesmith", Email: "joe.smith@edge-net.org"}}
+	memberSelector := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=true", memberBindingLabel)}
+
+	util.OK(t, SyncMemberRoleBindings(context.TODO(), tenant))
+	roleBindingRaw, err := g.client.RbacV1().RoleBindings(tenant.GetName()).List(context.TODO(), memberSelector)
+	util.OK(t, err)
+	bindings := map[string]string{}
+	for _, roleBindingRow := range roleBindingRaw.Items {
+		bindings[roleBindingRow.Subjects[0].Name] = roleBindingRow.RoleRef.Name
+	}
+	// The contact listed among the owners has the owner role binding of its own
+	util.Equals(t, map[string]string{"jane.doe@edge-net.org": TenantOwnerRole, "joe.smith@edge-net.org": TenantAdminRole}, bindings)
+
+	t.Run("member removal", func(t *testing.T) {
+		tenant.Spec.Owners = nil
+		util.OK(t, SyncMemberRoleBindings(context.TODO(), tenant))
+		roleBindingRaw, err := g.client.RbacV1().RoleBindings(tenant.GetName()).List(context.TODO(), memberSelector)
+		util.OK(t, err)
+		util.Equals(t, 1, len(roleBindingRaw.Items))
+		util.Equals(t, TenantAdminRole, roleBindingRaw.Items[0].RoleRef.Name)
+	})
+	t.Run("missing email", func(t *testing.T) {
+		tenant.Spec.Admins = append(tenant.Spec.Admins, corev1alpha.Contact{Handle: "nobody"})
+		util.Assert(t, SyncMemberRoleBindings(context.TODO(), tenant) != nil, "member without email accepted")
+		roleBindingRaw, err := g.client.RbacV1().RoleBindings(tenant.GetName()).List(context.TODO(), memberSelector)
+		util.OK(t, err)
+		util.Equals(t, 1, len(roleBindingRaw.Items))
+	})
+}
+
+func TestServiceRoleBindings(t *testing.T) {
+	roleBindings := ServiceRoleBindings("lip6")
+	util.Equals(t, len(namespacedServices), len(roleBindings))
+	for i, roleBinding := range roleBindings {
+		util.Equals(t, "lip6", roleBinding.GetNamespace())
+		util.Equals(t, "ClusterRole", roleBinding.RoleRef.Kind)
+		util.Equals(t, fmt.Sprintf("edgenet:service:%s:namespaced", namespacedServices[i]), roleBinding.RoleRef.Name)
+		// The rights never reach beyond the namespace of the binding
+		util.Equals(t, []rbacv1.Subject{{Kind: "ServiceAccount", Name: namespacedServices[i], Namespace: ServiceNamespace}}, roleBinding.Subjects)
+	}
+}
+
+func TestRemindersDue(t *testing.T) {
+	intervals := ReminderIntervals
+	defer func() { ReminderIntervals = intervals }()
+	util.OK(t, ReminderIntervals.Set("2h, 24h"))
+	util.Equals(t, Durations{24 * time.Hour, 2 * time.Hour}, ReminderIntervals)
+	util.Equals(t, "24h0m0s,2h0m0s", ReminderIntervals.String())
+	util.Assert(t, ReminderIntervals.Set("-1h") != nil, "negative interval accepted")
+
+	now := time.Now()
+	cases := map[string]struct {
+		expiry   time.Time
+		expected int
+		next     time.Duration
+	}{
+		"none due":  {now.Add(72 * time.Hour), 0, 48 * time.Hour},
+		"first due": {now.Add(12 * time.Hour), 1, 10 * time.Hour},
+		"all due":   {now.Add(time.Hour), 2, 0},
+		"expired":   {now.Add(-time.Hour), 2, 0},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			due, next := RemindersDue(tc.expiry, now)
+			util.Equals(t, tc.expected, due)
+			util.Equals(t, tc.next, next)
+		})
+	}
+}
+
+func TestTenantTokenPolicy(t *testing.T) {
+	cases := map[string]struct {
+		priority *corev1alpha.Priority
+		expected TokenPolicy
+	}{
+		"no tier":      {nil, TokenPolicies["community"]},
+		"unknown tier": {&corev1alpha.Priority{Tier: "gold"}, TokenPolicies["community"]},
+		"paying":       {&corev1alpha.Priority{Tier: "paying"}, TokenPolicies["paying"]},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			tenant := &corev1alpha.Tenant{Spec: corev1alpha.TenantSpec{Priority: tc.priority}}
+			util.Equals(t, tc.expected, TenantTokenPolicy(tenant))
+		})
+	}
+	// The default service accounts never mount their token unless a tier is configured to
+	for tier, policy := range TokenPolicies {
+		util.Assert(t, !policy.Automount, "automount enabled in the %s tier", tier)
+	}
+}
@@ -19,17 +19,17 @@ package access
 import (
 	"context"
 	"fmt"
-	"log"
 
 	rbacv1 "k8s.io/api/rbac/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
 )
 
 var labels = map[string]string{"edge-net.io/generated": "true"}
 
 // CheckAuthorization returns true if the user is holder of a role
-func CheckAuthorization(namespace, email, resource, resourceName, scope string) bool {
+func CheckAuthorization(ctx context.Context, namespace, email, resource, resourceName, scope string) bool {
 	authorized := false
 
 	checkRules := func(rule rbacv1.PolicyRule) {
@@ -52,19 +52,19 @@ func CheckAuthorization(namespace, email, resource, resourceName, scope string)
 		}
 	}
 	if scope == "namespace" {
-		roleBindingRaw, _ := Clientset.RbacV1().RoleBindings(namespace).List(context.TODO(), metav1.ListOptions{})
+		roleBindingRaw, _ := Clientset.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{})
 		for _, roleBindingRow := range roleBindingRaw.Items {
 			for _, subject := range roleBindingRow.Subjects {
 				if subject.Kind == "User" && subject.Name == email {
 					if roleBindingRow.RoleRef.Kind == "Role" {
-						role, err := Clientset.RbacV1().Roles(namespace).Get(context.TODO(), roleBindingRow.RoleRef.Name, metav1.GetOptions{})
+						role, err := Clientset.RbacV1().Roles(namespace).Get(ctx, roleBindingRow.RoleRef.Name, metav1.GetOptions{})
 						if err == nil {
 							for _, rule := range role.Rules {
 								checkRules(rule)
 							}
 						}
 					} else if roleBindingRow.RoleRef.Kind == "ClusterRole" {
-						role, err := Clientset.RbacV1().ClusterRoles().Get(context.TODO(), roleBindingRow.RoleRef.Name, metav1.GetOptions{})
+						role, err := Clientset.RbacV1().ClusterRoles().Get(ctx, roleBindingRow.RoleRef.Name, metav1.GetOptions{})
 						if err == nil {
 							for _, rule := range role.Rules {
 								checkRules(rule)
@@ -75,11 +75,11 @@ func CheckAuthorization(namespace, email, resource, resourceName, scope string)
 			}
 		}
 	} else {
-		clusterRoleBindingRaw, _ := Clientset.RbacV1().ClusterRoleBindings().List(context.TODO(), metav1.ListOptions{})
+		clusterRoleBindingRaw, _ := Clientset.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
 		for _, clusterRoleBindingRow := range clusterRoleBindingRaw.Items {
 			for _, subject := range clusterRoleBindingRow.Subjects {
 				if subject.Kind == "User" && subject.Name == email {
-					clusterRole, err := Clientset.RbacV1().ClusterRoles().Get(context.TODO(), clusterRoleBindingRow.RoleRef.Name, metav1.GetOptions{})
+					clusterRole, err := Clientset.RbacV1().ClusterRoles().Get(ctx, clusterRoleBindingRow.RoleRef.Name, metav1.GetOptions{})
 					if err == nil {
 						for _, rule := range clusterRole.Rules {
 							checkRules(rule)
@@ -93,10 +93,28 @@ func CheckAuthorization(namespace, email, resource, resourceName, scope string)
 	return authorized
 }
 
-// CreateClusterRoles generate a cluster role for tenant owners, admins, and collaborators
-func CreateClusterRoles() error {
-	policyRule := []rbacv1.PolicyRule{{APIGroups: []string{"core.edgenet.io"}, Resources: []string{"subnamespaces"}, Verbs: []string{"*"}},
+// tenantOwnerPolicyRules are the rules that tenant owners hold in the namespaces of their tenant, the
+// rules of the admins along with the rosters, as the students are provisioned as admins
+func tenantOwnerPolicyRules() []rbacv1.PolicyRule {
+	return append(tenantAdminPolicyRules(),
+		rbacv1.PolicyRule{APIGroups: []string{"registration.edgenet.io"}, Resources: []string{"rosters"}, Verbs: []string{"*"}},
+		rbacv1.PolicyRule{APIGroups: []string{"registration.edgenet.io"}, Resources: []string{"rosters/status"}, Verbs: []string{"get", "list", "watch"}})
+}
+
+// tenantAdminPolicyRules are the rules that tenant admins hold in the namespaces of their tenant
+func tenantAdminPolicyRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{{APIGroups: []string{"core.edgenet.io"}, Resources: []string{"subnamespaces"}, Verbs: []string{"*"}},
 		{APIGroups: []string{"core.edgenet.io"}, Resources: []string{"subnamespaces/status"}, Verbs: []string{"get", "list", "watch"}},
+		{APIGroups: []string{"core.edgenet.io"}, Resources: []string{"tenantserviceaccounts"}, Verbs: []string{"*"}},
+		{APIGroups: []string{"core.edgenet.io"}, Resources: []string{"tenantserviceaccounts/status"}, Verbs: []string{"get", "list", "watch"}},
+		{APIGroups: []string{"core.edgenet.io"}, Resources: []string{"tenantsecretstores"}, Verbs: []string{"*"}},
+		{APIGroups: []string{"core.edgenet.io"}, Resources: []string{"tenantsecretstores/status"}, Verbs: []string{"get", "list", "watch"}},
+		{APIGroups: []string{"apps.edgenet.io"}, Resources: []string{"logins"}, Verbs: []string{"get", "list", "watch", "delete"}},
+		{APIGroups: []string{"apps.edgenet.io"}, Resources: []string{"tenantapps"}, Verbs: []string{"*"}},
+		{APIGroups: []string{"apps.edgenet.io"}, Resources: []string{"tenantapps/status"}, Verbs: []string{"get", "list", "watch"}},
+		{APIGroups: []string{"registration.edgenet.io"}, Resources: []string{"extensionrequests"}, Verbs: []string{"create", "get", "list", "watch", "delete"}},
+		{APIGroups: []string{"registration.edgenet.io"}, Resources: []string{"extensionrequests/status"}, Verbs: []string{"get", "list", "watch"}},
+		{APIGroups: []string{"views.edgenet.io"}, Resources: []string{"tenantsummaries"}, Verbs: []string{"get", "list"}},
 		{APIGroups: []string{"apps.edgenet.io"}, Resources: []string{"selectivedeployments"}, Verbs: []string{"*"}},
 		{APIGroups: []string{"rbac.authorization.k8s.io"}, Resources: []string{"roles", "rolebindings"}, Verbs: []string{"*"}},
 		{APIGroups: []string{""}, Resources: []string{"configmaps", "endpoints", "persistentvolumeclaims", "pods", "pods/exec", "pods/log", "pods/attach", "replicationcontrollers", "services", "secrets", "serviceaccounts"}, Verbs: []string{"*"}},
@@ -106,136 +124,59 @@ func CreateClusterRoles() error {
 		{APIGroups: []string{"extensions"}, Resources: []string{"daemonsets", "deployments", "ingresses", "networkpolicies", "replicasets", "replicationcontrollers"}, Verbs: []string{"*"}},
 		{APIGroups: []string{"networking.k8s.io"}, Resources: []string{"ingresses", "networkpolicies"}, Verbs: []string{"*"}},
 		{APIGroups: []string{""}, Resources: []string{"events", "controllerrevisions"}, Verbs: []string{"get", "list", "watch"}}}
-	ownerRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "edgenet:tenant-owner"}, Rules: policyRule}
-	ownerRole.SetLabels(labels)
-	_, err := Clientset.RbacV1().ClusterRoles().Create(context.TODO(), ownerRole, metav1.CreateOptions{})
-	if err != nil {
-		log.Printf("Couldn't create tenant owner cluster role: %s", err)
-		if errors.IsAlreadyExists(err) {
-			currentClusterRole, err := Clientset.RbacV1().ClusterRoles().Get(context.TODO(), ownerRole.GetName(), metav1.GetOptions{})
-			if err == nil {
-				currentClusterRole.Rules = policyRule
-				_, err = Clientset.RbacV1().ClusterRoles().Update(context.TODO(), currentClusterRole, metav1.UpdateOptions{})
-				if err == nil {
-					log.Println("Tenant owner cluster role updated")
-				} else {
-					return err
-				}
-			}
-		}
-	}
-	adminRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "edgenet:tenant-admin"}, Rules: policyRule}
-	adminRole.SetLabels(labels)
-	_, err = Clientset.RbacV1().ClusterRoles().Create(context.TODO(), adminRole, metav1.CreateOptions{})
-	if err != nil {
-		log.Printf("Couldn't create tenant admin cluster role: %s", err)
-		if errors.IsAlreadyExists(err) {
-			currentClusterRole, err := Clientset.RbacV1().ClusterRoles().Get(context.TODO(), adminRole.GetName(), metav1.GetOptions{})
-			if err == nil {
-				currentClusterRole.Rules = policyRule
-				_, err = Clientset.RbacV1().ClusterRoles().Update(context.TODO(), currentClusterRole, metav1.UpdateOptions{})
-				if err == nil {
-					log.Println("Tenant admin cluster role updated")
-				} else {
-					return err
-				}
-			}
-		}
-	}
+}
 
-	policyRule = []rbacv1.PolicyRule{{APIGroups: []string{"apps.edgenet.io"}, Resources: []string{"selectivedeployments"}, Verbs: []string{"*"}},
+// tenantCollaboratorPolicyRules are the rules that collaborators hold in the namespaces they take part in
+func tenantCollaboratorPolicyRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{{APIGroups: []string{"apps.edgenet.io"}, Resources: []string{"selectivedeployments"}, Verbs: []string{"*"}},
 		{APIGroups: []string{""}, Resources: []string{"configmaps", "endpoints", "persistentvolumeclaims", "pods", "pods/exec", "pods/log", "pods/attach", "replicationcontrollers", "services", "secrets", "serviceaccounts"}, Verbs: []string{"*"}},
 		{APIGroups: []string{"apps"}, Resources: []string{"daemonsets", "deployments", "replicasets", "statefulsets"}, Verbs: []string{"*"}},
 		{APIGroups: []string{"autoscaling"}, Resources: []string{"horizontalpodautoscalers"}, Verbs: []string{"*"}},
 		{APIGroups: []string{"batch"}, Resources: []string{"cronjobs", "jobs"}, Verbs: []string{"*"}},
 		{APIGroups: []string{"extensions"}, Resources: []string{"daemonsets", "deployments", "replicasets", "replicationcontrollers"}, Verbs: []string{"*"}},
 		{APIGroups: []string{""}, Resources: []string{"events", "controllerrevisions"}, Verbs: []string{"get", "list", "watch"}}}
-	collaboratorRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "edgenet:tenant-collaborator"}, Rules: policyRule}
-	collaboratorRole.SetLabels(labels)
-	_, err = Clientset.RbacV1().ClusterRoles().Create(context.TODO(), collaboratorRole, metav1.CreateOptions{})
-	if err != nil {
-		log.Printf("Couldn't create tenant collaborator cluster role: %s", err)
-		if errors.IsAlreadyExists(err) {
-			currentClusterRole, err := Clientset.RbacV1().ClusterRoles().Get(context.TODO(), collaboratorRole.GetName(), metav1.GetOptions{})
-			if err == nil {
-				currentClusterRole.Rules = policyRule
-				_, err = Clientset.RbacV1().ClusterRoles().Update(context.TODO(), currentClusterRole, metav1.UpdateOptions{})
-				if err == nil {
-					log.Println("Tenant collaborator cluster role updated")
-					return err
-				}
-			}
-		}
-	}
-
-	return err
 }
 
-// CreateObjectSpecificClusterRole generates a object specific cluster role to allow the user access
-func CreateObjectSpecificClusterRole(tenant, apiGroup, resource, resourceName, name string, verbs []string, ownerReferences []metav1.OwnerReference) (string, error) {
-	objectName := fmt.Sprintf("edgenet:%s:%s:%s-%s", tenant, resource, resourceName, name)
-	policyRule := []rbacv1.PolicyRule{{APIGroups: []string{apiGroup}, Resources: []string{resource}, ResourceNames: []string{resourceName}, Verbs: verbs},
+// objectSpecificPolicyRules give access to a single object and let read its status
+func objectSpecificPolicyRules(apiGroup, resource, resourceName string, verbs []string) []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{{APIGroups: []string{apiGroup}, Resources: []string{resource}, ResourceNames: []string{resourceName}, Verbs: verbs},
 		{APIGroups: []string{apiGroup}, Resources: []string{fmt.Sprintf("%s/status", resource)}, ResourceNames: []string{resourceName}, Verbs: []string{"get", "list", "watch"}},
 	}
-	role := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: objectName, OwnerReferences: ownerReferences},
-		Rules: policyRule}
-	roleLabels := map[string]string{"edge-net.io/tenant": tenant}
-	for key, value := range labels {
-		roleLabels[key] = value
-	}
-	role.SetLabels(roleLabels)
-	_, err := Clientset.RbacV1().ClusterRoles().Create(context.TODO(), role, metav1.CreateOptions{})
+}
+
+// CreateObjectSpecificClusterRole generates a object specific cluster role to allow the user access
+func CreateObjectSpecificClusterRole(ctx context.Context, tenant, apiGroup, resource, resourceName, name string, verbs []string, ownerReferences []metav1.OwnerReference) (string, error) {
+	role := objectSpecificClusterRole(objectSpec{Tenant: tenant, APIGroup: apiGroup, Resource: resource, ResourceName: resourceName, Name: name, Verbs: verbs}, ownerReferences)
+	objectName := role.GetName()
+	patch, err := ApplyPatch(role, rbacv1.SchemeGroupVersion.WithKind("ClusterRole"))
 	if err != nil {
-		log.Printf("Couldn't create %s cluster role: %s", objectName, err)
-		if errors.IsAlreadyExists(err) {
-			currentRole, err := Clientset.RbacV1().ClusterRoles().Get(context.TODO(), role.GetName(), metav1.GetOptions{})
-			if err == nil {
-				currentRole.Rules = policyRule
-				_, err = Clientset.RbacV1().ClusterRoles().Update(context.TODO(), currentRole, metav1.UpdateOptions{})
-				if err == nil {
-					log.Printf("Updated: %s cluster role updated", objectName)
-					return objectName, err
-				}
-			}
-		}
+		return objectName, err
+	}
+	// The roles created before the reconciler are adopted
+	if _, err := Clientset.RbacV1().ClusterRoles().Patch(ctx, objectName, types.ApplyPatchType, patch, ApplyOptions()); err != nil {
+		klog.ErrorS(err, "Couldn't apply cluster role", "objectName", objectName)
+		return objectName, err
 	}
-	return objectName, err
+	return objectName, nil
 }
 
 // CreateObjectSpecificClusterRoleBinding links the cluster role up with the user
-func CreateObjectSpecificClusterRoleBinding(roleName, initialHandle, email string, roleBindLabels map[string]string, ownerReferences []metav1.OwnerReference) error {
-	objectName := fmt.Sprintf("%s-%s", roleName, initialHandle)
-	roleRef := rbacv1.RoleRef{Kind: "ClusterRole", Name: roleName}
-	rbSubjects := []rbacv1.Subject{{Kind: "User", Name: email, APIGroup: "rbac.authorization.k8s.io"}}
-	roleBind := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: objectName},
-		Subjects: rbSubjects, RoleRef: roleRef}
-	roleBind.ObjectMeta.OwnerReferences = ownerReferences
-	for key, value := range labels {
-		roleBindLabels[key] = value
-	}
-	roleBind.SetLabels(roleBindLabels)
-	_, err := Clientset.RbacV1().ClusterRoleBindings().Create(context.TODO(), roleBind, metav1.CreateOptions{})
+func CreateObjectSpecificClusterRoleBinding(ctx context.Context, roleName, initialHandle, email string, roleBindLabels map[string]string, ownerReferences []metav1.OwnerReference) error {
+	roleBind := objectSpecificClusterRoleBinding(roleName, initialHandle, email, roleBindLabels, ownerReferences)
+	objectName := roleBind.GetName()
+	patch, err := ApplyPatch(roleBind, rbacv1.SchemeGroupVersion.WithKind("ClusterRoleBinding"))
 	if err != nil {
-		log.Printf("Couldn't create %s cluster role binding: %s", objectName, err)
-		if errors.IsAlreadyExists(err) {
-			currentRoleBind, err := Clientset.RbacV1().ClusterRoleBindings().Get(context.TODO(), roleBind.GetName(), metav1.GetOptions{})
-			if err == nil {
-				currentRoleBind.Subjects = rbSubjects
-				currentRoleBind.RoleRef = roleRef
-				_, err = Clientset.RbacV1().ClusterRoleBindings().Update(context.TODO(), currentRoleBind, metav1.UpdateOptions{})
-				if err == nil {
-					log.Printf("Updated: %s cluster role binding updated", objectName)
-					return err
-				}
-			}
-		}
 		return err
 	}
-	return err
+	if _, err := Clientset.RbacV1().ClusterRoleBindings().Patch(ctx, objectName, types.ApplyPatchType, patch, ApplyOptions()); err != nil {
+		klog.ErrorS(err, "Couldn't apply cluster role binding", "objectName", objectName)
+		return err
+	}
+	return nil
 }
 
 // CreateObjectSpecificRoleBinding links the cluster role up with the user
-func CreateObjectSpecificRoleBinding(tenant, namespace, roleName, initialHandle, email string) error {
+func CreateObjectSpecificRoleBinding(ctx context.Context, tenant, namespace, roleName, initialHandle, email string) error {
 	objectName := fmt.Sprintf("%s-%s", roleName, initialHandle)
 	roleRef := rbacv1.RoleRef{Kind: "ClusterRole", Name: roleName}
 	rbSubjects := []rbacv1.Subject{{Kind: "User", Name: email, APIGroup: "rbac.authorization.k8s.io"}}
@@ -247,27 +188,19 @@ func CreateObjectSpecificRoleBinding(tenant, namespace, roleName, initialHandle,
 		roleBindLabels[key] = value
 	}
 	roleBind.SetLabels(roleBindLabels)
-	_, err := Clientset.RbacV1().RoleBindings(namespace).Create(context.TODO(), roleBind, metav1.CreateOptions{})
+	patch, err := ApplyPatch(roleBind, rbacv1.SchemeGroupVersion.WithKind("RoleBinding"))
 	if err != nil {
-		log.Printf("Couldn't create %s role binding: %s", objectName, err)
-		if errors.IsAlreadyExists(err) {
-			currentRoleBind, err := Clientset.RbacV1().RoleBindings(namespace).Get(context.TODO(), roleBind.GetName(), metav1.GetOptions{})
-			if err == nil {
-				currentRoleBind.Subjects = rbSubjects
-				currentRoleBind.RoleRef = roleRef
-				_, err = Clientset.RbacV1().RoleBindings(namespace).Update(context.TODO(), currentRoleBind, metav1.UpdateOptions{})
-				if err == nil {
-					log.Printf("Updated: %s role binding updated", objectName)
-					return err
-				}
-			}
-		}
+		return err
+	}
+	if _, err := Clientset.RbacV1().RoleBindings(namespace).Patch(ctx, objectName, types.ApplyPatchType, patch, ApplyOptions()); err != nil {
+		klog.ErrorS(err, "Couldn't apply role binding", "objectName", objectName)
+		return err
 	}
-	return err
+	return nil
 }
 
 // SendTenantEmail to send notification to participants
-/*func SendTenantEmail(tenant *corev1alpha.Tenant, user *registrationv1alpha.UserRequest, subject string) {
+/*func SendTenantEmail(ctx context.Context, tenant *corev1alpha.Tenant, user *registrationv1alpha.UserRequest, subject string) {
 	// Set the HTML template variables
 	contentData := mailer.CommonContentData{}
 	if tenant == nil {
@@ -280,7 +213,7 @@ func CreateObjectSpecificRoleBinding(tenant, namespace, roleName, initialHandle,
 	} else {
 		contentData.CommonData.Tenant = tenant.GetName()
 		if user == nil {
-			if tenantRequest, err := EdgenetClientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), tenant.GetName(), metav1.GetOptions{}); err == nil {
+			if tenantRequest, err := EdgenetClientset.RegistrationV1alpha().TenantRequests().Get(ctx, tenant.GetName(), metav1.GetOptions{}); err == nil {
 				contentData.CommonData.Username = tenantRequest.Spec.Contact.Username
 			}
 			contentData.CommonData.Name = fmt.Sprintf("%s %s", tenant.Spec.Contact.FirstName, tenant.Spec.Contact.LastName)
@@ -290,7 +223,7 @@ func CreateObjectSpecificRoleBinding(tenant, namespace, roleName, initialHandle,
 			usernameHash := fmt.Sprintf("%s-%s", user.GetName(), userLabels["edge-net.io/user-template-hash"])
 			contentData.CommonData.Username = usernameHash
 			contentData.CommonData.Name = fmt.Sprintf("%s %s", user.Spec.FirstName, user.Spec.LastName)
-			if acceptableUsePolicyRaw, err := EdgenetClientset.CoreV1alpha().AcceptableUsePolicies().List(context.TODO(), metav1.ListOptions{LabelSelector: fmt.Sprintf("edge-net.io/generated=true,edge-net.io/tenant=%s,edge-net.io/identity=true", tenant.GetName())}); err == nil {
+			if acceptableUsePolicyRaw, err := EdgenetClientset.CoreV1alpha().AcceptableUsePolicies().List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("edge-net.io/generated=true,edge-net.io/tenant=%s,edge-net.io/identity=true", tenant.GetName())}); err == nil {
 				for _, acceptableUsePolicyRow := range acceptableUsePolicyRaw.Items {
 					aupLabels := acceptableUsePolicyRow.GetLabels()
 					if aupLabels != nil && aupLabels["edge-net.io/username"] != "" && aupLabels["edge-net.io/user-template-hash"] != "" {
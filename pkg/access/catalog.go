@@ -0,0 +1,151 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/klog/v2"
+)
+
+// The cluster roles that the controllers bind to, a catalog has to define them
+const (
+	TenantOwnerRole        = "edgenet:tenant-owner"
+	TenantAdminRole        = "edgenet:tenant-admin"
+	TenantCollaboratorRole = "edgenet:tenant-collaborator"
+)
+
+// catalogLabel marks the cluster roles that come from the catalog, the ones no longer in
+// the catalog are removed
+const catalogLabel = "edge-net.io/catalog"
+
+// CatalogPath is the file of the cluster role catalog, the built-in catalog is in use when empty.
+// The file is read at every reconcile so that the edits take effect without a restart.
+var CatalogPath string
+
+// Catalog declares the cluster roles granted to the tenant members
+type Catalog struct {
+	Roles []CatalogRole `json:"roles"`
+}
+
+// CatalogRole is a cluster role of the catalog
+type CatalogRole struct {
+	Name  string              `json:"name"`
+	Rules []rbacv1.PolicyRule `json:"rules"`
+}
+
+var (
+	catalog      = DefaultCatalog()
+	catalogMutex sync.RWMutex
+)
+
+// DefaultCatalog returns the built-in catalog
+func DefaultCatalog() Catalog {
+	return Catalog{Roles: []CatalogRole{
+		{Name: TenantOwnerRole, Rules: tenantOwnerPolicyRules()},
+		{Name: TenantAdminRole, Rules: tenantAdminPolicyRules()},
+		{Name: TenantCollaboratorRole, Rules: tenantCollaboratorPolicyRules()},
+	}}
+}
+
+// Validate checks that the catalog defines the roles the controllers depend on, with unique names
+func (c Catalog) Validate() error {
+	names := map[string]bool{}
+	for _, role := range c.Roles {
+		if !strings.HasPrefix(role.Name, "edgenet:") {
+			return fmt.Errorf("role %q must have the edgenet: prefix", role.Name)
+		}
+		if names[role.Name] {
+			return fmt.Errorf("role %q is defined more than once", role.Name)
+		}
+		names[role.Name] = true
+	}
+	for _, required := range []string{TenantOwnerRole, TenantAdminRole, TenantCollaboratorRole} {
+		if !names[required] {
+			return fmt.Errorf("role %q is missing", required)
+		}
+	}
+	return nil
+}
+
+// ReadCatalog decodes and validates a catalog written in YAML or JSON
+func ReadCatalog(path string) (Catalog, error) {
+	loaded := Catalog{}
+	file, err := os.Open(path)
+	if err != nil {
+		return loaded, err
+	}
+	defer file.Close()
+	if err := yaml.NewYAMLOrJSONDecoder(file, 4096).Decode(&loaded); err != nil {
+		return loaded, err
+	}
+	return loaded, loaded.Validate()
+}
+
+// loadCatalog replaces the catalog in use with the content of the catalog file, the catalog
+// in use is kept when the file is unreadable or invalid
+func loadCatalog() {
+	if CatalogPath == "" {
+		return
+	}
+	loaded, err := ReadCatalog(CatalogPath)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't load the cluster role catalog", "catalogPath", CatalogPath)
+		return
+	}
+	catalogMutex.Lock()
+	catalog = loaded
+	catalogMutex.Unlock()
+}
+
+// SetCatalog replaces the catalog in use
+func SetCatalog(c Catalog) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	catalogMutex.Lock()
+	catalog = c
+	catalogMutex.Unlock()
+	return nil
+}
+
+// currentCatalog returns the catalog in use
+func currentCatalog() Catalog {
+	catalogMutex.RLock()
+	defer catalogMutex.RUnlock()
+	return catalog
+}
+
+// ReconcileClusterRoles brings the cluster roles in line with the catalog, it corrects the
+// drift of the live roles and removes the roles dropped from the catalog
+func ReconcileClusterRoles(ctx context.Context) error {
+	loadCatalog()
+	plan, err := DiffClusterRoles(ctx)
+	if err != nil {
+		return err
+	}
+	for _, change := range plan.Changes {
+		klog.InfoS("Reconciling the cluster role catalog", "change", change)
+	}
+	return ApplyPlan(ctx, plan)
+}
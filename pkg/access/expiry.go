@@ -0,0 +1,77 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReminderIntervals are the times before the expiry of a pending request at which the requester
+// is reminded of it
+var ReminderIntervals = Durations{24 * time.Hour, 2 * time.Hour}
+
+// Durations is a list of durations set by a flag of comma-separated values, such as '24h,2h'
+type Durations []time.Duration
+
+// String returns the durations as comma-separated values
+func (d *Durations) String() string {
+	values := []string{}
+	for _, duration := range *d {
+		values = append(values, duration.String())
+	}
+	return strings.Join(values, ",")
+}
+
+// Set parses the comma-separated durations, the longest one comes first
+func (d *Durations) Set(value string) error {
+	durations := Durations{}
+	for _, field := range strings.Split(value, ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(field)
+		if err != nil {
+			return err
+		}
+		if duration <= 0 {
+			return fmt.Errorf("duration %s is not positive", field)
+		}
+		durations = append(durations, duration)
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] > durations[j] })
+	*d = durations
+	return nil
+}
+
+// RemindersDue returns how many of the reminders before the expiry are due by now, along with
+// the time left until the next one falls due, zero if there is none left
+func RemindersDue(expiry, now time.Time) (int, time.Duration) {
+	remaining := expiry.Sub(now)
+	due := 0
+	var next time.Duration
+	for _, interval := range ReminderIntervals {
+		if remaining <= interval {
+			due++
+		} else if until := remaining - interval; next == 0 || until < next {
+			next = until
+		}
+	}
+	return due, next
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// groupBindingLabel marks the role bindings generated for the identity-provider groups of a tenant
+const groupBindingLabel = "edge-net.io/group-binding"
+
+// groupRoles are the cluster roles that the identity-provider groups can be mapped to
+var groupRoles = map[string]string{
+	"owner":        TenantOwnerRole,
+	"admin":        TenantAdminRole,
+	"collaborator": TenantCollaboratorRole,
+}
+
+// groupRoleBinding returns the role binding of a tenant role to an identity-provider group, the
+// group name may hold characters that an object name cannot so it is hashed
+func groupRoleBinding(tenant, roleName, group string) *rbacv1.RoleBinding {
+	hash := sha256.Sum256([]byte(group))
+	roleBind := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s:group-%s", roleName, hex.EncodeToString(hash[:])[:10]), Namespace: tenant},
+		Subjects: []rbacv1.Subject{{Kind: "Group", Name: group, APIGroup: "rbac.authorization.k8s.io"}},
+		RoleRef:  rbacv1.RoleRef{Kind: "ClusterRole", Name: roleName}}
+	roleBindLabels := map[string]string{"edge-net.io/tenant": tenant, groupBindingLabel: "true"}
+	for key, value := range labels {
+		roleBindLabels[key] = value
+	}
+	roleBind.SetLabels(roleBindLabels)
+	return roleBind
+}
+
+// SyncGroupRoleBindings binds the tenant roles to the identity-provider groups listed by the tenant
+// in its core namespace, and removes the bindings of the groups no longer listed
+func SyncGroupRoleBindings(ctx context.Context, tenant *corev1alpha.Tenant) error {
+	roleBindings := Clientset.RbacV1().RoleBindings(tenant.GetName())
+	desired := map[string]bool{}
+	var syncErr error
+	for _, groupBinding := range tenant.Spec.GroupBindings {
+		roleName, ok := groupRoles[groupBinding.Role]
+		if !ok || groupBinding.Group == "" {
+			syncErr = fmt.Errorf("invalid group binding %q to role %q", groupBinding.Group, groupBinding.Role)
+			continue
+		}
+		roleBind := groupRoleBinding(tenant.GetName(), roleName, groupBinding.Group)
+		desired[roleBind.GetName()] = true
+		current, err := roleBindings.Get(ctx, roleBind.GetName(), metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			_, err = roleBindings.Create(ctx, roleBind, metav1.CreateOptions{})
+		} else if err == nil && !reflect.DeepEqual(current.Subjects, roleBind.Subjects) {
+			current.Subjects = roleBind.Subjects
+			_, err = roleBindings.Update(ctx, current, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			syncErr = err
+		}
+	}
+
+	roleBindingRaw, err := roleBindings.List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=true", groupBindingLabel)})
+	if err != nil {
+		return err
+	}
+	for _, roleBindingRow := range roleBindingRaw.Items {
+		if !desired[roleBindingRow.GetName()] {
+			if err := roleBindings.Delete(ctx, roleBindingRow.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				syncErr = err
+			}
+		}
+	}
+	return syncErr
+}
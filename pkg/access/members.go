@@ -0,0 +1,103 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// memberBindingLabel marks the role bindings generated for the owners and the administrators
+// listed by a tenant
+const memberBindingLabel = "edge-net.io/member-binding"
+
+// memberRoleBinding returns the role binding of a tenant role to a single user, the binding is
+// named after the hash of the email so that it stays the same when the other details change
+func memberRoleBinding(tenant, roleName, email string) *rbacv1.RoleBinding {
+	hash := sha256.Sum256([]byte(strings.ToLower(email)))
+	roleBind := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s:user-%s", roleName, hex.EncodeToString(hash[:])[:10]), Namespace: tenant},
+		Subjects: []rbacv1.Subject{{Kind: "User", Name: email, APIGroup: "rbac.authorization.k8s.io"}},
+		RoleRef:  rbacv1.RoleRef{Kind: "ClusterRole", Name: roleName}}
+	roleBindLabels := map[string]string{"edge-net.io/tenant": tenant, memberBindingLabel: "true"}
+	for key, value := range labels {
+		roleBindLabels[key] = value
+	}
+	roleBind.SetLabels(roleBindLabels)
+	return roleBind
+}
+
+// SyncMemberRoleBindings binds the tenant roles to each owner and administrator listed by the
+// tenant in its core namespace, and removes the bindings of the members no longer listed. The
+// contact of the tenant has the owner role binding of its own.
+func SyncMemberRoleBindings(ctx context.Context, tenant *corev1alpha.Tenant) error {
+	roleBindings := Clientset.RbacV1().RoleBindings(tenant.GetName())
+	desired := map[string]*rbacv1.RoleBinding{}
+	var syncErr error
+	add := func(roleName string, member corev1alpha.Contact) {
+		if member.Email == "" {
+			syncErr = fmt.Errorf("member %q of role %q has no email", member.Handle, roleName)
+			return
+		}
+		if strings.EqualFold(member.Email, tenant.Spec.Contact.Email) {
+			return
+		}
+		roleBind := memberRoleBinding(tenant.GetName(), roleName, member.Email)
+		desired[roleBind.GetName()] = roleBind
+	}
+	for _, owner := range tenant.Spec.Owners {
+		add(TenantOwnerRole, owner)
+	}
+	for _, admin := range tenant.Spec.Admins {
+		add(TenantAdminRole, admin)
+	}
+
+	for name, roleBind := range desired {
+		current, err := roleBindings.Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			_, err = roleBindings.Create(ctx, roleBind, metav1.CreateOptions{})
+		} else if err == nil && !reflect.DeepEqual(current.Subjects, roleBind.Subjects) {
+			current.Subjects = roleBind.Subjects
+			_, err = roleBindings.Update(ctx, current, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			syncErr = err
+		}
+	}
+
+	roleBindingRaw, err := roleBindings.List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=true", memberBindingLabel)})
+	if err != nil {
+		return err
+	}
+	for _, roleBindingRow := range roleBindingRaw.Items {
+		if _, ok := desired[roleBindingRow.GetName()]; !ok {
+			if err := roleBindings.Delete(ctx, roleBindingRow.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				syncErr = err
+			}
+		}
+	}
+	return syncErr
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Label of the network policies installed by ApplyBaselineClusterPolicies
+const baselinePolicyLabel = "edge-net.io/baseline-policy"
+
+// Private ranges of each address family, they are left out of the traffic allowed to and from
+// outside the cluster. The IPv6 ranges are the unique local and the link-local addresses.
+var (
+	PrivateIPv4Ranges = []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"}
+	PrivateIPv6Ranges = []string{"fc00::/7", "fe80::/10"}
+)
+
+// SetPrivateRanges replaces the private ranges with a comma-separated list of CIDRs of both
+// address families. A family left out of the list has no private range.
+func SetPrivateRanges(value string) error {
+	ipv4Ranges, ipv6Ranges := []string{}, []string{}
+	for _, cidr := range strings.Split(value, ",") {
+		if cidr = strings.TrimSpace(cidr); cidr == "" {
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return err
+		}
+		if ipNet.IP.To4() != nil {
+			ipv4Ranges = append(ipv4Ranges, ipNet.String())
+		} else {
+			ipv6Ranges = append(ipv6Ranges, ipNet.String())
+		}
+	}
+	PrivateIPv4Ranges, PrivateIPv6Ranges = ipv4Ranges, ipv6Ranges
+	return nil
+}
+
+// PrivateRanges returns the private ranges of both address families
+func PrivateRanges() []string {
+	return append(append([]string{}, PrivateIPv4Ranges...), PrivateIPv6Ranges...)
+}
+
+// ExternalPeers returns the peers standing for the addresses outside the cluster, one IP block per
+// address family as the exceptions of a block must fall within it
+func ExternalPeers() []networkingv1.NetworkPolicyPeer {
+	return []networkingv1.NetworkPolicyPeer{
+		{IPBlock: &networkingv1.IPBlock{CIDR: "0.0.0.0/0", Except: PrivateIPv4Ranges}},
+		{IPBlock: &networkingv1.IPBlock{CIDR: "::/0", Except: PrivateIPv6Ranges}},
+	}
+}
+
+// EgressPeers returns the peers outside the cluster the pods of a tenant reach, the addresses listed
+// by its egress if it has one and all the external addresses otherwise
+func EgressPeers(egress *corev1alpha.Egress) ([]networkingv1.NetworkPolicyPeer, error) {
+	if egress == nil {
+		return ExternalPeers(), nil
+	}
+	peers := []networkingv1.NetworkPolicyPeer{}
+	for _, cidr := range egress.CIDRs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: ipNet.String()}})
+	}
+	return peers, nil
+}
+
+// EgressRules returns the egress rules of a tenant namespace: the DNS, the peer of the tenant, and
+// the destinations outside the cluster the tenant reaches
+func EgressRules(tenantPeer networkingv1.NetworkPolicyPeer, egress *corev1alpha.Egress) ([]networkingv1.NetworkPolicyEgressRule, error) {
+	egressPeers, err := EgressPeers(egress)
+	if err != nil {
+		return nil, err
+	}
+	dnsPort := intstr.FromInt(53)
+	udp := corev1.ProtocolUDP
+	tcp := corev1.ProtocolTCP
+	return []networkingv1.NetworkPolicyEgressRule{
+		{
+			To: []networkingv1.NetworkPolicyPeer{
+				{
+					NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "kube-system"}},
+					PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"k8s-app": "kube-dns"}},
+				},
+			},
+			Ports: []networkingv1.NetworkPolicyPort{{Protocol: &udp, Port: &dnsPort}, {Protocol: &tcp, Port: &dnsPort}},
+		},
+		{To: append([]networkingv1.NetworkPolicyPeer{tenantPeer}, egressPeers...)},
+	}, nil
+}
+
+// ApplyBaselineClusterPolicies installs the network policies that deny the ingress traffic to the namespace by default.
+// Only the namespaces of the same tenant and the external traffic to the ports of the tenant are let in, and the egress traffic
+// is limited to the DNS, the namespaces of the same tenant, and the destinations outside the cluster the egress of the
+// tenant lists, all of them if it has none.
+func ApplyBaselineClusterPolicies(ctx context.Context, namespace, tenant, tenantUID, clusterUID string, egress *corev1alpha.Egress, ownerReferences []metav1.OwnerReference) error {
+	tenantPeer := networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"edge-net.io/tenant":      tenant,
+				"edge-net.io/tenant-uid":  tenantUID,
+				"edge-net.io/cluster-uid": clusterUID,
+			},
+		},
+	}
+	externalPeers := ExternalPeers()
+	// The tenant is only reached on the ports allocated to it
+	portRange, allocated, err := LookupPortRange(ctx, tenant)
+	if err != nil {
+		return err
+	} else if !allocated {
+		portRange = NodePortRange()
+	}
+	nodePort := intstr.FromInt(int(portRange.First))
+	nodePortEnd := portRange.Last
+	egressRules, err := EgressRules(tenantPeer, egress)
+	if err != nil {
+		return err
+	}
+
+	defaultDeny := new(networkingv1.NetworkPolicy)
+	defaultDeny.SetName("default-deny-ingress")
+	defaultDeny.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+
+	allowTenant := new(networkingv1.NetworkPolicy)
+	allowTenant.SetName("allow-tenant-ingress")
+	allowTenant.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+	allowTenant.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{
+		{From: []networkingv1.NetworkPolicyPeer{tenantPeer}},
+		{
+			From:  externalPeers,
+			Ports: []networkingv1.NetworkPolicyPort{{Port: &nodePort, EndPort: &nodePortEnd}},
+		},
+	}
+
+	allowDNS := new(networkingv1.NetworkPolicy)
+	allowDNS.SetName("allow-dns-egress")
+	allowDNS.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}
+	allowDNS.Spec.Egress = egressRules
+
+	for _, networkPolicy := range []*networkingv1.NetworkPolicy{defaultDeny, allowTenant, allowDNS} {
+		networkPolicy.SetNamespace(namespace)
+		networkPolicy.SetLabels(map[string]string{"edge-net.io/generated": "true", baselinePolicyLabel: "true"})
+		networkPolicy.SetOwnerReferences(ownerReferences)
+		patch, err := ApplyPatch(networkPolicy, networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"))
+		if err != nil {
+			return err
+		}
+		if _, err := Clientset.NetworkingV1().NetworkPolicies(namespace).Patch(ctx, networkPolicy.GetName(), types.ApplyPatchType, patch, ApplyOptions()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveBaselineClusterPolicies deletes the network policies installed by ApplyBaselineClusterPolicies
+func RemoveBaselineClusterPolicies(ctx context.Context, namespace string) error {
+	networkPolicyRaw, err := Clientset.NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{LabelSelector: baselinePolicyLabel + "=true"})
+	if err != nil {
+		return err
+	}
+	for _, networkPolicyRow := range networkPolicyRaw.Items {
+		if err := Clientset.NetworkingV1().NetworkPolicies(namespace).Delete(ctx, networkPolicyRow.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
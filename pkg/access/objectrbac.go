@@ -0,0 +1,192 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// The object-specific cluster roles and bindings are selected by the label, and the annotation
+// holds what they are generated from so that they can be restored
+const (
+	objectSpecificLabel      = "edge-net.io/object-specific"
+	objectSpecificAnnotation = "edge-net.io/object-specific"
+	objectSubjectAnnotation  = "edge-net.io/object-subject"
+)
+
+// objectSpec describes the object an object-specific cluster role gives access to
+type objectSpec struct {
+	Tenant       string   `json:"tenant"`
+	APIGroup     string   `json:"apiGroup"`
+	Resource     string   `json:"resource"`
+	ResourceName string   `json:"resourceName"`
+	Name         string   `json:"name"`
+	Verbs        []string `json:"verbs"`
+}
+
+// roleName returns the name of the cluster role generated from the spec
+func (s objectSpec) roleName() string {
+	return fmt.Sprintf("edgenet:%s:%s:%s-%s", s.Tenant, s.Resource, s.ResourceName, s.Name)
+}
+
+// objectSpecificClusterRole returns the cluster role giving access to a single object
+func objectSpecificClusterRole(spec objectSpec, ownerReferences []metav1.OwnerReference) *rbacv1.ClusterRole {
+	role := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: spec.roleName(), OwnerReferences: ownerReferences},
+		Rules: objectSpecificPolicyRules(spec.APIGroup, spec.Resource, spec.ResourceName, spec.Verbs)}
+	roleLabels := map[string]string{"edge-net.io/tenant": spec.Tenant, objectSpecificLabel: "true"}
+	for key, value := range labels {
+		roleLabels[key] = value
+	}
+	role.SetLabels(roleLabels)
+	encoded, _ := json.Marshal(spec)
+	role.SetAnnotations(map[string]string{objectSpecificAnnotation: string(encoded)})
+	return role
+}
+
+// objectSpecificClusterRoleBinding returns the binding of an object-specific cluster role to a user
+func objectSpecificClusterRoleBinding(roleName, initialHandle, email string, roleBindLabels map[string]string, ownerReferences []metav1.OwnerReference) *rbacv1.ClusterRoleBinding {
+	roleBind := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-%s", roleName, initialHandle), OwnerReferences: ownerReferences},
+		Subjects: []rbacv1.Subject{{Kind: "User", Name: email, APIGroup: "rbac.authorization.k8s.io"}},
+		RoleRef:  rbacv1.RoleRef{Kind: "ClusterRole", Name: roleName}}
+	bindLabels := map[string]string{objectSpecificLabel: "true"}
+	for key, value := range roleBindLabels {
+		bindLabels[key] = value
+	}
+	for key, value := range labels {
+		bindLabels[key] = value
+	}
+	roleBind.SetLabels(bindLabels)
+	roleBind.SetAnnotations(map[string]string{objectSubjectAnnotation: email})
+	return roleBind
+}
+
+// parentExists tells whether the object that a cluster role gives access to still exists,
+// the objects of the kinds not known here are assumed to exist
+func parentExists(ctx context.Context, spec objectSpec) (bool, error) {
+	var err error
+	if _, err = EdgenetClientset.CoreV1alpha().Tenants().Get(ctx, spec.Tenant, metav1.GetOptions{}); err == nil {
+		switch fmt.Sprintf("%s/%s", spec.APIGroup, spec.Resource) {
+		case "core.edgenet.io/tenants":
+			// The tenant itself is already found
+		case "core.edgenet.io/tenantresourcequotas":
+			_, err = EdgenetClientset.CoreV1alpha().TenantResourceQuotas().Get(ctx, spec.ResourceName, metav1.GetOptions{})
+		case "core.edgenet.io/nodecontributions":
+			_, err = EdgenetClientset.CoreV1alpha().NodeContributions().Get(ctx, spec.ResourceName, metav1.GetOptions{})
+		}
+	}
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// DiffObjectSpecificRBAC compares the live object-specific cluster roles and bindings with what
+// they are generated from. The drifted ones are restored, and the ones of removed objects, of a
+// former naming pattern, or bound to a missing role are to be removed.
+func DiffObjectSpecificRBAC(ctx context.Context) (Plan, error) {
+	plan := Plan{}
+	selector := metav1.ListOptions{LabelSelector: fmt.Sprintf("edge-net.io/generated=true,%s=true", objectSpecificLabel)}
+	clusterRoleRaw, err := Clientset.RbacV1().ClusterRoles().List(ctx, selector)
+	if err != nil {
+		return plan, err
+	}
+	kept := map[string]bool{}
+	for i, clusterRoleRow := range clusterRoleRaw.Items {
+		spec := objectSpec{}
+		if err := json.Unmarshal([]byte(clusterRoleRow.GetAnnotations()[objectSpecificAnnotation]), &spec); err != nil || spec.roleName() != clusterRoleRow.GetName() {
+			plan.Changes = append(plan.Changes, Change{Action: Remove, Kind: kindClusterRole, Object: &clusterRoleRaw.Items[i]})
+			continue
+		}
+		exists, err := parentExists(ctx, spec)
+		if err != nil {
+			return plan, err
+		}
+		if !exists {
+			plan.Changes = append(plan.Changes, Change{Action: Remove, Kind: kindClusterRole, Object: &clusterRoleRaw.Items[i]})
+			continue
+		}
+		kept[clusterRoleRow.GetName()] = true
+		desired := objectSpecificClusterRole(spec, clusterRoleRow.GetOwnerReferences())
+		if !reflect.DeepEqual(clusterRoleRow.Rules, desired.Rules) {
+			current := clusterRoleRow.DeepCopy()
+			current.Rules = desired.Rules
+			plan.Changes = append(plan.Changes, Change{Action: Update, Kind: kindClusterRole, Object: current})
+		}
+	}
+
+	clusterRoleBindingRaw, err := Clientset.RbacV1().ClusterRoleBindings().List(ctx, selector)
+	if err != nil {
+		return plan, err
+	}
+	for i, clusterRoleBindingRow := range clusterRoleBindingRaw.Items {
+		if !kept[clusterRoleBindingRow.RoleRef.Name] {
+			if isPruned(plan, clusterRoleBindingRow.RoleRef.Name) {
+				plan.Changes = append(plan.Changes, Change{Action: Remove, Kind: kindClusterRoleBinding, Object: &clusterRoleBindingRaw.Items[i]})
+				continue
+			}
+			// A binding may refer to a role that is not object-specific
+			if _, err := Clientset.RbacV1().ClusterRoles().Get(ctx, clusterRoleBindingRow.RoleRef.Name, metav1.GetOptions{}); errors.IsNotFound(err) {
+				plan.Changes = append(plan.Changes, Change{Action: Remove, Kind: kindClusterRoleBinding, Object: &clusterRoleBindingRaw.Items[i]})
+				continue
+			} else if err != nil {
+				return plan, err
+			}
+		}
+		email := clusterRoleBindingRow.GetAnnotations()[objectSubjectAnnotation]
+		if email == "" {
+			continue
+		}
+		subjects := []rbacv1.Subject{{Kind: "User", Name: email, APIGroup: "rbac.authorization.k8s.io"}}
+		if !reflect.DeepEqual(clusterRoleBindingRow.Subjects, subjects) {
+			current := clusterRoleBindingRow.DeepCopy()
+			current.Subjects = subjects
+			plan.Changes = append(plan.Changes, Change{Action: Update, Kind: kindClusterRoleBinding, Object: current})
+		}
+	}
+	return plan, nil
+}
+
+// isPruned tells whether the plan removes the cluster role
+func isPruned(plan Plan, roleName string) bool {
+	for _, change := range plan.Changes {
+		if change.Action == Remove && change.Kind == kindClusterRole && change.Object.GetName() == roleName {
+			return true
+		}
+	}
+	return false
+}
+
+// ReconcileObjectSpecificRBAC repairs the drifted object-specific cluster roles and bindings, and
+// prunes the orphaned ones
+func ReconcileObjectSpecificRBAC(ctx context.Context) error {
+	plan, err := DiffObjectSpecificRBAC(ctx)
+	if err != nil {
+		return err
+	}
+	for _, change := range plan.Changes {
+		klog.InfoS("Reconciling the object-specific RBAC", "change", change)
+	}
+	return ApplyPlan(ctx, plan)
+}
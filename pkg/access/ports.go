@@ -0,0 +1,184 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// PortRegistryName is the config map in kube-system that records the port range of each tenant
+const PortRegistryName = "edgenet-port-allocations"
+
+// Bounds of the node port range of the cluster, the ranges of the tenants are carved out of it
+var (
+	NodePortFirst int32 = 30000
+	NodePortLast  int32 = 32767
+)
+
+// PortBlockSize is the number of ports allocated to a tenant, the node port range holds the blocks
+// of 138 tenants by default
+var PortBlockSize int32 = 20
+
+// PortRange is a range of node ports and host ports, both bounds included
+type PortRange struct {
+	First int32
+	Last  int32
+}
+
+// String returns the range as it is recorded in the registry and in the namespace annotations
+func (r PortRange) String() string {
+	return fmt.Sprintf("%d-%d", r.First, r.Last)
+}
+
+// ParsePortRange parses a range written as first-last
+func ParsePortRange(value string) (PortRange, error) {
+	portRange := PortRange{}
+	if _, err := fmt.Sscanf(value, "%d-%d", &portRange.First, &portRange.Last); err != nil {
+		return portRange, fmt.Errorf("invalid port range %q: %v", value, err)
+	}
+	if portRange.First > portRange.Last {
+		return portRange, fmt.Errorf("invalid port range %q", value)
+	}
+	return portRange, nil
+}
+
+// NodePortRange returns the whole node port range, the range of the tenants established before the registry
+func NodePortRange() PortRange {
+	return PortRange{First: NodePortFirst, Last: NodePortLast}
+}
+
+// LookupPortRange returns the port range allocated to the tenant, if any
+func LookupPortRange(ctx context.Context, tenant string) (PortRange, bool, error) {
+	registry, err := Clientset.CoreV1().ConfigMaps("kube-system").Get(ctx, PortRegistryName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return PortRange{}, false, nil
+	} else if err != nil {
+		return PortRange{}, false, err
+	}
+	value, ok := registry.Data[tenant]
+	if !ok {
+		return PortRange{}, false, nil
+	}
+	portRange, err := ParsePortRange(value)
+	return portRange, err == nil, err
+}
+
+// AllocatePortRange returns the port range of the tenant, the first free block is allocated to it
+// if it has none. The ranges of the tenants that no longer exist are reclaimed when the node port
+// range is exhausted. Concurrent allocations are serialized by the version of the registry.
+func AllocatePortRange(ctx context.Context, tenant string) (PortRange, error) {
+	var allocated PortRange
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		registry, err := portRegistry(ctx)
+		if err != nil {
+			return err
+		}
+		if value, ok := registry.Data[tenant]; ok {
+			allocated, err = ParsePortRange(value)
+			return err
+		}
+		portRange, ok := freeBlock(registry.Data)
+		if !ok {
+			reclaimed := false
+			for name := range registry.Data {
+				if _, err := EdgenetClientset.CoreV1alpha().Tenants().Get(ctx, name, metav1.GetOptions{}); errors.IsNotFound(err) {
+					delete(registry.Data, name)
+					reclaimed = true
+				}
+			}
+			if !reclaimed {
+				return fmt.Errorf("no port range left for tenant %s", tenant)
+			}
+			if portRange, ok = freeBlock(registry.Data); !ok {
+				return fmt.Errorf("no port range left for tenant %s", tenant)
+			}
+		}
+		registry.Data[tenant] = portRange.String()
+		if _, err := Clientset.CoreV1().ConfigMaps("kube-system").Update(ctx, registry, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		allocated = portRange
+		return nil
+	})
+	return allocated, err
+}
+
+// ReleasePortRange frees the port range of the tenant
+func ReleasePortRange(ctx context.Context, tenant string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		registry, err := Clientset.CoreV1().ConfigMaps("kube-system").Get(ctx, PortRegistryName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if _, ok := registry.Data[tenant]; !ok {
+			return nil
+		}
+		delete(registry.Data, tenant)
+		_, err = Clientset.CoreV1().ConfigMaps("kube-system").Update(ctx, registry, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// portRegistry returns the registry, it is created empty on first use
+func portRegistry(ctx context.Context) (*corev1.ConfigMap, error) {
+	registry, err := Clientset.CoreV1().ConfigMaps("kube-system").Get(ctx, PortRegistryName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		registry = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: PortRegistryName, Namespace: "kube-system",
+			Labels: map[string]string{"edge-net.io/generated": "true"}}}
+		registry, err = Clientset.CoreV1().ConfigMaps("kube-system").Create(ctx, registry, metav1.CreateOptions{})
+		if errors.IsAlreadyExists(err) {
+			return nil, errors.NewConflict(corev1.Resource("configmaps"), PortRegistryName, err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if registry.Data == nil {
+		registry.Data = map[string]string{}
+	}
+	return registry, nil
+}
+
+// freeBlock returns the lowest block of the node port range that overlaps no allocated range
+func freeBlock(allocations map[string]string) (PortRange, bool) {
+	allocated := []PortRange{}
+	for _, value := range allocations {
+		if portRange, err := ParsePortRange(value); err == nil {
+			allocated = append(allocated, portRange)
+		}
+	}
+	sort.Slice(allocated, func(i, j int) bool { return allocated[i].First < allocated[j].First })
+	candidate := PortRange{First: NodePortFirst, Last: NodePortFirst + PortBlockSize - 1}
+	for _, portRange := range allocated {
+		if candidate.Last < portRange.First {
+			break
+		}
+		if portRange.Last >= candidate.First {
+			candidate = PortRange{First: portRange.Last + 1, Last: portRange.Last + PortBlockSize}
+		}
+	}
+	return candidate, candidate.Last <= NodePortLast
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Actions of a migration plan
+const (
+	Add    = "Add"
+	Update = "Update"
+	Remove = "Remove"
+)
+
+// Kinds of the objects in a migration plan
+const (
+	kindClusterRole        = "ClusterRole"
+	kindClusterRoleBinding = "ClusterRoleBinding"
+	kindRoleBinding        = "RoleBinding"
+)
+
+// Change is a single step of a migration plan
+type Change struct {
+	Action string
+	Kind   string
+	// Object holds the desired state for additions and updates, the live object for removals
+	Object metav1.Object
+}
+
+// String returns the change in a single line
+func (c Change) String() string {
+	name := c.Object.GetName()
+	if c.Object.GetNamespace() != "" {
+		name = fmt.Sprintf("%s/%s", c.Object.GetNamespace(), name)
+	}
+	return fmt.Sprintf("%s\t%s\t%s", c.Action, c.Kind, name)
+}
+
+// Plan brings the live RBAC of a tenant in line with the archetype, the tenant is empty
+// for the cluster roles shared by all tenants
+type Plan struct {
+	Tenant  string
+	Changes []Change
+}
+
+// Empty returns true if the live RBAC already matches the archetype
+func (p Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// tenantClusterRoles returns the archetype of the cluster roles shared by all tenants, as declared by the catalog
+func tenantClusterRoles() []*rbacv1.ClusterRole {
+	clusterRoles := []*rbacv1.ClusterRole{}
+	for _, role := range currentCatalog().Roles {
+		clusterRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: role.Name}, Rules: role.Rules}
+		roleLabels := map[string]string{catalogLabel: "true"}
+		for key, value := range labels {
+			roleLabels[key] = value
+		}
+		clusterRole.SetLabels(roleLabels)
+		clusterRoles = append(clusterRoles, clusterRole)
+	}
+	return clusterRoles
+}
+
+// tenantRBAC returns the archetype of the RBAC objects that the tenant controller generates for a tenant
+func tenantRBAC(tenant *corev1alpha.Tenant) (*rbacv1.ClusterRole, *rbacv1.ClusterRoleBinding, *rbacv1.RoleBinding) {
+	ownerReference := *metav1.NewControllerRef(tenant, corev1alpha.SchemeGroupVersion.WithKind("Tenant"))
+	ownerRole := objectSpecificClusterRole(objectSpec{Tenant: tenant.GetName(), APIGroup: "core.edgenet.io", Resource: "tenants", ResourceName: tenant.GetName(),
+		Name: "owner", Verbs: []string{"get", "update", "patch"}}, []metav1.OwnerReference{ownerReference})
+	ownerRoleBind := objectSpecificClusterRoleBinding(ownerRole.GetName(), tenant.Spec.Contact.Handle, tenant.Spec.Contact.Email, map[string]string{}, nil)
+	subjects := ownerRoleBind.Subjects
+	roleBind := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "edgenet:tenant-owner", Namespace: tenant.GetName()},
+		Subjects: subjects, RoleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "edgenet:tenant-owner"}}
+	roleBind.SetLabels(labels)
+	return ownerRole, ownerRoleBind, roleBind
+}
+
+// DiffClusterRoles compares the live cluster roles shared by all tenants with the archetype
+func DiffClusterRoles(ctx context.Context) (Plan, error) {
+	plan := Plan{}
+	desired := map[string]bool{}
+	for _, clusterRole := range tenantClusterRoles() {
+		desired[clusterRole.GetName()] = true
+		current, err := Clientset.RbacV1().ClusterRoles().Get(ctx, clusterRole.GetName(), metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			plan.Changes = append(plan.Changes, Change{Action: Add, Kind: kindClusterRole, Object: clusterRole})
+		} else if err != nil {
+			return plan, err
+		} else if !reflect.DeepEqual(current.Rules, clusterRole.Rules) || current.GetLabels()[catalogLabel] != "true" {
+			current.Rules = clusterRole.Rules
+			currentLabels := current.GetLabels()
+			if currentLabels == nil {
+				currentLabels = map[string]string{}
+			}
+			for key, value := range clusterRole.GetLabels() {
+				currentLabels[key] = value
+			}
+			current.SetLabels(currentLabels)
+			plan.Changes = append(plan.Changes, Change{Action: Update, Kind: kindClusterRole, Object: current})
+		}
+	}
+	// The roles dropped from the catalog are withdrawn
+	clusterRoleRaw, err := Clientset.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=true", catalogLabel)})
+	if err != nil {
+		return plan, err
+	}
+	for i, clusterRoleRow := range clusterRoleRaw.Items {
+		if !desired[clusterRoleRow.GetName()] {
+			plan.Changes = append(plan.Changes, Change{Action: Remove, Kind: kindClusterRole, Object: &clusterRoleRaw.Items[i]})
+		}
+	}
+	return plan, nil
+}
+
+// DiffTenantRBAC compares the live RBAC of a tenant with its snapshot, the objects that the
+// archetype no longer generates, such as the bindings of a former contact, are to be removed
+func DiffTenantRBAC(ctx context.Context, tenant *corev1alpha.Tenant) (Plan, error) {
+	plan := Plan{Tenant: tenant.GetName()}
+	// The tenant controller withdraws the RBAC of disabled tenants by itself
+	if !tenant.Spec.Enabled {
+		return plan, nil
+	}
+	ownerRole, ownerRoleBind, roleBind := tenantRBAC(tenant)
+
+	current, err := Clientset.RbacV1().ClusterRoles().Get(ctx, ownerRole.GetName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		plan.Changes = append(plan.Changes, Change{Action: Add, Kind: kindClusterRole, Object: ownerRole})
+	} else if err != nil {
+		return plan, err
+	} else if !reflect.DeepEqual(current.Rules, ownerRole.Rules) {
+		current.Rules = ownerRole.Rules
+		plan.Changes = append(plan.Changes, Change{Action: Update, Kind: kindClusterRole, Object: current})
+	}
+	clusterRoleRaw, err := Clientset.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("edge-net.io/generated=true,edge-net.io/tenant=%s", tenant.GetName())})
+	if err != nil {
+		return plan, err
+	}
+	for i, clusterRoleRow := range clusterRoleRaw.Items {
+		if strings.HasPrefix(clusterRoleRow.GetName(), fmt.Sprintf("edgenet:%s:tenants:", tenant.GetName())) && clusterRoleRow.GetName() != ownerRole.GetName() {
+			plan.Changes = append(plan.Changes, Change{Action: Remove, Kind: kindClusterRole, Object: &clusterRoleRaw.Items[i]})
+		}
+	}
+
+	currentRoleBind, err := Clientset.RbacV1().ClusterRoleBindings().Get(ctx, ownerRoleBind.GetName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		plan.Changes = append(plan.Changes, Change{Action: Add, Kind: kindClusterRoleBinding, Object: ownerRoleBind})
+	} else if err != nil {
+		return plan, err
+	} else if !reflect.DeepEqual(currentRoleBind.Subjects, ownerRoleBind.Subjects) || currentRoleBind.RoleRef != ownerRoleBind.RoleRef {
+		currentRoleBind.Subjects = ownerRoleBind.Subjects
+		currentRoleBind.RoleRef = ownerRoleBind.RoleRef
+		plan.Changes = append(plan.Changes, Change{Action: Update, Kind: kindClusterRoleBinding, Object: currentRoleBind})
+	}
+	// The bindings of the owner role under another name belong to a former contact
+	clusterRoleBindingRaw, err := Clientset.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{LabelSelector: "edge-net.io/generated=true"})
+	if err != nil {
+		return plan, err
+	}
+	for i, clusterRoleBindingRow := range clusterRoleBindingRaw.Items {
+		if clusterRoleBindingRow.RoleRef.Name == ownerRole.GetName() && clusterRoleBindingRow.GetName() != ownerRoleBind.GetName() {
+			plan.Changes = append(plan.Changes, Change{Action: Remove, Kind: kindClusterRoleBinding, Object: &clusterRoleBindingRaw.Items[i]})
+		}
+	}
+
+	currentNamespacedRoleBind, err := Clientset.RbacV1().RoleBindings(tenant.GetName()).Get(ctx, roleBind.GetName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		plan.Changes = append(plan.Changes, Change{Action: Add, Kind: kindRoleBinding, Object: roleBind})
+	} else if err != nil {
+		return plan, err
+	} else if !reflect.DeepEqual(currentNamespacedRoleBind.Subjects, roleBind.Subjects) || currentNamespacedRoleBind.RoleRef != roleBind.RoleRef {
+		currentNamespacedRoleBind.Subjects = roleBind.Subjects
+		currentNamespacedRoleBind.RoleRef = roleBind.RoleRef
+		plan.Changes = append(plan.Changes, Change{Action: Update, Kind: kindRoleBinding, Object: currentNamespacedRoleBind})
+	}
+
+	if err := diffRoleBindings(ctx, &plan, tenant.GetName(), memberRoleBindings(tenant), fmt.Sprintf("%s=true", memberBindingLabel)); err != nil {
+		return plan, err
+	}
+	if err := diffRoleBindings(ctx, &plan, tenant.GetName(), groupRoleBindings(tenant), fmt.Sprintf("%s=true", groupBindingLabel)); err != nil {
+		return plan, err
+	}
+	namespaces, err := tenantNamespaces(ctx, tenant.GetName())
+	if err != nil {
+		return plan, err
+	}
+	for _, namespace := range namespaces {
+		if err := diffRoleBindings(ctx, &plan, namespace, ServiceRoleBindings(namespace), serviceBindingLabel); err != nil {
+			return plan, err
+		}
+	}
+	return plan, nil
+}
+
+// DiffAllTenants returns the plan of the shared cluster roles followed by the plan of each tenant
+func DiffAllTenants(ctx context.Context) ([]Plan, error) {
+	plans := []Plan{}
+	plan, err := DiffClusterRoles(ctx)
+	if err != nil {
+		return plans, err
+	}
+	plans = append(plans, plan)
+	tenantRaw, err := EdgenetClientset.CoreV1alpha().Tenants().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return plans, err
+	}
+	for i := range tenantRaw.Items {
+		plan, err := DiffTenantRBAC(ctx, &tenantRaw.Items[i])
+		if err != nil {
+			return plans, err
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// ApplyPlan carries out the changes of a plan in order and stops at the first failure
+func ApplyPlan(ctx context.Context, plan Plan) error {
+	for _, change := range plan.Changes {
+		if err := applyChange(ctx, change); err != nil {
+			return fmt.Errorf("%s: %s", change, err)
+		}
+	}
+	return nil
+}
+
+func applyChange(ctx context.Context, change Change) error {
+	var err error
+	switch object := change.Object.(type) {
+	case *rbacv1.ClusterRole:
+		clusterRoles := Clientset.RbacV1().ClusterRoles()
+		switch change.Action {
+		case Add:
+			_, err = clusterRoles.Create(ctx, object, metav1.CreateOptions{})
+		case Update:
+			_, err = clusterRoles.Update(ctx, object, metav1.UpdateOptions{})
+		case Remove:
+			err = clusterRoles.Delete(ctx, object.GetName(), metav1.DeleteOptions{})
+		}
+	case *rbacv1.ClusterRoleBinding:
+		clusterRoleBindings := Clientset.RbacV1().ClusterRoleBindings()
+		switch change.Action {
+		case Add:
+			_, err = clusterRoleBindings.Create(ctx, object, metav1.CreateOptions{})
+		case Update:
+			// The role reference is immutable, the binding is recreated
+			if err = clusterRoleBindings.Delete(ctx, object.GetName(), metav1.DeleteOptions{}); err == nil {
+				object.SetResourceVersion("")
+				_, err = clusterRoleBindings.Create(ctx, object, metav1.CreateOptions{})
+			}
+		case Remove:
+			err = clusterRoleBindings.Delete(ctx, object.GetName(), metav1.DeleteOptions{})
+		}
+	case *rbacv1.RoleBinding:
+		roleBindings := Clientset.RbacV1().RoleBindings(object.GetNamespace())
+		switch change.Action {
+		case Add:
+			_, err = roleBindings.Create(ctx, object, metav1.CreateOptions{})
+		case Update:
+			if err = roleBindings.Delete(ctx, object.GetName(), metav1.DeleteOptions{}); err == nil {
+				object.SetResourceVersion("")
+				_, err = roleBindings.Create(ctx, object, metav1.CreateOptions{})
+			}
+		case Remove:
+			err = roleBindings.Delete(ctx, object.GetName(), metav1.DeleteOptions{})
+		}
+	default:
+		err = fmt.Errorf("unsupported kind %s", change.Kind)
+	}
+	if errors.IsNotFound(err) && change.Action == Remove {
+		return nil
+	}
+	return err
+}
@@ -18,6 +18,7 @@ package access
 
 import (
 	"context"
+	"time"
 
 	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
 	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
@@ -26,7 +27,7 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 	//cmdconfig "k8s.io/kubernetes/pkg/kubectl/cmd/config"
 )
 
@@ -34,8 +35,23 @@ import (
 var Clientset kubernetes.Interface
 var EdgenetClientset clientset.Interface
 
-// Create function is for being used by other resources to create a tenant
-func CreateTenant(tenantRequest *registrationv1alpha.TenantRequest) error {
+// EmailVerifiedAnnotation is set to false on the requests submitted through the registration API
+// until the requester verifies their email address, the requests made otherwise do not have it
+const EmailVerifiedAnnotation = "edge-net.io/email-verified"
+
+// EmailVerified tells whether the email address of a request is verified, or needs no verification
+func EmailVerified(request metav1.Object) bool {
+	return request.GetAnnotations()[EmailVerifiedAnnotation] != "false"
+}
+
+// EmailConfirmed tells whether the requester has verified the email address of a request, unlike
+// EmailVerified it does not hold for the requests without the annotation
+func EmailConfirmed(request metav1.Object) bool {
+	return request.GetAnnotations()[EmailVerifiedAnnotation] == "true"
+}
+
+// Create function is for being used by other resources to create a tenant, with the defaults of the profile if any
+func CreateTenant(ctx context.Context, tenantRequest *registrationv1alpha.TenantRequest, profile *corev1alpha.TenantProfile) error {
 	// Create a tenant on the cluster
 	tenant := new(corev1alpha.Tenant)
 	tenant.SetName(tenantRequest.GetName())
@@ -48,32 +64,52 @@ func CreateTenant(tenantRequest *registrationv1alpha.TenantRequest) error {
 	if tenantRequest.GetOwnerReferences() != nil {
 		tenant.SetOwnerReferences(tenantRequest.GetOwnerReferences())
 	}
+	claim := corev1alpha.ResourceTuning{
+		ResourceList: tenantRequest.Spec.ResourceAllocation,
+	}
+	if profile != nil {
+		stampProfile(tenant, &claim, profile)
+	}
 
-	if _, err := EdgenetClientset.CoreV1alpha().Tenants().Create(context.TODO(), tenant, metav1.CreateOptions{}); err != nil {
-		klog.V(4).Infof("Couldn't create tenant %s: %s", tenant.GetName(), err)
+	if _, err := EdgenetClientset.CoreV1alpha().Tenants().Create(ctx, tenant, metav1.CreateOptions{}); err != nil {
+		klog.ErrorS(err, "Couldn't create tenant", "tenant", klog.KObj(tenant))
 		return err
 	}
 
-	if tenantRequest.Spec.ResourceAllocation != nil {
+	if claim.ResourceList != nil {
 		// TODO: Take tenant resource quota into account while error handling
-		claim := corev1alpha.ResourceTuning{
-			ResourceList: tenantRequest.Spec.ResourceAllocation,
-		}
-		err := ApplyTenantResourceQuota(tenantRequest.GetName(), nil, claim)
+		err := ApplyTenantResourceQuota(ctx, tenantRequest.GetName(), nil, claim)
 		if err != nil {
-			klog.V(4).Infof("Couldn't create tenant resource quota %s: %s", tenantRequest.GetName(), err)
+			klog.ErrorS(err, "Couldn't create tenant resource quota", "tenantRequest", klog.KObj(tenantRequest))
 		}
 	}
 
 	return nil
 }
 
+// stampProfile sets the defaults of the profile on the tenant to create and its initial quota claim
+func stampProfile(tenant *corev1alpha.Tenant, claim *corev1alpha.ResourceTuning, profile *corev1alpha.TenantProfile) {
+	tenant.Spec.Profile = profile.GetName()
+	tenant.Spec.NodePools = append([]string(nil), profile.Spec.NodePools...)
+	if profile.Spec.NetworkPolicy == "permissive" {
+		// The tenant controller keeps the tenant off the default-deny baseline with this annotation
+		tenant.SetAnnotations(map[string]string{"edge-net.io/baseline-policies": "false"})
+	}
+	// The allocation requested takes precedence over the one of the profile
+	if claim.ResourceList == nil && profile.Spec.ResourceAllocation != nil {
+		claim.ResourceList = profile.DeepCopy().Spec.ResourceAllocation
+	}
+	if profile.Spec.Expiry != nil {
+		claim.Expiry = &metav1.Time{Time: time.Now().Add(profile.Spec.Expiry.Duration)}
+	}
+}
+
 // ApplyTenantResourceQuota generates a tenant resource quota with the name provided
-func ApplyTenantResourceQuota(name string, ownerReferences []metav1.OwnerReference, claim corev1alpha.ResourceTuning) error {
+func ApplyTenantResourceQuota(ctx context.Context, name string, ownerReferences []metav1.OwnerReference, claim corev1alpha.ResourceTuning) error {
 	// Set a tenant resource quota
-	if tenantResourceQuota, err := EdgenetClientset.CoreV1alpha().TenantResourceQuotas().Get(context.TODO(), name, metav1.GetOptions{}); err == nil {
+	if tenantResourceQuota, err := EdgenetClientset.CoreV1alpha().TenantResourceQuotas().Get(ctx, name, metav1.GetOptions{}); err == nil {
 		tenantResourceQuota.Spec.Claim["initial"] = claim
-		if _, err := EdgenetClientset.CoreV1alpha().TenantResourceQuotas().Update(context.TODO(), tenantResourceQuota.DeepCopy(), metav1.UpdateOptions{}); err != nil {
+		if _, err := EdgenetClientset.CoreV1alpha().TenantResourceQuotas().Update(ctx, tenantResourceQuota.DeepCopy(), metav1.UpdateOptions{}); err != nil {
 			return err
 		}
 	} else {
@@ -84,7 +120,7 @@ func ApplyTenantResourceQuota(name string, ownerReferences []metav1.OwnerReferen
 		}
 		tenantResourceQuota.Spec.Claim = make(map[string]corev1alpha.ResourceTuning)
 		tenantResourceQuota.Spec.Claim["initial"] = claim
-		if _, err := EdgenetClientset.CoreV1alpha().TenantResourceQuotas().Create(context.TODO(), tenantResourceQuota.DeepCopy(), metav1.CreateOptions{}); err != nil {
+		if _, err := EdgenetClientset.CoreV1alpha().TenantResourceQuotas().Create(ctx, tenantResourceQuota.DeepCopy(), metav1.CreateOptions{}); err != nil {
 			return err
 		}
 	}
@@ -92,6 +128,15 @@ func ApplyTenantResourceQuota(name string, ownerReferences []metav1.OwnerReferen
 }
 
 func SendEmailForRoleRequest(roleRequestCopy *registrationv1alpha.RoleRequest, purpose, subject, clusterUID string, recipient []string) {
+	roleRequestContent(roleRequestCopy, subject, clusterUID, recipient).Send(purpose)
+}
+
+// NotifyForRoleRequest posts the role request to the chat channels subscribed to the purpose, without emailing anyone
+func NotifyForRoleRequest(roleRequestCopy *registrationv1alpha.RoleRequest, purpose, subject, clusterUID string) {
+	roleRequestContent(roleRequestCopy, subject, clusterUID, nil).Notify(purpose)
+}
+
+func roleRequestContent(roleRequestCopy *registrationv1alpha.RoleRequest, subject, clusterUID string, recipient []string) *mailer.Content {
 	email := new(mailer.Content)
 	email.Cluster = clusterUID
 	email.User = roleRequestCopy.Spec.Email
@@ -102,10 +147,51 @@ func SendEmailForRoleRequest(roleRequestCopy *registrationv1alpha.RoleRequest, p
 	email.RoleRequest = new(mailer.RoleRequest)
 	email.RoleRequest.Name = roleRequestCopy.GetName()
 	email.RoleRequest.Namespace = roleRequestCopy.GetNamespace()
-	email.Send(purpose)
+	return email
+}
+
+// SendReminderEmailForRoleRequest reminds the requester that the pending request is about to expire,
+// and whether they can still extend its expiry
+func SendReminderEmailForRoleRequest(roleRequestCopy *registrationv1alpha.RoleRequest, clusterUID string) error {
+	email := roleRequestContent(roleRequestCopy, "[EdgeNet] Role request expiring", clusterUID, []string{roleRequestCopy.Spec.Email})
+	email.RequestExpiry = &mailer.RequestExpiry{Expiry: roleRequestCopy.Status.Expiry.Time, Renewable: !roleRequestCopy.Status.Renewed}
+	return email.Send("role-request-expiry-reminder")
+}
+
+// SendVerificationEmailForRoleRequest emails the requester the code that verifies their email address,
+// the path leads to the verification page of the console
+func SendVerificationEmailForRoleRequest(roleRequestCopy *registrationv1alpha.RoleRequest, code, path, clusterUID string) error {
+	email := roleRequestContent(roleRequestCopy, "[EdgeNet] Verify email address", clusterUID, []string{roleRequestCopy.Spec.Email})
+	email.EmailVerification = &mailer.EmailVerification{Code: code, URL: path}
+	return email.Send("role-request-email-verification")
+}
+
+// SendReminderEmailForTenantRequest reminds the contact that the pending request is about to expire,
+// and whether they can still extend its expiry
+func SendReminderEmailForTenantRequest(tenantRequestCopy *registrationv1alpha.TenantRequest, clusterUID string) error {
+	email := tenantRequestContent(tenantRequestCopy, "[EdgeNet] Tenant request expiring", clusterUID, []string{tenantRequestCopy.Spec.Contact.Email})
+	email.RequestExpiry = &mailer.RequestExpiry{Expiry: tenantRequestCopy.Status.Expiry.Time, Renewable: !tenantRequestCopy.Status.Renewed}
+	return email.Send("tenant-request-expiry-reminder")
 }
 
 func SendEmailForTenantRequest(tenantRequestCopy *registrationv1alpha.TenantRequest, purpose, subject, clusterUID string, recipient []string) {
+	tenantRequestContent(tenantRequestCopy, subject, clusterUID, recipient).Send(purpose)
+}
+
+// NotifyForTenantRequest posts the tenant request to the chat channels subscribed to the purpose, without emailing anyone
+func NotifyForTenantRequest(tenantRequestCopy *registrationv1alpha.TenantRequest, purpose, subject, clusterUID string) {
+	tenantRequestContent(tenantRequestCopy, subject, clusterUID, nil).Notify(purpose)
+}
+
+// SendVerificationEmailForTenantRequest emails the contact the code that verifies their email address,
+// the path leads to the verification page of the console
+func SendVerificationEmailForTenantRequest(tenantRequestCopy *registrationv1alpha.TenantRequest, code, path, clusterUID string) error {
+	email := tenantRequestContent(tenantRequestCopy, "[EdgeNet] Verify email address", clusterUID, []string{tenantRequestCopy.Spec.Contact.Email})
+	email.EmailVerification = &mailer.EmailVerification{Code: code, URL: path}
+	return email.Send("tenant-email-verification")
+}
+
+func tenantRequestContent(tenantRequestCopy *registrationv1alpha.TenantRequest, subject, clusterUID string, recipient []string) *mailer.Content {
 	email := new(mailer.Content)
 	email.Cluster = clusterUID
 	email.User = tenantRequestCopy.Spec.Contact.Email
@@ -115,5 +201,12 @@ func SendEmailForTenantRequest(tenantRequestCopy *registrationv1alpha.TenantRequ
 	email.Recipient = recipient
 	email.TenantRequest = new(mailer.TenantRequest)
 	email.TenantRequest.Tenant = tenantRequestCopy.GetName()
-	email.Send(purpose)
+	if estimate := tenantRequestCopy.Status.Estimate; estimate != nil {
+		if email.TenantRequest.Cost = estimate.Monthly; estimate.Monthly != "" && estimate.Currency != "" {
+			email.TenantRequest.Cost += " " + estimate.Currency
+		}
+		email.TenantRequest.Share = estimate.Share
+		email.TenantRequest.DominantResource = string(estimate.DominantResource)
+	}
+	return email
 }
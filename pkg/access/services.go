@@ -0,0 +1,51 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access
+
+import (
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Label of the role bindings returned by ServiceRoleBindings
+const serviceBindingLabel = "edge-net.io/service-binding"
+
+// ServiceNamespace is the namespace of the service accounts of the controllers
+var ServiceNamespace = "edgenet"
+
+// namespacedServices are the controllers that create objects on behalf of the tenants, they hold
+// their rights in the tenant namespaces only
+var namespacedServices = []string{"extensionrequest", "tenantapp"}
+
+// ServiceRoleBindings returns the role bindings that grant the controllers their rights in a tenant
+// namespace, each binds the service account to the namespaced cluster role of the controller
+func ServiceRoleBindings(namespace string) []*rbacv1.RoleBinding {
+	roleBindings := []*rbacv1.RoleBinding{}
+	for _, service := range namespacedServices {
+		name := "edgenet:service:" + service
+		roleBindings = append(roleBindings, &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{"edge-net.io/generated": "true", serviceBindingLabel: service},
+			},
+			RoleRef:  rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: name + ":namespaced"},
+			Subjects: []rbacv1.Subject{{Kind: "ServiceAccount", Name: service, Namespace: ServiceNamespace}},
+		})
+	}
+	return roleBindings
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Snapshot is the complete RBAC that EdgeNet generates for a tenant from its spec, the
+// cluster roles shared by all tenants aside
+type Snapshot struct {
+	Tenant              string
+	ClusterRoles        []*rbacv1.ClusterRole
+	ClusterRoleBindings []*rbacv1.ClusterRoleBinding
+	// RoleBindings in the core namespace and in the workspaces of the tenant
+	RoleBindings []*rbacv1.RoleBinding
+}
+
+// Objects returns the objects of the snapshot in the order they are listed
+func (s Snapshot) Objects() []metav1.Object {
+	objects := []metav1.Object{}
+	for _, clusterRole := range s.ClusterRoles {
+		objects = append(objects, clusterRole)
+	}
+	for _, clusterRoleBinding := range s.ClusterRoleBindings {
+		objects = append(objects, clusterRoleBinding)
+	}
+	for _, roleBinding := range s.RoleBindings {
+		objects = append(objects, roleBinding)
+	}
+	return objects
+}
+
+// TenantSnapshot renders the RBAC expected for the tenant: the role and the bindings of its
+// contact, the bindings of its owners, administrators, and identity-provider groups, and the
+// service bindings of its namespaces. A disabled tenant has none.
+func TenantSnapshot(ctx context.Context, tenant *corev1alpha.Tenant) (Snapshot, error) {
+	snapshot := Snapshot{Tenant: tenant.GetName()}
+	if !tenant.Spec.Enabled {
+		return snapshot, nil
+	}
+	ownerRole, ownerRoleBind, roleBind := tenantRBAC(tenant)
+	snapshot.ClusterRoles = append(snapshot.ClusterRoles, ownerRole)
+	snapshot.ClusterRoleBindings = append(snapshot.ClusterRoleBindings, ownerRoleBind)
+	snapshot.RoleBindings = append(snapshot.RoleBindings, roleBind)
+	snapshot.RoleBindings = append(snapshot.RoleBindings, memberRoleBindings(tenant)...)
+	snapshot.RoleBindings = append(snapshot.RoleBindings, groupRoleBindings(tenant)...)
+
+	namespaces, err := tenantNamespaces(ctx, tenant.GetName())
+	if err != nil {
+		return snapshot, err
+	}
+	for _, namespace := range namespaces {
+		snapshot.RoleBindings = append(snapshot.RoleBindings, ServiceRoleBindings(namespace)...)
+	}
+	return snapshot, nil
+}
+
+// memberRoleBindings returns the role bindings of the owners and the administrators listed by
+// the tenant, sorted by name
+func memberRoleBindings(tenant *corev1alpha.Tenant) []*rbacv1.RoleBinding {
+	roleBindings := []*rbacv1.RoleBinding{}
+	add := func(roleName string, member corev1alpha.Contact) {
+		if member.Email != "" && !strings.EqualFold(member.Email, tenant.Spec.Contact.Email) {
+			roleBindings = append(roleBindings, memberRoleBinding(tenant.GetName(), roleName, member.Email))
+		}
+	}
+	for _, owner := range tenant.Spec.Owners {
+		add(TenantOwnerRole, owner)
+	}
+	for _, admin := range tenant.Spec.Admins {
+		add(TenantAdminRole, admin)
+	}
+	sort.Slice(roleBindings, func(i, j int) bool { return roleBindings[i].GetName() < roleBindings[j].GetName() })
+	return roleBindings
+}
+
+// groupRoleBindings returns the role bindings of the identity-provider groups listed by the
+// tenant, the invalid group bindings are left out
+func groupRoleBindings(tenant *corev1alpha.Tenant) []*rbacv1.RoleBinding {
+	roleBindings := []*rbacv1.RoleBinding{}
+	for _, groupBinding := range tenant.Spec.GroupBindings {
+		if roleName, ok := groupRoles[groupBinding.Role]; ok && groupBinding.Group != "" {
+			roleBindings = append(roleBindings, groupRoleBinding(tenant.GetName(), roleName, groupBinding.Group))
+		}
+	}
+	sort.Slice(roleBindings, func(i, j int) bool { return roleBindings[i].GetName() < roleBindings[j].GetName() })
+	return roleBindings
+}
+
+// tenantNamespaces returns the core namespace of the tenant followed by its workspaces
+func tenantNamespaces(ctx context.Context, tenant string) ([]string, error) {
+	namespaces := []string{tenant}
+	namespaceRaw, err := Clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("edge-net.io/tenant=%s,edge-net.io/kind=sub", tenant)})
+	if err != nil {
+		return namespaces, err
+	}
+	workspaces := []string{}
+	for _, namespaceRow := range namespaceRaw.Items {
+		workspaces = append(workspaces, namespaceRow.GetName())
+	}
+	sort.Strings(workspaces)
+	return append(namespaces, workspaces...), nil
+}
+
+// diffRoleBindings compares the live role bindings of a namespace with the desired ones, the
+// live bindings matching the selector that are not desired are to be removed
+func diffRoleBindings(ctx context.Context, plan *Plan, namespace string, desired []*rbacv1.RoleBinding, selector string) error {
+	desiredNames := map[string]bool{}
+	for _, roleBind := range desired {
+		desiredNames[roleBind.GetName()] = true
+		current, err := Clientset.RbacV1().RoleBindings(namespace).Get(ctx, roleBind.GetName(), metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			plan.Changes = append(plan.Changes, Change{Action: Add, Kind: kindRoleBinding, Object: roleBind})
+		} else if err != nil {
+			return err
+		} else if !reflect.DeepEqual(current.Subjects, roleBind.Subjects) || current.RoleRef != roleBind.RoleRef {
+			current.Subjects = roleBind.Subjects
+			current.RoleRef = roleBind.RoleRef
+			plan.Changes = append(plan.Changes, Change{Action: Update, Kind: kindRoleBinding, Object: current})
+		}
+	}
+	roleBindingRaw, err := Clientset.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return err
+	}
+	for i, roleBindingRow := range roleBindingRaw.Items {
+		if !desiredNames[roleBindingRow.GetName()] {
+			plan.Changes = append(plan.Changes, Change{Action: Remove, Kind: kindRoleBinding, Object: &roleBindingRaw.Items[i]})
+		}
+	}
+	return nil
+}
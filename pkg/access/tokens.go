@@ -0,0 +1,47 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package access
+
+import (
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+)
+
+// TokenPolicy is how the service account tokens are handed out in the namespaces of a tenant
+type TokenPolicy struct {
+	// Automount lets the pods running as the default service account of a namespace mount its token
+	Automount bool
+	// Lifetime of the scoped tokens issued on request to the tenant service accounts
+	Lifetime time.Duration
+}
+
+// TokenPolicies gives the token policy of each tier, the tenants without a tier follow the community tier
+var TokenPolicies = map[string]TokenPolicy{
+	"community": {Automount: false, Lifetime: time.Hour},
+	"paying":    {Automount: false, Lifetime: 24 * time.Hour},
+}
+
+// TenantTokenPolicy returns the token policy of the tier of the tenant
+func TenantTokenPolicy(tenant *corev1alpha.Tenant) TokenPolicy {
+	if tenant.Spec.Priority != nil {
+		if policy, ok := TokenPolicies[tenant.Spec.Priority.Tier]; ok {
+			return policy
+		}
+	}
+	return TokenPolicies["community"]
+}
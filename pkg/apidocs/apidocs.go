@@ -0,0 +1,341 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apidocs renders the reference documentation of the EdgeNet APIs out of the Go types,
+// so that the documentation follows the API surface as it changes
+package apidocs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Markers read from the comments of the types and fields
+const (
+	markerGenClient     = "+genclient"
+	markerNonNamespaced = "+genclient:nonNamespaced"
+	markerGroupName     = "+groupName="
+	markerPrintColumn   = "+kubebuilder:printcolumn:"
+	markerValidation    = "+kubebuilder:validation:"
+	markerOptional      = "+optional"
+)
+
+// Group is an API group version as declared by its package
+type Group struct {
+	// Name of the API group, such as core.edgenet.io
+	Name string
+	// Version of the API group, such as v1alpha
+	Version string
+	// Kinds are the types served as resources, in the order of declaration
+	Kinds []*Kind
+	// Types are all the struct types of the package by name
+	Types map[string]*Type
+}
+
+// Kind is a type served as a resource
+type Kind struct {
+	*Type
+	// Namespaced is false for the cluster-scoped resources
+	Namespaced bool
+	// PrintColumns are the additional columns of kubectl get
+	PrintColumns []PrintColumn
+	// Example is the manifest shown for the kind
+	Example string
+}
+
+// PrintColumn is an additional column of kubectl get
+type PrintColumn struct {
+	Name     string
+	Type     string
+	JSONPath string
+}
+
+// Type is a struct type of an API package
+type Type struct {
+	Name string
+	Doc  string
+	// Fields are in the order of declaration, the embedded TypeMeta is left out
+	Fields []Field
+}
+
+// Field is a field of a struct type as it appears in the manifests
+type Field struct {
+	// Name is the JSON name of the field
+	Name string
+	// GoType is the type as written in the source, such as []GroupBinding
+	GoType string
+	Doc    string
+	// Optional is true for the fields that are omitted when empty or marked optional
+	Optional bool
+	// Validation holds the rules of the validation markers, such as Enum=owner;admin
+	Validation []string
+	// Inline is true for the embedded fields whose fields appear at the same level
+	Inline bool
+}
+
+// Parse reads the API group version declared by the Go package in the directory
+func Parse(dir string) (*Group, error) {
+	fset := token.NewFileSet()
+	packages, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && !strings.HasPrefix(info.Name(), "zz_generated")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	if len(packages) != 1 {
+		return nil, fmt.Errorf("expected a single package in %s, found %d", dir, len(packages))
+	}
+
+	group := &Group{Types: make(map[string]*Type)}
+	for _, pkg := range packages {
+		group.Version = pkg.Name
+		// Sort the files so that the kinds keep the same order from one run to another
+		fileNames := []string{}
+		for fileName := range pkg.Files {
+			fileNames = append(fileNames, fileName)
+		}
+		sort.Strings(fileNames)
+		for _, fileName := range fileNames {
+			file := pkg.Files[fileName]
+			// The package markers are separated by a blank line from the package clause
+			for _, commentGroup := range file.Comments {
+				if commentGroup.End() > file.Package {
+					break
+				}
+				for _, line := range markerLines(commentGroup) {
+					if strings.HasPrefix(line, markerGroupName) {
+						group.Name = strings.TrimPrefix(line, markerGroupName)
+					}
+				}
+			}
+			group.parseFile(file)
+		}
+	}
+	if group.Name == "" {
+		return nil, fmt.Errorf("no %s marker in %s", markerGroupName, dir)
+	}
+	return group, nil
+}
+
+// parseFile collects the struct types of a file, and the kinds among them. The type markers
+// are separated by a blank line from the doc comment, they are found in the comments between
+// the type and the preceding declaration.
+func (g *Group) parseFile(file *ast.File) {
+	previous := file.Name.End()
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			previous = decl.End()
+			continue
+		}
+		markers := []string{}
+		for _, commentGroup := range file.Comments {
+			if commentGroup.Pos() > previous && commentGroup.End() < genDecl.Pos() {
+				markers = append(markers, markerLines(commentGroup)...)
+			}
+		}
+		previous = genDecl.End()
+
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			doc := genDecl.Doc
+			if typeSpec.Doc != nil {
+				doc = typeSpec.Doc
+			}
+			t := &Type{Name: typeSpec.Name.Name, Doc: docText(doc)}
+			for _, field := range structType.Fields.List {
+				if f, ok := parseField(field); ok {
+					t.Fields = append(t.Fields, f)
+				}
+			}
+			g.Types[t.Name] = t
+			if hasMarker(markers, markerGenClient) {
+				g.Kinds = append(g.Kinds, &Kind{Type: t, Namespaced: !hasMarker(markers, markerNonNamespaced),
+					PrintColumns: printColumns(markers)})
+			}
+		}
+	}
+}
+
+// parseField reads the JSON name, type, doc, and validation of a field
+func parseField(field *ast.Field) (Field, bool) {
+	f := Field{GoType: typeString(field.Type), Doc: docText(field.Doc)}
+	name := ""
+	if len(field.Names) > 0 {
+		name = field.Names[0].Name
+	}
+	if field.Tag != nil {
+		tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+		options := strings.Split(tag.Get("json"), ",")
+		if options[0] == "-" {
+			return f, false
+		}
+		if options[0] != "" {
+			name = options[0]
+		}
+		for _, option := range options[1:] {
+			switch option {
+			case "omitempty":
+				f.Optional = true
+			case "inline":
+				f.Inline = true
+			}
+		}
+	}
+	// The type meta is documented once per kind as apiVersion and kind
+	if f.Inline && f.GoType == "metav1.TypeMeta" {
+		return f, false
+	}
+	f.Name = name
+	for _, line := range markerLines(field.Doc) {
+		switch {
+		case line == markerOptional:
+			f.Optional = true
+		case strings.HasPrefix(line, markerValidation):
+			f.Validation = append(f.Validation, strings.TrimPrefix(line, markerValidation))
+		}
+	}
+	return f, true
+}
+
+// printColumns reads the printer column markers of a kind
+func printColumns(markers []string) []PrintColumn {
+	columns := []PrintColumn{}
+	for _, marker := range markers {
+		if !strings.HasPrefix(marker, markerPrintColumn) {
+			continue
+		}
+		column := PrintColumn{}
+		for _, argument := range splitArguments(strings.TrimPrefix(marker, markerPrintColumn)) {
+			parts := strings.SplitN(argument, "=", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			value := unquote(parts[1])
+			switch strings.ToLower(parts[0]) {
+			case "name":
+				column.Name = value
+			case "type":
+				column.Type = value
+			case "jsonpath":
+				column.JSONPath = value
+			}
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// splitArguments splits the comma-separated arguments of a marker, leaving the quoted commas
+func splitArguments(arguments string) []string {
+	var split []string
+	var quote rune
+	start := 0
+	for i, char := range arguments {
+		switch {
+		case quote != 0 && char == quote:
+			quote = 0
+		case quote == 0 && (char == '"' || char == '`'):
+			quote = char
+		case quote == 0 && char == ',':
+			split = append(split, arguments[start:i])
+			start = i + 1
+		}
+	}
+	return append(split, arguments[start:])
+}
+
+// unquote removes the double quotes or backticks around a marker value
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '`') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// typeString renders a type expression as written in the source
+func typeString(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return fmt.Sprintf("%s.%s", typeString(t.X), t.Sel.Name)
+	case *ast.StarExpr:
+		return "*" + typeString(t.X)
+	case *ast.ArrayType:
+		return "[]" + typeString(t.Elt)
+	case *ast.MapType:
+		return fmt.Sprintf("map[%s]%s", typeString(t.Key), typeString(t.Value))
+	case *ast.InterfaceType:
+		return "interface{}"
+	}
+	return fmt.Sprintf("%T", expr)
+}
+
+// commentLines returns the lines of a comment group without the comment markers
+func commentLines(commentGroup *ast.CommentGroup) []string {
+	if commentGroup == nil {
+		return nil
+	}
+	return strings.Split(strings.TrimSpace(commentGroup.Text()), "\n")
+}
+
+// markerLines returns the lines of a comment group that are markers
+func markerLines(commentGroup *ast.CommentGroup) []string {
+	markers := []string{}
+	if commentGroup == nil {
+		return markers
+	}
+	// Each marker is a comment of its own
+	for _, comment := range commentGroup.List {
+		line := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if strings.HasPrefix(line, "+") {
+			markers = append(markers, line)
+		}
+	}
+	return markers
+}
+
+// docText joins the lines of a doc comment that are not markers
+func docText(commentGroup *ast.CommentGroup) string {
+	lines := []string{}
+	for _, line := range commentLines(commentGroup) {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "+") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, " ")
+}
+
+func hasMarker(markers []string, marker string) bool {
+	for _, line := range markers {
+		if line == marker {
+			return true
+		}
+	}
+	return false
+}
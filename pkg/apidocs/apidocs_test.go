@@ -0,0 +1,98 @@
+package apidocs
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	appsv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/apps/v1alpha"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	federationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/federation/v1alpha"
+	networkingv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/networking/v1alpha"
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const apis = "../apis"
+
+func TestParse(t *testing.T) {
+	group, err := Parse(filepath.Join(apis, "core/v1alpha"))
+	util.OK(t, err)
+	util.Equals(t, "core.edgenet.io", group.Name)
+	util.Equals(t, "v1alpha", group.Version)
+
+	tenant := group.kind("Tenant")
+	util.Assert(t, tenant != nil, "Tenant is not a kind")
+	util.Equals(t, false, tenant.Namespaced)
+	util.Equals(t, PrintColumn{Name: "Official Name", Type: "string", JSONPath: ".spec.fullname"}, tenant.PrintColumns[0])
+	util.Equals(t, true, group.kind("SubNamespace").Namespaced)
+	util.Assert(t, group.kind("TenantSpec") == nil, "TenantSpec is not a kind")
+
+	// The type meta is left out, the markers are not part of the doc
+	util.Equals(t, "metadata", tenant.Fields[0].Name)
+	for _, field := range group.Types["GroupBinding"].Fields {
+		util.Assert(t, !strings.Contains(field.Doc, "+kubebuilder"), "marker in the doc of %s", field.Name)
+		if field.Name == "role" {
+			util.Equals(t, []string{"Enum=owner;admin;collaborator"}, field.Validation)
+		}
+	}
+	for _, field := range group.Types["TenantSpec"].Fields {
+		if field.Name == "priority" {
+			util.Equals(t, "*Priority", field.GoType)
+			util.Equals(t, true, field.Optional)
+		}
+	}
+}
+
+func TestReference(t *testing.T) {
+	cases := map[string]func() []runtime.Object{
+		"apps/v1alpha":         appsv1alpha.Examples,
+		"core/v1alpha":         corev1alpha.Examples,
+		"federation/v1alpha":   federationv1alpha.Examples,
+		"networking/v1alpha":   networkingv1alpha.Examples,
+		"registration/v1alpha": registrationv1alpha.Examples,
+	}
+	for dir, examples := range cases {
+		t.Run(dir, func(t *testing.T) {
+			group, err := Parse(filepath.Join(apis, dir))
+			util.OK(t, err)
+			util.Assert(t, len(group.Kinds) > 0, "no kinds in %s", dir)
+			util.OK(t, group.SetExamples(examples()))
+
+			reference := &bytes.Buffer{}
+			util.OK(t, group.Render(reference))
+			for _, kind := range group.Kinds {
+				util.Assert(t, strings.Contains(reference.String(), "## "+kind.Name+"\n"), "no section for %s", kind.Name)
+			}
+		})
+	}
+}
+
+func TestSetExamples(t *testing.T) {
+	group, err := Parse(filepath.Join(apis, "core/v1alpha"))
+	util.OK(t, err)
+
+	t.Run("missing example", func(t *testing.T) {
+		util.Assert(t, group.SetExamples(corev1alpha.Examples()[1:]) != nil, "missing example accepted")
+	})
+	t.Run("example of another group", func(t *testing.T) {
+		util.Assert(t, group.SetExamples(networkingv1alpha.Examples()) != nil, "example of another group accepted")
+	})
+}
+
+func TestManifest(t *testing.T) {
+	tenant := &corev1alpha.Tenant{TypeMeta: metav1.TypeMeta{APIVersion: "core.edgenet.io/v1alpha", Kind: "Tenant"},
+		ObjectMeta: metav1.ObjectMeta{Name: "edgenet"},
+		Spec:       corev1alpha.TenantSpec{FullName: "EdgeNet", Priority: &corev1alpha.Priority{Tier: "paying", Weight: 1000000}},
+		Status:     corev1alpha.TenantStatus{State: "Established"}}
+	manifest, err := Manifest(tenant)
+	util.OK(t, err)
+	util.Assert(t, strings.HasPrefix(manifest, "apiVersion: core.edgenet.io/v1alpha\nkind: Tenant\nmetadata:\n  name: edgenet\n"), "unexpected manifest:\n%s", manifest)
+	util.Assert(t, !strings.Contains(manifest, "status"), "status in the manifest")
+	util.Assert(t, !strings.Contains(manifest, "creationTimestamp"), "creation timestamp in the manifest")
+	util.Assert(t, strings.Contains(manifest, "weight: 1000000\n"), "weight is not an integer:\n%s", manifest)
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apidocs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// SetExamples attaches the example manifests to the kinds. Every kind must have an example,
+// and every example must be of a kind of the group.
+func (g *Group) SetExamples(objects []runtime.Object) error {
+	for _, object := range objects {
+		gvk := object.GetObjectKind().GroupVersionKind()
+		if gvk.Group != g.Name || gvk.Version != g.Version {
+			return fmt.Errorf("example of %s is not in %s/%s", gvk, g.Name, g.Version)
+		}
+		kind := g.kind(gvk.Kind)
+		if kind == nil {
+			return fmt.Errorf("example of %s is not a kind of %s/%s", gvk.Kind, g.Name, g.Version)
+		}
+		manifest, err := Manifest(object)
+		if err != nil {
+			return err
+		}
+		kind.Example = manifest
+	}
+	for _, kind := range g.Kinds {
+		if kind.Example == "" {
+			return fmt.Errorf("%s of %s/%s has no example", kind.Name, g.Name, g.Version)
+		}
+	}
+	return nil
+}
+
+func (g *Group) kind(name string) *Kind {
+	for _, kind := range g.Kinds {
+		if kind.Name == name {
+			return kind
+		}
+	}
+	return nil
+}
+
+// Manifest renders an object as the YAML that a user applies. The status, the server-side
+// metadata, and the empty fields are left out.
+func Manifest(object runtime.Object) (string, error) {
+	encoded, err := json.Marshal(object)
+	if err != nil {
+		return "", err
+	}
+	manifest := map[string]interface{}{}
+	if err := json.Unmarshal(encoded, &manifest); err != nil {
+		return "", err
+	}
+	delete(manifest, "status")
+	if metadata, ok := manifest["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+	}
+	rendered, err := yaml.Marshal(prune(manifest))
+	if err != nil {
+		return "", err
+	}
+	return string(rendered), nil
+}
+
+// prune removes the null values and empty objects, and turns the whole numbers decoded from
+// JSON back into integers
+func prune(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, element := range v {
+			element = prune(element)
+			if object, ok := element.(map[string]interface{}); element == nil || (ok && len(object) == 0) {
+				delete(v, key)
+				continue
+			}
+			v[key] = element
+		}
+		return v
+	case []interface{}:
+		for i, element := range v {
+			v[i] = prune(element)
+		}
+		return v
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v)
+		}
+	}
+	return value
+}
+
+// Render writes the reference of the group in Markdown
+func (g *Group) Render(w io.Writer) error {
+	out := bufio.NewWriter(w)
+	groupVersion := fmt.Sprintf("%s/%s", g.Name, g.Version)
+	fmt.Fprintf(out, "# %s\n\n", groupVersion)
+	fmt.Fprintf(out, "<!-- Generated by cmd/apidocs out of pkg/apis, do not edit -->\n\n")
+	kindLinks := []string{}
+	for _, kind := range g.Kinds {
+		kindLinks = append(kindLinks, link(kind.Name))
+	}
+	fmt.Fprintf(out, "Kinds: %s\n\n", strings.Join(kindLinks, ", "))
+
+	for _, kind := range g.Kinds {
+		scope := "Cluster"
+		if kind.Namespaced {
+			scope = "Namespaced"
+		}
+		fmt.Fprintf(out, "## %s\n\n%s\n\nScope: %s\n\n", kind.Name, kind.Doc, scope)
+		if len(kind.PrintColumns) > 0 {
+			fmt.Fprintf(out, "Columns of `kubectl get`:\n\n| Name | Type | JSONPath |\n| --- | --- | --- |\n")
+			for _, column := range kind.PrintColumns {
+				fmt.Fprintf(out, "| %s | %s | `%s` |\n", column.Name, column.Type, column.JSONPath)
+			}
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "Example:\n\n```yaml\n%s```\n\n", kind.Example)
+		fmt.Fprintf(out, "| Field | Type | Description | Validation |\n| --- | --- | --- | --- |\n")
+		fmt.Fprintf(out, "| apiVersion | string | %s | |\n", groupVersion)
+		fmt.Fprintf(out, "| kind | string | %s | |\n", kind.Name)
+		g.renderFields(out, kind.Type)
+		fmt.Fprintln(out)
+	}
+
+	types := g.referencedTypes()
+	if len(types) > 0 {
+		fmt.Fprintf(out, "## Types\n\n")
+	}
+	for _, t := range types {
+		fmt.Fprintf(out, "### %s\n\n", t.Name)
+		if t.Doc != "" {
+			fmt.Fprintf(out, "%s\n\n", t.Doc)
+		}
+		fmt.Fprintf(out, "| Field | Type | Description | Validation |\n| --- | --- | --- | --- |\n")
+		g.renderFields(out, t)
+		fmt.Fprintln(out)
+	}
+	return out.Flush()
+}
+
+// renderFields writes the rows of the fields of a type
+func (g *Group) renderFields(out io.Writer, t *Type) {
+	for _, field := range t.Fields {
+		rules := []string{}
+		if field.Optional {
+			rules = append(rules, "Optional")
+		}
+		for _, validation := range field.Validation {
+			rules = append(rules, describeValidation(validation))
+		}
+		fmt.Fprintf(out, "| %s | %s | %s | %s |\n", field.Name, g.typeLink(field.GoType), escape(field.Doc), escape(strings.Join(rules, "<br>")))
+	}
+}
+
+// referencedTypes returns the types reachable from the kinds, in the order they are reached
+func (g *Group) referencedTypes() []*Type {
+	types := []*Type{}
+	seen := map[string]bool{}
+	queue := []*Type{}
+	for _, kind := range g.Kinds {
+		seen[kind.Name] = true
+		queue = append(queue, kind.Type)
+	}
+	for len(queue) > 0 {
+		t := queue[0]
+		queue = queue[1:]
+		for _, field := range t.Fields {
+			name := baseType(field.GoType)
+			if referenced, ok := g.Types[name]; ok && !seen[name] {
+				seen[name] = true
+				types = append(types, referenced)
+				queue = append(queue, referenced)
+			}
+		}
+	}
+	return types
+}
+
+// typeLink renders a field type, the types of the group link to their section
+func (g *Group) typeLink(goType string) string {
+	name := baseType(goType)
+	if _, ok := g.Types[name]; ok {
+		return strings.TrimPrefix(strings.TrimSuffix(goType, name), "*") + link(name)
+	}
+	return fmt.Sprintf("`%s`", strings.TrimPrefix(goType, "*"))
+}
+
+// baseType strips the pointer, slice, and map around a type name
+func baseType(goType string) string {
+	goType = strings.TrimLeft(goType, "*[]")
+	if strings.HasPrefix(goType, "map[") {
+		goType = goType[strings.Index(goType, "]")+1:]
+	}
+	return strings.TrimLeft(goType, "*[]")
+}
+
+func link(name string) string {
+	return fmt.Sprintf("[%s](#%s)", name, strings.ToLower(name))
+}
+
+// describeValidation renders a validation marker such as Enum=owner;admin in words
+func describeValidation(validation string) string {
+	parts := strings.SplitN(validation, "=", 2)
+	if len(parts) != 2 {
+		return validation
+	}
+	value := unquote(parts[1])
+	switch parts[0] {
+	case "Enum":
+		return fmt.Sprintf("One of `%s`", strings.Join(strings.Split(value, ";"), "`, `"))
+	case "Minimum":
+		return fmt.Sprintf("Minimum %s", value)
+	case "Maximum":
+		return fmt.Sprintf("Maximum %s", value)
+	case "MinLength":
+		return fmt.Sprintf("Minimum length %s", value)
+	case "MaxLength":
+		return fmt.Sprintf("Maximum length %s", value)
+	case "Pattern":
+		return fmt.Sprintf("Matches `%s`", value)
+	case "Format":
+		return fmt.Sprintf("Format %s", value)
+	}
+	return fmt.Sprintf("%s %s", parts[0], value)
+}
+
+// escape keeps a text within its table cell
+func escape(text string) string {
+	return strings.ReplaceAll(text, "|", "\\|")
+}
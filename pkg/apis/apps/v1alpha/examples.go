@@ -0,0 +1,68 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha
+
+import (
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Examples returns a manifest of each kind in the group, the API reference is rendered with them
+func Examples() []runtime.Object {
+	typeMeta := func(kind string) metav1.TypeMeta {
+		return metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: kind}
+	}
+	deployment := func(name, image string) appsv1.DeploymentSpec {
+		podLabels := map[string]string{"app": name}
+		return appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: podLabels},
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: name, Image: image}}}}}
+	}
+	nginx := deployment("nginx", "nginx:1.21")
+	expiry := metav1.Date(2021, 10, 4, 18, 0, 0, 0, time.UTC)
+
+	return []runtime.Object{
+		&SelectiveDeployment{TypeMeta: typeMeta("SelectiveDeployment"), ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "lip6-lab"},
+			Spec: SelectiveDeploymentSpec{
+				Workloads: Workloads{Deployment: []appsv1.Deployment{{TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+					ObjectMeta: metav1.ObjectMeta{Name: "nginx"}, Spec: nginx}}},
+				Selector: []Selector{{Name: "Country", Value: []string{"FR", "US"}, Operator: corev1.NodeSelectorOpIn, Quantity: 2}},
+				Recovery: true}},
+		&Extension{TypeMeta: typeMeta("Extension"), ObjectMeta: metav1.ObjectMeta{Name: "postgres-operator"},
+			Spec: ExtensionSpec{Description: "Runs PostgreSQL clusters in the tenant namespace",
+				CustomResources: []CustomResource{{Group: "acid.zalan.do", Version: "v1", Resources: []string{"postgresqls"}}},
+				Rules: []rbacv1.PolicyRule{{APIGroups: []string{"acid.zalan.do"}, Resources: []string{"postgresqls", "postgresqls/status"},
+					Verbs: []string{"get", "list", "watch", "update", "patch"}}},
+				Deployment: deployment("postgres-operator", "registry.opensource.zalan.do/acid/postgres-operator:v1.7.0"),
+				Enabled:    true}},
+		&Chart{TypeMeta: typeMeta("Chart"), ObjectMeta: metav1.ObjectMeta{Name: "grafana"},
+			Spec: ChartSpec{Description: "Dashboards for the metrics of the tenant", Repository: "https://grafana.github.io/helm-charts",
+				Chart: "grafana", Versions: []string{"6.17.2", "6.17.3"},
+				Values:  runtime.RawExtension{Raw: []byte(`{"persistence":{"enabled":false}}`)},
+				Enabled: true}},
+		&TenantApp{TypeMeta: typeMeta("TenantApp"), ObjectMeta: metav1.ObjectMeta{Name: "dashboards", Namespace: "lip6-lab"},
+			Spec: TenantAppSpec{Chart: "grafana", Version: "6.17.3",
+				Values: runtime.RawExtension{Raw: []byte(`{"adminUser":"lip6"}`)}}},
+		&Login{TypeMeta: typeMeta("Login"), ObjectMeta: metav1.ObjectMeta{Name: "login-7d9f2", Namespace: "lip6"},
+			Spec: LoginSpec{User: "john.doe@edge-net.org", Method: "oidc", Expiry: &expiry}},
+	}
+}
@@ -52,6 +52,14 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&SelectiveDeployment{},
 		&SelectiveDeploymentList{},
+		&Extension{},
+		&ExtensionList{},
+		&Chart{},
+		&ChartList{},
+		&TenantApp{},
+		&TenantAppList{},
+		&Login{},
+		&LoginList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
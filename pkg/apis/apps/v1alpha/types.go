@@ -21,10 +21,16 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // +genclient
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=".status.ready"
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // SelectiveDeployment describes a SelectiveDeployment resource
@@ -50,6 +56,17 @@ type SelectiveDeploymentSpec struct {
 	// If true, selective deployment tries to find another suitable
 	// node to run the workload in case of a node goes down.
 	Recovery bool `json:"recovery"`
+	// Rollout bounds the pods replaced at once when the workloads are re-targeted
+	// as the nodes selected change. It applies to the deployments and the daemonsets.
+	Rollout *Rollout `json:"rollout,omitempty"`
+}
+
+// Rollout is the rolling update strategy of the re-targeted workloads
+type Rollout struct {
+	// The maximum number of pods that can be scheduled above the desired number of pods.
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// The maximum number of pods that can be unavailable during the update.
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
 // Workloads indicates deployments, daemonsets, statefulsets, jobs, or cronjobs.
@@ -69,15 +86,21 @@ type Workloads struct {
 // Selector to define desired node filtering parameters
 type Selector struct {
 	// Name of the selector. This can be City, State, Country, Continent, or Polygon
+	// +kubebuilder:validation:Enum=City;State;Country;Continent;Polygon
 	Name string `json:"name"`
 	// Value of the selector. For example; if the name of the selector is 'City'
 	// then the value can be the city name. For example; if the name of
 	// the selector is 'Polygon' then the value can be the GeoJSON representation of the polygon.
 	Value []string `json:"value"`
 	// Operator means basic mathematical operators such as 'In', 'NotIn', 'Exists', 'NotExsists' etc...
+	// +kubebuilder:validation:Enum=In;NotIn
 	Operator corev1.NodeSelectorOperator `json:"operator"`
 	// Quantity represents number of nodes on which the workloads will be running.
+	// +kubebuilder:validation:Minimum=1
 	Quantity int `json:"quantity"`
+	// MaxLatency extends the nodes selected with the In operator to the nodes within this
+	// round-trip time of them, as measured by the node links.
+	MaxLatency *metav1.Duration `json:"maxLatency,omitempty"`
 }
 
 // SelectiveDeploymentStatus is the status for a SelectiveDeployment resource
@@ -91,6 +114,37 @@ type SelectiveDeploymentStatus struct {
 	State string `json:"state"`
 	// There can be multiple display messages for state description.
 	Message []string `json:"message"`
+	// Selectors reports the nodes each selector matches, in the order of the selectors.
+	Selectors []SelectorStatus `json:"selectors,omitempty"`
+	// Workloads reports the readiness of each workload.
+	Workloads []WorkloadStatus `json:"workloads,omitempty"`
+	// Conditions of the selective deployment, it is Degraded if a selector matches no node.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// SelectorStatus is the placement of the workloads by a selector
+type SelectorStatus struct {
+	// Name of the selector.
+	Name string `json:"name"`
+	// Value of the selector.
+	Value []string `json:"value"`
+	// Number of nodes the selector matches.
+	Matched int `json:"matched"`
+	// Hostnames of the nodes the selector matches.
+	Nodes []string `json:"nodes,omitempty"`
+}
+
+// WorkloadStatus is the readiness of a workload of the selective deployment. For the jobs,
+// the ready count is the number of completions, and for the cron jobs the number of active jobs.
+type WorkloadStatus struct {
+	// Kind of the workload, such as Deployment.
+	Kind string `json:"kind"`
+	// Name of the workload.
+	Name string `json:"name"`
+	// Number of pods desired.
+	Desired int32 `json:"desired"`
+	// Number of pods ready.
+	Ready int32 `json:"ready"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -105,3 +159,242 @@ type SelectiveDeploymentList struct {
 	// SelectiveDeployments are contained here.
 	Items []SelectiveDeployment `json:"items"`
 }
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +kubebuilder:printcolumn:name="Description",type=string,JSONPath=".spec.description"
+// +kubebuilder:printcolumn:name="Enabled",type=boolean,JSONPath=".spec.enabled"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Extension describes an Extension resource, an entry of the catalog of
+// namespaced operators that tenants can request to install
+type Extension struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the extension resource spec
+	Spec ExtensionSpec `json:"spec"`
+}
+
+// ExtensionSpec is the spec for an Extension resource. The custom resource
+// definitions are installed by the cluster administrators, whereas the
+// operator is deployed into the namespace of each tenant whose request is approved.
+type ExtensionSpec struct {
+	// Description of the extension to be shown to the tenants.
+	Description string `json:"description"`
+	// List of custom resources the operator manages. They must be namespaced
+	// and become available to the requester in the tenant namespace.
+	CustomResources []CustomResource `json:"customresources"`
+	// Rules of the role granted to the operator in the tenant namespace.
+	Rules []rbacv1.PolicyRule `json:"rules"`
+	// Deployment of the operator that runs in the tenant namespace.
+	Deployment appsv1.DeploymentSpec `json:"deployment"`
+	// Extension is open to new requests if true.
+	Enabled bool `json:"enabled"`
+}
+
+// CustomResource indicates the resources of a custom resource definition
+type CustomResource struct {
+	// API group of the resources.
+	Group string `json:"group"`
+	// API version of the resources.
+	Version string `json:"version"`
+	// Plural names of the resources.
+	Resources []string `json:"resources"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ExtensionList is a list of Extension resources
+type ExtensionList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// ExtensionList is a list of Extension resources thus,
+	// Extensions are contained here.
+	Items []Extension `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +genclient:noStatus
+// +kubebuilder:printcolumn:name="Chart",type=string,JSONPath=".spec.chart"
+// +kubebuilder:printcolumn:name="Repository",type=string,JSONPath=".spec.repository"
+// +kubebuilder:printcolumn:name="Enabled",type=boolean,JSONPath=".spec.enabled"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Chart describes a Chart resource, an entry of the catalog of Helm charts
+// that tenants can deploy into their namespaces
+type Chart struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the chart resource spec
+	Spec ChartSpec `json:"spec"`
+}
+
+// ChartSpec is the spec for a Chart resource
+type ChartSpec struct {
+	// Description of the chart to be shown to the tenants.
+	Description string `json:"description"`
+	// URL of the chart repository.
+	Repository string `json:"repository"`
+	// Name of the chart in the repository.
+	Chart string `json:"chart"`
+	// Versions of the chart that tenants can deploy.
+	Versions []string `json:"versions"`
+	// Default values of the chart, overridden by the values of tenants.
+	Values runtime.RawExtension `json:"values,omitempty"`
+	// Chart is open to new deployments if true.
+	Enabled bool `json:"enabled"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ChartList is a list of Chart resources
+type ChartList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// ChartList is a list of Chart resources thus,
+	// Charts are contained here.
+	Items []Chart `json:"items"`
+}
+
+// +genclient
+// +kubebuilder:printcolumn:name="Chart",type=string,JSONPath=".spec.chart"
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=".status.version"
+// +kubebuilder:printcolumn:name="Revision",type=integer,JSONPath=".status.revision"
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantApp describes a TenantApp resource, a release of a chart from the
+// catalog in a tenant namespace
+type TenantApp struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the tenantapp resource spec
+	Spec TenantAppSpec `json:"spec"`
+	// Status is the tenantapp resource status
+	Status TenantAppStatus `json:"status,omitempty"`
+}
+
+// TenantAppSpec is the spec for a TenantApp resource. Changing the version or
+// the values upgrades the release, and deleting the object uninstalls it.
+type TenantAppSpec struct {
+	// Name of the chart in the catalog.
+	Chart string `json:"chart"`
+	// Version of the chart, it must be one of the versions in the catalog.
+	Version string `json:"version"`
+	// Values of the release.
+	Values runtime.RawExtension `json:"values,omitempty"`
+}
+
+// TenantAppStatus is the status for a TenantApp resource
+type TenantAppStatus struct {
+	// Current state of the release. This can be 'Failure', or 'Deployed'.
+	State string `json:"state"`
+	// Description for additional information.
+	Message string `json:"message"`
+	// Version of the chart deployed.
+	Version string `json:"version"`
+	// Revision increases by one on each upgrade.
+	Revision int `json:"revision"`
+	// Generation of the spec that the release reflects.
+	ObservedGeneration int64 `json:"observedgeneration"`
+	// Objects that make up the release.
+	Resources []AppResource `json:"resources"`
+}
+
+// AppResource indicates an object created by a release
+type AppResource struct {
+	// API version of the object.
+	APIVersion string `json:"apiversion"`
+	// Kind of the object.
+	Kind string `json:"kind"`
+	// Name of the object.
+	Name string `json:"name"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantAppList is a list of TenantApp resources
+type TenantAppList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// TenantAppList is a list of TenantApp resources thus,
+	// TenantApps are contained here.
+	Items []TenantApp `json:"items"`
+}
+
+// +genclient
+// +kubebuilder:printcolumn:name="User",type=string,JSONPath=".spec.user"
+// +kubebuilder:printcolumn:name="Method",type=string,JSONPath=".spec.method"
+// +kubebuilder:printcolumn:name="Expiry",type=string,JSONPath=".spec.expiry"
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Login describes a Login resource, a session of a user in the web console
+// recorded in the core namespace of the tenant. Deleting the object ends the
+// session and revokes its tokens.
+type Login struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the login resource spec
+	Spec LoginSpec `json:"spec"`
+	// Status is the login resource status
+	Status LoginStatus `json:"status,omitempty"`
+}
+
+// LoginSpec is the spec for a Login resource. It is set by the registration
+// API once it has validated the credentials of the user.
+type LoginSpec struct {
+	// User the credentials were issued to, the email address that the role
+	// bindings name.
+	User string `json:"user"`
+	// Method the credentials were validated with. This can be 'certificate',
+	// or 'oidc'.
+	Method string `json:"method"`
+	// Expiry is when the session ends, no token outlives it.
+	Expiry *metav1.Time `json:"expiry"`
+}
+
+// LoginStatus is the status for a Login resource
+type LoginStatus struct {
+	// Current state of the session. This can be 'Failure', or 'Active'.
+	State string `json:"state"`
+	// Description for additional information.
+	Message string `json:"message"`
+	// ServiceAccount the tokens of the session are issued for.
+	ServiceAccount string `json:"serviceaccount"`
+	// Namespaces in which the roles of the user are bound to the session.
+	Namespaces []string `json:"namespaces"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// LoginList is a list of Login resources
+type LoginList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// LoginList is a list of Login resources thus,
+	// Logins are contained here.
+	Items []Login `json:"items"`
+}
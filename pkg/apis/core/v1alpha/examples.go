@@ -0,0 +1,98 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Examples returns a manifest of each kind in the group, the API reference is rendered with them
+func Examples() []runtime.Object {
+	typeMeta := func(kind string) metav1.TypeMeta {
+		return metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: kind}
+	}
+	contact := Contact{Handle: "johndoe", FirstName: "John", LastName: "Doe", Email: "john.doe@edge-net.org", Phone: "+33000000000"}
+	tenantName := "lip6-lab"
+	softLimit := 80
+	imageGCHigh, imageGCLow := int32(70), int32(50)
+
+	return []runtime.Object{
+		&Tenant{TypeMeta: typeMeta("Tenant"), ObjectMeta: metav1.ObjectMeta{Name: tenantName},
+			Spec: TenantSpec{FullName: "LIP6", ShortName: "LIP6", URL: "https://www.lip6.fr",
+				Address: Address{Street: "4 place Jussieu", ZIP: "75005", City: "Paris", Region: "Ile-de-France", Country: "France"},
+				Contact: contact, ClusterNetworkPolicy: true,
+				Priority:      &Priority{Tier: "community", Weight: 100},
+				GroupBindings: []GroupBinding{{Group: "lip6-admins", Role: "admin"}},
+				Enabled:       true}},
+		&SubNamespace{TypeMeta: typeMeta("SubNamespace"), ObjectMeta: metav1.ObjectMeta{Name: "experiments", Namespace: tenantName},
+			Spec: SubNamespaceSpec{Workspace: &Workspace{
+				ResourceAllocation: map[corev1.ResourceName]resource.Quantity{
+					corev1.ResourceCPU:    resource.MustParse("2000m"),
+					corev1.ResourceMemory: resource.MustParse("2Gi"),
+				},
+				Inheritance: map[string]bool{"rbac": true, "networkpolicy": true, "limitrange": true, "configmap": false, "secret": false, "serviceaccount": false},
+				Scope:       "local", Sync: false, Owner: &contact}}},
+		&ClusterUpgradePlan{TypeMeta: typeMeta("ClusterUpgradePlan"), ObjectMeta: metav1.ObjectMeta{Name: "kubelet-1-21-3"},
+			Spec: ClusterUpgradePlanSpec{KubeletVersion: "v1.21.3", ContainerdVersion: "1.4.9",
+				Policy: RolloutPolicy{MaxUnavailable: 2, NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"edge-net.io/country-iso": "FR"}}}}},
+		&NodeContribution{TypeMeta: typeMeta("NodeContribution"), ObjectMeta: metav1.ObjectMeta{Name: "node-paris-1"},
+			Spec: NodeContributionSpec{Tenant: &tenantName, Host: "192.0.2.10", Port: 22, User: "edgenet", Enabled: true,
+				Limitations: []Limitations{{Kind: "Tenant", Indentifier: tenantName}}}},
+		&NodeConfiguration{TypeMeta: typeMeta("NodeConfiguration"), ObjectMeta: metav1.ObjectMeta{Name: "teaching"},
+			Spec: NodeConfigurationSpec{NodePool: "teaching",
+				EvictionHard: map[string]string{"nodefs.available": "10%", "imagefs.available": "1Gi"},
+				ImageGC:      &ImageGC{HighThresholdPercent: &imageGCHigh, LowThresholdPercent: &imageGCLow, MinimumAge: &metav1.Duration{Duration: 10 * time.Minute}}}},
+		&NodePool{TypeMeta: typeMeta("NodePool"), ObjectMeta: metav1.ObjectMeta{Name: "teaching"},
+			Spec: NodePoolSpec{Description: "Nodes of the universities offered to the classrooms",
+				Selector: metav1.LabelSelector{MatchLabels: map[string]string{"edge-net.io/institution-type": "university"}}}},
+		&Operation{TypeMeta: typeMeta("Operation"), ObjectMeta: metav1.ObjectMeta{Name: "lip6-lab-teardown"},
+			Spec: OperationSpec{Type: "TenantTeardown",
+				Initiator:  corev1.ObjectReference{APIVersion: SchemeGroupVersion.String(), Kind: "Tenant", Name: tenantName},
+				Parameters: map[string]string{"tenant": tenantName}, MaxRetries: 3}},
+		&InstallCheck{TypeMeta: typeMeta("InstallCheck"), ObjectMeta: metav1.ObjectMeta{Name: "post-upgrade"},
+			Spec: InstallCheckSpec{Email: "operator@edge-net.org", ProbeImage: "busybox:1.34", StepTimeout: 300}},
+		&TenantAudit{TypeMeta: typeMeta("TenantAudit"), ObjectMeta: metav1.ObjectMeta{Name: tenantName + "-kxq3f1b2c0w0"},
+			Spec: TenantAuditSpec{Tenant: tenantName, Action: "RequestApproved", Actor: "admin@edge-net.org",
+				Object:  TenantAuditObject{Kind: "TenantRequest", Name: tenantName},
+				Message: "The tenant request is approved", Time: metav1.Date(2021, 10, 1, 9, 0, 0, 0, time.UTC)}},
+		&TenantMigration{TypeMeta: typeMeta("TenantMigration"), ObjectMeta: metav1.ObjectMeta{Name: "lip6-lab-to-lip6"},
+			Spec: TenantMigrationSpec{Source: tenantName, Target: "lip6", DisableSource: true}},
+		&TenantProfile{TypeMeta: typeMeta("TenantProfile"), ObjectMeta: metav1.ObjectMeta{Name: "classroom"},
+			Spec: TenantProfileSpec{ResourceAllocation: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8"), corev1.ResourceMemory: resource.MustParse("8Gi")},
+				NetworkPolicy: "baseline", NodePools: []string{"teaching"}, Expiry: &metav1.Duration{Duration: 120 * 24 * time.Hour}}},
+		&TenantServiceAccount{TypeMeta: typeMeta("TenantServiceAccount"), ObjectMeta: metav1.ObjectMeta{Name: "ci-pipeline", Namespace: tenantName},
+			Spec: TenantServiceAccountSpec{Role: "collaborator", Namespaces: []string{"experiments-8a2c3f9b"}, RotationPeriod: 720}},
+		&TenantSecretStore{TypeMeta: typeMeta("TenantSecretStore"), ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: tenantName},
+			Spec: TenantSecretStoreSpec{Secrets: []TenantSecretSync{{Name: "registry-credentials", Path: "registry", Keys: []string{"username", "password"},
+				Namespaces: []string{"experiments-8a2c3f9b"}}}, RefreshInterval: 60}},
+		&TenantUsage{TypeMeta: typeMeta("TenantUsage"), ObjectMeta: metav1.ObjectMeta{Name: tenantName},
+			Spec: TenantUsageSpec{Tenant: tenantName}},
+		&TenantResourceQuota{TypeMeta: typeMeta("TenantResourceQuota"), ObjectMeta: metav1.ObjectMeta{Name: tenantName},
+			Spec: TenantResourceQuotaSpec{
+				Claim: map[string]ResourceTuning{"initial": {ResourceList: map[corev1.ResourceName]resource.Quantity{
+					corev1.ResourceCPU:    resource.MustParse("8000m"),
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				}}},
+				Drop:      map[string]ResourceTuning{},
+				SoftLimit: &softLimit}},
+	}
+}
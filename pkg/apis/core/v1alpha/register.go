@@ -52,8 +52,30 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		&Tenant{},
 		&TenantList{},
+		&ClusterUpgradePlan{},
+		&ClusterUpgradePlanList{},
+		&NodeConfiguration{},
+		&NodeConfigurationList{},
 		&NodeContribution{},
 		&NodeContributionList{},
+		&Operation{},
+		&OperationList{},
+		&InstallCheck{},
+		&InstallCheckList{},
+		&TenantAudit{},
+		&TenantAuditList{},
+		&TenantMigration{},
+		&TenantMigrationList{},
+		&TenantProfile{},
+		&TenantProfileList{},
+		&NodePool{},
+		&NodePoolList{},
+		&TenantSecretStore{},
+		&TenantServiceAccount{},
+		&TenantSecretStoreList{},
+		&TenantServiceAccountList{},
+		&TenantUsage{},
+		&TenantUsageList{},
 		&TenantResourceQuota{},
 		&TenantResourceQuotaList{},
 		&SubNamespace{},
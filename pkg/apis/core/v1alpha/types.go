@@ -18,16 +18,25 @@ package v1alpha
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/EdgeNet-project/edgenet/pkg/util"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // +genclient
 // +genclient:nonNamespaced
+// +kubebuilder:printcolumn:name="Official Name",type=string,JSONPath=".spec.fullname"
+// +kubebuilder:printcolumn:name="Short Name",type=string,JSONPath=".spec.shortname"
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=".spec.url"
+// +kubebuilder:printcolumn:name="City",type=string,JSONPath=".spec.address.city"
+// +kubebuilder:printcolumn:name="Country",type=string,JSONPath=".spec.address.country"
+// +kubebuilder:printcolumn:name="Enabled",type=boolean,JSONPath=".spec.enabled"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // Tenant describes a tenant that consumes the cluster resources in an isolated environment
@@ -54,13 +63,132 @@ type TenantSpec struct {
 	Address Address `json:"address"`
 	// Contact information of the tenant.
 	Contact Contact `json:"contact"`
+	// Owners of the tenant besides the contact, who is always an owner. Owners are added and
+	// removed without disabling the tenant.
+	Owners []Contact `json:"owners,omitempty"`
+	// Administrators of the tenant, they hold the admin role in the core namespace.
+	Admins []Contact `json:"admins,omitempty"`
 	// Whether cluster-level network policies will be applied to tenant namespaces
 	// for security purposes.
 	ClusterNetworkPolicy bool `json:"clusternetworkpolicy"`
+	// Limits applied to each container in the tenant namespaces. The cluster
+	// defaults are used for the limits left empty.
+	ContainerLimits *ContainerLimits `json:"containerlimits,omitempty"`
+	// A dedicated priority class is generated and assigned by default to the pods
+	// in the tenant namespaces if set.
+	Priority *Priority `json:"priority,omitempty"`
+	// Groups of the identity provider whose members hold a tenant role, so that the
+	// membership is managed in the identity provider rather than by user emails.
+	GroupBindings []GroupBinding `json:"groupbindings,omitempty"`
+	// Profile the tenant was created with.
+	Profile string `json:"profile,omitempty"`
+	// Node pools the workloads of the tenant are allowed on, all nodes if empty.
+	NodePools []string `json:"nodepools,omitempty"`
+	// The tenant gets an ingress class of its own, with the certificates of its ingresses
+	// issued by ACME, if set.
+	Ingress *Ingress `json:"ingress,omitempty"`
+	// The sidecars of a service mesh are injected into the pods of the tenant if set, the traffic
+	// between them is encrypted and the traffic from the other tenants denied.
+	Mesh *Mesh `json:"mesh,omitempty"`
+	// Destinations outside the cluster the pods of the tenant reach if set, all of them otherwise.
+	// The DNS and the namespaces of the tenant are always reached.
+	Egress *Egress `json:"egress,omitempty"`
+	// Checks of the pod security level of the tenant that its pods are exempted from. The level
+	// follows the tier of the tenant, 'baseline' for the paying tier and 'restricted' otherwise.
+	// +kubebuilder:validation:items:Enum=hostNamespaces;privileged;capabilities;hostPathVolumes;procMount;sysctls;seccompProfile;allowPrivilegeEscalation;runAsNonRoot;volumeTypes
+	PodSecurityExemptions []string `json:"podsecurityexemptions,omitempty"`
+	// Public keys the tenant signs its requests with, so that the automation acting as the tenant
+	// is authenticated without the kubeconfig of an owner.
+	SigningKeys []SigningKey `json:"signingkeys,omitempty"`
+	// The requests of the tenant without a valid signature by one of its keys are refused if set.
+	RequireSignedRequests bool `json:"requiresignedrequests,omitempty"`
 	// If the tenant is active then this field is true.
 	Enabled bool `json:"enabled"`
 }
 
+// OwnerContacts returns the contact of the tenant followed by its other owners, an owner listed
+// twice is returned once
+func (t TenantSpec) OwnerContacts() []Contact {
+	owners := []Contact{t.Contact}
+	seen := map[string]bool{strings.ToLower(t.Contact.Email): true}
+	for _, owner := range t.Owners {
+		if email := strings.ToLower(owner.Email); !seen[email] {
+			seen[email] = true
+			owners = append(owners, owner)
+		}
+	}
+	return owners
+}
+
+// Ingress is the exposure of the services of a tenant through the ingress controllers of the edge nodes
+type Ingress struct {
+	// Controller implementing the ingress class of the tenant, 'k8s.io/ingress-nginx' by default.
+	Controller string `json:"controller,omitempty"`
+	// Cluster issuer of cert-manager that issues the certificates of the ingresses through ACME,
+	// 'letsencrypt' by default.
+	Issuer string `json:"issuer,omitempty"`
+}
+
+// Mesh is the membership of the tenant namespaces in a service mesh
+type Mesh struct {
+	// Service mesh injecting the sidecars, 'istio' or 'linkerd'.
+	// +kubebuilder:validation:Enum=istio;linkerd
+	Provider string `json:"provider"`
+}
+
+// Egress lists the destinations outside the cluster the pods of a tenant reach
+type Egress struct {
+	// Address ranges in CIDR notation, of either address family.
+	CIDRs []string `json:"cidrs,omitempty"`
+	// Domain names, a leading '*.' matches the subdomains. They are enforced by the DNS-aware
+	// policies of Cilium, and fail to apply on the clusters without it.
+	FQDNs []string `json:"fqdns,omitempty"`
+}
+
+// GroupBinding maps a group of the identity provider to a tenant role
+type GroupBinding struct {
+	// Name of the group as it appears in the groups claim of the OIDC tokens.
+	// +kubebuilder:validation:MinLength=1
+	Group string `json:"group"`
+	// Role of the group members in the tenant, 'owner', 'admin', or 'collaborator'.
+	// +kubebuilder:validation:Enum=owner;admin;collaborator
+	Role string `json:"role"`
+}
+
+// SigningKey is a public key of a tenant that verifies the signatures of its requests
+type SigningKey struct {
+	// Name of the key, which the signed requests refer to.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// PEM encoded Ed25519 or ECDSA public key.
+	PublicKey string `json:"publickey"`
+}
+
+// Priority describes the priority class of a tenant
+type Priority struct {
+	// Tier of the tenant, 'community' or 'paying'. Each tier has its own band of priority values
+	// so that the pods of paying tenants can preempt the pods of community tenants.
+	// +kubebuilder:validation:Enum=community;paying
+	Tier string `json:"tier"`
+	// Position of the tenant within the band of its tier, from 0 to 999.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=999
+	Weight int32 `json:"weight"`
+	// Whether the pods of the tenant preempt the pods with lower priority, 'PreemptLowerPriority' by default.
+	// +kubebuilder:validation:Enum=PreemptLowerPriority;Never
+	PreemptionPolicy *corev1.PreemptionPolicy `json:"preemptionpolicy,omitempty"`
+}
+
+// ContainerLimits describes the limit range of the containers in tenant namespaces
+type ContainerLimits struct {
+	// Limits assigned to the containers that do not set any.
+	Default corev1.ResourceList `json:"default,omitempty"`
+	// Requests assigned to the containers that do not set any.
+	DefaultRequest corev1.ResourceList `json:"defaultrequest,omitempty"`
+	// Maximum limits a container can set.
+	Max corev1.ResourceList `json:"max,omitempty"`
+}
+
 // Address describes postal address of tenant
 type Address struct {
 	// Street name.
@@ -95,6 +223,46 @@ type TenantStatus struct {
 	State string `json:"state"`
 	// Additional description can be located here.
 	Message string `json:"message"`
+	// The generation of the spec that the controller processed last.
+	ObservedGeneration int64 `json:"observedgeneration"`
+	// Number of consecutive syncs that failed, it is reset after a successful sync.
+	Retries int `json:"retries"`
+	// Time of the last failed sync.
+	LastFailure *metav1.Time `json:"lastfailure,omitempty"`
+	// Conditions record the results of the checks run against an established
+	// tenant, such as the verification probes.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// NetworkPolicies reports the sync of the network policies to each subnamespace of the tenant.
+	NetworkPolicies []NamespacePolicySync `json:"networkpolicies,omitempty"`
+	// Operation is the long-running operation the tenant started last, such as the teardown of
+	// its subnamespaces.
+	Operation *OperationReference `json:"operation,omitempty"`
+	// Onboarding is the progress of the establishment of the tenant through its ordered steps.
+	Onboarding *Onboarding `json:"onboarding,omitempty"`
+}
+
+// Onboarding is the step the establishment of a tenant reached, a retry resumes at the step
+// that failed as long as the spec stays the same
+type Onboarding struct {
+	// The step can be 'Namespace', 'Quota', 'RBAC', 'Network', 'Notify', or 'Done' once all
+	// of them are complete. The steps before it are complete.
+	Step string `json:"step"`
+	// Failed tells whether the step failed, it hasn't run yet otherwise.
+	Failed bool `json:"failed,omitempty"`
+	// Message contains the error the step failed with.
+	Message string `json:"message,omitempty"`
+	// The generation of the spec the completed steps applied.
+	Generation int64 `json:"generation"`
+}
+
+// NamespacePolicySync is the outcome of the network policy sync in a subnamespace
+type NamespacePolicySync struct {
+	// Name of the subnamespace.
+	Namespace string `json:"namespace"`
+	// This can be 'Synced', 'Failure', 'Rolled Back', or 'Skipped'.
+	State string `json:"state"`
+	// Message contains additional information.
+	Message string `json:"message,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -110,6 +278,12 @@ type TenantList struct {
 }
 
 // +genclient
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="CPU Allocation",type=string,JSONPath=".spec.resources.cpu"
+// +kubebuilder:printcolumn:name="Memory Allocation",type=string,JSONPath=".spec.resources.memory"
+// +kubebuilder:printcolumn:name="Network Policy Inheritance",type=string,JSONPath=".spec.inheritance.networkpolicy"
+// +kubebuilder:printcolumn:name="RBAC Inheritance",type=string,JSONPath=".spec.inheritance.rbac"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // SubNamespace describes a SubNamespace resource
@@ -152,6 +326,44 @@ type Workspace struct {
 	Sync bool `json:"sync"`
 	// Owner of the workspace.
 	Owner *Contact `json:"owner"`
+	// Clone copies the objects of another workspace into this one at creation.
+	Clone *Clone `json:"clone,omitempty"`
+	// Lending opts the workspace into lending the quota it leaves unused to its siblings, or
+	// borrowing theirs.
+	Lending *QuotaLending `json:"lending,omitempty"`
+}
+
+// Clone refers to the workspace whose Deployments, Services, and Config Maps are copied.
+// References to the source namespace are rewritten to point to the new one.
+type Clone struct {
+	// Name of the source subnamespace, it must be in the same namespace.
+	Source string `json:"source"`
+	// If the secrets are copied as well.
+	Secrets bool `json:"secrets"`
+}
+
+// QuotaLending lets the sibling workspaces in a namespace borrow the quota they leave unused
+// from each other. A lender keeps half of its unused quota at least, and takes back what it
+// lent once it runs out of its own.
+type QuotaLending struct {
+	// Resources the workspace lends to its siblings at most.
+	Lend map[corev1.ResourceName]resource.Quantity `json:"lend,omitempty"`
+	// Resources the workspace borrows from its siblings at most.
+	Borrow map[corev1.ResourceName]resource.Quantity `json:"borrow,omitempty"`
+	// ReclaimPolicy can be 'Immediate', or 'Graceful'. Immediate takes the loans back at once,
+	// Graceful takes back what the borrowers leave unused and the rest as they free it.
+	// It is Graceful if empty.
+	ReclaimPolicy string `json:"reclaimpolicy,omitempty"`
+}
+
+// QuotaLoan is quota lent by a workspace to a sibling
+type QuotaLoan struct {
+	// Name of the sibling subnamespace, the lender or the borrower.
+	Sibling string `json:"sibling"`
+	// Resources lent.
+	Resources map[corev1.ResourceName]resource.Quantity `json:"resources"`
+	// Since when the loan is in its current amount.
+	Since metav1.Time `json:"since"`
 }
 
 // Subtenant resource represents a tenant under another tenant.
@@ -169,6 +381,28 @@ type SubNamespaceStatus struct {
 	State string `json:"state"`
 	// Message contains additional information.
 	Message string `json:"message"`
+	// If the objects of the clone source are copied into the workspace.
+	Cloned bool `json:"cloned,omitempty"`
+	// Quota the workspace borrowed from its siblings.
+	Borrowed []QuotaLoan `json:"borrowed,omitempty"`
+	// Quota the workspace lent to its siblings.
+	Lent []QuotaLoan `json:"lent,omitempty"`
+	// Namespace the subnamespace formed, the core namespace of the subtenant for a subtenant.
+	Child string `json:"child,omitempty"`
+	// Subnamespace that formed the namespace this one is in, none in the core namespace of a tenant.
+	Parent *SubNamespaceReference `json:"parent,omitempty"`
+	// Subnamespaces in the namespace of the workspace.
+	Children []SubNamespaceReference `json:"children,omitempty"`
+	// Levels below the core namespace of the tenant, 1 in the core namespace.
+	Depth int `json:"depth,omitempty"`
+}
+
+// SubNamespaceReference refers to a subnamespace
+type SubNamespaceReference struct {
+	// Namespace the subnamespace is in.
+	Namespace string `json:"namespace"`
+	// Name of the subnamespace.
+	Name string `json:"name"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -225,6 +459,93 @@ func (s SubNamespace) GetMode() string {
 
 // +genclient
 // +genclient:nonNamespaced
+// +kubebuilder:printcolumn:name="Kubelet",type=string,JSONPath=".spec.kubeletversion"
+// +kubebuilder:printcolumn:name="Containerd",type=string,JSONPath=".spec.containerdversion"
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Upgraded",type=integer,JSONPath=".status.upgraded"
+// +kubebuilder:printcolumn:name="Total",type=integer,JSONPath=".status.total"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterUpgradePlan describes the upgrade of kubelet and containerd on the contributed nodes
+type ClusterUpgradePlan struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the cluster upgrade plan resource spec
+	Spec ClusterUpgradePlanSpec `json:"spec"`
+	// Status is the cluster upgrade plan resource status
+	Status ClusterUpgradePlanStatus `json:"status,omitempty"`
+}
+
+// ClusterUpgradePlanSpec is the spec for a ClusterUpgradePlan resource
+type ClusterUpgradePlanSpec struct {
+	// Kubelet version to upgrade the nodes to, such as 'v1.21.3'.
+	// +kubebuilder:validation:Pattern=`^v?[0-9]+\.[0-9]+\.[0-9]+$`
+	KubeletVersion string `json:"kubeletversion"`
+	// Containerd version to upgrade the nodes to, containerd is left as it is if empty.
+	// +kubebuilder:validation:Pattern=`^[0-9]+\.[0-9]+\.[0-9]+(-[0-9]+)?$`
+	ContainerdVersion string `json:"containerdversion,omitempty"`
+	// Policy of the rollout over the nodes.
+	Policy RolloutPolicy `json:"policy"`
+}
+
+// RolloutPolicy describes which nodes get upgraded and how many at a time
+type RolloutPolicy struct {
+	// Maximum number of nodes being upgraded at the same time, 1 if not set.
+	// +kubebuilder:validation:Minimum=0
+	MaxUnavailable int `json:"maxunavailable"`
+	// Selector of the nodes to upgrade, all contributed nodes if not set.
+	NodeSelector *metav1.LabelSelector `json:"nodeselector,omitempty"`
+}
+
+// ClusterUpgradePlanStatus is the status for a ClusterUpgradePlan resource
+type ClusterUpgradePlanStatus struct {
+	// This can be 'In Progress', 'Completed', 'Halted', or 'Failure'.
+	State string `json:"state"`
+	// Message contains additional information.
+	Message string `json:"message"`
+	// Number of nodes selected by the plan.
+	Total int `json:"total"`
+	// Number of nodes upgraded so far.
+	Upgraded int `json:"upgraded"`
+	// Nodes reports the progress of the upgrade on each node.
+	Nodes []NodeUpgradeProgress `json:"nodes,omitempty"`
+}
+
+// NodeUpgradeProgress is the progress of the upgrade on a node
+type NodeUpgradeProgress struct {
+	// Name of the node contribution.
+	NodeContribution string `json:"nodecontribution"`
+	// This can be 'Pending', 'Scheduled', 'Upgrading', 'Upgraded', 'Up To Date', or 'Failure'.
+	State string `json:"state"`
+	// Message contains additional information.
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterUpgradePlanList is a list of ClusterUpgradePlan resources
+type ClusterUpgradePlanList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// ClusterUpgradePlanList is a list of ClusterUpgradePlan resources. This element contains
+	// ClusterUpgradePlan resources.
+	Items []ClusterUpgradePlan `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:printcolumn:name="Address",type=string,JSONPath=".spec.host"
+// +kubebuilder:printcolumn:name="Port",type=integer,JSONPath=".spec.port"
+// +kubebuilder:printcolumn:name="Enabled",type=boolean,JSONPath=".spec.enabled"
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Maintenance",type=string,JSONPath=".status.maintenance.state"
+// +kubebuilder:printcolumn:name="Upgrade",type=string,JSONPath=".status.upgrade.state"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // NodeContribution describes a NodeContribution resource
@@ -243,25 +564,37 @@ type NodeContribution struct {
 type NodeContributionSpec struct {
 	// Tenant resource of the contributor. This is to award the tenant
 	// who contributes to the cluster with the node.
+	// +kubebuilder:validation:Pattern=`[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*`
 	Tenant *string `json:"tenant"`
 	// Name of the host.
 	Host string `json:"host"`
 	// SSH port.
+	// +kubebuilder:validation:Minimum=1
 	Port int `json:"port"`
 	// SSH username.
 	User string `json:"user"`
 	// To enable/disable scheduling on the contributed node.
 	Enabled bool `json:"enabled"`
+	// To cordon and drain the contributed node for reboots and upgrades. The node
+	// gets uncordoned once the maintenance is cleared.
+	Maintenance bool `json:"maintenance,omitempty"`
 	// Each contribution can have none or many limitations. This field denotese these
 	// limitations.
 	Limitations []Limitations `json:"limitations"`
+	// How the node joins the cluster, 'SSH' by default where EdgeNet logs in to the node as the
+	// user, or 'Token' where the contributor runs the bootstrap script with a one-time join token.
+	// The port and the user are not used with a token.
+	// +kubebuilder:validation:Enum=SSH;Token
+	Mode string `json:"mode,omitempty"`
 }
 
 // Limitations describes which tenants and namespaces can make use of node
 type Limitations struct {
 	// Kind of the limitation.
+	// +kubebuilder:validation:Enum=Tenant;Namespace
 	Kind string `json:"kind"`
 	// Identifier of the limitator.
+	// +kubebuilder:validation:Pattern=`[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*`
 	Indentifier string `json:"identifier"`
 }
 
@@ -271,6 +604,87 @@ type NodeContributionStatus struct {
 	State string `json:"state"`
 	// Message contains additional information.
 	Message []string `json:"message"`
+	// Maintenance reports the progress of the drain while the node is under maintenance.
+	Maintenance *MaintenanceStatus `json:"maintenance,omitempty"`
+	// Upgrade reports the progress of the upgrade scheduled by a cluster upgrade plan.
+	Upgrade *UpgradeStatus `json:"upgrade,omitempty"`
+	// Time when the node stopped being ready, cleared once it is ready again.
+	NotReadySince *metav1.Time `json:"notreadysince,omitempty"`
+	// Preflight reports the checks run on the node before it joins the cluster.
+	Preflight *PreflightStatus `json:"preflight,omitempty"`
+	// Join reports the one-time join token of a node contributed with a token.
+	Join *JoinStatus `json:"join,omitempty"`
+}
+
+// JoinStatus is the one-time join token given to the contributor of a node
+type JoinStatus struct {
+	// SHA-256 hash of the token, the token itself is only kept in the secret.
+	TokenHash string `json:"tokenhash"`
+	// Secret holding the token and the command running the bootstrap script with it.
+	Secret corev1.SecretReference `json:"secret"`
+	// Time when the token expires, a new one is issued then if the node has not joined.
+	Expires metav1.Time `json:"expires"`
+	// Time when the token was exchanged for the join command, it is not accepted again.
+	Used *metav1.Time `json:"used,omitempty"`
+	// Node that joined the cluster with the token.
+	Node string `json:"node,omitempty"`
+}
+
+// PreflightStatus is the outcome of the checks run on a node before it joins the cluster
+type PreflightStatus struct {
+	// Whether the node passed all checks, it does not join the cluster otherwise.
+	Passed bool `json:"passed"`
+	// Checks run on the node.
+	Checks []PreflightCheck `json:"checks"`
+	// Time when the checks ran.
+	Checked metav1.Time `json:"checked"`
+}
+
+// PreflightCheck is the outcome of a single check run on a node
+type PreflightCheck struct {
+	// This can be 'Kernel', 'Architecture', 'OS', 'CgroupDriver', 'Ports', 'NAT', or 'DiskSpace'.
+	Name string `json:"name"`
+	// Whether the node passed the check.
+	Passed bool `json:"passed"`
+	// Value observed on the node.
+	Value string `json:"value,omitempty"`
+	// Reason of the failure and how to fix it.
+	Reason string `json:"reason,omitempty"`
+}
+
+// MaintenanceStatus is the progress of the drain of a contributed node
+type MaintenanceStatus struct {
+	// This can be 'Cordoned', 'Draining', 'Drained', or 'Failure'.
+	// +kubebuilder:validation:Enum=Cordoned;Draining;Drained;Failure
+	State string `json:"state"`
+	// Number of pods evicted from the node so far.
+	Evicted int `json:"evicted"`
+	// Number of pods waiting for their eviction, either blocked by a
+	// PodDisruptionBudget or still terminating.
+	Remaining int `json:"remaining"`
+	// Message contains additional information.
+	Message string `json:"message,omitempty"`
+	// Time when the node got cordoned.
+	Started metav1.Time `json:"started"`
+}
+
+// UpgradeStatus is the progress of the upgrade of a contributed node
+type UpgradeStatus struct {
+	// Name of the cluster upgrade plan that scheduled the upgrade.
+	Plan string `json:"plan"`
+	// Kubelet version to upgrade the node to.
+	KubeletVersion string `json:"kubeletversion"`
+	// Containerd version to upgrade the node to.
+	ContainerdVersion string `json:"containerdversion,omitempty"`
+	// This can be 'Scheduled', 'Upgrading', 'Upgraded', or 'Failure'.
+	// +kubebuilder:validation:Enum=Scheduled;Upgrading;Upgraded;Failure
+	State string `json:"state"`
+	// Message contains additional information.
+	Message string `json:"message,omitempty"`
+	// Time when the upgrade started.
+	Started *metav1.Time `json:"started,omitempty"`
+	// Time when the upgrade finished.
+	Finished *metav1.Time `json:"finished,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -288,6 +702,750 @@ type NodeContributionList struct {
 
 // +genclient
 // +genclient:nonNamespaced
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Progress",type=integer,JSONPath=".status.progress"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Operation describes a long-running operation, such as a bulk teardown or a migration, carried
+// out item by item by the worker registered for its type
+type Operation struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the operation resource spec
+	Spec OperationSpec `json:"spec"`
+	// Status is the operation resource status
+	Status OperationStatus `json:"status,omitempty"`
+}
+
+// OperationSpec is the spec for an Operation resource
+type OperationSpec struct {
+	// Type of the operation, such as 'TenantTeardown'. It picks the worker that carries the operation out.
+	Type string `json:"type"`
+	// Object that initiated the operation.
+	Initiator corev1.ObjectReference `json:"initiator"`
+	// Parameters of the operation, the worker interprets them.
+	Parameters map[string]string `json:"parameters,omitempty"`
+	// Number of times a failed item is retried before it counts as a partial failure.
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries int `json:"maxretries"`
+	// Setting cancel stops the operation once the item in progress is done.
+	Cancel bool `json:"cancel,omitempty"`
+}
+
+// OperationStatus is the status for an Operation resource
+type OperationStatus struct {
+	// This can be 'Pending', 'Running', 'Succeeded', 'Partially Failed', 'Failed', or 'Cancelled'.
+	State string `json:"state"`
+	// Message contains additional information.
+	Message string `json:"message"`
+	// Items to process, listed by the worker when the operation starts.
+	Items []string `json:"items,omitempty"`
+	// Number of items processed, whether they succeeded or failed.
+	Processed int `json:"processed"`
+	// Percentage of the items processed.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Progress int `json:"progress"`
+	// Failures of the items, an item stays here with its attempts until it succeeds or runs out of retries.
+	Failures []OperationFailure `json:"failures,omitempty"`
+	// Time when the operation started.
+	StartTime *metav1.Time `json:"starttime,omitempty"`
+	// Time when the operation reached a final state.
+	CompletionTime *metav1.Time `json:"completiontime,omitempty"`
+}
+
+// OperationFailure describes the failure of an item
+type OperationFailure struct {
+	// Item that failed.
+	Item string `json:"item"`
+	// Error of the last attempt.
+	Error string `json:"error"`
+	// Number of attempts so far.
+	Attempts int `json:"attempts"`
+}
+
+// OperationReference points the initiating object to the operation it started
+type OperationReference struct {
+	// Name of the operation.
+	Name string `json:"name"`
+	// Type of the operation.
+	Type string `json:"type"`
+	// State of the operation, mirrored from its status.
+	State string `json:"state,omitempty"`
+	// Percentage of the items the operation processed, mirrored from its status.
+	Progress int `json:"progress,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// OperationList is a list of Operation resources
+type OperationList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// OperationList is a list of Operation resources. This element contains
+	// Operation resources.
+	Items []Operation `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Step",type=string,JSONPath=".status.step"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// InstallCheck describes an end-to-end probe of the installation. A synthetic tenant goes from
+// the request to the teardown, and the outcome of each step is reported in the status.
+type InstallCheck struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the install check resource spec
+	Spec InstallCheckSpec `json:"spec"`
+	// Status is the install check resource status
+	Status InstallCheckStatus `json:"status,omitempty"`
+}
+
+// InstallCheckSpec is the spec for an InstallCheck resource
+type InstallCheckSpec struct {
+	// Email of the contact of the synthetic tenant, the tenant owner probed by the RBAC step.
+	// +kubebuilder:validation:Format=email
+	Email string `json:"email"`
+	// Invitation token for the email, required when the cluster only takes tenant requests by invitation.
+	Invitation string `json:"invitation,omitempty"`
+	// Image of the pod that probes the connectivity from the tenant namespace, 'busybox:1.34' if empty.
+	ProbeImage string `json:"probeimage,omitempty"`
+	// Number of seconds a step can take before it fails, 300 if not set.
+	// +kubebuilder:validation:Minimum=0
+	StepTimeout int `json:"steptimeout,omitempty"`
+	// Leaves the synthetic tenant in place after a failure, so that it can be examined.
+	Retain bool `json:"retain,omitempty"`
+}
+
+// InstallCheckStatus is the status for an InstallCheck resource
+type InstallCheckStatus struct {
+	// This can be 'Running', 'Passed', or 'Failed'.
+	// +kubebuilder:validation:Enum=Running;Passed;Failed
+	State string `json:"state"`
+	// Message contains additional information.
+	Message string `json:"message"`
+	// Name of the step in progress.
+	Step string `json:"step,omitempty"`
+	// Name of the synthetic tenant.
+	Tenant string `json:"tenant,omitempty"`
+	// Steps reports the outcome of each step of the check.
+	Steps []InstallCheckStep `json:"steps,omitempty"`
+	// Time when the check started.
+	StartTime *metav1.Time `json:"starttime,omitempty"`
+	// Time when the check reached a final state.
+	CompletionTime *metav1.Time `json:"completiontime,omitempty"`
+}
+
+// InstallCheckStep is the outcome of a step of the check
+type InstallCheckStep struct {
+	// Name of the step, 'TenantRequest', 'Approval', 'Establishment', 'Connectivity', 'RBAC', or 'Teardown'.
+	Name string `json:"name"`
+	// This can be 'Pending', 'Running', 'Passed', 'Failed', or 'Skipped'.
+	// +kubebuilder:validation:Enum=Pending;Running;Passed;Failed;Skipped
+	State string `json:"state"`
+	// Message contains additional information.
+	Message string `json:"message,omitempty"`
+	// Diagnostics gathered when the step failed, such as the status of the objects involved.
+	Diagnostics []string `json:"diagnostics,omitempty"`
+	// Time when the step started.
+	StartTime *metav1.Time `json:"starttime,omitempty"`
+	// Time when the step finished.
+	CompletionTime *metav1.Time `json:"completiontime,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// InstallCheckList is a list of InstallCheck resources
+type InstallCheckList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// InstallCheckList is a list of InstallCheck resources. This element contains
+	// InstallCheck resources.
+	Items []InstallCheck `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:printcolumn:name="Tenant",type=string,JSONPath=".spec.tenant"
+// +kubebuilder:printcolumn:name="Action",type=string,JSONPath=".spec.action"
+// +kubebuilder:printcolumn:name="Actor",type=string,JSONPath=".spec.actor"
+// +kubebuilder:printcolumn:name="Object",type=string,JSONPath=".spec.object.name"
+// +kubebuilder:printcolumn:name="Time",type=date,JSONPath=".spec.time"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantAudit is a record in the audit trail of a tenant, such as the approval of its request or a
+// change of its quota. The records outlive the events and the tenant itself, and they carry the
+// label of the tenant so that its trail can be listed.
+type TenantAudit struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the tenant audit resource spec
+	Spec TenantAuditSpec `json:"spec"`
+}
+
+// TenantAuditSpec is the spec for a TenantAudit resource
+type TenantAuditSpec struct {
+	// Name of the tenant concerned by the action.
+	Tenant string `json:"tenant"`
+	// This can be 'RequestCreated', 'RequestApproved', 'RequestDeclined', 'TenantEnabled',
+	// 'TenantDisabled', 'QuotaChanged', or 'RoleBound'.
+	// +kubebuilder:validation:Enum=RequestCreated;RequestApproved;RequestDeclined;TenantEnabled;TenantDisabled;QuotaChanged;RoleBound
+	Action string `json:"action"`
+	// Who took the action, empty if the cluster does not know.
+	// +optional
+	Actor string `json:"actor,omitempty"`
+	// Object the action was taken on.
+	Object TenantAuditObject `json:"object"`
+	// Message contains additional information.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Time when the action was taken.
+	Time metav1.Time `json:"time"`
+}
+
+// TenantAuditObject refers to the object an audited action was taken on
+type TenantAuditObject struct {
+	Kind string `json:"kind"`
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantAuditList is a list of TenantAudit resources
+type TenantAuditList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// TenantAuditList is a list of TenantAudit resources. This element contains
+	// TenantAudit resources.
+	Items []TenantAudit `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:printcolumn:name="Source",type=string,JSONPath=".spec.source"
+// +kubebuilder:printcolumn:name="Target",type=string,JSONPath=".spec.target"
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantMigration moves the subnamespaces of a tenant to another one, which renames a tenant when
+// the target is a new tenant and merges two tenants otherwise. Namespaces cannot be renamed, so
+// the namespaces keep their names and only their tenant changes.
+type TenantMigration struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the tenant migration resource spec
+	Spec TenantMigrationSpec `json:"spec"`
+	// Status is the tenant migration resource status
+	Status TenantMigrationStatus `json:"status,omitempty"`
+}
+
+// TenantMigrationSpec is the spec for a TenantMigration resource
+type TenantMigrationSpec struct {
+	// Name of the tenant whose subnamespaces move.
+	Source string `json:"source"`
+	// Name of the tenant the subnamespaces move to, it must exist and be enabled.
+	Target string `json:"target"`
+	// Disable the source tenant once its subnamespaces moved.
+	// +optional
+	DisableSource bool `json:"disablesource,omitempty"`
+}
+
+// TenantMigrationStatus is the status for a TenantMigration resource
+type TenantMigrationStatus struct {
+	// This can be 'In Progress', 'Completed', 'Rolled Back', or 'Failure'.
+	State string `json:"state,omitempty"`
+	// Message contains additional information.
+	Message string `json:"message,omitempty"`
+	// Changes journals what the migration changed, in order, so that a failed migration
+	// undoes them in the reverse order.
+	Changes []MigrationChange `json:"changes,omitempty"`
+	// Time when the migration completed or was rolled back.
+	CompletionTime *metav1.Time `json:"completiontime,omitempty"`
+}
+
+// MigrationChange holds what an object looked like before the migration changed it
+type MigrationChange struct {
+	// This can be 'Namespace', 'RoleBinding', 'TenantResourceQuota', or 'Tenant'.
+	Kind string `json:"kind"`
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// The labels of the object before the migration.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// The owner references of the namespace before the migration.
+	// +optional
+	OwnerReferences []metav1.OwnerReference `json:"ownerreferences,omitempty"`
+	// The subjects of the role binding before the migration.
+	// +optional
+	Subjects []rbacv1.Subject `json:"subjects,omitempty"`
+	// The claim the migration added to the tenant resource quota of the target.
+	// +optional
+	Claim string `json:"claim,omitempty"`
+	// Undone tells whether the change was rolled back.
+	// +optional
+	Undone bool `json:"undone,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantMigrationList is a list of TenantMigration resources
+type TenantMigrationList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// TenantMigrationList is a list of TenantMigration resources. This element contains
+	// TenantMigration resources.
+	Items []TenantMigration `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:printcolumn:name="Network Policy",type=string,JSONPath=".spec.networkpolicy"
+// +kubebuilder:printcolumn:name="Expiry",type=string,JSONPath=".spec.expiry"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantProfile bundles the defaults of the tenants of a kind, such as research, commercial, or classroom.
+// A tenant request refers to a profile by name and the tenant is created with its values.
+type TenantProfile struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the tenant profile resource spec
+	Spec TenantProfileSpec `json:"spec"`
+}
+
+// TenantProfileSpec is the spec for a TenantProfile resource
+type TenantProfileSpec struct {
+	// Default allocation of the resource types, the allocation given in the request
+	// takes precedence.
+	// +optional
+	ResourceAllocation map[corev1.ResourceName]resource.Quantity `json:"resourceallocation,omitempty"`
+	// Network policy tier of the tenant namespaces. This can be 'baseline' for the
+	// default-deny policies, or 'permissive'.
+	// +kubebuilder:validation:Enum=baseline;permissive
+	// +optional
+	NetworkPolicy string `json:"networkpolicy,omitempty"`
+	// Node pools the workloads of the tenant are allowed on, all nodes if empty.
+	// +optional
+	NodePools []string `json:"nodepools,omitempty"`
+	// How long the default allocation lasts once the tenant is created, it does not
+	// expire if not set.
+	// +optional
+	Expiry *metav1.Duration `json:"expiry,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantProfileList is a list of TenantProfile resources
+type TenantProfileList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// TenantProfileList is a list of TenantProfile resources. This element contains
+	// TenantProfile resources.
+	Items []TenantProfile `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:printcolumn:name="Description",type=string,JSONPath=".spec.description"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodePool groups the nodes by their labels, such as the nodes of an institution, of a hardware class,
+// or behind the same connectivity. A tenant that lists node pools in its spec runs on their nodes alone.
+type NodePool struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the node pool resource spec
+	Spec NodePoolSpec `json:"spec"`
+}
+
+// NodePoolSpec is the spec for a NodePool resource
+type NodePoolSpec struct {
+	// Description of the nodes in the pool.
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Labels of the nodes in the pool. A pool without any requirement holds no node.
+	Selector metav1.LabelSelector `json:"selector"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodePoolList is a list of NodePool resources
+type NodePoolList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// NodePoolList is a list of NodePool resources. This element contains
+	// NodePool resources.
+	Items []NodePool `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:printcolumn:name="Node Pool",type=string,JSONPath=".spec.nodepool"
+// +kubebuilder:printcolumn:name="Nodes",type=integer,JSONPath=".status.nodes"
+// +kubebuilder:printcolumn:name="Applied",type=integer,JSONPath=".status.applied"
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeConfiguration sets the kubelet of the nodes in a pool up, such as the eviction thresholds and
+// the image garbage collection of the edge nodes with small disks. The node agent applies it on each node.
+type NodeConfiguration struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the node configuration resource spec
+	Spec NodeConfigurationSpec `json:"spec"`
+	// Status is the node configuration resource status
+	Status NodeConfigurationStatus `json:"status,omitempty"`
+}
+
+// NodeConfigurationSpec is the spec for a NodeConfiguration resource
+type NodeConfigurationSpec struct {
+	// Node pool whose nodes take the configuration. A node in the pools of several
+	// configurations takes the oldest one.
+	NodePool string `json:"nodepool"`
+	// Eviction signals with the threshold at which the kubelet evicts pods right away,
+	// such as nodefs.available: 10% or imagefs.available: 500Mi.
+	// +optional
+	EvictionHard map[string]string `json:"evictionhard,omitempty"`
+	// Eviction signals with the threshold at which the kubelet evicts pods once the
+	// grace period of the signal is over.
+	// +optional
+	EvictionSoft map[string]string `json:"evictionsoft,omitempty"`
+	// Grace periods of the soft eviction signals, such as nodefs.available: 1m30s.
+	// +optional
+	EvictionSoftGracePeriod map[string]string `json:"evictionsoftgraceperiod,omitempty"`
+	// When the kubelet removes the unused images, the defaults of the kubelet are kept if not set.
+	// +optional
+	ImageGC *ImageGC `json:"imagegc,omitempty"`
+}
+
+// ImageGC describes when the kubelet removes the unused images
+type ImageGC struct {
+	// Disk usage, in percent, above which the kubelet removes the unused images.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	HighThresholdPercent *int32 `json:"highthresholdpercent,omitempty"`
+	// Disk usage, in percent, the removal brings the images down to.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	LowThresholdPercent *int32 `json:"lowthresholdpercent,omitempty"`
+	// Minimum age of an unused image before it is removed.
+	// +optional
+	MinimumAge *metav1.Duration `json:"minimumage,omitempty"`
+}
+
+// NodeConfigurationStatus is the status for a NodeConfiguration resource
+type NodeConfigurationStatus struct {
+	// This can be 'Applying', 'Applied', or 'Failure'.
+	State string `json:"state"`
+	// Message contains additional information.
+	Message string `json:"message"`
+	// Number of nodes that take the configuration.
+	Nodes int `json:"nodes"`
+	// Number of nodes whose kubelet runs with the configuration.
+	Applied int `json:"applied"`
+	// Nodes at disk pressure, their contributors are notified as the nodes enter it.
+	DiskPressure []string `json:"diskpressure,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeConfigurationList is a list of NodeConfiguration resources
+type NodeConfigurationList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// NodeConfigurationList is a list of NodeConfiguration resources. This element contains
+	// NodeConfiguration resources.
+	Items []NodeConfiguration `json:"items"`
+}
+
+// +genclient
+// +kubebuilder:printcolumn:name="Role",type=string,JSONPath=".spec.role"
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Token",type=string,JSONPath=".status.tokensecret"
+// +kubebuilder:printcolumn:name="Rotated",type=date,JSONPath=".status.lastrotation"
+// +kubebuilder:printcolumn:name="Expires",type=date,JSONPath=".status.tokenexpiry"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantServiceAccount describes a robot identity of a tenant, such as the one of a CI pipeline.
+// It lives in the core namespace of the tenant.
+type TenantServiceAccount struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the tenant service account resource spec
+	Spec TenantServiceAccountSpec `json:"spec"`
+	// Status is the tenant service account resource status
+	Status TenantServiceAccountStatus `json:"status,omitempty"`
+}
+
+// TenantServiceAccountSpec is the spec for a TenantServiceAccount resource
+type TenantServiceAccountSpec struct {
+	// Role of the robot in the tenant.
+	// +kubebuilder:validation:Enum=admin;collaborator
+	Role string `json:"role"`
+	// Subsidiary namespaces of the tenant where the robot holds the role, in addition to the core namespace.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Number of hours after which the token is replaced, the token is replaced before it expires anyway.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	RotationPeriod int `json:"rotationperiod,omitempty"`
+	// Audiences the token is scoped to, the API server if empty.
+	// +optional
+	Audiences []string `json:"audiences,omitempty"`
+}
+
+// TenantServiceAccountStatus is the status for a TenantServiceAccount resource
+type TenantServiceAccountStatus struct {
+	// This can be 'Ready', 'Disabled', or 'Failure'.
+	// +kubebuilder:validation:Enum=Ready;Disabled;Failure
+	State string `json:"state"`
+	// Message contains additional information.
+	Message string `json:"message"`
+	// Name of the service account of the robot.
+	ServiceAccount string `json:"serviceaccount,omitempty"`
+	// Name of the secret that holds the current token.
+	TokenSecret string `json:"tokensecret,omitempty"`
+	// Number of times the token was replaced.
+	Rotations int `json:"rotations,omitempty"`
+	// Time when the current token was issued.
+	LastRotation *metav1.Time `json:"lastrotation,omitempty"`
+	// Time when the current token expires, its lifetime follows the tier of the tenant.
+	TokenExpiry *metav1.Time `json:"tokenexpiry,omitempty"`
+	// Namespaces where the robot is bound to its role.
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantServiceAccountList is a list of TenantServiceAccount resources
+type TenantServiceAccountList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// TenantServiceAccountList is a list of TenantServiceAccount resources. This element contains
+	// TenantServiceAccount resources.
+	Items []TenantServiceAccount `json:"items"`
+}
+
+// +genclient
+// +kubebuilder:printcolumn:name="Role",type=string,JSONPath=".status.role"
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Synced",type=date,JSONPath=".status.lastsync"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantSecretStore syncs the secrets a tenant keeps in Vault into the namespaces of the tenant, so
+// that the credentials never have to be pasted into plain secrets. It lives in the core namespace of
+// the tenant, and the secrets are read with a Vault role that only reaches the path of the tenant.
+type TenantSecretStore struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the tenant secret store resource spec
+	Spec TenantSecretStoreSpec `json:"spec"`
+	// Status is the tenant secret store resource status
+	Status TenantSecretStoreStatus `json:"status,omitempty"`
+}
+
+// TenantSecretStoreSpec is the spec for a TenantSecretStore resource
+type TenantSecretStoreSpec struct {
+	// Secrets to sync from the path of the tenant in Vault.
+	Secrets []TenantSecretSync `json:"secrets"`
+	// Number of minutes after which the secrets are read again from Vault.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	RefreshInterval int `json:"refreshinterval,omitempty"`
+}
+
+// TenantSecretSync designates a secret of the tenant in Vault and the secret it is synced into
+type TenantSecretSync struct {
+	// Name of the secret created in the namespaces.
+	Name string `json:"name"`
+	// Path of the secret in Vault, relative to the path of the tenant.
+	Path string `json:"path"`
+	// Keys of the Vault secret to sync, all of them if empty.
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+	// Subsidiary namespaces of the tenant where the secret is synced, in addition to the core namespace.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// TenantSecretStoreStatus is the status for a TenantSecretStore resource
+type TenantSecretStoreStatus struct {
+	// This can be 'Ready', 'Disabled', or 'Failure'.
+	// +kubebuilder:validation:Enum=Ready;Disabled;Failure
+	State string `json:"state"`
+	// Message contains additional information.
+	Message string `json:"message"`
+	// Vault role the secrets of the tenant are read with.
+	Role string `json:"role,omitempty"`
+	// Secrets synced into the namespaces.
+	Secrets []TenantSecretSyncStatus `json:"secrets,omitempty"`
+	// Time when the secrets were last read from Vault.
+	LastSync *metav1.Time `json:"lastsync,omitempty"`
+}
+
+// TenantSecretSyncStatus tells which version of a Vault secret is synced and where
+type TenantSecretSyncStatus struct {
+	// Name of the secret created in the namespaces.
+	Name string `json:"name"`
+	// Version of the Vault secret synced.
+	Version int `json:"version,omitempty"`
+	// Namespaces where the secret is synced.
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantSecretStoreList is a list of TenantSecretStore resources
+type TenantSecretStoreList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// TenantSecretStoreList is a list of TenantSecretStore resources. This element contains
+	// TenantSecretStore resources.
+	Items []TenantSecretStore `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:printcolumn:name="Pods",type=integer,JSONPath=".status.pods"
+// +kubebuilder:printcolumn:name="CPU Requests",type=string,JSONPath=".status.requests.cpu"
+// +kubebuilder:printcolumn:name="CPU Usage",type=string,JSONPath=".status.usage.cpu"
+// +kubebuilder:printcolumn:name="Share",type=integer,JSONPath=".status.fairshare.share"
+// +kubebuilder:printcolumn:name="Sampled",type=date,JSONPath=".status.lastsampled"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantUsage accounts for the resources a tenant consumes across its namespaces. It has the
+// name of the tenant and its status is sampled periodically by the accounting controller.
+type TenantUsage struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the tenant usage resource spec
+	Spec TenantUsageSpec `json:"spec"`
+	// Status is the tenant usage resource status
+	Status TenantUsageStatus `json:"status,omitempty"`
+}
+
+// TenantUsageSpec is the spec for a TenantUsage resource
+type TenantUsageSpec struct {
+	// Name of the tenant accounted for.
+	Tenant string `json:"tenant"`
+}
+
+// TenantUsageStatus is the status for a TenantUsage resource
+type TenantUsageStatus struct {
+	// Number of running pods of the tenant at the last sample.
+	Pods int `json:"pods"`
+	// Sum of the resources requested by the running pods of the tenant.
+	// +optional
+	Requests map[corev1.ResourceName]resource.Quantity `json:"requests,omitempty"`
+	// Resources the running pods of the tenant actually use, as reported by the metrics server.
+	// This is empty if the cluster has no metrics server.
+	// +optional
+	Usage map[corev1.ResourceName]resource.Quantity `json:"usage,omitempty"`
+	// Time the nodes spent running the pods of the tenant since the accounting began.
+	NodeTime NodeTime `json:"nodetime"`
+	// Recent consumption of the tenant relative to its entitlement, it is not assessed for the
+	// tenants without a CPU quota.
+	// +optional
+	FairShare *FairShare `json:"fairshare,omitempty"`
+	// Time of the last sample.
+	// +optional
+	LastSampled *metav1.Time `json:"lastsampled,omitempty"`
+}
+
+// FairShare compares the recent CPU consumption of a tenant with its quota. The heavy tenants
+// yield to the light ones on the contended nodes as their priority is lowered.
+type FairShare struct {
+	// CPU consumption averaged over the samples, the older samples weigh less.
+	Consumption resource.Quantity `json:"consumption"`
+	// CPU quota of the tenant.
+	Entitlement resource.Quantity `json:"entitlement"`
+	// Consumption as a percentage of the entitlement.
+	Share int64 `json:"share"`
+	// Weight taken off the priority of the tenant, zero as long as the consumption is within the
+	// entitlement.
+	Penalty int32 `json:"penalty"`
+}
+
+// NodeTime splits the node time of a tenant between the nodes contributed to the cluster and the
+// nodes of the cluster itself
+type NodeTime struct {
+	// Node time on the nodes contributed through node contributions.
+	Contributed metav1.Duration `json:"contributed"`
+	// Node time on the other nodes of the cluster.
+	Shared metav1.Duration `json:"shared"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantUsageList is a list of TenantUsage resources
+type TenantUsageList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// TenantUsageList is a list of TenantUsage resources. This element contains
+	// TenantUsage resources.
+	Items []TenantUsage `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // TenantResourceQuota describes a tenant resouce quota resource
@@ -308,6 +1466,13 @@ type TenantResourceQuotaSpec struct {
 	Claim map[string]ResourceTuning `json:"claim"`
 	// To decrease the overall quota.
 	Drop map[string]ResourceTuning `json:"drop"`
+	// Percentage of the quota at which the tenant is alerted, it is not alerted if nil.
+	// +optional
+	SoftLimit *int `json:"softLimit,omitempty"`
+	// HardBlock rejects the pods that would take the tenant beyond its quota, even if the
+	// resource quotas of its namespaces drifted from it.
+	// +optional
+	HardBlock bool `json:"hardBlock,omitempty"`
 }
 
 // ResourceTuning indicates resources to add or remove, and how long they will remain.
@@ -326,6 +1491,13 @@ type TenantResourceQuotaStatus struct {
 	State string `json:"state"`
 	// Message contains additional information.
 	Message string `json:"message"`
+	// Resources requested by the pods of the tenant that count against the quota.
+	// +optional
+	Used map[corev1.ResourceName]resource.Quantity `json:"used,omitempty"`
+	// Conditions of the tenant resource quota, SoftLimitExceeded is true while the usage of a
+	// resource is beyond the soft limit.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -23,7 +23,9 @@ package v1alpha
 
 import (
 	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	resource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -44,39 +46,23 @@ func (in *Address) DeepCopy() *Address {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Contact) DeepCopyInto(out *Contact) {
-	*out = *in
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Contact.
-func (in *Contact) DeepCopy() *Contact {
-	if in == nil {
-		return nil
-	}
-	out := new(Contact)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Limitations) DeepCopyInto(out *Limitations) {
+func (in *Clone) DeepCopyInto(out *Clone) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Limitations.
-func (in *Limitations) DeepCopy() *Limitations {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Clone.
+func (in *Clone) DeepCopy() *Clone {
 	if in == nil {
 		return nil
 	}
-	out := new(Limitations)
+	out := new(Clone)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeContribution) DeepCopyInto(out *NodeContribution) {
+func (in *ClusterUpgradePlan) DeepCopyInto(out *ClusterUpgradePlan) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -85,18 +71,18 @@ func (in *NodeContribution) DeepCopyInto(out *NodeContribution) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeContribution.
-func (in *NodeContribution) DeepCopy() *NodeContribution {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradePlan.
+func (in *ClusterUpgradePlan) DeepCopy() *ClusterUpgradePlan {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeContribution)
+	out := new(ClusterUpgradePlan)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NodeContribution) DeepCopyObject() runtime.Object {
+func (in *ClusterUpgradePlan) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -104,13 +90,13 @@ func (in *NodeContribution) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeContributionList) DeepCopyInto(out *NodeContributionList) {
+func (in *ClusterUpgradePlanList) DeepCopyInto(out *ClusterUpgradePlanList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]NodeContribution, len(*in))
+		*out = make([]ClusterUpgradePlan, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -118,18 +104,18 @@ func (in *NodeContributionList) DeepCopyInto(out *NodeContributionList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeContributionList.
-func (in *NodeContributionList) DeepCopy() *NodeContributionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradePlanList.
+func (in *ClusterUpgradePlanList) DeepCopy() *ClusterUpgradePlanList {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeContributionList)
+	out := new(ClusterUpgradePlanList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *NodeContributionList) DeepCopyObject() runtime.Object {
+func (in *ClusterUpgradePlanList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -137,232 +123,209 @@ func (in *NodeContributionList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeContributionSpec) DeepCopyInto(out *NodeContributionSpec) {
+func (in *ClusterUpgradePlanSpec) DeepCopyInto(out *ClusterUpgradePlanSpec) {
 	*out = *in
-	if in.Tenant != nil {
-		in, out := &in.Tenant, &out.Tenant
-		*out = new(string)
-		**out = **in
-	}
-	if in.Limitations != nil {
-		in, out := &in.Limitations, &out.Limitations
-		*out = make([]Limitations, len(*in))
-		copy(*out, *in)
-	}
+	in.Policy.DeepCopyInto(&out.Policy)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeContributionSpec.
-func (in *NodeContributionSpec) DeepCopy() *NodeContributionSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradePlanSpec.
+func (in *ClusterUpgradePlanSpec) DeepCopy() *ClusterUpgradePlanSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeContributionSpec)
+	out := new(ClusterUpgradePlanSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeContributionStatus) DeepCopyInto(out *NodeContributionStatus) {
+func (in *ClusterUpgradePlanStatus) DeepCopyInto(out *ClusterUpgradePlanStatus) {
 	*out = *in
-	if in.Message != nil {
-		in, out := &in.Message, &out.Message
-		*out = make([]string, len(*in))
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NodeUpgradeProgress, len(*in))
 		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeContributionStatus.
-func (in *NodeContributionStatus) DeepCopy() *NodeContributionStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradePlanStatus.
+func (in *ClusterUpgradePlanStatus) DeepCopy() *ClusterUpgradePlanStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeContributionStatus)
+	out := new(ClusterUpgradePlanStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceTuning) DeepCopyInto(out *ResourceTuning) {
+func (in *Contact) DeepCopyInto(out *Contact) {
 	*out = *in
-	if in.ResourceList != nil {
-		in, out := &in.ResourceList, &out.ResourceList
-		*out = make(map[v1.ResourceName]resource.Quantity, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val.DeepCopy()
-		}
-	}
-	if in.Expiry != nil {
-		in, out := &in.Expiry, &out.Expiry
-		*out = (*in).DeepCopy()
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceTuning.
-func (in *ResourceTuning) DeepCopy() *ResourceTuning {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Contact.
+func (in *Contact) DeepCopy() *Contact {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceTuning)
+	out := new(Contact)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SubNamespace) DeepCopyInto(out *SubNamespace) {
+func (in *ContainerLimits) DeepCopyInto(out *ContainerLimits) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.DefaultRequest != nil {
+		in, out := &in.DefaultRequest, &out.DefaultRequest
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubNamespace.
-func (in *SubNamespace) DeepCopy() *SubNamespace {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerLimits.
+func (in *ContainerLimits) DeepCopy() *ContainerLimits {
 	if in == nil {
 		return nil
 	}
-	out := new(SubNamespace)
+	out := new(ContainerLimits)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SubNamespace) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SubNamespaceList) DeepCopyInto(out *SubNamespaceList) {
+func (in *Egress) DeepCopyInto(out *Egress) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]SubNamespace, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.CIDRs != nil {
+		in, out := &in.CIDRs, &out.CIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FQDNs != nil {
+		in, out := &in.FQDNs, &out.FQDNs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubNamespaceList.
-func (in *SubNamespaceList) DeepCopy() *SubNamespaceList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Egress.
+func (in *Egress) DeepCopy() *Egress {
 	if in == nil {
 		return nil
 	}
-	out := new(SubNamespaceList)
+	out := new(Egress)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SubNamespaceList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SubNamespaceSpec) DeepCopyInto(out *SubNamespaceSpec) {
+func (in *FairShare) DeepCopyInto(out *FairShare) {
 	*out = *in
-	if in.Workspace != nil {
-		in, out := &in.Workspace, &out.Workspace
-		*out = new(Workspace)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Subtenant != nil {
-		in, out := &in.Subtenant, &out.Subtenant
-		*out = new(Subtenant)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Expiry != nil {
-		in, out := &in.Expiry, &out.Expiry
-		*out = (*in).DeepCopy()
-	}
+	out.Consumption = in.Consumption.DeepCopy()
+	out.Entitlement = in.Entitlement.DeepCopy()
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubNamespaceSpec.
-func (in *SubNamespaceSpec) DeepCopy() *SubNamespaceSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FairShare.
+func (in *FairShare) DeepCopy() *FairShare {
 	if in == nil {
 		return nil
 	}
-	out := new(SubNamespaceSpec)
+	out := new(FairShare)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SubNamespaceStatus) DeepCopyInto(out *SubNamespaceStatus) {
+func (in *GroupBinding) DeepCopyInto(out *GroupBinding) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubNamespaceStatus.
-func (in *SubNamespaceStatus) DeepCopy() *SubNamespaceStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GroupBinding.
+func (in *GroupBinding) DeepCopy() *GroupBinding {
 	if in == nil {
 		return nil
 	}
-	out := new(SubNamespaceStatus)
+	out := new(GroupBinding)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Subtenant) DeepCopyInto(out *Subtenant) {
+func (in *ImageGC) DeepCopyInto(out *ImageGC) {
 	*out = *in
-	if in.ResourceAllocation != nil {
-		in, out := &in.ResourceAllocation, &out.ResourceAllocation
-		*out = make(map[v1.ResourceName]resource.Quantity, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val.DeepCopy()
-		}
+	if in.HighThresholdPercent != nil {
+		in, out := &in.HighThresholdPercent, &out.HighThresholdPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LowThresholdPercent != nil {
+		in, out := &in.LowThresholdPercent, &out.LowThresholdPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinimumAge != nil {
+		in, out := &in.MinimumAge, &out.MinimumAge
+		*out = new(metav1.Duration)
+		**out = **in
 	}
-	out.Owner = in.Owner
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subtenant.
-func (in *Subtenant) DeepCopy() *Subtenant {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageGC.
+func (in *ImageGC) DeepCopy() *ImageGC {
 	if in == nil {
 		return nil
 	}
-	out := new(Subtenant)
+	out := new(ImageGC)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Tenant) DeepCopyInto(out *Tenant) {
+func (in *InstallCheck) DeepCopyInto(out *InstallCheck) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tenant.
-func (in *Tenant) DeepCopy() *Tenant {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallCheck.
+func (in *InstallCheck) DeepCopy() *InstallCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(Tenant)
+	out := new(InstallCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Tenant) DeepCopyObject() runtime.Object {
+func (in *InstallCheck) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -370,13 +333,13 @@ func (in *Tenant) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TenantList) DeepCopyInto(out *TenantList) {
+func (in *InstallCheckList) DeepCopyInto(out *InstallCheckList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Tenant, len(*in))
+		*out = make([]InstallCheck, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -384,18 +347,18 @@ func (in *TenantList) DeepCopyInto(out *TenantList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantList.
-func (in *TenantList) DeepCopy() *TenantList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallCheckList.
+func (in *InstallCheckList) DeepCopy() *InstallCheckList {
 	if in == nil {
 		return nil
 	}
-	out := new(TenantList)
+	out := new(InstallCheckList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TenantList) DeepCopyObject() runtime.Object {
+func (in *InstallCheckList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -403,154 +366,2117 @@ func (in *TenantList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TenantResourceQuota) DeepCopyInto(out *TenantResourceQuota) {
+func (in *InstallCheckSpec) DeepCopyInto(out *InstallCheckSpec) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantResourceQuota.
-func (in *TenantResourceQuota) DeepCopy() *TenantResourceQuota {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallCheckSpec.
+func (in *InstallCheckSpec) DeepCopy() *InstallCheckSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(TenantResourceQuota)
+	out := new(InstallCheckSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TenantResourceQuota) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TenantResourceQuotaList) DeepCopyInto(out *TenantResourceQuotaList) {
+func (in *InstallCheckStatus) DeepCopyInto(out *InstallCheckStatus) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]TenantResourceQuota, len(*in))
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]InstallCheckStep, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantResourceQuotaList.
-func (in *TenantResourceQuotaList) DeepCopy() *TenantResourceQuotaList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallCheckStatus.
+func (in *InstallCheckStatus) DeepCopy() *InstallCheckStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(TenantResourceQuotaList)
+	out := new(InstallCheckStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *TenantResourceQuotaList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TenantResourceQuotaSpec) DeepCopyInto(out *TenantResourceQuotaSpec) {
+func (in *InstallCheckStep) DeepCopyInto(out *InstallCheckStep) {
 	*out = *in
-	if in.Claim != nil {
-		in, out := &in.Claim, &out.Claim
-		*out = make(map[string]ResourceTuning, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
+	if in.Diagnostics != nil {
+		in, out := &in.Diagnostics, &out.Diagnostics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.Drop != nil {
-		in, out := &in.Drop, &out.Drop
-		*out = make(map[string]ResourceTuning, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantResourceQuotaSpec.
-func (in *TenantResourceQuotaSpec) DeepCopy() *TenantResourceQuotaSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallCheckStep.
+func (in *InstallCheckStep) DeepCopy() *InstallCheckStep {
 	if in == nil {
 		return nil
 	}
-	out := new(TenantResourceQuotaSpec)
+	out := new(InstallCheckStep)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TenantResourceQuotaStatus) DeepCopyInto(out *TenantResourceQuotaStatus) {
+func (in *JoinStatus) DeepCopyInto(out *JoinStatus) {
 	*out = *in
+	out.Secret = in.Secret
+	in.Expires.DeepCopyInto(&out.Expires)
+	if in.Used != nil {
+		in, out := &in.Used, &out.Used
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantResourceQuotaStatus.
-func (in *TenantResourceQuotaStatus) DeepCopy() *TenantResourceQuotaStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JoinStatus.
+func (in *JoinStatus) DeepCopy() *JoinStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(TenantResourceQuotaStatus)
+	out := new(JoinStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TenantSpec) DeepCopyInto(out *TenantSpec) {
+func (in *Limitations) DeepCopyInto(out *Limitations) {
 	*out = *in
-	out.Address = in.Address
-	out.Contact = in.Contact
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSpec.
-func (in *TenantSpec) DeepCopy() *TenantSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Limitations.
+func (in *Limitations) DeepCopy() *Limitations {
 	if in == nil {
 		return nil
 	}
-	out := new(TenantSpec)
+	out := new(Limitations)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TenantStatus) DeepCopyInto(out *TenantStatus) {
+func (in *MaintenanceStatus) DeepCopyInto(out *MaintenanceStatus) {
 	*out = *in
+	in.Started.DeepCopyInto(&out.Started)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantStatus.
-func (in *TenantStatus) DeepCopy() *TenantStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceStatus.
+func (in *MaintenanceStatus) DeepCopy() *MaintenanceStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(TenantStatus)
+	out := new(MaintenanceStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Workspace) DeepCopyInto(out *Workspace) {
+func (in *MigrationChange) DeepCopyInto(out *MigrationChange) {
 	*out = *in
-	if in.ResourceAllocation != nil {
-		in, out := &in.ResourceAllocation, &out.ResourceAllocation
-		*out = make(map[v1.ResourceName]resource.Quantity, len(*in))
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val.DeepCopy()
+			(*out)[key] = val
+		}
+	}
+	if in.OwnerReferences != nil {
+		in, out := &in.OwnerReferences, &out.OwnerReferences
+		*out = make([]metav1.OwnerReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Subjects != nil {
+		in, out := &in.Subjects, &out.Subjects
+		*out = make([]rbacv1.Subject, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationChange.
+func (in *MigrationChange) DeepCopy() *MigrationChange {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespacePolicySync) DeepCopyInto(out *NamespacePolicySync) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespacePolicySync.
+func (in *NamespacePolicySync) DeepCopy() *NamespacePolicySync {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespacePolicySync)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Ingress) DeepCopyInto(out *Ingress) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ingress.
+func (in *Ingress) DeepCopy() *Ingress {
+	if in == nil {
+		return nil
+	}
+	out := new(Ingress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Mesh) DeepCopyInto(out *Mesh) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Mesh.
+func (in *Mesh) DeepCopy() *Mesh {
+	if in == nil {
+		return nil
+	}
+	out := new(Mesh)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeConfiguration) DeepCopyInto(out *NodeConfiguration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeConfiguration.
+func (in *NodeConfiguration) DeepCopy() *NodeConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeConfiguration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeConfigurationList) DeepCopyInto(out *NodeConfigurationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeConfigurationList.
+func (in *NodeConfigurationList) DeepCopy() *NodeConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeConfigurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeConfigurationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeConfigurationSpec) DeepCopyInto(out *NodeConfigurationSpec) {
+	*out = *in
+	if in.EvictionHard != nil {
+		in, out := &in.EvictionHard, &out.EvictionHard
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.EvictionSoft != nil {
+		in, out := &in.EvictionSoft, &out.EvictionSoft
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.EvictionSoftGracePeriod != nil {
+		in, out := &in.EvictionSoftGracePeriod, &out.EvictionSoftGracePeriod
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ImageGC != nil {
+		in, out := &in.ImageGC, &out.ImageGC
+		*out = new(ImageGC)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeConfigurationSpec.
+func (in *NodeConfigurationSpec) DeepCopy() *NodeConfigurationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeConfigurationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeConfigurationStatus) DeepCopyInto(out *NodeConfigurationStatus) {
+	*out = *in
+	if in.DiskPressure != nil {
+		in, out := &in.DiskPressure, &out.DiskPressure
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeConfigurationStatus.
+func (in *NodeConfigurationStatus) DeepCopy() *NodeConfigurationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeConfigurationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeContribution) DeepCopyInto(out *NodeContribution) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeContribution.
+func (in *NodeContribution) DeepCopy() *NodeContribution {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeContribution)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeContribution) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeContributionList) DeepCopyInto(out *NodeContributionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeContribution, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeContributionList.
+func (in *NodeContributionList) DeepCopy() *NodeContributionList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeContributionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodeContributionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeContributionSpec) DeepCopyInto(out *NodeContributionSpec) {
+	*out = *in
+	if in.Tenant != nil {
+		in, out := &in.Tenant, &out.Tenant
+		*out = new(string)
+		**out = **in
+	}
+	if in.Limitations != nil {
+		in, out := &in.Limitations, &out.Limitations
+		*out = make([]Limitations, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeContributionSpec.
+func (in *NodeContributionSpec) DeepCopy() *NodeContributionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeContributionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeContributionStatus) DeepCopyInto(out *NodeContributionStatus) {
+	*out = *in
+	if in.Message != nil {
+		in, out := &in.Message, &out.Message
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(MaintenanceStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(UpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NotReadySince != nil {
+		in, out := &in.NotReadySince, &out.NotReadySince
+		*out = (*in).DeepCopy()
+	}
+	if in.Preflight != nil {
+		in, out := &in.Preflight, &out.Preflight
+		*out = new(PreflightStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Join != nil {
+		in, out := &in.Join, &out.Join
+		*out = new(JoinStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeContributionStatus.
+func (in *NodeContributionStatus) DeepCopy() *NodeContributionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeContributionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePool) DeepCopyInto(out *NodePool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePool.
+func (in *NodePool) DeepCopy() *NodePool {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodePool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolList) DeepCopyInto(out *NodePoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolList.
+func (in *NodePoolList) DeepCopy() *NodePoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NodePoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolSpec) DeepCopyInto(out *NodePoolSpec) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolSpec.
+func (in *NodePoolSpec) DeepCopy() *NodePoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeTime) DeepCopyInto(out *NodeTime) {
+	*out = *in
+	out.Contributed = in.Contributed
+	out.Shared = in.Shared
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeTime.
+func (in *NodeTime) DeepCopy() *NodeTime {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeTime)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeUpgradeProgress) DeepCopyInto(out *NodeUpgradeProgress) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeUpgradeProgress.
+func (in *NodeUpgradeProgress) DeepCopy() *NodeUpgradeProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeUpgradeProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Onboarding) DeepCopyInto(out *Onboarding) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Onboarding.
+func (in *Onboarding) DeepCopy() *Onboarding {
+	if in == nil {
+		return nil
+	}
+	out := new(Onboarding)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Operation) DeepCopyInto(out *Operation) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Operation.
+func (in *Operation) DeepCopy() *Operation {
+	if in == nil {
+		return nil
+	}
+	out := new(Operation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Operation) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationFailure) DeepCopyInto(out *OperationFailure) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationFailure.
+func (in *OperationFailure) DeepCopy() *OperationFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationList) DeepCopyInto(out *OperationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Operation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationList.
+func (in *OperationList) DeepCopy() *OperationList {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OperationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationReference) DeepCopyInto(out *OperationReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationReference.
+func (in *OperationReference) DeepCopy() *OperationReference {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationSpec) DeepCopyInto(out *OperationSpec) {
+	*out = *in
+	out.Initiator = in.Initiator
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationSpec.
+func (in *OperationSpec) DeepCopy() *OperationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationStatus) DeepCopyInto(out *OperationStatus) {
+	*out = *in
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Failures != nil {
+		in, out := &in.Failures, &out.Failures
+		*out = make([]OperationFailure, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationStatus.
+func (in *OperationStatus) DeepCopy() *OperationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreflightCheck) DeepCopyInto(out *PreflightCheck) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreflightCheck.
+func (in *PreflightCheck) DeepCopy() *PreflightCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(PreflightCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreflightStatus) DeepCopyInto(out *PreflightStatus) {
+	*out = *in
+	if in.Checks != nil {
+		in, out := &in.Checks, &out.Checks
+		*out = make([]PreflightCheck, len(*in))
+		copy(*out, *in)
+	}
+	in.Checked.DeepCopyInto(&out.Checked)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreflightStatus.
+func (in *PreflightStatus) DeepCopy() *PreflightStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PreflightStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Priority) DeepCopyInto(out *Priority) {
+	*out = *in
+	if in.PreemptionPolicy != nil {
+		in, out := &in.PreemptionPolicy, &out.PreemptionPolicy
+		*out = new(v1.PreemptionPolicy)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Priority.
+func (in *Priority) DeepCopy() *Priority {
+	if in == nil {
+		return nil
+	}
+	out := new(Priority)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaLending) DeepCopyInto(out *QuotaLending) {
+	*out = *in
+	if in.Lend != nil {
+		in, out := &in.Lend, &out.Lend
+		*out = make(map[v1.ResourceName]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Borrow != nil {
+		in, out := &in.Borrow, &out.Borrow
+		*out = make(map[v1.ResourceName]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaLending.
+func (in *QuotaLending) DeepCopy() *QuotaLending {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaLending)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaLoan) DeepCopyInto(out *QuotaLoan) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(map[v1.ResourceName]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	in.Since.DeepCopyInto(&out.Since)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaLoan.
+func (in *QuotaLoan) DeepCopy() *QuotaLoan {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaLoan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceTuning) DeepCopyInto(out *ResourceTuning) {
+	*out = *in
+	if in.ResourceList != nil {
+		in, out := &in.ResourceList, &out.ResourceList
+		*out = make(map[v1.ResourceName]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Expiry != nil {
+		in, out := &in.Expiry, &out.Expiry
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceTuning.
+func (in *ResourceTuning) DeepCopy() *ResourceTuning {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceTuning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutPolicy) DeepCopyInto(out *RolloutPolicy) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutPolicy.
+func (in *RolloutPolicy) DeepCopy() *RolloutPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SigningKey) DeepCopyInto(out *SigningKey) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SigningKey.
+func (in *SigningKey) DeepCopy() *SigningKey {
+	if in == nil {
+		return nil
+	}
+	out := new(SigningKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubNamespace) DeepCopyInto(out *SubNamespace) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubNamespace.
+func (in *SubNamespace) DeepCopy() *SubNamespace {
+	if in == nil {
+		return nil
+	}
+	out := new(SubNamespace)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubNamespace) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubNamespaceList) DeepCopyInto(out *SubNamespaceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SubNamespace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubNamespaceList.
+func (in *SubNamespaceList) DeepCopy() *SubNamespaceList {
+	if in == nil {
+		return nil
+	}
+	out := new(SubNamespaceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SubNamespaceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubNamespaceReference) DeepCopyInto(out *SubNamespaceReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubNamespaceReference.
+func (in *SubNamespaceReference) DeepCopy() *SubNamespaceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SubNamespaceReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubNamespaceSpec) DeepCopyInto(out *SubNamespaceSpec) {
+	*out = *in
+	if in.Workspace != nil {
+		in, out := &in.Workspace, &out.Workspace
+		*out = new(Workspace)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Subtenant != nil {
+		in, out := &in.Subtenant, &out.Subtenant
+		*out = new(Subtenant)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Expiry != nil {
+		in, out := &in.Expiry, &out.Expiry
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubNamespaceSpec.
+func (in *SubNamespaceSpec) DeepCopy() *SubNamespaceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SubNamespaceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubNamespaceStatus) DeepCopyInto(out *SubNamespaceStatus) {
+	*out = *in
+	if in.Borrowed != nil {
+		in, out := &in.Borrowed, &out.Borrowed
+		*out = make([]QuotaLoan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Lent != nil {
+		in, out := &in.Lent, &out.Lent
+		*out = make([]QuotaLoan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Parent != nil {
+		in, out := &in.Parent, &out.Parent
+		*out = new(SubNamespaceReference)
+		**out = **in
+	}
+	if in.Children != nil {
+		in, out := &in.Children, &out.Children
+		*out = make([]SubNamespaceReference, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubNamespaceStatus.
+func (in *SubNamespaceStatus) DeepCopy() *SubNamespaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SubNamespaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Subtenant) DeepCopyInto(out *Subtenant) {
+	*out = *in
+	if in.ResourceAllocation != nil {
+		in, out := &in.ResourceAllocation, &out.ResourceAllocation
+		*out = make(map[v1.ResourceName]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	out.Owner = in.Owner
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Subtenant.
+func (in *Subtenant) DeepCopy() *Subtenant {
+	if in == nil {
+		return nil
+	}
+	out := new(Subtenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Tenant) DeepCopyInto(out *Tenant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Tenant.
+func (in *Tenant) DeepCopy() *Tenant {
+	if in == nil {
+		return nil
+	}
+	out := new(Tenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Tenant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantAudit) DeepCopyInto(out *TenantAudit) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantAudit.
+func (in *TenantAudit) DeepCopy() *TenantAudit {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantAudit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantAudit) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantAuditList) DeepCopyInto(out *TenantAuditList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TenantAudit, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantAuditList.
+func (in *TenantAuditList) DeepCopy() *TenantAuditList {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantAuditList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantAuditList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantAuditObject) DeepCopyInto(out *TenantAuditObject) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantAuditObject.
+func (in *TenantAuditObject) DeepCopy() *TenantAuditObject {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantAuditObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantAuditSpec) DeepCopyInto(out *TenantAuditSpec) {
+	*out = *in
+	out.Object = in.Object
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantAuditSpec.
+func (in *TenantAuditSpec) DeepCopy() *TenantAuditSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantAuditSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantList) DeepCopyInto(out *TenantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Tenant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantList.
+func (in *TenantList) DeepCopy() *TenantList {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantMigration) DeepCopyInto(out *TenantMigration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantMigration.
+func (in *TenantMigration) DeepCopy() *TenantMigration {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantMigration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantMigration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantMigrationList) DeepCopyInto(out *TenantMigrationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TenantMigration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantMigrationList.
+func (in *TenantMigrationList) DeepCopy() *TenantMigrationList {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantMigrationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantMigrationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantMigrationSpec) DeepCopyInto(out *TenantMigrationSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantMigrationSpec.
+func (in *TenantMigrationSpec) DeepCopy() *TenantMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantMigrationStatus) DeepCopyInto(out *TenantMigrationStatus) {
+	*out = *in
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]MigrationChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantMigrationStatus.
+func (in *TenantMigrationStatus) DeepCopy() *TenantMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantProfile) DeepCopyInto(out *TenantProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantProfile.
+func (in *TenantProfile) DeepCopy() *TenantProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantProfileList) DeepCopyInto(out *TenantProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TenantProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantProfileList.
+func (in *TenantProfileList) DeepCopy() *TenantProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantProfileSpec) DeepCopyInto(out *TenantProfileSpec) {
+	*out = *in
+	if in.ResourceAllocation != nil {
+		in, out := &in.ResourceAllocation, &out.ResourceAllocation
+		*out = make(map[v1.ResourceName]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Expiry != nil {
+		in, out := &in.Expiry, &out.Expiry
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantProfileSpec.
+func (in *TenantProfileSpec) DeepCopy() *TenantProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantResourceQuota) DeepCopyInto(out *TenantResourceQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantResourceQuota.
+func (in *TenantResourceQuota) DeepCopy() *TenantResourceQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantResourceQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantResourceQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantResourceQuotaList) DeepCopyInto(out *TenantResourceQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TenantResourceQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantResourceQuotaList.
+func (in *TenantResourceQuotaList) DeepCopy() *TenantResourceQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantResourceQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantResourceQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantResourceQuotaSpec) DeepCopyInto(out *TenantResourceQuotaSpec) {
+	*out = *in
+	if in.Claim != nil {
+		in, out := &in.Claim, &out.Claim
+		*out = make(map[string]ResourceTuning, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.Drop != nil {
+		in, out := &in.Drop, &out.Drop
+		*out = make(map[string]ResourceTuning, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.SoftLimit != nil {
+		in, out := &in.SoftLimit, &out.SoftLimit
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantResourceQuotaSpec.
+func (in *TenantResourceQuotaSpec) DeepCopy() *TenantResourceQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantResourceQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantResourceQuotaStatus) DeepCopyInto(out *TenantResourceQuotaStatus) {
+	*out = *in
+	if in.Used != nil {
+		in, out := &in.Used, &out.Used
+		*out = make(map[v1.ResourceName]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantResourceQuotaStatus.
+func (in *TenantResourceQuotaStatus) DeepCopy() *TenantResourceQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantResourceQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantSecretStore) DeepCopyInto(out *TenantSecretStore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSecretStore.
+func (in *TenantSecretStore) DeepCopy() *TenantSecretStore {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantSecretStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantSecretStore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantSecretStoreList) DeepCopyInto(out *TenantSecretStoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TenantSecretStore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSecretStoreList.
+func (in *TenantSecretStoreList) DeepCopy() *TenantSecretStoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantSecretStoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantSecretStoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantSecretStoreSpec) DeepCopyInto(out *TenantSecretStoreSpec) {
+	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]TenantSecretSync, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSecretStoreSpec.
+func (in *TenantSecretStoreSpec) DeepCopy() *TenantSecretStoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantSecretStoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantSecretStoreStatus) DeepCopyInto(out *TenantSecretStoreStatus) {
+	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]TenantSecretSyncStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSync != nil {
+		in, out := &in.LastSync, &out.LastSync
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSecretStoreStatus.
+func (in *TenantSecretStoreStatus) DeepCopy() *TenantSecretStoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantSecretStoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantSecretSync) DeepCopyInto(out *TenantSecretSync) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSecretSync.
+func (in *TenantSecretSync) DeepCopy() *TenantSecretSync {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantSecretSync)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantSecretSyncStatus) DeepCopyInto(out *TenantSecretSyncStatus) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSecretSyncStatus.
+func (in *TenantSecretSyncStatus) DeepCopy() *TenantSecretSyncStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantSecretSyncStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantServiceAccount) DeepCopyInto(out *TenantServiceAccount) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantServiceAccount.
+func (in *TenantServiceAccount) DeepCopy() *TenantServiceAccount {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantServiceAccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantServiceAccount) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantServiceAccountList) DeepCopyInto(out *TenantServiceAccountList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TenantServiceAccount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantServiceAccountList.
+func (in *TenantServiceAccountList) DeepCopy() *TenantServiceAccountList {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantServiceAccountList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantServiceAccountList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantServiceAccountSpec) DeepCopyInto(out *TenantServiceAccountSpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Audiences != nil {
+		in, out := &in.Audiences, &out.Audiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantServiceAccountSpec.
+func (in *TenantServiceAccountSpec) DeepCopy() *TenantServiceAccountSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantServiceAccountSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantServiceAccountStatus) DeepCopyInto(out *TenantServiceAccountStatus) {
+	*out = *in
+	if in.LastRotation != nil {
+		in, out := &in.LastRotation, &out.LastRotation
+		*out = (*in).DeepCopy()
+	}
+	if in.TokenExpiry != nil {
+		in, out := &in.TokenExpiry, &out.TokenExpiry
+		*out = (*in).DeepCopy()
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantServiceAccountStatus.
+func (in *TenantServiceAccountStatus) DeepCopy() *TenantServiceAccountStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantServiceAccountStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantSpec) DeepCopyInto(out *TenantSpec) {
+	*out = *in
+	out.Address = in.Address
+	out.Contact = in.Contact
+	if in.Owners != nil {
+		in, out := &in.Owners, &out.Owners
+		*out = make([]Contact, len(*in))
+		copy(*out, *in)
+	}
+	if in.Admins != nil {
+		in, out := &in.Admins, &out.Admins
+		*out = make([]Contact, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContainerLimits != nil {
+		in, out := &in.ContainerLimits, &out.ContainerLimits
+		*out = new(ContainerLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Priority != nil {
+		in, out := &in.Priority, &out.Priority
+		*out = new(Priority)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GroupBindings != nil {
+		in, out := &in.GroupBindings, &out.GroupBindings
+		*out = make([]GroupBinding, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(Ingress)
+		**out = **in
+	}
+	if in.Mesh != nil {
+		in, out := &in.Mesh, &out.Mesh
+		*out = new(Mesh)
+		**out = **in
+	}
+	if in.Egress != nil {
+		in, out := &in.Egress, &out.Egress
+		*out = new(Egress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSecurityExemptions != nil {
+		in, out := &in.PodSecurityExemptions, &out.PodSecurityExemptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SigningKeys != nil {
+		in, out := &in.SigningKeys, &out.SigningKeys
+		*out = make([]SigningKey, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantSpec.
+func (in *TenantSpec) DeepCopy() *TenantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantStatus) DeepCopyInto(out *TenantStatus) {
+	*out = *in
+	if in.LastFailure != nil {
+		in, out := &in.LastFailure, &out.LastFailure
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NetworkPolicies != nil {
+		in, out := &in.NetworkPolicies, &out.NetworkPolicies
+		*out = make([]NamespacePolicySync, len(*in))
+		copy(*out, *in)
+	}
+	if in.Operation != nil {
+		in, out := &in.Operation, &out.Operation
+		*out = new(OperationReference)
+		**out = **in
+	}
+	if in.Onboarding != nil {
+		in, out := &in.Onboarding, &out.Onboarding
+		*out = new(Onboarding)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantStatus.
+func (in *TenantStatus) DeepCopy() *TenantStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantUsage) DeepCopyInto(out *TenantUsage) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantUsage.
+func (in *TenantUsage) DeepCopy() *TenantUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantUsage) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantUsageList) DeepCopyInto(out *TenantUsageList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TenantUsage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantUsageList.
+func (in *TenantUsageList) DeepCopy() *TenantUsageList {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantUsageList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TenantUsageList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantUsageSpec) DeepCopyInto(out *TenantUsageSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantUsageSpec.
+func (in *TenantUsageSpec) DeepCopy() *TenantUsageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantUsageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantUsageStatus) DeepCopyInto(out *TenantUsageStatus) {
+	*out = *in
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make(map[v1.ResourceName]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = make(map[v1.ResourceName]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	out.NodeTime = in.NodeTime
+	if in.FairShare != nil {
+		in, out := &in.FairShare, &out.FairShare
+		*out = new(FairShare)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastSampled != nil {
+		in, out := &in.LastSampled, &out.LastSampled
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantUsageStatus.
+func (in *TenantUsageStatus) DeepCopy() *TenantUsageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantUsageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeStatus) DeepCopyInto(out *UpgradeStatus) {
+	*out = *in
+	if in.Started != nil {
+		in, out := &in.Started, &out.Started
+		*out = (*in).DeepCopy()
+	}
+	if in.Finished != nil {
+		in, out := &in.Finished, &out.Finished
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStatus.
+func (in *UpgradeStatus) DeepCopy() *UpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Workspace) DeepCopyInto(out *Workspace) {
+	*out = *in
+	if in.ResourceAllocation != nil {
+		in, out := &in.ResourceAllocation, &out.ResourceAllocation
+		*out = make(map[v1.ResourceName]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
 		}
 	}
 	if in.Inheritance != nil {
@@ -565,6 +2491,16 @@ func (in *Workspace) DeepCopyInto(out *Workspace) {
 		*out = new(Contact)
 		**out = **in
 	}
+	if in.Clone != nil {
+		in, out := &in.Clone, &out.Clone
+		*out = new(Clone)
+		**out = **in
+	}
+	if in.Lending != nil {
+		in, out := &in.Lending, &out.Lending
+		*out = new(QuotaLending)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
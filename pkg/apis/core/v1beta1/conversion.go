@@ -0,0 +1,300 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+)
+
+// v1alpha is the hub of the conversions: each v1beta1 type converts to and from its v1alpha
+// counterpart, and the conversions are lossless both ways.
+
+// ConvertTo converts the tenant to v1alpha
+func (t *Tenant) ConvertTo(alpha *corev1alpha.Tenant) {
+	alpha.ObjectMeta = *t.ObjectMeta.DeepCopy()
+	spec := t.Spec.DeepCopy()
+	alpha.Spec = corev1alpha.TenantSpec{
+		FullName:              spec.FullName,
+		ShortName:             spec.ShortName,
+		URL:                   spec.URL,
+		Address:               corev1alpha.Address(spec.Address),
+		Contact:               corev1alpha.Contact(spec.Contact),
+		ClusterNetworkPolicy:  spec.ClusterNetworkPolicy,
+		Profile:               spec.Profile,
+		NodePools:             spec.NodePools,
+		PodSecurityExemptions: spec.PodSecurityExemptions,
+		RequireSignedRequests: spec.RequireSignedRequests,
+		Enabled:               spec.Enabled,
+	}
+	if spec.ContainerLimits != nil {
+		containerLimits := corev1alpha.ContainerLimits(*spec.ContainerLimits)
+		alpha.Spec.ContainerLimits = &containerLimits
+	}
+	if spec.Priority != nil {
+		priority := corev1alpha.Priority(*spec.Priority)
+		alpha.Spec.Priority = &priority
+	}
+	if spec.Ingress != nil {
+		ingress := corev1alpha.Ingress(*spec.Ingress)
+		alpha.Spec.Ingress = &ingress
+	}
+	if spec.Mesh != nil {
+		mesh := corev1alpha.Mesh(*spec.Mesh)
+		alpha.Spec.Mesh = &mesh
+	}
+	if spec.Egress != nil {
+		egress := corev1alpha.Egress(*spec.Egress)
+		alpha.Spec.Egress = &egress
+	}
+	for _, groupBinding := range spec.GroupBindings {
+		alpha.Spec.GroupBindings = append(alpha.Spec.GroupBindings, corev1alpha.GroupBinding(groupBinding))
+	}
+	for _, owner := range spec.Owners {
+		alpha.Spec.Owners = append(alpha.Spec.Owners, corev1alpha.Contact(owner))
+	}
+	for _, admin := range spec.Admins {
+		alpha.Spec.Admins = append(alpha.Spec.Admins, corev1alpha.Contact(admin))
+	}
+	for _, signingKey := range spec.SigningKeys {
+		alpha.Spec.SigningKeys = append(alpha.Spec.SigningKeys, corev1alpha.SigningKey(signingKey))
+	}
+
+	status := t.Status.DeepCopy()
+	alpha.Status = corev1alpha.TenantStatus{
+		State:              status.State,
+		Message:            status.Message,
+		ObservedGeneration: status.ObservedGeneration,
+		Retries:            status.Retries,
+		LastFailure:        status.LastFailure,
+		Conditions:         status.Conditions,
+	}
+	for _, networkPolicy := range status.NetworkPolicies {
+		alpha.Status.NetworkPolicies = append(alpha.Status.NetworkPolicies, corev1alpha.NamespacePolicySync(networkPolicy))
+	}
+	if status.Operation != nil {
+		operation := corev1alpha.OperationReference(*status.Operation)
+		alpha.Status.Operation = &operation
+	}
+	if status.Onboarding != nil {
+		onboarding := corev1alpha.Onboarding(*status.Onboarding)
+		alpha.Status.Onboarding = &onboarding
+	}
+}
+
+// ConvertFrom converts the v1alpha tenant to the tenant
+func (t *Tenant) ConvertFrom(alpha *corev1alpha.Tenant) {
+	t.ObjectMeta = *alpha.ObjectMeta.DeepCopy()
+	spec := alpha.Spec.DeepCopy()
+	t.Spec = TenantSpec{
+		FullName:              spec.FullName,
+		ShortName:             spec.ShortName,
+		URL:                   spec.URL,
+		Address:               Address(spec.Address),
+		Contact:               Contact(spec.Contact),
+		ClusterNetworkPolicy:  spec.ClusterNetworkPolicy,
+		Profile:               spec.Profile,
+		NodePools:             spec.NodePools,
+		PodSecurityExemptions: spec.PodSecurityExemptions,
+		RequireSignedRequests: spec.RequireSignedRequests,
+		Enabled:               spec.Enabled,
+	}
+	if spec.ContainerLimits != nil {
+		containerLimits := ContainerLimits(*spec.ContainerLimits)
+		t.Spec.ContainerLimits = &containerLimits
+	}
+	if spec.Priority != nil {
+		priority := Priority(*spec.Priority)
+		t.Spec.Priority = &priority
+	}
+	if spec.Ingress != nil {
+		ingress := Ingress(*spec.Ingress)
+		t.Spec.Ingress = &ingress
+	}
+	if spec.Mesh != nil {
+		mesh := Mesh(*spec.Mesh)
+		t.Spec.Mesh = &mesh
+	}
+	if spec.Egress != nil {
+		egress := Egress(*spec.Egress)
+		t.Spec.Egress = &egress
+	}
+	for _, groupBinding := range spec.GroupBindings {
+		t.Spec.GroupBindings = append(t.Spec.GroupBindings, GroupBinding(groupBinding))
+	}
+	for _, owner := range spec.Owners {
+		t.Spec.Owners = append(t.Spec.Owners, Contact(owner))
+	}
+	for _, admin := range spec.Admins {
+		t.Spec.Admins = append(t.Spec.Admins, Contact(admin))
+	}
+	for _, signingKey := range spec.SigningKeys {
+		t.Spec.SigningKeys = append(t.Spec.SigningKeys, SigningKey(signingKey))
+	}
+
+	status := alpha.Status.DeepCopy()
+	t.Status = TenantStatus{
+		State:              status.State,
+		Message:            status.Message,
+		ObservedGeneration: status.ObservedGeneration,
+		Retries:            status.Retries,
+		LastFailure:        status.LastFailure,
+		Conditions:         status.Conditions,
+	}
+	for _, networkPolicy := range status.NetworkPolicies {
+		t.Status.NetworkPolicies = append(t.Status.NetworkPolicies, NamespacePolicySync(networkPolicy))
+	}
+	if status.Operation != nil {
+		operation := OperationReference(*status.Operation)
+		t.Status.Operation = &operation
+	}
+	if status.Onboarding != nil {
+		onboarding := Onboarding(*status.Onboarding)
+		t.Status.Onboarding = &onboarding
+	}
+}
+
+// ConvertTo converts the subnamespace to v1alpha
+func (s *SubNamespace) ConvertTo(alpha *corev1alpha.SubNamespace) {
+	alpha.ObjectMeta = *s.ObjectMeta.DeepCopy()
+	spec := s.Spec.DeepCopy()
+	alpha.Spec = corev1alpha.SubNamespaceSpec{Expiry: spec.Expiry}
+	if workspace := spec.Workspace; workspace != nil {
+		alpha.Spec.Workspace = &corev1alpha.Workspace{
+			ResourceAllocation: workspace.ResourceAllocation,
+			Inheritance:        workspace.Inheritance,
+			Scope:              workspace.Scope,
+			Sync:               workspace.Sync,
+		}
+		if workspace.Owner != nil {
+			owner := corev1alpha.Contact(*workspace.Owner)
+			alpha.Spec.Workspace.Owner = &owner
+		}
+		if workspace.Clone != nil {
+			clone := corev1alpha.Clone(*workspace.Clone)
+			alpha.Spec.Workspace.Clone = &clone
+		}
+		if workspace.Lending != nil {
+			lending := corev1alpha.QuotaLending(*workspace.Lending)
+			alpha.Spec.Workspace.Lending = &lending
+		}
+	}
+	if subtenant := spec.Subtenant; subtenant != nil {
+		alpha.Spec.Subtenant = &corev1alpha.Subtenant{ResourceAllocation: subtenant.ResourceAllocation, Owner: corev1alpha.Contact(subtenant.Owner)}
+	}
+	status := s.Status.DeepCopy()
+	alpha.Status = corev1alpha.SubNamespaceStatus{State: status.State, Message: status.Message, Cloned: status.Cloned}
+	for _, loan := range status.Borrowed {
+		alpha.Status.Borrowed = append(alpha.Status.Borrowed, corev1alpha.QuotaLoan(loan))
+	}
+	for _, loan := range status.Lent {
+		alpha.Status.Lent = append(alpha.Status.Lent, corev1alpha.QuotaLoan(loan))
+	}
+	alpha.Status.Child, alpha.Status.Depth = status.Child, status.Depth
+	if status.Parent != nil {
+		parent := corev1alpha.SubNamespaceReference(*status.Parent)
+		alpha.Status.Parent = &parent
+	}
+	for _, child := range status.Children {
+		alpha.Status.Children = append(alpha.Status.Children, corev1alpha.SubNamespaceReference(child))
+	}
+}
+
+// ConvertFrom converts the v1alpha subnamespace to the subnamespace
+func (s *SubNamespace) ConvertFrom(alpha *corev1alpha.SubNamespace) {
+	s.ObjectMeta = *alpha.ObjectMeta.DeepCopy()
+	spec := alpha.Spec.DeepCopy()
+	s.Spec = SubNamespaceSpec{Expiry: spec.Expiry}
+	if workspace := spec.Workspace; workspace != nil {
+		s.Spec.Workspace = &Workspace{
+			ResourceAllocation: workspace.ResourceAllocation,
+			Inheritance:        workspace.Inheritance,
+			Scope:              workspace.Scope,
+			Sync:               workspace.Sync,
+		}
+		if workspace.Owner != nil {
+			owner := Contact(*workspace.Owner)
+			s.Spec.Workspace.Owner = &owner
+		}
+		if workspace.Clone != nil {
+			clone := Clone(*workspace.Clone)
+			s.Spec.Workspace.Clone = &clone
+		}
+		if workspace.Lending != nil {
+			lending := QuotaLending(*workspace.Lending)
+			s.Spec.Workspace.Lending = &lending
+		}
+	}
+	if subtenant := spec.Subtenant; subtenant != nil {
+		s.Spec.Subtenant = &Subtenant{ResourceAllocation: subtenant.ResourceAllocation, Owner: Contact(subtenant.Owner)}
+	}
+	status := alpha.Status.DeepCopy()
+	s.Status = SubNamespaceStatus{State: status.State, Message: status.Message, Cloned: status.Cloned}
+	for _, loan := range status.Borrowed {
+		s.Status.Borrowed = append(s.Status.Borrowed, QuotaLoan(loan))
+	}
+	for _, loan := range status.Lent {
+		s.Status.Lent = append(s.Status.Lent, QuotaLoan(loan))
+	}
+	s.Status.Child, s.Status.Depth = status.Child, status.Depth
+	if status.Parent != nil {
+		parent := SubNamespaceReference(*status.Parent)
+		s.Status.Parent = &parent
+	}
+	for _, child := range status.Children {
+		s.Status.Children = append(s.Status.Children, SubNamespaceReference(child))
+	}
+}
+
+// ConvertTo converts the tenant resource quota to v1alpha
+func (t *TenantResourceQuota) ConvertTo(alpha *corev1alpha.TenantResourceQuota) {
+	alpha.ObjectMeta = *t.ObjectMeta.DeepCopy()
+	spec := t.Spec.DeepCopy()
+	alpha.Spec = corev1alpha.TenantResourceQuotaSpec{SoftLimit: spec.SoftLimit, HardBlock: spec.HardBlock}
+	if spec.Claims != nil {
+		alpha.Spec.Claim = map[string]corev1alpha.ResourceTuning{}
+		for name, claim := range spec.Claims {
+			alpha.Spec.Claim[name] = corev1alpha.ResourceTuning(claim)
+		}
+	}
+	if spec.Drops != nil {
+		alpha.Spec.Drop = map[string]corev1alpha.ResourceTuning{}
+		for name, drop := range spec.Drops {
+			alpha.Spec.Drop[name] = corev1alpha.ResourceTuning(drop)
+		}
+	}
+	alpha.Status = corev1alpha.TenantResourceQuotaStatus(*t.Status.DeepCopy())
+}
+
+// ConvertFrom converts the v1alpha tenant resource quota to the tenant resource quota
+func (t *TenantResourceQuota) ConvertFrom(alpha *corev1alpha.TenantResourceQuota) {
+	t.ObjectMeta = *alpha.ObjectMeta.DeepCopy()
+	spec := alpha.Spec.DeepCopy()
+	t.Spec = TenantResourceQuotaSpec{SoftLimit: spec.SoftLimit, HardBlock: spec.HardBlock}
+	if spec.Claim != nil {
+		t.Spec.Claims = map[string]ResourceTuning{}
+		for name, claim := range spec.Claim {
+			t.Spec.Claims[name] = ResourceTuning(claim)
+		}
+	}
+	if spec.Drop != nil {
+		t.Spec.Drops = map[string]ResourceTuning{}
+		for name, drop := range spec.Drop {
+			t.Spec.Drops[name] = ResourceTuning(drop)
+		}
+	}
+	t.Status = TenantResourceQuotaStatus(*alpha.Status.DeepCopy())
+}
@@ -0,0 +1,419 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The v1beta1 types mirror the v1alpha ones field by field, with the field names in camel case as
+// in the Kubernetes APIs. v1alpha remains the storage version, the objects are converted between
+// the versions by the conversion webhook.
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:printcolumn:name="Official Name",type=string,JSONPath=".spec.fullName"
+// +kubebuilder:printcolumn:name="Short Name",type=string,JSONPath=".spec.shortName"
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=".spec.url"
+// +kubebuilder:printcolumn:name="City",type=string,JSONPath=".spec.address.city"
+// +kubebuilder:printcolumn:name="Country",type=string,JSONPath=".spec.address.country"
+// +kubebuilder:printcolumn:name="Enabled",type=boolean,JSONPath=".spec.enabled"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Tenant describes a tenant that consumes the cluster resources in an isolated environment
+type Tenant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the tenant resource spec
+	Spec TenantSpec `json:"spec"`
+	// Status is the tenant resource status
+	Status TenantStatus `json:"status,omitempty"`
+}
+
+// TenantSpec is the spec for a Tenant resource
+type TenantSpec struct {
+	// Full name of the tenant.
+	FullName string `json:"fullName"`
+	// Shortened name of the tenant.
+	ShortName string `json:"shortName"`
+	// Website of the tenant.
+	URL string `json:"url"`
+	// Open address of the tenant, this includes country, city, and street information.
+	Address Address `json:"address"`
+	// Contact information of the tenant.
+	Contact Contact `json:"contact"`
+	// Owners of the tenant besides the contact, who is always an owner.
+	Owners []Contact `json:"owners,omitempty"`
+	// Administrators of the tenant, they hold the admin role in the core namespace.
+	Admins []Contact `json:"admins,omitempty"`
+	// Whether cluster-level network policies will be applied to tenant namespaces
+	// for security purposes.
+	ClusterNetworkPolicy bool `json:"clusterNetworkPolicy"`
+	// Limits applied to each container in the tenant namespaces. The cluster
+	// defaults are used for the limits left empty.
+	ContainerLimits *ContainerLimits `json:"containerLimits,omitempty"`
+	// A dedicated priority class is generated and assigned by default to the pods
+	// in the tenant namespaces if set.
+	Priority *Priority `json:"priority,omitempty"`
+	// Groups of the identity provider whose members hold a tenant role.
+	GroupBindings []GroupBinding `json:"groupBindings,omitempty"`
+	// Profile the tenant was created with.
+	Profile string `json:"profile,omitempty"`
+	// Node pools the workloads of the tenant are allowed on, all nodes if empty.
+	NodePools []string `json:"nodePools,omitempty"`
+	// The tenant gets an ingress class of its own, with the certificates of its ingresses
+	// issued by ACME, if set.
+	Ingress *Ingress `json:"ingress,omitempty"`
+	// The sidecars of a service mesh are injected into the pods of the tenant if set.
+	Mesh *Mesh `json:"mesh,omitempty"`
+	// Destinations outside the cluster the pods of the tenant reach if set, all of them otherwise.
+	Egress *Egress `json:"egress,omitempty"`
+	// Checks of the pod security level of the tenant that its pods are exempted from.
+	// +kubebuilder:validation:items:Enum=hostNamespaces;privileged;capabilities;hostPathVolumes;procMount;sysctls;seccompProfile;allowPrivilegeEscalation;runAsNonRoot;volumeTypes
+	PodSecurityExemptions []string `json:"podSecurityExemptions,omitempty"`
+	// Public keys the tenant signs its requests with.
+	SigningKeys []SigningKey `json:"signingKeys,omitempty"`
+	// The requests of the tenant without a valid signature by one of its keys are refused if set.
+	RequireSignedRequests bool `json:"requireSignedRequests,omitempty"`
+	// If the tenant is active then this field is true.
+	Enabled bool `json:"enabled"`
+}
+
+// Ingress is the exposure of the services of a tenant through the ingress controllers of the edge nodes
+type Ingress struct {
+	// Controller implementing the ingress class of the tenant, 'k8s.io/ingress-nginx' by default.
+	Controller string `json:"controller,omitempty"`
+	// Cluster issuer of cert-manager that issues the certificates of the ingresses through ACME,
+	// 'letsencrypt' by default.
+	Issuer string `json:"issuer,omitempty"`
+}
+
+// Mesh is the membership of the tenant namespaces in a service mesh
+type Mesh struct {
+	// Service mesh injecting the sidecars, 'istio' or 'linkerd'.
+	// +kubebuilder:validation:Enum=istio;linkerd
+	Provider string `json:"provider"`
+}
+
+// Egress lists the destinations outside the cluster the pods of a tenant reach
+type Egress struct {
+	// Address ranges in CIDR notation, of either address family.
+	CIDRs []string `json:"cidrs,omitempty"`
+	// Domain names, a leading '*.' matches the subdomains. They need the DNS-aware policies of Cilium.
+	FQDNs []string `json:"fqdns,omitempty"`
+}
+
+// GroupBinding maps a group of the identity provider to a tenant role
+type GroupBinding struct {
+	// Name of the group as it appears in the groups claim of the OIDC tokens.
+	// +kubebuilder:validation:MinLength=1
+	Group string `json:"group"`
+	// Role of the group members in the tenant, 'owner', 'admin', or 'collaborator'.
+	// +kubebuilder:validation:Enum=owner;admin;collaborator
+	Role string `json:"role"`
+}
+
+// SigningKey is a public key of a tenant that verifies the signatures of its requests
+type SigningKey struct {
+	// Name of the key, which the signed requests refer to.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// PEM encoded Ed25519 or ECDSA public key.
+	PublicKey string `json:"publicKey"`
+}
+
+// Priority describes the priority class of a tenant
+type Priority struct {
+	// Tier of the tenant, 'community' or 'paying'.
+	// +kubebuilder:validation:Enum=community;paying
+	Tier string `json:"tier"`
+	// Position of the tenant within the band of its tier, from 0 to 999.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=999
+	Weight int32 `json:"weight"`
+	// Whether the pods of the tenant preempt the pods with lower priority, 'PreemptLowerPriority' by default.
+	// +kubebuilder:validation:Enum=PreemptLowerPriority;Never
+	PreemptionPolicy *corev1.PreemptionPolicy `json:"preemptionPolicy,omitempty"`
+}
+
+// ContainerLimits describes the limit range of the containers in tenant namespaces
+type ContainerLimits struct {
+	// Limits assigned to the containers that do not set any.
+	Default corev1.ResourceList `json:"default,omitempty"`
+	// Requests assigned to the containers that do not set any.
+	DefaultRequest corev1.ResourceList `json:"defaultRequest,omitempty"`
+	// Maximum limits a container can set.
+	Max corev1.ResourceList `json:"max,omitempty"`
+}
+
+// Address describes postal address of tenant
+type Address struct {
+	Street  string `json:"street"`
+	ZIP     string `json:"zip"`
+	City    string `json:"city"`
+	Region  string `json:"region"`
+	Country string `json:"country"`
+}
+
+// Contact contains handle, personal information, and role
+type Contact struct {
+	// Identifier of the person.
+	Handle    string `json:"handle"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Email     string `json:"email"`
+	Phone     string `json:"phone"`
+}
+
+// TenantStatus is the status for a Tenant resource
+type TenantStatus struct {
+	// The state can be 'Established' or 'Failure'.
+	State string `json:"state"`
+	// Additional description can be located here.
+	Message string `json:"message"`
+	// The generation of the spec that the controller processed last.
+	ObservedGeneration int64 `json:"observedGeneration"`
+	// Number of consecutive syncs that failed, it is reset after a successful sync.
+	Retries int `json:"retries"`
+	// Time of the last failed sync.
+	LastFailure *metav1.Time `json:"lastFailure,omitempty"`
+	// Conditions record the results of the checks run against an established tenant.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// NetworkPolicies reports the sync of the network policies to each subnamespace of the tenant.
+	NetworkPolicies []NamespacePolicySync `json:"networkPolicies,omitempty"`
+	// Operation is the long-running operation the tenant started last.
+	Operation *OperationReference `json:"operation,omitempty"`
+	// Onboarding is the progress of the establishment of the tenant through its ordered steps.
+	Onboarding *Onboarding `json:"onboarding,omitempty"`
+}
+
+// Onboarding is the step the establishment of a tenant reached
+type Onboarding struct {
+	// The step can be 'Namespace', 'Quota', 'RBAC', 'Network', 'Notify', or 'Done'.
+	Step       string `json:"step"`
+	Failed     bool   `json:"failed,omitempty"`
+	Message    string `json:"message,omitempty"`
+	Generation int64  `json:"generation"`
+}
+
+// NamespacePolicySync is the outcome of the network policy sync in a subnamespace
+type NamespacePolicySync struct {
+	Namespace string `json:"namespace"`
+	// This can be 'Synced', 'Failure', 'Rolled Back', or 'Skipped'.
+	State   string `json:"state"`
+	Message string `json:"message,omitempty"`
+}
+
+// OperationReference refers to an operation
+type OperationReference struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	State    string `json:"state,omitempty"`
+	Progress int    `json:"progress,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantList is a list of Tenant resources
+type TenantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []Tenant `json:"items"`
+}
+
+// +genclient
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SubNamespace describes a SubNamespace resource
+type SubNamespace struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the subsidiary namespace resource spec
+	Spec SubNamespaceSpec `json:"spec"`
+	// Status is the subsidiary namespace resource status
+	Status SubNamespaceStatus `json:"status,omitempty"`
+}
+
+// SubNamespaceSpec is the spec for a SubNamespace resource
+type SubNamespaceSpec struct {
+	// The subnamespace is a workspace, a child namespace within the namespace hierarchy.
+	// Either the workspace or the subtenant is set, it cannot be changed after creation.
+	Workspace *Workspace `json:"workspace,omitempty"`
+	// The subnamespace is a subtenant, where all information is hidden from its parent.
+	Subtenant *Subtenant `json:"subtenant,omitempty"`
+	// Expiration date of the subnamespace.
+	Expiry *metav1.Time `json:"expiry,omitempty"`
+}
+
+// Workspace contains the resources, the attributes to inherit, the scope, and the owner of a workspace
+type Workspace struct {
+	// Represents maximum resources to be used.
+	ResourceAllocation map[corev1.ResourceName]resource.Quantity `json:"resourceAllocation"`
+	// Which services are going to be available to the workspace.
+	Inheritance map[string]bool `json:"inheritance,omitempty"`
+	// Scope can be 'federated', or 'local'. It cannot be changed after creation.
+	Scope string `json:"scope"`
+	// If the workspace in sync.
+	Sync bool `json:"sync"`
+	// Owner of the workspace.
+	Owner *Contact `json:"owner,omitempty"`
+	// Clone copies the objects of another workspace into this one at creation.
+	Clone *Clone `json:"clone,omitempty"`
+	// Lending opts the workspace into lending the quota it leaves unused to its siblings, or
+	// borrowing theirs.
+	Lending *QuotaLending `json:"lending,omitempty"`
+}
+
+// Clone refers to the workspace whose Deployments, Services, and Config Maps are copied
+type Clone struct {
+	// Name of the source subnamespace, it must be in the same namespace.
+	Source string `json:"source"`
+	// If the secrets are copied as well.
+	Secrets bool `json:"secrets"`
+}
+
+// QuotaLending lets the sibling workspaces in a namespace borrow the quota they leave unused
+// from each other. A lender keeps half of its unused quota at least, and takes back what it
+// lent once it runs out of its own.
+type QuotaLending struct {
+	// Resources the workspace lends to its siblings at most.
+	Lend map[corev1.ResourceName]resource.Quantity `json:"lend,omitempty"`
+	// Resources the workspace borrows from its siblings at most.
+	Borrow map[corev1.ResourceName]resource.Quantity `json:"borrow,omitempty"`
+	// ReclaimPolicy can be 'Immediate', or 'Graceful'. Immediate takes the loans back at once,
+	// Graceful takes back what the borrowers leave unused and the rest as they free it.
+	// It is Graceful if empty.
+	ReclaimPolicy string `json:"reclaimPolicy,omitempty"`
+}
+
+// QuotaLoan is quota lent by a workspace to a sibling
+type QuotaLoan struct {
+	// Name of the sibling subnamespace, the lender or the borrower.
+	Sibling string `json:"sibling"`
+	// Resources lent.
+	Resources map[corev1.ResourceName]resource.Quantity `json:"resources"`
+	// Since when the loan is in its current amount.
+	Since metav1.Time `json:"since"`
+}
+
+// Subtenant resource represents a tenant under another tenant
+type Subtenant struct {
+	// Represents maximum resources to be used.
+	ResourceAllocation map[corev1.ResourceName]resource.Quantity `json:"resourceAllocation"`
+	// Owner of the Subtenant.
+	Owner Contact `json:"owner"`
+}
+
+// SubNamespaceStatus is the status for a SubNamespace resource
+type SubNamespaceStatus struct {
+	// Denotes the state of the SubNamespace. This can be 'Failure', or 'Established'.
+	State string `json:"state"`
+	// Message contains additional information.
+	Message string `json:"message"`
+	// If the objects of the clone source are copied into the workspace.
+	Cloned bool `json:"cloned,omitempty"`
+	// Quota the workspace borrowed from its siblings.
+	Borrowed []QuotaLoan `json:"borrowed,omitempty"`
+	// Quota the workspace lent to its siblings.
+	Lent []QuotaLoan `json:"lent,omitempty"`
+	// Namespace the subnamespace formed, the core namespace of the subtenant for a subtenant.
+	Child string `json:"child,omitempty"`
+	// Subnamespace that formed the namespace this one is in, none in the core namespace of a tenant.
+	Parent *SubNamespaceReference `json:"parent,omitempty"`
+	// Subnamespaces in the namespace of the workspace.
+	Children []SubNamespaceReference `json:"children,omitempty"`
+	// Levels below the core namespace of the tenant, 1 in the core namespace.
+	Depth int `json:"depth,omitempty"`
+}
+
+// SubNamespaceReference refers to a subnamespace
+type SubNamespaceReference struct {
+	// Namespace the subnamespace is in.
+	Namespace string `json:"namespace"`
+	// Name of the subnamespace.
+	Name string `json:"name"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SubNamespaceList is a list of SubNamespace resources
+type SubNamespaceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []SubNamespace `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantResourceQuota describes a tenant resource quota resource
+type TenantResourceQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the tenantresourcequota resource spec
+	Spec TenantResourceQuotaSpec `json:"spec"`
+	// Status is the tenantresourcequota resource status
+	Status TenantResourceQuotaStatus `json:"status,omitempty"`
+}
+
+// TenantResourceQuotaSpec is the spec for a tenant resource quota resource
+type TenantResourceQuotaSpec struct {
+	// Claims increase the overall quota, by name.
+	Claims map[string]ResourceTuning `json:"claims,omitempty"`
+	// Drops decrease the overall quota, by name.
+	Drops map[string]ResourceTuning `json:"drops,omitempty"`
+	// Percentage of the quota at which the tenant is alerted, it is not alerted if nil.
+	SoftLimit *int `json:"softLimit,omitempty"`
+	// HardBlock rejects the pods that would take the tenant beyond its quota.
+	HardBlock bool `json:"hardBlock,omitempty"`
+}
+
+// ResourceTuning indicates resources to add or remove, and how long they will remain
+type ResourceTuning struct {
+	// This denotes which resources to be included.
+	ResourceList map[corev1.ResourceName]resource.Quantity `json:"resourceList"`
+	// Expiration date of the ResourceTuning, none if nil.
+	Expiry *metav1.Time `json:"expiry,omitempty"`
+}
+
+// TenantResourceQuotaStatus is the status for a tenant resource quota resource
+type TenantResourceQuotaStatus struct {
+	// Denotes the state of the TenantResourceQuota. This can be 'Failure', or 'Success'.
+	State string `json:"state"`
+	// Message contains additional information.
+	Message string `json:"message"`
+	// Resources requested by the pods of the tenant that count against the quota.
+	Used map[corev1.ResourceName]resource.Quantity `json:"used,omitempty"`
+	// Conditions of the tenant resource quota.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantResourceQuotaList is a list of tenant resource quota resources
+type TenantResourceQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []TenantResourceQuota `json:"items"`
+}
@@ -0,0 +1,3 @@
+package federation
+
+const GroupName = "federation.edgenet.io"
@@ -0,0 +1,43 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Examples returns a manifest of each kind in the group, the API reference is rendered with them
+func Examples() []runtime.Object {
+	typeMeta := func(kind string) metav1.TypeMeta {
+		return metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: kind}
+	}
+
+	return []runtime.Object{
+		&Cluster{TypeMeta: typeMeta("Cluster"), ObjectMeta: metav1.ObjectMeta{Name: "paris", Labels: map[string]string{"edge-net.io/country-iso": "FR"}},
+			Spec: ClusterSpec{Role: "Workload", Endpoint: "https://paris.edge-net.io:6443",
+				BootstrapSecret: SecretReference{Name: "paris-bootstrap", Namespace: "federation", Key: "token"}}},
+		&FederatedTenant{TypeMeta: typeMeta("FederatedTenant"), ObjectMeta: metav1.ObjectMeta{Name: "lip6-lab"},
+			Spec: FederatedTenantSpec{Tenant: "lip6-lab", Clusters: []string{"paris"}, SubNamespaces: true}},
+		&SelectiveDeploymentAnchor{TypeMeta: typeMeta("SelectiveDeploymentAnchor"), ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: "lip6-lab"},
+			Spec: SelectiveDeploymentAnchorSpec{SelectiveDeployment: "nginx",
+				Selector: []ClusterSelector{{Name: "Continent", Value: []string{"Europe"}, Operator: corev1.NodeSelectorOpIn, Quantity: 1}},
+				Capacity: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4"), corev1.ResourceMemory: resource.MustParse("8Gi")}}},
+	}
+}
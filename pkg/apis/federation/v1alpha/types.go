@@ -0,0 +1,244 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:printcolumn:name="Role",type=string,JSONPath=".spec.role"
+// +kubebuilder:printcolumn:name="Endpoint",type=string,JSONPath=".spec.endpoint"
+// +kubebuilder:printcolumn:name="Version",type=string,JSONPath=".status.version"
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Heartbeat",type=date,JSONPath=".status.lastheartbeat"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Cluster describes a cluster that takes part in the federation
+type Cluster struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the cluster resource spec
+	Spec ClusterSpec `json:"spec"`
+	// Status is the cluster resource status
+	Status ClusterStatus `json:"status,omitempty"`
+}
+
+// ClusterSpec is the spec for a Cluster resource
+type ClusterSpec struct {
+	// Role of the cluster in the federation, 'Manager' or 'Workload'.
+	// +kubebuilder:validation:Enum=Manager;Workload
+	Role string `json:"role"`
+	// URL of the API server of the cluster.
+	// +kubebuilder:validation:Pattern=`^https://`
+	Endpoint string `json:"endpoint"`
+	// PEM encoded CA bundle to verify the API server of the cluster.
+	CABundle []byte `json:"cabundle"`
+	// Secret holding the bootstrap token that grants access to the cluster until the
+	// credentials of a service account are exchanged.
+	BootstrapSecret SecretReference `json:"bootstrapsecret"`
+}
+
+// SecretReference points to a secret and the key of the token in it
+type SecretReference struct {
+	// Name of the secret.
+	Name string `json:"name"`
+	// Namespace of the secret.
+	Namespace string `json:"namespace"`
+	// Key of the token in the data of the secret, 'token' if empty.
+	Key string `json:"key"`
+}
+
+// ClusterStatus is the status for a Cluster resource
+type ClusterStatus struct {
+	// State of the cluster, 'Ready', 'Pending', 'Unreachable', or 'Failure'.
+	State string `json:"state"`
+	// Message contains additional information.
+	Message string `json:"message"`
+	// Kubernetes version of the cluster, as reported by its API server.
+	Version string `json:"version"`
+	// Last time the cluster replied to a heartbeat.
+	LastHeartbeat *metav1.Time `json:"lastheartbeat,omitempty"`
+	// Resources allocatable on the ready nodes of a workload cluster, as of the last heartbeat.
+	Allocatable corev1.ResourceList `json:"allocatable,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterList is a list of Cluster resources
+type ClusterList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// ClusterList is a list of Cluster resources thus, Clusters are contained here.
+	Items []Cluster `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:printcolumn:name="Tenant",type=string,JSONPath=".spec.tenant"
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FederatedTenant describes the propagation of a tenant from the manager cluster to the workload clusters
+type FederatedTenant struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the federatedtenant resource spec
+	Spec FederatedTenantSpec `json:"spec"`
+	// Status is the federatedtenant resource status
+	Status FederatedTenantStatus `json:"status,omitempty"`
+}
+
+// FederatedTenantSpec is the spec for a FederatedTenant resource
+type FederatedTenantSpec struct {
+	// Name of the tenant to be propagated.
+	Tenant string `json:"tenant"`
+	// Names of the workload clusters that receive the tenant. The tenant is propagated
+	// to all registered workload clusters if empty.
+	Clusters []string `json:"clusters"`
+	// If the subnamespaces of the tenant are propagated as well.
+	SubNamespaces bool `json:"subnamespaces"`
+}
+
+// FederatedTenantStatus is the status for a FederatedTenant resource
+type FederatedTenantStatus struct {
+	// State of the propagation, 'Established' once the tenant is in all clusters.
+	State string `json:"state"`
+	// Message contains additional information.
+	Message string `json:"message"`
+	// Establishment status of the tenant in each workload cluster.
+	Clusters []ClusterPropagation `json:"clusters"`
+}
+
+// ClusterPropagation describes the state of a tenant in a workload cluster
+type ClusterPropagation struct {
+	// Name of the workload cluster.
+	Name string `json:"name"`
+	// State of the tenant in the cluster, 'Established' or 'Failure'.
+	State string `json:"state"`
+	// Message contains additional information.
+	Message string `json:"message"`
+	// Last time the tenant was synced to the cluster.
+	LastSync *metav1.Time `json:"lastsync,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// FederatedTenantList is a list of FederatedTenant resources
+type FederatedTenantList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// FederatedTenantList is a list of FederatedTenant resources thus, FederatedTenants are contained here.
+	Items []FederatedTenant `json:"items"`
+}
+
+// +genclient
+// +kubebuilder:printcolumn:name="Selective Deployment",type=string,JSONPath=".spec.selectivedeployment"
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=".status.ready"
+// +kubebuilder:printcolumn:name="Status",type=string,JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SelectiveDeploymentAnchor places a selective deployment of the originating cluster in the workload clusters
+type SelectiveDeploymentAnchor struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the selectivedeploymentanchor resource spec
+	Spec SelectiveDeploymentAnchorSpec `json:"spec"`
+	// Status is the selectivedeploymentanchor resource status
+	Status SelectiveDeploymentAnchorStatus `json:"status,omitempty"`
+}
+
+// SelectiveDeploymentAnchorSpec is the spec for a SelectiveDeploymentAnchor resource.
+// Selectors filter the workload clusters by the geolabels of the Cluster resources.
+type SelectiveDeploymentAnchorSpec struct {
+	// Name of the selective deployment, in the namespace of the anchor, to be copied into the clusters.
+	SelectiveDeployment string `json:"selectivedeployment"`
+	// List of ClusterSelector resources. Each selector picks the clusters with the
+	// requested method, all ready workload clusters are candidates if empty.
+	Selector []ClusterSelector `json:"selector"`
+	// Minimum resources allocatable in a cluster for it to be picked.
+	Capacity corev1.ResourceList `json:"capacity,omitempty"`
+}
+
+// ClusterSelector to define desired cluster filtering parameters
+type ClusterSelector struct {
+	// Name of the selector. This can be City, State, Country, or Continent.
+	// +kubebuilder:validation:Enum=City;State;Country;Continent
+	Name string `json:"name"`
+	// Value of the selector. For example; if the name of the selector is 'Country'
+	// then the value can be the ISO code of the country.
+	Value []string `json:"value"`
+	// Operator can be 'In' or 'NotIn'.
+	// +kubebuilder:validation:Enum=In;NotIn
+	Operator corev1.NodeSelectorOperator `json:"operator"`
+	// Quantity represents number of clusters in which the selective deployment will be placed.
+	// +kubebuilder:validation:Minimum=1
+	Quantity int `json:"quantity"`
+}
+
+// SelectiveDeploymentAnchorStatus is the status for a SelectiveDeploymentAnchor resource
+type SelectiveDeploymentAnchorStatus struct {
+	// Ready string denotes number of workloads running in all clusters.
+	// The string is 'x/y' if x instances are running and y instances are requested.
+	Ready string `json:"ready"`
+	// Represents state of the anchor. This can be 'Failure' if none of the clusters
+	// run the workloads. 'Partial' if some of them do. 'Success' if all of them do.
+	State string `json:"state"`
+	// Message contains additional information.
+	Message string `json:"message"`
+	// Rollout status of the selective deployment in each chosen cluster.
+	Clusters []ClusterPlacement `json:"clusters"`
+}
+
+// ClusterPlacement describes the rollout of a selective deployment in a workload cluster
+type ClusterPlacement struct {
+	// Name of the workload cluster.
+	Name string `json:"name"`
+	// Ready string of the selective deployment in the cluster.
+	Ready string `json:"ready"`
+	// State of the selective deployment in the cluster, 'Success', 'Partial', or 'Failure'.
+	State string `json:"state"`
+	// Message contains additional information.
+	Message string `json:"message"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SelectiveDeploymentAnchorList is a list of SelectiveDeploymentAnchor resources
+type SelectiveDeploymentAnchorList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// SelectiveDeploymentAnchorList is a list of SelectiveDeploymentAnchor resources thus,
+	// SelectiveDeploymentAnchors are contained here.
+	Items []SelectiveDeploymentAnchor `json:"items"`
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Examples returns a manifest of each kind in the group, the API reference is rendered with them
+func Examples() []runtime.Object {
+	endpointAddress := "192.0.2.10"
+	endpointPort := 51820
+	latency := metav1.Duration{Duration: 1800 * time.Microsecond}
+
+	return []runtime.Object{
+		&NodeLink{TypeMeta: metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: "NodeLink"}, ObjectMeta: metav1.ObjectMeta{Name: "node-paris-1--node-paris-2"},
+			Spec:   NodeLinkSpec{Source: "node-paris-1", Target: "node-paris-2"},
+			Status: NodeLinkStatus{Address: "192.0.2.11", Latency: &latency, PacketLoss: 0, Measured: metav1.Date(2021, 11, 8, 14, 0, 0, 0, time.UTC)}},
+		&VPNPeer{TypeMeta: metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: "VPNPeer"}, ObjectMeta: metav1.ObjectMeta{Name: "node-paris-1"},
+			Spec: VPNPeerSpec{AddressV4: "10.183.0.10", AddressV6: "fdb4:ae86:ec99:4004::a", EndpointAddress: &endpointAddress,
+				EndpointPort: &endpointPort, PublicKey: "xTIBA5rboUvnH4htodjb6e697QjLERt1NAB4mZqp8Dg="}},
+	}
+}
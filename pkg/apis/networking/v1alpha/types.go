@@ -22,6 +22,10 @@ import (
 
 // +genclient
 // +genclient:nonNamespaced
+// +kubebuilder:printcolumn:name="Address-V4",type=string,JSONPath=".spec.addressV4"
+// +kubebuilder:printcolumn:name="Address-V6",type=string,JSONPath=".spec.addressV6"
+// +kubebuilder:printcolumn:name="Endpoint",type=string,JSONPath=".spec.endpointAddress"
+// +kubebuilder:printcolumn:name="Port",type=string,JSONPath=".spec.endpointPort"
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // VPNPeer describes a WireGuard peer
@@ -36,13 +40,17 @@ type VPNPeer struct {
 
 // VPNPeerSpec is the spec for a VPNPeer resource
 type VPNPeerSpec struct {
-	// IPv4 address of VPN peer.
-	AddressV4 string `json:"addressV4"`
-	// IPv6 address of VPN peer.
-	AddressV6 string `json:"addressV6"`
+	// IPv4 address of VPN peer, empty on an IPv6-only node.
+	// +kubebuilder:validation:Pattern=`^[0-9.]+$`
+	AddressV4 string `json:"addressV4,omitempty"`
+	// IPv6 address of VPN peer, empty on an IPv4-only node.
+	// +kubebuilder:validation:Pattern=`^[a-f0-9:]+$`
+	AddressV6 string `json:"addressV6,omitempty"`
 	// Endpoint address of the VPN tunnel.
+	// +kubebuilder:validation:Pattern=`^[a-f0-9.:]+$`
 	EndpointAddress *string `json:"endpointAddress"`
 	// Endpoint port of the VPN tunnel.
+	// +kubebuilder:validation:Minimum=1
 	EndpointPort *int `json:"endpointPort"`
 	// VPN public key of the peer.
 	PublicKey string `json:"publicKey"`
@@ -59,3 +67,58 @@ type VPNPeerList struct {
 	// VPNPeerList is a list of VPNPeer resources thus, VPNPeers are contained here.
 	Items []VPNPeer `json:"items"`
 }
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:printcolumn:name="Source",type=string,JSONPath=".spec.source"
+// +kubebuilder:printcolumn:name="Target",type=string,JSONPath=".spec.target"
+// +kubebuilder:printcolumn:name="Latency",type=string,JSONPath=".status.latency"
+// +kubebuilder:printcolumn:name="Loss",type=integer,JSONPath=".status.packetLoss"
+// +kubebuilder:printcolumn:name="Measured",type=date,JSONPath=".status.measured"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeLink describes the path from a node to another, measured by the agent on the source node
+type NodeLink struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the nodelink resource spec
+	Spec NodeLinkSpec `json:"spec"`
+	// Status is the nodelink resource status
+	Status NodeLinkStatus `json:"status,omitempty"`
+}
+
+// NodeLinkSpec is the spec for a NodeLink resource
+type NodeLinkSpec struct {
+	// Node the probes are sent from.
+	Source string `json:"source"`
+	// Node the probes are sent to.
+	Target string `json:"target"`
+}
+
+// NodeLinkStatus is the status for a NodeLink resource
+type NodeLinkStatus struct {
+	// Address of the target node the probes were sent to.
+	Address string `json:"address,omitempty"`
+	// Round-trip time averaged over the probes answered, unset if none was.
+	Latency *metav1.Duration `json:"latency,omitempty"`
+	// Percentage of the probes left unanswered.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	PacketLoss int `json:"packetLoss"`
+	// Time of the last measurement.
+	Measured metav1.Time `json:"measured,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// NodeLinkList is a list of NodeLink resources
+type NodeLinkList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// NodeLinkList is a list of NodeLink resources thus, NodeLinks are contained here.
+	Items []NodeLink `json:"items"`
+}
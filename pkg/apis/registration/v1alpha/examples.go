@@ -0,0 +1,60 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+)
+
+// Examples returns a manifest of each kind in the group, the API reference is rendered with them
+func Examples() []runtime.Object {
+	typeMeta := func(kind string) metav1.TypeMeta {
+		return metav1.TypeMeta{APIVersion: SchemeGroupVersion.String(), Kind: kind}
+	}
+
+	return []runtime.Object{
+		&TenantRequest{TypeMeta: typeMeta("TenantRequest"), ObjectMeta: metav1.ObjectMeta{Name: "lip6-lab"},
+			Spec: TenantRequestSpec{FullName: "LIP6", ShortName: "LIP6", URL: "https://www.lip6.fr",
+				Address:              corev1alpha.Address{Street: "4 place Jussieu", ZIP: "75005", City: "Paris", Region: "Ile-de-France", Country: "France"},
+				Contact:              corev1alpha.Contact{Handle: "johndoe", FirstName: "John", LastName: "Doe", Email: "john.doe@edge-net.org", Phone: "+33000000000"},
+				ClusterNetworkPolicy: false,
+				ResourceAllocation: map[corev1.ResourceName]resource.Quantity{
+					corev1.ResourceCPU:    resource.MustParse("8000m"),
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				}}},
+		&ClusterRoleRequest{TypeMeta: typeMeta("ClusterRoleRequest"), ObjectMeta: metav1.ObjectMeta{Name: "johndoe"},
+			Spec: ClusterRoleRequestSpec{FirstName: "John", LastName: "Doe", Email: "john.doe@edge-net.org", RoleName: "edgenet:tenant-owner"}},
+		&RoleRequest{TypeMeta: typeMeta("RoleRequest"), ObjectMeta: metav1.ObjectMeta{Name: "janedoe", Namespace: "lip6-lab"},
+			Spec: RoleRequestSpec{FirstName: "Jane", LastName: "Doe", Email: "jane.doe@edge-net.org",
+				RoleRef: RoleRefSpec{Kind: "ClusterRole", Name: "edgenet:tenant-collaborator"}}},
+		&ExtensionRequest{TypeMeta: typeMeta("ExtensionRequest"), ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "lip6-lab"},
+			Spec: ExtensionRequestSpec{FirstName: "John", LastName: "Doe", Email: "john.doe@edge-net.org", Extension: "postgres-operator"}},
+		&Roster{TypeMeta: typeMeta("Roster"), ObjectMeta: metav1.ObjectMeta{Name: "networks-101", Namespace: "lip6-lab"},
+			Spec: RosterSpec{Role: "collaborator",
+				Students: []Student{{Email: "jane.doe@edge-net.org", FirstName: "Jane", LastName: "Doe"}},
+				CSV:      "email,firstname,lastname\nrichard.roe@edge-net.org,Richard,Roe\n",
+				ResourceAllocation: map[corev1.ResourceName]resource.Quantity{
+					corev1.ResourceCPU:    resource.MustParse("1000m"),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				}}},
+	}
+}
@@ -56,6 +56,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&ClusterRoleRequestList{},
 		&RoleRequest{},
 		&RoleRequestList{},
+		&Roster{},
+		&RosterList{},
+		&ExtensionRequest{},
+		&ExtensionRequestList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
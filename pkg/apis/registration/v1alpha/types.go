@@ -26,6 +26,13 @@ import (
 
 // +genclient
 // +genclient:nonNamespaced
+// +kubebuilder:printcolumn:name="Official Name",type=string,JSONPath=".spec.fullname"
+// +kubebuilder:printcolumn:name="Short Name",type=string,JSONPath=".spec.shortname"
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=".spec.url"
+// +kubebuilder:printcolumn:name="City",type=string,JSONPath=".spec.address.city"
+// +kubebuilder:printcolumn:name="Country",type=string,JSONPath=".spec.address.country"
+// +kubebuilder:printcolumn:name="Expiry",type=string,JSONPath=".status.expiry"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // TenantRequest describes a TenantRequest resource
@@ -58,20 +65,66 @@ type TenantRequestSpec struct {
 	// Requested allocation of certain resource types. Resource types are
 	// kubernetes default resource types.
 	ResourceAllocation map[corev1.ResourceName]resource.Quantity `json:"resourceallocation"`
-	// If the tenant is approved or not by the administrators.
+	// Name of the tenant profile whose defaults the tenant is created with.
+	Profile string `json:"profile,omitempty"`
+	// Invitation token signed for the contact email, required when the cluster only
+	// takes tenant requests by invitation.
+	Invitation string `json:"invitation,omitempty"`
+	// If the tenant is approved or not by the administrators. It stands for a single
+	// approval, the approval records are needed when the quorum is more than one.
 	Approved bool `json:"approved"`
+	// Verdicts of the administrators on the request.
+	Approvals []Approval `json:"approvals,omitempty"`
+	// Set by the contact to extend the expiry of the pending request, the expiry can be
+	// extended once.
+	Renew bool `json:"renew,omitempty"`
+}
+
+// Approval records the verdict of an administrator on a request
+type Approval struct {
+	// Administrator who gave the verdict.
+	Approver string `json:"approver"`
+	// Time of the verdict.
+	Time metav1.Time `json:"time"`
+	// This can be 'Approve' or 'Reject'.
+	// +kubebuilder:validation:Enum=Approve;Reject
+	Verdict string `json:"verdict"`
 }
 
 // TenantRequestStatus is the status for a TenantRequest resource
 type TenantRequestStatus struct {
 	// Expiration date of the request.
 	Expiry *metav1.Time `json:"expiry"`
-	// Current state of the policy. This can be 'Failure', 'Pending', or 'Approved'.
+	// Number of the reminders sent to the requester before the expiry.
+	Reminders int `json:"reminders,omitempty"`
+	// Whether the requester has already extended the expiry.
+	Renewed bool `json:"renewed,omitempty"`
+	// Whether the request has been forwarded to the external approval system.
+	Forwarded bool `json:"forwarded,omitempty"`
+	// Estimate of what the requested allocation costs and weighs in the cluster, to help the
+	// administrators decide.
+	Estimate *CostEstimate `json:"estimate,omitempty"`
+	// Current state of the policy. This can be 'Failure', 'Rejected', 'Pending', or 'Approved'.
 	State string `json:"state"`
 	// Description for additional information.
 	Message string `json:"message"`
 }
 
+// CostEstimate is the monthly cost of a requested allocation against the pricing table of the
+// cluster, and its share of the allocatable capacity of the cluster
+type CostEstimate struct {
+	// Monthly cost of the requested allocation, empty if the cluster has no pricing table.
+	Monthly string `json:"monthly,omitempty"`
+	// Currency of the pricing table.
+	Currency string `json:"currency,omitempty"`
+	// Monthly cost of each priced resource type in the allocation.
+	Breakdown map[corev1.ResourceName]string `json:"breakdown,omitempty"`
+	// Largest share of the allocatable capacity of the cluster among the requested resource types, in percent.
+	Share int `json:"share"`
+	// Resource type whose share is the largest.
+	DominantResource corev1.ResourceName `json:"dominantresource,omitempty"`
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // TenantRequestList is a list of TenantRequest resources
@@ -114,12 +167,19 @@ type ClusterRoleRequestSpec struct {
 	RoleName string `json:"rolename"`
 	// True if this role request is approved false if not.
 	Approved bool `json:"approved"`
+	// Set by the requester to extend the expiry of the pending request, the expiry can be
+	// extended once.
+	Renew bool `json:"renew,omitempty"`
 }
 
 // ClusterRoleRequestStatus is the status for a ClusterRoleRequest resource
 type ClusterRoleRequestStatus struct {
 	// Expiration date of the request.
 	Expiry *metav1.Time `json:"expiry"`
+	// Number of the reminders sent to the requester before the expiry.
+	Reminders int `json:"reminders,omitempty"`
+	// Whether the requester has already extended the expiry.
+	Renewed bool `json:"renewed,omitempty"`
 	// Current state of the policy. This can be 'Failure', 'Pending', or 'Approved'.
 	State string `json:"state"`
 	// Description for additional information.
@@ -166,6 +226,9 @@ type RoleRequestSpec struct {
 	RoleRef RoleRefSpec `json:"roleref"`
 	// True if this role request is approved false if not.
 	Approved bool `json:"approved"`
+	// Set by the requester to extend the expiry of the pending request, the expiry can be
+	// extended once.
+	Renew bool `json:"renew,omitempty"`
 }
 
 // RoleRefSpec indicates the requested Role / ClusterRole
@@ -180,7 +243,13 @@ type RoleRefSpec struct {
 type RoleRequestStatus struct {
 	// Expiration date of the request.
 	Expiry *metav1.Time `json:"expiry"`
-	// Current state of the policy. This can be 'Failure', 'Pending', or 'Approved'.
+	// Number of the reminders sent to the requester before the expiry.
+	Reminders int `json:"reminders,omitempty"`
+	// Whether the requester has already extended the expiry.
+	Renewed bool `json:"renewed,omitempty"`
+	// Whether the request has been forwarded to the external approval system.
+	Forwarded bool `json:"forwarded,omitempty"`
+	// Current state of the policy. This can be 'Failure', 'Rejected', 'Pending', or 'Approved'.
 	State string `json:"state"`
 	// Description for additional information.
 	Message string `json:"message"`
@@ -198,3 +267,159 @@ type RoleRequestList struct {
 	// RoleRequest resources.
 	Items []RoleRequest `json:"items"`
 }
+
+// +genclient
+// +kubebuilder:printcolumn:name="Extension",type=string,JSONPath=".spec.extension"
+// +kubebuilder:printcolumn:name="Email",type=string,JSONPath=".spec.email"
+// +kubebuilder:printcolumn:name="Approved",type=boolean,JSONPath=".status.approved"
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ExtensionRequest describes an ExtensionRequest resource
+type ExtensionRequest struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the extensionrequest resource spec
+	Spec ExtensionRequestSpec `json:"spec"`
+	// Status is the extensionrequest resource status
+	Status ExtensionRequestStatus `json:"status,omitempty"`
+}
+
+// ExtensionRequestSpec is the spec for an ExtensionRequest resource
+type ExtensionRequestSpec struct {
+	// First name of the person requesting the extension.
+	FirstName string `json:"firstname"`
+	// Last name of the person requesting the extension.
+	LastName string `json:"lastname"`
+	// Email of the person requesting the extension.
+	// +kubebuilder:validation:Format=email
+	Email string `json:"email"`
+	// Name of the extension in the catalog.
+	Extension string `json:"extension"`
+}
+
+// ExtensionRequestStatus is the status for an ExtensionRequest resource
+type ExtensionRequestStatus struct {
+	// True if this extension request is approved false if not. The cluster administrators approve
+	// it through the status subresource, which the requester cannot write.
+	Approved bool `json:"approved"`
+	// Expiration date of the request.
+	Expiry *metav1.Time `json:"expiry"`
+	// Current state of the request. This can be 'Failure', 'Pending', or 'Approved'.
+	State string `json:"state"`
+	// Description for additional information.
+	Message string `json:"message"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ExtensionRequestList is a list of ExtensionRequest resources
+type ExtensionRequestList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// ExtensionRequestList is a list of ExtensionRequest resources. This element contains
+	// ExtensionRequest resources.
+	Items []ExtensionRequest `json:"items"`
+}
+
+// +genclient
+// +kubebuilder:printcolumn:name="Role",type=string,JSONPath=".spec.role"
+// +kubebuilder:printcolumn:name="Students",type=integer,JSONPath=".status.provisioned"
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=".status.state"
+// +kubebuilder:printcolumn:name="Expiry",type=date,JSONPath=".spec.expiry"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Roster describes the students of a class that a tenant owner provisions at once. It lives in the
+// core namespace of the tenant, and each student gets a role request, a workspace, and a kubeconfig.
+type Roster struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Spec is the roster resource spec
+	Spec RosterSpec `json:"spec"`
+	// Status is the roster resource status
+	Status RosterStatus `json:"status,omitempty"`
+}
+
+// RosterSpec is the spec for a Roster resource
+type RosterSpec struct {
+	// Students of the class.
+	// +optional
+	Students []Student `json:"students,omitempty"`
+	// Students of the class in CSV, as exported from a spreadsheet. Each line holds the email,
+	// first name, and last name of a student, a header line is skipped.
+	// +optional
+	CSV string `json:"csv,omitempty"`
+	// Role of the students in the tenant. This can be 'collaborator', or 'admin'.
+	// +kubebuilder:validation:Enum=collaborator;admin
+	Role string `json:"role"`
+	// Resources of the workspace of each student. The students get no workspace if empty.
+	// +optional
+	ResourceAllocation map[corev1.ResourceName]resource.Quantity `json:"resourceallocation,omitempty"`
+	// End of the semester, the students are torn down once it is over.
+	// +optional
+	Expiry *metav1.Time `json:"expiry,omitempty"`
+	// Teardown removes the role requests, the workspaces, and the kubeconfigs of the students
+	// and their roles in the tenant, the roster is kept for the records.
+	// +optional
+	Teardown bool `json:"teardown,omitempty"`
+}
+
+// Student is a member of a class
+type Student struct {
+	// Email of the student, it identifies the student in the cluster.
+	// +kubebuilder:validation:Format=email
+	Email string `json:"email"`
+	// First name of the student.
+	FirstName string `json:"firstname"`
+	// Last name of the student.
+	LastName string `json:"lastname"`
+}
+
+// RosterStatus is the status for a Roster resource
+type RosterStatus struct {
+	// Current state of the roster. This can be 'Failure', 'Provisioned', or 'TornDown'.
+	State string `json:"state"`
+	// Description for additional information.
+	Message string `json:"message"`
+	// Number of students provisioned.
+	Provisioned int `json:"provisioned"`
+	// Objects provisioned for each student.
+	Students []StudentStatus `json:"students,omitempty"`
+}
+
+// StudentStatus lists the objects provisioned for a student
+type StudentStatus struct {
+	// Email of the student.
+	Email string `json:"email"`
+	// Handle of the student, the objects provisioned are named after it.
+	Handle string `json:"handle"`
+	// Name of the role request of the student in the core namespace.
+	RoleRequest string `json:"rolerequest,omitempty"`
+	// Name of the workspace of the student.
+	Workspace string `json:"workspace,omitempty"`
+	// Name of the secret holding the kubeconfig of the student.
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	// Description of the failure if the student could not be provisioned.
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RosterList is a list of Roster resources
+type RosterList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// RosterList is a list of Roster resources. This element contains
+	// Roster resources.
+	Items []Roster `json:"items"`
+}
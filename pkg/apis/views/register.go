@@ -0,0 +1,3 @@
+package views
+
+const GroupName = "views.edgenet.io"
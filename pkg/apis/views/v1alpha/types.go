@@ -0,0 +1,103 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantSummary is a read-only view of a tenant computed from its namespaces, quotas, nodes, and
+// requests. It is served by the views API server in the core namespace of the tenant, under the
+// name of the tenant, and is never stored.
+type TenantSummary struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Status is the tenant summary computed on request
+	Status TenantSummaryStatus `json:"status"`
+}
+
+// TenantSummaryStatus is the status for a TenantSummary resource
+type TenantSummaryStatus struct {
+	// If the tenant is active then this field is true.
+	Enabled bool `json:"enabled"`
+	// State of the tenant, this can be 'Established' or 'Failure'.
+	State string `json:"state"`
+	// Quota of the tenant summed over its namespaces.
+	Quota ResourceUsage `json:"quota"`
+	// Nodes contributed by the tenant.
+	Nodes NodeCount `json:"nodes"`
+	// Namespaces is the tree of the namespaces of the tenant, rooted at its core namespace.
+	Namespaces NamespaceTree `json:"namespaces"`
+	// Requests awaiting a decision in the namespaces of the tenant.
+	Requests RequestBacklog `json:"requests"`
+}
+
+// ResourceUsage is the hard limit of a resource quota and how much of it is used
+type ResourceUsage struct {
+	// Hard limits of the quota.
+	Hard corev1.ResourceList `json:"hard,omitempty"`
+	// Resources used out of the hard limits.
+	Used corev1.ResourceList `json:"used,omitempty"`
+}
+
+// NodeCount is the number of nodes contributed by a tenant
+type NodeCount struct {
+	// Number of the node contributions of the tenant.
+	Contributed int `json:"contributed"`
+	// Number of the contributed nodes that are ready.
+	Ready int `json:"ready"`
+}
+
+// NamespaceTree is a namespace of a tenant and the subnamespaces it holds
+type NamespaceTree struct {
+	// Name of the namespace.
+	Name string `json:"name"`
+	// Name of the subnamespace the namespace is made from, empty for the core namespace.
+	SubNamespace string `json:"subnamespace,omitempty"`
+	// Quota of the namespace.
+	Quota ResourceUsage `json:"quota"`
+	// Namespaces made from the subnamespaces in this namespace.
+	Children []NamespaceTree `json:"children,omitempty"`
+}
+
+// RequestBacklog is the number of requests pending in the namespaces of a tenant
+type RequestBacklog struct {
+	// Number of the pending role requests.
+	RoleRequests int `json:"rolerequests"`
+	// Number of the pending extension requests.
+	ExtensionRequests int `json:"extensionrequests"`
+	// Creation time of the oldest pending request.
+	Oldest *metav1.Time `json:"oldest,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TenantSummaryList is a list of TenantSummary resources
+type TenantSummaryList struct {
+	// TypeMeta is the metadata for the resource, like kind and apiversion
+	metav1.TypeMeta `json:",inline"`
+	// ObjectMeta contains the metadata for the particular object, including
+	metav1.ListMeta `json:"metadata"`
+	// TenantSummaryList is a list of TenantSummary resources. This element contains
+	// TenantSummary resources.
+	Items []TenantSummary `json:"items"`
+}
@@ -0,0 +1,420 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apiserver serves the registration API of the console, so that the console submits the
+// tenant and role requests without cluster credentials of its own:
+//
+//	POST /v1/tenantrequests                                        {"name": "...", "spec": {...}}
+//	GET  /v1/tenantrequests/<name>?email=<contact email>
+//	POST /v1/tenantrequests/<name>/verification                    {"code": "..."}
+//	POST /v1/tenantrequests/<name>/approval                        (Authorization: Bearer <token>)
+//	POST /v1/tenantrequests/<name>/rejection                       (Authorization: Bearer <token>)
+//	POST /v1/namespaces/<namespace>/rolerequests                   {"name": "...", "spec": {...}}
+//	GET  /v1/namespaces/<namespace>/rolerequests/<name>?email=<requester email>
+//	POST /v1/namespaces/<namespace>/rolerequests/<name>/verification {"code": "..."}
+//	POST /v1/approvals                                             (X-EdgeNet-Signature, X-EdgeNet-Timestamp)
+//	POST /v1/nodecontributions/join                                {"token": "..."}
+//	DELETE /v1/tenants/<name>?purge=true                           (Authorization: Bearer <token>)
+//	POST /v1/logins                                                {"tenant": "..."} (client certificate or Authorization: Bearer <OIDC token>)
+//	POST /v1/logins/<namespace>/<name>/token                       (Authorization: Bearer <session token>)
+//	DELETE /v1/logins/<namespace>/<name>                           (Authorization: Bearer <session token>)
+//
+// The requests are submitted with their email address unverified. The requester receives a code by
+// email, and the request awaits the approval only once the code is posted back. The administrators
+// approve or reject the tenant requests with their cluster token, they need the right to update them.
+// An external approval system decides the requests it has been forwarded through the signed
+// callbacks of the approvals endpoint, see the approval package. The bootstrap script of the nodes
+// contributed with a token exchanges their one-time join token for the kubeadm join command. The
+// administrators allowed to delete a tenant may ask for its personal data to be purged along with
+// it, see the purge package. The web console opens a session in a tenant with the client
+// certificate or the OIDC token of the user, and works with the short-lived tokens of the session,
+// which hold the roles of the user in the tenant until the session is closed or expires.
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/approval"
+	"github.com/EdgeNet-project/edgenet/pkg/config"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	registrationclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/typed/registration/v1alpha"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// maxBodySize bounds the size of the submissions
+const maxBodySize = 1 << 20
+
+// now is replaced in the tests to issue and check the codes at a known time
+var now = time.Now
+
+// TenantRequestSubmission is the body posted to submit a tenant request
+type TenantRequestSubmission struct {
+	Name string                                `json:"name"`
+	Spec registrationv1alpha.TenantRequestSpec `json:"spec"`
+}
+
+// RoleRequestSubmission is the body posted to submit a role request
+type RoleRequestSubmission struct {
+	Name string                              `json:"name"`
+	Spec registrationv1alpha.RoleRequestSpec `json:"spec"`
+}
+
+// Verification is the body posted to verify the email address of a request
+type Verification struct {
+	Code string `json:"code"`
+}
+
+// RequestStatus is the reply about a request
+type RequestStatus struct {
+	Name          string       `json:"name"`
+	Namespace     string       `json:"namespace,omitempty"`
+	EmailVerified bool         `json:"emailVerified"`
+	State         string       `json:"state"`
+	Message       string       `json:"message"`
+	Expiry        *metav1.Time `json:"expiry,omitempty"`
+}
+
+type server struct {
+	kubeclientset    kubernetes.Interface
+	edgenetclientset clientset.Interface
+	identity         *config.ClusterIdentity
+	// replay holds the nonces of the callbacks of the external approval system
+	replay *approval.ReplayGuard
+}
+
+// Handler serves the registration API backed by the clientsets
+func Handler(kubeclientset kubernetes.Interface, edgenetclientset clientset.Interface) http.Handler {
+	s := &server{kubeclientset: kubeclientset, edgenetclientset: edgenetclientset, identity: config.NewClusterIdentity(kubeclientset),
+		replay: approval.NewReplayGuard()}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/approvals", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.externalVerdict(w, r)
+	})
+	mux.HandleFunc("/v1/nodecontributions/join", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.exchangeJoinToken(w, r)
+	})
+	mux.HandleFunc("/v1/tenants/", func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/v1/tenants/")
+		switch {
+		case name != "" && !strings.Contains(name, "/") && r.Method == http.MethodDelete:
+			s.deleteTenant(w, r, name)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	mux.HandleFunc("/v1/logins", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.openSession(w, r)
+	})
+	mux.HandleFunc("/v1/logins/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/logins/"), "/")
+		switch {
+		case len(parts) == 3 && parts[0] != "" && parts[1] != "" && parts[2] == "token" && r.Method == http.MethodPost:
+			s.refreshSession(w, r, parts[0], parts[1])
+		case len(parts) == 2 && parts[0] != "" && parts[1] != "" && r.Method == http.MethodDelete:
+			s.closeSession(w, r, parts[0], parts[1])
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	mux.HandleFunc("/v1/tenantrequests", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.submitTenantRequest(w, r)
+	})
+	mux.HandleFunc("/v1/tenantrequests/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/tenantrequests/"), "/")
+		switch {
+		case len(parts) == 1 && parts[0] != "" && r.Method == http.MethodGet:
+			s.tenantRequestStatus(w, r, parts[0])
+		case len(parts) == 2 && parts[0] != "" && parts[1] == "verification" && r.Method == http.MethodPost:
+			s.verifyTenantRequest(w, r, parts[0])
+		case len(parts) == 2 && parts[0] != "" && parts[1] == "approval" && r.Method == http.MethodPost:
+			s.decideTenantRequest(w, r, parts[0], registrationclient.VerdictApprove)
+		case len(parts) == 2 && parts[0] != "" && parts[1] == "rejection" && r.Method == http.MethodPost:
+			s.decideTenantRequest(w, r, parts[0], registrationclient.VerdictReject)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	mux.HandleFunc("/v1/namespaces/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/namespaces/"), "/")
+		if len(parts) < 2 || parts[0] == "" || parts[1] != "rolerequests" {
+			http.NotFound(w, r)
+			return
+		}
+		switch {
+		case len(parts) == 2 && r.Method == http.MethodPost:
+			s.submitRoleRequest(w, r, parts[0])
+		case len(parts) == 3 && parts[2] != "" && r.Method == http.MethodGet:
+			s.roleRequestStatus(w, r, parts[0], parts[2])
+		case len(parts) == 4 && parts[2] != "" && parts[3] == "verification" && r.Method == http.MethodPost:
+			s.verifyRoleRequest(w, r, parts[0], parts[2])
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	return mux
+}
+
+func (s *server) submitTenantRequest(w http.ResponseWriter, r *http.Request) {
+	submission := TenantRequestSubmission{}
+	if err := decode(w, r, &submission); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validate(submission.Name, submission.Spec.Contact.Email); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tenantRequest := new(registrationv1alpha.TenantRequest)
+	tenantRequest.SetName(submission.Name)
+	tenantRequest.Spec = submission.Spec
+	// The approval is the administrators' call only
+	tenantRequest.Spec.Approved = false
+	tenantRequest.Spec.Approvals = nil
+	tenantRequest.SetAnnotations(map[string]string{access.EmailVerifiedAnnotation: "false"})
+	tenantRequest, err := s.edgenetclientset.RegistrationV1alpha().TenantRequests().Create(r.Context(), tenantRequest, metav1.CreateOptions{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if len(VerificationKey) > 0 {
+		path := fmt.Sprintf("/tenantrequests/%s", tenantRequest.GetName())
+		code := newVerificationCode(VerificationKey, path, tenantRequest.Spec.Contact.Email, now().Add(VerificationPeriod))
+		if err := access.SendVerificationEmailForTenantRequest(tenantRequest, code, verificationPath(path, code), s.clusterUID(r.Context())); err != nil {
+			klog.ErrorS(err, "Couldn't send the verification code of the tenant request", "tenantRequest", klog.KObj(tenantRequest))
+		}
+	}
+	writeJSON(w, http.StatusCreated, tenantRequestStatus(tenantRequest))
+}
+
+func (s *server) tenantRequestStatus(w http.ResponseWriter, r *http.Request, name string) {
+	tenantRequest, err := s.edgenetclientset.RegistrationV1alpha().TenantRequests().Get(r.Context(), name, metav1.GetOptions{})
+	// The status is only told to the requester, the others cannot tell whether the request exists
+	if apierrors.IsNotFound(err) || (err == nil && !strings.EqualFold(r.URL.Query().Get("email"), tenantRequest.Spec.Contact.Email)) {
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tenantRequestStatus(tenantRequest))
+}
+
+func (s *server) verifyTenantRequest(w http.ResponseWriter, r *http.Request, name string) {
+	verification := Verification{}
+	if err := decode(w, r, &verification); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tenantRequest, err := s.edgenetclientset.RegistrationV1alpha().TenantRequests().Get(r.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if !access.EmailVerified(tenantRequest) {
+		path := fmt.Sprintf("/tenantrequests/%s", name)
+		if status, err := check(path, tenantRequest.Spec.Contact.Email, verification.Code); err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		tenantRequest.Annotations[access.EmailVerifiedAnnotation] = "true"
+		if tenantRequest, err = s.edgenetclientset.RegistrationV1alpha().TenantRequests().Update(r.Context(), tenantRequest, metav1.UpdateOptions{}); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, tenantRequestStatus(tenantRequest))
+}
+
+func (s *server) submitRoleRequest(w http.ResponseWriter, r *http.Request, namespace string) {
+	submission := RoleRequestSubmission{}
+	if err := decode(w, r, &submission); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validate(submission.Name, submission.Spec.Email); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	roleRequest := new(registrationv1alpha.RoleRequest)
+	roleRequest.SetName(submission.Name)
+	roleRequest.SetNamespace(namespace)
+	roleRequest.Spec = submission.Spec
+	// The approval is the tenant administrators' call only
+	roleRequest.Spec.Approved = false
+	roleRequest.SetAnnotations(map[string]string{access.EmailVerifiedAnnotation: "false"})
+	roleRequest, err := s.edgenetclientset.RegistrationV1alpha().RoleRequests(namespace).Create(r.Context(), roleRequest, metav1.CreateOptions{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if len(VerificationKey) > 0 {
+		path := fmt.Sprintf("/namespaces/%s/rolerequests/%s", namespace, roleRequest.GetName())
+		code := newVerificationCode(VerificationKey, path, roleRequest.Spec.Email, now().Add(VerificationPeriod))
+		if err := access.SendVerificationEmailForRoleRequest(roleRequest, code, verificationPath(path, code), s.clusterUID(r.Context())); err != nil {
+			klog.ErrorS(err, "Couldn't send the verification code", "roleRequest", klog.KObj(roleRequest))
+		}
+	}
+	writeJSON(w, http.StatusCreated, roleRequestStatus(roleRequest))
+}
+
+func (s *server) roleRequestStatus(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	roleRequest, err := s.edgenetclientset.RegistrationV1alpha().RoleRequests(namespace).Get(r.Context(), name, metav1.GetOptions{})
+	// The status is only told to the requester, the others cannot tell whether the request exists
+	if apierrors.IsNotFound(err) || (err == nil && !strings.EqualFold(r.URL.Query().Get("email"), roleRequest.Spec.Email)) {
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, roleRequestStatus(roleRequest))
+}
+
+func (s *server) verifyRoleRequest(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	verification := Verification{}
+	if err := decode(w, r, &verification); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	roleRequest, err := s.edgenetclientset.RegistrationV1alpha().RoleRequests(namespace).Get(r.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if !access.EmailVerified(roleRequest) {
+		path := fmt.Sprintf("/namespaces/%s/rolerequests/%s", namespace, name)
+		if status, err := check(path, roleRequest.Spec.Email, verification.Code); err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+		roleRequest.Annotations[access.EmailVerifiedAnnotation] = "true"
+		if roleRequest, err = s.edgenetclientset.RegistrationV1alpha().RoleRequests(namespace).Update(r.Context(), roleRequest, metav1.UpdateOptions{}); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, roleRequestStatus(roleRequest))
+}
+
+// check verifies the code posted for the request, and returns the status to reply with if it is wrong
+func check(path, email, code string) (int, error) {
+	if len(VerificationKey) == 0 {
+		// Without the key, no code could be told apart from a forged one
+		return http.StatusServiceUnavailable, fmt.Errorf("email verification is not available")
+	}
+	if err := verifyCode(VerificationKey, path, email, code, now()); err != nil {
+		return http.StatusForbidden, err
+	}
+	return http.StatusOK, nil
+}
+
+// clusterUID returns the UID of the cluster that the emails are sent on behalf of
+func (s *server) clusterUID(ctx context.Context) string {
+	clusterUID, err := s.identity.UID(ctx)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't read the cluster UID")
+		return ""
+	}
+	return clusterUID
+}
+
+// verificationPath is the page of the console that posts the code back
+func verificationPath(path, code string) string {
+	return fmt.Sprintf("/verify%s?code=%s", path, url.QueryEscape(code))
+}
+
+func tenantRequestStatus(tenantRequest *registrationv1alpha.TenantRequest) RequestStatus {
+	return RequestStatus{Name: tenantRequest.GetName(), EmailVerified: access.EmailVerified(tenantRequest),
+		State: tenantRequest.Status.State, Message: tenantRequest.Status.Message, Expiry: tenantRequest.Status.Expiry}
+}
+
+func roleRequestStatus(roleRequest *registrationv1alpha.RoleRequest) RequestStatus {
+	return RequestStatus{Name: roleRequest.GetName(), Namespace: roleRequest.GetNamespace(), EmailVerified: access.EmailVerified(roleRequest),
+		State: roleRequest.Status.State, Message: roleRequest.Status.Message, Expiry: roleRequest.Status.Expiry}
+}
+
+// validate makes sure the submission names the request and gives a valid email address
+func validate(name, email string) error {
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if _, err := mail.ParseAddress(email); err != nil {
+		return fmt.Errorf("invalid email address %q", email)
+	}
+	return nil
+}
+
+func decode(w http.ResponseWriter, r *http.Request, body interface{}) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(body); err != nil {
+		return fmt.Errorf("invalid body: %s", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError replies with a generic message for the error of the API server, such as a conflict when
+// the request already exists. The error itself tells about the cluster, it is only logged.
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case apierrors.IsAlreadyExists(err):
+		http.Error(w, "request already exists", http.StatusConflict)
+	case apierrors.IsConflict(err):
+		http.Error(w, "request was modified, try again", http.StatusConflict)
+	case apierrors.IsNotFound(err):
+		http.Error(w, "request not found", http.StatusNotFound)
+	case apierrors.IsInvalid(err) || apierrors.IsBadRequest(err):
+		http.Error(w, "invalid request", http.StatusBadRequest)
+	default:
+		klog.ErrorS(err, "Couldn't serve the request")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,263 @@
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/approval"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+type apiTest struct {
+	t                *testing.T
+	handler          http.Handler
+	kubeclientset    *testclient.Clientset
+	edgenetclientset *edgenettestclient.Clientset
+}
+
+func newAPITest(t *testing.T, key string) *apiTest {
+	VerificationKey = []byte(key)
+	kubeclientset := testclient.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", UID: "cluster-uid"}})
+	edgenetclientset := edgenettestclient.NewSimpleClientset()
+	return &apiTest{t: t, handler: Handler(kubeclientset, edgenetclientset), kubeclientset: kubeclientset, edgenetclientset: edgenetclientset}
+}
+
+// do serves the request and decodes the reply into the body if it is successful
+func (a *apiTest) do(method, path string, payload interface{}, body interface{}) int {
+	return a.doWithToken(method, path, "", payload, body)
+}
+
+// doWithToken serves the request authenticated by the bearer token
+func (a *apiTest) doWithToken(method, path, token string, payload interface{}, body interface{}) int {
+	buffer := &bytes.Buffer{}
+	if payload != nil {
+		util.OK(a.t, json.NewEncoder(buffer).Encode(payload))
+	}
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(method, path, buffer)
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	a.handler.ServeHTTP(recorder, request)
+	if body != nil && recorder.Code < 300 {
+		util.OK(a.t, json.NewDecoder(recorder.Body).Decode(body))
+	}
+	return recorder.Code
+}
+
+func tenantRequestSubmission() TenantRequestSubmission {
+	submission := TenantRequestSubmission{Name: "lip6"}
+	submission.Spec.FullName = "LIP6"
+	submission.Spec.Contact.Email = "john.doe@edge-net.org"
+	submission.Spec.Approved = true
+	return submission
+}
+
+func TestTenantRequest(t *testing.T) {
+	a := newAPITest(t, "")
+	defer func() { VerificationKey = nil }()
+
+	status := RequestStatus{}
+	util.Equals(t, http.StatusCreated, a.do(http.MethodPost, "/v1/tenantrequests", tenantRequestSubmission(), &status))
+	util.Equals(t, "lip6", status.Name)
+	// Without the key, nothing verifies the email address
+	util.Equals(t, false, status.EmailVerified)
+	tenantRequest, err := a.edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), "lip6", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, false, tenantRequest.Spec.Approved)
+
+	util.Equals(t, http.StatusConflict, a.do(http.MethodPost, "/v1/tenantrequests", tenantRequestSubmission(), nil))
+	util.Equals(t, http.StatusOK, a.do(http.MethodGet, "/v1/tenantrequests/lip6?email=John.Doe@edge-net.org", nil, &status))
+	util.Equals(t, http.StatusNotFound, a.do(http.MethodGet, "/v1/tenantrequests/lip6?email=jane.doe@edge-net.org", nil, nil))
+	util.Equals(t, http.StatusNotFound, a.do(http.MethodGet, "/v1/tenantrequests/nyu?email=john.doe@edge-net.org", nil, nil))
+	util.Equals(t, http.StatusMethodNotAllowed, a.do(http.MethodGet, "/v1/tenantrequests", nil, nil))
+
+	t.Run("invalid", func(t *testing.T) {
+		submission := tenantRequestSubmission()
+		submission.Name = "nyu"
+		submission.Spec.Contact.Email = "john.doe"
+		util.Equals(t, http.StatusBadRequest, a.do(http.MethodPost, "/v1/tenantrequests", submission, nil))
+		util.Equals(t, http.StatusBadRequest, a.do(http.MethodPost, "/v1/tenantrequests", map[string]string{"name": "nyu", "approved": "true"}, nil))
+	})
+}
+
+func TestErrorMessages(t *testing.T) {
+	a := newAPITest(t, "verification-key")
+	defer func() { VerificationKey = nil }()
+	a.edgenetclientset.PrependReactor("create", "tenantrequests", func(action ktesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("etcd cluster 10.0.0.4:2379 is unavailable")
+	})
+
+	recorder := httptest.NewRecorder()
+	buffer := &bytes.Buffer{}
+	util.OK(t, json.NewEncoder(buffer).Encode(tenantRequestSubmission()))
+	a.handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/v1/tenantrequests", buffer))
+	util.Equals(t, http.StatusInternalServerError, recorder.Code)
+	util.Equals(t, "Internal Server Error\n", recorder.Body.String())
+}
+
+func TestEmailVerification(t *testing.T) {
+	a := newAPITest(t, "verification-key")
+	defer func() { VerificationKey = nil }()
+	at := time.Date(2021, 10, 1, 9, 0, 0, 0, time.UTC)
+	now = func() time.Time { return at }
+	defer func() { now = time.Now }()
+
+	status := RequestStatus{}
+	util.Equals(t, http.StatusCreated, a.do(http.MethodPost, "/v1/tenantrequests", tenantRequestSubmission(), &status))
+	util.Equals(t, false, status.EmailVerified)
+	tenantRequest, err := a.edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), "lip6", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, false, access.EmailVerified(tenantRequest))
+
+	code := newVerificationCode(VerificationKey, "/tenantrequests/lip6", "john.doe@edge-net.org", at.Add(VerificationPeriod))
+	forged := newVerificationCode([]byte("other-key"), "/tenantrequests/lip6", "john.doe@edge-net.org", at.Add(VerificationPeriod))
+	util.Equals(t, http.StatusForbidden, a.do(http.MethodPost, "/v1/tenantrequests/lip6/verification", Verification{Code: forged}, nil))
+	util.Equals(t, http.StatusOK, a.do(http.MethodPost, "/v1/tenantrequests/lip6/verification", Verification{Code: code}, &status))
+	util.Equals(t, true, status.EmailVerified)
+	tenantRequest, err = a.edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), "lip6", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, true, access.EmailVerified(tenantRequest))
+
+	t.Run("role request", func(t *testing.T) {
+		submission := RoleRequestSubmission{Name: "johndoe", Spec: registrationv1alpha.RoleRequestSpec{Email: "john.doe@edge-net.org", Approved: true}}
+		util.Equals(t, http.StatusCreated, a.do(http.MethodPost, "/v1/namespaces/lip6/rolerequests", submission, &status))
+		util.Equals(t, "lip6", status.Namespace)
+		util.Equals(t, false, status.EmailVerified)
+
+		// The code of the tenant request does not verify the role request
+		util.Equals(t, http.StatusForbidden, a.do(http.MethodPost, "/v1/namespaces/lip6/rolerequests/johndoe/verification", Verification{Code: code}, nil))
+		code := newVerificationCode(VerificationKey, "/namespaces/lip6/rolerequests/johndoe", "john.doe@edge-net.org", at.Add(VerificationPeriod))
+		now = func() time.Time { return at.Add(VerificationPeriod + time.Second) }
+		util.Equals(t, http.StatusForbidden, a.do(http.MethodPost, "/v1/namespaces/lip6/rolerequests/johndoe/verification", Verification{Code: code}, nil))
+		now = func() time.Time { return at }
+		util.Equals(t, http.StatusOK, a.do(http.MethodPost, "/v1/namespaces/lip6/rolerequests/johndoe/verification", Verification{Code: code}, &status))
+		util.Equals(t, true, status.EmailVerified)
+
+		roleRequest, err := a.edgenetclientset.RegistrationV1alpha().RoleRequests("lip6").Get(context.TODO(), "johndoe", metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, false, roleRequest.Spec.Approved)
+		util.Equals(t, http.StatusOK, a.do(http.MethodGet, "/v1/namespaces/lip6/rolerequests/johndoe?email=john.doe@edge-net.org", nil, &status))
+		util.Equals(t, http.StatusNotFound, a.do(http.MethodGet, "/v1/namespaces/lip6/tenantrequests/johndoe", nil, nil))
+	})
+}
+
+func TestVerdicts(t *testing.T) {
+	a := newAPITest(t, "")
+	defer func() { VerificationKey = nil }()
+	// The tokens are the names of their users, only the administrator may update the requests
+	a.kubeclientset.PrependReactor("create", "tokenreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		review.Status.Authenticated = review.Spec.Token != "expired"
+		review.Status.User.Username = review.Spec.Token
+		return true, review, nil
+	})
+	a.kubeclientset.PrependReactor("create", "subjectaccessreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		review.Status.Allowed = review.Spec.User == "admin" && review.Spec.ResourceAttributes.Verb == "update"
+		return true, review, nil
+	})
+	util.Equals(t, http.StatusCreated, a.do(http.MethodPost, "/v1/tenantrequests", tenantRequestSubmission(), nil))
+
+	util.Equals(t, http.StatusUnauthorized, a.do(http.MethodPost, "/v1/tenantrequests/lip6/approval", nil, nil))
+	util.Equals(t, http.StatusUnauthorized, a.doWithToken(http.MethodPost, "/v1/tenantrequests/lip6/approval", "expired", nil, nil))
+	util.Equals(t, http.StatusForbidden, a.doWithToken(http.MethodPost, "/v1/tenantrequests/lip6/approval", "john.doe@edge-net.org", nil, nil))
+	util.Equals(t, http.StatusNotFound, a.doWithToken(http.MethodPost, "/v1/tenantrequests/nyu/approval", "admin", nil, nil))
+	util.Equals(t, http.StatusOK, a.doWithToken(http.MethodPost, "/v1/tenantrequests/lip6/approval", "admin", nil, nil))
+	util.Equals(t, http.StatusConflict, a.doWithToken(http.MethodPost, "/v1/tenantrequests/lip6/approval", "admin", nil, nil))
+	util.Equals(t, http.StatusOK, a.doWithToken(http.MethodPost, "/v1/tenantrequests/lip6/rejection", "admin", nil, nil))
+
+	tenantRequest, err := a.edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), "lip6", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, 2, len(tenantRequest.Spec.Approvals))
+	util.Equals(t, "admin", tenantRequest.Spec.Approvals[0].Approver)
+	util.Equals(t, "Approve", tenantRequest.Spec.Approvals[0].Verdict)
+	util.Equals(t, "Reject", tenantRequest.Spec.Approvals[1].Verdict)
+}
+
+func TestExternalVerdicts(t *testing.T) {
+	a := newAPITest(t, "")
+	defer func() { VerificationKey = nil }()
+	callback := func(c approval.Callback) int {
+		body, err := json.Marshal(c)
+		util.OK(t, err)
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/v1/approvals", bytes.NewReader(body))
+		approval.Sign(request.Header, []byte("approval-key"), body)
+		a.handler.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+	tenantRequest := &registrationv1alpha.TenantRequest{ObjectMeta: metav1.ObjectMeta{Name: "lip6", UID: "tenant-request-uid"}}
+	_, err := a.edgenetclientset.RegistrationV1alpha().TenantRequests().Create(context.TODO(), tenantRequest, metav1.CreateOptions{})
+	util.OK(t, err)
+	roleRequest := &registrationv1alpha.RoleRequest{ObjectMeta: metav1.ObjectMeta{Name: "johndoe", Namespace: "lip6", UID: "role-request-uid"}}
+	_, err = a.edgenetclientset.RegistrationV1alpha().RoleRequests("lip6").Create(context.TODO(), roleRequest, metav1.CreateOptions{})
+	util.OK(t, err)
+	approve := approval.Callback{Kind: approval.KindTenantRequest, Name: "lip6", UID: "tenant-request-uid", Verdict: approval.VerdictApprove, Approver: "ticket-42", Nonce: "1"}
+
+	t.Run("disabled", func(t *testing.T) {
+		util.Equals(t, http.StatusNotFound, callback(approve))
+	})
+	approval.Key = []byte("approval-key")
+	defer func() { approval.Key = nil }()
+	t.Run("tenant request", func(t *testing.T) {
+		util.Equals(t, http.StatusOK, callback(approve))
+		util.Equals(t, http.StatusUnauthorized, callback(approve))
+		tenantRequest, err := a.edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), "lip6", metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, 1, len(tenantRequest.Spec.Approvals))
+		util.Equals(t, "ticket-42", tenantRequest.Spec.Approvals[0].Approver)
+		approve.Nonce = "2"
+		util.Equals(t, http.StatusConflict, callback(approve))
+	})
+	t.Run("recreated", func(t *testing.T) {
+		approve.Nonce, approve.UID = "3", "previous-uid"
+		util.Equals(t, http.StatusNotFound, callback(approve))
+	})
+	t.Run("forged", func(t *testing.T) {
+		body, _ := json.Marshal(approval.Callback{Kind: approval.KindRoleRequest, Namespace: "lip6", Name: "johndoe", UID: "role-request-uid",
+			Verdict: approval.VerdictApprove, Approver: "ticket-43", Nonce: "4"})
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodPost, "/v1/approvals", bytes.NewReader(body))
+		approval.Sign(request.Header, []byte("another-key"), body)
+		a.handler.ServeHTTP(recorder, request)
+		util.Equals(t, http.StatusUnauthorized, recorder.Code)
+	})
+	t.Run("role request", func(t *testing.T) {
+		util.Equals(t, http.StatusOK, callback(approval.Callback{Kind: approval.KindRoleRequest, Namespace: "lip6", Name: "johndoe",
+			UID: "role-request-uid", Verdict: approval.VerdictReject, Approver: "ticket-44", Nonce: "5"}))
+		roleRequest, err := a.edgenetclientset.RegistrationV1alpha().RoleRequests("lip6").Get(context.TODO(), "johndoe", metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, false, roleRequest.Spec.Approved)
+		util.Equals(t, "Rejected", roleRequest.Status.State)
+		util.Equals(t, http.StatusConflict, callback(approval.Callback{Kind: approval.KindRoleRequest, Namespace: "lip6", Name: "johndoe",
+			UID: "role-request-uid", Verdict: approval.VerdictApprove, Approver: "ticket-45", Nonce: "6"}))
+	})
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"net/http"
+
+	"github.com/EdgeNet-project/edgenet/pkg/purge"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// TenantDeletion is the reply to the deletion of a tenant
+type TenantDeletion struct {
+	Name  string `json:"name"`
+	Purge bool   `json:"purge"`
+	// Report is the name of the config map in the edgenet namespace that will hold the signed
+	// deletion report once the tenant is purged
+	Report string `json:"report,omitempty"`
+}
+
+// deleteTenant deletes the tenant on behalf of the administrator, who must be allowed to delete
+// it. With purge=true, the tenant is annotated and held by the purge finalizer before it is
+// deleted, so that the tenant controller removes its personal data and emits the deletion report
+// before the tenant goes away.
+func (s *server) deleteTenant(w http.ResponseWriter, r *http.Request, name string) {
+	administrator, status := s.authorize(r, authorizationv1.ResourceAttributes{
+		Verb:     "delete",
+		Group:    "core.edgenet.io",
+		Resource: "tenants",
+		Name:     name,
+	})
+	if status != http.StatusOK {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	deletion := TenantDeletion{Name: name, Purge: r.URL.Query().Get("purge") == "true"}
+	tenants := s.edgenetclientset.CoreV1alpha().Tenants()
+	tenant, err := tenants.Get(r.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if deletion.Purge {
+		tenantCopy := tenant.DeepCopy()
+		annotations := tenantCopy.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[purge.Annotation] = "true"
+		tenantCopy.SetAnnotations(annotations)
+		if !purge.Pending(tenantCopy) {
+			tenantCopy.SetFinalizers(append(tenantCopy.GetFinalizers(), purge.Finalizer))
+		}
+		if _, err := tenants.Update(r.Context(), tenantCopy, metav1.UpdateOptions{}); err != nil {
+			writeError(w, err)
+			return
+		}
+		deletion.Report = purge.ReportName(name, string(tenant.GetUID()))
+	}
+	// The precondition keeps a tenant recreated in the meantime from being deleted
+	uid := tenant.GetUID()
+	if err := tenants.Delete(r.Context(), name, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &uid}}); err != nil {
+		writeError(w, err)
+		return
+	}
+	klog.InfoS("Deleted the tenant", "tenant", name, "purge", deletion.Purge, "administrator", administrator)
+	writeJSON(w, http.StatusAccepted, deletion)
+}
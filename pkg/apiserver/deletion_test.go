@@ -0,0 +1,68 @@
+package apiserver
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/purge"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func TestDeleteTenant(t *testing.T) {
+	a := newAPITest(t, "")
+	// The tokens are the names of their users, only the administrator may delete the tenants
+	a.kubeclientset.PrependReactor("create", "tokenreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		review.Status.Authenticated = true
+		review.Status.User.Username = review.Spec.Token
+		return true, review, nil
+	})
+	a.kubeclientset.PrependReactor("create", "subjectaccessreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = review.Spec.User == "admin" && attributes.Verb == "delete" && attributes.Resource == "tenants"
+		return true, review, nil
+	})
+	// The fake clientset deletes the tenants regardless of their finalizers, the update is kept
+	var updated *corev1alpha.Tenant
+	a.edgenetclientset.PrependReactor("update", "tenants", func(action ktesting.Action) (bool, runtime.Object, error) {
+		updated = action.(ktesting.UpdateAction).GetObject().(*corev1alpha.Tenant).DeepCopy()
+		return false, nil, nil
+	})
+	for _, name := range []string{"lip6", "nyu"} {
+		tenant := &corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: name, UID: "ab9bc5d2-5b1d-4b5e-9b1e-6a8c1a2b3c4d"}}
+		_, err := a.edgenetclientset.CoreV1alpha().Tenants().Create(context.TODO(), tenant, metav1.CreateOptions{})
+		util.OK(t, err)
+	}
+
+	util.Equals(t, http.StatusUnauthorized, a.do(http.MethodDelete, "/v1/tenants/lip6", nil, nil))
+	util.Equals(t, http.StatusForbidden, a.doWithToken(http.MethodDelete, "/v1/tenants/lip6", "john.doe@edge-net.org", nil, nil))
+	util.Equals(t, http.StatusNotFound, a.doWithToken(http.MethodDelete, "/v1/tenants/ucl", "admin", nil, nil))
+	util.Equals(t, http.StatusNotFound, a.doWithToken(http.MethodGet, "/v1/tenants/lip6", "admin", nil, nil))
+
+	t.Run("delete", func(t *testing.T) {
+		deletion := TenantDeletion{}
+		util.Equals(t, http.StatusAccepted, a.doWithToken(http.MethodDelete, "/v1/tenants/nyu", "admin", nil, &deletion))
+		util.Equals(t, TenantDeletion{Name: "nyu"}, deletion)
+		util.Assert(t, updated == nil, "the tenant was updated without purge")
+		_, err := a.edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), "nyu", metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+	})
+	t.Run("purge", func(t *testing.T) {
+		deletion := TenantDeletion{}
+		util.Equals(t, http.StatusAccepted, a.doWithToken(http.MethodDelete, "/v1/tenants/lip6?purge=true", "admin", nil, &deletion))
+		util.Equals(t, TenantDeletion{Name: "lip6", Purge: true, Report: "deletion-report-lip6-ab9bc5d2"}, deletion)
+		util.Assert(t, updated != nil, "the tenant was not updated")
+		util.Equals(t, true, purge.Requested(updated))
+		util.Equals(t, true, purge.Pending(updated))
+	})
+}
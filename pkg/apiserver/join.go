@@ -0,0 +1,95 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/EdgeNet-project/edgenet/pkg/node"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// joinCommand is replaced in the tests, the bootstrap tokens need the certificate authority of
+// the cluster
+var joinCommand = node.JoinCommand
+
+// JoinTokenExchange is the body the bootstrap script posts to get the join command of the node
+type JoinTokenExchange struct {
+	Token string `json:"token"`
+}
+
+// JoinCommand is the reply with the command the bootstrap script runs on the node
+type JoinCommand struct {
+	NodeName string `json:"nodeName"`
+	Command  string `json:"command"`
+}
+
+// exchangeJoinToken exchanges the one-time join token of a node contribution for a kubeadm join
+// command with a short-lived bootstrap token. The token is marked used before the command is
+// made, so that two exchanges racing for it cannot both succeed.
+func (s *server) exchangeJoinToken(w http.ResponseWriter, r *http.Request) {
+	exchange := JoinTokenExchange{}
+	if err := decode(w, r, &exchange); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// The token starts with the name of its node contribution, whose name may have dots
+	separator := strings.LastIndex(exchange.Token, ".")
+	if separator <= 0 {
+		http.Error(w, "invalid token", http.StatusForbidden)
+		return
+	}
+	name := exchange.Token[:separator]
+	nodecontribution, err := s.edgenetclientset.CoreV1alpha().NodeContributions().Get(r.Context(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		// The others cannot tell whether the node contribution exists
+		http.Error(w, "invalid token", http.StatusForbidden)
+		return
+	} else if err != nil {
+		writeError(w, err)
+		return
+	}
+	if err := node.CheckJoinToken(nodecontribution, exchange.Token, now()); err != nil {
+		klog.V(4).InfoS("Refused the join token", "nodeContribution", name, "reason", err.Error())
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	used := metav1.NewTime(now())
+	nodecontribution.Status.Join.Used = &used
+	if _, err := s.edgenetclientset.CoreV1alpha().NodeContributions().UpdateStatus(r.Context(), nodecontribution, metav1.UpdateOptions{}); err != nil {
+		if apierrors.IsConflict(err) {
+			http.Error(w, "token already used", http.StatusForbidden)
+			return
+		}
+		writeError(w, err)
+		return
+	}
+	nodeName := fmt.Sprintf("%s.edge-net.io", name)
+	command, err := joinCommand(r.Context(), s.kubeclientset, nodeName)
+	if err != nil {
+		// The node contribution issues a new token once this one expires
+		klog.ErrorS(err, "Couldn't create the join command", "nodeContribution", name)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, JoinCommand{NodeName: nodeName, Command: command})
+}
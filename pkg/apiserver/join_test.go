@@ -0,0 +1,51 @@
+package apiserver
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/node"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func TestJoinTokenExchange(t *testing.T) {
+	a := newAPITest(t, "")
+	joinCommand = func(ctx context.Context, clientset kubernetes.Interface, nodeName string) (string, error) {
+		return "kubeadm join 192.0.2.1:6443 --token abcdef.0123456789abcdef --node-name " + nodeName, nil
+	}
+	defer func() { joinCommand = node.JoinCommand }()
+
+	newContribution := func(name string, expires time.Time) string {
+		token, hash, err := node.NewJoinToken(name)
+		util.OK(t, err)
+		nodecontribution := &corev1alpha.NodeContribution{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: corev1alpha.NodeContributionSpec{Mode: "Token"},
+			Status: corev1alpha.NodeContributionStatus{Join: &corev1alpha.JoinStatus{TokenHash: hash, Expires: metav1.NewTime(expires)}}}
+		_, err = a.edgenetclientset.CoreV1alpha().NodeContributions().Create(context.TODO(), nodecontribution, metav1.CreateOptions{})
+		util.OK(t, err)
+		return token
+	}
+	token := newContribution("ple-1", time.Now().Add(time.Hour))
+	expired := newContribution("ple-2", time.Now().Add(-time.Minute))
+
+	// The token of a node contribution does not join another
+	util.Equals(t, http.StatusForbidden, a.do(http.MethodPost, "/v1/nodecontributions/join", JoinTokenExchange{Token: "ple-2" + token[len("ple-1"):]}, nil))
+	util.Equals(t, http.StatusForbidden, a.do(http.MethodPost, "/v1/nodecontributions/join", JoinTokenExchange{Token: "ple-3.0123"}, nil))
+	util.Equals(t, http.StatusForbidden, a.do(http.MethodPost, "/v1/nodecontributions/join", JoinTokenExchange{Token: expired}, nil))
+
+	command := JoinCommand{}
+	util.Equals(t, http.StatusOK, a.do(http.MethodPost, "/v1/nodecontributions/join", JoinTokenExchange{Token: token}, &command))
+	util.Equals(t, "ple-1.edge-net.io", command.NodeName)
+	util.Equals(t, "kubeadm join 192.0.2.1:6443 --token abcdef.0123456789abcdef --node-name ple-1.edge-net.io", command.Command)
+	nodecontribution, err := a.edgenetclientset.CoreV1alpha().NodeContributions().Get(context.TODO(), "ple-1", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Assert(t, nodecontribution.Status.Join.Used != nil, "token not marked used")
+
+	// The token is only good once
+	util.Equals(t, http.StatusForbidden, a.do(http.MethodPost, "/v1/nodecontributions/join", JoinTokenExchange{Token: token}, nil))
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	appsv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/apps/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/usercert"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// Methods the credentials of a session are validated with
+const (
+	methodCertificate = "certificate"
+	methodOIDC        = "oidc"
+)
+
+// SessionDuration is how long the sessions of the web console last, no token outlives its session
+var SessionDuration = 8 * time.Hour
+
+// SessionTokenTTL is how long the tokens of the sessions are valid, the console exchanges the token
+// of a session for a new one before it expires
+var SessionTokenTTL = 15 * time.Minute
+
+// ActivationTimeout is how long the login controller has to bind the roles of the user to a session
+var ActivationTimeout = 10 * time.Second
+
+// activationInterval is how often a new session is checked while it awaits its roles
+var activationInterval = 250 * time.Millisecond
+
+// minTokenTTL is the shortest lifetime the API server issues a token for
+const minTokenTTL = 10 * time.Minute
+
+// LoginSubmission is the body posted to open a session in a tenant
+type LoginSubmission struct {
+	Tenant string `json:"tenant"`
+}
+
+// Session is the reply carrying the token of a session
+type Session struct {
+	Name                string    `json:"name"`
+	Namespace           string    `json:"namespace"`
+	User                string    `json:"user"`
+	Token               string    `json:"token"`
+	ExpirationTimestamp time.Time `json:"expirationTimestamp"`
+	Expiry              time.Time `json:"expiry"`
+}
+
+// openSession validates the credentials of the user and records the session in a login of the core
+// namespace of the tenant. The login controller binds the roles that the user holds in the tenant to
+// the service account of the session, and the reply carries a token of that service account.
+func (s *server) openSession(w http.ResponseWriter, r *http.Request) {
+	submission := LoginSubmission{}
+	if err := decode(w, r, &submission); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if submission.Tenant == "" {
+		http.Error(w, "tenant is required", http.StatusBadRequest)
+		return
+	}
+	user, method, status := s.credentials(r)
+	if status != http.StatusOK {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	expiry := metav1.NewTime(now().Add(SessionDuration))
+	login := &appsv1alpha.Login{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("login-%s", utilrand.String(5)), Namespace: submission.Tenant},
+		Spec: appsv1alpha.LoginSpec{User: user, Method: method, Expiry: &expiry}}
+	logins := s.edgenetclientset.AppsV1alpha().Logins(submission.Tenant)
+	login, err := logins.Create(r.Context(), login, metav1.CreateOptions{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	err = wait.PollImmediate(activationInterval, ActivationTimeout, func() (bool, error) {
+		current, err := logins.Get(r.Context(), login.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		login = current
+		return login.Status.State == "Active", nil
+	})
+	if err != nil {
+		// The session never holds a role, the user is told why if the controller failed
+		if err := logins.Delete(context.Background(), login.GetName(), metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Couldn't delete the login", "login", klog.KObj(login))
+		}
+		if err == wait.ErrWaitTimeout && login.Status.State == "Failure" {
+			http.Error(w, login.Status.Message, http.StatusForbidden)
+			return
+		}
+		klog.ErrorS(err, "The session wasn't activated", "login", klog.KObj(login))
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+	session, err := s.issue(r.Context(), login)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	klog.InfoS("Opened a session", "login", klog.KObj(login), "user", user, "method", method)
+	writeJSON(w, http.StatusCreated, session)
+}
+
+// refreshSession exchanges the token of a session for a new one, up to the expiry of the session
+func (s *server) refreshSession(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	login, status := s.session(r, namespace, name)
+	if status != http.StatusOK {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	if login.Status.State != "Active" || !login.Spec.Expiry.After(now()) {
+		http.Error(w, "session is not active", http.StatusForbidden)
+		return
+	}
+	session, err := s.issue(r.Context(), login)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, session)
+}
+
+// closeSession ends the session, the tokens of the session stop working as its service account goes away
+func (s *server) closeSession(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	login, status := s.session(r, namespace, name)
+	if status != http.StatusOK {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	uid := login.GetUID()
+	if err := s.edgenetclientset.AppsV1alpha().Logins(namespace).Delete(r.Context(), name, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &uid}}); err != nil {
+		writeError(w, err)
+		return
+	}
+	klog.InfoS("Closed a session", "login", klog.KObj(login), "user", login.Spec.User)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// session returns the login of the session whose token the request bears, together with the status
+// of the reply when the token is not a token of that session
+func (s *server) session(r *http.Request, namespace, name string) (*appsv1alpha.Login, int) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || token == r.Header.Get("Authorization") {
+		return nil, http.StatusUnauthorized
+	}
+	user, err := s.authenticate(r.Context(), token)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't review the token")
+		return nil, http.StatusInternalServerError
+	} else if user == nil || user.Username != fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name) {
+		return nil, http.StatusUnauthorized
+	}
+	login, err := s.edgenetclientset.AppsV1alpha().Logins(namespace).Get(r.Context(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, http.StatusUnauthorized
+	} else if err != nil {
+		klog.ErrorS(err, "Couldn't get the login", "login", klog.KRef(namespace, name))
+		return nil, http.StatusInternalServerError
+	}
+	// A service account recreated under the name of the session is not the session
+	serviceAccount, err := s.kubeclientset.CoreV1().ServiceAccounts(namespace).Get(r.Context(), name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, http.StatusUnauthorized
+	} else if err != nil {
+		klog.ErrorS(err, "Couldn't get the service account of the session", "login", klog.KRef(namespace, name))
+		return nil, http.StatusInternalServerError
+	} else if string(serviceAccount.GetUID()) != user.UID || !metav1.IsControlledBy(serviceAccount, login) {
+		return nil, http.StatusUnauthorized
+	}
+	return login, http.StatusOK
+}
+
+// credentials returns the user opening a session and the method their credentials were validated
+// with, together with the status of the reply when they are not valid. A client certificate signed
+// by the cluster authority is preferred to the OIDC token that the request bears.
+func (s *server) credentials(r *http.Request) (string, string, int) {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+		certificate := r.TLS.VerifiedChains[0][0]
+		user := usercert.User(certificate.Subject.CommonName)
+		if user == "" || strings.HasPrefix(user, "system:") {
+			return "", "", http.StatusUnauthorized
+		}
+		// The revoked certificates are only denied by the certificate webhook otherwise
+		revocationList, err := s.kubeclientset.CoreV1().ConfigMaps(usercert.RevocationList.Namespace).Get(r.Context(), usercert.RevocationList.Name, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Couldn't get the revocation list")
+			return "", "", http.StatusInternalServerError
+		} else if err == nil {
+			if id, revoked := usercert.RevokedID(certificate.Subject.Organization, revocationList.Data); revoked {
+				klog.V(4).InfoS("Refused the session of a revoked certificate", "user", user, "certificate", id)
+				return "", "", http.StatusUnauthorized
+			}
+		}
+		return user, methodCertificate, http.StatusOK
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || token == r.Header.Get("Authorization") {
+		return "", "", http.StatusUnauthorized
+	}
+	user, err := s.authenticate(r.Context(), token)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't review the token")
+		return "", "", http.StatusInternalServerError
+	}
+	// The tokens of the service accounts, those of the sessions included, cannot open a session
+	if user == nil || strings.HasPrefix(user.Username, "system:") {
+		return "", "", http.StatusUnauthorized
+	}
+	return user.Username, methodOIDC, http.StatusOK
+}
+
+// issue requests a token of the service account of the session, which lasts until the session
+// expires at the latest. The API server has a minimum lifetime, but the service account is removed
+// as the session expires, and its tokens with it.
+func (s *server) issue(ctx context.Context, login *appsv1alpha.Login) (Session, error) {
+	ttl := login.Spec.Expiry.Sub(now())
+	if ttl > SessionTokenTTL {
+		ttl = SessionTokenTTL
+	}
+	if ttl < minTokenTTL {
+		ttl = minTokenTTL
+	}
+	expiration := int64(ttl.Seconds())
+	tokenRequest := &authenticationv1.TokenRequest{Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &expiration}}
+	tokenRequest, err := s.kubeclientset.CoreV1().ServiceAccounts(login.GetNamespace()).CreateToken(ctx, login.Status.ServiceAccount, tokenRequest, metav1.CreateOptions{})
+	if err != nil {
+		return Session{}, err
+	}
+	session := Session{Name: login.GetName(), Namespace: login.GetNamespace(), User: login.Spec.User, Token: tokenRequest.Status.Token,
+		ExpirationTimestamp: tokenRequest.Status.ExpirationTimestamp.Time, Expiry: login.Spec.Expiry.Time}
+	if session.ExpirationTimestamp.After(session.Expiry) {
+		session.ExpirationTimestamp = session.Expiry
+	}
+	return session, nil
+}
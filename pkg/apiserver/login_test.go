@@ -0,0 +1,178 @@
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/apps/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/usercert"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ktesting "k8s.io/client-go/testing"
+)
+
+// newLoginTest returns an API whose tokens are either "oidc:<user>" or the "<namespace>:<name>" of a
+// session, and whose logins are activated on creation unless their user holds no role
+func newLoginTest(t *testing.T) *apiTest {
+	a := newAPITest(t, "")
+	ActivationTimeout = 100 * time.Millisecond
+	activationInterval = 10 * time.Millisecond
+	a.kubeclientset.PrependReactor("create", "tokenreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if user := strings.TrimPrefix(review.Spec.Token, "oidc:"); user != review.Spec.Token {
+			review.Status.Authenticated = true
+			review.Status.User.Username = user
+		} else if parts := strings.Split(review.Spec.Token, ":"); len(parts) == 2 {
+			// The reactors run under the lock of the clientset, the tracker is read without it
+			obj, err := a.kubeclientset.Tracker().Get(corev1.SchemeGroupVersion.WithResource("serviceaccounts"), parts[0], parts[1])
+			if err == nil {
+				serviceAccount := obj.(*corev1.ServiceAccount)
+				review.Status.Authenticated = true
+				review.Status.User.Username = fmt.Sprintf("system:serviceaccount:%s:%s", parts[0], parts[1])
+				review.Status.User.UID = string(serviceAccount.GetUID())
+			}
+		}
+		return true, review, nil
+	})
+	a.edgenetclientset.PrependReactor("create", "logins", func(action ktesting.Action) (bool, runtime.Object, error) {
+		login := action.(ktesting.CreateAction).GetObject().(*appsv1alpha.Login)
+		login.SetUID(types.UID(fmt.Sprintf("%s-uid", login.GetName())))
+		if login.Spec.User == "nobody@edge-net.org" {
+			login.Status.State = "Failure"
+			login.Status.Message = "User nobody@edge-net.org holds no role in the tenant"
+			return false, nil, nil
+		}
+		login.Status.State = "Active"
+		login.Status.ServiceAccount = login.GetName()
+		serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: login.GetName(), Namespace: login.GetNamespace(),
+			UID:             types.UID(fmt.Sprintf("%s-sa-uid", login.GetName())),
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(login, appsv1alpha.SchemeGroupVersion.WithKind("Login"))}}}
+		_, err := a.kubeclientset.CoreV1().ServiceAccounts(login.GetNamespace()).Create(context.TODO(), serviceAccount, metav1.CreateOptions{})
+		return false, nil, err
+	})
+	a.kubeclientset.PrependReactor("create", "serviceaccounts", func(action ktesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "token" {
+			return false, nil, nil
+		}
+		tokenRequest := action.(ktesting.CreateAction).GetObject().(*authenticationv1.TokenRequest).DeepCopy()
+		tokenRequest.Status.Token = fmt.Sprintf("%s:%s", action.GetNamespace(), action.(ktesting.CreateActionImpl).Name)
+		tokenRequest.Status.ExpirationTimestamp = metav1.NewTime(now().Add(time.Duration(*tokenRequest.Spec.ExpirationSeconds) * time.Second))
+		return true, tokenRequest, nil
+	})
+	return a
+}
+
+// withCertificate serves the request made with a client certificate of the user
+func (a *apiTest) withCertificate(method, path, user string, groups []string, payload interface{}, body interface{}) int {
+	buffer := &bytes.Buffer{}
+	util.OK(a.t, json.NewEncoder(buffer).Encode(payload))
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(method, path, buffer)
+	certificate := &x509.Certificate{Subject: pkix.Name{CommonName: user, Organization: groups}}
+	request.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{certificate}}}
+	a.handler.ServeHTTP(recorder, request)
+	if body != nil && recorder.Code < 300 {
+		util.OK(a.t, json.NewDecoder(recorder.Body).Decode(body))
+	}
+	return recorder.Code
+}
+
+func TestOpenSession(t *testing.T) {
+	a := newLoginTest(t)
+	submission := LoginSubmission{Tenant: "lip6"}
+
+	util.Equals(t, http.StatusUnauthorized, a.do(http.MethodPost, "/v1/logins", submission, nil))
+	util.Equals(t, http.StatusBadRequest, a.doWithToken(http.MethodPost, "/v1/logins", "oidc:john.doe@edge-net.org", LoginSubmission{}, nil))
+	util.Equals(t, http.StatusUnauthorized, a.doWithToken(http.MethodPost, "/v1/logins", "forged", submission, nil))
+	util.Equals(t, http.StatusUnauthorized, a.doWithToken(http.MethodPost, "/v1/logins", "oidc:system:serviceaccount:lip6:robot", submission, nil))
+	util.Equals(t, http.StatusMethodNotAllowed, a.doWithToken(http.MethodGet, "/v1/logins", "oidc:john.doe@edge-net.org", nil, nil))
+
+	t.Run("oidc", func(t *testing.T) {
+		session := Session{}
+		util.Equals(t, http.StatusCreated, a.doWithToken(http.MethodPost, "/v1/logins", "oidc:john.doe@edge-net.org", submission, &session))
+		util.Equals(t, "lip6", session.Namespace)
+		util.Equals(t, "john.doe@edge-net.org", session.User)
+		util.Equals(t, fmt.Sprintf("lip6:%s", session.Name), session.Token)
+		util.Assert(t, session.ExpirationTimestamp.Sub(now()) <= SessionTokenTTL, "token expires at %s", session.ExpirationTimestamp)
+		util.Assert(t, session.Expiry.Sub(now()) > SessionDuration-time.Minute, "session expires at %s", session.Expiry)
+		login, err := a.edgenetclientset.AppsV1alpha().Logins("lip6").Get(context.TODO(), session.Name, metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, "oidc", login.Spec.Method)
+		util.Equals(t, "john.doe@edge-net.org", login.Spec.User)
+		// The token of a session cannot open another one
+		util.Equals(t, http.StatusUnauthorized, a.doWithToken(http.MethodPost, "/v1/logins", session.Token, submission, nil))
+	})
+	t.Run("certificate", func(t *testing.T) {
+		session := Session{}
+		util.Equals(t, http.StatusCreated, a.withCertificate(http.MethodPost, "/v1/logins", usercert.UserPrefix+"jane.doe@edge-net.org", []string{usercert.GroupPrefix + "0a1b2c3d"}, submission, &session))
+		util.Equals(t, "jane.doe@edge-net.org", session.User)
+		login, err := a.edgenetclientset.AppsV1alpha().Logins("lip6").Get(context.TODO(), session.Name, metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, "certificate", login.Spec.Method)
+
+		revocationList := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: usercert.RevocationList.Name, Namespace: usercert.RevocationList.Namespace},
+			Data: map[string]string{"0a1b2c3d": "jane.doe@edge-net.org"}}
+		_, err = a.kubeclientset.CoreV1().ConfigMaps(usercert.RevocationList.Namespace).Create(context.TODO(), revocationList, metav1.CreateOptions{})
+		util.OK(t, err)
+		util.Equals(t, http.StatusUnauthorized, a.withCertificate(http.MethodPost, "/v1/logins", usercert.UserPrefix+"jane.doe@edge-net.org", []string{usercert.GroupPrefix + "0a1b2c3d"}, submission, nil))
+		util.Equals(t, http.StatusUnauthorized, a.withCertificate(http.MethodPost, "/v1/logins", "system:admin", nil, submission, nil))
+		util.Equals(t, http.StatusUnauthorized, a.withCertificate(http.MethodPost, "/v1/logins", usercert.UserPrefix+"system:admin", nil, submission, nil))
+	})
+	t.Run("no role", func(t *testing.T) {
+		util.Equals(t, http.StatusForbidden, a.doWithToken(http.MethodPost, "/v1/logins", "oidc:nobody@edge-net.org", submission, nil))
+		logins, err := a.edgenetclientset.AppsV1alpha().Logins("lip6").List(context.TODO(), metav1.ListOptions{})
+		util.OK(t, err)
+		for _, login := range logins.Items {
+			util.Assert(t, login.Spec.User != "nobody@edge-net.org", "login %s kept", login.GetName())
+		}
+	})
+}
+
+func TestSession(t *testing.T) {
+	a := newLoginTest(t)
+	session, other := Session{}, Session{}
+	util.Equals(t, http.StatusCreated, a.doWithToken(http.MethodPost, "/v1/logins", "oidc:john.doe@edge-net.org", LoginSubmission{Tenant: "lip6"}, &session))
+	util.Equals(t, http.StatusCreated, a.doWithToken(http.MethodPost, "/v1/logins", "oidc:jane.doe@edge-net.org", LoginSubmission{Tenant: "lip6"}, &other))
+	path := fmt.Sprintf("/v1/logins/lip6/%s", session.Name)
+
+	t.Run("refresh", func(t *testing.T) {
+		refreshed := Session{}
+		util.Equals(t, http.StatusOK, a.doWithToken(http.MethodPost, path+"/token", session.Token, nil, &refreshed))
+		util.Equals(t, session.Name, refreshed.Name)
+		util.Equals(t, session.Expiry.Unix(), refreshed.Expiry.Unix())
+		// Only the session itself can refresh its token
+		util.Equals(t, http.StatusUnauthorized, a.doWithToken(http.MethodPost, path+"/token", other.Token, nil, nil))
+		util.Equals(t, http.StatusUnauthorized, a.doWithToken(http.MethodPost, path+"/token", "oidc:john.doe@edge-net.org", nil, nil))
+	})
+	t.Run("expired", func(t *testing.T) {
+		login, err := a.edgenetclientset.AppsV1alpha().Logins("lip6").Get(context.TODO(), other.Name, metav1.GetOptions{})
+		util.OK(t, err)
+		login.Spec.Expiry = &metav1.Time{Time: now().Add(-time.Minute)}
+		_, err = a.edgenetclientset.AppsV1alpha().Logins("lip6").Update(context.TODO(), login, metav1.UpdateOptions{})
+		util.OK(t, err)
+		util.Equals(t, http.StatusForbidden, a.doWithToken(http.MethodPost, fmt.Sprintf("/v1/logins/lip6/%s/token", other.Name), other.Token, nil, nil))
+	})
+	t.Run("close", func(t *testing.T) {
+		util.Equals(t, http.StatusUnauthorized, a.doWithToken(http.MethodDelete, path, other.Token, nil, nil))
+		util.Equals(t, http.StatusNoContent, a.doWithToken(http.MethodDelete, path, session.Token, nil, nil))
+		_, err := a.edgenetclientset.AppsV1alpha().Logins("lip6").Get(context.TODO(), session.Name, metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+		util.Equals(t, http.StatusUnauthorized, a.doWithToken(http.MethodPost, path+"/token", session.Token, nil, nil))
+	})
+}
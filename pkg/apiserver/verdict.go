@@ -0,0 +1,171 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/approval"
+	registrationclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/typed/registration/v1alpha"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// decideTenantRequest records the verdict of the administrator on the tenant request. The
+// administrator is authenticated by their bearer token, and must be allowed to update the request.
+func (s *server) decideTenantRequest(w http.ResponseWriter, r *http.Request, name, verdict string) {
+	approver, status := s.authorize(r, authorizationv1.ResourceAttributes{
+		Verb:     "update",
+		Group:    "registration.edgenet.io",
+		Resource: "tenantrequests",
+		Name:     name,
+	})
+	if status != http.StatusOK {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	tenantRequests := s.edgenetclientset.RegistrationV1alpha().TenantRequests()
+	decide := tenantRequests.Approve
+	if verdict == registrationclient.VerdictReject {
+		decide = tenantRequests.Reject
+	}
+	tenantRequest, err := decide(r.Context(), name, approver)
+	if errors.Is(err, registrationclient.ErrDecided) {
+		http.Error(w, "request already decided", http.StatusConflict)
+		return
+	} else if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, tenantRequestStatus(tenantRequest))
+}
+
+// externalVerdict records the verdict that the external approval system calls back with, once its
+// signature and nonce are verified. The request must still be the one that was forwarded.
+func (s *server) externalVerdict(w http.ResponseWriter, r *http.Request) {
+	if len(approval.Key) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "invalid callback", http.StatusBadRequest)
+		return
+	}
+	callback, err := approval.ParseCallback(r.Header, body, s.replay)
+	if err != nil {
+		klog.InfoS("Refused the callback of the external approval system", "reason", err.Error())
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	var decided interface{}
+	switch callback.Kind {
+	case approval.KindTenantRequest:
+		tenantRequests := s.edgenetclientset.RegistrationV1alpha().TenantRequests()
+		var tenantRequest *registrationv1alpha.TenantRequest
+		if tenantRequest, err = tenantRequests.Get(r.Context(), callback.Name, metav1.GetOptions{}); err == nil {
+			if tenantRequest.GetUID() != callback.UID {
+				http.Error(w, "request not found", http.StatusNotFound)
+				return
+			}
+			decide := tenantRequests.Approve
+			if callback.Verdict == approval.VerdictReject {
+				decide = tenantRequests.Reject
+			}
+			if tenantRequest, err = decide(r.Context(), callback.Name, callback.Approver); err == nil {
+				decided = tenantRequestStatus(tenantRequest)
+			}
+		}
+	case approval.KindRoleRequest:
+		roleRequests := s.edgenetclientset.RegistrationV1alpha().RoleRequests(callback.Namespace)
+		var roleRequest *registrationv1alpha.RoleRequest
+		if roleRequest, err = roleRequests.Get(r.Context(), callback.Name, metav1.GetOptions{}); err == nil {
+			if roleRequest.GetUID() != callback.UID {
+				http.Error(w, "request not found", http.StatusNotFound)
+				return
+			}
+			decide := roleRequests.Approve
+			if callback.Verdict == approval.VerdictReject {
+				decide = roleRequests.Reject
+			}
+			if roleRequest, err = decide(r.Context(), callback.Name, callback.Approver); err == nil {
+				decided = roleRequestStatus(roleRequest)
+			}
+		}
+	}
+	if errors.Is(err, registrationclient.ErrDecided) {
+		http.Error(w, "request already decided", http.StatusConflict)
+		return
+	} else if err != nil {
+		writeError(w, err)
+		return
+	}
+	klog.InfoS("Recorded the verdict of the external approval system", "kind", callback.Kind, "request", klog.KRef(callback.Namespace, callback.Name),
+		"verdict", callback.Verdict, "approver", callback.Approver)
+	writeJSON(w, http.StatusOK, decided)
+}
+
+// authorize returns the name of the user of the bearer token, together with the status of the reply
+// when the user is not allowed to take the action on the resource
+func (s *server) authorize(r *http.Request, attributes authorizationv1.ResourceAttributes) (string, int) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" || token == r.Header.Get("Authorization") {
+		return "", http.StatusUnauthorized
+	}
+	user, err := s.authenticate(r.Context(), token)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't review the token")
+		return "", http.StatusInternalServerError
+	} else if user == nil {
+		return "", http.StatusUnauthorized
+	}
+	review := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+		User:               user.Username,
+		Groups:             user.Groups,
+		UID:                user.UID,
+		ResourceAttributes: &attributes,
+	}}
+	review, err = s.kubeclientset.AuthorizationV1().SubjectAccessReviews().Create(r.Context(), review, metav1.CreateOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Couldn't review the access of the administrator", "user", user.Username, "resource", attributes.Resource)
+		return "", http.StatusInternalServerError
+	} else if !review.Status.Allowed {
+		return "", http.StatusForbidden
+	}
+	return user.Username, http.StatusOK
+}
+
+// authenticate returns the user of the token, nil if the token is not valid
+func (s *server) authenticate(ctx context.Context, token string) (*authenticationv1.UserInfo, error) {
+	review := &authenticationv1.TokenReview{Spec: authenticationv1.TokenReviewSpec{Token: token}}
+	review, err := s.kubeclientset.AuthenticationV1().TokenReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if !review.Status.Authenticated {
+		return nil, nil
+	}
+	return &review.Status.User, nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VerificationKey signs the email verification codes, the email addresses are not verified if it is empty
+var VerificationKey []byte
+
+// VerificationPeriod is how long a verification code is valid, the approval timeout of the requests
+var VerificationPeriod = 72 * time.Hour
+
+// newVerificationCode returns a code that verifies the email address given in the request until it expires,
+// the request is the path of its resource
+func newVerificationCode(key []byte, request, email string, expiry time.Time) string {
+	expires := strconv.FormatInt(expiry.Unix(), 10)
+	return fmt.Sprintf("%s.%s", expires, signCode(key, request, strings.ToLower(email), expires))
+}
+
+// verifyCode makes sure the code was issued for the email address given in the request and has not expired
+func verifyCode(key []byte, request, email, code string, now time.Time) error {
+	parts := strings.SplitN(code, ".", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("malformed verification code")
+	}
+	expires, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("malformed verification code")
+	}
+	if !hmac.Equal([]byte(parts[1]), []byte(signCode(key, request, strings.ToLower(email), parts[0]))) {
+		return fmt.Errorf("invalid verification code")
+	}
+	if now.After(time.Unix(expires, 0)) {
+		return fmt.Errorf("verification code expired")
+	}
+	return nil
+}
+
+func signCode(key []byte, request, email, expires string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(fmt.Sprintf("%s\n%s\n%s", request, email, expires)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
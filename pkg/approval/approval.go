@@ -0,0 +1,246 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package approval lets an external approval system, such as a ticketing system, decide the tenant
+// and role requests. The controllers post the requests awaiting an approval to its webhook, and it
+// calls the registration API back with its verdicts. Both directions are signed with HMAC-SHA256 over
+// the timestamp and the body, the callbacks are refused once they are older than the callback window
+// or if their nonce has already been seen.
+package approval
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// Headers of the signed messages
+const (
+	SignatureHeader = "X-EdgeNet-Signature"
+	TimestampHeader = "X-EdgeNet-Timestamp"
+)
+
+// Kinds of the requests the external approval system decides
+const (
+	KindTenantRequest = "TenantRequest"
+	KindRoleRequest   = "RoleRequest"
+)
+
+// Verdicts of the external approval system
+const (
+	VerdictApprove = "Approve"
+	VerdictReject  = "Reject"
+)
+
+// WebhookURL receives the requests awaiting an approval, they are not forwarded if empty
+var WebhookURL string
+
+// Key signs the forwarded requests and the callbacks
+var Key []byte
+
+// CallbackWindow is how old a callback can be, and how long its nonce is remembered
+var CallbackWindow = 5 * time.Minute
+
+// Client posts the requests to the webhook
+var Client = &http.Client{Timeout: 10 * time.Second}
+
+// Backoff of the attempts to post a request to the webhook
+var Backoff = wait.Backoff{Duration: time.Second, Factor: 2, Steps: 4}
+
+// now is replaced in the tests to sign and verify at a known time
+var now = time.Now
+
+// Request is the body posted to the webhook for a request awaiting an approval
+type Request struct {
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name"`
+	UID       types.UID `json:"uid"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"firstName,omitempty"`
+	LastName  string    `json:"lastName,omitempty"`
+	// Details of the request for the approvers, such as the requested tenant or role
+	Details map[string]string `json:"details,omitempty"`
+	Expiry  *time.Time        `json:"expiry,omitempty"`
+}
+
+// Callback is the body the external approval system posts back with its verdict. The UID binds the
+// verdict to the request it was forwarded for, a request recreated under the same name is not
+// decided by it.
+type Callback struct {
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name"`
+	UID       types.UID `json:"uid"`
+	Verdict   string    `json:"verdict"`
+	Approver  string    `json:"approver"`
+	Nonce     string    `json:"nonce"`
+}
+
+// Enabled tells whether the requests are forwarded to an external approval system
+func Enabled() bool {
+	return WebhookURL != "" && len(Key) > 0
+}
+
+// ReadKey reads the key shared with the external approval system
+func ReadKey(path string) error {
+	key, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if Key = bytes.TrimSpace(key); len(Key) == 0 {
+		return fmt.Errorf("the approval key is empty")
+	}
+	return nil
+}
+
+// Forward posts the request to the webhook, the attempts are retried with a backoff as long as the
+// webhook is unreachable or fails on its side
+func Forward(ctx context.Context, request Request) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	var lastErr error
+	err = wait.ExponentialBackoff(Backoff, func() (bool, error) {
+		post, err := http.NewRequestWithContext(ctx, http.MethodPost, WebhookURL, bytes.NewReader(body))
+		if err != nil {
+			return false, err
+		}
+		Sign(post.Header, Key, body)
+		post.Header.Set("Content-Type", "application/json")
+		response, err := Client.Do(post)
+		if err != nil {
+			lastErr = err
+			return false, ctx.Err()
+		}
+		response.Body.Close()
+		switch {
+		case response.StatusCode < 300:
+			return true, nil
+		case response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500:
+			lastErr = fmt.Errorf("webhook replied %s", response.Status)
+			return false, nil
+		default:
+			return false, fmt.Errorf("webhook refused the request: %s", response.Status)
+		}
+	})
+	if err == wait.ErrWaitTimeout && lastErr != nil {
+		return lastErr
+	}
+	return err
+}
+
+// Sign sets the timestamp and the signature of the body in the headers
+func Sign(header http.Header, key, body []byte) {
+	timestamp := strconv.FormatInt(now().Unix(), 10)
+	header.Set(TimestampHeader, timestamp)
+	header.Set(SignatureHeader, "sha256="+signature(key, timestamp, body))
+}
+
+// Verify makes sure the body is signed with the key and the signature is not older than the
+// callback window
+func Verify(header http.Header, key, body []byte) error {
+	timestamp := header.Get(TimestampHeader)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("malformed timestamp")
+	}
+	expected := "sha256=" + signature(key, timestamp, body)
+	if !hmac.Equal([]byte(header.Get(SignatureHeader)), []byte(expected)) {
+		return fmt.Errorf("invalid signature")
+	}
+	if age := now().Sub(time.Unix(seconds, 0)); age > CallbackWindow || age < -CallbackWindow {
+		return fmt.Errorf("signature out of the callback window")
+	}
+	return nil
+}
+
+func signature(key []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(timestamp + "\n"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ReplayGuard remembers the nonces of the callbacks for the callback window, the callbacks older than
+// the window are refused by their signature anyway
+type ReplayGuard struct {
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// NewReplayGuard returns a guard that has seen no nonce yet
+func NewReplayGuard() *ReplayGuard {
+	return &ReplayGuard{seen: make(map[string]time.Time)}
+}
+
+// Check records the nonce and fails if it has been seen within the callback window
+func (g *ReplayGuard) Check(nonce string) error {
+	if nonce == "" {
+		return fmt.Errorf("nonce required")
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	at := now()
+	for seenNonce, seenAt := range g.seen {
+		if at.Sub(seenAt) > CallbackWindow {
+			delete(g.seen, seenNonce)
+		}
+	}
+	if _, ok := g.seen[nonce]; ok {
+		return fmt.Errorf("callback replayed")
+	}
+	g.seen[nonce] = at
+	return nil
+}
+
+// ParseCallback verifies the signature and the nonce of the callback and decodes it
+func ParseCallback(header http.Header, body []byte, guard *ReplayGuard) (*Callback, error) {
+	if err := Verify(header, Key, body); err != nil {
+		return nil, err
+	}
+	callback := &Callback{}
+	if err := json.Unmarshal(body, callback); err != nil {
+		return nil, fmt.Errorf("malformed callback")
+	}
+	if err := guard.Check(callback.Nonce); err != nil {
+		return nil, err
+	}
+	switch {
+	case callback.Kind != KindTenantRequest && callback.Kind != KindRoleRequest:
+		return nil, fmt.Errorf("unknown kind %q", callback.Kind)
+	case callback.Verdict != VerdictApprove && callback.Verdict != VerdictReject:
+		return nil, fmt.Errorf("unknown verdict %q", callback.Verdict)
+	case callback.Name == "" || callback.UID == "" || callback.Approver == "":
+		return nil, fmt.Errorf("the name, the UID, and the approver are required")
+	case callback.Kind == KindRoleRequest && callback.Namespace == "":
+		return nil, fmt.Errorf("the namespace of the role request is required")
+	}
+	return callback, nil
+}
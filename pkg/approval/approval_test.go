@@ -0,0 +1,134 @@
+package approval
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestSignature(t *testing.T) {
+	at := time.Date(2021, 10, 1, 9, 0, 0, 0, time.UTC)
+	now = func() time.Time { return at }
+	defer func() { now = time.Now }()
+
+	body := []byte(`{"kind":"TenantRequest","name":"lip6"}`)
+	header := http.Header{}
+	Sign(header, []byte("key"), body)
+	util.OK(t, Verify(header, []byte("key"), body))
+	util.Assert(t, Verify(header, []byte("other-key"), body) != nil, "signature of another key accepted")
+	util.Assert(t, Verify(header, []byte("key"), []byte(`{"kind":"TenantRequest","name":"nyu"}`)) != nil, "altered body accepted")
+
+	now = func() time.Time { return at.Add(CallbackWindow + time.Second) }
+	util.Assert(t, Verify(header, []byte("key"), body) != nil, "outdated signature accepted")
+	header.Set(TimestampHeader, "yesterday")
+	util.Assert(t, Verify(header, []byte("key"), body) != nil, "malformed timestamp accepted")
+}
+
+func TestReplayGuard(t *testing.T) {
+	at := time.Date(2021, 10, 1, 9, 0, 0, 0, time.UTC)
+	now = func() time.Time { return at }
+	defer func() { now = time.Now }()
+
+	guard := NewReplayGuard()
+	util.OK(t, guard.Check("a1"))
+	util.Assert(t, guard.Check("a1") != nil, "replayed nonce accepted")
+	util.OK(t, guard.Check("b2"))
+	util.Assert(t, guard.Check("") != nil, "empty nonce accepted")
+	// The nonces are forgotten once the signatures they come with are outdated
+	now = func() time.Time { return at.Add(CallbackWindow + time.Second) }
+	util.OK(t, guard.Check("a1"))
+	util.Equals(t, 1, len(guard.seen))
+}
+
+func TestParseCallback(t *testing.T) {
+	Key = []byte("key")
+	defer func() { Key = nil }()
+	guard := NewReplayGuard()
+
+	cases := map[string]struct {
+		callback Callback
+		valid    bool
+	}{
+		"tenant request":  {Callback{Kind: KindTenantRequest, Name: "lip6", UID: "uid", Verdict: VerdictApprove, Approver: "ticket-42", Nonce: "1"}, true},
+		"role request":    {Callback{Kind: KindRoleRequest, Namespace: "lip6", Name: "johndoe", UID: "uid", Verdict: VerdictReject, Approver: "ticket-43", Nonce: "2"}, true},
+		"no namespace":    {Callback{Kind: KindRoleRequest, Name: "johndoe", UID: "uid", Verdict: VerdictReject, Approver: "ticket-44", Nonce: "3"}, false},
+		"unknown kind":    {Callback{Kind: "Tenant", Name: "lip6", UID: "uid", Verdict: VerdictApprove, Approver: "ticket-45", Nonce: "4"}, false},
+		"unknown verdict": {Callback{Kind: KindTenantRequest, Name: "lip6", UID: "uid", Verdict: "Maybe", Approver: "ticket-46", Nonce: "5"}, false},
+		"no approver":     {Callback{Kind: KindTenantRequest, Name: "lip6", UID: "uid", Verdict: VerdictApprove, Nonce: "6"}, false},
+		"no uid":          {Callback{Kind: KindTenantRequest, Name: "lip6", Verdict: VerdictApprove, Approver: "ticket-47", Nonce: "7"}, false},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			body, err := json.Marshal(tc.callback)
+			util.OK(t, err)
+			header := http.Header{}
+			Sign(header, Key, body)
+			callback, err := ParseCallback(header, body, guard)
+			util.Equals(t, tc.valid, err == nil)
+			if tc.valid {
+				util.Equals(t, tc.callback, *callback)
+				// The same callback is refused the second time
+				_, err = ParseCallback(header, body, guard)
+				util.Assert(t, err != nil, "replayed callback accepted")
+			}
+		})
+	}
+}
+
+func TestForward(t *testing.T) {
+	Key = []byte("key")
+	backoff := Backoff
+	Backoff = wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 3}
+	defer func() {
+		Key = nil
+		WebhookURL = ""
+		Backoff = backoff
+	}()
+
+	replies := []int{}
+	received := []Request{}
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if err := Verify(r.Header, Key, body); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		request := Request{}
+		json.Unmarshal(body, &request)
+		received = append(received, request)
+		w.WriteHeader(replies[0])
+		replies = replies[1:]
+	}))
+	defer webhook.Close()
+	WebhookURL = webhook.URL
+	util.Equals(t, true, Enabled())
+	request := Request{Kind: KindTenantRequest, Name: "lip6", UID: "uid", Email: "john.doe@edge-net.org"}
+
+	t.Run("retried", func(t *testing.T) {
+		replies = []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusAccepted}
+		received = nil
+		util.OK(t, Forward(context.TODO(), request))
+		util.Equals(t, 3, len(received))
+		util.Equals(t, request, received[2])
+	})
+	t.Run("failing", func(t *testing.T) {
+		replies = []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}
+		received = nil
+		util.Assert(t, Forward(context.TODO(), request) != nil, "failing webhook reported as reached")
+		util.Equals(t, 3, len(received))
+	})
+	t.Run("refused", func(t *testing.T) {
+		replies = []int{http.StatusBadRequest}
+		received = nil
+		util.Assert(t, Forward(context.TODO(), request) != nil, "refusal reported as accepted")
+		util.Equals(t, 1, len(received))
+	})
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit keeps the trail of the actions taken on the tenants as TenantAudit records
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// Actions recorded in the audit trail
+const (
+	RequestCreated  = "RequestCreated"
+	RequestApproved = "RequestApproved"
+	RequestDeclined = "RequestDeclined"
+	TenantEnabled   = "TenantEnabled"
+	TenantDisabled  = "TenantDisabled"
+	QuotaChanged    = "QuotaChanged"
+	RoleBound       = "RoleBound"
+)
+
+// Labels of the records, the tenant label is the one to list the trail of a tenant with
+const (
+	TenantLabel = "edge-net.io/tenant"
+	ActionLabel = "edge-net.io/audit-action"
+)
+
+// now is replaced in the tests to give the records a known time
+var now = time.Now
+
+// Record appends a record of the action to the audit trail of the tenant. The actor is left
+// empty when the cluster does not know who took the action. A record that cannot be created is
+// logged, the action it records is not held back.
+func Record(ctx context.Context, edgenetclientset clientset.Interface, tenant, action, actor string, object corev1alpha.TenantAuditObject, message string) error {
+	at := now()
+	record := new(corev1alpha.TenantAudit)
+	// The time in the name keeps the records of a tenant apart, and in order
+	record.SetName(fmt.Sprintf("%s-%s", tenant, strconv.FormatInt(at.UnixNano(), 36)))
+	record.SetLabels(map[string]string{TenantLabel: tenant, ActionLabel: action})
+	record.Spec.Tenant = tenant
+	record.Spec.Action = action
+	record.Spec.Actor = actor
+	record.Spec.Object = object
+	record.Spec.Message = message
+	record.Spec.Time = metav1.NewTime(at)
+	if _, err := edgenetclientset.CoreV1alpha().TenantAudits().Create(ctx, record, metav1.CreateOptions{}); err != nil {
+		klog.ErrorS(err, "Couldn't record the action", "tenant", tenant, "action", action, "kind", object.Kind, "name", object.Name, "actor", actor)
+		return err
+	}
+	return nil
+}
+
+// Trail returns the records of the tenant, the oldest first
+func Trail(ctx context.Context, edgenetclientset clientset.Interface, tenant string) ([]corev1alpha.TenantAudit, error) {
+	records, err := edgenetclientset.CoreV1alpha().TenantAudits().List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", TenantLabel, tenant)})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(records.Items, func(i, j int) bool {
+		return records.Items[i].Spec.Time.Before(&records.Items[j].Spec.Time)
+	})
+	return records.Items, nil
+}
+
+// Forget removes the records of the tenant that name their actor, the actor is personal data. The
+// records of the cluster itself stay. It returns the names of the removed records.
+func Forget(ctx context.Context, edgenetclientset clientset.Interface, tenant string) ([]string, error) {
+	records, err := Trail(ctx, edgenetclientset, tenant)
+	if err != nil {
+		return nil, err
+	}
+	removed := []string{}
+	for _, record := range records {
+		if record.Spec.Actor == "" {
+			continue
+		}
+		if err := edgenetclientset.CoreV1alpha().TenantAudits().Delete(ctx, record.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return removed, err
+		}
+		removed = append(removed, record.GetName())
+	}
+	return removed, nil
+}
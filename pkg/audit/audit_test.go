@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+)
+
+func TestTrail(t *testing.T) {
+	edgenetclientset := edgenettestclient.NewSimpleClientset()
+	start := time.Date(2021, 10, 1, 9, 0, 0, 0, time.UTC)
+	defer func() { now = time.Now }()
+
+	records := []struct {
+		tenant string
+		action string
+		at     time.Duration
+	}{
+		{"lip6", TenantEnabled, 2 * time.Hour},
+		{"lip6", RequestApproved, time.Hour},
+		{"nyu", RequestCreated, 0},
+		{"lip6", RequestCreated, 0},
+	}
+	for _, record := range records {
+		at := start.Add(record.at)
+		now = func() time.Time { return at }
+		object := corev1alpha.TenantAuditObject{Kind: "Tenant", Name: record.tenant}
+		util.OK(t, Record(context.TODO(), edgenetclientset, record.tenant, record.action, "admin@edge-net.org", object, ""))
+	}
+
+	trail, err := Trail(context.TODO(), edgenetclientset, "lip6")
+	util.OK(t, err)
+	actions := []string{}
+	for _, record := range trail {
+		actions = append(actions, record.Spec.Action)
+		util.Equals(t, "lip6", record.Spec.Tenant)
+	}
+	util.Equals(t, []string{RequestCreated, RequestApproved, TenantEnabled}, actions)
+
+	t.Run("same time", func(t *testing.T) {
+		now = func() time.Time { return start }
+		err := Record(context.TODO(), edgenetclientset, "lip6", RequestCreated, "", corev1alpha.TenantAuditObject{}, "")
+		util.Assert(t, err != nil, "record with a taken name is created")
+	})
+}
+
+func TestForget(t *testing.T) {
+	edgenetclientset := edgenettestclient.NewSimpleClientset()
+	start := time.Date(2021, 10, 1, 9, 0, 0, 0, time.UTC)
+	defer func() { now = time.Now }()
+
+	records := []struct {
+		tenant string
+		actor  string
+		at     time.Duration
+	}{
+		{"lip6", "admin@edge-net.org", 0},
+		{"lip6", "", time.Hour},
+		{"nyu", "admin@edge-net.org", 2 * time.Hour},
+	}
+	for _, record := range records {
+		at := start.Add(record.at)
+		now = func() time.Time { return at }
+		object := corev1alpha.TenantAuditObject{Kind: "Tenant", Name: record.tenant}
+		util.OK(t, Record(context.TODO(), edgenetclientset, record.tenant, TenantEnabled, record.actor, object, ""))
+	}
+
+	removed, err := Forget(context.TODO(), edgenetclientset, "lip6")
+	util.OK(t, err)
+	util.Equals(t, 1, len(removed))
+	trail, err := Trail(context.TODO(), edgenetclientset, "lip6")
+	util.OK(t, err)
+	util.Equals(t, 1, len(trail))
+	util.Equals(t, "", trail[0].Spec.Actor)
+	trail, err = Trail(context.TODO(), edgenetclientset, "nyu")
+	util.OK(t, err)
+	util.Equals(t, 1, len(trail))
+}
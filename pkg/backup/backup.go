@@ -0,0 +1,502 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backup exports the EdgeNet control objects of a cluster to a portable archive, and
+// restores them on another cluster. The objects derived from them, such as the namespaces and
+// the roles of the tenants, are not archived: the controllers rebuild them once the objects are
+// restored.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/config"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// Version is the version of the archive format
+const Version = 1
+
+// manifestName is the entry of the archive that describes the cluster the objects come from
+const manifestName = "manifest.json"
+
+// Manifest describes the archive
+type Manifest struct {
+	Version    int       `json:"version"`
+	ClusterUID string    `json:"clusterUID"`
+	Created    time.Time `json:"created"`
+}
+
+// kind is a kind of object archived, the kinds are restored in the order of the list
+type kind struct {
+	name       string
+	namespaced bool
+	list       func(ctx context.Context, edgenetclientset clientset.Interface) ([]metav1.Object, error)
+	create     func(ctx context.Context, edgenetclientset clientset.Interface, data []byte, prepare func(metav1.Object)) (metav1.Object, error)
+}
+
+var kinds = []kind{
+	{
+		name: "tenants",
+		list: func(ctx context.Context, edgenetclientset clientset.Interface) ([]metav1.Object, error) {
+			list, err := edgenetclientset.CoreV1alpha().Tenants().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			objects := []metav1.Object{}
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			return objects, nil
+		},
+		create: func(ctx context.Context, edgenetclientset clientset.Interface, data []byte, prepare func(metav1.Object)) (metav1.Object, error) {
+			object := new(corev1alpha.Tenant)
+			if err := json.Unmarshal(data, object); err != nil {
+				return nil, err
+			}
+			prepare(object)
+			return edgenetclientset.CoreV1alpha().Tenants().Create(ctx, object, metav1.CreateOptions{})
+		},
+	},
+	{
+		name: "tenantresourcequotas",
+		list: func(ctx context.Context, edgenetclientset clientset.Interface) ([]metav1.Object, error) {
+			list, err := edgenetclientset.CoreV1alpha().TenantResourceQuotas().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			objects := []metav1.Object{}
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			return objects, nil
+		},
+		create: func(ctx context.Context, edgenetclientset clientset.Interface, data []byte, prepare func(metav1.Object)) (metav1.Object, error) {
+			object := new(corev1alpha.TenantResourceQuota)
+			if err := json.Unmarshal(data, object); err != nil {
+				return nil, err
+			}
+			prepare(object)
+			return edgenetclientset.CoreV1alpha().TenantResourceQuotas().Create(ctx, object, metav1.CreateOptions{})
+		},
+	},
+	{
+		name:       "subnamespaces",
+		namespaced: true,
+		list: func(ctx context.Context, edgenetclientset clientset.Interface) ([]metav1.Object, error) {
+			list, err := edgenetclientset.CoreV1alpha().SubNamespaces("").List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			objects := []metav1.Object{}
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			return objects, nil
+		},
+		create: func(ctx context.Context, edgenetclientset clientset.Interface, data []byte, prepare func(metav1.Object)) (metav1.Object, error) {
+			object := new(corev1alpha.SubNamespace)
+			if err := json.Unmarshal(data, object); err != nil {
+				return nil, err
+			}
+			prepare(object)
+			return edgenetclientset.CoreV1alpha().SubNamespaces(object.GetNamespace()).Create(ctx, object, metav1.CreateOptions{})
+		},
+	},
+	{
+		name: "nodecontributions",
+		list: func(ctx context.Context, edgenetclientset clientset.Interface) ([]metav1.Object, error) {
+			list, err := edgenetclientset.CoreV1alpha().NodeContributions().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			objects := []metav1.Object{}
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			return objects, nil
+		},
+		create: func(ctx context.Context, edgenetclientset clientset.Interface, data []byte, prepare func(metav1.Object)) (metav1.Object, error) {
+			object := new(corev1alpha.NodeContribution)
+			if err := json.Unmarshal(data, object); err != nil {
+				return nil, err
+			}
+			prepare(object)
+			return edgenetclientset.CoreV1alpha().NodeContributions().Create(ctx, object, metav1.CreateOptions{})
+		},
+	},
+	{
+		name: "tenantrequests",
+		list: func(ctx context.Context, edgenetclientset clientset.Interface) ([]metav1.Object, error) {
+			list, err := edgenetclientset.RegistrationV1alpha().TenantRequests().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			objects := []metav1.Object{}
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			return objects, nil
+		},
+		create: func(ctx context.Context, edgenetclientset clientset.Interface, data []byte, prepare func(metav1.Object)) (metav1.Object, error) {
+			object := new(registrationv1alpha.TenantRequest)
+			if err := json.Unmarshal(data, object); err != nil {
+				return nil, err
+			}
+			prepare(object)
+			return edgenetclientset.RegistrationV1alpha().TenantRequests().Create(ctx, object, metav1.CreateOptions{})
+		},
+	},
+	{
+		name: "clusterrolerequests",
+		list: func(ctx context.Context, edgenetclientset clientset.Interface) ([]metav1.Object, error) {
+			list, err := edgenetclientset.RegistrationV1alpha().ClusterRoleRequests().List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			objects := []metav1.Object{}
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			return objects, nil
+		},
+		create: func(ctx context.Context, edgenetclientset clientset.Interface, data []byte, prepare func(metav1.Object)) (metav1.Object, error) {
+			object := new(registrationv1alpha.ClusterRoleRequest)
+			if err := json.Unmarshal(data, object); err != nil {
+				return nil, err
+			}
+			prepare(object)
+			return edgenetclientset.RegistrationV1alpha().ClusterRoleRequests().Create(ctx, object, metav1.CreateOptions{})
+		},
+	},
+	{
+		name:       "rolerequests",
+		namespaced: true,
+		list: func(ctx context.Context, edgenetclientset clientset.Interface) ([]metav1.Object, error) {
+			list, err := edgenetclientset.RegistrationV1alpha().RoleRequests("").List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			objects := []metav1.Object{}
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			return objects, nil
+		},
+		create: func(ctx context.Context, edgenetclientset clientset.Interface, data []byte, prepare func(metav1.Object)) (metav1.Object, error) {
+			object := new(registrationv1alpha.RoleRequest)
+			if err := json.Unmarshal(data, object); err != nil {
+				return nil, err
+			}
+			prepare(object)
+			return edgenetclientset.RegistrationV1alpha().RoleRequests(object.GetNamespace()).Create(ctx, object, metav1.CreateOptions{})
+		},
+	},
+	{
+		name:       "extensionrequests",
+		namespaced: true,
+		list: func(ctx context.Context, edgenetclientset clientset.Interface) ([]metav1.Object, error) {
+			list, err := edgenetclientset.RegistrationV1alpha().ExtensionRequests("").List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			objects := []metav1.Object{}
+			for i := range list.Items {
+				objects = append(objects, &list.Items[i])
+			}
+			return objects, nil
+		},
+		create: func(ctx context.Context, edgenetclientset clientset.Interface, data []byte, prepare func(metav1.Object)) (metav1.Object, error) {
+			object := new(registrationv1alpha.ExtensionRequest)
+			if err := json.Unmarshal(data, object); err != nil {
+				return nil, err
+			}
+			prepare(object)
+			return edgenetclientset.RegistrationV1alpha().ExtensionRequests(object.GetNamespace()).Create(ctx, object, metav1.CreateOptions{})
+		},
+	},
+}
+
+// Export writes the control objects of the cluster to the archive, a gzipped tarball holding a
+// JSON file per object under the directory of its kind
+func Export(ctx context.Context, kubeclientset kubernetes.Interface, edgenetclientset clientset.Interface, w io.Writer) error {
+	clusterUID, err := config.ClusterUID(ctx, kubeclientset)
+	if err != nil {
+		return err
+	}
+	gzipWriter := gzip.NewWriter(w)
+	archive := tar.NewWriter(gzipWriter)
+	manifest := Manifest{Version: Version, ClusterUID: clusterUID, Created: time.Now().UTC()}
+	if err := writeEntry(archive, manifestName, manifest); err != nil {
+		return err
+	}
+	for _, kind := range kinds {
+		objects, err := kind.list(ctx, edgenetclientset)
+		if err != nil {
+			return fmt.Errorf("cannot list the %s: %v", kind.name, err)
+		}
+		for _, object := range objects {
+			// The versions are only meaningful to the cluster the objects come from
+			object.SetResourceVersion("")
+			object.SetManagedFields(nil)
+			object.SetSelfLink("")
+			if err := writeEntry(archive, entryName(kind, object.GetNamespace(), object.GetName()), object); err != nil {
+				return err
+			}
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+// Result counts the objects restored and the objects skipped as they already exist, per kind
+type Result struct {
+	Restored map[string]int
+	Skipped  map[string]int
+}
+
+// Import restores the control objects of the archive in the cluster. The UIDs of the cluster and of
+// the objects restored are remapped in the labels, the annotations, and the owner references, and
+// the namespaces of the subnamespaces are renamed after the child namespaces of the new cluster.
+// The namespaced objects wait for their namespace, created by the controllers, up to the timeout.
+func Import(ctx context.Context, kubeclientset kubernetes.Interface, edgenetclientset clientset.Interface, r io.Reader, timeout time.Duration) (Result, error) {
+	result := Result{Restored: map[string]int{}, Skipped: map[string]int{}}
+	manifest, entries, err := readArchive(r)
+	if err != nil {
+		return result, err
+	}
+	clusterUID, err := config.ClusterUID(ctx, kubeclientset)
+	if err != nil {
+		return result, err
+	}
+	namespaces, subnamespaces, err := childNamespaces(entries["subnamespaces"], manifest.ClusterUID, clusterUID)
+	if err != nil {
+		return result, err
+	}
+	entries["subnamespaces"] = subnamespaces
+
+	uids := map[string]string{manifest.ClusterUID: clusterUID}
+	remap := func(values map[string]string) map[string]string {
+		for key, value := range values {
+			if remapped, ok := uids[value]; ok && value != "" {
+				values[key] = remapped
+			}
+		}
+		return values
+	}
+	prepare := func(object metav1.Object) {
+		object.SetUID("")
+		object.SetResourceVersion("")
+		object.SetGeneration(0)
+		object.SetCreationTimestamp(metav1.Time{})
+		object.SetManagedFields(nil)
+		if namespace, ok := namespaces[object.GetNamespace()]; ok {
+			object.SetNamespace(namespace)
+		}
+		object.SetLabels(remap(object.GetLabels()))
+		object.SetAnnotations(remap(object.GetAnnotations()))
+		// The owners outside of the archive do not exist on the new cluster
+		owners := []metav1.OwnerReference{}
+		for _, owner := range object.GetOwnerReferences() {
+			if uid, ok := uids[string(owner.UID)]; ok {
+				owner.UID = types.UID(uid)
+				owners = append(owners, owner)
+			}
+		}
+		object.SetOwnerReferences(owners)
+	}
+
+	for _, kind := range kinds {
+		for _, data := range entries[kind.name] {
+			meta := new(metav1.PartialObjectMetadata)
+			if err := json.Unmarshal(data, meta); err != nil {
+				return result, fmt.Errorf("invalid %s entry: %v", kind.name, err)
+			}
+			if kind.namespaced {
+				namespace := meta.GetNamespace()
+				if remapped, ok := namespaces[namespace]; ok {
+					namespace = remapped
+				}
+				if err := waitForNamespace(ctx, kubeclientset, namespace, timeout); err != nil {
+					return result, fmt.Errorf("namespace %s of %s %s is not ready: %v", namespace, kind.name, meta.GetName(), err)
+				}
+			}
+			created, err := kind.create(ctx, edgenetclientset, data, prepare)
+			if errors.IsAlreadyExists(err) {
+				klog.InfoS("Object already exists, skipped", "kind", kind.name, "namespace", meta.GetNamespace(), "name", meta.GetName())
+				result.Skipped[kind.name]++
+				continue
+			} else if err != nil {
+				return result, fmt.Errorf("cannot restore %s %s: %v", kind.name, meta.GetName(), err)
+			}
+			if meta.GetUID() != "" && created.GetUID() != "" {
+				uids[string(meta.GetUID())] = string(created.GetUID())
+			}
+			result.Restored[kind.name]++
+		}
+	}
+	return result, nil
+}
+
+// childNamespaces maps the child namespaces of the subnamespaces on the old cluster to their
+// names on the new one, as the names derive from the UID of the cluster for the federated
+// workspaces and from the name of the parent namespace. The subnamespaces are returned parents
+// first, so that each child namespace exists before the subnamespaces it holds are restored.
+func childNamespaces(entries [][]byte, oldClusterUID, newClusterUID string) (map[string]string, [][]byte, error) {
+	pending := []*corev1alpha.SubNamespace{}
+	raw := map[*corev1alpha.SubNamespace][]byte{}
+	for _, data := range entries {
+		subnamespace := new(corev1alpha.SubNamespace)
+		if err := json.Unmarshal(data, subnamespace); err != nil {
+			return nil, nil, fmt.Errorf("invalid subnamespaces entry: %v", err)
+		}
+		pending = append(pending, subnamespace)
+		raw[subnamespace] = data
+	}
+	children := map[string]bool{}
+	for _, subnamespace := range pending {
+		child, err := subnamespace.GenerateChildName(oldClusterUID)
+		if err != nil {
+			return nil, nil, err
+		}
+		children[child] = true
+	}
+
+	namespaces := map[string]string{}
+	ordered := [][]byte{}
+	for len(pending) > 0 {
+		remaining := []*corev1alpha.SubNamespace{}
+		for _, subnamespace := range pending {
+			parent := subnamespace.GetNamespace()
+			_, mapped := namespaces[parent]
+			if children[parent] && !mapped {
+				// The parent is the child namespace of a subnamespace not yet mapped
+				remaining = append(remaining, subnamespace)
+				continue
+			}
+			oldChild, err := subnamespace.GenerateChildName(oldClusterUID)
+			if err != nil {
+				return nil, nil, err
+			}
+			if mapped {
+				subnamespace.SetNamespace(namespaces[parent])
+			}
+			newChild, err := subnamespace.GenerateChildName(newClusterUID)
+			if err != nil {
+				return nil, nil, err
+			}
+			namespaces[oldChild] = newChild
+			ordered = append(ordered, raw[subnamespace])
+		}
+		if len(remaining) == len(pending) {
+			return nil, nil, fmt.Errorf("the subnamespaces in %s form a cycle", remaining[0].GetNamespace())
+		}
+		pending = remaining
+	}
+	return namespaces, ordered, nil
+}
+
+// waitForNamespace waits until the namespace exists
+func waitForNamespace(ctx context.Context, kubeclientset kubernetes.Interface, namespace string, timeout time.Duration) error {
+	return wait.PollImmediate(time.Second, timeout, func() (bool, error) {
+		if _, err := kubeclientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{}); err == nil {
+			return true, nil
+		} else if !errors.IsNotFound(err) {
+			return false, err
+		}
+		return false, nil
+	})
+}
+
+// readArchive returns the manifest and the entries of the archive by kind
+func readArchive(r io.Reader) (Manifest, map[string][][]byte, error) {
+	manifest := Manifest{}
+	entries := map[string][][]byte{}
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return manifest, nil, err
+	}
+	defer gzipReader.Close()
+	archive := tar.NewReader(gzipReader)
+	found := false
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return manifest, nil, err
+		}
+		data, err := ioutil.ReadAll(archive)
+		if err != nil {
+			return manifest, nil, err
+		}
+		if header.Name == manifestName {
+			if err := json.Unmarshal(data, &manifest); err != nil {
+				return manifest, nil, fmt.Errorf("invalid manifest: %v", err)
+			}
+			found = true
+			continue
+		}
+		kind := strings.SplitN(header.Name, "/", 2)[0]
+		entries[kind] = append(entries[kind], data)
+	}
+	if !found {
+		return manifest, nil, fmt.Errorf("the archive has no manifest")
+	}
+	if manifest.Version != Version {
+		return manifest, nil, fmt.Errorf("unsupported archive version %d", manifest.Version)
+	}
+	return manifest, entries, nil
+}
+
+func entryName(kind kind, namespace, name string) string {
+	if kind.namespaced {
+		return path.Join(kind.name, namespace, name+".json")
+	}
+	return path.Join(kind.name, name+".json")
+}
+
+func writeEntry(archive *tar.Writer, name string, object interface{}) error {
+	data, err := json.MarshalIndent(object, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := archive.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), ModTime: time.Now()}); err != nil {
+		return err
+	}
+	_, err = archive.Write(data)
+	return err
+}
@@ -0,0 +1,77 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestExportImport(t *testing.T) {
+	team := &corev1alpha.SubNamespace{ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "lip6", UID: "team-uid"},
+		Spec: corev1alpha.SubNamespaceSpec{Workspace: &corev1alpha.Workspace{Scope: "federated"}}}
+	oldChild, err := team.GenerateChildName("old-cluster-uid")
+	util.OK(t, err)
+	project := &corev1alpha.SubNamespace{ObjectMeta: metav1.ObjectMeta{Name: "project", Namespace: oldChild},
+		Spec: corev1alpha.SubNamespaceSpec{Workspace: &corev1alpha.Workspace{Scope: "local"}}}
+	tenant := &corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "lip6", UID: "tenant-uid", ResourceVersion: "12"},
+		Spec: corev1alpha.TenantSpec{FullName: "LIP6", Enabled: true}}
+	quota := &corev1alpha.TenantResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: "lip6",
+		Labels: map[string]string{"edge-net.io/cluster-uid": "old-cluster-uid", "edge-net.io/tenant": "lip6"}}}
+	request := &registrationv1alpha.TenantRequest{ObjectMeta: metav1.ObjectMeta{Name: "lip6"}, Spec: registrationv1alpha.TenantRequestSpec{FullName: "LIP6"}}
+
+	source := testclient.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", UID: "old-cluster-uid"}})
+	sourceEdgenet := edgenettestclient.NewSimpleClientset(tenant, quota, team, project, request)
+	var archive bytes.Buffer
+	util.OK(t, Export(context.TODO(), source, sourceEdgenet, &archive))
+
+	newTeam := team.DeepCopy()
+	newChild, err := newTeam.GenerateChildName("new-cluster-uid")
+	util.OK(t, err)
+	destination := testclient.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", UID: "new-cluster-uid"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "lip6"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: newChild}})
+	destinationEdgenet := edgenettestclient.NewSimpleClientset(request)
+	result, err := Import(context.TODO(), destination, destinationEdgenet, &archive, time.Second)
+	util.OK(t, err)
+	util.Equals(t, map[string]int{"tenants": 1, "tenantresourcequotas": 1, "subnamespaces": 2}, result.Restored)
+	util.Equals(t, map[string]int{"tenantrequests": 1}, result.Skipped)
+
+	restoredTenant, err := destinationEdgenet.CoreV1alpha().Tenants().Get(context.TODO(), "lip6", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, "LIP6", restoredTenant.Spec.FullName)
+	util.Equals(t, "", restoredTenant.GetResourceVersion())
+	restoredQuota, err := destinationEdgenet.CoreV1alpha().TenantResourceQuotas().Get(context.TODO(), "lip6", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, "new-cluster-uid", restoredQuota.GetLabels()["edge-net.io/cluster-uid"])
+	_, err = destinationEdgenet.CoreV1alpha().SubNamespaces(newChild).Get(context.TODO(), "project", metav1.GetOptions{})
+	util.OK(t, err)
+}
+
+func TestImportWaitsForNamespace(t *testing.T) {
+	source := testclient.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", UID: "old-cluster-uid"}})
+	sourceEdgenet := edgenettestclient.NewSimpleClientset(&corev1alpha.SubNamespace{ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "lip6"},
+		Spec: corev1alpha.SubNamespaceSpec{Workspace: &corev1alpha.Workspace{Scope: "local"}}})
+	var archive bytes.Buffer
+	util.OK(t, Export(context.TODO(), source, sourceEdgenet, &archive))
+
+	destination := testclient.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", UID: "new-cluster-uid"}})
+	_, err := Import(context.TODO(), destination, edgenettestclient.NewSimpleClientset(), &archive, time.Millisecond)
+	util.Assert(t, err != nil, "the subnamespace is restored without its namespace")
+}
+
+func TestImportRejectsArchive(t *testing.T) {
+	destination := testclient.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}})
+	_, err := Import(context.TODO(), destination, edgenettestclient.NewSimpleClientset(), bytes.NewBufferString("not an archive"), time.Second)
+	util.Assert(t, err != nil, "an invalid archive is accepted")
+}
@@ -21,7 +21,6 @@ package bootstrap
 
 import (
 	"flag"
-	"log"
 	"os"
 	"path/filepath"
 
@@ -29,9 +28,11 @@ import (
 	"github.com/EdgeNet-project/edgenet/pkg/util"
 
 	namecheap "github.com/billputer/go-namecheap"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
 )
 
 var kubeconfig string
@@ -70,9 +71,10 @@ func CreateEdgeNetClientset(by string) (*clientset.Clientset, error) {
 		// Use the current context in kubeconfig
 		config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
 		if err != nil {
-			log.Println(err.Error())
+			klog.ErrorS(err, "Couldn't build the config from the kubeconfig")
 			panic(err.Error())
 		}
+		ConfigureReadOnly(config)
 		edgenetclientset = generateClientset(config)
 	} else if by == "serviceaccount" {
 		// Creates the in-cluster config
@@ -80,6 +82,7 @@ func CreateEdgeNetClientset(by string) (*clientset.Clientset, error) {
 		if err != nil {
 			panic(err.Error())
 		}
+		ConfigureReadOnly(config)
 		edgenetclientset = generateClientset(config)
 	}
 	return edgenetclientset, nil
@@ -105,6 +108,7 @@ func CreateClientset(by string) (*kubernetes.Clientset, error) {
 			// TODO: Error handling
 			panic(err.Error())
 		}
+		ConfigureReadOnly(config)
 		kubeclientset = generateClientset(config)
 	} else if by == "serviceaccount" {
 		// Creates the in-cluster config
@@ -113,16 +117,35 @@ func CreateClientset(by string) (*kubernetes.Clientset, error) {
 			// TODO: Error handling
 			panic(err.Error())
 		}
+		ConfigureReadOnly(config)
 		kubeclientset = generateClientset(config)
 	}
 	return kubeclientset, nil
 }
 
+// CreateDynamicClient generates the client to interact with the resources of any kind
+func CreateDynamicClient(by string) (dynamic.Interface, error) {
+	var config *rest.Config
+	var err error
+	if by == "kubeconfig" {
+		// Use the current context in kubeconfig
+		config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	} else {
+		// Creates the in-cluster config
+		config, err = rest.InClusterConfig()
+	}
+	if err != nil {
+		return nil, err
+	}
+	ConfigureReadOnly(config)
+	return dynamic.NewForConfig(config)
+}
+
 // CreateNamecheapClient generates the client to interact with Namecheap API
 func CreateNamecheapClient() (*namecheap.Client, error) {
 	apiuser, apitoken, username, err := util.GetNamecheapCredentials()
 	if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't read the Namecheap credentials")
 		panic(err.Error())
 	}
 	client := namecheap.NewClient(apiuser, apitoken, username)
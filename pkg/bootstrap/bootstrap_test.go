@@ -1,10 +1,26 @@
 package bootstrap
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/EdgeNet-project/edgenet/pkg/util"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
 )
 
 func TestHomeDir(t *testing.T) {
@@ -36,3 +52,116 @@ func TestNamecheapClient(t *testing.T) {
 	_, err := CreateNamecheapClient()
 	util.OK(t, err)
 }
+
+func TestReadOnly(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"kind":"Namespace","apiVersion":"v1","metadata":{"name":"edgenet"}}`))
+	}))
+	defer server.Close()
+	ReadOnly = true
+	defer func() { ReadOnly = false }()
+	suppressedWrites.Reset()
+
+	config := &rest.Config{Host: server.URL}
+	ConfigureReadOnly(config)
+	client, err := kubernetes.NewForConfig(config)
+	util.OK(t, err)
+
+	_, err = client.CoreV1().Namespaces().Get(context.TODO(), "edgenet", metav1.GetOptions{})
+	util.OK(t, err)
+	_, err = client.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "edgenet"}}, metav1.CreateOptions{})
+	util.Equals(t, true, errors.IsMethodNotSupported(err))
+	_, err = client.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "edgenet"}}, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	util.OK(t, err)
+	err = client.CoreV1().Pods("edgenet").Delete(context.TODO(), "pod", metav1.DeleteOptions{})
+	util.Equals(t, true, errors.IsMethodNotSupported(err))
+	util.Equals(t, []string{http.MethodGet, http.MethodPost}, requests)
+
+	util.Equals(t, float64(1), testutil.ToFloat64(suppressedWrites.WithLabelValues(http.MethodPost, "namespaces")))
+	util.Equals(t, float64(1), testutil.ToFloat64(suppressedWrites.WithLabelValues(http.MethodDelete, "pods")))
+}
+
+func TestDryRun(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, fmt.Sprintf("%s %s", r.Method, r.URL.Query().Get("dryRun")))
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"kind":"Namespace","apiVersion":"v1","metadata":{"name":"edgenet","resourceVersion":"1","labels":{"owner":"lip6"}}}`))
+			return
+		}
+		w.Write([]byte(`{"kind":"Namespace","apiVersion":"v1","metadata":{"name":"edgenet","resourceVersion":"2","labels":{"owner":"edgenet"}}}`))
+	}))
+	defer server.Close()
+	DryRun = true
+	defer func() { DryRun = false }()
+
+	config := &rest.Config{Host: server.URL}
+	ConfigureReadOnly(config)
+	client, err := kubernetes.NewForConfig(config)
+	util.OK(t, err)
+
+	_, err = client.CoreV1().Namespaces().Get(context.TODO(), "edgenet", metav1.GetOptions{})
+	util.OK(t, err)
+	namespace, err := client.CoreV1().Namespaces().Update(context.TODO(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "edgenet"}}, metav1.UpdateOptions{})
+	util.OK(t, err)
+	util.Equals(t, "edgenet", namespace.GetLabels()["owner"])
+	_, err = client.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "edgenet"}}, metav1.CreateOptions{})
+	util.OK(t, err)
+	util.Equals(t, []string{"GET ", "GET ", "PUT All", "POST All"}, requests)
+}
+
+func TestDiffObjects(t *testing.T) {
+	before := []byte(`{"metadata":{"name":"edgenet","resourceVersion":"1","labels":{"owner":"lip6","tier":"paying"}},"spec":{"finalizers":["kubernetes"]}}`)
+	after := []byte(`{"metadata":{"name":"edgenet","resourceVersion":"2","labels":{"owner":"edgenet","team":"core"}},"spec":{"finalizers":["kubernetes"]}}`)
+	util.Equals(t, "~ metadata.labels.owner: \"lip6\" -> \"edgenet\"\n+ metadata.labels.team: \"core\"\n- metadata.labels.tier: \"paying\"",
+		diffObjects(before, after))
+	util.Equals(t, "", diffObjects(before, before))
+	util.Equals(t, "+ metadata.name: \"edgenet\"", diffObjects(nil, []byte(`{"metadata":{"name":"edgenet","uid":"1"}}`)))
+}
+
+func TestResourceOf(t *testing.T) {
+	cases := map[string]string{
+		"/api/v1/namespaces":                                                         "namespaces",
+		"/api/v1/namespaces/edgenet/status":                                          "namespaces/status",
+		"/api/v1/namespaces/edgenet/pods/pod":                                        "pods",
+		"/apis/core.edgenet.io/v1alpha/tenants/edgenet/status":                       "tenants/status",
+		"/apis/authorization.k8s.io/v1/subjectaccessreviews":                         "subjectaccessreviews",
+		"/apis/rbac.authorization.k8s.io/v1/namespaces/edgenet/rolebindings/binding": "rolebindings",
+	}
+	for path, expected := range cases {
+		util.Equals(t, expected, resourceOf(path))
+	}
+}
+
+func TestJSONLogger(t *testing.T) {
+	out := &bytes.Buffer{}
+	logger := newJSONLogger(out).WithName("tenant").WithValues("controller", "tenant")
+	tenant := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "lip6"}}
+	logger.V(4).Info("Successfully synced", "tenant", klog.KObj(tenant), "attempts", 2)
+	logger.Error(fmt.Errorf("quota exceeded"), "Couldn't apply limit range", "tenant", klog.KObj(tenant))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	util.Equals(t, 2, len(lines))
+	entry := map[string]interface{}{}
+	util.OK(t, json.Unmarshal([]byte(lines[0]), &entry))
+	util.Equals(t, "Successfully synced", entry["msg"])
+	util.Equals(t, float64(4), entry["v"])
+	util.Equals(t, "tenant", entry["controller"])
+	util.Equals(t, "lip6", entry["tenant"])
+	util.Equals(t, float64(2), entry["attempts"])
+	entry = map[string]interface{}{}
+	util.OK(t, json.Unmarshal([]byte(lines[1]), &entry))
+	util.Equals(t, "Couldn't apply limit range", entry["msg"])
+	util.Equals(t, "quota exceeded", entry["err"])
+
+	t.Run("flag", func(t *testing.T) {
+		err := flag.Set("log-format", "xml")
+		util.Assert(t, err != nil, "unknown log format is accepted")
+		util.OK(t, flag.Set("log-format", "text"))
+		util.Equals(t, "text", LogFormat)
+	})
+}
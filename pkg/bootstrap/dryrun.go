@@ -0,0 +1,178 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+// DryRun sends the writes of the controllers to the API server as dry runs. The server validates
+// them and runs the admission as usual but persists nothing, and each write is logged as a diff
+// against the live object. It is meant to try an upgrade or a new policy against the production state.
+var DryRun bool
+
+func init() {
+	flag.BoolVar(&DryRun, "dry-run", false, "Perform the writes as dry runs and log them as diffs against the live objects")
+}
+
+// Fields that the API server sets on every write, they would clutter the diffs
+var volatileFields = map[string]bool{
+	"metadata.resourceVersion":   true,
+	"metadata.managedFields":     true,
+	"metadata.generation":        true,
+	"metadata.uid":               true,
+	"metadata.creationTimestamp": true,
+}
+
+// dryRunRoundTripper turns the writes into dry runs and logs what they would change
+type dryRunRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (d *dryRunRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isWrite(req) {
+		return d.next.RoundTrip(req)
+	}
+	var live []byte
+	if req.Method != http.MethodPost {
+		live = d.get(req)
+	}
+
+	dryRun := req.Clone(req.Context())
+	query := dryRun.URL.Query()
+	query.Set("dryRun", "All")
+	dryRun.URL.RawQuery = query.Encode()
+	resp, err := d.next.RoundTrip(dryRun)
+	if err != nil {
+		return resp, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		klog.InfoS("Dry run rejected", "method", req.Method, "path", req.URL.Path, "code", resp.StatusCode)
+		return resp, nil
+	}
+	var result []byte
+	if req.Method != http.MethodDelete && strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		result, err = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(result))
+	}
+	klog.InfoS("Dry run", "method", req.Method, "path", req.URL.Path, "diff", diffObjects(live, result))
+	return resp, nil
+}
+
+// get returns the live object the request is about to change, nil if it cannot be read
+func (d *dryRunRoundTripper) get(req *http.Request) []byte {
+	get, err := http.NewRequestWithContext(req.Context(), http.MethodGet, req.URL.String(), nil)
+	if err != nil {
+		return nil
+	}
+	get.Header = req.Header.Clone()
+	get.Header.Set("Accept", "application/json")
+	get.Header.Del("Content-Type")
+	resp, err := d.next.RoundTrip(get)
+	if err != nil {
+		return nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil
+	}
+	return body
+}
+
+// diffObjects lists the fields that differ between two objects in JSON, one per line: + for the
+// fields added, - for the fields removed, and ~ for the fields changed
+func diffObjects(before, after []byte) string {
+	old, new := map[string]string{}, map[string]string{}
+	flatten(before, old)
+	flatten(after, new)
+	paths := []string{}
+	for path := range old {
+		paths = append(paths, path)
+	}
+	for path := range new {
+		if _, ok := old[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	lines := []string{}
+	for _, path := range paths {
+		oldValue, inOld := old[path]
+		newValue, inNew := new[path]
+		switch {
+		case !inOld:
+			lines = append(lines, fmt.Sprintf("+ %s: %s", path, newValue))
+		case !inNew:
+			lines = append(lines, fmt.Sprintf("- %s: %s", path, oldValue))
+		case oldValue != newValue:
+			lines = append(lines, fmt.Sprintf("~ %s: %s -> %s", path, oldValue, newValue))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// flatten maps the path of every leaf of the object to its value, the volatile fields are left out
+func flatten(data []byte, fields map[string]string) {
+	if len(data) == 0 {
+		return
+	}
+	var object interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return
+	}
+	var walk func(path string, value interface{})
+	walk = func(path string, value interface{}) {
+		if volatileFields[path] {
+			return
+		}
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			for key, child := range typed {
+				if path == "" {
+					walk(key, child)
+				} else {
+					walk(fmt.Sprintf("%s.%s", path, key), child)
+				}
+			}
+		case []interface{}:
+			for i, child := range typed {
+				walk(fmt.Sprintf("%s[%d]", path, i), child)
+			}
+		default:
+			encoded, _ := json.Marshal(typed)
+			fields[path] = string(encoded)
+		}
+	}
+	walk("", object)
+}
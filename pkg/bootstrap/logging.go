@@ -0,0 +1,60 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-logr/logr"
+	"go.uber.org/zap/zapcore"
+	logsjson "k8s.io/component-base/logs/json"
+	"k8s.io/klog/v2"
+)
+
+// LogFormat is the format of the logs, text as klog prints them or json as the Kubernetes components do
+var LogFormat = "text"
+
+func init() {
+	flag.Var(logFormatValue{}, "log-format", "Format of the logs, text or json")
+}
+
+// logFormatValue switches the klog output over as soon as the flag is parsed
+type logFormatValue struct{}
+
+func (logFormatValue) String() string {
+	return LogFormat
+}
+
+func (logFormatValue) Set(value string) error {
+	switch value {
+	case "text":
+	case "json":
+		klog.SetLogger(newJSONLogger(os.Stderr))
+	default:
+		return fmt.Errorf("unknown log format %q, text or json", value)
+	}
+	LogFormat = value
+	return nil
+}
+
+// newJSONLogger returns the logger of the JSON format of the Kubernetes components, writing to out
+func newJSONLogger(out io.Writer) logr.Logger {
+	return logsjson.NewJSONLogger(zapcore.AddSync(out))
+}
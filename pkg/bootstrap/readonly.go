@@ -0,0 +1,167 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/EdgeNet-project/edgenet/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// ReadOnly keeps the controllers from writing to the cluster. The informers still list and watch
+// so that the caches stay warm, the writes that reconciles attempt are refused and counted as
+// drift. It is meant for maintenance, migrations, restores from backup, and debugging reconcile storms.
+var ReadOnly bool
+
+// ReadOnlyMetricsAddress is the address to serve the metrics of the process on in read-only mode,
+// the suppressed writes among them
+var ReadOnlyMetricsAddress string
+
+func init() {
+	flag.BoolVar(&ReadOnly, "read-only", false, "Perform no writes, report the writes that reconciles attempt instead")
+	flag.StringVar(&ReadOnlyMetricsAddress, "read-only-metrics-address", "", "Address to serve the metrics, the suppressed writes among them, on in read-only mode, disabled when empty")
+}
+
+// Reviews are created to ask the API server a question, they don't change the cluster
+var reviewResources = map[string]bool{
+	"subjectaccessreviews":      true,
+	"selfsubjectaccessreviews":  true,
+	"localsubjectaccessreviews": true,
+	"selfsubjectrulesreviews":   true,
+	"tokenreviews":              true,
+}
+
+// ConfigureReadOnly makes the clients built out of the config refuse the writes in read-only mode,
+// and turn them into dry runs in dry-run mode
+func ConfigureReadOnly(config *rest.Config) {
+	if DryRun && !ReadOnly {
+		config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &dryRunRoundTripper{next: rt}
+		})
+		return
+	}
+	if !ReadOnly {
+		return
+	}
+	startReadOnlyMetrics()
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &readOnlyRoundTripper{next: rt}
+	})
+}
+
+// readOnlyRoundTripper lets the reads, dry runs, and reviews through and refuses the rest
+type readOnlyRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (r *readOnlyRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isWrite(req) {
+		return r.next.RoundTrip(req)
+	}
+	resource := resourceOf(req.URL.Path)
+	suppressedWrites.WithLabelValues(req.Method, resource).Inc()
+	klog.InfoS("Suppressed a write in read-only mode", "method", req.Method, "path", req.URL.Path)
+
+	status := metav1.Status{TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}, Status: metav1.StatusFailure,
+		Code: http.StatusMethodNotAllowed, Reason: metav1.StatusReasonMethodNotAllowed,
+		Message: fmt.Sprintf("read-only mode: %s %s is not performed", req.Method, req.URL.Path)}
+	body, _ := json.Marshal(status)
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return &http.Response{StatusCode: http.StatusMethodNotAllowed, Status: "405 Method Not Allowed", Proto: req.Proto,
+		ProtoMajor: req.ProtoMajor, ProtoMinor: req.ProtoMinor, Header: http.Header{"Content-Type": []string{"application/json"}},
+		Body: ioutil.NopCloser(bytes.NewReader(body)), ContentLength: int64(len(body)), Request: req}, nil
+}
+
+// isWrite tells whether the request changes the cluster
+func isWrite(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	if req.URL.Query().Get("dryRun") == "All" {
+		return false
+	}
+	return !(req.Method == http.MethodPost && reviewResources[resourceOf(req.URL.Path)])
+}
+
+// resourceOf returns the resource of an API path such as /apis/<group>/<version>/namespaces/<namespace>/<resource>/<name>
+func resourceOf(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	// Drop the /api/<version> or /apis/<group>/<version> prefix
+	if len(segments) > 0 && segments[0] == "api" {
+		segments = segments[min(2, len(segments)):]
+	} else if len(segments) > 0 && segments[0] == "apis" {
+		segments = segments[min(3, len(segments)):]
+	}
+	if len(segments) >= 3 && segments[0] == "namespaces" && segments[2] != "status" && segments[2] != "finalize" {
+		segments = segments[2:]
+	}
+	if len(segments) == 0 {
+		return ""
+	}
+	if len(segments) >= 3 {
+		// A subresource such as status
+		return fmt.Sprintf("%s/%s", segments[0], segments[2])
+	}
+	return segments[0]
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// suppressedWrites counts the writes refused in read-only mode by verb and resource
+var suppressedWrites = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "edgenet_readonly_suppressed_writes_total",
+	Help: "Number of writes refused in read-only mode, a sign of drift between the desired and live state."}, []string{"verb", "resource"})
+
+func init() {
+	metrics.Registry.MustRegister(suppressedWrites)
+}
+
+var readOnlyMetricsOnce sync.Once
+
+// startReadOnlyMetrics serves the metrics of the process, the suppressed writes among them, once
+// per process
+func startReadOnlyMetrics() {
+	if ReadOnlyMetricsAddress == "" {
+		return
+	}
+	readOnlyMetricsOnce.Do(func() {
+		go func() {
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", metrics.Handler())
+			klog.ErrorS(http.ListenAndServe(ReadOnlyMetricsAddress, mux), "Couldn't serve the read-only metrics")
+		}()
+	})
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cli implements the subcommands of the kubectl-edgenet plugin, so that operators and
+// tenants administer the tenants without crafting the custom resources by hand.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/credential"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	"github.com/EdgeNet-project/edgenet/pkg/signature"
+	"github.com/EdgeNet-project/edgenet/pkg/usercert"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// RequestTenant creates the request of a tenant, it awaits the approval of the administrators
+func RequestTenant(ctx context.Context, edgenetclientset clientset.Interface, name string, spec registrationv1alpha.TenantRequestSpec) error {
+	tenantRequest := new(registrationv1alpha.TenantRequest)
+	tenantRequest.SetName(name)
+	tenantRequest.Spec = spec
+	// The approval is the administrators' call only
+	tenantRequest.Spec.Approved = false
+	tenantRequest.Spec.Approvals = nil
+	_, err := edgenetclientset.RegistrationV1alpha().TenantRequests().Create(ctx, tenantRequest, metav1.CreateOptions{})
+	return err
+}
+
+// ApproveRequest records the approval of the administrator on a tenant request, the request is
+// approved once the approvals reach the quorum of the cluster
+func ApproveRequest(ctx context.Context, edgenetclientset clientset.Interface, name, approver string) error {
+	_, err := edgenetclientset.RegistrationV1alpha().TenantRequests().Approve(ctx, name, approver)
+	return err
+}
+
+// ListTenants writes the tenants with their state
+func ListTenants(ctx context.Context, edgenetclientset clientset.Interface, out io.Writer) error {
+	tenants, err := edgenetclientset.CoreV1alpha().Tenants().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	sort.Slice(tenants.Items, func(i, j int) bool { return tenants.Items[i].GetName() < tenants.Items[j].GetName() })
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tFULL NAME\tENABLED\tSTATE\tMESSAGE")
+	for _, tenant := range tenants.Items {
+		state := tenant.Status.State
+		if state == "" {
+			state = "Pending"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%t\t%s\t%s\n", tenant.GetName(), tenant.Spec.FullName, tenant.Spec.Enabled, state, tenant.Status.Message)
+	}
+	return w.Flush()
+}
+
+// CreateSubNamespace creates a workspace in the namespace of a tenant, the workspace inherits the
+// RBAC, network policies and limit ranges of its parent. It is signed with a key of the tenant
+// if a signer is given.
+func CreateSubNamespace(ctx context.Context, edgenetclientset clientset.Interface, namespace, name string, allocation corev1.ResourceList, owner *corev1alpha.Contact, signer *signature.Signer) error {
+	subnamespace := new(corev1alpha.SubNamespace)
+	subnamespace.SetName(name)
+	subnamespace.SetNamespace(namespace)
+	subnamespace.Spec.Workspace = &corev1alpha.Workspace{
+		ResourceAllocation: allocation,
+		Inheritance:        map[string]bool{"rbac": true, "networkpolicy": true, "limitrange": true, "configmap": false, "secret": false, "serviceaccount": false},
+		Scope:              "local",
+		Owner:              owner,
+	}
+	if signer != nil {
+		if err := signer.Sign("SubNamespace", subnamespace, subnamespace.Spec); err != nil {
+			return err
+		}
+	}
+	_, err := edgenetclientset.CoreV1alpha().SubNamespaces(namespace).Create(ctx, subnamespace, metav1.CreateOptions{})
+	return err
+}
+
+// ParseAllocation parses the resource allocation given as a list of name=quantity pairs
+func ParseAllocation(pairs []string) (corev1.ResourceList, error) {
+	allocation := corev1.ResourceList{}
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid resource allocation %q, expected name=quantity", pair)
+		}
+		quantity, err := resource.ParseQuantity(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity of %s: %s", parts[0], err)
+		}
+		allocation[corev1.ResourceName(parts[0])] = quantity
+	}
+	return allocation, nil
+}
+
+// Kubeconfig returns the kubeconfig of a user for the cluster of the current context, the
+// credentials of the user are obtained through the credential plugin
+func Kubeconfig(current *clientcmdapi.Config, user, registrationServer string) ([]byte, error) {
+	if user == "" || registrationServer == "" {
+		return nil, fmt.Errorf("the user and the registration server are required")
+	}
+	currentContext, ok := current.Contexts[current.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("current context %q not found in the kubeconfig", current.CurrentContext)
+	}
+	cluster, ok := current.Clusters[currentContext.Cluster]
+	if !ok {
+		return nil, fmt.Errorf("cluster %q not found in the kubeconfig", currentContext.Cluster)
+	}
+	return credential.GenerateKubeconfig(currentContext.Cluster, cluster.Server, cluster.CertificateAuthorityData, user, registrationServer)
+}
+
+// ShowQuota writes the usage of the quota of each namespace of the tenant
+func ShowQuota(ctx context.Context, kubeclientset kubernetes.Interface, tenant string, out io.Writer) error {
+	namespaces, err := kubeclientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("edge-net.io/tenant=%s", tenant)})
+	if err != nil {
+		return err
+	}
+	if len(namespaces.Items) == 0 {
+		return fmt.Errorf("tenant %s has no namespaces", tenant)
+	}
+	sort.Slice(namespaces.Items, func(i, j int) bool { return namespaces.Items[i].GetName() < namespaces.Items[j].GetName() })
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tRESOURCE\tUSED\tHARD")
+	for _, namespace := range namespaces.Items {
+		// Each namespace is bound by the quota named after its kind
+		quota, err := kubeclientset.CoreV1().ResourceQuotas(namespace.GetName()).Get(ctx, fmt.Sprintf("%s-quota", namespace.GetLabels()["edge-net.io/kind"]), metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		names := []string{}
+		for name := range quota.Status.Hard {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			hard := quota.Status.Hard[corev1.ResourceName(name)]
+			used := quota.Status.Used[corev1.ResourceName(name)]
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", namespace.GetName(), name, used.String(), hard.String())
+		}
+	}
+	return w.Flush()
+}
+
+// ShowTree writes the subnamespaces below the namespace as a tree, each with the namespace it
+// formed. The subtenants are leaves, their subnamespaces are hidden from the parent.
+func ShowTree(ctx context.Context, edgenetclientset clientset.Interface, namespace string, out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tSUBNAMESPACE\tMODE\tDEPTH\tSTATE")
+	fmt.Fprintf(w, "%s\t\t\t\t\n", namespace)
+	// A namespace is listed once, even if the labels of the tree loop
+	visited := map[string]bool{namespace: true}
+	var walk func(namespace, indent string) error
+	walk = func(namespace, indent string) error {
+		subnamespaces, err := edgenetclientset.CoreV1alpha().SubNamespaces(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		sort.Slice(subnamespaces.Items, func(i, j int) bool { return subnamespaces.Items[i].GetName() < subnamespaces.Items[j].GetName() })
+		for i, subnamespace := range subnamespaces.Items {
+			branch, next := "|- ", "|  "
+			if i == len(subnamespaces.Items)-1 {
+				branch, next = "`- ", "   "
+			}
+			child, state := subnamespace.Status.Child, subnamespace.Status.State
+			if child == "" {
+				child = "<none>"
+			}
+			if state == "" {
+				state = "Pending"
+			}
+			fmt.Fprintf(w, "%s%s%s\t%s\t%s\t%d\t%s\n", indent, branch, child, subnamespace.GetName(), subnamespace.GetMode(), subnamespace.Status.Depth, state)
+			if subnamespace.GetMode() == "workspace" && subnamespace.Status.Child != "" && !visited[subnamespace.Status.Child] {
+				visited[subnamespace.Status.Child] = true
+				if err := walk(subnamespace.Status.Child, indent+next); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := walk(namespace, ""); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// ListCertificates writes the client certificates issued to the users of the tenant
+func ListCertificates(ctx context.Context, kubeclientset kubernetes.Interface, tenant string, out io.Writer) error {
+	certificates, err := usercert.List(ctx, kubeclientset, tenant)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tUSER\tEXPIRES\tREVOKED")
+	for _, certificate := range certificates {
+		expires := "<unknown>"
+		if !certificate.NotAfter.IsZero() {
+			expires = certificate.NotAfter.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\n", certificate.Name, certificate.User, expires, certificate.Revoked)
+	}
+	return w.Flush()
+}
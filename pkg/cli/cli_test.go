@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/credential"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	registrationclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/typed/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/usercert"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestRequestAndApprove(t *testing.T) {
+	edgenetclientset := edgenettestclient.NewSimpleClientset()
+	at := time.Date(2021, 10, 1, 9, 0, 0, 0, time.UTC)
+	registrationclient.Now = func() time.Time { return at }
+	defer func() { registrationclient.Now = time.Now }()
+
+	spec := registrationv1alpha.TenantRequestSpec{FullName: "LIP6", Contact: corev1alpha.Contact{Email: "john.doe@edge-net.org"}, Approved: true}
+	util.OK(t, RequestTenant(context.TODO(), edgenetclientset, "lip6", spec))
+	tenantRequest, err := edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), "lip6", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, "LIP6", tenantRequest.Spec.FullName)
+	util.Equals(t, false, tenantRequest.Spec.Approved)
+
+	util.OK(t, ApproveRequest(context.TODO(), edgenetclientset, "lip6", "admin@edge-net.org"))
+	tenantRequest, err = edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), "lip6", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, []registrationv1alpha.Approval{{Approver: "admin@edge-net.org", Time: metav1.NewTime(at), Verdict: "Approve"}}, tenantRequest.Spec.Approvals)
+
+	err = ApproveRequest(context.TODO(), edgenetclientset, "lip6", "Admin@edge-net.org")
+	util.Assert(t, err != nil, "second approval of the same administrator is recorded")
+	err = ApproveRequest(context.TODO(), edgenetclientset, "lip6", "")
+	util.Assert(t, err != nil, "approval without approver is recorded")
+
+	tenantRequest.Status.State = "Rejected"
+	_, err = edgenetclientset.RegistrationV1alpha().TenantRequests().Update(context.TODO(), tenantRequest, metav1.UpdateOptions{})
+	util.OK(t, err)
+	err = ApproveRequest(context.TODO(), edgenetclientset, "lip6", "root@edge-net.org")
+	util.Assert(t, err != nil, "rejected request is approved")
+}
+
+func TestListTenants(t *testing.T) {
+	edgenetclientset := edgenettestclient.NewSimpleClientset(
+		&corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "nyu"}, Spec: corev1alpha.TenantSpec{FullName: "NYU"}},
+		&corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "lip6"}, Spec: corev1alpha.TenantSpec{FullName: "LIP6", Enabled: true},
+			Status: corev1alpha.TenantStatus{State: "Established", Message: "Tenant established"}})
+	out := &bytes.Buffer{}
+	util.OK(t, ListTenants(context.TODO(), edgenetclientset, out))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	util.Equals(t, 3, len(lines))
+	util.Equals(t, []string{"lip6", "LIP6", "true", "Established", "Tenant", "established"}, strings.Fields(lines[1]))
+	util.Equals(t, []string{"nyu", "NYU", "false", "Pending"}, strings.Fields(lines[2]))
+}
+
+func TestCreateSubNamespace(t *testing.T) {
+	edgenetclientset := edgenettestclient.NewSimpleClientset()
+	allocation, err := ParseAllocation([]string{"cpu=2", "memory=2Gi"})
+	util.OK(t, err)
+	util.OK(t, CreateSubNamespace(context.TODO(), edgenetclientset, "lip6", "experiments", allocation, nil, nil))
+	subnamespace, err := edgenetclientset.CoreV1alpha().SubNamespaces("lip6").Get(context.TODO(), "experiments", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Assert(t, subnamespace.Spec.Workspace != nil, "subnamespace is not a workspace")
+	cpu := subnamespace.Spec.Workspace.ResourceAllocation[corev1.ResourceCPU]
+	util.Equals(t, "2", cpu.String())
+	util.Equals(t, true, subnamespace.Spec.Workspace.Inheritance["rbac"])
+
+	_, err = ParseAllocation([]string{"cpu"})
+	util.Assert(t, err != nil, "allocation without quantity is parsed")
+	_, err = ParseAllocation([]string{"memory=lots"})
+	util.Assert(t, err != nil, "invalid quantity is parsed")
+}
+
+func TestKubeconfig(t *testing.T) {
+	current := clientcmdapi.NewConfig()
+	current.Clusters["edgenet"] = &clientcmdapi.Cluster{Server: "https://api.edge-net.org:6443", CertificateAuthorityData: []byte("ca")}
+	current.Contexts["admin@edgenet"] = &clientcmdapi.Context{Cluster: "edgenet", AuthInfo: "admin"}
+	current.CurrentContext = "admin@edgenet"
+
+	kubeconfig, err := Kubeconfig(current, "johndoe", "https://registration.edge-net.org")
+	util.OK(t, err)
+	config, err := clientcmd.Load(kubeconfig)
+	util.OK(t, err)
+	util.Equals(t, "johndoe@edgenet", config.CurrentContext)
+	util.Equals(t, "https://api.edge-net.org:6443", config.Clusters["edgenet"].Server)
+
+	_, err = Kubeconfig(current, "", "https://registration.edge-net.org")
+	util.Assert(t, err != nil, "kubeconfig without user is generated")
+	current.CurrentContext = "missing"
+	_, err = Kubeconfig(current, "johndoe", "https://registration.edge-net.org")
+	util.Assert(t, err != nil, "kubeconfig without current context is generated")
+}
+
+func TestShowQuota(t *testing.T) {
+	kubeclientset := testclient.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "lip6", Labels: map[string]string{"edge-net.io/kind": "core", "edge-net.io/tenant": "lip6"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "experiments", Labels: map[string]string{"edge-net.io/kind": "sub", "edge-net.io/tenant": "lip6"}}},
+		&corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: "core-quota", Namespace: "lip6"},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8"), corev1.ResourceMemory: resource.MustParse("8Gi")},
+				Used: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")}}})
+	out := &bytes.Buffer{}
+	util.OK(t, ShowQuota(context.TODO(), kubeclientset, "lip6", out))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	util.Equals(t, 3, len(lines))
+	util.Equals(t, []string{"lip6", "cpu", "500m", "8"}, strings.Fields(lines[1]))
+	util.Equals(t, []string{"lip6", "memory", "0", "8Gi"}, strings.Fields(lines[2]))
+
+	err := ShowQuota(context.TODO(), kubeclientset, "nyu", out)
+	util.Assert(t, err != nil, "quota of a tenant without namespaces is shown")
+}
+
+func TestShowTree(t *testing.T) {
+	workspace := func(namespace, name, child string, depth int) *corev1alpha.SubNamespace {
+		return &corev1alpha.SubNamespace{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:   corev1alpha.SubNamespaceSpec{Workspace: &corev1alpha.Workspace{Scope: "local"}},
+			Status: corev1alpha.SubNamespaceStatus{State: "Established", Child: child, Depth: depth}}
+	}
+	spinoff := &corev1alpha.SubNamespace{ObjectMeta: metav1.ObjectMeta{Name: "spinoff", Namespace: "lip6"},
+		Spec: corev1alpha.SubNamespaceSpec{Subtenant: &corev1alpha.Subtenant{}}}
+	edgenetclientset := edgenettestclient.NewSimpleClientset(
+		workspace("lip6", "lab", "lab-1a2b3c", 1),
+		workspace("lab-1a2b3c", "course", "course-4d5e6f", 2),
+		workspace("course-4d5e6f", "loop", "lab-1a2b3c", 3),
+		spinoff,
+	)
+	out := &bytes.Buffer{}
+	util.OK(t, ShowTree(context.TODO(), edgenetclientset, "lip6", out))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	util.Equals(t, 6, len(lines))
+	util.Equals(t, []string{"lip6"}, strings.Fields(lines[1]))
+	util.Equals(t, []string{"|-", "lab-1a2b3c", "lab", "workspace", "1", "Established"}, strings.Fields(lines[2]))
+	util.Equals(t, []string{"|", "`-", "course-4d5e6f", "course", "workspace", "2", "Established"}, strings.Fields(lines[3]))
+	util.Equals(t, []string{"|", "`-", "lab-1a2b3c", "loop", "workspace", "3", "Established"}, strings.Fields(lines[4]))
+	util.Equals(t, []string{"`-", "<none>", "spinoff", "subtenant", "0", "Pending"}, strings.Fields(lines[5]))
+}
+
+func TestListCertificates(t *testing.T) {
+	kubeclientset := testclient.NewSimpleClientset(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "certificate-0a1b2c3d", Namespace: "lip6",
+			Labels:      map[string]string{credential.LabelCredential: "certificate"},
+			Annotations: map[string]string{credential.AnnotationUser: "john.doe@edge-net.org", usercert.AnnotationID: "0a1b2c3d", usercert.AnnotationRevoked: "2021-10-01T09:00:00Z"}}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig", Namespace: "lip6", Labels: map[string]string{credential.LabelCredential: "kubeconfig"}}})
+	out := &bytes.Buffer{}
+	util.OK(t, ListCertificates(context.TODO(), kubeclientset, "lip6", out))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	util.Equals(t, 2, len(lines))
+	util.Equals(t, []string{"certificate-0a1b2c3d", "john.doe@edge-net.org", "<unknown>", "true"}, strings.Fields(lines[1]))
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cni
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// ConfigMapName is the name of the config map in kube-system that holds the detected capabilities
+const ConfigMapName = "edgenet-network-capabilities"
+
+// The network policy support that operators can declare, it is detected by default
+const (
+	SupportAuto      = "auto"
+	SupportFull      = "full"
+	SupportNoEndPort = "no-endport"
+	SupportNone      = "none"
+)
+
+// Support overrides the detection of the network policy support, for a plugin that is not
+// recognized among the daemon sets. It is one of the support constants, set by a flag.
+var Support = SupportAuto
+
+// Capabilities describes what the installed CNI plugin does with network policies
+type Capabilities struct {
+	// Name of the plugin, empty if the plugin couldn't be recognized
+	Plugin string
+	// Whether the support is declared by the operators rather than detected
+	Declared bool
+	// Whether the plugin enforces network policies
+	NetworkPolicy bool
+	// Whether port ranges in network policies are both accepted by the API server and
+	// enforced by the plugin
+	EndPort bool
+}
+
+// plugin holds the known behavior of a CNI plugin
+type plugin struct {
+	name          string
+	networkPolicy bool
+	endPort       bool
+}
+
+// The order matters as canal bundles flannel, and the more capable plugin must win
+var plugins = []plugin{
+	{name: "calico", networkPolicy: true, endPort: true},
+	{name: "canal", networkPolicy: true, endPort: true},
+	{name: "cilium", networkPolicy: true, endPort: false},
+	{name: "antrea", networkPolicy: true, endPort: true},
+	{name: "weave", networkPolicy: true, endPort: false},
+	{name: "kube-router", networkPolicy: true, endPort: false},
+	{name: "flannel", networkPolicy: false, endPort: false},
+}
+
+// Unknown returns true if neither the plugin was recognized nor the support was declared, the
+// tenants are then neither degraded nor enforced
+func (c Capabilities) Unknown() bool {
+	return c.Plugin == "" && !c.Declared
+}
+
+// Degraded returns true if the network policies created for tenants do not isolate them
+func (c Capabilities) Degraded() bool {
+	return !c.Unknown() && (!c.NetworkPolicy || !c.EndPort)
+}
+
+// Reason returns a human readable explanation of the degradation
+func (c Capabilities) Reason() string {
+	if c.Unknown() {
+		return "The CNI plugin is not recognized, its support of network policies can be declared with --network-policy-support"
+	}
+	plugin := c.Plugin
+	if plugin == "" {
+		plugin = "declared"
+	}
+	if !c.NetworkPolicy {
+		return fmt.Sprintf("The CNI plugin (%s) does not enforce network policies", plugin)
+	}
+	if !c.EndPort {
+		return fmt.Sprintf("The CNI plugin (%s) or the API server does not support port ranges in network policies", plugin)
+	}
+	return fmt.Sprintf("The CNI plugin (%s) enforces network policies", plugin)
+}
+
+// Detect inspects the daemon sets in kube-system to find out which CNI plugin runs in the
+// cluster, unless the operators declared the support, and verifies with a dry run whether the
+// API server keeps the port range field.
+func Detect(ctx context.Context, clientset kubernetes.Interface) (Capabilities, error) {
+	capabilities := Capabilities{}
+	switch Support {
+	case SupportAuto:
+	case SupportFull, SupportNoEndPort, SupportNone:
+		capabilities.Declared = true
+		capabilities.NetworkPolicy = Support != SupportNone
+		capabilities.EndPort = Support == SupportFull
+	default:
+		return capabilities, fmt.Errorf("unknown network policy support %q", Support)
+	}
+	daemonSetRaw, err := clientset.AppsV1().DaemonSets("kube-system").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return capabilities, err
+	}
+	for _, plugin := range plugins {
+		for _, daemonSetRow := range daemonSetRaw.Items {
+			if strings.Contains(daemonSetRow.GetName(), plugin.name) {
+				capabilities.Plugin = plugin.name
+				if !capabilities.Declared {
+					capabilities.NetworkPolicy = plugin.networkPolicy
+					capabilities.EndPort = plugin.endPort
+				}
+				break
+			}
+		}
+		if capabilities.Plugin != "" {
+			break
+		}
+	}
+	if capabilities.EndPort {
+		capabilities.EndPort, err = endPortAccepted(ctx, clientset)
+		if err != nil {
+			klog.ErrorS(err, "Couldn't verify the port range support")
+		}
+	}
+	return capabilities, nil
+}
+
+// endPortAccepted creates a network policy with a port range in dry run mode. The API server
+// silently drops the field when the feature gate is disabled.
+func endPortAccepted(ctx context.Context, clientset kubernetes.Interface) (bool, error) {
+	networkPolicy := new(networkingv1.NetworkPolicy)
+	networkPolicy.SetName("edgenet-endport-probe")
+	networkPolicy.Spec.PolicyTypes = []networkingv1.PolicyType{"Ingress"}
+	port := intstr.FromInt(30000)
+	endPort := int32(32768)
+	networkPolicy.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{{Ports: []networkingv1.NetworkPolicyPort{{Port: &port, EndPort: &endPort}}}}
+	result, err := clientset.NetworkingV1().NetworkPolicies("kube-system").Create(ctx, networkPolicy, metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}})
+	if err != nil {
+		return false, err
+	}
+	if len(result.Spec.Ingress) == 0 || len(result.Spec.Ingress[0].Ports) == 0 || result.Spec.Ingress[0].Ports[0].EndPort == nil {
+		return false, nil
+	}
+	return true, nil
+}
+
+// Record stores the capabilities in a config map in kube-system so that they are visible
+// cluster-wide
+func Record(ctx context.Context, clientset kubernetes.Interface, capabilities Capabilities) error {
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: "kube-system",
+		Labels: map[string]string{"edge-net.io/generated": "true"}}}
+	configMap.Data = map[string]string{
+		"plugin":        capabilities.Plugin,
+		"networkPolicy": strconv.FormatBool(capabilities.NetworkPolicy),
+		"endPort":       strconv.FormatBool(capabilities.EndPort),
+		"declared":      strconv.FormatBool(capabilities.Declared),
+		"unknown":       strconv.FormatBool(capabilities.Unknown()),
+		"degraded":      strconv.FormatBool(capabilities.Degraded()),
+		"message":       capabilities.Reason(),
+	}
+	if _, err := clientset.CoreV1().ConfigMaps("kube-system").Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return err
+		}
+		currentConfigMap, err := clientset.CoreV1().ConfigMaps("kube-system").Get(ctx, ConfigMapName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		currentConfigMap.Data = configMap.Data
+		if _, err := clientset.CoreV1().ConfigMaps("kube-system").Update(ctx, currentConfigMap, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
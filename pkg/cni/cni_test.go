@@ -0,0 +1,68 @@
+package cni
+
+import (
+	"context"
+	"testing"
+
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDetect(t *testing.T) {
+	defer func() { Support = SupportAuto }()
+	cases := map[string]struct {
+		support    string
+		daemonSets []string
+		plugin     string
+		unknown    bool
+		degraded   bool
+	}{
+		"none":             {SupportAuto, []string{"kube-proxy"}, "", true, false},
+		"flannel":          {SupportAuto, []string{"kube-proxy", "kube-flannel-ds"}, "flannel", false, true},
+		"canal":            {SupportAuto, []string{"kube-proxy", "canal", "kube-flannel-ds"}, "canal", false, false},
+		"calico":           {SupportAuto, []string{"kube-proxy", "calico-node"}, "calico", false, false},
+		"cilium":           {SupportAuto, []string{"cilium"}, "cilium", false, true},
+		"declared full":    {SupportFull, []string{"kube-proxy", "ovn-kubernetes"}, "", false, false},
+		"declared none":    {SupportNone, []string{"kube-proxy", "calico-node"}, "calico", false, true},
+		"declared endport": {SupportNoEndPort, []string{"kube-proxy"}, "", false, true},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			Support = tc.support
+			clientset := testclient.NewSimpleClientset()
+			for _, name := range tc.daemonSets {
+				daemonSet := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "kube-system"}}
+				_, err := clientset.AppsV1().DaemonSets("kube-system").Create(context.TODO(), daemonSet, metav1.CreateOptions{})
+				util.OK(t, err)
+			}
+			capabilities, err := Detect(context.TODO(), clientset)
+			util.OK(t, err)
+			util.Equals(t, tc.plugin, capabilities.Plugin)
+			util.Equals(t, tc.unknown, capabilities.Unknown())
+			util.Equals(t, tc.degraded, capabilities.Degraded())
+		})
+	}
+}
+
+func TestRecord(t *testing.T) {
+	clientset := testclient.NewSimpleClientset()
+	capabilities := Capabilities{Plugin: "flannel"}
+	util.OK(t, Record(context.TODO(), clientset, capabilities))
+	capabilities = Capabilities{Plugin: "calico", NetworkPolicy: true, EndPort: true}
+	util.OK(t, Record(context.TODO(), clientset, capabilities))
+
+	configMap, err := clientset.CoreV1().ConfigMaps("kube-system").Get(context.TODO(), ConfigMapName, metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, "calico", configMap.Data["plugin"])
+	util.Equals(t, "false", configMap.Data["degraded"])
+}
+
+func TestUnknownSupport(t *testing.T) {
+	Support = "partial"
+	defer func() { Support = SupportAuto }()
+	_, err := Detect(context.TODO(), testclient.NewSimpleClientset())
+	util.Assert(t, err != nil, "unknown support accepted")
+}
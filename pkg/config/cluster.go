@@ -0,0 +1,91 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config holds the configuration shared by the components of EdgeNet across the cluster.
+package config
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ClusterIDName is the config map in kube-system that records the identity of the cluster
+const ClusterIDName = "edgenet-cluster-id"
+
+// clusterUIDKey is the key of the config map holding the UID of the cluster
+const clusterUIDKey = "cluster-uid"
+
+// ClusterIdentity is the UID that the objects of EdgeNet are labeled with as edge-net.io/cluster-uid.
+// It is persisted in a config map rather than read from the kube-system namespace, so that the
+// labels survive a cluster rebuilt or restored elsewhere. The config map is seeded with the UID of
+// the kube-system namespace, which identified the cluster before.
+type ClusterIdentity struct {
+	kubeclientset kubernetes.Interface
+	mutex         sync.Mutex
+	uid           string
+}
+
+// NewClusterIdentity returns the identity of the cluster the clientset talks to, the components
+// hold one for as long as they run so that the UID is read once
+func NewClusterIdentity(kubeclientset kubernetes.Interface) *ClusterIdentity {
+	return &ClusterIdentity{kubeclientset: kubeclientset}
+}
+
+// ClusterUID reads the UID of the cluster the clientset talks to, for the commands that need it once
+func ClusterUID(ctx context.Context, kubeclientset kubernetes.Interface) (string, error) {
+	return NewClusterIdentity(kubeclientset).UID(ctx)
+}
+
+// UID returns the UID of the cluster, the config map is created on first use
+func (i *ClusterIdentity) UID(ctx context.Context) (string, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+	if i.uid != "" {
+		return i.uid, nil
+	}
+	clusterID, err := i.kubeclientset.CoreV1().ConfigMaps("kube-system").Get(ctx, ClusterIDName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		systemNamespace, err := i.kubeclientset.CoreV1().Namespaces().Get(ctx, "kube-system", metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		clusterID = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: ClusterIDName, Namespace: "kube-system",
+			Labels: map[string]string{"edge-net.io/generated": "true"}},
+			Data: map[string]string{clusterUIDKey: string(systemNamespace.GetUID())}}
+		clusterID, err = i.kubeclientset.CoreV1().ConfigMaps("kube-system").Create(ctx, clusterID, metav1.CreateOptions{})
+		if errors.IsAlreadyExists(err) {
+			// Another component seeded it meanwhile
+			clusterID, err = i.kubeclientset.CoreV1().ConfigMaps("kube-system").Get(ctx, ClusterIDName, metav1.GetOptions{})
+		}
+		if err != nil {
+			return "", err
+		}
+	} else if err != nil {
+		return "", err
+	}
+	uid := clusterID.Data[clusterUIDKey]
+	if uid == "" {
+		return "", fmt.Errorf("config map %s has no %s", ClusterIDName, clusterUIDKey)
+	}
+	i.uid = uid
+	return uid, nil
+}
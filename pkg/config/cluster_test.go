@@ -0,0 +1,39 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestClusterUID(t *testing.T) {
+	kubeclientset := testclient.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", UID: "cluster-uid"}})
+	uid, err := ClusterUID(context.TODO(), kubeclientset)
+	util.OK(t, err)
+	util.Equals(t, "cluster-uid", uid)
+	clusterID, err := kubeclientset.CoreV1().ConfigMaps("kube-system").Get(context.TODO(), ClusterIDName, metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, "cluster-uid", clusterID.Data[clusterUIDKey])
+
+	// The identity outlives the kube-system namespace it was seeded with
+	restored := testclient.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", UID: "new-uid"}}, clusterID)
+	uid, err = ClusterUID(context.TODO(), restored)
+	util.OK(t, err)
+	util.Equals(t, "cluster-uid", uid)
+
+	util.OK(t, kubeclientset.CoreV1().ConfigMaps("kube-system").Delete(context.TODO(), ClusterIDName, metav1.DeleteOptions{}))
+	uid, err = ClusterUID(context.TODO(), kubeclientset)
+	util.OK(t, err)
+	util.Equals(t, "cluster-uid", uid)
+}
+
+func TestClusterUIDMissing(t *testing.T) {
+	kubeclientset := testclient.NewSimpleClientset(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: ClusterIDName, Namespace: "kube-system"}})
+	_, err := ClusterUID(context.TODO(), kubeclientset)
+	util.Assert(t, err != nil, "an empty cluster identity is accepted")
+}
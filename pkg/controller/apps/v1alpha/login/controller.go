@@ -0,0 +1,540 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package login
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/apps/v1alpha"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/apps/v1alpha"
+	coreinformers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/apps/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/index"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const controllerAgentName = "login-controller"
+
+// Definitions of the state of the login resource
+const (
+	successSynced         = "Synced"
+	messageResourceSynced = "Login synced successfully"
+	successEnded          = "Ended"
+	messageExpired        = "Session expired"
+	messageDisabled       = "Session ended as the tenant is disabled"
+	messageActive         = "Roles of the user bound in %d namespaces"
+	failureTenant         = "Not Found"
+	messageTenantNotFound = "The namespace is not the core namespace of a tenant"
+	failureServiceAccount = "Creation Failed"
+	messageServiceAccount = "Service account creation failed"
+	messageNotOwned       = "Service account %s exists and does not belong to the session"
+	failureBinding        = "Binding Failed"
+	messageBindingFailed  = "Role binding failed in %s"
+	messageNoRole         = "User %s holds no role in the tenant"
+	active                = "Active"
+	failure               = "Failure"
+)
+
+// LoginLabel marks the service account and the role bindings of a session with the name of its login
+const LoginLabel = "edge-net.io/login"
+
+// SyncPeriod is the time after which the roles of the user are bound again to the session, so that
+// the session loses the roles taken back from the user
+var SyncPeriod = time.Minute
+
+// Controller is the controller implementation for Login resources
+type Controller struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+	// edgenetclientset is a clientset for the EdgeNet API groups
+	edgenetclientset clientset.Interface
+
+	loginsLister  listers.LoginLister
+	loginsSynced  cache.InformerSynced
+	tenantsSynced cache.InformerSynced
+
+	// workqueue is a rate limited work queue. This is used to queue work to be
+	// processed instead of performing it as soon as a change happens. This
+	// means we can ensure we only process a fixed amount of resources at a
+	// time, and makes it easy to ensure we are never processing the same item
+	// simultaneously in two different workers.
+	workqueue workqueue.RateLimitingInterface
+	// recorder is an event recorder for recording Event resources to the
+	// Kubernetes API.
+	recorder record.EventRecorder
+}
+
+// NewController returns a new controller
+func NewController(
+	ctx context.Context,
+	kubeclientset kubernetes.Interface,
+	edgenetclientset clientset.Interface,
+	loginInformer informers.LoginInformer,
+	tenantInformer coreinformers.TenantInformer) *Controller {
+
+	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
+	klog.V(4).InfoS("Creating event broadcaster")
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartStructuredLogging(0)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+
+	controller := &Controller{
+		kubeclientset:    kubeclientset,
+		edgenetclientset: edgenetclientset,
+		loginsLister:     loginInformer.Lister(),
+		loginsSynced:     loginInformer.Informer().HasSynced,
+		tenantsSynced:    tenantInformer.Informer().HasSynced,
+		workqueue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Logins"),
+		recorder:         recorder,
+	}
+
+	klog.V(4).InfoS("Setting up event handlers")
+	// Set up an event handler for when Login resources change
+	loginInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueLogin,
+		UpdateFunc: func(old, new interface{}) {
+			newObj := new.(*appsv1alpha.Login)
+			oldObj := old.(*appsv1alpha.Login)
+			if !reflect.DeepEqual(newObj.Spec, oldObj.Spec) {
+				controller.enqueueLogin(new)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			// The service account and the bindings in the core namespace are garbage collected,
+			// which revokes the tokens of the session. The other bindings cannot have an owner
+			// in another namespace.
+			login, ok := obj.(*appsv1alpha.Login)
+			if !ok {
+				return
+			}
+			controller.unbind(ctx, login)
+		},
+	})
+	// The sessions end as soon as their tenant gets disabled
+	tenantInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			newObj := new.(*corev1alpha.Tenant)
+			oldObj := old.(*corev1alpha.Tenant)
+			if newObj.Spec.Enabled != oldObj.Spec.Enabled {
+				controller.handleTenant(newObj)
+			}
+		},
+	})
+
+	return controller
+}
+
+// Run will set up the event handlers for the types of login, as well
+// as syncing informer caches and starting workers. It will block until stopCh
+// is closed, at which point it will shutdown the workqueue and wait for
+// workers to finish processing their current work items.
+func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting Login controller")
+
+	klog.V(4).InfoS("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh,
+		c.loginsSynced,
+		c.tenantsSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	klog.V(4).InfoS("Starting workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	klog.V(4).InfoS("Started workers")
+	<-stopCh
+	klog.V(4).InfoS("Shutting down workers")
+
+	return nil
+}
+
+// runWorker is a long-running function that will continually call the
+// processNextWorkItem function in order to read and process a message on the
+// workqueue.
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+// processNextWorkItem will read a single work item off the workqueue and
+// attempt to process it, by calling the syncHandler.
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.workqueue.Done(obj)
+		var key string
+		var ok bool
+
+		if key, ok = obj.(string); !ok {
+			c.workqueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			return nil
+		}
+		if err := c.syncHandler(ctx, key); err != nil {
+			c.workqueue.AddRateLimited(key)
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
+		}
+		c.workqueue.Forget(obj)
+		klog.V(4).InfoS("Successfully synced", "key", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+
+	return true
+}
+
+// syncHandler compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the Login
+// resource with the current status of the resource.
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	login, err := c.loginsLister.Logins(namespace).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("login '%s' in work queue no longer exists", key))
+			return nil
+		}
+
+		return err
+	}
+
+	loginCopy := login.DeepCopy()
+	next, ended := c.processLogin(ctx, loginCopy)
+	if ended {
+		// The service account goes away with the login, and its tokens along with it
+		if err := c.edgenetclientset.AppsV1alpha().Logins(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+	if !reflect.DeepEqual(login.Status, loginCopy.Status) {
+		if _, err := c.edgenetclientset.AppsV1alpha().Logins(namespace).UpdateStatus(ctx, loginCopy, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	c.recorder.Event(login, corev1.EventTypeNormal, successSynced, messageResourceSynced)
+	c.workqueue.AddAfter(key, next)
+	return nil
+}
+
+// enqueueLogin takes a Login resource and converts it into a namespace/name
+// string which is then put onto the work queue. This method should *not* be
+// passed resources of any type other than Login.
+func (c *Controller) enqueueLogin(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+// handleTenant enqueues the sessions in the core namespace of a tenant
+func (c *Controller) handleTenant(tenant *corev1alpha.Tenant) {
+	logins, err := c.loginsLister.Logins(tenant.GetName()).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, login := range logins {
+		c.enqueueLogin(login)
+	}
+}
+
+// processLogin binds the roles that the user holds in the tenant to the service account of the
+// session. It returns the time after which the session is to be synced again, or whether the
+// session has ended.
+func (c *Controller) processLogin(ctx context.Context, loginCopy *appsv1alpha.Login) (time.Duration, bool) {
+	if loginCopy.Spec.Expiry == nil || !loginCopy.Spec.Expiry.After(time.Now()) {
+		c.recorder.Event(loginCopy, corev1.EventTypeNormal, successEnded, messageExpired)
+		return 0, true
+	}
+	next := time.Until(loginCopy.Spec.Expiry.Time)
+	if next > SyncPeriod {
+		next = SyncPeriod
+	}
+
+	namespace := loginCopy.GetNamespace()
+	// The core namespace has the same name as the tenant
+	tenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Couldn't get the tenant", "tenant", namespace)
+		c.fail(loginCopy, failureTenant, messageTenantNotFound)
+		return next, false
+	}
+	if !tenant.Spec.Enabled {
+		c.recorder.Event(loginCopy, corev1.EventTypeNormal, successEnded, messageDisabled)
+		return 0, true
+	}
+
+	if err := c.applyServiceAccount(ctx, loginCopy); err != nil {
+		klog.ErrorS(err, "Couldn't apply the service account", "login", klog.KObj(loginCopy))
+		message := messageServiceAccount
+		if err == errNotOwned {
+			message = fmt.Sprintf(messageNotOwned, loginCopy.GetName())
+		}
+		c.fail(loginCopy, failureServiceAccount, message)
+		return next, false
+	}
+	if message := c.bind(ctx, loginCopy); message != "" {
+		c.fail(loginCopy, failureBinding, message)
+	} else if len(loginCopy.Status.Namespaces) == 0 {
+		c.fail(loginCopy, failureBinding, fmt.Sprintf(messageNoRole, loginCopy.Spec.User))
+	} else {
+		loginCopy.Status.State = active
+		loginCopy.Status.Message = fmt.Sprintf(messageActive, len(loginCopy.Status.Namespaces))
+	}
+	return next, false
+}
+
+// errNotOwned tells that a service account of the same name was not created for the session
+var errNotOwned = fmt.Errorf("service account not owned by the login")
+
+// applyServiceAccount creates the service account of the session in the core namespace
+func (c *Controller) applyServiceAccount(ctx context.Context, loginCopy *appsv1alpha.Login) error {
+	namespace := loginCopy.GetNamespace()
+	serviceAccount, err := c.kubeclientset.CoreV1().ServiceAccounts(namespace).Get(ctx, loginCopy.GetName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		serviceAccount = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: loginCopy.GetName(), Namespace: namespace,
+			OwnerReferences: ownerReferences(loginCopy), Labels: loginLabels(loginCopy)}}
+		// The tokens are issued by the registration API alone, up to the expiry of the session
+		automount := false
+		serviceAccount.AutomountServiceAccountToken = &automount
+		if _, err := c.kubeclientset.CoreV1().ServiceAccounts(namespace).Create(ctx, serviceAccount, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	} else if !metav1.IsControlledBy(serviceAccount, loginCopy) {
+		return errNotOwned
+	}
+	loginCopy.Status.ServiceAccount = loginCopy.GetName()
+	return nil
+}
+
+// bind mirrors the role bindings and the cluster role bindings of the tenant that name the user
+// onto the service account of the session, and removes the mirrors of the bindings that no longer
+// name the user. It returns a message if a binding failed.
+func (c *Controller) bind(ctx context.Context, loginCopy *appsv1alpha.Login) string {
+	tenant := loginCopy.GetNamespace()
+	subject := rbacv1.Subject{Kind: "ServiceAccount", Name: loginCopy.GetName(), Namespace: tenant}
+	selector := labels.Set{LoginLabel: loginCopy.GetName(), index.LabelTenant: tenant}.String()
+	messages := []string{}
+
+	namespaces, err := c.kubeclientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: labels.Set{index.LabelTenant: tenant}.String()})
+	if err != nil {
+		klog.ErrorS(err, "Couldn't list the namespaces of the tenant", "tenant", tenant)
+		return fmt.Sprintf(messageBindingFailed, tenant)
+	}
+	bound := []string{}
+	for _, namespace := range namespaces.Items {
+		roleBindings, err := c.kubeclientset.RbacV1().RoleBindings(namespace.GetName()).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			klog.ErrorS(err, "Couldn't list the role bindings", "namespace", namespace.GetName())
+			messages = append(messages, fmt.Sprintf(messageBindingFailed, namespace.GetName()))
+			continue
+		}
+		desired := map[string]bool{}
+		failed := false
+		for _, roleBinding := range roleBindings.Items {
+			if _, ok := roleBinding.GetLabels()[LoginLabel]; ok || !names(roleBinding.Subjects, loginCopy.Spec.User) {
+				continue
+			}
+			mirror := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: bindingName(loginCopy, roleBinding.RoleRef), Namespace: namespace.GetName(),
+				Labels: loginLabels(loginCopy)},
+				Subjects: []rbacv1.Subject{subject}, RoleRef: roleBinding.RoleRef}
+			if namespace.GetName() == tenant {
+				mirror.SetOwnerReferences(ownerReferences(loginCopy))
+			}
+			if _, err := c.kubeclientset.RbacV1().RoleBindings(namespace.GetName()).Create(ctx, mirror, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+				klog.ErrorS(err, "Couldn't create the role binding", "roleBinding", klog.KObj(mirror))
+				failed = true
+				continue
+			}
+			desired[mirror.GetName()] = true
+		}
+		// The roles taken back from the user are taken back from the session
+		mirrors, err := c.kubeclientset.RbacV1().RoleBindings(namespace.GetName()).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err == nil {
+			for _, mirror := range mirrors.Items {
+				if !desired[mirror.GetName()] {
+					if err := c.kubeclientset.RbacV1().RoleBindings(namespace.GetName()).Delete(ctx, mirror.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+						failed = true
+					}
+				}
+			}
+		} else {
+			failed = true
+		}
+		if failed {
+			messages = append(messages, fmt.Sprintf(messageBindingFailed, namespace.GetName()))
+		}
+		if len(desired) > 0 {
+			bound = append(bound, namespace.GetName())
+		}
+	}
+	sort.Strings(bound)
+	loginCopy.Status.Namespaces = bound
+
+	// The cluster roles of the tenant give access to the tenant object and the like
+	clusterRoleBindings, err := c.kubeclientset.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{LabelSelector: labels.Set{index.LabelTenant: tenant}.String()})
+	if err != nil {
+		klog.ErrorS(err, "Couldn't list the cluster role bindings of the tenant", "tenant", tenant)
+		return strings.Join(append(messages, fmt.Sprintf(messageBindingFailed, "the cluster scope")), ", ")
+	}
+	desired := map[string]bool{}
+	failed := false
+	for _, clusterRoleBinding := range clusterRoleBindings.Items {
+		if _, ok := clusterRoleBinding.GetLabels()[LoginLabel]; ok || !names(clusterRoleBinding.Subjects, loginCopy.Spec.User) {
+			continue
+		}
+		// Cluster-scoped objects cannot be owned by the login, their mirrors are removed along with it
+		mirror := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: clusterBindingName(loginCopy, clusterRoleBinding.RoleRef),
+			Labels: loginLabels(loginCopy)},
+			Subjects: []rbacv1.Subject{subject}, RoleRef: clusterRoleBinding.RoleRef}
+		if _, err := c.kubeclientset.RbacV1().ClusterRoleBindings().Create(ctx, mirror, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			klog.ErrorS(err, "Couldn't create the cluster role binding", "clusterRoleBinding", klog.KObj(mirror))
+			failed = true
+			continue
+		}
+		desired[mirror.GetName()] = true
+	}
+	if mirrors, err := c.kubeclientset.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{LabelSelector: selector}); err == nil {
+		for _, mirror := range mirrors.Items {
+			if !desired[mirror.GetName()] {
+				if err := c.kubeclientset.RbacV1().ClusterRoleBindings().Delete(ctx, mirror.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+					failed = true
+				}
+			}
+		}
+	} else {
+		failed = true
+	}
+	if failed {
+		messages = append(messages, fmt.Sprintf(messageBindingFailed, "the cluster scope"))
+	}
+	return strings.Join(messages, ", ")
+}
+
+// unbind removes the bindings of an ended session from the subsidiary namespaces and the cluster scope
+func (c *Controller) unbind(ctx context.Context, login *appsv1alpha.Login) {
+	selector := labels.Set{LoginLabel: login.GetName(), index.LabelTenant: login.GetNamespace()}.String()
+	for _, namespace := range login.Status.Namespaces {
+		if namespace == login.GetNamespace() {
+			continue
+		}
+		roleBindings, err := c.kubeclientset.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			klog.ErrorS(err, "Couldn't list the role bindings", "namespace", namespace, "login", klog.KObj(login))
+			continue
+		}
+		for _, roleBinding := range roleBindings.Items {
+			if err := c.kubeclientset.RbacV1().RoleBindings(namespace).Delete(ctx, roleBinding.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				klog.ErrorS(err, "Couldn't delete the role binding", "roleBinding", klog.KObj(&roleBinding))
+			}
+		}
+	}
+	clusterRoleBindings, err := c.kubeclientset.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		klog.ErrorS(err, "Couldn't list the cluster role bindings", "login", klog.KObj(login))
+		return
+	}
+	for _, clusterRoleBinding := range clusterRoleBindings.Items {
+		if err := c.kubeclientset.RbacV1().ClusterRoleBindings().Delete(ctx, clusterRoleBinding.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			klog.ErrorS(err, "Couldn't delete the cluster role binding", "clusterRoleBinding", klog.KObj(&clusterRoleBinding))
+		}
+	}
+}
+
+func (c *Controller) fail(loginCopy *appsv1alpha.Login, reason, message string) {
+	c.recorder.Event(loginCopy, corev1.EventTypeWarning, reason, message)
+	loginCopy.Status.State = failure
+	loginCopy.Status.Message = message
+}
+
+// names tells whether the subjects of a binding name the user
+func names(subjects []rbacv1.Subject, user string) bool {
+	for _, subject := range subjects {
+		if subject.Kind == "User" && subject.Name == user {
+			return true
+		}
+	}
+	return false
+}
+
+func ownerReferences(login *appsv1alpha.Login) []metav1.OwnerReference {
+	return []metav1.OwnerReference{*metav1.NewControllerRef(login, appsv1alpha.SchemeGroupVersion.WithKind("Login"))}
+}
+
+func loginLabels(login *appsv1alpha.Login) map[string]string {
+	return map[string]string{"edge-net.io/generated": "true", index.LabelTenant: login.GetNamespace(), LoginLabel: login.GetName()}
+}
+
+// bindingName names the mirror of the bindings to a role, the user may be bound to it more than once
+func bindingName(login *appsv1alpha.Login, roleRef rbacv1.RoleRef) string {
+	return fmt.Sprintf("edgenet:login-%s:%s:%s", login.GetName(), strings.ToLower(roleRef.Kind), roleRef.Name)
+}
+
+// clusterBindingName also carries the tenant, the logins of two tenants may have the same name
+func clusterBindingName(login *appsv1alpha.Login, roleRef rbacv1.RoleRef) string {
+	return fmt.Sprintf("edgenet:login-%s-%s:%s", login.GetNamespace(), login.GetName(), roleRef.Name)
+}
@@ -0,0 +1,171 @@
+package login
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	appsv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/apps/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/testutil"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+// newController returns a controller for the tenant edgenet, where the user owns the tenant and
+// takes part in the subsidiary namespace
+func newController(t *testing.T) *Controller {
+	kubeclientset, edgenetclientset := testutil.NewTenantClientsets(t, "edgenet", "lab-1a2b3c")
+	c := &Controller{
+		kubeclientset:    kubeclientset,
+		edgenetclientset: edgenetclientset,
+		recorder:         record.NewFakeRecorder(100),
+	}
+	user := []rbacv1.Subject{{Kind: "User", Name: "johndoe@edge-net.org", APIGroup: "rbac.authorization.k8s.io"}}
+	roleBindings := []*rbacv1.RoleBinding{
+		{ObjectMeta: metav1.ObjectMeta{Name: "edgenet:tenant-owner-johndoe", Namespace: "edgenet"}, Subjects: user,
+			RoleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "edgenet:tenant-owner"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "edgenet:tenant-collaborator-johndoe", Namespace: "lab-1a2b3c"}, Subjects: user,
+			RoleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "edgenet:tenant-collaborator"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "edgenet:tenant-owner-johndoe", Namespace: "other"}, Subjects: user,
+			RoleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "edgenet:tenant-owner"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "edgenet:tenant-admin-janedoe", Namespace: "edgenet"},
+			Subjects: []rbacv1.Subject{{Kind: "User", Name: "janedoe@edge-net.org", APIGroup: "rbac.authorization.k8s.io"}},
+			RoleRef:  rbacv1.RoleRef{Kind: "ClusterRole", Name: "edgenet:tenant-admin"}},
+	}
+	for _, roleBinding := range roleBindings {
+		_, err := c.kubeclientset.RbacV1().RoleBindings(roleBinding.GetNamespace()).Create(context.TODO(), roleBinding, metav1.CreateOptions{})
+		util.OK(t, err)
+	}
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "edgenet:tenants:edgenet-owners-johndoe",
+		Labels: map[string]string{"edge-net.io/tenant": "edgenet"}}, Subjects: user,
+		RoleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: "edgenet:tenants:edgenet-owners"}}
+	_, err := c.kubeclientset.RbacV1().ClusterRoleBindings().Create(context.TODO(), clusterRoleBinding, metav1.CreateOptions{})
+	util.OK(t, err)
+	return c
+}
+
+func newLogin(user string, expiry time.Duration) *appsv1alpha.Login {
+	return &appsv1alpha.Login{ObjectMeta: metav1.ObjectMeta{Name: "login-7d9f2", Namespace: "edgenet", UID: types.UID("login-uid")},
+		Spec: appsv1alpha.LoginSpec{User: user, Method: "oidc", Expiry: &metav1.Time{Time: time.Now().Add(expiry)}}}
+}
+
+func (c *Controller) mirror(namespace, name string) *rbacv1.RoleBinding {
+	roleBinding, err := c.kubeclientset.RbacV1().RoleBindings(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	return roleBinding
+}
+
+func TestProcessLogin(t *testing.T) {
+	c := newController(t)
+	login := newLogin("johndoe@edge-net.org", time.Hour)
+	next, ended := c.processLogin(context.TODO(), login)
+	util.Equals(t, false, ended)
+	util.Equals(t, SyncPeriod, next)
+	util.Equals(t, active, login.Status.State)
+	util.Equals(t, "login-7d9f2", login.Status.ServiceAccount)
+	util.Equals(t, []string{"edgenet", "lab-1a2b3c"}, login.Status.Namespaces)
+
+	serviceAccount, err := c.kubeclientset.CoreV1().ServiceAccounts("edgenet").Get(context.TODO(), "login-7d9f2", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, false, *serviceAccount.AutomountServiceAccountToken)
+	util.Assert(t, metav1.IsControlledBy(serviceAccount, login), "service account not owned by the login")
+
+	subject := rbacv1.Subject{Kind: "ServiceAccount", Name: "login-7d9f2", Namespace: "edgenet"}
+	owner := c.mirror("edgenet", "edgenet:login-login-7d9f2:clusterrole:edgenet:tenant-owner")
+	util.Assert(t, owner != nil, "owner role not bound to the session")
+	util.Equals(t, []rbacv1.Subject{subject}, owner.Subjects)
+	util.Assert(t, metav1.IsControlledBy(owner, login), "role binding not owned by the login")
+	collaborator := c.mirror("lab-1a2b3c", "edgenet:login-login-7d9f2:clusterrole:edgenet:tenant-collaborator")
+	util.Assert(t, collaborator != nil, "collaborator role not bound to the session")
+	util.Equals(t, 0, len(collaborator.GetOwnerReferences()))
+	// The roles of the user in another tenant and the roles of the others stay out of the session
+	util.Assert(t, c.mirror("other", "edgenet:login-login-7d9f2:clusterrole:edgenet:tenant-owner") == nil, "role of another tenant bound")
+	util.Assert(t, c.mirror("edgenet", "edgenet:login-login-7d9f2:clusterrole:edgenet:tenant-admin") == nil, "role of another user bound")
+	clusterRoleBinding, err := c.kubeclientset.RbacV1().ClusterRoleBindings().Get(context.TODO(), "edgenet:login-edgenet-login-7d9f2:edgenet:tenants:edgenet-owners", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, []rbacv1.Subject{subject}, clusterRoleBinding.Subjects)
+
+	t.Run("sync again", func(t *testing.T) {
+		_, ended := c.processLogin(context.TODO(), login)
+		util.Equals(t, false, ended)
+		util.Equals(t, active, login.Status.State)
+	})
+	t.Run("role taken back", func(t *testing.T) {
+		err := c.kubeclientset.RbacV1().RoleBindings("lab-1a2b3c").Delete(context.TODO(), "edgenet:tenant-collaborator-johndoe", metav1.DeleteOptions{})
+		util.OK(t, err)
+		c.processLogin(context.TODO(), login)
+		util.Equals(t, []string{"edgenet"}, login.Status.Namespaces)
+		util.Assert(t, c.mirror("lab-1a2b3c", "edgenet:login-login-7d9f2:clusterrole:edgenet:tenant-collaborator") == nil, "role kept by the session")
+	})
+	t.Run("session ended", func(t *testing.T) {
+		c.unbind(context.TODO(), &appsv1alpha.Login{ObjectMeta: login.ObjectMeta, Status: appsv1alpha.LoginStatus{Namespaces: []string{"edgenet", "lab-1a2b3c"}}})
+		clusterRoleBindings, err := c.kubeclientset.RbacV1().ClusterRoleBindings().List(context.TODO(), metav1.ListOptions{LabelSelector: LoginLabel})
+		util.OK(t, err)
+		util.Equals(t, 0, len(clusterRoleBindings.Items))
+		// The tenant role binding is left alone
+		_, err = c.kubeclientset.RbacV1().ClusterRoleBindings().Get(context.TODO(), "edgenet:tenants:edgenet-owners-johndoe", metav1.GetOptions{})
+		util.OK(t, err)
+	})
+}
+
+func TestNoRole(t *testing.T) {
+	c := newController(t)
+	login := newLogin("someone@edge-net.org", time.Hour)
+	_, ended := c.processLogin(context.TODO(), login)
+	util.Equals(t, false, ended)
+	util.Equals(t, failure, login.Status.State)
+	util.Equals(t, "User someone@edge-net.org holds no role in the tenant", login.Status.Message)
+	util.Equals(t, []string{}, login.Status.Namespaces)
+}
+
+func TestEnd(t *testing.T) {
+	t.Run("expired", func(t *testing.T) {
+		c := newController(t)
+		_, ended := c.processLogin(context.TODO(), newLogin("johndoe@edge-net.org", -time.Minute))
+		util.Equals(t, true, ended)
+	})
+	t.Run("tenant disabled", func(t *testing.T) {
+		c := newController(t)
+		tenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), "edgenet", metav1.GetOptions{})
+		util.OK(t, err)
+		tenant.Spec.Enabled = false
+		_, err = c.edgenetclientset.CoreV1alpha().Tenants().Update(context.TODO(), tenant, metav1.UpdateOptions{})
+		util.OK(t, err)
+		_, ended := c.processLogin(context.TODO(), newLogin("johndoe@edge-net.org", time.Hour))
+		util.Equals(t, true, ended)
+	})
+	t.Run("synced until expiry", func(t *testing.T) {
+		c := newController(t)
+		next, ended := c.processLogin(context.TODO(), newLogin("johndoe@edge-net.org", 10*time.Second))
+		util.Equals(t, false, ended)
+		util.Assert(t, next > 0 && next <= 10*time.Second, "synced again in %s", next)
+	})
+	t.Run("service account not owned", func(t *testing.T) {
+		c := newController(t)
+		serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "login-7d9f2", Namespace: "edgenet"}}
+		_, err := c.kubeclientset.CoreV1().ServiceAccounts("edgenet").Create(context.TODO(), serviceAccount, metav1.CreateOptions{})
+		util.OK(t, err)
+		login := newLogin("johndoe@edge-net.org", time.Hour)
+		_, ended := c.processLogin(context.TODO(), login)
+		util.Equals(t, false, ended)
+		util.Equals(t, failure, login.Status.State)
+		util.Equals(t, "", login.Status.ServiceAccount)
+	})
+}
@@ -29,8 +29,11 @@ import (
 	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
 	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
 	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/apps/v1alpha"
+	networkinginformers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/networking/v1alpha"
 	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/apps/v1alpha"
+	networkinglisters "github.com/EdgeNet-project/edgenet/pkg/generated/listers/networking/v1alpha"
 	"github.com/EdgeNet-project/edgenet/pkg/node"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
 	"github.com/EdgeNet-project/edgenet/pkg/util"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -57,7 +60,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 const controllerAgentName = "selectivedeployment-controller"
@@ -119,6 +122,9 @@ type Controller struct {
 	selectivedeploymentsLister listers.SelectiveDeploymentLister
 	selectivedeploymentsSynced cache.InformerSynced
 
+	nodeLinksLister networkinglisters.NodeLinkLister
+	nodeLinksSynced cache.InformerSynced
+
 	// workqueue is a rate limited work queue. This is used to queue work to be
 	// processed instead of performing it as soon as a change happens. This
 	// means we can ensure we only process a fixed amount of resources at a
@@ -140,10 +146,11 @@ func NewController(
 	statefulsetInformer appsinformers.StatefulSetInformer,
 	jobInformer batchinformers.JobInformer,
 	cronjobInformer batchv1beta1informers.CronJobInformer,
-	selectivedeploymentInformer informers.SelectiveDeploymentInformer) *Controller {
+	selectivedeploymentInformer informers.SelectiveDeploymentInformer,
+	nodeLinkInformer networkinginformers.NodeLinkInformer) *Controller {
 
 	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
-	klog.V(4).Info("Creating event broadcaster")
+	klog.V(4).InfoS("Creating event broadcaster")
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartStructuredLogging(0)
 	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
@@ -166,11 +173,13 @@ func NewController(
 		cronjobsSynced:             cronjobInformer.Informer().HasSynced,
 		selectivedeploymentsLister: selectivedeploymentInformer.Lister(),
 		selectivedeploymentsSynced: selectivedeploymentInformer.Informer().HasSynced,
+		nodeLinksLister:            nodeLinkInformer.Lister(),
+		nodeLinksSynced:            nodeLinkInformer.Informer().HasSynced,
 		workqueue:                  workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "SelectiveDeployments"),
 		recorder:                   recorder,
 	}
 
-	klog.V(4).Infoln("Setting up event handlers")
+	klog.V(4).InfoS("Setting up event handlers")
 	// Set up an event handler for when Selective Deployment resources change
 	selectivedeploymentInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: controller.enqueueSelectiveDeployment,
@@ -187,11 +196,22 @@ func NewController(
 			if newNode.ResourceVersion == oldNode.ResourceVersion {
 				return
 			}
+			controller.handleNodeLabels(oldNode, newNode)
 			controller.recoverSelectiveDeployments(new)
 		},
 		DeleteFunc: controller.recoverSelectiveDeployments,
 	})
 
+	nodeLinkInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			controller.handleNodeLink(nil, obj)
+		},
+		UpdateFunc: controller.handleNodeLink,
+		DeleteFunc: func(obj interface{}) {
+			controller.handleNodeLink(obj, nil)
+		},
+	})
+
 	deploymentInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: controller.handleObject,
 		UpdateFunc: func(old, new interface{}) {
@@ -262,12 +282,14 @@ func NewController(
 func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
 
-	klog.V(4).Infoln("Starting Selective Deployment controller")
+	klog.V(4).InfoS("Starting Selective Deployment controller")
 
-	klog.V(4).Infoln("Waiting for informer caches to sync")
+	klog.V(4).InfoS("Waiting for informer caches to sync")
 	if ok := cache.WaitForCacheSync(stopCh,
 		c.selectivedeploymentsSynced,
+		c.nodeLinksSynced,
 		c.nodesSynced,
 		c.deploymentsSynced,
 		c.daemonsetsSynced,
@@ -277,14 +299,14 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
-	klog.V(4).Infoln("Starting workers")
+	klog.V(4).InfoS("Starting workers")
 	for i := 0; i < threadiness; i++ {
-		go wait.Until(c.runWorker, time.Second, stopCh)
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
 	}
 
-	klog.V(4).Infoln("Started workers")
+	klog.V(4).InfoS("Started workers")
 	<-stopCh
-	klog.V(4).Infoln("Shutting down workers")
+	klog.V(4).InfoS("Shutting down workers")
 
 	return nil
 }
@@ -292,14 +314,14 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 // runWorker is a long-running function that will continually call the
 // processNextWorkItem function in order to read and process a message on the
 // workqueue.
-func (c *Controller) runWorker() {
-	for c.processNextWorkItem() {
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
 	}
 }
 
 // processNextWorkItem will read a single work item off the workqueue and
 // attempt to process it, by calling the syncHandler.
-func (c *Controller) processNextWorkItem() bool {
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
 	obj, shutdown := c.workqueue.Get()
 
 	if shutdown {
@@ -316,12 +338,12 @@ func (c *Controller) processNextWorkItem() bool {
 			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
 			return nil
 		}
-		if err := c.syncHandler(key); err != nil {
+		if err := c.syncHandler(ctx, key); err != nil {
 			c.workqueue.AddRateLimited(key)
-			return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
 		}
 		c.workqueue.Forget(obj)
-		klog.V(4).Infof("Successfully synced '%s'", key)
+		klog.V(4).InfoS("Successfully synced", "key", key)
 		return nil
 	}(obj)
 
@@ -336,7 +358,7 @@ func (c *Controller) processNextWorkItem() bool {
 // syncHandler compares the actual state with the desired, and attempts to
 // converge the two. It then updates the Status block of the Selective Deployment
 // resource with the current status of the resource.
-func (c *Controller) syncHandler(key string) error {
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
 		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
@@ -353,7 +375,9 @@ func (c *Controller) syncHandler(key string) error {
 		return err
 	}
 
-	c.applyCriteria(selectivedeployment)
+	if err := c.applyCriteria(ctx, selectivedeployment.DeepCopy()); err != nil {
+		return err
+	}
 	c.recorder.Event(selectivedeployment, corev1.EventTypeNormal, SuccessSynced, MessageResourceSynced)
 	return nil
 }
@@ -390,9 +414,9 @@ func (c *Controller) handleObject(obj interface{}) {
 			utilruntime.HandleError(fmt.Errorf("error decoding object tombstone, invalid type"))
 			return
 		}
-		klog.V(4).Infof("Recovered deleted object '%s' from tombstone", object.GetName())
+		klog.V(4).InfoS("Recovered deleted object from tombstone", "object", klog.KObj(object))
 	}
-	klog.V(4).Infof("Processing object: %s", object.GetName())
+	klog.V(4).InfoS("Processing object", "object", klog.KObj(object))
 	if ownerRef := metav1.GetControllerOf(object); ownerRef != nil {
 		if ownerRef.Kind != "SelectiveDeployment" {
 			return
@@ -400,7 +424,7 @@ func (c *Controller) handleObject(obj interface{}) {
 
 		selectivedeployment, err := c.selectivedeploymentsLister.SelectiveDeployments(object.GetNamespace()).Get(ownerRef.Name)
 		if err != nil {
-			klog.V(4).Infof("ignoring orphaned object '%s' of selectivedeployment '%s'", object.GetSelfLink(), ownerRef.Name)
+			klog.V(4).InfoS("Ignoring orphaned object", "object", klog.KObj(object), "selectiveDeployment", ownerRef.Name)
 			return
 		}
 
@@ -417,7 +441,7 @@ func (c *Controller) recoverSelectiveDeployments(obj interface{}) {
 			for _, ownerRow := range ownerRaw {
 				selectivedeployment, err := c.selectivedeploymentsLister.SelectiveDeployments(ownerRow[0]).Get(ownerRow[1])
 				if err != nil {
-					klog.V(4).Infoln(err.Error())
+					klog.ErrorS(err, "Couldn't get the selective deployment", "selectiveDeployment", klog.KRef(ownerRow[0], ownerRow[1]))
 					continue
 				}
 				if selectivedeployment.Spec.Recovery {
@@ -483,7 +507,7 @@ func (c *Controller) getByNode(nodeName string) ([][]string, bool) {
 	}
 	deploymentRaw, err := c.deploymentsLister.Deployments("").List(labels.Everything())
 	if err != nil {
-		klog.V(4).Infoln(err.Error())
+		klog.ErrorS(err, "Couldn't list the deployments")
 		panic(err.Error())
 	}
 	for _, deploymentRow := range deploymentRaw {
@@ -491,7 +515,7 @@ func (c *Controller) getByNode(nodeName string) ([][]string, bool) {
 	}
 	daemonsetRaw, err := c.daemonsetsLister.DaemonSets("").List(labels.Everything())
 	if err != nil {
-		klog.V(4).Infoln(err.Error())
+		klog.ErrorS(err, "Couldn't list the daemon sets")
 		panic(err.Error())
 	}
 	for _, daemonsetRow := range daemonsetRaw {
@@ -499,7 +523,7 @@ func (c *Controller) getByNode(nodeName string) ([][]string, bool) {
 	}
 	statefulsetRaw, err := c.statefulsetsLister.StatefulSets("").List(labels.Everything())
 	if err != nil {
-		klog.V(4).Infoln(err.Error())
+		klog.ErrorS(err, "Couldn't list the stateful sets")
 		panic(err.Error())
 	}
 	for _, statefulsetRow := range statefulsetRaw {
@@ -507,7 +531,7 @@ func (c *Controller) getByNode(nodeName string) ([][]string, bool) {
 	}
 	jobRaw, err := c.jobsLister.Jobs("").List(labels.Everything())
 	if err != nil {
-		klog.V(4).Infoln(err.Error())
+		klog.ErrorS(err, "Couldn't list the jobs")
 		panic(err.Error())
 	}
 	for _, jobRow := range jobRaw {
@@ -515,7 +539,7 @@ func (c *Controller) getByNode(nodeName string) ([][]string, bool) {
 	}
 	cronjobRaw, err := c.cronjobsLister.CronJobs("").List(labels.Everything())
 	if err != nil {
-		klog.V(4).Infoln(err.Error())
+		klog.ErrorS(err, "Couldn't list the cron jobs")
 		panic(err.Error())
 	}
 	for _, cronjobRow := range cronjobRaw {
@@ -524,21 +548,23 @@ func (c *Controller) getByNode(nodeName string) ([][]string, bool) {
 	return ownerList, status
 }
 
-// applyCriteria picks the nodes according to the selector
-func (c *Controller) applyCriteria(selectivedeploymentCopy *appsv1alpha.SelectiveDeployment) {
-	oldStatus := selectivedeploymentCopy.Status
+// applyCriteria picks the nodes according to the selector. It returns an error if a workload
+// could not be created as it already exists, the cache lags behind and the sync is retried.
+func (c *Controller) applyCriteria(ctx context.Context, selectivedeploymentCopy *appsv1alpha.SelectiveDeployment) error {
+	oldStatus := *selectivedeploymentCopy.Status.DeepCopy()
 	statusUpdate := func() {
 		if !reflect.DeepEqual(oldStatus, selectivedeploymentCopy.Status) {
-			c.edgenetclientset.AppsV1alpha().SelectiveDeployments(selectivedeploymentCopy.GetNamespace()).UpdateStatus(context.TODO(), selectivedeploymentCopy, metav1.UpdateOptions{})
+			c.edgenetclientset.AppsV1alpha().SelectiveDeployments(selectivedeploymentCopy.GetNamespace()).UpdateStatus(ctx, selectivedeploymentCopy, metav1.UpdateOptions{})
 		}
 	}
 	defer statusUpdate()
-	// Flush the status
-	selectivedeploymentCopy.Status = appsv1alpha.SelectiveDeploymentStatus{}
+	// Flush the status, the conditions are kept to track their transitions
+	selectivedeploymentCopy.Status = appsv1alpha.SelectiveDeploymentStatus{Conditions: oldStatus.DeepCopy().Conditions}
 
 	ownerReferences := SetAsOwnerReference(selectivedeploymentCopy)
 	workloadCounter := 0
 	failureCounter := 0
+	staleCache := false
 	if selectivedeploymentCopy.Spec.Workloads.Deployment != nil {
 		workloadCounter += len(selectivedeploymentCopy.Spec.Workloads.Deployment)
 		for _, deployment := range selectivedeploymentCopy.Spec.Workloads.Deployment {
@@ -546,8 +572,9 @@ func (c *Controller) applyCriteria(selectivedeploymentCopy *appsv1alpha.Selectiv
 			if errors.IsNotFound(err) {
 				configuredDeployment, failureCount := c.configureWorkload(selectivedeploymentCopy, deployment, ownerReferences)
 				failureCounter += failureCount
-				_, err = c.kubeclientset.AppsV1().Deployments(selectivedeploymentCopy.GetNamespace()).Create(context.TODO(), configuredDeployment.(*appsv1.Deployment), metav1.CreateOptions{})
+				_, err = c.kubeclientset.AppsV1().Deployments(selectivedeploymentCopy.GetNamespace()).Create(ctx, configuredDeployment.(*appsv1.Deployment), metav1.CreateOptions{})
 				if err != nil {
+					staleCache = staleCache || errors.IsAlreadyExists(err)
 					selectivedeploymentCopy.Status.Message = append(selectivedeploymentCopy.Status.Message, fmt.Sprintf(statusDict["deployment-creation-failure"], deployment.GetName(), err))
 					failureCounter++
 				}
@@ -557,7 +584,7 @@ func (c *Controller) applyCriteria(selectivedeploymentCopy *appsv1alpha.Selectiv
 					// Configure the deployment according to the SD
 					configuredDeployment, failureCount := c.configureWorkload(selectivedeploymentCopy, deployment, ownerReferences)
 					failureCounter += failureCount
-					_, err = c.kubeclientset.AppsV1().Deployments(selectivedeploymentCopy.GetNamespace()).Update(context.TODO(), configuredDeployment.(*appsv1.Deployment), metav1.UpdateOptions{})
+					_, err = c.kubeclientset.AppsV1().Deployments(selectivedeploymentCopy.GetNamespace()).Update(ctx, configuredDeployment.(*appsv1.Deployment), metav1.UpdateOptions{})
 					if err != nil {
 						selectivedeploymentCopy.Status.Message = append(selectivedeploymentCopy.Status.Message, fmt.Sprintf(statusDict["daemonset-creation-failure"], deployment.GetName(), err))
 						failureCounter++
@@ -576,8 +603,9 @@ func (c *Controller) applyCriteria(selectivedeploymentCopy *appsv1alpha.Selectiv
 			if errors.IsNotFound(err) {
 				configuredDaemonSet, failureCount := c.configureWorkload(selectivedeploymentCopy, sdDaemonset, ownerReferences)
 				failureCounter += failureCount
-				_, err = c.kubeclientset.AppsV1().DaemonSets(selectivedeploymentCopy.GetNamespace()).Create(context.TODO(), configuredDaemonSet.(*appsv1.DaemonSet), metav1.CreateOptions{})
+				_, err = c.kubeclientset.AppsV1().DaemonSets(selectivedeploymentCopy.GetNamespace()).Create(ctx, configuredDaemonSet.(*appsv1.DaemonSet), metav1.CreateOptions{})
 				if err != nil {
+					staleCache = staleCache || errors.IsAlreadyExists(err)
 					selectivedeploymentCopy.Status.Message = append(selectivedeploymentCopy.Status.Message, fmt.Sprintf(statusDict["daemonset-creation-failure"], sdDaemonset.GetName(), err))
 					failureCounter++
 				}
@@ -587,7 +615,7 @@ func (c *Controller) applyCriteria(selectivedeploymentCopy *appsv1alpha.Selectiv
 					// Configure the daemonset according to the SD
 					configuredDaemonSet, failureCount := c.configureWorkload(selectivedeploymentCopy, sdDaemonset, ownerReferences)
 					failureCounter += failureCount
-					_, err = c.kubeclientset.AppsV1().DaemonSets(selectivedeploymentCopy.GetNamespace()).Update(context.TODO(), configuredDaemonSet.(*appsv1.DaemonSet), metav1.UpdateOptions{})
+					_, err = c.kubeclientset.AppsV1().DaemonSets(selectivedeploymentCopy.GetNamespace()).Update(ctx, configuredDaemonSet.(*appsv1.DaemonSet), metav1.UpdateOptions{})
 					if err != nil {
 						selectivedeploymentCopy.Status.Message = append(selectivedeploymentCopy.Status.Message, fmt.Sprintf(statusDict["daemonset-creation-failure"], sdDaemonset.GetName(), err))
 						failureCounter++
@@ -606,8 +634,9 @@ func (c *Controller) applyCriteria(selectivedeploymentCopy *appsv1alpha.Selectiv
 			if errors.IsNotFound(err) {
 				configuredStatefulSet, failureCount := c.configureWorkload(selectivedeploymentCopy, sdStatefulset, ownerReferences)
 				failureCounter += failureCount
-				_, err = c.kubeclientset.AppsV1().StatefulSets(selectivedeploymentCopy.GetNamespace()).Create(context.TODO(), configuredStatefulSet.(*appsv1.StatefulSet), metav1.CreateOptions{})
+				_, err = c.kubeclientset.AppsV1().StatefulSets(selectivedeploymentCopy.GetNamespace()).Create(ctx, configuredStatefulSet.(*appsv1.StatefulSet), metav1.CreateOptions{})
 				if err != nil {
+					staleCache = staleCache || errors.IsAlreadyExists(err)
 					selectivedeploymentCopy.Status.Message = append(selectivedeploymentCopy.Status.Message, fmt.Sprintf(statusDict["statefulset-creation-failure"], sdStatefulset.GetName(), err))
 					failureCounter++
 				}
@@ -617,7 +646,7 @@ func (c *Controller) applyCriteria(selectivedeploymentCopy *appsv1alpha.Selectiv
 					// Configure the statefulset according to the SD
 					configuredStatefulSet, failureCount := c.configureWorkload(selectivedeploymentCopy, sdStatefulset, ownerReferences)
 					failureCounter += failureCount
-					_, err = c.kubeclientset.AppsV1().StatefulSets(selectivedeploymentCopy.GetNamespace()).Update(context.TODO(), configuredStatefulSet.(*appsv1.StatefulSet), metav1.UpdateOptions{})
+					_, err = c.kubeclientset.AppsV1().StatefulSets(selectivedeploymentCopy.GetNamespace()).Update(ctx, configuredStatefulSet.(*appsv1.StatefulSet), metav1.UpdateOptions{})
 					if err != nil {
 						selectivedeploymentCopy.Status.Message = append(selectivedeploymentCopy.Status.Message, fmt.Sprintf(statusDict["statefulset-creation-failure"], sdStatefulset.GetName(), err))
 						failureCounter++
@@ -636,8 +665,9 @@ func (c *Controller) applyCriteria(selectivedeploymentCopy *appsv1alpha.Selectiv
 			if errors.IsNotFound(err) {
 				configuredJob, failureCount := c.configureWorkload(selectivedeploymentCopy, sdJob, ownerReferences)
 				failureCounter += failureCount
-				_, err = c.kubeclientset.BatchV1().Jobs(selectivedeploymentCopy.GetNamespace()).Create(context.TODO(), configuredJob.(*batchv1.Job), metav1.CreateOptions{})
+				_, err = c.kubeclientset.BatchV1().Jobs(selectivedeploymentCopy.GetNamespace()).Create(ctx, configuredJob.(*batchv1.Job), metav1.CreateOptions{})
 				if err != nil {
+					staleCache = staleCache || errors.IsAlreadyExists(err)
 					selectivedeploymentCopy.Status.Message = append(selectivedeploymentCopy.Status.Message, fmt.Sprintf(statusDict["job-creation-failure"], sdJob.GetName(), err))
 					failureCounter++
 				}
@@ -647,7 +677,7 @@ func (c *Controller) applyCriteria(selectivedeploymentCopy *appsv1alpha.Selectiv
 					// Configure the job according to the SD
 					configuredJob, failureCount := c.configureWorkload(selectivedeploymentCopy, sdJob, ownerReferences)
 					failureCounter += failureCount
-					_, err = c.kubeclientset.BatchV1().Jobs(selectivedeploymentCopy.GetNamespace()).Update(context.TODO(), configuredJob.(*batchv1.Job), metav1.UpdateOptions{})
+					_, err = c.kubeclientset.BatchV1().Jobs(selectivedeploymentCopy.GetNamespace()).Update(ctx, configuredJob.(*batchv1.Job), metav1.UpdateOptions{})
 					if err != nil {
 						selectivedeploymentCopy.Status.Message = append(selectivedeploymentCopy.Status.Message, fmt.Sprintf(statusDict["job-creation-failure"], sdJob.GetName(), err))
 						failureCounter++
@@ -666,8 +696,9 @@ func (c *Controller) applyCriteria(selectivedeploymentCopy *appsv1alpha.Selectiv
 			if errors.IsNotFound(err) {
 				configuredCronJob, failureCount := c.configureWorkload(selectivedeploymentCopy, sdCronJob, ownerReferences)
 				failureCounter += failureCount
-				_, err = c.kubeclientset.BatchV1beta1().CronJobs(selectivedeploymentCopy.GetNamespace()).Create(context.TODO(), configuredCronJob.(*batchv1beta1.CronJob), metav1.CreateOptions{})
+				_, err = c.kubeclientset.BatchV1beta1().CronJobs(selectivedeploymentCopy.GetNamespace()).Create(ctx, configuredCronJob.(*batchv1beta1.CronJob), metav1.CreateOptions{})
 				if err != nil {
+					staleCache = staleCache || errors.IsAlreadyExists(err)
 					selectivedeploymentCopy.Status.Message = append(selectivedeploymentCopy.Status.Message, fmt.Sprintf(statusDict["cronjob-creation-failure"], sdCronJob.GetName(), err))
 					failureCounter++
 				}
@@ -677,7 +708,7 @@ func (c *Controller) applyCriteria(selectivedeploymentCopy *appsv1alpha.Selectiv
 					// Configure the cronjob according to the SD
 					configuredCronJob, failureCount := c.configureWorkload(selectivedeploymentCopy, sdCronJob, ownerReferences)
 					failureCounter += failureCount
-					_, err = c.kubeclientset.BatchV1beta1().CronJobs(selectivedeploymentCopy.GetNamespace()).Update(context.TODO(), configuredCronJob.(*batchv1beta1.CronJob), metav1.UpdateOptions{})
+					_, err = c.kubeclientset.BatchV1beta1().CronJobs(selectivedeploymentCopy.GetNamespace()).Update(ctx, configuredCronJob.(*batchv1beta1.CronJob), metav1.UpdateOptions{})
 					if err != nil {
 						selectivedeploymentCopy.Status.Message = append(selectivedeploymentCopy.Status.Message, fmt.Sprintf(statusDict["cronjob-creation-failure"], sdCronJob.GetName(), err))
 						failureCounter++
@@ -699,11 +730,20 @@ func (c *Controller) applyCriteria(selectivedeploymentCopy *appsv1alpha.Selectiv
 		selectivedeploymentCopy.Status.State = partial
 	}
 	selectivedeploymentCopy.Status.Ready = fmt.Sprintf("%d/%d", (workloadCounter - failureCounter), workloadCounter)
+	selectivedeploymentCopy.Status.Workloads = c.workloadStatuses(selectivedeploymentCopy)
+	if selectivedeploymentCopy.Status.Selectors != nil {
+		c.setDegraded(selectivedeploymentCopy)
+		c.recordPlacement(selectivedeploymentCopy, oldStatus.Selectors)
+	}
+	if staleCache {
+		return fmt.Errorf("workloads of selectivedeployment %s/%s are not in the cache yet", selectivedeploymentCopy.GetNamespace(), selectivedeploymentCopy.GetName())
+	}
+	return nil
 }
 
 // configureWorkload manipulate the workload by selectivedeployments to match the desired state that users supplied
 func (c *Controller) configureWorkload(selectivedeploymentCopy *appsv1alpha.SelectiveDeployment, workloadRow interface{}, ownerReferences []metav1.OwnerReference) (interface{}, int) {
-	klog.V(4).Infoln("configureWorkload: start")
+	klog.V(4).InfoS("configureWorkload: start")
 	nodeSelectorTermList, failureCount := c.setFilter(selectivedeploymentCopy, "addOrUpdate")
 	// Set the new node affinity configuration for the workload and update that
 	nodeAffinity := &corev1.NodeAffinity{
@@ -724,6 +764,9 @@ func (c *Controller) configureWorkload(selectivedeploymentCopy *appsv1alpha.Sele
 	var workloadCopy interface{}
 	switch workloadObj := workloadRow.(type) {
 	case appsv1.Deployment:
+		if selectivedeploymentCopy.Spec.Rollout != nil {
+			workloadObj.Spec.Strategy = deploymentStrategy(selectivedeploymentCopy.Spec.Rollout)
+		}
 		if len(nodeSelectorTermList) <= 0 && workloadObj.Spec.Template.Spec.Affinity != nil {
 			workloadObj.Spec.Template.Spec.Affinity.Reset()
 		} else if workloadObj.Spec.Template.Spec.Affinity != nil {
@@ -738,6 +781,9 @@ func (c *Controller) configureWorkload(selectivedeploymentCopy *appsv1alpha.Sele
 		workloadCopy = workloadObj.DeepCopy()
 		//c.kubeclientset.AppsV1().Deployments(selectivedeploymentCopy.GetNamespace()).Update(workloadCopy)
 	case appsv1.DaemonSet:
+		if selectivedeploymentCopy.Spec.Rollout != nil {
+			workloadObj.Spec.UpdateStrategy = daemonsetStrategy(selectivedeploymentCopy.Spec.Rollout)
+		}
 		if len(nodeSelectorTermList) <= 0 && workloadObj.Spec.Template.Spec.Affinity != nil {
 			workloadObj.Spec.Template.Spec.Affinity.Reset()
 		} else if workloadObj.Spec.Template.Spec.Affinity != nil {
@@ -800,6 +846,7 @@ func (c *Controller) configureWorkload(selectivedeploymentCopy *appsv1alpha.Sele
 // setFilter generates the values in the predefined form and puts those into the node selection fields of the selectivedeployment object
 func (c *Controller) setFilter(selectivedeploymentCopy *appsv1alpha.SelectiveDeployment, event string) ([]corev1.NodeSelectorTerm, int) {
 	var nodeSelectorTermList []corev1.NodeSelectorTerm
+	selectorStatuses := []appsv1alpha.SelectorStatus{}
 	failureCounter := 0
 	for _, selectorRow := range selectivedeploymentCopy.Spec.Selector {
 		var matchExpression corev1.NodeSelectorRequirement
@@ -823,7 +870,7 @@ func (c *Controller) setFilter(selectivedeploymentCopy *appsv1alpha.SelectiveDep
 				selector = selector.Add(*scheduleReq)
 				nodesRaw, err := c.nodesLister.List(selector)
 				if err != nil {
-					klog.V(4).Infoln(err.Error())
+					klog.ErrorS(err, "Couldn't list the nodes")
 					panic(err.Error())
 				}
 				counter := 0
@@ -861,6 +908,9 @@ func (c *Controller) setFilter(selectivedeploymentCopy *appsv1alpha.SelectiveDep
 						}
 					}
 				}
+				if selectorRow.MaxLatency != nil && selectorRow.Operator == "In" {
+					matchExpression.Values, counter = c.withinLatency(selectorRow, matchExpression.Values, counter)
+				}
 				if selectorRow.Quantity != 0 && selectorRow.Quantity > counter {
 					strLen := 16
 					strSuffix := "..."
@@ -883,7 +933,7 @@ func (c *Controller) setFilter(selectivedeploymentCopy *appsv1alpha.SelectiveDep
 				selector = selector.Add(*scheduleReq)
 				nodesRaw, err := c.nodesLister.List(selector)
 				if err != nil {
-					klog.V(4).Infoln(err.Error())
+					klog.ErrorS(err, "Couldn't list the nodes")
 					panic(err.Error())
 				}
 
@@ -954,6 +1004,9 @@ func (c *Controller) setFilter(selectivedeploymentCopy *appsv1alpha.SelectiveDep
 						}
 					}
 				}
+				if selectorRow.MaxLatency != nil && selectorRow.Operator == "In" {
+					matchExpression.Values, counter = c.withinLatency(selectorRow, matchExpression.Values, counter)
+				}
 				if selectorRow.Quantity != 0 && selectorRow.Quantity > counter {
 					strLen := 16
 					strSuffix := "..."
@@ -969,10 +1022,15 @@ func (c *Controller) setFilter(selectivedeploymentCopy *appsv1alpha.SelectiveDep
 			matchExpression.Key = ""
 		}
 
+		selectorStatuses = append(selectorStatuses, appsv1alpha.SelectorStatus{Name: selectorRow.Name, Value: selectorRow.Value,
+			Matched: len(matchExpression.Values), Nodes: matchExpression.Values})
 		var nodeSelectorTerm corev1.NodeSelectorTerm
 		nodeSelectorTerm.MatchExpressions = append(nodeSelectorTerm.MatchExpressions, matchExpression)
 		nodeSelectorTermList = append(nodeSelectorTermList, nodeSelectorTerm)
 	}
+	if event != "delete" {
+		selectivedeploymentCopy.Status.Selectors = selectorStatuses
+	}
 	return nodeSelectorTermList, failureCounter
 }
 
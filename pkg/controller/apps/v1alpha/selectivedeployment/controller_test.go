@@ -5,10 +5,12 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"sort"
 	"testing"
 	"time"
 
 	apps_v1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/apps/v1alpha"
+	networking_v1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/networking/v1alpha"
 	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
 	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
 	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
@@ -20,12 +22,15 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	testclient "k8s.io/client-go/kubernetes/fake"
-	"k8s.io/klog"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
 )
 
 type TestGroup struct {
@@ -61,7 +66,8 @@ func TestMain(m *testing.M) {
 		kubeInformerFactory.Apps().V1().StatefulSets(),
 		kubeInformerFactory.Batch().V1().Jobs(),
 		kubeInformerFactory.Batch().V1beta1().CronJobs(),
-		edgenetInformerFactory.Apps().V1alpha().SelectiveDeployments())
+		edgenetInformerFactory.Apps().V1alpha().SelectiveDeployments(),
+		edgenetInformerFactory.Networking().V1alpha().NodeLinks())
 
 	kubeInformerFactory.Start(stopCh)
 	edgenetInformerFactory.Start(stopCh)
@@ -1001,3 +1007,178 @@ func TestGetByNode(t *testing.T) {
 	util.Equals(t, "getbynode", ownerList[0][0])
 	util.Equals(t, sdObj.GetName(), ownerList[0][1])
 }
+
+func TestMaxLatency(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	newNode := func(name, city string) *corev1.Node {
+		nodeObj := g.nodeObj.DeepCopy()
+		nodeObj.SetName(name)
+		nodeObj.ObjectMeta.Labels = map[string]string{
+			"kubernetes.io/hostname": name,
+			"edge-net.io/city":       city,
+		}
+		return nodeObj
+	}
+	newLink := func(source, target string, latency time.Duration, loss int) *networking_v1alpha.NodeLink {
+		return &networking_v1alpha.NodeLink{
+			ObjectMeta: metav1.ObjectMeta{Name: source + "--" + target},
+			Spec:       networking_v1alpha.NodeLinkSpec{Source: source, Target: target},
+			Status:     networking_v1alpha.NodeLinkStatus{Latency: &metav1.Duration{Duration: latency}, PacketLoss: loss},
+		}
+	}
+	for _, nodeObj := range []*corev1.Node{newNode("paris-1", "Paris"), newNode("lyon-1", "Lyon"), newNode("london-1", "London"),
+		newNode("brussels-1", "Brussels"), newNode("tokyo-1", "Tokyo")} {
+		_, err := kubeclientset.CoreV1().Nodes().Create(context.TODO(), nodeObj, metav1.CreateOptions{})
+		util.OK(t, err)
+	}
+	for _, link := range []*networking_v1alpha.NodeLink{newLink("paris-1", "lyon-1", 9*time.Millisecond, 0),
+		newLink("london-1", "paris-1", 12*time.Millisecond, 0), newLink("paris-1", "brussels-1", 5*time.Millisecond, 100),
+		newLink("paris-1", "tokyo-1", 240*time.Millisecond, 0)} {
+		_, err := edgenetclientset.NetworkingV1alpha().NodeLinks().Create(context.TODO(), link, metav1.CreateOptions{})
+		util.OK(t, err)
+		defer edgenetclientset.NetworkingV1alpha().NodeLinks().Delete(context.TODO(), link.GetName(), metav1.DeleteOptions{})
+	}
+	time.Sleep(time.Millisecond * 500)
+
+	cases := map[string]struct {
+		maxLatency time.Duration
+		quantity   int
+		expected   []string
+		failures   int
+	}{
+		"within 20ms":            {20 * time.Millisecond, 0, []string{"paris-1", "lyon-1", "london-1"}, 0},
+		"within 10ms":            {10 * time.Millisecond, 0, []string{"paris-1", "lyon-1"}, 0},
+		"closest first":          {20 * time.Millisecond, 2, []string{"paris-1", "lyon-1"}, 0},
+		"fewer nodes than asked": {10 * time.Millisecond, 3, []string{"paris-1", "lyon-1"}, 1},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			sdObj := g.sdObj.DeepCopy()
+			selector := g.selector
+			selector.Value = []string{"Paris"}
+			selector.Quantity = tc.quantity
+			selector.MaxLatency = &metav1.Duration{Duration: tc.maxLatency}
+			sdObj.Spec.Selector = []apps_v1alpha.Selector{selector}
+			nodeSelectorTerms, failures := controller.setFilter(sdObj, create)
+			util.Equals(t, tc.expected, nodeSelectorTerms[0].MatchExpressions[0].Values)
+			util.Equals(t, tc.failures, failures)
+		})
+	}
+}
+
+func TestStatus(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	nodeParis := g.nodeObj.DeepCopy()
+	nodeParis.SetName("paris-1")
+	nodeParis.ObjectMeta.Labels = map[string]string{"kubernetes.io/hostname": "paris-1", "edge-net.io/city": "Paris"}
+	_, err := kubeclientset.CoreV1().Nodes().Create(context.TODO(), nodeParis, metav1.CreateOptions{})
+	util.OK(t, err)
+	replicas := int32(3)
+	deploymentObj := g.deploymentObj.DeepCopy()
+	deploymentObj.SetName("status")
+	deploymentObj.Spec.Replicas = &replicas
+	deploymentObj.Status.ReadyReplicas = 2
+	_, err = kubeclientset.AppsV1().Deployments("default").Create(context.TODO(), deploymentObj, metav1.CreateOptions{})
+	util.OK(t, err)
+	defer kubeclientset.AppsV1().Deployments("default").Delete(context.TODO(), deploymentObj.GetName(), metav1.DeleteOptions{})
+	time.Sleep(time.Millisecond * 500)
+
+	recorder := record.NewFakeRecorder(10)
+	c := &Controller{
+		nodesLister:        controller.nodesLister,
+		nodeLinksLister:    controller.nodeLinksLister,
+		deploymentsLister:  controller.deploymentsLister,
+		daemonsetsLister:   controller.daemonsetsLister,
+		statefulsetsLister: controller.statefulsetsLister,
+		jobsLister:         controller.jobsLister,
+		cronjobsLister:     controller.cronjobsLister,
+		recorder:           recorder,
+	}
+	sdObj := g.sdObj.DeepCopy()
+	sdObj.SetNamespace("default")
+	sdObj.Spec.Workloads = apps_v1alpha.Workloads{Deployment: []appsv1.Deployment{*deploymentObj}}
+	lyon := g.selector
+	lyon.Value = []string{"Lyon"}
+	sdObj.Spec.Selector = []apps_v1alpha.Selector{g.selector, lyon}
+
+	c.setFilter(sdObj, create)
+	util.Equals(t, []apps_v1alpha.SelectorStatus{{Name: "city", Value: []string{"Paris"}, Matched: 1, Nodes: []string{"paris-1"}},
+		{Name: "city", Value: []string{"Lyon"}, Matched: 0, Nodes: []string{}}}, sdObj.Status.Selectors)
+	util.Equals(t, []apps_v1alpha.WorkloadStatus{{Kind: "Deployment", Name: "status", Desired: 3, Ready: 2}}, c.workloadStatuses(sdObj))
+	c.setDegraded(sdObj)
+	degraded := meta.FindStatusCondition(sdObj.Status.Conditions, conditionDegraded)
+	util.Equals(t, metav1.ConditionTrue, degraded.Status)
+	util.Equals(t, "Selector(s) city [Lyon] match no node", degraded.Message)
+	util.Equals(t, "Warning NoNodeMatched Selector(s) city [Lyon] match no node", <-recorder.Events)
+
+	t.Run("node churn", func(t *testing.T) {
+		nodeLyon := g.nodeObj.DeepCopy()
+		nodeLyon.SetName("lyon-1")
+		nodeLyon.ObjectMeta.Labels = map[string]string{"kubernetes.io/hostname": "lyon-1", "edge-net.io/city": "Lyon"}
+		_, err := kubeclientset.CoreV1().Nodes().Create(context.TODO(), nodeLyon, metav1.CreateOptions{})
+		util.OK(t, err)
+		time.Sleep(time.Millisecond * 500)
+
+		oldSelectors := sdObj.Status.Selectors
+		c.setFilter(sdObj, create)
+		c.recordPlacement(sdObj, oldSelectors)
+		util.Equals(t, "Normal PlacementChanged Selector city [Lyon] matches 1 node(s), 1 joined and 0 left", <-recorder.Events)
+		c.setDegraded(sdObj)
+		util.Equals(t, metav1.ConditionFalse, meta.FindStatusCondition(sdObj.Status.Conditions, conditionDegraded).Status)
+		util.Equals(t, "Normal PlacementRestored Every selector matches at least one node", <-recorder.Events)
+		util.Equals(t, 0, len(recorder.Events))
+	})
+}
+
+func TestRetarget(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	newNode := func(name, city string) *corev1.Node {
+		nodeObj := g.nodeObj.DeepCopy()
+		nodeObj.SetName(name)
+		nodeObj.ObjectMeta.Labels = map[string]string{"kubernetes.io/hostname": name, "edge-net.io/city": city}
+		return nodeObj
+	}
+	_, err := kubeclientset.CoreV1().Nodes().Create(context.TODO(), newNode("paris-1", "Paris"), metav1.CreateOptions{})
+	util.OK(t, err)
+	_, err = kubeclientset.CoreV1().Nodes().Create(context.TODO(), newNode("lyon-1", "Lyon"), metav1.CreateOptions{})
+	util.OK(t, err)
+
+	maxSurge, maxUnavailable := intstr.FromInt(1), intstr.FromString("0%")
+	deploymentObj := g.deploymentObj.DeepCopy()
+	deploymentObj.SetName("retarget")
+	sdObj := g.sdObj.DeepCopy()
+	sdObj.SetName("retarget")
+	sdObj.Spec.Workloads = apps_v1alpha.Workloads{Deployment: []appsv1.Deployment{*deploymentObj}}
+	sdObj.Spec.Rollout = &apps_v1alpha.Rollout{MaxSurge: &maxSurge, MaxUnavailable: &maxUnavailable}
+	_, err = edgenetclientset.AppsV1alpha().SelectiveDeployments("default").Create(context.TODO(), sdObj, metav1.CreateOptions{})
+	util.OK(t, err)
+	defer kubeclientset.AppsV1().Deployments("default").Delete(context.TODO(), deploymentObj.GetName(), metav1.DeleteOptions{})
+	time.Sleep(time.Millisecond * 500)
+
+	hostnames := func() []string {
+		deploymentCopy, err := kubeclientset.AppsV1().Deployments("default").Get(context.TODO(), deploymentObj.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		values := deploymentCopy.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions[0].Values
+		sort.Strings(values)
+		return values
+	}
+	util.Equals(t, []string{"paris-1"}, hostnames())
+	deploymentCopy, err := kubeclientset.AppsV1().Deployments("default").Get(context.TODO(), deploymentObj.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, appsv1.RollingUpdateDeploymentStrategyType, deploymentCopy.Spec.Strategy.Type)
+	util.Equals(t, maxSurge, *deploymentCopy.Spec.Strategy.RollingUpdate.MaxSurge)
+	util.Equals(t, maxUnavailable, *deploymentCopy.Spec.Strategy.RollingUpdate.MaxUnavailable)
+
+	relabeled := newNode("lyon-1", "Paris")
+	relabeled.ResourceVersion = "1"
+	_, err = kubeclientset.CoreV1().Nodes().Update(context.TODO(), relabeled, metav1.UpdateOptions{})
+	util.OK(t, err)
+	time.Sleep(time.Millisecond * 500)
+	util.Equals(t, []string{"lyon-1", "paris-1"}, hostnames())
+}
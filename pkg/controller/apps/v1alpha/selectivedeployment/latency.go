@@ -0,0 +1,135 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selectivedeployment
+
+import (
+	"sort"
+	"time"
+
+	appsv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/apps/v1alpha"
+	networkingv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/networking/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/node"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// withinLatency adds the nodes within the maximum latency of the selector from the nodes selected
+// to the hostnames, the closest first and up to the quantity of the selector. It returns the
+// hostnames and the count of the nodes selected.
+func (c *Controller) withinLatency(selectorRow appsv1alpha.Selector, hostnames []string, counter int) ([]string, int) {
+	nodesRaw, err := c.nodesLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Couldn't list the nodes")
+		return hostnames, counter
+	}
+	linksRaw, err := c.nodeLinksLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Couldn't list the node links")
+		return hostnames, counter
+	}
+	nodes := map[string]*corev1.Node{}
+	selected := map[string]bool{}
+	for _, nodeRow := range nodesRaw {
+		nodes[nodeRow.GetName()] = nodeRow
+		if exists, _ := util.Contains(hostnames, nodeRow.Labels["kubernetes.io/hostname"]); exists {
+			selected[nodeRow.GetName()] = true
+		}
+	}
+
+	// The links are measured from both ends, the lowest latency to a node counts
+	latencies := map[string]time.Duration{}
+	for _, linkRow := range linksRaw {
+		if !linkWithin(linkRow, selectorRow.MaxLatency.Duration) {
+			continue
+		}
+		candidate := ""
+		if selected[linkRow.Spec.Source] && !selected[linkRow.Spec.Target] {
+			candidate = linkRow.Spec.Target
+		} else if selected[linkRow.Spec.Target] && !selected[linkRow.Spec.Source] {
+			candidate = linkRow.Spec.Source
+		}
+		if latency, ok := latencies[candidate]; candidate != "" && (!ok || linkRow.Status.Latency.Duration < latency) {
+			latencies[candidate] = linkRow.Status.Latency.Duration
+		}
+	}
+	candidates := []string{}
+	for candidate := range latencies {
+		candidates = append(candidates, candidate)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if latencies[candidates[i]] == latencies[candidates[j]] {
+			return candidates[i] < candidates[j]
+		}
+		return latencies[candidates[i]] < latencies[candidates[j]]
+	})
+
+	for _, candidate := range candidates {
+		if selectorRow.Quantity != 0 && selectorRow.Quantity <= counter {
+			break
+		}
+		nodeRow, ok := nodes[candidate]
+		if !ok || !schedulable(nodeRow) {
+			continue
+		}
+		hostnames = append(hostnames, nodeRow.Labels["kubernetes.io/hostname"])
+		counter++
+	}
+	return hostnames, counter
+}
+
+// linkWithin returns true if the link answered the probes within the latency
+func linkWithin(link *networkingv1alpha.NodeLink, maxLatency time.Duration) bool {
+	return link != nil && link.Status.Latency != nil && link.Status.PacketLoss < 100 && link.Status.Latency.Duration <= maxLatency
+}
+
+// schedulable returns true if the node is ready and takes workloads
+func schedulable(nodeRow *corev1.Node) bool {
+	if nodeRow.Spec.Unschedulable || node.GetConditionReadyStatus(nodeRow.DeepCopy()) != trueStr {
+		return false
+	}
+	for _, taint := range nodeRow.Spec.Taints {
+		if (taint.Key == "node-role.kubernetes.io/master" || taint.Key == "node.kubernetes.io/unschedulable") && taint.Effect == noSchedule {
+			return false
+		}
+	}
+	return true
+}
+
+// handleNodeLink enqueues the selective deployments a change of latency moves a node in or out of
+func (c *Controller) handleNodeLink(oldObj, newObj interface{}) {
+	oldLink, _ := oldObj.(*networkingv1alpha.NodeLink)
+	if tombstone, ok := oldObj.(cache.DeletedFinalStateUnknown); ok {
+		oldLink, _ = tombstone.Obj.(*networkingv1alpha.NodeLink)
+	}
+	newLink, _ := newObj.(*networkingv1alpha.NodeLink)
+	selectivedeploymentRaw, err := c.selectivedeploymentsLister.SelectiveDeployments("").List(labels.Everything())
+	if err != nil {
+		return
+	}
+	for _, selectivedeploymentRow := range selectivedeploymentRaw {
+		for _, selectorRow := range selectivedeploymentRow.Spec.Selector {
+			if selectorRow.MaxLatency != nil && linkWithin(oldLink, selectorRow.MaxLatency.Duration) != linkWithin(newLink, selectorRow.MaxLatency.Duration) {
+				c.enqueueSelectiveDeployment(selectivedeploymentRow)
+				break
+			}
+		}
+	}
+}
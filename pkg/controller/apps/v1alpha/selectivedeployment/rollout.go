@@ -0,0 +1,85 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selectivedeployment
+
+import (
+	"strings"
+
+	appsv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/apps/v1alpha"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// selectorLabels are the node labels each selector filters on
+var selectorLabels = map[string][]string{
+	"city":      {"edge-net.io/city"},
+	"state":     {"edge-net.io/state-iso"},
+	"country":   {"edge-net.io/country-iso"},
+	"continent": {"edge-net.io/continent"},
+	"polygon":   {"edge-net.io/lon", "edge-net.io/lat"},
+}
+
+// handleNodeLabels enqueues the selective deployments that filter on a label the node gained,
+// lost, or changed, so that their workloads are re-targeted without waiting for a resync
+func (c *Controller) handleNodeLabels(oldNode, newNode *corev1.Node) {
+	changed := map[string]bool{}
+	for selectorName, labelKeys := range selectorLabels {
+		for _, labelKey := range labelKeys {
+			oldValue, oldOk := oldNode.GetLabels()[labelKey]
+			newValue, newOk := newNode.GetLabels()[labelKey]
+			if oldOk != newOk || oldValue != newValue {
+				changed[selectorName] = true
+			}
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+	selectivedeploymentRaw, err := c.selectivedeploymentsLister.SelectiveDeployments("").List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Couldn't list the selective deployments")
+		return
+	}
+	for _, selectivedeploymentRow := range selectivedeploymentRaw {
+		for _, selectorRow := range selectivedeploymentRow.Spec.Selector {
+			if changed[strings.ToLower(selectorRow.Name)] {
+				klog.V(4).InfoS("Re-targeting the selective deployment", "selectiveDeployment", klog.KObj(selectivedeploymentRow), "node", newNode.GetName())
+				c.enqueueSelectiveDeployment(selectivedeploymentRow)
+				break
+			}
+		}
+	}
+}
+
+// deploymentStrategy returns the rolling update strategy of the deployments re-targeted
+func deploymentStrategy(rollout *appsv1alpha.Rollout) appsv1.DeploymentStrategy {
+	return appsv1.DeploymentStrategy{
+		Type:          appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{MaxSurge: rollout.MaxSurge, MaxUnavailable: rollout.MaxUnavailable},
+	}
+}
+
+// daemonsetStrategy returns the rolling update strategy of the daemonsets re-targeted
+func daemonsetStrategy(rollout *appsv1alpha.Rollout) appsv1.DaemonSetUpdateStrategy {
+	return appsv1.DaemonSetUpdateStrategy{
+		Type:          appsv1.RollingUpdateDaemonSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxSurge: rollout.MaxSurge, MaxUnavailable: rollout.MaxUnavailable},
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selectivedeployment
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/apps/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Definitions of the conditions and the events of the placement
+const (
+	conditionDegraded       = "Degraded"
+	reasonNoNodeMatched     = "NoNodeMatched"
+	reasonNodesMatched      = "NodesMatched"
+	reasonPlacementChanged  = "PlacementChanged"
+	reasonPlacementRestored = "PlacementRestored"
+	messageNoNodeMatched    = "Selector(s) %s match no node"
+	messageNodesMatched     = "Every selector matches at least one node"
+	messagePlacementChanged = "Selector %s %s matches %d node(s), %d joined and %d left"
+)
+
+// workloadStatuses returns the readiness of the workloads of the selective deployment
+func (c *Controller) workloadStatuses(selectivedeploymentCopy *appsv1alpha.SelectiveDeployment) []appsv1alpha.WorkloadStatus {
+	namespace := selectivedeploymentCopy.GetNamespace()
+	replicas := func(specReplicas *int32) int32 {
+		if specReplicas == nil {
+			return 1
+		}
+		return *specReplicas
+	}
+	workloadStatuses := []appsv1alpha.WorkloadStatus{}
+	for _, deployment := range selectivedeploymentCopy.Spec.Workloads.Deployment {
+		workloadStatus := appsv1alpha.WorkloadStatus{Kind: "Deployment", Name: deployment.GetName(), Desired: replicas(deployment.Spec.Replicas)}
+		if deploymentObj, err := c.deploymentsLister.Deployments(namespace).Get(deployment.GetName()); err == nil {
+			workloadStatus.Ready = deploymentObj.Status.ReadyReplicas
+		}
+		workloadStatuses = append(workloadStatuses, workloadStatus)
+	}
+	for _, daemonset := range selectivedeploymentCopy.Spec.Workloads.DaemonSet {
+		workloadStatus := appsv1alpha.WorkloadStatus{Kind: "DaemonSet", Name: daemonset.GetName()}
+		if daemonsetObj, err := c.daemonsetsLister.DaemonSets(namespace).Get(daemonset.GetName()); err == nil {
+			workloadStatus.Desired = daemonsetObj.Status.DesiredNumberScheduled
+			workloadStatus.Ready = daemonsetObj.Status.NumberReady
+		}
+		workloadStatuses = append(workloadStatuses, workloadStatus)
+	}
+	for _, statefulset := range selectivedeploymentCopy.Spec.Workloads.StatefulSet {
+		workloadStatus := appsv1alpha.WorkloadStatus{Kind: "StatefulSet", Name: statefulset.GetName(), Desired: replicas(statefulset.Spec.Replicas)}
+		if statefulsetObj, err := c.statefulsetsLister.StatefulSets(namespace).Get(statefulset.GetName()); err == nil {
+			workloadStatus.Ready = statefulsetObj.Status.ReadyReplicas
+		}
+		workloadStatuses = append(workloadStatuses, workloadStatus)
+	}
+	for _, job := range selectivedeploymentCopy.Spec.Workloads.Job {
+		workloadStatus := appsv1alpha.WorkloadStatus{Kind: "Job", Name: job.GetName(), Desired: replicas(job.Spec.Completions)}
+		if jobObj, err := c.jobsLister.Jobs(namespace).Get(job.GetName()); err == nil {
+			workloadStatus.Ready = jobObj.Status.Succeeded
+		}
+		workloadStatuses = append(workloadStatuses, workloadStatus)
+	}
+	for _, cronjob := range selectivedeploymentCopy.Spec.Workloads.CronJob {
+		workloadStatus := appsv1alpha.WorkloadStatus{Kind: "CronJob", Name: cronjob.GetName()}
+		if cronjobObj, err := c.cronjobsLister.CronJobs(namespace).Get(cronjob.GetName()); err == nil {
+			workloadStatus.Desired = int32(len(cronjobObj.Status.Active))
+			workloadStatus.Ready = workloadStatus.Desired
+		}
+		workloadStatuses = append(workloadStatuses, workloadStatus)
+	}
+	return workloadStatuses
+}
+
+// setDegraded sets the Degraded condition after the nodes the selectors match, and records an
+// event when the condition changes
+func (c *Controller) setDegraded(selectivedeploymentCopy *appsv1alpha.SelectiveDeployment) {
+	unmatched := []string{}
+	for _, selectorStatus := range selectivedeploymentCopy.Status.Selectors {
+		if selectorStatus.Matched == 0 {
+			unmatched = append(unmatched, fmt.Sprintf("%s %s", selectorStatus.Name, selectorStatus.Value))
+		}
+	}
+	condition := metav1.Condition{Type: conditionDegraded, ObservedGeneration: selectivedeploymentCopy.GetGeneration()}
+	if len(unmatched) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reasonNoNodeMatched
+		condition.Message = fmt.Sprintf(messageNoNodeMatched, strings.Join(unmatched, ", "))
+	} else {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonNodesMatched
+		condition.Message = messageNodesMatched
+	}
+
+	if current := meta.FindStatusCondition(selectivedeploymentCopy.Status.Conditions, conditionDegraded); current == nil || current.Status != condition.Status {
+		if condition.Status == metav1.ConditionTrue {
+			c.recorder.Event(selectivedeploymentCopy, corev1.EventTypeWarning, condition.Reason, condition.Message)
+		} else if current != nil {
+			c.recorder.Event(selectivedeploymentCopy, corev1.EventTypeNormal, reasonPlacementRestored, condition.Message)
+		}
+	}
+	meta.SetStatusCondition(&selectivedeploymentCopy.Status.Conditions, condition)
+}
+
+// recordPlacement records an event for each selector whose nodes changed since the last sync,
+// such as when nodes join, leave, or fail
+func (c *Controller) recordPlacement(selectivedeploymentCopy *appsv1alpha.SelectiveDeployment, oldSelectors []appsv1alpha.SelectorStatus) {
+	for i, selectorStatus := range selectivedeploymentCopy.Status.Selectors {
+		if i >= len(oldSelectors) || oldSelectors[i].Name != selectorStatus.Name {
+			continue
+		}
+		joined, left := 0, 0
+		for _, hostname := range selectorStatus.Nodes {
+			if exists, _ := util.Contains(oldSelectors[i].Nodes, hostname); !exists {
+				joined++
+			}
+		}
+		for _, hostname := range oldSelectors[i].Nodes {
+			if exists, _ := util.Contains(selectorStatus.Nodes, hostname); !exists {
+				left++
+			}
+		}
+		if joined != 0 || left != 0 {
+			c.recorder.Event(selectivedeploymentCopy, corev1.EventTypeNormal, reasonPlacementChanged,
+				fmt.Sprintf(messagePlacementChanged, selectorStatus.Name, selectorStatus.Value, selectorStatus.Matched, joined, left))
+		}
+	}
+}
@@ -0,0 +1,487 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	appsv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/apps/v1alpha"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/apps/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/apps/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/helm"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const controllerAgentName = "tenantapp-controller"
+
+// Definitions of the state of the tenantapp resource
+const (
+	successSynced            = "Synced"
+	messageResourceSynced    = "Tenant App synced successfully"
+	failureFound             = "Not Found"
+	messageChartNotFound     = "Requested chart is not in the catalog or not enabled"
+	failureVersion           = "Version Not Allowed"
+	messageVersionNotAllowed = "Requested chart version is not in the catalog"
+	failureNotPermitted      = "Not Permitted"
+	messageNotPermitted      = "Tenant is not enabled"
+	failureRender            = "Render Failed"
+	messageRenderFailed      = "Rendering the chart failed"
+	failureScope             = "Scope Violation"
+	messageScopeViolation    = "Chart contains objects out of the namespace scope"
+	messageKindNotAllowed    = "Chart contains objects of a kind that is not allowed"
+	failureDeploy            = "Deploy Failed"
+	messageDeployFailed      = "Deploying the chart objects failed"
+	successDeployed          = "Deployed"
+	messageDeployed          = "Chart deployed successfully"
+	failure                  = "Failure"
+	deployed                 = "Deployed"
+)
+
+// allowedResources are the resources that the charts can contain. The controller holds its rights on
+// these only, and in the tenant namespaces, it binds no role and grants no right.
+var allowedResources = map[schema.GroupResource]bool{
+	{Group: "", Resource: "configmaps"}:                          true,
+	{Group: "", Resource: "secrets"}:                             true,
+	{Group: "", Resource: "services"}:                            true,
+	{Group: "", Resource: "serviceaccounts"}:                     true,
+	{Group: "", Resource: "persistentvolumeclaims"}:              true,
+	{Group: "", Resource: "pods"}:                                true,
+	{Group: "apps", Resource: "deployments"}:                     true,
+	{Group: "apps", Resource: "statefulsets"}:                    true,
+	{Group: "apps", Resource: "daemonsets"}:                      true,
+	{Group: "apps", Resource: "replicasets"}:                     true,
+	{Group: "batch", Resource: "jobs"}:                           true,
+	{Group: "batch", Resource: "cronjobs"}:                       true,
+	{Group: "autoscaling", Resource: "horizontalpodautoscalers"}: true,
+	{Group: "networking.k8s.io", Resource: "ingresses"}:          true,
+	{Group: "networking.k8s.io", Resource: "networkpolicies"}:    true,
+	{Group: "policy", Resource: "poddisruptionbudgets"}:          true,
+}
+
+// Controller is the controller implementation for Tenant App resources
+type Controller struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+	// edgenetclientset is a clientset for the EdgeNet API groups
+	edgenetclientset clientset.Interface
+	// dynamicclient creates the objects of any kind that a chart contains
+	dynamicclient dynamic.Interface
+	// renderer turns the charts into manifests
+	renderer helm.Renderer
+
+	tenantappsLister listers.TenantAppLister
+	tenantappsSynced cache.InformerSynced
+
+	// workqueue is a rate limited work queue. This is used to queue work to be
+	// processed instead of performing it as soon as a change happens. This
+	// means we can ensure we only process a fixed amount of resources at a
+	// time, and makes it easy to ensure we are never processing the same item
+	// simultaneously in two different workers.
+	workqueue workqueue.RateLimitingInterface
+	// recorder is an event recorder for recording Event resources to the
+	// Kubernetes API.
+	recorder record.EventRecorder
+}
+
+// NewController returns a new controller
+func NewController(
+	kubeclientset kubernetes.Interface,
+	edgenetclientset clientset.Interface,
+	dynamicclient dynamic.Interface,
+	renderer helm.Renderer,
+	tenantappInformer informers.TenantAppInformer) *Controller {
+
+	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
+	klog.V(4).InfoS("Creating event broadcaster")
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartStructuredLogging(0)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+
+	controller := &Controller{
+		kubeclientset:    kubeclientset,
+		edgenetclientset: edgenetclientset,
+		dynamicclient:    dynamicclient,
+		renderer:         renderer,
+		tenantappsLister: tenantappInformer.Lister(),
+		tenantappsSynced: tenantappInformer.Informer().HasSynced,
+		workqueue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "TenantApps"),
+		recorder:         recorder,
+	}
+
+	klog.V(4).InfoS("Setting up event handlers")
+	// Set up an event handler for when Tenant App resources change. Deletions need no handling,
+	// as the objects of a release are owned by the tenant app and garbage collected along with it.
+	tenantappInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueTenantApp,
+		UpdateFunc: func(old, new interface{}) {
+			controller.enqueueTenantApp(new)
+		},
+	})
+
+	return controller
+}
+
+// Run will set up the event handlers for the types of tenant app, as well
+// as syncing informer caches and starting workers. It will block until stopCh
+// is closed, at which point it will shutdown the workqueue and wait for
+// workers to finish processing their current work items.
+func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting Tenant App controller")
+
+	klog.V(4).InfoS("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh,
+		c.tenantappsSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	klog.V(4).InfoS("Starting workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	klog.V(4).InfoS("Started workers")
+	<-stopCh
+	klog.V(4).InfoS("Shutting down workers")
+
+	return nil
+}
+
+// runWorker is a long-running function that will continually call the
+// processNextWorkItem function in order to read and process a message on the
+// workqueue.
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+// processNextWorkItem will read a single work item off the workqueue and
+// attempt to process it, by calling the syncHandler.
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.workqueue.Done(obj)
+		var key string
+		var ok bool
+
+		if key, ok = obj.(string); !ok {
+			c.workqueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			return nil
+		}
+		if err := c.syncHandler(ctx, key); err != nil {
+			c.workqueue.AddRateLimited(key)
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
+		}
+		c.workqueue.Forget(obj)
+		klog.V(4).InfoS("Successfully synced", "key", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+
+	return true
+}
+
+// syncHandler compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the Tenant App
+// resource with the current status of the resource.
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	tenantapp, err := c.tenantappsLister.TenantApps(namespace).Get(name)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("tenantapp '%s' in work queue no longer exists", key))
+			return nil
+		}
+
+		return err
+	}
+
+	// A release is rendered again only when its spec changes
+	if tenantapp.Status.State != deployed || tenantapp.Status.ObservedGeneration != tenantapp.GetGeneration() ||
+		tenantapp.Status.Version != tenantapp.Spec.Version {
+		c.processTenantApp(ctx, tenantapp.DeepCopy())
+	}
+	c.recorder.Event(tenantapp, corev1.EventTypeNormal, successSynced, messageResourceSynced)
+	return nil
+}
+
+// enqueueTenantApp takes a TenantApp resource and converts it into a namespace/name
+// string which is then put onto the work queue. This method should *not* be
+// passed resources of any type other than TenantApp.
+func (c *Controller) enqueueTenantApp(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+func (c *Controller) processTenantApp(ctx context.Context, tenantAppCopy *appsv1alpha.TenantApp) {
+	oldStatus := *tenantAppCopy.Status.DeepCopy()
+	statusUpdate := func() {
+		if !reflect.DeepEqual(oldStatus, tenantAppCopy.Status) {
+			if _, err := c.edgenetclientset.AppsV1alpha().TenantApps(tenantAppCopy.GetNamespace()).UpdateStatus(ctx, tenantAppCopy, metav1.UpdateOptions{}); err != nil {
+				klog.ErrorS(err, "Couldn't update the status of the tenant app", "tenantApp", klog.KObj(tenantAppCopy))
+			}
+		}
+	}
+	defer statusUpdate()
+	setFailure := func(reason, message string) {
+		c.recorder.Event(tenantAppCopy, corev1.EventTypeWarning, reason, message)
+		tenantAppCopy.Status.State = failure
+		tenantAppCopy.Status.Message = message
+		tenantAppCopy.Status.ObservedGeneration = tenantAppCopy.GetGeneration()
+	}
+
+	namespace, err := c.kubeclientset.CoreV1().Namespaces().Get(ctx, tenantAppCopy.GetNamespace(), metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Couldn't get the namespace", "namespace", tenantAppCopy.GetNamespace())
+		return
+	}
+	namespaceLabels := namespace.GetLabels()
+	tenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(ctx, strings.ToLower(namespaceLabels["edge-net.io/tenant"]), metav1.GetOptions{})
+	if err != nil || !tenant.Spec.Enabled {
+		setFailure(failureNotPermitted, messageNotPermitted)
+		return
+	}
+
+	chart, err := c.edgenetclientset.AppsV1alpha().Charts().Get(ctx, tenantAppCopy.Spec.Chart, metav1.GetOptions{})
+	if err != nil || !chart.Spec.Enabled {
+		setFailure(failureFound, messageChartNotFound)
+		return
+	}
+	versionAllowed := false
+	for _, version := range chart.Spec.Versions {
+		if version == tenantAppCopy.Spec.Version {
+			versionAllowed = true
+			break
+		}
+	}
+	if !versionAllowed {
+		setFailure(failureVersion, messageVersionNotAllowed)
+		return
+	}
+
+	values, err := c.composeValues(ctx, chart, tenantAppCopy, namespaceLabels["edge-net.io/kind"])
+	if err != nil {
+		klog.ErrorS(err, "Couldn't compose the values", "tenantApp", klog.KObj(tenantAppCopy))
+		setFailure(failureRender, messageRenderFailed)
+		return
+	}
+	release := helm.Release{Name: tenantAppCopy.GetName(), Namespace: tenantAppCopy.GetNamespace(), Repository: chart.Spec.Repository,
+		Chart: chart.Spec.Chart, Version: tenantAppCopy.Spec.Version, Values: values}
+	manifest, err := c.renderer.Render(release)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't render the chart", "tenantApp", klog.KObj(tenantAppCopy))
+		setFailure(failureRender, messageRenderFailed)
+		return
+	}
+	objects, err := helm.Decode(manifest)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't decode the manifest", "tenantApp", klog.KObj(tenantAppCopy))
+		setFailure(failureRender, messageRenderFailed)
+		return
+	}
+
+	// All objects are checked before creating any, so that a release is never partially out of scope
+	groupResources, err := restmapper.GetAPIGroupResources(c.kubeclientset.Discovery())
+	if err != nil {
+		klog.ErrorS(err, "Couldn't discover the API group resources")
+		setFailure(failureDeploy, messageDeployFailed)
+		return
+	}
+	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
+	mappings := make([]*meta.RESTMapping, len(objects))
+	for i, object := range objects {
+		mapping, err := mapper.RESTMapping(object.GroupVersionKind().GroupKind(), object.GroupVersionKind().Version)
+		if err != nil || mapping.Scope.Name() != meta.RESTScopeNameNamespace ||
+			(object.GetNamespace() != "" && object.GetNamespace() != tenantAppCopy.GetNamespace()) {
+			setFailure(failureScope, fmt.Sprintf("%s: %s %s", messageScopeViolation, object.GetKind(), object.GetName()))
+			return
+		}
+		if !allowedResources[mapping.Resource.GroupResource()] {
+			setFailure(failureScope, fmt.Sprintf("%s: %s %s", messageKindNotAllowed, object.GetKind(), object.GetName()))
+			return
+		}
+		mappings[i] = mapping
+	}
+
+	resources := []appsv1alpha.AppResource{}
+	for i, object := range objects {
+		if err := c.applyObject(ctx, tenantAppCopy, mappings[i].Resource, object); err != nil {
+			klog.ErrorS(err, "Couldn't apply the object", "tenantApp", klog.KObj(tenantAppCopy))
+			setFailure(failureDeploy, messageDeployFailed)
+			return
+		}
+		resources = append(resources, appsv1alpha.AppResource{APIVersion: object.GetAPIVersion(), Kind: object.GetKind(), Name: object.GetName()})
+	}
+	c.pruneObjects(ctx, tenantAppCopy, mapper, resources)
+
+	if tenantAppCopy.Status.State != deployed || tenantAppCopy.Status.Version != tenantAppCopy.Spec.Version ||
+		tenantAppCopy.Status.ObservedGeneration != tenantAppCopy.GetGeneration() {
+		tenantAppCopy.Status.Revision++
+	}
+	c.recorder.Event(tenantAppCopy, corev1.EventTypeNormal, successDeployed, messageDeployed)
+	tenantAppCopy.Status.State = deployed
+	tenantAppCopy.Status.Message = messageDeployed
+	tenantAppCopy.Status.Version = tenantAppCopy.Spec.Version
+	tenantAppCopy.Status.ObservedGeneration = tenantAppCopy.GetGeneration()
+	tenantAppCopy.Status.Resources = resources
+}
+
+// composeValues merges the values of the tenant into the defaults of the catalog, and adds the quota of the
+// namespace under the edgenet key so that charts can size their workloads to fit
+func (c *Controller) composeValues(ctx context.Context, chart *appsv1alpha.Chart, tenantAppCopy *appsv1alpha.TenantApp, namespaceKind string) (map[string]interface{}, error) {
+	defaults := map[string]interface{}{}
+	if len(chart.Spec.Values.Raw) != 0 {
+		if err := json.Unmarshal(chart.Spec.Values.Raw, &defaults); err != nil {
+			return nil, err
+		}
+	}
+	values := map[string]interface{}{}
+	if len(tenantAppCopy.Spec.Values.Raw) != 0 {
+		if err := json.Unmarshal(tenantAppCopy.Spec.Values.Raw, &values); err != nil {
+			return nil, err
+		}
+	}
+	merged := helm.MergeValues(defaults, values)
+
+	quota := map[string]interface{}{}
+	if resourceQuota, err := c.kubeclientset.CoreV1().ResourceQuotas(tenantAppCopy.GetNamespace()).Get(ctx, fmt.Sprintf("%s-quota", namespaceKind), metav1.GetOptions{}); err == nil {
+		for key, value := range resourceQuota.Spec.Hard {
+			quota[string(key)] = value.String()
+		}
+	}
+	// Tenants cannot override the values set by the cluster
+	merged["edgenet"] = map[string]interface{}{"namespace": tenantAppCopy.GetNamespace(), "quota": quota}
+	return merged, nil
+}
+
+// applyObject creates the object of a release in the namespace of the tenant app, or updates it if it exists
+func (c *Controller) applyObject(ctx context.Context, tenantAppCopy *appsv1alpha.TenantApp, resource schema.GroupVersionResource, object *unstructured.Unstructured) error {
+	object.SetNamespace(tenantAppCopy.GetNamespace())
+	objectLabels := object.GetLabels()
+	if objectLabels == nil {
+		objectLabels = map[string]string{}
+	}
+	objectLabels["edge-net.io/generated"] = "true"
+	objectLabels["edge-net.io/tenantapp"] = tenantAppCopy.GetName()
+	object.SetLabels(objectLabels)
+	object.SetOwnerReferences(SetAsOwnerReference(tenantAppCopy))
+
+	client := c.dynamicclient.Resource(resource).Namespace(tenantAppCopy.GetNamespace())
+	if _, err := client.Create(ctx, object, metav1.CreateOptions{}); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return err
+		}
+		current, err := client.Get(ctx, object.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		// Objects of the same name that do not belong to this release are not taken over
+		if current.GetLabels()["edge-net.io/tenantapp"] != tenantAppCopy.GetName() {
+			return fmt.Errorf("%s %s already exists", object.GetKind(), object.GetName())
+		}
+		object.SetResourceVersion(current.GetResourceVersion())
+		if _, err := client.Update(ctx, object, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneObjects removes the objects of the previous revision that the current revision no longer contains
+func (c *Controller) pruneObjects(ctx context.Context, tenantAppCopy *appsv1alpha.TenantApp, mapper meta.RESTMapper, resources []appsv1alpha.AppResource) {
+	current := make(map[appsv1alpha.AppResource]bool)
+	for _, resource := range resources {
+		current[resource] = true
+	}
+	for _, resource := range tenantAppCopy.Status.Resources {
+		if current[resource] {
+			continue
+		}
+		groupVersion, err := schema.ParseGroupVersion(resource.APIVersion)
+		if err != nil {
+			continue
+		}
+		mapping, err := mapper.RESTMapping(schema.GroupKind{Group: groupVersion.Group, Kind: resource.Kind}, groupVersion.Version)
+		if err != nil {
+			continue
+		}
+		if err := c.dynamicclient.Resource(mapping.Resource).Namespace(tenantAppCopy.GetNamespace()).Delete(ctx, resource.Name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			klog.ErrorS(err, "Couldn't prune", "kind", resource.Kind, "name", resource.Name)
+		}
+	}
+}
+
+// SetAsOwnerReference returns the tenant app as owner
+func SetAsOwnerReference(tenantApp *appsv1alpha.TenantApp) []metav1.OwnerReference {
+	// The following section makes tenant app become the owner
+	ownerReferences := []metav1.OwnerReference{}
+	newTenantAppRef := *metav1.NewControllerRef(tenantApp, appsv1alpha.SchemeGroupVersion.WithKind("TenantApp"))
+	takeControl := true
+	newTenantAppRef.Controller = &takeControl
+	ownerReferences = append(ownerReferences, newTenantAppRef)
+	return ownerReferences
+}
@@ -0,0 +1,292 @@
+package tenantapp
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	appsv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/apps/v1alpha"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/helm"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+)
+
+// fakeRenderer returns a canned manifest per chart version and keeps the last release
+type fakeRenderer struct {
+	mutex     sync.Mutex
+	manifests map[string]string
+	last      helm.Release
+}
+
+func (r *fakeRenderer) Render(release helm.Release) ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.last = release
+	manifest, ok := r.manifests[release.Version]
+	if !ok {
+		return nil, fmt.Errorf("version %s not found", release.Version)
+	}
+	return []byte(manifest), nil
+}
+
+func (r *fakeRenderer) lastRelease() helm.Release {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.last
+}
+
+type TestGroup struct {
+	tenantObj    corev1alpha.Tenant
+	chartObj     appsv1alpha.Chart
+	tenantAppObj appsv1alpha.TenantApp
+}
+
+var kubeclientset kubernetes.Interface = testclient.NewSimpleClientset()
+var edgenetclientset versioned.Interface = edgenettestclient.NewSimpleClientset()
+var dynamicclient dynamic.Interface = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+var renderer = &fakeRenderer{manifests: map[string]string{
+	"1.0.0": `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-config
+`,
+	"2.0.0": `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`,
+	"3.0.0": `apiVersion: v1
+kind: Namespace
+metadata:
+  name: escape
+`,
+	"4.0.0": `apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: web
+`,
+}}
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	logrus.SetOutput(ioutil.Discard)
+
+	flag.String("dir", "../../../../..", "Override the directory.")
+	flag.String("smtp-path", "../../../../../configs/smtp_test.yaml", "Set SMTP path.")
+	flag.Parse()
+
+	stopCh := signals.SetupSignalHandler()
+
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, time.Second*30)
+
+	controller := NewController(kubeclientset,
+		edgenetclientset,
+		dynamicclient,
+		renderer,
+		edgenetInformerFactory.Apps().V1alpha().TenantApps())
+
+	edgenetInformerFactory.Start(stopCh)
+
+	go func() {
+		if err := controller.Run(2, stopCh); err != nil {
+			klog.Fatalf("Error running controller: %s", err.Error())
+		}
+	}()
+
+	kubeclientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "services", Kind: "Service", Namespaced: true},
+				{Name: "configmaps", Kind: "ConfigMap", Namespaced: true},
+				{Name: "namespaces", Kind: "Namespace", Namespaced: false},
+			},
+		},
+		{
+			GroupVersion: "rbac.authorization.k8s.io/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "roles", Kind: "Role", Namespaced: true},
+			},
+		},
+	}
+
+	time.Sleep(500 * time.Millisecond)
+
+	os.Exit(m.Run())
+	<-stopCh
+}
+
+// Init syncs the test group
+func (g *TestGroup) Init() {
+	tenantObj := corev1alpha.Tenant{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Tenant",
+			APIVersion: "core.edgenet.io/v1alpha",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "edgenet",
+		},
+		Spec: corev1alpha.TenantSpec{
+			FullName:  "EdgeNet",
+			ShortName: "EdgeNet",
+			URL:       "https://www.edge-net.org",
+			Contact: corev1alpha.Contact{
+				Email:     "joe.public@edge-net.org",
+				FirstName: "Joe",
+				LastName:  "Public",
+				Phone:     "+33NUMBER",
+				Handle:    "joepublic",
+			},
+			Enabled: true,
+		},
+	}
+	chartObj := appsv1alpha.Chart{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Chart",
+			APIVersion: "apps.edgenet.io/v1alpha",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "web",
+		},
+		Spec: appsv1alpha.ChartSpec{
+			Description: "Web server",
+			Repository:  "https://charts.edge-net.org",
+			Chart:       "web",
+			Versions:    []string{"1.0.0", "2.0.0", "3.0.0", "4.0.0"},
+			Values:      runtime.RawExtension{Raw: []byte(`{"replicas":1,"image":{"tag":"stable"}}`)},
+			Enabled:     true,
+		},
+	}
+	tenantAppObj := appsv1alpha.TenantApp{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "TenantApp",
+			APIVersion: "apps.edgenet.io/v1alpha",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "web",
+			Namespace: "edgenet",
+		},
+		Spec: appsv1alpha.TenantAppSpec{
+			Chart:   "web",
+			Version: "1.0.0",
+			Values:  runtime.RawExtension{Raw: []byte(`{"replicas":2,"edgenet":{"quota":{}}}`)},
+		},
+	}
+	g.tenantObj = tenantObj
+	g.chartObj = chartObj
+	g.tenantAppObj = tenantAppObj
+
+	edgenetclientset.CoreV1alpha().Tenants().Create(context.TODO(), g.tenantObj.DeepCopy(), metav1.CreateOptions{})
+	edgenetclientset.AppsV1alpha().Charts().Create(context.TODO(), g.chartObj.DeepCopy(), metav1.CreateOptions{})
+	tenantCoreNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: g.tenantObj.GetName()}}
+	namespaceLabels := map[string]string{"edge-net.io/kind": "core", "edge-net.io/tenant": g.tenantObj.GetName()}
+	tenantCoreNamespace.SetLabels(namespaceLabels)
+	kubeclientset.CoreV1().Namespaces().Create(context.TODO(), tenantCoreNamespace, metav1.CreateOptions{})
+	resourceQuota := &corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: "core-quota", Namespace: g.tenantObj.GetName()},
+		Spec: corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")}}}
+	kubeclientset.CoreV1().ResourceQuotas(g.tenantObj.GetName()).Create(context.TODO(), resourceQuota, metav1.CreateOptions{})
+}
+
+func TestDeploy(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	tenantAppTest := g.tenantAppObj.DeepCopy()
+	tenantAppTest.SetName("web-deploy")
+
+	edgenetclientset.AppsV1alpha().TenantApps(tenantAppTest.GetNamespace()).Create(context.TODO(), tenantAppTest, metav1.CreateOptions{})
+	time.Sleep(time.Millisecond * 500)
+	tenantApp, err := edgenetclientset.AppsV1alpha().TenantApps(tenantAppTest.GetNamespace()).Get(context.TODO(), tenantAppTest.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, deployed, tenantApp.Status.State)
+	util.Equals(t, "1.0.0", tenantApp.Status.Version)
+	util.Equals(t, 1, tenantApp.Status.Revision)
+	util.Equals(t, 2, len(tenantApp.Status.Resources))
+
+	t.Run("values", func(t *testing.T) {
+		values := renderer.lastRelease().Values
+		util.Equals(t, float64(2), values["replicas"])
+		util.Equals(t, map[string]interface{}{"tag": "stable"}, values["image"])
+		// The quota cannot be overridden by the tenant
+		util.Equals(t, map[string]interface{}{"namespace": "edgenet", "quota": map[string]interface{}{"cpu": "8"}}, values["edgenet"])
+	})
+	t.Run("ownership", func(t *testing.T) {
+		service, err := dynamicclient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "services"}).Namespace(tenantAppTest.GetNamespace()).Get(context.TODO(), "web", metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, tenantApp.GetName(), service.GetOwnerReferences()[0].Name)
+		util.Equals(t, tenantApp.GetName(), service.GetLabels()["edge-net.io/tenantapp"])
+	})
+	t.Run("upgrade", func(t *testing.T) {
+		tenantApp.Spec.Version = "2.0.0"
+		edgenetclientset.AppsV1alpha().TenantApps(tenantAppTest.GetNamespace()).Update(context.TODO(), tenantApp, metav1.UpdateOptions{})
+		time.Sleep(time.Millisecond * 500)
+		tenantApp, err := edgenetclientset.AppsV1alpha().TenantApps(tenantAppTest.GetNamespace()).Get(context.TODO(), tenantAppTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, deployed, tenantApp.Status.State)
+		util.Equals(t, "2.0.0", tenantApp.Status.Version)
+		util.Equals(t, 2, tenantApp.Status.Revision)
+		util.Equals(t, 1, len(tenantApp.Status.Resources))
+		// The objects dropped by the new version are pruned
+		_, err = dynamicclient.Resource(schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}).Namespace(tenantAppTest.GetNamespace()).Get(context.TODO(), "web-config", metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+	})
+}
+
+func TestCatalog(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	cases := map[string]struct {
+		chart   string
+		version string
+		message string
+	}{
+		"not in catalog":      {"unknown", "1.0.0", messageChartNotFound},
+		"version not allowed": {"web", "0.1.0", messageVersionNotAllowed},
+		"cluster-scoped":      {"web", "3.0.0", fmt.Sprintf("%s: Namespace escape", messageScopeViolation)},
+		"kind not allowed":    {"web", "4.0.0", fmt.Sprintf("%s: Role web", messageKindNotAllowed)},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			tenantAppTest := g.tenantAppObj.DeepCopy()
+			tenantAppTest.SetName(fmt.Sprintf("web-%s", tc.version))
+			tenantAppTest.Spec.Chart = tc.chart
+			tenantAppTest.Spec.Version = tc.version
+			edgenetclientset.AppsV1alpha().TenantApps(tenantAppTest.GetNamespace()).Create(context.TODO(), tenantAppTest, metav1.CreateOptions{})
+			time.Sleep(time.Millisecond * 500)
+			tenantApp, err := edgenetclientset.AppsV1alpha().TenantApps(tenantAppTest.GetNamespace()).Get(context.TODO(), tenantAppTest.GetName(), metav1.GetOptions{})
+			util.OK(t, err)
+			util.Equals(t, failure, tenantApp.Status.State)
+			util.Equals(t, tc.message, tenantApp.Status.Message)
+		})
+	}
+}
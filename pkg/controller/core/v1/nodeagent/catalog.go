@@ -0,0 +1,146 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeagent
+
+import (
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Catalog declares the agents that run on the nodes of the cluster
+type Catalog struct {
+	Agents []Agent `json:"agents"`
+}
+
+// Agent is a host-level agent deployed as a DaemonSet on every node
+type Agent struct {
+	Name string `json:"name"`
+	// Image without a tag follows the release of the controller, a tag or a digest pins it.
+	Image          string                      `json:"image"`
+	Command        []string                    `json:"command,omitempty"`
+	Args           []string                    `json:"args,omitempty"`
+	Env            []corev1.EnvVar             `json:"env,omitempty"`
+	ServiceAccount string                      `json:"serviceaccount,omitempty"`
+	HostNetwork    bool                        `json:"hostnetwork,omitempty"`
+	HostPID        bool                        `json:"hostpid,omitempty"`
+	Privileged     bool                        `json:"privileged,omitempty"`
+	Capabilities   []corev1.Capability         `json:"capabilities,omitempty"`
+	Resources      corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Variants adapt the agent to the nodes of some pools, the agent runs as is elsewhere.
+	Variants []Variant `json:"variants,omitempty"`
+}
+
+// Variant adapts an agent to the nodes of a pool, it runs as a DaemonSet of its own
+type Variant struct {
+	NodePool string `json:"nodepool"`
+	// Args are appended to the arguments of the agent.
+	Args []string `json:"args,omitempty"`
+	// Env overrides the variables of the agent with the same name.
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// Disabled keeps the agent off the nodes of the pool.
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// DefaultCatalog returns the built-in catalog, which holds the agents the cluster cannot do without.
+// The kubelet configuration agent applies the node configurations, it restarts the kubelet through
+// the process namespace of the host.
+func DefaultCatalog() Catalog {
+	return Catalog{Agents: []Agent{
+		{
+			Name:           "vpnpeer",
+			Image:          "edgenetio/vpnpeer",
+			Command:        []string{"./vpnpeer"},
+			ServiceAccount: "vpnpeer",
+			HostNetwork:    true,
+			Capabilities:   []corev1.Capability{"NET_ADMIN"},
+		},
+		{
+			Name:    "kubeletconfig",
+			Image:   "edgenetio/kubeletconfig",
+			Command: []string{"./kubeletconfig"},
+			Env: []corev1.EnvVar{{Name: "NODE_NAME",
+				ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}}}},
+			ServiceAccount: "kubeletconfig",
+			HostPID:        true,
+			Privileged:     true,
+		},
+	}}
+}
+
+// Validate checks that the names of the agents and of their DaemonSets are valid and unique
+func (c Catalog) Validate() error {
+	names := map[string]bool{}
+	for _, agent := range c.Agents {
+		if agent.Image == "" {
+			return fmt.Errorf("agent %q has no image", agent.Name)
+		}
+		pools := map[string]bool{}
+		for _, variant := range agent.Variants {
+			if errs := validation.IsValidLabelValue(variant.NodePool); variant.NodePool == "" || len(errs) > 0 {
+				return fmt.Errorf("agent %q has an invalid node pool %q", agent.Name, variant.NodePool)
+			}
+			if pools[variant.NodePool] {
+				return fmt.Errorf("agent %q has more than one variant for the node pool %q", agent.Name, variant.NodePool)
+			}
+			pools[variant.NodePool] = true
+		}
+		for _, name := range daemonSetNames(agent) {
+			if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+				return fmt.Errorf("agent %q cannot run as %q: %v", agent.Name, name, errs)
+			}
+			if names[name] {
+				return fmt.Errorf("daemonset %q is defined more than once", name)
+			}
+			names[name] = true
+		}
+	}
+	return nil
+}
+
+// ReadCatalog decodes and validates a catalog written in YAML or JSON
+func ReadCatalog(path string) (Catalog, error) {
+	loaded := Catalog{}
+	file, err := os.Open(path)
+	if err != nil {
+		return loaded, err
+	}
+	defer file.Close()
+	if err := yaml.NewYAMLOrJSONDecoder(file, 4096).Decode(&loaded); err != nil {
+		return loaded, err
+	}
+	return loaded, loaded.Validate()
+}
+
+// daemonSetNames returns the names of the DaemonSets of the agent, one for the nodes outside the
+// pools of the variants and one per variant
+func daemonSetNames(agent Agent) []string {
+	names := []string{agent.Name}
+	for _, variant := range agent.Variants {
+		if !variant.Disabled {
+			names = append(names, variantName(agent, variant))
+		}
+	}
+	return names
+}
+
+func variantName(agent Agent, variant Variant) string {
+	return fmt.Sprintf("%s-%s", agent.Name, variant.NodePool)
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeagent
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// Labels of the DaemonSets of the agents, the ones that no longer match the catalog are removed
+const (
+	nodePoolLabel = "edge-net.io/nodepool"
+	agentLabel    = "edge-net.io/nodeagent"
+	releaseLabel  = "edge-net.io/release"
+)
+
+// Release is the release of the controller, the agent images without a tag are upgraded along with it
+var Release = "v1.0.0"
+
+// Namespace is where the agents run
+var Namespace = "edgenet"
+
+// Interval is the time between two reconciles of the agents
+var Interval = time.Minute
+
+// CatalogPath is the file of the agent catalog, the built-in catalog is in use when empty.
+// The file is read at every reconcile so that the edits take effect without a restart.
+var CatalogPath string
+
+// Controller deploys the agents of the catalog as DaemonSets and keeps them in line with it
+type Controller struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+
+	// catalog is the last valid catalog, it is kept when the file becomes unreadable or invalid
+	catalog Catalog
+}
+
+// NewController returns a new controller
+func NewController(kubeclientset kubernetes.Interface) *Controller {
+	return &Controller{
+		kubeclientset: kubeclientset,
+		catalog:       DefaultCatalog(),
+	}
+}
+
+// Run reconciles the agents at every interval. It will block until stopCh is closed.
+func (c *Controller) Run(stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting NodeAgent controller", "release", Release)
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := c.reconcile(ctx); err != nil {
+			klog.ErrorS(err, "Couldn't reconcile the node agents")
+		}
+	}, Interval)
+
+	<-stopCh
+	klog.V(4).InfoS("Shutting down NodeAgent controller")
+	return nil
+}
+
+// loadCatalog replaces the catalog in use with the content of the catalog file
+func (c *Controller) loadCatalog() {
+	if CatalogPath == "" {
+		return
+	}
+	loaded, err := ReadCatalog(CatalogPath)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't load the node agent catalog", "catalogPath", CatalogPath)
+		return
+	}
+	c.catalog = loaded
+}
+
+// reconcile applies the DaemonSets of the catalog and removes the ones dropped from it. A DaemonSet
+// of the same name deployed by hand is replaced, as its selector cannot be changed.
+func (c *Controller) reconcile(ctx context.Context) error {
+	c.loadCatalog()
+	desired := map[string]bool{}
+	for _, daemonSet := range daemonSets(c.catalog) {
+		desired[daemonSet.GetName()] = true
+		current, err := c.kubeclientset.AppsV1().DaemonSets(Namespace).Get(ctx, daemonSet.GetName(), metav1.GetOptions{})
+		if err == nil {
+			if _, managed := current.GetLabels()[agentLabel]; !managed {
+				klog.InfoS("Replacing the daemonset deployed by hand", "daemonset", klog.KObj(current))
+				if err := c.kubeclientset.AppsV1().DaemonSets(Namespace).Delete(ctx, current.GetName(), metav1.DeleteOptions{}); err != nil {
+					return err
+				}
+			} else if current.GetLabels()[releaseLabel] != Release {
+				klog.InfoS("Upgrading the node agent", "daemonset", klog.KObj(current), "from", current.GetLabels()[releaseLabel], "to", Release)
+			}
+		} else if !errors.IsNotFound(err) {
+			return err
+		}
+		patch, err := access.ApplyPatch(daemonSet, appsv1.SchemeGroupVersion.WithKind("DaemonSet"))
+		if err != nil {
+			return err
+		}
+		if _, err := c.kubeclientset.AppsV1().DaemonSets(Namespace).Patch(ctx, daemonSet.GetName(), types.ApplyPatchType, patch, access.ApplyOptions()); err != nil {
+			return err
+		}
+	}
+
+	daemonSetRaw, err := c.kubeclientset.AppsV1().DaemonSets(Namespace).List(ctx, metav1.ListOptions{LabelSelector: agentLabel})
+	if err != nil {
+		return err
+	}
+	for _, daemonSet := range daemonSetRaw.Items {
+		if desired[daemonSet.GetName()] {
+			continue
+		}
+		klog.InfoS("Removing the node agent dropped from the catalog", "daemonset", klog.KObj(&daemonSet))
+		if err := c.kubeclientset.AppsV1().DaemonSets(Namespace).Delete(ctx, daemonSet.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// daemonSets returns the DaemonSets of the catalog, the DaemonSet of an agent runs on the nodes
+// outside the pools of its variants while each variant runs on the nodes of its pool
+func daemonSets(catalog Catalog) []*appsv1.DaemonSet {
+	daemonSetList := []*appsv1.DaemonSet{}
+	for _, agent := range catalog.Agents {
+		pools := []string{}
+		for _, variant := range agent.Variants {
+			pools = append(pools, variant.NodePool)
+		}
+		daemonSet := newDaemonSet(agent.Name, agent, agent.Args, agent.Env)
+		if len(pools) > 0 {
+			daemonSet.Spec.Template.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{Key: nodePoolLabel, Operator: corev1.NodeSelectorOpNotIn, Values: pools}},
+				}}},
+			}}
+		}
+		daemonSetList = append(daemonSetList, daemonSet)
+
+		for _, variant := range agent.Variants {
+			if variant.Disabled {
+				continue
+			}
+			args := append(append([]string{}, agent.Args...), variant.Args...)
+			daemonSet := newDaemonSet(variantName(agent, variant), agent, args, mergeEnv(agent.Env, variant.Env))
+			daemonSet.Spec.Template.Spec.NodeSelector = map[string]string{nodePoolLabel: variant.NodePool}
+			daemonSetList = append(daemonSetList, daemonSet)
+		}
+	}
+	return daemonSetList
+}
+
+// newDaemonSet returns a DaemonSet that runs the agent on every node, the critical priority and the
+// tolerations keep it on the nodes that are cordoned or reserved for the control plane
+func newDaemonSet(name string, agent Agent, args []string, env []corev1.EnvVar) *appsv1.DaemonSet {
+	selector := map[string]string{"app": "edgenet", "component": agent.Name, agentLabel: name}
+	labels := map[string]string{releaseLabel: Release}
+	for key, value := range selector {
+		labels[key] = value
+	}
+	container := corev1.Container{
+		Name:            agent.Name,
+		Image:           image(agent.Image),
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command:         agent.Command,
+		Args:            args,
+		Env:             env,
+		Resources:       agent.Resources,
+	}
+	if agent.Privileged || len(agent.Capabilities) > 0 {
+		container.SecurityContext = &corev1.SecurityContext{}
+		if agent.Privileged {
+			privileged := true
+			container.SecurityContext.Privileged = &privileged
+		}
+		if len(agent.Capabilities) > 0 {
+			container.SecurityContext.Capabilities = &corev1.Capabilities{Add: agent.Capabilities}
+		}
+	}
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: Namespace, Labels: labels},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers:         []corev1.Container{container},
+					HostNetwork:        agent.HostNetwork,
+					HostPID:            agent.HostPID,
+					PriorityClassName:  "system-cluster-critical",
+					ServiceAccountName: agent.ServiceAccount,
+					Tolerations: []corev1.Toleration{
+						{Key: "CriticalAddonsOnly", Operator: corev1.TolerationOpExists},
+						{Key: "node-role.kubernetes.io/control-plane", Effect: corev1.TaintEffectNoSchedule},
+						{Key: "node.kubernetes.io/unschedulable", Effect: corev1.TaintEffectNoSchedule},
+					},
+				},
+			},
+		},
+	}
+}
+
+// image tags the image with the release unless it is pinned to a tag or a digest, the registry
+// may have a port so only the last element of the path is looked at
+func image(name string) string {
+	if strings.Contains(name, "@") || strings.Contains(path.Base(name), ":") {
+		return name
+	}
+	return fmt.Sprintf("%s:%s", name, Release)
+}
+
+// mergeEnv returns the variables of the agent overridden by those of the variant
+func mergeEnv(env, overrides []corev1.EnvVar) []corev1.EnvVar {
+	merged := []corev1.EnvVar{}
+	overridden := map[string]bool{}
+	for _, variable := range overrides {
+		overridden[variable.Name] = true
+	}
+	for _, variable := range env {
+		if !overridden[variable.Name] {
+			merged = append(merged, variable)
+		}
+	}
+	return append(merged, overrides...)
+}
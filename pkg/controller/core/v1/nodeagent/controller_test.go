@@ -0,0 +1,157 @@
+package nodeagent
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	kubescheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+func newController() *Controller {
+	kubeclientset := testclient.NewSimpleClientset()
+	kubeclientset.PrependReactor("patch", "*", util.ApplyReactor(kubeclientset.Tracker(), kubescheme.Scheme))
+	return NewController(kubeclientset)
+}
+
+func newCatalog() Catalog {
+	return Catalog{Agents: []Agent{
+		{Name: "vpnpeer", Image: "edgenetio/vpnpeer", Args: []string{"--link=edgenetmesh0"}, Env: []corev1.EnvVar{{Name: "MTU", Value: "1420"}},
+			Variants: []Variant{
+				{NodePool: "satellite", Args: []string{"--keepalive=25s"}, Env: []corev1.EnvVar{{Name: "MTU", Value: "1280"}}},
+				{NodePool: "cloud", Disabled: true},
+			}},
+		{Name: "sysctl", Image: "registry.edge-net.io:5000/busybox:1.34", Privileged: true},
+	}}
+}
+
+func (c *Controller) daemonSet(t *testing.T, name string) *appsv1.DaemonSet {
+	daemonSet, err := c.kubeclientset.AppsV1().DaemonSets(Namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	util.OK(t, err)
+	return daemonSet
+}
+
+func TestValidate(t *testing.T) {
+	cases := map[string]struct {
+		catalog Catalog
+		valid   bool
+	}{
+		"default":           {DefaultCatalog(), true},
+		"variants":          {newCatalog(), true},
+		"no image":          {Catalog{Agents: []Agent{{Name: "vpnpeer"}}}, false},
+		"invalid name":      {Catalog{Agents: []Agent{{Name: "VPN peer", Image: "edgenetio/vpnpeer"}}}, false},
+		"duplicate agent":   {Catalog{Agents: []Agent{{Name: "vpnpeer", Image: "edgenetio/vpnpeer"}, {Name: "vpnpeer", Image: "edgenetio/vpnpeer"}}}, false},
+		"duplicate variant": {Catalog{Agents: []Agent{{Name: "vpnpeer", Image: "edgenetio/vpnpeer", Variants: []Variant{{NodePool: "cloud"}, {NodePool: "cloud"}}}}}, false},
+		"no node pool":      {Catalog{Agents: []Agent{{Name: "vpnpeer", Image: "edgenetio/vpnpeer", Variants: []Variant{{}}}}}, false},
+		"name conflict": {Catalog{Agents: []Agent{{Name: "vpnpeer", Image: "edgenetio/vpnpeer", Variants: []Variant{{NodePool: "cloud"}}},
+			{Name: "vpnpeer-cloud", Image: "edgenetio/vpnpeer"}}}, false},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			util.Equals(t, tc.valid, tc.catalog.Validate() == nil)
+		})
+	}
+}
+
+func TestImage(t *testing.T) {
+	cases := map[string]string{
+		"edgenetio/vpnpeer":                         "edgenetio/vpnpeer:v1.0.0",
+		"busybox:1.34":                              "busybox:1.34",
+		"registry.edge-net.io:5000/vpnpeer":         "registry.edge-net.io:5000/vpnpeer:v1.0.0",
+		"edgenetio/vpnpeer@sha256:0123456789abcdef": "edgenetio/vpnpeer@sha256:0123456789abcdef",
+	}
+	for name, expected := range cases {
+		util.Equals(t, expected, image(name))
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	c := newController()
+	c.catalog = newCatalog()
+	util.OK(t, c.reconcile(context.TODO()))
+
+	daemonSet := c.daemonSet(t, "vpnpeer")
+	util.Equals(t, "edgenetio/vpnpeer:v1.0.0", daemonSet.Spec.Template.Spec.Containers[0].Image)
+	util.Equals(t, []string{"satellite", "cloud"}, daemonSet.Spec.Template.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions[0].Values)
+	variant := c.daemonSet(t, "vpnpeer-satellite")
+	util.Equals(t, map[string]string{nodePoolLabel: "satellite"}, variant.Spec.Template.Spec.NodeSelector)
+	util.Equals(t, []string{"--link=edgenetmesh0", "--keepalive=25s"}, variant.Spec.Template.Spec.Containers[0].Args)
+	util.Equals(t, []corev1.EnvVar{{Name: "MTU", Value: "1280"}}, variant.Spec.Template.Spec.Containers[0].Env)
+	_, err := c.kubeclientset.AppsV1().DaemonSets(Namespace).Get(context.TODO(), "vpnpeer-cloud", metav1.GetOptions{})
+	util.Assert(t, err != nil, "disabled variant deployed")
+	sysctl := c.daemonSet(t, "sysctl")
+	util.Equals(t, "registry.edge-net.io:5000/busybox:1.34", sysctl.Spec.Template.Spec.Containers[0].Image)
+	util.Equals(t, true, *sysctl.Spec.Template.Spec.Containers[0].SecurityContext.Privileged)
+
+	t.Run("release upgrade", func(t *testing.T) {
+		defer func(release string) { Release = release }(Release)
+		Release = "v1.1.0"
+		util.OK(t, c.reconcile(context.TODO()))
+		for _, name := range []string{"vpnpeer", "vpnpeer-satellite"} {
+			daemonSet := c.daemonSet(t, name)
+			util.Equals(t, "edgenetio/vpnpeer:v1.1.0", daemonSet.Spec.Template.Spec.Containers[0].Image)
+			util.Equals(t, "v1.1.0", daemonSet.GetLabels()[releaseLabel])
+		}
+		util.Equals(t, "registry.edge-net.io:5000/busybox:1.34", c.daemonSet(t, "sysctl").Spec.Template.Spec.Containers[0].Image)
+	})
+	t.Run("dropped from the catalog", func(t *testing.T) {
+		c.catalog.Agents = c.catalog.Agents[:1]
+		util.OK(t, c.reconcile(context.TODO()))
+		_, err := c.kubeclientset.AppsV1().DaemonSets(Namespace).Get(context.TODO(), "sysctl", metav1.GetOptions{})
+		util.Assert(t, err != nil, "dropped agent kept")
+	})
+}
+
+func TestReplaceManualDaemonSet(t *testing.T) {
+	c := newController()
+	manual := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "vpnpeer", Namespace: Namespace, Labels: map[string]string{"app": "edgenet", "component": "vpnpeer"}},
+		Spec: appsv1.DaemonSetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "edgenet", "component": "vpnpeer"}}}}
+	_, err := c.kubeclientset.AppsV1().DaemonSets(Namespace).Create(context.TODO(), manual, metav1.CreateOptions{})
+	util.OK(t, err)
+	// A DaemonSet that the controller doesn't manage is left alone if it is not in the catalog
+	other := manual.DeepCopy()
+	other.SetName("kube-proxy")
+	_, err = c.kubeclientset.AppsV1().DaemonSets(Namespace).Create(context.TODO(), other, metav1.CreateOptions{})
+	util.OK(t, err)
+
+	util.OK(t, c.reconcile(context.TODO()))
+	daemonSet := c.daemonSet(t, "vpnpeer")
+	util.Equals(t, "vpnpeer", daemonSet.Spec.Selector.MatchLabels[agentLabel])
+	util.Equals(t, []corev1.Capability{"NET_ADMIN"}, daemonSet.Spec.Template.Spec.Containers[0].SecurityContext.Capabilities.Add)
+	util.Equals(t, true, c.daemonSet(t, "kubeletconfig").Spec.Template.Spec.HostPID)
+	c.daemonSet(t, "kube-proxy")
+}
+
+func TestLoadCatalog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nodeagent")
+	util.OK(t, err)
+	defer os.RemoveAll(dir)
+	defer func(path string) { CatalogPath = path }(CatalogPath)
+	CatalogPath = filepath.Join(dir, "nodeagents.yaml")
+
+	c := newController()
+	util.OK(t, ioutil.WriteFile(CatalogPath, []byte("agents:\n- name: sysctl\n  image: busybox:1.34\n  privileged: true\n"), 0644))
+	c.loadCatalog()
+	util.Equals(t, "sysctl", c.catalog.Agents[0].Name)
+
+	// An invalid catalog doesn't replace the one in use
+	util.OK(t, ioutil.WriteFile(CatalogPath, []byte("agents:\n- name: sysctl\n"), 0644))
+	c.loadCatalog()
+	util.Equals(t, "busybox:1.34", c.catalog.Agents[0].Image)
+}
@@ -2,6 +2,7 @@ package nodelabeler
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -9,7 +10,9 @@ import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/EdgeNet-project/edgenet/pkg/node"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 
 	corev1 "k8s.io/api/core/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -22,11 +25,21 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 const controllerAgentName = "nodelabeler-controller"
 
+// Definitions of the events
+const (
+	successAddressChanged = "AddressChanged"
+	messageAddressChanged = "External IP changed from %s to %s, the geolocation labels and the VPN peer endpoint are refreshed"
+)
+
+// annotationExternalIP records the last external IP of the node seen, a renumbering is detected
+// against it even if the controller was down when the address changed
+const annotationExternalIP = "edge-net.io/external-ip"
+
 // The main structure of controller
 type Controller struct {
 	kubeclientset    kubernetes.Interface
@@ -61,7 +74,7 @@ func NewController(
 ) *Controller {
 	// Create event broadcaster
 	utilruntime.Must(scheme.AddToScheme(scheme.Scheme))
-	klog.V(4).Infoln("Creating event broadcaster")
+	klog.V(4).InfoS("Creating event broadcaster")
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartLogging(klog.Infof)
 	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
@@ -79,7 +92,7 @@ func NewController(
 		maxmindLicenseKey: maxmindLicenseKey,
 	}
 
-	klog.Infoln("Setting up event handlers")
+	klog.InfoS("Setting up event handlers")
 
 	// Event handlers deal with events of resources. In here, we take into consideration of adding and updating nodes.
 	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -100,34 +113,35 @@ func NewController(
 func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
 
-	klog.V(4).Infoln("Starting Node Labeler Controller")
+	klog.V(4).InfoS("Starting Node Labeler Controller")
 
-	klog.V(4).Infoln("Waiting for informer caches to sync")
+	klog.V(4).InfoS("Waiting for informer caches to sync")
 
 	if ok := cache.WaitForCacheSync(stopCh,
 		c.synced); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
-	klog.V(4).Infoln("Starting workers")
+	klog.V(4).InfoS("Starting workers")
 	for i := 0; i < threadiness; i++ {
-		go wait.Until(c.runWorker, time.Second, stopCh)
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
 	}
 
-	klog.V(4).Infoln("Started workers")
+	klog.V(4).InfoS("Started workers")
 	<-stopCh
-	klog.V(4).Infoln("Shutting down workers")
+	klog.V(4).InfoS("Shutting down workers")
 
 	return nil
 }
 
-func (c *Controller) runWorker() {
-	for c.processNextWorkItem() {
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
 	}
 }
 
-func (c *Controller) processNextWorkItem() bool {
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
 	obj, shutdown := c.workqueue.Get()
 
 	if shutdown {
@@ -144,12 +158,12 @@ func (c *Controller) processNextWorkItem() bool {
 			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
 			return nil
 		}
-		if err := c.syncHandler(key); err != nil {
+		if err := c.syncHandler(ctx, key); err != nil {
 			c.workqueue.AddRateLimited(key)
-			return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
 		}
 		c.workqueue.Forget(obj)
-		klog.V(4).Infof("Successfully synced '%s'", key)
+		klog.V(4).InfoS("Successfully synced", "key", key)
 		return nil
 	}(obj)
 
@@ -164,7 +178,7 @@ func (c *Controller) processNextWorkItem() bool {
 // syncHandler compares the actual state with the desired, and attempts to
 // converge the two. It then updates the Status block of the Foo resource
 // with the current status of the resource.
-func (c *Controller) syncHandler(key string) error {
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
 	_, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
 		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
@@ -180,30 +194,61 @@ func (c *Controller) syncHandler(key string) error {
 
 		return err
 	}
-	klog.V(4).Infof("processNextItem: object created/updated detected: %s", key)
-	c.setNodeGeolocation(item)
+	klog.V(4).InfoS("processNextItem: object created/updated detected", "key", key)
+	if err := c.trackExternalIP(ctx, item); err != nil {
+		return err
+	}
+	c.setNodeGeolocation(ctx, item)
 
 	return nil
 }
 
-func (c *Controller) setNodeGeolocation(obj interface{}) {
-	klog.V(4).Infoln("Handler.ObjectCreated")
+// trackExternalIP follows the external IP of the node through DHCP leases and ISP renumberings. Once
+// it changes, the endpoint of the VPN peer of the node is moved to the new address, which the
+// geolocation is then taken from, and an event is recorded on the node.
+func (c *Controller) trackExternalIP(ctx context.Context, nodeObj *corev1.Node) error {
+	_, externalIP := node.GetNodeIPAddresses(nodeObj)
+	previousIP := nodeObj.GetAnnotations()[annotationExternalIP]
+	if externalIP == "" || externalIP == previousIP {
+		return nil
+	}
+	if previousIP != "" {
+		klog.InfoS("External IP of the node changed", "node", klog.KObj(nodeObj), "previous", previousIP, "current", externalIP)
+		peer, err := c.edgenetclientset.NetworkingV1alpha().VPNPeers().Get(ctx, nodeObj.GetName(), v1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err == nil && peer.Spec.EndpointAddress != nil && *peer.Spec.EndpointAddress == previousIP {
+			peerCopy := peer.DeepCopy()
+			peerCopy.Spec.EndpointAddress = &externalIP
+			if _, err := c.edgenetclientset.NetworkingV1alpha().VPNPeers().Update(ctx, peerCopy, v1.UpdateOptions{}); err != nil {
+				return err
+			}
+		}
+		c.recorder.Event(nodeObj, corev1.EventTypeNormal, successAddressChanged, fmt.Sprintf(messageAddressChanged, previousIP, externalIP))
+	}
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]string{annotationExternalIP: externalIP}},
+	})
+	_, err := c.kubeclientset.CoreV1().Nodes().Patch(ctx, nodeObj.GetName(), types.MergePatchType, patch, v1.PatchOptions{})
+	return err
+}
+
+func (c *Controller) setNodeGeolocation(ctx context.Context, obj interface{}) {
+	klog.V(4).InfoS("Handler.ObjectCreated")
 	nodeObj := obj.(*corev1.Node)
 
 	internalIP, externalIP := node.GetNodeIPAddresses(nodeObj)
 	result := false
 
 	// 1. Use the VPNPeer endpoint address if available.
-	peer, err := c.edgenetclientset.NetworkingV1alpha().VPNPeers().Get(context.TODO(), nodeObj.Name, v1.GetOptions{})
+	peer, err := c.edgenetclientset.NetworkingV1alpha().VPNPeers().Get(ctx, nodeObj.Name, v1.GetOptions{})
 	if err != nil {
-		klog.V(4).Infof(
-			"Failed to find a matching VPNPeer object for %s: %s. The node IP will be used instead.",
-			nodeObj.Name,
-			err,
-		)
+		klog.V(4).InfoS("Couldn't get the VPN peer, the node IP is used instead", "node", klog.KObj(nodeObj), "err", err)
 	} else {
-		klog.V(4).Infof("VPNPeer endpoint IP: %s", *peer.Spec.EndpointAddress)
+		klog.V(4).InfoS("Using the endpoint of the VPN peer", "node", klog.KObj(nodeObj), "address", *peer.Spec.EndpointAddress)
 		result = node.GetGeolocationByIP(
+			ctx,
 			c.maxmindUrl,
 			c.maxmindAccountId,
 			c.maxmindLicenseKey,
@@ -214,8 +259,9 @@ func (c *Controller) setNodeGeolocation(obj interface{}) {
 
 	// 2. Otherwise use the node external IP if available.
 	if externalIP != "" && !result {
-		klog.V(4).Infof("External IP: %s", externalIP)
+		klog.V(4).InfoS("External IP", "externalIP", externalIP)
 		result = node.GetGeolocationByIP(
+			ctx,
 			c.maxmindUrl,
 			c.maxmindAccountId,
 			c.maxmindLicenseKey,
@@ -226,8 +272,9 @@ func (c *Controller) setNodeGeolocation(obj interface{}) {
 
 	// 3. Otherwise use the node internal IP if available.
 	if internalIP != "" && !result {
-		klog.V(4).Infof("Internal IP: %s", internalIP)
+		klog.V(4).InfoS("Internal IP", "internalIP", internalIP)
 		node.GetGeolocationByIP(
+			ctx,
 			c.maxmindUrl,
 			c.maxmindAccountId,
 			c.maxmindLicenseKey,
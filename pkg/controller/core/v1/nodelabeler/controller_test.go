@@ -16,6 +16,7 @@ import (
 	"github.com/EdgeNet-project/edgenet/pkg/signals"
 	"github.com/EdgeNet-project/edgenet/pkg/util"
 
+	networkingv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/networking/v1alpha"
 	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -23,7 +24,7 @@ import (
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	kubetestclient "k8s.io/client-go/kubernetes/fake"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 // The main structure of test group
@@ -196,3 +197,61 @@ func TestAssigningGeoLabels(t *testing.T) {
 		})
 	}
 }
+
+func TestExternalIPChange(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	nodeObj := g.nodeObj.DeepCopy()
+	nodeObj.ObjectMeta = metav1.ObjectMeta{
+		Name: "renumbered.edge-net.io",
+		Labels: map[string]string{
+			"kubernetes.io/hostname": "renumbered.edge-net.io",
+		},
+	}
+	nodeObj.Status.Addresses = []corev1.NodeAddress{
+		{
+			Type:    "ExternalIP",
+			Address: "206.196.180.220",
+		},
+	}
+	endpointAddress := "206.196.180.220"
+	endpointPort := 51820
+	peer := &networkingv1alpha.VPNPeer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: nodeObj.GetName(),
+		},
+		Spec: networkingv1alpha.VPNPeerSpec{
+			AddressV4:       "10.183.0.1",
+			AddressV6:       "fdb4:ae86:ec99:4004::1",
+			EndpointAddress: &endpointAddress,
+			EndpointPort:    &endpointPort,
+			PublicKey:       "public-key",
+		},
+	}
+	_, err := edgenetclientset.NetworkingV1alpha().VPNPeers().Create(context.TODO(), peer, metav1.CreateOptions{})
+	util.OK(t, err)
+	defer edgenetclientset.NetworkingV1alpha().VPNPeers().Delete(context.TODO(), peer.GetName(), metav1.DeleteOptions{})
+	_, err = kubeclientset.CoreV1().Nodes().Create(context.TODO(), nodeObj, metav1.CreateOptions{})
+	util.OK(t, err)
+	time.Sleep(time.Millisecond * 500)
+	nodeCopy, err := kubeclientset.CoreV1().Nodes().Get(context.TODO(), nodeObj.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, "206.196.180.220", nodeCopy.GetAnnotations()[annotationExternalIP])
+	util.Equals(t, "US", nodeCopy.GetLabels()["edge-net.io/country-iso"])
+
+	t.Run("renumbering", func(t *testing.T) {
+		nodeCopy.Status.Addresses[0].Address = "132.227.123.51"
+		_, err := kubeclientset.CoreV1().Nodes().UpdateStatus(context.TODO(), nodeCopy, metav1.UpdateOptions{})
+		util.OK(t, err)
+		time.Sleep(time.Millisecond * 500)
+		nodeCopy, err := kubeclientset.CoreV1().Nodes().Get(context.TODO(), nodeObj.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, "132.227.123.51", nodeCopy.GetAnnotations()[annotationExternalIP])
+		util.Equals(t, "FR", nodeCopy.GetLabels()["edge-net.io/country-iso"])
+		util.Equals(t, "Pantin", nodeCopy.GetLabels()["edge-net.io/city"])
+		peerCopy, err := edgenetclientset.NetworkingV1alpha().VPNPeers().Get(context.TODO(), peer.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, "132.227.123.51", *peerCopy.Spec.EndpointAddress)
+	})
+}
@@ -0,0 +1,301 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantdns
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/dns"
+	"github.com/EdgeNet-project/edgenet/pkg/remoteip"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+const controllerAgentName = "tenantdns-controller"
+
+// Definitions of the event reasons
+const (
+	failureClaim = "Name Rejected"
+)
+
+// hostnameAnnotation lists the names a Service or an Ingress claims, separated by commas.
+// The hosts of the rules of an Ingress are claimed without it.
+const hostnameAnnotation = "edge-net.io/hostname"
+
+// ownerPrefix is prepended to a name to get the TXT record holding its owner, the records of a name
+// are only changed by the controller if that TXT record is there
+const ownerPrefix = "_edgenet."
+
+// Zone is the zone of the cluster, each tenant gets the subdomain <tenant>.<zone> delegated to it
+var Zone = "edge-net.io"
+
+// Interval is the time between two syncs of the zone
+var Interval = time.Minute
+
+// Controller publishes the names that the Services and the Ingresses of the tenants claim
+type Controller struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+	// provider hosts the zone
+	provider dns.Provider
+	// recorder is an event recorder for recording Event resources to the
+	// Kubernetes API.
+	recorder record.EventRecorder
+}
+
+// claim is a name that an object of a tenant claims along with the targets it points to
+type claim struct {
+	name    string
+	tenant  string
+	targets []string
+	object  runtime.Object
+	meta    metav1.Object
+}
+
+// NewController returns a new controller
+func NewController(kubeclientset kubernetes.Interface, provider dns.Provider) *Controller {
+	klog.V(4).InfoS("Creating event broadcaster")
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartStructuredLogging(0)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+
+	return &Controller{
+		kubeclientset: kubeclientset,
+		provider:      provider,
+		recorder:      recorder,
+	}
+}
+
+// Run syncs the zone at every interval. It will block until stopCh is closed.
+func (c *Controller) Run(stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting TenantDNS controller", "zone", Zone)
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := c.sync(ctx); err != nil {
+			klog.ErrorS(err, "Couldn't sync the zone", "zone", Zone)
+		}
+	}, Interval)
+
+	<-stopCh
+	klog.V(4).InfoS("Shutting down TenantDNS controller")
+	return nil
+}
+
+// sync gathers the claims of the tenants and applies the changes they make to the zone
+func (c *Controller) sync(ctx context.Context) error {
+	claims, err := c.claims(ctx)
+	if err != nil {
+		return err
+	}
+	records, err := c.provider.Records(ctx)
+	if err != nil {
+		return err
+	}
+	changes, rejected := plan(Zone, claims, records)
+	for _, rejection := range rejected {
+		klog.V(4).InfoS("Rejected the claim", "name", rejection.claim.name, "tenant", rejection.claim.tenant, "reason", rejection.reason)
+		c.recorder.Event(rejection.claim.object, corev1.EventTypeWarning, failureClaim, fmt.Sprintf("%s: %s", rejection.claim.name, rejection.reason))
+	}
+	if changes.Empty() {
+		return nil
+	}
+	klog.InfoS("Updating the zone", "zone", Zone, "upsert", len(changes.Upsert), "delete", len(changes.Delete))
+	return c.provider.Apply(ctx, changes)
+}
+
+// claims returns the names claimed in the namespaces of the tenants, the tenant of an object comes
+// from the label of its namespace and never from the object itself
+func (c *Controller) claims(ctx context.Context) ([]claim, error) {
+	namespaceRaw, err := c.kubeclientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: "edge-net.io/tenant"})
+	if err != nil {
+		return nil, err
+	}
+	claims := []claim{}
+	for _, namespaceRow := range namespaceRaw.Items {
+		tenant := namespaceRow.GetLabels()["edge-net.io/tenant"]
+		serviceRaw, err := c.kubeclientset.CoreV1().Services(namespaceRow.GetName()).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range serviceRaw.Items {
+			service := &serviceRaw.Items[i]
+			targets := append([]string{}, service.Spec.ExternalIPs...)
+			for _, ingress := range service.Status.LoadBalancer.Ingress {
+				targets = append(targets, ingress.IP, ingress.Hostname)
+			}
+			for _, name := range annotatedNames(service) {
+				claims = append(claims, claim{name: name, tenant: tenant, targets: targets, object: service, meta: service})
+			}
+		}
+		ingressRaw, err := c.kubeclientset.NetworkingV1().Ingresses(namespaceRow.GetName()).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range ingressRaw.Items {
+			ingress := &ingressRaw.Items[i]
+			targets := []string{}
+			for _, loadBalancer := range ingress.Status.LoadBalancer.Ingress {
+				targets = append(targets, loadBalancer.IP, loadBalancer.Hostname)
+			}
+			names := annotatedNames(ingress)
+			for _, rule := range ingress.Spec.Rules {
+				if rule.Host != "" {
+					names = append(names, dns.Normalize(rule.Host))
+				}
+			}
+			for _, name := range names {
+				claims = append(claims, claim{name: name, tenant: tenant, targets: targets, object: ingress, meta: ingress})
+			}
+		}
+	}
+	return claims, nil
+}
+
+func annotatedNames(obj metav1.Object) []string {
+	names := []string{}
+	for _, name := range strings.Split(obj.GetAnnotations()[hostnameAnnotation], ",") {
+		if name = dns.Normalize(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+type rejection struct {
+	claim  claim
+	reason string
+}
+
+// plan returns the changes that bring the zone in line with the claims and the claims it rejects.
+// A tenant only gets the names of its own subdomain, the earliest object claiming a name wins it, and
+// a name that exists in the zone without the ownership record of the tenant is never taken over.
+// Only the records of the names owned by the controller are ever deleted.
+func plan(zone string, claims []claim, records []dns.Record) (dns.Changes, []rejection) {
+	existing := map[dns.Record]bool{}
+	unowned := map[string]bool{}
+	owners := map[string]string{}
+	for _, record := range records {
+		record.Name = dns.Normalize(record.Name)
+		existing[record] = true
+		if record.Type == "TXT" && strings.HasPrefix(record.Name, ownerPrefix) {
+			if tenant, ok := parseOwner(record.Target); ok {
+				owners[strings.TrimPrefix(record.Name, ownerPrefix)] = tenant
+			}
+		}
+	}
+	for record := range existing {
+		if _, owned := owners[record.Name]; !owned && !strings.HasPrefix(record.Name, ownerPrefix) {
+			unowned[record.Name] = true
+		}
+	}
+
+	sort.SliceStable(claims, func(i, j int) bool {
+		ti, tj := claims[i].meta.GetCreationTimestamp(), claims[j].meta.GetCreationTimestamp()
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return fmt.Sprintf("%s/%s", claims[i].meta.GetNamespace(), claims[i].meta.GetName()) <
+			fmt.Sprintf("%s/%s", claims[j].meta.GetNamespace(), claims[j].meta.GetName())
+	})
+	granted := map[string]claim{}
+	rejected := []rejection{}
+	for _, claimRow := range claims {
+		if winner, ok := granted[claimRow.name]; ok {
+			if winner.object != claimRow.object {
+				rejected = append(rejected, rejection{claimRow, fmt.Sprintf("already claimed by %s/%s", winner.meta.GetNamespace(), winner.meta.GetName())})
+			}
+			continue
+		}
+		subdomain := fmt.Sprintf("%s.%s", claimRow.tenant, dns.Normalize(zone))
+		if !dns.InDomain(claimRow.name, subdomain) {
+			rejected = append(rejected, rejection{claimRow, fmt.Sprintf("the name is outside of %s", subdomain)})
+		} else if owner, ok := owners[claimRow.name]; ok && owner != claimRow.tenant {
+			rejected = append(rejected, rejection{claimRow, fmt.Sprintf("the name is owned by tenant %s", owner)})
+		} else if unowned[claimRow.name] {
+			rejected = append(rejected, rejection{claimRow, "the name exists and is not managed by EdgeNet"})
+		} else {
+			granted[claimRow.name] = claimRow
+		}
+	}
+
+	desired := map[dns.Record]bool{}
+	for name, claimRow := range granted {
+		desired[dns.Record{Name: ownerPrefix + name, Type: "TXT", Target: ownerValue(claimRow.tenant)}] = true
+		for _, target := range claimRow.targets {
+			if target == "" {
+				continue
+			}
+			recordType := remoteip.GetRecordType(target)
+			if recordType == "" {
+				recordType, target = "CNAME", dns.Normalize(target)
+			}
+			desired[dns.Record{Name: name, Type: recordType, Target: target}] = true
+		}
+	}
+	changes := dns.Changes{}
+	for record := range desired {
+		if !existing[record] {
+			changes.Upsert = append(changes.Upsert, record)
+		}
+	}
+	for record := range existing {
+		name := strings.TrimPrefix(record.Name, ownerPrefix)
+		if _, owned := owners[name]; owned && !desired[record] {
+			changes.Delete = append(changes.Delete, record)
+		}
+	}
+	sortRecords(changes.Upsert)
+	sortRecords(changes.Delete)
+	return changes, rejected
+}
+
+func ownerValue(tenant string) string {
+	return fmt.Sprintf("heritage=edgenet,tenant=%s", tenant)
+}
+
+// parseOwner returns the tenant of an ownership record, the TXT records of others are ignored
+func parseOwner(value string) (string, bool) {
+	value = strings.Trim(value, "\"")
+	if !strings.HasPrefix(value, "heritage=edgenet,tenant=") {
+		return "", false
+	}
+	return strings.TrimPrefix(value, "heritage=edgenet,tenant="), true
+}
+
+func sortRecords(records []dns.Record) {
+	sort.Slice(records, func(i, j int) bool {
+		return fmt.Sprintf("%s %s %s", records[i].Name, records[i].Type, records[i].Target) <
+			fmt.Sprintf("%s %s %s", records[j].Name, records[j].Type, records[j].Target)
+	})
+}
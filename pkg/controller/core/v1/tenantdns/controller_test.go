@@ -0,0 +1,136 @@
+package tenantdns
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/dns"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+// provider keeps the zone in memory
+type provider struct {
+	records map[dns.Record]bool
+}
+
+func (p *provider) Records(ctx context.Context) ([]dns.Record, error) {
+	records := []dns.Record{}
+	for record := range p.records {
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (p *provider) Apply(ctx context.Context, changes dns.Changes) error {
+	for _, record := range changes.Delete {
+		delete(p.records, record)
+	}
+	for _, record := range changes.Upsert {
+		p.records[record] = true
+	}
+	return nil
+}
+
+func newController(records ...dns.Record) (*Controller, *provider, *record.FakeRecorder) {
+	zone := &provider{records: map[dns.Record]bool{}}
+	for _, record := range records {
+		zone.records[record] = true
+	}
+	recorder := record.NewFakeRecorder(10)
+	return &Controller{kubeclientset: testclient.NewSimpleClientset(), provider: zone, recorder: recorder}, zone, recorder
+}
+
+func (c *Controller) createNamespace(t *testing.T, name, tenant string) {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"edge-net.io/tenant": tenant}}}
+	_, err := c.kubeclientset.CoreV1().Namespaces().Create(context.TODO(), namespace, metav1.CreateOptions{})
+	util.OK(t, err)
+}
+
+func (c *Controller) createService(t *testing.T, namespace, name, hostname, ip string, created time.Time) {
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, CreationTimestamp: metav1.NewTime(created),
+		Annotations: map[string]string{hostnameAnnotation: hostname}}}
+	service.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: ip}}
+	_, err := c.kubeclientset.CoreV1().Services(namespace).Create(context.TODO(), service, metav1.CreateOptions{})
+	util.OK(t, err)
+}
+
+func TestSync(t *testing.T) {
+	c, zone, recorder := newController(dns.Record{Name: "www.edge-net.io", Type: "A", Target: "192.0.2.1"})
+	c.createNamespace(t, "lip6", "lip6")
+	c.createNamespace(t, "cslash", "cslash")
+	now := time.Now()
+	c.createService(t, "lip6", "web", "web.lip6.edge-net.io", "198.51.100.1", now)
+	ingress := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "lip6"},
+		Spec: networkingv1.IngressSpec{Rules: []networkingv1.IngressRule{{Host: "App.lip6.edge-net.io"}}}}
+	ingress.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: "lb.example.org"}}
+	_, err := c.kubeclientset.NetworkingV1().Ingresses("lip6").Create(context.TODO(), ingress, metav1.CreateOptions{})
+	util.OK(t, err)
+	// The name of another tenant, the name of an existing record, and the name of the cluster are rejected
+	c.createService(t, "cslash", "hijack", "web.lip6.edge-net.io", "203.0.113.1", now)
+	c.createService(t, "cslash", "www", "www.edge-net.io", "203.0.113.1", now)
+	c.createService(t, "cslash", "own", "own.cslash.edge-net.io", "203.0.113.1", now)
+
+	util.OK(t, c.sync(context.TODO()))
+	expected := []dns.Record{
+		{Name: "www.edge-net.io", Type: "A", Target: "192.0.2.1"},
+		{Name: "web.lip6.edge-net.io", Type: "A", Target: "198.51.100.1"},
+		{Name: "_edgenet.web.lip6.edge-net.io", Type: "TXT", Target: "heritage=edgenet,tenant=lip6"},
+		{Name: "app.lip6.edge-net.io", Type: "CNAME", Target: "lb.example.org"},
+		{Name: "_edgenet.app.lip6.edge-net.io", Type: "TXT", Target: "heritage=edgenet,tenant=lip6"},
+		{Name: "own.cslash.edge-net.io", Type: "A", Target: "203.0.113.1"},
+		{Name: "_edgenet.own.cslash.edge-net.io", Type: "TXT", Target: "heritage=edgenet,tenant=cslash"},
+	}
+	util.Equals(t, len(expected), len(zone.records))
+	for _, record := range expected {
+		util.Assert(t, zone.records[record], "missing record %v", record)
+	}
+	util.Equals(t, 2, len(recorder.Events))
+
+	t.Run("release", func(t *testing.T) {
+		util.OK(t, c.kubeclientset.CoreV1().Services("lip6").Delete(context.TODO(), "web", metav1.DeleteOptions{}))
+		util.OK(t, c.sync(context.TODO()))
+		util.Assert(t, !zone.records[expected[1]], "released record kept")
+		util.Assert(t, !zone.records[expected[2]], "ownership record kept")
+		util.Assert(t, zone.records[expected[0]], "unowned record deleted")
+	})
+}
+
+func TestPlanOwnership(t *testing.T) {
+	earlier, later := time.Now().Add(-time.Hour), time.Now()
+	first := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "lip6", CreationTimestamp: metav1.NewTime(earlier)}}
+	second := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "lip6-dev", CreationTimestamp: metav1.NewTime(later)}}
+	claims := []claim{
+		{name: "web.lip6.edge-net.io", tenant: "lip6", targets: []string{"198.51.100.2"}, object: second, meta: second},
+		{name: "web.lip6.edge-net.io", tenant: "lip6", targets: []string{"198.51.100.1"}, object: first, meta: first},
+	}
+	changes, rejected := plan("edge-net.io", claims, nil)
+	util.Equals(t, 1, len(rejected))
+	util.Equals(t, "second", rejected[0].claim.meta.GetName())
+	util.Equals(t, []dns.Record{
+		{Name: "_edgenet.web.lip6.edge-net.io", Type: "TXT", Target: "heritage=edgenet,tenant=lip6"},
+		{Name: "web.lip6.edge-net.io", Type: "A", Target: "198.51.100.1"},
+	}, changes.Upsert)
+
+	// A name owned by another tenant is never taken over, even if the tenant names overlap
+	owned := []dns.Record{{Name: "_edgenet.web.lip6.edge-net.io", Type: "TXT", Target: "\"heritage=edgenet,tenant=lip\""}}
+	changes, rejected = plan("edge-net.io", claims[1:], owned)
+	util.Equals(t, 1, len(rejected))
+	util.Equals(t, 0, len(changes.Upsert))
+}
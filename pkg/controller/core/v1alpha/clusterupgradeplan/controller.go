@@ -0,0 +1,418 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterupgradeplan
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/node"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const controllerAgentName = "clusterupgradeplan-controller"
+
+// Definitions of the state of the clusterupgradeplan resource
+const (
+	successSynced         = "Synced"
+	messageResourceSynced = "Cluster Upgrade Plan synced successfully"
+	upgradeProcedure      = "Upgrade"
+	messageInProgress     = "Nodes are being upgraded"
+	messageCompleted      = "All nodes upgraded"
+	messageHalted         = "Upgrade halted as nodes failed to upgrade"
+	messageInvalidPlan    = "Invalid plan"
+	messageNoNode         = "Node not in the cluster"
+	messageMaintenance    = "Node under maintenance"
+	messageOtherPlan      = "Node being upgraded by another plan"
+	inprogress            = "In Progress"
+	completed             = "Completed"
+	halted                = "Halted"
+	failure               = "Failure"
+	pending               = "Pending"
+	scheduled             = "Scheduled"
+	upgrading             = "Upgrading"
+	upgraded              = "Upgraded"
+	uptodate              = "Up To Date"
+)
+
+// The plan in progress is checked again after checkInterval, in case a node contribution update is missed
+var checkInterval = 30 * time.Second
+
+// Controller is the controller implementation for Cluster Upgrade Plan resources
+type Controller struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+	// edgenetclientset is a clientset for the EdgeNet API groups
+	edgenetclientset clientset.Interface
+
+	clusterupgradeplansLister listers.ClusterUpgradePlanLister
+	clusterupgradeplansSynced cache.InformerSynced
+	nodecontributionsSynced   cache.InformerSynced
+
+	// workqueue is a rate limited work queue. This is used to queue work to be
+	// processed instead of performing it as soon as a change happens. This
+	// means we can ensure we only process a fixed amount of resources at a
+	// time, and makes it easy to ensure we are never processing the same item
+	// simultaneously in two different workers.
+	workqueue workqueue.RateLimitingInterface
+	// recorder is an event recorder for recording Event resources to the
+	// Kubernetes API.
+	recorder record.EventRecorder
+}
+
+// NewController returns a new controller
+func NewController(
+	kubeclientset kubernetes.Interface,
+	edgenetclientset clientset.Interface,
+	clusterupgradeplanInformer informers.ClusterUpgradePlanInformer,
+	nodecontributionInformer informers.NodeContributionInformer) *Controller {
+
+	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
+	klog.V(4).InfoS("Creating event broadcaster")
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartStructuredLogging(0)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+
+	controller := &Controller{
+		kubeclientset:             kubeclientset,
+		edgenetclientset:          edgenetclientset,
+		clusterupgradeplansLister: clusterupgradeplanInformer.Lister(),
+		clusterupgradeplansSynced: clusterupgradeplanInformer.Informer().HasSynced,
+		nodecontributionsSynced:   nodecontributionInformer.Informer().HasSynced,
+		workqueue:                 workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ClusterUpgradePlans"),
+		recorder:                  recorder,
+	}
+
+	klog.V(4).InfoS("Setting up event handlers")
+	// Set up an event handler for when Cluster Upgrade Plan resources change
+	clusterupgradeplanInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueClusterUpgradePlan,
+		UpdateFunc: func(old, new interface{}) {
+			newObj := new.(*corev1alpha.ClusterUpgradePlan)
+			oldObj := old.(*corev1alpha.ClusterUpgradePlan)
+			if !reflect.DeepEqual(newObj.Spec, oldObj.Spec) {
+				controller.enqueueClusterUpgradePlan(new)
+			}
+		},
+	})
+	// The node contribution agent reports the progress of the upgrade in its status
+	nodecontributionInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			newObj := new.(*corev1alpha.NodeContribution)
+			oldObj := old.(*corev1alpha.NodeContribution)
+			if newObj.Status.Upgrade != nil && !reflect.DeepEqual(newObj.Status.Upgrade, oldObj.Status.Upgrade) {
+				controller.workqueue.Add(newObj.Status.Upgrade.Plan)
+			}
+		},
+	})
+
+	return controller
+}
+
+// Run will set up the event handlers for the types of cluster upgrade plan and node contribution, as well
+// as syncing informer caches and starting workers. It will block until stopCh
+// is closed, at which point it will shutdown the workqueue and wait for
+// workers to finish processing their current work items.
+func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting Cluster Upgrade Plan controller")
+
+	klog.V(4).InfoS("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh,
+		c.clusterupgradeplansSynced,
+		c.nodecontributionsSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	klog.V(4).InfoS("Starting workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	klog.V(4).InfoS("Started workers")
+	<-stopCh
+	klog.V(4).InfoS("Shutting down workers")
+
+	return nil
+}
+
+// runWorker is a long-running function that will continually call the
+// processNextWorkItem function in order to read and process a message on the
+// workqueue.
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+// processNextWorkItem will read a single work item off the workqueue and
+// attempt to process it, by calling the syncHandler.
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.workqueue.Done(obj)
+		var key string
+		var ok bool
+
+		if key, ok = obj.(string); !ok {
+			c.workqueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			return nil
+		}
+		if err := c.syncHandler(ctx, key); err != nil {
+			c.workqueue.AddRateLimited(key)
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
+		}
+		c.workqueue.Forget(obj)
+		klog.V(4).InfoS("Successfully synced", "key", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+
+	return true
+}
+
+// syncHandler compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the Cluster Upgrade Plan
+// resource with the current status of the resource.
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	clusterupgradeplan, err := c.clusterupgradeplansLister.Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("clusterupgradeplan '%s' in work queue no longer exists", key))
+			return nil
+		}
+
+		return err
+	}
+
+	clusterupgradeplanCopy := clusterupgradeplan.DeepCopy()
+	if err := c.processClusterUpgradePlan(ctx, clusterupgradeplanCopy); err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(clusterupgradeplan.Status, clusterupgradeplanCopy.Status) {
+		if clusterupgradeplan.Status.State != clusterupgradeplanCopy.Status.State {
+			eventType := corev1.EventTypeNormal
+			if clusterupgradeplanCopy.Status.State == halted || clusterupgradeplanCopy.Status.State == failure {
+				eventType = corev1.EventTypeWarning
+			}
+			c.recorder.Event(clusterupgradeplanCopy, eventType, upgradeProcedure, clusterupgradeplanCopy.Status.Message)
+		}
+		if _, err := c.edgenetclientset.CoreV1alpha().ClusterUpgradePlans().UpdateStatus(ctx, clusterupgradeplanCopy, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	c.recorder.Event(clusterupgradeplan, corev1.EventTypeNormal, successSynced, messageResourceSynced)
+	if clusterupgradeplanCopy.Status.State == inprogress || clusterupgradeplanCopy.Status.State == halted {
+		c.workqueue.AddAfter(key, checkInterval)
+	}
+	return nil
+}
+
+// enqueueClusterUpgradePlan takes a ClusterUpgradePlan resource and converts it into a namespace/name
+// string which is then put onto the work queue. This method should *not* be
+// passed resources of any type other than ClusterUpgradePlan.
+func (c *Controller) enqueueClusterUpgradePlan(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+// processClusterUpgradePlan gathers the progress of the upgrade from the node contributions the plan
+// selects, and schedules the upgrade of the pending nodes as long as the nodes being upgraded stay
+// within the unavailability budget. A failed node halts the rollout.
+func (c *Controller) processClusterUpgradePlan(ctx context.Context, clusterupgradeplanCopy *corev1alpha.ClusterUpgradePlan) error {
+	spec := clusterupgradeplanCopy.Spec
+	if _, err := node.UpgradeCommands(spec.KubeletVersion, spec.ContainerdVersion); err != nil {
+		clusterupgradeplanCopy.Status.State = failure
+		clusterupgradeplanCopy.Status.Message = fmt.Sprintf("%s: %s", messageInvalidPlan, err)
+		return nil
+	}
+	selector := labels.Everything()
+	if spec.Policy.NodeSelector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(spec.Policy.NodeSelector); err != nil {
+			clusterupgradeplanCopy.Status.State = failure
+			clusterupgradeplanCopy.Status.Message = fmt.Sprintf("%s: %s", messageInvalidPlan, err)
+			return nil
+		}
+	}
+
+	nodeRaw, err := c.kubeclientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return err
+	}
+	nodes := make(map[string]corev1.Node)
+	for _, nodeRow := range nodeRaw.Items {
+		nodes[nodeRow.GetName()] = nodeRow
+	}
+	nodecontributionRaw, err := c.edgenetclientset.CoreV1alpha().NodeContributions().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	nodecontributions := nodecontributionRaw.Items
+	sort.Slice(nodecontributions, func(i, j int) bool { return nodecontributions[i].GetName() < nodecontributions[j].GetName() })
+
+	progress := []corev1alpha.NodeUpgradeProgress{}
+	unavailable, failed := 0, 0
+	candidates := []int{}
+	for _, nodecontribution := range nodecontributions {
+		contributedNode, ok := nodes[fmt.Sprintf("%s.edge-net.io", nodecontribution.GetName())]
+		if !ok {
+			continue
+		}
+		nodeProgress := corev1alpha.NodeUpgradeProgress{NodeContribution: nodecontribution.GetName(), State: pending}
+		upgrade := nodecontribution.Status.Upgrade
+		if upgrade != nil && upgrade.Plan == clusterupgradeplanCopy.GetName() && upgrade.KubeletVersion == spec.KubeletVersion && upgrade.ContainerdVersion == spec.ContainerdVersion {
+			nodeProgress.State = upgrade.State
+			nodeProgress.Message = upgrade.Message
+		} else if upToDate(contributedNode, spec) {
+			nodeProgress.State = uptodate
+		} else if upgrade != nil && (upgrade.State == scheduled || upgrade.State == upgrading) {
+			nodeProgress.Message = messageOtherPlan
+		} else if nodecontribution.Spec.Maintenance {
+			nodeProgress.Message = messageMaintenance
+		} else {
+			candidates = append(candidates, len(progress))
+		}
+		switch nodeProgress.State {
+		case scheduled, upgrading:
+			unavailable++
+		case failure:
+			failed++
+		}
+		progress = append(progress, nodeProgress)
+	}
+
+	maxUnavailable := spec.Policy.MaxUnavailable
+	if maxUnavailable <= 0 {
+		maxUnavailable = 1
+	}
+	if failed == 0 {
+		for _, index := range candidates {
+			if unavailable >= maxUnavailable {
+				break
+			}
+			if err := c.schedule(ctx, progress[index].NodeContribution, clusterupgradeplanCopy); err != nil {
+				klog.ErrorS(err, "Couldn't schedule the upgrade", "nodeContribution", progress[index].NodeContribution)
+				continue
+			}
+			progress[index].State = scheduled
+			unavailable++
+		}
+	}
+
+	done := 0
+	for _, nodeProgress := range progress {
+		if nodeProgress.State == upgraded || nodeProgress.State == uptodate {
+			done++
+		}
+	}
+	if len(progress) == 0 {
+		progress = nil
+	}
+	clusterupgradeplanCopy.Status.Nodes = progress
+	clusterupgradeplanCopy.Status.Total = len(progress)
+	clusterupgradeplanCopy.Status.Upgraded = done
+	switch {
+	case failed > 0:
+		clusterupgradeplanCopy.Status.State = halted
+		clusterupgradeplanCopy.Status.Message = fmt.Sprintf("%s: %d of %d", messageHalted, failed, len(progress))
+	case done == len(progress):
+		clusterupgradeplanCopy.Status.State = completed
+		clusterupgradeplanCopy.Status.Message = messageCompleted
+	default:
+		clusterupgradeplanCopy.Status.State = inprogress
+		clusterupgradeplanCopy.Status.Message = messageInProgress
+	}
+	return nil
+}
+
+// schedule hands the upgrade of a node over to the node contribution agent
+func (c *Controller) schedule(ctx context.Context, name string, clusterupgradeplanCopy *corev1alpha.ClusterUpgradePlan) error {
+	nodecontribution, err := c.edgenetclientset.CoreV1alpha().NodeContributions().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	nodecontributionCopy := nodecontribution.DeepCopy()
+	nodecontributionCopy.Status.Upgrade = &corev1alpha.UpgradeStatus{
+		Plan:              clusterupgradeplanCopy.GetName(),
+		KubeletVersion:    clusterupgradeplanCopy.Spec.KubeletVersion,
+		ContainerdVersion: clusterupgradeplanCopy.Spec.ContainerdVersion,
+		State:             scheduled,
+	}
+	_, err = c.edgenetclientset.CoreV1alpha().NodeContributions().UpdateStatus(ctx, nodecontributionCopy, metav1.UpdateOptions{})
+	return err
+}
+
+// upToDate tells whether the node already runs the versions of the plan
+func upToDate(contributedNode corev1.Node, spec corev1alpha.ClusterUpgradePlanSpec) bool {
+	nodeInfo := contributedNode.Status.NodeInfo
+	if nodeInfo.KubeletVersion != fmt.Sprintf("v%s", strings.TrimPrefix(spec.KubeletVersion, "v")) {
+		return false
+	}
+	if spec.ContainerdVersion != "" {
+		version := strings.SplitN(spec.ContainerdVersion, "-", 2)[0]
+		return nodeInfo.ContainerRuntimeVersion == fmt.Sprintf("containerd://%s", version)
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+package clusterupgradeplan
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+// newController returns a controller with contributed nodes named node-1 to node-n, the given
+// ones in Paris and the others in Tokyo, all running kubelet v1.20.9
+func newController(t *testing.T, n int) *Controller {
+	c := &Controller{
+		kubeclientset:    testclient.NewSimpleClientset(),
+		edgenetclientset: edgenettestclient.NewSimpleClientset(),
+		recorder:         record.NewFakeRecorder(100),
+	}
+	for i := 1; i <= n; i++ {
+		city := "paris"
+		if i%2 == 0 {
+			city = "tokyo"
+		}
+		name := fmt.Sprintf("node-%d", i)
+		contributedNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s.edge-net.io", name), Labels: map[string]string{"edge-net.io/city": city}}}
+		contributedNode.Status.NodeInfo = corev1.NodeSystemInfo{KubeletVersion: "v1.20.9", ContainerRuntimeVersion: "containerd://1.4.4"}
+		_, err := c.kubeclientset.CoreV1().Nodes().Create(context.TODO(), contributedNode, metav1.CreateOptions{})
+		util.OK(t, err)
+		nodecontribution := &corev1alpha.NodeContribution{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: corev1alpha.NodeContributionSpec{Enabled: true}}
+		_, err = c.edgenetclientset.CoreV1alpha().NodeContributions().Create(context.TODO(), nodecontribution, metav1.CreateOptions{})
+		util.OK(t, err)
+	}
+	return c
+}
+
+// report plays the node contribution agent, moving the upgrades from a state to another
+func report(t *testing.T, c *Controller, from, to string) {
+	nodecontributionRaw, err := c.edgenetclientset.CoreV1alpha().NodeContributions().List(context.TODO(), metav1.ListOptions{})
+	util.OK(t, err)
+	for _, nodecontribution := range nodecontributionRaw.Items {
+		if nodecontribution.Status.Upgrade == nil || nodecontribution.Status.Upgrade.State != from {
+			continue
+		}
+		nodecontribution.Status.Upgrade.State = to
+		_, err := c.edgenetclientset.CoreV1alpha().NodeContributions().UpdateStatus(context.TODO(), nodecontribution.DeepCopy(), metav1.UpdateOptions{})
+		util.OK(t, err)
+	}
+}
+
+// nodes returns the node contributions of the plan in a state
+func nodes(plan *corev1alpha.ClusterUpgradePlan, state string) []string {
+	names := []string{}
+	for _, nodeProgress := range plan.Status.Nodes {
+		if nodeProgress.State == state {
+			names = append(names, nodeProgress.NodeContribution)
+		}
+	}
+	return names
+}
+
+func TestRollout(t *testing.T) {
+	c := newController(t, 5)
+	plan := &corev1alpha.ClusterUpgradePlan{ObjectMeta: metav1.ObjectMeta{Name: "v1.21.3"},
+		Spec: corev1alpha.ClusterUpgradePlanSpec{KubeletVersion: "v1.21.3", Policy: corev1alpha.RolloutPolicy{MaxUnavailable: 2}}}
+
+	// The nodes are upgraded two at a time
+	for i, wave := range [][]string{{"node-1", "node-2"}, {"node-3", "node-4"}, {"node-5"}} {
+		util.OK(t, c.processClusterUpgradePlan(context.TODO(), plan))
+		util.Equals(t, inprogress, plan.Status.State)
+		util.Equals(t, 5, plan.Status.Total)
+		util.Equals(t, i*2, plan.Status.Upgraded)
+		util.Equals(t, wave, nodes(plan, scheduled))
+		report(t, c, scheduled, upgrading)
+		// No more node gets scheduled while the wave is being upgraded
+		util.OK(t, c.processClusterUpgradePlan(context.TODO(), plan))
+		util.Equals(t, wave, nodes(plan, upgrading))
+		util.Equals(t, []string{}, nodes(plan, scheduled))
+		report(t, c, upgrading, upgraded)
+	}
+	util.OK(t, c.processClusterUpgradePlan(context.TODO(), plan))
+	util.Equals(t, completed, plan.Status.State)
+	util.Equals(t, 5, plan.Status.Upgraded)
+}
+
+func TestHalt(t *testing.T) {
+	c := newController(t, 3)
+	plan := &corev1alpha.ClusterUpgradePlan{ObjectMeta: metav1.ObjectMeta{Name: "v1.21.3"},
+		Spec: corev1alpha.ClusterUpgradePlanSpec{KubeletVersion: "v1.21.3"}}
+	util.OK(t, c.processClusterUpgradePlan(context.TODO(), plan))
+	util.Equals(t, []string{"node-1"}, nodes(plan, scheduled))
+	report(t, c, scheduled, failure)
+	util.OK(t, c.processClusterUpgradePlan(context.TODO(), plan))
+	util.Equals(t, halted, plan.Status.State)
+	util.Equals(t, []string{"node-1"}, nodes(plan, failure))
+	util.Equals(t, []string{"node-2", "node-3"}, nodes(plan, pending))
+}
+
+func TestSelection(t *testing.T) {
+	c := newController(t, 4)
+	// node-3 already runs the versions of the plan and node-1 is under maintenance
+	contributedNode, err := c.kubeclientset.CoreV1().Nodes().Get(context.TODO(), "node-3.edge-net.io", metav1.GetOptions{})
+	util.OK(t, err)
+	contributedNode.Status.NodeInfo = corev1.NodeSystemInfo{KubeletVersion: "v1.21.3", ContainerRuntimeVersion: "containerd://1.4.6"}
+	c.kubeclientset.CoreV1().Nodes().Update(context.TODO(), contributedNode, metav1.UpdateOptions{})
+	nodecontribution, err := c.edgenetclientset.CoreV1alpha().NodeContributions().Get(context.TODO(), "node-1", metav1.GetOptions{})
+	util.OK(t, err)
+	nodecontribution.Spec.Maintenance = true
+	c.edgenetclientset.CoreV1alpha().NodeContributions().Update(context.TODO(), nodecontribution, metav1.UpdateOptions{})
+
+	plan := &corev1alpha.ClusterUpgradePlan{ObjectMeta: metav1.ObjectMeta{Name: "paris"},
+		Spec: corev1alpha.ClusterUpgradePlanSpec{KubeletVersion: "1.21.3", ContainerdVersion: "1.4.6-1",
+			Policy: corev1alpha.RolloutPolicy{MaxUnavailable: 5, NodeSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"edge-net.io/city": "paris"}}}}}
+	util.OK(t, c.processClusterUpgradePlan(context.TODO(), plan))
+	util.Equals(t, 2, plan.Status.Total)
+	util.Equals(t, []corev1alpha.NodeUpgradeProgress{
+		{NodeContribution: "node-1", State: pending, Message: messageMaintenance},
+		{NodeContribution: "node-3", State: uptodate},
+	}, plan.Status.Nodes)
+	util.Equals(t, inprogress, plan.Status.State)
+}
+
+func TestInvalidPlan(t *testing.T) {
+	c := newController(t, 1)
+	plan := &corev1alpha.ClusterUpgradePlan{ObjectMeta: metav1.ObjectMeta{Name: "latest"},
+		Spec: corev1alpha.ClusterUpgradePlanSpec{KubeletVersion: "latest"}}
+	util.OK(t, c.processClusterUpgradePlan(context.TODO(), plan))
+	util.Equals(t, failure, plan.Status.State)
+	util.Equals(t, []string{}, nodes(plan, scheduled))
+}
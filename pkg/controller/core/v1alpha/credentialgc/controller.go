@@ -0,0 +1,336 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialgc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/credential"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// Reasons the credentials are collected for
+const (
+	reasonTenantRemoved      = "tenant removed"
+	reasonTenantDisabled     = "tenant disabled"
+	reasonRoleBindingRemoved = "role binding removed"
+	reasonUserUnbound        = "user unbound"
+	reasonExpired            = "expired"
+)
+
+// Controller revokes and deletes the generated credentials whose owner is gone. The credentials are
+// the secrets labeled by the controllers generating them, the kubeconfigs of the students for example.
+// They go once their tenant is removed or disabled, the role binding granting the user its rights is
+// removed or no longer binds the user, or their expiry is over. An expired credential is revoked by
+// taking the user out of the role binding first, the others have lost their rights already.
+type Controller struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+
+	secretsLister corelisters.SecretLister
+	secretsSynced cache.InformerSynced
+
+	tenantsLister listers.TenantLister
+	tenantsSynced cache.InformerSynced
+
+	// workqueue is a rate limited work queue. This is used to queue work to be
+	// processed instead of performing it as soon as a change happens. This
+	// means we can ensure we only process a fixed amount of resources at a
+	// time, and makes it easy to ensure we are never processing the same item
+	// simultaneously in two different workers.
+	workqueue workqueue.RateLimitingInterface
+}
+
+// NewController returns a new controller, the secret informer is expected to list the secrets
+// labeled as credentials only
+func NewController(
+	kubeclientset kubernetes.Interface,
+	secretInformer coreinformers.SecretInformer,
+	tenantInformer informers.TenantInformer) *Controller {
+
+	controller := &Controller{
+		kubeclientset: kubeclientset,
+		secretsLister: secretInformer.Lister(),
+		secretsSynced: secretInformer.Informer().HasSynced,
+		tenantsLister: tenantInformer.Lister(),
+		tenantsSynced: tenantInformer.Informer().HasSynced,
+		workqueue:     workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Credentials"),
+	}
+
+	klog.V(4).InfoS("Setting up event handlers")
+	// The resyncs of the informer catch the role bindings changed in the meantime
+	secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueSecret,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			controller.enqueueSecret(newObj)
+		},
+	})
+	// The credentials of a tenant go as soon as it is disabled or removed
+	tenantInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			if oldObj.(*corev1alpha.Tenant).Spec.Enabled && !newObj.(*corev1alpha.Tenant).Spec.Enabled {
+				controller.handleTenant(newObj)
+			}
+		},
+		DeleteFunc: controller.handleTenant,
+	})
+
+	return controller
+}
+
+// Run will set up the event handlers for the credentials, as well
+// as syncing informer caches and starting workers. It will block until stopCh
+// is closed, at which point it will shutdown the workqueue and wait for
+// workers to finish processing their current work items.
+func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting credential garbage collector")
+
+	klog.V(4).InfoS("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh,
+		c.secretsSynced,
+		c.tenantsSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	klog.V(4).InfoS("Starting workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	klog.V(4).InfoS("Started workers")
+	<-stopCh
+	klog.V(4).InfoS("Shutting down workers")
+
+	return nil
+}
+
+// runWorker is a long-running function that will continually call the
+// processNextWorkItem function in order to read and process a message on the
+// workqueue.
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+// processNextWorkItem will read a single work item off the workqueue and
+// attempt to process it, by calling the syncHandler.
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.workqueue.Done(obj)
+		var key string
+		var ok bool
+
+		if key, ok = obj.(string); !ok {
+			c.workqueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			return nil
+		}
+		if err := c.syncHandler(ctx, key); err != nil {
+			c.workqueue.AddRateLimited(key)
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
+		}
+		c.workqueue.Forget(obj)
+		klog.V(4).InfoS("Successfully synced", "key", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+
+	return true
+}
+
+// syncHandler collects the credential if its owner is gone, and queues it again for its expiry otherwise
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	secret, err := c.secretsLister.Secrets(namespace).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return c.collectCredential(ctx, secret)
+}
+
+// collectCredential revokes and deletes the credential once it is obsolete
+func (c *Controller) collectCredential(ctx context.Context, secret *corev1.Secret) error {
+	reason, left, err := c.obsolete(ctx, secret)
+	if err != nil {
+		return err
+	}
+	if reason == "" {
+		if left > 0 {
+			c.workqueue.AddAfter(fmt.Sprintf("%s/%s", secret.GetNamespace(), secret.GetName()), left)
+		}
+		return nil
+	}
+	if reason == reasonExpired {
+		if err := c.revoke(ctx, secret); err != nil {
+			return err
+		}
+	}
+	klog.InfoS("Collecting the credential", "secret", klog.KObj(secret), "reason", reason)
+	if err := c.kubeclientset.CoreV1().Secrets(secret.GetNamespace()).Delete(ctx, secret.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// obsolete returns the reason the credential is to be collected for, or the time left before it
+// expires if it is still in use
+func (c *Controller) obsolete(ctx context.Context, secret *corev1.Secret) (string, time.Duration, error) {
+	if tenantName, ok := secret.GetLabels()["edge-net.io/tenant"]; ok {
+		tenant, err := c.tenantsLister.Get(tenantName)
+		if errors.IsNotFound(err) {
+			return reasonTenantRemoved, 0, nil
+		} else if err != nil {
+			return "", 0, err
+		}
+		if !tenant.Spec.Enabled {
+			return reasonTenantDisabled, 0, nil
+		}
+	}
+	annotations := secret.GetAnnotations()
+	var left time.Duration
+	if expiry, ok := annotations[credential.AnnotationExpiry]; ok {
+		expiryTime, err := time.Parse(time.RFC3339, expiry)
+		if err != nil {
+			klog.ErrorS(err, "Couldn't parse the expiry of the credential", "secret", klog.KObj(secret))
+		} else if left = time.Until(expiryTime); left <= 0 {
+			return reasonExpired, 0, nil
+		}
+	}
+	if roleBindingName, ok := annotations[credential.AnnotationRoleBinding]; ok {
+		roleBinding, err := c.kubeclientset.RbacV1().RoleBindings(secret.GetNamespace()).Get(ctx, roleBindingName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return reasonRoleBindingRemoved, 0, nil
+		} else if err != nil {
+			return "", 0, err
+		}
+		user := annotations[credential.AnnotationUser]
+		bound := false
+		for _, subject := range roleBinding.Subjects {
+			if subject.Kind == "User" && strings.EqualFold(subject.Name, user) {
+				bound = true
+				break
+			}
+		}
+		if !bound {
+			return reasonUserUnbound, 0, nil
+		}
+	}
+	return "", left, nil
+}
+
+// revoke takes the user of the credential out of the role binding granting it its rights
+func (c *Controller) revoke(ctx context.Context, secret *corev1.Secret) error {
+	annotations := secret.GetAnnotations()
+	roleBindingName, ok := annotations[credential.AnnotationRoleBinding]
+	if !ok {
+		return nil
+	}
+	roleBinding, err := c.kubeclientset.RbacV1().RoleBindings(secret.GetNamespace()).Get(ctx, roleBindingName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	roleBindingCopy := roleBinding.DeepCopy()
+	roleBindingCopy.Subjects = roleBindingCopy.Subjects[:0]
+	for _, subject := range roleBinding.Subjects {
+		if subject.Kind != "User" || !strings.EqualFold(subject.Name, annotations[credential.AnnotationUser]) {
+			roleBindingCopy.Subjects = append(roleBindingCopy.Subjects, subject)
+		}
+	}
+	if len(roleBindingCopy.Subjects) == len(roleBinding.Subjects) {
+		return nil
+	}
+	_, err = c.kubeclientset.RbacV1().RoleBindings(secret.GetNamespace()).Update(ctx, roleBindingCopy, metav1.UpdateOptions{})
+	return err
+}
+
+// handleTenant enqueues the credentials of the tenant disabled or removed
+func (c *Controller) handleTenant(obj interface{}) {
+	tenant, ok := obj.(*corev1alpha.Tenant)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return
+		}
+		if tenant, ok = tombstone.Obj.(*corev1alpha.Tenant); !ok {
+			return
+		}
+	}
+	secrets, err := c.secretsLister.List(labels.SelectorFromSet(labels.Set{"edge-net.io/tenant": tenant.GetName()}))
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, secret := range secrets {
+		c.enqueueSecret(secret)
+	}
+}
+
+// enqueueSecret takes a Secret resource and converts it into a namespace/name
+// string which is then put onto the work queue.
+func (c *Controller) enqueueSecret(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
@@ -0,0 +1,106 @@
+package credentialgc
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/credential"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+func newController(tenants ...*corev1alpha.Tenant) *Controller {
+	tenantIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, tenant := range tenants {
+		tenantIndexer.Add(tenant)
+	}
+	return &Controller{
+		kubeclientset: testclient.NewSimpleClientset(),
+		secretsLister: corelisters.NewSecretLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})),
+		tenantsLister: listers.NewTenantLister(tenantIndexer),
+		workqueue:     workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Credentials"),
+	}
+}
+
+func newCredential(t *testing.T, c *Controller, name, tenant string, expiry time.Time) *corev1.Secret {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "edgenet",
+		Labels: map[string]string{credential.LabelCredential: "kubeconfig", "edge-net.io/tenant": tenant},
+		Annotations: map[string]string{
+			credential.AnnotationUser:        "john.doe@edge-net.org",
+			credential.AnnotationRoleBinding: "edgenet:clusterrole:student",
+			credential.AnnotationExpiry:      expiry.UTC().Format(time.RFC3339),
+		}}}
+	_, err := c.kubeclientset.CoreV1().Secrets(secret.GetNamespace()).Create(context.TODO(), secret, metav1.CreateOptions{})
+	util.OK(t, err)
+	return secret
+}
+
+func TestCollectCredential(t *testing.T) {
+	tenant := &corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "edgenet"}, Spec: corev1alpha.TenantSpec{Enabled: true}}
+	disabled := &corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "disabled"}}
+	c := newController(tenant, disabled)
+	defer c.workqueue.ShutDown()
+
+	roleBinding := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "edgenet:clusterrole:student", Namespace: "edgenet"},
+		Subjects: []rbacv1.Subject{{Kind: "User", Name: "John.Doe@edge-net.org"}, {Kind: "User", Name: "jane.doe@edge-net.org"}}}
+	_, err := c.kubeclientset.RbacV1().RoleBindings("edgenet").Create(context.TODO(), roleBinding, metav1.CreateOptions{})
+	util.OK(t, err)
+
+	collected := func(t *testing.T, secret *corev1.Secret) bool {
+		util.OK(t, c.collectCredential(context.TODO(), secret))
+		_, err := c.kubeclientset.CoreV1().Secrets(secret.GetNamespace()).Get(context.TODO(), secret.GetName(), metav1.GetOptions{})
+		return errors.IsNotFound(err)
+	}
+
+	t.Run("in use", func(t *testing.T) {
+		secret := newCredential(t, c, "in-use", "edgenet", time.Now().Add(time.Hour))
+		util.Equals(t, false, collected(t, secret))
+	})
+	t.Run("tenant removed", func(t *testing.T) {
+		secret := newCredential(t, c, "tenant-removed", "removed", time.Now().Add(time.Hour))
+		util.Equals(t, true, collected(t, secret))
+	})
+	t.Run("tenant disabled", func(t *testing.T) {
+		secret := newCredential(t, c, "tenant-disabled", "disabled", time.Now().Add(time.Hour))
+		util.Equals(t, true, collected(t, secret))
+	})
+	t.Run("user unbound", func(t *testing.T) {
+		secret := newCredential(t, c, "user-unbound", "edgenet", time.Now().Add(time.Hour))
+		secret.Annotations[credential.AnnotationUser] = "someone@edge-net.org"
+		util.Equals(t, true, collected(t, secret))
+	})
+	t.Run("expired", func(t *testing.T) {
+		secret := newCredential(t, c, "expired", "edgenet", time.Now().Add(-time.Hour))
+		util.Equals(t, true, collected(t, secret))
+		// The user is revoked, the others keep their rights
+		roleBinding, err := c.kubeclientset.RbacV1().RoleBindings("edgenet").Get(context.TODO(), "edgenet:clusterrole:student", metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, []rbacv1.Subject{{Kind: "User", Name: "jane.doe@edge-net.org"}}, roleBinding.Subjects)
+	})
+	t.Run("role binding removed", func(t *testing.T) {
+		util.OK(t, c.kubeclientset.RbacV1().RoleBindings("edgenet").Delete(context.TODO(), "edgenet:clusterrole:student", metav1.DeleteOptions{}))
+		secret := newCredential(t, c, "role-binding-removed", "edgenet", time.Now().Add(time.Hour))
+		util.Equals(t, true, collected(t, secret))
+	})
+}
@@ -0,0 +1,411 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installcheck
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const controllerAgentName = "installcheck-controller"
+
+// Definitions of the state of the install check resource
+const (
+	successSynced         = "Synced"
+	messageResourceSynced = "Install check synced successfully"
+	successStepPassed     = "Step Passed"
+	failureStepFailed     = "Step Failed"
+	successCompleted      = "Completed"
+	messageStarted        = "Install check started"
+	messagePassed         = "All steps passed"
+	messageTimedOut       = "Timed out after %s"
+	messageSkipped        = "Skipped after the failure of an earlier step"
+	messageRetained       = "Skipped to retain the synthetic tenant for examination"
+	running               = "Running"
+	passed                = "Passed"
+	failed                = "Failed"
+	stepPending           = "Pending"
+	stepRunning           = "Running"
+	stepPassed            = "Passed"
+	stepFailed            = "Failed"
+	stepSkipped           = "Skipped"
+)
+
+// The steps of the check in their order, the teardown runs even after a failure
+const (
+	stepTenantRequest = "TenantRequest"
+	stepApproval      = "Approval"
+	stepEstablishment = "Establishment"
+	stepConnectivity  = "Connectivity"
+	stepRBAC          = "RBAC"
+	stepTeardown      = "Teardown"
+)
+
+var steps = []string{stepTenantRequest, stepApproval, stepEstablishment, stepConnectivity, stepRBAC, stepTeardown}
+
+// The defaults of the install check spec
+const (
+	defaultStepTimeout = 300
+	defaultProbeImage  = "busybox:1.34"
+)
+
+// pollInterval spaces the observations of a step that waits for other controllers out
+var pollInterval = 5 * time.Second
+
+// Controller is the controller implementation for InstallCheck resources
+type Controller struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+	// edgenetclientset is a clientset for the EdgeNet API groups
+	edgenetclientset clientset.Interface
+
+	installChecksLister listers.InstallCheckLister
+	installChecksSynced cache.InformerSynced
+
+	// workqueue is a rate limited work queue. This is used to queue work to be
+	// processed instead of performing it as soon as a change happens. This
+	// means we can ensure we only process a fixed amount of resources at a
+	// time, and makes it easy to ensure we are never processing the same item
+	// simultaneously in two different workers.
+	workqueue workqueue.RateLimitingInterface
+	// recorder is an event recorder for recording Event resources to the
+	// Kubernetes API.
+	recorder record.EventRecorder
+}
+
+// NewController returns a new controller
+func NewController(
+	kubeclientset kubernetes.Interface,
+	edgenetclientset clientset.Interface,
+	installCheckInformer informers.InstallCheckInformer) *Controller {
+
+	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
+	klog.V(4).InfoS("Creating event broadcaster")
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartStructuredLogging(0)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+
+	controller := &Controller{
+		kubeclientset:       kubeclientset,
+		edgenetclientset:    edgenetclientset,
+		installChecksLister: installCheckInformer.Lister(),
+		installChecksSynced: installCheckInformer.Informer().HasSynced,
+		workqueue:           workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "InstallChecks"),
+		recorder:            recorder,
+	}
+
+	klog.V(4).InfoS("Setting up event handlers")
+	// Set up an event handler for when InstallCheck resources change
+	installCheckInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueInstallCheck,
+		UpdateFunc: func(old, new interface{}) {
+			newObj := new.(*corev1alpha.InstallCheck)
+			oldObj := old.(*corev1alpha.InstallCheck)
+			// Status updates are skipped, the check is queued again after each step
+			if !reflect.DeepEqual(newObj.Spec, oldObj.Spec) {
+				controller.enqueueInstallCheck(new)
+			}
+		},
+	})
+
+	return controller
+}
+
+// Run will set up the event handlers for the types of install check, as well
+// as syncing informer caches and starting workers. It will block until stopCh
+// is closed, at which point it will shutdown the workqueue and wait for
+// workers to finish processing their current work items.
+func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting InstallCheck controller")
+
+	klog.V(4).InfoS("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh,
+		c.installChecksSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	klog.V(4).InfoS("Starting workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	klog.V(4).InfoS("Started workers")
+	<-stopCh
+	klog.V(4).InfoS("Shutting down workers")
+
+	return nil
+}
+
+// runWorker is a long-running function that will continually call the
+// processNextWorkItem function in order to read and process a message on the
+// workqueue.
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+// processNextWorkItem will read a single work item off the workqueue and
+// attempt to process it, by calling the syncHandler.
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.workqueue.Done(obj)
+		var key string
+		var ok bool
+
+		if key, ok = obj.(string); !ok {
+			c.workqueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			return nil
+		}
+		if err := c.syncHandler(ctx, key); err != nil {
+			c.workqueue.AddRateLimited(key)
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
+		}
+		c.workqueue.Forget(obj)
+		klog.V(4).InfoS("Successfully synced", "key", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+
+	return true
+}
+
+// syncHandler moves the check one step forward and records the outcome in the Status block
+// of the InstallCheck resource
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	if _, err := c.installChecksLister.Get(name); err != nil {
+		if errors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("install check '%s' in work queue no longer exists", key))
+			return nil
+		}
+
+		return err
+	}
+	// The cache may lag behind the status update of the previous step
+	installCheck, err := c.edgenetclientset.CoreV1alpha().InstallChecks().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if finished(installCheck) {
+		return nil
+	}
+
+	installCheckCopy := installCheck.DeepCopy()
+	next := c.processInstallCheck(ctx, installCheckCopy)
+	if !reflect.DeepEqual(installCheck.Status, installCheckCopy.Status) {
+		if _, err := c.edgenetclientset.CoreV1alpha().InstallChecks().UpdateStatus(ctx, installCheckCopy, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	c.recorder.Event(installCheck, corev1.EventTypeNormal, successSynced, messageResourceSynced)
+	if next >= 0 {
+		c.workqueue.AddAfter(key, next)
+	}
+	return nil
+}
+
+// enqueueInstallCheck takes an InstallCheck resource and converts it into a namespace/name
+// string which is then put onto the work queue. This method should *not* be
+// passed resources of any type other than InstallCheck.
+func (c *Controller) enqueueInstallCheck(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+// processInstallCheck moves the check one step forward and returns when to take the next step,
+// a negative duration once the check is over
+func (c *Controller) processInstallCheck(ctx context.Context, installCheckCopy *corev1alpha.InstallCheck) time.Duration {
+	now := metav1.Now()
+	if installCheckCopy.Status.State == "" {
+		installCheckCopy.Status.State = running
+		installCheckCopy.Status.Message = messageStarted
+		installCheckCopy.Status.StartTime = &now
+		installCheckCopy.Status.Tenant = fmt.Sprintf("installcheck-%s", installCheckCopy.GetName())
+		installCheckCopy.Status.Steps = []corev1alpha.InstallCheckStep{}
+		for _, name := range steps {
+			installCheckCopy.Status.Steps = append(installCheckCopy.Status.Steps, corev1alpha.InstallCheckStep{Name: name, State: stepPending})
+		}
+		return 0
+	}
+
+	step := currentStep(installCheckCopy)
+	if step == nil {
+		c.complete(installCheckCopy)
+		return -1
+	}
+	if step.State == stepPending {
+		step.State = stepRunning
+		step.StartTime = &now
+		installCheckCopy.Status.Step = step.Name
+		return 0
+	}
+
+	done, diagnostics, err := c.runStep(ctx, installCheckCopy, step.Name)
+	timeout := time.Duration(stepTimeout(installCheckCopy)) * time.Second
+	switch {
+	case err != nil:
+		c.failStep(installCheckCopy, step, err.Error(), diagnostics)
+	case done:
+		step.State = stepPassed
+		step.Message = passMessages[step.Name]
+		step.Diagnostics = nil
+		step.CompletionTime = &now
+		c.recorder.Event(installCheckCopy, corev1.EventTypeNormal, successStepPassed, fmt.Sprintf("%s: %s", step.Name, step.Message))
+	case now.Sub(step.StartTime.Time) >= timeout:
+		c.failStep(installCheckCopy, step, fmt.Sprintf(messageTimedOut, timeout), diagnostics)
+	default:
+		// Waiting for the other controllers, the diagnostics tell what the step is waiting for
+		step.Diagnostics = diagnostics
+		return pollInterval
+	}
+	return 0
+}
+
+// runStep carries out the step or observes its progress, it returns true once the step passed
+// and an error if it failed
+func (c *Controller) runStep(ctx context.Context, installCheckCopy *corev1alpha.InstallCheck, name string) (bool, []string, error) {
+	switch name {
+	case stepTenantRequest:
+		return c.requestTenant(ctx, installCheckCopy)
+	case stepApproval:
+		return c.approveTenantRequest(ctx, installCheckCopy)
+	case stepEstablishment:
+		return c.observeEstablishment(ctx, installCheckCopy)
+	case stepConnectivity:
+		return c.probeConnectivity(ctx, installCheckCopy)
+	case stepRBAC:
+		return c.probeRBAC(ctx, installCheckCopy)
+	case stepTeardown:
+		return c.teardown(ctx, installCheckCopy)
+	}
+	return false, nil, fmt.Errorf("unknown step %s", name)
+}
+
+// failStep marks the step as failed and skips the rest, except for the teardown that removes the
+// synthetic tenant unless it is to be retained
+func (c *Controller) failStep(installCheckCopy *corev1alpha.InstallCheck, step *corev1alpha.InstallCheckStep, message string, diagnostics []string) {
+	now := metav1.Now()
+	step.State = stepFailed
+	step.Message = message
+	step.Diagnostics = diagnostics
+	step.CompletionTime = &now
+	c.recorder.Event(installCheckCopy, corev1.EventTypeWarning, failureStepFailed, fmt.Sprintf("%s: %s", step.Name, message))
+	for i := range installCheckCopy.Status.Steps {
+		rest := &installCheckCopy.Status.Steps[i]
+		if rest.State != stepPending {
+			continue
+		}
+		if rest.Name != stepTeardown {
+			rest.State = stepSkipped
+			rest.Message = messageSkipped
+		} else if installCheckCopy.Spec.Retain {
+			rest.State = stepSkipped
+			rest.Message = messageRetained
+		}
+	}
+}
+
+// complete puts the check into a final state, the check passes if all of its steps did
+func (c *Controller) complete(installCheckCopy *corev1alpha.InstallCheck) {
+	now := metav1.Now()
+	installCheckCopy.Status.State = passed
+	installCheckCopy.Status.Message = messagePassed
+	for _, step := range installCheckCopy.Status.Steps {
+		if step.State == stepFailed {
+			installCheckCopy.Status.State = failed
+			installCheckCopy.Status.Message = fmt.Sprintf("%s failed: %s", step.Name, step.Message)
+			break
+		}
+	}
+	installCheckCopy.Status.Step = ""
+	installCheckCopy.Status.CompletionTime = &now
+	eventType := corev1.EventTypeNormal
+	if installCheckCopy.Status.State != passed {
+		eventType = corev1.EventTypeWarning
+	}
+	c.recorder.Event(installCheckCopy, eventType, successCompleted, fmt.Sprintf("%s: %s", installCheckCopy.Status.State, installCheckCopy.Status.Message))
+}
+
+// currentStep returns the first step that is not over, nil if all of them are
+func currentStep(installCheck *corev1alpha.InstallCheck) *corev1alpha.InstallCheckStep {
+	for i, step := range installCheck.Status.Steps {
+		if step.State == stepPending || step.State == stepRunning {
+			return &installCheck.Status.Steps[i]
+		}
+	}
+	return nil
+}
+
+// finished tells whether the check reached a final state
+func finished(installCheck *corev1alpha.InstallCheck) bool {
+	return installCheck.Status.State == passed || installCheck.Status.State == failed
+}
+
+func stepTimeout(installCheck *corev1alpha.InstallCheck) int {
+	if installCheck.Spec.StepTimeout <= 0 {
+		return defaultStepTimeout
+	}
+	return installCheck.Spec.StepTimeout
+}
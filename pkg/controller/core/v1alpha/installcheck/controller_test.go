@@ -0,0 +1,206 @@
+package installcheck
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+// cluster plays the part of the other controllers of an installation
+type cluster struct {
+	requestState string
+	tenantState  string
+	podPhase     corev1.PodPhase
+	// Actions the owner is allowed, keyed by verb and resource
+	allowed map[string]bool
+}
+
+func newCluster() *cluster {
+	return &cluster{requestState: requestPending, tenantState: tenantEstablished, podPhase: corev1.PodSucceeded,
+		allowed: map[string]bool{"list pods": true, "create subnamespaces": true, "create rolebindings": true}}
+}
+
+func newController(env *cluster) *Controller {
+	c := &Controller{
+		kubeclientset:    testclient.NewSimpleClientset(),
+		edgenetclientset: edgenettestclient.NewSimpleClientset(),
+		recorder:         record.NewFakeRecorder(100),
+	}
+	c.kubeclientset.(*testclient.Clientset).PrependReactor("create", "subjectaccessreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = env.allowed[attributes.Verb+" "+attributes.Resource]
+		return true, review, nil
+	})
+	return c
+}
+
+// reconcile moves the synthetic objects forward as the tenant request and tenant controllers would
+func (env *cluster) reconcile(t *testing.T, c *Controller, name string) {
+	tenantRequest, err := c.edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), name, metav1.GetOptions{})
+	if err == nil {
+		tenantRequest.Status.State = env.requestState
+		if approvedBy(tenantRequest, approver) && env.requestState == requestPending {
+			tenantRequest.Status.State = requestApproved
+			tenant := &corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: name}}
+			tenant.Status.State = env.tenantState
+			c.edgenetclientset.CoreV1alpha().Tenants().Create(context.TODO(), tenant, metav1.CreateOptions{})
+			c.kubeclientset.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}, metav1.CreateOptions{})
+		}
+		_, err := c.edgenetclientset.RegistrationV1alpha().TenantRequests().UpdateStatus(context.TODO(), tenantRequest, metav1.UpdateOptions{})
+		util.OK(t, err)
+	}
+	if pod, err := c.kubeclientset.CoreV1().Pods(name).Get(context.TODO(), probePodName, metav1.GetOptions{}); err == nil && pod.Status.Phase == "" {
+		pod.Status.Phase = env.podPhase
+		_, err := c.kubeclientset.CoreV1().Pods(name).UpdateStatus(context.TODO(), pod, metav1.UpdateOptions{})
+		util.OK(t, err)
+	}
+	if _, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), name, metav1.GetOptions{}); errors.IsNotFound(err) {
+		c.kubeclientset.CoreV1().Namespaces().Delete(context.TODO(), name, metav1.DeleteOptions{})
+	}
+}
+
+// run processes the check until it is over
+func run(t *testing.T, c *Controller, env *cluster, installCheck *corev1alpha.InstallCheck) {
+	for i := 0; i < 100; i++ {
+		if c.processInstallCheck(context.TODO(), installCheck) < 0 {
+			return
+		}
+		env.reconcile(t, c, installCheck.Status.Tenant)
+	}
+	t.Fatalf("install check %s did not finish", installCheck.GetName())
+}
+
+func newInstallCheck() *corev1alpha.InstallCheck {
+	return &corev1alpha.InstallCheck{ObjectMeta: metav1.ObjectMeta{Name: "check"},
+		Spec: corev1alpha.InstallCheckSpec{Email: "operator@edge-net.org"}}
+}
+
+func stepStates(installCheck *corev1alpha.InstallCheck) map[string]string {
+	states := map[string]string{}
+	for _, step := range installCheck.Status.Steps {
+		states[step.Name] = step.State
+	}
+	return states
+}
+
+func TestInstallCheck(t *testing.T) {
+	cases := map[string]struct {
+		change   func(env *cluster)
+		retain   bool
+		expected string
+		failed   string
+		teardown string
+	}{
+		"passed":           {func(env *cluster) {}, false, passed, "", stepPassed},
+		"request rejected": {func(env *cluster) { env.requestState = requestRejected }, false, failed, stepTenantRequest, stepPassed},
+		"tenant failed":    {func(env *cluster) { env.tenantState = tenantFailure }, false, failed, stepEstablishment, stepPassed},
+		"probe failed":     {func(env *cluster) { env.podPhase = corev1.PodFailed }, false, failed, stepConnectivity, stepPassed},
+		"owner denied":     {func(env *cluster) { env.allowed["list pods"] = false }, false, failed, stepRBAC, stepPassed},
+		"owner too broad":  {func(env *cluster) { env.allowed["create tenants"] = true }, false, failed, stepRBAC, stepPassed},
+		"retained":         {func(env *cluster) { env.podPhase = corev1.PodFailed }, true, failed, stepConnectivity, stepSkipped},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			env := newCluster()
+			tc.change(env)
+			c := newController(env)
+			installCheck := newInstallCheck()
+			installCheck.Spec.Retain = tc.retain
+			run(t, c, env, installCheck)
+			util.Equals(t, tc.expected, installCheck.Status.State)
+			util.Assert(t, installCheck.Status.CompletionTime != nil, "install check not completed")
+			util.Assert(t, finished(installCheck), "install check not finished")
+
+			states := stepStates(installCheck)
+			util.Equals(t, tc.teardown, states[stepTeardown])
+			if tc.failed != "" {
+				util.Equals(t, stepFailed, states[tc.failed])
+				// The steps after the failed one are skipped
+				for i, name := range steps {
+					if name == tc.failed && name != stepRBAC {
+						util.Equals(t, stepSkipped, states[steps[i+1]])
+					}
+				}
+			}
+			_, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), installCheck.Status.Tenant, metav1.GetOptions{})
+			util.Equals(t, !tc.retain, errors.IsNotFound(err))
+		})
+	}
+}
+
+func TestSyntheticTenantRequest(t *testing.T) {
+	env := newCluster()
+	c := newController(env)
+	installCheck := newInstallCheck()
+	installCheck.Spec.Invitation = "token"
+	// Starting the check, then the first step
+	c.processInstallCheck(context.TODO(), installCheck)
+	c.processInstallCheck(context.TODO(), installCheck)
+	util.Equals(t, "installcheck-check", installCheck.Status.Tenant)
+	util.Equals(t, stepTenantRequest, installCheck.Status.Step)
+	util.Equals(t, pollInterval, c.processInstallCheck(context.TODO(), installCheck))
+
+	tenantRequest, err := c.edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), "installcheck-check", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, "operator@edge-net.org", tenantRequest.Spec.Contact.Email)
+	util.Equals(t, "token", tenantRequest.Spec.Invitation)
+	util.Equals(t, "true", tenantRequest.GetLabels()[generatedLabel])
+	util.Equals(t, "check", tenantRequest.GetOwnerReferences()[0].Name)
+}
+
+func TestStepTimeout(t *testing.T) {
+	env := newCluster()
+	// The tenant request controller never admits the request
+	env.requestState = ""
+	c := newController(env)
+	installCheck := newInstallCheck()
+	installCheck.Spec.StepTimeout = 60
+	c.processInstallCheck(context.TODO(), installCheck)
+	c.processInstallCheck(context.TODO(), installCheck)
+	util.Equals(t, pollInterval, c.processInstallCheck(context.TODO(), installCheck))
+	env.reconcile(t, c, installCheck.Status.Tenant)
+	util.Equals(t, pollInterval, c.processInstallCheck(context.TODO(), installCheck))
+	util.Equals(t, []string{"Tenant request state \"\": "}, installCheck.Status.Steps[0].Diagnostics)
+
+	started := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	installCheck.Status.Steps[0].StartTime = &started
+	c.processInstallCheck(context.TODO(), installCheck)
+	util.Equals(t, stepFailed, installCheck.Status.Steps[0].State)
+	util.Equals(t, "Timed out after 1m0s", installCheck.Status.Steps[0].Message)
+	util.Equals(t, stepPending, installCheck.Status.Steps[len(steps)-1].State)
+}
+
+func TestProbePod(t *testing.T) {
+	installCheck := newInstallCheck()
+	installCheck.Status.Tenant = "installcheck-check"
+	pod := probePod(installCheck)
+	util.Equals(t, defaultProbeImage, pod.Spec.Containers[0].Image)
+	util.Equals(t, "installcheck-check", pod.GetNamespace())
+	util.Equals(t, corev1.RestartPolicyNever, pod.Spec.RestartPolicy)
+	installCheck.Spec.ProbeImage = "busybox:latest"
+	util.Equals(t, "busybox:latest", probePod(installCheck).Spec.Containers[0].Image)
+}
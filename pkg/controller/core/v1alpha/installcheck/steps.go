@@ -0,0 +1,284 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installcheck
+
+import (
+	"context"
+	"fmt"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The messages of the steps that passed
+var passMessages = map[string]string{
+	stepTenantRequest: "Tenant request admitted and waiting for approval",
+	stepApproval:      "Tenant request approved",
+	stepEstablishment: "Tenant established",
+	stepConnectivity:  "Probe pod resolved the cluster DNS from the tenant namespace",
+	stepRBAC:          "Tenant owner holds the expected permissions",
+	stepTeardown:      "Synthetic tenant removed",
+}
+
+// The states reported by the tenant request and tenant controllers
+const (
+	requestPending     = "Pending"
+	requestApproved    = "Approved"
+	requestFailure     = "Failure"
+	requestRejected    = "Rejected"
+	tenantEstablished  = "Established"
+	tenantFailure      = "Failure"
+	probePodName       = "installcheck-probe"
+	installCheckLabel  = "edge-net.io/install-check"
+	generatedLabel     = "edge-net.io/generated"
+	probeCommandLookup = "kubernetes.default"
+	// approver is the name the check approves the tenant request under
+	approver = "system:serviceaccount:edgenet:installcheck"
+)
+
+// accessProbe is an action the tenant owner is expected to be allowed or denied
+type accessProbe struct {
+	verb      string
+	group     string
+	resource  string
+	namespace string
+	allowed   bool
+}
+
+// accessProbes returns the actions probed in the RBAC step, the owner manages its tenant and
+// nothing beyond it
+func accessProbes(tenant string) []accessProbe {
+	return []accessProbe{
+		{verb: "list", resource: "pods", namespace: tenant, allowed: true},
+		{verb: "create", group: "core.edgenet.io", resource: "subnamespaces", namespace: tenant, allowed: true},
+		{verb: "create", group: "rbac.authorization.k8s.io", resource: "rolebindings", namespace: tenant, allowed: true},
+		{verb: "list", resource: "secrets", namespace: "kube-system", allowed: false},
+		{verb: "create", group: "core.edgenet.io", resource: "tenants", allowed: false},
+		{verb: "update", group: "core.edgenet.io", resource: "tenantresourcequotas", allowed: false},
+	}
+}
+
+// ownerReferences makes the synthetic objects go away along with the check
+func ownerReferences(installCheck *corev1alpha.InstallCheck) []metav1.OwnerReference {
+	return []metav1.OwnerReference{*metav1.NewControllerRef(installCheck, corev1alpha.SchemeGroupVersion.WithKind("InstallCheck"))}
+}
+
+// requestTenant files the tenant request of the synthetic tenant and waits for it to go through the gate
+func (c *Controller) requestTenant(ctx context.Context, installCheckCopy *corev1alpha.InstallCheck) (bool, []string, error) {
+	name := installCheckCopy.Status.Tenant
+	tenantRequest, err := c.edgenetclientset.RegistrationV1alpha().TenantRequests().Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		tenantRequest = &registrationv1alpha.TenantRequest{ObjectMeta: metav1.ObjectMeta{Name: name, OwnerReferences: ownerReferences(installCheckCopy),
+			Labels: map[string]string{generatedLabel: "true", installCheckLabel: installCheckCopy.GetName()}}}
+		tenantRequest.Spec = registrationv1alpha.TenantRequestSpec{FullName: "EdgeNet install check", ShortName: "installcheck", URL: "https://www.edge-net.org",
+			Address: corev1alpha.Address{Street: "4 place Jussieu", ZIP: "75005", City: "Paris", Region: "Ile-de-France", Country: "France"},
+			Contact: corev1alpha.Contact{Handle: "installcheck", FirstName: "Install", LastName: "Check", Email: installCheckCopy.Spec.Email},
+			ResourceAllocation: map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+			Invitation: installCheckCopy.Spec.Invitation}
+		if _, err := c.edgenetclientset.RegistrationV1alpha().TenantRequests().Create(ctx, tenantRequest, metav1.CreateOptions{}); err != nil {
+			return false, nil, err
+		}
+		return false, []string{fmt.Sprintf("Tenant request %s created", name)}, nil
+	} else if err != nil {
+		return false, []string{err.Error()}, nil
+	}
+	diagnostics := []string{requestDiagnostic(tenantRequest)}
+	switch tenantRequest.Status.State {
+	case requestPending, requestApproved:
+		return true, nil, nil
+	case requestFailure, requestRejected:
+		return false, diagnostics, fmt.Errorf("tenant request %s", tenantRequest.Status.State)
+	}
+	return false, diagnostics, nil
+}
+
+// approvedBy tells whether the approver has approved the request
+func approvedBy(tenantRequest *registrationv1alpha.TenantRequest, approver string) bool {
+	for _, approval := range tenantRequest.Spec.Approvals {
+		if approval.Approver == approver && approval.Verdict == "Approve" {
+			return true
+		}
+	}
+	return false
+}
+
+// approveTenantRequest approves the request as an administrator would and waits for the tenant
+// to be created. The approval stands for a single one, the others are up to the administrators
+// when the quorum is larger.
+func (c *Controller) approveTenantRequest(ctx context.Context, installCheckCopy *corev1alpha.InstallCheck) (bool, []string, error) {
+	tenantRequest, err := c.edgenetclientset.RegistrationV1alpha().TenantRequests().Get(ctx, installCheckCopy.Status.Tenant, metav1.GetOptions{})
+	if err != nil {
+		return false, nil, err
+	}
+	diagnostics := []string{requestDiagnostic(tenantRequest)}
+	if !approvedBy(tenantRequest, approver) {
+		if _, err := c.edgenetclientset.RegistrationV1alpha().TenantRequests().Approve(ctx, tenantRequest.GetName(), approver); err != nil {
+			return false, diagnostics, err
+		}
+		return false, diagnostics, nil
+	}
+	switch tenantRequest.Status.State {
+	case requestApproved:
+		return true, nil, nil
+	case requestFailure, requestRejected:
+		return false, diagnostics, fmt.Errorf("tenant request %s", tenantRequest.Status.State)
+	}
+	return false, diagnostics, nil
+}
+
+// observeEstablishment waits for the tenant controller to establish the synthetic tenant
+func (c *Controller) observeEstablishment(ctx context.Context, installCheckCopy *corev1alpha.InstallCheck) (bool, []string, error) {
+	tenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(ctx, installCheckCopy.Status.Tenant, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return false, []string{fmt.Sprintf("Tenant %s not created yet", installCheckCopy.Status.Tenant)}, nil
+	} else if err != nil {
+		return false, []string{err.Error()}, nil
+	}
+	diagnostics := []string{fmt.Sprintf("Tenant state %q: %s", tenant.Status.State, tenant.Status.Message)}
+	for _, condition := range tenant.Status.Conditions {
+		diagnostics = append(diagnostics, fmt.Sprintf("Condition %s is %s: %s", condition.Type, condition.Status, condition.Message))
+	}
+	switch tenant.Status.State {
+	case tenantEstablished:
+		return true, nil, nil
+	case tenantFailure:
+		return false, diagnostics, fmt.Errorf("tenant failed: %s", tenant.Status.Message)
+	}
+	return false, diagnostics, nil
+}
+
+// probeConnectivity runs a pod in the tenant namespace that resolves the name of the API server,
+// which takes the scheduling, the image pull, the network policies, and the cluster DNS
+func (c *Controller) probeConnectivity(ctx context.Context, installCheckCopy *corev1alpha.InstallCheck) (bool, []string, error) {
+	namespace := installCheckCopy.Status.Tenant
+	pod, err := c.kubeclientset.CoreV1().Pods(namespace).Get(ctx, probePodName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		if _, err := c.kubeclientset.CoreV1().Pods(namespace).Create(ctx, probePod(installCheckCopy), metav1.CreateOptions{}); err != nil {
+			return false, nil, err
+		}
+		return false, []string{fmt.Sprintf("Probe pod created in %s", namespace)}, nil
+	} else if err != nil {
+		return false, []string{err.Error()}, nil
+	}
+	diagnostics := podDiagnostics(pod)
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return true, nil, nil
+	case corev1.PodFailed:
+		return false, diagnostics, fmt.Errorf("probe pod failed")
+	}
+	return false, diagnostics, nil
+}
+
+// probePod returns the pod that probes the connectivity, it fits in the quota of the synthetic tenant
+func probePod(installCheck *corev1alpha.InstallCheck) *corev1.Pod {
+	image := installCheck.Spec.ProbeImage
+	if image == "" {
+		image = defaultProbeImage
+	}
+	resources := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m"), corev1.ResourceMemory: resource.MustParse("32Mi")}
+	allowPrivilegeEscalation := false
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: probePodName, Namespace: installCheck.Status.Tenant,
+		Labels: map[string]string{generatedLabel: "true", installCheckLabel: installCheck.GetName()}},
+		Spec: corev1.PodSpec{RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{Name: "probe", Image: image, Command: []string{"nslookup", probeCommandLookup},
+				Resources:       corev1.ResourceRequirements{Requests: resources, Limits: resources},
+				SecurityContext: &corev1.SecurityContext{AllowPrivilegeEscalation: &allowPrivilegeEscalation}}}}}
+}
+
+// podDiagnostics describes why a pod isn't done yet or failed
+func podDiagnostics(pod *corev1.Pod) []string {
+	diagnostics := []string{fmt.Sprintf("Probe pod phase %s", pod.Status.Phase)}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue && condition.Message != "" {
+			diagnostics = append(diagnostics, fmt.Sprintf("Condition %s: %s", condition.Type, condition.Message))
+		}
+	}
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if waiting := containerStatus.State.Waiting; waiting != nil {
+			diagnostics = append(diagnostics, fmt.Sprintf("Container %s waiting, %s: %s", containerStatus.Name, waiting.Reason, waiting.Message))
+		}
+		if terminated := containerStatus.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+			diagnostics = append(diagnostics, fmt.Sprintf("Container %s exited with %d, %s: %s", containerStatus.Name, terminated.ExitCode, terminated.Reason, terminated.Message))
+		}
+	}
+	return diagnostics
+}
+
+// probeRBAC asks the API server what the owner of the synthetic tenant can do
+func (c *Controller) probeRBAC(ctx context.Context, installCheckCopy *corev1alpha.InstallCheck) (bool, []string, error) {
+	probes := accessProbes(installCheckCopy.Status.Tenant)
+	diagnostics := []string{}
+	for _, probe := range probes {
+		review := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{User: installCheckCopy.Spec.Email,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{Namespace: probe.namespace, Verb: probe.verb, Group: probe.group, Resource: probe.resource}}}
+		result, err := c.kubeclientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+		if err != nil {
+			return false, diagnostics, err
+		}
+		if result.Status.Allowed != probe.allowed {
+			expectation := "denied"
+			if probe.allowed {
+				expectation = "allowed"
+			}
+			diagnostics = append(diagnostics, fmt.Sprintf("Owner is not %s to %s %s in %q", expectation, probe.verb, probe.resource, probe.namespace))
+		}
+	}
+	if len(diagnostics) > 0 {
+		return false, diagnostics, fmt.Errorf("%d of %d access probes failed", len(diagnostics), len(probes))
+	}
+	return true, nil, nil
+}
+
+// teardown removes the synthetic tenant and its request, and waits for the core namespace to go away
+func (c *Controller) teardown(ctx context.Context, installCheckCopy *corev1alpha.InstallCheck) (bool, []string, error) {
+	name := installCheckCopy.Status.Tenant
+	if err := c.edgenetclientset.RegistrationV1alpha().TenantRequests().Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return false, []string{err.Error()}, nil
+	}
+	diagnostics := []string{}
+	if tenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(ctx, name, metav1.GetOptions{}); err == nil {
+		if tenant.GetDeletionTimestamp() == nil {
+			if err := c.edgenetclientset.CoreV1alpha().Tenants().Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				return false, []string{err.Error()}, nil
+			}
+		}
+		diagnostics = append(diagnostics, fmt.Sprintf("Tenant %s is being deleted, finalizers %v", name, tenant.GetFinalizers()))
+	} else if !errors.IsNotFound(err) {
+		return false, []string{err.Error()}, nil
+	}
+	if namespace, err := c.kubeclientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{}); err == nil {
+		diagnostics = append(diagnostics, fmt.Sprintf("Namespace %s is %s, finalizers %v", name, namespace.Status.Phase, namespace.Spec.Finalizers))
+	} else if !errors.IsNotFound(err) {
+		return false, []string{err.Error()}, nil
+	}
+	return len(diagnostics) == 0, diagnostics, nil
+}
+
+// requestDiagnostic describes the state of a tenant request
+func requestDiagnostic(tenantRequest *registrationv1alpha.TenantRequest) string {
+	return fmt.Sprintf("Tenant request state %q: %s", tenantRequest.Status.State, tenantRequest.Status.Message)
+}
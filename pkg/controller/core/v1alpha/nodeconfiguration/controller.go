@@ -0,0 +1,500 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeconfiguration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/config"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/node/kubelet"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const controllerAgentName = "nodeconfiguration-controller"
+
+// Definitions of the state of the node configuration resource
+const (
+	successSynced         = "Synced"
+	messageResourceSynced = "Node configuration synced successfully"
+	failureInvalid        = "Invalid"
+	messageInvalid        = "The kubelet cannot run with the configuration, the nodes keep their settings"
+	failureNodePool       = "Node Pool Not Found"
+	messageNodePool       = "The node pool of the configuration does not exist"
+	warningDiskPressure   = "Disk Pressure"
+	messageApplying       = "The kubelets of the nodes are being restarted with the configuration"
+	messageApplied        = "The kubelets of the nodes run with the configuration"
+	messageOverridden     = "nodes take an older configuration"
+	applying              = "Applying"
+	applied               = "Applied"
+	failure               = "Failure"
+)
+
+// Controller is the controller implementation for NodeConfiguration resources
+type Controller struct {
+	// identity resolves the UID of the cluster mentioned in the emails
+	identity *config.ClusterIdentity
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+	// edgenetclientset is a clientset for the EdgeNet API groups
+	edgenetclientset clientset.Interface
+
+	nodesLister              corelisters.NodeLister
+	nodesSynced              cache.InformerSynced
+	nodePoolsLister          listers.NodePoolLister
+	nodePoolsSynced          cache.InformerSynced
+	nodeConfigurationsLister listers.NodeConfigurationLister
+	nodeConfigurationsSynced cache.InformerSynced
+
+	// workqueue is a rate limited work queue. This is used to queue work to be
+	// processed instead of performing it as soon as a change happens. This
+	// means we can ensure we only process a fixed amount of resources at a
+	// time, and makes it easy to ensure we are never processing the same item
+	// simultaneously in two different workers.
+	workqueue workqueue.RateLimitingInterface
+	// recorder is an event recorder for recording Event resources to the
+	// Kubernetes API.
+	recorder record.EventRecorder
+}
+
+// NewController returns a new controller
+func NewController(
+	kubeclientset kubernetes.Interface,
+	edgenetclientset clientset.Interface,
+	nodeInformer coreinformers.NodeInformer,
+	nodePoolInformer informers.NodePoolInformer,
+	nodeConfigurationInformer informers.NodeConfigurationInformer) *Controller {
+
+	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
+	klog.V(4).InfoS("Creating event broadcaster")
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartStructuredLogging(0)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+
+	controller := &Controller{
+		identity:                 config.NewClusterIdentity(kubeclientset),
+		kubeclientset:            kubeclientset,
+		edgenetclientset:         edgenetclientset,
+		nodesLister:              nodeInformer.Lister(),
+		nodesSynced:              nodeInformer.Informer().HasSynced,
+		nodePoolsLister:          nodePoolInformer.Lister(),
+		nodePoolsSynced:          nodePoolInformer.Informer().HasSynced,
+		nodeConfigurationsLister: nodeConfigurationInformer.Lister(),
+		nodeConfigurationsSynced: nodeConfigurationInformer.Informer().HasSynced,
+		workqueue:                workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "NodeConfigurations"),
+		recorder:                 recorder,
+	}
+
+	klog.V(4).InfoS("Setting up event handlers")
+	// A deleted configuration is queued as well, so that its nodes get their original settings back
+	nodeConfigurationInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueNodeConfiguration,
+		UpdateFunc: func(old, new interface{}) {
+			if reflect.DeepEqual(old.(*corev1alpha.NodeConfiguration).Spec, new.(*corev1alpha.NodeConfiguration).Spec) {
+				return
+			}
+			controller.enqueueNodeConfiguration(new)
+		},
+		DeleteFunc: controller.enqueueNodeConfiguration,
+	})
+	nodePoolInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    controller.enqueueAll,
+		UpdateFunc: func(old, new interface{}) { controller.enqueueAll(new) },
+		DeleteFunc: controller.enqueueAll,
+	})
+	// The nodes report their status every few seconds, only the changes of their pools, of the
+	// settings their kubelet runs with, and of their disk pressure matter here
+	nodeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueAll,
+		UpdateFunc: func(old, new interface{}) {
+			oldNode, newNode := old.(*corev1.Node), new.(*corev1.Node)
+			if reflect.DeepEqual(oldNode.GetLabels(), newNode.GetLabels()) &&
+				oldNode.GetAnnotations()[kubelet.AnnotationApplied] == newNode.GetAnnotations()[kubelet.AnnotationApplied] &&
+				oldNode.GetAnnotations()[kubelet.AnnotationSettings] == newNode.GetAnnotations()[kubelet.AnnotationSettings] &&
+				diskPressure(oldNode) == diskPressure(newNode) {
+				return
+			}
+			controller.enqueueAll(new)
+		},
+	})
+
+	return controller
+}
+
+// Run will set up the event handlers for the types of node configuration, node pool, and node, as
+// well as syncing informer caches and starting workers. It will block until stopCh
+// is closed, at which point it will shutdown the workqueue and wait for
+// workers to finish processing their current work items.
+func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting NodeConfiguration controller")
+
+	klog.V(4).InfoS("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh,
+		c.nodesSynced,
+		c.nodePoolsSynced,
+		c.nodeConfigurationsSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	klog.V(4).InfoS("Starting workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	klog.V(4).InfoS("Started workers")
+	<-stopCh
+	klog.V(4).InfoS("Shutting down workers")
+
+	return nil
+}
+
+// runWorker is a long-running function that will continually call the
+// processNextWorkItem function in order to read and process a message on the
+// workqueue.
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+// processNextWorkItem will read a single work item off the workqueue and
+// attempt to process it, by calling the syncHandler.
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.workqueue.Done(obj)
+		var key string
+		var ok bool
+
+		if key, ok = obj.(string); !ok {
+			c.workqueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			return nil
+		}
+		if err := c.syncHandler(ctx, key); err != nil {
+			c.workqueue.AddRateLimited(key)
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
+		}
+		c.workqueue.Forget(obj)
+		klog.V(4).InfoS("Successfully synced", "key", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+
+	return true
+}
+
+// syncHandler releases the nodes that no configuration takes anymore, then annotates the nodes of
+// the configuration with its settings and records their progress in the Status block of the
+// NodeConfiguration resource
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	owners, err := c.owners()
+	if err != nil {
+		return err
+	}
+	if err := c.release(ctx, owners); err != nil {
+		return err
+	}
+
+	nodeConfiguration, err := c.nodeConfigurationsLister.Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	nodeConfigurationCopy := nodeConfiguration.DeepCopy()
+	if err := c.processNodeConfiguration(ctx, nodeConfigurationCopy, owners); err != nil {
+		return err
+	}
+	c.recorder.Event(nodeConfiguration, corev1.EventTypeNormal, successSynced, messageResourceSynced)
+	return nil
+}
+
+// enqueueNodeConfiguration takes a NodeConfiguration resource and converts it into a name
+// string which is then put onto the work queue. This method should *not* be
+// passed resources of any type other than NodeConfiguration.
+func (c *Controller) enqueueNodeConfiguration(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+// enqueueAll enqueues every node configuration, a change of a node or a node pool may move nodes
+// from a configuration to another
+func (c *Controller) enqueueAll(obj interface{}) {
+	nodeConfigurations, err := c.nodeConfigurationsLister.List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, nodeConfiguration := range nodeConfigurations {
+		c.enqueueNodeConfiguration(nodeConfiguration)
+	}
+}
+
+// processNodeConfiguration annotates the nodes the configuration takes with its settings, the agent
+// on each node restarts the kubelet with them. It reports the nodes that enter disk pressure to the
+// tenants contributing them.
+func (c *Controller) processNodeConfiguration(ctx context.Context, nodeConfigurationCopy *corev1alpha.NodeConfiguration, owners map[string]*corev1alpha.NodeConfiguration) error {
+	status := corev1alpha.NodeConfigurationStatus{DiskPressure: nodeConfigurationCopy.Status.DiskPressure}
+	if err := kubelet.Validate(nodeConfigurationCopy.Spec); err != nil {
+		status.State = failure
+		status.Message = fmt.Sprintf("%s: %v", messageInvalid, err)
+		c.recorder.Event(nodeConfigurationCopy, corev1.EventTypeWarning, failureInvalid, status.Message)
+		return c.updateStatus(ctx, nodeConfigurationCopy, status)
+	}
+	nodes, err := c.nodesOf(nodeConfigurationCopy)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			status.State = failure
+			status.Message = messageNodePool
+			c.recorder.Event(nodeConfigurationCopy, corev1.EventTypeWarning, failureNodePool, messageNodePool)
+			return c.updateStatus(ctx, nodeConfigurationCopy, status)
+		}
+		return err
+	}
+	settings, err := kubelet.Settings(nodeConfigurationCopy.Spec)
+	if err != nil {
+		return err
+	}
+
+	overridden := 0
+	status.DiskPressure = []string{}
+	for _, node := range nodes {
+		if owner := owners[node.GetName()]; owner == nil || owner.GetName() != nodeConfigurationCopy.GetName() {
+			overridden++
+			continue
+		}
+		status.Nodes++
+		if node.GetAnnotations()[kubelet.AnnotationSettings] != settings {
+			if err := c.annotate(ctx, node.GetName(), &settings); err != nil {
+				return err
+			}
+		} else if node.GetAnnotations()[kubelet.AnnotationApplied] == kubelet.Hash(settings) {
+			status.Applied++
+		}
+		if diskPressure(node) {
+			status.DiskPressure = append(status.DiskPressure, node.GetName())
+			if !contains(nodeConfigurationCopy.Status.DiskPressure, node.GetName()) {
+				c.recorder.Eventf(nodeConfigurationCopy, corev1.EventTypeWarning, warningDiskPressure, "Node %s is at disk pressure", node.GetName())
+				c.sendDiskPressureEmail(ctx, node)
+			}
+		}
+	}
+	sort.Strings(status.DiskPressure)
+	if len(status.DiskPressure) == 0 {
+		status.DiskPressure = nil
+	}
+
+	if status.Applied == status.Nodes {
+		status.State = applied
+		status.Message = messageApplied
+	} else {
+		status.State = applying
+		status.Message = messageApplying
+	}
+	if overridden > 0 {
+		status.Message = fmt.Sprintf("%s, %d %s", status.Message, overridden, messageOverridden)
+	}
+	return c.updateStatus(ctx, nodeConfigurationCopy, status)
+}
+
+// nodesOf returns the nodes in the pool of the configuration
+func (c *Controller) nodesOf(nodeConfiguration *corev1alpha.NodeConfiguration) ([]*corev1.Node, error) {
+	nodePool, err := c.nodePoolsLister.Get(nodeConfiguration.Spec.NodePool)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(&nodePool.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	// A pool without any requirement holds no node
+	if selector.Empty() {
+		return []*corev1.Node{}, nil
+	}
+	return c.nodesLister.List(selector)
+}
+
+// owners returns the configuration each node takes, the oldest of the configurations whose pool
+// holds the node. The invalid configurations take no node, so that they do not hide a valid one.
+func (c *Controller) owners() (map[string]*corev1alpha.NodeConfiguration, error) {
+	nodeConfigurations, err := c.nodeConfigurationsLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(nodeConfigurations, func(i, j int) bool {
+		if nodeConfigurations[i].CreationTimestamp.Equal(&nodeConfigurations[j].CreationTimestamp) {
+			return nodeConfigurations[i].GetName() < nodeConfigurations[j].GetName()
+		}
+		return nodeConfigurations[i].CreationTimestamp.Before(&nodeConfigurations[j].CreationTimestamp)
+	})
+	owners := map[string]*corev1alpha.NodeConfiguration{}
+	for _, nodeConfiguration := range nodeConfigurations {
+		if kubelet.Validate(nodeConfiguration.Spec) != nil {
+			continue
+		}
+		nodes, err := c.nodesOf(nodeConfiguration)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, node := range nodes {
+			if _, ok := owners[node.GetName()]; !ok {
+				owners[node.GetName()] = nodeConfiguration
+			}
+		}
+	}
+	return owners, nil
+}
+
+// release removes the settings from the nodes that no configuration takes anymore, the agent puts
+// the original configuration of their kubelet back. The nodes of an invalid configuration keep
+// their settings.
+func (c *Controller) release(ctx context.Context, owners map[string]*corev1alpha.NodeConfiguration) error {
+	nodes, err := c.nodesLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	nodeConfigurations, err := c.nodeConfigurationsLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if _, ok := node.GetAnnotations()[kubelet.AnnotationSettings]; !ok || owners[node.GetName()] != nil {
+			continue
+		}
+		if c.heldByInvalid(node, nodeConfigurations) {
+			continue
+		}
+		klog.InfoS("Releasing the node from the node configurations", "node", node.GetName())
+		if err := c.annotate(ctx, node.GetName(), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// heldByInvalid tells whether the node is in the pool of an invalid configuration
+func (c *Controller) heldByInvalid(node *corev1.Node, nodeConfigurations []*corev1alpha.NodeConfiguration) bool {
+	for _, nodeConfiguration := range nodeConfigurations {
+		if kubelet.Validate(nodeConfiguration.Spec) == nil {
+			continue
+		}
+		if nodes, err := c.nodesOf(nodeConfiguration); err == nil {
+			for _, held := range nodes {
+				if held.GetName() == node.GetName() {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// annotate sets the settings of the kubelet on the node, they are removed if nil
+func (c *Controller) annotate(ctx context.Context, nodeName string, settings *string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]*string{kubelet.AnnotationSettings: settings}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.kubeclientset.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// updateStatus persists the status unless it is unchanged
+func (c *Controller) updateStatus(ctx context.Context, nodeConfigurationCopy *corev1alpha.NodeConfiguration, status corev1alpha.NodeConfigurationStatus) error {
+	if reflect.DeepEqual(nodeConfigurationCopy.Status, status) {
+		return nil
+	}
+	nodeConfigurationCopy.Status = status
+	_, err := c.edgenetclientset.CoreV1alpha().NodeConfigurations().UpdateStatus(ctx, nodeConfigurationCopy, metav1.UpdateOptions{})
+	return err
+}
+
+// diskPressure tells whether the kubelet of the node reports disk pressure
+func diskPressure(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeDiskPressure {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func contains(names []string, name string) bool {
+	for _, value := range names {
+		if value == name {
+			return true
+		}
+	}
+	return false
+}
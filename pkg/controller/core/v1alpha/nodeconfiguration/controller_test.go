@@ -0,0 +1,161 @@
+package nodeconfiguration
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/node/kubelet"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+func newNode(name, pool string, annotations map[string]string, pressure bool) *corev1.Node {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}, Annotations: annotations}}
+	if pool != "" {
+		node.Labels["edge-net.io/nodepool"] = pool
+	}
+	if pressure {
+		node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue,
+			Message: "kubelet has disk pressure"}}
+	}
+	return node
+}
+
+func newNodePool(name string) *corev1alpha.NodePool {
+	return &corev1alpha.NodePool{ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1alpha.NodePoolSpec{Selector: metav1.LabelSelector{MatchLabels: map[string]string{"edge-net.io/nodepool": name}}}}
+}
+
+func newNodeConfiguration(name, pool string, created int) *corev1alpha.NodeConfiguration {
+	high, low := int32(70), int32(50)
+	return &corev1alpha.NodeConfiguration{ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.Unix(int64(created), 0)},
+		Spec: corev1alpha.NodeConfigurationSpec{NodePool: pool,
+			EvictionHard: map[string]string{"nodefs.available": "10%", "imagefs.available": "500Mi"},
+			ImageGC:      &corev1alpha.ImageGC{HighThresholdPercent: &high, LowThresholdPercent: &low}}}
+}
+
+// newController returns a controller whose caches hold the objects
+func newController(t *testing.T, nodes []*corev1.Node, objects ...*corev1alpha.NodeConfiguration) *Controller {
+	kubeclientset := testclient.NewSimpleClientset()
+	for _, node := range nodes {
+		_, err := kubeclientset.CoreV1().Nodes().Create(context.TODO(), node, metav1.CreateOptions{})
+		util.OK(t, err)
+	}
+	tenant := "lip6"
+	edgenetclientset := edgenettestclient.NewSimpleClientset(newNodePool("satellite"), newNodePool("cloud"),
+		&corev1alpha.NodeContribution{ObjectMeta: metav1.ObjectMeta{Name: "paris-1"}, Spec: corev1alpha.NodeContributionSpec{Tenant: &tenant, Host: "192.0.2.1"}},
+		&corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "lip6"}, Spec: corev1alpha.TenantSpec{Contact: corev1alpha.Contact{Email: "john.doe@lip6.fr"}}})
+	for _, nodeConfiguration := range objects {
+		_, err := edgenetclientset.CoreV1alpha().NodeConfigurations().Create(context.TODO(), nodeConfiguration, metav1.CreateOptions{})
+		util.OK(t, err)
+	}
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeclientset, 0)
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, 0)
+	c := NewController(kubeclientset, edgenetclientset,
+		kubeInformerFactory.Core().V1().Nodes(),
+		edgenetInformerFactory.Core().V1alpha().NodePools(),
+		edgenetInformerFactory.Core().V1alpha().NodeConfigurations())
+	c.recorder = record.NewFakeRecorder(100)
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	kubeInformerFactory.Start(stopCh)
+	edgenetInformerFactory.Start(stopCh)
+	util.Assert(t, cache.WaitForCacheSync(stopCh, c.nodesSynced, c.nodePoolsSynced, c.nodeConfigurationsSynced), "caches not synced")
+	return c
+}
+
+func (c *Controller) annotations(t *testing.T, name string) map[string]string {
+	node, err := c.kubeclientset.CoreV1().Nodes().Get(context.TODO(), name, metav1.GetOptions{})
+	util.OK(t, err)
+	return node.GetAnnotations()
+}
+
+func (c *Controller) status(t *testing.T, name string) corev1alpha.NodeConfigurationStatus {
+	nodeConfiguration, err := c.edgenetclientset.CoreV1alpha().NodeConfigurations().Get(context.TODO(), name, metav1.GetOptions{})
+	util.OK(t, err)
+	return nodeConfiguration.Status
+}
+
+func TestApply(t *testing.T) {
+	settings, err := kubelet.Settings(newNodeConfiguration("satellite", "satellite", 1).Spec)
+	util.OK(t, err)
+	nodes := []*corev1.Node{
+		newNode("paris-1.edge-net.io", "satellite", nil, true),
+		newNode("lyon-1.edge-net.io", "satellite", map[string]string{kubelet.AnnotationSettings: settings, kubelet.AnnotationApplied: kubelet.Hash(settings)}, false),
+		newNode("berlin-1.edge-net.io", "cloud", map[string]string{kubelet.AnnotationSettings: settings}, false),
+		newNode("nice-1.edge-net.io", "", map[string]string{kubelet.AnnotationSettings: settings, kubelet.AnnotationApplied: kubelet.Hash(settings)}, false),
+	}
+	c := newController(t, nodes, newNodeConfiguration("satellite", "satellite", 1))
+	util.OK(t, c.syncHandler(context.TODO(), "satellite"))
+
+	util.Equals(t, settings, c.annotations(t, "paris-1.edge-net.io")[kubelet.AnnotationSettings])
+	// The nodes that no configuration takes get their original settings back
+	_, ok := c.annotations(t, "berlin-1.edge-net.io")[kubelet.AnnotationSettings]
+	util.Equals(t, false, ok)
+	_, ok = c.annotations(t, "nice-1.edge-net.io")[kubelet.AnnotationSettings]
+	util.Equals(t, false, ok)
+
+	status := c.status(t, "satellite")
+	util.Equals(t, applying, status.State)
+	util.Equals(t, 2, status.Nodes)
+	util.Equals(t, 1, status.Applied)
+	util.Equals(t, []string{"paris-1.edge-net.io"}, status.DiskPressure)
+}
+
+func TestOverlap(t *testing.T) {
+	nodes := []*corev1.Node{newNode("paris-1.edge-net.io", "satellite", nil, false)}
+	newer := newNodeConfiguration("satellite-strict", "satellite", 2)
+	newer.Spec.EvictionHard["nodefs.available"] = "20%"
+	c := newController(t, nodes, newNodeConfiguration("satellite", "satellite", 1), newer)
+
+	util.OK(t, c.syncHandler(context.TODO(), "satellite-strict"))
+	_, ok := c.annotations(t, "paris-1.edge-net.io")[kubelet.AnnotationSettings]
+	util.Equals(t, false, ok)
+	status := c.status(t, "satellite-strict")
+	util.Equals(t, applied, status.State)
+	util.Equals(t, 0, status.Nodes)
+	util.Equals(t, messageApplied+", 1 "+messageOverridden, status.Message)
+
+	util.OK(t, c.syncHandler(context.TODO(), "satellite"))
+	settings, err := kubelet.Settings(newNodeConfiguration("satellite", "satellite", 1).Spec)
+	util.OK(t, err)
+	util.Equals(t, settings, c.annotations(t, "paris-1.edge-net.io")[kubelet.AnnotationSettings])
+}
+
+func TestFailure(t *testing.T) {
+	settings := `{"imageGCHighThresholdPercent":80}`
+	nodes := []*corev1.Node{newNode("paris-1.edge-net.io", "satellite", map[string]string{kubelet.AnnotationSettings: settings}, false)}
+	invalid := newNodeConfiguration("satellite", "satellite", 1)
+	invalid.Spec.EvictionHard["disk.available"] = "10%"
+	orphan := newNodeConfiguration("edge", "edge", 2)
+	c := newController(t, nodes, invalid, orphan)
+
+	util.OK(t, c.syncHandler(context.TODO(), "satellite"))
+	status := c.status(t, "satellite")
+	util.Equals(t, failure, status.State)
+	util.Equals(t, messageInvalid+": unknown eviction signal disk.available", status.Message)
+	// The nodes of an invalid configuration keep their settings
+	util.Equals(t, settings, c.annotations(t, "paris-1.edge-net.io")[kubelet.AnnotationSettings])
+
+	util.OK(t, c.syncHandler(context.TODO(), "edge"))
+	util.Equals(t, messageNodePool, c.status(t, "edge").Message)
+}
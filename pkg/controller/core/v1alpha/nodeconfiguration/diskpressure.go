@@ -0,0 +1,68 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeconfiguration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/EdgeNet-project/edgenet/pkg/mailer"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// nodeDomain is the domain of the contributed nodes, a node is named after its node contribution in it
+const nodeDomain = ".edge-net.io"
+
+// sendDiskPressureEmail notifies the contact of the tenant contributing the node that its disk is
+// running out, the cluster administrators are notified instead if the node is not contributed by a
+// tenant
+func (c *Controller) sendDiskPressureEmail(ctx context.Context, node *corev1.Node) {
+	email := new(mailer.Content)
+	if clusterUID, err := c.identity.UID(ctx); err == nil {
+		email.Cluster = clusterUID
+	}
+	email.NodeContribution = new(mailer.NodeContribution)
+	email.NodeContribution.Name = strings.TrimSuffix(node.GetName(), nodeDomain)
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeExternalIP || (address.Type == corev1.NodeInternalIP && email.NodeContribution.Host == "") {
+			email.NodeContribution.Host = address.Address
+		}
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeDiskPressure {
+			email.NodeContribution.Reason = condition.Message
+		}
+	}
+	if nodeContribution, err := c.edgenetclientset.CoreV1alpha().NodeContributions().Get(ctx, email.NodeContribution.Name, metav1.GetOptions{}); err == nil {
+		email.NodeContribution.Host = nodeContribution.Spec.Host
+		if nodeContribution.Spec.Tenant != nil {
+			if contributorTenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(ctx, *nodeContribution.Spec.Tenant, metav1.GetOptions{}); err == nil {
+				email.User = contributorTenant.Spec.Contact.Email
+				email.FirstName = contributorTenant.Spec.Contact.FirstName
+				email.LastName = contributorTenant.Spec.Contact.LastName
+				email.Recipient = []string{contributorTenant.Spec.Contact.Email}
+			}
+		}
+	}
+	email.Subject = fmt.Sprintf("[EdgeNet] Node %s is at disk pressure", email.NodeContribution.Name)
+	klog.InfoS("Reporting the node at disk pressure", "node", node.GetName(), "recipient", email.Recipient)
+	email.Send("node-disk-pressure")
+}
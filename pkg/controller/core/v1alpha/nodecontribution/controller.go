@@ -24,19 +24,23 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
 	"time"
 
 	namecheap "github.com/billputer/go-namecheap"
 	"golang.org/x/crypto/ssh"
 
 	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/config"
 	"github.com/EdgeNet-project/edgenet/pkg/controller/core/v1alpha/tenant"
 	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
 	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
 	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
 	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/mailer"
 	"github.com/EdgeNet-project/edgenet/pkg/node"
 	"github.com/EdgeNet-project/edgenet/pkg/remoteip"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -52,7 +56,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 const controllerAgentName = "nodecontribution-controller"
@@ -65,8 +69,18 @@ const (
 	messageSetupPhase     = "Setup process commenced"
 	messageDoneDNS        = "DNS record configured"
 	messageDoneSSH        = "SSH connection established"
+	messageDonePreflight  = "Preflight checks passed"
 	messageDoneKubeadm    = "Bootstrap token created and join command has been invoked"
 	messageDonePatch      = "Node scheduling updated"
+	messageDoneJoinToken  = "Join token issued"
+	messageDoneLink       = "Node joined with the token"
+	maintenanceProcedure  = "Maintenance"
+	messageCordoned       = "Node cordoned for maintenance"
+	messageDrained        = "Node drained"
+	messageUncordoned     = "Maintenance cleared"
+	upgradeProcedure      = "Upgrade"
+	messageUpgradeStarted = "Upgrade commenced"
+	messageUpgraded       = "Kubelet upgraded"
 	messageTimeout        = "Procedure terminated due to timeout"
 	messageEnd            = "Procedure finished"
 	inqueue               = "In Queue"
@@ -74,6 +88,12 @@ const (
 	failure               = "Failure"
 	incomplete            = "Halting"
 	success               = "Successful"
+	cordoned              = "Cordoned"
+	draining              = "Draining"
+	drained               = "Drained"
+	upgradeScheduled      = "Scheduled"
+	upgrading             = "Upgrading"
+	upgraded              = "Upgraded"
 	create                = "create"
 	update                = "update"
 	delete                = "delete"
@@ -86,6 +106,7 @@ const (
 var statusDict = map[string]string{
 	"successful":              "Node is up and running",
 	"failure":                 "Node is unready",
+	"unresponsive":            "Node is not ready, it is reported down if it does not recover",
 	"in-progress":             "Node setup in progress",
 	"in-queue":                "Node contribution is in queue to be processed",
 	"configuration-failure":   "Warning: Scheduling configuration failed",
@@ -93,10 +114,24 @@ var statusDict = map[string]string{
 	"status-update":           "Error: Object update failure",
 	"invalid-host":            "Error: Host field must be an IP Address",
 	"ssh-failure":             "Error: SSH handshake failed",
+	"preflight-failure":       "Error: Node failed the preflight checks",
 	"join-failure":            "Error: Node cannot join the cluster",
+	"awaiting-join":           "Node awaits the contributor to run the bootstrap script with the join token",
+	"token-failure":           "Error: Join token cannot be issued",
+	"link-failure":            "Warning: Node joined with the token cannot be linked to the node contribution",
 	"timeout":                 "Error: Node contribution failed due to timeout",
+	"cordoned":                "Node is cordoned, its pods are to be evicted",
+	"draining":                "Pods are waiting for their eviction, either blocked by a disruption budget or terminating",
+	"drained":                 "Node is drained and ready for maintenance",
+	"drain-failure":           "Error: Pods cannot be evicted from the node",
+	"upgrading":               "Node is being upgraded",
+	"upgraded":                "Node upgraded",
+	"upgrade-failure":         "Error: Node upgrade failed",
 }
 
+// The drain of a node under maintenance is checked again after drainInterval until no pod is left
+var drainInterval = 30 * time.Second
+
 // Controller is the controller implementation for Node Contribution resources
 type Controller struct {
 	// kubeclientset is a standard kubernetes clientset
@@ -118,8 +153,14 @@ type Controller struct {
 	workqueue workqueue.RateLimitingInterface
 	// recorder is an event recorder for recording Event resources to the
 	// Kubernetes API.
-	recorder  record.EventRecorder
+	recorder record.EventRecorder
+	// identity is the identity of the cluster that the objects are labeled with
+	identity  *config.ClusterIdentity
 	publicKey ssh.Signer
+
+	// digest holds the emails of the nodes down until they are sent together, keyed by recipients
+	digestMutex sync.Mutex
+	digest      map[string]*mailer.Content
 }
 
 // NewController returns a new controller
@@ -130,7 +171,7 @@ func NewController(
 	nodecontributionInformer informers.NodeContributionInformer) *Controller {
 
 	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
-	klog.V(4).Info("Creating event broadcaster")
+	klog.V(4).InfoS("Creating event broadcaster")
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartStructuredLogging(0)
 	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
@@ -139,17 +180,18 @@ func NewController(
 	// Get the SSH Private Key of the control plane node
 	key, err := ioutil.ReadFile("../../.ssh/id_rsa")
 	if err != nil {
-		klog.V(4).Info(err.Error())
+		klog.ErrorS(err, "Couldn't read the SSH private key")
 		panic(err.Error())
 	}
 
 	publicKey, err := ssh.ParsePrivateKey(key)
 	if err != nil {
-		klog.V(4).Info(err.Error())
+		klog.ErrorS(err, "Couldn't parse the SSH private key")
 		panic(err.Error())
 	}
 
 	controller := &Controller{
+		identity:                config.NewClusterIdentity(kubeclientset),
 		kubeclientset:           kubeclientset,
 		edgenetclientset:        edgenetclientset,
 		nodesLister:             nodeInformer.Lister(),
@@ -159,16 +201,19 @@ func NewController(
 		workqueue:               workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "NodeContributions"),
 		recorder:                recorder,
 		publicKey:               publicKey,
+		digest:                  map[string]*mailer.Content{},
 	}
 
-	klog.V(4).Infoln("Setting up event handlers")
+	klog.V(4).InfoS("Setting up event handlers")
 	// Set up an event handler for when Node Contribution resources change
 	nodecontributionInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: controller.enqueueNodeContribution,
 		UpdateFunc: func(old, new interface{}) {
 			newNodeContribution := new.(*corev1alpha.NodeContribution)
 			oldNodeContribution := old.(*corev1alpha.NodeContribution)
-			if reflect.DeepEqual(newNodeContribution.Spec, oldNodeContribution.Spec) && (newNodeContribution.Status.State != inqueue) {
+			// An upgrade scheduled by a cluster upgrade plan only shows up in the status
+			scheduled := newNodeContribution.Status.Upgrade != nil && newNodeContribution.Status.Upgrade.State == upgradeScheduled
+			if reflect.DeepEqual(newNodeContribution.Spec, oldNodeContribution.Spec) && (newNodeContribution.Status.State != inqueue) && !scheduled {
 				return
 			}
 			controller.enqueueNodeContribution(new)
@@ -200,24 +245,33 @@ func NewController(
 func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
 
-	klog.V(4).Infoln("Starting Node Contribution controller")
+	klog.V(4).InfoS("Starting Node Contribution controller")
 
-	klog.V(4).Infoln("Waiting for informer caches to sync")
+	klog.V(4).InfoS("Waiting for informer caches to sync")
 	if ok := cache.WaitForCacheSync(stopCh,
 		c.nodecontributionsSynced,
 		c.nodesSynced); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
-	klog.V(4).Infoln("Starting workers")
+	klog.V(4).InfoS("Starting workers")
 	for i := 0; i < threadiness; i++ {
-		go wait.Until(c.runWorker, time.Second, stopCh)
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+	if DigestInterval > 0 {
+		go wait.UntilWithContext(ctx, c.flushDigest, DigestInterval)
 	}
 
-	klog.V(4).Infoln("Started workers")
+	klog.V(4).InfoS("Started workers")
 	<-stopCh
-	klog.V(4).Infoln("Shutting down workers")
+	klog.V(4).InfoS("Shutting down workers")
+	// The nodes down since the last digest are not to be forgotten, the context of the workers is
+	// cancelled by now
+	flushCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	c.flushDigest(flushCtx)
 
 	return nil
 }
@@ -225,14 +279,14 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 // runWorker is a long-running function that will continually call the
 // processNextWorkItem function in order to read and process a message on the
 // workqueue.
-func (c *Controller) runWorker() {
-	for c.processNextWorkItem() {
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
 	}
 }
 
 // processNextWorkItem will read a single work item off the workqueue and
 // attempt to process it, by calling the syncHandler.
-func (c *Controller) processNextWorkItem() bool {
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
 	obj, shutdown := c.workqueue.Get()
 
 	if shutdown {
@@ -249,12 +303,12 @@ func (c *Controller) processNextWorkItem() bool {
 			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
 			return nil
 		}
-		if err := c.syncHandler(key); err != nil {
+		if err := c.syncHandler(ctx, key); err != nil {
 			c.workqueue.AddRateLimited(key)
-			return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
 		}
 		c.workqueue.Forget(obj)
-		klog.V(4).Infof("Successfully synced '%s'", key)
+		klog.V(4).InfoS("Successfully synced", "key", key)
 		return nil
 	}(obj)
 
@@ -269,7 +323,7 @@ func (c *Controller) processNextWorkItem() bool {
 // syncHandler compares the actual state with the desired, and attempts to
 // converge the two. It then updates the Status block of the Node Contribution
 // resource with the current status of the resource.
-func (c *Controller) syncHandler(key string) error {
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
 	_, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
 		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
@@ -290,16 +344,16 @@ func (c *Controller) syncHandler(key string) error {
 		nodecontributionCopy := nodecontribution.DeepCopy()
 		nodecontributionCopy.Status.State = inqueue
 		nodecontributionCopy.Status.Message = append(nodecontributionCopy.Status.Message, statusDict["in-queue"])
-		c.edgenetclientset.CoreV1alpha().NodeContributions().UpdateStatus(context.TODO(), nodecontributionCopy, metav1.UpdateOptions{})
+		c.edgenetclientset.CoreV1alpha().NodeContributions().UpdateStatus(ctx, nodecontributionCopy, metav1.UpdateOptions{})
 		return nil
 	}
 
-	go c.init(nodecontribution)
+	go c.init(ctx, nodecontribution)
 	c.recorder.Event(nodecontribution, corev1.EventTypeNormal, successSynced, messageResourceSynced)
 	return nil
 }
 
-func (c *Controller) init(nodecontribution *corev1alpha.NodeContribution) {
+func (c *Controller) init(ctx context.Context, nodecontribution *corev1alpha.NodeContribution) {
 	nodecontributionCopy := nodecontribution.DeepCopy()
 	nodecontributionCopy.Status.Message = []string{}
 	nodeName := fmt.Sprintf("%s.edge-net.io", nodecontributionCopy.GetName())
@@ -308,7 +362,7 @@ func (c *Controller) init(nodecontribution *corev1alpha.NodeContribution) {
 	if recordType == "" {
 		nodecontributionCopy.Status.State = failure
 		nodecontributionCopy.Status.Message = append(nodecontributionCopy.Status.Message, statusDict["invalid-host"])
-		c.edgenetclientset.CoreV1alpha().NodeContributions().UpdateStatus(context.TODO(), nodecontributionCopy, metav1.UpdateOptions{})
+		c.edgenetclientset.CoreV1alpha().NodeContributions().UpdateStatus(ctx, nodecontributionCopy, metav1.UpdateOptions{})
 		return
 	}
 	// Set the client config according to the node contribution,
@@ -323,26 +377,143 @@ func (c *Controller) init(nodecontribution *corev1alpha.NodeContribution) {
 	contributedNode, err := c.nodesLister.Get(nodeName)
 
 	if err == nil {
-		if contributedNode.Spec.Unschedulable != !nodecontributionCopy.Spec.Enabled {
-			err := node.SetNodeScheduling(nodeName, !nodecontributionCopy.Spec.Enabled)
+		// The node that joined with a token is taken over once, as the SSH setup does at its end
+		if nodecontributionCopy.Spec.Mode == modeToken && nodecontributionCopy.Status.Join != nil && nodecontributionCopy.Status.Join.Node == "" {
+			if err := c.linkJoinedNode(ctx, nodeName, nodecontributionCopy); err != nil {
+				klog.ErrorS(err, "Couldn't link the node joined with the token", "nodeName", nodeName)
+				nodecontributionCopy.Status.State = incomplete
+				nodecontributionCopy.Status.Message = append(nodecontributionCopy.Status.Message, statusDict["link-failure"])
+			} else {
+				c.recorder.Event(nodecontributionCopy, corev1.EventTypeNormal, setupProcedure, messageDoneLink)
+			}
+		}
+		// A node under maintenance stays cordoned regardless of the scheduling option
+		unschedulable := !nodecontributionCopy.Spec.Enabled || nodecontributionCopy.Spec.Maintenance
+		cordonFailed := false
+		if contributedNode.Spec.Unschedulable != unschedulable {
+			err := node.SetNodeScheduling(ctx, nodeName, unschedulable)
 			if err != nil {
+				cordonFailed = true
 				nodecontributionCopy.Status.State = incomplete
 				nodecontributionCopy.Status.Message = append(nodecontributionCopy.Status.Message, statusDict["configuration-failure"])
 			} else {
 				c.recorder.Event(nodecontributionCopy, corev1.EventTypeNormal, setupProcedure, messageDonePatch)
 			}
 		}
+		if nodecontributionCopy.Spec.Maintenance && !cordonFailed {
+			c.maintain(ctx, nodeName, nodecontributionCopy)
+		} else if !nodecontributionCopy.Spec.Maintenance && nodecontributionCopy.Status.Maintenance != nil && !cordonFailed {
+			nodecontributionCopy.Status.Maintenance = nil
+			c.recorder.Event(nodecontributionCopy, corev1.EventTypeNormal, maintenanceProcedure, messageUncordoned)
+		}
+		if nodecontributionCopy.Status.Upgrade != nil && nodecontributionCopy.Status.Upgrade.State == upgradeScheduled {
+			c.upgrade(ctx, addr, nodeName, config, nodecontributionCopy)
+		}
 		if node.GetConditionReadyStatus(contributedNode.DeepCopy()) == trueStr {
 			nodecontributionCopy.Status.State = success
 			nodecontributionCopy.Status.Message = append(nodecontributionCopy.Status.Message, statusDict["successful"])
+			nodecontributionCopy.Status.NotReadySince = nil
+		} else if nodecontribution.Status.State == success && !c.checkHealth(nodecontributionCopy, contributedNode) {
+			nodecontributionCopy.Status.Message = append(nodecontributionCopy.Status.Message, statusDict["unresponsive"])
 		} else {
+			if nodecontribution.Status.State == success {
+				c.sendNodeDownEmail(ctx, nodecontributionCopy, contributedNode)
+			}
 			nodecontributionCopy.Status.State = failure
 			nodecontributionCopy.Status.Message = append(nodecontributionCopy.Status.Message, statusDict["failure"])
 		}
-		c.edgenetclientset.CoreV1alpha().NodeContributions().UpdateStatus(context.TODO(), nodecontributionCopy, metav1.UpdateOptions{})
+		c.edgenetclientset.CoreV1alpha().NodeContributions().UpdateStatus(ctx, nodecontributionCopy, metav1.UpdateOptions{})
+	} else if nodecontributionCopy.Spec.Mode == modeToken {
+		// EdgeNet does not log in to the node, the contributor runs the bootstrap script
+		c.awaitJoin(ctx, nodeName, recordType, nodecontributionCopy)
 	} else {
 		c.balanceMultiThreading(5)
-		c.setup(addr, nodeName, recordType, config, nodecontributionCopy)
+		c.setup(ctx, addr, nodeName, recordType, config, nodecontributionCopy)
+	}
+}
+
+// maintain drains the cordoned node and records the progress in the status. The node contribution
+// is queued again until the last pod leaves the node, as the disruption budgets may hold evictions back.
+func (c *Controller) maintain(ctx context.Context, nodeName string, nodecontributionCopy *corev1alpha.NodeContribution) {
+	if nodecontributionCopy.Status.Maintenance == nil {
+		nodecontributionCopy.Status.Maintenance = &corev1alpha.MaintenanceStatus{State: cordoned, Message: statusDict["cordoned"], Started: metav1.Now()}
+		c.recorder.Event(nodecontributionCopy, corev1.EventTypeNormal, maintenanceProcedure, messageCordoned)
+	}
+	maintenance := nodecontributionCopy.Status.Maintenance
+	evicted, remaining, err := node.Drain(ctx, nodeName)
+	maintenance.Evicted += evicted
+	maintenance.Remaining = remaining
+	if err != nil {
+		klog.ErrorS(err, "Couldn't drain the node", "nodeName", nodeName)
+		maintenance.State = failure
+		maintenance.Message = statusDict["drain-failure"]
+		c.recorder.Event(nodecontributionCopy, corev1.EventTypeWarning, maintenanceProcedure, err.Error())
+	} else if remaining > 0 {
+		maintenance.State = draining
+		maintenance.Message = statusDict["draining"]
+	} else {
+		if maintenance.State != drained {
+			c.recorder.Event(nodecontributionCopy, corev1.EventTypeNormal, maintenanceProcedure, messageDrained)
+		}
+		maintenance.State = drained
+		maintenance.Message = statusDict["drained"]
+		return
+	}
+	c.workqueue.AddAfter(nodecontributionCopy.GetName(), drainInterval)
+}
+
+// upgrade cordons and drains the node, then upgrades kubelet and containerd over SSH to the versions
+// set by the cluster upgrade plan. The node gets its scheduling option back whatever the outcome.
+func (c *Controller) upgrade(ctx context.Context, addr, nodeName string, config *ssh.ClientConfig, nodecontributionCopy *corev1alpha.NodeContribution) {
+	upgrade := nodecontributionCopy.Status.Upgrade
+	started := metav1.Now()
+	upgrade.State = upgrading
+	upgrade.Message = statusDict["upgrading"]
+	upgrade.Started = &started
+	upgrade.Finished = nil
+	// The plan follows the progress through the status of the node contribution
+	if nodecontributionUpdated, err := c.edgenetclientset.CoreV1alpha().NodeContributions().UpdateStatus(ctx, nodecontributionCopy, metav1.UpdateOptions{}); err == nil {
+		nodecontributionCopy.SetResourceVersion(nodecontributionUpdated.GetResourceVersion())
+	}
+	c.recorder.Event(nodecontributionCopy, corev1.EventTypeNormal, upgradeProcedure, messageUpgradeStarted)
+
+	err := func() error {
+		commands, err := node.UpgradeCommands(upgrade.KubeletVersion, upgrade.ContainerdVersion)
+		if err != nil {
+			return err
+		}
+		if err := node.SetNodeScheduling(ctx, nodeName, true); err != nil {
+			return err
+		}
+		// The pods held back by their disruption budgets get killed along with the kubelet restart
+		if _, remaining, err := node.Drain(ctx, nodeName); err != nil {
+			return err
+		} else if remaining > 0 {
+			klog.V(4).InfoS("Pods left before the upgrade", "nodeName", nodeName, "pods", remaining)
+		}
+		conn, err := ssh.Dial("tcp", addr, config)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		return runCommands(conn, commands)
+	}()
+
+	finished := metav1.Now()
+	upgrade.Finished = &finished
+	if err != nil {
+		klog.ErrorS(err, "Couldn't upgrade the node", "nodeName", nodeName)
+		upgrade.State = failure
+		upgrade.Message = fmt.Sprintf("%s: %s", statusDict["upgrade-failure"], err)
+		c.recorder.Event(nodecontributionCopy, corev1.EventTypeWarning, upgradeProcedure, err.Error())
+	} else {
+		upgrade.State = upgraded
+		upgrade.Message = statusDict["upgraded"]
+		c.recorder.Event(nodecontributionCopy, corev1.EventTypeNormal, upgradeProcedure, messageUpgraded)
+	}
+	if err := node.SetNodeScheduling(ctx, nodeName, !nodecontributionCopy.Spec.Enabled || nodecontributionCopy.Spec.Maintenance); err != nil {
+		nodecontributionCopy.Status.State = incomplete
+		nodecontributionCopy.Status.Message = append(nodecontributionCopy.Status.Message, statusDict["configuration-failure"])
 	}
 }
 
@@ -378,9 +549,9 @@ func (c *Controller) handleObject(obj interface{}) {
 			utilruntime.HandleError(fmt.Errorf("error decoding object tombstone, invalid type"))
 			return
 		}
-		klog.V(4).Infof("Recovered deleted object '%s' from tombstone", object.GetName())
+		klog.V(4).InfoS("Recovered deleted object from tombstone", "object", klog.KObj(object))
 	}
-	klog.V(4).Infof("Processing object: %s", object.GetName())
+	klog.V(4).InfoS("Processing object", "object", klog.KObj(object))
 	if ownerRef := metav1.GetControllerOf(object); ownerRef != nil {
 		if ownerRef.Kind != "NodeContribution" {
 			return
@@ -388,7 +559,7 @@ func (c *Controller) handleObject(obj interface{}) {
 
 		nodecontribution, err := c.nodecontributionsLister.Get(ownerRef.Name)
 		if err != nil {
-			klog.V(4).Infof("ignoring orphaned object '%s' of nodecontribution '%s'", object.GetSelfLink(), ownerRef.Name)
+			klog.V(4).InfoS("Ignoring orphaned object", "object", klog.KObj(object), "nodeContribution", ownerRef.Name)
 			return
 		}
 
@@ -398,6 +569,12 @@ func (c *Controller) handleObject(obj interface{}) {
 		}
 		return
 	}
+	// The node that joined with a token has no owner until its node contribution takes it over
+	if joined, ok := object.(*corev1.Node); ok {
+		if nodecontribution, ok := c.joiningContribution(joined.GetName()); ok {
+			c.enqueueNodeContribution(nodecontribution)
+		}
+	}
 }
 
 // balanceMultiThreading is a simple algorithm to limit concurrent threads
@@ -438,25 +615,26 @@ check:
 }
 
 // setup registers DNS record and makes the node join into the cluster
-func (c *Controller) setup(addr, nodeName, recordType string, config *ssh.ClientConfig, nodecontributionCopy *corev1alpha.NodeContribution) error {
+func (c *Controller) setup(ctx context.Context, addr, nodeName, recordType string, config *ssh.ClientConfig, nodecontributionCopy *corev1alpha.NodeContribution) error {
 	c.recorder.Event(nodecontributionCopy, corev1.EventTypeNormal, setupProcedure, messageSetupPhase)
 	// Steps in the procedure
 	endProcedure := make(chan bool, 1)
 	dnsConfiguration := make(chan bool, 1)
 	establishConnection := make(chan bool, 1)
+	preflight := make(chan bool, 1)
 	kubeadm := make(chan bool, 1)
 	nodePatch := make(chan bool, 1)
 
 	// Set the status as in progress
 	nodecontributionCopy.Status.State = inprogress
 	nodecontributionCopy.Status.Message = append(nodecontributionCopy.Status.Message, statusDict["in-progress"])
-	nodecontributionUpdated, err := c.edgenetclientset.CoreV1alpha().NodeContributions().UpdateStatus(context.TODO(), nodecontributionCopy, metav1.UpdateOptions{})
+	nodecontributionUpdated, err := c.edgenetclientset.CoreV1alpha().NodeContributions().UpdateStatus(ctx, nodecontributionCopy, metav1.UpdateOptions{})
 
 	defer func() {
 		if !reflect.DeepEqual(nodecontributionCopy.Status, nodecontributionUpdated.Status) {
-			if _, err := c.edgenetclientset.CoreV1alpha().NodeContributions().UpdateStatus(context.TODO(), nodecontributionUpdated, metav1.UpdateOptions{}); err != nil {
+			if _, err := c.edgenetclientset.CoreV1alpha().NodeContributions().UpdateStatus(ctx, nodecontributionUpdated, metav1.UpdateOptions{}); err != nil {
 				// TO-DO: Provide more information on error
-				klog.V(4).Info(err)
+				klog.ErrorS(err, "Couldn't update the status of the node contribution", "nodeContribution", klog.KObj(nodecontributionUpdated))
 			}
 		}
 	}()
@@ -471,7 +649,7 @@ nodeSetupLoop:
 	for {
 		select {
 		case <-dnsConfiguration:
-			klog.V(4).Infof("DNS configuration started: %s", nodeName)
+			klog.V(4).InfoS("DNS configuration started", "nodeName", nodeName)
 			// Use Namecheap API for registration
 			hostRecord := namecheap.DomainDNSHost{
 				Name:    strings.TrimSuffix(nodeName, ".edge-net.io"),
@@ -485,17 +663,17 @@ nodeSetupLoop:
 				hostnameError := fmt.Sprintf("Warning: Hostname %s or address %s couldn't added", hostRecord.Name, hostRecord.Address)
 				nodecontributionUpdated.Status.State = incomplete
 				nodecontributionUpdated.Status.Message = append(nodecontributionUpdated.Status.Message, hostnameError)
-				klog.V(4).Info(hostnameError)
+				klog.InfoS("Couldn't add the DNS record", "hostname", hostRecord.Name, "address", hostRecord.Address)
 			} else {
 				c.recorder.Event(nodecontributionCopy, corev1.EventTypeNormal, setupProcedure, messageDoneDNS)
 			}
 			establishConnection <- true
 		case <-establishConnection:
-			klog.V(4).Infof("Establish SSH connection: %s", nodeName)
+			klog.V(4).InfoS("Establish SSH connection", "nodeName", nodeName)
 			go func() {
 				conn, err = ssh.Dial("tcp", addr, config)
 				if err != nil && connCounter < 3 {
-					klog.V(4).Info(err)
+					klog.ErrorS(err, "Couldn't establish the SSH connection", "nodeName", nodeName)
 					// Wait one minute to try establishing a connection again
 					time.Sleep(1 * time.Minute)
 					establishConnection <- true
@@ -504,15 +682,33 @@ nodeSetupLoop:
 				} else if (conn == nil) || (err != nil && connCounter >= 3) {
 					nodecontributionUpdated.Status.State = failure
 					nodecontributionUpdated.Status.Message = append(nodecontributionUpdated.Status.Message, statusDict["ssh-failure"])
-					klog.V(4).Info(err)
+					klog.ErrorS(err, "Couldn't establish the SSH connection", "nodeName", nodeName)
 					endProcedure <- true
 					return
 				}
 				c.recorder.Event(nodecontributionCopy, corev1.EventTypeNormal, setupProcedure, messageDoneSSH)
+				preflight <- true
+			}()
+		case <-preflight:
+			klog.V(4).InfoS("Run the preflight checks", "nodeName", nodeName)
+			go func() {
+				// The node does not join unless it can run the workloads, the contributor gets what to fix
+				status := runPreflight(sshRunner(conn), nodecontributionUpdated.Spec.Host)
+				nodecontributionUpdated.Status.Preflight = status
+				if !status.Passed {
+					conn.Close()
+					nodecontributionUpdated.Status.State = failure
+					nodecontributionUpdated.Status.Message = append(nodecontributionUpdated.Status.Message,
+						fmt.Sprintf("%s: %s", statusDict["preflight-failure"], strings.Join(preflightReasons(status), "; ")))
+					klog.InfoS("The node failed the preflight checks", "nodeName", nodeName, "reasons", preflightReasons(status))
+					endProcedure <- true
+					return
+				}
+				c.recorder.Event(nodecontributionCopy, corev1.EventTypeNormal, setupProcedure, messageDonePreflight)
 				kubeadm <- true
 			}()
 		case <-kubeadm:
-			klog.V(4).Infof("Create a token and run kubadm join: %s", nodeName)
+			klog.V(4).InfoS("Create a token and run kubadm join", "nodeName", nodeName)
 			// To prevent hanging forever during establishing a connection
 			go func() {
 				defer func() {
@@ -520,11 +716,11 @@ nodeSetupLoop:
 						conn.Close()
 					}
 				}()
-				err := c.join(conn, nodeName)
+				err := c.join(ctx, conn, nodeName)
 				if err != nil {
 					nodecontributionUpdated.Status.State = failure
 					nodecontributionUpdated.Status.Message = append(nodecontributionUpdated.Status.Message, statusDict["join-failure"])
-					klog.V(4).Info(err)
+					klog.ErrorS(err, "Couldn't join the node", "nodeName", nodeName)
 					endProcedure <- true
 					return
 				}
@@ -535,9 +731,9 @@ nodeSetupLoop:
 				}
 			}()
 		case <-nodePatch:
-			klog.V(4).Infof("Patch scheduling option: %s", nodeName)
+			klog.V(4).InfoS("Patch scheduling option", "nodeName", nodeName)
 			// Set the node as schedulable or unschedulable according to the node contribution
-			err := node.SetNodeScheduling(nodeName, !nodecontributionUpdated.Spec.Enabled)
+			err := node.SetNodeScheduling(ctx, nodeName, !nodecontributionUpdated.Spec.Enabled)
 			if err != nil {
 				nodecontributionUpdated.Status.State = incomplete
 				nodecontributionUpdated.Status.Message = append(nodecontributionUpdated.Status.Message, statusDict["configuration-failure"])
@@ -546,65 +742,96 @@ nodeSetupLoop:
 			}
 			ownerReferences := SetAsOwnerReference(nodecontributionUpdated)
 			if nodecontributionUpdated.Spec.Tenant != nil {
-				contributorTenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), *nodecontributionUpdated.Spec.Tenant, metav1.GetOptions{})
+				contributorTenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(ctx, *nodecontributionUpdated.Spec.Tenant, metav1.GetOptions{})
 				if err == nil {
 					ownerReferences = append(ownerReferences, tenant.SetAsOwnerReference(contributorTenant)...)
 				}
 			}
-			err = node.SetOwnerReferences(nodeName, ownerReferences)
+			err = node.SetOwnerReferences(ctx, nodeName, ownerReferences)
 			if err != nil {
 				nodecontributionUpdated.Status.State = incomplete
 				nodecontributionUpdated.Status.Message = append(nodecontributionUpdated.Status.Message, statusDict["owner-reference-failure"])
 			}
 			endProcedure <- true
 		case <-endProcedure:
-			klog.V(4).Infof("Procedure completed: %s", nodeName)
+			klog.V(4).InfoS("Procedure completed", "nodeName", nodeName)
 			c.recorder.Event(nodecontributionCopy, corev1.EventTypeNormal, setupProcedure, messageEnd)
 			break nodeSetupLoop
 		case <-time.After(5 * time.Minute):
-			klog.V(4).Infof("Timeout: %s", nodeName)
+			klog.V(4).InfoS("Timeout", "nodeName", nodeName)
 			c.recorder.Event(nodecontributionCopy, corev1.EventTypeWarning, setupProcedure, messageTimeout)
 			// Terminate the procedure after 5 minutes
 			nodecontributionUpdated.Status.State = failure
 			nodecontributionUpdated.Status.Message = append(nodecontributionUpdated.Status.Message, statusDict["timeout"])
-			klog.V(4).Info(err)
 			break nodeSetupLoop
 		}
 	}
 	return err
 }
 
-// join creates a token and runs kubeadm join command
-func (c *Controller) join(conn *ssh.Client, nodeName string) error {
-	commands := []string{
-		"sudo su",
+// join creates a token and runs kubeadm join command, the nodes contributed without Kubernetes
+// get the packages of their platform in the version of the cluster first
+func (c *Controller) join(ctx context.Context, conn *ssh.Client, nodeName string) error {
+	commands := []string{"sudo su"}
+	run := sshRunner(conn)
+	if _, err := run("command -v kubeadm"); err != nil {
+		install, err := installCommands(ctx, run)
+		if err != nil {
+			return err
+		}
+		commands = append(commands, install...)
+	}
+	commands = append(commands,
 		"kubeadm reset -f",
-		node.CreateJoinToken("30m", nodeName),
+		node.CreateJoinToken(ctx, "30m", nodeName))
+	return runCommands(conn, commands)
+}
+
+// installCommands detects the platform of the node and returns the commands installing the
+// container runtime and the kubelet of the cluster version on it
+func installCommands(ctx context.Context, run commandRunner) ([]string, error) {
+	machine, err := run(node.MachineCommand)
+	if err != nil {
+		return nil, err
 	}
+	osRelease, err := run(node.OSReleaseCommand)
+	if err != nil {
+		return nil, err
+	}
+	platform, err := node.DetectPlatform(machine, osRelease)
+	if err != nil {
+		return nil, err
+	}
+	klog.V(4).InfoS("Installing the node", "architecture", platform.Architecture, "flavor", platform.Flavor)
+	return node.InstallCommands(platform, node.GetKubeletVersion(ctx))
+}
+
+// runCommands runs the commands sequentially in a shell on the node
+func runCommands(conn *ssh.Client, commands []string) error {
 	sess, err := startSession(conn)
 	if err != nil {
-		klog.V(4).Info(err)
+		klog.ErrorS(err, "Couldn't start the SSH session")
 		return err
 	}
 	defer sess.Close()
 	// StdinPipe for commands
 	stdin, err := sess.StdinPipe()
 	if err != nil {
-		klog.V(4).Info(err)
+		klog.ErrorS(err, "Couldn't open the standard input of the SSH session")
 		return err
 	}
 	//sess.Stdout = os.Stdout
 	sess.Stderr = os.Stderr
 	sess, err = startShell(sess)
 	if err != nil {
-		klog.V(4).Info(err)
+		klog.ErrorS(err, "Couldn't start the shell")
 		return err
 	}
 	// Run commands sequentially
 	for _, cmd := range commands {
 		_, err = fmt.Fprintf(stdin, "%s\n", cmd)
 		if err != nil {
-			klog.V(4).Info(err)
+			klog.ErrorS(err, "Couldn't send the command", "command", cmd)
 			return err
 		}
 	}
@@ -612,7 +839,7 @@ func (c *Controller) join(conn *ssh.Client, nodeName string) error {
 	// Wait for session to finish
 	err = sess.Wait()
 	if err != nil {
-		klog.V(4).Info(err)
+		klog.ErrorS(err, "Couldn't run the commands")
 		return err
 	}
 	return nil
@@ -622,7 +849,7 @@ func (c *Controller) join(conn *ssh.Client, nodeName string) error {
 func startSession(conn *ssh.Client) (*ssh.Session, error) {
 	sess, err := conn.NewSession()
 	if err != nil {
-		klog.V(4).Info(err)
+		klog.ErrorS(err, "Couldn't start the SSH session")
 		return nil, err
 	}
 	return sess, nil
@@ -632,7 +859,7 @@ func startSession(conn *ssh.Client) (*ssh.Session, error) {
 func startShell(sess *ssh.Session) (*ssh.Session, error) {
 	// Start remote shell
 	if err := sess.Shell(); err != nil {
-		klog.V(4).Info(err)
+		klog.ErrorS(err, "Couldn't start the shell")
 		return nil, err
 	}
 	return sess, nil
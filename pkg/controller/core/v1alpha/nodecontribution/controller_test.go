@@ -1,12 +1,26 @@
 package nodecontribution
 
 import (
+	"context"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
 
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/mailer"
+	"github.com/EdgeNet-project/edgenet/pkg/node"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
 	"github.com/sirupsen/logrus"
 	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
 )
 
 // Dictionary for error messages
@@ -30,3 +44,135 @@ func TestMain(m *testing.M) {
 	logrus.SetOutput(ioutil.Discard)
 	os.Exit(m.Run())
 }
+
+func newNotReadyNode(since time.Time) *corev1.Node {
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "ple-1.edge-net.io"},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionUnknown, LastTransitionTime: metav1.NewTime(since), Message: "Kubelet stopped posting node status."},
+		}}}
+}
+
+func TestCheckHealth(t *testing.T) {
+	c := &Controller{workqueue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "NodeContributions")}
+	defer c.workqueue.ShutDown()
+
+	cases := map[string]struct {
+		since    time.Time
+		recorded *metav1.Time
+		down     bool
+	}{
+		"within grace period":    {time.Now().Add(-time.Minute), nil, false},
+		"grace period over":      {time.Now().Add(-10 * time.Minute), nil, true},
+		"recorded earlier":       {time.Now(), &metav1.Time{Time: time.Now().Add(-time.Hour)}, true},
+		"recorded within grace":  {time.Now().Add(-time.Hour), &metav1.Time{Time: time.Now()}, false},
+		"no transition reported": {time.Time{}, nil, false},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			nodecontribution := &corev1alpha.NodeContribution{ObjectMeta: metav1.ObjectMeta{Name: "ple-1"}}
+			nodecontribution.Status.NotReadySince = tc.recorded
+			util.Equals(t, tc.down, c.checkHealth(nodecontribution, newNotReadyNode(tc.since)))
+			util.Assert(t, nodecontribution.Status.NotReadySince != nil, "not ready time not recorded")
+		})
+	}
+}
+
+func TestDigest(t *testing.T) {
+	c := &Controller{digest: map[string]*mailer.Content{}}
+	for _, node := range []struct{ name, recipient string }{{"ple-1", "john.doe@edge-net.org"}, {"ple-2", "john.doe@edge-net.org"}, {"ple-3", "jane.doe@edge-net.org"}} {
+		c.addToDigest(&mailer.Content{Recipient: []string{node.recipient}, NodeContribution: &mailer.NodeContribution{Name: node.name}})
+	}
+	util.Equals(t, 2, len(c.digest))
+	util.Equals(t, []mailer.NodeContribution{{Name: "ple-1"}, {Name: "ple-2"}}, c.digest["john.doe@edge-net.org"].NodeContributions)
+	util.Equals(t, []mailer.NodeContribution{{Name: "ple-3"}}, c.digest["jane.doe@edge-net.org"].NodeContributions)
+}
+
+func TestPreflight(t *testing.T) {
+	ready := map[string]string{
+		"uname -r":            "5.4.0-89-generic\n",
+		"uname -m":            "armv7l\n",
+		"cat /etc/os-release": "PRETTY_NAME=\"Raspbian GNU/Linux 11 (bullseye)\"\nID=raspbian\nID_LIKE=debian\n",
+		"echo $(ps -p 1 -o comm=) $(stat -fc %T /sys/fs/cgroup)": "systemd cgroup2fs\n",
+		"ss -Hltn":                    "LISTEN 0 4096 127.0.0.53%lo:53 0.0.0.0:*\nLISTEN 0 128 0.0.0.0:22 0.0.0.0:*\n",
+		"hostname -I":                 "192.0.2.10 10.0.0.2\n",
+		"df -Pk /var/lib | tail -n 1": "/dev/sda1 41152736 9238548 31897804 23% /\n",
+	}
+	runner := func(overrides map[string]string) commandRunner {
+		return func(command string) (string, error) {
+			if output, ok := overrides[command]; ok {
+				if output == "" {
+					return "", fmt.Errorf("exit status 127")
+				}
+				return output, nil
+			}
+			return ready[command], nil
+		}
+	}
+
+	status := runPreflight(runner(nil), "192.0.2.10")
+	util.Equals(t, true, status.Passed)
+	util.Equals(t, len(preflightChecks), len(status.Checks))
+	util.Equals(t, "5.4.0-89-generic", status.Checks[0].Value)
+	util.Equals(t, "arm", status.Checks[1].Value)
+	util.Equals(t, "debian", status.Checks[2].Value)
+	util.Equals(t, "31150Mi", status.Checks[6].Value)
+	util.Equals(t, []string{}, preflightReasons(status))
+
+	cases := map[string]struct {
+		overrides map[string]string
+		failed    string
+	}{
+		"old kernel":      {map[string]string{"uname -r": "3.10.0-1160.el7.x86_64"}, "Kernel"},
+		"unsupported":     {map[string]string{"uname -m": "mips"}, "Architecture"},
+		"unknown os":      {map[string]string{"cat /etc/os-release": "ID=alpine"}, "OS"},
+		"no systemd":      {map[string]string{"echo $(ps -p 1 -o comm=) $(stat -fc %T /sys/fs/cgroup)": "init tmpfs"}, "CgroupDriver"},
+		"kubelet running": {map[string]string{"ss -Hltn": "LISTEN 0 4096 *:10250 *:*\nLISTEN 0 4096 [::]:10250 [::]:*"}, "Ports"},
+		"behind a NAT":    {map[string]string{"hostname -I": "10.0.0.2"}, "NAT"},
+		"disk full":       {map[string]string{"df -Pk /var/lib | tail -n 1": "/dev/sda1 41152736 40128548 1024188 98% /"}, "DiskSpace"},
+		"command failed":  {map[string]string{"ss -Hltn": ""}, "Ports"},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			status := runPreflight(runner(tc.overrides), "192.0.2.10")
+			util.Equals(t, false, status.Passed)
+			reasons := preflightReasons(status)
+			util.Equals(t, 1, len(reasons))
+			for _, check := range status.Checks {
+				util.Equals(t, check.Name != tc.failed, check.Passed)
+			}
+		})
+	}
+	util.Equals(t, "10250", runPreflight(runner(cases["kubelet running"].overrides), "192.0.2.10").Checks[4].Value)
+}
+
+func TestJoinToken(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	c := &Controller{kubeclientset: testclient.NewSimpleClientset(), nodecontributionsLister: listers.NewNodeContributionLister(indexer)}
+	tenant := "lip6"
+	nodecontribution := &corev1alpha.NodeContribution{ObjectMeta: metav1.ObjectMeta{Name: "ple-1"}, Spec: corev1alpha.NodeContributionSpec{Tenant: &tenant, Mode: modeToken}}
+
+	token := func() string {
+		secret, err := c.kubeclientset.CoreV1().Secrets("lip6").Get(context.TODO(), "nodecontribution-ple-1-join", metav1.GetOptions{})
+		util.OK(t, err)
+		return secret.StringData["token"]
+	}
+	util.OK(t, c.issueJoinToken(context.TODO(), nodecontribution))
+	first := token()
+	util.OK(t, node.CheckJoinToken(nodecontribution, first, time.Now()))
+	util.Equals(t, corev1.SecretReference{Name: "nodecontribution-ple-1-join", Namespace: "lip6"}, nodecontribution.Status.Join.Secret)
+
+	// A new token replaces the expired one in the same secret
+	util.OK(t, c.issueJoinToken(context.TODO(), nodecontribution))
+	util.Assert(t, token() != first, "token not renewed")
+	util.Assert(t, node.CheckJoinToken(nodecontribution, first, time.Now()) != nil, "former token accepted")
+	util.Assert(t, node.CheckJoinToken(nodecontribution, token(), time.Now().Add(JoinTokenTTL)) != nil, "expired token accepted")
+
+	indexer.Add(nodecontribution)
+	_, ok := c.joiningContribution("ple-1.edge-net.io")
+	util.Equals(t, true, ok)
+	_, ok = c.joiningContribution("ple-2.edge-net.io")
+	util.Equals(t, false, ok)
+	nodecontribution.Status.Join.Node = "ple-1.edge-net.io"
+	_, ok = c.joiningContribution("ple-1.edge-net.io")
+	util.Equals(t, false, ok)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodecontribution
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/mailer"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// NotReadyGracePeriod is how long a contributed node may stay not ready before it is reported down,
+// a node that only misses a few heartbeats is not reported
+var NotReadyGracePeriod = 5 * time.Minute
+
+// DigestInterval gathers the nodes going down within the interval into a single email to each
+// contact, the nodes are reported one by one if it is zero
+var DigestInterval time.Duration
+
+// checkHealth records since when the node is not ready, the node contribution is queued again until
+// the grace period is over. It returns true once the node is to be considered down.
+func (c *Controller) checkHealth(nodecontributionCopy *corev1alpha.NodeContribution, contributedNode *corev1.Node) bool {
+	if nodecontributionCopy.Status.NotReadySince == nil {
+		nodecontributionCopy.Status.NotReadySince = notReadySince(contributedNode)
+	}
+	remaining := NotReadyGracePeriod - time.Since(nodecontributionCopy.Status.NotReadySince.Time)
+	if remaining <= 0 {
+		return true
+	}
+	c.workqueue.AddAfter(nodecontributionCopy.GetName(), remaining)
+	return false
+}
+
+// notReadySince returns the last transition of the ready condition of the node, the current time if
+// the node has not reported any
+func notReadySince(contributedNode *corev1.Node) *metav1.Time {
+	for _, condition := range contributedNode.Status.Conditions {
+		if condition.Type == corev1.NodeReady && !condition.LastTransitionTime.IsZero() {
+			return condition.LastTransitionTime.DeepCopy()
+		}
+	}
+	now := metav1.Now()
+	return &now
+}
+
+// sendNodeDownEmail notifies the contact of the contributing tenant that the node is not ready anymore,
+// the cluster administrators are notified instead if the node is not contributed by a tenant
+func (c *Controller) sendNodeDownEmail(ctx context.Context, nodecontributionCopy *corev1alpha.NodeContribution, contributedNode *corev1.Node) {
+	email := new(mailer.Content)
+	if clusterUID, err := c.identity.UID(ctx); err == nil {
+		email.Cluster = clusterUID
+	}
+	if nodecontributionCopy.Spec.Tenant != nil {
+		if contributorTenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(ctx, *nodecontributionCopy.Spec.Tenant, metav1.GetOptions{}); err == nil {
+			email.User = contributorTenant.Spec.Contact.Email
+			email.FirstName = contributorTenant.Spec.Contact.FirstName
+			email.LastName = contributorTenant.Spec.Contact.LastName
+			email.Recipient = []string{contributorTenant.Spec.Contact.Email}
+		}
+	}
+	email.Subject = fmt.Sprintf("[EdgeNet] Node %s is down", nodecontributionCopy.GetName())
+	email.NodeContribution = new(mailer.NodeContribution)
+	email.NodeContribution.Name = nodecontributionCopy.GetName()
+	email.NodeContribution.Host = nodecontributionCopy.Spec.Host
+	for _, condition := range contributedNode.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			email.NodeContribution.Reason = condition.Message
+		}
+	}
+	if DigestInterval > 0 {
+		c.addToDigest(email)
+		return
+	}
+	email.Send("node-down")
+}
+
+// addToDigest holds the email back until the next flush, the nodes of the same contact are listed
+// in the first email held for that contact
+func (c *Controller) addToDigest(email *mailer.Content) {
+	key := strings.Join(email.Recipient, ",")
+	c.digestMutex.Lock()
+	defer c.digestMutex.Unlock()
+	if pending, ok := c.digest[key]; ok {
+		pending.NodeContributions = append(pending.NodeContributions, *email.NodeContribution)
+		return
+	}
+	email.NodeContributions = []mailer.NodeContribution{*email.NodeContribution}
+	c.digest[key] = email
+}
+
+// flushDigest sends the emails held since the last flush, a single node is reported as usual
+func (c *Controller) flushDigest(ctx context.Context) {
+	c.digestMutex.Lock()
+	digest := c.digest
+	c.digest = map[string]*mailer.Content{}
+	c.digestMutex.Unlock()
+
+	for _, email := range digest {
+		if len(email.NodeContributions) == 1 {
+			email.Send("node-down")
+			continue
+		}
+		email.Subject = fmt.Sprintf("[EdgeNet] %d nodes are down", len(email.NodeContributions))
+		klog.V(4).InfoS("Reporting the nodes down in a digest", "recipient", email.Recipient, "nodes", len(email.NodeContributions))
+		email.Send("node-down-digest")
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodecontribution
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/node"
+	"golang.org/x/crypto/ssh"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MinKernelVersion is the oldest kernel a contributed node may run
+var MinKernelVersion = "4.15"
+
+// RequiredPorts must be free on the node, the kubelet listens on them once it joins
+var RequiredPorts = []int{10250}
+
+// MinDiskSpace is the space in bytes left under /var/lib for the images and the pods
+var MinDiskSpace int64 = 10 << 30
+
+// commandRunner runs a command on the node and returns its output
+type commandRunner func(command string) (string, error)
+
+// preflightCheck runs a command on the node and evaluates its output into the value observed, the
+// reason tells the contributor what to change on the node for the check to pass
+type preflightCheck struct {
+	name     string
+	command  string
+	evaluate func(output, host string) (value string, passed bool, reason string)
+}
+
+// preflightChecks are run in order on a node before it joins the cluster
+var preflightChecks = []preflightCheck{
+	{"Kernel", "uname -r", checkKernel},
+	{"Architecture", node.MachineCommand, checkArchitecture},
+	{"OS", node.OSReleaseCommand, checkOS},
+	{"CgroupDriver", "echo $(ps -p 1 -o comm=) $(stat -fc %T /sys/fs/cgroup)", checkCgroupDriver},
+	{"Ports", "ss -Hltn", checkPorts},
+	{"NAT", "hostname -I", checkNAT},
+	{"DiskSpace", "df -Pk /var/lib | tail -n 1", checkDiskSpace},
+}
+
+// runPreflight runs the preflight checks on the node, a check whose command fails is not passed
+func runPreflight(run commandRunner, host string) *corev1alpha.PreflightStatus {
+	status := &corev1alpha.PreflightStatus{Passed: true, Checked: metav1.Now()}
+	for _, check := range preflightChecks {
+		result := corev1alpha.PreflightCheck{Name: check.name}
+		output, err := run(check.command)
+		if err != nil {
+			result.Reason = fmt.Sprintf("%q failed on the node: %s", check.command, err)
+		} else {
+			result.Value, result.Passed, result.Reason = check.evaluate(strings.TrimSpace(output), host)
+		}
+		status.Passed = status.Passed && result.Passed
+		status.Checks = append(status.Checks, result)
+	}
+	return status
+}
+
+// preflightReasons returns the reasons of the failed checks
+func preflightReasons(status *corev1alpha.PreflightStatus) []string {
+	reasons := []string{}
+	for _, check := range status.Checks {
+		if !check.Passed {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", check.Name, check.Reason))
+		}
+	}
+	return reasons
+}
+
+// sshRunner runs each command in a session of its own over the connection
+func sshRunner(conn *ssh.Client) commandRunner {
+	return func(command string) (string, error) {
+		sess, err := startSession(conn)
+		if err != nil {
+			return "", err
+		}
+		defer sess.Close()
+		output, err := sess.CombinedOutput(command)
+		return string(output), err
+	}
+}
+
+func checkKernel(output, _ string) (string, bool, string) {
+	if kernelAtLeast(output, MinKernelVersion) {
+		return output, true, ""
+	}
+	return output, false, fmt.Sprintf("kernel %s is older than %s, upgrade the kernel of the node", output, MinKernelVersion)
+}
+
+// kernelAtLeast compares the major and minor versions of the kernel release, such as 5.4.0-89-generic
+func kernelAtLeast(release, min string) bool {
+	version := func(release string) (int, int) {
+		parts := strings.SplitN(strings.SplitN(release, "-", 2)[0], ".", 3)
+		if len(parts) < 2 {
+			return 0, 0
+		}
+		major, _ := strconv.Atoi(parts[0])
+		minor, _ := strconv.Atoi(parts[1])
+		return major, minor
+	}
+	major, minor := version(release)
+	minMajor, minMinor := version(min)
+	return major > minMajor || (major == minMajor && minor >= minMinor)
+}
+
+func checkArchitecture(output, _ string) (string, bool, string) {
+	architecture, err := node.Architecture(output)
+	if err != nil {
+		return output, false, err.Error()
+	}
+	return architecture, true, ""
+}
+
+func checkOS(output, _ string) (string, bool, string) {
+	flavor, err := node.Flavor(output)
+	if err != nil {
+		return "", false, err.Error()
+	}
+	return flavor, true, ""
+}
+
+// checkCgroupDriver makes sure the node boots with systemd, the value also tells the cgroup version
+func checkCgroupDriver(output, _ string) (string, bool, string) {
+	fields := strings.Fields(output)
+	if len(fields) > 0 && fields[0] == "systemd" {
+		return output, true, ""
+	}
+	return output, false, "the kubelet uses the systemd cgroup driver, run a distribution booting with systemd"
+}
+
+// checkPorts looks for the required ports among the listening sockets, the value lists the ones in use
+func checkPorts(output, _ string) (string, bool, string) {
+	inUse := []string{}
+	seen := map[string]bool{}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		local := fields[3]
+		port := local[strings.LastIndex(local, ":")+1:]
+		for _, required := range RequiredPorts {
+			if port == strconv.Itoa(required) && !seen[port] {
+				seen[port] = true
+				inUse = append(inUse, port)
+			}
+		}
+	}
+	if len(inUse) == 0 {
+		return "", true, ""
+	}
+	return strings.Join(inUse, ", "), false, fmt.Sprintf("port %s is in use, stop the service listening on it", strings.Join(inUse, ", "))
+}
+
+// checkNAT makes sure the address of the node contribution is among the addresses of the node
+func checkNAT(output, host string) (string, bool, string) {
+	for _, address := range strings.Fields(output) {
+		if address == host {
+			return output, true, ""
+		}
+	}
+	return output, false, fmt.Sprintf("the node does not hold %s, it is behind a NAT; assign the public address to the node or forward the ports to it", host)
+}
+
+// checkDiskSpace reads the space available from the POSIX output of df, given in KiB
+func checkDiskSpace(output, _ string) (string, bool, string) {
+	fields := strings.Fields(output)
+	if len(fields) < 4 {
+		return output, false, "the free space under /var/lib cannot be read"
+	}
+	available, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return output, false, "the free space under /var/lib cannot be read"
+	}
+	value := fmt.Sprintf("%dMi", available>>10)
+	if available<<10 >= MinDiskSpace {
+		return value, true, ""
+	}
+	return value, false, fmt.Sprintf("%d MiB left under /var/lib, free at least %d MiB", available>>10, MinDiskSpace>>20)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodecontribution
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/controller/core/v1alpha/tenant"
+	"github.com/EdgeNet-project/edgenet/pkg/node"
+
+	namecheap "github.com/billputer/go-namecheap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// The modes of joining the cluster
+const (
+	modeSSH   = "SSH"
+	modeToken = "Token"
+)
+
+// JoinTokenTTL is how long the contributor has to run the bootstrap script with the join token,
+// a new token is issued once it expires
+var JoinTokenTTL = time.Hour
+
+// RegistrationServer is the URL of the registration API that exchanges the join tokens
+var RegistrationServer = "https://registration.edge-net.org"
+
+// JoinScriptURL is where the bootstrap script is published
+var JoinScriptURL = "https://raw.githubusercontent.com/EdgeNet-project/edgenet/release-1.0/assets/scripts/edgenet-join.sh"
+
+// awaitJoin issues the join token of a node contributed with a token, and awaits the node until
+// the token expires. The contributor gets the token from the secret named in the status.
+func (c *Controller) awaitJoin(ctx context.Context, nodeName, recordType string, nodecontributionCopy *corev1alpha.NodeContribution) {
+	join := nodecontributionCopy.Status.Join
+	if join == nil {
+		// The DNS record points to the node before it joins, as the SSH setup does
+		hostRecord := namecheap.DomainDNSHost{Name: strings.TrimSuffix(nodeName, ".edge-net.io"), Type: recordType, Address: nodecontributionCopy.Spec.Host}
+		if updated, _ := node.SetHostname(hostRecord); !updated {
+			nodecontributionCopy.Status.Message = append(nodecontributionCopy.Status.Message,
+				fmt.Sprintf("Warning: Hostname %s or address %s couldn't added", hostRecord.Name, hostRecord.Address))
+		}
+	}
+	if join == nil || !time.Now().Before(join.Expires.Time) {
+		if err := c.issueJoinToken(ctx, nodecontributionCopy); err != nil {
+			klog.ErrorS(err, "Couldn't issue the join token", "nodeContribution", klog.KObj(nodecontributionCopy))
+			nodecontributionCopy.Status.State = failure
+			nodecontributionCopy.Status.Message = append(nodecontributionCopy.Status.Message, statusDict["token-failure"])
+			c.edgenetclientset.CoreV1alpha().NodeContributions().UpdateStatus(ctx, nodecontributionCopy, metav1.UpdateOptions{})
+			return
+		}
+		c.recorder.Event(nodecontributionCopy, corev1.EventTypeNormal, setupProcedure, messageDoneJoinToken)
+	}
+	nodecontributionCopy.Status.State = inprogress
+	nodecontributionCopy.Status.Message = append(nodecontributionCopy.Status.Message, statusDict["awaiting-join"])
+	if _, err := c.edgenetclientset.CoreV1alpha().NodeContributions().UpdateStatus(ctx, nodecontributionCopy, metav1.UpdateOptions{}); err != nil {
+		klog.ErrorS(err, "Couldn't update the status of the node contribution", "nodeContribution", klog.KObj(nodecontributionCopy))
+		return
+	}
+	// The node showing up queues the node contribution earlier
+	c.workqueue.AddAfter(nodecontributionCopy.GetName(), time.Until(nodecontributionCopy.Status.Join.Expires.Time))
+}
+
+// issueJoinToken keeps a new join token and the command running the bootstrap script with it in a
+// secret of the tenant of the contributor, only its hash goes into the status
+func (c *Controller) issueJoinToken(ctx context.Context, nodecontributionCopy *corev1alpha.NodeContribution) error {
+	token, hash, err := node.NewJoinToken(nodecontributionCopy.GetName())
+	if err != nil {
+		return err
+	}
+	namespace := "edgenet"
+	if nodecontributionCopy.Spec.Tenant != nil {
+		namespace = *nodecontributionCopy.Spec.Tenant
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("nodecontribution-%s-join", nodecontributionCopy.GetName()), Namespace: namespace,
+		Labels:          map[string]string{"edge-net.io/generated": "true"},
+		OwnerReferences: SetAsOwnerReference(nodecontributionCopy)},
+		StringData: map[string]string{
+			"token":   token,
+			"command": fmt.Sprintf("curl -sfL %s | sudo sh -s -- --server %s --token %s", JoinScriptURL, RegistrationServer, token),
+		}}
+	secretsClient := c.kubeclientset.CoreV1().Secrets(namespace)
+	if _, err := secretsClient.Create(ctx, secret, metav1.CreateOptions{}); errors.IsAlreadyExists(err) {
+		current, err := secretsClient.Get(ctx, secret.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		current.Data = nil
+		current.StringData = secret.StringData
+		if _, err := secretsClient.Update(ctx, current, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	nodecontributionCopy.Status.Join = &corev1alpha.JoinStatus{
+		TokenHash: hash,
+		Secret:    corev1.SecretReference{Name: secret.GetName(), Namespace: namespace},
+		Expires:   metav1.NewTime(time.Now().Add(JoinTokenTTL)),
+	}
+	return nil
+}
+
+// linkJoinedNode makes the node contribution own the node that joined with its token, and drops
+// the secret of the token that is of no use anymore
+func (c *Controller) linkJoinedNode(ctx context.Context, nodeName string, nodecontributionCopy *corev1alpha.NodeContribution) error {
+	ownerReferences := SetAsOwnerReference(nodecontributionCopy)
+	if nodecontributionCopy.Spec.Tenant != nil {
+		if contributorTenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(ctx, *nodecontributionCopy.Spec.Tenant, metav1.GetOptions{}); err == nil {
+			ownerReferences = append(ownerReferences, tenant.SetAsOwnerReference(contributorTenant)...)
+		}
+	}
+	if err := node.SetOwnerReferences(ctx, nodeName, ownerReferences); err != nil {
+		return err
+	}
+	join := nodecontributionCopy.Status.Join
+	join.Node = nodeName
+	if err := c.kubeclientset.CoreV1().Secrets(join.Secret.Namespace).Delete(ctx, join.Secret.Name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		klog.ErrorS(err, "Couldn't delete the secret of the join token", "nodeContribution", klog.KObj(nodecontributionCopy))
+	}
+	return nil
+}
+
+// joiningContribution returns the node contribution that awaits the node to join with a token
+func (c *Controller) joiningContribution(nodeName string) (*corev1alpha.NodeContribution, bool) {
+	if !strings.HasSuffix(nodeName, ".edge-net.io") {
+		return nil, false
+	}
+	nodecontribution, err := c.nodecontributionsLister.Get(strings.TrimSuffix(nodeName, ".edge-net.io"))
+	if err != nil || nodecontribution.Spec.Mode != modeToken || nodecontribution.Status.Join == nil || nodecontribution.Status.Join.Node != "" {
+		return nil, false
+	}
+	return nodecontribution, true
+}
@@ -27,9 +27,11 @@ import (
 
 	"github.com/EdgeNet-project/edgenet/pkg/access"
 	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/config"
 	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
 	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/registration/v1alpha"
 	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
 
 	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -43,7 +45,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 const controllerAgentName = "notifier-controller"
@@ -73,6 +75,8 @@ type Controller struct {
 	// recorder is an event recorder for recording Event resources to the
 	// Kubernetes API.
 	recorder record.EventRecorder
+	// identity is the identity of the cluster that the objects are labeled with
+	identity *config.ClusterIdentity
 }
 
 // NewController returns a new controller
@@ -83,13 +87,14 @@ func NewController(
 	rolerequestInformer informers.RoleRequestInformer) *Controller {
 	// Create event broadcaster
 	utilruntime.Must(scheme.AddToScheme(scheme.Scheme))
-	klog.V(4).Infoln("Creating event broadcaster")
+	klog.V(4).InfoS("Creating event broadcaster")
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartLogging(klog.Infof)
 	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
 	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
 
 	controller := &Controller{
+		identity:             config.NewClusterIdentity(kubeclientset),
 		kubeclientset:        kubeclientset,
 		edgenetclientset:     edgenetclientset,
 		tenantrequestsLister: tenantrequestInformer.Lister(),
@@ -100,7 +105,7 @@ func NewController(
 		recorder:             recorder,
 	}
 
-	klog.Infoln("Setting up event handlers")
+	klog.InfoS("Setting up event handlers")
 
 	// Event handlers deal with events of resources.
 	tenantrequestInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -128,10 +133,11 @@ func NewController(
 func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
 
-	klog.V(4).Infoln("Starting Notifier Controller")
+	klog.V(4).InfoS("Starting Notifier Controller")
 
-	klog.V(4).Infoln("Waiting for informer caches to sync")
+	klog.V(4).InfoS("Waiting for informer caches to sync")
 
 	if ok := cache.WaitForCacheSync(stopCh,
 		c.tenantrequestsSynced,
@@ -139,24 +145,24 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
-	klog.V(4).Infoln("Starting workers")
+	klog.V(4).InfoS("Starting workers")
 	for i := 0; i < threadiness; i++ {
-		go wait.Until(c.runWorker, time.Second, stopCh)
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
 	}
 
-	klog.V(4).Infoln("Started workers")
+	klog.V(4).InfoS("Started workers")
 	<-stopCh
-	klog.V(4).Infoln("Shutting down workers")
+	klog.V(4).InfoS("Shutting down workers")
 
 	return nil
 }
 
-func (c *Controller) runWorker() {
-	for c.processNextWorkItem() {
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
 	}
 }
 
-func (c *Controller) processNextWorkItem() bool {
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
 	obj, shutdown := c.workqueue.Get()
 
 	if shutdown {
@@ -175,18 +181,18 @@ func (c *Controller) processNextWorkItem() bool {
 		}
 		switch obj.(type) {
 		case registrationv1alpha.TenantRequest:
-			if err := c.syncTenantRequestHandler(key); err != nil {
+			if err := c.syncTenantRequestHandler(ctx, key); err != nil {
 				c.workqueue.AddRateLimited(key)
-				return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
+				return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
 			}
 		case registrationv1alpha.RoleRequest:
-			if err := c.syncRoleRequestHandler(key); err != nil {
+			if err := c.syncRoleRequestHandler(ctx, key); err != nil {
 				c.workqueue.AddRateLimited(key)
-				return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
+				return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
 			}
 		}
 		c.workqueue.Forget(obj)
-		klog.V(4).Infof("Successfully synced '%s'", key)
+		klog.V(4).InfoS("Successfully synced", "key", key)
 		return nil
 	}(obj)
 
@@ -199,7 +205,7 @@ func (c *Controller) processNextWorkItem() bool {
 }
 
 // syncTenantRequestHandler looks at the actual state and sends a notification if desired.
-func (c *Controller) syncTenantRequestHandler(key string) error {
+func (c *Controller) syncTenantRequestHandler(ctx context.Context, key string) error {
 	_, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
 		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
@@ -214,14 +220,14 @@ func (c *Controller) syncTenantRequestHandler(key string) error {
 
 		return err
 	}
-	klog.V(4).Infof("processNextItem: object created/updated detected: %s", key)
-	c.processTenantRequest(tenantrequest)
+	klog.V(4).InfoS("processNextItem: object created/updated detected", "key", key)
+	c.processTenantRequest(ctx, tenantrequest)
 
 	return nil
 }
 
 // syncRoleRequestHandler looks at the actual state and sends a notification if desired.
-func (c *Controller) syncRoleRequestHandler(key string) error {
+func (c *Controller) syncRoleRequestHandler(ctx context.Context, key string) error {
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
 		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
@@ -236,8 +242,8 @@ func (c *Controller) syncRoleRequestHandler(key string) error {
 
 		return err
 	}
-	klog.V(4).Infof("processNextItem: object created/updated detected: %s", key)
-	c.processRoleRequest(rolerequest)
+	klog.V(4).InfoS("processNextItem: object created/updated detected", "key", key)
+	c.processRoleRequest(ctx, rolerequest)
 
 	return nil
 }
@@ -255,22 +261,23 @@ func (c *Controller) enqueueNotifier(obj interface{}) {
 	c.workqueue.Add(key)
 }
 
-func (c *Controller) processTenantRequest(tenantrequest *registrationv1alpha.TenantRequest) {
-	klog.V(4).Infoln("Handler.ObjectCreated")
+func (c *Controller) processTenantRequest(ctx context.Context, tenantrequest *registrationv1alpha.TenantRequest) {
+	klog.V(4).InfoS("Handler.ObjectCreated")
 	//nodeObj := obj.(*corev1.Node)
 
-	systemNamespace, err := c.kubeclientset.CoreV1().Namespaces().Get(context.TODO(), "kube-system", metav1.GetOptions{})
+	clusterUID, err := c.identity.UID(ctx)
 	if err != nil {
 		return
 	}
-	if tenantrequest.Status.State == failure || tenantrequest.Status.State == "" {
+	if tenantrequest.Status.State == failure || tenantrequest.Status.State == "" || !access.EmailVerified(tenantrequest) {
+		// The administrators are notified once the email address of the contact is verified
 		return
 	} else if tenantrequest.Status.State == pending {
 		// The function below notifies those who have the right to approve this tenant request.
 		// As tenant requests are cluster-wide resources, we check the permissions granted by Cluster Role Binding following a pattern to avoid overhead.
 		// Furthermore, only those to which the system has granted permission, by attaching the "edge-net.io/generated=true" label, receive a notification email.
 		emailList := []string{}
-		if clusterRoleBindingRaw, err := c.kubeclientset.RbacV1().ClusterRoleBindings().List(context.TODO(), metav1.ListOptions{LabelSelector: "edge-net.io/generated=true"}); err == nil {
+		if clusterRoleBindingRaw, err := c.kubeclientset.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{LabelSelector: "edge-net.io/generated=true"}); err == nil {
 			r, _ := regexp.Compile("(.*)(edgenet:clusteradministration)(.*)(admin|manager|deputy)(.*)")
 			for _, clusterRoleBindingRow := range clusterRoleBindingRaw.Items {
 				if match := r.MatchString(clusterRoleBindingRow.GetName()); !match {
@@ -284,7 +291,7 @@ func (c *Controller) processTenantRequest(tenantrequest *registrationv1alpha.Ten
 							subjectAccessReview.Spec.ResourceAttributes.Resource = "tenantrequests"
 							subjectAccessReview.Spec.ResourceAttributes.Verb = "UPDATE"
 							subjectAccessReview.Spec.ResourceAttributes.Name = tenantrequest.GetName()
-							if subjectAccessReviewResult, err := c.kubeclientset.AuthorizationV1().SubjectAccessReviews().Create(context.TODO(), subjectAccessReview, metav1.CreateOptions{}); err == nil {
+							if subjectAccessReviewResult, err := c.kubeclientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, subjectAccessReview, metav1.CreateOptions{}); err == nil {
 								if subjectAccessReviewResult.Status.Allowed {
 									emailList = append(emailList, subjectRow.Name)
 								}
@@ -296,29 +303,34 @@ func (c *Controller) processTenantRequest(tenantrequest *registrationv1alpha.Ten
 		}
 		if len(emailList) > 0 {
 			access.SendEmailForTenantRequest(tenantrequest, "tenant-request-made", "[EdgeNet Admin] A tenant request made",
-				string(systemNamespace.GetUID()), emailList)
+				clusterUID, emailList)
+		} else {
+			// Nobody can be emailed, the chat channels are still pinged
+			access.NotifyForTenantRequest(tenantrequest, "tenant-request-made", "[EdgeNet Admin] A tenant request made",
+				clusterUID)
 		}
 	} else {
 		access.SendEmailForTenantRequest(tenantrequest, "tenant-request-approved", "[EdgeNet] Tenant request approved",
-			string(systemNamespace.GetUID()), []string{tenantrequest.Spec.Contact.Email})
+			clusterUID, []string{tenantrequest.Spec.Contact.Email})
 	}
 }
 
-func (c *Controller) processRoleRequest(rolerequest *registrationv1alpha.RoleRequest) {
-	klog.V(4).Infoln("Handler.ObjectCreated")
+func (c *Controller) processRoleRequest(ctx context.Context, rolerequest *registrationv1alpha.RoleRequest) {
+	klog.V(4).InfoS("Handler.ObjectCreated")
 
-	systemNamespace, err := c.kubeclientset.CoreV1().Namespaces().Get(context.TODO(), "kube-system", metav1.GetOptions{})
+	clusterUID, err := c.identity.UID(ctx)
 	if err != nil {
 		return
 	}
-	if rolerequest.Status.State == failure || rolerequest.Status.State == "" {
+	if rolerequest.Status.State == failure || rolerequest.Status.State == "" || !access.EmailVerified(rolerequest) {
+		// The administrators are notified once the email address of the requester is verified
 		return
 	} else if rolerequest.Status.State == pending {
 		// The function below notifies those who have the right to approve this role request.
 		// As role requests run on the layer of namespaces, we here ignore the permissions granted by Cluster Role Binding to avoid email floods.
 		// Furthermore, only those to which the system has granted permission, by attaching the "edge-net.io/generated=true" label, receive a notification email.
 		emailList := []string{}
-		if roleBindingRaw, err := c.kubeclientset.RbacV1().RoleBindings(rolerequest.GetNamespace()).List(context.TODO(), metav1.ListOptions{LabelSelector: "edge-net.io/generated=true"}); err == nil {
+		if roleBindingRaw, err := c.kubeclientset.RbacV1().RoleBindings(rolerequest.GetNamespace()).List(ctx, metav1.ListOptions{LabelSelector: "edge-net.io/generated=true"}); err == nil {
 			r, _ := regexp.Compile("(.*)(owner|admin|manager|deputy)(.*)")
 			for _, roleBindingRow := range roleBindingRaw.Items {
 				if match := r.MatchString(roleBindingRow.GetName()); !match {
@@ -334,7 +346,7 @@ func (c *Controller) processRoleRequest(rolerequest *registrationv1alpha.RoleReq
 							subjectAccessReview.Spec.ResourceAttributes.Namespace = rolerequest.GetNamespace()
 							subjectAccessReview.Spec.ResourceAttributes.Verb = "UPDATE"
 							subjectAccessReview.Spec.ResourceAttributes.Name = rolerequest.GetName()
-							if subjectAccessReviewResult, err := c.kubeclientset.AuthorizationV1().SubjectAccessReviews().Create(context.TODO(), subjectAccessReview, metav1.CreateOptions{}); err == nil {
+							if subjectAccessReviewResult, err := c.kubeclientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, subjectAccessReview, metav1.CreateOptions{}); err == nil {
 								if subjectAccessReviewResult.Status.Allowed {
 									emailList = append(emailList, subjectRow.Name)
 								}
@@ -346,10 +358,14 @@ func (c *Controller) processRoleRequest(rolerequest *registrationv1alpha.RoleReq
 		}
 		if len(emailList) > 0 {
 			access.SendEmailForRoleRequest(rolerequest, "role-request-made", "[EdgeNet] A role request made",
-				string(systemNamespace.GetUID()), emailList)
+				clusterUID, emailList)
+		} else {
+			// Nobody can be emailed, the chat channels are still pinged
+			access.NotifyForRoleRequest(rolerequest, "role-request-made", "[EdgeNet] A role request made",
+				clusterUID)
 		}
 	} else {
 		access.SendEmailForRoleRequest(rolerequest, "role-request-approved", "[EdgeNet] Role request approved",
-			string(systemNamespace.GetUID()), []string{rolerequest.Spec.Email})
+			clusterUID, []string{rolerequest.Spec.Email})
 	}
 }
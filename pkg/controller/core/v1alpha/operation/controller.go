@@ -0,0 +1,397 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operation
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const controllerAgentName = "operation-controller"
+
+// Definitions of the state of the operation resource
+const (
+	successSynced         = "Synced"
+	messageResourceSynced = "Operation synced successfully"
+	successCompleted      = "Completed"
+	messageStarted        = "Operation started"
+	messageSucceeded      = "All items processed successfully"
+	messagePartial        = "Some items failed after all retries"
+	messageFailed         = "All items failed after all retries"
+	messageCancelled      = "Operation cancelled"
+	messageNoWorker       = "No worker carries out this type of operation"
+	messageItemsFailed    = "Items of the operation could not be listed"
+	pending               = "Pending"
+	running               = "Running"
+	succeeded             = "Succeeded"
+	partiallyFailed       = "Partially Failed"
+	failure               = "Failed"
+	cancelled             = "Cancelled"
+)
+
+// The number of retries of a failed item when the operation does not set it
+const defaultMaxRetries = 3
+
+// retryBackoff spaces the attempts of a failed item out, it grows with the attempts
+var retryBackoff = 5 * time.Second
+
+// Worker carries out the operations of a type
+type Worker interface {
+	// Items lists the units of work of the operation, it is called once when the operation starts
+	Items(ctx context.Context, operation *corev1alpha.Operation) ([]string, error)
+	// Process carries out a unit of work, it is called again for the same item after a failure
+	Process(ctx context.Context, operation *corev1alpha.Operation, item string) error
+}
+
+// Controller is the controller implementation for Operation resources
+type Controller struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+	// edgenetclientset is a clientset for the EdgeNet API groups
+	edgenetclientset clientset.Interface
+	// workers carry the operations out by type
+	workers map[string]Worker
+
+	operationsLister listers.OperationLister
+	operationsSynced cache.InformerSynced
+
+	// workqueue is a rate limited work queue. This is used to queue work to be
+	// processed instead of performing it as soon as a change happens. This
+	// means we can ensure we only process a fixed amount of resources at a
+	// time, and makes it easy to ensure we are never processing the same item
+	// simultaneously in two different workers.
+	workqueue workqueue.RateLimitingInterface
+	// recorder is an event recorder for recording Event resources to the
+	// Kubernetes API.
+	recorder record.EventRecorder
+}
+
+// NewController returns a new controller
+func NewController(
+	kubeclientset kubernetes.Interface,
+	edgenetclientset clientset.Interface,
+	operationInformer informers.OperationInformer,
+	workers map[string]Worker) *Controller {
+
+	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
+	klog.V(4).InfoS("Creating event broadcaster")
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartStructuredLogging(0)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+
+	controller := &Controller{
+		kubeclientset:    kubeclientset,
+		edgenetclientset: edgenetclientset,
+		workers:          workers,
+		operationsLister: operationInformer.Lister(),
+		operationsSynced: operationInformer.Informer().HasSynced,
+		workqueue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Operations"),
+		recorder:         recorder,
+	}
+
+	klog.V(4).InfoS("Setting up event handlers")
+	// Set up an event handler for when Operation resources change
+	operationInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueOperation,
+		UpdateFunc: func(old, new interface{}) {
+			newObj := new.(*corev1alpha.Operation)
+			oldObj := old.(*corev1alpha.Operation)
+			// Status updates are skipped, the workers queue the operation again after each item
+			if !reflect.DeepEqual(newObj.Spec, oldObj.Spec) {
+				controller.enqueueOperation(new)
+			}
+		},
+	})
+
+	return controller
+}
+
+// Run will set up the event handlers for the types of operation, as well
+// as syncing informer caches and starting workers. It will block until stopCh
+// is closed, at which point it will shutdown the workqueue and wait for
+// workers to finish processing their current work items.
+func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting Operation controller")
+
+	klog.V(4).InfoS("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh,
+		c.operationsSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	klog.V(4).InfoS("Starting workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	klog.V(4).InfoS("Started workers")
+	<-stopCh
+	klog.V(4).InfoS("Shutting down workers")
+
+	return nil
+}
+
+// runWorker is a long-running function that will continually call the
+// processNextWorkItem function in order to read and process a message on the
+// workqueue.
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+// processNextWorkItem will read a single work item off the workqueue and
+// attempt to process it, by calling the syncHandler.
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.workqueue.Done(obj)
+		var key string
+		var ok bool
+
+		if key, ok = obj.(string); !ok {
+			c.workqueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			return nil
+		}
+		if err := c.syncHandler(ctx, key); err != nil {
+			c.workqueue.AddRateLimited(key)
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
+		}
+		c.workqueue.Forget(obj)
+		klog.V(4).InfoS("Successfully synced", "key", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+
+	return true
+}
+
+// syncHandler carries out the next item of the operation and records the progress in the
+// Status block of the Operation resource. A single item is processed per sync so that the
+// operations share the workers, and a cancellation takes effect between two items.
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	if _, err := c.operationsLister.Get(name); err != nil {
+		if errors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("operation '%s' in work queue no longer exists", key))
+			return nil
+		}
+
+		return err
+	}
+	// The cache may lag behind the status update of the previous item
+	operation, err := c.edgenetclientset.CoreV1alpha().Operations().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if finished(operation) {
+		return nil
+	}
+
+	operationCopy := operation.DeepCopy()
+	next := c.processOperation(ctx, operationCopy)
+	if !reflect.DeepEqual(operation.Status, operationCopy.Status) {
+		if _, err := c.edgenetclientset.CoreV1alpha().Operations().UpdateStatus(ctx, operationCopy, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	c.recorder.Event(operation, corev1.EventTypeNormal, successSynced, messageResourceSynced)
+	if next >= 0 {
+		c.workqueue.AddAfter(key, next)
+	}
+	return nil
+}
+
+// enqueueOperation takes an Operation resource and converts it into a namespace/name
+// string which is then put onto the work queue. This method should *not* be
+// passed resources of any type other than Operation.
+func (c *Controller) enqueueOperation(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+// processOperation moves the operation one step forward and returns when to take the next step,
+// a negative duration once the operation is over
+func (c *Controller) processOperation(ctx context.Context, operationCopy *corev1alpha.Operation) time.Duration {
+	if operationCopy.Spec.Cancel {
+		c.complete(operationCopy, cancelled, messageCancelled)
+		return -1
+	}
+	worker, ok := c.workers[operationCopy.Spec.Type]
+	if !ok {
+		c.complete(operationCopy, failure, messageNoWorker)
+		return -1
+	}
+
+	if operationCopy.Status.State == "" || operationCopy.Status.State == pending {
+		items, err := worker.Items(ctx, operationCopy)
+		if err != nil {
+			klog.ErrorS(err, "Couldn't list the items", "operation", klog.KObj(operationCopy))
+			c.complete(operationCopy, failure, messageItemsFailed)
+			return -1
+		}
+		now := metav1.Now()
+		operationCopy.Status.StartTime = &now
+		operationCopy.Status.Items = items
+		operationCopy.Status.State = running
+		operationCopy.Status.Message = messageStarted
+		return 0
+	}
+
+	if operationCopy.Status.Processed < len(operationCopy.Status.Items) {
+		item := operationCopy.Status.Items[operationCopy.Status.Processed]
+		if err := worker.Process(ctx, operationCopy, item); err != nil {
+			klog.ErrorS(err, "Item failed", "item", item, "operation", klog.KObj(operationCopy))
+			attempts := recordFailure(operationCopy, item, err)
+			if attempts <= maxRetries(operationCopy) {
+				return retryBackoff * time.Duration(attempts)
+			}
+			// The item runs out of retries and stays as a partial failure
+			operationCopy.Status.Processed++
+		} else {
+			clearFailure(operationCopy, item)
+			operationCopy.Status.Processed++
+		}
+		operationCopy.Status.Progress = operationCopy.Status.Processed * 100 / len(operationCopy.Status.Items)
+	}
+
+	if operationCopy.Status.Processed < len(operationCopy.Status.Items) {
+		return 0
+	}
+	switch failed := len(operationCopy.Status.Failures); {
+	case failed == 0:
+		c.complete(operationCopy, succeeded, messageSucceeded)
+	case failed < len(operationCopy.Status.Items):
+		c.complete(operationCopy, partiallyFailed, messagePartial)
+	default:
+		c.complete(operationCopy, failure, messageFailed)
+	}
+	return -1
+}
+
+// complete puts the operation into a final state
+func (c *Controller) complete(operationCopy *corev1alpha.Operation, state, message string) {
+	now := metav1.Now()
+	operationCopy.Status.State = state
+	operationCopy.Status.Message = message
+	operationCopy.Status.CompletionTime = &now
+	if len(operationCopy.Status.Items) == 0 && state == succeeded {
+		operationCopy.Status.Progress = 100
+	}
+	eventType := corev1.EventTypeNormal
+	if state != succeeded {
+		eventType = corev1.EventTypeWarning
+	}
+	c.recorder.Event(operationCopy, eventType, successCompleted, fmt.Sprintf("%s: %s", state, message))
+}
+
+// finished tells whether the operation reached a final state
+func finished(operation *corev1alpha.Operation) bool {
+	switch operation.Status.State {
+	case succeeded, partiallyFailed, failure, cancelled:
+		return true
+	}
+	return false
+}
+
+func maxRetries(operation *corev1alpha.Operation) int {
+	if operation.Spec.MaxRetries <= 0 {
+		return defaultMaxRetries
+	}
+	return operation.Spec.MaxRetries
+}
+
+// recordFailure counts a failed attempt of the item and returns the number of retries made so far
+func recordFailure(operationCopy *corev1alpha.Operation, item string, err error) int {
+	for i, itemFailure := range operationCopy.Status.Failures {
+		if itemFailure.Item == item {
+			operationCopy.Status.Failures[i].Attempts++
+			operationCopy.Status.Failures[i].Error = err.Error()
+			return operationCopy.Status.Failures[i].Attempts
+		}
+	}
+	operationCopy.Status.Failures = append(operationCopy.Status.Failures, corev1alpha.OperationFailure{Item: item, Error: err.Error(), Attempts: 1})
+	return 1
+}
+
+// clearFailure drops the failures of an item that went through on a retry
+func clearFailure(operationCopy *corev1alpha.Operation, item string) {
+	for i, itemFailure := range operationCopy.Status.Failures {
+		if itemFailure.Item == item {
+			operationCopy.Status.Failures = append(operationCopy.Status.Failures[:i], operationCopy.Status.Failures[i+1:]...)
+			return
+		}
+	}
+}
+
+// Start creates an operation on behalf of the initiator and returns the reference to keep in the
+// status of the initiator. The name identifies the operation, starting it again does nothing.
+func Start(ctx context.Context, edgenetclientset clientset.Interface, name, operationType string, initiator corev1.ObjectReference, parameters map[string]string, ownerReferences []metav1.OwnerReference) (*corev1alpha.OperationReference, error) {
+	operation := &corev1alpha.Operation{ObjectMeta: metav1.ObjectMeta{Name: name, OwnerReferences: ownerReferences}}
+	operation.SetLabels(map[string]string{"edge-net.io/generated": "true", "edge-net.io/operation-type": operationType})
+	operation.Spec = corev1alpha.OperationSpec{Type: operationType, Initiator: initiator, Parameters: parameters, MaxRetries: defaultMaxRetries}
+	if _, err := edgenetclientset.CoreV1alpha().Operations().Create(ctx, operation, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return nil, err
+	}
+	return &corev1alpha.OperationReference{Name: name, Type: operationType}, nil
+}
@@ -0,0 +1,144 @@
+package operation
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+// fakeWorker fails each item as many times as told
+type fakeWorker struct {
+	items     []string
+	failures  map[string]int
+	processed []string
+}
+
+func (w *fakeWorker) Items(ctx context.Context, operation *corev1alpha.Operation) ([]string, error) {
+	if w.items == nil {
+		return nil, fmt.Errorf("no items")
+	}
+	return w.items, nil
+}
+
+func (w *fakeWorker) Process(ctx context.Context, operation *corev1alpha.Operation, item string) error {
+	if w.failures[item] > 0 {
+		w.failures[item]--
+		return fmt.Errorf("%s failed", item)
+	}
+	w.processed = append(w.processed, item)
+	return nil
+}
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+func newOperation(operationType string, maxRetries int) *corev1alpha.Operation {
+	return &corev1alpha.Operation{
+		ObjectMeta: metav1.ObjectMeta{Name: "operation"},
+		Spec:       corev1alpha.OperationSpec{Type: operationType, MaxRetries: maxRetries},
+	}
+}
+
+// run processes the operation until it is over and returns the delays between the steps
+func run(c *Controller, operation *corev1alpha.Operation) []int {
+	delays := []int{}
+	for i := 0; i < 100; i++ {
+		next := c.processOperation(context.TODO(), operation)
+		if next < 0 {
+			break
+		}
+		delays = append(delays, int(next/retryBackoff))
+	}
+	return delays
+}
+
+func TestProcessOperation(t *testing.T) {
+	cases := map[string]struct {
+		worker    *fakeWorker
+		cancel    bool
+		workType  string
+		expected  string
+		processed []string
+		failures  int
+		progress  int
+	}{
+		"succeeded":    {&fakeWorker{items: []string{"a", "b", "c", "d"}, failures: map[string]int{}}, false, "test", succeeded, []string{"a", "b", "c", "d"}, 0, 100},
+		"retried":      {&fakeWorker{items: []string{"a", "b"}, failures: map[string]int{"a": 2}}, false, "test", succeeded, []string{"a", "b"}, 0, 100},
+		"partial":      {&fakeWorker{items: []string{"a", "b"}, failures: map[string]int{"b": 5}}, false, "test", partiallyFailed, []string{"a"}, 1, 100},
+		"failed":       {&fakeWorker{items: []string{"a"}, failures: map[string]int{"a": 5}}, false, "test", failure, []string{}, 1, 100},
+		"empty":        {&fakeWorker{items: []string{}, failures: map[string]int{}}, false, "test", succeeded, []string{}, 0, 100},
+		"items failed": {&fakeWorker{failures: map[string]int{}}, false, "test", failure, []string{}, 0, 0},
+		"cancelled":    {&fakeWorker{items: []string{"a"}, failures: map[string]int{}}, true, "test", cancelled, []string{}, 0, 0},
+		"no worker":    {&fakeWorker{items: []string{"a"}, failures: map[string]int{}}, false, "unknown", failure, []string{}, 0, 0},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			c := &Controller{workers: map[string]Worker{"test": tc.worker}, recorder: record.NewFakeRecorder(100)}
+			operation := newOperation(tc.workType, 2)
+			operation.Spec.Cancel = tc.cancel
+			run(c, operation)
+			util.Equals(t, tc.expected, operation.Status.State)
+			util.Equals(t, tc.failures, len(operation.Status.Failures))
+			util.Equals(t, tc.progress, operation.Status.Progress)
+			util.Equals(t, len(tc.processed), len(tc.worker.processed))
+			util.Assert(t, operation.Status.CompletionTime != nil, "operation not completed")
+			util.Assert(t, finished(operation), "operation not finished")
+		})
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	worker := &fakeWorker{items: []string{"a"}, failures: map[string]int{"a": 5}}
+	c := &Controller{workers: map[string]Worker{"test": worker}, recorder: record.NewFakeRecorder(100)}
+	operation := newOperation("test", 2)
+	// Listing the items, two retries growing apart, then the item is given up on
+	util.Equals(t, []int{0, 1, 2}, run(c, operation))
+	util.Equals(t, 3, operation.Status.Failures[0].Attempts)
+	util.Equals(t, "a failed", operation.Status.Failures[0].Error)
+}
+
+func TestProgress(t *testing.T) {
+	worker := &fakeWorker{items: []string{"a", "b", "c", "d"}, failures: map[string]int{}}
+	c := &Controller{workers: map[string]Worker{"test": worker}, recorder: record.NewFakeRecorder(100)}
+	operation := newOperation("test", 0)
+	c.processOperation(context.TODO(), operation)
+	util.Equals(t, running, operation.Status.State)
+	util.Equals(t, 0, operation.Status.Progress)
+	util.Assert(t, operation.Status.StartTime != nil, "start time not set")
+	for _, expected := range []int{25, 50, 75} {
+		c.processOperation(context.TODO(), operation)
+		util.Equals(t, expected, operation.Status.Progress)
+		util.Equals(t, running, operation.Status.State)
+	}
+	// Cancelling between two items leaves the rest untouched
+	operation.Spec.Cancel = true
+	c.processOperation(context.TODO(), operation)
+	util.Equals(t, cancelled, operation.Status.State)
+	util.Equals(t, 3, len(worker.processed))
+}
+
+func TestStart(t *testing.T) {
+	edgenetclientset := edgenettestclient.NewSimpleClientset()
+	initiator := corev1.ObjectReference{Kind: "Tenant", Name: "edgenet"}
+	reference, err := Start(context.TODO(), edgenetclientset, "edgenet-teardown-1", "TenantTeardown", initiator, nil, nil)
+	util.OK(t, err)
+	util.Equals(t, "edgenet-teardown-1", reference.Name)
+	// Starting the operation again does not fail
+	_, err = Start(context.TODO(), edgenetclientset, "edgenet-teardown-1", "TenantTeardown", initiator, nil, nil)
+	util.OK(t, err)
+}
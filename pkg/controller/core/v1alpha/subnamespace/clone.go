@@ -0,0 +1,192 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subnamespace
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+)
+
+// cloneWorkspace copies the Deployments, Services, and Config Maps of the source workspace into the child,
+// Secrets are only copied when flagged. The objects generated by EdgeNet are left out as the inheritance
+// handles them. It returns false with the reason if the clone cannot be completed.
+func (c *Controller) cloneWorkspace(ctx context.Context, subnamespaceCopy *corev1alpha.SubNamespace, childName string) (bool, string) {
+	clone := subnamespaceCopy.Spec.Workspace.Clone
+	source, err := c.edgenetclientset.CoreV1alpha().SubNamespaces(subnamespaceCopy.GetNamespace()).Get(ctx, clone.Source, metav1.GetOptions{})
+	if err != nil || source.GetMode() != "workspace" || source.GetName() == subnamespaceCopy.GetName() {
+		return false, messageCloneSourceNotFound
+	}
+	parentNamespace, err := c.kubeclientset.CoreV1().Namespaces().Get(ctx, subnamespaceCopy.GetNamespace(), metav1.GetOptions{})
+	if err != nil {
+		return false, messageCloneFail
+	}
+	sourceName, err := source.GenerateChildName(parentNamespace.GetLabels()["edge-net.io/cluster-uid"])
+	if err != nil {
+		return false, messageCloneFail
+	}
+
+	// The objects running in the source must fit in the quota of the copy
+	if sourceQuota, err := c.kubeclientset.CoreV1().ResourceQuotas(sourceName).Get(ctx, "sub-quota", metav1.GetOptions{}); err == nil {
+		if childQuota, err := c.kubeclientset.CoreV1().ResourceQuotas(childName).Get(ctx, "sub-quota", metav1.GetOptions{}); err == nil {
+			for key, used := range sourceQuota.Status.Used {
+				if hard, ok := childQuota.Spec.Hard[key]; ok && used.Cmp(hard) == 1 {
+					return false, messageCloneQuota
+				}
+			}
+		}
+	}
+
+	listOptions := metav1.ListOptions{LabelSelector: "edge-net.io/generated!=true"}
+	done := true
+	if deploymentRaw, err := c.kubeclientset.AppsV1().Deployments(sourceName).List(ctx, listOptions); err == nil {
+		for _, deploymentRow := range deploymentRaw.Items {
+			deployment := new(appsv1.Deployment)
+			if err := rewriteNamespace(&deploymentRow, deployment, sourceName, childName); err != nil {
+				done = false
+				continue
+			}
+			deployment.Status = appsv1.DeploymentStatus{}
+			if _, err := c.kubeclientset.AppsV1().Deployments(childName).Create(ctx, deployment, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+				klog.ErrorS(err, "Couldn't create the deployment", "deployment", klog.KObj(deployment))
+				done = false
+			}
+		}
+	} else {
+		done = false
+	}
+	if serviceRaw, err := c.kubeclientset.CoreV1().Services(sourceName).List(ctx, listOptions); err == nil {
+		for _, serviceRow := range serviceRaw.Items {
+			service := new(corev1.Service)
+			if err := rewriteNamespace(&serviceRow, service, sourceName, childName); err != nil {
+				done = false
+				continue
+			}
+			// Addresses and node ports are allocated to the copy by the API server
+			service.Spec.ClusterIP = ""
+			service.Spec.ClusterIPs = nil
+			for i := range service.Spec.Ports {
+				service.Spec.Ports[i].NodePort = 0
+			}
+			service.Status = corev1.ServiceStatus{}
+			if _, err := c.kubeclientset.CoreV1().Services(childName).Create(ctx, service, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+				klog.ErrorS(err, "Couldn't create the service", "service", klog.KObj(service))
+				done = false
+			}
+		}
+	} else {
+		done = false
+	}
+	if configMapRaw, err := c.kubeclientset.CoreV1().ConfigMaps(sourceName).List(ctx, listOptions); err == nil {
+		for _, configMapRow := range configMapRaw.Items {
+			// Published to every namespace by Kubernetes
+			if configMapRow.GetName() == "kube-root-ca.crt" {
+				continue
+			}
+			configMap := new(corev1.ConfigMap)
+			if err := rewriteNamespace(&configMapRow, configMap, sourceName, childName); err != nil {
+				done = false
+				continue
+			}
+			if _, err := c.kubeclientset.CoreV1().ConfigMaps(childName).Create(ctx, configMap, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+				klog.ErrorS(err, "Couldn't create the config map", "configMap", klog.KObj(configMap))
+				done = false
+			}
+		}
+	} else {
+		done = false
+	}
+	if clone.Secrets {
+		if secretRaw, err := c.kubeclientset.CoreV1().Secrets(sourceName).List(ctx, listOptions); err == nil {
+			for _, secretRow := range secretRaw.Items {
+				// Tokens belong to the service accounts of the source
+				if secretRow.Type == corev1.SecretTypeServiceAccountToken {
+					continue
+				}
+				secret := new(corev1.Secret)
+				if err := rewriteNamespace(&secretRow, secret, sourceName, childName); err != nil {
+					done = false
+					continue
+				}
+				if _, err := c.kubeclientset.CoreV1().Secrets(childName).Create(ctx, secret, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+					klog.ErrorS(err, "Couldn't create the secret", "secret", klog.KObj(secret))
+					done = false
+				}
+			}
+		} else {
+			done = false
+		}
+	}
+	if !done {
+		return false, messageCloneFail
+	}
+	return true, ""
+}
+
+// rewriteNamespace copies the object into out, set in the target namespace. The service DNS names
+// that point to the source namespace, such as backend.<source>.svc.cluster.local, are pointed to the
+// target namespace in the spec. Child namespace names are hashes that can appear in unrelated
+// values, such as image tags, so the names without the svc label are left as they are.
+func rewriteNamespace(in metav1.Object, out metav1.Object, source, target string) error {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(in)
+	if err != nil {
+		return err
+	}
+	serviceName := regexp.MustCompile(`\.` + regexp.QuoteMeta(source) + `\.svc\b`)
+	for key, value := range content {
+		if key != "metadata" {
+			content[key] = rewriteServiceNames(value, serviceName, "."+target+".svc")
+		}
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, out); err != nil {
+		return fmt.Errorf("couldn't rewrite %s: %s", in.GetName(), err)
+	}
+	out.SetNamespace(target)
+	out.SetUID("")
+	out.SetResourceVersion("")
+	out.SetSelfLink("")
+	out.SetCreationTimestamp(metav1.Time{})
+	out.SetOwnerReferences(nil)
+	out.SetManagedFields(nil)
+	return nil
+}
+
+// rewriteServiceNames replaces the service names in the string values of the content
+func rewriteServiceNames(value interface{}, serviceName *regexp.Regexp, replacement string) interface{} {
+	switch typed := value.(type) {
+	case string:
+		return serviceName.ReplaceAllLiteralString(typed, replacement)
+	case map[string]interface{}:
+		for key, nested := range typed {
+			typed[key] = rewriteServiceNames(nested, serviceName, replacement)
+		}
+	case []interface{}:
+		for i, nested := range typed {
+			typed[i] = rewriteServiceNames(nested, serviceName, replacement)
+		}
+	}
+	return value
+}
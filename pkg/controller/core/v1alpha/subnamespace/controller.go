@@ -18,20 +18,27 @@ package subnamespace
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/EdgeNet-project/edgenet/pkg/access"
 	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
 	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/config"
 	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
 	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
 	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
 	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
 	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/hierarchy"
 	namespacev1 "github.com/EdgeNet-project/edgenet/pkg/namespace"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+	"github.com/EdgeNet-project/edgenet/pkg/signature"
 
 	"github.com/google/uuid"
 
@@ -56,41 +63,51 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 const controllerAgentName = "subnamespace-controller"
 
 // Definitions of the state of the subnamespace resource
 const (
-	successSynced          = "Synced"
-	messageResourceSynced  = "Subsidiary namespace synced successfully"
-	successFormed          = "Formed"
-	messageFormed          = "Subsidiary namespace formed successfully"
-	successExpired         = "Expired"
-	messageExpired         = "Subsidiary namespace deleted successfully"
-	successApplied         = "Applied"
-	messageApplied         = "Child quota applied successfully"
-	successQuotaCheck      = "Checked"
-	messageQuotaCheck      = "The parent has sufficient quota"
-	failureQuotaShortage   = "Shortage"
-	messageQuotaShortage   = "Insufficient quota at the parent"
-	failureUpdate          = "Not Updated"
-	messageUpdateFail      = "Parent quota cannot be updated"
-	failureApplied         = "Not Applied"
-	messageApplyFail       = "Child quota cannot be applied"
-	failureCreation        = "Not Created"
-	messageCreationFail    = "Subsidiary namespace cannot be created"
-	failureInheritance     = "Not Inherited"
-	messageInheritanceFail = "Inheritance from parent to child failed"
-	failureBinding         = "Binding Failed"
-	messageBindingFailed   = "Role binding failed"
-	failureHashing         = "Hashing Failed"
-	messageHashingFailed   = "Hash generation as suffix failed"
-	failureCollision       = "Name Collision"
-	messageCollision       = "Name is not available. Please choose another one."
-	failure                = "Failure"
-	established            = "Established"
+	successSynced              = "Synced"
+	messageResourceSynced      = "Subsidiary namespace synced successfully"
+	successFormed              = "Formed"
+	messageFormed              = "Subsidiary namespace formed successfully"
+	successExpired             = "Expired"
+	messageExpired             = "Subsidiary namespace deleted successfully"
+	successApplied             = "Applied"
+	messageApplied             = "Child quota applied successfully"
+	successQuotaCheck          = "Checked"
+	messageQuotaCheck          = "The parent has sufficient quota"
+	failureQuotaShortage       = "Shortage"
+	messageQuotaShortage       = "Insufficient quota at the parent"
+	failureUpdate              = "Not Updated"
+	messageUpdateFail          = "Parent quota cannot be updated"
+	failureApplied             = "Not Applied"
+	messageApplyFail           = "Child quota cannot be applied"
+	failureCreation            = "Not Created"
+	messageCreationFail        = "Subsidiary namespace cannot be created"
+	failureInheritance         = "Not Inherited"
+	messageInheritanceFail     = "Inheritance from parent to child failed"
+	failureLimitRange          = "Not Applied"
+	messageLimitRangeFail      = "Container limit range cannot be applied"
+	failureRegistry            = "Not Propagated"
+	messageRegistryFail        = "Registry credentials cannot be propagated"
+	failureClone               = "Not Cloned"
+	messageCloneFail           = "Objects of the source workspace cannot be cloned"
+	messageCloneQuota          = "Objects of the source workspace exceed the quota"
+	messageCloneSourceNotFound = "Source workspace to clone not found"
+	failureBinding             = "Binding Failed"
+	messageBindingFailed       = "Role binding failed"
+	failureHashing             = "Hashing Failed"
+	messageHashingFailed       = "Hash generation as suffix failed"
+	failureCollision           = "Name Collision"
+	messageCollision           = "Name is not available. Please choose another one."
+	failureSignature           = "Signature Invalid"
+	messageSignatureInvalid    = "Signature of the subnamespace cannot be verified"
+	failure                    = "Failure"
+	established                = "Established"
 )
 
 // Controller is the controller implementation for Subsidiary Namespace resources
@@ -127,10 +144,17 @@ type Controller struct {
 	// recorder is an event recorder for recording Event resources to the
 	// Kubernetes API.
 	recorder record.EventRecorder
+	// ctx is cancelled at shutdown, it bounds the requests of the event handlers
+	ctx context.Context
+	// identity is the identity of the cluster that the objects are labeled with
+	identity *config.ClusterIdentity
+	// loans serializes the settlements of the loans between the workspaces
+	loans sync.Mutex
 }
 
 // NewController returns a new controller
 func NewController(
+	ctx context.Context,
 	kubeclientset kubernetes.Interface,
 	edgenetclientset clientset.Interface,
 	roleInformer rbacinformers.RoleInformer,
@@ -143,13 +167,15 @@ func NewController(
 	subnamespaceInformer informers.SubNamespaceInformer) *Controller {
 
 	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
-	klog.V(4).Info("Creating event broadcaster")
+	klog.V(4).InfoS("Creating event broadcaster")
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartStructuredLogging(0)
 	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
 	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
 
 	controller := &Controller{
+		ctx:                   ctx,
+		identity:              config.NewClusterIdentity(kubeclientset),
 		kubeclientset:         kubeclientset,
 		edgenetclientset:      edgenetclientset,
 		rolesLister:           roleInformer.Lister(),
@@ -172,7 +198,7 @@ func NewController(
 		recorder:              recorder,
 	}
 
-	klog.V(4).Infoln("Setting up event handlers")
+	klog.V(4).InfoS("Setting up event handlers")
 	// Set up an event handler for when Subsidiary Namespace resources change
 	subnamespaceInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
@@ -181,6 +207,7 @@ func NewController(
 				controller.enqueueSubNamespaceAfter(obj, time.Until(subnamespace.Spec.Expiry.Time))
 			}
 			controller.enqueueSubNamespace(obj)
+			controller.linkParent(subnamespace)
 		},
 		UpdateFunc: func(old, new interface{}) {
 			newSubnamespace := new.(*corev1alpha.SubNamespace)
@@ -196,41 +223,45 @@ func NewController(
 			}
 		}, DeleteFunc: func(obj interface{}) {
 			subnamespace := obj.(*corev1alpha.SubNamespace)
+			if isLending(subnamespace) {
+				controller.enqueueSiblings(subnamespace)
+			}
+			controller.linkParent(subnamespace)
 			if subnamespace.Status.State == "Established" {
-				namespace, err := controller.kubeclientset.CoreV1().Namespaces().Get(context.TODO(), subnamespace.GetNamespace(), metav1.GetOptions{})
+				namespace, err := controller.kubeclientset.CoreV1().Namespaces().Get(ctx, subnamespace.GetNamespace(), metav1.GetOptions{})
 				if err != nil {
-					klog.V(4).Infoln(err)
+					klog.ErrorS(err, "Couldn't get the namespace", "namespace", subnamespace.GetNamespace())
 					return
 				}
 				namespaceLabels := namespace.GetLabels()
 				childNameHashed, err := subnamespace.GenerateChildName(namespaceLabels["edge-net.io/cluster-uid"])
 				if err != nil {
-					klog.V(4).Infoln(err)
+					klog.ErrorS(err, "Couldn't generate the name of the child", "subNamespace", klog.KObj(subnamespace))
 					return
 				}
 				switch subnamespace.GetMode() {
 				case "workspace":
-					if childExists, childOwned := controller.validateChildOwnership(namespace, childNameHashed); childExists && childOwned {
-						controller.kubeclientset.CoreV1().Namespaces().Delete(context.TODO(), childNameHashed, metav1.DeleteOptions{})
+					if childExists, childOwned := controller.validateChildOwnership(ctx, namespace, childNameHashed); childExists && childOwned {
+						controller.kubeclientset.CoreV1().Namespaces().Delete(ctx, childNameHashed, metav1.DeleteOptions{})
 					} else {
 						return
 					}
 				case "subtenant":
-					if childExists, childOwned := controller.validateChildOwnership(namespace, childNameHashed); childExists && childOwned {
-						controller.edgenetclientset.CoreV1alpha().Tenants().Delete(context.TODO(), childNameHashed, metav1.DeleteOptions{})
+					if childExists, childOwned := controller.validateChildOwnership(ctx, namespace, childNameHashed); childExists && childOwned {
+						controller.edgenetclientset.CoreV1alpha().Tenants().Delete(ctx, childNameHashed, metav1.DeleteOptions{})
 					} else {
 						return
 					}
 				}
 
-				if parentResourceQuota, err := controller.kubeclientset.CoreV1().ResourceQuotas(subnamespace.GetNamespace()).Get(context.TODO(), fmt.Sprintf("%s-quota", namespaceLabels["edge-net.io/kind"]), metav1.GetOptions{}); err == nil {
+				if parentResourceQuota, err := controller.kubeclientset.CoreV1().ResourceQuotas(subnamespace.GetNamespace()).Get(ctx, fmt.Sprintf("%s-quota", namespaceLabels["edge-net.io/kind"]), metav1.GetOptions{}); err == nil {
 					parentResourceQuotaCopy := parentResourceQuota.DeepCopy()
 					for key, value := range parentResourceQuotaCopy.Spec.Hard {
 						resourceDemand := subnamespace.RetrieveQuantityValue(key)
 						availableQuota := value.Value()
 						parentResourceQuotaCopy.Spec.Hard[key] = *resource.NewQuantity(availableQuota+resourceDemand, parentResourceQuota.Spec.Hard[key].Format)
 					}
-					controller.kubeclientset.CoreV1().ResourceQuotas(parentResourceQuota.GetNamespace()).Update(context.TODO(), parentResourceQuotaCopy, metav1.UpdateOptions{})
+					controller.kubeclientset.CoreV1().ResourceQuotas(parentResourceQuota.GetNamespace()).Update(ctx, parentResourceQuotaCopy, metav1.UpdateOptions{})
 				}
 			}
 		},
@@ -273,28 +304,28 @@ func NewController(
 		DeleteFunc: controller.handleObject,
 	})
 	limitrangeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: controller.handleObject,
+		AddFunc: controller.handleLimitRange,
 		UpdateFunc: func(old, new interface{}) {
 			newObj := new.(*corev1.LimitRange)
 			oldObj := old.(*corev1.LimitRange)
 			if newObj.ResourceVersion == oldObj.ResourceVersion {
 				return
 			}
-			controller.handleObject(new)
+			controller.handleLimitRange(new)
 		},
-		DeleteFunc: controller.handleObject,
+		DeleteFunc: controller.handleLimitRange,
 	})
 	secretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: controller.handleObject,
+		AddFunc: controller.handleRegistryCredential,
 		UpdateFunc: func(old, new interface{}) {
 			newObj := new.(*corev1.Secret)
 			oldObj := old.(*corev1.Secret)
 			if newObj.ResourceVersion == oldObj.ResourceVersion {
 				return
 			}
-			controller.handleObject(new)
+			controller.handleRegistryCredential(new)
 		},
-		DeleteFunc: controller.handleObject,
+		DeleteFunc: controller.handleRegistryCredential,
 	})
 	configmapInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: controller.handleObject,
@@ -309,7 +340,7 @@ func NewController(
 		DeleteFunc: controller.handleObject,
 	})
 	serviceaccountInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: controller.handleObject,
+		AddFunc: controller.handleServiceAccount,
 		UpdateFunc: func(old, new interface{}) {
 			newObj := new.(*corev1.ServiceAccount)
 			oldObj := old.(*corev1.ServiceAccount)
@@ -331,23 +362,24 @@ func NewController(
 func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
 
-	klog.V(4).Infoln("Starting Subsidiary Namespace controller")
+	klog.V(4).InfoS("Starting Subsidiary Namespace controller")
 
-	klog.V(4).Infoln("Waiting for informer caches to sync")
+	klog.V(4).InfoS("Waiting for informer caches to sync")
 	if ok := cache.WaitForCacheSync(stopCh,
 		c.subnamespacesSynced); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
-	klog.V(4).Infoln("Starting workers")
+	klog.V(4).InfoS("Starting workers")
 	for i := 0; i < threadiness; i++ {
-		go wait.Until(c.runWorker, time.Second, stopCh)
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
 	}
 
-	klog.V(4).Infoln("Started workers")
+	klog.V(4).InfoS("Started workers")
 	<-stopCh
-	klog.V(4).Infoln("Shutting down workers")
+	klog.V(4).InfoS("Shutting down workers")
 
 	return nil
 }
@@ -355,14 +387,14 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 // runWorker is a long-running function that will continually call the
 // processNextWorkItem function in order to read and process a message on the
 // workqueue.
-func (c *Controller) runWorker() {
-	for c.processNextWorkItem() {
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
 	}
 }
 
 // processNextWorkItem will read a single work item off the workqueue and
 // attempt to process it, by calling the syncHandler.
-func (c *Controller) processNextWorkItem() bool {
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
 	obj, shutdown := c.workqueue.Get()
 
 	if shutdown {
@@ -379,12 +411,12 @@ func (c *Controller) processNextWorkItem() bool {
 			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
 			return nil
 		}
-		if err := c.syncHandler(key); err != nil {
+		if err := c.syncHandler(ctx, key); err != nil {
 			c.workqueue.AddRateLimited(key)
-			return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
 		}
 		c.workqueue.Forget(obj)
-		klog.V(4).Infof("Successfully synced '%s'", key)
+		klog.V(4).InfoS("Successfully synced", "key", key)
 		return nil
 	}(obj)
 
@@ -399,7 +431,7 @@ func (c *Controller) processNextWorkItem() bool {
 // syncHandler compares the actual state with the desired, and attempts to
 // converge the two. It then updates the Status block of the Subsidiary Namespace
 // resource with the current status of the resource.
-func (c *Controller) syncHandler(key string) error {
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
 		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
@@ -416,8 +448,11 @@ func (c *Controller) syncHandler(key string) error {
 		return err
 	}
 
-	c.processSubNamespace(subnamespace.DeepCopy())
+	c.processSubNamespace(ctx, subnamespace.DeepCopy())
 	c.recorder.Event(subnamespace, corev1.EventTypeNormal, successSynced, messageResourceSynced)
+	if isLending(subnamespace) {
+		c.enqueueSubNamespaceAfter(subnamespace, LendingInterval)
+	}
 	return nil
 }
 
@@ -447,6 +482,32 @@ func (c *Controller) enqueueSubNamespaceAfter(obj interface{}, after time.Durati
 	c.workqueue.AddAfter(key, after)
 }
 
+// linkParent refreshes the children in the status of the subnamespace that formed the namespace
+// the subnamespace is in. The parent is not synced again, as that would reapply its quota over
+// the share its children took.
+func (c *Controller) linkParent(subnamespace *corev1alpha.SubNamespace) {
+	namespace, err := c.kubeclientset.CoreV1().Namespaces().Get(c.ctx, subnamespace.GetNamespace(), metav1.GetOptions{})
+	if err != nil {
+		return
+	}
+	reference := hierarchy.Parent(namespace)
+	if reference == nil {
+		return
+	}
+	parent, err := c.subnamespacesLister.SubNamespaces(reference.Namespace).Get(reference.Name)
+	if err != nil || parent.Status.State != established {
+		return
+	}
+	parentCopy := parent.DeepCopy()
+	c.linkChildren(parentCopy, namespace.GetName())
+	if reflect.DeepEqual(parent.Status.Children, parentCopy.Status.Children) {
+		return
+	}
+	if _, err := c.edgenetclientset.CoreV1alpha().SubNamespaces(parentCopy.GetNamespace()).UpdateStatus(c.ctx, parentCopy, metav1.UpdateOptions{}); err != nil {
+		klog.ErrorS(err, "Couldn't update the status of the subnamespace", "subNamespace", klog.KObj(parentCopy))
+	}
+}
+
 // handleObject will take any resource implementing metav1.Object and attempt
 // to find the SubNamespace resource that 'owns' its namespace. It does this by
 // looking at the objects metadata.ownerReferences field for an appropriate OwnerReference.
@@ -466,15 +527,15 @@ func (c *Controller) handleObject(obj interface{}) {
 			utilruntime.HandleError(fmt.Errorf("error decoding object tombstone, invalid type"))
 			return
 		}
-		klog.V(4).Infof("Recovered deleted object '%s' from tombstone", object.GetName())
+		klog.V(4).InfoS("Recovered deleted object from tombstone", "object", klog.KObj(object))
 	}
 	objectLabels := object.GetLabels()
 	if objectLabels["edge-net.io/generated"] != "true" {
 		return
 	}
-	klog.V(4).Infof("Processing object: %s", object.GetName())
+	klog.V(4).InfoS("Processing object", "object", klog.KObj(object))
 
-	childnamespace, err := c.kubeclientset.CoreV1().Namespaces().Get(context.TODO(), object.GetNamespace(), metav1.GetOptions{})
+	childnamespace, err := c.kubeclientset.CoreV1().Namespaces().Get(c.ctx, object.GetNamespace(), metav1.GetOptions{})
 	if err != nil {
 		return
 	}
@@ -483,7 +544,7 @@ func (c *Controller) handleObject(obj interface{}) {
 			return
 		}
 
-		parentnamespace, err := c.kubeclientset.CoreV1().Namespaces().Get(context.TODO(), ownerRef.Name, metav1.GetOptions{})
+		parentnamespace, err := c.kubeclientset.CoreV1().Namespaces().Get(c.ctx, ownerRef.Name, metav1.GetOptions{})
 		if err != nil {
 			return
 		}
@@ -491,7 +552,7 @@ func (c *Controller) handleObject(obj interface{}) {
 
 		subnamespaceRaw, err := c.subnamespacesLister.SubNamespaces(ownerRef.Name).List(labels.Everything())
 		if err != nil {
-			klog.V(4).Infof("ignoring orphaned object '%s' of subnamespace '%s'", object.GetSelfLink(), ownerRef.Name)
+			klog.V(4).InfoS("Ignoring orphaned object", "object", klog.KObj(object), "subNamespace", ownerRef.Name)
 		} else {
 			for _, subnamespaceRow := range subnamespaceRaw {
 				childNameHashed, err := subnamespaceRow.GenerateChildName(parentnamespaceLabels["edge-net.io/cluster-uid"])
@@ -499,7 +560,7 @@ func (c *Controller) handleObject(obj interface{}) {
 					continue
 				}
 
-				if childExist, childOwned := c.validateChildOwnership(parentnamespace, childNameHashed); childExist && childOwned {
+				if childExist, childOwned := c.validateChildOwnership(c.ctx, parentnamespace, childNameHashed); childExist && childOwned {
 					c.enqueueSubNamespace(subnamespaceRow)
 				}
 			}
@@ -515,17 +576,31 @@ func (c *Controller) handleObject(obj interface{}) {
 	}
 }
 
-func (c *Controller) processSubNamespace(subnamespaceCopy *corev1alpha.SubNamespace) {
+// handleLimitRange enqueues the subnamespaces of the namespace the limit range is in,
+// as the limit ranges generated in tenant namespaces are enforced down the tree.
+// The object is then handled as any other object in a subsidiary namespace.
+func (c *Controller) handleLimitRange(obj interface{}) {
+	if limitRange, ok := obj.(*corev1.LimitRange); ok && limitRange.GetLabels()["edge-net.io/generated"] == "true" {
+		if subnamespaceRaw, err := c.subnamespacesLister.SubNamespaces(limitRange.GetNamespace()).List(labels.Everything()); err == nil {
+			for _, subnamespaceRow := range subnamespaceRaw {
+				c.enqueueSubNamespace(subnamespaceRow)
+			}
+		}
+	}
+	c.handleObject(obj)
+}
+
+func (c *Controller) processSubNamespace(ctx context.Context, subnamespaceCopy *corev1alpha.SubNamespace) {
 	if subnamespaceCopy.Spec.Expiry != nil && time.Until(subnamespaceCopy.Spec.Expiry.Time) <= 0 {
 		c.recorder.Event(subnamespaceCopy, corev1.EventTypeWarning, successExpired, messageExpired)
-		c.edgenetclientset.CoreV1alpha().SubNamespaces(subnamespaceCopy.GetNamespace()).Delete(context.TODO(), subnamespaceCopy.GetName(), metav1.DeleteOptions{})
+		c.edgenetclientset.CoreV1alpha().SubNamespaces(subnamespaceCopy.GetNamespace()).Delete(ctx, subnamespaceCopy.GetName(), metav1.DeleteOptions{})
 		return
 	}
 	oldStatus := subnamespaceCopy.Status
 	statusUpdate := func() {
 		if !reflect.DeepEqual(oldStatus, subnamespaceCopy.Status) {
-			if _, err := c.edgenetclientset.CoreV1alpha().SubNamespaces(subnamespaceCopy.GetNamespace()).UpdateStatus(context.TODO(), subnamespaceCopy, metav1.UpdateOptions{}); err != nil {
-				klog.V(4).Infoln(err)
+			if _, err := c.edgenetclientset.CoreV1alpha().SubNamespaces(subnamespaceCopy.GetNamespace()).UpdateStatus(ctx, subnamespaceCopy, metav1.UpdateOptions{}); err != nil {
+				klog.ErrorS(err, "Couldn't update the status of the subnamespace", "subNamespace", klog.KObj(subnamespaceCopy))
 			}
 		}
 	}
@@ -534,26 +609,32 @@ func (c *Controller) processSubNamespace(subnamespaceCopy *corev1alpha.SubNamesp
 	// Below code checks whether namespace, where role request made, is local to the cluster or is propagated along with a federated deployment.
 	// If another cluster propagates the namespace, we skip checking the owner tenant's status as the Selective Deployment entity manages this life-cycle.
 	permitted := false
-	systemNamespace, err := c.kubeclientset.CoreV1().Namespaces().Get(context.TODO(), "kube-system", metav1.GetOptions{})
+	clusterUID, err := c.identity.UID(ctx)
 	if err != nil {
-		klog.V(4).Infoln(err)
+		klog.ErrorS(err, "Couldn't read the cluster UID")
 		return
 	}
-	namespace, err := c.kubeclientset.CoreV1().Namespaces().Get(context.TODO(), subnamespaceCopy.GetNamespace(), metav1.GetOptions{})
+	namespace, err := c.kubeclientset.CoreV1().Namespaces().Get(ctx, subnamespaceCopy.GetNamespace(), metav1.GetOptions{})
 	if err != nil {
-		klog.V(4).Infoln(err)
+		klog.ErrorS(err, "Couldn't get the namespace", "namespace", subnamespaceCopy.GetNamespace())
 		return
 	}
 	namespaceLabels := namespace.GetLabels()
-	if systemNamespace.GetUID() != types.UID(namespaceLabels["edge-net.io/cluster-uid"]) {
+	if clusterUID != namespaceLabels["edge-net.io/cluster-uid"] {
 		permitted = true
 	} else {
-		if tenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), strings.ToLower(namespaceLabels["edge-net.io/tenant"]), metav1.GetOptions{}); err == nil {
+		if tenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(ctx, strings.ToLower(namespaceLabels["edge-net.io/tenant"]), metav1.GetOptions{}); err == nil {
 			if tenant.GetUID() == types.UID(namespaceLabels["edge-net.io/tenant-uid"]) && tenant.Spec.Enabled {
+				if err := signature.Verify(tenant, "SubNamespace", subnamespaceCopy, subnamespaceCopy.Spec); err != nil {
+					c.recorder.Event(subnamespaceCopy, corev1.EventTypeWarning, failureSignature, messageSignatureInvalid)
+					subnamespaceCopy.Status.State = failure
+					subnamespaceCopy.Status.Message = fmt.Sprintf("%s: %v", messageSignatureInvalid, err)
+					return
+				}
 				permitted = true
 			}
 		} else {
-			klog.V(4).Infoln(err)
+			klog.ErrorS(err, "Couldn't get the tenant", "tenant", strings.ToLower(namespaceLabels["edge-net.io/tenant"]))
 			return
 		}
 	}
@@ -569,7 +650,7 @@ func (c *Controller) processSubNamespace(subnamespaceCopy *corev1alpha.SubNamesp
 			subnamespaceCopy.Status.Message = messageHashingFailed
 			return
 		}
-		childExist, childOwned := c.validateChildOwnership(namespace, childNameHashed)
+		childExist, childOwned := c.validateChildOwnership(ctx, namespace, childNameHashed)
 		if childExist && !childOwned {
 			c.recorder.Event(subnamespaceCopy, corev1.EventTypeWarning, failureCollision, messageCollision)
 			subnamespaceCopy.Status.State = failure
@@ -579,39 +660,78 @@ func (c *Controller) processSubNamespace(subnamespaceCopy *corev1alpha.SubNamesp
 
 		switch subnamespaceCopy.GetMode() {
 		case "workspace":
-			if subResourceQuota, err := c.kubeclientset.CoreV1().ResourceQuotas(childNameHashed).Get(context.TODO(), "sub-quota", metav1.GetOptions{}); err == nil {
-				childResourceQuota = subResourceQuota.Spec.Hard
+			if subResourceQuota, err := c.kubeclientset.CoreV1().ResourceQuotas(childNameHashed).Get(ctx, "sub-quota", metav1.GetOptions{}); err == nil {
+				childResourceQuota = appliedAllocation(subResourceQuota)
 			}
 
 			labels = map[string]string{"edge-net.io/generated": "true", "edge-net.io/kind": "sub", "edge-net.io/tenant": namespaceLabels["edge-net.io/tenant"],
 				"edge-net.io/owner": subnamespaceCopy.GetName(), "edge-net.io/parent-namespace": subnamespaceCopy.GetNamespace()}
 		case "subtenant":
-			if subtenantResourceQuota, err := c.edgenetclientset.CoreV1alpha().TenantResourceQuotas().Get(context.TODO(), childNameHashed, metav1.GetOptions{}); err == nil {
+			if subtenantResourceQuota, err := c.edgenetclientset.CoreV1alpha().TenantResourceQuotas().Get(ctx, childNameHashed, metav1.GetOptions{}); err == nil {
 				_, assignedQuota := subtenantResourceQuota.Fetch()
 				childResourceQuota = assignedQuota
 			}
 		}
 
-		if parentResourceQuota, err := c.kubeclientset.CoreV1().ResourceQuotas(subnamespaceCopy.GetNamespace()).Get(context.TODO(), fmt.Sprintf("%s-quota", namespaceLabels["edge-net.io/kind"]), metav1.GetOptions{}); err == nil {
-			if sufficientQuota := c.tuneParentResourceQuota(subnamespaceCopy, parentResourceQuota, childResourceQuota); !sufficientQuota {
+		if parentResourceQuota, err := c.kubeclientset.CoreV1().ResourceQuotas(subnamespaceCopy.GetNamespace()).Get(ctx, fmt.Sprintf("%s-quota", namespaceLabels["edge-net.io/kind"]), metav1.GetOptions{}); err == nil {
+			if sufficientQuota := c.tuneParentResourceQuota(ctx, subnamespaceCopy, parentResourceQuota, childResourceQuota); !sufficientQuota {
 				return
 			}
 		}
 
+		if subnamespaceCopy.GetMode() == "workspace" && subnamespaceCopy.Spec.Workspace.ResourceAllocation != nil {
+			c.settleLoans(ctx, subnamespaceCopy, namespaceLabels["edge-net.io/cluster-uid"])
+		}
+
 		ownerReferences := namespacev1.SetAsOwnerReference(namespace)
-		childInitiated := c.constructSubsidiaryNamespace(subnamespaceCopy, childNameHashed, childExist, labels, ownerReferences)
+		childInitiated := c.constructSubsidiaryNamespace(ctx, subnamespaceCopy, childNameHashed, childExist, labels, ownerReferences)
 		if !childInitiated {
 			return
 		}
 
+		c.linkSubNamespace(ctx, subnamespaceCopy, namespace, childNameHashed)
 		subnamespaceCopy.Status.State = established
 		subnamespaceCopy.Status.Message = messageFormed
 		c.recorder.Event(subnamespaceCopy, corev1.EventTypeNormal, successFormed, messageFormed)
 	}
 }
 
-func (c *Controller) validateChildOwnership(parentNamespace *corev1.Namespace, childName string) (bool, bool) {
-	if childNamespace, err := c.kubeclientset.CoreV1().Namespaces().Get(context.TODO(), childName, metav1.GetOptions{}); err == nil {
+// linkSubNamespace records the place of the subnamespace in the tree of its tenant. The children
+// of a subtenant are hidden from its parent.
+func (c *Controller) linkSubNamespace(ctx context.Context, subnamespaceCopy *corev1alpha.SubNamespace, namespace *corev1.Namespace, childName string) {
+	subnamespaceCopy.Status.Child = childName
+	subnamespaceCopy.Status.Parent = hierarchy.Parent(namespace)
+	ancestors, err := hierarchy.Ancestors(func(name string) (*corev1.Namespace, error) {
+		return c.kubeclientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	}, namespace.GetName())
+	if err != nil {
+		klog.ErrorS(err, "Couldn't walk the ancestors of the namespace", "namespace", namespace.GetName())
+	} else {
+		subnamespaceCopy.Status.Depth = len(ancestors)
+	}
+
+	c.linkChildren(subnamespaceCopy, childName)
+}
+
+// linkChildren lists the subnamespaces in the namespace the workspace formed as its children
+func (c *Controller) linkChildren(subnamespaceCopy *corev1alpha.SubNamespace, childName string) {
+	subnamespaceCopy.Status.Children = nil
+	if subnamespaceCopy.GetMode() != "workspace" {
+		return
+	}
+	children, err := c.subnamespacesLister.SubNamespaces(childName).List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Couldn't list the subnamespaces of the workspace", "namespace", childName)
+		return
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].GetName() < children[j].GetName() })
+	for _, child := range children {
+		subnamespaceCopy.Status.Children = append(subnamespaceCopy.Status.Children, corev1alpha.SubNamespaceReference{Namespace: childName, Name: child.GetName()})
+	}
+}
+
+func (c *Controller) validateChildOwnership(ctx context.Context, parentNamespace *corev1.Namespace, childName string) (bool, bool) {
+	if childNamespace, err := c.kubeclientset.CoreV1().Namespaces().Get(ctx, childName, metav1.GetOptions{}); err == nil {
 		for _, ownerReference := range childNamespace.GetOwnerReferences() {
 			if ownerReference.Kind == "Namespace" && ownerReference.UID == parentNamespace.GetUID() && ownerReference.Name == parentNamespace.GetName() {
 				return true, true
@@ -622,7 +742,7 @@ func (c *Controller) validateChildOwnership(parentNamespace *corev1.Namespace, c
 	return false, false
 }
 
-func (c *Controller) tuneParentResourceQuota(subnamespaceCopy *corev1alpha.SubNamespace, parentResourceQuota *corev1.ResourceQuota, childResourceQuota map[corev1.ResourceName]resource.Quantity) bool {
+func (c *Controller) tuneParentResourceQuota(ctx context.Context, subnamespaceCopy *corev1alpha.SubNamespace, parentResourceQuota *corev1.ResourceQuota, childResourceQuota map[corev1.ResourceName]resource.Quantity) bool {
 	remainingQuota := make(map[corev1.ResourceName]resource.Quantity)
 	for key, value := range parentResourceQuota.Spec.Hard {
 		resourceDemand := subnamespaceCopy.RetrieveQuantityValue(key)
@@ -645,7 +765,7 @@ func (c *Controller) tuneParentResourceQuota(subnamespaceCopy *corev1alpha.SubNa
 
 	parentResourceQuotaCopy := parentResourceQuota.DeepCopy()
 	parentResourceQuotaCopy.Spec.Hard = remainingQuota
-	if _, err := c.kubeclientset.CoreV1().ResourceQuotas(parentResourceQuota.GetNamespace()).Update(context.TODO(), parentResourceQuotaCopy, metav1.UpdateOptions{}); err != nil {
+	if _, err := c.kubeclientset.CoreV1().ResourceQuotas(parentResourceQuota.GetNamespace()).Update(ctx, parentResourceQuotaCopy, metav1.UpdateOptions{}); err != nil {
 		c.recorder.Event(subnamespaceCopy, corev1.EventTypeWarning, failureUpdate, messageUpdateFail)
 		subnamespaceCopy.Status.State = failure
 		subnamespaceCopy.Status.Message = messageUpdateFail
@@ -656,14 +776,14 @@ func (c *Controller) tuneParentResourceQuota(subnamespaceCopy *corev1alpha.SubNa
 	return true
 }
 
-func (c *Controller) constructSubsidiaryNamespace(subnamespaceCopy *corev1alpha.SubNamespace, childName string, childExists bool, labels map[string]string, ownerReferences []metav1.OwnerReference) bool {
+func (c *Controller) constructSubsidiaryNamespace(ctx context.Context, subnamespaceCopy *corev1alpha.SubNamespace, childName string, childExists bool, labels map[string]string, ownerReferences []metav1.OwnerReference) bool {
 	switch subnamespaceCopy.GetMode() {
 	case "workspace":
 		if !childExists {
 			childNamespaceObj := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: childName, OwnerReferences: ownerReferences}}
 			childNamespaceObj.SetName(childName)
 			childNamespaceObj.SetLabels(labels)
-			if _, err := c.kubeclientset.CoreV1().Namespaces().Create(context.TODO(), childNamespaceObj, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			if _, err := c.kubeclientset.CoreV1().Namespaces().Create(ctx, childNamespaceObj, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
 				c.recorder.Event(subnamespaceCopy, corev1.EventTypeWarning, failureCreation, messageCreationFail)
 				subnamespaceCopy.Status.State = failure
 				subnamespaceCopy.Status.Message = messageCreationFail
@@ -673,14 +793,14 @@ func (c *Controller) constructSubsidiaryNamespace(subnamespaceCopy *corev1alpha.
 
 		if subnamespaceCopy.Spec.Workspace.Owner != nil {
 			objectName := "edgenet:workspace:owner"
-			if roleBinding, err := c.kubeclientset.RbacV1().RoleBindings(subnamespaceCopy.GetNamespace()).Get(context.TODO(), objectName, metav1.GetOptions{}); err == nil {
+			if roleBinding, err := c.kubeclientset.RbacV1().RoleBindings(subnamespaceCopy.GetNamespace()).Get(ctx, objectName, metav1.GetOptions{}); err == nil {
 				roleBindingCopy := roleBinding.DeepCopy()
 				roleBindingCopy.Subjects = []rbacv1.Subject{{Kind: "User", Name: subnamespaceCopy.Spec.Workspace.Owner.Email, APIGroup: "rbac.authorization.k8s.io"}}
-				if _, err := c.kubeclientset.RbacV1().RoleBindings(subnamespaceCopy.GetNamespace()).Update(context.TODO(), roleBindingCopy, metav1.UpdateOptions{}); err != nil {
+				if _, err := c.kubeclientset.RbacV1().RoleBindings(subnamespaceCopy.GetNamespace()).Update(ctx, roleBindingCopy, metav1.UpdateOptions{}); err != nil {
 					c.recorder.Event(subnamespaceCopy, corev1.EventTypeWarning, failureBinding, messageBindingFailed)
 					subnamespaceCopy.Status.State = failure
 					subnamespaceCopy.Status.Message = messageBindingFailed
-					klog.V(4).Infoln(err)
+					klog.ErrorS(err, "Couldn't update the role binding", "roleBinding", klog.KObj(roleBindingCopy))
 					return false
 				}
 			} else {
@@ -690,27 +810,61 @@ func (c *Controller) constructSubsidiaryNamespace(subnamespaceCopy *corev1alpha.
 					Subjects: rbSubjects, RoleRef: roleRef}
 				roleBindLabels := map[string]string{"edge-net.io/generated": "true"}
 				roleBind.SetLabels(roleBindLabels)
-				if _, err := c.kubeclientset.RbacV1().RoleBindings(subnamespaceCopy.GetNamespace()).Create(context.TODO(), roleBind, metav1.CreateOptions{}); err != nil {
+				if _, err := c.kubeclientset.RbacV1().RoleBindings(subnamespaceCopy.GetNamespace()).Create(ctx, roleBind, metav1.CreateOptions{}); err != nil {
 					c.recorder.Event(subnamespaceCopy, corev1.EventTypeWarning, failureBinding, messageBindingFailed)
 					subnamespaceCopy.Status.State = failure
 					subnamespaceCopy.Status.Message = messageBindingFailed
-					klog.V(4).Infoln(err)
+					klog.ErrorS(err, "Couldn't create the role binding", "roleBinding", klog.KObj(roleBind))
 					return false
 				}
 			}
 		}
 
+		// The controllers acting on the objects of the tenant hold their rights in its namespaces only
+		if err := c.applyServiceRoleBindings(ctx, childName); err != nil {
+			c.recorder.Event(subnamespaceCopy, corev1.EventTypeWarning, failureBinding, messageBindingFailed)
+			subnamespaceCopy.Status.State = failure
+			subnamespaceCopy.Status.Message = messageBindingFailed
+			klog.ErrorS(err, "Couldn't bind the services", "namespace", childName)
+			return false
+		}
+
 		if subnamespaceCopy.Spec.Workspace.ResourceAllocation != nil {
-			quotaApplied := c.applyChildResourceQuota(subnamespaceCopy, childName, ownerReferences)
+			quotaApplied := c.applyChildResourceQuota(ctx, subnamespaceCopy, childName, ownerReferences)
 			if !quotaApplied {
 				return false
 			}
 		}
 
-		done := c.handleInheritance(subnamespaceCopy, childName)
+		if !c.propagateLimitRange(ctx, subnamespaceCopy, childName) {
+			c.recorder.Event(subnamespaceCopy, corev1.EventTypeWarning, failureLimitRange, messageLimitRangeFail)
+			subnamespaceCopy.Status.State = failure
+			subnamespaceCopy.Status.Message = messageLimitRangeFail
+			return false
+		}
+
+		if !c.propagateRegistryCredentials(ctx, subnamespaceCopy, childName) {
+			c.recorder.Event(subnamespaceCopy, corev1.EventTypeWarning, failureRegistry, messageRegistryFail)
+			subnamespaceCopy.Status.State = failure
+			subnamespaceCopy.Status.Message = messageRegistryFail
+			return false
+		}
+
+		done := c.handleInheritance(ctx, subnamespaceCopy, childName)
 		if !done {
 			return false
 		}
+
+		// Objects are cloned once, later changes in the source are not followed
+		if subnamespaceCopy.Spec.Workspace.Clone != nil && !subnamespaceCopy.Status.Cloned {
+			if cloned, message := c.cloneWorkspace(ctx, subnamespaceCopy, childName); !cloned {
+				c.recorder.Event(subnamespaceCopy, corev1.EventTypeWarning, failureClone, message)
+				subnamespaceCopy.Status.State = failure
+				subnamespaceCopy.Status.Message = message
+				return false
+			}
+			subnamespaceCopy.Status.Cloned = true
+		}
 	case "subtenant":
 		if !childExists {
 			// Separate tenant creation and tenant resource quota creation
@@ -720,7 +874,7 @@ func (c *Controller) constructSubsidiaryNamespace(subnamespaceCopy *corev1alpha.
 			tenantRequest.SetOwnerReferences(ownerReferences)
 			tenantRequest.Spec.Contact = subnamespaceCopy.Spec.Subtenant.Owner
 			tenantRequest.Spec.ResourceAllocation = subnamespaceCopy.Spec.Subtenant.ResourceAllocation
-			if err := access.CreateTenant(tenantRequest); err != nil {
+			if err := access.CreateTenant(ctx, tenantRequest, nil); err != nil {
 				c.recorder.Event(subnamespaceCopy, corev1.EventTypeWarning, failureCreation, messageCreationFail)
 				subnamespaceCopy.Status.State = failure
 				subnamespaceCopy.Status.Message = messageCreationFail
@@ -728,7 +882,7 @@ func (c *Controller) constructSubsidiaryNamespace(subnamespaceCopy *corev1alpha.
 			}
 		} else {
 			if subnamespaceCopy.Spec.Subtenant.ResourceAllocation != nil {
-				quotaApplied := c.applyChildResourceQuota(subnamespaceCopy, childName, ownerReferences)
+				quotaApplied := c.applyChildResourceQuota(ctx, subnamespaceCopy, childName, ownerReferences)
 				if !quotaApplied {
 					return false
 				}
@@ -738,27 +892,47 @@ func (c *Controller) constructSubsidiaryNamespace(subnamespaceCopy *corev1alpha.
 	return true
 }
 
-func (c *Controller) applyChildResourceQuota(subnamespaceCopy *corev1alpha.SubNamespace, childName string, ownerReferences []metav1.OwnerReference) bool {
+// applyServiceRoleBindings binds the services in the child namespace
+func (c *Controller) applyServiceRoleBindings(ctx context.Context, childName string) error {
+	for _, roleBind := range access.ServiceRoleBindings(childName) {
+		if _, err := c.kubeclientset.RbacV1().RoleBindings(childName).Create(ctx, roleBind, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Controller) applyChildResourceQuota(ctx context.Context, subnamespaceCopy *corev1alpha.SubNamespace, childName string, ownerReferences []metav1.OwnerReference) bool {
 	switch subnamespaceCopy.GetMode() {
 	case "workspace":
-		if childResourceQuota, err := c.kubeclientset.CoreV1().ResourceQuotas(childName).Get(context.TODO(), "sub-quota", metav1.GetOptions{}); err == nil {
+		allocation, err := json.Marshal(subnamespaceCopy.Spec.Workspace.ResourceAllocation)
+		if err != nil {
+			klog.ErrorS(err, "Couldn't encode the allocation", "subNamespace", klog.KObj(subnamespaceCopy))
+			return false
+		}
+		if childResourceQuota, err := c.kubeclientset.CoreV1().ResourceQuotas(childName).Get(ctx, "sub-quota", metav1.GetOptions{}); err == nil {
 			childResourceQuotaCopy := childResourceQuota.DeepCopy()
-			childResourceQuotaCopy.Spec.Hard = subnamespaceCopy.Spec.Workspace.ResourceAllocation
-			if _, err := c.kubeclientset.CoreV1().ResourceQuotas(childName).Update(context.TODO(), childResourceQuotaCopy, metav1.UpdateOptions{}); err != nil {
-				klog.V(4).Infoln(err)
+			childResourceQuotaCopy.Spec.Hard = loanedQuota(subnamespaceCopy)
+			if childResourceQuotaCopy.GetAnnotations() == nil {
+				childResourceQuotaCopy.SetAnnotations(map[string]string{})
+			}
+			childResourceQuotaCopy.GetAnnotations()[allocationAnnotation] = string(allocation)
+			if _, err := c.kubeclientset.CoreV1().ResourceQuotas(childName).Update(ctx, childResourceQuotaCopy, metav1.UpdateOptions{}); err != nil {
+				klog.ErrorS(err, "Couldn't update the resource quota", "resourceQuota", klog.KObj(childResourceQuotaCopy))
 				return false
 			}
 		} else {
 			resourceQuota := corev1.ResourceQuota{}
 			resourceQuota.Name = "sub-quota"
+			resourceQuota.SetAnnotations(map[string]string{allocationAnnotation: string(allocation)})
 			resourceQuota.Spec = corev1.ResourceQuotaSpec{
-				Hard: subnamespaceCopy.Spec.Workspace.ResourceAllocation,
+				Hard: loanedQuota(subnamespaceCopy),
 			}
-			if _, err := c.kubeclientset.CoreV1().ResourceQuotas(childName).Create(context.TODO(), resourceQuota.DeepCopy(), metav1.CreateOptions{}); err != nil {
+			if _, err := c.kubeclientset.CoreV1().ResourceQuotas(childName).Create(ctx, resourceQuota.DeepCopy(), metav1.CreateOptions{}); err != nil {
 				c.recorder.Event(subnamespaceCopy, corev1.EventTypeWarning, failureApplied, messageApplyFail)
 				subnamespaceCopy.Status.State = failure
 				subnamespaceCopy.Status.Message = failureApplied
-				klog.V(4).Infoln(err)
+				klog.ErrorS(err, "Couldn't create the resource quota", "resourceQuota", klog.KObj(&resourceQuota))
 				return false
 			}
 		}
@@ -766,118 +940,155 @@ func (c *Controller) applyChildResourceQuota(subnamespaceCopy *corev1alpha.SubNa
 		claim := corev1alpha.ResourceTuning{
 			ResourceList: subnamespaceCopy.Spec.Subtenant.ResourceAllocation,
 		}
-		access.ApplyTenantResourceQuota(childName, nil, claim)
+		access.ApplyTenantResourceQuota(ctx, childName, nil, claim)
 	}
 
 	c.recorder.Event(subnamespaceCopy, corev1.EventTypeNormal, successApplied, messageApplied)
 	return true
 }
 
-func (c *Controller) handleInheritance(subnamespaceCopy *corev1alpha.SubNamespace, childNamespace string) bool {
+// propagateLimitRange copies the limit ranges generated in the parent to the child regardless of
+// the inheritance settings, so that a workspace cannot be used to escape the container limits of its tenant
+func (c *Controller) propagateLimitRange(ctx context.Context, subnamespaceCopy *corev1alpha.SubNamespace, childNamespace string) bool {
+	limitRangeRaw, err := c.kubeclientset.CoreV1().LimitRanges(subnamespaceCopy.GetNamespace()).List(ctx, metav1.ListOptions{LabelSelector: "edge-net.io/generated=true"})
+	if err != nil {
+		klog.ErrorS(err, "Couldn't list the limit ranges", "namespace", subnamespaceCopy.GetNamespace())
+		return false
+	}
+	done := true
+	for _, limitRangeRow := range limitRangeRaw.Items {
+		limitRange := limitRangeRow.DeepCopy()
+		limitRange.SetNamespace(childNamespace)
+		limitRange.SetUID(types.UID(uuid.New().String()))
+		limitRange.SetResourceVersion("")
+		if _, err := c.kubeclientset.CoreV1().LimitRanges(childNamespace).Create(ctx, limitRange, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			done = false
+			klog.ErrorS(err, "Couldn't create the limit range", "limitRange", klog.KObj(limitRange))
+		} else if errors.IsAlreadyExists(err) {
+			if existingLimitRange, err := c.kubeclientset.CoreV1().LimitRanges(childNamespace).Get(ctx, limitRange.GetName(), metav1.GetOptions{}); err != nil {
+				done = false
+			} else if !reflect.DeepEqual(limitRange.Spec, existingLimitRange.Spec) || !reflect.DeepEqual(limitRange.GetLabels(), existingLimitRange.GetLabels()) {
+				existingLimitRange.Spec = limitRange.Spec
+				existingLimitRange.SetLabels(limitRange.GetLabels())
+				if _, err := c.kubeclientset.CoreV1().LimitRanges(childNamespace).Update(ctx, existingLimitRange, metav1.UpdateOptions{}); err != nil {
+					done = false
+					klog.ErrorS(err, "Couldn't update the limit range", "limitRange", klog.KObj(existingLimitRange))
+				}
+			}
+		}
+	}
+	return done
+}
+
+func (c *Controller) handleInheritance(ctx context.Context, subnamespaceCopy *corev1alpha.SubNamespace, childNamespace string) bool {
 	done := true
 	if subnamespaceCopy.Spec.Workspace != nil {
-		if roleRaw, err := c.kubeclientset.RbacV1().Roles(subnamespaceCopy.GetNamespace()).List(context.TODO(), metav1.ListOptions{}); err == nil && subnamespaceCopy.Spec.Workspace.Inheritance["rbac"] {
+		if roleRaw, err := c.kubeclientset.RbacV1().Roles(subnamespaceCopy.GetNamespace()).List(ctx, metav1.ListOptions{}); err == nil && subnamespaceCopy.Spec.Workspace.Inheritance["rbac"] {
 			for _, roleRow := range roleRaw.Items {
 				role := roleRow.DeepCopy()
 				role.SetNamespace(childNamespace)
 				role.SetUID(types.UID(uuid.New().String()))
-				if _, err := c.kubeclientset.RbacV1().Roles(childNamespace).Create(context.TODO(), role, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+				if _, err := c.kubeclientset.RbacV1().Roles(childNamespace).Create(ctx, role, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
 					done = false
 				} else if errors.IsAlreadyExists(err) && subnamespaceCopy.Spec.Workspace.Sync {
-					if existingRole, err := c.kubeclientset.RbacV1().Roles(childNamespace).Get(context.TODO(), role.GetName(), metav1.GetOptions{}); err != nil {
+					if existingRole, err := c.kubeclientset.RbacV1().Roles(childNamespace).Get(ctx, role.GetName(), metav1.GetOptions{}); err != nil {
 						done = false
 					} else {
 						if !reflect.DeepEqual(role.Rules, existingRole.Rules) || !reflect.DeepEqual(role.GetLabels(), existingRole.GetLabels()) {
 							existingRole.Rules = role.Rules
 							existingRole.SetLabels(role.GetLabels())
-							if _, err := c.kubeclientset.RbacV1().Roles(childNamespace).Update(context.TODO(), existingRole, metav1.UpdateOptions{}); err != nil {
+							if _, err := c.kubeclientset.RbacV1().Roles(childNamespace).Update(ctx, existingRole, metav1.UpdateOptions{}); err != nil {
 								done = false
-								klog.V(4).Infoln(err)
+								klog.ErrorS(err, "Couldn't update the role", "role", klog.KObj(existingRole))
 							}
 						}
 					}
 				}
 			}
 		}
-		if roleBindingRaw, err := c.kubeclientset.RbacV1().RoleBindings(subnamespaceCopy.GetNamespace()).List(context.TODO(), metav1.ListOptions{}); err == nil && subnamespaceCopy.Spec.Workspace.Inheritance["rbac"] {
+		if roleBindingRaw, err := c.kubeclientset.RbacV1().RoleBindings(subnamespaceCopy.GetNamespace()).List(ctx, metav1.ListOptions{}); err == nil && subnamespaceCopy.Spec.Workspace.Inheritance["rbac"] {
 			for _, roleBindingRow := range roleBindingRaw.Items {
 				roleBinding := roleBindingRow.DeepCopy()
 				roleBinding.SetNamespace(childNamespace)
 				roleBinding.SetUID(types.UID(uuid.New().String()))
-				if _, err := c.kubeclientset.RbacV1().RoleBindings(childNamespace).Create(context.TODO(), roleBinding, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+				if _, err := c.kubeclientset.RbacV1().RoleBindings(childNamespace).Create(ctx, roleBinding, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
 					done = false
 				} else if errors.IsAlreadyExists(err) && subnamespaceCopy.Spec.Workspace.Sync {
-					if existingRoleBinding, err := c.kubeclientset.RbacV1().RoleBindings(childNamespace).Get(context.TODO(), roleBinding.GetName(), metav1.GetOptions{}); err != nil {
+					if existingRoleBinding, err := c.kubeclientset.RbacV1().RoleBindings(childNamespace).Get(ctx, roleBinding.GetName(), metav1.GetOptions{}); err != nil {
 						done = false
 					} else {
 						if !reflect.DeepEqual(roleBinding.RoleRef, existingRoleBinding.RoleRef) || !reflect.DeepEqual(roleBinding.Subjects, existingRoleBinding.Subjects) || !reflect.DeepEqual(roleBinding.GetLabels(), existingRoleBinding.GetLabels()) {
 							existingRoleBinding.RoleRef = roleBinding.RoleRef
 							existingRoleBinding.Subjects = roleBinding.Subjects
 							existingRoleBinding.SetLabels(roleBinding.GetLabels())
-							if _, err := c.kubeclientset.RbacV1().RoleBindings(childNamespace).Update(context.TODO(), existingRoleBinding, metav1.UpdateOptions{}); err != nil {
+							if _, err := c.kubeclientset.RbacV1().RoleBindings(childNamespace).Update(ctx, existingRoleBinding, metav1.UpdateOptions{}); err != nil {
 								done = false
-								klog.V(4).Infoln(err)
+								klog.ErrorS(err, "Couldn't update the role binding", "roleBinding", klog.KObj(existingRoleBinding))
 							}
 						}
 					}
 				}
 			}
 		}
-		if networkPolicyRaw, err := c.kubeclientset.NetworkingV1().NetworkPolicies(subnamespaceCopy.GetNamespace()).List(context.TODO(), metav1.ListOptions{}); err == nil && subnamespaceCopy.Spec.Workspace.Inheritance["networkpolicy"] {
+		if networkPolicyRaw, err := c.kubeclientset.NetworkingV1().NetworkPolicies(subnamespaceCopy.GetNamespace()).List(ctx, metav1.ListOptions{}); err == nil && subnamespaceCopy.Spec.Workspace.Inheritance["networkpolicy"] {
 			for _, networkPolicyRow := range networkPolicyRaw.Items {
 				networkPolicy := networkPolicyRow.DeepCopy()
 				networkPolicy.SetNamespace(childNamespace)
 				networkPolicy.SetUID(types.UID(uuid.New().String()))
-				if _, err := c.kubeclientset.NetworkingV1().NetworkPolicies(childNamespace).Create(context.TODO(), networkPolicy, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+				if _, err := c.kubeclientset.NetworkingV1().NetworkPolicies(childNamespace).Create(ctx, networkPolicy, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
 					done = false
 				} else if errors.IsAlreadyExists(err) && subnamespaceCopy.Spec.Workspace.Sync {
-					if existingNetworkPolicy, err := c.kubeclientset.NetworkingV1().NetworkPolicies(childNamespace).Get(context.TODO(), networkPolicy.GetName(), metav1.GetOptions{}); err != nil {
+					if existingNetworkPolicy, err := c.kubeclientset.NetworkingV1().NetworkPolicies(childNamespace).Get(ctx, networkPolicy.GetName(), metav1.GetOptions{}); err != nil {
 						done = false
 					} else {
 						if !reflect.DeepEqual(networkPolicy.Spec, existingNetworkPolicy.Spec) || !reflect.DeepEqual(networkPolicy.GetLabels(), existingNetworkPolicy.GetLabels()) {
 							existingNetworkPolicy.Spec = networkPolicy.Spec
 							existingNetworkPolicy.SetLabels(networkPolicy.GetLabels())
-							if _, err := c.kubeclientset.NetworkingV1().NetworkPolicies(childNamespace).Update(context.TODO(), existingNetworkPolicy, metav1.UpdateOptions{}); err != nil {
+							if _, err := c.kubeclientset.NetworkingV1().NetworkPolicies(childNamespace).Update(ctx, existingNetworkPolicy, metav1.UpdateOptions{}); err != nil {
 								done = false
-								klog.V(4).Infoln(err)
+								klog.ErrorS(err, "Couldn't update the network policy", "networkPolicy", klog.KObj(existingNetworkPolicy))
 							}
 						}
 					}
 				}
 			}
 		}
-		if limitRangeRaw, err := c.kubeclientset.CoreV1().LimitRanges(subnamespaceCopy.GetNamespace()).List(context.TODO(), metav1.ListOptions{}); err == nil && subnamespaceCopy.Spec.Workspace.Inheritance["limitrange"] {
+		if limitRangeRaw, err := c.kubeclientset.CoreV1().LimitRanges(subnamespaceCopy.GetNamespace()).List(ctx, metav1.ListOptions{}); err == nil && subnamespaceCopy.Spec.Workspace.Inheritance["limitrange"] {
 			for _, limitRangeRow := range limitRangeRaw.Items {
 				limitRange := limitRangeRow.DeepCopy()
 				limitRange.SetNamespace(childNamespace)
 				limitRange.SetUID(types.UID(uuid.New().String()))
-				if _, err := c.kubeclientset.CoreV1().LimitRanges(childNamespace).Create(context.TODO(), limitRange, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+				if _, err := c.kubeclientset.CoreV1().LimitRanges(childNamespace).Create(ctx, limitRange, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
 					done = false
 				} else if errors.IsAlreadyExists(err) && subnamespaceCopy.Spec.Workspace.Sync {
-					if existingLimitRange, err := c.kubeclientset.CoreV1().LimitRanges(childNamespace).Get(context.TODO(), limitRange.GetName(), metav1.GetOptions{}); err != nil {
+					if existingLimitRange, err := c.kubeclientset.CoreV1().LimitRanges(childNamespace).Get(ctx, limitRange.GetName(), metav1.GetOptions{}); err != nil {
 						done = false
 					} else {
 						if !reflect.DeepEqual(limitRange.Spec, existingLimitRange.Spec) || !reflect.DeepEqual(limitRange.GetLabels(), existingLimitRange.GetLabels()) {
 							existingLimitRange.Spec = limitRange.Spec
 							existingLimitRange.SetLabels(limitRange.GetLabels())
-							if _, err := c.kubeclientset.CoreV1().LimitRanges(childNamespace).Update(context.TODO(), existingLimitRange, metav1.UpdateOptions{}); err != nil {
+							if _, err := c.kubeclientset.CoreV1().LimitRanges(childNamespace).Update(ctx, existingLimitRange, metav1.UpdateOptions{}); err != nil {
 								done = false
-								klog.V(4).Infoln(err)
+								klog.ErrorS(err, "Couldn't update the limit range", "limitRange", klog.KObj(existingLimitRange))
 							}
 						}
 					}
 				}
 			}
 		}
-		if secretRaw, err := c.kubeclientset.CoreV1().Secrets(subnamespaceCopy.GetNamespace()).List(context.TODO(), metav1.ListOptions{}); err == nil && subnamespaceCopy.Spec.Workspace.Inheritance["secret"] {
+		if secretRaw, err := c.kubeclientset.CoreV1().Secrets(subnamespaceCopy.GetNamespace()).List(ctx, metav1.ListOptions{}); err == nil && subnamespaceCopy.Spec.Workspace.Inheritance["secret"] {
 			for _, secretRow := range secretRaw.Items {
+				// The registry credentials are propagated regardless of the inheritance
+				if secretRow.GetLabels()[registryCredentialLabel] == "true" {
+					continue
+				}
 				secret := secretRow.DeepCopy()
 				secret.SetNamespace(childNamespace)
 				secret.SetUID(types.UID(uuid.New().String()))
-				if _, err := c.kubeclientset.CoreV1().Secrets(childNamespace).Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+				if _, err := c.kubeclientset.CoreV1().Secrets(childNamespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
 					done = false
 				} else if errors.IsAlreadyExists(err) && subnamespaceCopy.Spec.Workspace.Sync {
-					if existingSecret, err := c.kubeclientset.CoreV1().Secrets(childNamespace).Get(context.TODO(), secret.GetName(), metav1.GetOptions{}); err != nil {
+					if existingSecret, err := c.kubeclientset.CoreV1().Secrets(childNamespace).Get(ctx, secret.GetName(), metav1.GetOptions{}); err != nil {
 						done = false
 					} else {
 						if !reflect.DeepEqual(secret.Type, existingSecret.Type) || !reflect.DeepEqual(secret.Data, existingSecret.Data) || !reflect.DeepEqual(secret.StringData, existingSecret.StringData) || !reflect.DeepEqual(secret.Immutable, existingSecret.Immutable) || !reflect.DeepEqual(secret.GetLabels(), existingSecret.GetLabels()) {
@@ -886,24 +1097,24 @@ func (c *Controller) handleInheritance(subnamespaceCopy *corev1alpha.SubNamespac
 							existingSecret.StringData = secret.StringData
 							existingSecret.Immutable = secret.Immutable
 							existingSecret.SetLabels(secret.GetLabels())
-							if _, err := c.kubeclientset.CoreV1().Secrets(childNamespace).Update(context.TODO(), existingSecret, metav1.UpdateOptions{}); err != nil {
+							if _, err := c.kubeclientset.CoreV1().Secrets(childNamespace).Update(ctx, existingSecret, metav1.UpdateOptions{}); err != nil {
 								done = false
-								klog.V(4).Infoln(err)
+								klog.ErrorS(err, "Couldn't update the secret", "secret", klog.KObj(existingSecret))
 							}
 						}
 					}
 				}
 			}
 		}
-		if configMapRaw, err := c.kubeclientset.CoreV1().ConfigMaps(subnamespaceCopy.GetNamespace()).List(context.TODO(), metav1.ListOptions{}); err == nil && subnamespaceCopy.Spec.Workspace.Inheritance["configmap"] {
+		if configMapRaw, err := c.kubeclientset.CoreV1().ConfigMaps(subnamespaceCopy.GetNamespace()).List(ctx, metav1.ListOptions{}); err == nil && subnamespaceCopy.Spec.Workspace.Inheritance["configmap"] {
 			for _, configMapRow := range configMapRaw.Items {
 				configMap := configMapRow.DeepCopy()
 				configMap.SetNamespace(childNamespace)
 				configMap.SetUID(types.UID(uuid.New().String()))
-				if _, err := c.kubeclientset.CoreV1().ConfigMaps(childNamespace).Create(context.TODO(), configMap, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+				if _, err := c.kubeclientset.CoreV1().ConfigMaps(childNamespace).Create(ctx, configMap, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
 					done = false
 				} else if errors.IsAlreadyExists(err) && subnamespaceCopy.Spec.Workspace.Sync {
-					if existingConfigMap, err := c.kubeclientset.CoreV1().ConfigMaps(childNamespace).Get(context.TODO(), configMap.GetName(), metav1.GetOptions{}); err != nil {
+					if existingConfigMap, err := c.kubeclientset.CoreV1().ConfigMaps(childNamespace).Get(ctx, configMap.GetName(), metav1.GetOptions{}); err != nil {
 						done = false
 					} else {
 						if !reflect.DeepEqual(configMap.BinaryData, existingConfigMap.BinaryData) || !reflect.DeepEqual(configMap.Data, existingConfigMap.Data) || !reflect.DeepEqual(configMap.Immutable, existingConfigMap.Immutable) || !reflect.DeepEqual(configMap.GetLabels(), existingConfigMap.GetLabels()) {
@@ -911,24 +1122,24 @@ func (c *Controller) handleInheritance(subnamespaceCopy *corev1alpha.SubNamespac
 							existingConfigMap.Data = configMap.Data
 							existingConfigMap.Immutable = configMap.Immutable
 							existingConfigMap.SetLabels(configMap.GetLabels())
-							if _, err := c.kubeclientset.CoreV1().ConfigMaps(childNamespace).Update(context.TODO(), existingConfigMap, metav1.UpdateOptions{}); err != nil {
+							if _, err := c.kubeclientset.CoreV1().ConfigMaps(childNamespace).Update(ctx, existingConfigMap, metav1.UpdateOptions{}); err != nil {
 								done = false
-								klog.V(4).Infoln(err)
+								klog.ErrorS(err, "Couldn't update the config map", "configMap", klog.KObj(existingConfigMap))
 							}
 						}
 					}
 				}
 			}
 		}
-		if serviceAccountRaw, err := c.kubeclientset.CoreV1().ServiceAccounts(subnamespaceCopy.GetNamespace()).List(context.TODO(), metav1.ListOptions{}); err == nil && subnamespaceCopy.Spec.Workspace.Inheritance["serviceaccount"] {
+		if serviceAccountRaw, err := c.kubeclientset.CoreV1().ServiceAccounts(subnamespaceCopy.GetNamespace()).List(ctx, metav1.ListOptions{}); err == nil && subnamespaceCopy.Spec.Workspace.Inheritance["serviceaccount"] {
 			for _, serviceAccountRow := range serviceAccountRaw.Items {
 				serviceAccount := serviceAccountRow.DeepCopy()
 				serviceAccount.SetNamespace(childNamespace)
 				serviceAccount.SetUID(types.UID(uuid.New().String()))
-				if _, err := c.kubeclientset.CoreV1().ServiceAccounts(childNamespace).Create(context.TODO(), serviceAccount, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+				if _, err := c.kubeclientset.CoreV1().ServiceAccounts(childNamespace).Create(ctx, serviceAccount, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
 					done = false
 				} else if errors.IsAlreadyExists(err) && subnamespaceCopy.Spec.Workspace.Sync {
-					if existingServiceAccount, err := c.kubeclientset.CoreV1().ServiceAccounts(childNamespace).Get(context.TODO(), serviceAccount.GetName(), metav1.GetOptions{}); err != nil {
+					if existingServiceAccount, err := c.kubeclientset.CoreV1().ServiceAccounts(childNamespace).Get(ctx, serviceAccount.GetName(), metav1.GetOptions{}); err != nil {
 						done = false
 					} else {
 						if !reflect.DeepEqual(serviceAccount.AutomountServiceAccountToken, existingServiceAccount.AutomountServiceAccountToken) || !reflect.DeepEqual(serviceAccount.ImagePullSecrets, existingServiceAccount.ImagePullSecrets) || !reflect.DeepEqual(serviceAccount.Secrets, existingServiceAccount.Secrets) || !reflect.DeepEqual(serviceAccount.GetLabels(), existingServiceAccount.GetLabels()) {
@@ -936,9 +1147,9 @@ func (c *Controller) handleInheritance(subnamespaceCopy *corev1alpha.SubNamespac
 							existingServiceAccount.ImagePullSecrets = serviceAccount.ImagePullSecrets
 							existingServiceAccount.Secrets = serviceAccount.Secrets
 							existingServiceAccount.SetLabels(serviceAccount.GetLabels())
-							if _, err := c.kubeclientset.CoreV1().ServiceAccounts(childNamespace).Update(context.TODO(), existingServiceAccount, metav1.UpdateOptions{}); err != nil {
+							if _, err := c.kubeclientset.CoreV1().ServiceAccounts(childNamespace).Update(ctx, existingServiceAccount, metav1.UpdateOptions{}); err != nil {
 								done = false
-								klog.V(4).Infoln(err)
+								klog.ErrorS(err, "Couldn't update the service account", "serviceAccount", klog.KObj(existingServiceAccount))
 							}
 						}
 					}
@@ -2,6 +2,10 @@ package subnamespace
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"flag"
 	"fmt"
 	"io/ioutil"
@@ -11,13 +15,16 @@ import (
 	"time"
 
 	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/config"
 	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
 	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
 	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
 	"github.com/EdgeNet-project/edgenet/pkg/signals"
+	"github.com/EdgeNet-project/edgenet/pkg/signature"
 	"github.com/EdgeNet-project/edgenet/pkg/util"
 	"github.com/sirupsen/logrus"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -27,7 +34,9 @@ import (
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	testclient "k8s.io/client-go/kubernetes/fake"
-	"k8s.io/klog"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
 )
 
 // The main structure of test group
@@ -38,6 +47,9 @@ type TestGroup struct {
 	subNamespaceObj  corev1alpha.SubNamespace
 }
 
+// clusterUID is the UID of the kube-system namespace, the cluster identity is seeded from it
+const clusterUID = "kube-system-uid"
+
 var kubeclientset kubernetes.Interface = testclient.NewSimpleClientset()
 var edgenetclientset versioned.Interface = edgenettestclient.NewSimpleClientset()
 
@@ -54,7 +66,8 @@ func TestMain(m *testing.M) {
 	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeclientset, 0)
 	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, 0)
 
-	controller := NewController(kubeclientset,
+	controller := NewController(signals.ContextFor(stopCh),
+		kubeclientset,
 		edgenetclientset,
 		kubeInformerFactory.Rbac().V1().Roles(),
 		kubeInformerFactory.Rbac().V1().RoleBindings(),
@@ -73,7 +86,7 @@ func TestMain(m *testing.M) {
 		}
 	}()
 
-	kubeSystemNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}}
+	kubeSystemNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", UID: clusterUID}}
 	kubeclientset.CoreV1().Namespaces().Create(context.TODO(), kubeSystemNamespace, metav1.CreateOptions{})
 
 	time.Sleep(500 * time.Millisecond)
@@ -188,6 +201,9 @@ func (g *TestGroup) Init() {
 		kubeclientset.RbacV1().Roles(tenantCoreNamespace.GetName()).Create(context.TODO(), &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: "edgenet-test"}}, metav1.CreateOptions{})
 		kubeclientset.RbacV1().RoleBindings(tenantCoreNamespace.GetName()).Create(context.TODO(), &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "edgenet-test"}}, metav1.CreateOptions{})
 		kubeclientset.NetworkingV1().NetworkPolicies(tenantCoreNamespace.GetName()).Create(context.TODO(), &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "edgenet-test"}}, metav1.CreateOptions{})
+		tenantLimitRange := &corev1.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: "tenant-limits", Labels: map[string]string{"edge-net.io/generated": "true"}}}
+		tenantLimitRange.Spec.Limits = []corev1.LimitRangeItem{{Type: corev1.LimitTypeContainer, Max: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}}}
+		kubeclientset.CoreV1().LimitRanges(tenantCoreNamespace.GetName()).Create(context.TODO(), tenantLimitRange, metav1.CreateOptions{})
 	}
 }
 
@@ -203,7 +219,7 @@ func TestStartController(t *testing.T) {
 	// Create a subnamespace
 	subNamespaceControllerTest := g.subNamespaceObj.DeepCopy()
 	subNamespaceControllerTest.SetName("subnamespace-controller")
-	childName, _ := subNamespaceControllerTest.GenerateChildName("")
+	childName, _ := subNamespaceControllerTest.GenerateChildName(clusterUID)
 	_, err = edgenetclientset.CoreV1alpha().SubNamespaces(g.tenantObj.GetName()).Create(context.TODO(), subNamespaceControllerTest, metav1.CreateOptions{})
 	util.OK(t, err)
 	// Wait for the status update of the created object
@@ -240,7 +256,7 @@ func TestStartController(t *testing.T) {
 	subNamespaceControllerNestedTest.Spec.Workspace.ResourceAllocation["memory"] = resource.MustParse("1Gi")
 	subNamespaceControllerNestedTest.SetName("subnamespace-controller-nested")
 	subNamespaceControllerNestedTest.SetNamespace(childName)
-	nestedChildName, _ := subNamespaceControllerNestedTest.GenerateChildName("")
+	nestedChildName, _ := subNamespaceControllerNestedTest.GenerateChildName(clusterUID)
 	_, err = edgenetclientset.CoreV1alpha().SubNamespaces(subNamespaceControllerNestedTest.GetNamespace()).Create(context.TODO(), subNamespaceControllerNestedTest, metav1.CreateOptions{})
 	util.OK(t, err)
 	// Wait for the status update of the created object
@@ -288,30 +304,30 @@ func TestCreate(t *testing.T) {
 	subnamespace1.SetName("all")
 	subnamespace1.Spec.Workspace.ResourceAllocation["cpu"] = resource.MustParse("2000m")
 	subnamespace1.Spec.Workspace.ResourceAllocation["memory"] = resource.MustParse("2Gi")
-	childName1, _ := subnamespace1.GenerateChildName("")
+	childName1, _ := subnamespace1.GenerateChildName(clusterUID)
 	subnamespace1nested := g.subNamespaceObj.DeepCopy()
 	subnamespace1nested.SetName("all-nested")
 	subnamespace1nested.Spec.Workspace.ResourceAllocation["cpu"] = resource.MustParse("1000m")
 	subnamespace1nested.Spec.Workspace.ResourceAllocation["memory"] = resource.MustParse("1Gi")
 	subnamespace1nested.SetNamespace(childName1)
-	childName1nested, _ := subnamespace1nested.GenerateChildName("")
+	childName1nested, _ := subnamespace1nested.GenerateChildName(clusterUID)
 	subnamespace2 := g.subNamespaceObj.DeepCopy()
 	subnamespace2.SetName("rbac")
 	subnamespace2.Spec.Workspace.Inheritance["networkpolicy"] = false
 	subnamespace2.Spec.Workspace.ResourceAllocation["cpu"] = resource.MustParse("1000m")
 	subnamespace2.Spec.Workspace.ResourceAllocation["memory"] = resource.MustParse("1Gi")
-	childName2, _ := subnamespace2.GenerateChildName("")
+	childName2, _ := subnamespace2.GenerateChildName(clusterUID)
 	subnamespace3 := g.subNamespaceObj.DeepCopy()
 	subnamespace3.SetName("networkpolicy")
 	subnamespace3.Spec.Workspace.Inheritance["rbac"] = false
 	subnamespace3.Spec.Workspace.ResourceAllocation["cpu"] = resource.MustParse("1000m")
 	subnamespace3.Spec.Workspace.ResourceAllocation["memory"] = resource.MustParse("1Gi")
-	childName3, _ := subnamespace3.GenerateChildName("")
+	childName3, _ := subnamespace3.GenerateChildName(clusterUID)
 	subnamespace4 := g.subNamespaceObj.DeepCopy()
 	subnamespace4.SetName("expiry")
 	subnamespace4.Spec.Workspace.ResourceAllocation["cpu"] = resource.MustParse("1000m")
 	subnamespace4.Spec.Workspace.ResourceAllocation["memory"] = resource.MustParse("1Gi")
-	childName4, _ := subnamespace4.GenerateChildName("")
+	childName4, _ := subnamespace4.GenerateChildName(clusterUID)
 
 	t.Run("inherit all without expiry date", func(t *testing.T) {
 		defer edgenetclientset.CoreV1alpha().SubNamespaces(g.tenantObj.GetName()).Delete(context.TODO(), subnamespace1.GetName(), metav1.DeleteOptions{})
@@ -328,6 +344,12 @@ func TestCreate(t *testing.T) {
 			util.Equals(t, int64(6442450944), coreResourceQuota.Spec.Hard.Memory().Value())
 		})
 
+		t.Run("check tenant limit range", func(t *testing.T) {
+			// Propagated even though the limit range inheritance is off
+			limitRange, err := kubeclientset.CoreV1().LimitRanges(childNamespace.GetName()).Get(context.TODO(), "tenant-limits", metav1.GetOptions{})
+			util.OK(t, err)
+			util.Equals(t, int64(2), limitRange.Spec.Limits[0].Max.Cpu().Value())
+		})
 		t.Run("check sub resource quota", func(t *testing.T) {
 			subResourceQuota, _ := kubeclientset.CoreV1().ResourceQuotas(childNamespace.GetName()).Get(context.TODO(), "sub-quota", metav1.GetOptions{})
 			util.Equals(t, int64(2), subResourceQuota.Spec.Hard.Cpu().Value())
@@ -415,6 +437,10 @@ func TestCreate(t *testing.T) {
 				util.Equals(t, true, errors.IsNotFound(err))
 			}
 		}
+		// The services are bound whatever the inheritance
+		roleBinding, err := kubeclientset.RbacV1().RoleBindings(childNamespace.GetName()).Get(context.TODO(), "edgenet:service:extensionrequest", metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, "edgenet:service:extensionrequest:namespaced", roleBinding.RoleRef.Name)
 		if networkPolicyRaw, err := kubeclientset.NetworkingV1().NetworkPolicies(subnamespace3.GetNamespace()).List(context.TODO(), metav1.ListOptions{}); err == nil && subnamespace3.Spec.Workspace.Inheritance["networkpolicy"] {
 			for _, networkPolicyRow := range networkPolicyRaw.Items {
 				_, err := kubeclientset.NetworkingV1().NetworkPolicies(childNamespace.GetName()).Get(context.TODO(), networkPolicyRow.GetName(), metav1.GetOptions{})
@@ -437,21 +463,68 @@ func TestCreate(t *testing.T) {
 	})
 }
 
+func TestLinkage(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	parent := g.subNamespaceObj.DeepCopy()
+	parent.SetName("linkage")
+	parent.Spec.Workspace.ResourceAllocation["cpu"] = resource.MustParse("1000m")
+	parent.Spec.Workspace.ResourceAllocation["memory"] = resource.MustParse("1Gi")
+	childName, _ := parent.GenerateChildName(clusterUID)
+	child := g.subNamespaceObj.DeepCopy()
+	child.SetName("linkage-nested")
+	child.SetNamespace(childName)
+	child.Spec.Workspace.ResourceAllocation["cpu"] = resource.MustParse("500m")
+	child.Spec.Workspace.ResourceAllocation["memory"] = resource.MustParse("512Mi")
+	nestedChildName, _ := child.GenerateChildName(clusterUID)
+
+	defer edgenetclientset.CoreV1alpha().SubNamespaces(g.tenantObj.GetName()).Delete(context.TODO(), parent.GetName(), metav1.DeleteOptions{})
+	_, err := edgenetclientset.CoreV1alpha().SubNamespaces(g.tenantObj.GetName()).Create(context.TODO(), parent, metav1.CreateOptions{})
+	util.OK(t, err)
+	time.Sleep(450 * time.Millisecond)
+	_, err = edgenetclientset.CoreV1alpha().SubNamespaces(childName).Create(context.TODO(), child, metav1.CreateOptions{})
+	util.OK(t, err)
+	time.Sleep(450 * time.Millisecond)
+
+	nested, err := edgenetclientset.CoreV1alpha().SubNamespaces(childName).Get(context.TODO(), child.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, nestedChildName, nested.Status.Child)
+	util.Equals(t, &corev1alpha.SubNamespaceReference{Namespace: g.tenantObj.GetName(), Name: parent.GetName()}, nested.Status.Parent)
+	util.Equals(t, 2, nested.Status.Depth)
+
+	top, err := edgenetclientset.CoreV1alpha().SubNamespaces(g.tenantObj.GetName()).Get(context.TODO(), parent.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, childName, top.Status.Child)
+	util.Equals(t, true, top.Status.Parent == nil)
+	util.Equals(t, 1, top.Status.Depth)
+	util.Equals(t, []corev1alpha.SubNamespaceReference{{Namespace: childName, Name: child.GetName()}}, top.Status.Children)
+
+	t.Run("child deleted", func(t *testing.T) {
+		err := edgenetclientset.CoreV1alpha().SubNamespaces(childName).Delete(context.TODO(), child.GetName(), metav1.DeleteOptions{})
+		util.OK(t, err)
+		time.Sleep(450 * time.Millisecond)
+		top, err := edgenetclientset.CoreV1alpha().SubNamespaces(g.tenantObj.GetName()).Get(context.TODO(), parent.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, 0, len(top.Status.Children))
+	})
+}
+
 func TestQuota(t *testing.T) {
 	g := TestGroup{}
 	g.Init()
 
 	subnamespace1 := g.subNamespaceObj.DeepCopy()
 	subnamespace1.SetName("all")
-	childName1, _ := subnamespace1.GenerateChildName("")
+	childName1, _ := subnamespace1.GenerateChildName(clusterUID)
 	subnamespace2 := g.subNamespaceObj.DeepCopy()
 	subnamespace2.SetName("rbac")
 	subnamespace2.Spec.Workspace.Inheritance["networkpolicy"] = false
-	childName2, _ := subnamespace2.GenerateChildName("")
+	childName2, _ := subnamespace2.GenerateChildName(clusterUID)
 	subnamespace3 := g.subNamespaceObj.DeepCopy()
 	subnamespace3.SetName("networkpolicy")
 	subnamespace3.Spec.Workspace.Inheritance["rbac"] = false
-	childName3, _ := subnamespace3.GenerateChildName("")
+	childName3, _ := subnamespace3.GenerateChildName(clusterUID)
 
 	_, err := edgenetclientset.CoreV1alpha().SubNamespaces(g.tenantObj.GetName()).Create(context.TODO(), subnamespace1, metav1.CreateOptions{})
 	util.OK(t, err)
@@ -471,3 +544,284 @@ func TestQuota(t *testing.T) {
 	_, err = kubeclientset.CoreV1().Namespaces().Get(context.TODO(), childName3, metav1.GetOptions{})
 	util.Equals(t, true, errors.IsNotFound(err))
 }
+
+func TestClone(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	source := g.subNamespaceObj.DeepCopy()
+	source.SetName("clone-source")
+	source.Spec.Workspace.ResourceAllocation["cpu"] = resource.MustParse("500m")
+	source.Spec.Workspace.ResourceAllocation["memory"] = resource.MustParse("512Mi")
+	sourceName, err := source.GenerateChildName(clusterUID)
+	util.OK(t, err)
+	target := source.DeepCopy()
+	target.SetName("clone-copy-target")
+	target.Spec.Workspace.Clone = &corev1alpha.Clone{Source: source.GetName()}
+	targetName, err := target.GenerateChildName(clusterUID)
+	util.OK(t, err)
+	// The copy would be the source itself
+	util.Assert(t, sourceName != targetName, "source and target share the child namespace")
+	defer edgenetclientset.CoreV1alpha().SubNamespaces(g.tenantObj.GetName()).Delete(context.TODO(), source.GetName(), metav1.DeleteOptions{})
+	defer edgenetclientset.CoreV1alpha().SubNamespaces(g.tenantObj.GetName()).Delete(context.TODO(), target.GetName(), metav1.DeleteOptions{})
+
+	_, err = edgenetclientset.CoreV1alpha().SubNamespaces(g.tenantObj.GetName()).Create(context.TODO(), source, metav1.CreateOptions{})
+	util.OK(t, err)
+	time.Sleep(450 * time.Millisecond)
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "experiment", UID: "source-uid"}}
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: "app", Image: "nginx",
+		Env: []corev1.EnvVar{{Name: "BACKEND", Value: fmt.Sprintf("backend.%s.svc.cluster.local", sourceName)}}}}
+	kubeclientset.AppsV1().Deployments(sourceName).Create(context.TODO(), deployment, metav1.CreateOptions{})
+	service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "backend"}, Spec: corev1.ServiceSpec{ClusterIP: "10.96.0.10"}}
+	kubeclientset.CoreV1().Services(sourceName).Create(context.TODO(), service, metav1.CreateOptions{})
+	kubeclientset.CoreV1().ConfigMaps(sourceName).Create(context.TODO(), &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings"}}, metav1.CreateOptions{})
+	kubeclientset.CoreV1().Secrets(sourceName).Create(context.TODO(), &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "credentials"}}, metav1.CreateOptions{})
+
+	_, err = edgenetclientset.CoreV1alpha().SubNamespaces(g.tenantObj.GetName()).Create(context.TODO(), target, metav1.CreateOptions{})
+	util.OK(t, err)
+	time.Sleep(450 * time.Millisecond)
+	subnamespace, err := edgenetclientset.CoreV1alpha().SubNamespaces(g.tenantObj.GetName()).Get(context.TODO(), target.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, true, subnamespace.Status.Cloned)
+
+	t.Run("name rewriting", func(t *testing.T) {
+		deploymentCopy, err := kubeclientset.AppsV1().Deployments(targetName).Get(context.TODO(), deployment.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, fmt.Sprintf("backend.%s.svc.cluster.local", targetName), deploymentCopy.Spec.Template.Spec.Containers[0].Env[0].Value)
+		util.Assert(t, deploymentCopy.GetUID() != deployment.GetUID(), "UID is copied from the source")
+	})
+	t.Run("service", func(t *testing.T) {
+		serviceCopy, err := kubeclientset.CoreV1().Services(targetName).Get(context.TODO(), service.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, "", serviceCopy.Spec.ClusterIP)
+	})
+	t.Run("config map", func(t *testing.T) {
+		_, err := kubeclientset.CoreV1().ConfigMaps(targetName).Get(context.TODO(), "settings", metav1.GetOptions{})
+		util.OK(t, err)
+	})
+	t.Run("secret not flagged", func(t *testing.T) {
+		_, err := kubeclientset.CoreV1().Secrets(targetName).Get(context.TODO(), "credentials", metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+	})
+	t.Run("source not found", func(t *testing.T) {
+		orphan := target.DeepCopy()
+		orphan.SetName("clone-orphan-missing")
+		orphan.Spec.Workspace.Clone.Source = "missing"
+		defer edgenetclientset.CoreV1alpha().SubNamespaces(g.tenantObj.GetName()).Delete(context.TODO(), orphan.GetName(), metav1.DeleteOptions{})
+		edgenetclientset.CoreV1alpha().SubNamespaces(g.tenantObj.GetName()).Create(context.TODO(), orphan, metav1.CreateOptions{})
+		time.Sleep(450 * time.Millisecond)
+		subnamespace, err := edgenetclientset.CoreV1alpha().SubNamespaces(g.tenantObj.GetName()).Get(context.TODO(), orphan.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, messageCloneSourceNotFound, subnamespace.Status.Message)
+	})
+}
+
+func TestRewriteNamespace(t *testing.T) {
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "experiment", Namespace: "20", UID: "source-uid",
+		Labels: map[string]string{"release": "20"}}}
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: "app", Image: "nginx:1.20",
+		Env: []corev1.EnvVar{
+			{Name: "BACKEND", Value: "backend.20.svc.cluster.local"},
+			{Name: "CACHE", Value: "cache.20.svc:6379"},
+			{Name: "WORKERS", Value: "20"},
+		}}}
+
+	copy := new(appsv1.Deployment)
+	util.OK(t, rewriteNamespace(deployment, copy, "20", "25"))
+	util.Equals(t, "25", copy.GetNamespace())
+	util.Equals(t, "", string(copy.GetUID()))
+	util.Equals(t, "nginx:1.20", copy.Spec.Template.Spec.Containers[0].Image)
+	util.Equals(t, []corev1.EnvVar{
+		{Name: "BACKEND", Value: "backend.25.svc.cluster.local"},
+		{Name: "CACHE", Value: "cache.25.svc:6379"},
+		{Name: "WORKERS", Value: "20"},
+	}, copy.Spec.Template.Spec.Containers[0].Env)
+	util.Equals(t, map[string]string{"release": "20"}, copy.GetLabels())
+	// The source is left as is
+	util.Equals(t, "backend.20.svc.cluster.local", deployment.Spec.Template.Spec.Containers[0].Env[0].Value)
+}
+
+func TestRegistryCredentials(t *testing.T) {
+	c := &Controller{kubeclientset: testclient.NewSimpleClientset()}
+	subnamespace := &corev1alpha.SubNamespace{ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "edgenet"}}
+	for _, namespace := range []string{"edgenet", "edgenet-registry"} {
+		serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: namespace}}
+		if namespace == "edgenet" {
+			serviceAccount.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "own"}}
+		}
+		_, err := c.kubeclientset.CoreV1().ServiceAccounts(namespace).Create(context.TODO(), serviceAccount, metav1.CreateOptions{})
+		util.OK(t, err)
+	}
+	credential := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "edgenet", Labels: map[string]string{registryCredentialLabel: "true"}},
+		Type: corev1.SecretTypeDockerConfigJson, Data: map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{"auths":{}}`)}}
+	_, err := c.kubeclientset.CoreV1().Secrets("edgenet").Create(context.TODO(), credential, metav1.CreateOptions{})
+	util.OK(t, err)
+	imagePullSecrets := func(namespace string) []corev1.LocalObjectReference {
+		serviceAccount, err := c.kubeclientset.CoreV1().ServiceAccounts(namespace).Get(context.TODO(), "default", metav1.GetOptions{})
+		util.OK(t, err)
+		return serviceAccount.ImagePullSecrets
+	}
+
+	util.Equals(t, true, c.propagateRegistryCredentials(context.TODO(), subnamespace, "edgenet-registry"))
+	replica, err := c.kubeclientset.CoreV1().Secrets("edgenet-registry").Get(context.TODO(), "registry", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, credential.Data, replica.Data)
+	util.Equals(t, corev1.SecretTypeDockerConfigJson, replica.Type)
+	util.Equals(t, []corev1.LocalObjectReference{{Name: "own"}, {Name: "registry"}}, imagePullSecrets("edgenet"))
+	util.Equals(t, []corev1.LocalObjectReference{{Name: "registry"}}, imagePullSecrets("edgenet-registry"))
+
+	t.Run("rotation", func(t *testing.T) {
+		credential.Data[corev1.DockerConfigJsonKey] = []byte(`{"auths":{"registry.edge-net.io":{}}}`)
+		_, err := c.kubeclientset.CoreV1().Secrets("edgenet").Update(context.TODO(), credential, metav1.UpdateOptions{})
+		util.OK(t, err)
+		util.Equals(t, true, c.propagateRegistryCredentials(context.TODO(), subnamespace, "edgenet-registry"))
+		replica, err := c.kubeclientset.CoreV1().Secrets("edgenet-registry").Get(context.TODO(), "registry", metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, credential.Data, replica.Data)
+	})
+	t.Run("deregistration", func(t *testing.T) {
+		util.OK(t, c.kubeclientset.CoreV1().Secrets("edgenet").Delete(context.TODO(), "registry", metav1.DeleteOptions{}))
+		util.Equals(t, true, c.propagateRegistryCredentials(context.TODO(), subnamespace, "edgenet-registry"))
+		_, err := c.kubeclientset.CoreV1().Secrets("edgenet-registry").Get(context.TODO(), "registry", metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+		util.Equals(t, []corev1.LocalObjectReference{{Name: "own"}}, imagePullSecrets("edgenet"))
+		util.Equals(t, 0, len(imagePullSecrets("edgenet-registry")))
+	})
+}
+
+func TestLending(t *testing.T) {
+	workspace := func(name, allocation string, lending *corev1alpha.QuotaLending) *corev1alpha.SubNamespace {
+		return &corev1alpha.SubNamespace{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "lip6-lab"},
+			Spec: corev1alpha.SubNamespaceSpec{Workspace: &corev1alpha.Workspace{
+				ResourceAllocation: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(allocation)}, Scope: "local", Lending: lending}}}
+	}
+	lab := workspace("lab", "4", &corev1alpha.QuotaLending{Lend: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}})
+	course := workspace("course", "1", &corev1alpha.QuotaLending{Borrow: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}})
+	subQuota := func(subnamespace *corev1alpha.SubNamespace, used string) *corev1.ResourceQuota {
+		childName, err := subnamespace.GenerateChildName(clusterUID)
+		util.OK(t, err)
+		return &corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: "sub-quota", Namespace: childName},
+			Status: corev1.ResourceQuotaStatus{Used: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(used)}}}
+	}
+	c := &Controller{
+		kubeclientset:    testclient.NewSimpleClientset(subQuota(lab, "1"), subQuota(course, "1")),
+		edgenetclientset: edgenettestclient.NewSimpleClientset(lab, course),
+		workqueue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "SubNamespaces"),
+		recorder:         record.NewFakeRecorder(10),
+	}
+	defer c.workqueue.ShutDown()
+	setUsage := func(subnamespace *corev1alpha.SubNamespace, used string) {
+		_, err := c.kubeclientset.CoreV1().ResourceQuotas(subQuota(subnamespace, used).GetNamespace()).UpdateStatus(context.TODO(), subQuota(subnamespace, used), metav1.UpdateOptions{})
+		util.OK(t, err)
+	}
+	settle := func() (*corev1alpha.SubNamespace, *corev1alpha.SubNamespace) {
+		courseCopy, err := c.edgenetclientset.CoreV1alpha().SubNamespaces("lip6-lab").Get(context.TODO(), "course", metav1.GetOptions{})
+		util.OK(t, err)
+		c.settleLoans(context.TODO(), courseCopy, clusterUID)
+		_, err = c.edgenetclientset.CoreV1alpha().SubNamespaces("lip6-lab").UpdateStatus(context.TODO(), courseCopy, metav1.UpdateOptions{})
+		util.OK(t, err)
+		labCopy, err := c.edgenetclientset.CoreV1alpha().SubNamespaces("lip6-lab").Get(context.TODO(), "lab", metav1.GetOptions{})
+		util.OK(t, err)
+		return labCopy, courseCopy
+	}
+	cpu := func(subnamespace *corev1alpha.SubNamespace) string {
+		quantity := loanedQuota(subnamespace)[corev1.ResourceCPU]
+		return quantity.String()
+	}
+
+	// The lab keeps half of the 3 CPUs it leaves unused
+	labCopy, courseCopy := settle()
+	util.Equals(t, "lab", courseCopy.Status.Borrowed[0].Sibling)
+	util.Equals(t, "course", labCopy.Status.Lent[0].Sibling)
+	util.Equals(t, "2500m", cpu(labCopy))
+	util.Equals(t, "2500m", cpu(courseCopy))
+	since := courseCopy.Status.Borrowed[0].Since
+	labCopy, courseCopy = settle()
+	util.Equals(t, "2500m", cpu(courseCopy))
+	util.Equals(t, since, courseCopy.Status.Borrowed[0].Since)
+
+	t.Run("graceful reclaim", func(t *testing.T) {
+		setUsage(lab, "2500m")
+		setUsage(course, "2")
+		// The course gives back the 500m it leaves unused, and the rest as it frees it
+		labCopy, courseCopy := settle()
+		util.Equals(t, "3", cpu(labCopy))
+		util.Equals(t, "2", cpu(courseCopy))
+		setUsage(lab, "3")
+		setUsage(course, "1")
+		labCopy, courseCopy = settle()
+		util.Equals(t, "4", cpu(labCopy))
+		util.Equals(t, "1", cpu(courseCopy))
+		util.Equals(t, 0, len(courseCopy.Status.Borrowed))
+		util.Equals(t, 0, len(labCopy.Status.Lent))
+	})
+	t.Run("immediate reclaim", func(t *testing.T) {
+		setUsage(lab, "1")
+		labCopy, courseCopy := settle()
+		util.Equals(t, "2500m", cpu(courseCopy))
+		labCopy.Spec.Workspace.Lending.ReclaimPolicy = "Immediate"
+		_, err := c.edgenetclientset.CoreV1alpha().SubNamespaces("lip6-lab").Update(context.TODO(), labCopy, metav1.UpdateOptions{})
+		util.OK(t, err)
+		setUsage(lab, "2500m")
+		setUsage(course, "2")
+		labCopy, courseCopy = settle()
+		util.Equals(t, "4", cpu(labCopy))
+		util.Equals(t, "1", cpu(courseCopy))
+	})
+	t.Run("no longer borrowing", func(t *testing.T) {
+		setUsage(lab, "1")
+		setUsage(course, "1")
+		_, courseCopy := settle()
+		util.Equals(t, "2500m", cpu(courseCopy))
+		courseCopy.Spec.Workspace.Lending = nil
+		_, err := c.edgenetclientset.CoreV1alpha().SubNamespaces("lip6-lab").Update(context.TODO(), courseCopy, metav1.UpdateOptions{})
+		util.OK(t, err)
+		labCopy, courseCopy := settle()
+		util.Equals(t, "4", cpu(labCopy))
+		util.Equals(t, "1", cpu(courseCopy))
+	})
+}
+
+func TestSignature(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	util.OK(t, err)
+	privateBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	util.OK(t, err)
+	publicBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+	util.OK(t, err)
+	tenant := &corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "lip6", UID: "lip6-uid"},
+		Spec: corev1alpha.TenantSpec{Enabled: true, RequireSignedRequests: true,
+			SigningKeys: []corev1alpha.SigningKey{{Name: "ci", PublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicBytes}))}}}}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "lip6", Labels: map[string]string{"edge-net.io/kind": "core",
+		"edge-net.io/tenant": "lip6", "edge-net.io/tenant-uid": "lip6-uid", "edge-net.io/cluster-uid": clusterUID}}}
+	kubeclientset := testclient.NewSimpleClientset(namespace, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", UID: clusterUID}})
+	recorder := record.NewFakeRecorder(10)
+	c := &Controller{
+		kubeclientset:    kubeclientset,
+		edgenetclientset: edgenettestclient.NewSimpleClientset(tenant),
+		identity:         config.NewClusterIdentity(kubeclientset),
+		recorder:         recorder,
+	}
+	subnamespace := &corev1alpha.SubNamespace{ObjectMeta: metav1.ObjectMeta{Name: "automation", Namespace: "lip6"},
+		Spec: corev1alpha.SubNamespaceSpec{Workspace: &corev1alpha.Workspace{
+			ResourceAllocation: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}, Scope: "local"}}}
+
+	t.Run("unsigned", func(t *testing.T) {
+		subnamespaceCopy := subnamespace.DeepCopy()
+		c.processSubNamespace(context.TODO(), subnamespaceCopy)
+		util.Equals(t, failure, subnamespaceCopy.Status.State)
+		util.Equals(t, fmt.Sprintf("%s: %v", messageSignatureInvalid, signature.ErrUnsigned), subnamespaceCopy.Status.Message)
+		util.Equals(t, fmt.Sprintf("Warning %s %s", failureSignature, messageSignatureInvalid), <-recorder.Events)
+	})
+	t.Run("tampered", func(t *testing.T) {
+		signer, err := signature.NewSigner("ci", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateBytes}))
+		util.OK(t, err)
+		subnamespaceCopy := subnamespace.DeepCopy()
+		util.OK(t, signer.Sign("SubNamespace", subnamespaceCopy, subnamespaceCopy.Spec))
+		util.OK(t, signature.Verify(tenant, "SubNamespace", subnamespaceCopy, subnamespaceCopy.Spec))
+		subnamespaceCopy.Spec.Workspace.ResourceAllocation[corev1.ResourceCPU] = resource.MustParse("8")
+		c.processSubNamespace(context.TODO(), subnamespaceCopy)
+		util.Equals(t, failure, subnamespaceCopy.Status.State)
+		util.Equals(t, fmt.Sprintf("%s: the signature does not match signing key \"ci\"", messageSignatureInvalid), subnamespaceCopy.Status.Message)
+	})
+}
@@ -0,0 +1,440 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subnamespace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// LendingInterval is how often the loans of the workspaces are settled, the usage of the
+// workspaces changes without the subnamespaces changing
+var LendingInterval = time.Minute
+
+// allocationAnnotation records the allocation of a workspace on its quota, as the hard limits
+// of the quota move away from the allocation with the loans
+const allocationAnnotation = "edge-net.io/allocation"
+
+// Definitions of the events of the loans
+const (
+	successBorrowed        = "Quota Borrowed"
+	messageBorrowed        = "Borrowed %s from %s"
+	successReturned        = "Quota Returned"
+	messageReturned        = "Returned %s to %s"
+	warningReclaimed       = "Quota Reclaimed"
+	messageReclaimed       = "%s reclaimed %s"
+	reclaimPolicyImmediate = "Immediate"
+)
+
+// loanEvent is an event of the ledger to be recorded on the borrower
+type loanEvent struct {
+	borrower  string
+	eventType string
+	reason    string
+	message   string
+}
+
+// ledger holds the loans between the sibling workspaces in a namespace, in milli units
+type ledger struct {
+	workspaces map[string]*corev1alpha.SubNamespace
+	used       map[string]corev1.ResourceList
+	// loans of each borrower by lender
+	loans  map[string]map[string]map[corev1.ResourceName]int64
+	events []loanEvent
+}
+
+// newLedger reads the loans from the status of the borrowers, the loans of lenders that are
+// gone or no longer lend the resource are dropped
+func newLedger(workspaces map[string]*corev1alpha.SubNamespace, used map[string]corev1.ResourceList) *ledger {
+	l := &ledger{workspaces: workspaces, used: used, loans: map[string]map[string]map[corev1.ResourceName]int64{}}
+	for _, borrower := range l.names() {
+		for _, loan := range workspaces[borrower].Status.Borrowed {
+			for name, quantity := range loan.Resources {
+				if lender, ok := workspaces[loan.Sibling]; !ok || lender.Spec.Workspace.Lending == nil || !hasResource(lender.Spec.Workspace.Lending.Lend, name) {
+					l.record(borrower, corev1.EventTypeWarning, warningReclaimed, fmt.Sprintf(messageReclaimed, loan.Sibling, formatResource(name, quantity.MilliValue())))
+					continue
+				}
+				l.add(borrower, loan.Sibling, name, quantity.MilliValue())
+			}
+		}
+	}
+	return l
+}
+
+// settle takes the loans back from the lenders that ran out of their own quota, returns what the
+// borrowers no longer want, and grants what they want from the quota their siblings leave unused
+func (l *ledger) settle() {
+	reclaimed := map[string]map[corev1.ResourceName]bool{}
+	for _, lender := range l.names() {
+		reclaimed[lender] = map[corev1.ResourceName]bool{}
+		for _, name := range sortedNames(l.lent(lender)) {
+			if l.free(lender, name) > 0 {
+				continue
+			}
+			reclaimed[lender][name] = true
+			for _, borrower := range l.names() {
+				amount := l.loans[borrower][lender][name]
+				if amount == 0 {
+					continue
+				}
+				if l.workspaces[lender].Spec.Workspace.Lending.ReclaimPolicy != reclaimPolicyImmediate {
+					if free := l.free(borrower, name); free < amount {
+						amount = free
+					}
+				}
+				if amount > 0 {
+					l.add(borrower, lender, name, -amount)
+					l.record(borrower, corev1.EventTypeWarning, warningReclaimed, fmt.Sprintf(messageReclaimed, lender, formatResource(name, amount)))
+				}
+			}
+		}
+	}
+
+	for _, borrower := range l.names() {
+		var borrow map[corev1.ResourceName]resource.Quantity
+		if lending := l.workspaces[borrower].Spec.Workspace.Lending; lending != nil {
+			borrow = lending.Borrow
+		}
+		for _, name := range sortedNames(l.borrowed(borrower)) {
+			wanted := borrow[name]
+			excess := l.borrowed(borrower)[name] - wanted.MilliValue()
+			for _, lender := range l.names() {
+				if excess <= 0 {
+					break
+				}
+				if amount := min(excess, l.loans[borrower][lender][name]); amount > 0 {
+					l.add(borrower, lender, name, -amount)
+					l.record(borrower, corev1.EventTypeNormal, successReturned, fmt.Sprintf(messageReturned, formatResource(name, amount), lender))
+					excess -= amount
+				}
+			}
+		}
+		for _, name := range sortedNames(milliValues(borrow)) {
+			wanted := borrow[name]
+			want := wanted.MilliValue() - l.borrowed(borrower)[name]
+			if !hasResource(l.workspaces[borrower].Spec.Workspace.ResourceAllocation, name) {
+				continue
+			}
+			for _, lender := range l.names() {
+				if want <= 0 {
+					break
+				}
+				if lender == borrower || reclaimed[lender][name] || l.borrowed(lender)[name] > 0 {
+					continue
+				}
+				lending := l.workspaces[lender].Spec.Workspace.Lending
+				if lending == nil || !hasResource(lending.Lend, name) || !hasResource(l.workspaces[lender].Spec.Workspace.ResourceAllocation, name) {
+					continue
+				}
+				offer, lent := lending.Lend[name], l.lent(lender)[name]
+				// The lender lends half of the quota it leaves unused at most
+				lendable := min(offer.MilliValue()-lent, (l.free(lender, name)+lent)/2-lent)
+				if amount := min(want, lendable); amount > 0 {
+					l.add(borrower, lender, name, amount)
+					l.record(borrower, corev1.EventTypeNormal, successBorrowed, fmt.Sprintf(messageBorrowed, formatResource(name, amount), lender))
+					want -= amount
+				}
+			}
+		}
+	}
+}
+
+// status returns the loans of the workspace to be stored in its status, the loans whose amount
+// did not change keep their date
+func (l *ledger) status(workspace string, now metav1.Time) ([]corev1alpha.QuotaLoan, []corev1alpha.QuotaLoan) {
+	status := l.workspaces[workspace].Status
+	borrowed, lent := []corev1alpha.QuotaLoan{}, []corev1alpha.QuotaLoan{}
+	for _, sibling := range l.names() {
+		if loan := l.loan(l.loans[workspace][sibling], sibling, status.Borrowed, now); loan != nil {
+			borrowed = append(borrowed, *loan)
+		}
+		if loan := l.loan(l.loans[sibling][workspace], sibling, status.Lent, now); loan != nil {
+			lent = append(lent, *loan)
+		}
+	}
+	if len(borrowed) == 0 {
+		borrowed = nil
+	}
+	if len(lent) == 0 {
+		lent = nil
+	}
+	return borrowed, lent
+}
+
+func (l *ledger) loan(amounts map[corev1.ResourceName]int64, sibling string, previous []corev1alpha.QuotaLoan, now metav1.Time) *corev1alpha.QuotaLoan {
+	loan := &corev1alpha.QuotaLoan{Sibling: sibling, Resources: map[corev1.ResourceName]resource.Quantity{}, Since: now}
+	for name, amount := range amounts {
+		if amount > 0 {
+			loan.Resources[name] = l.quantity(name, amount)
+		}
+	}
+	if len(loan.Resources) == 0 {
+		return nil
+	}
+	for _, previousLoan := range previous {
+		if previousLoan.Sibling == sibling && sameResources(previousLoan.Resources, loan.Resources) {
+			loan.Since = previousLoan.Since
+		}
+	}
+	return loan
+}
+
+func (l *ledger) add(borrower, lender string, name corev1.ResourceName, amount int64) {
+	if l.loans[borrower] == nil {
+		l.loans[borrower] = map[string]map[corev1.ResourceName]int64{}
+	}
+	if l.loans[borrower][lender] == nil {
+		l.loans[borrower][lender] = map[corev1.ResourceName]int64{}
+	}
+	l.loans[borrower][lender][name] += amount
+	if l.loans[borrower][lender][name] <= 0 {
+		delete(l.loans[borrower][lender], name)
+	}
+}
+
+func (l *ledger) record(borrower, eventType, reason, message string) {
+	l.events = append(l.events, loanEvent{borrower: borrower, eventType: eventType, reason: reason, message: message})
+}
+
+func (l *ledger) borrowed(workspace string) map[corev1.ResourceName]int64 {
+	borrowed := map[corev1.ResourceName]int64{}
+	for _, amounts := range l.loans[workspace] {
+		for name, amount := range amounts {
+			borrowed[name] += amount
+		}
+	}
+	return borrowed
+}
+
+func (l *ledger) lent(workspace string) map[corev1.ResourceName]int64 {
+	lent := map[corev1.ResourceName]int64{}
+	for _, lenders := range l.loans {
+		for name, amount := range lenders[workspace] {
+			lent[name] += amount
+		}
+	}
+	return lent
+}
+
+// free returns the quota of the resource the workspace leaves unused with its loans
+func (l *ledger) free(workspace string, name corev1.ResourceName) int64 {
+	allocation := l.workspaces[workspace].Spec.Workspace.ResourceAllocation[name]
+	used := l.used[workspace][name]
+	return allocation.MilliValue() - l.lent(workspace)[name] + l.borrowed(workspace)[name] - used.MilliValue()
+}
+
+func (l *ledger) quantity(name corev1.ResourceName, amount int64) resource.Quantity {
+	for _, workspace := range l.workspaces {
+		if allocation, ok := workspace.Spec.Workspace.ResourceAllocation[name]; ok {
+			return *resource.NewMilliQuantity(amount, allocation.Format)
+		}
+	}
+	return *resource.NewMilliQuantity(amount, resource.DecimalSI)
+}
+
+func (l *ledger) names() []string {
+	names := []string{}
+	for name := range l.workspaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// settleLoans settles the loans between the workspace and its siblings. The status of the
+// workspace is updated in place, the siblings whose loans change are updated and enqueued to
+// apply their quota.
+func (c *Controller) settleLoans(ctx context.Context, subnamespaceCopy *corev1alpha.SubNamespace, clusterUID string) {
+	c.loans.Lock()
+	defer c.loans.Unlock()
+
+	subnamespaceRaw, err := c.edgenetclientset.CoreV1alpha().SubNamespaces(subnamespaceCopy.GetNamespace()).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Couldn't list the subnamespaces", "namespace", subnamespaceCopy.GetNamespace())
+		return
+	}
+	workspaces := map[string]*corev1alpha.SubNamespace{subnamespaceCopy.GetName(): subnamespaceCopy}
+	lending := isLending(subnamespaceCopy)
+	for i := range subnamespaceRaw.Items {
+		sibling := subnamespaceRaw.Items[i].DeepCopy()
+		if sibling.GetName() != subnamespaceCopy.GetName() && sibling.Spec.Workspace != nil && sibling.Spec.Workspace.ResourceAllocation != nil {
+			workspaces[sibling.GetName()] = sibling
+			lending = lending || isLending(sibling)
+		}
+	}
+	// The workspaces lend nothing unless they opt in
+	if !lending {
+		return
+	}
+	used := map[string]corev1.ResourceList{}
+	for name, workspace := range workspaces {
+		childName, err := workspace.GenerateChildName(clusterUID)
+		if err != nil {
+			continue
+		}
+		if childResourceQuota, err := c.kubeclientset.CoreV1().ResourceQuotas(childName).Get(ctx, "sub-quota", metav1.GetOptions{}); err == nil {
+			used[name] = childResourceQuota.Status.Used
+		}
+	}
+
+	ledger := newLedger(workspaces, used)
+	ledger.settle()
+	now := metav1.Now()
+	for _, name := range ledger.names() {
+		workspace := workspaces[name]
+		borrowed, lent := ledger.status(name, now)
+		if loansEqual(workspace.Status.Borrowed, borrowed) && loansEqual(workspace.Status.Lent, lent) {
+			continue
+		}
+		workspace.Status.Borrowed, workspace.Status.Lent = borrowed, lent
+		if name == subnamespaceCopy.GetName() {
+			continue
+		}
+		if _, err := c.edgenetclientset.CoreV1alpha().SubNamespaces(workspace.GetNamespace()).UpdateStatus(ctx, workspace, metav1.UpdateOptions{}); err != nil {
+			klog.ErrorS(err, "Couldn't update the loans of the subnamespace", "subNamespace", klog.KObj(workspace))
+			continue
+		}
+		c.enqueueSubNamespace(workspace)
+	}
+	for _, event := range ledger.events {
+		c.recorder.Event(workspaces[event.borrower], event.eventType, event.reason, event.message)
+	}
+}
+
+// enqueueSiblings enqueues the workspaces in the namespace of the subnamespace, for them to settle
+// the loans it took part in
+func (c *Controller) enqueueSiblings(subnamespace *corev1alpha.SubNamespace) {
+	if subnamespaceRaw, err := c.subnamespacesLister.SubNamespaces(subnamespace.GetNamespace()).List(labels.Everything()); err == nil {
+		for _, subnamespaceRow := range subnamespaceRaw {
+			if subnamespaceRow.GetName() != subnamespace.GetName() {
+				c.enqueueSubNamespace(subnamespaceRow)
+			}
+		}
+	}
+}
+
+// isLending returns whether the subnamespace takes part in the loans between the workspaces
+func isLending(subnamespace *corev1alpha.SubNamespace) bool {
+	return (subnamespace.Spec.Workspace != nil && subnamespace.Spec.Workspace.Lending != nil) ||
+		len(subnamespace.Status.Borrowed) > 0 || len(subnamespace.Status.Lent) > 0
+}
+
+// loanedQuota returns the allocation of the workspace with the quota it lent taken out, and
+// the quota it borrowed added
+func loanedQuota(subnamespace *corev1alpha.SubNamespace) map[corev1.ResourceName]resource.Quantity {
+	hard := map[corev1.ResourceName]resource.Quantity{}
+	for name, quantity := range subnamespace.Spec.Workspace.ResourceAllocation {
+		hard[name] = quantity.DeepCopy()
+	}
+	for _, loan := range subnamespace.Status.Borrowed {
+		for name, quantity := range loan.Resources {
+			if total, ok := hard[name]; ok {
+				total.Add(quantity)
+				hard[name] = total
+			}
+		}
+	}
+	for _, loan := range subnamespace.Status.Lent {
+		for name, quantity := range loan.Resources {
+			if total, ok := hard[name]; ok {
+				total.Sub(quantity)
+				hard[name] = total
+			}
+		}
+	}
+	return hard
+}
+
+// appliedAllocation returns the allocation the quota of the workspace was applied with
+func appliedAllocation(childResourceQuota *corev1.ResourceQuota) map[corev1.ResourceName]resource.Quantity {
+	if value, ok := childResourceQuota.GetAnnotations()[allocationAnnotation]; ok {
+		allocation := map[corev1.ResourceName]resource.Quantity{}
+		if err := json.Unmarshal([]byte(value), &allocation); err == nil {
+			return allocation
+		}
+	}
+	return childResourceQuota.Spec.Hard
+}
+
+func loansEqual(a, b []corev1alpha.QuotaLoan) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Sibling != b[i].Sibling || !sameResources(a[i].Resources, b[i].Resources) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameResources(a, b map[corev1.ResourceName]resource.Quantity) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, quantity := range a {
+		if other, ok := b[name]; !ok || quantity.Cmp(other) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func hasResource(resources map[corev1.ResourceName]resource.Quantity, name corev1.ResourceName) bool {
+	_, ok := resources[name]
+	return ok
+}
+
+func sortedNames(amounts map[corev1.ResourceName]int64) []corev1.ResourceName {
+	names := []corev1.ResourceName{}
+	for name := range amounts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+func milliValues(resources map[corev1.ResourceName]resource.Quantity) map[corev1.ResourceName]int64 {
+	amounts := map[corev1.ResourceName]int64{}
+	for name, quantity := range resources {
+		amounts[name] = quantity.MilliValue()
+	}
+	return amounts
+}
+
+func min(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func formatResource(name corev1.ResourceName, amount int64) string {
+	quantity := resource.NewMilliQuantity(amount, resource.DecimalSI)
+	return fmt.Sprintf("%s %s", quantity.String(), name)
+}
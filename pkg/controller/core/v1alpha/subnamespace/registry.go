@@ -0,0 +1,188 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package subnamespace
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// registryCredentialLabel marks the image pull secrets that a tenant registers in its core namespace.
+// They are replicated down the subnamespace tree, and the default service accounts pull with them.
+const registryCredentialLabel = "edge-net.io/registry-credential"
+
+// registryCredentialsAnnotation lists the image pull secrets of a default service account that come
+// from the registry credentials, the others are set by the tenant and left untouched
+const registryCredentialsAnnotation = "edge-net.io/registry-credentials"
+
+// handleRegistryCredential attaches the registry credentials to the default service account of their
+// namespace and enqueues the subnamespaces of that namespace, so that a rotation reaches the whole tree.
+// The object is then handled as any other object in a subsidiary namespace.
+func (c *Controller) handleRegistryCredential(obj interface{}) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			secret, _ = tombstone.Obj.(*corev1.Secret)
+		}
+	}
+	if secret != nil && secret.GetLabels()[registryCredentialLabel] == "true" {
+		if err := c.attachRegistryCredentials(c.ctx, secret.GetNamespace()); err != nil {
+			klog.ErrorS(err, "Couldn't attach the registry credentials", "namespace", secret.GetNamespace())
+		}
+		if subnamespaceRaw, err := c.subnamespacesLister.SubNamespaces(secret.GetNamespace()).List(labels.Everything()); err == nil {
+			for _, subnamespaceRow := range subnamespaceRaw {
+				c.enqueueSubNamespace(subnamespaceRow)
+			}
+		}
+	}
+	c.handleObject(obj)
+}
+
+// handleServiceAccount attaches the registry credentials to a default service account as it is
+// created, which happens after its namespace and possibly after the credentials got copied
+func (c *Controller) handleServiceAccount(obj interface{}) {
+	if serviceAccount, ok := obj.(*corev1.ServiceAccount); ok && serviceAccount.GetName() == "default" {
+		if err := c.attachRegistryCredentials(c.ctx, serviceAccount.GetNamespace()); err != nil {
+			klog.ErrorS(err, "Couldn't attach the registry credentials", "namespace", serviceAccount.GetNamespace())
+		}
+	}
+	c.handleObject(obj)
+}
+
+// propagateRegistryCredentials copies the registry credentials of the parent to the child regardless
+// of the inheritance settings, and removes the copies of the credentials the tenant deregistered
+func (c *Controller) propagateRegistryCredentials(ctx context.Context, subnamespaceCopy *corev1alpha.SubNamespace, childNamespace string) bool {
+	selector := metav1.ListOptions{LabelSelector: registryCredentialLabel + "=true"}
+	secretRaw, err := c.kubeclientset.CoreV1().Secrets(subnamespaceCopy.GetNamespace()).List(ctx, selector)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't list the secrets", "namespace", subnamespaceCopy.GetNamespace())
+		return false
+	}
+	done := true
+	registered := map[string]bool{}
+	for _, secretRow := range secretRaw.Items {
+		registered[secretRow.GetName()] = true
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretRow.GetName(), Namespace: childNamespace,
+			Labels: map[string]string{"edge-net.io/generated": "true", registryCredentialLabel: "true"}},
+			Type: secretRow.Type, Data: secretRow.Data}
+		if _, err := c.kubeclientset.CoreV1().Secrets(childNamespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			done = false
+			klog.ErrorS(err, "Couldn't create the secret", "secret", klog.KObj(secret))
+		} else if errors.IsAlreadyExists(err) {
+			if existingSecret, err := c.kubeclientset.CoreV1().Secrets(childNamespace).Get(ctx, secret.GetName(), metav1.GetOptions{}); err != nil {
+				done = false
+			} else if existingSecret.Type != secret.Type {
+				// The type of a secret cannot be changed, the copy is recreated at the next reconcile
+				c.kubeclientset.CoreV1().Secrets(childNamespace).Delete(ctx, secret.GetName(), metav1.DeleteOptions{})
+				done = false
+			} else if !reflect.DeepEqual(secret.Data, existingSecret.Data) || !reflect.DeepEqual(secret.GetLabels(), existingSecret.GetLabels()) {
+				existingSecret.Data = secret.Data
+				existingSecret.SetLabels(secret.GetLabels())
+				if _, err := c.kubeclientset.CoreV1().Secrets(childNamespace).Update(ctx, existingSecret, metav1.UpdateOptions{}); err != nil {
+					done = false
+					klog.ErrorS(err, "Couldn't update the secret", "secret", klog.KObj(existingSecret))
+				}
+			}
+		}
+	}
+	if copyRaw, err := c.kubeclientset.CoreV1().Secrets(childNamespace).List(ctx, selector); err == nil {
+		for _, copyRow := range copyRaw.Items {
+			if !registered[copyRow.GetName()] {
+				if err := c.kubeclientset.CoreV1().Secrets(childNamespace).Delete(ctx, copyRow.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+					done = false
+				}
+			}
+		}
+	} else {
+		done = false
+	}
+	for _, namespace := range []string{subnamespaceCopy.GetNamespace(), childNamespace} {
+		if err := c.attachRegistryCredentials(ctx, namespace); err != nil {
+			done = false
+			klog.ErrorS(err, "Couldn't attach the registry credentials", "namespace", namespace)
+		}
+	}
+	return done
+}
+
+// attachRegistryCredentials sets the registry credentials of the namespace as image pull secrets of
+// its default service account, the service account is left as is until it is created
+func (c *Controller) attachRegistryCredentials(ctx context.Context, namespace string) error {
+	serviceAccount, err := c.kubeclientset.CoreV1().ServiceAccounts(namespace).Get(ctx, "default", metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	secretRaw, err := c.kubeclientset.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{LabelSelector: registryCredentialLabel + "=true"})
+	if err != nil {
+		return err
+	}
+	credentials := []string{}
+	for _, secretRow := range secretRaw.Items {
+		credentials = append(credentials, secretRow.GetName())
+	}
+	sort.Strings(credentials)
+
+	previous := map[string]bool{}
+	if annotation := serviceAccount.GetAnnotations()[registryCredentialsAnnotation]; annotation != "" {
+		for _, name := range strings.Split(annotation, ",") {
+			previous[name] = true
+		}
+	}
+	imagePullSecrets := []corev1.LocalObjectReference{}
+	set := map[string]bool{}
+	for _, imagePullSecret := range serviceAccount.ImagePullSecrets {
+		if !previous[imagePullSecret.Name] {
+			imagePullSecrets = append(imagePullSecrets, imagePullSecret)
+			set[imagePullSecret.Name] = true
+		}
+	}
+	for _, name := range credentials {
+		if !set[name] {
+			imagePullSecrets = append(imagePullSecrets, corev1.LocalObjectReference{Name: name})
+		}
+	}
+	annotation := strings.Join(credentials, ",")
+	unchanged := len(imagePullSecrets) == len(serviceAccount.ImagePullSecrets) && (len(imagePullSecrets) == 0 || reflect.DeepEqual(imagePullSecrets, serviceAccount.ImagePullSecrets))
+	if unchanged && serviceAccount.GetAnnotations()[registryCredentialsAnnotation] == annotation {
+		return nil
+	}
+	serviceAccountCopy := serviceAccount.DeepCopy()
+	serviceAccountCopy.ImagePullSecrets = imagePullSecrets
+	if serviceAccountCopy.Annotations == nil {
+		serviceAccountCopy.Annotations = map[string]string{}
+	}
+	if annotation == "" {
+		delete(serviceAccountCopy.Annotations, registryCredentialsAnnotation)
+	} else {
+		serviceAccountCopy.Annotations[registryCredentialsAnnotation] = annotation
+	}
+	_, err = c.kubeclientset.CoreV1().ServiceAccounts(namespace).Update(ctx, serviceAccountCopy, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Annotations the controller maintains on the namespaces of a tenant. External tools such as
+// backup, cost, or security scanners read them to make tenant-aware decisions without access
+// to the EdgeNet API.
+const (
+	AnnotationTenantState   = "edge-net.io/tenant-state"
+	AnnotationIsolation     = "edge-net.io/isolation"
+	AnnotationQuotaHash     = "edge-net.io/quota-hash"
+	AnnotationLastReconcile = "edge-net.io/last-reconcile"
+	AnnotationPortRange     = "edge-net.io/port-range"
+	// The admission policy enforces the pod security level of the tenant, less the exempted checks
+	AnnotationPodSecurity           = "edge-net.io/pod-security"
+	AnnotationPodSecurityExemptions = "edge-net.io/pod-security-exemptions"
+)
+
+// Values of the isolation annotation
+const (
+	isolationBaseline   = "baseline"
+	isolationPermissive = "permissive"
+)
+
+// isolationMode tells whether the tenant namespaces get the default-deny baseline policies
+func (c *Controller) isolationMode(tenantCopy *corev1alpha.Tenant) string {
+	if c.baselinePolicies && tenantCopy.GetAnnotations()[annotationBaselinePolicies] != "false" {
+		return isolationBaseline
+	}
+	return isolationPermissive
+}
+
+// annotateNamespaces writes the well-known annotations to the core namespace and the
+// subnamespaces of the tenant
+func (c *Controller) annotateNamespaces(ctx context.Context, tenantCopy *corev1alpha.Tenant) error {
+	namespaceRaw, err := c.tenantNamespaces(ctx, tenantCopy.GetName(), nil)
+	if err != nil {
+		return err
+	}
+	isolation := c.isolationMode(tenantCopy)
+	reconciled := time.Now().UTC().Format(time.RFC3339)
+	// The admission policy checks the node ports and the host ports against this annotation
+	portRange, allocated, err := access.LookupPortRange(ctx, tenantCopy.GetName())
+	if err != nil {
+		return err
+	}
+	var annotateErr error
+	for _, namespaceRow := range namespaceRaw {
+		namespace := namespaceRow.DeepCopy()
+		annotations := namespace.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[AnnotationTenantState] = tenantCopy.Status.State
+		annotations[AnnotationIsolation] = isolation
+		annotations[AnnotationLastReconcile] = reconciled
+		if allocated {
+			annotations[AnnotationPortRange] = portRange.String()
+		} else {
+			delete(annotations, AnnotationPortRange)
+		}
+		annotations[AnnotationPodSecurity] = podSecurityLevel(tenantCopy)
+		if exemptions := podSecurityExemptions(tenantCopy); exemptions != "" {
+			annotations[AnnotationPodSecurityExemptions] = exemptions
+		} else {
+			delete(annotations, AnnotationPodSecurityExemptions)
+		}
+		// Each namespace is bound by the quota named after its kind
+		quota, err := c.kubeclientset.CoreV1().ResourceQuotas(namespace.GetName()).Get(ctx, fmt.Sprintf("%s-quota", namespace.GetLabels()["edge-net.io/kind"]), metav1.GetOptions{})
+		if err == nil {
+			annotations[AnnotationQuotaHash] = quotaHash(quota.Spec.Hard)
+		} else {
+			delete(annotations, AnnotationQuotaHash)
+		}
+		namespace.SetAnnotations(annotations)
+		if _, err := c.kubeclientset.CoreV1().Namespaces().Update(ctx, namespace, metav1.UpdateOptions{}); err != nil {
+			annotateErr = err
+		}
+	}
+	return annotateErr
+}
+
+// quotaHash returns a short digest of the hard limits that changes whenever the quota does
+func quotaHash(hard corev1.ResourceList) string {
+	names := []string{}
+	for name := range hard {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	digest := sha256.New()
+	for _, name := range names {
+		quantity := hard[corev1.ResourceName(name)]
+		fmt.Fprintf(digest, "%s=%s\n", name, quantity.String())
+	}
+	return hex.EncodeToString(digest.Sum(nil))[:16]
+}
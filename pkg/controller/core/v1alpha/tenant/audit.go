@@ -0,0 +1,33 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/audit"
+)
+
+// auditEnabled records the tenant getting enabled or disabled in its audit trail
+func (c *Controller) auditEnabled(ctx context.Context, tenant *corev1alpha.Tenant) {
+	action := audit.TenantDisabled
+	if tenant.Spec.Enabled {
+		action = audit.TenantEnabled
+	}
+	object := corev1alpha.TenantAuditObject{Kind: "Tenant", Name: tenant.GetName()}
+	audit.Record(ctx, c.edgenetclientset, tenant.GetName(), action, "", object, "")
+}
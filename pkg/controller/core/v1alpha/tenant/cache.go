@@ -0,0 +1,133 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// informerCache is the cache of the manager running the controller. It hands out the informers of
+// the shared informer factories, so that the watches of the manager and the listers of the
+// controller read the very same objects, which are not cached twice either. The objects are only
+// read through the listers, the cache serves no reads of its own.
+type informerCache struct {
+	kubeInformerFactory    kubeinformers.SharedInformerFactory
+	edgenetInformerFactory informers.SharedInformerFactory
+	options                cache.Options
+
+	mutex sync.Mutex
+	// stopCh is set once the cache starts, the informers asked for later are started at once
+	stopCh <-chan struct{}
+}
+
+// NewCache returns the function that creates the cache of the manager on top of the informer
+// factories, the factories are started along with the manager
+func NewCache(kubeInformerFactory kubeinformers.SharedInformerFactory, edgenetInformerFactory informers.SharedInformerFactory) cache.NewCacheFunc {
+	return func(config *rest.Config, options cache.Options) (cache.Cache, error) {
+		return &informerCache{kubeInformerFactory: kubeInformerFactory, edgenetInformerFactory: edgenetInformerFactory, options: options}, nil
+	}
+}
+
+// GetInformer returns the informer of the kind of the object
+func (c *informerCache) GetInformer(ctx context.Context, obj client.Object) (cache.Informer, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.options.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetInformerForKind(ctx, gvk)
+}
+
+// GetInformerForKind returns the informer of the kind from the factory of its group
+func (c *informerCache) GetInformerForKind(ctx context.Context, gvk schema.GroupVersionKind) (cache.Informer, error) {
+	mapping, err := c.options.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, err
+	}
+	var informer toolscache.SharedIndexInformer
+	if genericInformer, err := c.edgenetInformerFactory.ForResource(mapping.Resource); err == nil {
+		informer = genericInformer.Informer()
+	} else if genericInformer, err := c.kubeInformerFactory.ForResource(mapping.Resource); err == nil {
+		informer = genericInformer.Informer()
+	} else {
+		return nil, fmt.Errorf("no informer for %s", mapping.Resource)
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.stopCh != nil {
+		c.start()
+	}
+	return informer, nil
+}
+
+// Start starts the informers of the factories and blocks until the context is done
+func (c *informerCache) Start(ctx context.Context) error {
+	c.mutex.Lock()
+	c.stopCh = ctx.Done()
+	c.start()
+	c.mutex.Unlock()
+	<-ctx.Done()
+	return nil
+}
+
+// start starts the informers that are not running yet
+func (c *informerCache) start() {
+	c.kubeInformerFactory.Start(c.stopCh)
+	c.edgenetInformerFactory.Start(c.stopCh)
+}
+
+// WaitForCacheSync waits for the informers of both factories to sync
+func (c *informerCache) WaitForCacheSync(ctx context.Context) bool {
+	for _, synced := range c.kubeInformerFactory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return false
+		}
+	}
+	for _, synced := range c.edgenetInformerFactory.WaitForCacheSync(ctx.Done()) {
+		if !synced {
+			return false
+		}
+	}
+	return true
+}
+
+// Get is not served, the controller reads the objects through the listers
+func (c *informerCache) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	return fmt.Errorf("the informer cache serves no reads, %T is read through its lister", obj)
+}
+
+// List is not served, the controller reads the objects through the listers
+func (c *informerCache) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	return fmt.Errorf("the informer cache serves no reads, %T is read through its lister", list)
+}
+
+// IndexField is not served, the informers are indexed by the controller with the index package
+func (c *informerCache) IndexField(ctx context.Context, obj client.Object, field string, extractValue client.IndexerFunc) error {
+	return fmt.Errorf("the informer cache adds no field indexes, %T is indexed by the controller", obj)
+}
@@ -20,63 +20,107 @@ import (
 	"context"
 	"fmt"
 	"reflect"
-	"time"
+	"sync"
 
 	"github.com/EdgeNet-project/edgenet/pkg/access"
 	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/cni"
+	"github.com/EdgeNet-project/edgenet/pkg/config"
 	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
 	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
 	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
 	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
 	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/index"
 
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	networkinginformers "k8s.io/client-go/informers/networking/v1"
+	rbacinformers "k8s.io/client-go/informers/rbac/v1"
 	"k8s.io/client-go/kubernetes"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	networkinglisters "k8s.io/client-go/listers/networking/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const controllerAgentName = "tenant-controller"
 
+// annotationBaselinePolicies set to false keeps a tenant on the permissive network policy
+const annotationBaselinePolicies = "edge-net.io/baseline-policies"
+
 // Definitions of the state of the tenant resource
 const (
-	successSynced                           = "Synced"
-	messageResourceSynced                   = "Tenant synced successfully"
-	successEstablished                      = "Established"
-	messageEstablished                      = "Tenant established successfully"
-	warningAUP                              = "Not Agreed"
-	messageAUPNotAgreed                     = "Waiting for the Acceptable Use Policy to be agreed"
-	failureAUP                              = "Creation Failed"
-	messageAUPFailed                        = "Acceptable Use Policy creation failed"
-	failureCreation                         = "Not Created"
-	messageCreationFailed                   = "Core namespace creation failed"
-	failureBinding                          = "Binding Failed"
-	messageBindingFailed                    = "Role binding failed"
-	failureNetworkPolicy                    = "Not Applied"
-	messageNetworkPolicyFailed              = "Applying network policy failed"
-	failureSubNamespaceDeletion             = "Not Removed"
-	messageSubNamespaceDeletionFailed       = "Subsidiary namespace clean up failed"
-	failureClusterRoleDeletion              = "Not Removed"
-	messageClusterRoleDeletionFailed        = "Cluster role clean up failed"
-	failureClusterRoleBindingDeletion       = "Not Removed"
-	messageClusterRoleBindingDeletionFailed = "Cluster role binding clean up failed"
-	failureRoleBindingDeletion              = "Not Removed"
-	messageRoleBindingDeletionFailed        = "Role binding clean up failed"
-	failureRoleBindingCreation              = "Not Created"
-	messageRoleBindingCreationFailed        = "Role binding creation for tenant failed"
-	failure                                 = "Failure"
-	pending                                 = "Pending"
-	established                             = "Established"
+	successSynced                     = "Synced"
+	messageResourceSynced             = "Tenant synced successfully"
+	successEstablished                = "Established"
+	messageEstablished                = "Tenant established successfully"
+	warningAUP                        = "Not Agreed"
+	messageAUPNotAgreed               = "Waiting for the Acceptable Use Policy to be agreed"
+	failureAUP                        = "Creation Failed"
+	messageAUPFailed                  = "Acceptable Use Policy creation failed"
+	failureCreation                   = "Not Created"
+	messageCreationFailed             = "Core namespace creation failed"
+	failureBinding                    = "Binding Failed"
+	messageBindingFailed              = "Role binding failed"
+	failureGroupBinding               = "Binding Failed"
+	messageGroupBindingFailed         = "Role binding of the identity-provider groups failed"
+	failureMemberBinding              = "Binding Failed"
+	messageMemberBindingFailed        = "Role binding of the owners and administrators failed"
+	failureServiceBinding             = "Binding Failed"
+	messageServiceBindingFailed       = "Role binding of the services failed"
+	failureNetworkPolicy              = "Not Applied"
+	messageNetworkPolicyFailed        = "Applying network policy failed"
+	failureSubNamespacePolicy         = "Not Synced"
+	messageSubNamespacePolicyFailed   = "Syncing network policies to subnamespaces failed"
+	failureLimitRange                 = "Not Applied"
+	messageLimitRangeFailed           = "Applying container limit range failed"
+	failureTokenPolicy                = "Not Applied"
+	messageTokenPolicyFailed          = "Applying service account token policy failed"
+	failurePriorityClass              = "Not Applied"
+	messagePriorityClassFailed        = "Applying priority class failed"
+	failureIngress                    = "Not Applied"
+	messageIngressFailed              = "Applying ingress class failed"
+	failureMesh                       = "Not Applied"
+	messageMeshFailed                 = "Applying service mesh failed"
+	failureEgress                     = "Not Applied"
+	messageEgressFailed               = "Applying egress to the domain names failed"
+	failureNodePool                   = "Not Applied"
+	messageNodePoolFailed             = "Applying node pools failed"
+	failurePortRange                  = "Not Allocated"
+	messagePortRangeFailed            = "Port range allocation failed"
+	failureSubNamespaceDeletion       = "Not Removed"
+	messageSubNamespaceDeletionFailed = "Tenant teardown could not be started"
+	failureClusterRoleDeletion        = "Not Removed"
+	messageClusterRoleDeletionFailed  = "Cluster role clean up failed"
+	failureRoleBindingCreation        = "Not Created"
+	messageRoleBindingCreationFailed  = "Role binding creation for tenant failed"
+	failureClusterRoleCreation        = "Not Created"
+	messageClusterRoleCreationFailed  = "Owner cluster role creation failed"
+	successPurged                     = "Purged"
+	messagePurged                     = "Personal data purged, deletion report emitted"
+	failurePurge                      = "Not Purged"
+	messagePurgeFailed                = "Personal data purge failed"
+	messageReportFailed               = "Deletion report could not be emitted"
+	failurePolicy                     = "Not Consulted"
+	messagePolicyFailed               = "Policies could not be consulted"
+	failurePolicyDenied               = "Denied"
+	messagePolicyDenied               = "Tenant establishment denied by the policies"
+	failure                           = "Failure"
+	pending                           = "Pending"
+	established                       = "Established"
 )
 
 // The main structure of controller
@@ -85,265 +129,221 @@ type Controller struct {
 	kubeclientset kubernetes.Interface
 	// edgenetclientset is a clientset for the EdgeNet API groups
 	edgenetclientset clientset.Interface
-
-	tenantsLister listers.TenantLister
-	tenantsSynced cache.InformerSynced
-
-	// workqueue is a rate limited work queue. This is used to queue work to be
-	// processed instead of performing it as soon as a change happens. This
-	// means we can ensure we only process a fixed amount of resources at a
-	// time, and makes it easy to ensure we are never processing the same item
-	// simultaneously in two different workers.
-	workqueue workqueue.RateLimitingInterface
+	// dynamicclientset reaches the admission mutations that are not in the standard clientset
+	dynamicclientset dynamic.Interface
+
+	// The listers read the informers that the manager waits for before it starts the workers
+	tenantsLister    listers.TenantLister
+	nodePoolsLister  listers.NodePoolLister
+	operationsLister listers.OperationLister
+	namespacesLister corelisters.NamespaceLister
+	// The verification probes read the pod address ranges of the nodes and the network policies
+	nodesLister           corelisters.NodeLister
+	networkPoliciesLister networkinglisters.NetworkPolicyLister
+	// The namespaces and the cluster roles are indexed by tenant, so that they are not listed
+	// from the API server on every reconcile
+	namespacesIndexer   cache.Indexer
+	clusterRolesIndexer cache.Indexer
+
+	// queue is the tiered queue that the watches fill, a flapping tenant holds up neither the
+	// other tenants nor the establishment of the new ones
+	queue *tieredQueue
+	// feed hands the keys of the queue over to the workers of the manager
+	feed *tierFeed
+	// backoff is the rate limiter of the queue of the manager, a forced reconcile resets the
+	// backoff of the tenant
+	backoff workqueue.RateLimiter
+	// resync takes the tenants to reconcile that no watch event brings along
+	resync chan event.GenericEvent
 	// recorder is an event recorder for recording Event resources to the
 	// Kubernetes API.
 	recorder record.EventRecorder
+	// identity is the identity of the cluster that the objects are labeled with
+	identity *config.ClusterIdentity
+	// capabilities holds what the installed CNI plugin does with network policies,
+	// nil until the first detection completes.
+	capabilities      *cni.Capabilities
+	capabilitiesMutex sync.RWMutex
+	// failures coalesces the failures repeating over reconciles
+	failures *failureAggregator
+	// baselinePolicies enables the default-deny network policies in the tenant namespaces
+	baselinePolicies bool
 }
 
 func NewController(
+	ctx context.Context,
 	kubeclientset kubernetes.Interface,
 	edgenetclientset clientset.Interface,
-	tenantInformer informers.TenantInformer) *Controller {
+	dynamicclientset dynamic.Interface,
+	tenantInformer informers.TenantInformer,
+	nodePoolInformer informers.NodePoolInformer,
+	operationInformer informers.OperationInformer,
+	namespaceInformer coreinformers.NamespaceInformer,
+	clusterRoleInformer rbacinformers.ClusterRoleInformer,
+	nodeInformer coreinformers.NodeInformer,
+	networkPolicyInformer networkinginformers.NetworkPolicyInformer,
+	baselinePolicies bool) *Controller {
 
 	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
-	klog.V(4).Infoln("Creating event broadcaster")
+	klog.V(4).InfoS("Creating event broadcaster")
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartStructuredLogging(0)
 	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
 	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
 
 	controller := &Controller{
-		kubeclientset:    kubeclientset,
-		edgenetclientset: edgenetclientset,
-		tenantsLister:    tenantInformer.Lister(),
-		tenantsSynced:    tenantInformer.Informer().HasSynced,
-		workqueue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Tenants"),
-		recorder:         recorder,
+		identity:              config.NewClusterIdentity(kubeclientset),
+		kubeclientset:         kubeclientset,
+		edgenetclientset:      edgenetclientset,
+		dynamicclientset:      dynamicclientset,
+		tenantsLister:         tenantInformer.Lister(),
+		nodePoolsLister:       nodePoolInformer.Lister(),
+		operationsLister:      operationInformer.Lister(),
+		namespacesLister:      namespaceInformer.Lister(),
+		nodesLister:           nodeInformer.Lister(),
+		networkPoliciesLister: networkPolicyInformer.Lister(),
+		namespacesIndexer:     namespaceInformer.Informer().GetIndexer(),
+		clusterRolesIndexer:   clusterRoleInformer.Informer().GetIndexer(),
+		backoff:               workqueue.NewItemExponentialFailureRateLimiter(QueueBaseDelay, QueueMaxDelay),
+		resync:                make(chan event.GenericEvent),
+		recorder:              newThrottledRecorder(recorder),
+		failures:              newFailureAggregator(),
+		baselinePolicies:      baselinePolicies,
 	}
-
-	klog.V(4).Infoln("Setting up event handlers")
+	index.AddTenantIndex(namespaceInformer.Informer())
+	index.AddTenantIndex(clusterRoleInformer.Informer())
+	controller.queue = newTieredQueue("Tenants", controller.tier)
+	// The watches are set up along with the manager, the tenant informer only audits the switches
 	tenantInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc: controller.enqueueTenant,
 		UpdateFunc: func(oldObj, newObj interface{}) {
-			controller.enqueueTenant(newObj)
+			newTenant := newObj.(*corev1alpha.Tenant)
+			if oldObj.(*corev1alpha.Tenant).Spec.Enabled != newTenant.Spec.Enabled {
+				controller.auditEnabled(ctx, newTenant)
+			}
 		},
 	})
 
 	access.Clientset = kubeclientset
 	access.EdgenetClientset = edgenetclientset
 
-	access.CreateClusterRoles()
-
 	return controller
 }
 
-// Run will set up the event handlers for the types of tenant, as well
-// as syncing informer caches and starting workers. It will block until stopCh
-// is closed, at which point it will shutdown the workqueue and wait for
-// workers to finish processing their current work items.
-func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
-	defer utilruntime.HandleCrash()
-	defer c.workqueue.ShutDown()
-
-	klog.V(4).Infoln("Starting Tenant controller")
-
-	klog.V(4).Infoln("Waiting for informer caches to sync")
-	if ok := cache.WaitForCacheSync(stopCh,
-		c.tenantsSynced); !ok {
-		return fmt.Errorf("failed to wait for caches to sync")
-	}
-
-	klog.V(4).Infoln("Starting workers")
-	for i := 0; i < threadiness; i++ {
-		go wait.Until(c.runWorker, time.Second, stopCh)
+// transition moves the tenant to the state of the copy through the status helpers of the client,
+// the rest of the status of the copy is written on top of the tenant returned
+func (c *Controller) transition(ctx context.Context, tenantCopy *corev1alpha.Tenant) (*corev1alpha.Tenant, error) {
+	if tenantCopy.Status.State == failure {
+		return c.edgenetclientset.CoreV1alpha().Tenants().Fail(ctx, tenantCopy.GetName(), tenantCopy.Status.Message)
 	}
-
-	klog.V(4).Infoln("Started workers")
-	<-stopCh
-	klog.V(4).Infoln("Shutting down workers")
-
-	return nil
+	return c.edgenetclientset.CoreV1alpha().Tenants().Establish(ctx, tenantCopy.GetName())
 }
 
-// runWorker is a long-running function that will continually call the
-// processNextWorkItem function in order to read and process a message on the
-// workqueue.
-func (c *Controller) runWorker() {
-	for c.processNextWorkItem() {
+// tier returns the tier of the queue the tenant goes in, the tenants that have never been
+// established come first
+func (c *Controller) tier(item interface{}) int {
+	request, ok := item.(reconcile.Request)
+	if !ok {
+		return tierEstablished
 	}
-}
-
-// processNextWorkItem will read a single work item off the workqueue and
-// attempt to process it, by calling the syncHandler.
-func (c *Controller) processNextWorkItem() bool {
-	obj, shutdown := c.workqueue.Get()
-
-	if shutdown {
-		return false
+	// A deleted tenant is only cleaned up, it waits behind the others
+	if tenant, err := c.tenantsLister.Get(request.Name); err == nil && tenant.Status.State == "" {
+		return tierNew
 	}
-
-	err := func(obj interface{}) error {
-		defer c.workqueue.Done(obj)
-		var key string
-		var ok bool
-
-		if key, ok = obj.(string); !ok {
-			c.workqueue.Forget(obj)
-			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
-			return nil
-		}
-		if err := c.syncHandler(key); err != nil {
-			c.workqueue.AddRateLimited(key)
-			return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
-		}
-		c.workqueue.Forget(obj)
-		klog.V(4).Infof("Successfully synced '%s'", key)
-		return nil
-	}(obj)
-
-	if err != nil {
-		utilruntime.HandleError(err)
-		return true
-	}
-
-	return true
+	return tierEstablished
 }
 
-// syncHandler compares the actual state with the desired, and attempts to
-// converge the two. It then updates the Status block of the Tenant
-// resource with the current status of the resource.
-func (c *Controller) syncHandler(key string) error {
-	_, name, err := cache.SplitMetaNamespaceKey(key)
-	if err != nil {
-		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
-		return nil
+// ProcessTenant reconciles the tenant and returns an error if any of the sub-steps fails
+func (c *Controller) ProcessTenant(ctx context.Context, tenantCopy *corev1alpha.Tenant) error {
+	// A tenant being deleted is only purged, the garbage collector takes its objects down
+	if tenantCopy.GetDeletionTimestamp() != nil {
+		return c.purgeTenant(ctx, tenantCopy)
 	}
-
-	tenant, err := c.tenantsLister.Get(name)
-	if err != nil {
-		if errors.IsNotFound(err) {
-			utilruntime.HandleError(fmt.Errorf("tenant '%s' in work queue no longer exists", key))
-			return nil
-		}
-
+	if err := c.requestPurge(ctx, tenantCopy); err != nil {
+		klog.ErrorS(err, "Couldn't request the purge of the tenant", "tenant", klog.KObj(tenantCopy))
 		return err
 	}
-
-	c.ProcessTenant(tenant.DeepCopy())
-
-	c.recorder.Event(tenant, corev1.EventTypeNormal, successSynced, messageResourceSynced)
-	return nil
-}
-
-// enqueueTenant takes a Tenant resource and converts it into a namespace/name
-// string which is then put onto the work queue. This method should *not* be
-// passed resources of any type other than Tenant.
-func (c *Controller) enqueueTenant(obj interface{}) {
-	var key string
-	var err error
-	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
-		utilruntime.HandleError(err)
-		return
-	}
-	c.workqueue.Add(key)
-}
-
-func (c *Controller) ProcessTenant(tenantCopy *corev1alpha.Tenant) {
-	oldStatus := tenantCopy.Status
+	oldStatus := *tenantCopy.Status.DeepCopy()
+	c.forceReconcile(ctx, tenantCopy)
+	// Failed sub-steps are collected and reported at once to avoid flooding the events
+	failures := stepFailures{}
 	statusUpdate := func() {
+		c.failures.flush(c.recorder, tenantCopy, failures)
+		c.recordSyncResult(tenantCopy, failures)
+		if err := c.annotateNamespaces(ctx, tenantCopy); err != nil {
+			klog.ErrorS(err, "Couldn't annotate the namespaces", "tenant", klog.KObj(tenantCopy))
+		}
+		if oldStatus.State != tenantCopy.Status.State {
+			updated, err := c.transition(ctx, tenantCopy)
+			if err != nil {
+				klog.ErrorS(err, "Couldn't update the state of the tenant", "tenant", klog.KObj(tenantCopy))
+				return
+			}
+			c.notifyState(tenantCopy)
+			tenantCopy.SetResourceVersion(updated.GetResourceVersion())
+			oldStatus = updated.Status
+		}
 		if !reflect.DeepEqual(oldStatus, tenantCopy.Status) {
-			if _, err := c.edgenetclientset.CoreV1alpha().Tenants().UpdateStatus(context.TODO(), tenantCopy, metav1.UpdateOptions{}); err != nil {
-				klog.V(4).Infoln(err)
+			if _, err := c.edgenetclientset.CoreV1alpha().Tenants().UpdateStatus(ctx, tenantCopy, metav1.UpdateOptions{}); err != nil {
+				klog.ErrorS(err, "Couldn't update the status of the tenant", "tenant", klog.KObj(tenantCopy))
 			}
 		}
 	}
 	defer statusUpdate()
 
-	systemNamespace, err := c.kubeclientset.CoreV1().Namespaces().Get(context.TODO(), "kube-system", metav1.GetOptions{})
+	clusterUID, err := c.identity.UID(ctx)
 	if err != nil {
-		klog.V(4).Infoln(err)
-		return
+		klog.ErrorS(err, "Couldn't read the cluster UID")
+		return err
 	}
 
 	if tenantCopy.Spec.Enabled {
-		// When a tenant is deleted, the owner references feature drives the namespace to be automatically removed
-		ownerReferences := SetAsOwnerReference(tenantCopy)
-		// Create the cluster roles
-		tenantOwnerClusterRole, err := access.CreateObjectSpecificClusterRole(tenantCopy.GetName(), "core.edgenet.io", "tenants", tenantCopy.GetName(), "owner", []string{"get", "update", "patch"}, ownerReferences)
-		if err != nil && !errors.IsAlreadyExists(err) {
-			klog.V(4).Infof("Couldn't create owner cluster role %s: %s", tenantCopy.GetName(), err)
-			// TODO: Provide err information at the EVENTS
-		}
-		err = c.createCoreNamespace(tenantCopy, ownerReferences, string(systemNamespace.GetUID()))
-		if err == nil || errors.IsAlreadyExists(err) {
-			// Apply network policies
-			err = c.applyNetworkPolicy(tenantCopy.GetName(), string(tenantCopy.GetUID()), string(systemNamespace.GetUID()))
-			if err != nil && !errors.IsAlreadyExists(err) {
-				c.recorder.Event(tenantCopy, corev1.EventTypeWarning, failureNetworkPolicy, messageNetworkPolicyFailed)
-			}
-
-			// Cluster role binding
-			if err := access.CreateObjectSpecificClusterRoleBinding(tenantOwnerClusterRole, tenantCopy.Spec.Contact.Handle, tenantCopy.Spec.Contact.Email, map[string]string{"edge-net.io/generated": "true"}, []metav1.OwnerReference{}); err != nil {
-				c.recorder.Event(tenantCopy, corev1.EventTypeWarning, failureRoleBindingCreation, messageRoleBindingCreationFailed)
-			}
-			// Role binding
-			clusterRoleName := "edgenet:tenant-owner"
-			roleRef := rbacv1.RoleRef{Kind: "ClusterRole", Name: clusterRoleName}
-			rbSubjects := []rbacv1.Subject{{Kind: "User", Name: tenantCopy.Spec.Contact.Email, APIGroup: "rbac.authorization.k8s.io"}}
-			roleBind := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: clusterRoleName, Namespace: tenantCopy.GetName()},
-				Subjects: rbSubjects, RoleRef: roleRef}
-			roleBindLabels := map[string]string{"edge-net.io/generated": "true"}
-			roleBind.SetLabels(roleBindLabels)
-			if _, err := c.kubeclientset.RbacV1().RoleBindings(tenantCopy.GetName()).Create(context.TODO(), roleBind, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
-				c.recorder.Event(tenantCopy, corev1.EventTypeWarning, failureBinding, messageBindingFailed)
-				tenantCopy.Status.State = failure
-				tenantCopy.Status.Message = messageBindingFailed
-				klog.V(4).Infoln(err)
-			} else {
-				c.recorder.Event(tenantCopy, corev1.EventTypeNormal, successEstablished, messageEstablished)
-				tenantCopy.Status.State = established
-				tenantCopy.Status.Message = successEstablished
-			}
+		// Namespace, quota, RBAC, network, and notification in order, a retry resumes at the step
+		// that failed. The policies of the operators may hold the establishment back.
+		if c.admitted(ctx, tenantCopy, &failures) {
+			c.establish(ctx, tenantCopy, clusterUID, &failures)
 		}
 	} else {
-		// Delete all subsidiary namespaces
-		if namespaceRaw, err := c.kubeclientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{LabelSelector: fmt.Sprintf("edge-net.io/tenant=%s,edge-net.io/tenant-uid=%s,edge-net.io/cluster-uid=%s,edge-net.io/kind=sub", tenantCopy.GetName(), string(tenantCopy.GetUID()), string(systemNamespace.GetUID()))}); err == nil {
-			for _, namespaceRow := range namespaceRaw.Items {
-				c.kubeclientset.CoreV1().Namespaces().Delete(context.TODO(), namespaceRow.GetName(), metav1.DeleteOptions{})
-			}
-		} else {
-			c.recorder.Event(tenantCopy, corev1.EventTypeWarning, failureSubNamespaceDeletion, messageSubNamespaceDeletionFailed)
+		// A tenant enabled again goes through all the steps
+		tenantCopy.Status.Onboarding = nil
+		// Delete the bindings, the extension requests, and the subsidiary namespaces in the background
+		if err := c.startTeardown(ctx, tenantCopy, clusterUID); err != nil {
+			klog.ErrorS(err, "Couldn't start the teardown", "tenant", klog.KObj(tenantCopy))
+			failures.add(failureSubNamespaceDeletion, messageSubNamespaceDeletionFailed)
 		}
-		// Delete all roles, role bindings, and subsidiary namespaces
-		if err := c.kubeclientset.RbacV1().ClusterRoles().DeleteCollection(context.TODO(), metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: fmt.Sprintf("edge-net.io/tenant=%s,edge-net.io/tenant-uid=%s,edge-net.io/cluster-uid=%s", tenantCopy.GetName(), string(tenantCopy.GetUID()), string(systemNamespace.GetUID()))}); err != nil {
-			c.recorder.Event(tenantCopy, corev1.EventTypeWarning, failureClusterRoleDeletion, messageClusterRoleDeletionFailed)
+		// The ports of a disabled tenant go back to the pool
+		if err := access.ReleasePortRange(ctx, tenantCopy.GetName()); err != nil {
+			klog.ErrorS(err, "Couldn't release the port range", "tenant", klog.KObj(tenantCopy))
 		}
-		if err := c.kubeclientset.RbacV1().ClusterRoleBindings().DeleteCollection(context.TODO(), metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: fmt.Sprintf("edge-net.io/tenant=%s,edge-net.io/tenant-uid=%s,edge-net.io/cluster-uid=%s", tenantCopy.GetName(), string(tenantCopy.GetUID()), string(systemNamespace.GetUID()))}); err != nil {
-			c.recorder.Event(tenantCopy, corev1.EventTypeWarning, failureClusterRoleBindingDeletion, messageClusterRoleBindingDeletionFailed)
-		}
-		if err := c.kubeclientset.RbacV1().RoleBindings(tenantCopy.GetName()).DeleteCollection(context.TODO(), metav1.DeleteOptions{}, metav1.ListOptions{}); err != nil {
-			c.recorder.Event(tenantCopy, corev1.EventTypeWarning, failureRoleBindingDeletion, messageRoleBindingDeletionFailed)
+		// The cluster roles go at once, the bindings left to the teardown grant nothing without them.
+		// The cache tells whether any is left, a disabled tenant is reconciled long after they are gone.
+		if c.hasClusterRoles(tenantCopy, clusterUID) {
+			if err := c.kubeclientset.RbacV1().ClusterRoles().DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: fmt.Sprintf("edge-net.io/tenant=%s,edge-net.io/tenant-uid=%s,edge-net.io/cluster-uid=%s", tenantCopy.GetName(), string(tenantCopy.GetUID()), clusterUID)}); err != nil {
+				failures.add(failureClusterRoleDeletion, messageClusterRoleDeletionFailed)
+			}
 		}
 	}
+	return failures.err()
 }
 
-func (c *Controller) createCoreNamespace(tenantCopy *corev1alpha.Tenant, ownerReferences []metav1.OwnerReference, clusterUID string) error {
+func (c *Controller) createCoreNamespace(ctx context.Context, tenantCopy *corev1alpha.Tenant, ownerReferences []metav1.OwnerReference, clusterUID string) error {
 	// Core namespace has the same name as the tenant
 	coreNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: tenantCopy.GetName(), OwnerReferences: ownerReferences}}
 	// Namespace labels indicate this namespace created by a tenant, not by a team or slice
 	namespaceLabels := map[string]string{"edge-net.io/kind": "core", "edge-net.io/tenant": tenantCopy.GetName(),
 		"edge-net.io/tenant-uid": string(tenantCopy.GetUID()), "edge-net.io/cluster-uid": clusterUID}
 	coreNamespace.SetLabels(namespaceLabels)
-	_, err := c.kubeclientset.CoreV1().Namespaces().Create(context.TODO(), coreNamespace, metav1.CreateOptions{})
-	if err != nil && !errors.IsAlreadyExists(err) {
-		c.recorder.Event(tenantCopy, corev1.EventTypeWarning, failureCreation, messageCreationFailed)
+	patch, err := access.ApplyPatch(coreNamespace, corev1.SchemeGroupVersion.WithKind("Namespace"))
+	if err == nil {
+		_, err = c.kubeclientset.CoreV1().Namespaces().Patch(ctx, coreNamespace.GetName(), types.ApplyPatchType, patch, access.ApplyOptions())
+	}
+	if err != nil {
 		tenantCopy.Status.State = failure
 		tenantCopy.Status.Message = messageCreationFailed
 	}
 	return err
 }
 
-func (c *Controller) applyNetworkPolicy(namespace, tenantUID, clusterUID string) error {
+func (c *Controller) applyNetworkPolicy(ctx context.Context, namespace, tenantUID, clusterUID string, egress *corev1alpha.Egress) error {
 	// TODO: Apply a network policy to the core namespace according to spec
 	// Restricted only allows intra-tenant communication
 	// Baseline allows intra-tenant communication plus ingress from external traffic
@@ -351,29 +351,38 @@ func (c *Controller) applyNetworkPolicy(namespace, tenantUID, clusterUID string)
 	// TODO: ClusterNetworkPolicy
 	networkPolicy := new(networkingv1.NetworkPolicy)
 	networkPolicy.SetName("baseline")
+	networkPolicy.SetLabels(map[string]string{"edge-net.io/generated": "true"})
 	networkPolicy.Spec.PolicyTypes = []networkingv1.PolicyType{"Ingress"}
-	port := intstr.IntOrString{IntVal: 30000}
-	endPort := int32(32768)
+	portRange, allocated, err := access.LookupPortRange(ctx, namespace)
+	if err != nil {
+		return err
+	} else if !allocated {
+		portRange = access.NodePortRange()
+	}
+	port := intstr.FromInt(int(portRange.First))
+	endPort := portRange.Last
+	tenantPeer := networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"edge-net.io/subtenant":   "false",
+				"edge-net.io/tenant":      namespace,
+				"edge-net.io/tenant-uid":  tenantUID,
+				"edge-net.io/cluster-uid": clusterUID,
+			},
+		},
+	}
+	// The egress is only restricted once the tenant lists the destinations it reaches
+	if egress != nil {
+		egressRules, err := access.EgressRules(tenantPeer, egress)
+		if err != nil {
+			return err
+		}
+		networkPolicy.Spec.PolicyTypes = append(networkPolicy.Spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+		networkPolicy.Spec.Egress = egressRules
+	}
 	networkPolicy.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{
 		{
-			From: []networkingv1.NetworkPolicyPeer{
-				{
-					NamespaceSelector: &metav1.LabelSelector{
-						MatchLabels: map[string]string{
-							"edge-net.io/subtenant":   "false",
-							"edge-net.io/tenant":      namespace,
-							"edge-net.io/tenant-uid":  tenantUID,
-							"edge-net.io/cluster-uid": clusterUID,
-						},
-					},
-				},
-				{
-					IPBlock: &networkingv1.IPBlock{
-						CIDR:   "0.0.0.0/0",
-						Except: []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"},
-					},
-				},
-			},
+			From: append([]networkingv1.NetworkPolicyPeer{tenantPeer}, access.ExternalPeers()...),
 			Ports: []networkingv1.NetworkPolicyPort{
 				{
 					Port:    &port,
@@ -382,10 +391,34 @@ func (c *Controller) applyNetworkPolicy(namespace, tenantUID, clusterUID string)
 			},
 		},
 	}
-	_, err := c.kubeclientset.NetworkingV1().NetworkPolicies(namespace).Create(context.TODO(), networkPolicy, metav1.CreateOptions{})
+	patch, err := access.ApplyPatch(networkPolicy, networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"))
+	if err != nil {
+		return err
+	}
+	_, err = c.kubeclientset.NetworkingV1().NetworkPolicies(namespace).Patch(ctx, networkPolicy.GetName(), types.ApplyPatchType, patch, access.ApplyOptions())
 	return err
 }
 
+// applyRoleBinding brings the role binding of the tenant owner in line with the one generated
+func (c *Controller) applyRoleBinding(ctx context.Context, roleBind *rbacv1.RoleBinding) error {
+	patch, err := access.ApplyPatch(roleBind, rbacv1.SchemeGroupVersion.WithKind("RoleBinding"))
+	if err != nil {
+		return err
+	}
+	_, err = c.kubeclientset.RbacV1().RoleBindings(roleBind.GetNamespace()).Patch(ctx, roleBind.GetName(), types.ApplyPatchType, patch, access.ApplyOptions())
+	return err
+}
+
+// applyServiceRoleBindings binds the services in the namespace
+func (c *Controller) applyServiceRoleBindings(ctx context.Context, namespace string) error {
+	for _, roleBind := range access.ServiceRoleBindings(namespace) {
+		if err := c.applyRoleBinding(ctx, roleBind); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // SetAsOwnerReference returns the tenant as owner
 func SetAsOwnerReference(tenant *corev1alpha.Tenant) []metav1.OwnerReference {
 	// The following section makes tenant become the owner
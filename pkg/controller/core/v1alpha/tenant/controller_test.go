@@ -2,11 +2,20 @@ package tenant
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,17 +23,46 @@ import (
 	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
 	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
 	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
 	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/index"
+	"github.com/EdgeNet-project/edgenet/pkg/policy"
+	"github.com/EdgeNet-project/edgenet/pkg/purge"
 	"github.com/EdgeNet-project/edgenet/pkg/signals"
+	"github.com/EdgeNet-project/edgenet/pkg/signature"
 	"github.com/EdgeNet-project/edgenet/pkg/util"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/sirupsen/logrus"
 
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	testclient "k8s.io/client-go/kubernetes/fake"
-	"k8s.io/klog"
+	kubescheme "k8s.io/client-go/kubernetes/scheme"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/rest"
+	ktesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 type TestGroup struct {
@@ -33,10 +71,13 @@ type TestGroup struct {
 
 var kubeclientset kubernetes.Interface = testclient.NewSimpleClientset()
 var edgenetclientset versioned.Interface = edgenettestclient.NewSimpleClientset()
+var dynamicclient dynamic.Interface = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
 
 func TestMain(m *testing.M) {
 	klog.SetOutput(ioutil.Discard)
 	log.SetOutput(ioutil.Discard)
+	// The pods of the connectivity probe never run with the fake clientset
+	IsolationProbeImage = ""
 	logrus.SetOutput(ioutil.Discard)
 
 	flag.String("dir", "../../../../..", "Override the directory.")
@@ -48,23 +89,62 @@ func TestMain(m *testing.M) {
 	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeclientset, time.Second*30)
 	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, time.Second*30)
 
-	controller := NewController(kubeclientset,
+	controller := NewController(signals.ContextFor(stopCh),
+		kubeclientset,
 		edgenetclientset,
-		edgenetInformerFactory.Core().V1alpha().Tenants())
+		dynamicclient,
+		edgenetInformerFactory.Core().V1alpha().Tenants(),
+		edgenetInformerFactory.Core().V1alpha().NodePools(),
+		edgenetInformerFactory.Core().V1alpha().Operations(),
+		kubeInformerFactory.Core().V1().Namespaces(),
+		kubeInformerFactory.Rbac().V1().ClusterRoles(),
+		kubeInformerFactory.Core().V1().Nodes(),
+		kubeInformerFactory.Networking().V1().NetworkPolicies(),
+		true)
 
-	kubeInformerFactory.Start(stopCh)
-	edgenetInformerFactory.Start(stopCh)
+	// The manager runs on the informers of the fake clientsets, the mapper stands in for the
+	// discovery of the API server that is not there
+	scheme := runtime.NewScheme()
+	utilruntime.Must(kubescheme.AddToScheme(scheme))
+	utilruntime.Must(edgenetscheme.AddToScheme(scheme))
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ServiceAccount"), meta.RESTScopeNamespace)
+	for _, kind := range []string{"Tenant", "TenantUsage", "NodePool", "Operation"} {
+		mapper.Add(corev1alpha.SchemeGroupVersion.WithKind(kind), meta.RESTScopeRoot)
+	}
+	mgr, err := ctrl.NewManager(&rest.Config{Host: "localhost"}, ctrl.Options{
+		Scheme:             scheme,
+		MetricsBindAddress: "0",
+		MapperProvider:     func(*rest.Config) (meta.RESTMapper, error) { return mapper, nil },
+		NewCache:           NewCache(kubeInformerFactory, edgenetInformerFactory),
+	})
+	if err != nil {
+		klog.Fatalf("Error creating manager: %s", err.Error())
+	}
+	if err := controller.SetupWithManager(mgr, 2); err != nil {
+		klog.Fatalf("Error setting up controller: %s", err.Error())
+	}
+
+	// The owner is always authorized in the test environment to let the verification succeed
+	kubeclientset.(*testclient.Clientset).PrependReactor("create", "subjectaccessreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		review.Status.Allowed = true
+		return true, review, nil
+	})
+
+	kubeclientset.(*testclient.Clientset).PrependReactor("patch", "*", util.ApplyReactor(kubeclientset.(*testclient.Clientset).Tracker(), kubescheme.Scheme))
+
+	access.Clientset = kubeclientset
+	kubeSystemNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", UID: "kube-system-uid"}}
+	kubeclientset.CoreV1().Namespaces().Create(context.TODO(), kubeSystemNamespace, metav1.CreateOptions{})
 
 	go func() {
-		if err := controller.Run(2, stopCh); err != nil {
+		if err := mgr.Start(signals.ContextFor(stopCh)); err != nil {
 			klog.Fatalf("Error running controller: %s", err.Error())
 		}
 	}()
 
-	access.Clientset = kubeclientset
-	kubeSystemNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}}
-	kubeclientset.CoreV1().Namespaces().Create(context.TODO(), kubeSystemNamespace, metav1.CreateOptions{})
-
 	time.Sleep(500 * time.Millisecond)
 
 	os.Exit(m.Run())
@@ -154,3 +234,1149 @@ func TestCreate(t *testing.T) {
 		util.OK(t, err)
 	})
 }
+
+func TestVerification(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	tenant := g.tenantObj.DeepCopy()
+	tenant.SetName("verification-test")
+
+	edgenetclientset.CoreV1alpha().Tenants().Create(context.TODO(), tenant, metav1.CreateOptions{})
+	time.Sleep(250 * time.Millisecond)
+	tenant, err := edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenant.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	t.Run("owner access", func(t *testing.T) {
+		condition := meta.FindStatusCondition(tenant.Status.Conditions, conditionOwnerAccess)
+		util.Assert(t, condition != nil, "owner access condition is missing")
+		util.Equals(t, metav1.ConditionTrue, condition.Status)
+	})
+	t.Run("isolation", func(t *testing.T) {
+		condition := meta.FindStatusCondition(tenant.Status.Conditions, conditionIsolation)
+		util.Assert(t, condition != nil, "isolation condition is missing")
+		util.Equals(t, metav1.ConditionTrue, condition.Status)
+	})
+	t.Run("generation", func(t *testing.T) {
+		util.Equals(t, true, isVerified(tenant))
+		tenant := tenant.DeepCopy()
+		tenant.SetGeneration(tenant.GetGeneration() + 1)
+		util.Equals(t, false, isVerified(tenant))
+	})
+}
+
+func TestBaselinePolicies(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	tenant := g.tenantObj.DeepCopy()
+	tenant.SetName("baseline-test")
+	edgenetclientset.CoreV1alpha().Tenants().Create(context.TODO(), tenant, metav1.CreateOptions{})
+	time.Sleep(250 * time.Millisecond)
+	_, err := kubeclientset.NetworkingV1().NetworkPolicies(tenant.GetName()).Get(context.TODO(), "default-deny-ingress", metav1.GetOptions{})
+	util.OK(t, err)
+	_, err = kubeclientset.NetworkingV1().NetworkPolicies(tenant.GetName()).Get(context.TODO(), "baseline", metav1.GetOptions{})
+	util.Equals(t, true, errors.IsNotFound(err))
+
+	t.Run("opt out", func(t *testing.T) {
+		tenant, err := edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenant.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		tenant.SetAnnotations(map[string]string{annotationBaselinePolicies: "false"})
+		edgenetclientset.CoreV1alpha().Tenants().Update(context.TODO(), tenant, metav1.UpdateOptions{})
+		time.Sleep(250 * time.Millisecond)
+		_, err = kubeclientset.NetworkingV1().NetworkPolicies(tenant.GetName()).Get(context.TODO(), "default-deny-ingress", metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+		_, err = kubeclientset.NetworkingV1().NetworkPolicies(tenant.GetName()).Get(context.TODO(), "baseline", metav1.GetOptions{})
+		util.OK(t, err)
+	})
+}
+
+func TestLimitRange(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	tenant := g.tenantObj.DeepCopy()
+	tenant.SetName("limitrange-test")
+	tenant.Spec.ContainerLimits = &corev1alpha.ContainerLimits{
+		Max: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+	}
+
+	edgenetclientset.CoreV1alpha().Tenants().Create(context.TODO(), tenant, metav1.CreateOptions{})
+	time.Sleep(250 * time.Millisecond)
+	limitRange, err := kubeclientset.CoreV1().LimitRanges(tenant.GetName()).Get(context.TODO(), limitRangeName, metav1.GetOptions{})
+	util.OK(t, err)
+	limits := limitRange.Spec.Limits[0]
+	util.Equals(t, corev1.LimitTypeContainer, limits.Type)
+	util.Equals(t, "1", limits.Max.Cpu().String())
+	// The limits left empty fall back to the cluster defaults
+	util.Equals(t, "4Gi", limits.Max.Memory().String())
+	util.Equals(t, "500m", limits.Default.Cpu().String())
+	util.Equals(t, "128Mi", limits.DefaultRequest.Memory().String())
+
+	t.Run("update", func(t *testing.T) {
+		tenant, err := edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenant.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		tenant.Spec.ContainerLimits.Max[corev1.ResourceCPU] = resource.MustParse("3")
+		edgenetclientset.CoreV1alpha().Tenants().Update(context.TODO(), tenant, metav1.UpdateOptions{})
+		time.Sleep(250 * time.Millisecond)
+		limitRange, err := kubeclientset.CoreV1().LimitRanges(tenant.GetName()).Get(context.TODO(), limitRangeName, metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, "3", limitRange.Spec.Limits[0].Max.Cpu().String())
+	})
+}
+
+func TestPriorityClass(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	tenant := g.tenantObj.DeepCopy()
+	tenant.SetName("priority-test")
+	tenant.Spec.Priority = &corev1alpha.Priority{Tier: "paying", Weight: 10}
+
+	edgenetclientset.CoreV1alpha().Tenants().Create(context.TODO(), tenant, metav1.CreateOptions{})
+	time.Sleep(250 * time.Millisecond)
+	priorityClass, err := kubeclientset.SchedulingV1().PriorityClasses().Get(context.TODO(), priorityClassName(tenant.GetName()), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, int32(10010), priorityClass.Value)
+	util.Equals(t, corev1.PreemptLowerPriority, *priorityClass.PreemptionPolicy)
+	assign, err := dynamicclient.Resource(assignGVR).Get(context.TODO(), priorityClassName(tenant.GetName()), metav1.GetOptions{})
+	util.OK(t, err)
+	value, _, _ := unstructured.NestedString(assign.Object, "spec", "parameters", "assign", "value")
+	util.Equals(t, priorityClass.GetName(), value)
+
+	t.Run("tier change", func(t *testing.T) {
+		tenant, err := edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenant.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		never := corev1.PreemptNever
+		tenant.Spec.Priority = &corev1alpha.Priority{Tier: "community", Weight: 10, PreemptionPolicy: &never}
+		edgenetclientset.CoreV1alpha().Tenants().Update(context.TODO(), tenant, metav1.UpdateOptions{})
+		time.Sleep(250 * time.Millisecond)
+		priorityClass, err := kubeclientset.SchedulingV1().PriorityClasses().Get(context.TODO(), priorityClassName(tenant.GetName()), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, int32(1010), priorityClass.Value)
+		util.Equals(t, corev1.PreemptNever, *priorityClass.PreemptionPolicy)
+	})
+	t.Run("fair share", func(t *testing.T) {
+		tenantUsage := &corev1alpha.TenantUsage{ObjectMeta: metav1.ObjectMeta{Name: tenant.GetName()}, Spec: corev1alpha.TenantUsageSpec{Tenant: tenant.GetName()}}
+		tenantUsage, err := edgenetclientset.CoreV1alpha().TenantUsages().Create(context.TODO(), tenantUsage, metav1.CreateOptions{})
+		util.OK(t, err)
+		tenantUsage.Status.FairShare = &corev1alpha.FairShare{Share: 150, Penalty: 250}
+		edgenetclientset.CoreV1alpha().TenantUsages().UpdateStatus(context.TODO(), tenantUsage, metav1.UpdateOptions{})
+		time.Sleep(250 * time.Millisecond)
+		priorityClass, err := kubeclientset.SchedulingV1().PriorityClasses().Get(context.TODO(), priorityClassName(tenant.GetName()), metav1.GetOptions{})
+		util.OK(t, err)
+		// The penalty does not take the tenant below its tier
+		util.Equals(t, int32(1000), priorityClass.Value)
+	})
+	t.Run("removal", func(t *testing.T) {
+		tenant, err := edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenant.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		tenant.Spec.Priority = nil
+		edgenetclientset.CoreV1alpha().Tenants().Update(context.TODO(), tenant, metav1.UpdateOptions{})
+		time.Sleep(250 * time.Millisecond)
+		_, err = kubeclientset.SchedulingV1().PriorityClasses().Get(context.TODO(), priorityClassName(tenant.GetName()), metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+		_, err = dynamicclient.Resource(assignGVR).Get(context.TODO(), priorityClassName(tenant.GetName()), metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+	})
+}
+
+func TestIngress(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	tenant := g.tenantObj.DeepCopy()
+	tenant.SetName("ingress-test")
+	tenant.Spec.Ingress = &corev1alpha.Ingress{}
+
+	edgenetclientset.CoreV1alpha().Tenants().Create(context.TODO(), tenant, metav1.CreateOptions{})
+	time.Sleep(250 * time.Millisecond)
+	ingressClass, err := kubeclientset.NetworkingV1().IngressClasses().Get(context.TODO(), ingressClassName(tenant.GetName()), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, defaultIngressController, ingressClass.Spec.Controller)
+	assign, err := dynamicclient.Resource(assignGVR).Get(context.TODO(), ingressMutationName(tenant.GetName(), "ingress-class"), metav1.GetOptions{})
+	util.OK(t, err)
+	value, _, _ := unstructured.NestedString(assign.Object, "spec", "parameters", "assign", "value")
+	util.Equals(t, ingressClass.GetName(), value)
+	assignMetadata, err := dynamicclient.Resource(assignMetadataGVR).Get(context.TODO(), ingressMutationName(tenant.GetName(), "ingress-issuer"), metav1.GetOptions{})
+	util.OK(t, err)
+	value, _, _ = unstructured.NestedString(assignMetadata.Object, "spec", "parameters", "assign", "value")
+	util.Equals(t, defaultIngressIssuer, value)
+	// The priority mutation of the tenant is not overwritten
+	_, err = dynamicclient.Resource(assignGVR).Get(context.TODO(), priorityClassName(tenant.GetName()), metav1.GetOptions{})
+	util.Equals(t, true, errors.IsNotFound(err))
+
+	t.Run("controller change", func(t *testing.T) {
+		tenant, err := edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenant.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		tenant.Spec.Ingress = &corev1alpha.Ingress{Controller: "traefik.io/ingress-controller", Issuer: "letsencrypt-staging"}
+		edgenetclientset.CoreV1alpha().Tenants().Update(context.TODO(), tenant, metav1.UpdateOptions{})
+		time.Sleep(250 * time.Millisecond)
+		ingressClass, err := kubeclientset.NetworkingV1().IngressClasses().Get(context.TODO(), ingressClassName(tenant.GetName()), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, "traefik.io/ingress-controller", ingressClass.Spec.Controller)
+		assignMetadata, err := dynamicclient.Resource(assignMetadataGVR).Get(context.TODO(), ingressMutationName(tenant.GetName(), "ingress-issuer"), metav1.GetOptions{})
+		util.OK(t, err)
+		value, _, _ := unstructured.NestedString(assignMetadata.Object, "spec", "parameters", "assign", "value")
+		util.Equals(t, "letsencrypt-staging", value)
+	})
+	t.Run("removal", func(t *testing.T) {
+		tenant, err := edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenant.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		tenant.Spec.Ingress = nil
+		edgenetclientset.CoreV1alpha().Tenants().Update(context.TODO(), tenant, metav1.UpdateOptions{})
+		time.Sleep(250 * time.Millisecond)
+		_, err = kubeclientset.NetworkingV1().IngressClasses().Get(context.TODO(), ingressClassName(tenant.GetName()), metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+		_, err = dynamicclient.Resource(assignGVR).Get(context.TODO(), ingressMutationName(tenant.GetName(), "ingress-class"), metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+		_, err = dynamicclient.Resource(assignMetadataGVR).Get(context.TODO(), ingressMutationName(tenant.GetName(), "ingress-ssl-redirect"), metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+	})
+}
+
+func TestPodSecurity(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	tenant := g.tenantObj.DeepCopy()
+
+	cases := map[string]struct {
+		priority *corev1alpha.Priority
+		expected string
+	}{
+		"no tier":   {nil, podSecurityRestricted},
+		"community": {&corev1alpha.Priority{Tier: "community"}, podSecurityRestricted},
+		"paying":    {&corev1alpha.Priority{Tier: "paying"}, podSecurityBaseline},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			tenant.Spec.Priority = tc.priority
+			util.Equals(t, tc.expected, podSecurityLevel(tenant))
+		})
+	}
+	t.Run("exemptions", func(t *testing.T) {
+		tenant.Spec.PodSecurityExemptions = []string{"runAsNonRoot", "hostPathVolumes"}
+		util.Equals(t, "hostPathVolumes,runAsNonRoot", podSecurityExemptions(tenant))
+	})
+}
+
+func TestIsIngressIsolated(t *testing.T) {
+	peer := labels.Set{"edge-net.io/tenant": "other"}
+	ingress := []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+	_, podCIDR, _ := net.ParseCIDR("10.244.0.0/16")
+	_, podCIDRv6, _ := net.ParseCIDR("fd00:10:244::/56")
+	sameTenant := []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"edge-net.io/tenant": "edgenet"}}}}
+	denyAll := &networkingv1.NetworkPolicy{Spec: networkingv1.NetworkPolicySpec{PolicyTypes: ingress}}
+	ipBlock := func(cidr string, except ...string) *networkingv1.NetworkPolicy {
+		return &networkingv1.NetworkPolicy{Spec: networkingv1.NetworkPolicySpec{PolicyTypes: ingress,
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{From: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: cidr, Except: except}}}}}}}
+	}
+	selected := metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	cases := map[string]struct {
+		policies []*networkingv1.NetworkPolicy
+		podCIDRs []*net.IPNet
+		expected bool
+	}{
+		"no policy": {nil, nil, false},
+		"same tenant only": {[]*networkingv1.NetworkPolicy{{Spec: networkingv1.NetworkPolicySpec{PolicyTypes: ingress,
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{From: sameTenant}}}}}, nil, true},
+		"any namespace": {[]*networkingv1.NetworkPolicy{{Spec: networkingv1.NetworkPolicySpec{PolicyTypes: ingress,
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{From: []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{}}}}}}}}, nil, false},
+		"allow all": {[]*networkingv1.NetworkPolicy{{Spec: networkingv1.NetworkPolicySpec{PolicyTypes: ingress,
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{}}}}}, nil, false},
+		"ip block of any address":          {[]*networkingv1.NetworkPolicy{ipBlock("0.0.0.0/0")}, []*net.IPNet{podCIDR}, false},
+		"ip block with unknown pod range":  {[]*networkingv1.NetworkPolicy{ipBlock("192.0.2.0/24")}, nil, false},
+		"ip block outside the pod range":   {[]*networkingv1.NetworkPolicy{ipBlock("192.0.2.0/24")}, []*net.IPNet{podCIDR}, true},
+		"ip block excepting the pod range": {[]*networkingv1.NetworkPolicy{ipBlock("0.0.0.0/0", "10.0.0.0/8")}, []*net.IPNet{podCIDR}, true},
+		"dual-stack ip blocks": {[]*networkingv1.NetworkPolicy{ipBlock("0.0.0.0/0", "10.0.0.0/8"), ipBlock("::/0", "fc00::/7")},
+			[]*net.IPNet{podCIDR, podCIDRv6}, true},
+		"ip block of any ipv6 address": {[]*networkingv1.NetworkPolicy{ipBlock("0.0.0.0/0", "10.0.0.0/8"), ipBlock("::/0")},
+			[]*net.IPNet{podCIDR, podCIDRv6}, false},
+		"selected pods only": {[]*networkingv1.NetworkPolicy{{Spec: networkingv1.NetworkPolicySpec{PodSelector: selected, PolicyTypes: ingress,
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{From: sameTenant}}}}}, nil, false},
+		"selected pods open to all": {[]*networkingv1.NetworkPolicy{denyAll, {Spec: networkingv1.NetworkPolicySpec{PodSelector: selected, PolicyTypes: ingress,
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{From: []networkingv1.NetworkPolicyPeer{{NamespaceSelector: &metav1.LabelSelector{}}}}}}}}, nil, false},
+		"selected pods within the tenant": {[]*networkingv1.NetworkPolicy{denyAll, {Spec: networkingv1.NetworkPolicySpec{PodSelector: selected, PolicyTypes: ingress,
+			Ingress: []networkingv1.NetworkPolicyIngressRule{{From: sameTenant}}}}}, nil, true},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			util.Equals(t, tc.expected, isIngressIsolated(tc.policies, peer, tc.podCIDRs))
+		})
+	}
+}
+
+func TestConnectivityProbe(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	tenant := g.tenantObj.DeepCopy()
+	tenant.SetGeneration(1)
+
+	complete := func(c *Controller, namespace, name string, phase corev1.PodPhase, podIP string) {
+		pod, err := c.kubeclientset.CoreV1().Pods(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		util.OK(t, err)
+		pod.Status.Phase = phase
+		pod.Status.PodIP = podIP
+		_, err = c.kubeclientset.CoreV1().Pods(namespace).UpdateStatus(context.TODO(), pod, metav1.UpdateOptions{})
+		util.OK(t, err)
+	}
+	cases := map[string]struct {
+		control corev1.PodPhase
+		peer    corev1.PodPhase
+		status  metav1.ConditionStatus
+		reason  string
+		removed bool
+	}{
+		"blocked":     {corev1.PodSucceeded, corev1.PodFailed, metav1.ConditionTrue, reasonBlocked, true},
+		"not blocked": {corev1.PodSucceeded, corev1.PodSucceeded, metav1.ConditionFalse, reasonAccepted, true},
+		"unreachable": {corev1.PodFailed, corev1.PodFailed, metav1.ConditionUnknown, reasonProbeError, true},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			c := &Controller{kubeclientset: testclient.NewSimpleClientset()}
+			condition := c.probeConnectivity(context.TODO(), tenant, "cluster")
+			util.Equals(t, reasonProbeRunning, condition.Reason)
+			complete(c, tenant.GetName(), probeTargetName, corev1.PodRunning, "10.244.0.10")
+
+			condition = c.probeConnectivity(context.TODO(), tenant, "cluster")
+			util.Equals(t, reasonProbeRunning, condition.Reason)
+			control, err := c.kubeclientset.CoreV1().Pods(tenant.GetName()).Get(context.TODO(), probeControlName, metav1.GetOptions{})
+			util.OK(t, err)
+			util.Equals(t, "http://10.244.0.10:8080/", control.Spec.Containers[0].Command[len(control.Spec.Containers[0].Command)-1])
+			_, err = c.kubeclientset.CoreV1().Namespaces().Get(context.TODO(), probeTenant, metav1.GetOptions{})
+			util.OK(t, err)
+			complete(c, tenant.GetName(), probeControlName, tc.control, "")
+			complete(c, probeTenant, tenant.GetName(), tc.peer, "")
+
+			condition = c.probeConnectivity(context.TODO(), tenant, "cluster")
+			util.Equals(t, tc.status, condition.Status)
+			util.Equals(t, tc.reason, condition.Reason)
+			_, err = c.kubeclientset.CoreV1().Pods(probeTenant).Get(context.TODO(), tenant.GetName(), metav1.GetOptions{})
+			util.Equals(t, tc.removed, errors.IsNotFound(err))
+		})
+	}
+	t.Run("result of the generation", func(t *testing.T) {
+		c := &Controller{kubeclientset: testclient.NewSimpleClientset()}
+		tenant := tenant.DeepCopy()
+		meta.SetStatusCondition(&tenant.Status.Conditions, metav1.Condition{Type: conditionIsolation, Status: metav1.ConditionTrue, Reason: reasonBlocked, ObservedGeneration: 1})
+		condition := c.probeConnectivity(context.TODO(), tenant, "cluster")
+		util.Equals(t, reasonBlocked, condition.Reason)
+		_, err := c.kubeclientset.CoreV1().Pods(tenant.GetName()).Get(context.TODO(), probeTargetName, metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+	})
+}
+
+func TestFailureAggregation(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	tenant := g.tenantObj.DeepCopy()
+
+	recorder := record.NewFakeRecorder(10)
+	aggregator := newFailureAggregator()
+	now := time.Now()
+	aggregator.now = func() time.Time { return now }
+
+	failures := stepFailures{}
+	failures.add(failureNetworkPolicy, messageNetworkPolicyFailed)
+	failures.add(failureBinding, messageBindingFailed)
+	aggregator.flush(recorder, tenant, failures)
+	util.Equals(t, 2, len(recorder.Events))
+	condition := meta.FindStatusCondition(tenant.Status.Conditions, conditionReconciled)
+	util.Equals(t, metav1.ConditionFalse, condition.Status)
+
+	t.Run("coalesce repeated failures", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		aggregator.flush(recorder, tenant, failures)
+		util.Equals(t, 2, len(recorder.Events))
+		util.Equals(t, 2, aggregator.records[tenant.GetName()][failures[0].key()].count)
+	})
+	t.Run("repeat after interval", func(t *testing.T) {
+		now = now.Add(failureEventInterval)
+		aggregator.flush(recorder, tenant, failures[:1])
+		util.Equals(t, 3, len(recorder.Events))
+		util.Equals(t, 1, len(aggregator.records[tenant.GetName()]))
+	})
+	t.Run("recover", func(t *testing.T) {
+		aggregator.flush(recorder, tenant, stepFailures{})
+		condition := meta.FindStatusCondition(tenant.Status.Conditions, conditionReconciled)
+		util.Equals(t, metav1.ConditionTrue, condition.Status)
+		_, exists := aggregator.records[tenant.GetName()]
+		util.Equals(t, false, exists)
+	})
+}
+
+func TestStalled(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	tenant := g.tenantObj.DeepCopy()
+
+	c := Controller{failures: newFailureAggregator()}
+	failures := stepFailures{}
+	failures.add(failureBinding, messageBindingFailed)
+	for i := 1; i < stalledThreshold; i++ {
+		c.recordSyncResult(tenant, failures)
+	}
+	util.Equals(t, stalledThreshold-1, tenant.Status.Retries)
+	util.Equals(t, false, meta.IsStatusConditionTrue(tenant.Status.Conditions, conditionStalled))
+	c.recordSyncResult(tenant, failures)
+	util.Equals(t, true, meta.IsStatusConditionTrue(tenant.Status.Conditions, conditionStalled))
+
+	c.recordSyncResult(tenant, stepFailures{})
+	util.Equals(t, 0, tenant.Status.Retries)
+	util.Equals(t, (*metav1.Time)(nil), tenant.Status.LastFailure)
+	util.Equals(t, (*metav1.Condition)(nil), meta.FindStatusCondition(tenant.Status.Conditions, conditionStalled))
+}
+
+func TestForceReconcile(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	tenant := g.tenantObj.DeepCopy()
+	tenant.SetName("force-reconcile-test")
+	tenant.SetAnnotations(map[string]string{AnnotationForceReconcile: "true"})
+	tenant.Status.Retries = stalledThreshold
+	edgenetclientset.CoreV1alpha().Tenants().Create(context.TODO(), tenant, metav1.CreateOptions{})
+	time.Sleep(250 * time.Millisecond)
+
+	tenant, err := edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenant.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	_, exists := tenant.GetAnnotations()[AnnotationForceReconcile]
+	util.Equals(t, false, exists)
+	util.Equals(t, 0, tenant.Status.Retries)
+}
+
+func TestOnboarding(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	tenant := g.tenantObj.DeepCopy()
+	tenant.SetName("onboarding-test")
+	edgenetclientset.CoreV1alpha().Tenants().Create(context.TODO(), tenant, metav1.CreateOptions{})
+	time.Sleep(250 * time.Millisecond)
+	tenant, err := edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenant.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Assert(t, tenant.Status.Onboarding != nil, "onboarding is missing")
+	util.Equals(t, stepDone, tenant.Status.Onboarding.Step)
+
+	t.Run("resume", func(t *testing.T) {
+		c := &Controller{}
+		steps := c.onboardingSteps()
+		tenant := g.tenantObj.DeepCopy()
+		tenant.SetGeneration(2)
+		util.Equals(t, 0, resumeStep(tenant, steps))
+		tenant.Status.Onboarding = &corev1alpha.Onboarding{Step: stepRBAC, Failed: true, Generation: 2}
+		tenant.Status.State = failure
+		util.Equals(t, 2, resumeStep(tenant, steps))
+		// A new spec or an established tenant runs all the steps
+		tenant.SetGeneration(3)
+		util.Equals(t, 0, resumeStep(tenant, steps))
+		tenant.SetGeneration(2)
+		tenant.Status.State = established
+		util.Equals(t, 0, resumeStep(tenant, steps))
+	})
+}
+
+func TestOwnerChange(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	tenant := g.tenantObj.DeepCopy()
+	tenant.SetName("owner-change-test")
+	edgenetclientset.CoreV1alpha().Tenants().Create(context.TODO(), tenant, metav1.CreateOptions{})
+	time.Sleep(250 * time.Millisecond)
+	formerBinding := fmt.Sprintf("edgenet:%s:tenants:%s-owner-%s", tenant.GetName(), tenant.GetName(), tenant.Spec.Contact.Handle)
+	_, err := kubeclientset.RbacV1().ClusterRoleBindings().Get(context.TODO(), formerBinding, metav1.GetOptions{})
+	util.OK(t, err)
+
+	tenant, err = edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenant.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	tenant.Spec.Contact.Handle = "janedoe"
+	tenant.Spec.Contact.Email = "jane.doe@edge-net.org"
+	edgenetclientset.CoreV1alpha().Tenants().Update(context.TODO(), tenant, metav1.UpdateOptions{})
+	time.Sleep(250 * time.Millisecond)
+
+	t.Run("cluster role binding", func(t *testing.T) {
+		clusterRoleBinding, err := kubeclientset.RbacV1().ClusterRoleBindings().Get(context.TODO(), fmt.Sprintf("edgenet:%s:tenants:%s-owner-janedoe", tenant.GetName(), tenant.GetName()), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, "jane.doe@edge-net.org", clusterRoleBinding.Subjects[0].Name)
+		_, err = kubeclientset.RbacV1().ClusterRoleBindings().Get(context.TODO(), formerBinding, metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+	})
+	t.Run("role binding", func(t *testing.T) {
+		roleBinding, err := kubeclientset.RbacV1().RoleBindings(tenant.GetName()).Get(context.TODO(), "edgenet:tenant-owner", metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, 1, len(roleBinding.Subjects))
+		util.Equals(t, "jane.doe@edge-net.org", roleBinding.Subjects[0].Name)
+	})
+	t.Run("former owners", func(t *testing.T) {
+		// Only the owner bindings of the tenant are looked up
+		other := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "owner-change-other",
+			Labels: map[string]string{"edge-net.io/generated": "true", "edge-net.io/tenant": "other"}},
+			RoleRef:  rbacv1.RoleRef{Kind: "ClusterRole", Name: fmt.Sprintf("edgenet:%s:tenants:%s-owner", tenant.GetName(), tenant.GetName())},
+			Subjects: []rbacv1.Subject{{Kind: "User", Name: "joe.doe@edge-net.org", APIGroup: "rbac.authorization.k8s.io"}}}
+		_, err := kubeclientset.RbacV1().ClusterRoleBindings().Create(context.TODO(), other, metav1.CreateOptions{})
+		util.OK(t, err)
+		c := &Controller{kubeclientset: kubeclientset}
+		former, err := c.formerOwners(context.TODO(), tenant, fmt.Sprintf("edgenet:%s:tenants:%s-owner", tenant.GetName(), tenant.GetName()))
+		util.OK(t, err)
+		util.Equals(t, 0, len(former.subjects))
+		util.Equals(t, 0, len(former.clusterRoleBindings))
+	})
+}
+
+func TestMultipleOwners(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	tenant := g.tenantObj.DeepCopy()
+	tenant.SetName("multiple-owners-test")
+	tenant.Spec.Owners = []corev1alpha.Contact{{Handle: "janedoe", Email: "jane.doe@edge-net.org"}}
+	tenant.Spec.Admins = []corev1alpha.Contact{{Handle: "joesmith", Email: "joe.smith@edge-net.org"}}
+	edgenetclientset.CoreV1alpha().Tenants().Create(context.TODO(), tenant, metav1.CreateOptions{})
+	time.Sleep(250 * time.Millisecond)
+	ownerBinding := fmt.Sprintf("edgenet:%s:tenants:%s-owner-janedoe", tenant.GetName(), tenant.GetName())
+	_, err := kubeclientset.RbacV1().ClusterRoleBindings().Get(context.TODO(), ownerBinding, metav1.GetOptions{})
+	util.OK(t, err)
+	_, err = kubeclientset.RbacV1().ClusterRoleBindings().Get(context.TODO(), fmt.Sprintf("edgenet:%s:tenants:%s-owner-johndoe", tenant.GetName(), tenant.GetName()), metav1.GetOptions{})
+	util.OK(t, err)
+	roleBindingRaw, err := kubeclientset.RbacV1().RoleBindings(tenant.GetName()).List(context.TODO(), metav1.ListOptions{LabelSelector: "edge-net.io/member-binding=true"})
+	util.OK(t, err)
+	util.Equals(t, 2, len(roleBindingRaw.Items))
+
+	t.Run("owner removal", func(t *testing.T) {
+		tenant, err := edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenant.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		tenant.Spec.Owners = nil
+		edgenetclientset.CoreV1alpha().Tenants().Update(context.TODO(), tenant, metav1.UpdateOptions{})
+		time.Sleep(250 * time.Millisecond)
+		tenant, err = edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenant.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, established, tenant.Status.State)
+		_, err = kubeclientset.RbacV1().ClusterRoleBindings().Get(context.TODO(), ownerBinding, metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+		roleBindingRaw, err := kubeclientset.RbacV1().RoleBindings(tenant.GetName()).List(context.TODO(), metav1.ListOptions{LabelSelector: "edge-net.io/member-binding=true"})
+		util.OK(t, err)
+		util.Equals(t, 1, len(roleBindingRaw.Items))
+		util.Equals(t, "joe.smith@edge-net.org", roleBindingRaw.Items[0].Subjects[0].Name)
+	})
+}
+
+func TestSubNamespacePolicies(t *testing.T) {
+	client := testclient.NewSimpleClientset()
+	c := &Controller{kubeclientset: client}
+	g := TestGroup{}
+	g.Init()
+	tenant := g.tenantObj.DeepCopy()
+	tenant.SetName("policy-sync")
+
+	generated := map[string]string{"edge-net.io/generated": "true"}
+	for _, name := range []string{"subnamespace-a", "subnamespace-b"} {
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"edge-net.io/tenant": tenant.GetName(), "edge-net.io/kind": "sub"}}}
+		client.CoreV1().Namespaces().Create(context.TODO(), namespace, metav1.CreateOptions{})
+		// The permissive policy of the previous mode and a policy of the tenant's own
+		client.NetworkingV1().NetworkPolicies(name).Create(context.TODO(), &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "baseline", Labels: generated}}, metav1.CreateOptions{})
+		client.NetworkingV1().NetworkPolicies(name).Create(context.TODO(), &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "allow-web"}}, metav1.CreateOptions{})
+	}
+	defaultDeny := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "default-deny-ingress", Labels: generated}}
+	defaultDeny.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+	client.NetworkingV1().NetworkPolicies(tenant.GetName()).Create(context.TODO(), defaultDeny, metav1.CreateOptions{})
+
+	t.Run("rollback", func(t *testing.T) {
+		client.PrependReactor("create", "networkpolicies", func(action ktesting.Action) (bool, runtime.Object, error) {
+			if action.GetNamespace() == "subnamespace-b" {
+				return true, nil, fmt.Errorf("admission denied")
+			}
+			return false, nil, nil
+		})
+		err := c.syncSubNamespacePolicies(context.TODO(), tenant)
+		util.Assert(t, err != nil, "failure is not reported")
+		util.Equals(t, []corev1alpha.NamespacePolicySync{
+			{Namespace: "subnamespace-a", State: policyRolledBack},
+			{Namespace: "subnamespace-b", State: policyFailure, Message: "admission denied"},
+		}, tenant.Status.NetworkPolicies)
+		for _, namespace := range []string{"subnamespace-a", "subnamespace-b"} {
+			_, err = client.NetworkingV1().NetworkPolicies(namespace).Get(context.TODO(), "baseline", metav1.GetOptions{})
+			util.OK(t, err)
+			_, err = client.NetworkingV1().NetworkPolicies(namespace).Get(context.TODO(), "default-deny-ingress", metav1.GetOptions{})
+			util.Equals(t, true, errors.IsNotFound(err))
+		}
+		client.ReactionChain = client.ReactionChain[1:]
+	})
+	t.Run("sync", func(t *testing.T) {
+		util.OK(t, c.syncSubNamespacePolicies(context.TODO(), tenant))
+		util.Equals(t, []corev1alpha.NamespacePolicySync{
+			{Namespace: "subnamespace-a", State: policySynced},
+			{Namespace: "subnamespace-b", State: policySynced},
+		}, tenant.Status.NetworkPolicies)
+		for _, namespace := range []string{"subnamespace-a", "subnamespace-b"} {
+			networkPolicy, err := client.NetworkingV1().NetworkPolicies(namespace).Get(context.TODO(), "default-deny-ingress", metav1.GetOptions{})
+			util.OK(t, err)
+			util.Equals(t, defaultDeny.Spec, networkPolicy.Spec)
+			_, err = client.NetworkingV1().NetworkPolicies(namespace).Get(context.TODO(), "baseline", metav1.GetOptions{})
+			util.Equals(t, true, errors.IsNotFound(err))
+			_, err = client.NetworkingV1().NetworkPolicies(namespace).Get(context.TODO(), "allow-web", metav1.GetOptions{})
+			util.OK(t, err)
+		}
+	})
+}
+
+func TestNamespaceAnnotations(t *testing.T) {
+	client := testclient.NewSimpleClientset()
+	c := &Controller{kubeclientset: client, baselinePolicies: true}
+	g := TestGroup{}
+	g.Init()
+	tenant := g.tenantObj.DeepCopy()
+	tenant.SetName("annotated")
+	tenant.Status.State = established
+
+	core := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: tenant.GetName(), Labels: map[string]string{"edge-net.io/tenant": tenant.GetName(), "edge-net.io/kind": "core"}}}
+	sub := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "annotated-sub", Annotations: map[string]string{"owner": "team"}, Labels: map[string]string{"edge-net.io/tenant": tenant.GetName(), "edge-net.io/kind": "sub"}}}
+	other := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{"edge-net.io/tenant": "other", "edge-net.io/kind": "core"}}}
+	for _, namespace := range []*corev1.Namespace{core, sub, other} {
+		client.CoreV1().Namespaces().Create(context.TODO(), namespace, metav1.CreateOptions{})
+	}
+	quota := &corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: "core-quota", Namespace: tenant.GetName()}}
+	quota.Spec.Hard = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8"), corev1.ResourceMemory: resource.MustParse("8Gi")}
+	client.CoreV1().ResourceQuotas(tenant.GetName()).Create(context.TODO(), quota, metav1.CreateOptions{})
+
+	portRange, err := access.AllocatePortRange(context.TODO(), tenant.GetName())
+	util.OK(t, err)
+
+	t.Run("annotate", func(t *testing.T) {
+		util.OK(t, c.annotateNamespaces(context.TODO(), tenant))
+		coreNamespace, err := client.CoreV1().Namespaces().Get(context.TODO(), core.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, established, coreNamespace.GetAnnotations()[AnnotationTenantState])
+		util.Equals(t, isolationBaseline, coreNamespace.GetAnnotations()[AnnotationIsolation])
+		util.Equals(t, quotaHash(quota.Spec.Hard), coreNamespace.GetAnnotations()[AnnotationQuotaHash])
+		_, err = time.Parse(time.RFC3339, coreNamespace.GetAnnotations()[AnnotationLastReconcile])
+		util.OK(t, err)
+		util.Equals(t, portRange.String(), coreNamespace.GetAnnotations()[AnnotationPortRange])
+		util.Equals(t, podSecurityRestricted, coreNamespace.GetAnnotations()[AnnotationPodSecurity])
+		_, exists := coreNamespace.GetAnnotations()[AnnotationPodSecurityExemptions]
+		util.Equals(t, false, exists)
+
+		subNamespace, err := client.CoreV1().Namespaces().Get(context.TODO(), sub.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, "team", subNamespace.GetAnnotations()["owner"])
+		util.Equals(t, established, subNamespace.GetAnnotations()[AnnotationTenantState])
+		_, exists = subNamespace.GetAnnotations()[AnnotationQuotaHash]
+		util.Equals(t, false, exists)
+
+		otherNamespace, err := client.CoreV1().Namespaces().Get(context.TODO(), other.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, 0, len(otherNamespace.GetAnnotations()))
+	})
+	t.Run("opt out", func(t *testing.T) {
+		optOut := tenant.DeepCopy()
+		optOut.SetAnnotations(map[string]string{annotationBaselinePolicies: "false"})
+		util.OK(t, c.annotateNamespaces(context.TODO(), optOut))
+		coreNamespace, err := client.CoreV1().Namespaces().Get(context.TODO(), core.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, isolationPermissive, coreNamespace.GetAnnotations()[AnnotationIsolation])
+	})
+	t.Run("quota hash", func(t *testing.T) {
+		hash := quotaHash(quota.Spec.Hard)
+		util.Equals(t, hash, quotaHash(quota.Spec.DeepCopy().Hard))
+		changed := quota.Spec.Hard.DeepCopy()
+		changed[corev1.ResourceCPU] = resource.MustParse("16")
+		util.Equals(t, false, hash == quotaHash(changed))
+	})
+}
+
+func TestThrottledRecorder(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	tenant := g.tenantObj.DeepCopy()
+	tenant.SetName("flapping")
+	other := g.tenantObj.DeepCopy()
+	other.SetName("steady")
+
+	fake := record.NewFakeRecorder(100)
+	recorder := newThrottledRecorder(fake)
+	now := time.Now()
+	recorder.now = func() time.Time { return now }
+	recorder.metrics = newSuppressedEvents()
+
+	t.Run("deduplication", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			recorder.Event(tenant, corev1.EventTypeWarning, failureBinding, messageBindingFailed)
+		}
+		util.Equals(t, 1, len(fake.Events))
+		<-fake.Events
+		util.Equals(t, float64(4), promtestutil.ToFloat64(recorder.metrics.WithLabelValues(failureBinding)))
+
+		now = now.Add(eventDedupWindow)
+		recorder.Event(tenant, corev1.EventTypeWarning, failureBinding, messageBindingFailed)
+		util.Equals(t, 1, len(fake.Events))
+		event := <-fake.Events
+		util.Assert(t, strings.Contains(event, "(occurred 5 times since"), "suppressed events are not summarized: %s", event)
+	})
+	t.Run("burst", func(t *testing.T) {
+		now = now.Add(time.Duration(eventBurst) * eventRefill)
+		for i := 0; i < eventBurst+3; i++ {
+			recorder.Eventf(tenant, corev1.EventTypeWarning, failureCreation, "attempt %d failed", i)
+		}
+		util.Equals(t, eventBurst, len(fake.Events))
+		// The budget is per object
+		recorder.Event(other, corev1.EventTypeNormal, successSynced, messageResourceSynced)
+		util.Equals(t, eventBurst+1, len(fake.Events))
+		for len(fake.Events) > 0 {
+			<-fake.Events
+		}
+
+		now = now.Add(eventRefill)
+		recorder.Eventf(tenant, corev1.EventTypeWarning, failureCreation, "attempt %d failed", eventBurst)
+		util.Equals(t, 1, len(fake.Events))
+		event := <-fake.Events
+		util.Assert(t, strings.Contains(event, "(occurred 2 times since"), "suppressed events are not summarized: %s", event)
+	})
+	t.Run("metrics", func(t *testing.T) {
+		exposition := fmt.Sprintf(`# HELP edgenet_tenant_events_suppressed_total Number of tenant controller events suppressed by deduplication or rate limiting.
+# TYPE edgenet_tenant_events_suppressed_total counter
+edgenet_tenant_events_suppressed_total{reason=%q} 4
+edgenet_tenant_events_suppressed_total{reason=%q} 3
+`, failureBinding, failureCreation)
+		util.OK(t, promtestutil.CollectAndCompare(recorder.metrics, strings.NewReader(exposition)))
+	})
+}
+
+func TestTokenPolicy(t *testing.T) {
+	client := testclient.NewSimpleClientset()
+	c := &Controller{kubeclientset: client}
+	g := TestGroup{}
+	g.Init()
+	tenant := g.tenantObj.DeepCopy()
+	tenant.SetName("tokens")
+
+	automount := true
+	objects := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tokens", Labels: map[string]string{"edge-net.io/tenant": "tokens", "edge-net.io/kind": "core"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tokens-sub", Labels: map[string]string{"edge-net.io/tenant": "tokens", "edge-net.io/kind": "sub"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{"edge-net.io/tenant": "other", "edge-net.io/kind": "core"}}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "tokens"}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "tokens-sub"}, AutomountServiceAccountToken: &automount},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "builder", Namespace: "tokens"}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "other"}},
+	}
+	for _, object := range objects {
+		util.OK(t, client.Tracker().Add(object))
+	}
+	automountOf := func(namespace, name string) *bool {
+		serviceAccount, err := client.CoreV1().ServiceAccounts(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		util.OK(t, err)
+		return serviceAccount.AutomountServiceAccountToken
+	}
+
+	util.OK(t, c.applyTokenPolicy(context.TODO(), tenant))
+	util.Equals(t, false, *automountOf("tokens", "default"))
+	util.Equals(t, false, *automountOf("tokens-sub", "default"))
+	// The service accounts that the tenant creates and the other tenants are left alone
+	util.Equals(t, (*bool)(nil), automountOf("tokens", "builder"))
+	util.Equals(t, (*bool)(nil), automountOf("other", "default"))
+
+	t.Run("tier", func(t *testing.T) {
+		policies := access.TokenPolicies
+		defer func() { access.TokenPolicies = policies }()
+		access.TokenPolicies = map[string]access.TokenPolicy{"community": {}, "paying": {Automount: true, Lifetime: time.Hour}}
+		tenant.Spec.Priority = &corev1alpha.Priority{Tier: "paying"}
+		util.OK(t, c.applyTokenPolicy(context.TODO(), tenant))
+		util.Equals(t, true, *automountOf("tokens", "default"))
+	})
+	t.Run("default service account created later", func(t *testing.T) {
+		namespaceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		c := &Controller{namespacesLister: corelisters.NewNamespaceLister(namespaceIndexer)}
+		namespaceIndexer.Add(objects[1])
+		util.Equals(t, 0, len(c.mapServiceAccount(&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "builder", Namespace: "tokens-sub"}})))
+		util.Equals(t, []reconcile.Request{request("tokens")}, c.mapServiceAccount(&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "tokens-sub"}}))
+	})
+}
+
+func TestMesh(t *testing.T) {
+	client := testclient.NewSimpleClientset()
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	c := &Controller{kubeclientset: client, dynamicclientset: dynamicClient}
+	g := TestGroup{}
+	g.Init()
+	tenant := g.tenantObj.DeepCopy()
+	tenant.SetName("mesh")
+	tenant.Spec.Mesh = &corev1alpha.Mesh{Provider: "istio"}
+
+	objects := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "mesh", Labels: map[string]string{"edge-net.io/tenant": "mesh", "edge-net.io/kind": "core"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "mesh-sub", Labels: map[string]string{"edge-net.io/tenant": "mesh", "edge-net.io/kind": "sub"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{"edge-net.io/tenant": "other", "edge-net.io/kind": "core"}}},
+	}
+	for _, object := range objects {
+		util.OK(t, client.Tracker().Add(object))
+	}
+	namespaceOf := func(name string) *corev1.Namespace {
+		namespace, err := client.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+		util.OK(t, err)
+		return namespace
+	}
+
+	util.OK(t, c.applyMesh(context.TODO(), tenant))
+	for _, name := range []string{"mesh", "mesh-sub"} {
+		util.Equals(t, "enabled", namespaceOf(name).GetLabels()["istio-injection"])
+		util.Equals(t, "istio", namespaceOf(name).GetAnnotations()[annotationMesh])
+		peerAuthentication, err := dynamicClient.Resource(peerAuthenticationGVR).Namespace(name).Get(context.TODO(), meshPolicyName, metav1.GetOptions{})
+		util.OK(t, err)
+		mode, _, _ := unstructured.NestedString(peerAuthentication.Object, "spec", "mtls", "mode")
+		util.Equals(t, "STRICT", mode)
+		authorizationPolicy, err := dynamicClient.Resource(istioAuthorizationPolicyGVR).Namespace(name).Get(context.TODO(), meshPolicyName, metav1.GetOptions{})
+		util.OK(t, err)
+		rules, _, _ := unstructured.NestedSlice(authorizationPolicy.Object, "spec", "rules")
+		from := rules[0].(map[string]interface{})["from"].([]interface{})
+		sources, _, _ := unstructured.NestedStringSlice(from[0].(map[string]interface{}), "source", "namespaces")
+		util.Equals(t, []string{"mesh", "mesh-sub", "istio-system"}, sources)
+	}
+	// The namespaces of the other tenants stay out of the mesh
+	util.Equals(t, "", namespaceOf("other").GetLabels()["istio-injection"])
+	_, err := dynamicClient.Resource(peerAuthenticationGVR).Namespace("other").Get(context.TODO(), meshPolicyName, metav1.GetOptions{})
+	util.Equals(t, true, errors.IsNotFound(err))
+
+	t.Run("provider change", func(t *testing.T) {
+		tenant.Spec.Mesh = &corev1alpha.Mesh{Provider: "linkerd"}
+		util.OK(t, c.applyMesh(context.TODO(), tenant))
+		namespace := namespaceOf("mesh")
+		util.Equals(t, "", namespace.GetLabels()["istio-injection"])
+		util.Equals(t, "enabled", namespace.GetAnnotations()["linkerd.io/inject"])
+		util.Equals(t, "linkerd", namespace.GetAnnotations()[annotationMesh])
+		_, err := dynamicClient.Resource(peerAuthenticationGVR).Namespace("mesh").Get(context.TODO(), meshPolicyName, metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+		authentication, err := dynamicClient.Resource(meshTLSAuthenticationGVR).Namespace("mesh").Get(context.TODO(), meshPolicyName, metav1.GetOptions{})
+		util.OK(t, err)
+		identityRefs, _, _ := unstructured.NestedSlice(authentication.Object, "spec", "identityRefs")
+		util.Equals(t, 3, len(identityRefs))
+		authorizationPolicy, err := dynamicClient.Resource(linkerdAuthorizationPolicyGVR).Namespace("mesh").Get(context.TODO(), meshPolicyName, metav1.GetOptions{})
+		util.OK(t, err)
+		target, _, _ := unstructured.NestedString(authorizationPolicy.Object, "spec", "targetRef", "name")
+		util.Equals(t, "mesh", target)
+	})
+	t.Run("opt out", func(t *testing.T) {
+		tenant.Spec.Mesh = nil
+		util.OK(t, c.applyMesh(context.TODO(), tenant))
+		for _, name := range []string{"mesh", "mesh-sub"} {
+			_, ok := namespaceOf(name).GetAnnotations()["linkerd.io/inject"]
+			util.Equals(t, false, ok)
+			_, ok = namespaceOf(name).GetAnnotations()[annotationMesh]
+			util.Equals(t, false, ok)
+			_, err := dynamicClient.Resource(linkerdAuthorizationPolicyGVR).Namespace(name).Get(context.TODO(), meshPolicyName, metav1.GetOptions{})
+			util.Equals(t, true, errors.IsNotFound(err))
+		}
+	})
+	t.Run("namespace created later", func(t *testing.T) {
+		tenantIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		c := &Controller{tenantsLister: listers.NewTenantLister(tenantIndexer)}
+		tenantIndexer.Add(tenant)
+		util.Equals(t, 0, len(c.mapNamespace(objects[1].(*corev1.Namespace))))
+		meshTenant := tenant.DeepCopy()
+		meshTenant.Spec.Mesh = &corev1alpha.Mesh{Provider: "istio"}
+		tenantIndexer.Update(meshTenant)
+		util.Equals(t, []reconcile.Request{request("mesh")}, c.mapNamespace(objects[1].(*corev1.Namespace)))
+	})
+}
+
+func TestNodePools(t *testing.T) {
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	nodePoolIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	c := &Controller{dynamicclientset: dynamicClient, nodePoolsLister: listers.NewNodePoolLister(nodePoolIndexer)}
+	g := TestGroup{}
+	g.Init()
+	tenant := g.tenantObj.DeepCopy()
+	tenant.SetName("pools")
+	tenant.Spec.NodePools = []string{"teaching", "gpu"}
+
+	nodePoolIndexer.Add(&corev1alpha.NodePool{ObjectMeta: metav1.ObjectMeta{Name: "teaching"},
+		Spec: corev1alpha.NodePoolSpec{Selector: metav1.LabelSelector{MatchLabels: map[string]string{"edge-net.io/institution": "sorbonne", "edge-net.io/connectivity": "fiber"}}}})
+	// The tenant is not placed while one of its pools is missing
+	util.Assert(t, c.applyNodePools(context.TODO(), tenant, nil) != nil, "missing node pool accepted")
+	_, err := dynamicClient.Resource(assignGVR).Get(context.TODO(), nodePoolMutationName("pools"), metav1.GetOptions{})
+	util.Equals(t, true, errors.IsNotFound(err))
+
+	nodePoolIndexer.Add(&corev1alpha.NodePool{ObjectMeta: metav1.ObjectMeta{Name: "gpu"},
+		Spec: corev1alpha.NodePoolSpec{Selector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "edge-net.io/gpu", Operator: metav1.LabelSelectorOpExists}}}}})
+	util.OK(t, c.applyNodePools(context.TODO(), tenant, nil))
+	assign, err := dynamicClient.Resource(assignGVR).Get(context.TODO(), nodePoolMutationName("pools"), metav1.GetOptions{})
+	util.OK(t, err)
+	terms, _, _ := unstructured.NestedSlice(assign.Object, "spec", "parameters", "assign", "value", "nodeSelectorTerms")
+	util.Equals(t, []interface{}{
+		map[string]interface{}{"matchExpressions": []interface{}{
+			map[string]interface{}{"key": "edge-net.io/connectivity", "operator": "In", "values": []interface{}{"fiber"}},
+			map[string]interface{}{"key": "edge-net.io/institution", "operator": "In", "values": []interface{}{"sorbonne"}},
+		}},
+		map[string]interface{}{"matchExpressions": []interface{}{
+			map[string]interface{}{"key": "edge-net.io/gpu", "operator": "Exists"},
+		}},
+	}, terms)
+	namespaceSelector, _, _ := unstructured.NestedStringMap(assign.Object, "spec", "match", "namespaceSelector", "matchLabels")
+	util.Equals(t, map[string]string{"edge-net.io/tenant": "pools"}, namespaceSelector)
+
+	t.Run("pool change", func(t *testing.T) {
+		tenantIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		c := &Controller{tenantsLister: listers.NewTenantLister(tenantIndexer)}
+		tenantIndexer.Add(tenant)
+		tenantIndexer.Add(g.tenantObj.DeepCopy())
+		util.Equals(t, 0, len(c.mapNodePool(&corev1alpha.NodePool{ObjectMeta: metav1.ObjectMeta{Name: "cloud"}})))
+		util.Equals(t, []reconcile.Request{request("pools")}, c.mapNodePool(&corev1alpha.NodePool{ObjectMeta: metav1.ObjectMeta{Name: "gpu"}}))
+	})
+	t.Run("all nodes", func(t *testing.T) {
+		tenant.Spec.NodePools = nil
+		util.OK(t, c.applyNodePools(context.TODO(), tenant, nil))
+		_, err := dynamicClient.Resource(assignGVR).Get(context.TODO(), nodePoolMutationName("pools"), metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+	})
+}
+
+func TestRequeueAfter(t *testing.T) {
+	ctx, r := withRequeue(context.TODO())
+	util.Equals(t, time.Duration(0), r.after)
+	requeueAfter(ctx, time.Minute)
+	requeueAfter(ctx, 10*time.Second)
+	requeueAfter(ctx, time.Hour)
+	// The step that needs the tenant back the soonest wins
+	util.Equals(t, 10*time.Second, r.after)
+	// Outside of a reconcile nothing is there to requeue the tenant
+	requeueAfter(context.TODO(), time.Minute)
+}
+
+func TestTieredQueue(t *testing.T) {
+	tiers := map[string]int{"new": tierNew, "flapping": tierEstablished, "steady": tierEstablished}
+	queue := newTierQueue(func(item interface{}) int { return tiers[item.(string)] })
+	defer queue.ShutDown()
+
+	t.Run("tiers", func(t *testing.T) {
+		queue.Add("flapping")
+		queue.Add("steady")
+		queue.Add("new")
+		queue.Add("flapping")
+		util.Equals(t, 3, queue.Len())
+		for _, expected := range []string{"new", "flapping", "steady"} {
+			item, shutdown := queue.Get()
+			util.Equals(t, false, shutdown)
+			util.Equals(t, expected, item)
+			queue.Done(item)
+		}
+	})
+	t.Run("promotion", func(t *testing.T) {
+		queue.Add("flapping")
+		queue.Add("steady")
+		tiers["steady"] = tierNew
+		queue.Add("steady")
+		item, _ := queue.Get()
+		util.Equals(t, "steady", item)
+		queue.Done(item)
+		item, _ = queue.Get()
+		util.Equals(t, "flapping", item)
+		queue.Done(item)
+		tiers["steady"] = tierEstablished
+	})
+	t.Run("added while processing", func(t *testing.T) {
+		queue.Add("flapping")
+		item, _ := queue.Get()
+		queue.Add("flapping")
+		util.Equals(t, 0, queue.Len())
+		queue.Done(item)
+		util.Equals(t, 1, queue.Len())
+		item, _ = queue.Get()
+		queue.Done(item)
+	})
+	t.Run("rate per key", func(t *testing.T) {
+		limiter := newKeyRateLimiter(1, 2)
+		now := time.Now()
+		limiter.now = func() time.Time { return now }
+		util.Equals(t, time.Duration(0), limiter.reserve("flapping"))
+		util.Equals(t, time.Duration(0), limiter.reserve("flapping"))
+		util.Equals(t, time.Second, limiter.reserve("flapping"))
+		// The flapping tenant delays nobody but itself
+		util.Equals(t, time.Duration(0), limiter.reserve("steady"))
+		util.Equals(t, time.Second, limiter.reserve("flapping"))
+		now = now.Add(time.Second)
+		util.Equals(t, time.Duration(0), limiter.reserve("flapping"))
+	})
+	t.Run("delayed once per key", func(t *testing.T) {
+		QueueKeyQPS, QueueKeyBurst = 1, 1
+		defer func() { QueueKeyQPS, QueueKeyBurst = 1.0, 10 }()
+		delayed := newTieredQueue("TieredQueueTest", func(item interface{}) int { return tiers[item.(string)] })
+		defer delayed.ShutDown()
+		delayed.Add("flapping")
+		for i := 0; i < 100; i++ {
+			delayed.Add("flapping")
+		}
+		util.Equals(t, 1, delayed.Len())
+		item, _ := delayed.Get()
+		delayed.Done(item)
+		item, _ = delayed.Get()
+		util.Equals(t, "flapping", item)
+		delayed.Done(item)
+		util.Equals(t, 0, delayed.Len())
+	})
+	t.Run("feed", func(t *testing.T) {
+		tiered := newTieredQueue("TierFeedTest", func(item interface{}) int { return tiers[item.(string)] })
+		managed := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+		defer managed.ShutDown()
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+		feed := newTierFeed(tiered, 1)
+		util.OK(t, feed.Start(ctx, nil, managed))
+		handOver := func() interface{} {
+			err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) { return managed.Len() == 1, nil })
+			util.OK(t, err)
+			item, _ := managed.Get()
+			managed.Done(item)
+			return item
+		}
+
+		tiered.Add("steady")
+		util.Equals(t, "steady", handOver())
+		// The single worker is busy, the keys wait in their tiers meanwhile
+		tiered.Add("flapping")
+		tiered.Add("new")
+		time.Sleep(50 * time.Millisecond)
+		util.Equals(t, 0, managed.Len())
+		feed.release("steady")
+		util.Equals(t, "new", handOver())
+		feed.release("new")
+		util.Equals(t, "flapping", handOver())
+		feed.release("flapping")
+		// The reconciles of the requeues free no slot
+		feed.release("flapping")
+	})
+}
+
+func TestTier(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	indexer.Add(&corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "tier-new"}})
+	indexer.Add(&corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "tier-established"}, Status: corev1alpha.TenantStatus{State: established}})
+	c := &Controller{tenantsLister: listers.NewTenantLister(indexer)}
+
+	util.Equals(t, tierNew, c.tier(request("tier-new")))
+	util.Equals(t, tierEstablished, c.tier(request("tier-established")))
+	// A deleted tenant is only cleaned up
+	util.Equals(t, tierEstablished, c.tier(request("tier-missing")))
+}
+
+func TestTeardown(t *testing.T) {
+	client := testclient.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "teardown-sub", Labels: map[string]string{"edge-net.io/tenant": "teardown", "edge-net.io/tenant-uid": "uid", "edge-net.io/cluster-uid": "cluster", "edge-net.io/kind": "sub"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "teardown", Labels: map[string]string{"edge-net.io/tenant": "teardown", "edge-net.io/tenant-uid": "uid", "edge-net.io/cluster-uid": "cluster", "edge-net.io/kind": "core"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other-sub", Labels: map[string]string{"edge-net.io/tenant": "other", "edge-net.io/tenant-uid": "other-uid", "edge-net.io/cluster-uid": "cluster", "edge-net.io/kind": "sub"}}},
+	)
+	worker := TeardownWorker{Clientset: client, EdgenetClientset: edgenettestclient.NewSimpleClientset()}
+	operationObj := &corev1alpha.Operation{ObjectMeta: metav1.ObjectMeta{Name: "teardown-teardown-1"},
+		Spec: corev1alpha.OperationSpec{Type: OperationTeardown, Initiator: corev1.ObjectReference{Kind: "Tenant", Name: "teardown"},
+			Parameters: map[string]string{"tenant": "teardown", "tenant-uid": "uid", "cluster-uid": "cluster"}}}
+
+	// The bindings are taken away before the namespaces
+	items, err := worker.Items(context.TODO(), operationObj)
+	util.OK(t, err)
+	util.Equals(t, []string{itemClusterRoleBindings, itemRoleBindings, itemExtensionRequests, "teardown-sub"}, items)
+
+	t.Run("cache", func(t *testing.T) {
+		namespaceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{index.ByTenant: index.Tenant})
+		namespaceRaw, err := client.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+		util.OK(t, err)
+		for i := range namespaceRaw.Items {
+			util.OK(t, namespaceIndexer.Add(&namespaceRaw.Items[i]))
+		}
+		cached := worker
+		cached.Namespaces = namespaceIndexer
+		cachedItems, err := cached.Items(context.TODO(), operationObj)
+		util.OK(t, err)
+		util.Equals(t, items, cachedItems)
+	})
+	t.Run("namespace", func(t *testing.T) {
+		util.OK(t, worker.Process(context.TODO(), operationObj, "teardown-sub"))
+		_, err := client.CoreV1().Namespaces().Get(context.TODO(), "teardown-sub", metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+		// A namespace removed in the meantime counts as done
+		util.OK(t, worker.Process(context.TODO(), operationObj, "teardown-sub"))
+	})
+	t.Run("progress", func(t *testing.T) {
+		util.Equals(t, []reconcile.Request{request("teardown")}, mapOperation(operationObj))
+		other := operationObj.DeepCopy()
+		other.Spec.Initiator.Kind = "SubNamespace"
+		util.Equals(t, 0, len(mapOperation(other)))
+	})
+}
+
+func TestPurge(t *testing.T) {
+	now := metav1.Now()
+	tenantObj := &corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "purge", UID: "8a2c3f9b-purge",
+		Annotations: map[string]string{purge.Annotation: "true"}}}
+	c := &Controller{
+		kubeclientset: testclient.NewSimpleClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "purge", Labels: map[string]string{"edge-net.io/tenant": "purge", "edge-net.io/kind": "core"}}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "registry-credentials", Namespace: "purge", Labels: map[string]string{"edge-net.io/generated": "true"}}}),
+		edgenetclientset: edgenettestclient.NewSimpleClientset(tenantObj),
+		recorder:         record.NewFakeRecorder(100),
+	}
+
+	// The annotated tenant gets the finalizer before it is deleted
+	tenantCopy := tenantObj.DeepCopy()
+	util.OK(t, c.requestPurge(context.TODO(), tenantCopy))
+	tenantCopy, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), "purge", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, []string{purge.Finalizer}, tenantCopy.GetFinalizers())
+	tenantCopy.SetDeletionTimestamp(&now)
+
+	// The deletion waits as long as the report cannot be signed
+	purge.Signer = nil
+	util.Assert(t, c.ProcessTenant(context.TODO(), tenantCopy.DeepCopy()) != nil, "tenant purged without a signing key")
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	util.OK(t, err)
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	util.OK(t, err)
+	purge.Signer, err = signature.NewSigner("edgenet-purge", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}))
+	util.OK(t, err)
+	defer func() { purge.Signer = nil }()
+
+	util.OK(t, c.ProcessTenant(context.TODO(), tenantCopy.DeepCopy()))
+	purged, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), "purge", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, 0, len(purged.GetFinalizers()))
+	_, err = c.kubeclientset.CoreV1().Secrets("purge").Get(context.TODO(), "registry-credentials", metav1.GetOptions{})
+	util.Equals(t, true, errors.IsNotFound(err))
+	_, err = c.kubeclientset.CoreV1().ConfigMaps(purge.ReportNamespace).Get(context.TODO(), "deletion-report-purge-8a2c3f9b", metav1.GetOptions{})
+	util.OK(t, err)
+}
+
+func TestPolicies(t *testing.T) {
+	// The policies deny the tenants without an address
+	opa := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		util.Equals(t, "/v1/data/edgenet/tenant/establishment", r.URL.Path)
+		body := struct {
+			Input struct {
+				Object corev1alpha.Tenant `json:"object"`
+			} `json:"input"`
+		}{}
+		util.OK(t, json.NewDecoder(r.Body).Decode(&body))
+		if body.Input.Object.Spec.Address.City == "" {
+			w.Write([]byte(`{"result": {"deny": ["the address is missing"]}}`))
+			return
+		}
+		w.Write([]byte(`{"result": {}}`))
+	}))
+	defer opa.Close()
+	util.OK(t, policy.Configure(policy.BackendOPA, opa.URL+"/v1/data/edgenet", ""))
+	defer func() { policy.URL = "" }()
+
+	c := &Controller{recorder: record.NewFakeRecorder(100)}
+	tenantCopy := &corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "policy"}}
+	failures := stepFailures{}
+	ctx, r := withRequeue(context.TODO())
+	util.Equals(t, false, c.admitted(ctx, tenantCopy, &failures))
+	util.Equals(t, failure, tenantCopy.Status.State)
+	util.Equals(t, "Tenant establishment denied by the policies: the address is missing", tenantCopy.Status.Message)
+	util.Equals(t, 0, len(failures))
+	// The policies are consulted again in case they let the tenant through later on
+	util.Equals(t, PolicyRecheck, r.after)
+
+	tenantCopy.Spec.Address.City = "Paris"
+	util.Equals(t, true, c.admitted(context.TODO(), tenantCopy, &failures))
+
+	// The establishment waits while the policies cannot be consulted
+	opa.Close()
+	util.Equals(t, false, c.admitted(context.TODO(), tenantCopy, &failures))
+	util.Equals(t, 1, len(failures))
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"strings"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// egressPolicyName is the name of the DNS-aware egress policies in the tenant namespaces
+const egressPolicyName = "edgenet-tenant-egress"
+
+// ciliumNetworkPolicyGVR is the resource of the policies Cilium matches the domain names with
+var ciliumNetworkPolicyGVR = schema.GroupVersionResource{Group: "cilium.io", Version: "v2", Resource: "ciliumnetworkpolicies"}
+
+// applyEgress lets the pods of the tenant reach the domain names its egress lists. The network
+// policies only know addresses, so the names are matched by Cilium that learns the addresses
+// behind them from the DNS answers. The policies go away once the tenant lists no names.
+func (c *Controller) applyEgress(ctx context.Context, tenantCopy *corev1alpha.Tenant) error {
+	namespaceRaw, err := c.tenantNamespaces(ctx, tenantCopy.GetName(), nil)
+	if err != nil {
+		return err
+	}
+	fqdns := []string{}
+	if tenantCopy.Spec.Egress != nil {
+		fqdns = tenantCopy.Spec.Egress.FQDNs
+	}
+	var egressErr error
+	for _, namespaceRow := range namespaceRaw {
+		if len(fqdns) == 0 {
+			err := c.dynamicclientset.Resource(ciliumNetworkPolicyGVR).Namespace(namespaceRow.GetName()).Delete(ctx, egressPolicyName, metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				egressErr = err
+			}
+			continue
+		}
+		if err := c.applyMeshPolicy(ctx, ciliumNetworkPolicyGVR, egressPolicy(namespaceRow.GetName(), tenantCopy.GetName(), fqdns)); err != nil {
+			egressErr = err
+		}
+	}
+	return egressErr
+}
+
+// egressPolicy returns the Cilium policy of a namespace of the tenant that lets its pods reach the
+// domain names, the lookups go through the DNS proxy of Cilium for the names to be learned
+func egressPolicy(namespace, tenant string, fqdns []string) *unstructured.Unstructured {
+	toFQDNs := []interface{}{}
+	for _, fqdn := range fqdns {
+		if strings.HasPrefix(fqdn, "*.") {
+			toFQDNs = append(toFQDNs, map[string]interface{}{"matchPattern": fqdn})
+		} else {
+			toFQDNs = append(toFQDNs, map[string]interface{}{"matchName": fqdn})
+		}
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": ciliumNetworkPolicyGVR.GroupVersion().String(),
+		"kind":       "CiliumNetworkPolicy",
+		"metadata": map[string]interface{}{
+			"name":      egressPolicyName,
+			"namespace": namespace,
+			"labels":    map[string]interface{}{"edge-net.io/generated": "true", "edge-net.io/tenant": tenant},
+		},
+		"spec": map[string]interface{}{
+			"endpointSelector": map[string]interface{}{},
+			"egress": []interface{}{
+				map[string]interface{}{
+					"toEndpoints": []interface{}{
+						map[string]interface{}{"matchLabels": map[string]interface{}{
+							"k8s:io.kubernetes.pod.namespace": "kube-system",
+							"k8s:k8s-app":                     "kube-dns",
+						}},
+					},
+					"toPorts": []interface{}{
+						map[string]interface{}{
+							"ports": []interface{}{map[string]interface{}{"port": "53", "protocol": "ANY"}},
+							"rules": map[string]interface{}{"dns": []interface{}{map[string]interface{}{"matchPattern": "*"}}},
+						},
+					},
+				},
+				map[string]interface{}{"toFQDNs": toFQDNs},
+			},
+		},
+	}}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// Condition type and reasons summarizing the sub-steps of a reconcile
+const (
+	conditionReconciled  = "Reconciled"
+	reasonAllSucceeded   = "AllSubStepsSucceeded"
+	reasonSubStepsFailed = "SubStepsFailed"
+	// failureEventInterval is the minimum time between two events of a repeating failure
+	failureEventInterval = 15 * time.Minute
+)
+
+// stepFailure is a sub-step that failed during a reconcile
+type stepFailure struct {
+	reason  string
+	message string
+}
+
+func (s stepFailure) key() string {
+	return fmt.Sprintf("%s/%s", s.reason, s.message)
+}
+
+// stepFailures collects the sub-steps that failed during a single reconcile
+type stepFailures []stepFailure
+
+func (s *stepFailures) add(reason, message string) {
+	*s = append(*s, stepFailure{reason: reason, message: message})
+}
+
+// failureRecord keeps track of a failure repeating over consecutive reconciles
+type failureRecord struct {
+	first     time.Time
+	last      time.Time
+	count     int
+	lastEvent time.Time
+}
+
+// failureAggregator coalesces repeated failures of tenants so that the event stream is not
+// flooded by a warning per failed sub-step on every resync.
+type failureAggregator struct {
+	mutex   sync.Mutex
+	records map[string]map[string]*failureRecord
+	now     func() time.Time
+}
+
+func newFailureAggregator() *failureAggregator {
+	return &failureAggregator{records: make(map[string]map[string]*failureRecord), now: time.Now}
+}
+
+// flush records the failures of a reconcile, emits an event for the failures occurring the
+// first time or repeating since the last interval, forgets the failures that are gone, and
+// summarizes the outcome as a status condition of the tenant.
+func (f *failureAggregator) flush(recorder record.EventRecorder, tenantCopy *corev1alpha.Tenant, failures stepFailures) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	now := f.now()
+	previous := f.records[tenantCopy.GetName()]
+	current := make(map[string]*failureRecord)
+	summary := []string{}
+	for _, failure := range failures {
+		if _, exists := current[failure.key()]; exists {
+			continue
+		}
+		entry, exists := previous[failure.key()]
+		if !exists {
+			entry = &failureRecord{first: now}
+		}
+		entry.last = now
+		entry.count++
+		if entry.lastEvent.IsZero() {
+			recorder.Event(tenantCopy, corev1.EventTypeWarning, failure.reason, failure.message)
+			entry.lastEvent = now
+		} else if now.Sub(entry.lastEvent) >= failureEventInterval {
+			recorder.Eventf(tenantCopy, corev1.EventTypeWarning, failure.reason, "%s (occurred %d times since %s)",
+				failure.message, entry.count, entry.first.Format(time.RFC3339))
+			entry.lastEvent = now
+		}
+		current[failure.key()] = entry
+		summary = append(summary, fmt.Sprintf("%s since %s", failure.message, entry.first.Format(time.RFC3339)))
+	}
+	if len(current) == 0 {
+		delete(f.records, tenantCopy.GetName())
+	} else {
+		f.records[tenantCopy.GetName()] = current
+	}
+
+	condition := metav1.Condition{Type: conditionReconciled, ObservedGeneration: tenantCopy.GetGeneration()}
+	if len(summary) == 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reasonAllSucceeded
+		condition.Message = messageResourceSynced
+	} else {
+		// The order of sub-steps is kept stable so that the status doesn't change needlessly
+		sort.Strings(summary)
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonSubStepsFailed
+		condition.Message = fmt.Sprintf("%d sub-step(s) failing: %s", len(summary), strings.Join(summary, "; "))
+	}
+	meta.SetStatusCondition(&tenantCopy.Status.Conditions, condition)
+}
+
+// forget drops the records of a tenant
+func (f *failureAggregator) forget(name string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	delete(f.records, name)
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Defaults of the ingress option of the tenants
+const (
+	defaultIngressController = "k8s.io/ingress-nginx"
+	defaultIngressIssuer     = "letsencrypt"
+)
+
+// assignMetadataGVR is the mutation of Gatekeeper that adds the annotations to the ingresses
+var assignMetadataGVR = schema.GroupVersionResource{Group: "mutations.gatekeeper.sh", Version: "v1alpha1", Resource: "assignmetadata"}
+
+// ingressMutations gives the suffix of the name of the mutation adding each annotation
+var ingressMutations = map[string]string{
+	"cert-manager.io/cluster-issuer":           "ingress-issuer",
+	"nginx.ingress.kubernetes.io/ssl-redirect": "ingress-ssl-redirect",
+}
+
+// ingressAnnotations returns the annotations set on every ingress of the tenant. The certificates
+// of the TLS hosts are issued by cert-manager, and the plain HTTP requests are redirected to them.
+func ingressAnnotations(ingress *corev1alpha.Ingress) map[string]string {
+	issuer := ingress.Issuer
+	if issuer == "" {
+		issuer = defaultIngressIssuer
+	}
+	return map[string]string{
+		"cert-manager.io/cluster-issuer":           issuer,
+		"nginx.ingress.kubernetes.io/ssl-redirect": "true",
+	}
+}
+
+// ingressClassName returns the name of the ingress class dedicated to the tenant
+func ingressClassName(tenant string) string {
+	return fmt.Sprintf("edgenet-tenant-%s", tenant)
+}
+
+// ingressMutationName returns the name of a mutation of the ingresses, the priority mutation of
+// the tenant already goes by the plain name
+func ingressMutationName(tenant, suffix string) string {
+	return fmt.Sprintf("edgenet-tenant-%s-%s", tenant, suffix)
+}
+
+// applyIngress creates the ingress class of the tenant and has it set on the ingresses in the tenant
+// namespaces along with the annotations, so that the ingresses of a tenant never go through the class
+// of another one.
+// All of them are removed when the tenant drops the ingress option.
+func (c *Controller) applyIngress(ctx context.Context, tenantCopy *corev1alpha.Tenant, ownerReferences []metav1.OwnerReference) error {
+	name := ingressClassName(tenantCopy.GetName())
+	ingress := tenantCopy.Spec.Ingress
+	if ingress == nil {
+		if err := c.kubeclientset.NetworkingV1().IngressClasses().Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err := c.dynamicclientset.Resource(assignGVR).Delete(ctx, ingressMutationName(tenantCopy.GetName(), "ingress-class"), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		for _, suffix := range ingressMutations {
+			if err := c.dynamicclientset.Resource(assignMetadataGVR).Delete(ctx, ingressMutationName(tenantCopy.GetName(), suffix), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	controller := ingress.Controller
+	if controller == "" {
+		controller = defaultIngressController
+	}
+	ingressClass := &networkingv1.IngressClass{ObjectMeta: metav1.ObjectMeta{Name: name, OwnerReferences: ownerReferences}}
+	ingressClass.SetLabels(map[string]string{"edge-net.io/generated": "true", "edge-net.io/tenant": tenantCopy.GetName()})
+	ingressClass.Spec.Controller = controller
+	// The controller of an ingress class is immutable, a change recreates the ingress class
+	if current, err := c.kubeclientset.NetworkingV1().IngressClasses().Get(ctx, name, metav1.GetOptions{}); err == nil {
+		if current.Spec.Controller != controller {
+			if err := c.kubeclientset.NetworkingV1().IngressClasses().Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			if _, err := c.kubeclientset.NetworkingV1().IngressClasses().Create(ctx, ingressClass, metav1.CreateOptions{}); err != nil {
+				return err
+			}
+		}
+	} else if errors.IsNotFound(err) {
+		if _, err := c.kubeclientset.NetworkingV1().IngressClasses().Create(ctx, ingressClass, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	} else {
+		return err
+	}
+
+	match := ingressMatch(tenantCopy.GetName())
+	assign := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": assignGVR.GroupVersion().String(),
+		"kind":       "Assign",
+		"metadata": map[string]interface{}{
+			"name":   ingressMutationName(tenantCopy.GetName(), "ingress-class"),
+			"labels": map[string]interface{}{"edge-net.io/generated": "true", "edge-net.io/tenant": tenantCopy.GetName()},
+		},
+		"spec": map[string]interface{}{
+			"applyTo": []interface{}{
+				map[string]interface{}{"groups": []interface{}{"networking.k8s.io"}, "kinds": []interface{}{"Ingress"}, "versions": []interface{}{"v1"}},
+			},
+			"match":    match,
+			"location": "spec.ingressClassName",
+			// The class requested by the ingress is overridden, it may belong to another tenant
+			"parameters": map[string]interface{}{"assign": map[string]interface{}{"value": name}},
+		},
+	}}
+	assign.SetOwnerReferences(ownerReferences)
+	if err := c.applyMutation(ctx, assignGVR, assign); err != nil {
+		return err
+	}
+	for annotation, value := range ingressAnnotations(ingress) {
+		assignMetadata := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": assignMetadataGVR.GroupVersion().String(),
+			"kind":       "AssignMetadata",
+			"metadata": map[string]interface{}{
+				"name":   ingressMutationName(tenantCopy.GetName(), ingressMutations[annotation]),
+				"labels": map[string]interface{}{"edge-net.io/generated": "true", "edge-net.io/tenant": tenantCopy.GetName()},
+			},
+			"spec": map[string]interface{}{
+				"match":      match,
+				"location":   fmt.Sprintf("metadata.annotations.%q", annotation),
+				"parameters": map[string]interface{}{"assign": map[string]interface{}{"value": value}},
+			},
+		}}
+		assignMetadata.SetOwnerReferences(ownerReferences)
+		if err := c.applyMutation(ctx, assignMetadataGVR, assignMetadata); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ingressMatch returns the match of the mutations, the ingresses in the core namespace and the
+// subnamespaces of the tenant
+func ingressMatch(tenant string) map[string]interface{} {
+	return map[string]interface{}{
+		"scope": "Namespaced",
+		"kinds": []interface{}{
+			map[string]interface{}{"apiGroups": []interface{}{"networking.k8s.io"}, "kinds": []interface{}{"Ingress"}},
+		},
+		"namespaceSelector": map[string]interface{}{
+			"matchLabels": map[string]interface{}{"edge-net.io/tenant": tenant},
+		},
+	}
+}
+
+// applyMutation creates the Gatekeeper mutation or updates its spec if it exists
+func (c *Controller) applyMutation(ctx context.Context, gvr schema.GroupVersionResource, mutation *unstructured.Unstructured) error {
+	_, err := c.dynamicclientset.Resource(gvr).Create(ctx, mutation, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		current, err := c.dynamicclientset.Resource(gvr).Get(ctx, mutation.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		current.Object["spec"] = mutation.Object["spec"]
+		_, err = c.dynamicclientset.Resource(gvr).Update(ctx, current, metav1.UpdateOptions{})
+		return err
+	}
+	return err
+}
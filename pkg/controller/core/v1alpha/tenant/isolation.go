@@ -0,0 +1,115 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/cni"
+	"github.com/EdgeNet-project/edgenet/pkg/mailer"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// Condition type, reasons, and event definitions of the network isolation state
+const (
+	conditionNetworkIsolation = "NetworkIsolation"
+	reasonEnforced            = "Enforced"
+	reasonDegraded            = "Degraded"
+	reasonUnknown             = "Unknown"
+	warningDegraded           = "Isolation Degraded"
+	warningCNI                = "CNI Capability"
+)
+
+// detectCapabilities finds out whether the installed CNI plugin enforces the network policies.
+// A change in the capabilities is recorded cluster-wide and all tenants are sent for a reconcile
+// so that their isolation state follows.
+func (c *Controller) detectCapabilities(ctx context.Context) {
+	capabilities, err := cni.Detect(ctx, c.kubeclientset)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't detect the CNI capabilities")
+		return
+	}
+	c.capabilitiesMutex.Lock()
+	changed := c.capabilities == nil || *c.capabilities != capabilities
+	c.capabilities = &capabilities
+	c.capabilitiesMutex.Unlock()
+	if !changed {
+		return
+	}
+
+	if err := cni.Record(ctx, c.kubeclientset, capabilities); err != nil {
+		klog.ErrorS(err, "Couldn't record the CNI capabilities")
+	}
+	if capabilities.Degraded() || capabilities.Unknown() {
+		if systemNamespace, err := c.kubeclientset.CoreV1().Namespaces().Get(ctx, "kube-system", metav1.GetOptions{}); err == nil {
+			c.recorder.Event(systemNamespace, corev1.EventTypeWarning, warningCNI, capabilities.Reason())
+		}
+	}
+	if tenantRaw, err := c.tenantsLister.List(labels.Everything()); err == nil {
+		for _, tenantRow := range tenantRaw {
+			c.resync <- event.GenericEvent{Object: tenantRow}
+		}
+	}
+}
+
+// setNetworkIsolation marks the isolation state of the tenant according to the CNI capabilities,
+// and notifies the tenant contact when the state turns to degraded.
+func (c *Controller) setNetworkIsolation(tenantCopy *corev1alpha.Tenant, clusterUID string) {
+	c.capabilitiesMutex.RLock()
+	defer c.capabilitiesMutex.RUnlock()
+	if c.capabilities == nil {
+		return
+	}
+	condition := metav1.Condition{Type: conditionNetworkIsolation, ObservedGeneration: tenantCopy.GetGeneration(), Message: c.capabilities.Reason()}
+	if c.capabilities.Unknown() {
+		// Nobody is alarmed about a plugin that might well enforce the policies
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = reasonUnknown
+	} else if c.capabilities.Degraded() {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonDegraded
+		if !meta.IsStatusConditionFalse(tenantCopy.Status.Conditions, conditionNetworkIsolation) {
+			c.recorder.Event(tenantCopy, corev1.EventTypeWarning, warningDegraded, condition.Message)
+			c.sendIsolationDegradedEmail(tenantCopy, clusterUID, condition.Message)
+		}
+	} else {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reasonEnforced
+	}
+	meta.SetStatusCondition(&tenantCopy.Status.Conditions, condition)
+}
+
+func (c *Controller) sendIsolationDegradedEmail(tenantCopy *corev1alpha.Tenant, clusterUID, reason string) {
+	email := new(mailer.Content)
+	email.Cluster = clusterUID
+	email.User = tenantCopy.Spec.Contact.Email
+	email.FirstName = tenantCopy.Spec.Contact.FirstName
+	email.LastName = tenantCopy.Spec.Contact.LastName
+	email.Subject = "[EdgeNet] Tenant network isolation degraded"
+	email.Recipient = []string{tenantCopy.Spec.Contact.Email}
+	email.NetworkIsolation = new(mailer.NetworkIsolation)
+	email.NetworkIsolation.Tenant = tenantCopy.GetName()
+	email.NetworkIsolation.Reason = reason
+	email.Send("tenant-isolation-degraded")
+}
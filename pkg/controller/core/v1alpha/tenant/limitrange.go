@@ -0,0 +1,91 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"reflect"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// limitRangeName is the name of the limit range in the tenant namespaces,
+// the subnamespace controller propagates it to the subnamespaces
+const limitRangeName = "tenant-limits"
+
+// Cluster defaults of the container limits, a tenant overrides them resource by resource
+var (
+	defaultContainerLimit = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("512Mi"),
+	}
+	defaultContainerRequest = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("100m"),
+		corev1.ResourceMemory: resource.MustParse("128Mi"),
+	}
+	maxContainerLimit = corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("2"),
+		corev1.ResourceMemory: resource.MustParse("4Gi"),
+	}
+)
+
+// applyLimitRange creates or updates the limit range of containers in the core namespace
+// so that a pod cannot consume the whole quota of the tenant
+func (c *Controller) applyLimitRange(ctx context.Context, tenantCopy *corev1alpha.Tenant) error {
+	limits := corev1alpha.ContainerLimits{}
+	if tenantCopy.Spec.ContainerLimits != nil {
+		limits = *tenantCopy.Spec.ContainerLimits
+	}
+	limitRangeItem := corev1.LimitRangeItem{
+		Type:           corev1.LimitTypeContainer,
+		Default:        mergeResourceList(defaultContainerLimit, limits.Default),
+		DefaultRequest: mergeResourceList(defaultContainerRequest, limits.DefaultRequest),
+		Max:            mergeResourceList(maxContainerLimit, limits.Max),
+	}
+
+	limitRange := &corev1.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: limitRangeName, Namespace: tenantCopy.GetName()}}
+	limitRange.SetLabels(map[string]string{"edge-net.io/generated": "true"})
+	limitRange.Spec.Limits = []corev1.LimitRangeItem{limitRangeItem}
+	_, err := c.kubeclientset.CoreV1().LimitRanges(tenantCopy.GetName()).Create(ctx, limitRange, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		current, err := c.kubeclientset.CoreV1().LimitRanges(tenantCopy.GetName()).Get(ctx, limitRangeName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if reflect.DeepEqual(current.Spec, limitRange.Spec) {
+			return nil
+		}
+		current.Spec = limitRange.Spec
+		_, err = c.kubeclientset.CoreV1().LimitRanges(tenantCopy.GetName()).Update(ctx, current, metav1.UpdateOptions{})
+		return err
+	}
+	return err
+}
+
+// mergeResourceList returns the defaults overridden by the given resources
+func mergeResourceList(defaults, resources corev1.ResourceList) corev1.ResourceList {
+	merged := defaults.DeepCopy()
+	for name, quantity := range resources {
+		merged[name] = quantity.DeepCopy()
+	}
+	return merged
+}
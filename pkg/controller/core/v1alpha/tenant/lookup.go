@@ -0,0 +1,63 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/index"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// tenantNamespaces returns the namespaces of the tenant whose labels also match the set. They
+// come from the informer cache, a controller built without the informers lists them from the
+// API server.
+func (c *Controller) tenantNamespaces(ctx context.Context, tenant string, set labels.Set) ([]*corev1.Namespace, error) {
+	if c.namespacesIndexer != nil {
+		return index.Namespaces(c.namespacesIndexer, tenant, set)
+	}
+	selector := labels.Merge(labels.Set{index.LabelTenant: tenant}, set)
+	namespaceRaw, err := c.kubeclientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	namespaces := make([]*corev1.Namespace, 0, len(namespaceRaw.Items))
+	for i := range namespaceRaw.Items {
+		namespaces = append(namespaces, &namespaceRaw.Items[i])
+	}
+	return namespaces, nil
+}
+
+// hasClusterRoles tells whether the cluster roles generated for the tenant on this cluster are
+// still there. Without the cache, the cluster roles are assumed to be there.
+func (c *Controller) hasClusterRoles(tenantCopy *corev1alpha.Tenant, clusterUID string) bool {
+	if c.clusterRolesIndexer == nil {
+		return true
+	}
+	clusterRoles, err := index.ClusterRoles(c.clusterRolesIndexer, tenantCopy.GetName(),
+		labels.Set{"edge-net.io/tenant-uid": string(tenantCopy.GetUID()), "edge-net.io/cluster-uid": clusterUID})
+	if err != nil {
+		klog.ErrorS(err, "Couldn't look the cluster roles up", "tenant", klog.KObj(tenantCopy))
+		return true
+	}
+	return len(clusterRoles) > 0
+}
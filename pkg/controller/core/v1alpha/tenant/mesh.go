@@ -0,0 +1,251 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Service meshes the tenants can join
+const (
+	meshIstio   = "istio"
+	meshLinkerd = "linkerd"
+)
+
+// annotationMesh records the mesh a namespace was put in, so that it is taken out of it alone
+const annotationMesh = "edge-net.io/mesh"
+
+// meshPolicyName is the name of the mesh policies in the tenant namespaces
+const meshPolicyName = "edgenet-tenant"
+
+// Mesh policies, Istio enforces mutual TLS with the peer authentications and Linkerd with the
+// authentications the authorization policies require
+var (
+	peerAuthenticationGVR         = schema.GroupVersionResource{Group: "security.istio.io", Version: "v1beta1", Resource: "peerauthentications"}
+	istioAuthorizationPolicyGVR   = schema.GroupVersionResource{Group: "security.istio.io", Version: "v1beta1", Resource: "authorizationpolicies"}
+	meshTLSAuthenticationGVR      = schema.GroupVersionResource{Group: "policy.linkerd.io", Version: "v1alpha1", Resource: "meshtlsauthentications"}
+	linkerdAuthorizationPolicyGVR = schema.GroupVersionResource{Group: "policy.linkerd.io", Version: "v1alpha1", Resource: "authorizationpolicies"}
+)
+
+// MeshSystemNamespaces are the namespaces of each mesh whose workloads, such as the ingress
+// gateways, reach the tenant namespaces besides the tenant itself
+var MeshSystemNamespaces = map[string][]string{
+	meshIstio:   {"istio-system"},
+	meshLinkerd: {"linkerd"},
+}
+
+// meshPolicyGVRs gives the policies generated in the tenant namespaces for each mesh
+var meshPolicyGVRs = map[string][]schema.GroupVersionResource{
+	meshIstio:   {peerAuthenticationGVR, istioAuthorizationPolicyGVR},
+	meshLinkerd: {meshTLSAuthenticationGVR, linkerdAuthorizationPolicyGVR},
+}
+
+// applyMesh puts the namespaces of the tenant in the mesh it opted in, where the sidecars are
+// injected into its pods, the traffic between them requires mutual TLS, and only the tenant and
+// the mesh itself reach them. The namespaces are taken out of the mesh once the tenant opts out.
+func (c *Controller) applyMesh(ctx context.Context, tenantCopy *corev1alpha.Tenant) error {
+	namespaceRaw, err := c.tenantNamespaces(ctx, tenantCopy.GetName(), nil)
+	if err != nil {
+		return err
+	}
+	provider := ""
+	if tenantCopy.Spec.Mesh != nil {
+		provider = tenantCopy.Spec.Mesh.Provider
+		if _, ok := meshPolicyGVRs[provider]; !ok {
+			return fmt.Errorf("unknown mesh %s", provider)
+		}
+	}
+	namespaces := []string{}
+	for _, namespaceRow := range namespaceRaw {
+		namespaces = append(namespaces, namespaceRow.GetName())
+	}
+	sort.Strings(namespaces)
+
+	var meshErr error
+	for _, namespaceRow := range namespaceRaw {
+		namespace := namespaceRow.DeepCopy()
+		current := namespace.GetAnnotations()[annotationMesh]
+		if current != "" && current != provider {
+			if err := c.leaveMesh(ctx, namespace, current); err != nil {
+				meshErr = err
+				continue
+			}
+		}
+		if provider == "" {
+			continue
+		}
+		if err := c.joinMesh(ctx, namespace, provider, tenantCopy.GetName(), namespaces); err != nil {
+			meshErr = err
+		}
+	}
+	return meshErr
+}
+
+// joinMesh has the sidecars injected in the namespace and applies the policies of the mesh
+func (c *Controller) joinMesh(ctx context.Context, namespace *corev1.Namespace, provider, tenant string, namespaces []string) error {
+	for i, policy := range meshPolicies(provider, namespace.GetName(), tenant, namespaces) {
+		if err := c.applyMeshPolicy(ctx, meshPolicyGVRs[provider][i], policy); err != nil {
+			return err
+		}
+	}
+	labels, annotations := namespace.GetLabels(), namespace.GetAnnotations()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	if annotations[annotationMesh] == provider {
+		return nil
+	}
+	switch provider {
+	case meshIstio:
+		labels["istio-injection"] = "enabled"
+	case meshLinkerd:
+		annotations["linkerd.io/inject"] = "enabled"
+	}
+	annotations[annotationMesh] = provider
+	namespace.SetLabels(labels)
+	namespace.SetAnnotations(annotations)
+	_, err := c.kubeclientset.CoreV1().Namespaces().Update(ctx, namespace, metav1.UpdateOptions{})
+	return err
+}
+
+// leaveMesh stops the injection of the sidecars in the namespace and removes the policies of the
+// mesh, the pods running keep their sidecars until they are recreated
+func (c *Controller) leaveMesh(ctx context.Context, namespace *corev1.Namespace, provider string) error {
+	for _, gvr := range meshPolicyGVRs[provider] {
+		if err := c.dynamicclientset.Resource(gvr).Namespace(namespace.GetName()).Delete(ctx, meshPolicyName, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	labels, annotations := namespace.GetLabels(), namespace.GetAnnotations()
+	delete(labels, "istio-injection")
+	delete(annotations, "linkerd.io/inject")
+	delete(annotations, annotationMesh)
+	namespace.SetLabels(labels)
+	namespace.SetAnnotations(annotations)
+	_, err := c.kubeclientset.CoreV1().Namespaces().Update(ctx, namespace, metav1.UpdateOptions{})
+	return err
+}
+
+// meshPolicies returns the policies of the mesh in a namespace of the tenant in the order of their
+// resources, they require mutual TLS and admit the traffic from the namespaces of the tenant and
+// of the mesh alone
+func meshPolicies(provider, namespace, tenant string, namespaces []string) []*unstructured.Unstructured {
+	sources := append(append([]string{}, namespaces...), MeshSystemNamespaces[provider]...)
+	labels := map[string]interface{}{"edge-net.io/generated": "true", "edge-net.io/tenant": tenant}
+	metadata := func() map[string]interface{} {
+		return map[string]interface{}{"name": meshPolicyName, "namespace": namespace, "labels": labels}
+	}
+	switch provider {
+	case meshIstio:
+		peerAuthentication := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": peerAuthenticationGVR.GroupVersion().String(),
+			"kind":       "PeerAuthentication",
+			"metadata":   metadata(),
+			"spec":       map[string]interface{}{"mtls": map[string]interface{}{"mode": "STRICT"}},
+		}}
+		authorizationPolicy := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": istioAuthorizationPolicyGVR.GroupVersion().String(),
+			"kind":       "AuthorizationPolicy",
+			"metadata":   metadata(),
+			"spec": map[string]interface{}{
+				"action": "ALLOW",
+				"rules": []interface{}{
+					map[string]interface{}{"from": []interface{}{
+						map[string]interface{}{"source": map[string]interface{}{"namespaces": toInterfaces(sources)}},
+					}},
+				},
+			},
+		}}
+		return []*unstructured.Unstructured{peerAuthentication, authorizationPolicy}
+	case meshLinkerd:
+		identityRefs := []interface{}{}
+		for _, source := range sources {
+			identityRefs = append(identityRefs, map[string]interface{}{"kind": "Namespace", "name": source})
+		}
+		meshTLSAuthentication := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": meshTLSAuthenticationGVR.GroupVersion().String(),
+			"kind":       "MeshTLSAuthentication",
+			"metadata":   metadata(),
+			"spec":       map[string]interface{}{"identityRefs": identityRefs},
+		}}
+		authorizationPolicy := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": linkerdAuthorizationPolicyGVR.GroupVersion().String(),
+			"kind":       "AuthorizationPolicy",
+			"metadata":   metadata(),
+			"spec": map[string]interface{}{
+				"targetRef": map[string]interface{}{"kind": "Namespace", "name": namespace},
+				"requiredAuthenticationRefs": []interface{}{
+					map[string]interface{}{"group": meshTLSAuthenticationGVR.Group, "kind": "MeshTLSAuthentication", "name": meshPolicyName},
+				},
+			},
+		}}
+		return []*unstructured.Unstructured{meshTLSAuthentication, authorizationPolicy}
+	}
+	return nil
+}
+
+// applyMeshPolicy creates the policy or updates its spec if it exists
+func (c *Controller) applyMeshPolicy(ctx context.Context, gvr schema.GroupVersionResource, policy *unstructured.Unstructured) error {
+	client := c.dynamicclientset.Resource(gvr).Namespace(policy.GetNamespace())
+	_, err := client.Create(ctx, policy, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		current, err := client.Get(ctx, policy.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		current.Object["spec"] = policy.Object["spec"]
+		_, err = client.Update(ctx, current, metav1.UpdateOptions{})
+		return err
+	}
+	return err
+}
+
+func toInterfaces(values []string) []interface{} {
+	list := []interface{}{}
+	for _, value := range values {
+		list = append(list, value)
+	}
+	return list
+}
+
+// mapNamespace returns the tenant of a new namespace if the tenant is in a service mesh, the
+// policies of its other namespaces admit the traffic from the new one then
+func (c *Controller) mapNamespace(obj client.Object) []reconcile.Request {
+	tenantName, ok := obj.GetLabels()["edge-net.io/tenant"]
+	if !ok {
+		return nil
+	}
+	if tenant, err := c.tenantsLister.Get(tenantName); err == nil && tenant.Spec.Mesh != nil {
+		return []reconcile.Request{request(tenantName)}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// States of the network policy sync in a subnamespace
+const (
+	policySynced     = "Synced"
+	policyFailure    = "Failure"
+	policyRolledBack = "Rolled Back"
+	policySkipped    = "Skipped"
+)
+
+// generatedPolicySelector picks the network policies the controllers manage, the tenant's own are left alone
+const generatedPolicySelector = "edge-net.io/generated=true"
+
+// syncSubNamespacePolicies carries the generated network policies of the core namespace over to every
+// subnamespace of the tenant. The sync is all or nothing: once a subnamespace fails, the subnamespaces
+// changed so far get their previous policies back so that the tenant never ends up half isolated.
+func (c *Controller) syncSubNamespacePolicies(ctx context.Context, tenantCopy *corev1alpha.Tenant) error {
+	namespaceRaw, err := c.tenantNamespaces(ctx, tenantCopy.GetName(), labels.Set{"edge-net.io/kind": "sub"})
+	if err != nil {
+		return err
+	}
+	desired, err := c.kubeclientset.NetworkingV1().NetworkPolicies(tenantCopy.GetName()).List(ctx, metav1.ListOptions{LabelSelector: generatedPolicySelector})
+	if err != nil {
+		return err
+	}
+	namespaces := []string{}
+	for _, namespaceRow := range namespaceRaw {
+		namespaces = append(namespaces, namespaceRow.GetName())
+	}
+	sort.Strings(namespaces)
+
+	results := make([]corev1alpha.NamespacePolicySync, len(namespaces))
+	snapshots := make(map[string][]networkingv1.NetworkPolicy)
+	var syncErr error
+	failed := len(namespaces)
+	for i, namespace := range namespaces {
+		results[i] = corev1alpha.NamespacePolicySync{Namespace: namespace, State: policySynced}
+		current, err := c.kubeclientset.NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{LabelSelector: generatedPolicySelector})
+		if err == nil {
+			snapshots[namespace] = current.Items
+			err = c.replaceNetworkPolicies(ctx, namespace, current.Items, desired.Items)
+		}
+		if err != nil {
+			klog.ErrorS(err, "Couldn't sync the network policies", "namespace", namespace)
+			results[i].State = policyFailure
+			results[i].Message = err.Error()
+			syncErr = err
+			failed = i
+			break
+		}
+	}
+	if syncErr != nil {
+		for i, namespace := range namespaces {
+			if i > failed {
+				results[i] = corev1alpha.NamespacePolicySync{Namespace: namespace, State: policySkipped}
+				continue
+			}
+			snapshot, touched := snapshots[namespace]
+			if !touched {
+				continue
+			}
+			current, err := c.kubeclientset.NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{LabelSelector: generatedPolicySelector})
+			if err == nil {
+				err = c.replaceNetworkPolicies(ctx, namespace, current.Items, snapshot)
+			}
+			if err != nil {
+				klog.ErrorS(err, "Couldn't roll the network policies back", "namespace", namespace)
+				results[i].State = policyFailure
+				results[i].Message = fmt.Sprintf("Rollback failed: %s", err)
+			} else if results[i].State == policySynced {
+				results[i].State = policyRolledBack
+			}
+		}
+	}
+	if len(results) == 0 {
+		results = nil
+	}
+	tenantCopy.Status.NetworkPolicies = results
+	return syncErr
+}
+
+// replaceNetworkPolicies brings the generated network policies of the namespace from the current ones to the
+// desired ones, the policies missing from the desired set are removed
+func (c *Controller) replaceNetworkPolicies(ctx context.Context, namespace string, current, desired []networkingv1.NetworkPolicy) error {
+	existing := make(map[string]networkingv1.NetworkPolicy)
+	for _, networkPolicy := range current {
+		existing[networkPolicy.GetName()] = networkPolicy
+	}
+	for _, desiredPolicy := range desired {
+		if currentPolicy, ok := existing[desiredPolicy.GetName()]; ok {
+			delete(existing, desiredPolicy.GetName())
+			if reflect.DeepEqual(currentPolicy.Spec, desiredPolicy.Spec) && reflect.DeepEqual(currentPolicy.GetLabels(), desiredPolicy.GetLabels()) {
+				continue
+			}
+			currentPolicy.Spec = desiredPolicy.Spec
+			currentPolicy.SetLabels(desiredPolicy.GetLabels())
+			if _, err := c.kubeclientset.NetworkingV1().NetworkPolicies(namespace).Update(ctx, &currentPolicy, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+			continue
+		}
+		networkPolicy := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: desiredPolicy.GetName(), Namespace: namespace,
+			Labels: desiredPolicy.GetLabels(), OwnerReferences: desiredPolicy.GetOwnerReferences()}, Spec: desiredPolicy.Spec}
+		if _, err := c.kubeclientset.NetworkingV1().NetworkPolicies(namespace).Create(ctx, networkPolicy, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	}
+	for name := range existing {
+		if err := c.kubeclientset.NetworkingV1().NetworkPolicies(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
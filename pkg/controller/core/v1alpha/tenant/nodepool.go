@@ -0,0 +1,130 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// nodePoolMutationName returns the name of the mutation confining the pods of the tenant to its node pools
+func nodePoolMutationName(tenant string) string {
+	return fmt.Sprintf("edgenet-tenant-%s-nodepools", tenant)
+}
+
+// applyNodePools has the pods in the tenant namespaces require the nodes of the pools the tenant is
+// entitled to. The required node affinity of the pods is overridden, a pod narrows its placement
+// down with a node selector or a preferred affinity. The mutation is removed when the tenant is
+// allowed on all nodes, and left as it is while a pool of the tenant is missing.
+func (c *Controller) applyNodePools(ctx context.Context, tenantCopy *corev1alpha.Tenant, ownerReferences []metav1.OwnerReference) error {
+	name := nodePoolMutationName(tenantCopy.GetName())
+	if len(tenantCopy.Spec.NodePools) == 0 {
+		if err := c.dynamicclientset.Resource(assignGVR).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	nodeSelectorTerms := []interface{}{}
+	for _, poolName := range tenantCopy.Spec.NodePools {
+		nodePool, err := c.nodePoolsLister.Get(poolName)
+		if err != nil {
+			return err
+		}
+		nodeSelectorTerms = append(nodeSelectorTerms, nodeSelectorTerm(&nodePool.Spec.Selector))
+	}
+	assign := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": assignGVR.GroupVersion().String(),
+		"kind":       "Assign",
+		"metadata": map[string]interface{}{
+			"name":   name,
+			"labels": map[string]interface{}{"edge-net.io/generated": "true", "edge-net.io/tenant": tenantCopy.GetName()},
+		},
+		"spec": map[string]interface{}{
+			"applyTo": []interface{}{
+				map[string]interface{}{"groups": []interface{}{""}, "kinds": []interface{}{"Pod"}, "versions": []interface{}{"v1"}},
+			},
+			"match": map[string]interface{}{
+				"scope": "Namespaced",
+				"kinds": []interface{}{
+					map[string]interface{}{"apiGroups": []interface{}{"*"}, "kinds": []interface{}{"Pod"}},
+				},
+				"namespaceSelector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{"edge-net.io/tenant": tenantCopy.GetName()},
+				},
+			},
+			"location": "spec.affinity.nodeAffinity.requiredDuringSchedulingIgnoredDuringExecution",
+			"parameters": map[string]interface{}{
+				"assign": map[string]interface{}{"value": map[string]interface{}{"nodeSelectorTerms": nodeSelectorTerms}},
+			},
+		},
+	}}
+	assign.SetOwnerReferences(ownerReferences)
+	return c.applyMutation(ctx, assignGVR, assign)
+}
+
+// nodeSelectorTerm turns the label selector of a node pool into a term of the node affinity, the
+// terms of the pools are ORed and the requirements within a term ANDed
+func nodeSelectorTerm(selector *metav1.LabelSelector) map[string]interface{} {
+	keys := []string{}
+	for key := range selector.MatchLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	matchExpressions := []interface{}{}
+	for _, key := range keys {
+		matchExpressions = append(matchExpressions, map[string]interface{}{
+			"key": key, "operator": "In", "values": []interface{}{selector.MatchLabels[key]},
+		})
+	}
+	for _, requirement := range selector.MatchExpressions {
+		expression := map[string]interface{}{"key": requirement.Key, "operator": string(requirement.Operator)}
+		if len(requirement.Values) > 0 {
+			expression["values"] = toInterfaces(requirement.Values)
+		}
+		matchExpressions = append(matchExpressions, expression)
+	}
+	return map[string]interface{}{"matchExpressions": matchExpressions}
+}
+
+// mapNodePool returns the tenants entitled to the node pool, their pods follow its selector
+func (c *Controller) mapNodePool(obj client.Object) []reconcile.Request {
+	tenants, err := c.tenantsLister.List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, tenant := range tenants {
+		for _, poolName := range tenant.Spec.NodePools {
+			if poolName == obj.GetName() {
+				requests = append(requests, request(tenant.GetName()))
+				break
+			}
+		}
+	}
+	return requests
+}
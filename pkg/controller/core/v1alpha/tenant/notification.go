@@ -0,0 +1,45 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"fmt"
+	"strings"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/mailer"
+)
+
+// notifyState posts the establishment or the failure of the tenant to the chat channels subscribed to it
+func (c *Controller) notifyState(tenantCopy *corev1alpha.Tenant) {
+	purpose := "tenant-established"
+	if tenantCopy.Status.State == failure {
+		purpose = "tenant-failure"
+	} else if tenantCopy.Status.State != established {
+		return
+	}
+	notification := new(mailer.Content)
+	notification.User = tenantCopy.Spec.Contact.Email
+	notification.FirstName = tenantCopy.Spec.Contact.FirstName
+	notification.LastName = tenantCopy.Spec.Contact.LastName
+	notification.Subject = fmt.Sprintf("[EdgeNet] Tenant %s %s", tenantCopy.GetName(), strings.ToLower(tenantCopy.Status.State))
+	notification.TenantStatus = new(mailer.TenantStatus)
+	notification.TenantStatus.Tenant = tenantCopy.GetName()
+	notification.TenantStatus.State = tenantCopy.Status.State
+	notification.TenantStatus.Message = tenantCopy.Status.Message
+	notification.Notify(purpose)
+}
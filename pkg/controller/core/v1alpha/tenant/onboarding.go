@@ -0,0 +1,275 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// Steps of the establishment of a tenant in the order they run
+const (
+	stepNamespace = "Namespace"
+	stepQuota     = "Quota"
+	stepRBAC      = "RBAC"
+	stepNetwork   = "Network"
+	stepNotify    = "Notify"
+	stepDone      = "Done"
+)
+
+// onboardingRun holds what the steps of a single establishment share
+type onboardingRun struct {
+	tenantCopy      *corev1alpha.Tenant
+	clusterUID      string
+	ownerReferences []metav1.OwnerReference
+	// failures collects the sub-steps that failed without stopping their step
+	failures *stepFailures
+}
+
+// onboardingStep is a step of the establishment, an error stops the establishment at the step
+type onboardingStep struct {
+	name string
+	run  func(ctx context.Context, run *onboardingRun) error
+}
+
+// onboardingSteps returns the steps of the establishment in the order they run
+func (c *Controller) onboardingSteps() []onboardingStep {
+	return []onboardingStep{
+		{name: stepNamespace, run: c.onboardNamespace},
+		{name: stepQuota, run: c.onboardQuota},
+		{name: stepRBAC, run: c.onboardRBAC},
+		{name: stepNetwork, run: c.onboardNetwork},
+		{name: stepNotify, run: c.onboardNotify},
+	}
+}
+
+// establish runs the steps of the establishment in order and records the step it reached in the
+// status. A tenant that is not established yet resumes at the step it stopped at, as long as its
+// spec stays the same, while an established tenant goes through all the steps to correct the drift.
+func (c *Controller) establish(ctx context.Context, tenantCopy *corev1alpha.Tenant, clusterUID string, failures *stepFailures) {
+	run := &onboardingRun{
+		tenantCopy:      tenantCopy,
+		clusterUID:      clusterUID,
+		ownerReferences: SetAsOwnerReference(tenantCopy),
+		failures:        failures,
+	}
+	generation := tenantCopy.GetGeneration()
+	steps := c.onboardingSteps()
+	for _, step := range steps[resumeStep(tenantCopy, steps):] {
+		if err := step.run(ctx, run); err != nil {
+			klog.ErrorS(err, "Couldn't complete the onboarding step", "tenant", klog.KObj(tenantCopy), "step", step.name)
+			tenantCopy.Status.Onboarding = &corev1alpha.Onboarding{Step: step.name, Failed: true, Message: err.Error(), Generation: generation}
+			return
+		}
+	}
+	tenantCopy.Status.Onboarding = &corev1alpha.Onboarding{Step: stepDone, Generation: generation}
+}
+
+// resumeStep returns the index of the step the establishment of the tenant resumes at
+func resumeStep(tenantCopy *corev1alpha.Tenant, steps []onboardingStep) int {
+	onboarding := tenantCopy.Status.Onboarding
+	if onboarding == nil || onboarding.Generation != tenantCopy.GetGeneration() || tenantCopy.Status.State == established {
+		return 0
+	}
+	for i, step := range steps {
+		if step.name == onboarding.Step {
+			return i
+		}
+	}
+	return 0
+}
+
+// onboardNamespace creates the core namespace that has the name of the tenant
+func (c *Controller) onboardNamespace(ctx context.Context, run *onboardingRun) error {
+	if err := c.createCoreNamespace(ctx, run.tenantCopy, run.ownerReferences, run.clusterUID); err != nil {
+		run.failures.add(failureCreation, messageCreationFailed)
+		return err
+	}
+	return nil
+}
+
+// onboardQuota hands the tenant its share of the cluster: the ports, the bounds of the containers,
+// the priority of its pods, and the node pools they run on
+func (c *Controller) onboardQuota(ctx context.Context, run *onboardingRun) error {
+	tenantCopy := run.tenantCopy
+	// The node ports and host ports of the tenant are disjoint from those of the others
+	if _, err := access.AllocatePortRange(ctx, tenantCopy.GetName()); err != nil {
+		klog.ErrorS(err, "Couldn't allocate port range", "tenant", klog.KObj(tenantCopy))
+		run.failures.add(failurePortRange, messagePortRangeFailed)
+	}
+	// Bound the containers within the quota
+	if err := c.applyLimitRange(ctx, tenantCopy); err != nil {
+		klog.ErrorS(err, "Couldn't apply limit range", "tenant", klog.KObj(tenantCopy))
+		run.failures.add(failureLimitRange, messageLimitRangeFailed)
+	}
+	// Preemption between the tiers on contended nodes
+	if err := c.applyPriorityClass(ctx, tenantCopy, run.ownerReferences); err != nil {
+		klog.ErrorS(err, "Couldn't apply priority class", "tenant", klog.KObj(tenantCopy))
+		run.failures.add(failurePriorityClass, messagePriorityClassFailed)
+	}
+	// Placement of the pods on the node pools the tenant is entitled to
+	if err := c.applyNodePools(ctx, tenantCopy, run.ownerReferences); err != nil {
+		klog.ErrorS(err, "Couldn't apply node pools", "tenant", klog.KObj(tenantCopy))
+		run.failures.add(failureNodePool, messageNodePoolFailed)
+	}
+	return nil
+}
+
+// onboardRBAC grants the roles of the tenant to its owners, members, groups, and services
+func (c *Controller) onboardRBAC(ctx context.Context, run *onboardingRun) error {
+	tenantCopy := run.tenantCopy
+	// Create the cluster roles
+	tenantOwnerClusterRole, err := access.CreateObjectSpecificClusterRole(ctx, tenantCopy.GetName(), "core.edgenet.io", "tenants", tenantCopy.GetName(), "owner", []string{"get", "update", "patch"}, run.ownerReferences)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't create owner cluster role", "tenant", klog.KObj(tenantCopy))
+		run.failures.add(failureClusterRoleCreation, messageClusterRoleCreationFailed)
+	}
+	// Pods get an API token only if they run as a service account that holds one
+	if err := c.applyTokenPolicy(ctx, tenantCopy); err != nil {
+		klog.ErrorS(err, "Couldn't apply the token policy", "tenant", klog.KObj(tenantCopy))
+		run.failures.add(failureTokenPolicy, messageTokenPolicyFailed)
+	}
+
+	// The bindings of the former owners are looked up before the roles move to the contact
+	former, formerErr := c.formerOwners(ctx, tenantCopy, tenantOwnerClusterRole)
+	if formerErr != nil {
+		klog.ErrorS(formerErr, "Couldn't look up the former owners", "tenant", klog.KObj(tenantCopy))
+	}
+	// Cluster role binding, one per owner
+	var clusterRoleBindErr error
+	for _, owner := range tenantCopy.Spec.OwnerContacts() {
+		if err := access.CreateObjectSpecificClusterRoleBinding(ctx, tenantOwnerClusterRole, owner.Handle, owner.Email, map[string]string{"edge-net.io/generated": "true", "edge-net.io/tenant": tenantCopy.GetName()}, []metav1.OwnerReference{}); err != nil {
+			clusterRoleBindErr = err
+		}
+	}
+	if clusterRoleBindErr != nil {
+		run.failures.add(failureRoleBindingCreation, messageRoleBindingCreationFailed)
+	}
+	// Role binding
+	clusterRoleName := "edgenet:tenant-owner"
+	roleRef := rbacv1.RoleRef{Kind: "ClusterRole", Name: clusterRoleName}
+	rbSubjects := []rbacv1.Subject{{Kind: "User", Name: tenantCopy.Spec.Contact.Email, APIGroup: "rbac.authorization.k8s.io"}}
+	roleBind := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: clusterRoleName, Namespace: tenantCopy.GetName()},
+		Subjects: rbSubjects, RoleRef: roleRef}
+	roleBind.SetLabels(map[string]string{"edge-net.io/generated": "true"})
+	fail := func(reason, message string, err error) error {
+		run.failures.add(reason, message)
+		tenantCopy.Status.State = failure
+		tenantCopy.Status.Message = message
+		return err
+	}
+	if err := c.applyRoleBinding(ctx, roleBind); err != nil {
+		klog.ErrorS(err, "Couldn't apply the role binding", "roleBinding", klog.KObj(roleBind))
+		return fail(failureBinding, messageBindingFailed, err)
+	}
+	// The membership of the identity-provider groups is managed outside EdgeNet
+	if err := access.SyncGroupRoleBindings(ctx, tenantCopy); err != nil {
+		klog.ErrorS(err, "Couldn't bind the groups", "tenant", klog.KObj(tenantCopy))
+		return fail(failureGroupBinding, messageGroupBindingFailed, err)
+	}
+	// The owners and administrators other than the contact have a binding each
+	if err := access.SyncMemberRoleBindings(ctx, tenantCopy); err != nil {
+		klog.ErrorS(err, "Couldn't bind the members", "tenant", klog.KObj(tenantCopy))
+		return fail(failureMemberBinding, messageMemberBindingFailed, err)
+	}
+	// The controllers acting on the objects of the tenant hold their rights in its namespaces only
+	if err := c.applyServiceRoleBindings(ctx, tenantCopy.GetName()); err != nil {
+		klog.ErrorS(err, "Couldn't bind the services", "tenant", klog.KObj(tenantCopy))
+		return fail(failureServiceBinding, messageServiceBindingFailed, err)
+	}
+	// The former owners lose the roles only once the contact holds them
+	if formerErr == nil && clusterRoleBindErr == nil {
+		if err := c.revokeFormerOwners(ctx, tenantCopy, former); err != nil {
+			klog.ErrorS(err, "Couldn't revoke the former owners", "tenant", klog.KObj(tenantCopy))
+			run.failures.add(failureOwnerRevocation, messageOwnerRevocationFailed)
+		}
+	}
+	return nil
+}
+
+// onboardNetwork isolates the namespaces of the tenant and attaches them to the ingress and the mesh
+func (c *Controller) onboardNetwork(ctx context.Context, run *onboardingRun) error {
+	tenantCopy := run.tenantCopy
+	var err error
+	// Apply network policies, the tenant can opt out of the default-deny baseline by annotation
+	if c.isolationMode(tenantCopy) == isolationBaseline {
+		err = access.ApplyBaselineClusterPolicies(ctx, tenantCopy.GetName(), tenantCopy.GetName(), string(tenantCopy.GetUID()), run.clusterUID, tenantCopy.Spec.Egress, run.ownerReferences)
+		if err == nil {
+			// The permissive policy of the tenants established before would let the traffic in
+			if err := c.kubeclientset.NetworkingV1().NetworkPolicies(tenantCopy.GetName()).Delete(ctx, "baseline", metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				klog.ErrorS(err, "Couldn't delete the network policy", "networkPolicy", klog.KRef(tenantCopy.GetName(), "baseline"))
+			}
+		}
+	} else {
+		if err := access.RemoveBaselineClusterPolicies(ctx, tenantCopy.GetName()); err != nil {
+			klog.ErrorS(err, "Couldn't delete the network policy", "networkPolicy", klog.KRef(tenantCopy.GetName(), "baseline"))
+		}
+		err = c.applyNetworkPolicy(ctx, tenantCopy.GetName(), string(tenantCopy.GetUID()), run.clusterUID, tenantCopy.Spec.Egress)
+	}
+	if err != nil {
+		// The tenant is not established with its namespaces open to the others
+		run.failures.add(failureNetworkPolicy, messageNetworkPolicyFailed)
+		tenantCopy.Status.State = failure
+		tenantCopy.Status.Message = messageNetworkPolicyFailed
+		return err
+	}
+	// The whole tenant tree shares the isolation level of the core namespace
+	if err := c.syncSubNamespacePolicies(ctx, tenantCopy); err != nil {
+		run.failures.add(failureSubNamespacePolicy, messageSubNamespacePolicyFailed)
+	}
+	// Exposure of the services through the ingress controllers of the edge nodes
+	if err := c.applyIngress(ctx, tenantCopy, run.ownerReferences); err != nil {
+		klog.ErrorS(err, "Couldn't apply ingress class", "tenant", klog.KObj(tenantCopy))
+		run.failures.add(failureIngress, messageIngressFailed)
+	}
+	// Sidecars and mutual TLS of the service mesh the tenant opted in
+	if err := c.applyMesh(ctx, tenantCopy); err != nil {
+		klog.ErrorS(err, "Couldn't apply service mesh", "tenant", klog.KObj(tenantCopy))
+		run.failures.add(failureMesh, messageMeshFailed)
+	}
+	// Destinations named by domain rather than address the tenant reaches
+	if err := c.applyEgress(ctx, tenantCopy); err != nil {
+		klog.ErrorS(err, "Couldn't apply egress", "tenant", klog.KObj(tenantCopy))
+		run.failures.add(failureEgress, messageEgressFailed)
+	}
+	return nil
+}
+
+// onboardNotify establishes the tenant and checks that its owner and isolation work, the
+// notifications go out once the state of the tenant is updated
+func (c *Controller) onboardNotify(ctx context.Context, run *onboardingRun) error {
+	tenantCopy := run.tenantCopy
+	if tenantCopy.Status.State != established {
+		c.recorder.Event(tenantCopy, corev1.EventTypeNormal, successEstablished, messageEstablished)
+	}
+	tenantCopy.Status.State = established
+	tenantCopy.Status.Message = successEstablished
+	c.setNetworkIsolation(tenantCopy, run.clusterUID)
+	// Catch silent misconfiguration of RBAC or network policy support
+	if !c.verifyTenant(ctx, tenantCopy, run.clusterUID) {
+		run.failures.add(failureVerification, messageVerificationFail)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// Definitions of the owner change of the tenant
+const (
+	tenantOwnerRoleBinding       = "edgenet:tenant-owner"
+	reasonOwnerChanged           = "OwnerChanged"
+	messageOwnerChanged          = "Tenant owner changed from %s to %s"
+	failureOwnerRevocation       = "Not Revoked"
+	messageOwnerRevocationFailed = "Revoking the roles of the former owner failed"
+)
+
+// ownerBindings are the bindings of the tenant owner roles to the subjects other than the owners
+// of the tenant, they are left behind when the contact changes or an owner is removed
+type ownerBindings struct {
+	clusterRoleBindings []string
+	subjects            []string
+}
+
+// formerOwners finds the bindings of the owner roles that do not match the owners of the tenant
+// anymore. It needs to run before the owner role binding is brought in line with the contact.
+func (c *Controller) formerOwners(ctx context.Context, tenantCopy *corev1alpha.Tenant, ownerClusterRole string) (ownerBindings, error) {
+	former := ownerBindings{}
+	subjects := make(map[string]bool)
+	owners := make(map[string]bool)
+	currentBindings := make(map[string]bool)
+	for _, owner := range tenantCopy.Spec.OwnerContacts() {
+		owners[strings.ToLower(owner.Email)] = true
+		currentBindings[fmt.Sprintf("%s-%s", ownerClusterRole, owner.Handle)] = true
+	}
+	// The owner bindings carry the name of the tenant, the ones of the other tenants are left out
+	clusterRoleBindings, err := c.kubeclientset.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("edge-net.io/generated=true,edge-net.io/tenant=%s", tenantCopy.GetName())})
+	if err != nil {
+		return former, err
+	}
+	for _, clusterRoleBinding := range clusterRoleBindings.Items {
+		if clusterRoleBinding.RoleRef.Name != ownerClusterRole {
+			continue
+		}
+		for _, subject := range clusterRoleBinding.Subjects {
+			if !owners[strings.ToLower(subject.Name)] {
+				subjects[subject.Name] = true
+			}
+		}
+		// The binding is named after the handle, the ones of the current owners are reapplied
+		if !currentBindings[clusterRoleBinding.GetName()] {
+			former.clusterRoleBindings = append(former.clusterRoleBindings, clusterRoleBinding.GetName())
+		}
+	}
+	roleBinding, err := c.kubeclientset.RbacV1().RoleBindings(tenantCopy.GetName()).Get(ctx, tenantOwnerRoleBinding, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return former, err
+	} else if err == nil {
+		for _, subject := range roleBinding.Subjects {
+			if !owners[strings.ToLower(subject.Name)] {
+				subjects[subject.Name] = true
+			}
+		}
+	}
+	for subject := range subjects {
+		former.subjects = append(former.subjects, subject)
+	}
+	sort.Strings(former.subjects)
+	return former, nil
+}
+
+// revokeFormerOwners removes the bindings left behind by the former owners once the roles are bound
+// to the owners of the tenant, and records the owner change
+func (c *Controller) revokeFormerOwners(ctx context.Context, tenantCopy *corev1alpha.Tenant, former ownerBindings) error {
+	for _, name := range former.clusterRoleBindings {
+		if err := c.kubeclientset.RbacV1().ClusterRoleBindings().Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	if len(former.subjects) != 0 {
+		owners := []string{}
+		for _, owner := range tenantCopy.Spec.OwnerContacts() {
+			owners = append(owners, owner.Email)
+		}
+		klog.InfoS("Tenant owner changed", "tenant", klog.KObj(tenantCopy), "former", former.subjects, "owners", owners)
+		c.recorder.Event(tenantCopy, corev1.EventTypeNormal, reasonOwnerChanged,
+			fmt.Sprintf(messageOwnerChanged, strings.Join(former.subjects, ", "), strings.Join(owners, ", ")))
+	}
+	return nil
+}
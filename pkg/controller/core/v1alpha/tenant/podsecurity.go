@@ -0,0 +1,53 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"sort"
+	"strings"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+)
+
+// Pod security levels, named after the Pod Security Standards
+const (
+	podSecurityBaseline   = "baseline"
+	podSecurityRestricted = "restricted"
+)
+
+// podSecurityLevels gives the pod security level of each tier, the tenants without a tier are restricted
+var podSecurityLevels = map[string]string{
+	"community": podSecurityRestricted,
+	"paying":    podSecurityBaseline,
+}
+
+// podSecurityLevel returns the level the pods of the tenant are admitted at
+func podSecurityLevel(tenantCopy *corev1alpha.Tenant) string {
+	if tenantCopy.Spec.Priority != nil {
+		if level, ok := podSecurityLevels[tenantCopy.Spec.Priority.Tier]; ok {
+			return level
+		}
+	}
+	return podSecurityRestricted
+}
+
+// podSecurityExemptions returns the exempted checks as they are written to the namespace annotation
+func podSecurityExemptions(tenantCopy *corev1alpha.Tenant) string {
+	exemptions := append([]string{}, tenantCopy.Spec.PodSecurityExemptions...)
+	sort.Strings(exemptions)
+	return strings.Join(exemptions, ",")
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/policy"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// PolicyRecheck is how often the policies are consulted again about a tenant they deny, so that
+// the tenant gets established once the policies let it through
+var PolicyRecheck = 10 * time.Minute
+
+// admitted consults the policies of the operators before the establishment of the tenant. A
+// tenant the policies deny is left as it is with their reasons in its status, and the
+// establishment waits while the policies cannot be consulted.
+func (c *Controller) admitted(ctx context.Context, tenantCopy *corev1alpha.Tenant, failures *stepFailures) bool {
+	decision, err := policy.Evaluate(ctx, policy.HookTenantEstablishment, tenantCopy)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't consult the policies", "tenant", klog.KObj(tenantCopy))
+		failures.add(failurePolicy, messagePolicyFailed)
+		return false
+	}
+	if !decision.Denied() {
+		return true
+	}
+	requeueAfter(ctx, PolicyRecheck)
+	message := fmt.Sprintf("%s: %s", messagePolicyDenied, decision.Reason())
+	if tenantCopy.Status.State == failure && tenantCopy.Status.Message == message {
+		return false
+	}
+	c.recorder.Event(tenantCopy, corev1.EventTypeWarning, failurePolicyDenied, message)
+	tenantCopy.Status.State = failure
+	tenantCopy.Status.Message = message
+	return false
+}
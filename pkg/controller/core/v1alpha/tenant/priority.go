@@ -0,0 +1,175 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Lowest priority value of each tier, a tier spans priorityBandWidth values
+var priorityBands = map[string]int32{
+	"community": 1000,
+	"paying":    10000,
+}
+
+const priorityBandWidth = 1000
+
+// assignGVR is the mutation of Gatekeeper that sets the default priority class of the pods
+var assignGVR = schema.GroupVersionResource{Group: "mutations.gatekeeper.sh", Version: "v1alpha1", Resource: "assigns"}
+
+// priorityClassName returns the name of the priority class dedicated to the tenant
+func priorityClassName(tenant string) string {
+	return fmt.Sprintf("edgenet-tenant-%s", tenant)
+}
+
+// applyPriorityClass creates the priority class of the tenant within the band of its tier, less
+// the penalty of its fair share, and has it assigned to the pods in the tenant namespaces that do
+// not request any.
+// Both are removed when the tenant drops the priority option.
+func (c *Controller) applyPriorityClass(ctx context.Context, tenantCopy *corev1alpha.Tenant, ownerReferences []metav1.OwnerReference) error {
+	name := priorityClassName(tenantCopy.GetName())
+	priority := tenantCopy.Spec.Priority
+	if priority == nil {
+		if err := c.kubeclientset.SchedulingV1().PriorityClasses().Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err := c.dynamicclientset.Resource(assignGVR).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	base, ok := priorityBands[priority.Tier]
+	if !ok {
+		return fmt.Errorf("unknown tier %s", priority.Tier)
+	}
+	if priority.Weight < 0 || priority.Weight >= priorityBandWidth {
+		return fmt.Errorf("weight %d is out of the band", priority.Weight)
+	}
+	preemptionPolicy := corev1.PreemptLowerPriority
+	if priority.PreemptionPolicy != nil {
+		preemptionPolicy = *priority.PreemptionPolicy
+	}
+	// The heavy tenants yield to the light ones within their tier, they never drop to a lower tier
+	weight := priority.Weight
+	if tenantUsage, err := c.edgenetclientset.CoreV1alpha().TenantUsages().Get(ctx, tenantCopy.GetName(), metav1.GetOptions{}); err == nil {
+		weight -= penalty(tenantUsage)
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+	if weight < 0 {
+		weight = 0
+	}
+	priorityClass := &schedulingv1.PriorityClass{ObjectMeta: metav1.ObjectMeta{Name: name, OwnerReferences: ownerReferences}}
+	priorityClass.SetLabels(map[string]string{"edge-net.io/generated": "true", "edge-net.io/tenant": tenantCopy.GetName()})
+	priorityClass.Value = base + weight
+	priorityClass.PreemptionPolicy = &preemptionPolicy
+	priorityClass.Description = fmt.Sprintf("Priority of the pods of tenant %s in the %s tier", tenantCopy.GetName(), priority.Tier)
+	if weight != priority.Weight {
+		priorityClass.Description = fmt.Sprintf("%s, lowered by its fair share", priorityClass.Description)
+	}
+
+	// The value and the preemption policy are immutable, a change recreates the priority class.
+	// Running pods keep the priority they were admitted with.
+	if current, err := c.kubeclientset.SchedulingV1().PriorityClasses().Get(ctx, name, metav1.GetOptions{}); err == nil {
+		if current.Value == priorityClass.Value && current.PreemptionPolicy != nil && *current.PreemptionPolicy == preemptionPolicy {
+			return c.applyPriorityAssign(ctx, name, tenantCopy.GetName(), ownerReferences)
+		}
+		if err := c.kubeclientset.SchedulingV1().PriorityClasses().Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	} else if !errors.IsNotFound(err) {
+		return err
+	}
+	if _, err := c.kubeclientset.SchedulingV1().PriorityClasses().Create(ctx, priorityClass, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	return c.applyPriorityAssign(ctx, name, tenantCopy.GetName(), ownerReferences)
+}
+
+// penalty returns the weight taken off the priority of the tenant for its recent consumption
+func penalty(tenantUsage *corev1alpha.TenantUsage) int32 {
+	if tenantUsage.Status.FairShare == nil {
+		return 0
+	}
+	return tenantUsage.Status.FairShare.Penalty
+}
+
+// mapTenantUsage returns the tenant whose consumption the usage tracks
+func mapTenantUsage(obj client.Object) []reconcile.Request {
+	return []reconcile.Request{request(obj.(*corev1alpha.TenantUsage).Spec.Tenant)}
+}
+
+// applyPriorityAssign creates the Gatekeeper mutation that defaults the priority class name
+// of the pods in the core namespace and the subnamespaces of the tenant
+func (c *Controller) applyPriorityAssign(ctx context.Context, name, tenant string, ownerReferences []metav1.OwnerReference) error {
+	assign := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": assignGVR.GroupVersion().String(),
+		"kind":       "Assign",
+		"metadata": map[string]interface{}{
+			"name":   name,
+			"labels": map[string]interface{}{"edge-net.io/generated": "true", "edge-net.io/tenant": tenant},
+		},
+		"spec": map[string]interface{}{
+			"applyTo": []interface{}{
+				map[string]interface{}{"groups": []interface{}{""}, "kinds": []interface{}{"Pod"}, "versions": []interface{}{"v1"}},
+			},
+			"match": map[string]interface{}{
+				"scope": "Namespaced",
+				"kinds": []interface{}{
+					map[string]interface{}{"apiGroups": []interface{}{"*"}, "kinds": []interface{}{"Pod"}},
+				},
+				"namespaceSelector": map[string]interface{}{
+					"matchLabels": map[string]interface{}{"edge-net.io/tenant": tenant},
+				},
+			},
+			"location": "spec.priorityClassName",
+			"parameters": map[string]interface{}{
+				// Pods requesting a priority class explicitly are left untouched
+				"pathTests": []interface{}{
+					map[string]interface{}{"subPath": "spec.priorityClassName", "condition": "MustNotExist"},
+				},
+				"assign": map[string]interface{}{"value": name},
+			},
+		},
+	}}
+	assign.SetOwnerReferences(ownerReferences)
+
+	_, err := c.dynamicclientset.Resource(assignGVR).Create(ctx, assign, metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		current, err := c.dynamicclientset.Resource(assignGVR).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		current.Object["spec"] = assign.Object["spec"]
+		_, err = c.dynamicclientset.Resource(assignGVR).Update(ctx, current, metav1.UpdateOptions{})
+		return err
+	}
+	return err
+}
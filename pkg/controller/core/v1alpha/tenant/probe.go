@@ -0,0 +1,184 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// IsolationProbeImage is the image of the pods of the connectivity probe, it needs the httpd and
+// wget applets of busybox. The network policies are only evaluated when empty.
+var IsolationProbeImage = "busybox:1.33"
+
+// IsolationProbeTimeout is how long the pods of the connectivity probe have to complete
+var IsolationProbeTimeout = 5 * time.Minute
+
+// Definitions of the connectivity probe
+const (
+	reasonProbeRunning    = "ProbeRunning"
+	reasonBlocked         = "ConnectionBlocked"
+	reasonAccepted        = "ConnectionAccepted"
+	messageProbeRunning   = "Connectivity probe is running"
+	messageProbeTimeout   = "Connectivity probe did not complete in time"
+	messageProbeUnreached = "Probe target is unreachable from the core namespace"
+	probeTargetName       = "edgenet-isolation-probe"
+	probeControlName      = "edgenet-isolation-probe-control"
+	probePort             = 8080
+	probeRecheck          = 5 * time.Second
+	labelIsolationProbe   = "edge-net.io/isolation-probe"
+	probeRoleTarget       = "target"
+	probeRoleClient       = "client"
+)
+
+// probeConnectivity starts a target pod in the core namespace and, once it serves, two clients:
+// one in the core namespace that must reach the target, and one in the namespace of the synthetic
+// peer that must not. The result holds for the generation of the tenant it is found for, the pods
+// are removed as soon as it is known.
+func (c *Controller) probeConnectivity(ctx context.Context, tenantCopy *corev1alpha.Tenant, clusterUID string) metav1.Condition {
+	condition := metav1.Condition{Type: conditionIsolation, ObservedGeneration: tenantCopy.GetGeneration()}
+	if current := meta.FindStatusCondition(tenantCopy.Status.Conditions, conditionIsolation); current != nil &&
+		current.ObservedGeneration == tenantCopy.GetGeneration() && (current.Reason == reasonBlocked || current.Reason == reasonAccepted) {
+		return *current
+	}
+	probeError := func(err error) metav1.Condition {
+		klog.ErrorS(err, "Couldn't run the connectivity probe", "tenant", klog.KObj(tenantCopy))
+		c.removeProbe(ctx, tenantCopy.GetName())
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = reasonProbeError
+		condition.Message = err.Error()
+		return condition
+	}
+	running := func() metav1.Condition {
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = reasonProbeRunning
+		condition.Message = messageProbeRunning
+		return condition
+	}
+
+	target, err := c.ensureProbePod(ctx, tenantCopy.GetName(), probeTargetName, probeRoleTarget,
+		[]string{"sh", "-c", fmt.Sprintf("mkdir -p /tmp/www && echo ok > /tmp/www/index.html && exec httpd -f -p %d -h /tmp/www", probePort)})
+	if err != nil {
+		return probeError(err)
+	}
+	if created := target.GetCreationTimestamp(); !created.IsZero() && time.Since(created.Time) > IsolationProbeTimeout {
+		return probeError(errors.New(messageProbeTimeout))
+	}
+	if target.Status.Phase != corev1.PodRunning || target.Status.PodIP == "" {
+		return running()
+	}
+
+	if err := c.ensureProbeNamespace(ctx, clusterUID); err != nil {
+		return probeError(err)
+	}
+	fetch := []string{"wget", "-q", "-T", "5", "-O", "/dev/null", fmt.Sprintf("http://%s/", net.JoinHostPort(target.Status.PodIP, strconv.Itoa(probePort)))}
+	control, err := c.ensureProbePod(ctx, tenantCopy.GetName(), probeControlName, probeRoleClient, fetch)
+	if err != nil {
+		return probeError(err)
+	}
+	peer, err := c.ensureProbePod(ctx, probeTenant, tenantCopy.GetName(), probeRoleClient, fetch)
+	if err != nil {
+		return probeError(err)
+	}
+	if !podCompleted(control) || !podCompleted(peer) {
+		return running()
+	}
+
+	// The peer failing to reach a target that does not serve proves nothing
+	if control.Status.Phase != corev1.PodSucceeded {
+		return probeError(errors.New(messageProbeUnreached))
+	}
+	c.removeProbe(ctx, tenantCopy.GetName())
+	if peer.Status.Phase == corev1.PodSucceeded {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonAccepted
+		condition.Message = messageNotEnforced
+	} else {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reasonBlocked
+		condition.Message = messageIsolation
+	}
+	return condition
+}
+
+// ensureProbeNamespace creates the namespace of the synthetic peer, labeled as the core namespace
+// of another tenant
+func (c *Controller) ensureProbeNamespace(ctx context.Context, clusterUID string) error {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: probeTenant, Labels: probeNamespaceLabels(clusterUID)}}
+	if _, err := c.kubeclientset.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// ensureProbePod returns the probe pod, created if missing. The pods run unprivileged to pass the
+// pod security level of any tenant.
+func (c *Controller) ensureProbePod(ctx context.Context, namespace, name, role string, command []string) (*corev1.Pod, error) {
+	pod, err := c.kubeclientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err == nil || !apierrors.IsNotFound(err) {
+		return pod, err
+	}
+	nonRoot := true
+	noEscalation := false
+	user := int64(65534)
+	limits := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m"), corev1.ResourceMemory: resource.MustParse("16Mi")}
+	pod = &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace,
+			Labels: map[string]string{labelIsolationProbe: role, "edge-net.io/generated": "true"}},
+		Spec: corev1.PodSpec{
+			RestartPolicy:                corev1.RestartPolicyNever,
+			AutomountServiceAccountToken: &noEscalation,
+			SecurityContext: &corev1.PodSecurityContext{RunAsNonRoot: &nonRoot, RunAsUser: &user,
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}},
+			Containers: []corev1.Container{{
+				Name:      "probe",
+				Image:     IsolationProbeImage,
+				Command:   command,
+				Resources: corev1.ResourceRequirements{Requests: limits, Limits: limits},
+				SecurityContext: &corev1.SecurityContext{AllowPrivilegeEscalation: &noEscalation,
+					Capabilities: &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}}},
+			}},
+		},
+	}
+	return c.kubeclientset.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{})
+}
+
+// removeProbe deletes the pods of the probe of a tenant
+func (c *Controller) removeProbe(ctx context.Context, tenant string) {
+	for _, pod := range []struct{ namespace, name string }{{tenant, probeTargetName}, {tenant, probeControlName}, {probeTenant, tenant}} {
+		if err := c.kubeclientset.CoreV1().Pods(pod.namespace).Delete(ctx, pod.name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Couldn't remove the probe pod", "pod", klog.KRef(pod.namespace, pod.name))
+		}
+	}
+}
+
+func podCompleted(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/purge"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// requestPurge adds the purge finalizer to a tenant annotated for a purge, so that its deletion
+// waits for the purge
+func (c *Controller) requestPurge(ctx context.Context, tenantCopy *corev1alpha.Tenant) error {
+	if !purge.Requested(tenantCopy) || purge.Pending(tenantCopy) {
+		return nil
+	}
+	tenantCopy.SetFinalizers(append(tenantCopy.GetFinalizers(), purge.Finalizer))
+	updated, err := c.edgenetclientset.CoreV1alpha().Tenants().Update(ctx, tenantCopy, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	tenantCopy.SetResourceVersion(updated.GetResourceVersion())
+	return nil
+}
+
+// purgeTenant removes the personal data of a tenant being deleted and emits the deletion report,
+// then lets the deletion go on. A purge that fails holds the deletion and is retried.
+func (c *Controller) purgeTenant(ctx context.Context, tenantCopy *corev1alpha.Tenant) error {
+	if !purge.Pending(tenantCopy) {
+		return nil
+	}
+	namespaces, err := c.tenantNamespaces(ctx, tenantCopy.GetName(), nil)
+	if err != nil {
+		return err
+	}
+	namespaceNames := []string{}
+	for _, namespace := range namespaces {
+		namespaceNames = append(namespaceNames, namespace.GetName())
+	}
+	report, err := purge.Purge(ctx, c.kubeclientset, c.edgenetclientset, tenantCopy, namespaceNames)
+	if err != nil {
+		c.recorder.Event(tenantCopy, corev1.EventTypeWarning, failurePurge, messagePurgeFailed)
+		klog.ErrorS(err, "Couldn't purge the tenant", "tenant", klog.KObj(tenantCopy))
+		return err
+	}
+	configMap, err := purge.Emit(ctx, c.kubeclientset, report)
+	if err != nil {
+		c.recorder.Event(tenantCopy, corev1.EventTypeWarning, failurePurge, messageReportFailed)
+		klog.ErrorS(err, "Couldn't emit the deletion report", "tenant", klog.KObj(tenantCopy))
+		return err
+	}
+
+	finalizers := []string{}
+	for _, finalizer := range tenantCopy.GetFinalizers() {
+		if finalizer != purge.Finalizer {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+	tenantCopy.SetFinalizers(finalizers)
+	if _, err := c.edgenetclientset.CoreV1alpha().Tenants().Update(ctx, tenantCopy, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	klog.InfoS("Purged the tenant", "tenant", klog.KObj(tenantCopy), "report", klog.KObj(configMap))
+	c.recorder.Event(tenantCopy, corev1.EventTypeNormal, successPurged, fmt.Sprintf("%s: %s", messagePurged, configMap.GetName()))
+	return nil
+}
@@ -0,0 +1,353 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Limits of the work queue, set by the flags of the controller
+var (
+	// QueueKeyQPS is the rate at which a tenant is queued again once it has spent its burst
+	QueueKeyQPS = 1.0
+	// QueueKeyBurst is the number of times a tenant can be queued in a row
+	QueueKeyBurst = 10
+	// QueueBaseDelay is the backoff of a tenant after its first failure, it doubles after each one
+	QueueBaseDelay = 5 * time.Millisecond
+	// QueueMaxDelay caps the backoff of a failing tenant
+	QueueMaxDelay = 1000 * time.Second
+)
+
+// The tiers of the queue, the tenants to establish are processed before the established ones
+const (
+	tierNew = iota
+	tierEstablished
+	tiers
+)
+
+// keyBudget is the token bucket of a key
+type keyBudget struct {
+	tokens  float64
+	updated time.Time
+}
+
+// keyRateLimiter gives each key a token bucket of its own, so that a flapping tenant delays
+// nobody but itself
+type keyRateLimiter struct {
+	mutex     sync.Mutex
+	qps       float64
+	burst     int
+	budgets   map[interface{}]*keyBudget
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func newKeyRateLimiter(qps float64, burst int) *keyRateLimiter {
+	return &keyRateLimiter{qps: qps, burst: burst, budgets: make(map[interface{}]*keyBudget), now: time.Now}
+}
+
+// reserve takes a token from the bucket of the key and returns how long the key needs to wait
+// for it
+func (l *keyRateLimiter) reserve(item interface{}) time.Duration {
+	if l.qps <= 0 {
+		return 0
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+	budget, exists := l.budgets[item]
+	if !exists {
+		budget = &keyBudget{tokens: float64(l.burst), updated: now}
+		l.budgets[item] = budget
+	}
+	budget.tokens += now.Sub(budget.updated).Seconds() * l.qps
+	if budget.tokens > float64(l.burst) {
+		budget.tokens = float64(l.burst)
+	}
+	budget.updated = now
+	// A key over its rate waits for the next token without taking it, the delaying queue holds
+	// a single entry for it however many times it is added meanwhile
+	if budget.tokens < 1 {
+		return time.Duration((1 - budget.tokens) / l.qps * float64(time.Second))
+	}
+	budget.tokens--
+	return 0
+}
+
+// sweep drops the buckets that are full again, such as the ones of the deleted tenants
+func (l *keyRateLimiter) sweep(now time.Time) {
+	refill := time.Duration(float64(l.burst) / l.qps * float64(time.Second))
+	if now.Sub(l.lastSweep) < refill {
+		return
+	}
+	l.lastSweep = now
+	for item, budget := range l.budgets {
+		if now.Sub(budget.updated) >= refill {
+			delete(l.budgets, item)
+		}
+	}
+}
+
+// tieredQueue is the work queue of two tiers that the watches of the controller fill. A tenant
+// waiting for its establishment is handed out before the established tenants, however many of
+// them are queued. Each key is limited by its own rate, where the default queue shares a single
+// bucket among all keys that a flapping tenant would drain for everybody. The delayed keys wait
+// in a delaying queue, which holds a single entry per key however many times it is added. The
+// failures are retried with the backoff of the queue of the manager.
+type tieredQueue struct {
+	workqueue.DelayingInterface
+	keys *keyRateLimiter
+}
+
+func newTieredQueue(name string, tier func(item interface{}) int) *tieredQueue {
+	return &tieredQueue{
+		DelayingInterface: workqueue.NewDelayingQueueWithCustomQueue(newTierQueue(tier), name),
+		keys:              newKeyRateLimiter(QueueKeyQPS, QueueKeyBurst),
+	}
+}
+
+// Add queues the key once it is within its rate
+func (q *tieredQueue) Add(item interface{}) {
+	if delay := q.keys.reserve(item); delay > 0 {
+		q.AddAfter(item, delay)
+		return
+	}
+	q.DelayingInterface.Add(item)
+}
+
+// AddRateLimited queues the key within its rate, the handlers of the watches add the keys
+// through Add alone
+func (q *tieredQueue) AddRateLimited(item interface{}) {
+	q.Add(item)
+}
+
+// Forget has nothing to reset, the backoff of the failures is kept by the queue of the manager
+func (q *tieredQueue) Forget(item interface{}) {}
+
+// NumRequeues is always zero, the failures are counted by the queue of the manager
+func (q *tieredQueue) NumRequeues(item interface{}) int {
+	return 0
+}
+
+// tieredHandler has the keys of a handler queued in the tiered queue, instead of the queue of
+// the manager that the watch hands over
+type tieredHandler struct {
+	handler handler.EventHandler
+	queue   workqueue.RateLimitingInterface
+}
+
+func (h tieredHandler) Create(e event.CreateEvent, _ workqueue.RateLimitingInterface) {
+	h.handler.Create(e, h.queue)
+}
+
+func (h tieredHandler) Update(e event.UpdateEvent, _ workqueue.RateLimitingInterface) {
+	h.handler.Update(e, h.queue)
+}
+
+func (h tieredHandler) Delete(e event.DeleteEvent, _ workqueue.RateLimitingInterface) {
+	h.handler.Delete(e, h.queue)
+}
+
+func (h tieredHandler) Generic(e event.GenericEvent, _ workqueue.RateLimitingInterface) {
+	h.handler.Generic(e, h.queue)
+}
+
+// tierFeed is the source that hands the keys of the tiered queue over to the queue of the
+// manager. It hands over no more keys than the workers can take at a time, the others wait in
+// the tiers where the new tenants overtake the established ones.
+type tierFeed struct {
+	queue *tieredQueue
+	slots chan struct{}
+
+	mutex sync.Mutex
+	// fed holds the keys handed over whose reconcile has not ended yet
+	fed map[interface{}]bool
+}
+
+var _ source.Source = &tierFeed{}
+
+func newTierFeed(queue *tieredQueue, workers int) *tierFeed {
+	return &tierFeed{queue: queue, slots: make(chan struct{}, workers), fed: make(map[interface{}]bool)}
+}
+
+// Start hands the keys over to the queue of the manager until the context is done
+func (f *tierFeed) Start(ctx context.Context, _ handler.EventHandler, queue workqueue.RateLimitingInterface, _ ...predicate.Predicate) error {
+	go func() {
+		<-ctx.Done()
+		f.queue.ShutDown()
+	}()
+	go func() {
+		for {
+			// The slot is taken first, the keys wait in their tiers while the workers are busy
+			select {
+			case f.slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			item, shutdown := f.queue.Get()
+			if shutdown {
+				return
+			}
+			f.mutex.Lock()
+			f.fed[item] = true
+			f.mutex.Unlock()
+			queue.Add(item)
+			f.queue.Done(item)
+		}
+	}()
+	return nil
+}
+
+// release frees the slot of a key handed over once its reconcile ends. The reconciles of the
+// requeues do not go through the feed, they free no slot.
+func (f *tierFeed) release(item interface{}) {
+	if f == nil {
+		return
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.fed[item] {
+		delete(f.fed, item)
+		<-f.slots
+	}
+}
+
+// tierQueue is the work queue of the tiers. As in the default queue, a key is queued once however
+// many times it is added, and it is never handed out to two workers at the same time.
+type tierQueue struct {
+	cond         *sync.Cond
+	queues       [tiers][]interface{}
+	dirty        map[interface{}]int
+	processing   map[interface{}]bool
+	shuttingDown bool
+	// tier tells the tier of a key as it is added
+	tier func(item interface{}) int
+}
+
+func newTierQueue(tier func(item interface{}) int) *tierQueue {
+	return &tierQueue{
+		cond:       sync.NewCond(&sync.Mutex{}),
+		dirty:      make(map[interface{}]int),
+		processing: make(map[interface{}]bool),
+		tier:       tier,
+	}
+}
+
+// Add queues the key in its tier, a key already queued in a lower tier moves up
+func (q *tierQueue) Add(item interface{}) {
+	tier := q.tier(item)
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown {
+		return
+	}
+	if current, ok := q.dirty[item]; ok {
+		if tier < current {
+			q.dirty[item] = tier
+			if !q.processing[item] && q.remove(current, item) {
+				q.queues[tier] = append(q.queues[tier], item)
+			}
+		}
+		return
+	}
+	q.dirty[item] = tier
+	if q.processing[item] {
+		return
+	}
+	q.queues[tier] = append(q.queues[tier], item)
+	q.cond.Signal()
+}
+
+// remove takes the key out of the tier
+func (q *tierQueue) remove(tier int, item interface{}) bool {
+	for i, queued := range q.queues[tier] {
+		if queued == item {
+			q.queues[tier] = append(q.queues[tier][:i], q.queues[tier][i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of keys queued in all tiers
+func (q *tierQueue) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	length := 0
+	for _, queue := range q.queues {
+		length += len(queue)
+	}
+	return length
+}
+
+// Get blocks until a key is queued and hands out the first key of the highest tier
+func (q *tierQueue) Get() (interface{}, bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for {
+		for tier := range q.queues {
+			if len(q.queues[tier]) == 0 {
+				continue
+			}
+			item := q.queues[tier][0]
+			q.queues[tier][0] = nil
+			q.queues[tier] = q.queues[tier][1:]
+			q.processing[item] = true
+			delete(q.dirty, item)
+			return item, false
+		}
+		if q.shuttingDown {
+			return nil, true
+		}
+		q.cond.Wait()
+	}
+}
+
+// Done marks the key as processed, it is queued again if it was added meanwhile
+func (q *tierQueue) Done(item interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	delete(q.processing, item)
+	if tier, ok := q.dirty[item]; ok {
+		q.queues[tier] = append(q.queues[tier], item)
+		q.cond.Signal()
+	}
+}
+
+// ShutDown makes the queue ignore the keys added and the workers return once it is empty
+func (q *tierQueue) ShutDown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
+
+func (q *tierQueue) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.shuttingDown
+}
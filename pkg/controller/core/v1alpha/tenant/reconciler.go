@@ -0,0 +1,221 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// The controller reconciles the tenants as a controller-runtime reconciler, the requeues go
+// through its result
+var _ reconcile.Reconciler = &Controller{}
+
+// SetupWithManager registers the controller with the manager, which runs the workers and the
+// periodic checks once the informers have synced. The manager is expected to run on the cache
+// of NewCache, so that the watches hand out the objects that the listers read. The watches fill
+// the tiered queue of the controller, which feeds the queue of the manager.
+func (c *Controller) SetupWithManager(mgr ctrl.Manager, threadiness int) error {
+	if err := mgr.Add(manager.RunnableFunc(c.maintain)); err != nil {
+		return err
+	}
+	owner := &handler.EnqueueRequestForOwner{OwnerType: &corev1alpha.Tenant{}, IsController: true}
+	if err := mgr.SetFields(owner); err != nil {
+		return err
+	}
+	c.feed = newTierFeed(c.queue, threadiness)
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("tenant").
+		// The builder requires the tenants as the object of the controller, their events go
+		// through the tiered queue with the watch below instead
+		For(&corev1alpha.Tenant{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(client.Object) bool { return false }))).
+		// Status updates are skipped so that failing tenants are retried with backoff, resyncs
+		// hand over the same object and they still get through
+		Watches(&source.Kind{Type: &corev1alpha.Tenant{}}, c.tiered(&handler.EnqueueRequestForObject{}),
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: func(e event.UpdateEvent) bool {
+				oldTenant := e.ObjectOld.(*corev1alpha.Tenant)
+				newTenant := e.ObjectNew.(*corev1alpha.Tenant)
+				return oldTenant == newTenant || !reflect.DeepEqual(oldTenant.Spec, newTenant.Spec) ||
+					!reflect.DeepEqual(oldTenant.GetAnnotations(), newTenant.GetAnnotations()) ||
+					!oldTenant.GetDeletionTimestamp().Equal(newTenant.GetDeletionTimestamp())
+			}})).
+		// The core namespace is put back as soon as it is deleted or its labels drift
+		Watches(&source.Kind{Type: &corev1.Namespace{}}, c.tiered(owner),
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: func(e event.UpdateEvent) bool {
+				return !reflect.DeepEqual(e.ObjectOld.GetLabels(), e.ObjectNew.GetLabels()) ||
+					!reflect.DeepEqual(e.ObjectOld.GetOwnerReferences(), e.ObjectNew.GetOwnerReferences())
+			}})).
+		// The subnamespaces of a tenant join its service mesh as they get created
+		Watches(&source.Kind{Type: &corev1.Namespace{}}, c.tiered(handler.EnqueueRequestsFromMapFunc(c.mapNamespace)),
+			builder.WithPredicates(onCreate())).
+		// The priority of a tenant follows the penalty of its fair share
+		Watches(&source.Kind{Type: &corev1alpha.TenantUsage{}}, c.tiered(handler.EnqueueRequestsFromMapFunc(mapTenantUsage)),
+			builder.WithPredicates(predicate.Funcs{
+				CreateFunc:  func(event.CreateEvent) bool { return false },
+				DeleteFunc:  func(event.DeleteEvent) bool { return false },
+				GenericFunc: func(event.GenericEvent) bool { return false },
+				UpdateFunc: func(e event.UpdateEvent) bool {
+					return penalty(e.ObjectOld.(*corev1alpha.TenantUsage)) != penalty(e.ObjectNew.(*corev1alpha.TenantUsage))
+				},
+			})).
+		// The status of a disabled tenant follows the progress of its teardown
+		Watches(&source.Kind{Type: &corev1alpha.Operation{}}, c.tiered(handler.EnqueueRequestsFromMapFunc(mapOperation)),
+			builder.WithPredicates(predicate.Funcs{
+				CreateFunc:  func(event.CreateEvent) bool { return false },
+				DeleteFunc:  func(event.DeleteEvent) bool { return false },
+				GenericFunc: func(event.GenericEvent) bool { return false },
+				UpdateFunc: func(e event.UpdateEvent) bool {
+					oldOperation := e.ObjectOld.(*corev1alpha.Operation)
+					newOperation := e.ObjectNew.(*corev1alpha.Operation)
+					return oldOperation.Status.State != newOperation.Status.State || oldOperation.Status.Progress != newOperation.Status.Progress
+				},
+			})).
+		// The pods of the tenants follow the selectors of their node pools
+		Watches(&source.Kind{Type: &corev1alpha.NodePool{}}, c.tiered(handler.EnqueueRequestsFromMapFunc(c.mapNodePool)),
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: func(e event.UpdateEvent) bool {
+				return !reflect.DeepEqual(e.ObjectOld.(*corev1alpha.NodePool).Spec, e.ObjectNew.(*corev1alpha.NodePool).Spec)
+			}})).
+		// The default service accounts follow the token policy of the tier as they get created
+		Watches(&source.Kind{Type: &corev1.ServiceAccount{}}, c.tiered(handler.EnqueueRequestsFromMapFunc(c.mapServiceAccount)),
+			builder.WithPredicates(predicate.Funcs{
+				DeleteFunc:  func(event.DeleteEvent) bool { return false },
+				GenericFunc: func(event.GenericEvent) bool { return false },
+				UpdateFunc: func(e event.UpdateEvent) bool {
+					return !reflect.DeepEqual(e.ObjectOld.(*corev1.ServiceAccount).AutomountServiceAccountToken, e.ObjectNew.(*corev1.ServiceAccount).AutomountServiceAccountToken)
+				},
+			})).
+		// The tenants that the periodic checks send along, such as on a change of the CNI plugin
+		Watches(&source.Channel{Source: c.resync}, c.tiered(&handler.EnqueueRequestForObject{})).
+		// The keys of the tiered queue, no faster than the workers take them
+		Watches(c.feed, &handler.Funcs{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: threadiness, RateLimiter: c.backoff}).
+		Complete(c)
+}
+
+// tiered has the keys of the handler go through the tiered queue
+func (c *Controller) tiered(h handler.EventHandler) handler.EventHandler {
+	return tieredHandler{handler: h, queue: c.queue}
+}
+
+// onCreate lets through the creation events alone
+func onCreate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc:  func(event.UpdateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
+
+// maintain runs the periodic checks until the manager stops
+func (c *Controller) maintain(ctx context.Context) error {
+	// Detect the CNI capabilities periodically as the plugin can be replaced at any time
+	go wait.UntilWithContext(ctx, c.detectCapabilities, 10*time.Minute)
+	// Correct the drift of the cluster roles granted to the tenant members and pick up the catalog edits
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := access.ReconcileClusterRoles(ctx); err != nil {
+			klog.ErrorS(err, "Couldn't reconcile the cluster role catalog")
+		}
+	}, time.Minute)
+	// Repair the drifted object-specific roles and bindings and prune the orphaned ones
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := access.ReconcileObjectSpecificRBAC(ctx); err != nil {
+			klog.ErrorS(err, "Couldn't reconcile the object-specific RBAC")
+		}
+	}, 10*time.Minute)
+	<-ctx.Done()
+	return nil
+}
+
+// requeueKey is the key of the requeue of a reconcile in its context
+type requeueKey struct{}
+
+// requeue is the earliest time a step asks the tenant to be reconciled again
+type requeue struct {
+	mutex sync.Mutex
+	after time.Duration
+}
+
+// withRequeue returns a context where the steps of the reconcile can ask for a requeue
+func withRequeue(ctx context.Context) (context.Context, *requeue) {
+	r := &requeue{}
+	return context.WithValue(ctx, requeueKey{}, r), r
+}
+
+// requeueAfter asks for the tenant to be reconciled again after the delay at the latest, the
+// shortest delay asked for wins
+func requeueAfter(ctx context.Context, after time.Duration) {
+	r, ok := ctx.Value(requeueKey{}).(*requeue)
+	if !ok {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.after == 0 || after < r.after {
+		r.after = after
+	}
+}
+
+// Reconcile compares the actual state with the desired, and attempts to converge the two. It then
+// updates the Status block of the Tenant resource with the current status of the resource.
+func (c *Controller) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	defer c.feed.release(request)
+	tenant, err := c.tenantsLister.Get(request.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("tenant '%s' in work queue no longer exists", request.Name))
+			c.failures.forget(request.Name)
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	ctx, r := withRequeue(ctx)
+	if err := c.ProcessTenant(ctx, tenant.DeepCopy()); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	c.recorder.Event(tenant, corev1.EventTypeNormal, successSynced, messageResourceSynced)
+	return reconcile.Result{RequeueAfter: r.after}, nil
+}
+
+// request returns the reconcile request of the key of a tenant
+func request(key string) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Name: key}}
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// defaultServiceAccount is the service account the pods run as unless they name another one
+const defaultServiceAccount = "default"
+
+// applyTokenPolicy sets the automount of the default service accounts in the namespaces of the
+// tenant after the policy of its tier, so that a pod does not get an API token it never asked for.
+// The namespaces whose default service account is not created yet are handled once it is.
+func (c *Controller) applyTokenPolicy(ctx context.Context, tenantCopy *corev1alpha.Tenant) error {
+	policy := access.TenantTokenPolicy(tenantCopy)
+	namespaceRaw, err := c.tenantNamespaces(ctx, tenantCopy.GetName(), nil)
+	if err != nil {
+		return err
+	}
+	var applyErr error
+	for _, namespaceRow := range namespaceRaw {
+		serviceAccount, err := c.kubeclientset.CoreV1().ServiceAccounts(namespaceRow.GetName()).Get(ctx, defaultServiceAccount, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			applyErr = err
+			continue
+		}
+		if serviceAccount.AutomountServiceAccountToken != nil && *serviceAccount.AutomountServiceAccountToken == policy.Automount {
+			continue
+		}
+		serviceAccountCopy := serviceAccount.DeepCopy()
+		automount := policy.Automount
+		serviceAccountCopy.AutomountServiceAccountToken = &automount
+		if _, err := c.kubeclientset.CoreV1().ServiceAccounts(namespaceRow.GetName()).Update(ctx, serviceAccountCopy, metav1.UpdateOptions{}); err != nil {
+			applyErr = err
+		}
+	}
+	return applyErr
+}
+
+// mapServiceAccount returns the tenant of the namespace when its default service account is
+// created, the service account controller creates it after the namespace
+func (c *Controller) mapServiceAccount(obj client.Object) []reconcile.Request {
+	if obj.GetName() != defaultServiceAccount {
+		return nil
+	}
+	namespace, err := c.namespacesLister.Get(obj.GetNamespace())
+	if err != nil {
+		return nil
+	}
+	if tenant, ok := namespace.GetLabels()["edge-net.io/tenant"]; ok {
+		return []reconcile.Request{request(tenant)}
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// Definitions of the stuck-state detection
+const (
+	conditionStalled = "Stalled"
+	reasonRetries    = "ConsecutiveFailures"
+	// AnnotationForceReconcile can be set on a tenant with any value to reset its retry counters
+	// and to make the controller reconcile it from scratch
+	AnnotationForceReconcile = "edge-net.io/force-reconcile"
+	successForced            = "Reconcile Forced"
+	messageForced            = "Retry counters reset and full reconcile forced"
+)
+
+// stalledThreshold is the number of consecutive failed syncs after which a tenant is stalled
+var stalledThreshold = 5
+
+// err summarizes the failed sub-steps as an error to let the work queue retry with backoff
+func (s stepFailures) err() error {
+	if len(s) == 0 {
+		return nil
+	}
+	messages := []string{}
+	for _, failure := range s {
+		messages = append(messages, failure.message)
+	}
+	return fmt.Errorf("%d sub-step(s) failed: %s", len(s), strings.Join(messages, ", "))
+}
+
+// recordSyncResult surfaces the retry state in the tenant status and sets the stalled
+// condition once the failures keep repeating
+func (c *Controller) recordSyncResult(tenantCopy *corev1alpha.Tenant, failures stepFailures) {
+	tenantCopy.Status.ObservedGeneration = tenantCopy.GetGeneration()
+	if len(failures) == 0 {
+		tenantCopy.Status.Retries = 0
+		tenantCopy.Status.LastFailure = nil
+		meta.RemoveStatusCondition(&tenantCopy.Status.Conditions, conditionStalled)
+		return
+	}
+	tenantCopy.Status.Retries++
+	tenantCopy.Status.LastFailure = &metav1.Time{Time: c.failures.now()}
+	if tenantCopy.Status.Retries >= stalledThreshold {
+		meta.SetStatusCondition(&tenantCopy.Status.Conditions, metav1.Condition{
+			Type:               conditionStalled,
+			Status:             metav1.ConditionTrue,
+			Reason:             reasonRetries,
+			ObservedGeneration: tenantCopy.GetGeneration(),
+			Message: fmt.Sprintf("Sync failed %d consecutive times, set the %s annotation to force a full reconcile",
+				stalledThreshold, AnnotationForceReconcile),
+		})
+	}
+}
+
+// forceReconcile removes the force annotation from the tenant, and resets its retry counters
+// together with the coalesced failures. It returns false if the tenant is not annotated.
+func (c *Controller) forceReconcile(ctx context.Context, tenantCopy *corev1alpha.Tenant) bool {
+	annotations := tenantCopy.GetAnnotations()
+	if _, exists := annotations[AnnotationForceReconcile]; !exists {
+		return false
+	}
+	delete(annotations, AnnotationForceReconcile)
+	tenantCopy.SetAnnotations(annotations)
+	status := tenantCopy.Status
+	tenantUpdated, err := c.edgenetclientset.CoreV1alpha().Tenants().Update(ctx, tenantCopy, metav1.UpdateOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Couldn't remove the force annotation", "tenant", klog.KObj(tenantCopy))
+		return false
+	}
+	// The status stays untouched by the update, the reset is applied to the copy
+	tenantUpdated.Status = status
+	tenantUpdated.DeepCopyInto(tenantCopy)
+
+	c.backoff.Forget(request(tenantCopy.GetName()))
+	c.failures.forget(tenantCopy.GetName())
+	tenantCopy.Status.Retries = 0
+	tenantCopy.Status.LastFailure = nil
+	// The establishment starts over from the first step
+	tenantCopy.Status.Onboarding = nil
+	meta.RemoveStatusCondition(&tenantCopy.Status.Conditions, conditionStalled)
+	c.recorder.Event(tenantCopy, corev1.EventTypeNormal, successForced, messageForced)
+	return true
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/controller/core/v1alpha/operation"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	"github.com/EdgeNet-project/edgenet/pkg/index"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// OperationTeardown is the type of the operation cleaning up after a disabled tenant
+const OperationTeardown = "TenantTeardown"
+
+// The collections the teardown deletes before the subsidiary namespaces. Their names are
+// capitalized so that they never collide with the names of the namespaces.
+const (
+	itemClusterRoleBindings = "ClusterRoleBindings"
+	itemRoleBindings        = "RoleBindings"
+	itemExtensionRequests   = "ExtensionRequests"
+)
+
+// TeardownWorker deletes the bindings and the extension requests of a disabled tenant, then its
+// subsidiary namespaces one by one. Each of them is an item of the operation, so that a tenant
+// with many objects neither holds the reconcile loop nor runs into the client timeouts.
+type TeardownWorker struct {
+	Clientset        kubernetes.Interface
+	EdgenetClientset clientset.Interface
+	// Namespaces is the cache of the namespaces indexed by tenant, the namespaces are listed from
+	// the API server without it
+	Namespaces cache.Indexer
+}
+
+// Items lists the collections to delete and the subsidiary namespaces of the tenant
+func (w TeardownWorker) Items(ctx context.Context, operationCopy *corev1alpha.Operation) ([]string, error) {
+	parameters := operationCopy.Spec.Parameters
+	set := labels.Set{"edge-net.io/tenant-uid": parameters["tenant-uid"], "edge-net.io/cluster-uid": parameters["cluster-uid"], "edge-net.io/kind": "sub"}
+	// The bindings go first to take the access away before the namespaces are gone
+	items := []string{itemClusterRoleBindings, itemRoleBindings, itemExtensionRequests}
+	if w.Namespaces != nil {
+		namespaces, err := index.Namespaces(w.Namespaces, parameters["tenant"], set)
+		if err != nil {
+			return nil, err
+		}
+		for _, namespace := range namespaces {
+			items = append(items, namespace.GetName())
+		}
+		return items, nil
+	}
+	set[index.LabelTenant] = parameters["tenant"]
+	namespaceRaw, err := w.Clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: set.String()})
+	if err != nil {
+		return nil, err
+	}
+	for _, namespaceRow := range namespaceRaw.Items {
+		items = append(items, namespaceRow.GetName())
+	}
+	return items, nil
+}
+
+// Process deletes a collection or a subsidiary namespace
+func (w TeardownWorker) Process(ctx context.Context, operationCopy *corev1alpha.Operation, item string) error {
+	parameters := operationCopy.Spec.Parameters
+	var err error
+	switch item {
+	case itemClusterRoleBindings:
+		selector := labels.Set{index.LabelTenant: parameters["tenant"], "edge-net.io/tenant-uid": parameters["tenant-uid"], "edge-net.io/cluster-uid": parameters["cluster-uid"]}
+		err = w.Clientset.RbacV1().ClusterRoleBindings().DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{LabelSelector: selector.String()})
+	case itemRoleBindings:
+		err = w.Clientset.RbacV1().RoleBindings(parameters["tenant"]).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{})
+	case itemExtensionRequests:
+		// Extension requests own the operators installed in the core namespace
+		err = w.EdgenetClientset.RegistrationV1alpha().ExtensionRequests(parameters["tenant"]).DeleteCollection(ctx, metav1.DeleteOptions{}, metav1.ListOptions{})
+	default:
+		err = w.Clientset.CoreV1().Namespaces().Delete(ctx, item, metav1.DeleteOptions{})
+	}
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// startTeardown hands the clean up of the tenant over to an operation, and mirrors the progress of
+// the operation in the status of the tenant
+func (c *Controller) startTeardown(ctx context.Context, tenantCopy *corev1alpha.Tenant, clusterUID string) error {
+	initiator := corev1.ObjectReference{Kind: "Tenant", APIVersion: corev1alpha.SchemeGroupVersion.String(), Name: tenantCopy.GetName(), UID: tenantCopy.GetUID()}
+	parameters := map[string]string{"tenant": tenantCopy.GetName(), "tenant-uid": string(tenantCopy.GetUID()), "cluster-uid": clusterUID}
+	// A tenant disabled again after being enabled starts a new teardown
+	name := fmt.Sprintf("%s-teardown-%d", tenantCopy.GetName(), tenantCopy.GetGeneration())
+	reference, err := operation.Start(ctx, c.edgenetclientset, name, OperationTeardown, initiator, parameters, SetAsOwnerReference(tenantCopy))
+	if err != nil {
+		return err
+	}
+	if c.operationsLister != nil {
+		if operationObj, err := c.operationsLister.Get(name); err == nil {
+			reference.State = operationObj.Status.State
+			reference.Progress = operationObj.Status.Progress
+		}
+	}
+	tenantCopy.Status.Operation = reference
+	return nil
+}
+
+// mapOperation returns the tenant that started the teardown, so that its status follows the
+// progress of the operation
+func mapOperation(obj client.Object) []reconcile.Request {
+	operationObj := obj.(*corev1alpha.Operation)
+	if operationObj.Spec.Type != OperationTeardown || operationObj.Spec.Initiator.Kind != "Tenant" {
+		return nil
+	}
+	return []reconcile.Request{request(operationObj.Spec.Initiator.Name)}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// Limits of the events emitted for a single object
+var (
+	// eventBurst is the number of events an object can emit in a row
+	eventBurst = 10
+	// eventRefill is the time it takes for an object to earn another event
+	eventRefill = 30 * time.Second
+	// eventDedupWindow is the time during which an identical event of an object is emitted once
+	eventDedupWindow = 10 * time.Minute
+)
+
+// eventKey identifies the identical events of an object
+type eventKey struct {
+	object    string
+	eventtype string
+	reason    string
+	message   string
+}
+
+// eventEntry counts the occurrences of an event suppressed since it was last emitted
+type eventEntry struct {
+	since      time.Time
+	lastEmit   time.Time
+	suppressed int
+}
+
+// eventBudget is the token bucket of an object
+type eventBudget struct {
+	tokens  float64
+	updated time.Time
+}
+
+// throttledRecorder deduplicates the events of each object and limits their rate, so that a
+// flapping tenant doesn't flood the event stream. An event that is let through after being
+// suppressed tells how many times it occurred meanwhile.
+type throttledRecorder struct {
+	recorder  record.EventRecorder
+	mutex     sync.Mutex
+	events    map[eventKey]*eventEntry
+	budgets   map[string]*eventBudget
+	lastSweep time.Time
+	metrics   *prometheus.CounterVec
+	now       func() time.Time
+}
+
+func newThrottledRecorder(recorder record.EventRecorder) *throttledRecorder {
+	return &throttledRecorder{recorder: recorder, events: make(map[eventKey]*eventEntry),
+		budgets: make(map[string]*eventBudget), metrics: suppressedEvents, now: time.Now}
+}
+
+// Event emits the event unless it is throttled
+func (t *throttledRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if message, ok := t.admit(object, eventtype, reason, message); ok {
+		t.recorder.Event(object, eventtype, reason, message)
+	}
+}
+
+// Eventf emits the formatted event unless it is throttled
+func (t *throttledRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	t.Event(object, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf emits the formatted event with annotations unless it is throttled
+func (t *throttledRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	if message, ok := t.admit(object, eventtype, reason, fmt.Sprintf(messageFmt, args...)); ok {
+		t.recorder.AnnotatedEventf(object, annotations, eventtype, reason, "%s", message)
+	}
+}
+
+// admit decides whether an event is emitted and returns its message, the suppressed
+// occurrences of the event are summarized in the message
+func (t *throttledRecorder) admit(object runtime.Object, eventtype, reason, message string) (string, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := t.now()
+	t.sweep(now)
+	key := eventKey{object: objectKey(object), eventtype: eventtype, reason: reason, message: message}
+	entry, exists := t.events[key]
+	if !exists {
+		entry = &eventEntry{since: now}
+		t.events[key] = entry
+	}
+	if !entry.lastEmit.IsZero() && now.Sub(entry.lastEmit) < eventDedupWindow {
+		entry.suppressed++
+		t.metrics.WithLabelValues(reason).Inc()
+		return "", false
+	}
+	budget, exists := t.budgets[key.object]
+	if !exists {
+		budget = &eventBudget{tokens: float64(eventBurst), updated: now}
+		t.budgets[key.object] = budget
+	}
+	budget.tokens += float64(now.Sub(budget.updated)) / float64(eventRefill)
+	if budget.tokens > float64(eventBurst) {
+		budget.tokens = float64(eventBurst)
+	}
+	budget.updated = now
+	if budget.tokens < 1 {
+		entry.suppressed++
+		t.metrics.WithLabelValues(reason).Inc()
+		return "", false
+	}
+	budget.tokens--
+
+	if entry.suppressed > 0 {
+		message = fmt.Sprintf("%s (occurred %d times since %s)", message, entry.suppressed+1, entry.since.Format(time.RFC3339))
+	}
+	entry.since = now
+	entry.lastEmit = now
+	entry.suppressed = 0
+	return message, true
+}
+
+// sweep drops the records that have been idle for longer than the deduplication window
+func (t *throttledRecorder) sweep(now time.Time) {
+	if now.Sub(t.lastSweep) < eventDedupWindow {
+		return
+	}
+	t.lastSweep = now
+	for key, entry := range t.events {
+		if entry.suppressed == 0 && now.Sub(entry.lastEmit) >= eventDedupWindow {
+			delete(t.events, key)
+		}
+	}
+	for object, budget := range t.budgets {
+		if now.Sub(budget.updated) >= time.Duration(eventBurst)*eventRefill {
+			delete(t.budgets, object)
+		}
+	}
+}
+
+// objectKey returns the kind, namespace, and name of the object
+func objectKey(object runtime.Object) string {
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return fmt.Sprintf("%T", object)
+	}
+	return fmt.Sprintf("%T/%s/%s", object, accessor.GetNamespace(), accessor.GetName())
+}
+
+// suppressedEvents counts the suppressed events by reason
+var suppressedEvents = newSuppressedEvents()
+
+func init() {
+	metrics.Registry.MustRegister(suppressedEvents)
+}
+
+func newSuppressedEvents() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{Name: "edgenet_tenant_events_suppressed_total",
+		Help: "Number of tenant controller events suppressed by deduplication or rate limiting."}, []string{"reason"})
+}
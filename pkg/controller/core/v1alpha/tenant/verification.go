@@ -0,0 +1,291 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// Condition types and reasons set by the verification probes
+const (
+	conditionOwnerAccess    = "OwnerAccessVerified"
+	conditionIsolation      = "IsolationVerified"
+	reasonProbeSucceeded    = "ProbeSucceeded"
+	reasonProbeFailed       = "ProbeFailed"
+	reasonProbeError        = "ProbeError"
+	messageOwnerAccess      = "Tenant owner is allowed to list pods in the core namespace"
+	messageOwnerNoAccess    = "Tenant owner is not allowed to list pods in the core namespace"
+	messageIsolation        = "Traffic from other tenants is blocked in the core namespace"
+	messageNoIsolation      = "Traffic from other tenants is not blocked in the core namespace"
+	messageNotEnforced      = "Traffic from other tenants reached the core namespace despite the network policies"
+	failureVerification     = "Verification Failed"
+	messageVerificationFail = "Post-establishment verification failed"
+	// probeTenant is the name given to the synthetic peer in the connectivity check
+	probeTenant = "edgenet-isolation-probe"
+)
+
+// verifyTenant runs the post-establishment probes against the tenant and records
+// the results as status conditions. It returns false if any probe did not succeed.
+// Once they all succeed, the probes only run again when the generation of the tenant changes.
+func (c *Controller) verifyTenant(ctx context.Context, tenantCopy *corev1alpha.Tenant, clusterUID string) bool {
+	if isVerified(tenantCopy) {
+		return true
+	}
+	verified := true
+	ownerAccess := c.probeOwnerAccess(ctx, tenantCopy)
+	if ownerAccess.Status != metav1.ConditionTrue {
+		verified = false
+	}
+	meta.SetStatusCondition(&tenantCopy.Status.Conditions, ownerAccess)
+
+	isolation := c.probeIsolation(ctx, tenantCopy, clusterUID)
+	if isolation.Reason == reasonProbeRunning {
+		// The connectivity probe is checked again shortly, it does not fail the tenant meanwhile
+		requeueAfter(ctx, probeRecheck)
+	} else if isolation.Status != metav1.ConditionTrue {
+		verified = false
+	}
+	meta.SetStatusCondition(&tenantCopy.Status.Conditions, isolation)
+	return verified
+}
+
+// isVerified tells whether all probes succeeded for the generation of the tenant. The failures
+// are probed again, they may come from the caches catching up with the objects just applied.
+func isVerified(tenantCopy *corev1alpha.Tenant) bool {
+	for _, conditionType := range []string{conditionOwnerAccess, conditionIsolation} {
+		condition := meta.FindStatusCondition(tenantCopy.Status.Conditions, conditionType)
+		if condition == nil || condition.ObservedGeneration != tenantCopy.GetGeneration() || condition.Status != metav1.ConditionTrue {
+			return false
+		}
+	}
+	return true
+}
+
+// probeOwnerAccess asks the API server whether the tenant owner can list pods
+// in the core namespace, which catches a missing or broken role binding.
+func (c *Controller) probeOwnerAccess(ctx context.Context, tenantCopy *corev1alpha.Tenant) metav1.Condition {
+	condition := metav1.Condition{Type: conditionOwnerAccess, ObservedGeneration: tenantCopy.GetGeneration()}
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User: tenantCopy.Spec.Contact.Email,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: tenantCopy.GetName(),
+				Verb:      "list",
+				Resource:  "pods",
+			},
+		},
+	}
+	result, err := c.kubeclientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Couldn't run the owner access probe", "tenant", klog.KObj(tenantCopy))
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = reasonProbeError
+		condition.Message = err.Error()
+		return condition
+	}
+	if result.Status.Allowed {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reasonProbeSucceeded
+		condition.Message = messageOwnerAccess
+	} else {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonProbeFailed
+		condition.Message = messageOwnerNoAccess
+		if result.Status.Reason != "" {
+			condition.Message = fmt.Sprintf("%s: %s", messageOwnerNoAccess, result.Status.Reason)
+		}
+	}
+	return condition
+}
+
+// probeIsolation checks the isolation of the core namespace in two stages. The network policies
+// are evaluated against a peer that pretends to live in the namespace of another tenant first.
+// If they block the peer, a pod of such a namespace tries to reach a pod of the tenant, once per
+// generation of the tenant, which catches a CNI plugin that ignores the policies.
+func (c *Controller) probeIsolation(ctx context.Context, tenantCopy *corev1alpha.Tenant, clusterUID string) metav1.Condition {
+	condition := metav1.Condition{Type: conditionIsolation, ObservedGeneration: tenantCopy.GetGeneration()}
+	networkPolicies, err := c.networkPoliciesLister.NetworkPolicies(tenantCopy.GetName()).List(labels.Everything())
+	if err == nil {
+		var podCIDRs []*net.IPNet
+		if podCIDRs, err = c.podCIDRs(); err == nil {
+			if !isIngressIsolated(networkPolicies, probeNamespaceLabels(clusterUID), podCIDRs) {
+				condition.Status = metav1.ConditionFalse
+				condition.Reason = reasonProbeFailed
+				condition.Message = messageNoIsolation
+				return condition
+			}
+		}
+	}
+	if err != nil {
+		klog.ErrorS(err, "Couldn't run the isolation probe", "tenant", klog.KObj(tenantCopy))
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = reasonProbeError
+		condition.Message = err.Error()
+		return condition
+	}
+	if IsolationProbeImage == "" {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reasonProbeSucceeded
+		condition.Message = messageIsolation
+		return condition
+	}
+	return c.probeConnectivity(ctx, tenantCopy, clusterUID)
+}
+
+// probeNamespaceLabels are the labels of the namespace of the synthetic peer, those of the core
+// namespace of another tenant
+func probeNamespaceLabels(clusterUID string) labels.Set {
+	return labels.Set{
+		"edge-net.io/kind":        "core",
+		"edge-net.io/subtenant":   "false",
+		"edge-net.io/tenant":      probeTenant,
+		"edge-net.io/tenant-uid":  probeTenant,
+		"edge-net.io/cluster-uid": clusterUID,
+	}
+}
+
+// podCIDRs returns the pod address ranges of the nodes, of both address families on dual-stack
+// nodes. The pods are assumed to take private addresses when the CNI plugin allocates them on its own.
+func (c *Controller) podCIDRs() ([]*net.IPNet, error) {
+	nodes, err := c.nodesLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	podCIDRs := []*net.IPNet{}
+	for _, nodeRow := range nodes {
+		ranges := nodeRow.Spec.PodCIDRs
+		if len(ranges) == 0 && nodeRow.Spec.PodCIDR != "" {
+			ranges = []string{nodeRow.Spec.PodCIDR}
+		}
+		for _, cidr := range ranges {
+			if _, podCIDR, err := net.ParseCIDR(cidr); err == nil {
+				podCIDRs = append(podCIDRs, podCIDR)
+			}
+		}
+	}
+	if len(podCIDRs) == 0 {
+		for _, cidr := range access.PrivateRanges() {
+			_, podCIDR, _ := net.ParseCIDR(cidr)
+			podCIDRs = append(podCIDRs, podCIDR)
+		}
+	}
+	return podCIDRs, nil
+}
+
+// isIngressIsolated returns true if all pods of the namespace are selected by an ingress policy,
+// and none of the ingress rules, whichever pods they apply to, admits a peer in a namespace with
+// the given labels. An IP block admits the peer when it overlaps the pod address ranges, or any
+// IP block does when the ranges are unknown. An empty list of policies means that all traffic is
+// allowed.
+func isIngressIsolated(networkPolicies []*networkingv1.NetworkPolicy, peerNamespaceLabels labels.Set, podCIDRs []*net.IPNet) bool {
+	isolated := false
+	for _, networkPolicy := range networkPolicies {
+		if !isIngressPolicy(networkPolicy) {
+			continue
+		}
+		if len(networkPolicy.Spec.PodSelector.MatchLabels) == 0 && len(networkPolicy.Spec.PodSelector.MatchExpressions) == 0 {
+			isolated = true
+		}
+		// A policy selecting some pods only opens these pods to the peers it admits
+		for _, rule := range networkPolicy.Spec.Ingress {
+			if len(rule.From) == 0 {
+				return false
+			}
+			for _, peer := range rule.From {
+				if peer.IPBlock != nil {
+					if ipBlockAdmits(peer.IPBlock, podCIDRs) {
+						return false
+					}
+					continue
+				}
+				if peer.NamespaceSelector == nil {
+					// A pod selector alone stands for the pods of the same namespace
+					continue
+				}
+				selector, err := metav1.LabelSelectorAsSelector(peer.NamespaceSelector)
+				if err != nil {
+					continue
+				}
+				if selector.Matches(peerNamespaceLabels) {
+					return false
+				}
+			}
+		}
+	}
+	return isolated
+}
+
+// isIngressPolicy tells whether the policy restricts the ingress, the policies without any type
+// do as the API server defaults them so
+func isIngressPolicy(networkPolicy *networkingv1.NetworkPolicy) bool {
+	if len(networkPolicy.Spec.PolicyTypes) == 0 {
+		return true
+	}
+	for _, policyType := range networkPolicy.Spec.PolicyTypes {
+		if policyType == networkingv1.PolicyTypeIngress {
+			return true
+		}
+	}
+	return false
+}
+
+// ipBlockAdmits tells whether the block covers the address of a pod. The block admits the pods
+// of a range it overlaps, unless the range falls entirely within one of its exceptions.
+func ipBlockAdmits(ipBlock *networkingv1.IPBlock, podCIDRs []*net.IPNet) bool {
+	_, block, err := net.ParseCIDR(ipBlock.CIDR)
+	if err != nil {
+		return false
+	}
+	if len(podCIDRs) == 0 {
+		return true
+	}
+	for _, podCIDR := range podCIDRs {
+		if !block.Contains(podCIDR.IP) && !podCIDR.Contains(block.IP) {
+			continue
+		}
+		excepted := false
+		for _, except := range ipBlock.Except {
+			if _, exception, err := net.ParseCIDR(except); err == nil && contains(exception, podCIDR) {
+				excepted = true
+				break
+			}
+		}
+		if !excepted {
+			return true
+		}
+	}
+	return false
+}
+
+// contains tells whether the outer range includes the whole inner range
+func contains(outer, inner *net.IPNet) bool {
+	outerOnes, outerBits := outer.Mask.Size()
+	innerOnes, innerBits := inner.Mask.Size()
+	return outerBits == innerBits && outerOnes <= innerOnes && outer.Contains(inner.IP)
+}
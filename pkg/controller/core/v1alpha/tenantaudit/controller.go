@@ -0,0 +1,213 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantaudit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+	"github.com/EdgeNet-project/edgenet/pkg/storage"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+// RetentionPeriod is how long a record stays in the audit trail before it gets pruned, or moved to
+// the archive if there is one
+var RetentionPeriod = 365 * 24 * time.Hour
+
+// Controller prunes the TenantAudit records once their retention period is over, the records
+// themselves are appended by the controllers that take the actions
+type Controller struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+	// edgenetclientset is a clientset for the EdgeNet API groups
+	edgenetclientset clientset.Interface
+
+	tenantAuditsLister listers.TenantAuditLister
+	tenantAuditsSynced cache.InformerSynced
+
+	// workqueue is a rate limited work queue. This is used to queue work to be
+	// processed instead of performing it as soon as a change happens. This
+	// means we can ensure we only process a fixed amount of resources at a
+	// time, and makes it easy to ensure we are never processing the same item
+	// simultaneously in two different workers.
+	workqueue workqueue.RateLimitingInterface
+}
+
+// NewController returns a new controller
+func NewController(
+	kubeclientset kubernetes.Interface,
+	edgenetclientset clientset.Interface,
+	tenantAuditInformer informers.TenantAuditInformer) *Controller {
+
+	controller := &Controller{
+		kubeclientset:      kubeclientset,
+		edgenetclientset:   edgenetclientset,
+		tenantAuditsLister: tenantAuditInformer.Lister(),
+		tenantAuditsSynced: tenantAuditInformer.Informer().HasSynced,
+		workqueue:          workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "TenantAudits"),
+	}
+
+	klog.V(4).InfoS("Setting up event handlers")
+	// The records are immutable, they are queued until the end of their retention period once
+	tenantAuditInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			controller.enqueueTenantAuditAfter(obj, remaining(obj.(*corev1alpha.TenantAudit)))
+		},
+	})
+
+	return controller
+}
+
+// Run will set up the event handlers for the types of tenant audit, as well
+// as syncing informer caches and starting workers. It will block until stopCh
+// is closed, at which point it will shutdown the workqueue and wait for
+// workers to finish processing their current work items.
+func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting TenantAudit controller")
+
+	klog.V(4).InfoS("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh,
+		c.tenantAuditsSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	klog.V(4).InfoS("Starting workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	klog.V(4).InfoS("Started workers")
+	<-stopCh
+	klog.V(4).InfoS("Shutting down workers")
+
+	return nil
+}
+
+// runWorker is a long-running function that will continually call the
+// processNextWorkItem function in order to read and process a message on the
+// workqueue.
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+// processNextWorkItem will read a single work item off the workqueue and
+// attempt to process it, by calling the syncHandler.
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.workqueue.Done(obj)
+		var key string
+		var ok bool
+
+		if key, ok = obj.(string); !ok {
+			c.workqueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			return nil
+		}
+		if err := c.syncHandler(ctx, key); err != nil {
+			c.workqueue.AddRateLimited(key)
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
+		}
+		c.workqueue.Forget(obj)
+		klog.V(4).InfoS("Successfully synced", "key", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+
+	return true
+}
+
+// syncHandler prunes the record if its retention period is over, and queues it again otherwise
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	tenantAudit, err := c.tenantAuditsLister.Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	return c.pruneTenantAudit(ctx, tenantAudit)
+}
+
+// pruneTenantAudit moves the record to the archive once its retention period is over, or removes
+// it if there is no archive
+func (c *Controller) pruneTenantAudit(ctx context.Context, tenantAudit *corev1alpha.TenantAudit) error {
+	if left := remaining(tenantAudit); left > 0 {
+		c.enqueueTenantAuditAfter(tenantAudit, left)
+		return nil
+	}
+	klog.V(4).InfoS("Pruning the audit record", "tenantAudit", klog.KObj(tenantAudit), "tenant", tenantAudit.Spec.Tenant)
+	// The record is kept in the archive if there is one, a record that cannot be archived stays
+	if err := storage.Archive(ctx, storage.KindTenantAudit, tenantAudit.GetName(), tenantAudit.Spec.Tenant, tenantAudit.Spec.Time.Time, tenantAudit); err != nil {
+		return err
+	}
+	if err := c.edgenetclientset.CoreV1alpha().TenantAudits().Delete(ctx, tenantAudit.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// remaining returns the time left before the record gets pruned
+func remaining(tenantAudit *corev1alpha.TenantAudit) time.Duration {
+	return time.Until(tenantAudit.Spec.Time.Add(RetentionPeriod))
+}
+
+// enqueueTenantAuditAfter takes a TenantAudit resource and converts it into a name
+// string which is then put onto the work queue after the given duration.
+func (c *Controller) enqueueTenantAuditAfter(obj interface{}, after time.Duration) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.AddAfter(key, after)
+}
@@ -0,0 +1,73 @@
+package tenantaudit
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	"github.com/EdgeNet-project/edgenet/pkg/storage"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+func newRecord(t *testing.T, c *Controller, name string, age time.Duration) *corev1alpha.TenantAudit {
+	record := &corev1alpha.TenantAudit{ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1alpha.TenantAuditSpec{Tenant: "edgenet", Action: "TenantEnabled", Time: metav1.NewTime(time.Now().Add(-age))}}
+	_, err := c.edgenetclientset.CoreV1alpha().TenantAudits().Create(context.TODO(), record, metav1.CreateOptions{})
+	util.OK(t, err)
+	return record
+}
+
+func TestPruneTenantAudit(t *testing.T) {
+	c := &Controller{
+		kubeclientset:    testclient.NewSimpleClientset(),
+		edgenetclientset: edgenettestclient.NewSimpleClientset(),
+		workqueue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "TenantAudits"),
+	}
+	defer c.workqueue.ShutDown()
+
+	expired := newRecord(t, c, "edgenet-expired", RetentionPeriod+time.Hour)
+	fresh := newRecord(t, c, "edgenet-fresh", time.Hour)
+
+	util.OK(t, c.pruneTenantAudit(context.TODO(), expired))
+	_, err := c.edgenetclientset.CoreV1alpha().TenantAudits().Get(context.TODO(), expired.GetName(), metav1.GetOptions{})
+	util.Assert(t, err != nil, "expired record is kept")
+
+	util.OK(t, c.pruneTenantAudit(context.TODO(), fresh))
+	_, err = c.edgenetclientset.CoreV1alpha().TenantAudits().Get(context.TODO(), fresh.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Assert(t, remaining(fresh) > RetentionPeriod-2*time.Hour, "remaining time of %s is %s", fresh.GetName(), remaining(fresh))
+
+	// Pruning a record already gone is not an error
+	util.OK(t, c.pruneTenantAudit(context.TODO(), expired))
+
+	t.Run("archive", func(t *testing.T) {
+		store := storage.NewMemoryStore()
+		storage.Default = store
+		defer func() { storage.Default = nil }()
+		expired := newRecord(t, c, "edgenet-archived", RetentionPeriod+time.Hour)
+		util.OK(t, c.pruneTenantAudit(context.TODO(), expired))
+		_, err := c.edgenetclientset.CoreV1alpha().TenantAudits().Get(context.TODO(), expired.GetName(), metav1.GetOptions{})
+		util.Assert(t, err != nil, "archived record is kept")
+		records, err := store.List(context.TODO(), storage.KindTenantAudit, "edgenet")
+		util.OK(t, err)
+		util.Equals(t, 1, len(records))
+		util.Equals(t, expired.GetName(), records[0].Name)
+		util.Equals(t, true, expired.Spec.Time.Time.Equal(records[0].Time))
+	})
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantmigration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const controllerAgentName = "tenantmigration-controller"
+
+// Definitions of the state of the tenant migration resource
+const (
+	successSynced          = "Synced"
+	messageResourceSynced  = "Tenant migration synced successfully"
+	successMigrated        = "Migrated"
+	messageMigrated        = "The subnamespaces moved to the target tenant"
+	failureMigration       = "Migration Failed"
+	messageMigrationFailed = "Migration failed and was rolled back"
+	messageInterrupted     = "Migration was interrupted and was rolled back"
+	messageRollbackFailed  = "Rollback failed, some changes are left"
+	messageStarted         = "Migration started"
+	inProgress             = "In Progress"
+	completed              = "Completed"
+	rolledBack             = "Rolled Back"
+	failure                = "Failure"
+)
+
+// Controller is the controller implementation for TenantMigration resources
+type Controller struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+	// edgenetclientset is a clientset for the EdgeNet API groups
+	edgenetclientset clientset.Interface
+
+	tenantMigrationsLister listers.TenantMigrationLister
+	tenantMigrationsSynced cache.InformerSynced
+
+	// workqueue is a rate limited work queue. This is used to queue work to be
+	// processed instead of performing it as soon as a change happens. This
+	// means we can ensure we only process a fixed amount of resources at a
+	// time, and makes it easy to ensure we are never processing the same item
+	// simultaneously in two different workers.
+	workqueue workqueue.RateLimitingInterface
+	// recorder is an event recorder for recording Event resources to the
+	// Kubernetes API.
+	recorder record.EventRecorder
+}
+
+// NewController returns a new controller
+func NewController(
+	kubeclientset kubernetes.Interface,
+	edgenetclientset clientset.Interface,
+	tenantMigrationInformer informers.TenantMigrationInformer) *Controller {
+
+	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
+	klog.V(4).InfoS("Creating event broadcaster")
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartStructuredLogging(0)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+
+	controller := &Controller{
+		kubeclientset:          kubeclientset,
+		edgenetclientset:       edgenetclientset,
+		tenantMigrationsLister: tenantMigrationInformer.Lister(),
+		tenantMigrationsSynced: tenantMigrationInformer.Informer().HasSynced,
+		workqueue:              workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "TenantMigrations"),
+		recorder:               recorder,
+	}
+
+	klog.V(4).InfoS("Setting up event handlers")
+	// The spec of a migration cannot change, a migration runs once when it is created
+	tenantMigrationInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueTenantMigration,
+	})
+
+	return controller
+}
+
+// Run will set up the event handlers for the types of tenant migration, as well
+// as syncing informer caches and starting workers. It will block until stopCh
+// is closed, at which point it will shutdown the workqueue and wait for
+// workers to finish processing their current work items.
+func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting TenantMigration controller")
+
+	klog.V(4).InfoS("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh,
+		c.tenantMigrationsSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	klog.V(4).InfoS("Starting workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	klog.V(4).InfoS("Started workers")
+	<-stopCh
+	klog.V(4).InfoS("Shutting down workers")
+
+	return nil
+}
+
+// runWorker is a long-running function that will continually call the
+// processNextWorkItem function in order to read and process a message on the
+// workqueue.
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+// processNextWorkItem will read a single work item off the workqueue and
+// attempt to process it, by calling the syncHandler.
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.workqueue.Done(obj)
+		var key string
+		var ok bool
+
+		if key, ok = obj.(string); !ok {
+			c.workqueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			return nil
+		}
+		if err := c.syncHandler(ctx, key); err != nil {
+			c.workqueue.AddRateLimited(key)
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
+		}
+		c.workqueue.Forget(obj)
+		klog.V(4).InfoS("Successfully synced", "key", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+
+	return true
+}
+
+// syncHandler runs the migration and records its outcome in the Status block of the
+// TenantMigration resource
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	tenantMigration, err := c.tenantMigrationsLister.Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("tenant migration '%s' in work queue no longer exists", key))
+			return nil
+		}
+
+		return err
+	}
+	if finished(tenantMigration) {
+		return nil
+	}
+
+	tenantMigrationCopy := tenantMigration.DeepCopy()
+	if err := c.processTenantMigration(ctx, tenantMigrationCopy); err != nil {
+		return err
+	}
+	c.recorder.Event(tenantMigration, corev1.EventTypeNormal, successSynced, messageResourceSynced)
+	return nil
+}
+
+// enqueueTenantMigration takes a TenantMigration resource and converts it into a name
+// string which is then put onto the work queue. This method should *not* be
+// passed resources of any type other than TenantMigration.
+func (c *Controller) enqueueTenantMigration(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+// processTenantMigration moves the subnamespaces of the source tenant to the target tenant. A
+// migration is all or nothing: the first change that fails rolls the ones before it back. A
+// migration found in progress was interrupted halfway, it is rolled back as well.
+func (c *Controller) processTenantMigration(ctx context.Context, tenantMigrationCopy *corev1alpha.TenantMigration) error {
+	if tenantMigrationCopy.Status.State == inProgress {
+		c.rollBack(ctx, tenantMigrationCopy, messageInterrupted)
+		return c.updateStatus(ctx, tenantMigrationCopy)
+	}
+
+	tenantMigrationCopy.Status.State = inProgress
+	tenantMigrationCopy.Status.Message = messageStarted
+	if err := c.updateStatus(ctx, tenantMigrationCopy); err != nil {
+		return err
+	}
+	if err := c.migrate(ctx, tenantMigrationCopy); err != nil {
+		klog.ErrorS(err, "Couldn't migrate the tenant", "tenantMigration", klog.KObj(tenantMigrationCopy))
+		c.rollBack(ctx, tenantMigrationCopy, fmt.Sprintf("%s: %v", messageMigrationFailed, err))
+		return c.updateStatus(ctx, tenantMigrationCopy)
+	}
+	c.complete(tenantMigrationCopy, completed, messageMigrated)
+	c.recorder.Event(tenantMigrationCopy, corev1.EventTypeNormal, successMigrated, messageMigrated)
+	return c.updateStatus(ctx, tenantMigrationCopy)
+}
+
+// rollBack undoes the journaled changes and puts the migration into a final state
+func (c *Controller) rollBack(ctx context.Context, tenantMigrationCopy *corev1alpha.TenantMigration, message string) {
+	if err := c.undo(ctx, tenantMigrationCopy); err != nil {
+		klog.ErrorS(err, "Couldn't roll the migration back", "tenantMigration", klog.KObj(tenantMigrationCopy))
+		c.complete(tenantMigrationCopy, failure, fmt.Sprintf("%s; %s: %v", message, messageRollbackFailed, err))
+	} else {
+		c.complete(tenantMigrationCopy, rolledBack, message)
+	}
+	c.recorder.Event(tenantMigrationCopy, corev1.EventTypeWarning, failureMigration, tenantMigrationCopy.Status.Message)
+}
+
+// complete puts the migration into a final state
+func (c *Controller) complete(tenantMigrationCopy *corev1alpha.TenantMigration, state, message string) {
+	now := metav1.Now()
+	tenantMigrationCopy.Status.State = state
+	tenantMigrationCopy.Status.Message = message
+	tenantMigrationCopy.Status.CompletionTime = &now
+}
+
+// updateStatus persists the status, the copy takes the new resource version so that the
+// journal is written after each change
+func (c *Controller) updateStatus(ctx context.Context, tenantMigrationCopy *corev1alpha.TenantMigration) error {
+	updated, err := c.edgenetclientset.CoreV1alpha().TenantMigrations().UpdateStatus(ctx, tenantMigrationCopy, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	tenantMigrationCopy.SetResourceVersion(updated.GetResourceVersion())
+	return nil
+}
+
+// finished tells whether the migration reached a final state
+func finished(tenantMigration *corev1alpha.TenantMigration) bool {
+	switch tenantMigration.Status.State {
+	case completed, rolledBack, failure:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,201 @@
+package tenantmigration
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+func newTenant(name string, enabled bool) *corev1alpha.Tenant {
+	return &corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name + "-uid")},
+		Spec: corev1alpha.TenantSpec{Enabled: enabled}}
+}
+
+func newTenantResourceQuota(name, cpu string) *corev1alpha.TenantResourceQuota {
+	return &corev1alpha.TenantResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1alpha.TenantResourceQuotaSpec{Claim: map[string]corev1alpha.ResourceTuning{
+			"initial": {ResourceList: map[corev1.ResourceName]resource.Quantity{corev1.ResourceCPU: resource.MustParse(cpu)}}}}}
+}
+
+func newRoleBinding(namespace, tenant, user string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "edgenet:tenant-admin", Namespace: namespace,
+		Labels: map[string]string{"edge-net.io/tenant": tenant}},
+		RoleRef:  rbacv1.RoleRef{Kind: "ClusterRole", Name: "edgenet:tenant-admin"},
+		Subjects: []rbacv1.Subject{{Kind: "User", Name: user, APIGroup: "rbac.authorization.k8s.io"}}}
+}
+
+// newController returns a controller with the tenant lip6-lab, whose subnamespace is to move to
+// the tenant lip6, and the migration
+func newController() (*Controller, *corev1alpha.TenantMigration) {
+	sourceNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "lip6-lab", UID: "lip6-lab-namespace-uid",
+		Labels: map[string]string{"edge-net.io/tenant": "lip6-lab", "edge-net.io/kind": "core"}}}
+	targetNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "lip6", UID: "lip6-namespace-uid",
+		Labels: map[string]string{"edge-net.io/tenant": "lip6", "edge-net.io/kind": "core"}}}
+	controller := false
+	subNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "lip6-lab-course",
+		Labels: map[string]string{"edge-net.io/tenant": "lip6-lab", "edge-net.io/tenant-uid": "lip6-lab-uid", "edge-net.io/kind": "sub",
+			"edge-net.io/parent-namespace": "lip6-lab"},
+		OwnerReferences: []metav1.OwnerReference{{APIVersion: "v1", Kind: "Namespace", Name: "lip6-lab", UID: "lip6-lab-namespace-uid", Controller: &controller}}}}
+	tenantMigration := &corev1alpha.TenantMigration{ObjectMeta: metav1.ObjectMeta{Name: "lip6-lab-to-lip6"},
+		Spec: corev1alpha.TenantMigrationSpec{Source: "lip6-lab", Target: "lip6", DisableSource: true}}
+
+	c := &Controller{
+		kubeclientset: testclient.NewSimpleClientset(sourceNamespace, targetNamespace, subNamespace,
+			newRoleBinding("lip6-lab-course", "lip6-lab", "alice@edge-net.org"), newRoleBinding("lip6", "lip6", "bob@edge-net.org")),
+		edgenetclientset: edgenettestclient.NewSimpleClientset(newTenant("lip6-lab", true), newTenant("lip6", true),
+			newTenantResourceQuota("lip6-lab", "2"), newTenantResourceQuota("lip6", "4"), tenantMigration),
+		recorder: record.NewFakeRecorder(100),
+	}
+	return c, tenantMigration.DeepCopy()
+}
+
+func TestMigrate(t *testing.T) {
+	c, tenantMigration := newController()
+	util.OK(t, c.processTenantMigration(context.TODO(), tenantMigration))
+	util.Equals(t, completed, tenantMigration.Status.State)
+	util.Equals(t, 4, len(tenantMigration.Status.Changes))
+
+	namespace, err := c.kubeclientset.CoreV1().Namespaces().Get(context.TODO(), "lip6-lab-course", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, "lip6", namespace.GetLabels()["edge-net.io/tenant"])
+	util.Equals(t, "lip6-uid", namespace.GetLabels()["edge-net.io/tenant-uid"])
+	util.Equals(t, "lip6", namespace.GetLabels()["edge-net.io/parent-namespace"])
+	util.Equals(t, "lip6", namespace.GetOwnerReferences()[0].Name)
+	util.Equals(t, types.UID("lip6-namespace-uid"), namespace.GetOwnerReferences()[0].UID)
+
+	roleBinding, err := c.kubeclientset.RbacV1().RoleBindings("lip6-lab-course").Get(context.TODO(), "edgenet:tenant-admin", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, "lip6", roleBinding.GetLabels()["edge-net.io/tenant"])
+	util.Equals(t, "bob@edge-net.org", roleBinding.Subjects[0].Name)
+
+	tenantResourceQuota, err := c.edgenetclientset.CoreV1alpha().TenantResourceQuotas().Get(context.TODO(), "lip6", metav1.GetOptions{})
+	util.OK(t, err)
+	claim, ok := tenantResourceQuota.Spec.Claim["migration-lip6-lab"]
+	util.Assert(t, ok, "quota of the source is not claimed")
+	cpu := claim.ResourceList[corev1.ResourceCPU]
+	util.Equals(t, "2", cpu.String())
+
+	source, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), "lip6-lab", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Assert(t, !source.Spec.Enabled, "source tenant is still enabled")
+}
+
+func TestRollBack(t *testing.T) {
+	assertRolledBack := func(t *testing.T, c *Controller, tenantMigration *corev1alpha.TenantMigration) {
+		util.Equals(t, rolledBack, tenantMigration.Status.State)
+		for _, change := range tenantMigration.Status.Changes {
+			util.Assert(t, change.Undone, "%s %s is not undone", change.Kind, change.Name)
+		}
+		namespace, err := c.kubeclientset.CoreV1().Namespaces().Get(context.TODO(), "lip6-lab-course", metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, "lip6-lab", namespace.GetLabels()["edge-net.io/tenant"])
+		util.Equals(t, "lip6-lab", namespace.GetLabels()["edge-net.io/parent-namespace"])
+		util.Equals(t, "lip6-lab", namespace.GetOwnerReferences()[0].Name)
+		roleBinding, err := c.kubeclientset.RbacV1().RoleBindings("lip6-lab-course").Get(context.TODO(), "edgenet:tenant-admin", metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, "lip6-lab", roleBinding.GetLabels()["edge-net.io/tenant"])
+		util.Equals(t, "alice@edge-net.org", roleBinding.Subjects[0].Name)
+		tenantResourceQuota, err := c.edgenetclientset.CoreV1alpha().TenantResourceQuotas().Get(context.TODO(), "lip6", metav1.GetOptions{})
+		util.OK(t, err)
+		_, ok := tenantResourceQuota.Spec.Claim["migration-lip6-lab"]
+		util.Assert(t, !ok, "quota of the source is still claimed")
+		source, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), "lip6-lab", metav1.GetOptions{})
+		util.OK(t, err)
+		util.Assert(t, source.Spec.Enabled, "source tenant is disabled")
+	}
+
+	t.Run("failure", func(t *testing.T) {
+		c, tenantMigration := newController()
+		c.edgenetclientset.(*edgenettestclient.Clientset).PrependReactor("update", "tenants", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.(k8stesting.UpdateAction).GetObject().(*corev1alpha.Tenant).Spec.Enabled {
+				return false, nil, nil
+			}
+			return true, nil, fmt.Errorf("tenant cannot be disabled")
+		})
+		util.OK(t, c.processTenantMigration(context.TODO(), tenantMigration))
+		util.Equals(t, 4, len(tenantMigration.Status.Changes))
+		assertRolledBack(t, c, tenantMigration)
+	})
+	t.Run("interrupted", func(t *testing.T) {
+		c, tenantMigration := newController()
+		util.OK(t, c.migrate(context.TODO(), tenantMigration))
+		// The controller stopped before the migration completed
+		tenantMigration.Status.State = inProgress
+		util.OK(t, c.processTenantMigration(context.TODO(), tenantMigration))
+		assertRolledBack(t, c, tenantMigration)
+	})
+}
+
+func TestAdmit(t *testing.T) {
+	newTenantMigration := func(name, source, target, state string) *corev1alpha.TenantMigration {
+		return &corev1alpha.TenantMigration{ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:   corev1alpha.TenantMigrationSpec{Source: source, Target: target},
+			Status: corev1alpha.TenantMigrationStatus{State: state}}
+	}
+	edgenetclientset := edgenettestclient.NewSimpleClientset(
+		newTenant("lip6-lab", true), newTenant("lip6", true), newTenant("sorbonne", true), newTenant("edgenet", true), newTenant("unipi", false),
+		newTenantMigration("ongoing", "lip6-lab", "lip6", inProgress),
+		newTenantMigration("done", "sorbonne", "lip6-lab", completed))
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, 0)
+	webhook := NewWebhook(
+		edgenetInformerFactory.Core().V1alpha().Tenants(),
+		edgenetInformerFactory.Core().V1alpha().TenantMigrations(),
+	)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	edgenetInformerFactory.Start(stopCh)
+	util.OK(t, webhook.WaitForCacheSync(stopCh))
+
+	cases := map[string]struct {
+		source   string
+		target   string
+		admitted bool
+	}{
+		"admitted":        {"sorbonne", "edgenet", true},
+		"itself":          {"lip6", "lip6", false},
+		"missing source":  {"ghost", "lip6", false},
+		"missing target":  {"sorbonne", "ghost", false},
+		"disabled target": {"sorbonne", "unipi", false},
+		"overlapping":     {"sorbonne", "lip6", false},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			err := webhook.Admit(newTenantMigration("new", tc.source, tc.target, ""), nil)
+			util.Equals(t, tc.admitted, err == nil)
+		})
+	}
+
+	t.Run("update", func(t *testing.T) {
+		old := newTenantMigration("ongoing", "lip6-lab", "lip6", inProgress)
+		updated := old.DeepCopy()
+		updated.Status.State = completed
+		util.OK(t, webhook.Admit(updated, old))
+		updated.Spec.Target = "edgenet"
+		util.Assert(t, webhook.Admit(updated, old) != nil, "spec change is admitted")
+	})
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantmigration
+
+import (
+	"context"
+	"fmt"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	namespacev1 "github.com/EdgeNet-project/edgenet/pkg/namespace"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+// The kinds of the objects a migration changes
+const (
+	kindNamespace           = "Namespace"
+	kindRoleBinding         = "RoleBinding"
+	kindTenantResourceQuota = "TenantResourceQuota"
+	kindTenant              = "Tenant"
+)
+
+// migrate moves the subnamespaces of the source tenant to the target tenant. Each change is
+// journaled in the status before it is made, so that the journal covers a change that fails or
+// is interrupted halfway.
+func (c *Controller) migrate(ctx context.Context, tenantMigrationCopy *corev1alpha.TenantMigration) error {
+	sourceName, targetName := tenantMigrationCopy.Spec.Source, tenantMigrationCopy.Spec.Target
+	source, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(ctx, sourceName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	target, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(ctx, targetName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if !target.Spec.Enabled {
+		return fmt.Errorf("target tenant %s is disabled", targetName)
+	}
+	targetNamespace, err := c.kubeclientset.CoreV1().Namespaces().Get(ctx, targetName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	selector := labels.Set{"edge-net.io/tenant": sourceName, "edge-net.io/kind": "sub"}.String()
+	namespaceRaw, err := c.kubeclientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return err
+	}
+	for _, namespace := range namespaceRaw.Items {
+		if err := c.migrateNamespace(ctx, tenantMigrationCopy, namespace.DeepCopy(), target, targetNamespace); err != nil {
+			return err
+		}
+		if err := c.migrateRoleBindings(ctx, tenantMigrationCopy, namespace.GetName(), targetName); err != nil {
+			return err
+		}
+	}
+	if err := c.migrateQuota(ctx, tenantMigrationCopy, sourceName, targetName); err != nil {
+		return err
+	}
+
+	if tenantMigrationCopy.Spec.DisableSource && source.Spec.Enabled {
+		if err := c.journal(ctx, tenantMigrationCopy, corev1alpha.MigrationChange{Kind: kindTenant, Name: sourceName}); err != nil {
+			return err
+		}
+		sourceCopy := source.DeepCopy()
+		sourceCopy.Spec.Enabled = false
+		if _, err := c.edgenetclientset.CoreV1alpha().Tenants().Update(ctx, sourceCopy, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateNamespace labels the namespace with the target tenant, and the namespace takes the core
+// namespace of the target as its parent if its parent was the core namespace of the source
+func (c *Controller) migrateNamespace(ctx context.Context, tenantMigrationCopy *corev1alpha.TenantMigration, namespace *corev1.Namespace, target *corev1alpha.Tenant, targetNamespace *corev1.Namespace) error {
+	change := corev1alpha.MigrationChange{Kind: kindNamespace, Name: namespace.GetName(), Labels: namespace.GetLabels(), OwnerReferences: namespace.GetOwnerReferences()}
+	if err := c.journal(ctx, tenantMigrationCopy, change); err != nil {
+		return err
+	}
+
+	namespaceLabels := map[string]string{}
+	for key, value := range namespace.GetLabels() {
+		namespaceLabels[key] = value
+	}
+	namespaceLabels["edge-net.io/tenant"] = target.GetName()
+	namespaceLabels["edge-net.io/tenant-uid"] = string(target.GetUID())
+	if namespaceLabels["edge-net.io/parent-namespace"] == tenantMigrationCopy.Spec.Source {
+		namespaceLabels["edge-net.io/parent-namespace"] = target.GetName()
+	}
+	namespace.SetLabels(namespaceLabels)
+
+	ownerReferences := []metav1.OwnerReference{}
+	for _, ownerReference := range namespace.GetOwnerReferences() {
+		if ownerReference.Kind == "Namespace" && ownerReference.Name == tenantMigrationCopy.Spec.Source {
+			ownerReferences = append(ownerReferences, namespacev1.SetAsOwnerReference(targetNamespace)...)
+			continue
+		}
+		ownerReferences = append(ownerReferences, ownerReference)
+	}
+	namespace.SetOwnerReferences(ownerReferences)
+
+	_, err := c.kubeclientset.CoreV1().Namespaces().Update(ctx, namespace, metav1.UpdateOptions{})
+	return err
+}
+
+// migrateRoleBindings labels the role bindings of the source tenant in the namespace with the
+// target tenant. A role binding takes the subjects of its namesake in the core namespace of the
+// target, the members of the target gain the same roles in the moved namespace.
+func (c *Controller) migrateRoleBindings(ctx context.Context, tenantMigrationCopy *corev1alpha.TenantMigration, namespace, targetName string) error {
+	selector := labels.Set{"edge-net.io/tenant": tenantMigrationCopy.Spec.Source}.String()
+	roleBindingRaw, err := c.kubeclientset.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return err
+	}
+	for _, roleBinding := range roleBindingRaw.Items {
+		change := corev1alpha.MigrationChange{Kind: kindRoleBinding, Namespace: namespace, Name: roleBinding.GetName(), Labels: roleBinding.GetLabels(), Subjects: roleBinding.Subjects}
+		if err := c.journal(ctx, tenantMigrationCopy, change); err != nil {
+			return err
+		}
+
+		roleBindingCopy := roleBinding.DeepCopy()
+		roleBindingLabels := roleBindingCopy.GetLabels()
+		roleBindingLabels["edge-net.io/tenant"] = targetName
+		roleBindingCopy.SetLabels(roleBindingLabels)
+		if targetRoleBinding, err := c.kubeclientset.RbacV1().RoleBindings(targetName).Get(ctx, roleBinding.GetName(), metav1.GetOptions{}); err == nil {
+			roleBindingCopy.Subjects = append([]rbacv1.Subject{}, targetRoleBinding.Subjects...)
+		} else if !errors.IsNotFound(err) {
+			return err
+		}
+		if _, err := c.kubeclientset.RbacV1().RoleBindings(namespace).Update(ctx, roleBindingCopy, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateQuota adds the quota assigned to the source tenant to the tenant resource quota of the
+// target as a claim, the moved namespaces keep the resources they were given
+func (c *Controller) migrateQuota(ctx context.Context, tenantMigrationCopy *corev1alpha.TenantMigration, sourceName, targetName string) error {
+	sourceQuota, err := c.edgenetclientset.CoreV1alpha().TenantResourceQuotas().Get(ctx, sourceName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	_, assignedQuota := sourceQuota.Fetch()
+	if len(assignedQuota) == 0 {
+		return nil
+	}
+	targetQuota, err := c.edgenetclientset.CoreV1alpha().TenantResourceQuotas().Get(ctx, targetName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	claim := fmt.Sprintf("migration-%s", sourceName)
+	if err := c.journal(ctx, tenantMigrationCopy, corev1alpha.MigrationChange{Kind: kindTenantResourceQuota, Name: targetName, Claim: claim}); err != nil {
+		return err
+	}
+	targetQuotaCopy := targetQuota.DeepCopy()
+	if targetQuotaCopy.Spec.Claim == nil {
+		targetQuotaCopy.Spec.Claim = map[string]corev1alpha.ResourceTuning{}
+	}
+	targetQuotaCopy.Spec.Claim[claim] = corev1alpha.ResourceTuning{ResourceList: assignedQuota}
+	_, err = c.edgenetclientset.CoreV1alpha().TenantResourceQuotas().Update(ctx, targetQuotaCopy, metav1.UpdateOptions{})
+	return err
+}
+
+// journal records the change in the status before it is made
+func (c *Controller) journal(ctx context.Context, tenantMigrationCopy *corev1alpha.TenantMigration, change corev1alpha.MigrationChange) error {
+	tenantMigrationCopy.Status.Changes = append(tenantMigrationCopy.Status.Changes, change)
+	return c.updateStatus(ctx, tenantMigrationCopy)
+}
+
+// undo rolls the journaled changes back in the reverse order. An object the migration changed
+// gets its state from before the migration back. The rollback goes on after a failure, so that
+// as few changes as possible are left, and returns the first error.
+func (c *Controller) undo(ctx context.Context, tenantMigrationCopy *corev1alpha.TenantMigration) error {
+	var firstErr error
+	changes := tenantMigrationCopy.Status.Changes
+	for i := len(changes) - 1; i >= 0; i-- {
+		if changes[i].Undone {
+			continue
+		}
+		if err := c.undoChange(ctx, changes[i]); err != nil && !errors.IsNotFound(err) {
+			klog.ErrorS(err, "Couldn't undo the change", "kind", changes[i].Kind, "namespace", changes[i].Namespace, "name", changes[i].Name)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		changes[i].Undone = true
+	}
+	return firstErr
+}
+
+// undoChange restores the object as it was before the change
+func (c *Controller) undoChange(ctx context.Context, change corev1alpha.MigrationChange) error {
+	switch change.Kind {
+	case kindNamespace:
+		namespace, err := c.kubeclientset.CoreV1().Namespaces().Get(ctx, change.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		namespaceCopy := namespace.DeepCopy()
+		namespaceCopy.SetLabels(change.Labels)
+		namespaceCopy.SetOwnerReferences(change.OwnerReferences)
+		_, err = c.kubeclientset.CoreV1().Namespaces().Update(ctx, namespaceCopy, metav1.UpdateOptions{})
+		return err
+	case kindRoleBinding:
+		roleBinding, err := c.kubeclientset.RbacV1().RoleBindings(change.Namespace).Get(ctx, change.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		roleBindingCopy := roleBinding.DeepCopy()
+		roleBindingCopy.SetLabels(change.Labels)
+		roleBindingCopy.Subjects = change.Subjects
+		_, err = c.kubeclientset.RbacV1().RoleBindings(change.Namespace).Update(ctx, roleBindingCopy, metav1.UpdateOptions{})
+		return err
+	case kindTenantResourceQuota:
+		tenantResourceQuota, err := c.edgenetclientset.CoreV1alpha().TenantResourceQuotas().Get(ctx, change.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if _, ok := tenantResourceQuota.Spec.Claim[change.Claim]; !ok {
+			return nil
+		}
+		tenantResourceQuotaCopy := tenantResourceQuota.DeepCopy()
+		delete(tenantResourceQuotaCopy.Spec.Claim, change.Claim)
+		_, err = c.edgenetclientset.CoreV1alpha().TenantResourceQuotas().Update(ctx, tenantResourceQuotaCopy, metav1.UpdateOptions{})
+		return err
+	case kindTenant:
+		tenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(ctx, change.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		tenantCopy := tenant.DeepCopy()
+		tenantCopy.Spec.Enabled = true
+		_, err = c.edgenetclientset.CoreV1alpha().Tenants().Update(ctx, tenantCopy, metav1.UpdateOptions{})
+		return err
+	}
+	return fmt.Errorf("unknown kind of change %s", change.Kind)
+}
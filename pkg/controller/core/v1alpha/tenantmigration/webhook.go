@@ -0,0 +1,146 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantmigration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// Webhook rejects the tenant migrations that cannot run: a tenant migrating to itself, a missing
+// or disabled tenant, or a tenant already taking part in a migration that is not over. The spec
+// of a migration cannot change once it is created.
+type Webhook struct {
+	tenantsLister          listers.TenantLister
+	tenantsSynced          cache.InformerSynced
+	tenantMigrationsLister listers.TenantMigrationLister
+	tenantMigrationsSynced cache.InformerSynced
+}
+
+// NewWebhook returns a new webhook
+func NewWebhook(
+	tenantInformer informers.TenantInformer,
+	tenantMigrationInformer informers.TenantMigrationInformer) *Webhook {
+	return &Webhook{
+		tenantsLister:          tenantInformer.Lister(),
+		tenantsSynced:          tenantInformer.Informer().HasSynced,
+		tenantMigrationsLister: tenantMigrationInformer.Lister(),
+		tenantMigrationsSynced: tenantMigrationInformer.Informer().HasSynced,
+	}
+}
+
+// WaitForCacheSync blocks until the caches of the webhook are synced or stopCh is closed
+func (w *Webhook) WaitForCacheSync(stopCh <-chan struct{}) error {
+	if ok := cache.WaitForCacheSync(stopCh, w.tenantsSynced, w.tenantMigrationsSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+	return nil
+}
+
+// Handler serves the admission reviews of the tenant migrations
+func (w *Webhook) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		review := admissionv1.AdmissionReview{}
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil || review.Request == nil {
+			http.Error(rw, "invalid admission review", http.StatusBadRequest)
+			return
+		}
+		response := &admissionv1.AdmissionResponse{UID: review.Request.UID, Allowed: true}
+		tenantMigration := new(corev1alpha.TenantMigration)
+		var oldTenantMigration *corev1alpha.TenantMigration
+		err := json.Unmarshal(review.Request.Object.Raw, tenantMigration)
+		if err == nil && review.Request.Operation == admissionv1.Update {
+			oldTenantMigration = new(corev1alpha.TenantMigration)
+			err = json.Unmarshal(review.Request.OldObject.Raw, oldTenantMigration)
+		}
+		if err != nil {
+			response.Allowed = false
+			response.Result = &metav1.Status{Status: metav1.StatusFailure, Code: http.StatusBadRequest, Message: err.Error()}
+		} else if err := w.Admit(tenantMigration, oldTenantMigration); err != nil {
+			response.Allowed = false
+			response.Result = &metav1.Status{Status: metav1.StatusFailure, Code: http.StatusForbidden, Reason: metav1.StatusReasonForbidden, Message: err.Error()}
+		}
+		review.Request = nil
+		review.Response = response
+		rw.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(rw).Encode(review); err != nil {
+			klog.ErrorS(err, "Couldn't write the admission review")
+		}
+	})
+}
+
+// Admit returns why the tenant migration is rejected, or nil if it is admitted. The old tenant
+// migration is nil on creation.
+func (w *Webhook) Admit(tenantMigration, oldTenantMigration *corev1alpha.TenantMigration) error {
+	if oldTenantMigration != nil {
+		if !reflect.DeepEqual(tenantMigration.Spec, oldTenantMigration.Spec) {
+			return fmt.Errorf("spec of tenant migration %s cannot change", tenantMigration.GetName())
+		}
+		return nil
+	}
+
+	source, target := tenantMigration.Spec.Source, tenantMigration.Spec.Target
+	if source == target {
+		return fmt.Errorf("tenant %s cannot migrate to itself", source)
+	}
+	if _, err := w.tenantsLister.Get(source); err != nil {
+		if errors.IsNotFound(err) {
+			return fmt.Errorf("source tenant %s does not exist", source)
+		}
+		return err
+	}
+	targetTenant, err := w.tenantsLister.Get(target)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return fmt.Errorf("target tenant %s does not exist", target)
+		}
+		return err
+	}
+	if !targetTenant.Spec.Enabled {
+		return fmt.Errorf("target tenant %s is disabled", target)
+	}
+
+	tenantMigrations, err := w.tenantMigrationsLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	for _, other := range tenantMigrations {
+		if other.GetName() == tenantMigration.GetName() || finished(other) {
+			continue
+		}
+		for _, tenant := range []string{other.Spec.Source, other.Spec.Target} {
+			if tenant == source || tenant == target {
+				klog.V(4).InfoS("Rejected the overlapping tenant migration", "tenantMigration", klog.KObj(tenantMigration), "other", klog.KObj(other))
+				return fmt.Errorf("tenant %s takes part in tenant migration %s, which is not over", tenant, other.GetName())
+			}
+		}
+	}
+	return nil
+}
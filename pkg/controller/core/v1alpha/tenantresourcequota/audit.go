@@ -0,0 +1,55 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantresourcequota
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/audit"
+)
+
+// auditQuota records the change of the claims and drops of the tenant in its audit trail
+func (c *Controller) auditQuota(ctx context.Context, oldTenantResourceQuota, newTenantResourceQuota *corev1alpha.TenantResourceQuota) {
+	changes := append(tuningChanges("claim", oldTenantResourceQuota.Spec.Claim, newTenantResourceQuota.Spec.Claim),
+		tuningChanges("drop", oldTenantResourceQuota.Spec.Drop, newTenantResourceQuota.Spec.Drop)...)
+	object := corev1alpha.TenantAuditObject{Kind: "TenantResourceQuota", Name: newTenantResourceQuota.GetName()}
+	audit.Record(ctx, c.edgenetclientset, newTenantResourceQuota.GetName(), audit.QuotaChanged, "", object, strings.Join(changes, ", "))
+}
+
+// tuningChanges lists the items added, removed, or modified between two sets of claims or drops
+func tuningChanges(kind string, oldTunings, newTunings map[string]corev1alpha.ResourceTuning) []string {
+	changes := []string{}
+	for name, tuning := range newTunings {
+		if oldTuning, exists := oldTunings[name]; !exists {
+			changes = append(changes, fmt.Sprintf("%s %s added", kind, name))
+		} else if !reflect.DeepEqual(oldTuning, tuning) {
+			changes = append(changes, fmt.Sprintf("%s %s modified", kind, name))
+		}
+	}
+	for name := range oldTunings {
+		if _, exists := newTunings[name]; !exists {
+			changes = append(changes, fmt.Sprintf("%s %s removed", kind, name))
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
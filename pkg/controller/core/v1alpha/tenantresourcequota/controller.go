@@ -25,12 +25,14 @@ import (
 
 	"github.com/EdgeNet-project/edgenet/pkg/access"
 	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/config"
 	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
 	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
 	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
 	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
 	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
 	"github.com/EdgeNet-project/edgenet/pkg/node"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -45,7 +47,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 const controllerAgentName = "tenantresourcequota-controller"
@@ -97,17 +99,20 @@ type Controller struct {
 	// recorder is an event recorder for recording Event resources to the
 	// Kubernetes API.
 	recorder record.EventRecorder
+	// identity is the identity of the cluster that the alerts are sent from
+	identity *config.ClusterIdentity
 }
 
 // NewController returns a new controller
 func NewController(
+	ctx context.Context,
 	kubeclientset kubernetes.Interface,
 	edgenetclientset clientset.Interface,
 	nodeInformer coreinformers.NodeInformer,
 	tenantresourcequotaInformer informers.TenantResourceQuotaInformer) *Controller {
 
 	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
-	klog.V(4).Info("Creating event broadcaster")
+	klog.V(4).InfoS("Creating event broadcaster")
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartStructuredLogging(0)
 	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
@@ -122,9 +127,10 @@ func NewController(
 		tenantresourcequotasSynced: tenantresourcequotaInformer.Informer().HasSynced,
 		workqueue:                  workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "TenantResourceQuotas"),
 		recorder:                   recorder,
+		identity:                   config.NewClusterIdentity(kubeclientset),
 	}
 
-	klog.V(4).Infoln("Setting up event handlers")
+	klog.V(4).InfoS("Setting up event handlers")
 	// Set up an event handler for when Tenant Resource Quota resources change
 	var getClosestExpiryDate = func(stale bool, objects ...map[string]corev1alpha.ResourceTuning) (*metav1.Time, bool) {
 		var closestDate *metav1.Time
@@ -152,10 +158,10 @@ func NewController(
 		UpdateFunc: func(old, new interface{}) {
 			newTenantResourceQuota := new.(*corev1alpha.TenantResourceQuota)
 			oldTenantResourceQuota := old.(*corev1alpha.TenantResourceQuota)
-			if reflect.DeepEqual(oldTenantResourceQuota.Spec, newTenantResourceQuota.Spec) {
-				if expired := newTenantResourceQuota.DropExpiredItems(); !expired {
-					return
-				}
+			if !reflect.DeepEqual(oldTenantResourceQuota.Spec, newTenantResourceQuota.Spec) {
+				controller.auditQuota(ctx, oldTenantResourceQuota, newTenantResourceQuota)
+			} else if expired := newTenantResourceQuota.DropExpiredItems(); !expired {
+				return
 			}
 			if newExpiryDate, exists := getClosestExpiryDate(false, newTenantResourceQuota.Spec.Claim, newTenantResourceQuota.Spec.Drop); exists {
 				if previousExpiryDate, exists := getClosestExpiryDate(true, oldTenantResourceQuota.Spec.Claim, oldTenantResourceQuota.Spec.Drop); !exists ||
@@ -176,7 +182,7 @@ func NewController(
 	var setIncentives = func(kind, nodeName string, ownerReferences []metav1.OwnerReference, cpuCapacity, memoryCapacity int64) {
 		for _, owner := range ownerReferences {
 			if owner.Kind == "Tenant" {
-				tenantResourceQuota, err := edgenetclientset.CoreV1alpha().TenantResourceQuotas().Get(context.TODO(), owner.Name, metav1.GetOptions{})
+				tenantResourceQuota, err := edgenetclientset.CoreV1alpha().TenantResourceQuotas().Get(ctx, owner.Name, metav1.GetOptions{})
 				if err == nil {
 					tenantResourceQuotaCopy := tenantResourceQuota.DeepCopy()
 
@@ -189,7 +195,7 @@ func NewController(
 								tenantResourceQuotaCopy.Spec.Claim[nodeName].ResourceList["memory"] != memoryAward {
 								tenantResourceQuotaCopy.Spec.Claim[nodeName].ResourceList["cpu"] = cpuAward
 								tenantResourceQuotaCopy.Spec.Claim[nodeName].ResourceList["memory"] = memoryAward
-								edgenetclientset.CoreV1alpha().TenantResourceQuotas().Update(context.TODO(), tenantResourceQuotaCopy, metav1.UpdateOptions{})
+								edgenetclientset.CoreV1alpha().TenantResourceQuotas().Update(ctx, tenantResourceQuotaCopy, metav1.UpdateOptions{})
 							}
 						} else {
 							claim := corev1alpha.ResourceTuning{
@@ -199,12 +205,12 @@ func NewController(
 								},
 							}
 							tenantResourceQuotaCopy.Spec.Claim[nodeName] = claim
-							edgenetclientset.CoreV1alpha().TenantResourceQuotas().Update(context.TODO(), tenantResourceQuotaCopy, metav1.UpdateOptions{})
+							edgenetclientset.CoreV1alpha().TenantResourceQuotas().Update(ctx, tenantResourceQuotaCopy, metav1.UpdateOptions{})
 						}
 					} else if kind == "disincentive" {
 						if _, elementExists := tenantResourceQuota.Spec.Claim[nodeName]; elementExists {
 							delete(tenantResourceQuota.Spec.Claim, nodeName)
-							edgenetclientset.CoreV1alpha().TenantResourceQuotas().Update(context.TODO(), tenantResourceQuota, metav1.UpdateOptions{})
+							edgenetclientset.CoreV1alpha().TenantResourceQuotas().Update(ctx, tenantResourceQuota, metav1.UpdateOptions{})
 						}
 					}
 
@@ -261,24 +267,25 @@ func NewController(
 func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
 
-	klog.V(4).Infoln("Starting Tenant Resource Quota controller")
+	klog.V(4).InfoS("Starting Tenant Resource Quota controller")
 
-	klog.V(4).Infoln("Waiting for informer caches to sync")
+	klog.V(4).InfoS("Waiting for informer caches to sync")
 	if ok := cache.WaitForCacheSync(stopCh,
 		c.nodesSynced,
 		c.tenantresourcequotasSynced); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
-	klog.V(4).Infoln("Starting workers")
+	klog.V(4).InfoS("Starting workers")
 	for i := 0; i < threadiness; i++ {
-		go wait.Until(c.runWorker, time.Second, stopCh)
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
 	}
 
-	klog.V(4).Infoln("Started workers")
+	klog.V(4).InfoS("Started workers")
 	<-stopCh
-	klog.V(4).Infoln("Shutting down workers")
+	klog.V(4).InfoS("Shutting down workers")
 
 	return nil
 }
@@ -286,14 +293,14 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 // runWorker is a long-running function that will continually call the
 // processNextWorkItem function in order to read and process a message on the
 // workqueue.
-func (c *Controller) runWorker() {
-	for c.processNextWorkItem() {
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
 	}
 }
 
 // processNextWorkItem will read a single work item off the workqueue and
 // attempt to process it, by calling the syncHandler.
-func (c *Controller) processNextWorkItem() bool {
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
 	obj, shutdown := c.workqueue.Get()
 
 	if shutdown {
@@ -310,12 +317,12 @@ func (c *Controller) processNextWorkItem() bool {
 			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
 			return nil
 		}
-		if err := c.syncHandler(key); err != nil {
+		if err := c.syncHandler(ctx, key); err != nil {
 			c.workqueue.AddRateLimited(key)
-			return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
 		}
 		c.workqueue.Forget(obj)
-		klog.V(4).Infof("Successfully synced '%s'", key)
+		klog.V(4).InfoS("Successfully synced", "key", key)
 		return nil
 	}(obj)
 
@@ -330,7 +337,7 @@ func (c *Controller) processNextWorkItem() bool {
 // syncHandler compares the actual state with the desired, and attempts to
 // converge the two. It then updates the Status block of the Tenant Resource Quota
 // resource with the current status of the resource.
-func (c *Controller) syncHandler(key string) error {
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
 	_, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
 		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
@@ -347,7 +354,10 @@ func (c *Controller) syncHandler(key string) error {
 		return err
 	}
 
-	c.processTenantResourceQuota(tenantresourcequota.DeepCopy())
+	c.processTenantResourceQuota(ctx, tenantresourcequota.DeepCopy())
+	if tenantresourcequota.Spec.SoftLimit != nil {
+		c.enqueueTenantResourceQuotaAfter(tenantresourcequota, SoftLimitInterval)
+	}
 
 	c.recorder.Event(tenantresourcequota, corev1.EventTypeNormal, successSynced, messageResourceSynced)
 	return nil
@@ -379,25 +389,25 @@ func (c *Controller) enqueueTenantResourceQuotaAfter(obj interface{}, after time
 	c.workqueue.AddAfter(key, after)
 }
 
-func (c *Controller) processTenantResourceQuota(tenantResourceQuotaCopy *corev1alpha.TenantResourceQuota) {
+func (c *Controller) processTenantResourceQuota(ctx context.Context, tenantResourceQuotaCopy *corev1alpha.TenantResourceQuota) {
 	oldStatus := tenantResourceQuotaCopy.Status
 	statusUpdate := func() {
 		if !reflect.DeepEqual(oldStatus, tenantResourceQuotaCopy.Status) {
-			if _, err := c.edgenetclientset.CoreV1alpha().TenantResourceQuotas().UpdateStatus(context.TODO(), tenantResourceQuotaCopy, metav1.UpdateOptions{}); err != nil {
-				klog.V(4).Infoln(err)
+			if _, err := c.edgenetclientset.CoreV1alpha().TenantResourceQuotas().UpdateStatus(ctx, tenantResourceQuotaCopy, metav1.UpdateOptions{}); err != nil {
+				klog.ErrorS(err, "Couldn't update the status of the tenant resource quota", "tenantResourceQuota", klog.KObj(tenantResourceQuotaCopy))
 			}
 		}
 	}
 	defer statusUpdate()
 
-	if tenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenantResourceQuotaCopy.GetName(), metav1.GetOptions{}); err != nil {
+	if tenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(ctx, tenantResourceQuotaCopy.GetName(), metav1.GetOptions{}); err != nil {
 		if errors.IsNotFound(err) {
-			c.edgenetclientset.CoreV1alpha().TenantResourceQuotas().Delete(context.TODO(), tenant.GetName(), metav1.DeleteOptions{})
+			c.edgenetclientset.CoreV1alpha().TenantResourceQuotas().Delete(ctx, tenant.GetName(), metav1.DeleteOptions{})
 		}
 	} else {
 		expired := tenantResourceQuotaCopy.DropExpiredItems()
 		if expired {
-			if tenantResourceQuotaUpdated, err := c.edgenetclientset.CoreV1alpha().TenantResourceQuotas().Update(context.TODO(), tenantResourceQuotaCopy, metav1.UpdateOptions{}); err == nil {
+			if tenantResourceQuotaUpdated, err := c.edgenetclientset.CoreV1alpha().TenantResourceQuotas().Update(ctx, tenantResourceQuotaCopy, metav1.UpdateOptions{}); err == nil {
 				tenantResourceQuotaCopy = tenantResourceQuotaUpdated.DeepCopy()
 				c.recorder.Event(tenantResourceQuotaCopy, corev1.EventTypeNormal, successRemoved, messageRemoved)
 			} else {
@@ -414,8 +424,8 @@ func (c *Controller) processTenantResourceQuota(tenantResourceQuotaCopy *corev1a
 				resourceQuota.Spec = corev1.ResourceQuotaSpec{
 					Hard: tenantResourceQuotaCopy.Spec.Claim["initial"].ResourceList,
 				}
-				if _, err := c.kubeclientset.CoreV1().ResourceQuotas(tenant.GetName()).Create(context.TODO(), resourceQuota.DeepCopy(), metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
-					klog.V(4).Infof("Couldn't create resource quota in %s: %s", tenant.GetName(), err)
+				if _, err := c.kubeclientset.CoreV1().ResourceQuotas(tenant.GetName()).Create(ctx, resourceQuota.DeepCopy(), metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+					klog.ErrorS(err, "Couldn't create resource quota", "tenant", klog.KObj(tenant))
 				} else {
 					c.recorder.Event(tenantResourceQuotaCopy, corev1.EventTypeNormal, successApplied, messageApplied)
 					tenantResourceQuotaCopy.Status.State = success
@@ -423,16 +433,17 @@ func (c *Controller) processTenantResourceQuota(tenantResourceQuotaCopy *corev1a
 				}
 			}
 
-			c.tuneResourceQuotaAcrossNamespaces(tenant.GetName(), tenantResourceQuotaCopy)
+			c.tuneResourceQuotaAcrossNamespaces(ctx, tenant.GetName(), tenantResourceQuotaCopy)
+			c.checkUsage(ctx, tenant, tenantResourceQuotaCopy)
 		}
 	}
 }
 
-func (c *Controller) tuneResourceQuotaAcrossNamespaces(coreNamespace string, tenantResourceQuotaCopy *corev1alpha.TenantResourceQuota) {
+func (c *Controller) tuneResourceQuotaAcrossNamespaces(ctx context.Context, coreNamespace string, tenantResourceQuotaCopy *corev1alpha.TenantResourceQuota) {
 	c.recorder.Event(tenantResourceQuotaCopy, corev1.EventTypeNormal, successTraversalStarted, messageTraversalStarted)
-	aggregateQuota, lastInSubNamespace := c.NamespaceTraversal(coreNamespace)
+	aggregateQuota, lastInSubNamespace := c.NamespaceTraversal(ctx, coreNamespace)
 	assignedQuota, _ := tenantResourceQuotaCopy.Fetch()
-	if coreResourceQuota, err := c.kubeclientset.CoreV1().ResourceQuotas(coreNamespace).Get(context.TODO(), "core-quota", metav1.GetOptions{}); err == nil {
+	if coreResourceQuota, err := c.kubeclientset.CoreV1().ResourceQuotas(coreNamespace).Get(ctx, "core-quota", metav1.GetOptions{}); err == nil {
 		coreResourceQuotaCopy := coreResourceQuota.DeepCopy()
 		canEntirelyCompansate := true
 		for key, value := range coreResourceQuotaCopy.Spec.Hard {
@@ -453,13 +464,13 @@ func (c *Controller) tuneResourceQuotaAcrossNamespaces(coreNamespace string, ten
 		}
 		if !reflect.DeepEqual(coreResourceQuota, coreResourceQuotaCopy) {
 			c.recorder.Event(tenantResourceQuotaCopy, corev1.EventTypeNormal, successTuned, messageTuned)
-			c.kubeclientset.CoreV1().ResourceQuotas(coreNamespace).Update(context.TODO(), coreResourceQuotaCopy, metav1.UpdateOptions{})
+			c.kubeclientset.CoreV1().ResourceQuotas(coreNamespace).Update(ctx, coreResourceQuotaCopy, metav1.UpdateOptions{})
 		}
 		if !canEntirelyCompansate && lastInSubNamespace != nil {
 			c.recorder.Event(tenantResourceQuotaCopy, corev1.EventTypeNormal, successDeleted, messageDeleted)
-			c.edgenetclientset.CoreV1alpha().SubNamespaces(lastInSubNamespace.GetNamespace()).Delete(context.TODO(), lastInSubNamespace.GetName(), metav1.DeleteOptions{})
+			c.edgenetclientset.CoreV1alpha().SubNamespaces(lastInSubNamespace.GetNamespace()).Delete(ctx, lastInSubNamespace.GetName(), metav1.DeleteOptions{})
 			time.Sleep(200 * time.Millisecond)
-			defer c.tuneResourceQuotaAcrossNamespaces(coreNamespace, tenantResourceQuotaCopy)
+			defer c.tuneResourceQuotaAcrossNamespaces(ctx, coreNamespace, tenantResourceQuotaCopy)
 		}
 	} else {
 		c.recorder.Event(tenantResourceQuotaCopy, corev1.EventTypeWarning, warningNotFound, messageNotFound)
@@ -468,20 +479,20 @@ func (c *Controller) tuneResourceQuotaAcrossNamespaces(coreNamespace string, ten
 	}
 }
 
-func (c *Controller) NamespaceTraversal(coreNamespace string) (map[corev1.ResourceName]*int64, *corev1alpha.SubNamespace) {
+func (c *Controller) NamespaceTraversal(ctx context.Context, coreNamespace string) (map[corev1.ResourceName]*int64, *corev1alpha.SubNamespace) {
 	aggregateQuota := make(map[corev1.ResourceName]*int64)
 	var lastInDate metav1.Time
 	var lastInSubNamespace *corev1alpha.SubNamespace
-	c.traverse(coreNamespace, coreNamespace, aggregateQuota, lastInSubNamespace, &lastInDate)
+	c.traverse(ctx, coreNamespace, coreNamespace, aggregateQuota, lastInSubNamespace, &lastInDate)
 	return aggregateQuota, lastInSubNamespace
 }
 
-func (c *Controller) traverse(coreNamespace, namespace string, aggregateQuota map[corev1.ResourceName]*int64, lastInSubNamespace *corev1alpha.SubNamespace, lastInDate *metav1.Time) {
+func (c *Controller) traverse(ctx context.Context, coreNamespace, namespace string, aggregateQuota map[corev1.ResourceName]*int64, lastInSubNamespace *corev1alpha.SubNamespace, lastInDate *metav1.Time) {
 	// This task becomes expensive when the hierarchy chain is gigantic with a substantial depth.
 	// So Goroutines come into play.
 	var wg sync.WaitGroup
-	c.accumulateQuota(namespace, aggregateQuota)
-	subNamespaceRaw, _ := c.edgenetclientset.CoreV1alpha().SubNamespaces(namespace).List(context.TODO(), metav1.ListOptions{})
+	c.accumulateQuota(ctx, namespace, aggregateQuota)
+	subNamespaceRaw, _ := c.edgenetclientset.CoreV1alpha().SubNamespaces(namespace).List(ctx, metav1.ListOptions{})
 	if len(subNamespaceRaw.Items) != 0 {
 		for _, subNamespaceRow := range subNamespaceRaw.Items {
 			wg.Add(1)
@@ -492,7 +503,7 @@ func (c *Controller) traverse(coreNamespace, namespace string, aggregateQuota ma
 			subNamespaceStr := fmt.Sprintf("%s-%s", coreNamespace, subNamespaceRow.GetName())
 			go func() {
 				defer wg.Done()
-				c.traverse(coreNamespace, subNamespaceStr, aggregateQuota, lastInSubNamespace, lastInDate)
+				c.traverse(ctx, coreNamespace, subNamespaceStr, aggregateQuota, lastInSubNamespace, lastInDate)
 			}()
 		}
 		wg.Wait()
@@ -500,8 +511,8 @@ func (c *Controller) traverse(coreNamespace, namespace string, aggregateQuota ma
 }
 
 // accumulateQuota adds each resource quota to the total to its aggregation.
-func (c *Controller) accumulateQuota(namespace string, aggregateQuota map[corev1.ResourceName]*int64) {
-	resourceQuotasRaw, _ := c.kubeclientset.CoreV1().ResourceQuotas(namespace).List(context.TODO(), metav1.ListOptions{})
+func (c *Controller) accumulateQuota(ctx context.Context, namespace string, aggregateQuota map[corev1.ResourceName]*int64) {
+	resourceQuotasRaw, _ := c.kubeclientset.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
 	if len(resourceQuotasRaw.Items) != 0 {
 		for _, resourceQuotasRow := range resourceQuotasRaw.Items {
 			for key, value := range resourceQuotasRow.Spec.Hard {
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/config"
 	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
 	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
 	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
@@ -19,13 +20,15 @@ import (
 	"github.com/sirupsen/logrus"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	testclient "k8s.io/client-go/kubernetes/fake"
-	"k8s.io/klog"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
 )
 
 // The main structure of test group
@@ -55,7 +58,8 @@ func TestMain(m *testing.M) {
 	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeclientset, time.Second*30)
 	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, time.Second*30)
 
-	controller := NewController(kubeclientset,
+	controller := NewController(signals.ContextFor(stopCh),
+		kubeclientset,
 		edgenetclientset,
 		kubeInformerFactory.Core().V1().Nodes(),
 		edgenetInformerFactory.Core().V1alpha().TenantResourceQuotas())
@@ -484,3 +488,41 @@ func getQuotas(claimRaw map[string]corev1alpha.ResourceTuning) (int64, int64) {
 	}
 	return cpuQuota, memoryQuota
 }
+
+func TestSoftLimit(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	tenant := g.tenantObj.DeepCopy()
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: tenant.GetName(), Labels: map[string]string{"edge-net.io/tenant": tenant.GetName()}}}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: tenant.GetName()},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "nginx", Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10"), corev1.ResourceMemory: resource.MustParse("1Gi")}}}}}}
+	kubeclientset := testclient.NewSimpleClientset(namespace, pod)
+	recorder := record.NewFakeRecorder(10)
+	c := &Controller{kubeclientset: kubeclientset, edgenetclientset: edgenettestclient.NewSimpleClientset(), recorder: recorder, identity: config.NewClusterIdentity(kubeclientset)}
+
+	softLimit := 80
+	tenantResourceQuota := g.tenantResourceQuotaObj.DeepCopy()
+	tenantResourceQuota.Spec.Claim = map[string]corev1alpha.ResourceTuning{"initial": g.claimObj}
+	c.checkUsage(context.TODO(), tenant, tenantResourceQuota)
+	used := tenantResourceQuota.Status.Used[corev1.ResourceCPU]
+	util.Equals(t, "10", used.String())
+	util.Equals(t, 0, len(tenantResourceQuota.Status.Conditions))
+
+	tenantResourceQuota.Spec.SoftLimit = &softLimit
+	c.checkUsage(context.TODO(), tenant, tenantResourceQuota)
+	util.Equals(t, true, meta.IsStatusConditionTrue(tenantResourceQuota.Status.Conditions, conditionSoftLimitExceeded))
+	util.Equals(t, "Usage is beyond 80% of the quota: cpu 83%", meta.FindStatusCondition(tenantResourceQuota.Status.Conditions, conditionSoftLimitExceeded).Message)
+	util.Equals(t, "Warning Soft Limit Exceeded Usage is beyond 80% of the quota: cpu 83%", <-recorder.Events)
+	// The tenant is alerted once as long as it stays beyond the soft limit
+	c.checkUsage(context.TODO(), tenant, tenantResourceQuota)
+	util.Equals(t, 0, len(recorder.Events))
+
+	tenantResourceQuota.Spec.Claim["extra"] = g.claimObj
+	c.checkUsage(context.TODO(), tenant, tenantResourceQuota)
+	util.Equals(t, true, meta.IsStatusConditionFalse(tenantResourceQuota.Status.Conditions, conditionSoftLimitExceeded))
+
+	tenantResourceQuota.Spec.SoftLimit = nil
+	c.checkUsage(context.TODO(), tenant, tenantResourceQuota)
+	util.Equals(t, 0, len(tenantResourceQuota.Status.Conditions))
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantresourcequota
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/mailer"
+	"github.com/EdgeNet-project/edgenet/pkg/quota"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// SoftLimitInterval is how often the usage of the tenants with a soft limit is checked, the pods
+// of the tenants come and go without the tenant resource quota changing
+var SoftLimitInterval = time.Minute
+
+// Condition type, reasons, and event definitions of the soft limit
+const (
+	conditionSoftLimitExceeded = "SoftLimitExceeded"
+	reasonExceeded             = "Exceeded"
+	reasonWithinLimit          = "WithinLimit"
+	warningSoftLimitExceeded   = "Soft Limit Exceeded"
+	messageSoftLimitExceeded   = "Usage is beyond %d%% of the quota: %s"
+	messageWithinSoftLimit     = "Usage is within %d%% of the quota"
+)
+
+// checkUsage reports the usage of the tenant against its quota, and alerts the tenant contact
+// when the usage of a resource crosses the soft limit
+func (c *Controller) checkUsage(ctx context.Context, tenant *corev1alpha.Tenant, tenantResourceQuotaCopy *corev1alpha.TenantResourceQuota) {
+	pods, err := c.tenantPods(ctx, tenant.GetName())
+	if err != nil {
+		klog.ErrorS(err, "Couldn't list the pods of the tenant", "tenant", klog.KObj(tenant))
+		return
+	}
+	usage := quota.Usage(pods)
+	_, assignedQuota := tenantResourceQuotaCopy.Fetch()
+	used := corev1.ResourceList{}
+	for name := range assignedQuota {
+		used[name] = usage[name]
+	}
+	tenantResourceQuotaCopy.Status.Used = used
+
+	if tenantResourceQuotaCopy.Spec.SoftLimit == nil {
+		meta.RemoveStatusCondition(&tenantResourceQuotaCopy.Status.Conditions, conditionSoftLimitExceeded)
+		return
+	}
+	softLimit := *tenantResourceQuotaCopy.Spec.SoftLimit
+	condition := metav1.Condition{Type: conditionSoftLimitExceeded, ObservedGeneration: tenantResourceQuotaCopy.GetGeneration()}
+	if exceeding := quota.Exceeding(usage, assignedQuota, softLimit); len(exceeding) > 0 {
+		resources := []string{}
+		for _, name := range exceeding {
+			resources = append(resources, fmt.Sprintf("%s %d%%", name, quota.Percent(usage[name], assignedQuota[name])))
+		}
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = reasonExceeded
+		condition.Message = fmt.Sprintf(messageSoftLimitExceeded, softLimit, strings.Join(resources, ", "))
+		if !meta.IsStatusConditionTrue(tenantResourceQuotaCopy.Status.Conditions, conditionSoftLimitExceeded) {
+			c.recorder.Event(tenantResourceQuotaCopy, corev1.EventTypeWarning, warningSoftLimitExceeded, condition.Message)
+			c.sendSoftLimitEmail(ctx, tenant, softLimit, strings.Join(resources, ", "))
+		}
+	} else {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonWithinLimit
+		condition.Message = fmt.Sprintf(messageWithinSoftLimit, softLimit)
+	}
+	meta.SetStatusCondition(&tenantResourceQuotaCopy.Status.Conditions, condition)
+}
+
+// tenantPods returns the pods in the namespaces of the tenant
+func (c *Controller) tenantPods(ctx context.Context, tenant string) ([]*corev1.Pod, error) {
+	namespaces, err := c.kubeclientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("edge-net.io/tenant=%s", tenant)})
+	if err != nil {
+		return nil, err
+	}
+	pods := []*corev1.Pod{}
+	for _, namespace := range namespaces.Items {
+		podRaw, err := c.kubeclientset.CoreV1().Pods(namespace.GetName()).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for i := range podRaw.Items {
+			pods = append(pods, &podRaw.Items[i])
+		}
+	}
+	return pods, nil
+}
+
+func (c *Controller) sendSoftLimitEmail(ctx context.Context, tenant *corev1alpha.Tenant, softLimit int, resources string) {
+	clusterUID, err := c.identity.UID(ctx)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't get the cluster UID")
+		return
+	}
+	email := new(mailer.Content)
+	email.Cluster = clusterUID
+	email.User = tenant.Spec.Contact.Email
+	email.FirstName = tenant.Spec.Contact.FirstName
+	email.LastName = tenant.Spec.Contact.LastName
+	email.Subject = "[EdgeNet] Tenant quota soft limit exceeded"
+	email.Recipient = []string{tenant.Spec.Contact.Email}
+	email.QuotaSoftLimit = &mailer.QuotaSoftLimit{Tenant: tenant.GetName(), SoftLimit: softLimit, Resources: resources}
+	email.Send("tenant-quota-soft-limit")
+}
@@ -0,0 +1,565 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantsecretstore
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+	"github.com/EdgeNet-project/edgenet/pkg/vault"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const controllerAgentName = "tenantsecretstore-controller"
+
+// Definitions of the state of the tenant secret store resource
+const (
+	successSynced          = "Synced"
+	messageResourceSynced  = "Tenant secret store synced successfully"
+	successDisabled        = "Disabled"
+	messageDisabled        = "Secrets removed as the tenant is disabled"
+	messageReady           = "%d secrets synced from Vault"
+	failureTenant          = "Not Found"
+	messageTenantNotFound  = "The namespace is not the core namespace of a tenant"
+	failureVault           = "Vault Failed"
+	messageNotConfigured   = "Vault is not configured in the cluster"
+	messageRoleFailed      = "Vault role configuration failed"
+	messageLoginFailed     = "Vault login failed"
+	failureSecret          = "Sync Failed"
+	messageSecretNotFound  = "Secret %s not found in Vault"
+	messageSecretFailed    = "Secret %s could not be read from Vault"
+	messageKeyNotFound     = "Key %s not found in the Vault secret of %s"
+	messageNotOwned        = "Secret %s exists in %s and does not belong to the store"
+	messageApplyFailed     = "Secret %s could not be synced in %s"
+	messageNamespaceDenied = "Namespace %s is not a subsidiary namespace of the tenant"
+	ready                  = "Ready"
+	disabled               = "Disabled"
+	failure                = "Failure"
+)
+
+// storeLabel marks the secrets synced by a store with its name
+const storeLabel = "edge-net.io/tenant-secret-store"
+
+// Annotations of the synced secrets, telling where they come from in Vault
+const (
+	pathAnnotation    = "edge-net.io/vault-path"
+	versionAnnotation = "edge-net.io/vault-version"
+)
+
+// ServiceAccount is the service account in the core namespace of each tenant that the role of the
+// tenant in Vault is bound to
+var ServiceAccount = "edgenet-vault"
+
+// RefreshInterval is the time after which the secrets are read again from Vault when the store
+// does not set it
+var RefreshInterval = 15 * time.Minute
+
+// Controller is the controller implementation for TenantSecretStore resources
+type Controller struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+	// edgenetclientset is a clientset for the EdgeNet API groups
+	edgenetclientset clientset.Interface
+
+	tenantSecretStoresLister listers.TenantSecretStoreLister
+	tenantSecretStoresSynced cache.InformerSynced
+	tenantsSynced            cache.InformerSynced
+
+	// workqueue is a rate limited work queue. This is used to queue work to be
+	// processed instead of performing it as soon as a change happens. This
+	// means we can ensure we only process a fixed amount of resources at a
+	// time, and makes it easy to ensure we are never processing the same item
+	// simultaneously in two different workers.
+	workqueue workqueue.RateLimitingInterface
+	// recorder is an event recorder for recording Event resources to the
+	// Kubernetes API.
+	recorder record.EventRecorder
+}
+
+// NewController returns a new controller
+func NewController(
+	ctx context.Context,
+	kubeclientset kubernetes.Interface,
+	edgenetclientset clientset.Interface,
+	tenantSecretStoreInformer informers.TenantSecretStoreInformer,
+	tenantInformer informers.TenantInformer) *Controller {
+
+	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
+	klog.V(4).InfoS("Creating event broadcaster")
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartStructuredLogging(0)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+
+	controller := &Controller{
+		kubeclientset:            kubeclientset,
+		edgenetclientset:         edgenetclientset,
+		tenantSecretStoresLister: tenantSecretStoreInformer.Lister(),
+		tenantSecretStoresSynced: tenantSecretStoreInformer.Informer().HasSynced,
+		tenantsSynced:            tenantInformer.Informer().HasSynced,
+		workqueue:                workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "TenantSecretStores"),
+		recorder:                 recorder,
+	}
+
+	klog.V(4).InfoS("Setting up event handlers")
+	// Set up an event handler for when TenantSecretStore resources change
+	tenantSecretStoreInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueTenantSecretStore,
+		UpdateFunc: func(old, new interface{}) {
+			newObj := new.(*corev1alpha.TenantSecretStore)
+			oldObj := old.(*corev1alpha.TenantSecretStore)
+			if !reflect.DeepEqual(newObj.Spec, oldObj.Spec) {
+				controller.enqueueTenantSecretStore(new)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			// The secrets in the core namespace are garbage collected, the ones in the
+			// subsidiary namespaces cannot have an owner in another namespace
+			tenantSecretStore, ok := obj.(*corev1alpha.TenantSecretStore)
+			if !ok {
+				return
+			}
+			controller.removeSecrets(ctx, tenantSecretStore, tenantSecretStore.Status.Secrets)
+		},
+	})
+	// The secrets of a tenant follow the tenant as it gets disabled or enabled
+	tenantInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			newObj := new.(*corev1alpha.Tenant)
+			oldObj := old.(*corev1alpha.Tenant)
+			if newObj.Spec.Enabled != oldObj.Spec.Enabled {
+				controller.handleTenant(newObj)
+			}
+		},
+	})
+
+	return controller
+}
+
+// Run will set up the event handlers for the types of tenant secret store, as well
+// as syncing informer caches and starting workers. It will block until stopCh
+// is closed, at which point it will shutdown the workqueue and wait for
+// workers to finish processing their current work items.
+func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting TenantSecretStore controller")
+
+	klog.V(4).InfoS("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh,
+		c.tenantSecretStoresSynced,
+		c.tenantsSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	klog.V(4).InfoS("Starting workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	klog.V(4).InfoS("Started workers")
+	<-stopCh
+	klog.V(4).InfoS("Shutting down workers")
+
+	return nil
+}
+
+// runWorker is a long-running function that will continually call the
+// processNextWorkItem function in order to read and process a message on the
+// workqueue.
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+// processNextWorkItem will read a single work item off the workqueue and
+// attempt to process it, by calling the syncHandler.
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.workqueue.Done(obj)
+		var key string
+		var ok bool
+
+		if key, ok = obj.(string); !ok {
+			c.workqueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			return nil
+		}
+		if err := c.syncHandler(ctx, key); err != nil {
+			c.workqueue.AddRateLimited(key)
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
+		}
+		c.workqueue.Forget(obj)
+		klog.V(4).InfoS("Successfully synced", "key", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+
+	return true
+}
+
+// syncHandler compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the TenantSecretStore
+// resource with the current status of the resource.
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	tenantSecretStore, err := c.tenantSecretStoresLister.TenantSecretStores(namespace).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("tenant secret store '%s' in work queue no longer exists", key))
+			return nil
+		}
+
+		return err
+	}
+
+	tenantSecretStoreCopy := tenantSecretStore.DeepCopy()
+	next := c.processTenantSecretStore(ctx, tenantSecretStoreCopy)
+	if !reflect.DeepEqual(tenantSecretStore.Status, tenantSecretStoreCopy.Status) {
+		if _, err := c.edgenetclientset.CoreV1alpha().TenantSecretStores(namespace).UpdateStatus(ctx, tenantSecretStoreCopy, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	c.recorder.Event(tenantSecretStore, corev1.EventTypeNormal, successSynced, messageResourceSynced)
+	if next > 0 {
+		// The secrets may have changed in Vault in the meantime
+		c.workqueue.AddAfter(key, next)
+	}
+	return nil
+}
+
+// enqueueTenantSecretStore takes a TenantSecretStore resource and converts it into a namespace/name
+// string which is then put onto the work queue. This method should *not* be
+// passed resources of any type other than TenantSecretStore.
+func (c *Controller) enqueueTenantSecretStore(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+// handleTenant enqueues the stores in the core namespace of a tenant
+func (c *Controller) handleTenant(tenant *corev1alpha.Tenant) {
+	tenantSecretStores, err := c.tenantSecretStoresLister.TenantSecretStores(tenant.GetName()).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, tenantSecretStore := range tenantSecretStores {
+		c.enqueueTenantSecretStore(tenantSecretStore)
+	}
+}
+
+// processTenantSecretStore syncs the secrets of the store from Vault into the namespaces, and returns
+// the time after which they are to be read again, zero if the store cannot be synced until it changes
+func (c *Controller) processTenantSecretStore(ctx context.Context, tenantSecretStoreCopy *corev1alpha.TenantSecretStore) time.Duration {
+	namespace := tenantSecretStoreCopy.GetNamespace()
+	// The core namespace has the same name as the tenant
+	tenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Couldn't get the tenant", "tenant", namespace)
+		c.fail(tenantSecretStoreCopy, failureTenant, messageTenantNotFound)
+		return 0
+	}
+	if !tenant.Spec.Enabled {
+		c.cleanup(ctx, tenantSecretStoreCopy)
+		return 0
+	}
+	if !vault.Enabled() {
+		c.fail(tenantSecretStoreCopy, failureVault, messageNotConfigured)
+		return 0
+	}
+
+	interval := RefreshInterval
+	if tenantSecretStoreCopy.Spec.RefreshInterval > 0 {
+		interval = time.Duration(tenantSecretStoreCopy.Spec.RefreshInterval) * time.Minute
+	}
+	if err := c.applyServiceAccount(ctx, namespace); err != nil {
+		klog.ErrorS(err, "Couldn't apply the service account", "serviceAccount", klog.KRef(namespace, ServiceAccount))
+		c.fail(tenantSecretStoreCopy, failureVault, messageRoleFailed)
+		return interval
+	}
+	if err := vault.ApplyRole(ctx, namespace, namespace, ServiceAccount); err != nil {
+		klog.ErrorS(err, "Couldn't configure the role in Vault", "tenant", namespace)
+		c.fail(tenantSecretStoreCopy, failureVault, messageRoleFailed)
+		return interval
+	}
+	tenantSecretStoreCopy.Status.Role = vault.RoleName(namespace)
+	token, err := c.login(ctx, namespace)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't log in to Vault", "tenant", namespace)
+		c.fail(tenantSecretStoreCopy, failureVault, messageLoginFailed)
+		return interval
+	}
+
+	messages := []string{}
+	synced := []corev1alpha.TenantSecretSyncStatus{}
+	for _, secretSync := range tenantSecretStoreCopy.Spec.Secrets {
+		status, message := c.syncSecret(ctx, tenantSecretStoreCopy, secretSync, token)
+		if message != "" {
+			messages = append(messages, message)
+		}
+		if status != nil {
+			synced = append(synced, *status)
+		}
+	}
+	c.removeSecrets(ctx, tenantSecretStoreCopy, stale(tenantSecretStoreCopy.Status.Secrets, synced))
+	tenantSecretStoreCopy.Status.Secrets = synced
+	now := metav1.Now()
+	tenantSecretStoreCopy.Status.LastSync = &now
+	if len(messages) > 0 {
+		c.fail(tenantSecretStoreCopy, failureSecret, strings.Join(messages, ", "))
+	} else {
+		tenantSecretStoreCopy.Status.State = ready
+		tenantSecretStoreCopy.Status.Message = fmt.Sprintf(messageReady, len(synced))
+	}
+	return interval
+}
+
+// applyServiceAccount creates the service account the role of the tenant is bound to
+func (c *Controller) applyServiceAccount(ctx context.Context, namespace string) error {
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: ServiceAccount, Namespace: namespace,
+		Labels: map[string]string{"edge-net.io/generated": "true", "edge-net.io/tenant": namespace}}}
+	// The tokens are requested by the controller alone for the audience of Vault
+	automount := false
+	serviceAccount.AutomountServiceAccountToken = &automount
+	if _, err := c.kubeclientset.CoreV1().ServiceAccounts(namespace).Create(ctx, serviceAccount, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// login requests a short-lived token of the service account of the tenant and exchanges it for a
+// Vault token of the role of the tenant
+func (c *Controller) login(ctx context.Context, namespace string) (string, error) {
+	expiration := int64(vault.TokenTTL.Seconds())
+	tokenRequest := &authenticationv1.TokenRequest{Spec: authenticationv1.TokenRequestSpec{
+		Audiences:         []string{vault.Audience},
+		ExpirationSeconds: &expiration,
+	}}
+	tokenRequest, err := c.kubeclientset.CoreV1().ServiceAccounts(namespace).CreateToken(ctx, ServiceAccount, tokenRequest, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+	return vault.Login(ctx, namespace, tokenRequest.Status.Token)
+}
+
+// syncSecret reads a secret of the tenant from Vault and applies it in the core namespace and in
+// the subsidiary namespaces listed. It returns where the secret is synced, nil if it is not to be
+// synced anywhere, and a message if it failed somewhere.
+func (c *Controller) syncSecret(ctx context.Context, tenantSecretStoreCopy *corev1alpha.TenantSecretStore, secretSync corev1alpha.TenantSecretSync, token string) (*corev1alpha.TenantSecretSyncStatus, string) {
+	tenant := tenantSecretStoreCopy.GetNamespace()
+	secret, err := vault.Read(ctx, token, tenant, secretSync.Path)
+	if err == vault.ErrNotFound {
+		return nil, fmt.Sprintf(messageSecretNotFound, secretSync.Path)
+	} else if err != nil {
+		klog.ErrorS(err, "Couldn't read the secret from Vault", "path", secretSync.Path, "tenantSecretStore", klog.KObj(tenantSecretStoreCopy))
+		// The secret synced before stays until Vault is reachable again
+		for _, status := range tenantSecretStoreCopy.Status.Secrets {
+			if status.Name == secretSync.Name {
+				return status.DeepCopy(), fmt.Sprintf(messageSecretFailed, secretSync.Path)
+			}
+		}
+		return nil, fmt.Sprintf(messageSecretFailed, secretSync.Path)
+	}
+	data := map[string][]byte{}
+	if len(secretSync.Keys) == 0 {
+		for key, value := range secret.Data {
+			data[key] = []byte(value)
+		}
+	}
+	for _, key := range secretSync.Keys {
+		value, ok := secret.Data[key]
+		if !ok {
+			return nil, fmt.Sprintf(messageKeyNotFound, key, secretSync.Path)
+		}
+		data[key] = []byte(value)
+	}
+
+	desired := []string{tenant}
+	messages := []string{}
+	for _, namespace := range secretSync.Namespaces {
+		if namespace == tenant {
+			continue
+		}
+		// The secrets of a tenant are never synced out of its namespaces
+		if subnamespace, err := c.kubeclientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{}); err != nil ||
+			subnamespace.GetLabels()["edge-net.io/tenant"] != tenant || subnamespace.GetLabels()["edge-net.io/kind"] != "sub" {
+			messages = append(messages, fmt.Sprintf(messageNamespaceDenied, namespace))
+			continue
+		}
+		desired = append(desired, namespace)
+	}
+	status := &corev1alpha.TenantSecretSyncStatus{Name: secretSync.Name, Version: secret.Version}
+	for _, namespace := range desired {
+		if err := c.applySecret(ctx, tenantSecretStoreCopy, secretSync, namespace, data, secret.Version); err == errNotOwned {
+			messages = append(messages, fmt.Sprintf(messageNotOwned, secretSync.Name, namespace))
+			continue
+		} else if err != nil {
+			klog.ErrorS(err, "Couldn't apply the secret", "secret", klog.KRef(namespace, secretSync.Name))
+			messages = append(messages, fmt.Sprintf(messageApplyFailed, secretSync.Name, namespace))
+			continue
+		}
+		status.Namespaces = append(status.Namespaces, namespace)
+	}
+	return status, strings.Join(messages, ", ")
+}
+
+// errNotOwned tells that a secret of the same name was not created by the store
+var errNotOwned = fmt.Errorf("secret not owned by the tenant secret store")
+
+// applySecret creates or updates the secret synced by the store in a namespace
+func (c *Controller) applySecret(ctx context.Context, tenantSecretStoreCopy *corev1alpha.TenantSecretStore, secretSync corev1alpha.TenantSecretSync, namespace string, data map[string][]byte, version int) error {
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secretSync.Name, Namespace: namespace, Labels: storeLabels(tenantSecretStoreCopy),
+		Annotations: map[string]string{pathAnnotation: fmt.Sprintf("%s/%s", vault.TenantPath(tenantSecretStoreCopy.GetNamespace()), secretSync.Path), versionAnnotation: strconv.Itoa(version)}},
+		Type: corev1.SecretTypeOpaque, Data: data}
+	if namespace == tenantSecretStoreCopy.GetNamespace() {
+		secret.SetOwnerReferences(ownerReferences(tenantSecretStoreCopy))
+	}
+	current, err := c.kubeclientset.CoreV1().Secrets(namespace).Get(ctx, secretSync.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = c.kubeclientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+	// The secrets pasted by the users are never overwritten
+	if current.GetLabels()[storeLabel] != tenantSecretStoreCopy.GetName() {
+		return errNotOwned
+	}
+	if reflect.DeepEqual(current.Data, secret.Data) && reflect.DeepEqual(current.GetAnnotations(), secret.GetAnnotations()) {
+		return nil
+	}
+	current.Data = secret.Data
+	current.SetAnnotations(secret.GetAnnotations())
+	_, err = c.kubeclientset.CoreV1().Secrets(namespace).Update(ctx, current, metav1.UpdateOptions{})
+	return err
+}
+
+// removeSecrets deletes the secrets synced by the store from the namespaces they are in
+func (c *Controller) removeSecrets(ctx context.Context, tenantSecretStore *corev1alpha.TenantSecretStore, secrets []corev1alpha.TenantSecretSyncStatus) {
+	for _, synced := range secrets {
+		for _, namespace := range synced.Namespaces {
+			secret, err := c.kubeclientset.CoreV1().Secrets(namespace).Get(ctx, synced.Name, metav1.GetOptions{})
+			if err != nil || secret.GetLabels()[storeLabel] != tenantSecretStore.GetName() {
+				continue
+			}
+			if err := c.kubeclientset.CoreV1().Secrets(namespace).Delete(ctx, synced.Name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				klog.ErrorS(err, "Couldn't delete the secret", "secret", klog.KRef(namespace, synced.Name))
+			}
+		}
+	}
+}
+
+// cleanup removes the secrets of a disabled tenant and its role in Vault, the resource stays so that
+// the secrets come back once the tenant is enabled
+func (c *Controller) cleanup(ctx context.Context, tenantSecretStoreCopy *corev1alpha.TenantSecretStore) {
+	c.removeSecrets(ctx, tenantSecretStoreCopy, tenantSecretStoreCopy.Status.Secrets)
+	if vault.Enabled() && tenantSecretStoreCopy.Status.Role != "" {
+		if err := vault.DeleteRole(ctx, tenantSecretStoreCopy.GetNamespace()); err != nil {
+			klog.ErrorS(err, "Couldn't delete the role in Vault", "tenant", tenantSecretStoreCopy.GetNamespace())
+		}
+	}
+	if tenantSecretStoreCopy.Status.State != disabled {
+		c.recorder.Event(tenantSecretStoreCopy, corev1.EventTypeNormal, successDisabled, messageDisabled)
+	}
+	tenantSecretStoreCopy.Status.State = disabled
+	tenantSecretStoreCopy.Status.Message = messageDisabled
+	tenantSecretStoreCopy.Status.Role = ""
+	tenantSecretStoreCopy.Status.Secrets = nil
+}
+
+func (c *Controller) fail(tenantSecretStoreCopy *corev1alpha.TenantSecretStore, reason, message string) {
+	c.recorder.Event(tenantSecretStoreCopy, corev1.EventTypeWarning, reason, message)
+	tenantSecretStoreCopy.Status.State = failure
+	tenantSecretStoreCopy.Status.Message = message
+}
+
+// stale returns the secrets synced before that are no longer synced in some namespaces
+func stale(previous, current []corev1alpha.TenantSecretSyncStatus) []corev1alpha.TenantSecretSyncStatus {
+	synced := map[string]bool{}
+	for _, status := range current {
+		for _, namespace := range status.Namespaces {
+			synced[namespace+"/"+status.Name] = true
+		}
+	}
+	left := []corev1alpha.TenantSecretSyncStatus{}
+	for _, status := range previous {
+		namespaces := []string{}
+		for _, namespace := range status.Namespaces {
+			if !synced[namespace+"/"+status.Name] {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+		if len(namespaces) > 0 {
+			left = append(left, corev1alpha.TenantSecretSyncStatus{Name: status.Name, Namespaces: namespaces})
+		}
+	}
+	return left
+}
+
+func ownerReferences(tenantSecretStore *corev1alpha.TenantSecretStore) []metav1.OwnerReference {
+	return []metav1.OwnerReference{*metav1.NewControllerRef(tenantSecretStore, corev1alpha.SchemeGroupVersion.WithKind("TenantSecretStore"))}
+}
+
+func storeLabels(tenantSecretStore *corev1alpha.TenantSecretStore) map[string]string {
+	return map[string]string{"edge-net.io/generated": "true", "edge-net.io/tenant": tenantSecretStore.GetNamespace(), storeLabel: tenantSecretStore.GetName()}
+}
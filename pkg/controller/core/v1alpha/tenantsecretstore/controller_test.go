@@ -0,0 +1,210 @@
+package tenantsecretstore
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/testutil"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+	"github.com/EdgeNet-project/edgenet/pkg/vault"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+// fakeVault serves the secrets of the tenants, a tenant logs in with a token of its vault service
+// account and reads the secrets in its own path only
+type fakeVault struct {
+	secrets map[string]string
+	roles   map[string]bool
+	down    bool
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if f.down {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/v1/")
+	switch {
+	case strings.HasPrefix(path, "sys/policies/acl/"):
+		w.WriteHeader(http.StatusNoContent)
+	case strings.HasPrefix(path, "auth/kubernetes/role/"):
+		f.roles[strings.TrimPrefix(path, "auth/kubernetes/role/")] = r.Method != http.MethodDelete
+		w.WriteHeader(http.StatusNoContent)
+	case path == "auth/kubernetes/login":
+		body := map[string]string{}
+		json.NewDecoder(r.Body).Decode(&body)
+		// The tokens of the fake API server are namespace:serviceaccount:bound object:audiences
+		parts := strings.Split(body["jwt"], ":")
+		if !f.roles[body["role"]] || body["role"] != "edgenet-"+parts[0] || parts[3] != vault.Audience {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"auth": map[string]string{"client_token": parts[0]}})
+	default:
+		prefix := "secret/data/edgenet/tenants/" + r.Header.Get("X-Vault-Token") + "/"
+		data, ok := f.secrets[strings.TrimPrefix(path, prefix)]
+		if !strings.HasPrefix(path, prefix) || !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"data":{"data":` + data + `,"metadata":{"version":2}}}`))
+	}
+}
+
+// newController returns a controller for the tenant lip6 that reads the secrets from a fake Vault
+// until the end of the test
+func newController(t *testing.T) (*Controller, *fakeVault) {
+	f := &fakeVault{secrets: map[string]string{
+		"registry": `{"username":"lip6","password":"s3cr3t"}`,
+		"database": `{"url":"postgres://db.lip6.fr"}`,
+	}, roles: map[string]bool{}}
+	server := httptest.NewServer(f)
+	t.Cleanup(func() {
+		server.Close()
+		vault.Address = ""
+	})
+	util.OK(t, vault.Configure(server.URL, "", ""))
+
+	kubeclientset, edgenetclientset := testutil.NewTenantClientsets(t, "lip6", "ci-1a2b3c")
+	testutil.IssueTokens(kubeclientset)
+	c := &Controller{
+		kubeclientset:    kubeclientset,
+		edgenetclientset: edgenetclientset,
+		recorder:         record.NewFakeRecorder(100),
+	}
+	return c, f
+}
+
+func newTenantSecretStore(secrets ...corev1alpha.TenantSecretSync) *corev1alpha.TenantSecretStore {
+	return &corev1alpha.TenantSecretStore{ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "lip6", UID: types.UID("store-uid")},
+		Spec: corev1alpha.TenantSecretStoreSpec{Secrets: secrets}}
+}
+
+func (c *Controller) secret(namespace, name string) (*corev1.Secret, error) {
+	return c.kubeclientset.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+}
+
+func TestSync(t *testing.T) {
+	c, _ := newController(t)
+	tenantSecretStore := newTenantSecretStore(corev1alpha.TenantSecretSync{Name: "registry-credentials", Path: "registry", Keys: []string{"password"}, Namespaces: []string{"ci-1a2b3c", "other"}},
+		corev1alpha.TenantSecretSync{Name: "database", Path: "database"})
+
+	next := c.processTenantSecretStore(context.TODO(), tenantSecretStore)
+	util.Equals(t, RefreshInterval, next)
+	util.Equals(t, "edgenet-lip6", tenantSecretStore.Status.Role)
+	util.Equals(t, []corev1alpha.TenantSecretSyncStatus{
+		{Name: "registry-credentials", Version: 2, Namespaces: []string{"lip6", "ci-1a2b3c"}},
+		{Name: "database", Version: 2, Namespaces: []string{"lip6"}}}, tenantSecretStore.Status.Secrets)
+	// The secrets are never synced out of the tenant
+	util.Equals(t, failure, tenantSecretStore.Status.State)
+	util.Equals(t, "Namespace other is not a subsidiary namespace of the tenant", tenantSecretStore.Status.Message)
+	_, err := c.secret("other", "registry-credentials")
+	util.Assert(t, errors.IsNotFound(err), "secret synced in another tenant")
+
+	secret, err := c.secret("ci-1a2b3c", "registry-credentials")
+	util.OK(t, err)
+	util.Equals(t, map[string][]byte{"password": []byte("s3cr3t")}, secret.Data)
+	util.Equals(t, "2", secret.GetAnnotations()[versionAnnotation])
+	util.Equals(t, "edgenet/tenants/lip6/registry", secret.GetAnnotations()[pathAnnotation])
+	secret, err = c.secret("lip6", "database")
+	util.OK(t, err)
+	util.Equals(t, map[string][]byte{"url": []byte("postgres://db.lip6.fr")}, secret.Data)
+	util.Equals(t, "store-uid", string(secret.GetOwnerReferences()[0].UID))
+	serviceAccount, err := c.kubeclientset.CoreV1().ServiceAccounts("lip6").Get(context.TODO(), ServiceAccount, metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, false, *serviceAccount.AutomountServiceAccountToken)
+
+	t.Run("dropped", func(t *testing.T) {
+		tenantSecretStore.Spec.Secrets = tenantSecretStore.Spec.Secrets[:1]
+		tenantSecretStore.Spec.Secrets[0].Namespaces = nil
+		c.processTenantSecretStore(context.TODO(), tenantSecretStore)
+		util.Equals(t, ready, tenantSecretStore.Status.State)
+		util.Equals(t, "1 secrets synced from Vault", tenantSecretStore.Status.Message)
+		_, err := c.secret("ci-1a2b3c", "registry-credentials")
+		util.Assert(t, errors.IsNotFound(err), "secret left in the namespace dropped")
+		_, err = c.secret("lip6", "database")
+		util.Assert(t, errors.IsNotFound(err), "secret left once dropped")
+		_, err = c.secret("lip6", "registry-credentials")
+		util.OK(t, err)
+	})
+}
+
+func TestFailure(t *testing.T) {
+	c, f := newController(t)
+
+	t.Run("not owned", func(t *testing.T) {
+		pasted := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "database", Namespace: "lip6"}, Data: map[string][]byte{"url": []byte("pasted")}}
+		_, err := c.kubeclientset.CoreV1().Secrets("lip6").Create(context.TODO(), pasted, metav1.CreateOptions{})
+		util.OK(t, err)
+		tenantSecretStore := newTenantSecretStore(corev1alpha.TenantSecretSync{Name: "database", Path: "database"})
+		c.processTenantSecretStore(context.TODO(), tenantSecretStore)
+		util.Equals(t, "Secret database exists in lip6 and does not belong to the store", tenantSecretStore.Status.Message)
+		secret, err := c.secret("lip6", "database")
+		util.OK(t, err)
+		util.Equals(t, []byte("pasted"), secret.Data["url"])
+	})
+	t.Run("out of the tenant path", func(t *testing.T) {
+		tenantSecretStore := newTenantSecretStore(corev1alpha.TenantSecretSync{Name: "stolen", Path: "../other/registry"},
+			corev1alpha.TenantSecretSync{Name: "missing", Path: "registry", Keys: []string{"token"}})
+		c.processTenantSecretStore(context.TODO(), tenantSecretStore)
+		util.Equals(t, failure, tenantSecretStore.Status.State)
+		util.Equals(t, "Secret ../other/registry could not be read from Vault, Key token not found in the Vault secret of registry", tenantSecretStore.Status.Message)
+		util.Equals(t, []corev1alpha.TenantSecretSyncStatus{}, tenantSecretStore.Status.Secrets)
+	})
+	t.Run("vault down", func(t *testing.T) {
+		tenantSecretStore := newTenantSecretStore(corev1alpha.TenantSecretSync{Name: "registry-credentials", Path: "registry"})
+		c.processTenantSecretStore(context.TODO(), tenantSecretStore)
+		util.Equals(t, ready, tenantSecretStore.Status.State)
+
+		f.down = true
+		defer func() { f.down = false }()
+		next := c.processTenantSecretStore(context.TODO(), tenantSecretStore)
+		util.Equals(t, RefreshInterval, next)
+		util.Equals(t, messageRoleFailed, tenantSecretStore.Status.Message)
+		// The secrets synced before stay
+		_, err := c.secret("lip6", "registry-credentials")
+		util.OK(t, err)
+	})
+}
+
+func TestDisabledTenant(t *testing.T) {
+	c, f := newController(t)
+	tenantSecretStore := newTenantSecretStore(corev1alpha.TenantSecretSync{Name: "registry-credentials", Path: "registry", Namespaces: []string{"ci-1a2b3c"}})
+	c.processTenantSecretStore(context.TODO(), tenantSecretStore)
+	util.Equals(t, true, f.roles["edgenet-lip6"])
+
+	tenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), "lip6", metav1.GetOptions{})
+	util.OK(t, err)
+	tenant.Spec.Enabled = false
+	_, err = c.edgenetclientset.CoreV1alpha().Tenants().Update(context.TODO(), tenant, metav1.UpdateOptions{})
+	util.OK(t, err)
+
+	util.Equals(t, time.Duration(0), c.processTenantSecretStore(context.TODO(), tenantSecretStore))
+	util.Equals(t, disabled, tenantSecretStore.Status.State)
+	util.Equals(t, false, f.roles["edgenet-lip6"])
+	for _, namespace := range []string{"lip6", "ci-1a2b3c"} {
+		_, err := c.secret(namespace, "registry-credentials")
+		util.Assert(t, errors.IsNotFound(err), "secret left in %s", namespace)
+	}
+}
@@ -0,0 +1,609 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantserviceaccount
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const controllerAgentName = "tenantserviceaccount-controller"
+
+// Definitions of the state of the tenant service account resource
+const (
+	successSynced          = "Synced"
+	messageResourceSynced  = "Tenant service account synced successfully"
+	successRotated         = "Token Rotated"
+	messageRotated         = "Token replaced by %s"
+	successDisabled        = "Disabled"
+	messageDisabled        = "Robot removed as the tenant is disabled"
+	messageReady           = "Robot bound in %d namespaces"
+	failureTenant          = "Not Found"
+	messageTenantNotFound  = "The namespace is not the core namespace of a tenant"
+	failureRole            = "Invalid Role"
+	messageRoleInvalid     = "Role %q is not a tenant role"
+	failureServiceAccount  = "Creation Failed"
+	messageServiceAccount  = "Service account creation failed"
+	messageNotOwned        = "Service account %s exists and does not belong to the robot"
+	failureToken           = "Token Failed"
+	messageTokenFailed     = "Token secret creation failed"
+	failureBinding         = "Binding Failed"
+	messageBindingFailed   = "Role binding failed in %s"
+	messageNamespaceDenied = "Namespace %s is not a subsidiary namespace of the tenant"
+	ready                  = "Ready"
+	disabled               = "Disabled"
+	failure                = "Failure"
+)
+
+// robotLabel marks the token secrets and role bindings of a robot with its name
+const robotLabel = "edge-net.io/tenant-service-account"
+
+// robotRoles are the cluster roles that a robot can hold, robots cannot own a tenant
+var robotRoles = map[string]string{
+	"admin":        access.TenantAdminRole,
+	"collaborator": access.TenantCollaboratorRole,
+}
+
+// Controller is the controller implementation for TenantServiceAccount resources
+type Controller struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+	// edgenetclientset is a clientset for the EdgeNet API groups
+	edgenetclientset clientset.Interface
+
+	tenantServiceAccountsLister listers.TenantServiceAccountLister
+	tenantServiceAccountsSynced cache.InformerSynced
+	tenantsSynced               cache.InformerSynced
+
+	// workqueue is a rate limited work queue. This is used to queue work to be
+	// processed instead of performing it as soon as a change happens. This
+	// means we can ensure we only process a fixed amount of resources at a
+	// time, and makes it easy to ensure we are never processing the same item
+	// simultaneously in two different workers.
+	workqueue workqueue.RateLimitingInterface
+	// recorder is an event recorder for recording Event resources to the
+	// Kubernetes API.
+	recorder record.EventRecorder
+}
+
+// NewController returns a new controller
+func NewController(
+	ctx context.Context,
+	kubeclientset kubernetes.Interface,
+	edgenetclientset clientset.Interface,
+	tenantServiceAccountInformer informers.TenantServiceAccountInformer,
+	tenantInformer informers.TenantInformer) *Controller {
+
+	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
+	klog.V(4).InfoS("Creating event broadcaster")
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartStructuredLogging(0)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+
+	controller := &Controller{
+		kubeclientset:               kubeclientset,
+		edgenetclientset:            edgenetclientset,
+		tenantServiceAccountsLister: tenantServiceAccountInformer.Lister(),
+		tenantServiceAccountsSynced: tenantServiceAccountInformer.Informer().HasSynced,
+		tenantsSynced:               tenantInformer.Informer().HasSynced,
+		workqueue:                   workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "TenantServiceAccounts"),
+		recorder:                    recorder,
+	}
+
+	klog.V(4).InfoS("Setting up event handlers")
+	// Set up an event handler for when TenantServiceAccount resources change
+	tenantServiceAccountInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueTenantServiceAccount,
+		UpdateFunc: func(old, new interface{}) {
+			newObj := new.(*corev1alpha.TenantServiceAccount)
+			oldObj := old.(*corev1alpha.TenantServiceAccount)
+			if !reflect.DeepEqual(newObj.Spec, oldObj.Spec) {
+				controller.enqueueTenantServiceAccount(new)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			// The bindings in the core namespace are garbage collected, the ones in the
+			// subsidiary namespaces cannot have an owner in another namespace
+			tenantServiceAccount, ok := obj.(*corev1alpha.TenantServiceAccount)
+			if !ok {
+				return
+			}
+			controller.unbind(ctx, tenantServiceAccount, tenantServiceAccount.Status.Namespaces)
+		},
+	})
+	// The robots of a tenant follow the tenant as it gets disabled or enabled
+	tenantInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			newObj := new.(*corev1alpha.Tenant)
+			oldObj := old.(*corev1alpha.Tenant)
+			if newObj.Spec.Enabled != oldObj.Spec.Enabled {
+				controller.handleTenant(newObj)
+			}
+		},
+	})
+
+	return controller
+}
+
+// Run will set up the event handlers for the types of tenant service account, as well
+// as syncing informer caches and starting workers. It will block until stopCh
+// is closed, at which point it will shutdown the workqueue and wait for
+// workers to finish processing their current work items.
+func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting TenantServiceAccount controller")
+
+	klog.V(4).InfoS("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh,
+		c.tenantServiceAccountsSynced,
+		c.tenantsSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	klog.V(4).InfoS("Starting workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	klog.V(4).InfoS("Started workers")
+	<-stopCh
+	klog.V(4).InfoS("Shutting down workers")
+
+	return nil
+}
+
+// runWorker is a long-running function that will continually call the
+// processNextWorkItem function in order to read and process a message on the
+// workqueue.
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+// processNextWorkItem will read a single work item off the workqueue and
+// attempt to process it, by calling the syncHandler.
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.workqueue.Done(obj)
+		var key string
+		var ok bool
+
+		if key, ok = obj.(string); !ok {
+			c.workqueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			return nil
+		}
+		if err := c.syncHandler(ctx, key); err != nil {
+			c.workqueue.AddRateLimited(key)
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
+		}
+		c.workqueue.Forget(obj)
+		klog.V(4).InfoS("Successfully synced", "key", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+
+	return true
+}
+
+// syncHandler compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the TenantServiceAccount
+// resource with the current status of the resource.
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	tenantServiceAccount, err := c.tenantServiceAccountsLister.TenantServiceAccounts(namespace).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("tenant service account '%s' in work queue no longer exists", key))
+			return nil
+		}
+
+		return err
+	}
+
+	tenantServiceAccountCopy := tenantServiceAccount.DeepCopy()
+	next := c.processTenantServiceAccount(ctx, tenantServiceAccountCopy)
+	if !reflect.DeepEqual(tenantServiceAccount.Status, tenantServiceAccountCopy.Status) {
+		if _, err := c.edgenetclientset.CoreV1alpha().TenantServiceAccounts(namespace).UpdateStatus(ctx, tenantServiceAccountCopy, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	c.recorder.Event(tenantServiceAccount, corev1.EventTypeNormal, successSynced, messageResourceSynced)
+	if next > 0 {
+		// The token is due for rotation
+		c.workqueue.AddAfter(key, next)
+	}
+	return nil
+}
+
+// enqueueTenantServiceAccount takes a TenantServiceAccount resource and converts it into a namespace/name
+// string which is then put onto the work queue. This method should *not* be
+// passed resources of any type other than TenantServiceAccount.
+func (c *Controller) enqueueTenantServiceAccount(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+// handleTenant enqueues the robots in the core namespace of a tenant
+func (c *Controller) handleTenant(tenant *corev1alpha.Tenant) {
+	tenantServiceAccounts, err := c.tenantServiceAccountsLister.TenantServiceAccounts(tenant.GetName()).List(labels.Everything())
+	if err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	for _, tenantServiceAccount := range tenantServiceAccounts {
+		c.enqueueTenantServiceAccount(tenantServiceAccount)
+	}
+}
+
+// processTenantServiceAccount makes the robot identity and its bindings match the spec, and returns
+// the time left until the token is to be rotated, zero if it never is
+func (c *Controller) processTenantServiceAccount(ctx context.Context, tenantServiceAccountCopy *corev1alpha.TenantServiceAccount) time.Duration {
+	namespace := tenantServiceAccountCopy.GetNamespace()
+	// The core namespace has the same name as the tenant
+	tenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Couldn't get the tenant", "tenant", namespace)
+		c.recorder.Event(tenantServiceAccountCopy, corev1.EventTypeWarning, failureTenant, messageTenantNotFound)
+		tenantServiceAccountCopy.Status.State = failure
+		tenantServiceAccountCopy.Status.Message = messageTenantNotFound
+		return 0
+	}
+	if !tenant.Spec.Enabled {
+		c.cleanup(ctx, tenantServiceAccountCopy)
+		return 0
+	}
+	roleName, ok := robotRoles[tenantServiceAccountCopy.Spec.Role]
+	if !ok {
+		c.fail(tenantServiceAccountCopy, failureRole, fmt.Sprintf(messageRoleInvalid, tenantServiceAccountCopy.Spec.Role))
+		return 0
+	}
+
+	if err := c.applyServiceAccount(ctx, tenantServiceAccountCopy); err != nil {
+		klog.ErrorS(err, "Couldn't apply the service account", "tenantServiceAccount", klog.KObj(tenantServiceAccountCopy))
+		message := messageServiceAccount
+		if err == errNotOwned {
+			message = fmt.Sprintf(messageNotOwned, tenantServiceAccountCopy.GetName())
+		}
+		c.fail(tenantServiceAccountCopy, failureServiceAccount, message)
+		return 0
+	}
+	if err := c.applyToken(ctx, tenantServiceAccountCopy, access.TenantTokenPolicy(tenant)); err != nil {
+		klog.ErrorS(err, "Couldn't apply the token", "tenantServiceAccount", klog.KObj(tenantServiceAccountCopy))
+		c.fail(tenantServiceAccountCopy, failureToken, messageTokenFailed)
+		return 0
+	}
+	if message := c.bind(ctx, tenantServiceAccountCopy, roleName); message != "" {
+		c.fail(tenantServiceAccountCopy, failureBinding, message)
+	} else {
+		tenantServiceAccountCopy.Status.State = ready
+		tenantServiceAccountCopy.Status.Message = fmt.Sprintf(messageReady, len(tenantServiceAccountCopy.Status.Namespaces))
+	}
+	return untilRotation(tenantServiceAccountCopy)
+}
+
+// errNotOwned tells that a service account of the same name was not created for the robot
+var errNotOwned = fmt.Errorf("service account not owned by the tenant service account")
+
+// applyServiceAccount creates the service account of the robot in the core namespace
+func (c *Controller) applyServiceAccount(ctx context.Context, tenantServiceAccountCopy *corev1alpha.TenantServiceAccount) error {
+	namespace := tenantServiceAccountCopy.GetNamespace()
+	serviceAccount, err := c.kubeclientset.CoreV1().ServiceAccounts(namespace).Get(ctx, tenantServiceAccountCopy.GetName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		serviceAccount = &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: tenantServiceAccountCopy.GetName(), Namespace: namespace,
+			OwnerReferences: ownerReferences(tenantServiceAccountCopy), Labels: robotLabels(tenantServiceAccountCopy)}}
+		// The tokens are issued by the controller alone so that it can rotate them
+		automount := false
+		serviceAccount.AutomountServiceAccountToken = &automount
+		if _, err := c.kubeclientset.CoreV1().ServiceAccounts(namespace).Create(ctx, serviceAccount, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	} else if !metav1.IsControlledBy(serviceAccount, tenantServiceAccountCopy) {
+		return errNotOwned
+	}
+	tenantServiceAccountCopy.Status.ServiceAccount = tenantServiceAccountCopy.GetName()
+	return nil
+}
+
+// applyToken issues the first token of the robot, or a new one when it is due for rotation. The tokens
+// are requested for the audiences of the robot with the lifetime of the tier of the tenant, and bound
+// to their secret so that the previous token stops working as soon as its secret is removed.
+func (c *Controller) applyToken(ctx context.Context, tenantServiceAccountCopy *corev1alpha.TenantServiceAccount, policy access.TokenPolicy) error {
+	namespace := tenantServiceAccountCopy.GetNamespace()
+	current := tenantServiceAccountCopy.Status.TokenSecret
+	unscoped := false
+	if current != "" {
+		if secret, err := c.kubeclientset.CoreV1().Secrets(namespace).Get(ctx, current, metav1.GetOptions{}); errors.IsNotFound(err) {
+			// Removed by hand, a new one is issued
+			current = ""
+		} else if err != nil {
+			return err
+		} else {
+			// Issued as a legacy token that never expires
+			unscoped = secret.Type == corev1.SecretTypeServiceAccountToken
+		}
+	}
+	if current == "" || unscoped || untilRotation(tenantServiceAccountCopy) < 0 {
+		rotations := tenantServiceAccountCopy.Status.Rotations
+		if current != "" {
+			rotations++
+		}
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-token-%d", tenantServiceAccountCopy.GetName(), rotations), Namespace: namespace,
+			OwnerReferences: ownerReferences(tenantServiceAccountCopy), Labels: robotLabels(tenantServiceAccountCopy),
+			Annotations: map[string]string{corev1.ServiceAccountNameKey: tenantServiceAccountCopy.GetName()}},
+			Type: corev1.SecretTypeOpaque}
+		secret, err := c.kubeclientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+		if errors.IsAlreadyExists(err) {
+			secret, err = c.kubeclientset.CoreV1().Secrets(namespace).Get(ctx, fmt.Sprintf("%s-token-%d", tenantServiceAccountCopy.GetName(), rotations), metav1.GetOptions{})
+		}
+		if err != nil {
+			return err
+		}
+		expiration := int64(policy.Lifetime.Seconds())
+		tokenRequest := &authenticationv1.TokenRequest{Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         tenantServiceAccountCopy.Spec.Audiences,
+			ExpirationSeconds: &expiration,
+			BoundObjectRef:    &authenticationv1.BoundObjectReference{Kind: "Secret", APIVersion: "v1", Name: secret.GetName(), UID: secret.GetUID()},
+		}}
+		tokenRequest, err = c.kubeclientset.CoreV1().ServiceAccounts(namespace).CreateToken(ctx, tenantServiceAccountCopy.GetName(), tokenRequest, metav1.CreateOptions{})
+		if err != nil {
+			return err
+		}
+		secret.Data = map[string][]byte{corev1.ServiceAccountTokenKey: []byte(tokenRequest.Status.Token)}
+		if _, err := c.kubeclientset.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		now := metav1.Now()
+		tenantServiceAccountCopy.Status.TokenSecret = secret.GetName()
+		tenantServiceAccountCopy.Status.Rotations = rotations
+		tenantServiceAccountCopy.Status.LastRotation = &now
+		tenantServiceAccountCopy.Status.TokenExpiry = tokenRequest.Status.ExpirationTimestamp.DeepCopy()
+		if current != "" {
+			c.recorder.Event(tenantServiceAccountCopy, corev1.EventTypeNormal, successRotated, fmt.Sprintf(messageRotated, secret.GetName()))
+		}
+	}
+
+	// Only the current token is valid
+	return c.revokeTokens(ctx, tenantServiceAccountCopy, tenantServiceAccountCopy.Status.TokenSecret)
+}
+
+// revokeTokens removes the token secrets of the robot except for the one to keep
+func (c *Controller) revokeTokens(ctx context.Context, tenantServiceAccount *corev1alpha.TenantServiceAccount, keep string) error {
+	namespace := tenantServiceAccount.GetNamespace()
+	secrets, err := c.kubeclientset.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", robotLabel, tenantServiceAccount.GetName())})
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets.Items {
+		if secret.GetName() != keep {
+			if err := c.kubeclientset.CoreV1().Secrets(namespace).Delete(ctx, secret.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bind grants the role to the robot in the core namespace and in the subsidiary namespaces listed,
+// and removes it from the ones no longer listed. It returns a message if a binding failed.
+func (c *Controller) bind(ctx context.Context, tenantServiceAccountCopy *corev1alpha.TenantServiceAccount, roleName string) string {
+	tenant := tenantServiceAccountCopy.GetNamespace()
+	desired := []string{tenant}
+	messages := []string{}
+	for _, namespace := range tenantServiceAccountCopy.Spec.Namespaces {
+		if namespace == tenant {
+			continue
+		}
+		// The robot cannot be bound outside its tenant
+		if subnamespace, err := c.kubeclientset.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{}); err != nil ||
+			subnamespace.GetLabels()["edge-net.io/tenant"] != tenant || subnamespace.GetLabels()["edge-net.io/kind"] != "sub" {
+			messages = append(messages, fmt.Sprintf(messageNamespaceDenied, namespace))
+			continue
+		}
+		desired = append(desired, namespace)
+	}
+
+	bound := []string{}
+	for _, namespace := range desired {
+		if err := c.applyRoleBinding(ctx, tenantServiceAccountCopy, namespace, roleName); err != nil {
+			klog.ErrorS(err, "Couldn't apply the role binding", "namespace", namespace, "tenantServiceAccount", klog.KObj(tenantServiceAccountCopy))
+			messages = append(messages, fmt.Sprintf(messageBindingFailed, namespace))
+			continue
+		}
+		bound = append(bound, namespace)
+	}
+	stale := []string{}
+	for _, namespace := range tenantServiceAccountCopy.Status.Namespaces {
+		if !contains(bound, namespace) {
+			stale = append(stale, namespace)
+		}
+	}
+	c.unbind(ctx, tenantServiceAccountCopy, stale)
+	tenantServiceAccountCopy.Status.Namespaces = bound
+	return strings.Join(messages, ", ")
+}
+
+// applyRoleBinding binds the role to the service account of the robot in a namespace
+func (c *Controller) applyRoleBinding(ctx context.Context, tenantServiceAccountCopy *corev1alpha.TenantServiceAccount, namespace, roleName string) error {
+	roleBind := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: roleBindingName(tenantServiceAccountCopy), Namespace: namespace,
+		Labels: robotLabels(tenantServiceAccountCopy)},
+		Subjects: []rbacv1.Subject{{Kind: "ServiceAccount", Name: tenantServiceAccountCopy.GetName(), Namespace: tenantServiceAccountCopy.GetNamespace()}},
+		RoleRef:  rbacv1.RoleRef{Kind: "ClusterRole", Name: roleName}}
+	if namespace == tenantServiceAccountCopy.GetNamespace() {
+		roleBind.SetOwnerReferences(ownerReferences(tenantServiceAccountCopy))
+	}
+	current, err := c.kubeclientset.RbacV1().RoleBindings(namespace).Get(ctx, roleBind.GetName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = c.kubeclientset.RbacV1().RoleBindings(namespace).Create(ctx, roleBind, metav1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+	if current.RoleRef != roleBind.RoleRef {
+		// The role reference of a binding cannot change
+		if err := c.kubeclientset.RbacV1().RoleBindings(namespace).Delete(ctx, current.GetName(), metav1.DeleteOptions{}); err != nil {
+			return err
+		}
+		_, err = c.kubeclientset.RbacV1().RoleBindings(namespace).Create(ctx, roleBind, metav1.CreateOptions{})
+		return err
+	}
+	if !reflect.DeepEqual(current.Subjects, roleBind.Subjects) {
+		current.Subjects = roleBind.Subjects
+		_, err = c.kubeclientset.RbacV1().RoleBindings(namespace).Update(ctx, current, metav1.UpdateOptions{})
+	}
+	return err
+}
+
+// unbind removes the role bindings of the robot from the namespaces
+func (c *Controller) unbind(ctx context.Context, tenantServiceAccount *corev1alpha.TenantServiceAccount, namespaces []string) {
+	for _, namespace := range namespaces {
+		if err := c.kubeclientset.RbacV1().RoleBindings(namespace).Delete(ctx, roleBindingName(tenantServiceAccount), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			klog.ErrorS(err, "Couldn't delete the role binding", "roleBinding", klog.KRef(namespace, roleBindingName(tenantServiceAccount)))
+		}
+	}
+}
+
+// cleanup removes the robot identity of a disabled tenant, the resource stays so that the robot
+// comes back with a new token once the tenant is enabled
+func (c *Controller) cleanup(ctx context.Context, tenantServiceAccountCopy *corev1alpha.TenantServiceAccount) {
+	namespace := tenantServiceAccountCopy.GetNamespace()
+	c.unbind(ctx, tenantServiceAccountCopy, append([]string{namespace}, tenantServiceAccountCopy.Status.Namespaces...))
+	if err := c.revokeTokens(ctx, tenantServiceAccountCopy, ""); err != nil {
+		klog.ErrorS(err, "Couldn't revoke the tokens", "tenantServiceAccount", klog.KObj(tenantServiceAccountCopy))
+	}
+	if serviceAccount, err := c.kubeclientset.CoreV1().ServiceAccounts(namespace).Get(ctx, tenantServiceAccountCopy.GetName(), metav1.GetOptions{}); err == nil && metav1.IsControlledBy(serviceAccount, tenantServiceAccountCopy) {
+		if err := c.kubeclientset.CoreV1().ServiceAccounts(namespace).Delete(ctx, serviceAccount.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			klog.ErrorS(err, "Couldn't delete the service account", "serviceAccount", klog.KRef(namespace, serviceAccount.GetName()))
+		}
+	}
+	if tenantServiceAccountCopy.Status.State != disabled {
+		c.recorder.Event(tenantServiceAccountCopy, corev1.EventTypeNormal, successDisabled, messageDisabled)
+	}
+	tenantServiceAccountCopy.Status.State = disabled
+	tenantServiceAccountCopy.Status.Message = messageDisabled
+	tenantServiceAccountCopy.Status.ServiceAccount = ""
+	tenantServiceAccountCopy.Status.TokenSecret = ""
+	tenantServiceAccountCopy.Status.TokenExpiry = nil
+	tenantServiceAccountCopy.Status.Namespaces = nil
+}
+
+func (c *Controller) fail(tenantServiceAccountCopy *corev1alpha.TenantServiceAccount, reason, message string) {
+	c.recorder.Event(tenantServiceAccountCopy, corev1.EventTypeWarning, reason, message)
+	tenantServiceAccountCopy.Status.State = failure
+	tenantServiceAccountCopy.Status.Message = message
+}
+
+// untilRotation returns the time left until the token is to be rotated, negative once it is due
+// and zero if the token is never rotated. A token is rotated after the rotation period, or once four
+// fifths of its lifetime are over so that the robot never holds an expired token.
+func untilRotation(tenantServiceAccount *corev1alpha.TenantServiceAccount) time.Duration {
+	if tenantServiceAccount.Status.LastRotation == nil {
+		return 0
+	}
+	issued := tenantServiceAccount.Status.LastRotation.Time
+	var due time.Time
+	if tenantServiceAccount.Spec.RotationPeriod > 0 {
+		due = issued.Add(time.Duration(tenantServiceAccount.Spec.RotationPeriod) * time.Hour)
+	}
+	if expiry := tenantServiceAccount.Status.TokenExpiry; expiry != nil {
+		if refresh := issued.Add(expiry.Sub(issued) * 4 / 5); due.IsZero() || refresh.Before(due) {
+			due = refresh
+		}
+	}
+	if due.IsZero() {
+		return 0
+	}
+	left := time.Until(due)
+	if left == 0 {
+		left = -1
+	}
+	return left
+}
+
+func ownerReferences(tenantServiceAccount *corev1alpha.TenantServiceAccount) []metav1.OwnerReference {
+	return []metav1.OwnerReference{*metav1.NewControllerRef(tenantServiceAccount, corev1alpha.SchemeGroupVersion.WithKind("TenantServiceAccount"))}
+}
+
+func robotLabels(tenantServiceAccount *corev1alpha.TenantServiceAccount) map[string]string {
+	return map[string]string{"edge-net.io/generated": "true", "edge-net.io/tenant": tenantServiceAccount.GetNamespace(), robotLabel: tenantServiceAccount.GetName()}
+}
+
+func roleBindingName(tenantServiceAccount *corev1alpha.TenantServiceAccount) string {
+	return fmt.Sprintf("edgenet:robot-%s", tenantServiceAccount.GetName())
+}
+
+func contains(list []string, value string) bool {
+	for _, element := range list {
+		if element == value {
+			return true
+		}
+	}
+	return false
+}
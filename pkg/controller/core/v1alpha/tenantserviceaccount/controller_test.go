@@ -0,0 +1,259 @@
+package tenantserviceaccount
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/testutil"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+// newController returns a controller with an enabled tenant that has a subsidiary namespace, and a
+// namespace of another tenant
+func newController(t *testing.T) *Controller {
+	kubeclientset, edgenetclientset := testutil.NewTenantClientsets(t, "edgenet", "ci-1a2b3c")
+	// The API server issues the tokens for their lifetime and binds them to the secret
+	testutil.IssueTokens(kubeclientset)
+	return &Controller{
+		kubeclientset:    kubeclientset,
+		edgenetclientset: edgenetclientset,
+		recorder:         record.NewFakeRecorder(100),
+	}
+}
+
+func newTenantServiceAccount(role string, namespaces ...string) *corev1alpha.TenantServiceAccount {
+	return &corev1alpha.TenantServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "pipeline", Namespace: "edgenet", UID: types.UID("robot-uid")},
+		Spec: corev1alpha.TenantServiceAccountSpec{Role: role, Namespaces: namespaces}}
+}
+
+func (c *Controller) roleBindingExists(namespace string) bool {
+	_, err := c.kubeclientset.RbacV1().RoleBindings(namespace).Get(context.TODO(), "edgenet:robot-pipeline", metav1.GetOptions{})
+	return err == nil
+}
+
+func (c *Controller) tokenSecrets(t *testing.T) []string {
+	secrets, err := c.kubeclientset.CoreV1().Secrets("edgenet").List(context.TODO(), metav1.ListOptions{})
+	util.OK(t, err)
+	names := []string{}
+	for _, secret := range secrets.Items {
+		names = append(names, secret.GetName())
+	}
+	return names
+}
+
+func TestProcessTenantServiceAccount(t *testing.T) {
+	cases := map[string]struct {
+		role       string
+		namespaces []string
+		expected   string
+		bound      []string
+	}{
+		"core namespace":        {"collaborator", nil, ready, []string{"edgenet"}},
+		"subsidiary namespace":  {"admin", []string{"ci-1a2b3c"}, ready, []string{"edgenet", "ci-1a2b3c"}},
+		"namespace of another":  {"admin", []string{"other"}, failure, []string{"edgenet"}},
+		"namespace not found":   {"admin", []string{"ci-1a2b3c", "missing"}, failure, []string{"edgenet", "ci-1a2b3c"}},
+		"owner role":            {"owner", nil, failure, nil},
+		"core namespace listed": {"admin", []string{"edgenet"}, ready, []string{"edgenet"}},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			c := newController(t)
+			tenantServiceAccount := newTenantServiceAccount(tc.role, tc.namespaces...)
+			c.processTenantServiceAccount(context.TODO(), tenantServiceAccount)
+			util.Equals(t, tc.expected, tenantServiceAccount.Status.State)
+			util.Equals(t, tc.bound, tenantServiceAccount.Status.Namespaces)
+			util.Equals(t, false, c.roleBindingExists("other"))
+			for _, namespace := range tc.bound {
+				util.Assert(t, c.roleBindingExists(namespace), "no role binding in %s", namespace)
+			}
+		})
+	}
+}
+
+func TestServiceAccount(t *testing.T) {
+	c := newController(t)
+	tenantServiceAccount := newTenantServiceAccount("collaborator")
+	next := c.processTenantServiceAccount(context.TODO(), tenantServiceAccount)
+	// The token of the community tier lasts an hour and is replaced before it expires
+	util.Assert(t, next > 47*time.Minute && next <= 48*time.Minute, "token rotated in %s", next)
+	util.Equals(t, "pipeline", tenantServiceAccount.Status.ServiceAccount)
+	util.Equals(t, "pipeline-token-0", tenantServiceAccount.Status.TokenSecret)
+	util.Assert(t, tenantServiceAccount.Status.TokenExpiry != nil, "no token expiry")
+
+	serviceAccount, err := c.kubeclientset.CoreV1().ServiceAccounts("edgenet").Get(context.TODO(), "pipeline", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, false, *serviceAccount.AutomountServiceAccountToken)
+	secret, err := c.kubeclientset.CoreV1().Secrets("edgenet").Get(context.TODO(), "pipeline-token-0", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, corev1.SecretTypeOpaque, secret.Type)
+	util.Equals(t, "pipeline", secret.GetAnnotations()[corev1.ServiceAccountNameKey])
+	util.Equals(t, "edgenet:pipeline:pipeline-token-0:", string(secret.Data[corev1.ServiceAccountTokenKey]))
+	roleBinding, err := c.kubeclientset.RbacV1().RoleBindings("edgenet").Get(context.TODO(), "edgenet:robot-pipeline", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, access.TenantCollaboratorRole, roleBinding.RoleRef.Name)
+	util.Equals(t, "ServiceAccount", roleBinding.Subjects[0].Kind)
+
+	t.Run("role change", func(t *testing.T) {
+		tenantServiceAccount.Spec.Role = "admin"
+		c.processTenantServiceAccount(context.TODO(), tenantServiceAccount)
+		roleBinding, err := c.kubeclientset.RbacV1().RoleBindings("edgenet").Get(context.TODO(), "edgenet:robot-pipeline", metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, access.TenantAdminRole, roleBinding.RoleRef.Name)
+	})
+	t.Run("service account of someone else", func(t *testing.T) {
+		c := newController(t)
+		serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "pipeline", Namespace: "edgenet"}}
+		_, err := c.kubeclientset.CoreV1().ServiceAccounts("edgenet").Create(context.TODO(), serviceAccount, metav1.CreateOptions{})
+		util.OK(t, err)
+		tenantServiceAccount := newTenantServiceAccount("admin")
+		c.processTenantServiceAccount(context.TODO(), tenantServiceAccount)
+		util.Equals(t, failure, tenantServiceAccount.Status.State)
+		util.Equals(t, "", tenantServiceAccount.Status.TokenSecret)
+	})
+	t.Run("not in a core namespace", func(t *testing.T) {
+		tenantServiceAccount := newTenantServiceAccount("admin")
+		tenantServiceAccount.SetNamespace("ci-1a2b3c")
+		c.processTenantServiceAccount(context.TODO(), tenantServiceAccount)
+		util.Equals(t, failure, tenantServiceAccount.Status.State)
+		util.Equals(t, messageTenantNotFound, tenantServiceAccount.Status.Message)
+	})
+}
+
+func TestTokenRotation(t *testing.T) {
+	policies := access.TokenPolicies
+	defer func() { access.TokenPolicies = policies }()
+	access.TokenPolicies = map[string]access.TokenPolicy{"community": {Lifetime: 72 * time.Hour}}
+	c := newController(t)
+	tenantServiceAccount := newTenantServiceAccount("admin")
+	tenantServiceAccount.Spec.RotationPeriod = 24
+	next := c.processTenantServiceAccount(context.TODO(), tenantServiceAccount)
+	util.Assert(t, next > 23*time.Hour && next <= 24*time.Hour, "token rotated in %s", next)
+	util.Equals(t, []string{"pipeline-token-0"}, c.tokenSecrets(t))
+
+	// Not yet due
+	c.processTenantServiceAccount(context.TODO(), tenantServiceAccount)
+	util.Equals(t, "pipeline-token-0", tenantServiceAccount.Status.TokenSecret)
+
+	issued := metav1.NewTime(time.Now().Add(-25 * time.Hour))
+	tenantServiceAccount.Status.LastRotation = &issued
+	c.processTenantServiceAccount(context.TODO(), tenantServiceAccount)
+	util.Equals(t, "pipeline-token-1", tenantServiceAccount.Status.TokenSecret)
+	util.Equals(t, 1, tenantServiceAccount.Status.Rotations)
+	// The previous token is revoked
+	util.Equals(t, []string{"pipeline-token-1"}, c.tokenSecrets(t))
+
+	// A token removed by hand is issued again
+	util.OK(t, c.kubeclientset.CoreV1().Secrets("edgenet").Delete(context.TODO(), "pipeline-token-1", metav1.DeleteOptions{}))
+	c.processTenantServiceAccount(context.TODO(), tenantServiceAccount)
+	util.Equals(t, []string{"pipeline-token-1"}, c.tokenSecrets(t))
+}
+
+func TestNamespaceRemoved(t *testing.T) {
+	c := newController(t)
+	tenantServiceAccount := newTenantServiceAccount("admin", "ci-1a2b3c")
+	c.processTenantServiceAccount(context.TODO(), tenantServiceAccount)
+	util.Assert(t, c.roleBindingExists("ci-1a2b3c"), "no role binding in the subsidiary namespace")
+
+	tenantServiceAccount.Spec.Namespaces = nil
+	c.processTenantServiceAccount(context.TODO(), tenantServiceAccount)
+	util.Equals(t, []string{"edgenet"}, tenantServiceAccount.Status.Namespaces)
+	util.Equals(t, false, c.roleBindingExists("ci-1a2b3c"))
+
+	// Bindings in the subsidiary namespaces are removed along with the resource
+	tenantServiceAccount.Spec.Namespaces = []string{"ci-1a2b3c"}
+	c.processTenantServiceAccount(context.TODO(), tenantServiceAccount)
+	c.unbind(context.TODO(), tenantServiceAccount, tenantServiceAccount.Status.Namespaces)
+	util.Equals(t, false, c.roleBindingExists("ci-1a2b3c"))
+}
+
+func TestTenantDisabled(t *testing.T) {
+	c := newController(t)
+	tenantServiceAccount := newTenantServiceAccount("admin", "ci-1a2b3c")
+	c.processTenantServiceAccount(context.TODO(), tenantServiceAccount)
+	util.Equals(t, ready, tenantServiceAccount.Status.State)
+
+	tenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), "edgenet", metav1.GetOptions{})
+	util.OK(t, err)
+	tenant.Spec.Enabled = false
+	_, err = c.edgenetclientset.CoreV1alpha().Tenants().Update(context.TODO(), tenant, metav1.UpdateOptions{})
+	util.OK(t, err)
+	c.processTenantServiceAccount(context.TODO(), tenantServiceAccount)
+	util.Equals(t, disabled, tenantServiceAccount.Status.State)
+	util.Equals(t, false, c.roleBindingExists("edgenet"))
+	util.Equals(t, false, c.roleBindingExists("ci-1a2b3c"))
+	util.Equals(t, []string{}, c.tokenSecrets(t))
+	_, err = c.kubeclientset.CoreV1().ServiceAccounts("edgenet").Get(context.TODO(), "pipeline", metav1.GetOptions{})
+	util.Equals(t, true, errors.IsNotFound(err))
+
+	// The robot comes back with a new token
+	tenant.Spec.Enabled = true
+	_, err = c.edgenetclientset.CoreV1alpha().Tenants().Update(context.TODO(), tenant, metav1.UpdateOptions{})
+	util.OK(t, err)
+	c.processTenantServiceAccount(context.TODO(), tenantServiceAccount)
+	util.Equals(t, ready, tenantServiceAccount.Status.State)
+	util.Assert(t, c.roleBindingExists("ci-1a2b3c"), "no role binding in the subsidiary namespace")
+	util.Equals(t, 1, len(c.tokenSecrets(t)))
+}
+
+func TestScopedToken(t *testing.T) {
+	c := newController(t)
+	tenantServiceAccount := newTenantServiceAccount("admin")
+	tenantServiceAccount.Spec.Audiences = []string{"registry.edge-net.io"}
+
+	t.Run("audiences", func(t *testing.T) {
+		c.processTenantServiceAccount(context.TODO(), tenantServiceAccount)
+		secret, err := c.kubeclientset.CoreV1().Secrets("edgenet").Get(context.TODO(), "pipeline-token-0", metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, "edgenet:pipeline:pipeline-token-0:registry.edge-net.io", string(secret.Data[corev1.ServiceAccountTokenKey]))
+	})
+	t.Run("tier", func(t *testing.T) {
+		tenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), "edgenet", metav1.GetOptions{})
+		util.OK(t, err)
+		tenant.Spec.Priority = &corev1alpha.Priority{Tier: "paying"}
+		_, err = c.edgenetclientset.CoreV1alpha().Tenants().Update(context.TODO(), tenant, metav1.UpdateOptions{})
+		util.OK(t, err)
+		// The token is replaced when four fifths of its lifetime are over
+		issued := metav1.NewTime(time.Now().Add(-49 * time.Minute))
+		tenantServiceAccount.Status.LastRotation = &issued
+		tenantServiceAccount.Status.TokenExpiry = &metav1.Time{Time: issued.Add(time.Hour)}
+		next := c.processTenantServiceAccount(context.TODO(), tenantServiceAccount)
+		util.Equals(t, "pipeline-token-1", tenantServiceAccount.Status.TokenSecret)
+		lifetime := access.TokenPolicies["paying"].Lifetime
+		util.Assert(t, next > lifetime*4/5-time.Minute && next <= lifetime*4/5, "token rotated in %s", next)
+		util.Equals(t, []string{"pipeline-token-1"}, c.tokenSecrets(t))
+	})
+	t.Run("legacy token", func(t *testing.T) {
+		c := newController(t)
+		tenantServiceAccount := newTenantServiceAccount("admin")
+		legacy := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "pipeline-token-0", Namespace: "edgenet",
+			Labels: map[string]string{robotLabel: "pipeline"}}, Type: corev1.SecretTypeServiceAccountToken}
+		_, err := c.kubeclientset.CoreV1().Secrets("edgenet").Create(context.TODO(), legacy, metav1.CreateOptions{})
+		util.OK(t, err)
+		issued := metav1.Now()
+		tenantServiceAccount.Status.TokenSecret = "pipeline-token-0"
+		tenantServiceAccount.Status.LastRotation = &issued
+		// The token that never expires is replaced by a scoped one at once
+		c.processTenantServiceAccount(context.TODO(), tenantServiceAccount)
+		util.Equals(t, "pipeline-token-1", tenantServiceAccount.Status.TokenSecret)
+		util.Equals(t, []string{"pipeline-token-1"}, c.tokenSecrets(t))
+	})
+}
@@ -0,0 +1,313 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantusage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+	"github.com/EdgeNet-project/edgenet/pkg/storage"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// Interval is the time between two samples of the usage of the tenants
+var Interval = 5 * time.Minute
+
+// podMetricsPath lists the usage of the pods as reported by the metrics server
+const podMetricsPath = "/apis/metrics.k8s.io/v1beta1/pods"
+
+// Controller samples the resources that each tenant consumes, records them in the TenantUsage
+// resource of the tenant, and exports them as metrics
+type Controller struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+	// edgenetclientset is a clientset for the EdgeNet API groups
+	edgenetclientset clientset.Interface
+
+	tenantsLister listers.TenantLister
+	tenantsSynced cache.InformerSynced
+
+	// podMetrics returns the usage of the pods summed by namespace
+	podMetrics func(ctx context.Context) (map[string]corev1.ResourceList, error)
+
+	// usages holds the last sample of each tenant, they are served as metrics
+	mutex  sync.RWMutex
+	usages map[string]corev1alpha.TenantUsageStatus
+}
+
+// NewController returns a new controller
+func NewController(
+	kubeclientset kubernetes.Interface,
+	edgenetclientset clientset.Interface,
+	tenantInformer informers.TenantInformer) *Controller {
+
+	controller := &Controller{
+		kubeclientset:    kubeclientset,
+		edgenetclientset: edgenetclientset,
+		tenantsLister:    tenantInformer.Lister(),
+		tenantsSynced:    tenantInformer.Informer().HasSynced,
+		usages:           map[string]corev1alpha.TenantUsageStatus{},
+	}
+	controller.podMetrics = controller.metricsServer
+	return controller
+}
+
+// Run waits for the informer caches to sync and samples the usage of the tenants at every
+// interval. It will block until stopCh is closed.
+func (c *Controller) Run(stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting TenantUsage controller")
+
+	klog.V(4).InfoS("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh,
+		c.tenantsSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	go wait.UntilWithContext(ctx, c.account, Interval)
+
+	klog.V(4).InfoS("Started accounting")
+	<-stopCh
+	klog.V(4).InfoS("Shutting down accounting")
+
+	return nil
+}
+
+// account samples the usage of every tenant, the actual usage is left out if the metrics server
+// cannot be reached
+func (c *Controller) account(ctx context.Context) {
+	tenants, err := c.tenantsLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Couldn't list the tenants")
+		return
+	}
+	contributed, err := c.contributedNodes(ctx)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't list the node contributions")
+		return
+	}
+	podUsage, err := c.podMetrics(ctx)
+	if err != nil {
+		klog.V(4).InfoS("Couldn't get the pod metrics, the usage is not accounted", "err", err)
+		podUsage = nil
+	}
+
+	now := metav1.Now()
+	usages := map[string]corev1alpha.TenantUsageStatus{}
+	for _, tenant := range tenants {
+		status, err := c.sample(ctx, tenant, contributed, podUsage, now)
+		if err != nil {
+			klog.ErrorS(err, "Couldn't account for the tenant", "tenant", klog.KObj(tenant))
+			continue
+		}
+		usages[tenant.GetName()] = status
+	}
+	c.mutex.Lock()
+	c.usages = usages
+	c.mutex.Unlock()
+}
+
+// sample sums the requests and the usage of the running pods in the namespaces of the tenant,
+// adds the time since the last sample to the node time of the nodes running them, and assesses
+// the fair share of the tenant
+func (c *Controller) sample(ctx context.Context, tenant *corev1alpha.Tenant, contributed map[string]bool, podUsage map[string]corev1.ResourceList, now metav1.Time) (corev1alpha.TenantUsageStatus, error) {
+	tenantUsage, err := c.tenantUsage(ctx, tenant)
+	if err != nil {
+		return corev1alpha.TenantUsageStatus{}, err
+	}
+	namespaces, err := c.kubeclientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("edge-net.io/tenant=%s", tenant.GetName())})
+	if err != nil {
+		return corev1alpha.TenantUsageStatus{}, err
+	}
+
+	tenantUsageCopy := tenantUsage.DeepCopy()
+	status := &tenantUsageCopy.Status
+	status.Pods = 0
+	status.Requests = corev1.ResourceList{}
+	status.Usage = nil
+	if podUsage != nil {
+		status.Usage = corev1.ResourceList{}
+	}
+	nodes := map[string]bool{}
+	for _, namespace := range namespaces.Items {
+		pods, err := c.kubeclientset.CoreV1().Pods(namespace.GetName()).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return corev1alpha.TenantUsageStatus{}, err
+		}
+		for _, pod := range pods.Items {
+			if pod.Status.Phase != corev1.PodRunning {
+				continue
+			}
+			status.Pods++
+			addResources(status.Requests, podRequests(&pod))
+			if pod.Spec.NodeName != "" {
+				nodes[pod.Spec.NodeName] = true
+			}
+		}
+		if podUsage != nil {
+			addResources(status.Usage, podUsage[namespace.GetName()])
+		}
+	}
+
+	var elapsed time.Duration
+	if status.LastSampled != nil {
+		// The accounting does not make up for the time it was not running
+		elapsed = now.Sub(status.LastSampled.Time)
+		if elapsed > 2*Interval {
+			elapsed = 2 * Interval
+		}
+		for node := range nodes {
+			if contributed[node] {
+				status.NodeTime.Contributed.Duration += elapsed
+			} else {
+				status.NodeTime.Shared.Duration += elapsed
+			}
+		}
+	}
+	entitlement, err := c.entitlement(ctx, tenant)
+	if err != nil {
+		return corev1alpha.TenantUsageStatus{}, err
+	}
+	// The requests stand for the consumption where the actual usage is unknown
+	consumption := status.Requests[corev1.ResourceCPU]
+	if status.Usage != nil {
+		consumption = status.Usage[corev1.ResourceCPU]
+	}
+	previous := status.FairShare
+	if status.LastSampled == nil {
+		previous = nil
+	}
+	status.FairShare = fairShare(previous, consumption, entitlement, elapsed)
+	status.LastSampled = &now
+
+	updated, err := c.edgenetclientset.CoreV1alpha().TenantUsages().UpdateStatus(ctx, tenantUsageCopy, metav1.UpdateOptions{})
+	if err != nil {
+		return corev1alpha.TenantUsageStatus{}, err
+	}
+	// etcd only holds the last sample, the archive keeps the history of the accounting
+	if err := storage.Archive(ctx, storage.KindTenantUsage, updated.GetName(), tenant.GetName(), now.Time, updated); err != nil {
+		klog.ErrorS(err, "Couldn't archive the usage sample", "tenant", klog.KObj(tenant))
+	}
+	return updated.Status, nil
+}
+
+// tenantUsage returns the usage record of the tenant, it is created along with the tenant's first sample
+// and goes away with the tenant
+func (c *Controller) tenantUsage(ctx context.Context, tenant *corev1alpha.Tenant) (*corev1alpha.TenantUsage, error) {
+	tenantUsage, err := c.edgenetclientset.CoreV1alpha().TenantUsages().Get(ctx, tenant.GetName(), metav1.GetOptions{})
+	if err == nil || !errors.IsNotFound(err) {
+		return tenantUsage, err
+	}
+	tenantUsage = &corev1alpha.TenantUsage{ObjectMeta: metav1.ObjectMeta{Name: tenant.GetName(),
+		OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(tenant, corev1alpha.SchemeGroupVersion.WithKind("Tenant"))},
+		Labels:          map[string]string{"edge-net.io/generated": "true", "edge-net.io/tenant": tenant.GetName()}},
+		Spec: corev1alpha.TenantUsageSpec{Tenant: tenant.GetName()}}
+	return c.edgenetclientset.CoreV1alpha().TenantUsages().Create(ctx, tenantUsage, metav1.CreateOptions{})
+}
+
+// contributedNodes returns the names of the nodes that join the cluster through node contributions
+func (c *Controller) contributedNodes(ctx context.Context) (map[string]bool, error) {
+	nodeContributions, err := c.edgenetclientset.CoreV1alpha().NodeContributions().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	nodes := map[string]bool{}
+	for _, nodeContribution := range nodeContributions.Items {
+		nodes[fmt.Sprintf("%s.edge-net.io", nodeContribution.GetName())] = true
+	}
+	return nodes, nil
+}
+
+// podMetricsList is the part of the metrics server's pod metrics that the accounting reads
+type podMetricsList struct {
+	Items []struct {
+		metav1.ObjectMeta `json:"metadata"`
+		Containers        []struct {
+			Usage corev1.ResourceList `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+// metricsServer queries the metrics server for the usage of the pods
+func (c *Controller) metricsServer(ctx context.Context) (map[string]corev1.ResourceList, error) {
+	restClient := c.kubeclientset.Discovery().RESTClient()
+	if restClient == nil {
+		return nil, fmt.Errorf("no client for the metrics server")
+	}
+	raw, err := restClient.Get().AbsPath(podMetricsPath).DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := podMetricsList{}
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, err
+	}
+	usage := map[string]corev1.ResourceList{}
+	for _, pod := range list.Items {
+		if _, ok := usage[pod.GetNamespace()]; !ok {
+			usage[pod.GetNamespace()] = corev1.ResourceList{}
+		}
+		for _, container := range pod.Containers {
+			addResources(usage[pod.GetNamespace()], container.Usage)
+		}
+	}
+	return usage, nil
+}
+
+// podRequests returns the resources requested by the pod, an init container runs alone so the pod
+// requests the most of its containers together or any of its init containers
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResources(requests, container.Resources.Requests)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if current, ok := requests[name]; !ok || quantity.Cmp(current) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	return requests
+}
+
+func addResources(total, resources corev1.ResourceList) {
+	for name, quantity := range resources {
+		sum := total[name]
+		sum.Add(quantity)
+		total[name] = sum
+	}
+}
@@ -0,0 +1,230 @@
+package tenantusage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/storage"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+// newController returns a controller with a tenant that runs a pod on a contributed node and one
+// on a node of the cluster, the metrics server reports the usage given
+func newController(t *testing.T, podUsage map[string]corev1.ResourceList) *Controller {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	c := &Controller{
+		kubeclientset:    testclient.NewSimpleClientset(),
+		edgenetclientset: edgenettestclient.NewSimpleClientset(),
+		tenantsLister:    listers.NewTenantLister(indexer),
+		usages:           map[string]corev1alpha.TenantUsageStatus{},
+	}
+	c.podMetrics = func(ctx context.Context) (map[string]corev1.ResourceList, error) {
+		if podUsage == nil {
+			return nil, fmt.Errorf("no metrics server")
+		}
+		return podUsage, nil
+	}
+
+	tenant := &corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "edgenet", UID: "tenant-uid"}, Spec: corev1alpha.TenantSpec{Enabled: true}}
+	util.OK(t, indexer.Add(tenant))
+	nodeContribution := &corev1alpha.NodeContribution{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+	_, err := c.edgenetclientset.CoreV1alpha().NodeContributions().Create(context.TODO(), nodeContribution, metav1.CreateOptions{})
+	util.OK(t, err)
+	namespaces := []*corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "edgenet", Labels: map[string]string{"edge-net.io/tenant": "edgenet"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "lab-1a2b3c", Labels: map[string]string{"edge-net.io/tenant": "edgenet"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{"edge-net.io/tenant": "other"}}},
+	}
+	for _, namespace := range namespaces {
+		_, err := c.kubeclientset.CoreV1().Namespaces().Create(context.TODO(), namespace, metav1.CreateOptions{})
+		util.OK(t, err)
+	}
+	pods := []*corev1.Pod{
+		newPod("edgenet", "web", "node-1.edge-net.io", corev1.PodRunning, "500m", "1Gi"),
+		newPod("lab-1a2b3c", "experiment", "master.edgenet", corev1.PodRunning, "250m", "512Mi"),
+		newPod("lab-1a2b3c", "done", "master.edgenet", corev1.PodSucceeded, "1", "1Gi"),
+		newPod("other", "web", "master.edgenet", corev1.PodRunning, "1", "1Gi"),
+	}
+	for _, pod := range pods {
+		_, err := c.kubeclientset.CoreV1().Pods(pod.GetNamespace()).Create(context.TODO(), pod, metav1.CreateOptions{})
+		util.OK(t, err)
+	}
+	return c
+}
+
+func newPod(namespace, name, node string, phase corev1.PodPhase, cpu, memory string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: corev1.PodSpec{NodeName: node, Containers: []corev1.Container{{Name: name,
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			}}}}},
+		Status: corev1.PodStatus{Phase: phase}}
+}
+
+func TestAccount(t *testing.T) {
+	c := newController(t, map[string]corev1.ResourceList{
+		"edgenet": {corev1.ResourceCPU: resource.MustParse("100m")},
+		"other":   {corev1.ResourceCPU: resource.MustParse("900m")},
+	})
+	c.account(context.TODO())
+
+	tenantUsage, err := c.edgenetclientset.CoreV1alpha().TenantUsages().Get(context.TODO(), "edgenet", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, "edgenet", tenantUsage.Spec.Tenant)
+	util.Equals(t, "Tenant", tenantUsage.GetOwnerReferences()[0].Kind)
+	util.Equals(t, 2, tenantUsage.Status.Pods)
+	cpu := tenantUsage.Status.Requests[corev1.ResourceCPU]
+	util.Equals(t, int64(750), cpu.MilliValue())
+	memory := tenantUsage.Status.Requests[corev1.ResourceMemory]
+	util.Equals(t, int64(1536*1024*1024), memory.Value())
+	cpu = tenantUsage.Status.Usage[corev1.ResourceCPU]
+	util.Equals(t, int64(100), cpu.MilliValue())
+	// There is no node time before the second sample
+	util.Equals(t, time.Duration(0), tenantUsage.Status.NodeTime.Contributed.Duration)
+	util.Assert(t, tenantUsage.Status.LastSampled != nil, "no sample time")
+
+	t.Run("node time", func(t *testing.T) {
+		tenantUsage.Status.LastSampled = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+		_, err := c.edgenetclientset.CoreV1alpha().TenantUsages().UpdateStatus(context.TODO(), tenantUsage, metav1.UpdateOptions{})
+		util.OK(t, err)
+		c.account(context.TODO())
+		nodeTime := c.usages["edgenet"].NodeTime
+		util.Assert(t, nodeTime.Contributed.Duration >= time.Minute && nodeTime.Contributed.Duration < 2*time.Minute, "contributed node time is %s", nodeTime.Contributed.Duration)
+		util.Assert(t, nodeTime.Shared.Duration >= time.Minute && nodeTime.Shared.Duration < 2*time.Minute, "shared node time is %s", nodeTime.Shared.Duration)
+	})
+	t.Run("gap", func(t *testing.T) {
+		before := c.usages["edgenet"].NodeTime.Shared.Duration
+		tenantUsage, err := c.edgenetclientset.CoreV1alpha().TenantUsages().Get(context.TODO(), "edgenet", metav1.GetOptions{})
+		util.OK(t, err)
+		tenantUsage.Status.LastSampled = &metav1.Time{Time: time.Now().Add(-24 * time.Hour)}
+		_, err = c.edgenetclientset.CoreV1alpha().TenantUsages().UpdateStatus(context.TODO(), tenantUsage, metav1.UpdateOptions{})
+		util.OK(t, err)
+		c.account(context.TODO())
+		util.Equals(t, before+2*Interval, c.usages["edgenet"].NodeTime.Shared.Duration)
+	})
+	t.Run("archive", func(t *testing.T) {
+		store := storage.NewMemoryStore()
+		storage.Default = store
+		defer func() { storage.Default = nil }()
+		c.account(context.TODO())
+		c.account(context.TODO())
+		// Each sample is archived, the last one stays in etcd as well
+		records, err := store.List(context.TODO(), storage.KindTenantUsage, "edgenet")
+		util.OK(t, err)
+		util.Equals(t, 2, len(records))
+		archived := corev1alpha.TenantUsage{}
+		util.OK(t, json.Unmarshal(records[0].Object, &archived))
+		util.Equals(t, c.usages["edgenet"].Pods, archived.Status.Pods)
+	})
+}
+
+func TestAccountWithoutMetricsServer(t *testing.T) {
+	c := newController(t, nil)
+	c.account(context.TODO())
+	util.Equals(t, 2, c.usages["edgenet"].Pods)
+	util.Equals(t, 0, len(c.usages["edgenet"].Usage))
+}
+
+func TestPodRequests(t *testing.T) {
+	pod := newPod("edgenet", "web", "", corev1.PodRunning, "500m", "1Gi")
+	pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: "sidecar",
+		Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}}})
+	pod.Spec.InitContainers = []corev1.Container{{Name: "init",
+		Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("128Mi")}}}}
+	requests := podRequests(pod)
+	cpu, memory := requests[corev1.ResourceCPU], requests[corev1.ResourceMemory]
+	util.Equals(t, int64(1000), cpu.MilliValue())
+	util.Equals(t, int64(1024*1024*1024), memory.Value())
+}
+
+func TestMetrics(t *testing.T) {
+	c := &Controller{usages: map[string]corev1alpha.TenantUsageStatus{
+		"edgenet": {Pods: 2,
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("750m"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+			NodeTime: corev1alpha.NodeTime{Contributed: metav1.Duration{Duration: 90 * time.Minute}}},
+	}}
+	expected := `# HELP edgenet_tenant_pods Number of running pods of the tenant.
+# TYPE edgenet_tenant_pods gauge
+edgenet_tenant_pods{tenant="edgenet"} 2
+# HELP edgenet_tenant_resource_requests Resources requested by the running pods of the tenant, in cores for cpu and in bytes otherwise.
+# TYPE edgenet_tenant_resource_requests gauge
+edgenet_tenant_resource_requests{resource="cpu",tenant="edgenet"} 0.75
+edgenet_tenant_resource_requests{resource="memory",tenant="edgenet"} 1073741824
+# HELP edgenet_tenant_node_hours_total Time the nodes spent running the pods of the tenant, by kind of node.
+# TYPE edgenet_tenant_node_hours_total counter
+edgenet_tenant_node_hours_total{node="contributed",tenant="edgenet"} 1.5
+edgenet_tenant_node_hours_total{node="shared",tenant="edgenet"} 0
+`
+	util.OK(t, testutil.CollectAndCompare(c, strings.NewReader(expected),
+		"edgenet_tenant_pods", "edgenet_tenant_resource_requests", "edgenet_tenant_node_hours_total"))
+}
+
+func TestFairShare(t *testing.T) {
+	entitlement := resource.MustParse("1")
+	cases := map[string]struct {
+		previous *corev1alpha.FairShare
+		current  string
+		elapsed  time.Duration
+		share    int64
+		penalty  int32
+	}{
+		"first sample":         {nil, "500m", 0, 50, 0},
+		"over the entitlement": {nil, "1200m", 0, 120, 100},
+		"far over":             {nil, "4", 0, 400, MaxPenalty},
+		"decayed by half":      {&corev1alpha.FairShare{Consumption: resource.MustParse("2")}, "0", HalfLife, 100, 0},
+		"recent sample":        {&corev1alpha.FairShare{Consumption: resource.MustParse("2")}, "0", 0, 200, MaxPenalty},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			fairShare := fairShare(tc.previous, resource.MustParse(tc.current), entitlement, tc.elapsed)
+			util.Equals(t, tc.share, fairShare.Share)
+			util.Equals(t, tc.penalty, fairShare.Penalty)
+		})
+	}
+	t.Run("no entitlement", func(t *testing.T) {
+		util.Assert(t, fairShare(nil, resource.MustParse("1"), resource.Quantity{}, 0) == nil, "fair share without entitlement")
+	})
+	t.Run("rounded penalty", func(t *testing.T) {
+		util.Equals(t, int32(50), penalty(119))
+		util.Equals(t, int32(0), penalty(109))
+	})
+	t.Run("quota", func(t *testing.T) {
+		c := newController(t, map[string]corev1.ResourceList{"edgenet": {corev1.ResourceCPU: resource.MustParse("3")}})
+		tenantResourceQuota := &corev1alpha.TenantResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: "edgenet"},
+			Spec: corev1alpha.TenantResourceQuotaSpec{Claim: map[string]corev1alpha.ResourceTuning{
+				"initial": {ResourceList: map[corev1.ResourceName]resource.Quantity{corev1.ResourceCPU: resource.MustParse("2")}},
+			}}}
+		_, err := c.edgenetclientset.CoreV1alpha().TenantResourceQuotas().Create(context.TODO(), tenantResourceQuota, metav1.CreateOptions{})
+		util.OK(t, err)
+		c.account(context.TODO())
+		fairShare := c.usages["edgenet"].FairShare
+		util.Assert(t, fairShare != nil, "no fair share")
+		util.Equals(t, int64(150), fairShare.Share)
+		util.Equals(t, int32(250), fairShare.Penalty)
+	})
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantusage
+
+import (
+	"context"
+	"math"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HalfLife is the age at which a sample weighs half as much in the recent consumption of a tenant
+var HalfLife = 24 * time.Hour
+
+// MaxPenalty is the most weight the fair share takes off the priority of a tenant
+var MaxPenalty int32 = 500
+
+const (
+	// penaltyPerPercent is the weight taken for each percent of consumption over the entitlement
+	penaltyPerPercent = 5
+	// penaltyStep rounds the penalty down, the priority class is recreated each time it changes
+	penaltyStep = 50
+)
+
+// entitlement returns the CPU quota of the tenant, zero if it has none
+func (c *Controller) entitlement(ctx context.Context, tenant *corev1alpha.Tenant) (resource.Quantity, error) {
+	tenantResourceQuota, err := c.edgenetclientset.CoreV1alpha().TenantResourceQuotas().Get(ctx, tenant.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return resource.Quantity{}, nil
+		}
+		return resource.Quantity{}, err
+	}
+	_, quota := tenantResourceQuota.Fetch()
+	return quota[corev1.ResourceCPU], nil
+}
+
+// fairShare folds the current CPU consumption into the recent one, each sample weighs less as
+// time passes so that a tenant recovers its priority once its consumption drops
+func fairShare(previous *corev1alpha.FairShare, current, entitlement resource.Quantity, elapsed time.Duration) *corev1alpha.FairShare {
+	if entitlement.MilliValue() <= 0 {
+		return nil
+	}
+	consumption := float64(current.MilliValue())
+	if previous != nil {
+		decay := math.Exp2(-float64(elapsed) / float64(HalfLife))
+		consumption = float64(previous.Consumption.MilliValue())*decay + consumption*(1-decay)
+	}
+	share := int64(consumption * 100 / float64(entitlement.MilliValue()))
+	return &corev1alpha.FairShare{
+		Consumption: *resource.NewMilliQuantity(int64(math.Round(consumption)), resource.DecimalSI),
+		Entitlement: entitlement.DeepCopy(),
+		Share:       share,
+		Penalty:     penalty(share),
+	}
+}
+
+// penalty grows with the share over the entitlement, the light tenants are not penalized
+func penalty(share int64) int32 {
+	if share <= 100 {
+		return 0
+	}
+	excess := (share - 100) * penaltyPerPercent
+	if excess >= int64(MaxPenalty) {
+		return MaxPenalty
+	}
+	return int32(excess / penaltyStep * penaltyStep)
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantusage
+
+import (
+	"sort"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var (
+	podsDesc     = prometheus.NewDesc("edgenet_tenant_pods", "Number of running pods of the tenant.", []string{"tenant"}, nil)
+	requestsDesc = prometheus.NewDesc("edgenet_tenant_resource_requests",
+		"Resources requested by the running pods of the tenant, in cores for cpu and in bytes otherwise.", []string{"tenant", "resource"}, nil)
+	usageDesc = prometheus.NewDesc("edgenet_tenant_resource_usage",
+		"Resources used by the running pods of the tenant, in cores for cpu and in bytes otherwise.", []string{"tenant", "resource"}, nil)
+	fairShareDesc = prometheus.NewDesc("edgenet_tenant_fair_share", "Recent CPU consumption of the tenant as a percentage of its quota.", []string{"tenant"}, nil)
+	penaltyDesc   = prometheus.NewDesc("edgenet_tenant_priority_penalty", "Weight taken off the priority of the tenant for its consumption over its quota.", []string{"tenant"}, nil)
+	nodeHoursDesc = prometheus.NewDesc("edgenet_tenant_node_hours_total", "Time the nodes spent running the pods of the tenant, by kind of node.", []string{"tenant", "node"}, nil)
+)
+
+// Describe sends the descriptors of the metrics of the tenants
+func (c *Controller) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{podsDesc, requestsDesc, usageDesc, fairShareDesc, penaltyDesc, nodeHoursDesc} {
+		ch <- desc
+	}
+}
+
+// Collect exports the last sample of each tenant, the dashboards compute the rates and the shares
+// from these series
+func (c *Controller) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	for tenant, usage := range c.usages {
+		ch <- prometheus.MustNewConstMetric(podsDesc, prometheus.GaugeValue, float64(usage.Pods), tenant)
+		collectResources(ch, requestsDesc, tenant, usage.Requests)
+		collectResources(ch, usageDesc, tenant, usage.Usage)
+		if fairShare := usage.FairShare; fairShare != nil {
+			ch <- prometheus.MustNewConstMetric(fairShareDesc, prometheus.GaugeValue, float64(fairShare.Share), tenant)
+			ch <- prometheus.MustNewConstMetric(penaltyDesc, prometheus.GaugeValue, float64(fairShare.Penalty), tenant)
+		}
+		ch <- prometheus.MustNewConstMetric(nodeHoursDesc, prometheus.CounterValue, usage.NodeTime.Contributed.Hours(), tenant, "contributed")
+		ch <- prometheus.MustNewConstMetric(nodeHoursDesc, prometheus.CounterValue, usage.NodeTime.Shared.Hours(), tenant, "shared")
+	}
+}
+
+func collectResources(ch chan<- prometheus.Metric, desc *prometheus.Desc, tenant string, resources corev1.ResourceList) {
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value(resources[corev1.ResourceName(name)]), tenant, name)
+	}
+}
+
+// value returns the quantity as a float, the millis keep the fractions of cores
+func value(quantity resource.Quantity) float64 {
+	return float64(quantity.MilliValue()) / 1000
+}
@@ -0,0 +1,567 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	federationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/federation/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/federation/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/federation/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const controllerAgentName = "cluster-controller"
+
+// Definitions of the state of the cluster resource
+const (
+	successSynced            = "Synced"
+	messageResourceSynced    = "Cluster synced successfully"
+	successReady             = "Ready"
+	messageReady             = "Cluster replies to the heartbeats"
+	failureRole              = "Invalid Role"
+	messageRoleInvalid       = "Role must be either Manager or Workload"
+	failureBootstrap         = "Bootstrap Failed"
+	messageBootstrapNotFound = "Bootstrap secret is not found or contains no token"
+	failureUnreachable       = "Unreachable"
+	messageUnreachable       = "API server of the cluster is unreachable"
+	failureExchange          = "Exchange Failed"
+	messageExchangeFailed    = "Service account credentials could not be exchanged"
+	messageTokenPending      = "Waiting for the token of the service account to be issued"
+	ready                    = "Ready"
+	pending                  = "Pending"
+	unreachable              = "Unreachable"
+	failure                  = "Failure"
+)
+
+// Roles of a cluster in the federation
+const (
+	roleManager  = "Manager"
+	roleWorkload = "Workload"
+)
+
+// The service account that the federation uses in a remote cluster. Its credentials are kept in the
+// EdgeNet namespace in a secret named after the cluster and labeled with the role of the cluster.
+const (
+	federationNamespace = "edgenet"
+	serviceAccountName  = "edgenet-federation"
+	tokenSecretName     = "edgenet-federation-token"
+	clusterRoleName     = "edgenet:federation"
+	kubeconfigKey       = "kubeconfig"
+)
+
+// ClientFunc builds the clientset of a remote cluster out of its REST config
+type ClientFunc func(config *rest.Config) (kubernetes.Interface, error)
+
+// NewClient creates the Kubernetes clientset of a remote cluster
+func NewClient(config *rest.Config) (kubernetes.Interface, error) {
+	bootstrap.ConfigureReadOnly(config)
+	return kubernetes.NewForConfig(config)
+}
+
+// Controller is the controller implementation for Cluster resources
+type Controller struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+	// edgenetclientset is a clientset for the EdgeNet API groups
+	edgenetclientset clientset.Interface
+	// clusterClient connects to the remote clusters
+	clusterClient ClientFunc
+	// heartbeat is the interval between two health checks of a cluster
+	heartbeat time.Duration
+
+	clustersLister listers.ClusterLister
+	clustersSynced cache.InformerSynced
+
+	// workqueue is a rate limited work queue. This is used to queue work to be
+	// processed instead of performing it as soon as a change happens. This
+	// means we can ensure we only process a fixed amount of resources at a
+	// time, and makes it easy to ensure we are never processing the same item
+	// simultaneously in two different workers.
+	workqueue workqueue.RateLimitingInterface
+	// recorder is an event recorder for recording Event resources to the
+	// Kubernetes API.
+	recorder record.EventRecorder
+}
+
+// NewController returns a new controller
+func NewController(
+	kubeclientset kubernetes.Interface,
+	edgenetclientset clientset.Interface,
+	clusterClient ClientFunc,
+	heartbeat time.Duration,
+	clusterInformer informers.ClusterInformer) *Controller {
+
+	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
+	klog.V(4).InfoS("Creating event broadcaster")
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartStructuredLogging(0)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+
+	controller := &Controller{
+		kubeclientset:    kubeclientset,
+		edgenetclientset: edgenetclientset,
+		clusterClient:    clusterClient,
+		heartbeat:        heartbeat,
+		clustersLister:   clusterInformer.Lister(),
+		clustersSynced:   clusterInformer.Informer().HasSynced,
+		workqueue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Clusters"),
+		recorder:         recorder,
+	}
+
+	klog.V(4).InfoS("Setting up event handlers")
+	// Set up an event handler for when Cluster resources change. Deletions need no handling,
+	// as the credentials of a cluster are owned by the cluster and garbage collected along with it.
+	clusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueCluster,
+		UpdateFunc: func(old, new interface{}) {
+			newObj := new.(*federationv1alpha.Cluster)
+			oldObj := old.(*federationv1alpha.Cluster)
+			// The heartbeats are scheduled by the workers, status updates are skipped
+			if !reflect.DeepEqual(newObj.Spec, oldObj.Spec) {
+				controller.enqueueCluster(new)
+			}
+		},
+	})
+
+	return controller
+}
+
+// Run will set up the event handlers for the types of cluster, as well
+// as syncing informer caches and starting workers. It will block until stopCh
+// is closed, at which point it will shutdown the workqueue and wait for
+// workers to finish processing their current work items.
+func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting Cluster controller")
+
+	klog.V(4).InfoS("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh,
+		c.clustersSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	klog.V(4).InfoS("Starting workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	klog.V(4).InfoS("Started workers")
+	<-stopCh
+	klog.V(4).InfoS("Shutting down workers")
+
+	return nil
+}
+
+// runWorker is a long-running function that will continually call the
+// processNextWorkItem function in order to read and process a message on the
+// workqueue.
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+// processNextWorkItem will read a single work item off the workqueue and
+// attempt to process it, by calling the syncHandler.
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.workqueue.Done(obj)
+		var key string
+		var ok bool
+
+		if key, ok = obj.(string); !ok {
+			c.workqueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			return nil
+		}
+		if err := c.syncHandler(ctx, key); err != nil {
+			c.workqueue.AddRateLimited(key)
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
+		}
+		c.workqueue.Forget(obj)
+		klog.V(4).InfoS("Successfully synced", "key", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+
+	return true
+}
+
+// syncHandler compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the Cluster
+// resource with the current status of the resource, and schedules the next heartbeat.
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	cluster, err := c.clustersLister.Get(name)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("cluster '%s' in work queue no longer exists", key))
+			return nil
+		}
+
+		return err
+	}
+
+	c.processCluster(ctx, cluster.DeepCopy())
+	c.recorder.Event(cluster, corev1.EventTypeNormal, successSynced, messageResourceSynced)
+	c.workqueue.AddAfter(key, c.heartbeat)
+	return nil
+}
+
+// enqueueCluster takes a Cluster resource and converts it into a namespace/name
+// string which is then put onto the work queue. This method should *not* be
+// passed resources of any type other than Cluster.
+func (c *Controller) enqueueCluster(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+func (c *Controller) processCluster(ctx context.Context, clusterCopy *federationv1alpha.Cluster) {
+	oldStatus := *clusterCopy.Status.DeepCopy()
+	statusUpdate := func() {
+		if !reflect.DeepEqual(oldStatus, clusterCopy.Status) {
+			if _, err := c.edgenetclientset.FederationV1alpha().Clusters().UpdateStatus(ctx, clusterCopy, metav1.UpdateOptions{}); err != nil {
+				klog.ErrorS(err, "Couldn't update the status of the cluster", "cluster", klog.KObj(clusterCopy))
+			}
+		}
+	}
+	defer statusUpdate()
+	setState := func(state, reason, message string) {
+		if state != oldStatus.State {
+			c.recorder.Event(clusterCopy, corev1.EventTypeWarning, reason, message)
+		}
+		clusterCopy.Status.State = state
+		clusterCopy.Status.Message = message
+	}
+
+	if clusterCopy.Spec.Role != roleManager && clusterCopy.Spec.Role != roleWorkload {
+		setState(failure, failureRole, messageRoleInvalid)
+		return
+	}
+
+	kubeconfig, err := c.getCredentials(ctx, clusterCopy)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't get the credentials", "cluster", klog.KObj(clusterCopy))
+	}
+	if kubeconfig == nil {
+		// The connectivity is validated with the bootstrap token before the credentials are exchanged
+		token, err := c.getBootstrapToken(ctx, clusterCopy)
+		if err != nil {
+			klog.ErrorS(err, "Couldn't get the bootstrap token", "cluster", klog.KObj(clusterCopy))
+			setState(failure, failureBootstrap, messageBootstrapNotFound)
+			return
+		}
+		bootstrapConfig := &rest.Config{Host: clusterCopy.Spec.Endpoint, BearerToken: token,
+			TLSClientConfig: rest.TLSClientConfig{CAData: clusterCopy.Spec.CABundle}}
+		clusterclientset, err := c.clusterClient(bootstrapConfig)
+		if err != nil {
+			klog.ErrorS(err, "Couldn't build the client of the cluster", "cluster", klog.KObj(clusterCopy))
+			setState(unreachable, failureUnreachable, messageUnreachable)
+			return
+		}
+		if _, err := clusterclientset.Discovery().ServerVersion(); err != nil {
+			klog.ErrorS(err, "Couldn't reach the cluster", "cluster", klog.KObj(clusterCopy))
+			setState(unreachable, failureUnreachable, messageUnreachable)
+			return
+		}
+		serviceAccountToken, err := exchangeCredentials(ctx, clusterclientset, clusterCopy.Spec.Role)
+		if err != nil {
+			klog.ErrorS(err, "Couldn't exchange the credentials", "cluster", klog.KObj(clusterCopy))
+			setState(failure, failureExchange, messageExchangeFailed)
+			return
+		}
+		if serviceAccountToken == "" {
+			clusterCopy.Status.State = pending
+			clusterCopy.Status.Message = messageTokenPending
+			return
+		}
+		if kubeconfig, err = generateKubeconfig(clusterCopy, serviceAccountToken); err == nil {
+			err = c.storeCredentials(ctx, clusterCopy, kubeconfig)
+		}
+		if err != nil {
+			klog.ErrorS(err, "Couldn't store the credentials", "cluster", klog.KObj(clusterCopy))
+			setState(failure, failureExchange, messageExchangeFailed)
+			return
+		}
+	}
+
+	// Heartbeats go through the exchanged credentials to make sure they keep granting access
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't parse the kubeconfig", "cluster", klog.KObj(clusterCopy))
+		setState(failure, failureExchange, messageExchangeFailed)
+		return
+	}
+	clusterclientset, err := c.clusterClient(config)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't build the client of the cluster", "cluster", klog.KObj(clusterCopy))
+		setState(unreachable, failureUnreachable, messageUnreachable)
+		return
+	}
+	version, err := clusterclientset.Discovery().ServerVersion()
+	if err != nil {
+		klog.ErrorS(err, "Couldn't reach the cluster", "cluster", klog.KObj(clusterCopy))
+		setState(unreachable, failureUnreachable, messageUnreachable)
+		return
+	}
+	if oldStatus.State != ready {
+		c.recorder.Event(clusterCopy, corev1.EventTypeNormal, successReady, messageReady)
+	}
+	now := metav1.Now()
+	clusterCopy.Status.State = ready
+	clusterCopy.Status.Message = messageReady
+	clusterCopy.Status.Version = version.GitVersion
+	clusterCopy.Status.LastHeartbeat = &now
+	if clusterCopy.Spec.Role == roleWorkload {
+		if allocatable, err := allocatableResources(ctx, clusterclientset); err == nil {
+			clusterCopy.Status.Allocatable = allocatable
+		} else {
+			klog.ErrorS(err, "Couldn't sum up the allocatable resources", "cluster", klog.KObj(clusterCopy))
+		}
+	}
+}
+
+// allocatableResources sums up the resources allocatable on the ready and schedulable nodes of a cluster
+func allocatableResources(ctx context.Context, clusterclientset kubernetes.Interface) (corev1.ResourceList, error) {
+	nodeRaw, err := clusterclientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	allocatable := corev1.ResourceList{}
+	for _, nodeRow := range nodeRaw.Items {
+		if nodeRow.Spec.Unschedulable {
+			continue
+		}
+		nodeReady := false
+		for _, condition := range nodeRow.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+				nodeReady = true
+			}
+		}
+		if !nodeReady {
+			continue
+		}
+		for name, quantity := range nodeRow.Status.Allocatable {
+			total := allocatable[name]
+			total.Add(quantity)
+			allocatable[name] = total
+		}
+	}
+	return allocatable, nil
+}
+
+// getBootstrapToken returns the token in the bootstrap secret of the cluster
+func (c *Controller) getBootstrapToken(ctx context.Context, clusterCopy *federationv1alpha.Cluster) (string, error) {
+	secretRef := clusterCopy.Spec.BootstrapSecret
+	namespace := secretRef.Namespace
+	if namespace == "" {
+		namespace = federationNamespace
+	}
+	key := secretRef.Key
+	if key == "" {
+		key = corev1.ServiceAccountTokenKey
+	}
+	secret, err := c.kubeclientset.CoreV1().Secrets(namespace).Get(ctx, secretRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	token, ok := secret.Data[key]
+	if !ok || len(token) == 0 {
+		return "", fmt.Errorf("secret %s/%s has no %s key", namespace, secretRef.Name, key)
+	}
+	return string(token), nil
+}
+
+// getCredentials returns the exchanged kubeconfig of the cluster, or nil if the credentials are
+// yet to be exchanged or no longer match the endpoint of the cluster
+func (c *Controller) getCredentials(ctx context.Context, clusterCopy *federationv1alpha.Cluster) ([]byte, error) {
+	secret, err := c.kubeclientset.CoreV1().Secrets(federationNamespace).Get(ctx, clusterCopy.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	kubeconfig, ok := secret.Data[kubeconfigKey]
+	if !ok || secret.GetLabels()["edge-net.io/federation"] != strings.ToLower(clusterCopy.Spec.Role) {
+		return nil, nil
+	}
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	currentContext, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return nil, nil
+	}
+	server, ok := config.Clusters[currentContext.Cluster]
+	if !ok || server.Server != clusterCopy.Spec.Endpoint || !bytes.Equal(server.CertificateAuthorityData, clusterCopy.Spec.CABundle) {
+		return nil, nil
+	}
+	return kubeconfig, nil
+}
+
+// storeCredentials keeps the kubeconfig of the cluster in a secret that the cluster owns
+func (c *Controller) storeCredentials(ctx context.Context, clusterCopy *federationv1alpha.Cluster, kubeconfig []byte) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterCopy.GetName(), Namespace: federationNamespace,
+			Labels:          map[string]string{"edge-net.io/federation": strings.ToLower(clusterCopy.Spec.Role)},
+			OwnerReferences: SetAsOwnerReference(clusterCopy)},
+		Data: map[string][]byte{kubeconfigKey: kubeconfig},
+	}
+	if _, err := c.kubeclientset.CoreV1().Secrets(federationNamespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return err
+		}
+		current, err := c.kubeclientset.CoreV1().Secrets(federationNamespace).Get(ctx, secret.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		current.SetLabels(secret.GetLabels())
+		current.SetOwnerReferences(secret.GetOwnerReferences())
+		current.Data = secret.Data
+		if _, err := c.kubeclientset.CoreV1().Secrets(federationNamespace).Update(ctx, current, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exchangeCredentials creates the service account of the federation in a remote cluster, and returns
+// its token once issued. The service account can manage the tenants in a workload cluster,
+// and can read the federation objects in a manager cluster.
+func exchangeCredentials(ctx context.Context, clusterclientset kubernetes.Interface, role string) (string, error) {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: federationNamespace}}
+	if _, err := clusterclientset.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return "", err
+	}
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: serviceAccountName, Namespace: federationNamespace}}
+	if _, err := clusterclientset.CoreV1().ServiceAccounts(federationNamespace).Create(ctx, serviceAccount, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return "", err
+	}
+
+	// Workload clusters receive the tenants and the anchored selective deployments, and report their capacity
+	policyRule := []rbacv1.PolicyRule{{APIGroups: []string{"core.edgenet.io"}, Resources: []string{"tenants", "tenantresourcequotas", "subnamespaces"}, Verbs: []string{"*"}},
+		{APIGroups: []string{"apps.edgenet.io"}, Resources: []string{"selectivedeployments"}, Verbs: []string{"*"}},
+		{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"get", "list", "watch"}}}
+	if role == roleManager {
+		policyRule = []rbacv1.PolicyRule{{APIGroups: []string{"federation.edgenet.io"}, Resources: []string{"*"}, Verbs: []string{"get", "list", "watch"}}}
+	}
+	clusterRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: clusterRoleName}, Rules: policyRule}
+	if _, err := clusterclientset.RbacV1().ClusterRoles().Create(ctx, clusterRole, metav1.CreateOptions{}); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return "", err
+		}
+		current, err := clusterclientset.RbacV1().ClusterRoles().Get(ctx, clusterRoleName, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		current.Rules = clusterRole.Rules
+		if _, err := clusterclientset.RbacV1().ClusterRoles().Update(ctx, current, metav1.UpdateOptions{}); err != nil {
+			return "", err
+		}
+	}
+	clusterRoleBinding := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: clusterRoleName},
+		RoleRef:  rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: clusterRoleName},
+		Subjects: []rbacv1.Subject{{Kind: "ServiceAccount", Name: serviceAccountName, Namespace: federationNamespace}}}
+	if _, err := clusterclientset.RbacV1().ClusterRoleBindings().Create(ctx, clusterRoleBinding, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return "", err
+	}
+
+	// The token controller of the remote cluster fills the secret in asynchronously
+	tokenSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: tokenSecretName, Namespace: federationNamespace,
+		Annotations: map[string]string{corev1.ServiceAccountNameKey: serviceAccountName}}, Type: corev1.SecretTypeServiceAccountToken}
+	if _, err := clusterclientset.CoreV1().Secrets(federationNamespace).Create(ctx, tokenSecret, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return "", err
+	}
+	tokenSecret, err := clusterclientset.CoreV1().Secrets(federationNamespace).Get(ctx, tokenSecretName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	return string(tokenSecret.Data[corev1.ServiceAccountTokenKey]), nil
+}
+
+// generateKubeconfig returns the kubeconfig to reach the cluster with the token of the service account
+func generateKubeconfig(clusterCopy *federationv1alpha.Cluster, token string) ([]byte, error) {
+	config := clientcmdapi.NewConfig()
+	config.Clusters[clusterCopy.GetName()] = &clientcmdapi.Cluster{Server: clusterCopy.Spec.Endpoint, CertificateAuthorityData: clusterCopy.Spec.CABundle}
+	config.AuthInfos[serviceAccountName] = &clientcmdapi.AuthInfo{Token: token}
+	config.Contexts[clusterCopy.GetName()] = &clientcmdapi.Context{Cluster: clusterCopy.GetName(), AuthInfo: serviceAccountName}
+	config.CurrentContext = clusterCopy.GetName()
+	return clientcmd.Write(*config)
+}
+
+// SetAsOwnerReference returns the cluster as owner
+func SetAsOwnerReference(cluster *federationv1alpha.Cluster) []metav1.OwnerReference {
+	// The following section makes cluster become the owner
+	ownerReferences := []metav1.OwnerReference{}
+	newClusterRef := *metav1.NewControllerRef(cluster, federationv1alpha.SchemeGroupVersion.WithKind("Cluster"))
+	takeControl := true
+	newClusterRef.Controller = &takeControl
+	ownerReferences = append(ownerReferences, newClusterRef)
+	return ownerReferences
+}
@@ -0,0 +1,196 @@
+package cluster
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	federationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/federation/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+)
+
+type TestGroup struct {
+	clusterObj federationv1alpha.Cluster
+}
+
+var kubeclientset kubernetes.Interface = testclient.NewSimpleClientset()
+var edgenetclientset versioned.Interface = edgenettestclient.NewSimpleClientset()
+
+// The remote clusters are reachable by their endpoints
+var remoteclientsets = map[string]kubernetes.Interface{
+	"https://paris.edge-net.io:6443": testclient.NewSimpleClientset(),
+	"https://nyc.edge-net.io:6443":   testclient.NewSimpleClientset(),
+}
+
+func fakeClient(config *rest.Config) (kubernetes.Interface, error) {
+	if clusterclientset, ok := remoteclientsets[config.Host]; ok {
+		return clusterclientset, nil
+	}
+	return nil, fmt.Errorf("%s unreachable", config.Host)
+}
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	logrus.SetOutput(ioutil.Discard)
+
+	flag.String("dir", "../../../../..", "Override the directory.")
+	flag.String("smtp-path", "../../../../../configs/smtp_test.yaml", "Set SMTP path.")
+	flag.Parse()
+
+	stopCh := signals.SetupSignalHandler()
+
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, time.Second*30)
+
+	controller := NewController(kubeclientset,
+		edgenetclientset,
+		fakeClient,
+		200*time.Millisecond,
+		edgenetInformerFactory.Federation().V1alpha().Clusters())
+
+	edgenetInformerFactory.Start(stopCh)
+
+	go func() {
+		if err := controller.Run(2, stopCh); err != nil {
+			klog.Fatalf("Error running controller: %s", err.Error())
+		}
+	}()
+
+	bootstrapSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "bootstrap", Namespace: federationNamespace},
+		Data: map[string][]byte{"token": []byte("bootstrap-token")}}
+	kubeclientset.CoreV1().Secrets(federationNamespace).Create(context.TODO(), bootstrapSecret, metav1.CreateOptions{})
+
+	time.Sleep(500 * time.Millisecond)
+
+	os.Exit(m.Run())
+	<-stopCh
+}
+
+// Init syncs the test group
+func (g *TestGroup) Init() {
+	clusterObj := federationv1alpha.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Cluster",
+			APIVersion: "federation.edgenet.io/v1alpha",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "paris",
+			UID:  "paris",
+		},
+		Spec: federationv1alpha.ClusterSpec{
+			Role:            roleWorkload,
+			Endpoint:        "https://paris.edge-net.io:6443",
+			CABundle:        []byte("ca"),
+			BootstrapSecret: federationv1alpha.SecretReference{Name: "bootstrap"},
+		},
+	}
+	g.clusterObj = clusterObj
+}
+
+func TestExchange(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	clusterTest := g.clusterObj.DeepCopy()
+	remoteclientset := remoteclientsets[clusterTest.Spec.Endpoint]
+
+	edgenetclientset.FederationV1alpha().Clusters().Create(context.TODO(), clusterTest, metav1.CreateOptions{})
+	time.Sleep(time.Millisecond * 500)
+	// The token of the service account is not issued yet
+	cluster, err := edgenetclientset.FederationV1alpha().Clusters().Get(context.TODO(), clusterTest.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, pending, cluster.Status.State)
+	_, err = remoteclientset.CoreV1().ServiceAccounts(federationNamespace).Get(context.TODO(), serviceAccountName, metav1.GetOptions{})
+	util.OK(t, err)
+	clusterRole, err := remoteclientset.RbacV1().ClusterRoles().Get(context.TODO(), clusterRoleName, metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, []string{"core.edgenet.io"}, clusterRole.Rules[0].APIGroups)
+
+	tokenSecret, _ := remoteclientset.CoreV1().Secrets(federationNamespace).Get(context.TODO(), tokenSecretName, metav1.GetOptions{})
+	tokenSecret.Data = map[string][]byte{corev1.ServiceAccountTokenKey: []byte("service-account-token")}
+	remoteclientset.CoreV1().Secrets(federationNamespace).Update(context.TODO(), tokenSecret, metav1.UpdateOptions{})
+	time.Sleep(time.Millisecond * 500)
+	cluster, err = edgenetclientset.FederationV1alpha().Clusters().Get(context.TODO(), clusterTest.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, ready, cluster.Status.State)
+	util.Assert(t, cluster.Status.LastHeartbeat != nil, "last heartbeat is not set")
+
+	t.Run("credentials", func(t *testing.T) {
+		secret, err := kubeclientset.CoreV1().Secrets(federationNamespace).Get(context.TODO(), clusterTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, "workload", secret.GetLabels()["edge-net.io/federation"])
+		util.Equals(t, clusterTest.GetName(), secret.GetOwnerReferences()[0].Name)
+		config, err := clientcmd.RESTConfigFromKubeConfig(secret.Data[kubeconfigKey])
+		util.OK(t, err)
+		util.Equals(t, clusterTest.Spec.Endpoint, config.Host)
+		util.Equals(t, "service-account-token", config.BearerToken)
+		util.Equals(t, clusterTest.Spec.CABundle, config.CAData)
+	})
+	t.Run("allocatable", func(t *testing.T) {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "paris-1"},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+				Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}}}
+		remoteclientset.CoreV1().Nodes().Create(context.TODO(), node, metav1.CreateOptions{})
+		notReadyNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "paris-2"},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}}}
+		remoteclientset.CoreV1().Nodes().Create(context.TODO(), notReadyNode, metav1.CreateOptions{})
+		time.Sleep(time.Millisecond * 500)
+		cluster, err := edgenetclientset.FederationV1alpha().Clusters().Get(context.TODO(), clusterTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		allocatable := cluster.Status.Allocatable[corev1.ResourceCPU]
+		util.Equals(t, int64(4), allocatable.Value())
+	})
+	t.Run("heartbeat", func(t *testing.T) {
+		lastHeartbeat := cluster.Status.LastHeartbeat
+		time.Sleep(time.Millisecond * 1500)
+		cluster, err := edgenetclientset.FederationV1alpha().Clusters().Get(context.TODO(), clusterTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Assert(t, lastHeartbeat.Before(cluster.Status.LastHeartbeat), "heartbeat is not renewed")
+	})
+}
+
+func TestFailure(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	cases := map[string]struct {
+		input    func(*federationv1alpha.Cluster)
+		expected string
+	}{
+		"role":        {func(c *federationv1alpha.Cluster) { c.Spec.Role = "Edge" }, messageRoleInvalid},
+		"bootstrap":   {func(c *federationv1alpha.Cluster) { c.Spec.BootstrapSecret.Name = "missing" }, messageBootstrapNotFound},
+		"unreachable": {func(c *federationv1alpha.Cluster) { c.Spec.Endpoint = "https://tokyo.edge-net.io:6443" }, messageUnreachable},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			clusterTest := g.clusterObj.DeepCopy()
+			clusterTest.SetName(k)
+			clusterTest.SetUID(types.UID(k))
+			tc.input(clusterTest)
+			edgenetclientset.FederationV1alpha().Clusters().Create(context.TODO(), clusterTest, metav1.CreateOptions{})
+			time.Sleep(time.Millisecond * 500)
+			cluster, err := edgenetclientset.FederationV1alpha().Clusters().Get(context.TODO(), clusterTest.GetName(), metav1.GetOptions{})
+			util.OK(t, err)
+			util.Equals(t, tc.expected, cluster.Status.Message)
+		})
+	}
+}
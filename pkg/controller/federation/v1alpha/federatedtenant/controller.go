@@ -0,0 +1,551 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package federatedtenant
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	federationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/federation/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	coreinformers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/federation/v1alpha"
+	corelisters "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/federation/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const controllerAgentName = "federatedtenant-controller"
+
+// Definitions of the state of the federatedtenant resource
+const (
+	successSynced            = "Synced"
+	messageResourceSynced    = "Federated Tenant synced successfully"
+	successEstablished       = "Established"
+	messageEstablished       = "Tenant established in all workload clusters"
+	messageClusterSynced     = "Tenant established in the workload cluster"
+	failureNotApproved       = "Not Approved"
+	messageNotApproved       = "Tenant does not exist or is not enabled"
+	failurePropagation       = "Propagation Failed"
+	messagePropagationFailed = "Tenant could not be established in all workload clusters"
+	messageClusterNotFound   = "Workload cluster is not registered"
+	messageKubeconfigInvalid = "Kubeconfig of the workload cluster is invalid"
+	messageClusterFailed     = "Tenant could not be propagated to the workload cluster"
+	established              = "Established"
+	failure                  = "Failure"
+)
+
+// The kubeconfigs of the registered workload clusters are kept in the secrets of the EdgeNet namespace
+// that carry the workload label, one secret per cluster named after it. The cluster controller stores
+// them once it exchanges the credentials of a cluster.
+const (
+	federationNamespace  = "edgenet"
+	workloadClusterLabel = "edge-net.io/federation=workload"
+	kubeconfigKey        = "kubeconfig"
+)
+
+// ClusterClientFunc builds the clientset of a workload cluster out of its kubeconfig
+type ClusterClientFunc func(kubeconfig []byte) (clientset.Interface, error)
+
+// NewClusterClient creates the EdgeNet clientset of a workload cluster
+func NewClusterClient(kubeconfig []byte) (clientset.Interface, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	bootstrap.ConfigureReadOnly(config)
+	return clientset.NewForConfig(config)
+}
+
+// Controller is the controller implementation for Federated Tenant resources
+type Controller struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+	// edgenetclientset is a clientset for the EdgeNet API groups
+	edgenetclientset clientset.Interface
+	// clusterClient connects to the workload clusters
+	clusterClient ClusterClientFunc
+
+	federatedtenantsLister listers.FederatedTenantLister
+	federatedtenantsSynced cache.InformerSynced
+	tenantsLister          corelisters.TenantLister
+	tenantsSynced          cache.InformerSynced
+
+	// workqueue is a rate limited work queue. This is used to queue work to be
+	// processed instead of performing it as soon as a change happens. This
+	// means we can ensure we only process a fixed amount of resources at a
+	// time, and makes it easy to ensure we are never processing the same item
+	// simultaneously in two different workers.
+	workqueue workqueue.RateLimitingInterface
+	// recorder is an event recorder for recording Event resources to the
+	// Kubernetes API.
+	recorder record.EventRecorder
+	// ctx is cancelled at shutdown, it bounds the requests of the event handlers
+	ctx context.Context
+}
+
+// NewController returns a new controller
+func NewController(
+	ctx context.Context,
+	kubeclientset kubernetes.Interface,
+	edgenetclientset clientset.Interface,
+	clusterClient ClusterClientFunc,
+	federatedtenantInformer informers.FederatedTenantInformer,
+	tenantInformer coreinformers.TenantInformer) *Controller {
+
+	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
+	klog.V(4).InfoS("Creating event broadcaster")
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartStructuredLogging(0)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+
+	controller := &Controller{
+		ctx:                    ctx,
+		kubeclientset:          kubeclientset,
+		edgenetclientset:       edgenetclientset,
+		clusterClient:          clusterClient,
+		federatedtenantsLister: federatedtenantInformer.Lister(),
+		federatedtenantsSynced: federatedtenantInformer.Informer().HasSynced,
+		tenantsLister:          tenantInformer.Lister(),
+		tenantsSynced:          tenantInformer.Informer().HasSynced,
+		workqueue:              workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "FederatedTenants"),
+		recorder:               recorder,
+	}
+
+	klog.V(4).InfoS("Setting up event handlers")
+	// Set up an event handler for when Federated Tenant resources change
+	federatedtenantInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueFederatedTenant,
+		UpdateFunc: func(old, new interface{}) {
+			newObj := new.(*federationv1alpha.FederatedTenant)
+			oldObj := old.(*federationv1alpha.FederatedTenant)
+			// Status updates are skipped, periodic resyncs keep the workload clusters in line
+			if newObj.ResourceVersion == oldObj.ResourceVersion || !reflect.DeepEqual(newObj.Spec, oldObj.Spec) {
+				controller.enqueueFederatedTenant(new)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			federatedTenant, ok := obj.(*federationv1alpha.FederatedTenant)
+			if !ok {
+				return
+			}
+			for _, clusterStatus := range federatedTenant.Status.Clusters {
+				if clusterStatus.State == established {
+					controller.withdraw(ctx, clusterStatus.Name, federatedTenant.Spec.Tenant)
+				}
+			}
+		},
+	})
+	// Approved tenants are federated automatically, and the changes on a tenant are propagated
+	tenantInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.handleTenant,
+		UpdateFunc: func(old, new interface{}) {
+			controller.handleTenant(new)
+		},
+	})
+
+	return controller
+}
+
+// Run will set up the event handlers for the types of federated tenant and tenant, as well
+// as syncing informer caches and starting workers. It will block until stopCh
+// is closed, at which point it will shutdown the workqueue and wait for
+// workers to finish processing their current work items.
+func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting Federated Tenant controller")
+
+	klog.V(4).InfoS("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh,
+		c.federatedtenantsSynced,
+		c.tenantsSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	klog.V(4).InfoS("Starting workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	klog.V(4).InfoS("Started workers")
+	<-stopCh
+	klog.V(4).InfoS("Shutting down workers")
+
+	return nil
+}
+
+// runWorker is a long-running function that will continually call the
+// processNextWorkItem function in order to read and process a message on the
+// workqueue.
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+// processNextWorkItem will read a single work item off the workqueue and
+// attempt to process it, by calling the syncHandler.
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.workqueue.Done(obj)
+		var key string
+		var ok bool
+
+		if key, ok = obj.(string); !ok {
+			c.workqueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			return nil
+		}
+		if err := c.syncHandler(ctx, key); err != nil {
+			c.workqueue.AddRateLimited(key)
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
+		}
+		c.workqueue.Forget(obj)
+		klog.V(4).InfoS("Successfully synced", "key", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+
+	return true
+}
+
+// syncHandler compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the Federated Tenant
+// resource with the current status of the resource.
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	federatedTenant, err := c.federatedtenantsLister.Get(name)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("federatedtenant '%s' in work queue no longer exists", key))
+			return nil
+		}
+
+		return err
+	}
+
+	c.processFederatedTenant(ctx, federatedTenant.DeepCopy())
+	c.recorder.Event(federatedTenant, corev1.EventTypeNormal, successSynced, messageResourceSynced)
+	return nil
+}
+
+// enqueueFederatedTenant takes a FederatedTenant resource and converts it into a namespace/name
+// string which is then put onto the work queue. This method should *not* be
+// passed resources of any type other than FederatedTenant.
+func (c *Controller) enqueueFederatedTenant(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+// handleTenant creates the federated tenant of an approved tenant if missing, and enqueues
+// the federated tenants that propagate the tenant
+func (c *Controller) handleTenant(obj interface{}) {
+	tenant, ok := obj.(*corev1alpha.Tenant)
+	if !ok || !tenant.Spec.Enabled {
+		return
+	}
+	if _, err := c.federatedtenantsLister.Get(tenant.GetName()); errors.IsNotFound(err) {
+		federatedTenant := &federationv1alpha.FederatedTenant{
+			ObjectMeta: metav1.ObjectMeta{Name: tenant.GetName(), OwnerReferences: SetAsOwnerReference(tenant)},
+			Spec:       federationv1alpha.FederatedTenantSpec{Tenant: tenant.GetName()},
+		}
+		if _, err := c.edgenetclientset.FederationV1alpha().FederatedTenants().Create(c.ctx, federatedTenant, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			klog.ErrorS(err, "Couldn't create the federated tenant", "federatedTenant", klog.KObj(federatedTenant))
+		}
+	}
+	federatedTenants, err := c.federatedtenantsLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Couldn't list the federated tenants")
+		return
+	}
+	for _, federatedTenant := range federatedTenants {
+		if federatedTenant.Spec.Tenant == tenant.GetName() {
+			c.enqueueFederatedTenant(federatedTenant)
+		}
+	}
+}
+
+func (c *Controller) processFederatedTenant(ctx context.Context, federatedTenantCopy *federationv1alpha.FederatedTenant) {
+	oldStatus := *federatedTenantCopy.Status.DeepCopy()
+	statusUpdate := func() {
+		if !reflect.DeepEqual(oldStatus, federatedTenantCopy.Status) {
+			if _, err := c.edgenetclientset.FederationV1alpha().FederatedTenants().UpdateStatus(ctx, federatedTenantCopy, metav1.UpdateOptions{}); err != nil {
+				klog.ErrorS(err, "Couldn't update the status of the federated tenant", "federatedTenant", klog.KObj(federatedTenantCopy))
+			}
+		}
+	}
+	defer statusUpdate()
+
+	tenant, err := c.tenantsLister.Get(federatedTenantCopy.Spec.Tenant)
+	if err != nil || !tenant.Spec.Enabled {
+		c.recorder.Event(federatedTenantCopy, corev1.EventTypeWarning, failureNotApproved, messageNotApproved)
+		federatedTenantCopy.Status.State = failure
+		federatedTenantCopy.Status.Message = messageNotApproved
+		return
+	}
+	kubeconfigs, err := c.workloadClusters(ctx)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't read the kubeconfigs of the workload clusters")
+		return
+	}
+	clusters := federatedTenantCopy.Spec.Clusters
+	if len(clusters) == 0 {
+		for name := range kubeconfigs {
+			clusters = append(clusters, name)
+		}
+		sort.Strings(clusters)
+	}
+
+	tenantResourceQuota, err := c.edgenetclientset.CoreV1alpha().TenantResourceQuotas().Get(ctx, tenant.GetName(), metav1.GetOptions{})
+	if err != nil {
+		tenantResourceQuota = nil
+	}
+	// Only the subnamespaces in the core namespace are propagated, as the names of the nested
+	// namespaces depend on the cluster in which their parents are created
+	subnamespaces := []corev1alpha.SubNamespace{}
+	if federatedTenantCopy.Spec.SubNamespaces {
+		if subnamespaceRaw, err := c.edgenetclientset.CoreV1alpha().SubNamespaces(tenant.GetName()).List(ctx, metav1.ListOptions{}); err == nil {
+			subnamespaces = subnamespaceRaw.Items
+		}
+	}
+
+	previous := make(map[string]federationv1alpha.ClusterPropagation)
+	for _, clusterStatus := range federatedTenantCopy.Status.Clusters {
+		previous[clusterStatus.Name] = clusterStatus
+	}
+	clusterStatuses := []federationv1alpha.ClusterPropagation{}
+	establishedAll := true
+	for _, name := range clusters {
+		clusterStatus := federationv1alpha.ClusterPropagation{Name: name, State: failure}
+		previousStatus, ok := previous[name]
+		if ok {
+			clusterStatus.LastSync = previousStatus.LastSync
+			delete(previous, name)
+		}
+		if kubeconfig, ok := kubeconfigs[name]; !ok {
+			clusterStatus.Message = messageClusterNotFound
+		} else if clusterclientset, err := c.clusterClient(kubeconfig); err != nil {
+			klog.ErrorS(err, "Couldn't build the client of the cluster", "cluster", name)
+			clusterStatus.Message = messageKubeconfigInvalid
+		} else if err := propagate(ctx, clusterclientset, tenant, tenantResourceQuota, subnamespaces); err != nil {
+			klog.ErrorS(err, "Couldn't propagate the tenant to the cluster", "tenant", klog.KObj(tenant), "cluster", name)
+			clusterStatus.Message = messageClusterFailed
+		} else {
+			clusterStatus.State = established
+			clusterStatus.Message = messageClusterSynced
+			// The sync time is renewed when the cluster recovers so that resyncs do not churn the status
+			if clusterStatus.LastSync == nil || previousStatus.State != established {
+				now := metav1.Now()
+				clusterStatus.LastSync = &now
+			}
+		}
+		if clusterStatus.State != established {
+			establishedAll = false
+		}
+		clusterStatuses = append(clusterStatuses, clusterStatus)
+	}
+	// The tenant is withdrawn from the clusters that are no longer selected
+	for name, clusterStatus := range previous {
+		if clusterStatus.State == established {
+			c.withdraw(ctx, name, tenant.GetName())
+		}
+	}
+	federatedTenantCopy.Status.Clusters = clusterStatuses
+
+	if !establishedAll {
+		c.recorder.Event(federatedTenantCopy, corev1.EventTypeWarning, failurePropagation, messagePropagationFailed)
+		federatedTenantCopy.Status.State = failure
+		federatedTenantCopy.Status.Message = messagePropagationFailed
+		return
+	}
+	c.recorder.Event(federatedTenantCopy, corev1.EventTypeNormal, successEstablished, messageEstablished)
+	federatedTenantCopy.Status.State = established
+	federatedTenantCopy.Status.Message = messageEstablished
+}
+
+// workloadClusters returns the kubeconfigs of the registered workload clusters by name
+func (c *Controller) workloadClusters(ctx context.Context) (map[string][]byte, error) {
+	secretRaw, err := c.kubeclientset.CoreV1().Secrets(federationNamespace).List(ctx, metav1.ListOptions{LabelSelector: workloadClusterLabel})
+	if err != nil {
+		return nil, err
+	}
+	kubeconfigs := make(map[string][]byte)
+	for _, secretRow := range secretRaw.Items {
+		if kubeconfig, ok := secretRow.Data[kubeconfigKey]; ok {
+			kubeconfigs[secretRow.GetName()] = kubeconfig
+		}
+	}
+	return kubeconfigs, nil
+}
+
+// withdraw removes the tenant from a workload cluster, the controllers of the workload cluster
+// clean up the namespaces of the tenant afterwards
+func (c *Controller) withdraw(ctx context.Context, cluster, tenantName string) {
+	kubeconfigs, err := c.workloadClusters(ctx)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't read the kubeconfigs of the workload clusters")
+		return
+	}
+	kubeconfig, ok := kubeconfigs[cluster]
+	if !ok {
+		return
+	}
+	clusterclientset, err := c.clusterClient(kubeconfig)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't build the client of the cluster", "cluster", cluster)
+		return
+	}
+	tenant, err := clusterclientset.CoreV1alpha().Tenants().Get(ctx, tenantName, metav1.GetOptions{})
+	if err != nil || tenant.GetLabels()["edge-net.io/federated"] != "true" {
+		return
+	}
+	if err := clusterclientset.CoreV1alpha().TenantResourceQuotas().Delete(ctx, tenantName, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		klog.ErrorS(err, "Couldn't delete the tenant resource quota", "tenantResourceQuota", tenantName)
+	}
+	if err := clusterclientset.CoreV1alpha().Tenants().Delete(ctx, tenantName, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		klog.ErrorS(err, "Couldn't delete the tenant", "tenant", tenantName)
+	}
+}
+
+// propagate creates the tenant, its quota, and its subnamespaces in a workload cluster, or updates them if they exist
+func propagate(ctx context.Context, clusterclientset clientset.Interface, tenant *corev1alpha.Tenant, tenantResourceQuota *corev1alpha.TenantResourceQuota, subnamespaces []corev1alpha.SubNamespace) error {
+	tenantClient := clusterclientset.CoreV1alpha().Tenants()
+	if current, err := tenantClient.Get(ctx, tenant.GetName(), metav1.GetOptions{}); err == nil {
+		if current.GetLabels()["edge-net.io/federated"] != "true" {
+			return fmt.Errorf("tenant %s already exists", tenant.GetName())
+		}
+		if !reflect.DeepEqual(current.Spec, tenant.Spec) {
+			current.Spec = *tenant.Spec.DeepCopy()
+			if _, err := tenantClient.Update(ctx, current, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+		}
+	} else if errors.IsNotFound(err) {
+		tenantCopy := &corev1alpha.Tenant{ObjectMeta: federatedObjectMeta(tenant.ObjectMeta), Spec: *tenant.Spec.DeepCopy()}
+		if _, err := tenantClient.Create(ctx, tenantCopy, metav1.CreateOptions{}); err != nil {
+			return err
+		}
+	} else {
+		return err
+	}
+
+	if tenantResourceQuota != nil {
+		quotaClient := clusterclientset.CoreV1alpha().TenantResourceQuotas()
+		if current, err := quotaClient.Get(ctx, tenantResourceQuota.GetName(), metav1.GetOptions{}); err == nil {
+			if !reflect.DeepEqual(current.Spec, tenantResourceQuota.Spec) {
+				current.Spec = *tenantResourceQuota.Spec.DeepCopy()
+				if _, err := quotaClient.Update(ctx, current, metav1.UpdateOptions{}); err != nil {
+					return err
+				}
+			}
+		} else if errors.IsNotFound(err) {
+			quotaCopy := &corev1alpha.TenantResourceQuota{ObjectMeta: federatedObjectMeta(tenantResourceQuota.ObjectMeta), Spec: *tenantResourceQuota.Spec.DeepCopy()}
+			if _, err := quotaClient.Create(ctx, quotaCopy, metav1.CreateOptions{}); err != nil {
+				return err
+			}
+		} else {
+			return err
+		}
+	}
+
+	for _, subnamespace := range subnamespaces {
+		subnamespaceClient := clusterclientset.CoreV1alpha().SubNamespaces(subnamespace.GetNamespace())
+		if current, err := subnamespaceClient.Get(ctx, subnamespace.GetName(), metav1.GetOptions{}); err == nil {
+			if !reflect.DeepEqual(current.Spec, subnamespace.Spec) {
+				current.Spec = *subnamespace.Spec.DeepCopy()
+				if _, err := subnamespaceClient.Update(ctx, current, metav1.UpdateOptions{}); err != nil {
+					return err
+				}
+			}
+		} else if errors.IsNotFound(err) {
+			subnamespaceCopy := &corev1alpha.SubNamespace{ObjectMeta: federatedObjectMeta(subnamespace.ObjectMeta), Spec: *subnamespace.Spec.DeepCopy()}
+			if _, err := subnamespaceClient.Create(ctx, subnamespaceCopy, metav1.CreateOptions{}); err != nil {
+				return err
+			}
+		} else {
+			return err
+		}
+	}
+	return nil
+}
+
+// federatedObjectMeta returns the metadata of an object to create in a workload cluster, the fields
+// bound to the manager cluster such as the uid and the owners are left out
+func federatedObjectMeta(objectMeta metav1.ObjectMeta) metav1.ObjectMeta {
+	objectLabels := map[string]string{}
+	for key, value := range objectMeta.GetLabels() {
+		objectLabels[key] = value
+	}
+	objectLabels["edge-net.io/federated"] = "true"
+	return metav1.ObjectMeta{Name: objectMeta.GetName(), Namespace: objectMeta.GetNamespace(), Labels: objectLabels, Annotations: objectMeta.GetAnnotations()}
+}
+
+// SetAsOwnerReference returns the tenant as owner
+func SetAsOwnerReference(tenant *corev1alpha.Tenant) []metav1.OwnerReference {
+	// The following section makes tenant become the owner
+	ownerReferences := []metav1.OwnerReference{}
+	newTenantRef := *metav1.NewControllerRef(tenant, corev1alpha.SchemeGroupVersion.WithKind("Tenant"))
+	takeControl := false
+	newTenantRef.Controller = &takeControl
+	ownerReferences = append(ownerReferences, newTenantRef)
+	return ownerReferences
+}
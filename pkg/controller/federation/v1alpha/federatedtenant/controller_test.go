@@ -0,0 +1,212 @@
+package federatedtenant
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	federationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/federation/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+)
+
+type TestGroup struct {
+	tenantObj       corev1alpha.Tenant
+	subnamespaceObj corev1alpha.SubNamespace
+}
+
+var kubeclientset kubernetes.Interface = testclient.NewSimpleClientset()
+var edgenetclientset versioned.Interface = edgenettestclient.NewSimpleClientset()
+
+// The kubeconfig of a fake workload cluster is the name of the cluster
+var workloadclientsets = map[string]versioned.Interface{
+	"paris": edgenettestclient.NewSimpleClientset(),
+	"nyc":   edgenettestclient.NewSimpleClientset(),
+}
+
+func fakeClusterClient(kubeconfig []byte) (versioned.Interface, error) {
+	if clusterclientset, ok := workloadclientsets[string(kubeconfig)]; ok {
+		return clusterclientset, nil
+	}
+	return nil, fmt.Errorf("cluster %s unreachable", string(kubeconfig))
+}
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	logrus.SetOutput(ioutil.Discard)
+
+	flag.String("dir", "../../../../..", "Override the directory.")
+	flag.String("smtp-path", "../../../../../configs/smtp_test.yaml", "Set SMTP path.")
+	flag.Parse()
+
+	stopCh := signals.SetupSignalHandler()
+
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, time.Second*30)
+
+	controller := NewController(signals.ContextFor(stopCh),
+		kubeclientset,
+		edgenetclientset,
+		fakeClusterClient,
+		edgenetInformerFactory.Federation().V1alpha().FederatedTenants(),
+		edgenetInformerFactory.Core().V1alpha().Tenants())
+
+	edgenetInformerFactory.Start(stopCh)
+
+	go func() {
+		if err := controller.Run(2, stopCh); err != nil {
+			klog.Fatalf("Error running controller: %s", err.Error())
+		}
+	}()
+
+	for _, name := range []string{"paris", "nyc", "tokyo"} {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: federationNamespace,
+			Labels: map[string]string{"edge-net.io/federation": "workload"}}, Data: map[string][]byte{kubeconfigKey: []byte(name)}}
+		kubeclientset.CoreV1().Secrets(federationNamespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+	}
+
+	time.Sleep(500 * time.Millisecond)
+
+	os.Exit(m.Run())
+	<-stopCh
+}
+
+// Init syncs the test group
+func (g *TestGroup) Init() {
+	tenantObj := corev1alpha.Tenant{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Tenant",
+			APIVersion: "core.edgenet.io/v1alpha",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "edgenet",
+			UID:  "edgenet",
+		},
+		Spec: corev1alpha.TenantSpec{
+			FullName:  "EdgeNet",
+			ShortName: "EdgeNet",
+			URL:       "https://www.edge-net.org",
+			Contact: corev1alpha.Contact{
+				Email:     "joe.public@edge-net.org",
+				FirstName: "Joe",
+				LastName:  "Public",
+				Phone:     "+33NUMBER",
+				Handle:    "joepublic",
+			},
+			Enabled: true,
+		},
+	}
+	subnamespaceObj := corev1alpha.SubNamespace{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "SubNamespace",
+			APIVersion: "core.edgenet.io/v1alpha",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "edgenet-sub",
+			Namespace: "edgenet",
+			UID:       "edgenet-sub",
+		},
+		Spec: corev1alpha.SubNamespaceSpec{
+			Workspace: &corev1alpha.Workspace{
+				Scope: "federation",
+			},
+		},
+	}
+	g.tenantObj = tenantObj
+	g.subnamespaceObj = subnamespaceObj
+}
+
+func TestPropagate(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	tenantTest := g.tenantObj.DeepCopy()
+	tenantTest.SetName("propagate")
+	tenantTest.SetUID("propagate")
+	subnamespaceTest := g.subnamespaceObj.DeepCopy()
+	subnamespaceTest.SetNamespace(tenantTest.GetName())
+	edgenetclientset.CoreV1alpha().SubNamespaces(tenantTest.GetName()).Create(context.TODO(), subnamespaceTest, metav1.CreateOptions{})
+	edgenetclientset.CoreV1alpha().Tenants().Create(context.TODO(), tenantTest, metav1.CreateOptions{})
+	time.Sleep(time.Millisecond * 500)
+
+	// Approved tenants are federated to all registered clusters, the unreachable cluster fails
+	federatedTenant, err := edgenetclientset.FederationV1alpha().FederatedTenants().Get(context.TODO(), tenantTest.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, tenantTest.GetName(), federatedTenant.GetOwnerReferences()[0].Name)
+	util.Equals(t, failure, federatedTenant.Status.State)
+	util.Equals(t, 3, len(federatedTenant.Status.Clusters))
+
+	t.Run("selected clusters", func(t *testing.T) {
+		federatedTenant.Spec.Clusters = []string{"paris", "nyc"}
+		federatedTenant.Spec.SubNamespaces = true
+		edgenetclientset.FederationV1alpha().FederatedTenants().Update(context.TODO(), federatedTenant, metav1.UpdateOptions{})
+		time.Sleep(time.Millisecond * 500)
+		federatedTenant, err := edgenetclientset.FederationV1alpha().FederatedTenants().Get(context.TODO(), tenantTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, established, federatedTenant.Status.State)
+		util.Equals(t, 2, len(federatedTenant.Status.Clusters))
+		for _, clusterStatus := range federatedTenant.Status.Clusters {
+			util.Equals(t, established, clusterStatus.State)
+			util.Assert(t, clusterStatus.LastSync != nil, "last sync is not set")
+			tenant, err := workloadclientsets[clusterStatus.Name].CoreV1alpha().Tenants().Get(context.TODO(), tenantTest.GetName(), metav1.GetOptions{})
+			util.OK(t, err)
+			util.Equals(t, tenantTest.Spec, tenant.Spec)
+			util.Equals(t, "true", tenant.GetLabels()["edge-net.io/federated"])
+			_, err = workloadclientsets[clusterStatus.Name].CoreV1alpha().SubNamespaces(tenantTest.GetName()).Get(context.TODO(), subnamespaceTest.GetName(), metav1.GetOptions{})
+			util.OK(t, err)
+		}
+	})
+	t.Run("unregistered cluster", func(t *testing.T) {
+		federatedTenant, _ := edgenetclientset.FederationV1alpha().FederatedTenants().Get(context.TODO(), tenantTest.GetName(), metav1.GetOptions{})
+		federatedTenant.Spec.Clusters = []string{"paris", "berlin"}
+		edgenetclientset.FederationV1alpha().FederatedTenants().Update(context.TODO(), federatedTenant, metav1.UpdateOptions{})
+		time.Sleep(time.Millisecond * 500)
+		federatedTenant, err := edgenetclientset.FederationV1alpha().FederatedTenants().Get(context.TODO(), tenantTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, failure, federatedTenant.Status.State)
+		util.Equals(t, messageClusterNotFound, federatedTenant.Status.Clusters[1].Message)
+		// The tenant is withdrawn from the cluster that is no longer selected
+		_, err = workloadclientsets["nyc"].CoreV1alpha().Tenants().Get(context.TODO(), tenantTest.GetName(), metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+	})
+	t.Run("tenant update", func(t *testing.T) {
+		tenant, _ := edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenantTest.GetName(), metav1.GetOptions{})
+		tenant.Spec.URL = "https://www.edge-net.io"
+		edgenetclientset.CoreV1alpha().Tenants().Update(context.TODO(), tenant, metav1.UpdateOptions{})
+		time.Sleep(time.Millisecond * 500)
+		tenant, err := workloadclientsets["paris"].CoreV1alpha().Tenants().Get(context.TODO(), tenantTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, "https://www.edge-net.io", tenant.Spec.URL)
+	})
+}
+
+func TestNotApproved(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	federatedTenant := &federationv1alpha.FederatedTenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "not-approved"},
+		Spec:       federationv1alpha.FederatedTenantSpec{Tenant: "not-approved"},
+	}
+	edgenetclientset.FederationV1alpha().FederatedTenants().Create(context.TODO(), federatedTenant, metav1.CreateOptions{})
+	time.Sleep(time.Millisecond * 500)
+	federatedTenant, err := edgenetclientset.FederationV1alpha().FederatedTenants().Get(context.TODO(), federatedTenant.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, failure, federatedTenant.Status.State)
+	util.Equals(t, messageNotApproved, federatedTenant.Status.Message)
+}
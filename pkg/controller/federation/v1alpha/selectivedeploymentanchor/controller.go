@@ -0,0 +1,612 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package selectivedeploymentanchor
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/apps/v1alpha"
+	federationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/federation/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/bootstrap"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	appsinformers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/apps/v1alpha"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/federation/v1alpha"
+	appslisters "github.com/EdgeNet-project/edgenet/pkg/generated/listers/apps/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/federation/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const controllerAgentName = "selectivedeploymentanchor-controller"
+
+// Definitions of the state of the selectivedeploymentanchor resource
+const (
+	successSynced                      = "Synced"
+	messageResourceSynced              = "Selective Deployment Anchor synced successfully"
+	successRolledOut                   = "Rolled Out"
+	messageRolledOut                   = "Selective deployment runs in all chosen clusters"
+	failureRollout                     = "Rollout Incomplete"
+	messageRolloutPartial              = "Selective deployment runs in some of the chosen clusters"
+	messageRolloutFailed               = "Selective deployment runs in none of the chosen clusters"
+	failureNotFound                    = "Not Found"
+	messageSelectiveDeploymentNotFound = "Selective deployment to anchor does not exist"
+	failureNoCluster                   = "No Cluster"
+	messageNoCluster                   = "No ready workload cluster fits the selectors and the capacity"
+	messageClusterNotFound             = "Kubeconfig of the workload cluster is missing"
+	messageKubeconfigInvalid           = "Kubeconfig of the workload cluster is invalid"
+	messageClusterFailed               = "Selective deployment could not be placed in the workload cluster"
+	success                            = "Success"
+	partial                            = "Partial"
+	failure                            = "Failure"
+)
+
+// The clusters are picked out of the Cluster resources, which carry the same geolabels as the nodes
+const (
+	roleWorkload = "Workload"
+	clusterReady = "Ready"
+)
+
+// The kubeconfigs of the workload clusters are kept in the secrets of the EdgeNet namespace, one secret per cluster named after it
+const (
+	federationNamespace  = "edgenet"
+	workloadClusterLabel = "edge-net.io/federation=workload"
+	kubeconfigKey        = "kubeconfig"
+)
+
+// ClusterClientFunc builds the clientset of a workload cluster out of its kubeconfig
+type ClusterClientFunc func(kubeconfig []byte) (clientset.Interface, error)
+
+// NewClusterClient creates the EdgeNet clientset of a workload cluster
+func NewClusterClient(kubeconfig []byte) (clientset.Interface, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	bootstrap.ConfigureReadOnly(config)
+	return clientset.NewForConfig(config)
+}
+
+// Controller is the controller implementation for Selective Deployment Anchor resources
+type Controller struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+	// edgenetclientset is a clientset for the EdgeNet API groups
+	edgenetclientset clientset.Interface
+	// clusterClient connects to the workload clusters
+	clusterClient ClusterClientFunc
+	// rollout is the interval between two collections of the rollout status in the workload clusters
+	rollout time.Duration
+
+	anchorsLister              listers.SelectiveDeploymentAnchorLister
+	anchorsSynced              cache.InformerSynced
+	clustersLister             listers.ClusterLister
+	clustersSynced             cache.InformerSynced
+	selectivedeploymentsLister appslisters.SelectiveDeploymentLister
+	selectivedeploymentsSynced cache.InformerSynced
+
+	// workqueue is a rate limited work queue. This is used to queue work to be
+	// processed instead of performing it as soon as a change happens. This
+	// means we can ensure we only process a fixed amount of resources at a
+	// time, and makes it easy to ensure we are never processing the same item
+	// simultaneously in two different workers.
+	workqueue workqueue.RateLimitingInterface
+	// recorder is an event recorder for recording Event resources to the
+	// Kubernetes API.
+	recorder record.EventRecorder
+}
+
+// NewController returns a new controller
+func NewController(
+	ctx context.Context,
+	kubeclientset kubernetes.Interface,
+	edgenetclientset clientset.Interface,
+	clusterClient ClusterClientFunc,
+	rollout time.Duration,
+	anchorInformer informers.SelectiveDeploymentAnchorInformer,
+	clusterInformer informers.ClusterInformer,
+	selectivedeploymentInformer appsinformers.SelectiveDeploymentInformer) *Controller {
+
+	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
+	klog.V(4).InfoS("Creating event broadcaster")
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartStructuredLogging(0)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+
+	controller := &Controller{
+		kubeclientset:              kubeclientset,
+		edgenetclientset:           edgenetclientset,
+		clusterClient:              clusterClient,
+		rollout:                    rollout,
+		anchorsLister:              anchorInformer.Lister(),
+		anchorsSynced:              anchorInformer.Informer().HasSynced,
+		clustersLister:             clusterInformer.Lister(),
+		clustersSynced:             clusterInformer.Informer().HasSynced,
+		selectivedeploymentsLister: selectivedeploymentInformer.Lister(),
+		selectivedeploymentsSynced: selectivedeploymentInformer.Informer().HasSynced,
+		workqueue:                  workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "SelectiveDeploymentAnchors"),
+		recorder:                   recorder,
+	}
+
+	klog.V(4).InfoS("Setting up event handlers")
+	// Set up an event handler for when Selective Deployment Anchor resources change
+	anchorInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueAnchor,
+		UpdateFunc: func(old, new interface{}) {
+			newObj := new.(*federationv1alpha.SelectiveDeploymentAnchor)
+			oldObj := old.(*federationv1alpha.SelectiveDeploymentAnchor)
+			// Status updates are skipped, the workers collect the rollout status periodically
+			if !reflect.DeepEqual(newObj.Spec, oldObj.Spec) {
+				controller.enqueueAnchor(new)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			anchor, ok := obj.(*federationv1alpha.SelectiveDeploymentAnchor)
+			if !ok {
+				return
+			}
+			for _, placement := range anchor.Status.Clusters {
+				controller.withdraw(ctx, placement.Name, anchor.GetNamespace(), anchor.Spec.SelectiveDeployment)
+			}
+		},
+	})
+	// The changes on a selective deployment are copied into the clusters that run it
+	selectivedeploymentInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.handleSelectiveDeployment,
+		UpdateFunc: func(old, new interface{}) {
+			newObj := new.(*appsv1alpha.SelectiveDeployment)
+			oldObj := old.(*appsv1alpha.SelectiveDeployment)
+			if !reflect.DeepEqual(newObj.Spec, oldObj.Spec) {
+				controller.handleSelectiveDeployment(new)
+			}
+		},
+	})
+	// A cluster joining or leaving the ready workload clusters may change the placements,
+	// the heartbeats alone are skipped
+	clusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.handleCluster,
+		UpdateFunc: func(old, new interface{}) {
+			newObj := new.(*federationv1alpha.Cluster)
+			oldObj := old.(*federationv1alpha.Cluster)
+			if newObj.Status.State != oldObj.Status.State || !reflect.DeepEqual(newObj.GetLabels(), oldObj.GetLabels()) {
+				controller.handleCluster(new)
+			}
+		},
+		DeleteFunc: controller.handleCluster,
+	})
+
+	return controller
+}
+
+// Run will set up the event handlers for the types of selective deployment anchor, cluster, and
+// selective deployment, as well as syncing informer caches and starting workers. It will block until stopCh
+// is closed, at which point it will shutdown the workqueue and wait for
+// workers to finish processing their current work items.
+func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting Selective Deployment Anchor controller")
+
+	klog.V(4).InfoS("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh,
+		c.anchorsSynced,
+		c.clustersSynced,
+		c.selectivedeploymentsSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	klog.V(4).InfoS("Starting workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	klog.V(4).InfoS("Started workers")
+	<-stopCh
+	klog.V(4).InfoS("Shutting down workers")
+
+	return nil
+}
+
+// runWorker is a long-running function that will continually call the
+// processNextWorkItem function in order to read and process a message on the
+// workqueue.
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+// processNextWorkItem will read a single work item off the workqueue and
+// attempt to process it, by calling the syncHandler.
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.workqueue.Done(obj)
+		var key string
+		var ok bool
+
+		if key, ok = obj.(string); !ok {
+			c.workqueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			return nil
+		}
+		if err := c.syncHandler(ctx, key); err != nil {
+			c.workqueue.AddRateLimited(key)
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
+		}
+		c.workqueue.Forget(obj)
+		klog.V(4).InfoS("Successfully synced", "key", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+
+	return true
+}
+
+// syncHandler compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the Selective Deployment Anchor
+// resource with the rollout status in the clusters, and schedules the next collection.
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	anchor, err := c.anchorsLister.SelectiveDeploymentAnchors(namespace).Get(name)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("selectivedeploymentanchor '%s' in work queue no longer exists", key))
+			return nil
+		}
+
+		return err
+	}
+
+	c.processAnchor(ctx, anchor.DeepCopy())
+	c.recorder.Event(anchor, corev1.EventTypeNormal, successSynced, messageResourceSynced)
+	c.workqueue.AddAfter(key, c.rollout)
+	return nil
+}
+
+// enqueueAnchor takes a SelectiveDeploymentAnchor resource and converts it into a namespace/name
+// string which is then put onto the work queue. This method should *not* be
+// passed resources of any type other than SelectiveDeploymentAnchor.
+func (c *Controller) enqueueAnchor(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+// handleSelectiveDeployment enqueues the anchors of a selective deployment
+func (c *Controller) handleSelectiveDeployment(obj interface{}) {
+	selectivedeployment, ok := obj.(*appsv1alpha.SelectiveDeployment)
+	if !ok {
+		return
+	}
+	anchors, err := c.anchorsLister.SelectiveDeploymentAnchors(selectivedeployment.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Couldn't list the selective deployment anchors", "namespace", selectivedeployment.GetNamespace())
+		return
+	}
+	for _, anchor := range anchors {
+		if anchor.Spec.SelectiveDeployment == selectivedeployment.GetName() {
+			c.enqueueAnchor(anchor)
+		}
+	}
+}
+
+// handleCluster enqueues all anchors
+func (c *Controller) handleCluster(obj interface{}) {
+	anchors, err := c.anchorsLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Couldn't list the selective deployment anchors")
+		return
+	}
+	for _, anchor := range anchors {
+		c.enqueueAnchor(anchor)
+	}
+}
+
+func (c *Controller) processAnchor(ctx context.Context, anchorCopy *federationv1alpha.SelectiveDeploymentAnchor) {
+	oldStatus := *anchorCopy.Status.DeepCopy()
+	statusUpdate := func() {
+		if !reflect.DeepEqual(oldStatus, anchorCopy.Status) {
+			if _, err := c.edgenetclientset.FederationV1alpha().SelectiveDeploymentAnchors(anchorCopy.GetNamespace()).UpdateStatus(ctx, anchorCopy, metav1.UpdateOptions{}); err != nil {
+				klog.ErrorS(err, "Couldn't update the status of the selective deployment anchor", "selectiveDeploymentAnchor", klog.KObj(anchorCopy))
+			}
+		}
+	}
+	defer statusUpdate()
+	setState := func(state, reason, message string) {
+		if state != oldStatus.State || message != oldStatus.Message {
+			eventType := corev1.EventTypeWarning
+			if state == success {
+				eventType = corev1.EventTypeNormal
+			}
+			c.recorder.Event(anchorCopy, eventType, reason, message)
+		}
+		anchorCopy.Status.State = state
+		anchorCopy.Status.Message = message
+	}
+
+	previous := make(map[string]bool)
+	for _, placement := range anchorCopy.Status.Clusters {
+		previous[placement.Name] = true
+	}
+	// The selective deployment is withdrawn from the clusters that are no longer chosen
+	defer func() {
+		for _, placement := range anchorCopy.Status.Clusters {
+			delete(previous, placement.Name)
+		}
+		for name := range previous {
+			c.withdraw(ctx, name, anchorCopy.GetNamespace(), anchorCopy.Spec.SelectiveDeployment)
+		}
+	}()
+
+	selectivedeployment, err := c.selectivedeploymentsLister.SelectiveDeployments(anchorCopy.GetNamespace()).Get(anchorCopy.Spec.SelectiveDeployment)
+	if err != nil {
+		anchorCopy.Status.Clusters = []federationv1alpha.ClusterPlacement{}
+		anchorCopy.Status.Ready = ""
+		setState(failure, failureNotFound, messageSelectiveDeploymentNotFound)
+		return
+	}
+	clusters, err := c.chooseClusters(anchorCopy, previous)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't choose the clusters", "selectiveDeploymentAnchor", klog.KObj(anchorCopy))
+		return
+	}
+	if len(clusters) == 0 {
+		anchorCopy.Status.Clusters = []federationv1alpha.ClusterPlacement{}
+		anchorCopy.Status.Ready = ""
+		setState(failure, failureNoCluster, messageNoCluster)
+		return
+	}
+	kubeconfigs, err := c.workloadClusters(ctx)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't read the kubeconfigs of the workload clusters")
+		return
+	}
+
+	placements := []federationv1alpha.ClusterPlacement{}
+	running, requested, succeeded, failed := 0, 0, 0, 0
+	for _, name := range clusters {
+		placement := federationv1alpha.ClusterPlacement{Name: name, State: failure}
+		if kubeconfig, ok := kubeconfigs[name]; !ok {
+			placement.Message = messageClusterNotFound
+		} else if clusterclientset, err := c.clusterClient(kubeconfig); err != nil {
+			klog.ErrorS(err, "Couldn't build the client of the cluster", "cluster", name)
+			placement.Message = messageKubeconfigInvalid
+		} else if current, err := place(ctx, clusterclientset, selectivedeployment); err != nil {
+			klog.ErrorS(err, "Couldn't place the selective deployment", "selectiveDeployment", klog.KObj(selectivedeployment), "cluster", name)
+			placement.Message = messageClusterFailed
+		} else {
+			// The controller of the workload cluster reports the rollout in the status of its copy
+			placement.Ready = current.Status.Ready
+			placement.State = current.Status.State
+			placement.Message = strings.Join(current.Status.Message, "; ")
+			var x, y int
+			if _, err := fmt.Sscanf(current.Status.Ready, "%d/%d", &x, &y); err == nil {
+				running += x
+				requested += y
+			}
+		}
+		switch placement.State {
+		case success:
+			succeeded++
+		case partial:
+		default:
+			failed++
+		}
+		placements = append(placements, placement)
+	}
+	anchorCopy.Status.Clusters = placements
+	anchorCopy.Status.Ready = fmt.Sprintf("%d/%d", running, requested)
+
+	if succeeded == len(placements) {
+		setState(success, successRolledOut, messageRolledOut)
+	} else if failed == len(placements) {
+		setState(failure, failureRollout, messageRolloutFailed)
+	} else {
+		setState(partial, failureRollout, messageRolloutPartial)
+	}
+}
+
+// chooseClusters returns the names of the ready workload clusters that fit the selectors and the capacity
+// of the anchor. The clusters that run the selective deployment already come first so that the placement
+// stays the same as long as they fit.
+func (c *Controller) chooseClusters(anchorCopy *federationv1alpha.SelectiveDeploymentAnchor, previous map[string]bool) ([]string, error) {
+	clusterRaw, err := c.clustersLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	candidates := []*federationv1alpha.Cluster{}
+	for _, clusterRow := range clusterRaw {
+		if clusterRow.Spec.Role == roleWorkload && clusterRow.Status.State == clusterReady && fits(clusterRow.Status.Allocatable, anchorCopy.Spec.Capacity) {
+			candidates = append(candidates, clusterRow)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if previous[candidates[i].GetName()] != previous[candidates[j].GetName()] {
+			return previous[candidates[i].GetName()]
+		}
+		return candidates[i].GetName() < candidates[j].GetName()
+	})
+
+	clusters := []string{}
+	if len(anchorCopy.Spec.Selector) == 0 {
+		for _, candidate := range candidates {
+			clusters = append(clusters, candidate.GetName())
+		}
+		sort.Strings(clusters)
+		return clusters, nil
+	}
+	chosen := make(map[string]bool)
+	for _, selectorRow := range anchorCopy.Spec.Selector {
+		labelKey := geoLabelKey(selectorRow.Name)
+		counter := 0
+		for _, candidate := range candidates {
+			if selectorRow.Quantity != 0 && selectorRow.Quantity == counter {
+				break
+			}
+			if chosen[candidate.GetName()] {
+				continue
+			}
+			value, ok := candidate.GetLabels()[labelKey]
+			matched := false
+			for _, selectorValue := range selectorRow.Value {
+				if ok && strings.EqualFold(selectorValue, value) {
+					matched = true
+				}
+			}
+			if (selectorRow.Operator == corev1.NodeSelectorOpIn && matched) || (selectorRow.Operator == corev1.NodeSelectorOpNotIn && !matched) {
+				chosen[candidate.GetName()] = true
+				clusters = append(clusters, candidate.GetName())
+				counter++
+			}
+		}
+	}
+	sort.Strings(clusters)
+	return clusters, nil
+}
+
+// geoLabelKey returns the key of the geolabel that a selector filters on, the same keys as the node labels
+func geoLabelKey(selectorName string) string {
+	selectorName = strings.ToLower(selectorName)
+	if selectorName == "state" || selectorName == "country" {
+		return fmt.Sprintf("edge-net.io/%s-iso", selectorName)
+	}
+	return fmt.Sprintf("edge-net.io/%s", selectorName)
+}
+
+// fits returns true if the allocatable resources cover the capacity required
+func fits(allocatable, capacity corev1.ResourceList) bool {
+	for name, required := range capacity {
+		quantity, ok := allocatable[name]
+		if !ok || quantity.Cmp(required) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// workloadClusters returns the kubeconfigs of the registered workload clusters by name
+func (c *Controller) workloadClusters(ctx context.Context) (map[string][]byte, error) {
+	secretRaw, err := c.kubeclientset.CoreV1().Secrets(federationNamespace).List(ctx, metav1.ListOptions{LabelSelector: workloadClusterLabel})
+	if err != nil {
+		return nil, err
+	}
+	kubeconfigs := make(map[string][]byte)
+	for _, secretRow := range secretRaw.Items {
+		if kubeconfig, ok := secretRow.Data[kubeconfigKey]; ok {
+			kubeconfigs[secretRow.GetName()] = kubeconfig
+		}
+	}
+	return kubeconfigs, nil
+}
+
+// withdraw removes the copy of a selective deployment from a workload cluster
+func (c *Controller) withdraw(ctx context.Context, cluster, namespace, name string) {
+	kubeconfigs, err := c.workloadClusters(ctx)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't read the kubeconfigs of the workload clusters")
+		return
+	}
+	kubeconfig, ok := kubeconfigs[cluster]
+	if !ok {
+		return
+	}
+	clusterclientset, err := c.clusterClient(kubeconfig)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't build the client of the cluster", "cluster", cluster)
+		return
+	}
+	selectivedeployment, err := clusterclientset.AppsV1alpha().SelectiveDeployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil || selectivedeployment.GetLabels()["edge-net.io/federated"] != "true" {
+		return
+	}
+	if err := clusterclientset.AppsV1alpha().SelectiveDeployments(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		klog.ErrorS(err, "Couldn't delete the selective deployment", "selectiveDeployment", klog.KRef(namespace, name))
+	}
+}
+
+// place creates the copy of a selective deployment in a workload cluster, or updates it if it exists, and
+// returns the copy along with its status
+func place(ctx context.Context, clusterclientset clientset.Interface, selectivedeployment *appsv1alpha.SelectiveDeployment) (*appsv1alpha.SelectiveDeployment, error) {
+	selectivedeploymentClient := clusterclientset.AppsV1alpha().SelectiveDeployments(selectivedeployment.GetNamespace())
+	current, err := selectivedeploymentClient.Get(ctx, selectivedeployment.GetName(), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		selectivedeploymentCopy := &appsv1alpha.SelectiveDeployment{ObjectMeta: federatedObjectMeta(selectivedeployment.ObjectMeta), Spec: *selectivedeployment.Spec.DeepCopy()}
+		return selectivedeploymentClient.Create(ctx, selectivedeploymentCopy, metav1.CreateOptions{})
+	} else if err != nil {
+		return nil, err
+	}
+	if current.GetLabels()["edge-net.io/federated"] != "true" {
+		return nil, fmt.Errorf("selective deployment %s/%s already exists", current.GetNamespace(), current.GetName())
+	}
+	if !reflect.DeepEqual(current.Spec, selectivedeployment.Spec) {
+		current.Spec = *selectivedeployment.Spec.DeepCopy()
+		return selectivedeploymentClient.Update(ctx, current, metav1.UpdateOptions{})
+	}
+	return current, nil
+}
+
+// federatedObjectMeta returns the metadata of an object to create in a workload cluster, the fields
+// bound to the originating cluster such as the uid and the owners are left out
+func federatedObjectMeta(objectMeta metav1.ObjectMeta) metav1.ObjectMeta {
+	objectLabels := map[string]string{}
+	for key, value := range objectMeta.GetLabels() {
+		objectLabels[key] = value
+	}
+	objectLabels["edge-net.io/federated"] = "true"
+	return metav1.ObjectMeta{Name: objectMeta.GetName(), Namespace: objectMeta.GetNamespace(), Labels: objectLabels, Annotations: objectMeta.GetAnnotations()}
+}
@@ -0,0 +1,237 @@
+package selectivedeploymentanchor
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	appsv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/apps/v1alpha"
+	federationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/federation/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+)
+
+type TestGroup struct {
+	anchorObj              federationv1alpha.SelectiveDeploymentAnchor
+	selectivedeploymentObj appsv1alpha.SelectiveDeployment
+}
+
+var kubeclientset kubernetes.Interface = testclient.NewSimpleClientset()
+var edgenetclientset versioned.Interface = edgenettestclient.NewSimpleClientset()
+
+// The kubeconfig of a fake workload cluster is the name of the cluster
+var workloadclientsets = map[string]versioned.Interface{
+	"paris": edgenettestclient.NewSimpleClientset(),
+	"nyc":   edgenettestclient.NewSimpleClientset(),
+	"tokyo": edgenettestclient.NewSimpleClientset(),
+}
+
+func fakeClusterClient(kubeconfig []byte) (versioned.Interface, error) {
+	if clusterclientset, ok := workloadclientsets[string(kubeconfig)]; ok {
+		return clusterclientset, nil
+	}
+	return nil, fmt.Errorf("cluster %s unreachable", string(kubeconfig))
+}
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	logrus.SetOutput(ioutil.Discard)
+
+	flag.String("dir", "../../../../..", "Override the directory.")
+	flag.String("smtp-path", "../../../../../configs/smtp_test.yaml", "Set SMTP path.")
+	flag.Parse()
+
+	stopCh := signals.SetupSignalHandler()
+
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, time.Second*30)
+
+	controller := NewController(signals.ContextFor(stopCh),
+		kubeclientset,
+		edgenetclientset,
+		fakeClusterClient,
+		200*time.Millisecond,
+		edgenetInformerFactory.Federation().V1alpha().SelectiveDeploymentAnchors(),
+		edgenetInformerFactory.Federation().V1alpha().Clusters(),
+		edgenetInformerFactory.Apps().V1alpha().SelectiveDeployments())
+
+	edgenetInformerFactory.Start(stopCh)
+
+	go func() {
+		if err := controller.Run(2, stopCh); err != nil {
+			klog.Fatalf("Error running controller: %s", err.Error())
+		}
+	}()
+
+	clusters := []struct {
+		name, continent, country, cpu, state string
+	}{
+		{"paris", "Europe", "FR", "8", clusterReady},
+		{"nyc", "North America", "US", "2", clusterReady},
+		{"tokyo", "Asia", "JP", "16", "Unreachable"},
+	}
+	for _, cluster := range clusters {
+		clusterObj := &federationv1alpha.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: cluster.name, Labels: map[string]string{"edge-net.io/continent": cluster.continent, "edge-net.io/country-iso": cluster.country}},
+			Spec:       federationv1alpha.ClusterSpec{Role: roleWorkload},
+			Status:     federationv1alpha.ClusterStatus{State: cluster.state, Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cluster.cpu)}},
+		}
+		edgenetclientset.FederationV1alpha().Clusters().Create(context.TODO(), clusterObj, metav1.CreateOptions{})
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: cluster.name, Namespace: federationNamespace,
+			Labels: map[string]string{"edge-net.io/federation": "workload"}}, Data: map[string][]byte{kubeconfigKey: []byte(cluster.name)}}
+		kubeclientset.CoreV1().Secrets(federationNamespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+	}
+
+	time.Sleep(500 * time.Millisecond)
+
+	os.Exit(m.Run())
+	<-stopCh
+}
+
+// Init syncs the test group
+func (g *TestGroup) Init() {
+	selectivedeploymentObj := appsv1alpha.SelectiveDeployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "SelectiveDeployment",
+			APIVersion: "apps.edgenet.io/v1alpha",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "country",
+			Namespace: "edgenet",
+			UID:       "country",
+		},
+		Spec: appsv1alpha.SelectiveDeploymentSpec{
+			Selector: []appsv1alpha.Selector{{Name: "Country", Value: []string{"FR", "US"}, Operator: "In", Quantity: 2}},
+		},
+	}
+	anchorObj := federationv1alpha.SelectiveDeploymentAnchor{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "SelectiveDeploymentAnchor",
+			APIVersion: "federation.edgenet.io/v1alpha",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "country",
+			Namespace: "edgenet",
+			UID:       "country",
+		},
+		Spec: federationv1alpha.SelectiveDeploymentAnchorSpec{
+			SelectiveDeployment: "country",
+			Selector:            []federationv1alpha.ClusterSelector{{Name: "Continent", Value: []string{"Europe", "North America", "Asia"}, Operator: "In"}},
+			Capacity:            corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+		},
+	}
+	g.selectivedeploymentObj = selectivedeploymentObj
+	g.anchorObj = anchorObj
+}
+
+func TestPlacement(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	selectivedeploymentTest := g.selectivedeploymentObj.DeepCopy()
+	anchorTest := g.anchorObj.DeepCopy()
+	namespace := anchorTest.GetNamespace()
+
+	edgenetclientset.AppsV1alpha().SelectiveDeployments(namespace).Create(context.TODO(), selectivedeploymentTest, metav1.CreateOptions{})
+	edgenetclientset.FederationV1alpha().SelectiveDeploymentAnchors(namespace).Create(context.TODO(), anchorTest, metav1.CreateOptions{})
+	time.Sleep(time.Millisecond * 500)
+	// Tokyo is unreachable and New York lacks the capacity
+	placed, err := workloadclientsets["paris"].AppsV1alpha().SelectiveDeployments(namespace).Get(context.TODO(), selectivedeploymentTest.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, "true", placed.GetLabels()["edge-net.io/federated"])
+	util.Equals(t, selectivedeploymentTest.Spec, placed.Spec)
+	for _, name := range []string{"nyc", "tokyo"} {
+		_, err = workloadclientsets[name].AppsV1alpha().SelectiveDeployments(namespace).Get(context.TODO(), selectivedeploymentTest.GetName(), metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+	}
+
+	t.Run("rollout status", func(t *testing.T) {
+		placed.Status = appsv1alpha.SelectiveDeploymentStatus{Ready: "2/2", State: success}
+		workloadclientsets["paris"].AppsV1alpha().SelectiveDeployments(namespace).UpdateStatus(context.TODO(), placed, metav1.UpdateOptions{})
+		time.Sleep(time.Millisecond * 500)
+		anchor, err := edgenetclientset.FederationV1alpha().SelectiveDeploymentAnchors(namespace).Get(context.TODO(), anchorTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, success, anchor.Status.State)
+		util.Equals(t, "2/2", anchor.Status.Ready)
+		util.Equals(t, 1, len(anchor.Status.Clusters))
+		util.Equals(t, "paris", anchor.Status.Clusters[0].Name)
+	})
+	t.Run("update", func(t *testing.T) {
+		selectivedeployment, _ := edgenetclientset.AppsV1alpha().SelectiveDeployments(namespace).Get(context.TODO(), selectivedeploymentTest.GetName(), metav1.GetOptions{})
+		selectivedeployment.Spec.Recovery = true
+		edgenetclientset.AppsV1alpha().SelectiveDeployments(namespace).Update(context.TODO(), selectivedeployment, metav1.UpdateOptions{})
+		time.Sleep(time.Millisecond * 500)
+		placed, err := workloadclientsets["paris"].AppsV1alpha().SelectiveDeployments(namespace).Get(context.TODO(), selectivedeploymentTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, true, placed.Spec.Recovery)
+	})
+	t.Run("capacity", func(t *testing.T) {
+		anchor, _ := edgenetclientset.FederationV1alpha().SelectiveDeploymentAnchors(namespace).Get(context.TODO(), anchorTest.GetName(), metav1.GetOptions{})
+		anchor.Spec.Capacity = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}
+		edgenetclientset.FederationV1alpha().SelectiveDeploymentAnchors(namespace).Update(context.TODO(), anchor, metav1.UpdateOptions{})
+		time.Sleep(time.Millisecond * 500)
+		_, err := workloadclientsets["nyc"].AppsV1alpha().SelectiveDeployments(namespace).Get(context.TODO(), selectivedeploymentTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		anchor, err = edgenetclientset.FederationV1alpha().SelectiveDeploymentAnchors(namespace).Get(context.TODO(), anchorTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		// New York has not reported a rollout yet
+		util.Equals(t, partial, anchor.Status.State)
+	})
+	t.Run("withdraw", func(t *testing.T) {
+		anchor, _ := edgenetclientset.FederationV1alpha().SelectiveDeploymentAnchors(namespace).Get(context.TODO(), anchorTest.GetName(), metav1.GetOptions{})
+		anchor.Spec.Selector = []federationv1alpha.ClusterSelector{{Name: "Country", Value: []string{"FR"}, Operator: "NotIn"}}
+		edgenetclientset.FederationV1alpha().SelectiveDeploymentAnchors(namespace).Update(context.TODO(), anchor, metav1.UpdateOptions{})
+		time.Sleep(time.Millisecond * 500)
+		_, err := workloadclientsets["paris"].AppsV1alpha().SelectiveDeployments(namespace).Get(context.TODO(), selectivedeploymentTest.GetName(), metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+		_, err = workloadclientsets["nyc"].AppsV1alpha().SelectiveDeployments(namespace).Get(context.TODO(), selectivedeploymentTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+	})
+}
+
+func TestFailure(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	cases := map[string]struct {
+		input    func(*federationv1alpha.SelectiveDeploymentAnchor)
+		expected string
+	}{
+		"missing":     {func(a *federationv1alpha.SelectiveDeploymentAnchor) { a.Spec.SelectiveDeployment = "missing" }, messageSelectiveDeploymentNotFound},
+		"unreachable": {func(a *federationv1alpha.SelectiveDeploymentAnchor) { a.Spec.Selector[0].Value = []string{"Asia"} }, messageNoCluster},
+		"capacity": {func(a *federationv1alpha.SelectiveDeploymentAnchor) {
+			a.Spec.Capacity = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("32")}
+		}, messageNoCluster},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			anchorTest := g.anchorObj.DeepCopy()
+			anchorTest.SetName(k)
+			anchorTest.SetUID(types.UID(k))
+			tc.input(anchorTest)
+			edgenetclientset.FederationV1alpha().SelectiveDeploymentAnchors(anchorTest.GetNamespace()).Create(context.TODO(), anchorTest, metav1.CreateOptions{})
+			time.Sleep(time.Millisecond * 500)
+			anchor, err := edgenetclientset.FederationV1alpha().SelectiveDeploymentAnchors(anchorTest.GetNamespace()).Get(context.TODO(), anchorTest.GetName(), metav1.GetOptions{})
+			util.OK(t, err)
+			util.Equals(t, failure, anchor.Status.State)
+			util.Equals(t, tc.expected, anchor.Status.Message)
+		})
+	}
+}
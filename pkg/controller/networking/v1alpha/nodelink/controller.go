@@ -0,0 +1,208 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodelink
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	networkingv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/networking/v1alpha"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	"github.com/EdgeNet-project/edgenet/pkg/node"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// ProbeLabel selects the nodes measured, each of them measures the paths to the others
+const ProbeLabel = "edge-net.io/linkprobe"
+
+// Interval is the time between two measurements of the links
+var Interval = time.Minute
+
+// Probes is the number of probes sent at each measurement of a link
+var Probes = 10
+
+// ProbePort is the port the probes connect to, the kubelet listens on it on every node
+var ProbePort = 10250
+
+// ProbeTimeout is the time after which a probe is counted as lost
+var ProbeTimeout = 2 * time.Second
+
+// prober returns the time a probe to the address took to be answered
+type prober func(ctx context.Context, address string) (time.Duration, error)
+
+// Controller measures the links from the node it runs on to the other nodes measured
+type Controller struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+	// edgenetclientset is a clientset for the EdgeNet API groups
+	edgenetclientset clientset.Interface
+
+	// nodeName is the node the agent runs on, the source of its links
+	nodeName string
+	probe    prober
+}
+
+// NewController returns a new controller
+func NewController(kubeclientset kubernetes.Interface, edgenetclientset clientset.Interface, nodeName string) *Controller {
+	return &Controller{
+		kubeclientset:    kubeclientset,
+		edgenetclientset: edgenetclientset,
+		nodeName:         nodeName,
+		probe:            tcpProbe,
+	}
+}
+
+// Run measures the links at every interval. It will block until stopCh is closed.
+func (c *Controller) Run(stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting NodeLink agent", "node", c.nodeName)
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := c.measure(ctx); err != nil {
+			klog.ErrorS(err, "Couldn't measure the node links", "node", c.nodeName)
+		}
+	}, Interval)
+
+	<-stopCh
+	klog.V(4).InfoS("Shutting down NodeLink agent", "node", c.nodeName)
+	return nil
+}
+
+// Name returns the name of the link from the source node to the target node
+func Name(source, target string) string {
+	return fmt.Sprintf("%s--%s", source, target)
+}
+
+// measure probes the other nodes measured and records the results in the links from this node.
+// The links to the nodes no longer measured are removed, all of them if this node is not measured.
+func (c *Controller) measure(ctx context.Context) error {
+	source, err := c.kubeclientset.CoreV1().Nodes().Get(ctx, c.nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	targets := map[string]bool{}
+	if _, ok := source.GetLabels()[ProbeLabel]; ok {
+		nodeRaw, err := c.kubeclientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: ProbeLabel})
+		if err != nil {
+			return err
+		}
+		for _, nodeRow := range nodeRaw.Items {
+			if nodeRow.GetName() == c.nodeName {
+				continue
+			}
+			targets[nodeRow.GetName()] = true
+			if err := c.measureLink(ctx, source, nodeRow.DeepCopy()); err != nil {
+				klog.ErrorS(err, "Couldn't measure the node link", "source", c.nodeName, "target", nodeRow.GetName())
+			}
+		}
+	}
+
+	linkRaw, err := c.edgenetclientset.NetworkingV1alpha().NodeLinks().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, linkRow := range linkRaw.Items {
+		if linkRow.Spec.Source == c.nodeName && !targets[linkRow.Spec.Target] {
+			if err := c.edgenetclientset.NetworkingV1alpha().NodeLinks().Delete(ctx, linkRow.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// measureLink sends the probes to the target node and updates the status of the link, which is
+// created with the source node as owner so that it goes along with the node
+func (c *Controller) measureLink(ctx context.Context, source, target *corev1.Node) error {
+	internalIP, externalIP := node.GetNodeIPAddresses(target)
+	address := externalIP
+	if address == "" {
+		address = internalIP
+	}
+	if address == "" {
+		return fmt.Errorf("node %s has no address", target.GetName())
+	}
+
+	name := Name(source.GetName(), target.GetName())
+	link, err := c.edgenetclientset.NetworkingV1alpha().NodeLinks().Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		link = &networkingv1alpha.NodeLink{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(source, corev1.SchemeGroupVersion.WithKind("Node"))},
+			},
+			Spec: networkingv1alpha.NodeLinkSpec{Source: source.GetName(), Target: target.GetName()},
+		}
+		link, err = c.edgenetclientset.NetworkingV1alpha().NodeLinks().Create(ctx, link, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	linkCopy := link.DeepCopy()
+	linkCopy.Status = c.probeAddress(ctx, net.JoinHostPort(address, strconv.Itoa(ProbePort)))
+	linkCopy.Status.Address = address
+	_, err = c.edgenetclientset.NetworkingV1alpha().NodeLinks().UpdateStatus(ctx, linkCopy, metav1.UpdateOptions{})
+	return err
+}
+
+// probeAddress sends the probes one after the other, the latency is the mean of the answered ones
+func (c *Controller) probeAddress(ctx context.Context, address string) networkingv1alpha.NodeLinkStatus {
+	status := networkingv1alpha.NodeLinkStatus{Measured: metav1.Now()}
+	var total time.Duration
+	answered := 0
+	for i := 0; i < Probes; i++ {
+		rtt, err := c.probe(ctx, address)
+		if err != nil {
+			klog.V(4).InfoS("Probe lost", "address", address, "err", err)
+			continue
+		}
+		total += rtt
+		answered++
+	}
+	if answered > 0 {
+		status.Latency = &metav1.Duration{Duration: total / time.Duration(answered)}
+	}
+	status.PacketLoss = (Probes - answered) * 100 / Probes
+	return status
+}
+
+// tcpProbe times the TCP handshake with the address, which takes a round trip. It requires no
+// privileges, unlike the ICMP echo requests.
+func tcpProbe(ctx context.Context, address string) (time.Duration, error) {
+	dialer := net.Dialer{Timeout: ProbeTimeout}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return 0, err
+	}
+	rtt := time.Since(start)
+	conn.Close()
+	return rtt, nil
+}
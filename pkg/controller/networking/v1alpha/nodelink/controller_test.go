@@ -0,0 +1,104 @@
+package nodelink
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubetestclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+func newNode(name, address string, measured bool) *corev1.Node {
+	nodeObj := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}},
+		Status:     corev1.NodeStatus{Addresses: []corev1.NodeAddress{{Type: corev1.NodeExternalIP, Address: address}}},
+	}
+	if measured {
+		nodeObj.Labels[ProbeLabel] = "true"
+	}
+	return nodeObj
+}
+
+func TestMeasure(t *testing.T) {
+	kubeclientset := kubetestclient.NewSimpleClientset(
+		newNode("paris", "192.0.2.1", true),
+		newNode("lyon", "192.0.2.2", true),
+		newNode("tokyo", "192.0.2.3", true),
+		newNode("berlin", "192.0.2.4", false),
+	)
+	edgenetclientset := edgenettestclient.NewSimpleClientset()
+	c := NewController(kubeclientset, edgenetclientset, "paris")
+	probes := map[string]int{}
+	c.probe = func(ctx context.Context, address string) (time.Duration, error) {
+		probes[address]++
+		switch address {
+		case "192.0.2.2:10250":
+			return 4 * time.Millisecond, nil
+		case "192.0.2.3:10250":
+			if probes[address]%2 == 0 {
+				return 0, errors.New("i/o timeout")
+			}
+			return 240 * time.Millisecond, nil
+		}
+		return 0, errors.New("connection refused")
+	}
+
+	util.OK(t, c.measure(context.TODO()))
+	_, err := edgenetclientset.NetworkingV1alpha().NodeLinks().Get(context.TODO(), Name("paris", "berlin"), metav1.GetOptions{})
+	util.Assert(t, err != nil, "link to a node not measured created")
+	lyon, err := edgenetclientset.NetworkingV1alpha().NodeLinks().Get(context.TODO(), Name("paris", "lyon"), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, "paris", lyon.Spec.Source)
+	util.Equals(t, "lyon", lyon.Spec.Target)
+	util.Equals(t, "192.0.2.2", lyon.Status.Address)
+	util.Equals(t, 4*time.Millisecond, lyon.Status.Latency.Duration)
+	util.Equals(t, 0, lyon.Status.PacketLoss)
+	util.Equals(t, "Node", lyon.GetOwnerReferences()[0].Kind)
+	tokyo, err := edgenetclientset.NetworkingV1alpha().NodeLinks().Get(context.TODO(), Name("paris", "tokyo"), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, 240*time.Millisecond, tokyo.Status.Latency.Duration)
+	util.Equals(t, 50, tokyo.Status.PacketLoss)
+
+	t.Run("unreachable", func(t *testing.T) {
+		unreachable := newNode("lyon", "192.0.2.5", true)
+		_, err := kubeclientset.CoreV1().Nodes().Update(context.TODO(), unreachable, metav1.UpdateOptions{})
+		util.OK(t, err)
+		util.OK(t, c.measure(context.TODO()))
+		lyon, err := edgenetclientset.NetworkingV1alpha().NodeLinks().Get(context.TODO(), Name("paris", "lyon"), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Assert(t, lyon.Status.Latency == nil, "latency of an unreachable node set")
+		util.Equals(t, 100, lyon.Status.PacketLoss)
+	})
+	t.Run("not measured", func(t *testing.T) {
+		excluded := newNode("tokyo", "192.0.2.3", false)
+		_, err := kubeclientset.CoreV1().Nodes().Update(context.TODO(), excluded, metav1.UpdateOptions{})
+		util.OK(t, err)
+		util.OK(t, c.measure(context.TODO()))
+		_, err = edgenetclientset.NetworkingV1alpha().NodeLinks().Get(context.TODO(), Name("paris", "tokyo"), metav1.GetOptions{})
+		util.Assert(t, err != nil, "link to a node no longer measured kept")
+
+		source := newNode("paris", "192.0.2.1", false)
+		_, err = kubeclientset.CoreV1().Nodes().Update(context.TODO(), source, metav1.UpdateOptions{})
+		util.OK(t, err)
+		util.OK(t, c.measure(context.TODO()))
+		linkRaw, err := edgenetclientset.NetworkingV1alpha().NodeLinks().List(context.TODO(), metav1.ListOptions{})
+		util.OK(t, err)
+		util.Equals(t, 0, len(linkRaw.Items))
+	})
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package vpnpeer
 
 import (
+	"context"
 	"fmt"
 	"github.com/EdgeNet-project/edgenet/pkg/apis/networking/v1alpha"
 	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
@@ -29,6 +30,7 @@ import (
 	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
 	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/networking/v1alpha"
 	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/networking/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
 	corev1 "k8s.io/api/core/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -66,7 +68,7 @@ func NewController(
 	vpnpeerInformer informers.VPNPeerInformer,
 	linkname string) *Controller {
 	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
-	klog.V(4).Info("Creating event broadcaster")
+	klog.V(4).InfoS("Creating event broadcaster")
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartStructuredLogging(0)
 	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
@@ -82,7 +84,7 @@ func NewController(
 		linkname:         linkname,
 	}
 
-	klog.Info("Setting up event handlers")
+	klog.InfoS("Setting up event handlers")
 	vpnpeerInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    controller.enqueueVPNPeer,
 		DeleteFunc: controller.enqueueVPNPeer,
@@ -102,22 +104,23 @@ func NewController(
 func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
 
-	klog.Info("Starting VPNPeer controller")
+	klog.InfoS("Starting VPNPeer controller")
 
-	klog.Info("Waiting for informer caches to sync")
+	klog.InfoS("Waiting for informer caches to sync")
 	if ok := cache.WaitForCacheSync(stopCh, c.vpnpeersSynced); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
-	klog.Info("Starting workers")
+	klog.InfoS("Starting workers")
 	for i := 0; i < threadiness; i++ {
-		go wait.Until(c.runWorker, time.Second, stopCh)
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
 	}
 
-	klog.Info("Started workers")
+	klog.InfoS("Started workers")
 	<-stopCh
-	klog.Info("Shutting down workers")
+	klog.InfoS("Shutting down workers")
 
 	return nil
 }
@@ -125,14 +128,14 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 // runWorker is a long-running function that will continually call the
 // processNextWorkItem function in order to read and process a message on the
 // workqueue.
-func (c *Controller) runWorker() {
-	for c.processNextWorkItem() {
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
 	}
 }
 
 // processNextWorkItem will read a single work item off the workqueue and
 // attempt to process it, by calling the syncHandler.
-func (c *Controller) processNextWorkItem() bool {
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
 	obj, shutdown := c.workqueue.Get()
 
 	if shutdown {
@@ -150,13 +153,13 @@ func (c *Controller) processNextWorkItem() bool {
 			return nil
 		}
 
-		if err := c.syncHandler(key); err != nil {
+		if err := c.syncHandler(ctx, key); err != nil {
 			c.workqueue.AddRateLimited(key)
-			return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
 		}
 
 		c.workqueue.Forget(obj)
-		klog.Infof("Successfully synced '%s'", key)
+		klog.InfoS("Successfully synced", "key", key)
 		return nil
 	}(obj)
 
@@ -171,7 +174,7 @@ func (c *Controller) processNextWorkItem() bool {
 // syncHandler compares the actual state with the desired, and attempts to
 // converge the two. It then updates the Status block of the VPNPeer resource
 // with the current status of the resource.
-func (c *Controller) syncHandler(key string) error {
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
 	peers, err := c.vpnpeersLister.List(labels.Everything())
 	if err != nil {
 		return err
@@ -188,14 +191,14 @@ func (c *Controller) syncHandler(key string) error {
 		if err != nil {
 			return err
 		}
-		klog.Infof("Peer with public key %s removed", key)
+		klog.InfoS("Peer with public key removed", "key", key)
 	} else {
 		// B. Creation/Update
 		err = addPeer(c.linkname, *peer)
 		if err != nil {
 			return err
 		}
-		klog.Infof("Peer with public key %s synced", key)
+		klog.InfoS("Peer with public key synced", "key", key)
 		c.recorder.Event(peer, corev1.EventTypeNormal, SuccessSynced, MessageResourceSynced)
 	}
 
@@ -225,26 +228,33 @@ func findPeer(peers []*v1alpha.VPNPeer, publicKey string) (*v1alpha.VPNPeer, err
 	return found, nil
 }
 
+// peerAllowedIPs returns the host routes of the addresses of the peer, a peer on a single-stack
+// node has only one of them
+func peerAllowedIPs(peer v1alpha.VPNPeer) []net.IPNet {
+	allowedIPs := []net.IPNet{}
+	if ip := net.ParseIP(peer.Spec.AddressV4); ip != nil && ip.To4() != nil {
+		allowedIPs = append(allowedIPs, net.IPNet{IP: ip, Mask: net.CIDRMask(32, 32)})
+	}
+	if ip := net.ParseIP(peer.Spec.AddressV6); ip != nil && ip.To4() == nil {
+		allowedIPs = append(allowedIPs, net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)})
+	}
+	return allowedIPs
+}
+
 func addPeer(linkname string, peer v1alpha.VPNPeer) error {
 	client, err := wgctrl.New()
 	if err != nil {
-		return fmt.Errorf("error while creating WG client: %s", err.Error())
+		return fmt.Errorf("error while creating WG client: %w", err)
 	}
 
 	publicKey, err := wgtypes.ParseKey(peer.Spec.PublicKey)
 	if err != nil {
-		return fmt.Errorf("error while parsing WG public key: %s", err.Error())
+		return fmt.Errorf("error while parsing WG public key: %w", err)
 	}
 
-	allowedIPs := []net.IPNet{
-		{
-			IP:   net.ParseIP(peer.Spec.AddressV4),
-			Mask: net.CIDRMask(32, 32),
-		},
-		{
-			IP:   net.ParseIP(peer.Spec.AddressV6),
-			Mask: net.CIDRMask(128, 128),
-		},
+	allowedIPs := peerAllowedIPs(peer)
+	if len(allowedIPs) == 0 {
+		return fmt.Errorf("peer %s has no valid address", peer.GetName())
 	}
 
 	var endpoint *net.UDPAddr
@@ -274,7 +284,7 @@ func addPeer(linkname string, peer v1alpha.VPNPeer) error {
 
 	err = client.ConfigureDevice(linkname, deviceConfig)
 	if err != nil {
-		return fmt.Errorf("error while configure WG device %s: %s", linkname, err.Error())
+		return fmt.Errorf("error while configure WG device %s: %w", linkname, err)
 	}
 
 	return nil
@@ -283,12 +293,12 @@ func addPeer(linkname string, peer v1alpha.VPNPeer) error {
 func removePeer(linkname string, publicKey string) error {
 	client, err := wgctrl.New()
 	if err != nil {
-		return fmt.Errorf("error while creating WG client: %s", err.Error())
+		return fmt.Errorf("error while creating WG client: %w", err)
 	}
 
 	pk, err := wgtypes.ParseKey(publicKey)
 	if err != nil {
-		return fmt.Errorf("error while parsing WG public key: %s", err.Error())
+		return fmt.Errorf("error while parsing WG public key: %w", err)
 	}
 
 	peerConfig := wgtypes.PeerConfig{
@@ -303,7 +313,7 @@ func removePeer(linkname string, publicKey string) error {
 
 	err = client.ConfigureDevice(linkname, deviceConfig)
 	if err != nil {
-		return fmt.Errorf("error while configure WG device %s: %s", linkname, err.Error())
+		return fmt.Errorf("error while configure WG device %s: %w", linkname, err)
 	}
 
 	return nil
@@ -30,6 +30,7 @@ import (
 	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
 	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/registration/v1alpha"
 	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -42,7 +43,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 const controllerAgentName = "clusterrolerequest-controller"
@@ -94,7 +95,7 @@ func NewController(
 	clusterrolerequestInformer informers.ClusterRoleRequestInformer) *Controller {
 
 	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
-	klog.V(4).Info("Creating event broadcaster")
+	klog.V(4).InfoS("Creating event broadcaster")
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartStructuredLogging(0)
 	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
@@ -109,7 +110,7 @@ func NewController(
 		recorder:                  recorder,
 	}
 
-	klog.V(4).Infoln("Setting up event handlers")
+	klog.V(4).InfoS("Setting up event handlers")
 	// Set up an event handler for when Cluster Role Request resources change
 	clusterrolerequestInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: controller.enqueueClusterRoleRequest,
@@ -137,23 +138,24 @@ func NewController(
 func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
 
-	klog.V(4).Infoln("Starting Cluster Role Request controller")
+	klog.V(4).InfoS("Starting Cluster Role Request controller")
 
-	klog.V(4).Infoln("Waiting for informer caches to sync")
+	klog.V(4).InfoS("Waiting for informer caches to sync")
 	if ok := cache.WaitForCacheSync(stopCh,
 		c.clusterrolerequestsSynced); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
-	klog.V(4).Infoln("Starting workers")
+	klog.V(4).InfoS("Starting workers")
 	for i := 0; i < threadiness; i++ {
-		go wait.Until(c.runWorker, time.Second, stopCh)
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
 	}
 
-	klog.V(4).Infoln("Started workers")
+	klog.V(4).InfoS("Started workers")
 	<-stopCh
-	klog.V(4).Infoln("Shutting down workers")
+	klog.V(4).InfoS("Shutting down workers")
 
 	return nil
 }
@@ -161,14 +163,14 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 // runWorker is a long-running function that will continually call the
 // processNextWorkItem function in order to read and process a message on the
 // workqueue.
-func (c *Controller) runWorker() {
-	for c.processNextWorkItem() {
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
 	}
 }
 
 // processNextWorkItem will read a single work item off the workqueue and
 // attempt to process it, by calling the syncHandler.
-func (c *Controller) processNextWorkItem() bool {
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
 	obj, shutdown := c.workqueue.Get()
 
 	if shutdown {
@@ -185,12 +187,12 @@ func (c *Controller) processNextWorkItem() bool {
 			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
 			return nil
 		}
-		if err := c.syncHandler(key); err != nil {
+		if err := c.syncHandler(ctx, key); err != nil {
 			c.workqueue.AddRateLimited(key)
-			return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
 		}
 		c.workqueue.Forget(obj)
-		klog.V(4).Infof("Successfully synced '%s'", key)
+		klog.V(4).InfoS("Successfully synced", "key", key)
 		return nil
 	}(obj)
 
@@ -205,7 +207,7 @@ func (c *Controller) processNextWorkItem() bool {
 // syncHandler compares the actual state with the desired, and attempts to
 // converge the two. It then updates the Status block of the Cluster Role Request
 // resource with the current status of the resource.
-func (c *Controller) syncHandler(key string) error {
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
 	_, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
 		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
@@ -224,7 +226,7 @@ func (c *Controller) syncHandler(key string) error {
 	}
 
 	if clusterrolerequest.Status.State != approved {
-		c.processClusterRoleRequest(clusterrolerequest.DeepCopy())
+		c.processClusterRoleRequest(ctx, clusterrolerequest.DeepCopy())
 	}
 	c.recorder.Event(clusterrolerequest, corev1.EventTypeNormal, successSynced, messageResourceSynced)
 	return nil
@@ -256,12 +258,12 @@ func (c *Controller) enqueueClusterRoleRequestAfter(obj interface{}, after time.
 	c.workqueue.AddAfter(key, after)
 }
 
-func (c *Controller) processClusterRoleRequest(clusterRoleRequestCopy *registrationv1alpha.ClusterRoleRequest) {
+func (c *Controller) processClusterRoleRequest(ctx context.Context, clusterRoleRequestCopy *registrationv1alpha.ClusterRoleRequest) {
 	oldStatus := clusterRoleRequestCopy.Status
 	statusUpdate := func() {
 		if !reflect.DeepEqual(oldStatus, clusterRoleRequestCopy.Status) {
-			if _, err := c.edgenetclientset.RegistrationV1alpha().ClusterRoleRequests().UpdateStatus(context.TODO(), clusterRoleRequestCopy, metav1.UpdateOptions{}); err != nil {
-				klog.V(4).Infoln(err)
+			if _, err := c.edgenetclientset.RegistrationV1alpha().ClusterRoleRequests().UpdateStatus(ctx, clusterRoleRequestCopy, metav1.UpdateOptions{}); err != nil {
+				klog.ErrorS(err, "Couldn't update the status of the cluster role request", "clusterRoleRequest", klog.KObj(clusterRoleRequestCopy))
 			}
 		}
 	}
@@ -271,14 +273,14 @@ func (c *Controller) processClusterRoleRequest(clusterRoleRequestCopy *registrat
 			Time: time.Now().Add(72 * time.Hour),
 		}
 	} else if time.Until(clusterRoleRequestCopy.Status.Expiry.Time) <= 0 {
-		c.edgenetclientset.RegistrationV1alpha().ClusterRoleRequests().Delete(context.TODO(), clusterRoleRequestCopy.GetName(), metav1.DeleteOptions{})
+		c.edgenetclientset.RegistrationV1alpha().ClusterRoleRequests().Delete(ctx, clusterRoleRequestCopy.GetName(), metav1.DeleteOptions{})
 		return
 	}
 	defer statusUpdate()
 
 	// Below is to ensure that the requested Role / ClusterRole exists before moving forward in the procedure.
 	// If not, the status of the object falls into an error state.
-	roleExists := c.checkForRequestedRole(clusterRoleRequestCopy)
+	roleExists := c.checkForRequestedRole(ctx, clusterRoleRequestCopy)
 	if !roleExists {
 		return
 	}
@@ -298,7 +300,7 @@ func (c *Controller) processClusterRoleRequest(clusterRoleRequestCopy *registrat
 		// The following section handles cluster role binding. There are two basic logical steps here.
 		// Check if cluster role binding already exists; if not, create a cluster role binding for the user.
 		// If cluster role binding exists, check if the user already holds the role. If not, pin the cluster role to the user.
-		if clusterRoleBindingRaw, err := c.kubeclientset.RbacV1().ClusterRoleBindings().List(context.TODO(), metav1.ListOptions{LabelSelector: "edge-net.io/generated=true"}); err == nil {
+		if clusterRoleBindingRaw, err := c.kubeclientset.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{LabelSelector: "edge-net.io/generated=true"}); err == nil {
 			// TODO: Simplfy below
 			clusterRoleBindingExists := false
 			clusterRoleBound := false
@@ -313,11 +315,11 @@ func (c *Controller) processClusterRoleRequest(clusterRoleRequestCopy *registrat
 					if !clusterRoleBound {
 						clusterRoleBindingCopy := clusterRoleBindingRow.DeepCopy()
 						clusterRoleBindingCopy.Subjects = append(clusterRoleBindingCopy.Subjects, rbacv1.Subject{Kind: "User", Name: clusterRoleRequestCopy.Spec.Email, APIGroup: "rbac.authorization.k8s.io"})
-						if _, err := c.kubeclientset.RbacV1().ClusterRoleBindings().Update(context.TODO(), clusterRoleBindingCopy, metav1.UpdateOptions{}); err != nil {
+						if _, err := c.kubeclientset.RbacV1().ClusterRoleBindings().Update(ctx, clusterRoleBindingCopy, metav1.UpdateOptions{}); err != nil {
 							c.recorder.Event(clusterRoleBindingCopy, corev1.EventTypeWarning, failureBinding, messageBindingFailed)
 							clusterRoleRequestCopy.Status.State = failure
 							clusterRoleRequestCopy.Status.Message = messageBindingFailed
-							klog.V(4).Infoln(err)
+							klog.ErrorS(err, "Couldn't update the cluster role binding", "clusterRoleBinding", klog.KObj(clusterRoleBindingCopy))
 						}
 						break
 					}
@@ -331,19 +333,19 @@ func (c *Controller) processClusterRoleRequest(clusterRoleRequestCopy *registrat
 					Subjects: rbSubjects, RoleRef: roleRef}
 				clusterRoleBindLabels := map[string]string{"edge-net.io/generated": "true"}
 				clusterRoleBind.SetLabels(clusterRoleBindLabels)
-				if _, err := c.kubeclientset.RbacV1().ClusterRoleBindings().Create(context.TODO(), clusterRoleBind, metav1.CreateOptions{}); err != nil {
+				if _, err := c.kubeclientset.RbacV1().ClusterRoleBindings().Create(ctx, clusterRoleBind, metav1.CreateOptions{}); err != nil {
 					c.recorder.Event(clusterRoleRequestCopy, corev1.EventTypeWarning, failureBinding, messageBindingFailed)
 					clusterRoleRequestCopy.Status.State = failure
 					clusterRoleRequestCopy.Status.Message = messageBindingFailed
-					klog.V(4).Infoln(err)
+					klog.ErrorS(err, "Couldn't create the cluster role binding", "clusterRoleBinding", klog.KObj(clusterRoleBind))
 				}
 			}
 		}
 	}
 }
 
-func (c *Controller) checkForRequestedRole(clusterRoleRequestCopy *registrationv1alpha.ClusterRoleRequest) bool {
-	if clusterRoleRaw, err := c.kubeclientset.RbacV1().ClusterRoles().List(context.TODO(), metav1.ListOptions{}); err == nil {
+func (c *Controller) checkForRequestedRole(ctx context.Context, clusterRoleRequestCopy *registrationv1alpha.ClusterRoleRequest) bool {
+	if clusterRoleRaw, err := c.kubeclientset.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{}); err == nil {
 		for _, clusterRoleRow := range clusterRoleRaw.Items {
 			if clusterRoleRow.GetName() == clusterRoleRequestCopy.Spec.RoleName {
 				c.recorder.Event(clusterRoleRequestCopy, corev1.EventTypeNormal, successFound, messageRoleFound)
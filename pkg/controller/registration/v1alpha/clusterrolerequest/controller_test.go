@@ -22,7 +22,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	testclient "k8s.io/client-go/kubernetes/fake"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 type TestGroup struct {
@@ -58,7 +58,7 @@ func TestMain(m *testing.M) {
 	}()
 
 	access.Clientset = kubeclientset
-	access.CreateClusterRoles()
+	access.ReconcileClusterRoles(context.TODO())
 
 	time.Sleep(500 * time.Millisecond)
 
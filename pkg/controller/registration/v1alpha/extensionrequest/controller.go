@@ -0,0 +1,441 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extensionrequest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	appsv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/apps/v1alpha"
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/config"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/registration/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const controllerAgentName = "extensionrequest-controller"
+
+// Definitions of the state of the extensionrequest resource
+const (
+	successSynced                = "Synced"
+	messageResourceSynced        = "Extension Request synced successfully"
+	failureFound                 = "Not Found"
+	messageExtensionNotFound     = "Requested extension is not in the catalog or not enabled"
+	failureNamespaced            = "Not Namespaced"
+	messageExtensionNotNamespace = "Requested extension serves resources that are not namespaced"
+	warningApproved              = "Not Approved"
+	messageNotApproved           = "Waiting for Requested Extension to be approved"
+	successApproved              = "Approved"
+	messageApproved              = "Requested Extension approved and installed successfully"
+	failureInstallation          = "Installation Failed"
+	messageInstallationFailed    = "Extension installation failed"
+	failure                      = "Failure"
+	pending                      = "Pending"
+	approved                     = "Approved"
+)
+
+// Controller is the controller implementation for Extension Request resources
+type Controller struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+	// edgenetclientset is a clientset for the EdgeNet API groups
+	edgenetclientset clientset.Interface
+
+	extensionrequestsLister listers.ExtensionRequestLister
+	extensionrequestsSynced cache.InformerSynced
+
+	// workqueue is a rate limited work queue. This is used to queue work to be
+	// processed instead of performing it as soon as a change happens. This
+	// means we can ensure we only process a fixed amount of resources at a
+	// time, and makes it easy to ensure we are never processing the same item
+	// simultaneously in two different workers.
+	workqueue workqueue.RateLimitingInterface
+	// recorder is an event recorder for recording Event resources to the
+	// Kubernetes API.
+	recorder record.EventRecorder
+	// identity is the identity of the cluster that the objects are labeled with
+	identity *config.ClusterIdentity
+}
+
+// NewController returns a new controller
+func NewController(
+	kubeclientset kubernetes.Interface,
+	edgenetclientset clientset.Interface,
+	extensionrequestInformer informers.ExtensionRequestInformer) *Controller {
+
+	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
+	klog.V(4).InfoS("Creating event broadcaster")
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartStructuredLogging(0)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+
+	controller := &Controller{
+		identity:                config.NewClusterIdentity(kubeclientset),
+		kubeclientset:           kubeclientset,
+		edgenetclientset:        edgenetclientset,
+		extensionrequestsLister: extensionrequestInformer.Lister(),
+		extensionrequestsSynced: extensionrequestInformer.Informer().HasSynced,
+		workqueue:               workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ExtensionRequests"),
+		recorder:                recorder,
+	}
+
+	klog.V(4).InfoS("Setting up event handlers")
+	// Set up an event handler for when Extension Request resources change
+	extensionrequestInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueExtensionRequest,
+		UpdateFunc: func(old, new interface{}) {
+			newExtensionRequest := new.(*registrationv1alpha.ExtensionRequest)
+			oldExtensionRequest := old.(*registrationv1alpha.ExtensionRequest)
+			// The expiry is unset until the request is first processed
+			if newExtensionRequest.Status.Expiry != nil &&
+				(oldExtensionRequest.Status.Expiry == nil || !oldExtensionRequest.Status.Expiry.Time.Equal(newExtensionRequest.Status.Expiry.Time)) {
+				controller.enqueueExtensionRequestAfter(newExtensionRequest, time.Until(newExtensionRequest.Status.Expiry.Time))
+			}
+			controller.enqueueExtensionRequest(new)
+		},
+	})
+
+	access.Clientset = kubeclientset
+	access.EdgenetClientset = edgenetclientset
+
+	return controller
+}
+
+// Run will set up the event handlers for the types of extension request, as well
+// as syncing informer caches and starting workers. It will block until stopCh
+// is closed, at which point it will shutdown the workqueue and wait for
+// workers to finish processing their current work items.
+func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting Extension Request controller")
+
+	klog.V(4).InfoS("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh,
+		c.extensionrequestsSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	klog.V(4).InfoS("Starting workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	klog.V(4).InfoS("Started workers")
+	<-stopCh
+	klog.V(4).InfoS("Shutting down workers")
+
+	return nil
+}
+
+// runWorker is a long-running function that will continually call the
+// processNextWorkItem function in order to read and process a message on the
+// workqueue.
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+// processNextWorkItem will read a single work item off the workqueue and
+// attempt to process it, by calling the syncHandler.
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.workqueue.Done(obj)
+		var key string
+		var ok bool
+
+		if key, ok = obj.(string); !ok {
+			c.workqueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			return nil
+		}
+		if err := c.syncHandler(ctx, key); err != nil {
+			c.workqueue.AddRateLimited(key)
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
+		}
+		c.workqueue.Forget(obj)
+		klog.V(4).InfoS("Successfully synced", "key", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+
+	return true
+}
+
+// syncHandler compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the Extension Request
+// resource with the current status of the resource.
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	extensionrequest, err := c.extensionrequestsLister.ExtensionRequests(namespace).Get(name)
+
+	if err != nil {
+		if errors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("extensionrequest '%s' in work queue no longer exists", key))
+			return nil
+		}
+
+		return err
+	}
+
+	if extensionrequest.Status.State != approved {
+		c.processExtensionRequest(ctx, extensionrequest.DeepCopy())
+	}
+	c.recorder.Event(extensionrequest, corev1.EventTypeNormal, successSynced, messageResourceSynced)
+	return nil
+}
+
+// enqueueExtensionRequest takes an ExtensionRequest resource and converts it into a namespace/name
+// string which is then put onto the work queue. This method should *not* be
+// passed resources of any type other than ExtensionRequest.
+func (c *Controller) enqueueExtensionRequest(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+// enqueueExtensionRequestAfter takes an ExtensionRequest resource and converts it into a namespace/name
+// string which is then put onto the work queue after the expiry date to be deleted. This method should *not* be
+// passed resources of any type other than ExtensionRequest.
+func (c *Controller) enqueueExtensionRequestAfter(obj interface{}, after time.Duration) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.AddAfter(key, after)
+}
+
+func (c *Controller) processExtensionRequest(ctx context.Context, extensionRequestCopy *registrationv1alpha.ExtensionRequest) {
+	oldStatus := extensionRequestCopy.Status
+	statusUpdate := func() {
+		if !reflect.DeepEqual(oldStatus, extensionRequestCopy.Status) {
+			if _, err := c.edgenetclientset.RegistrationV1alpha().ExtensionRequests(extensionRequestCopy.GetNamespace()).UpdateStatus(ctx, extensionRequestCopy, metav1.UpdateOptions{}); err != nil {
+				klog.ErrorS(err, "Couldn't update the status of the extension request", "extensionRequest", klog.KObj(extensionRequestCopy))
+			}
+		}
+	}
+	if extensionRequestCopy.Status.Expiry == nil {
+		// Set the approval timeout which is 72 hours
+		extensionRequestCopy.Status.Expiry = &metav1.Time{
+			Time: time.Now().Add(72 * time.Hour),
+		}
+	} else if time.Until(extensionRequestCopy.Status.Expiry.Time) <= 0 {
+		c.edgenetclientset.RegistrationV1alpha().ExtensionRequests(extensionRequestCopy.GetNamespace()).Delete(ctx, extensionRequestCopy.GetName(), metav1.DeleteOptions{})
+		return
+	}
+	defer statusUpdate()
+
+	// Below code checks whether namespace, where extension request made, is local to the cluster and belongs to an enabled tenant.
+	// A namespace that another cluster propagates is not a tenant of this one, the extension is not installed there.
+	permitted := false
+	clusterUID, err := c.identity.UID(ctx)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't read the cluster UID")
+		c.edgenetclientset.RegistrationV1alpha().ExtensionRequests(extensionRequestCopy.GetNamespace()).Delete(ctx, extensionRequestCopy.GetName(), metav1.DeleteOptions{})
+		return
+	}
+	namespace, err := c.kubeclientset.CoreV1().Namespaces().Get(ctx, extensionRequestCopy.GetNamespace(), metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Couldn't get the namespace", "namespace", extensionRequestCopy.GetNamespace())
+		c.edgenetclientset.RegistrationV1alpha().ExtensionRequests(extensionRequestCopy.GetNamespace()).Delete(ctx, extensionRequestCopy.GetName(), metav1.DeleteOptions{})
+		return
+	}
+	namespaceLabels := namespace.GetLabels()
+	if clusterUID == namespaceLabels["edge-net.io/cluster-uid"] {
+		tenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(ctx, strings.ToLower(namespaceLabels["edge-net.io/tenant"]), metav1.GetOptions{})
+		if err != nil {
+			klog.ErrorS(err, "Couldn't get the tenant", "tenant", strings.ToLower(namespaceLabels["edge-net.io/tenant"]))
+			c.edgenetclientset.RegistrationV1alpha().ExtensionRequests(extensionRequestCopy.GetNamespace()).Delete(ctx, extensionRequestCopy.GetName(), metav1.DeleteOptions{})
+			return
+		}
+		if tenant.GetUID() == types.UID(namespaceLabels["edge-net.io/tenant-uid"]) && tenant.Spec.Enabled {
+			permitted = true
+		}
+	}
+
+	if permitted {
+		// Only the extensions that the cluster administrators put into the catalog can be installed,
+		// and only if all resources they serve are namespaced so that a tenant cannot affect the others
+		extension, err := c.edgenetclientset.AppsV1alpha().Extensions().Get(ctx, extensionRequestCopy.Spec.Extension, metav1.GetOptions{})
+		if err != nil || !extension.Spec.Enabled {
+			c.recorder.Event(extensionRequestCopy, corev1.EventTypeWarning, failureFound, messageExtensionNotFound)
+			extensionRequestCopy.Status.State = failure
+			extensionRequestCopy.Status.Message = messageExtensionNotFound
+			return
+		}
+		if !c.checkNamespaced(extension) {
+			c.recorder.Event(extensionRequestCopy, corev1.EventTypeWarning, failureNamespaced, messageExtensionNotNamespace)
+			extensionRequestCopy.Status.State = failure
+			extensionRequestCopy.Status.Message = messageExtensionNotNamespace
+			return
+		}
+
+		if !extensionRequestCopy.Status.Approved {
+			if extensionRequestCopy.Status.State == pending && extensionRequestCopy.Status.Message == messageNotApproved {
+				return
+			}
+			c.recorder.Event(extensionRequestCopy, corev1.EventTypeWarning, warningApproved, messageNotApproved)
+			extensionRequestCopy.Status.State = pending
+			extensionRequestCopy.Status.Message = messageNotApproved
+		} else {
+			if err := c.installExtension(ctx, extensionRequestCopy, extension); err != nil {
+				klog.ErrorS(err, "Couldn't install the extension", "extensionRequest", klog.KObj(extensionRequestCopy))
+				c.recorder.Event(extensionRequestCopy, corev1.EventTypeWarning, failureInstallation, messageInstallationFailed)
+				extensionRequestCopy.Status.State = failure
+				extensionRequestCopy.Status.Message = messageInstallationFailed
+				return
+			}
+			c.recorder.Event(extensionRequestCopy, corev1.EventTypeNormal, successApproved, messageApproved)
+			extensionRequestCopy.Status.State = approved
+			extensionRequestCopy.Status.Message = messageApproved
+		}
+	} else {
+		c.edgenetclientset.RegistrationV1alpha().ExtensionRequests(extensionRequestCopy.GetNamespace()).Delete(ctx, extensionRequestCopy.GetName(), metav1.DeleteOptions{})
+	}
+}
+
+// checkNamespaced returns true if the API server serves all resources of the extension as namespaced
+func (c *Controller) checkNamespaced(extension *appsv1alpha.Extension) bool {
+	for _, customResource := range extension.Spec.CustomResources {
+		resourceList, err := c.kubeclientset.Discovery().ServerResourcesForGroupVersion(fmt.Sprintf("%s/%s", customResource.Group, customResource.Version))
+		if err != nil {
+			klog.ErrorS(err, "Couldn't discover the resources", "group", customResource.Group, "version", customResource.Version)
+			return false
+		}
+		for _, resource := range customResource.Resources {
+			namespaced := false
+			for _, apiResource := range resourceList.APIResources {
+				if apiResource.Name == resource {
+					namespaced = apiResource.Namespaced
+					break
+				}
+			}
+			if !namespaced {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// installExtension deploys the operator of the extension into the namespace of the request, and grants
+// the requester access to the custom resources. The request owns all these objects, so that they are
+// removed along with the request or the namespace.
+func (c *Controller) installExtension(ctx context.Context, extensionRequestCopy *registrationv1alpha.ExtensionRequest, extension *appsv1alpha.Extension) error {
+	ownerReferences := SetAsOwnerReference(extensionRequestCopy)
+	objectName := fmt.Sprintf("edgenet-extension-%s", extension.GetName())
+	objectLabels := map[string]string{"edge-net.io/generated": "true", "edge-net.io/extension": extension.GetName()}
+	objectMeta := metav1.ObjectMeta{Name: objectName, Namespace: extensionRequestCopy.GetNamespace(), Labels: objectLabels, OwnerReferences: ownerReferences}
+
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: objectMeta}
+	if _, err := c.kubeclientset.CoreV1().ServiceAccounts(extensionRequestCopy.GetNamespace()).Create(ctx, serviceAccount, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	operatorRole := &rbacv1.Role{ObjectMeta: objectMeta, Rules: extension.Spec.Rules}
+	if _, err := c.kubeclientset.RbacV1().Roles(extensionRequestCopy.GetNamespace()).Create(ctx, operatorRole, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	operatorRoleBinding := &rbacv1.RoleBinding{ObjectMeta: objectMeta,
+		Subjects: []rbacv1.Subject{{Kind: "ServiceAccount", Name: objectName, Namespace: extensionRequestCopy.GetNamespace()}},
+		RoleRef:  rbacv1.RoleRef{Kind: "Role", Name: objectName}}
+	if _, err := c.kubeclientset.RbacV1().RoleBindings(extensionRequestCopy.GetNamespace()).Create(ctx, operatorRoleBinding, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	deployment := &appsv1.Deployment{ObjectMeta: objectMeta, Spec: *extension.Spec.Deployment.DeepCopy()}
+	deployment.Spec.Template.Spec.ServiceAccountName = objectName
+	if _, err := c.kubeclientset.AppsV1().Deployments(extensionRequestCopy.GetNamespace()).Create(ctx, deployment, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	// The requester manages the custom resources of the extension in the namespace. The webhook admits
+	// the request only if its email is the name the requester authenticated with, and keeps it immutable.
+	userObjectMeta := *objectMeta.DeepCopy()
+	userObjectMeta.Name = fmt.Sprintf("%s-user", objectName)
+	userRole := &rbacv1.Role{ObjectMeta: userObjectMeta}
+	for _, customResource := range extension.Spec.CustomResources {
+		rule := rbacv1.PolicyRule{APIGroups: []string{customResource.Group}, Resources: customResource.Resources, Verbs: []string{"*"}}
+		userRole.Rules = append(userRole.Rules, rule)
+	}
+	if _, err := c.kubeclientset.RbacV1().Roles(extensionRequestCopy.GetNamespace()).Create(ctx, userRole, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	userRoleBinding := &rbacv1.RoleBinding{ObjectMeta: userObjectMeta,
+		Subjects: []rbacv1.Subject{{Kind: "User", Name: extensionRequestCopy.Spec.Email, APIGroup: "rbac.authorization.k8s.io"}},
+		RoleRef:  rbacv1.RoleRef{Kind: "Role", Name: userObjectMeta.Name}}
+	if _, err := c.kubeclientset.RbacV1().RoleBindings(extensionRequestCopy.GetNamespace()).Create(ctx, userRoleBinding, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// SetAsOwnerReference returns the extension request as owner
+func SetAsOwnerReference(extensionRequest *registrationv1alpha.ExtensionRequest) []metav1.OwnerReference {
+	// The following section makes extension request become the owner
+	ownerReferences := []metav1.OwnerReference{}
+	newExtensionRequestRef := *metav1.NewControllerRef(extensionRequest, registrationv1alpha.SchemeGroupVersion.WithKind("ExtensionRequest"))
+	takeControl := true
+	newExtensionRequestRef.Controller = &takeControl
+	ownerReferences = append(ownerReferences, newExtensionRequestRef)
+	return ownerReferences
+}
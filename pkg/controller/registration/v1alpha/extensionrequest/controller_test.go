@@ -0,0 +1,275 @@
+package extensionrequest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	appsv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/apps/v1alpha"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+	"github.com/sirupsen/logrus"
+
+	appsv1 "k8s.io/api/apps/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+)
+
+type TestGroup struct {
+	tenantObj           corev1alpha.Tenant
+	extensionObj        appsv1alpha.Extension
+	extensionRequestObj registrationv1alpha.ExtensionRequest
+}
+
+var kubeclientset kubernetes.Interface = testclient.NewSimpleClientset()
+var edgenetclientset versioned.Interface = edgenettestclient.NewSimpleClientset()
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	logrus.SetOutput(ioutil.Discard)
+
+	flag.String("dir", "../../../../..", "Override the directory.")
+	flag.String("smtp-path", "../../../../../configs/smtp_test.yaml", "Set SMTP path.")
+	flag.Parse()
+
+	stopCh := signals.SetupSignalHandler()
+
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, time.Second*30)
+
+	controller := NewController(kubeclientset,
+		edgenetclientset,
+		edgenetInformerFactory.Registration().V1alpha().ExtensionRequests())
+
+	edgenetInformerFactory.Start(stopCh)
+
+	go func() {
+		if err := controller.Run(2, stopCh); err != nil {
+			klog.Fatalf("Error running controller: %s", err.Error())
+		}
+	}()
+
+	access.Clientset = kubeclientset
+	kubeSystemNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", UID: "kube-system-uid"}}
+	kubeclientset.CoreV1().Namespaces().Create(context.TODO(), kubeSystemNamespace, metav1.CreateOptions{})
+	// The custom resources served by the API server
+	kubeclientset.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "example.edge-net.io/v1",
+			APIResources: []metav1.APIResource{{Name: "widgets", Namespaced: true}, {Name: "clusterwidgets", Namespaced: false}},
+		},
+	}
+
+	time.Sleep(500 * time.Millisecond)
+
+	os.Exit(m.Run())
+	<-stopCh
+}
+
+// Init syncs the test group
+func (g *TestGroup) Init() {
+	tenantObj := corev1alpha.Tenant{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Tenant",
+			APIVersion: "core.edgenet.io/v1alpha",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "edgenet",
+		},
+		Spec: corev1alpha.TenantSpec{
+			FullName:  "EdgeNet",
+			ShortName: "EdgeNet",
+			URL:       "https://www.edge-net.org",
+			Address: corev1alpha.Address{
+				City:    "Paris - NY - CA",
+				Country: "France - US",
+				Street:  "4 place Jussieu, boite 169",
+				ZIP:     "75005",
+			},
+			Contact: corev1alpha.Contact{
+				Email:     "joe.public@edge-net.org",
+				FirstName: "Joe",
+				LastName:  "Public",
+				Phone:     "+33NUMBER",
+				Handle:    "joepublic",
+			},
+			Enabled: true,
+		},
+	}
+	extensionObj := appsv1alpha.Extension{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Extension",
+			APIVersion: "apps.edgenet.io/v1alpha",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "widget-operator",
+		},
+		Spec: appsv1alpha.ExtensionSpec{
+			Description: "Widget operator",
+			CustomResources: []appsv1alpha.CustomResource{
+				{
+					Group:     "example.edge-net.io",
+					Version:   "v1",
+					Resources: []string{"widgets"},
+				},
+			},
+			Rules: []rbacv1.PolicyRule{{APIGroups: []string{"example.edge-net.io"}, Resources: []string{"widgets"}, Verbs: []string{"*"}}},
+			Deployment: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "operator", Image: "edgenet/widget-operator"}},
+					},
+				},
+			},
+			Enabled: true,
+		},
+	}
+	extensionRequestObj := registrationv1alpha.ExtensionRequest{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ExtensionRequest",
+			APIVersion: "registration.edgenet.io/v1alpha",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "johnsmith",
+			Namespace: "edgenet",
+		},
+		Spec: registrationv1alpha.ExtensionRequestSpec{
+			FirstName: "John",
+			LastName:  "Smith",
+			Email:     "john.smith@edge-net.org",
+			Extension: "widget-operator",
+		},
+	}
+	g.tenantObj = tenantObj
+	g.extensionObj = extensionObj
+	g.extensionRequestObj = extensionRequestObj
+
+	edgenetclientset.CoreV1alpha().Tenants().Create(context.TODO(), g.tenantObj.DeepCopy(), metav1.CreateOptions{})
+	edgenetclientset.AppsV1alpha().Extensions().Create(context.TODO(), g.extensionObj.DeepCopy(), metav1.CreateOptions{})
+	tenantCoreNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: g.tenantObj.GetName()}}
+	namespaceLabels := map[string]string{"edge-net.io/kind": "core", "edge-net.io/tenant": g.tenantObj.GetName(), "edge-net.io/cluster-uid": "kube-system-uid"}
+	tenantCoreNamespace.SetLabels(namespaceLabels)
+	kubeclientset.CoreV1().Namespaces().Create(context.TODO(), tenantCoreNamespace, metav1.CreateOptions{})
+}
+
+func TestStartController(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	extensionRequestTest := g.extensionRequestObj.DeepCopy()
+	extensionRequestTest.SetName("extension-request-controller-test")
+
+	// Create an extension request object
+	edgenetclientset.RegistrationV1alpha().ExtensionRequests(extensionRequestTest.GetNamespace()).Create(context.TODO(), extensionRequestTest, metav1.CreateOptions{})
+	// Wait for the status update of created object
+	time.Sleep(time.Millisecond * 500)
+	// Get the object and check the status
+	extensionRequest, err := edgenetclientset.RegistrationV1alpha().ExtensionRequests(extensionRequestTest.GetNamespace()).Get(context.TODO(), extensionRequestTest.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, pending, extensionRequest.Status.State)
+	util.Equals(t, messageNotApproved, extensionRequest.Status.Message)
+
+	extensionRequest.Status.Approved = true
+	edgenetclientset.RegistrationV1alpha().ExtensionRequests(extensionRequestTest.GetNamespace()).UpdateStatus(context.TODO(), extensionRequest, metav1.UpdateOptions{})
+	time.Sleep(time.Millisecond * 500)
+	extensionRequest, err = edgenetclientset.RegistrationV1alpha().ExtensionRequests(extensionRequestTest.GetNamespace()).Get(context.TODO(), extensionRequestTest.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, approved, extensionRequest.Status.State)
+	util.Equals(t, messageApproved, extensionRequest.Status.Message)
+
+	objectName := fmt.Sprintf("edgenet-extension-%s", g.extensionObj.GetName())
+	deployment, err := kubeclientset.AppsV1().Deployments(extensionRequestTest.GetNamespace()).Get(context.TODO(), objectName, metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, objectName, deployment.Spec.Template.Spec.ServiceAccountName)
+	util.Equals(t, extensionRequest.GetName(), deployment.GetOwnerReferences()[0].Name)
+	userRoleBinding, err := kubeclientset.RbacV1().RoleBindings(extensionRequestTest.GetNamespace()).Get(context.TODO(), fmt.Sprintf("%s-user", objectName), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, extensionRequest.Spec.Email, userRoleBinding.Subjects[0].Name)
+}
+
+func TestCatalog(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+
+	t.Run("not in catalog", func(t *testing.T) {
+		extensionRequestTest := g.extensionRequestObj.DeepCopy()
+		extensionRequestTest.SetName("extension-request-catalog-test")
+		extensionRequestTest.Spec.Extension = "unknown-operator"
+		edgenetclientset.RegistrationV1alpha().ExtensionRequests(extensionRequestTest.GetNamespace()).Create(context.TODO(), extensionRequestTest, metav1.CreateOptions{})
+		time.Sleep(time.Millisecond * 500)
+		extensionRequest, err := edgenetclientset.RegistrationV1alpha().ExtensionRequests(extensionRequestTest.GetNamespace()).Get(context.TODO(), extensionRequestTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, failure, extensionRequest.Status.State)
+		util.Equals(t, messageExtensionNotFound, extensionRequest.Status.Message)
+	})
+	t.Run("cluster-scoped resources", func(t *testing.T) {
+		extensionTest := g.extensionObj.DeepCopy()
+		extensionTest.SetName("clusterwidget-operator")
+		extensionTest.Spec.CustomResources[0].Resources = []string{"clusterwidgets"}
+		edgenetclientset.AppsV1alpha().Extensions().Create(context.TODO(), extensionTest, metav1.CreateOptions{})
+		extensionRequestTest := g.extensionRequestObj.DeepCopy()
+		extensionRequestTest.SetName("extension-request-namespaced-test")
+		extensionRequestTest.Spec.Extension = extensionTest.GetName()
+		edgenetclientset.RegistrationV1alpha().ExtensionRequests(extensionRequestTest.GetNamespace()).Create(context.TODO(), extensionRequestTest, metav1.CreateOptions{})
+		time.Sleep(time.Millisecond * 500)
+		extensionRequest, err := edgenetclientset.RegistrationV1alpha().ExtensionRequests(extensionRequestTest.GetNamespace()).Get(context.TODO(), extensionRequestTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, failure, extensionRequest.Status.State)
+		util.Equals(t, messageExtensionNotNamespace, extensionRequest.Status.Message)
+	})
+}
+
+func TestForeignNamespace(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	// The namespace is propagated by another cluster
+	foreignNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "foreign"}}
+	foreignNamespace.SetLabels(map[string]string{"edge-net.io/kind": "core", "edge-net.io/tenant": g.tenantObj.GetName(), "edge-net.io/cluster-uid": "other-cluster-uid"})
+	kubeclientset.CoreV1().Namespaces().Create(context.TODO(), foreignNamespace, metav1.CreateOptions{})
+	extensionRequestTest := g.extensionRequestObj.DeepCopy()
+	extensionRequestTest.SetName("extension-request-foreign-test")
+	extensionRequestTest.SetNamespace(foreignNamespace.GetName())
+	edgenetclientset.RegistrationV1alpha().ExtensionRequests(extensionRequestTest.GetNamespace()).Create(context.TODO(), extensionRequestTest, metav1.CreateOptions{})
+	time.Sleep(time.Millisecond * 500)
+	_, err := edgenetclientset.RegistrationV1alpha().ExtensionRequests(extensionRequestTest.GetNamespace()).Get(context.TODO(), extensionRequestTest.GetName(), metav1.GetOptions{})
+	util.Equals(t, true, errors.IsNotFound(err))
+}
+
+func TestWebhook(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	w := NewWebhook()
+	extensionRequest := g.extensionRequestObj.DeepCopy()
+
+	t.Run("requester", func(t *testing.T) {
+		util.OK(t, w.Admit(authenticationv1.UserInfo{Username: "john.smith@edge-net.org"}, extensionRequest, nil))
+	})
+	t.Run("on behalf of another user", func(t *testing.T) {
+		util.Assert(t, w.Admit(authenticationv1.UserInfo{Username: "joe.public@edge-net.org"}, extensionRequest, nil) != nil, "extension request admitted")
+		util.OK(t, w.Admit(authenticationv1.UserInfo{Username: "system:serviceaccount:edgenet:backup"}, extensionRequest, nil))
+		util.Assert(t, w.Admit(authenticationv1.UserInfo{Username: "system:serviceaccount:lip6:default"}, extensionRequest.DeepCopy(), nil) != nil, "extension request admitted")
+	})
+	t.Run("immutable spec", func(t *testing.T) {
+		extensionRequestUpdate := extensionRequest.DeepCopy()
+		extensionRequestUpdate.Spec.Email = "joe.public@edge-net.org"
+		util.Assert(t, w.Admit(authenticationv1.UserInfo{Username: "john.smith@edge-net.org"}, extensionRequestUpdate, extensionRequest) != nil, "extension request admitted")
+		util.OK(t, w.Admit(authenticationv1.UserInfo{Username: "john.smith@edge-net.org"}, extensionRequest.DeepCopy(), extensionRequest))
+	})
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extensionrequest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// The service accounts of the EdgeNet namespace, such as the restore of a backup, create the
+// requests on behalf of their requesters
+const serviceAccountPrefix = "system:serviceaccount:edgenet:"
+
+// Webhook rejects the extension requests whose email is not the identity of the user creating
+// them, and the changes to their spec. The controller grants the custom resources of the
+// extension to this email, a tenant member cannot have them granted to someone else.
+type Webhook struct{}
+
+// NewWebhook returns a new webhook
+func NewWebhook() *Webhook {
+	return &Webhook{}
+}
+
+// Handler serves the admission reviews of the extension requests
+func (w *Webhook) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		review := admissionv1.AdmissionReview{}
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil || review.Request == nil {
+			http.Error(rw, "invalid admission review", http.StatusBadRequest)
+			return
+		}
+		response := &admissionv1.AdmissionResponse{UID: review.Request.UID, Allowed: true}
+		extensionRequest := new(registrationv1alpha.ExtensionRequest)
+		var oldExtensionRequest *registrationv1alpha.ExtensionRequest
+		err := json.Unmarshal(review.Request.Object.Raw, extensionRequest)
+		if err == nil && review.Request.Operation == admissionv1.Update {
+			oldExtensionRequest = new(registrationv1alpha.ExtensionRequest)
+			err = json.Unmarshal(review.Request.OldObject.Raw, oldExtensionRequest)
+		}
+		if err != nil {
+			response.Allowed = false
+			response.Result = &metav1.Status{Status: metav1.StatusFailure, Code: http.StatusBadRequest, Message: err.Error()}
+		} else if err := w.Admit(review.Request.UserInfo, extensionRequest, oldExtensionRequest); err != nil {
+			response.Allowed = false
+			response.Result = &metav1.Status{Status: metav1.StatusFailure, Code: http.StatusForbidden, Reason: metav1.StatusReasonForbidden, Message: err.Error()}
+		}
+		review.Request = nil
+		review.Response = response
+		rw.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(rw).Encode(review); err != nil {
+			klog.ErrorS(err, "Couldn't write the admission review")
+		}
+	})
+}
+
+// Admit returns why the extension request is rejected, or nil if it is admitted. The old extension
+// request is nil on creation, when the email must be the name the user authenticated with, unless
+// the user is a cluster administrator or a service account of EdgeNet. The spec cannot change
+// afterwards, the approval is in the status.
+func (w *Webhook) Admit(userInfo authenticationv1.UserInfo, extensionRequest, oldExtensionRequest *registrationv1alpha.ExtensionRequest) error {
+	if oldExtensionRequest != nil {
+		if !reflect.DeepEqual(oldExtensionRequest.Spec, extensionRequest.Spec) {
+			return fmt.Errorf("spec of extension request %s is immutable", extensionRequest.GetName())
+		}
+		return nil
+	}
+	if strings.HasPrefix(userInfo.Username, serviceAccountPrefix) {
+		return nil
+	}
+	for _, group := range userInfo.Groups {
+		if group == "system:masters" {
+			return nil
+		}
+	}
+	if extensionRequest.Spec.Email != userInfo.Username {
+		klog.V(4).InfoS("Rejected the extension request on behalf of another user", "extensionRequest", klog.KObj(extensionRequest), "user", userInfo.Username)
+		return fmt.Errorf("extension request must be made with the email %s is authenticated with", userInfo.Username)
+	}
+	return nil
+}
@@ -24,12 +24,17 @@ import (
 	"time"
 
 	"github.com/EdgeNet-project/edgenet/pkg/access"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
 	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/audit"
+	"github.com/EdgeNet-project/edgenet/pkg/config"
 	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
 	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
 	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
 	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/registration/v1alpha"
 	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+	"github.com/EdgeNet-project/edgenet/pkg/storage"
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -43,11 +48,18 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 const controllerAgentName = "rolerequest-controller"
 
+// RetentionPeriod is how long an approved request is kept for audit before it gets pruned
+var RetentionPeriod = 30 * 24 * time.Hour
+
+// ApprovalTimeout is how long a request waits for the approval before it expires, the requester can
+// extend it once
+var ApprovalTimeout = 72 * time.Hour
+
 // Definitions of the state of the rolerequest resource
 const (
 	successSynced          = "Synced"
@@ -58,11 +70,16 @@ const (
 	messageRoleNotFound    = "Requested Role / Cluster Role does not exist"
 	warningApproved        = "Not Approved"
 	messageRoleNotApproved = "Waiting for Requested Role / Cluster Role to be approved"
+	warningNotVerified     = "Not Verified"
+	messageNotVerified     = "Waiting for the email address of the requester to be verified"
 	successApproved        = "Approved"
 	messageRoleApproved    = "Requested Role / Cluster Role approved successfully"
 	failureBinding         = "Binding Failed"
 	messageBindingFailed   = "Role binding failed"
+	successRenewed         = "Renewed"
+	messageRenewed         = "Request expiry extended"
 	failure                = "Failure"
+	rejected               = "Rejected"
 	pending                = "Pending"
 	approved               = "Approved"
 )
@@ -86,6 +103,8 @@ type Controller struct {
 	// recorder is an event recorder for recording Event resources to the
 	// Kubernetes API.
 	recorder record.EventRecorder
+	// identity is the identity of the cluster that the objects are labeled with
+	identity *config.ClusterIdentity
 }
 
 // NewController returns a new controller
@@ -95,13 +114,14 @@ func NewController(
 	rolerequestInformer informers.RoleRequestInformer) *Controller {
 
 	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
-	klog.V(4).Info("Creating event broadcaster")
+	klog.V(4).InfoS("Creating event broadcaster")
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartStructuredLogging(0)
 	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
 	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
 
 	controller := &Controller{
+		identity:           config.NewClusterIdentity(kubeclientset),
 		kubeclientset:      kubeclientset,
 		edgenetclientset:   edgenetclientset,
 		rolerequestsLister: rolerequestInformer.Lister(),
@@ -110,7 +130,7 @@ func NewController(
 		recorder:           recorder,
 	}
 
-	klog.V(4).Infoln("Setting up event handlers")
+	klog.V(4).InfoS("Setting up event handlers")
 	// Set up an event handler for when Role Request resources change
 	rolerequestInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: controller.enqueueRoleRequest,
@@ -138,23 +158,24 @@ func NewController(
 func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
 
-	klog.V(4).Infoln("Starting Role Request controller")
+	klog.V(4).InfoS("Starting Role Request controller")
 
-	klog.V(4).Infoln("Waiting for informer caches to sync")
+	klog.V(4).InfoS("Waiting for informer caches to sync")
 	if ok := cache.WaitForCacheSync(stopCh,
 		c.rolerequestsSynced); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
-	klog.V(4).Infoln("Starting workers")
+	klog.V(4).InfoS("Starting workers")
 	for i := 0; i < threadiness; i++ {
-		go wait.Until(c.runWorker, time.Second, stopCh)
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
 	}
 
-	klog.V(4).Infoln("Started workers")
+	klog.V(4).InfoS("Started workers")
 	<-stopCh
-	klog.V(4).Infoln("Shutting down workers")
+	klog.V(4).InfoS("Shutting down workers")
 
 	return nil
 }
@@ -162,14 +183,14 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 // runWorker is a long-running function that will continually call the
 // processNextWorkItem function in order to read and process a message on the
 // workqueue.
-func (c *Controller) runWorker() {
-	for c.processNextWorkItem() {
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
 	}
 }
 
 // processNextWorkItem will read a single work item off the workqueue and
 // attempt to process it, by calling the syncHandler.
-func (c *Controller) processNextWorkItem() bool {
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
 	obj, shutdown := c.workqueue.Get()
 
 	if shutdown {
@@ -186,12 +207,12 @@ func (c *Controller) processNextWorkItem() bool {
 			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
 			return nil
 		}
-		if err := c.syncHandler(key); err != nil {
+		if err := c.syncHandler(ctx, key); err != nil {
 			c.workqueue.AddRateLimited(key)
-			return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
 		}
 		c.workqueue.Forget(obj)
-		klog.V(4).Infof("Successfully synced '%s'", key)
+		klog.V(4).InfoS("Successfully synced", "key", key)
 		return nil
 	}(obj)
 
@@ -206,7 +227,7 @@ func (c *Controller) processNextWorkItem() bool {
 // syncHandler compares the actual state with the desired, and attempts to
 // converge the two. It then updates the Status block of the Role Request
 // resource with the current status of the resource.
-func (c *Controller) syncHandler(key string) error {
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
 		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
@@ -225,7 +246,9 @@ func (c *Controller) syncHandler(key string) error {
 	}
 
 	if rolerequest.Status.State != approved {
-		c.processRoleRequest(rolerequest.DeepCopy())
+		c.processRoleRequest(ctx, rolerequest.DeepCopy())
+	} else {
+		c.pruneRoleRequest(ctx, rolerequest)
 	}
 	c.recorder.Event(rolerequest, corev1.EventTypeNormal, successSynced, messageResourceSynced)
 	return nil
@@ -257,22 +280,46 @@ func (c *Controller) enqueueRoleRequestAfter(obj interface{}, after time.Duratio
 	c.workqueue.AddAfter(key, after)
 }
 
-func (c *Controller) processRoleRequest(roleRequestCopy *registrationv1alpha.RoleRequest) {
+// pruneRoleRequest removes the approved role request once its retention period is over, it is queued
+// again until then. The request is archived before its removal to leave a trace for audit.
+func (c *Controller) pruneRoleRequest(ctx context.Context, roleRequest *registrationv1alpha.RoleRequest) {
+	if roleRequest.Status.Expiry == nil {
+		return
+	}
+	if remaining := time.Until(roleRequest.Status.Expiry.Time); remaining > 0 {
+		c.enqueueRoleRequestAfter(roleRequest, remaining)
+		return
+	}
+	klog.InfoS("Pruning the approved role request", "roleRequest", klog.KObj(roleRequest), "email", roleRequest.Spec.Email, "roleKind", roleRequest.Spec.RoleRef.Kind, "roleName", roleRequest.Spec.RoleRef.Name)
+	if err := c.archive(ctx, roleRequest); err != nil {
+		klog.ErrorS(err, "Couldn't archive the role request", "roleRequest", klog.KObj(roleRequest))
+		c.workqueue.AddRateLimited(roleRequest.GetNamespace() + "/" + roleRequest.GetName())
+		return
+	}
+	if err := c.edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequest.GetNamespace()).Delete(ctx, roleRequest.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		klog.ErrorS(err, "Couldn't delete the role request", "roleRequest", klog.KRef(roleRequest.GetNamespace(), roleRequest.GetName()))
+	}
+}
+
+func (c *Controller) processRoleRequest(ctx context.Context, roleRequestCopy *registrationv1alpha.RoleRequest) {
 	oldStatus := roleRequestCopy.Status
 	statusUpdate := func() {
 		if !reflect.DeepEqual(oldStatus, roleRequestCopy.Status) {
-			if _, err := c.edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestCopy.GetNamespace()).UpdateStatus(context.TODO(), roleRequestCopy, metav1.UpdateOptions{}); err != nil {
-				klog.V(4).Infoln(err)
+			if _, err := c.edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestCopy.GetNamespace()).UpdateStatus(ctx, roleRequestCopy, metav1.UpdateOptions{}); err != nil {
+				klog.ErrorS(err, "Couldn't update the status of the role request", "roleRequest", klog.KObj(roleRequestCopy))
 			}
 		}
 	}
 	if roleRequestCopy.Status.Expiry == nil {
-		// Set the approval timeout which is 72 hours
+		// Set the approval timeout
 		roleRequestCopy.Status.Expiry = &metav1.Time{
-			Time: time.Now().Add(72 * time.Hour),
+			Time: time.Now().Add(ApprovalTimeout),
 		}
 	} else if time.Until(roleRequestCopy.Status.Expiry.Time) <= 0 {
-		c.edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestCopy.GetNamespace()).Delete(context.TODO(), roleRequestCopy.GetName(), metav1.DeleteOptions{})
+		c.edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestCopy.GetNamespace()).Delete(ctx, roleRequestCopy.GetName(), metav1.DeleteOptions{})
+		return
+	} else if roleRequestCopy.Status.State == rejected {
+		c.enqueueRoleRequestAfter(roleRequestCopy, time.Until(roleRequestCopy.Status.Expiry.Time))
 		return
 	}
 	defer statusUpdate()
@@ -280,26 +327,26 @@ func (c *Controller) processRoleRequest(roleRequestCopy *registrationv1alpha.Rol
 	// Below code checks whether namespace, where role request made, is local to the cluster or is propagated along with a federated deployment.
 	// If another cluster propagates the namespace, we skip checking the owner tenant's status as the Selective Deployment entity manages this life-cycle.
 	permitted := false
-	systemNamespace, err := c.kubeclientset.CoreV1().Namespaces().Get(context.TODO(), "kube-system", metav1.GetOptions{})
+	clusterUID, err := c.identity.UID(ctx)
 	if err != nil {
-		klog.V(4).Infoln(err)
-		c.edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestCopy.GetNamespace()).Delete(context.TODO(), roleRequestCopy.GetName(), metav1.DeleteOptions{})
+		klog.ErrorS(err, "Couldn't read the cluster UID")
+		c.edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestCopy.GetNamespace()).Delete(ctx, roleRequestCopy.GetName(), metav1.DeleteOptions{})
 		return
 	}
-	namespace, err := c.kubeclientset.CoreV1().Namespaces().Get(context.TODO(), roleRequestCopy.GetNamespace(), metav1.GetOptions{})
+	namespace, err := c.kubeclientset.CoreV1().Namespaces().Get(ctx, roleRequestCopy.GetNamespace(), metav1.GetOptions{})
 	if err != nil {
-		klog.V(4).Infoln(err)
-		c.edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestCopy.GetNamespace()).Delete(context.TODO(), roleRequestCopy.GetName(), metav1.DeleteOptions{})
+		klog.ErrorS(err, "Couldn't get the namespace", "namespace", roleRequestCopy.GetNamespace())
+		c.edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestCopy.GetNamespace()).Delete(ctx, roleRequestCopy.GetName(), metav1.DeleteOptions{})
 		return
 	}
 	namespaceLabels := namespace.GetLabels()
-	if systemNamespace.GetUID() != types.UID(namespaceLabels["edge-net.io/cluster-uid"]) {
+	if clusterUID != namespaceLabels["edge-net.io/cluster-uid"] {
 		permitted = true
 	} else {
-		tenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), strings.ToLower(namespaceLabels["edge-net.io/tenant"]), metav1.GetOptions{})
+		tenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(ctx, strings.ToLower(namespaceLabels["edge-net.io/tenant"]), metav1.GetOptions{})
 		if err != nil {
-			klog.V(4).Infoln(err)
-			c.edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestCopy.GetNamespace()).Delete(context.TODO(), roleRequestCopy.GetName(), metav1.DeleteOptions{})
+			klog.ErrorS(err, "Couldn't get the tenant", "tenant", strings.ToLower(namespaceLabels["edge-net.io/tenant"]))
+			c.edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestCopy.GetNamespace()).Delete(ctx, roleRequestCopy.GetName(), metav1.DeleteOptions{})
 			return
 		}
 		if tenant.GetUID() == types.UID(namespaceLabels["edge-net.io/tenant-uid"]) && tenant.Spec.Enabled {
@@ -310,12 +357,33 @@ func (c *Controller) processRoleRequest(roleRequestCopy *registrationv1alpha.Rol
 	if permitted {
 		// Below is to ensure that the requested Role / ClusterRole exists before moving forward in the procedure.
 		// If not, the status of the object falls into an error state.
-		roleExists := c.checkForRequestedRole(roleRequestCopy)
+		roleExists := c.checkForRequestedRole(ctx, roleRequestCopy)
 		if !roleExists {
 			return
 		}
 
-		if !roleRequestCopy.Spec.Approved {
+		if roleRequestCopy.Spec.Renew && !roleRequestCopy.Status.Renewed {
+			// The requester extends the expiry of the pending request once
+			c.recorder.Event(roleRequestCopy, corev1.EventTypeNormal, successRenewed, messageRenewed)
+			roleRequestCopy.Status.Expiry = &metav1.Time{
+				Time: time.Now().Add(ApprovalTimeout),
+			}
+			roleRequestCopy.Status.Renewed = true
+			roleRequestCopy.Status.Reminders = 0
+		}
+
+		if !access.EmailVerified(roleRequestCopy) {
+			c.remind(roleRequestCopy, clusterUID)
+			// The request awaits the approval only once the requester has verified their email address
+			if roleRequestCopy.Status.State == pending && roleRequestCopy.Status.Message == messageNotVerified {
+				return
+			}
+			c.recorder.Event(roleRequestCopy, corev1.EventTypeWarning, warningNotVerified, messageNotVerified)
+			roleRequestCopy.Status.State = pending
+			roleRequestCopy.Status.Message = messageNotVerified
+		} else if !roleRequestCopy.Spec.Approved {
+			c.remind(roleRequestCopy, clusterUID)
+			c.forward(ctx, roleRequestCopy, namespaceLabels["edge-net.io/tenant"])
 			if roleRequestCopy.Status.State == pending && roleRequestCopy.Status.Message == messageRoleNotApproved {
 				return
 			}
@@ -326,11 +394,15 @@ func (c *Controller) processRoleRequest(roleRequestCopy *registrationv1alpha.Rol
 			c.recorder.Event(roleRequestCopy, corev1.EventTypeNormal, successApproved, messageRoleApproved)
 			roleRequestCopy.Status.State = approved
 			roleRequestCopy.Status.Message = messageRoleApproved
+			// The approved request is kept for audit until the retention period is over
+			roleRequestCopy.Status.Expiry = &metav1.Time{
+				Time: time.Now().Add(RetentionPeriod),
+			}
 
 			// The following section handles role binding. There are two basic logical steps here.
 			// Check if role binding already exists; if not, create a role binding for the user.
 			// If role binding exists, check if the user already holds the role. If not, pin the role to the user.
-			if roleBindingRaw, err := c.kubeclientset.RbacV1().RoleBindings(roleRequestCopy.GetNamespace()).List(context.TODO(), metav1.ListOptions{LabelSelector: "edge-net.io/generated=true"}); err == nil {
+			if roleBindingRaw, err := c.kubeclientset.RbacV1().RoleBindings(roleRequestCopy.GetNamespace()).List(ctx, metav1.ListOptions{LabelSelector: "edge-net.io/generated=true"}); err == nil {
 				// TODO: Simplfy below
 				roleBindingExists := false
 				roleBound := false
@@ -346,11 +418,13 @@ func (c *Controller) processRoleRequest(roleRequestCopy *registrationv1alpha.Rol
 						if !roleBound {
 							roleBindingCopy := roleBindingRow.DeepCopy()
 							roleBindingCopy.Subjects = append(roleBindingCopy.Subjects, rbacv1.Subject{Kind: "User", Name: roleRequestCopy.Spec.Email, APIGroup: "rbac.authorization.k8s.io"})
-							if _, err := c.kubeclientset.RbacV1().RoleBindings(roleBindingCopy.GetNamespace()).Update(context.TODO(), roleBindingCopy, metav1.UpdateOptions{}); err != nil {
+							if _, err := c.kubeclientset.RbacV1().RoleBindings(roleBindingCopy.GetNamespace()).Update(ctx, roleBindingCopy, metav1.UpdateOptions{}); err != nil {
 								c.recorder.Event(roleRequestCopy, corev1.EventTypeWarning, failureBinding, messageBindingFailed)
 								roleRequestCopy.Status.State = failure
 								roleRequestCopy.Status.Message = messageBindingFailed
-								klog.V(4).Infoln(err)
+								klog.ErrorS(err, "Couldn't update the role binding", "roleBinding", klog.KObj(roleBindingCopy))
+							} else {
+								c.auditBinding(ctx, roleRequestCopy, namespaceLabels["edge-net.io/tenant"], roleBindingCopy.GetName())
 							}
 							break
 						}
@@ -364,23 +438,69 @@ func (c *Controller) processRoleRequest(roleRequestCopy *registrationv1alpha.Rol
 						Subjects: rbSubjects, RoleRef: roleRef}
 					roleBindLabels := map[string]string{"edge-net.io/generated": "true"}
 					roleBind.SetLabels(roleBindLabels)
-					if _, err := c.kubeclientset.RbacV1().RoleBindings(roleRequestCopy.GetNamespace()).Create(context.TODO(), roleBind, metav1.CreateOptions{}); err != nil {
+					if _, err := c.kubeclientset.RbacV1().RoleBindings(roleRequestCopy.GetNamespace()).Create(ctx, roleBind, metav1.CreateOptions{}); err != nil {
 						c.recorder.Event(roleRequestCopy, corev1.EventTypeWarning, failureBinding, messageBindingFailed)
 						roleRequestCopy.Status.State = failure
 						roleRequestCopy.Status.Message = messageBindingFailed
-						klog.V(4).Infoln(err)
+						klog.ErrorS(err, "Couldn't create the role binding", "roleBinding", klog.KObj(roleBind))
+					} else {
+						c.auditBinding(ctx, roleRequestCopy, namespaceLabels["edge-net.io/tenant"], objectName)
 					}
 				}
 			}
 		}
 	} else {
-		c.edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestCopy.GetNamespace()).Delete(context.TODO(), roleRequestCopy.GetName(), metav1.DeleteOptions{})
+		c.edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestCopy.GetNamespace()).Delete(ctx, roleRequestCopy.GetName(), metav1.DeleteOptions{})
+	}
+}
+
+// archive keeps the request in the archive of the tenant owning its namespace before it leaves etcd,
+// the request is archived at the time of its expiry
+func (c *Controller) archive(ctx context.Context, roleRequest *registrationv1alpha.RoleRequest) error {
+	if !storage.Enabled() {
+		return nil
+	}
+	namespace, err := c.kubeclientset.CoreV1().Namespaces().Get(ctx, roleRequest.GetNamespace(), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	tenant := namespace.GetLabels()["edge-net.io/tenant"]
+	name := fmt.Sprintf("%s/%s", roleRequest.GetNamespace(), roleRequest.GetName())
+	return storage.Archive(ctx, storage.KindRoleRequest, name, tenant, roleRequest.Status.Expiry.Time, roleRequest)
+}
+
+// auditBinding records the role granted by the request in the audit trail of the tenant, the
+// namespaces propagated by other clusters have no tenant here
+func (c *Controller) auditBinding(ctx context.Context, roleRequestCopy *registrationv1alpha.RoleRequest, tenant, roleBinding string) {
+	if tenant == "" {
+		return
+	}
+	object := corev1alpha.TenantAuditObject{Kind: "RoleBinding", Namespace: roleRequestCopy.GetNamespace(), Name: roleBinding}
+	message := fmt.Sprintf("%s %s bound to %s by role request %s", roleRequestCopy.Spec.RoleRef.Kind, roleRequestCopy.Spec.RoleRef.Name,
+		roleRequestCopy.Spec.Email, roleRequestCopy.GetName())
+	audit.Record(ctx, c.edgenetclientset, strings.ToLower(tenant), audit.RoleBound, "", object, message)
+}
+
+// remind emails the requester as the reminders before the expiry of the pending request fall due,
+// the request is queued again for the next reminder or the expiry
+func (c *Controller) remind(roleRequestCopy *registrationv1alpha.RoleRequest, clusterUID string) {
+	due, next := access.RemindersDue(roleRequestCopy.Status.Expiry.Time, time.Now())
+	if until := time.Until(roleRequestCopy.Status.Expiry.Time); next == 0 || until < next {
+		next = until
+	}
+	c.enqueueRoleRequestAfter(roleRequestCopy, next)
+	if due <= roleRequestCopy.Status.Reminders {
+		return
+	}
+	roleRequestCopy.Status.Reminders = due
+	if err := access.SendReminderEmailForRoleRequest(roleRequestCopy, clusterUID); err != nil {
+		klog.ErrorS(err, "Couldn't remind the requester of the request expiry", "roleRequest", klog.KObj(roleRequestCopy))
 	}
 }
 
-func (c *Controller) checkForRequestedRole(roleRequestCopy *registrationv1alpha.RoleRequest) bool {
+func (c *Controller) checkForRequestedRole(ctx context.Context, roleRequestCopy *registrationv1alpha.RoleRequest) bool {
 	if roleRequestCopy.Spec.RoleRef.Kind == "ClusterRole" {
-		if clusterRoleRaw, err := c.kubeclientset.RbacV1().ClusterRoles().List(context.TODO(), metav1.ListOptions{}); err == nil {
+		if clusterRoleRaw, err := c.kubeclientset.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{}); err == nil {
 			for _, clusterRoleRow := range clusterRoleRaw.Items {
 				if clusterRoleRow.GetName() == roleRequestCopy.Spec.RoleRef.Name {
 					c.recorder.Event(roleRequestCopy, corev1.EventTypeNormal, successFound, messageRoleFound)
@@ -389,7 +509,7 @@ func (c *Controller) checkForRequestedRole(roleRequestCopy *registrationv1alpha.
 			}
 		}
 	} else if roleRequestCopy.Spec.RoleRef.Kind == "Role" {
-		if roleRaw, err := c.kubeclientset.RbacV1().Roles(roleRequestCopy.GetNamespace()).List(context.TODO(), metav1.ListOptions{}); err == nil {
+		if roleRaw, err := c.kubeclientset.RbacV1().Roles(roleRequestCopy.GetNamespace()).List(ctx, metav1.ListOptions{}); err == nil {
 			for _, roleRow := range roleRaw.Items {
 				if roleRow.GetName() == roleRequestCopy.Spec.RoleRef.Name {
 					c.recorder.Event(roleRequestCopy, corev1.EventTypeNormal, successFound, messageRoleFound)
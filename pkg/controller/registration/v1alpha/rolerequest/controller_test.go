@@ -2,6 +2,7 @@ package rolerequest
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"io/ioutil"
 	"log"
@@ -16,6 +17,8 @@ import (
 	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
 	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
 	"github.com/EdgeNet-project/edgenet/pkg/signals"
+	"github.com/EdgeNet-project/edgenet/pkg/storage"
+	"github.com/EdgeNet-project/edgenet/pkg/testutil"
 	"github.com/EdgeNet-project/edgenet/pkg/util"
 	"github.com/sirupsen/logrus"
 
@@ -24,7 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	testclient "k8s.io/client-go/kubernetes/fake"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 type TestGroup struct {
@@ -61,8 +64,8 @@ func TestMain(m *testing.M) {
 	}()
 
 	access.Clientset = kubeclientset
-	access.CreateClusterRoles()
-	kubeSystemNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}}
+	access.ReconcileClusterRoles(context.TODO())
+	kubeSystemNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", UID: "kube-system-uid"}}
 	kubeclientset.CoreV1().Namespaces().Create(context.TODO(), kubeSystemNamespace, metav1.CreateOptions{})
 
 	time.Sleep(500 * time.Millisecond)
@@ -161,6 +164,21 @@ func TestStartController(t *testing.T) {
 	util.OK(t, err)
 	util.Equals(t, approved, roleRequest.Status.State)
 	util.Equals(t, messageRoleApproved, roleRequest.Status.Message)
+	// The approved request is kept until the retention period is over
+	expected = metav1.Time{
+		Time: time.Now().Add(RetentionPeriod),
+	}
+	util.Equals(t, expected.Day(), roleRequest.Status.Expiry.Day())
+	util.Equals(t, expected.Month(), roleRequest.Status.Expiry.Month())
+
+	roleRequest.Status.Expiry = &metav1.Time{
+		Time: time.Now().Add(10 * time.Millisecond),
+	}
+	edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestTest.GetNamespace()).UpdateStatus(context.TODO(), roleRequest, metav1.UpdateOptions{})
+	testutil.Eventually(t, func() (bool, error) {
+		_, err := edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestTest.GetNamespace()).Get(context.TODO(), roleRequest.GetName(), metav1.GetOptions{})
+		return errors.IsNotFound(err), nil
+	}, "Approved role request %s not pruned", roleRequest.GetName())
 }
 
 func TestTimeout(t *testing.T) {
@@ -191,3 +209,77 @@ func TestTimeout(t *testing.T) {
 		util.Equals(t, true, errors.IsNotFound(err))
 	})
 }
+
+func TestRenewal(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	roleRequestTest := g.roleRequestObj.DeepCopy()
+	roleRequestTest.SetName("role-request-renewal-test")
+	edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestTest.GetNamespace()).Create(context.TODO(), roleRequestTest, metav1.CreateOptions{})
+	time.Sleep(time.Millisecond * 500)
+
+	t.Run("reminder", func(t *testing.T) {
+		roleRequest, err := edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestTest.GetNamespace()).Get(context.TODO(), roleRequestTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, 0, roleRequest.Status.Reminders)
+		roleRequest.Status.Expiry = &metav1.Time{
+			Time: time.Now().Add(time.Hour),
+		}
+		edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestTest.GetNamespace()).UpdateStatus(context.TODO(), roleRequest, metav1.UpdateOptions{})
+		time.Sleep(time.Millisecond * 500)
+		roleRequest, err = edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestTest.GetNamespace()).Get(context.TODO(), roleRequestTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, len(access.ReminderIntervals), roleRequest.Status.Reminders)
+	})
+	t.Run("renew", func(t *testing.T) {
+		roleRequest, err := edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestTest.GetNamespace()).Get(context.TODO(), roleRequestTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		roleRequest.Spec.Renew = true
+		edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestTest.GetNamespace()).Update(context.TODO(), roleRequest, metav1.UpdateOptions{})
+		time.Sleep(time.Millisecond * 500)
+		roleRequest, err = edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestTest.GetNamespace()).Get(context.TODO(), roleRequestTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, true, roleRequest.Status.Renewed)
+		util.Equals(t, 0, roleRequest.Status.Reminders)
+		util.Assert(t, time.Until(roleRequest.Status.Expiry.Time) > ApprovalTimeout-time.Minute, "expiry not extended")
+	})
+	t.Run("renew once", func(t *testing.T) {
+		roleRequest, err := edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestTest.GetNamespace()).Get(context.TODO(), roleRequestTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		roleRequest.Status.Expiry = &metav1.Time{
+			Time: time.Now().Add(time.Hour),
+		}
+		edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestTest.GetNamespace()).UpdateStatus(context.TODO(), roleRequest, metav1.UpdateOptions{})
+		time.Sleep(time.Millisecond * 500)
+		roleRequest, err = edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestTest.GetNamespace()).Get(context.TODO(), roleRequestTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Assert(t, time.Until(roleRequest.Status.Expiry.Time) <= time.Hour, "expiry extended twice")
+	})
+}
+
+func TestArchive(t *testing.T) {
+	store := storage.NewMemoryStore()
+	storage.Default = store
+	defer func() { storage.Default = nil }()
+	g := TestGroup{}
+	g.Init()
+	c := &Controller{kubeclientset: kubeclientset, edgenetclientset: edgenettestclient.NewSimpleClientset()}
+	roleRequestTest := g.roleRequestObj.DeepCopy()
+	roleRequestTest.SetName("role-request-archive-test")
+	roleRequestTest.Status.State = approved
+	roleRequestTest.Status.Expiry = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+	_, err := c.edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestTest.GetNamespace()).Create(context.TODO(), roleRequestTest, metav1.CreateOptions{})
+	util.OK(t, err)
+
+	// The request leaves etcd for the archive of its tenant once its retention period is over
+	c.pruneRoleRequest(context.TODO(), roleRequestTest)
+	_, err = c.edgenetclientset.RegistrationV1alpha().RoleRequests(roleRequestTest.GetNamespace()).Get(context.TODO(), roleRequestTest.GetName(), metav1.GetOptions{})
+	util.Equals(t, true, errors.IsNotFound(err))
+	records, err := store.List(context.TODO(), storage.KindRoleRequest, g.tenantObj.GetName())
+	util.OK(t, err)
+	util.Equals(t, 1, len(records))
+	util.Equals(t, roleRequestTest.GetNamespace()+"/"+roleRequestTest.GetName(), records[0].Name)
+	archived := registrationv1alpha.RoleRequest{}
+	util.OK(t, json.Unmarshal(records[0].Object, &archived))
+	util.Equals(t, roleRequestTest.Spec.Email, archived.Spec.Email)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rolerequest
+
+import (
+	"context"
+
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/approval"
+
+	"k8s.io/klog/v2"
+)
+
+// forward posts the request awaiting an approval to the external approval system once, the request
+// is queued again with a backoff while the webhook fails
+func (c *Controller) forward(ctx context.Context, roleRequestCopy *registrationv1alpha.RoleRequest, tenant string) {
+	if !approval.Enabled() || roleRequestCopy.Status.Forwarded {
+		return
+	}
+	request := approval.Request{
+		Kind:      approval.KindRoleRequest,
+		Namespace: roleRequestCopy.GetNamespace(),
+		Name:      roleRequestCopy.GetName(),
+		UID:       roleRequestCopy.GetUID(),
+		Email:     roleRequestCopy.Spec.Email,
+		FirstName: roleRequestCopy.Spec.FirstName,
+		LastName:  roleRequestCopy.Spec.LastName,
+		Details: map[string]string{
+			"tenant":   tenant,
+			"roleKind": roleRequestCopy.Spec.RoleRef.Kind,
+			"roleName": roleRequestCopy.Spec.RoleRef.Name,
+		},
+	}
+	if roleRequestCopy.Status.Expiry != nil {
+		request.Expiry = &roleRequestCopy.Status.Expiry.Time
+	}
+	if err := approval.Forward(ctx, request); err != nil {
+		klog.ErrorS(err, "Couldn't forward the request to the external approval system", "roleRequest", klog.KObj(roleRequestCopy))
+		c.workqueue.AddRateLimited(roleRequestCopy.GetNamespace() + "/" + roleRequestCopy.GetName())
+		return
+	}
+	roleRequestCopy.Status.Forwarded = true
+}
@@ -0,0 +1,536 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roster
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/credential"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/registration/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const controllerAgentName = "roster-controller"
+
+// Definitions of the state of the roster resource
+const (
+	successSynced         = "Synced"
+	messageResourceSynced = "Roster synced successfully"
+	successProvisioned    = "Provisioned"
+	messageProvisioned    = "%d students provisioned"
+	successTornDown       = "Torn Down"
+	messageTornDown       = "Students torn down"
+	failureTenant         = "Not Found"
+	messageTenantNotFound = "The namespace is not the core namespace of an enabled tenant"
+	failureRole           = "Invalid Role"
+	messageRoleInvalid    = "Role %q is not a tenant role"
+	failureRoster         = "Malformed"
+	messageRosterInvalid  = "Malformed roster: %s"
+	failureStudents       = "Provisioning Failed"
+	messageStudentsFailed = "%d of %d students could not be provisioned"
+	provisioned           = "Provisioned"
+	tornDown              = "TornDown"
+	failure               = "Failure"
+)
+
+// rosterLabel marks the objects provisioned for the students with the name of the roster
+const rosterLabel = "edge-net.io/roster"
+
+// studentRoles are the cluster roles that the students can hold, students cannot own a tenant
+var studentRoles = map[string]string{
+	"admin":        access.TenantAdminRole,
+	"collaborator": access.TenantCollaboratorRole,
+}
+
+// Cluster is the cluster that the kubeconfigs of the students lead to
+type Cluster struct {
+	// Name of the cluster in the kubeconfigs
+	Name string
+	// Public address of the API server, no kubeconfig is generated if it is empty
+	Server string
+	// Certificate authority of the API server
+	CertificateAuthority []byte
+	// Registration server that issues the tokens of the students
+	RegistrationServer string
+}
+
+// Controller is the controller implementation for Roster resources
+type Controller struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+	// edgenetclientset is a clientset for the EdgeNet API groups
+	edgenetclientset clientset.Interface
+	// cluster is written in the kubeconfigs of the students
+	cluster Cluster
+
+	rostersLister listers.RosterLister
+	rostersSynced cache.InformerSynced
+
+	// workqueue is a rate limited work queue. This is used to queue work to be
+	// processed instead of performing it as soon as a change happens. This
+	// means we can ensure we only process a fixed amount of resources at a
+	// time, and makes it easy to ensure we are never processing the same item
+	// simultaneously in two different workers.
+	workqueue workqueue.RateLimitingInterface
+	// recorder is an event recorder for recording Event resources to the
+	// Kubernetes API.
+	recorder record.EventRecorder
+}
+
+// NewController returns a new controller
+func NewController(
+	ctx context.Context,
+	kubeclientset kubernetes.Interface,
+	edgenetclientset clientset.Interface,
+	rosterInformer informers.RosterInformer,
+	cluster Cluster) *Controller {
+
+	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
+	klog.V(4).InfoS("Creating event broadcaster")
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartStructuredLogging(0)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+
+	controller := &Controller{
+		kubeclientset:    kubeclientset,
+		edgenetclientset: edgenetclientset,
+		cluster:          cluster,
+		rostersLister:    rosterInformer.Lister(),
+		rostersSynced:    rosterInformer.Informer().HasSynced,
+		workqueue:        workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Rosters"),
+		recorder:         recorder,
+	}
+
+	klog.V(4).InfoS("Setting up event handlers")
+	// Set up an event handler for when Roster resources change. The objects provisioned
+	// are garbage collected along with the roster, except for the roles of the students.
+	rosterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueRoster,
+		UpdateFunc: func(old, new interface{}) {
+			newObj := new.(*registrationv1alpha.Roster)
+			oldObj := old.(*registrationv1alpha.Roster)
+			if !reflect.DeepEqual(newObj.Spec, oldObj.Spec) {
+				controller.enqueueRoster(new)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			roster, ok := obj.(*registrationv1alpha.Roster)
+			if !ok {
+				return
+			}
+			if roleName, ok := studentRoles[roster.Spec.Role]; ok {
+				controller.unbind(ctx, roster.GetNamespace(), roleName, roster.Status.Students)
+			}
+		},
+	})
+
+	return controller
+}
+
+// Run will set up the event handlers for the types of roster, as well
+// as syncing informer caches and starting workers. It will block until stopCh
+// is closed, at which point it will shutdown the workqueue and wait for
+// workers to finish processing their current work items.
+func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
+	defer utilruntime.HandleCrash()
+	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
+
+	klog.V(4).InfoS("Starting Roster controller")
+
+	klog.V(4).InfoS("Waiting for informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh,
+		c.rostersSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	klog.V(4).InfoS("Starting workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
+	}
+
+	klog.V(4).InfoS("Started workers")
+	<-stopCh
+	klog.V(4).InfoS("Shutting down workers")
+
+	return nil
+}
+
+// runWorker is a long-running function that will continually call the
+// processNextWorkItem function in order to read and process a message on the
+// workqueue.
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+// processNextWorkItem will read a single work item off the workqueue and
+// attempt to process it, by calling the syncHandler.
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
+	obj, shutdown := c.workqueue.Get()
+
+	if shutdown {
+		return false
+	}
+
+	err := func(obj interface{}) error {
+		defer c.workqueue.Done(obj)
+		var key string
+		var ok bool
+
+		if key, ok = obj.(string); !ok {
+			c.workqueue.Forget(obj)
+			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
+			return nil
+		}
+		if err := c.syncHandler(ctx, key); err != nil {
+			c.workqueue.AddRateLimited(key)
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
+		}
+		c.workqueue.Forget(obj)
+		klog.V(4).InfoS("Successfully synced", "key", key)
+		return nil
+	}(obj)
+
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+
+	return true
+}
+
+// syncHandler compares the actual state with the desired, and attempts to
+// converge the two. It then updates the Status block of the Roster
+// resource with the current status of the resource.
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
+		return nil
+	}
+
+	roster, err := c.rostersLister.Rosters(namespace).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("roster '%s' in work queue no longer exists", key))
+			return nil
+		}
+
+		return err
+	}
+
+	rosterCopy := roster.DeepCopy()
+	next := c.processRoster(ctx, rosterCopy)
+	if !reflect.DeepEqual(roster.Status, rosterCopy.Status) {
+		if _, err := c.edgenetclientset.RegistrationV1alpha().Rosters(namespace).UpdateStatus(ctx, rosterCopy, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	c.recorder.Event(roster, corev1.EventTypeNormal, successSynced, messageResourceSynced)
+	if next > 0 {
+		// The semester is to end
+		c.workqueue.AddAfter(key, next)
+	}
+	return nil
+}
+
+// enqueueRoster takes a Roster resource and converts it into a namespace/name
+// string which is then put onto the work queue. This method should *not* be
+// passed resources of any type other than Roster.
+func (c *Controller) enqueueRoster(obj interface{}) {
+	var key string
+	var err error
+	if key, err = cache.MetaNamespaceKeyFunc(obj); err != nil {
+		utilruntime.HandleError(err)
+		return
+	}
+	c.workqueue.Add(key)
+}
+
+// processRoster provisions the students listed and deprovisions the ones no longer listed, or tears
+// the whole class down at the end of the semester. It returns the time left until the semester is over,
+// zero if it has no end.
+func (c *Controller) processRoster(ctx context.Context, rosterCopy *registrationv1alpha.Roster) time.Duration {
+	namespace := rosterCopy.GetNamespace()
+	roleName, ok := studentRoles[rosterCopy.Spec.Role]
+	if !ok {
+		c.fail(rosterCopy, failureRole, fmt.Sprintf(messageRoleInvalid, rosterCopy.Spec.Role))
+		return 0
+	}
+	if rosterCopy.Spec.Teardown || (rosterCopy.Spec.Expiry != nil && time.Until(rosterCopy.Spec.Expiry.Time) <= 0) {
+		c.teardown(ctx, rosterCopy, roleName)
+		return 0
+	}
+	// The core namespace has the same name as the tenant
+	tenant, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Couldn't get the tenant", "tenant", namespace)
+		c.fail(rosterCopy, failureTenant, messageTenantNotFound)
+		return 0
+	} else if !tenant.Spec.Enabled {
+		klog.InfoS("Tenant is disabled", "roster", klog.KObj(rosterCopy), "tenant", namespace)
+		c.fail(rosterCopy, failureTenant, messageTenantNotFound)
+		return 0
+	}
+	students, err := ParseStudents(rosterCopy.Spec)
+	if err != nil {
+		c.fail(rosterCopy, failureRoster, fmt.Sprintf(messageRosterInvalid, err))
+		return 0
+	}
+
+	statuses := []registrationv1alpha.StudentStatus{}
+	handles := map[string]bool{}
+	failed := 0
+	for _, student := range students {
+		status := registrationv1alpha.StudentStatus{Email: student.Email, Handle: previousHandle(rosterCopy, student.Email)}
+		if status.Handle == "" {
+			status.Handle = uniqueHandle(Handle(student.Email), handles, rosterCopy.Status.Students)
+		}
+		handles[status.Handle] = true
+		if err := c.provision(ctx, rosterCopy, roleName, student, &status); err != nil {
+			klog.ErrorS(err, "Couldn't provision student", "roster", klog.KObj(rosterCopy), "handle", status.Handle)
+			status.Message = err.Error()
+			failed++
+		}
+		statuses = append(statuses, status)
+	}
+	stale := []registrationv1alpha.StudentStatus{}
+	for _, status := range rosterCopy.Status.Students {
+		if !handles[status.Handle] {
+			stale = append(stale, status)
+		}
+	}
+	c.deprovision(ctx, namespace, roleName, stale)
+
+	rosterCopy.Status.Students = statuses
+	rosterCopy.Status.Provisioned = len(students) - failed
+	if failed > 0 {
+		c.fail(rosterCopy, failureStudents, fmt.Sprintf(messageStudentsFailed, failed, len(students)))
+	} else {
+		message := fmt.Sprintf(messageProvisioned, len(students))
+		if rosterCopy.Status.State != provisioned || rosterCopy.Status.Message != message {
+			c.recorder.Event(rosterCopy, corev1.EventTypeNormal, successProvisioned, message)
+		}
+		rosterCopy.Status.State = provisioned
+		rosterCopy.Status.Message = message
+	}
+	if rosterCopy.Spec.Expiry != nil {
+		return time.Until(rosterCopy.Spec.Expiry.Time)
+	}
+	return 0
+}
+
+// provision creates the role request, the workspace, and the kubeconfig of a student, the objects
+// that exist already are left as they are
+func (c *Controller) provision(ctx context.Context, rosterCopy *registrationv1alpha.Roster, roleName string, student registrationv1alpha.Student, status *registrationv1alpha.StudentStatus) error {
+	namespace := rosterCopy.GetNamespace()
+	name := objectName(rosterCopy, status.Handle)
+
+	roleRequest := &registrationv1alpha.RoleRequest{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace,
+		OwnerReferences: ownerReferences(rosterCopy), Labels: rosterLabels(rosterCopy)},
+		Spec: registrationv1alpha.RoleRequestSpec{FirstName: student.FirstName, LastName: student.LastName, Email: student.Email,
+			// The tenant owner vouches for the students by listing them
+			RoleRef: registrationv1alpha.RoleRefSpec{Kind: "ClusterRole", Name: roleName}, Approved: true}}
+	if _, err := c.edgenetclientset.RegistrationV1alpha().RoleRequests(namespace).Create(ctx, roleRequest, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("role request: %w", err)
+	}
+	status.RoleRequest = name
+
+	if len(rosterCopy.Spec.ResourceAllocation) > 0 {
+		subnamespace := &corev1alpha.SubNamespace{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace,
+			OwnerReferences: ownerReferences(rosterCopy), Labels: rosterLabels(rosterCopy)}}
+		subnamespace.Spec.Workspace = &corev1alpha.Workspace{
+			ResourceAllocation: rosterCopy.Spec.ResourceAllocation,
+			Inheritance:        map[string]bool{"rbac": true, "networkpolicy": true, "limitrange": true, "configmap": false, "secret": false, "serviceaccount": false},
+			Scope:              "local",
+			Owner:              &corev1alpha.Contact{Handle: status.Handle, FirstName: student.FirstName, LastName: student.LastName, Email: student.Email},
+		}
+		subnamespace.Spec.Expiry = rosterCopy.Spec.Expiry
+		if _, err := c.edgenetclientset.CoreV1alpha().SubNamespaces(namespace).Create(ctx, subnamespace, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("workspace: %w", err)
+		}
+		status.Workspace = name
+	}
+
+	if c.cluster.Server != "" {
+		// The kubeconfig holds no credentials, the students obtain their tokens through the credential plugin
+		kubeconfig, err := credential.GenerateKubeconfig(c.cluster.Name, c.cluster.Server, c.cluster.CertificateAuthority, student.Email, c.cluster.RegistrationServer)
+		if err != nil {
+			return fmt.Errorf("kubeconfig: %w", err)
+		}
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("%s-kubeconfig", name), Namespace: namespace,
+			OwnerReferences: ownerReferences(rosterCopy), Labels: rosterLabels(rosterCopy)},
+			Data: map[string][]byte{"kubeconfig": kubeconfig}}
+		// The kubeconfig goes away with the role of the student in the tenant
+		secret.Labels[credential.LabelCredential] = "kubeconfig"
+		secret.SetAnnotations(map[string]string{
+			credential.AnnotationUser:        student.Email,
+			credential.AnnotationRoleBinding: fmt.Sprintf("edgenet:clusterrole:%s", strings.ToLower(roleName)),
+		})
+		if rosterCopy.Spec.Expiry != nil {
+			secret.Annotations[credential.AnnotationExpiry] = rosterCopy.Spec.Expiry.UTC().Format(time.RFC3339)
+		}
+		if _, err := c.kubeclientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("kubeconfig: %w", err)
+		}
+		status.Kubeconfig = secret.GetName()
+	}
+	return nil
+}
+
+// deprovision removes the objects provisioned for the students and their roles in the tenant
+func (c *Controller) deprovision(ctx context.Context, namespace, roleName string, students []registrationv1alpha.StudentStatus) {
+	for _, student := range students {
+		if student.RoleRequest != "" {
+			if err := c.edgenetclientset.RegistrationV1alpha().RoleRequests(namespace).Delete(ctx, student.RoleRequest, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				klog.ErrorS(err, "Couldn't delete the role request", "roleRequest", klog.KRef(namespace, student.RoleRequest))
+			}
+		}
+		if student.Workspace != "" {
+			if err := c.edgenetclientset.CoreV1alpha().SubNamespaces(namespace).Delete(ctx, student.Workspace, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				klog.ErrorS(err, "Couldn't delete the subnamespace", "subNamespace", klog.KRef(namespace, student.Workspace))
+			}
+		}
+		if student.Kubeconfig != "" {
+			if err := c.kubeclientset.CoreV1().Secrets(namespace).Delete(ctx, student.Kubeconfig, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+				klog.ErrorS(err, "Couldn't delete the secret", "secret", klog.KRef(namespace, student.Kubeconfig))
+			}
+		}
+	}
+	c.unbind(ctx, namespace, roleName, students)
+}
+
+// unbind takes the role back from the students, the role requests bind the role to them in the
+// role binding that the role request controller generates for the role
+func (c *Controller) unbind(ctx context.Context, namespace, roleName string, students []registrationv1alpha.StudentStatus) {
+	if len(students) == 0 {
+		return
+	}
+	emails := map[string]bool{}
+	for _, student := range students {
+		emails[strings.ToLower(student.Email)] = true
+	}
+	roleBindingName := fmt.Sprintf("edgenet:clusterrole:%s", strings.ToLower(roleName))
+	roleBinding, err := c.kubeclientset.RbacV1().RoleBindings(namespace).Get(ctx, roleBindingName, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			klog.ErrorS(err, "Couldn't get the role binding", "roleBinding", klog.KRef(namespace, roleBindingName))
+		}
+		return
+	}
+	roleBindingCopy := roleBinding.DeepCopy()
+	roleBindingCopy.Subjects = roleBindingCopy.Subjects[:0]
+	for _, subject := range roleBinding.Subjects {
+		if subject.Kind != "User" || !emails[strings.ToLower(subject.Name)] {
+			roleBindingCopy.Subjects = append(roleBindingCopy.Subjects, subject)
+		}
+	}
+	if len(roleBindingCopy.Subjects) == len(roleBinding.Subjects) {
+		return
+	}
+	if _, err := c.kubeclientset.RbacV1().RoleBindings(namespace).Update(ctx, roleBindingCopy, metav1.UpdateOptions{}); err != nil {
+		klog.ErrorS(err, "Couldn't unbind students", "roleBinding", klog.KRef(namespace, roleBindingName))
+	}
+}
+
+// teardown removes everything provisioned for the class, the roster stays for the records
+func (c *Controller) teardown(ctx context.Context, rosterCopy *registrationv1alpha.Roster, roleName string) {
+	c.deprovision(ctx, rosterCopy.GetNamespace(), roleName, rosterCopy.Status.Students)
+	if rosterCopy.Status.State != tornDown {
+		c.recorder.Event(rosterCopy, corev1.EventTypeNormal, successTornDown, messageTornDown)
+	}
+	rosterCopy.Status.State = tornDown
+	rosterCopy.Status.Message = messageTornDown
+	rosterCopy.Status.Provisioned = 0
+	rosterCopy.Status.Students = nil
+}
+
+func (c *Controller) fail(rosterCopy *registrationv1alpha.Roster, reason, message string) {
+	c.recorder.Event(rosterCopy, corev1.EventTypeWarning, reason, message)
+	rosterCopy.Status.State = failure
+	rosterCopy.Status.Message = message
+}
+
+// previousHandle returns the handle given to the student before, so that the objects keep their names
+// as the roster changes
+func previousHandle(roster *registrationv1alpha.Roster, email string) string {
+	for _, status := range roster.Status.Students {
+		if strings.EqualFold(status.Email, email) {
+			return status.Handle
+		}
+	}
+	return ""
+}
+
+// uniqueHandle appends a number to the handle if another student of the roster has it
+func uniqueHandle(handle string, taken map[string]bool, previous []registrationv1alpha.StudentStatus) string {
+	inUse := func(candidate string) bool {
+		if taken[candidate] {
+			return true
+		}
+		for _, status := range previous {
+			if status.Handle == candidate {
+				return true
+			}
+		}
+		return false
+	}
+	candidate := handle
+	for i := 2; inUse(candidate); i++ {
+		candidate = fmt.Sprintf("%s-%d", handle, i)
+	}
+	return candidate
+}
+
+func ownerReferences(roster *registrationv1alpha.Roster) []metav1.OwnerReference {
+	return []metav1.OwnerReference{*metav1.NewControllerRef(roster, registrationv1alpha.SchemeGroupVersion.WithKind("Roster"))}
+}
+
+func rosterLabels(roster *registrationv1alpha.Roster) map[string]string {
+	return map[string]string{"edge-net.io/generated": "true", "edge-net.io/tenant": roster.GetNamespace(), rosterLabel: roster.GetName()}
+}
+
+// objectName is the name of the objects provisioned for a student, prefixed with the roster so that
+// the classes of a tenant do not collide
+func objectName(roster *registrationv1alpha.Roster, handle string) string {
+	return fmt.Sprintf("%s-%s", roster.GetName(), handle)
+}
@@ -0,0 +1,209 @@
+package roster
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+// newController returns a controller with an enabled tenant, the kubeconfigs are generated for the
+// cluster given
+func newController(t *testing.T, cluster Cluster) *Controller {
+	c := &Controller{
+		kubeclientset:    testclient.NewSimpleClientset(),
+		edgenetclientset: edgenettestclient.NewSimpleClientset(),
+		cluster:          cluster,
+		recorder:         record.NewFakeRecorder(100),
+	}
+	tenant := &corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "edgenet"}, Spec: corev1alpha.TenantSpec{Enabled: true}}
+	_, err := c.edgenetclientset.CoreV1alpha().Tenants().Create(context.TODO(), tenant, metav1.CreateOptions{})
+	util.OK(t, err)
+	return c
+}
+
+func newRoster(students ...registrationv1alpha.Student) *registrationv1alpha.Roster {
+	return &registrationv1alpha.Roster{ObjectMeta: metav1.ObjectMeta{Name: "networks", Namespace: "edgenet", UID: types.UID("roster-uid")},
+		Spec: registrationv1alpha.RosterSpec{Role: "collaborator", Students: students}}
+}
+
+func (c *Controller) roleRequestExists(name string) bool {
+	_, err := c.edgenetclientset.RegistrationV1alpha().RoleRequests("edgenet").Get(context.TODO(), name, metav1.GetOptions{})
+	return err == nil
+}
+
+func TestParseStudents(t *testing.T) {
+	jane := registrationv1alpha.Student{Email: "jane.doe@edge-net.org", FirstName: "Jane", LastName: "Doe"}
+	cases := map[string]struct {
+		spec     registrationv1alpha.RosterSpec
+		expected []string
+		valid    bool
+	}{
+		"list":              {registrationv1alpha.RosterSpec{Students: []registrationv1alpha.Student{jane}}, []string{"jane.doe@edge-net.org"}, true},
+		"csv with header":   {registrationv1alpha.RosterSpec{CSV: "email,firstname,lastname\nrichard.roe@edge-net.org, Richard, Roe\n"}, []string{"richard.roe@edge-net.org"}, true},
+		"csv and list":      {registrationv1alpha.RosterSpec{Students: []registrationv1alpha.Student{jane}, CSV: "richard.roe@edge-net.org,Richard,Roe"}, []string{"jane.doe@edge-net.org", "richard.roe@edge-net.org"}, true},
+		"listed twice":      {registrationv1alpha.RosterSpec{Students: []registrationv1alpha.Student{jane}, CSV: "Jane.Doe@edge-net.org,Jane,Doe"}, []string{"jane.doe@edge-net.org"}, true},
+		"missing column":    {registrationv1alpha.RosterSpec{CSV: "richard.roe@edge-net.org,Richard"}, nil, false},
+		"malformed email":   {registrationv1alpha.RosterSpec{CSV: "richard.roe,Richard,Roe"}, nil, false},
+		"empty":             {registrationv1alpha.RosterSpec{}, []string{}, true},
+		"blank csv ignored": {registrationv1alpha.RosterSpec{CSV: "  \n"}, []string{}, true},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			students, err := ParseStudents(tc.spec)
+			util.Equals(t, tc.valid, err == nil)
+			if tc.valid {
+				emails := []string{}
+				for _, student := range students {
+					emails = append(emails, student.Email)
+				}
+				util.Equals(t, tc.expected, emails)
+			}
+		})
+	}
+}
+
+func TestHandle(t *testing.T) {
+	cases := map[string]string{
+		"jane.doe@edge-net.org":                             "jane-doe",
+		"Richard_Roe+class@edge-net.org":                    "richard-roe-class",
+		"...@edge-net.org":                                  "student",
+		"a.very.long.local.part.of.an.address@edge-net.org": "a-very-long-local-part-of-an-a",
+	}
+	for email, expected := range cases {
+		util.Equals(t, expected, Handle(email))
+	}
+}
+
+func TestProvision(t *testing.T) {
+	c := newController(t, Cluster{Name: "edgenet", Server: "https://api.edge-net.org:6443", RegistrationServer: "https://registration.edge-net.org"})
+	roster := newRoster(
+		registrationv1alpha.Student{Email: "jane.doe@edge-net.org", FirstName: "Jane", LastName: "Doe"},
+		registrationv1alpha.Student{Email: "jane.doe@lip6.fr", FirstName: "Jane", LastName: "Doe"})
+	roster.Spec.ResourceAllocation = map[corev1.ResourceName]resource.Quantity{corev1.ResourceCPU: resource.MustParse("1000m")}
+	util.Equals(t, time.Duration(0), c.processRoster(context.TODO(), roster))
+	util.Equals(t, provisioned, roster.Status.State)
+	util.Equals(t, 2, roster.Status.Provisioned)
+	util.Equals(t, "jane-doe", roster.Status.Students[0].Handle)
+	util.Equals(t, "jane-doe-2", roster.Status.Students[1].Handle)
+
+	roleRequest, err := c.edgenetclientset.RegistrationV1alpha().RoleRequests("edgenet").Get(context.TODO(), "networks-jane-doe", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, true, roleRequest.Spec.Approved)
+	util.Equals(t, access.TenantCollaboratorRole, roleRequest.Spec.RoleRef.Name)
+	util.Equals(t, "networks", roleRequest.GetLabels()[rosterLabel])
+	subnamespace, err := c.edgenetclientset.CoreV1alpha().SubNamespaces("edgenet").Get(context.TODO(), "networks-jane-doe-2", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, "jane.doe@lip6.fr", subnamespace.Spec.Workspace.Owner.Email)
+	secret, err := c.kubeclientset.CoreV1().Secrets("edgenet").Get(context.TODO(), "networks-jane-doe-kubeconfig", metav1.GetOptions{})
+	util.OK(t, err)
+	util.Assert(t, len(secret.Data["kubeconfig"]) > 0, "empty kubeconfig")
+
+	t.Run("handles kept", func(t *testing.T) {
+		roster.Spec.Students = roster.Spec.Students[1:]
+		c.processRoster(context.TODO(), roster)
+		util.Equals(t, 1, roster.Status.Provisioned)
+		util.Equals(t, "jane-doe-2", roster.Status.Students[0].Handle)
+		util.Equals(t, false, c.roleRequestExists("networks-jane-doe"))
+		util.Equals(t, true, c.roleRequestExists("networks-jane-doe-2"))
+	})
+	t.Run("no kubeconfig without a server", func(t *testing.T) {
+		c := newController(t, Cluster{})
+		roster := newRoster(registrationv1alpha.Student{Email: "jane.doe@edge-net.org", FirstName: "Jane", LastName: "Doe"})
+		c.processRoster(context.TODO(), roster)
+		util.Equals(t, provisioned, roster.Status.State)
+		util.Equals(t, "", roster.Status.Students[0].Kubeconfig)
+		util.Equals(t, "", roster.Status.Students[0].Workspace)
+	})
+	t.Run("semester to end", func(t *testing.T) {
+		c := newController(t, Cluster{})
+		roster := newRoster(registrationv1alpha.Student{Email: "jane.doe@edge-net.org", FirstName: "Jane", LastName: "Doe"})
+		roster.Spec.Expiry = &metav1.Time{Time: time.Now().Add(time.Hour)}
+		next := c.processRoster(context.TODO(), roster)
+		util.Assert(t, next > 0 && next <= time.Hour, "unexpected requeue after %s", next)
+	})
+}
+
+func TestProcessRosterFailure(t *testing.T) {
+	jane := registrationv1alpha.Student{Email: "jane.doe@edge-net.org", FirstName: "Jane", LastName: "Doe"}
+	cases := map[string]struct {
+		namespace string
+		role      string
+		csv       string
+	}{
+		"owner role":       {"edgenet", "owner", ""},
+		"tenant not found": {"other", "collaborator", ""},
+		"malformed csv":    {"edgenet", "collaborator", "richard.roe@edge-net.org"},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			c := newController(t, Cluster{})
+			roster := newRoster(jane)
+			roster.SetNamespace(tc.namespace)
+			roster.Spec.Role = tc.role
+			roster.Spec.CSV = tc.csv
+			c.processRoster(context.TODO(), roster)
+			util.Equals(t, failure, roster.Status.State)
+			util.Equals(t, 0, roster.Status.Provisioned)
+			util.Equals(t, false, c.roleRequestExists("networks-jane-doe"))
+		})
+	}
+}
+
+func TestTeardown(t *testing.T) {
+	c := newController(t, Cluster{})
+	roster := newRoster(registrationv1alpha.Student{Email: "jane.doe@edge-net.org", FirstName: "Jane", LastName: "Doe"})
+	c.processRoster(context.TODO(), roster)
+	util.Equals(t, true, c.roleRequestExists("networks-jane-doe"))
+	// The role request controller binds the approved students to the role
+	roleBinding := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "edgenet:clusterrole:edgenet:tenant-collaborator", Namespace: "edgenet"},
+		RoleRef: rbacv1.RoleRef{Kind: "ClusterRole", Name: access.TenantCollaboratorRole},
+		Subjects: []rbacv1.Subject{
+			{Kind: "User", Name: "jane.doe@edge-net.org", APIGroup: "rbac.authorization.k8s.io"},
+			{Kind: "User", Name: "john.doe@edge-net.org", APIGroup: "rbac.authorization.k8s.io"},
+		}}
+	_, err := c.kubeclientset.RbacV1().RoleBindings("edgenet").Create(context.TODO(), roleBinding, metav1.CreateOptions{})
+	util.OK(t, err)
+
+	roster.Spec.Teardown = true
+	c.processRoster(context.TODO(), roster)
+	util.Equals(t, tornDown, roster.Status.State)
+	util.Equals(t, 0, roster.Status.Provisioned)
+	util.Equals(t, false, c.roleRequestExists("networks-jane-doe"))
+	roleBinding, err = c.kubeclientset.RbacV1().RoleBindings("edgenet").Get(context.TODO(), roleBinding.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, []rbacv1.Subject{{Kind: "User", Name: "john.doe@edge-net.org", APIGroup: "rbac.authorization.k8s.io"}}, roleBinding.Subjects)
+
+	t.Run("expired", func(t *testing.T) {
+		c := newController(t, Cluster{})
+		roster := newRoster(registrationv1alpha.Student{Email: "jane.doe@edge-net.org", FirstName: "Jane", LastName: "Doe"})
+		c.processRoster(context.TODO(), roster)
+		roster.Spec.Expiry = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+		util.Equals(t, time.Duration(0), c.processRoster(context.TODO(), roster))
+		util.Equals(t, tornDown, roster.Status.State)
+		util.Equals(t, false, c.roleRequestExists("networks-jane-doe"))
+	})
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roster
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+)
+
+// maxHandleLength leaves room for the name of the roster in the names of the objects provisioned
+const maxHandleLength = 30
+
+// ParseStudents returns the students listed in the roster and the ones in its CSV, a student listed
+// twice is provisioned once
+func ParseStudents(spec registrationv1alpha.RosterSpec) ([]registrationv1alpha.Student, error) {
+	students := []registrationv1alpha.Student{}
+	listed := map[string]bool{}
+	add := func(student registrationv1alpha.Student) error {
+		student.Email = strings.TrimSpace(student.Email)
+		if !strings.Contains(student.Email, "@") {
+			return fmt.Errorf("invalid email %q", student.Email)
+		}
+		if email := strings.ToLower(student.Email); !listed[email] {
+			listed[email] = true
+			students = append(students, student)
+		}
+		return nil
+	}
+	for _, student := range spec.Students {
+		if err := add(student); err != nil {
+			return nil, err
+		}
+	}
+	if strings.TrimSpace(spec.CSV) == "" {
+		return students, nil
+	}
+
+	reader := csv.NewReader(strings.NewReader(spec.CSV))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "email") {
+			continue
+		}
+		if len(record) != 3 {
+			return nil, fmt.Errorf("line %d has %d columns, email, first name, and last name are expected", line, len(record))
+		}
+		student := registrationv1alpha.Student{Email: record[0], FirstName: strings.TrimSpace(record[1]), LastName: strings.TrimSpace(record[2])}
+		if err := add(student); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	return students, nil
+}
+
+// Handle derives the handle of a student from the local part of the email address, so that it can
+// be part of the names of the objects
+func Handle(email string) string {
+	local := strings.ToLower(email)
+	if at := strings.Index(local, "@"); at >= 0 {
+		local = local[:at]
+	}
+	handle := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, local)
+	if len(handle) > maxHandleLength {
+		handle = handle[:maxHandleLength]
+	}
+	handle = strings.Trim(handle, "-")
+	if handle == "" {
+		return "student"
+	}
+	return handle
+}
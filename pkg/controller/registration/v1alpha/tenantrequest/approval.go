@@ -0,0 +1,83 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantrequest
+
+import (
+	"fmt"
+	"strings"
+
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+)
+
+// Verdicts of the approval records
+const (
+	verdictApprove = "Approve"
+	verdictReject  = "Reject"
+)
+
+// ApprovalQuorum is the number of distinct administrators who need to approve a tenant request
+var ApprovalQuorum = 1
+
+// tally counts the distinct approvers of the request and returns the first administrator who
+// rejected it, if any. The approved field stands for a single approval as long as the quorum is one.
+func tally(tenantRequest *registrationv1alpha.TenantRequest) (int, string) {
+	approvers := make(map[string]bool)
+	for _, approval := range tenantRequest.Spec.Approvals {
+		approver := strings.ToLower(approval.Approver)
+		switch approval.Verdict {
+		case verdictReject:
+			return len(approvers), approval.Approver
+		case verdictApprove:
+			approvers[approver] = true
+		}
+	}
+	approvals := len(approvers)
+	if tenantRequest.Spec.Approved && quorum() == 1 && approvals == 0 {
+		approvals = 1
+	}
+	return approvals, ""
+}
+
+// approvers returns the distinct administrators who approved the request, in the order of their approvals
+func approvers(tenantRequest *registrationv1alpha.TenantRequest) []string {
+	list := []string{}
+	seen := make(map[string]bool)
+	for _, approval := range tenantRequest.Spec.Approvals {
+		approver := strings.ToLower(approval.Approver)
+		if approval.Verdict == verdictApprove && !seen[approver] {
+			seen[approver] = true
+			list = append(list, approval.Approver)
+		}
+	}
+	return list
+}
+
+// quorum returns the approval quorum, a request needs at least one approval whatever the setting
+func quorum() int {
+	if ApprovalQuorum < 1 {
+		return 1
+	}
+	return ApprovalQuorum
+}
+
+// approvalMessage tells how far the request is from the quorum
+func approvalMessage(approvals int) string {
+	if quorum() == 1 {
+		return messageNotApproved
+	}
+	return fmt.Sprintf("%s, %d of %d approvals", messageNotApproved, approvals, quorum())
+}
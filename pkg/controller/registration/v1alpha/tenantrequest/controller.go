@@ -20,46 +20,76 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/EdgeNet-project/edgenet/pkg/access"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
 	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/audit"
+	"github.com/EdgeNet-project/edgenet/pkg/config"
 	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
 	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
 	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
 	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/registration/v1alpha"
 	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/policy"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+	"github.com/EdgeNet-project/edgenet/pkg/storage"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 const controllerAgentName = "tenantrequest-controller"
 
+// RetentionPeriod is how long an approved request is kept for audit before it gets pruned
+var RetentionPeriod = 30 * 24 * time.Hour
+
+// ApprovalTimeout is how long a request waits for the approval before it expires, the contact can
+// extend it once
+var ApprovalTimeout = 72 * time.Hour
+
 // Definitions of the state of the tenantrequest resource
 const (
 	successSynced               = "Synced"
 	messageResourceSynced       = "Tenant Request synced successfully"
 	warningNotApproved          = "Not Approved"
 	messageNotApproved          = "Waiting for Requested Tenant to be approved"
+	warningNotVerified          = "Not Verified"
+	messageNotVerified          = "Waiting for the email address of the contact to be verified"
 	successApproved             = "Approved"
 	messageRoleApproved         = "Requested Tenant approved successfully"
+	messagePolicyApproved       = "Requested Tenant approved by the approval policy"
 	failureTenantCreation       = "Creation Failed"
 	messageTenantCreationFailed = "Tenant creation failed"
+	failureProfile              = "Profile Missing"
+	messageProfileMissing       = "Tenant profile not found"
 	failureTenantExists         = "Conflicting"
+	failureDeclined             = "Declined"
+	messageDeclined             = "Requested Tenant declined"
+	messagePolicyDeclined       = "Requested Tenant declined by the policies"
 	messageTenantExists         = "Tenant already exists"
+	successRenewed              = "Renewed"
+	messageRenewed              = "Request expiry extended"
+	failureRejected             = "Rejected"
 	failure                     = "Failure"
+	rejected                    = "Rejected"
 	pending                     = "Pending"
 	approved                    = "Approved"
+	// policyActor stands for the Rego policies of the operators in the audit trail
+	policyActor = "policy"
 )
 
 // Controller is the controller implementation for Tenant Request resources
@@ -71,6 +101,9 @@ type Controller struct {
 
 	tenantrequestsLister listers.TenantRequestLister
 	tenantrequestsSynced cache.InformerSynced
+	// nodesLister lists the nodes the requested allocations are weighed against
+	nodesLister corelisters.NodeLister
+	nodesSynced cache.InformerSynced
 
 	// workqueue is a rate limited work queue. This is used to queue work to be
 	// processed instead of performing it as soon as a change happens. This
@@ -81,41 +114,52 @@ type Controller struct {
 	// recorder is an event recorder for recording Event resources to the
 	// Kubernetes API.
 	recorder record.EventRecorder
+	// identity is the identity of the cluster that the objects are labeled with
+	identity *config.ClusterIdentity
+	// expectations hold the status updates that the cache has not caught up with yet
+	expectations *expectations
 }
 
 // NewController returns a new controller
 func NewController(
 	kubeclientset kubernetes.Interface,
 	edgenetclientset clientset.Interface,
-	tenantrequestInformer informers.TenantRequestInformer) *Controller {
+	tenantrequestInformer informers.TenantRequestInformer,
+	nodeInformer coreinformers.NodeInformer) *Controller {
 
 	utilruntime.Must(edgenetscheme.AddToScheme(scheme.Scheme))
-	klog.V(4).Info("Creating event broadcaster")
+	klog.V(4).InfoS("Creating event broadcaster")
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartStructuredLogging(0)
 	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeclientset.CoreV1().Events("")})
 	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
 
 	controller := &Controller{
+		identity:             config.NewClusterIdentity(kubeclientset),
 		kubeclientset:        kubeclientset,
 		edgenetclientset:     edgenetclientset,
 		tenantrequestsLister: tenantrequestInformer.Lister(),
 		tenantrequestsSynced: tenantrequestInformer.Informer().HasSynced,
+		nodesLister:          nodeInformer.Lister(),
+		nodesSynced:          nodeInformer.Informer().HasSynced,
 		workqueue:            workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "TenantRequests"),
 		recorder:             recorder,
+		expectations:         newExpectations(),
 	}
 
-	klog.V(4).Infoln("Setting up event handlers")
+	klog.V(4).InfoS("Setting up event handlers")
 	// Set up an event handler for when Tenant Request resources change
 	tenantrequestInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: controller.enqueueTenantRequest,
 		UpdateFunc: func(old, new interface{}) {
 			newTenantRequest := new.(*registrationv1alpha.TenantRequest)
 			oldTenantRequest := old.(*registrationv1alpha.TenantRequest)
-			if reflect.DeepEqual(newTenantRequest.Spec, oldTenantRequest.Spec) {
-				if (oldTenantRequest.Status.Expiry == nil && newTenantRequest.Status.Expiry != nil) ||
-					!oldTenantRequest.Status.Expiry.Time.Equal(newTenantRequest.Status.Expiry.Time) {
-					controller.enqueueTenantRequestAfter(newTenantRequest, time.Until(newTenantRequest.Status.Expiry.Time))
+			controller.expectations.observe(newTenantRequest)
+			// The verification of the email address lets the request move on to the approval
+			if reflect.DeepEqual(newTenantRequest.Spec, oldTenantRequest.Spec) && access.EmailVerified(newTenantRequest) == access.EmailVerified(oldTenantRequest) {
+				// The requests held at the gate have no expiry to wait for yet
+				if newTenantRequest.Status.Expiry != nil && !oldTenantRequest.Status.Expiry.Equal(newTenantRequest.Status.Expiry) {
+					controller.enqueueTenantRequestAfter(newTenantRequest, untilDeadline(newTenantRequest))
 				}
 				return
 			}
@@ -137,23 +181,25 @@ func NewController(
 func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 	defer utilruntime.HandleCrash()
 	defer c.workqueue.ShutDown()
+	ctx := signals.ContextFor(stopCh)
 
-	klog.V(4).Infoln("Starting Tenant Request controller")
+	klog.V(4).InfoS("Starting Tenant Request controller")
 
-	klog.V(4).Infoln("Waiting for informer caches to sync")
+	klog.V(4).InfoS("Waiting for informer caches to sync")
 	if ok := cache.WaitForCacheSync(stopCh,
-		c.tenantrequestsSynced); !ok {
+		c.tenantrequestsSynced,
+		c.nodesSynced); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
-	klog.V(4).Infoln("Starting workers")
+	klog.V(4).InfoS("Starting workers")
 	for i := 0; i < threadiness; i++ {
-		go wait.Until(c.runWorker, time.Second, stopCh)
+		go wait.UntilWithContext(ctx, c.runWorker, time.Second)
 	}
 
-	klog.V(4).Infoln("Started workers")
+	klog.V(4).InfoS("Started workers")
 	<-stopCh
-	klog.V(4).Infoln("Shutting down workers")
+	klog.V(4).InfoS("Shutting down workers")
 
 	return nil
 }
@@ -161,14 +207,14 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) error {
 // runWorker is a long-running function that will continually call the
 // processNextWorkItem function in order to read and process a message on the
 // workqueue.
-func (c *Controller) runWorker() {
-	for c.processNextWorkItem() {
+func (c *Controller) runWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
 	}
 }
 
 // processNextWorkItem will read a single work item off the workqueue and
 // attempt to process it, by calling the syncHandler.
-func (c *Controller) processNextWorkItem() bool {
+func (c *Controller) processNextWorkItem(ctx context.Context) bool {
 	obj, shutdown := c.workqueue.Get()
 
 	if shutdown {
@@ -185,12 +231,12 @@ func (c *Controller) processNextWorkItem() bool {
 			utilruntime.HandleError(fmt.Errorf("expected string in workqueue but got %#v", obj))
 			return nil
 		}
-		if err := c.syncHandler(key); err != nil {
+		if err := c.syncHandler(ctx, key); err != nil {
 			c.workqueue.AddRateLimited(key)
-			return fmt.Errorf("error syncing '%s': %s, requeuing", key, err.Error())
+			return fmt.Errorf("error syncing '%s': %w, requeuing", key, err)
 		}
 		c.workqueue.Forget(obj)
-		klog.V(4).Infof("Successfully synced '%s'", key)
+		klog.V(4).InfoS("Successfully synced", "key", key)
 		return nil
 	}(obj)
 
@@ -205,7 +251,7 @@ func (c *Controller) processNextWorkItem() bool {
 // syncHandler compares the actual state with the desired, and attempts to
 // converge the two. It then updates the Status block of the Tenant Request
 // resource with the current status of the resource.
-func (c *Controller) syncHandler(key string) error {
+func (c *Controller) syncHandler(ctx context.Context, key string) error {
 	_, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
 		utilruntime.HandleError(fmt.Errorf("invalid resource key: %s", key))
@@ -213,9 +259,14 @@ func (c *Controller) syncHandler(key string) error {
 	}
 
 	tenantrequest, err := c.tenantrequestsLister.Get(name)
+	if err == nil && !c.expectations.satisfied(tenantrequest) {
+		// The cache lags behind the last status update, the request is read from the API server
+		tenantrequest, err = c.edgenetclientset.RegistrationV1alpha().TenantRequests().Get(ctx, name, metav1.GetOptions{})
+	}
 	if err != nil {
 		if errors.IsNotFound(err) {
 			utilruntime.HandleError(fmt.Errorf("tenantrequest '%s' in work queue no longer exists", key))
+			c.expectations.forget(name)
 			return nil
 		}
 
@@ -223,7 +274,9 @@ func (c *Controller) syncHandler(key string) error {
 	}
 
 	if tenantrequest.Status.State != approved {
-		c.processTenantRequest(tenantrequest.DeepCopy())
+		c.processTenantRequest(ctx, tenantrequest.DeepCopy())
+	} else {
+		c.pruneTenantRequest(ctx, tenantrequest)
 	}
 	c.recorder.Event(tenantrequest, corev1.EventTypeNormal, successSynced, messageResourceSynced)
 	return nil
@@ -255,51 +308,205 @@ func (c *Controller) enqueueTenantRequestAfter(obj interface{}, after time.Durat
 	c.workqueue.AddAfter(key, after)
 }
 
-func (c *Controller) processTenantRequest(tenantRequestCopy *registrationv1alpha.TenantRequest) {
+// pruneTenantRequest removes the approved tenant request once its retention period is over, it is queued
+// again until then. The request is logged before its removal to leave a trace for audit.
+func (c *Controller) pruneTenantRequest(ctx context.Context, tenantRequest *registrationv1alpha.TenantRequest) {
+	if tenantRequest.Status.Expiry == nil {
+		return
+	}
+	if remaining := time.Until(tenantRequest.Status.Expiry.Time); remaining > 0 {
+		c.enqueueTenantRequestAfter(tenantRequest, remaining)
+		return
+	}
+	klog.InfoS("Pruning the approved tenant request", "tenantRequest", klog.KObj(tenantRequest), "email", tenantRequest.Spec.Contact.Email)
+	if err := archive(ctx, tenantRequest); err != nil {
+		klog.ErrorS(err, "Couldn't archive the tenant request", "tenantRequest", klog.KObj(tenantRequest))
+		c.workqueue.AddRateLimited(tenantRequest.GetName())
+		return
+	}
+	if err := c.edgenetclientset.RegistrationV1alpha().TenantRequests().Delete(ctx, tenantRequest.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		klog.ErrorS(err, "Couldn't delete the tenant request", "tenantRequest", tenantRequest.GetName())
+	}
+}
+
+func (c *Controller) processTenantRequest(ctx context.Context, tenantRequestCopy *registrationv1alpha.TenantRequest) {
 	oldStatus := tenantRequestCopy.Status
 	statusUpdate := func() {
 		if !reflect.DeepEqual(oldStatus, tenantRequestCopy.Status) {
-			c.edgenetclientset.RegistrationV1alpha().TenantRequests().UpdateStatus(context.TODO(), tenantRequestCopy, metav1.UpdateOptions{})
+			if updated, err := c.edgenetclientset.RegistrationV1alpha().TenantRequests().UpdateStatus(ctx, tenantRequestCopy, metav1.UpdateOptions{}); err == nil {
+				c.expectations.expect(updated)
+			} else {
+				klog.ErrorS(err, "Couldn't update the status of the request", "tenantRequest", klog.KObj(tenantRequestCopy))
+			}
 		}
 	}
-	if _, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenantRequestCopy.GetName(), metav1.GetOptions{}); err == nil {
+	if _, err := c.edgenetclientset.CoreV1alpha().Tenants().Get(ctx, tenantRequestCopy.GetName(), metav1.GetOptions{}); err == nil {
 		c.recorder.Event(tenantRequestCopy, corev1.EventTypeWarning, failureTenantExists, messageTenantExists)
 		tenantRequestCopy.Status.State = failure
 		tenantRequestCopy.Status.Message = messageTenantExists
 		return
 	}
 	if tenantRequestCopy.Status.Expiry == nil {
-		// Set the approval timeout which is 72 hours
+		// A new request goes through the gate before it waits for the approval, it waits at the
+		// gate while the pending requests cannot be counted
+		refusal, err := c.admit(tenantRequestCopy)
+		if err != nil {
+			klog.ErrorS(err, "Couldn't count the pending requests", "tenantRequest", klog.KObj(tenantRequestCopy))
+			c.workqueue.AddRateLimited(tenantRequestCopy.GetName())
+			return
+		}
+		c.audit(ctx, tenantRequestCopy, audit.RequestCreated, tenantRequestCopy.Spec.Contact.Email, "")
+		if refusal != "" {
+			c.audit(ctx, tenantRequestCopy, audit.RequestDeclined, "", refusal)
+			c.recorder.Event(tenantRequestCopy, corev1.EventTypeWarning, failureRejected, refusal)
+			tenantRequestCopy.Status.State = rejected
+			tenantRequestCopy.Status.Message = refusal
+			tenantRequestCopy.Status.Expiry = &metav1.Time{
+				Time: time.Now().Add(rejectionPeriod),
+			}
+			statusUpdate()
+			return
+		}
+		// Set the approval timeout
 		tenantRequestCopy.Status.Expiry = &metav1.Time{
-			Time: time.Now().Add(72 * time.Hour),
+			Time: time.Now().Add(ApprovalTimeout),
 		}
 	} else if time.Until(tenantRequestCopy.Status.Expiry.Time) <= 0 {
-		c.edgenetclientset.RegistrationV1alpha().TenantRequests().Delete(context.TODO(), tenantRequestCopy.GetName(), metav1.DeleteOptions{})
+		if err := archive(ctx, tenantRequestCopy); err != nil {
+			klog.ErrorS(err, "Couldn't archive the tenant request", "tenantRequest", klog.KObj(tenantRequestCopy))
+			c.workqueue.AddRateLimited(tenantRequestCopy.GetName())
+			return
+		}
+		c.edgenetclientset.RegistrationV1alpha().TenantRequests().Delete(ctx, tenantRequestCopy.GetName(), metav1.DeleteOptions{})
+		return
+	} else if tenantRequestCopy.Status.State == rejected {
 		return
 	}
 	defer statusUpdate()
 
-	_, err := c.kubeclientset.CoreV1().Namespaces().Get(context.TODO(), "kube-system", metav1.GetOptions{})
+	clusterUID, err := c.identity.UID(ctx)
 	if err != nil {
-		klog.V(4).Infoln(err)
-		c.edgenetclientset.RegistrationV1alpha().TenantRequests().Delete(context.TODO(), tenantRequestCopy.GetName(), metav1.DeleteOptions{})
+		klog.ErrorS(err, "Couldn't read the cluster UID")
+		c.edgenetclientset.RegistrationV1alpha().TenantRequests().Delete(ctx, tenantRequestCopy.GetName(), metav1.DeleteOptions{})
+		return
+	}
+
+	if tenantRequestCopy.Spec.Renew && !tenantRequestCopy.Status.Renewed {
+		// The contact extends the expiry of the pending request once
+		c.recorder.Event(tenantRequestCopy, corev1.EventTypeNormal, successRenewed, messageRenewed)
+		tenantRequestCopy.Status.Expiry = &metav1.Time{
+			Time: time.Now().Add(ApprovalTimeout),
+		}
+		tenantRequestCopy.Status.Renewed = true
+		tenantRequestCopy.Status.Reminders = 0
+	}
+
+	// The estimate helps the administrators decide, the request goes on without it if it cannot be made
+	if estimate, err := c.estimate(ctx, tenantRequestCopy); err == nil {
+		tenantRequestCopy.Status.Estimate = estimate
+	} else {
+		klog.ErrorS(err, "Couldn't estimate the requested allocation", "tenantRequest", klog.KObj(tenantRequestCopy))
+	}
+
+	if !access.EmailVerified(tenantRequestCopy) {
+		c.remind(tenantRequestCopy, clusterUID)
+		// The request awaits the approval only once the contact has verified their email address
+		if tenantRequestCopy.Status.State == pending && tenantRequestCopy.Status.Message == messageNotVerified {
+			return
+		}
+		c.recorder.Event(tenantRequestCopy, corev1.EventTypeWarning, warningNotVerified, messageNotVerified)
+		tenantRequestCopy.Status.State = pending
+		tenantRequestCopy.Status.Message = messageNotVerified
 		return
 	}
 
-	if !tenantRequestCopy.Spec.Approved {
-		if tenantRequestCopy.Status.State == pending && tenantRequestCopy.Status.Message == messageNotApproved {
+	// The policies of the operators have the last word, the request waits while they cannot be consulted
+	decision, err := policy.Evaluate(ctx, policy.HookTenantRequestApproval, tenantRequestCopy)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't consult the policies", "tenantRequest", klog.KObj(tenantRequestCopy))
+		c.workqueue.AddRateLimited(tenantRequestCopy.GetName())
+		return
+	}
+	approvals, rejectedBy := tally(tenantRequestCopy)
+	approvedBy := ""
+	if rejectedBy == "" && approvals < quorum() && access.EmailConfirmed(tenantRequestCopy) {
+		// The requests of the trusted institutions don't wait for the administrators, once the
+		// contact has proven to own the email address that the rules match
+		if decision.Approve {
+			approvedBy = policyActor
+			approvals = quorum()
+		} else if rule, err := c.preapproval(ctx, tenantRequestCopy); err != nil {
+			klog.ErrorS(err, "Couldn't evaluate the approval policy", "tenantRequest", klog.KObj(tenantRequestCopy))
+		} else if rule != "" {
+			approvedBy = fmt.Sprintf("%s/%s", ApprovalPolicyName, rule)
+			approvals = quorum()
+		}
+	}
+	if decision.Denied() {
+		message := fmt.Sprintf("%s: %s", messagePolicyDeclined, decision.Reason())
+		if tenantRequestCopy.Status.State == failure && tenantRequestCopy.Status.Message == message {
+			return
+		}
+		c.audit(ctx, tenantRequestCopy, audit.RequestDeclined, policyActor, decision.Reason())
+		c.recorder.Event(tenantRequestCopy, corev1.EventTypeWarning, failureDeclined, message)
+		tenantRequestCopy.Status.State = failure
+		tenantRequestCopy.Status.Message = message
+	} else if rejectedBy != "" {
+		message := fmt.Sprintf("%s by %s", messageDeclined, rejectedBy)
+		if tenantRequestCopy.Status.State == failure && tenantRequestCopy.Status.Message == message {
+			return
+		}
+		c.audit(ctx, tenantRequestCopy, audit.RequestDeclined, rejectedBy, "")
+		c.recorder.Event(tenantRequestCopy, corev1.EventTypeWarning, failureDeclined, message)
+		tenantRequestCopy.Status.State = failure
+		tenantRequestCopy.Status.Message = message
+	} else if approvals < quorum() {
+		c.remind(tenantRequestCopy, clusterUID)
+		c.forward(ctx, tenantRequestCopy)
+		message := approvalMessage(approvals)
+		if tenantRequestCopy.Status.State == pending && tenantRequestCopy.Status.Message == message {
 			return
 		}
-		c.recorder.Event(tenantRequestCopy, corev1.EventTypeWarning, warningNotApproved, messageNotApproved)
+		c.recorder.Event(tenantRequestCopy, corev1.EventTypeWarning, warningNotApproved, message)
 		tenantRequestCopy.Status.State = pending
-		tenantRequestCopy.Status.Message = messageNotApproved
+		tenantRequestCopy.Status.Message = message
 	} else {
-		c.recorder.Event(tenantRequestCopy, corev1.EventTypeNormal, successApproved, messageRoleApproved)
+		// The approval creates the tenant, it acts on the latest version of the request rather
+		// than the cached one. The request is processed again if the cache is out of date.
+		latest, err := c.edgenetclientset.RegistrationV1alpha().TenantRequests().Get(ctx, tenantRequestCopy.GetName(), metav1.GetOptions{})
+		if err != nil || latest.GetResourceVersion() != tenantRequestCopy.GetResourceVersion() {
+			tenantRequestCopy.Status = oldStatus
+			if err != nil {
+				klog.ErrorS(err, "Couldn't read the latest version of the request", "tenantRequest", klog.KObj(tenantRequestCopy))
+			}
+			c.workqueue.AddRateLimited(tenantRequestCopy.GetName())
+			return
+		}
+		message := messageRoleApproved
+		if approvedBy != "" {
+			message = messagePolicyApproved
+		} else {
+			approvedBy = strings.Join(approvers(tenantRequestCopy), ", ")
+		}
+		c.recorder.Event(tenantRequestCopy, corev1.EventTypeNormal, successApproved, message)
 		tenantRequestCopy.Status.State = approved
-		tenantRequestCopy.Status.Message = messageRoleApproved
+		tenantRequestCopy.Status.Message = message
 
-		if err := access.CreateTenant(tenantRequestCopy); err == nil {
-			c.recorder.Event(tenantRequestCopy, corev1.EventTypeNormal, successApproved, messageRoleApproved)
+		profile, err := c.profile(ctx, tenantRequestCopy)
+		if err != nil {
+			klog.ErrorS(err, "Couldn't get tenant profile", "tenantRequest", klog.KObj(tenantRequestCopy), "profile", tenantRequestCopy.Spec.Profile)
+			c.recorder.Event(tenantRequestCopy, corev1.EventTypeWarning, failureProfile, messageProfileMissing)
+			tenantRequestCopy.Status.State = failure
+			tenantRequestCopy.Status.Message = messageProfileMissing
+			return
+		}
+		if err := access.CreateTenant(ctx, tenantRequestCopy, profile); err == nil {
+			c.recorder.Event(tenantRequestCopy, corev1.EventTypeNormal, successApproved, message)
+			c.audit(ctx, tenantRequestCopy, audit.RequestApproved, approvedBy, "")
+			// The approved request is kept for audit until the retention period is over
+			tenantRequestCopy.Status.Expiry = &metav1.Time{
+				Time: time.Now().Add(RetentionPeriod),
+			}
 		} else {
 			c.recorder.Event(tenantRequestCopy, corev1.EventTypeWarning, failureTenantCreation, messageTenantCreationFailed)
 			tenantRequestCopy.Status.State = failure
@@ -307,3 +514,54 @@ func (c *Controller) processTenantRequest(tenantRequestCopy *registrationv1alpha
 		}
 	}
 }
+
+// untilDeadline returns the time left until the request expires or a reminder of its expiry falls due
+func untilDeadline(tenantRequest *registrationv1alpha.TenantRequest) time.Duration {
+	until := time.Until(tenantRequest.Status.Expiry.Time)
+	if tenantRequest.Status.State != pending {
+		return until
+	}
+	if due, next := access.RemindersDue(tenantRequest.Status.Expiry.Time, time.Now()); due > tenantRequest.Status.Reminders {
+		return 0
+	} else if next > 0 && next < until {
+		return next
+	}
+	return until
+}
+
+// remind emails the contact as the reminders before the expiry of the pending request fall due,
+// the request is queued again for the next reminder or the expiry
+func (c *Controller) remind(tenantRequestCopy *registrationv1alpha.TenantRequest, clusterUID string) {
+	due, next := access.RemindersDue(tenantRequestCopy.Status.Expiry.Time, time.Now())
+	if until := time.Until(tenantRequestCopy.Status.Expiry.Time); next == 0 || until < next {
+		next = until
+	}
+	c.enqueueTenantRequestAfter(tenantRequestCopy, next)
+	if due <= tenantRequestCopy.Status.Reminders {
+		return
+	}
+	tenantRequestCopy.Status.Reminders = due
+	if err := access.SendReminderEmailForTenantRequest(tenantRequestCopy, clusterUID); err != nil {
+		klog.ErrorS(err, "Couldn't remind the contact of the request expiry", "tenantRequest", klog.KObj(tenantRequestCopy))
+	}
+}
+
+// profile returns the tenant profile the request refers to, nil if it refers to none
+func (c *Controller) profile(ctx context.Context, tenantRequest *registrationv1alpha.TenantRequest) (*corev1alpha.TenantProfile, error) {
+	if tenantRequest.Spec.Profile == "" {
+		return nil, nil
+	}
+	return c.edgenetclientset.CoreV1alpha().TenantProfiles().Get(ctx, tenantRequest.Spec.Profile, metav1.GetOptions{})
+}
+
+// archive keeps the request in the archive before it leaves etcd, the request is archived at the
+// time of its expiry
+func archive(ctx context.Context, tenantRequest *registrationv1alpha.TenantRequest) error {
+	return storage.Archive(ctx, storage.KindTenantRequest, tenantRequest.GetName(), tenantRequest.GetName(), tenantRequest.Status.Expiry.Time, tenantRequest)
+}
+
+// audit records the action taken on the request in the audit trail of the requested tenant
+func (c *Controller) audit(ctx context.Context, tenantRequest *registrationv1alpha.TenantRequest, action, actor, message string) {
+	object := corev1alpha.TenantAuditObject{Kind: "TenantRequest", Name: tenantRequest.GetName()}
+	audit.Record(ctx, c.edgenetclientset, tenantRequest.GetName(), action, actor, object, message)
+}
@@ -2,20 +2,30 @@ package tenantrequest
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
+	"fmt"
 	"io/ioutil"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/EdgeNet-project/edgenet/pkg/access"
 	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
 	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/approval"
 	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
 	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
 	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/policy"
 	"github.com/EdgeNet-project/edgenet/pkg/signals"
+	"github.com/EdgeNet-project/edgenet/pkg/storage"
+	"github.com/EdgeNet-project/edgenet/pkg/testutil"
 	"github.com/EdgeNet-project/edgenet/pkg/util"
 	"github.com/sirupsen/logrus"
 
@@ -23,9 +33,13 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	testclient "k8s.io/client-go/kubernetes/fake"
-	"k8s.io/klog"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
 )
 
 type TestGroup struct {
@@ -44,14 +58,21 @@ func TestMain(m *testing.M) {
 	flag.String("smtp-path", "../../../../../configs/smtp_test.yaml", "Set SMTP path.")
 	flag.Parse()
 
+	// The test requests share the contact email, TestGate sets the limits itself
+	MaxPendingPerEmail = 0
+	MaxPendingPerDomain = 0
+
 	stopCh := signals.SetupSignalHandler()
 
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeclientset, time.Second*30)
 	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, time.Second*30)
 
 	controller := NewController(kubeclientset,
 		edgenetclientset,
-		edgenetInformerFactory.Registration().V1alpha().TenantRequests())
+		edgenetInformerFactory.Registration().V1alpha().TenantRequests(),
+		kubeInformerFactory.Core().V1().Nodes())
 
+	kubeInformerFactory.Start(stopCh)
 	edgenetInformerFactory.Start(stopCh)
 
 	go func() {
@@ -61,8 +82,8 @@ func TestMain(m *testing.M) {
 	}()
 
 	access.Clientset = kubeclientset
-	access.CreateClusterRoles()
-	kubeSystemNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}}
+	access.ReconcileClusterRoles(context.TODO())
+	kubeSystemNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", UID: "kube-system-uid"}}
 	kubeclientset.CoreV1().Namespaces().Create(context.TODO(), kubeSystemNamespace, metav1.CreateOptions{})
 
 	time.Sleep(500 * time.Millisecond)
@@ -137,6 +158,21 @@ func TestStartController(t *testing.T) {
 	util.OK(t, err)
 	util.Equals(t, approved, tenantRequest.Status.State)
 	util.Equals(t, messageRoleApproved, tenantRequest.Status.Message)
+	// The approved request is kept until the retention period is over
+	expected = metav1.Time{
+		Time: time.Now().Add(RetentionPeriod),
+	}
+	util.Equals(t, expected.Day(), tenantRequest.Status.Expiry.Day())
+	util.Equals(t, expected.Month(), tenantRequest.Status.Expiry.Month())
+
+	tenantRequest.Status.Expiry = &metav1.Time{
+		Time: time.Now().Add(10 * time.Millisecond),
+	}
+	edgenetclientset.RegistrationV1alpha().TenantRequests().UpdateStatus(context.TODO(), tenantRequest, metav1.UpdateOptions{})
+	testutil.Eventually(t, func() (bool, error) {
+		_, err := edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), tenantRequest.GetName(), metav1.GetOptions{})
+		return errors.IsNotFound(err), nil
+	}, "Approved tenant request %s not pruned", tenantRequest.GetName())
 }
 
 func TestTimeout(t *testing.T) {
@@ -193,3 +229,508 @@ func TestUpdate(t *testing.T) {
 		})
 	})
 }
+
+func TestGate(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	MaxPendingPerEmail = 1
+	MaxPendingPerDomain = 2
+	defer func() {
+		MaxPendingPerEmail = 0
+		MaxPendingPerDomain = 0
+	}()
+
+	cases := []struct {
+		name     string
+		email    string
+		expected string
+	}{
+		{"gate-test-1", "flood@spam.io", pending},
+		{"gate-test-2", "FLOOD@spam.io", rejected},
+		{"gate-test-3", "other@spam.io", pending},
+		{"gate-test-4", "another@spam.io", rejected},
+		{"gate-test-5", "someone@edge-net.org", pending},
+	}
+	for _, tc := range cases {
+		tenantRequestTest := g.tenantRequestObj.DeepCopy()
+		tenantRequestTest.SetName(tc.name)
+		tenantRequestTest.Spec.Contact.Email = tc.email
+		_, err := edgenetclientset.RegistrationV1alpha().TenantRequests().Create(context.TODO(), tenantRequestTest, metav1.CreateOptions{})
+		util.OK(t, err)
+		time.Sleep(250 * time.Millisecond)
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tenantRequest, err := edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), tc.name, metav1.GetOptions{})
+			util.OK(t, err)
+			util.Equals(t, tc.expected, tenantRequest.Status.State)
+			if tc.expected == rejected {
+				expected := time.Now().Add(rejectionPeriod)
+				util.Assert(t, tenantRequest.Status.Expiry.Time.Before(expected), "rejected request kept for the approval timeout")
+			}
+		})
+	}
+}
+
+func TestAdmitFromCache(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	MaxPendingPerEmail = 1
+	defer func() { MaxPendingPerEmail = 0 }()
+
+	earlierRequest := g.tenantRequestObj.DeepCopy()
+	earlierRequest.SetName("cache-test-1")
+	earlierRequest.SetCreationTimestamp(metav1.NewTime(time.Now().Add(-time.Minute)))
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	util.OK(t, indexer.Add(earlierRequest))
+	// The gate counts the requests of the cache, there is no API server to list them from
+	c := &Controller{tenantrequestsLister: listers.NewTenantRequestLister(indexer)}
+
+	tenantRequest := g.tenantRequestObj.DeepCopy()
+	tenantRequest.SetName("cache-test-2")
+	tenantRequest.SetCreationTimestamp(metav1.Now())
+	refusal, err := c.admit(tenantRequest)
+	util.OK(t, err)
+	util.Assert(t, refusal != "", "second pending request of the same email admitted")
+}
+
+func TestProfile(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	profile := &corev1alpha.TenantProfile{ObjectMeta: metav1.ObjectMeta{Name: "classroom"}}
+	profile.Spec.ResourceAllocation = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4000m")}
+	profile.Spec.NetworkPolicy = "permissive"
+	profile.Spec.NodePools = []string{"teaching"}
+	profile.Spec.Expiry = &metav1.Duration{Duration: 24 * time.Hour}
+	edgenetclientset.CoreV1alpha().TenantProfiles().Create(context.TODO(), profile, metav1.CreateOptions{})
+
+	t.Run("defaults", func(t *testing.T) {
+		tenantRequestTest := g.tenantRequestObj.DeepCopy()
+		tenantRequestTest.SetName("tenant-request-profile-test")
+		tenantRequestTest.Spec.Profile = profile.GetName()
+		tenantRequestTest.Spec.ResourceAllocation = nil
+		tenantRequestTest.Spec.Approved = true
+		edgenetclientset.RegistrationV1alpha().TenantRequests().Create(context.TODO(), tenantRequestTest, metav1.CreateOptions{})
+		time.Sleep(250 * time.Millisecond)
+
+		tenant, err := edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenantRequestTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, profile.GetName(), tenant.Spec.Profile)
+		util.Equals(t, profile.Spec.NodePools, tenant.Spec.NodePools)
+		util.Equals(t, "false", tenant.GetAnnotations()["edge-net.io/baseline-policies"])
+		tenantResourceQuota, err := edgenetclientset.CoreV1alpha().TenantResourceQuotas().Get(context.TODO(), tenantRequestTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, profile.Spec.ResourceAllocation, tenantResourceQuota.Spec.Claim["initial"].ResourceList)
+		util.Equals(t, time.Now().Add(24*time.Hour).Day(), tenantResourceQuota.Spec.Claim["initial"].Expiry.Day())
+	})
+	t.Run("requested allocation", func(t *testing.T) {
+		tenantRequestTest := g.tenantRequestObj.DeepCopy()
+		tenantRequestTest.SetName("tenant-request-profile-allocation-test")
+		tenantRequestTest.Spec.Profile = profile.GetName()
+		tenantRequestTest.Spec.Approved = true
+		edgenetclientset.RegistrationV1alpha().TenantRequests().Create(context.TODO(), tenantRequestTest, metav1.CreateOptions{})
+		time.Sleep(250 * time.Millisecond)
+
+		tenantResourceQuota, err := edgenetclientset.CoreV1alpha().TenantResourceQuotas().Get(context.TODO(), tenantRequestTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, tenantRequestTest.Spec.ResourceAllocation, tenantResourceQuota.Spec.Claim["initial"].ResourceList)
+	})
+	t.Run("missing profile", func(t *testing.T) {
+		tenantRequestTest := g.tenantRequestObj.DeepCopy()
+		tenantRequestTest.SetName("tenant-request-missing-profile-test")
+		tenantRequestTest.Spec.Profile = "commercial"
+		tenantRequestTest.Spec.Approved = true
+		edgenetclientset.RegistrationV1alpha().TenantRequests().Create(context.TODO(), tenantRequestTest, metav1.CreateOptions{})
+		time.Sleep(250 * time.Millisecond)
+
+		tenantRequest, err := edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), tenantRequestTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, failure, tenantRequest.Status.State)
+		util.Equals(t, messageProfileMissing, tenantRequest.Status.Message)
+		_, err = edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenantRequestTest.GetName(), metav1.GetOptions{})
+		util.Equals(t, true, errors.IsNotFound(err))
+	})
+}
+
+func TestInvitationToken(t *testing.T) {
+	key := []byte("secret")
+	now := time.Now()
+	token := NewInvitationToken(key, "Invited@EdgeNet.io", now.Add(time.Hour))
+
+	cases := map[string]struct {
+		key      []byte
+		email    string
+		token    string
+		now      time.Time
+		expected bool
+	}{
+		"valid":         {key, "invited@edgenet.io", token, now, true},
+		"missing":       {key, "invited@edgenet.io", "", now, false},
+		"malformed":     {key, "invited@edgenet.io", "token", now, false},
+		"other email":   {key, "intruder@edgenet.io", token, now, false},
+		"other key":     {[]byte("guess"), "invited@edgenet.io", token, now, false},
+		"expired":       {key, "invited@edgenet.io", token, now.Add(2 * time.Hour), false},
+		"forged expiry": {key, "invited@edgenet.io", "9999999999" + token[strings.Index(token, "."):], now, false},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			err := VerifyInvitationToken(tc.key, tc.email, tc.token, tc.now)
+			util.Equals(t, tc.expected, err == nil)
+		})
+	}
+}
+
+func TestQuorum(t *testing.T) {
+	approve := func(approver string) registrationv1alpha.Approval {
+		return registrationv1alpha.Approval{Approver: approver, Time: metav1.Now(), Verdict: verdictApprove}
+	}
+	reject := func(approver string) registrationv1alpha.Approval {
+		return registrationv1alpha.Approval{Approver: approver, Time: metav1.Now(), Verdict: verdictReject}
+	}
+	defer func() { ApprovalQuorum = 1 }()
+
+	cases := map[string]struct {
+		quorum     int
+		approved   bool
+		approvals  []registrationv1alpha.Approval
+		expected   int
+		rejectedBy string
+	}{
+		"approved field":          {1, true, nil, 1, ""},
+		"approved field quorum 2": {2, true, nil, 0, ""},
+		"one approval":            {2, false, []registrationv1alpha.Approval{approve("alice@edge-net.org")}, 1, ""},
+		"same approver twice":     {2, false, []registrationv1alpha.Approval{approve("alice@edge-net.org"), approve("Alice@edge-net.org")}, 1, ""},
+		"two approvals":           {2, false, []registrationv1alpha.Approval{approve("alice@edge-net.org"), approve("bob@edge-net.org")}, 2, ""},
+		"rejection":               {2, false, []registrationv1alpha.Approval{approve("alice@edge-net.org"), reject("bob@edge-net.org")}, 1, "bob@edge-net.org"},
+		"quorum below one":        {0, false, nil, 0, ""},
+		"approved field quorum 0": {0, true, nil, 1, ""},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			ApprovalQuorum = tc.quorum
+			tenantRequest := &registrationv1alpha.TenantRequest{Spec: registrationv1alpha.TenantRequestSpec{Approved: tc.approved, Approvals: tc.approvals}}
+			approvals, rejectedBy := tally(tenantRequest)
+			util.Equals(t, tc.expected, approvals)
+			util.Equals(t, tc.rejectedBy, rejectedBy)
+		})
+	}
+	ApprovalQuorum = 2
+	util.Equals(t, messageNotApproved+", 1 of 2 approvals", approvalMessage(1))
+}
+
+func TestApprovalPolicy(t *testing.T) {
+	rule := approvalRule{
+		Name:          "universities",
+		Emails:        []string{"*.sorbonne-universite.fr", "lip6.fr", "guest@inria.fr"},
+		MaxAllocation: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8000m")},
+	}
+	cases := map[string]struct {
+		email      string
+		allocation corev1.ResourceList
+		expected   bool
+	}{
+		"subdomain":          {"alice@lip6.sorbonne-universite.fr", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2000m")}, true},
+		"domain":             {"Alice@LIP6.fr", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8000m")}, true},
+		"address":            {"guest@inria.fr", nil, true},
+		"other address":      {"intruder@inria.fr", nil, false},
+		"other domain":       {"alice@sorbonne-universite.fr.evil.io", nil, false},
+		"allocation exceeds": {"alice@lip6.fr", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("12000m")}, false},
+		"unbounded resource": {"alice@lip6.fr", corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}, false},
+		"malformed email":    {"lip6.fr", nil, false},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			tenantRequest := &registrationv1alpha.TenantRequest{}
+			tenantRequest.Spec.Contact.Email = tc.email
+			tenantRequest.Spec.ResourceAllocation = tc.allocation
+			util.Equals(t, tc.expected, rule.matches(tenantRequest))
+		})
+	}
+
+	t.Run("auto-approval", func(t *testing.T) {
+		policy := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: ApprovalPolicyName, Namespace: "kube-system"},
+			Data: map[string]string{approvalPolicyKey: "- name: lip6\n  emails: [\"lip6.fr\"]\n"}}
+		_, err := kubeclientset.CoreV1().ConfigMaps("kube-system").Create(context.TODO(), policy, metav1.CreateOptions{})
+		util.OK(t, err)
+		defer kubeclientset.CoreV1().ConfigMaps("kube-system").Delete(context.TODO(), ApprovalPolicyName, metav1.DeleteOptions{})
+
+		g := TestGroup{}
+		g.Init()
+		tenantRequestTest := g.tenantRequestObj.DeepCopy()
+		tenantRequestTest.SetName("tenant-request-policy-test")
+		tenantRequestTest.SetAnnotations(map[string]string{access.EmailVerifiedAnnotation: "true"})
+		tenantRequestTest.Spec.Contact.Email = "alice@lip6.fr"
+		edgenetclientset.RegistrationV1alpha().TenantRequests().Create(context.TODO(), tenantRequestTest, metav1.CreateOptions{})
+		time.Sleep(250 * time.Millisecond)
+
+		tenantRequest, err := edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), tenantRequestTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, messagePolicyApproved, tenantRequest.Status.Message)
+		_, err = edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenantRequestTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+
+		t.Run("email not confirmed", func(t *testing.T) {
+			// Nothing proves that the contact owns the address without the annotation
+			tenantRequestTest := g.tenantRequestObj.DeepCopy()
+			tenantRequestTest.SetName("tenant-request-policy-unverified-test")
+			tenantRequestTest.Spec.Contact.Email = "mallory@lip6.fr"
+			edgenetclientset.RegistrationV1alpha().TenantRequests().Create(context.TODO(), tenantRequestTest, metav1.CreateOptions{})
+			time.Sleep(250 * time.Millisecond)
+
+			tenantRequest, err := edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), tenantRequestTest.GetName(), metav1.GetOptions{})
+			util.OK(t, err)
+			util.Equals(t, pending, tenantRequest.Status.State)
+			_, err = edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenantRequestTest.GetName(), metav1.GetOptions{})
+			util.Equals(t, true, errors.IsNotFound(err))
+		})
+	})
+}
+
+func TestEstimate(t *testing.T) {
+	nodes := []corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "paris-1.edge-net.io"}, Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("16"), corev1.ResourceMemory: resource.MustParse("32Gi")}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "lyon-1.edge-net.io"}, Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("8"), corev1.ResourceMemory: resource.MustParse("16Gi")}}},
+		// The cordoned nodes take no tenant
+		{ObjectMeta: metav1.ObjectMeta{Name: "nice-1.edge-net.io"}, Spec: corev1.NodeSpec{Unschedulable: true}, Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("64"), corev1.ResourceMemory: resource.MustParse("128Gi")}}},
+	}
+	nodeIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for i := range nodes {
+		util.OK(t, nodeIndexer.Add(&nodes[i]))
+	}
+	c := &Controller{kubeclientset: testclient.NewSimpleClientset(), nodesLister: corelisters.NewNodeLister(nodeIndexer)}
+	tenantRequest := &registrationv1alpha.TenantRequest{}
+	tenantRequest.Spec.ResourceAllocation = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("12000m"), corev1.ResourceMemory: resource.MustParse("12Gi"),
+		corev1.ResourceEphemeralStorage: resource.MustParse("20Gi")}
+
+	estimate, err := c.estimate(context.TODO(), tenantRequest)
+	util.OK(t, err)
+	util.Equals(t, &registrationv1alpha.CostEstimate{Share: 50, DominantResource: corev1.ResourceCPU}, estimate)
+
+	pricing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: PricingName, Namespace: "kube-system"},
+		Data: map[string]string{pricingKey: "currency: EUR\nresources:\n  cpu:\n    price: 12\n  memory:\n    unit: 1Gi\n    price: 3.5\n"}}
+	_, err = c.kubeclientset.CoreV1().ConfigMaps("kube-system").Create(context.TODO(), pricing, metav1.CreateOptions{})
+	util.OK(t, err)
+	estimate, err = c.estimate(context.TODO(), tenantRequest)
+	util.OK(t, err)
+	util.Equals(t, "186.00", estimate.Monthly)
+	util.Equals(t, "EUR", estimate.Currency)
+	util.Equals(t, map[corev1.ResourceName]string{corev1.ResourceCPU: "144.00", corev1.ResourceMemory: "42.00"}, estimate.Breakdown)
+
+	pricing.Data[pricingKey] = "resources:\n  cpu:\n    unit: \"0\"\n    price: 12\n"
+	_, err = c.kubeclientset.CoreV1().ConfigMaps("kube-system").Update(context.TODO(), pricing, metav1.UpdateOptions{})
+	util.OK(t, err)
+	_, err = c.estimate(context.TODO(), tenantRequest)
+	util.Assert(t, err != nil, "unit of zero accepted")
+}
+
+func TestRenewal(t *testing.T) {
+	g := TestGroup{}
+	g.Init()
+	tenantRequestTest := g.tenantRequestObj.DeepCopy()
+	tenantRequestTest.SetName("tenant-request-renewal-test")
+	edgenetclientset.RegistrationV1alpha().TenantRequests().Create(context.TODO(), tenantRequestTest, metav1.CreateOptions{})
+	time.Sleep(250 * time.Millisecond)
+
+	t.Run("reminder", func(t *testing.T) {
+		tenantRequest, err := edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), tenantRequestTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, 0, tenantRequest.Status.Reminders)
+		tenantRequest.Status.Expiry = &metav1.Time{
+			Time: time.Now().Add(time.Hour),
+		}
+		edgenetclientset.RegistrationV1alpha().TenantRequests().UpdateStatus(context.TODO(), tenantRequest, metav1.UpdateOptions{})
+		time.Sleep(250 * time.Millisecond)
+		tenantRequest, err = edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), tenantRequestTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, len(access.ReminderIntervals), tenantRequest.Status.Reminders)
+	})
+	t.Run("renew", func(t *testing.T) {
+		tenantRequest, err := edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), tenantRequestTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		tenantRequest.Spec.Renew = true
+		edgenetclientset.RegistrationV1alpha().TenantRequests().Update(context.TODO(), tenantRequest, metav1.UpdateOptions{})
+		time.Sleep(250 * time.Millisecond)
+		tenantRequest, err = edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), tenantRequestTest.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, true, tenantRequest.Status.Renewed)
+		util.Equals(t, 0, tenantRequest.Status.Reminders)
+		util.Assert(t, time.Until(tenantRequest.Status.Expiry.Time) > ApprovalTimeout-time.Minute, "expiry not extended")
+	})
+}
+
+func TestExpectations(t *testing.T) {
+	e := newExpectations()
+	now := time.Now()
+	e.now = func() time.Time { return now }
+	version := func(resourceVersion string) *registrationv1alpha.TenantRequest {
+		return &registrationv1alpha.TenantRequest{ObjectMeta: metav1.ObjectMeta{Name: "edgenet-request", ResourceVersion: resourceVersion}}
+	}
+
+	util.Equals(t, true, e.satisfied(version("10")))
+	e.expect(version("12"))
+	util.Equals(t, false, e.satisfied(version("10")))
+	e.observe(version("11"))
+	util.Equals(t, false, e.satisfied(version("11")))
+	// The versions are opaque, a greater number is not a later version
+	util.Equals(t, false, e.satisfied(version("13")))
+	e.observe(version("12"))
+	util.Equals(t, true, e.satisfied(version("10")))
+
+	t.Run("timeout", func(t *testing.T) {
+		e.expect(version("20"))
+		util.Equals(t, false, e.satisfied(version("15")))
+		now = now.Add(expectationTimeout + time.Second)
+		util.Equals(t, true, e.satisfied(version("15")))
+	})
+	t.Run("opaque versions", func(t *testing.T) {
+		e.expect(version("a"))
+		util.Equals(t, false, e.satisfied(version("b")))
+		util.Equals(t, true, e.satisfied(version("a")))
+	})
+	t.Run("no version", func(t *testing.T) {
+		e.expect(version(""))
+		util.Equals(t, true, e.satisfied(version("1")))
+	})
+}
+
+func TestStaleCache(t *testing.T) {
+	clientset := edgenettestclient.NewSimpleClientset()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	c := &Controller{
+		edgenetclientset:     clientset,
+		tenantrequestsLister: listers.NewTenantRequestLister(indexer),
+		recorder:             record.NewFakeRecorder(10),
+		expectations:         newExpectations(),
+	}
+
+	// The controller has approved the request, the cache still holds it pending
+	stale := &registrationv1alpha.TenantRequest{ObjectMeta: metav1.ObjectMeta{Name: "stale-cache", ResourceVersion: "1"}}
+	indexer.Add(stale)
+	latest := stale.DeepCopy()
+	latest.SetResourceVersion("2")
+	latest.Status.State = approved
+	latest.Status.Expiry = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+	clientset.RegistrationV1alpha().TenantRequests().Create(context.TODO(), latest, metav1.CreateOptions{})
+	c.expectations.expect(latest)
+
+	util.OK(t, c.syncHandler(context.TODO(), stale.GetName()))
+	// The approved request read from the API server is pruned
+	_, err := clientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), stale.GetName(), metav1.GetOptions{})
+	util.Equals(t, true, errors.IsNotFound(err))
+}
+
+func TestForward(t *testing.T) {
+	received := []approval.Request{}
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := approval.Request{}
+		util.OK(t, json.NewDecoder(r.Body).Decode(&request))
+		received = append(received, request)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer webhook.Close()
+	c := &Controller{}
+	g := TestGroup{}
+	g.Init()
+	tenantRequestTest := g.tenantRequestObj.DeepCopy()
+	tenantRequestTest.SetUID("tenant-request-uid")
+
+	t.Run("disabled", func(t *testing.T) {
+		c.forward(context.TODO(), tenantRequestTest)
+		util.Equals(t, false, tenantRequestTest.Status.Forwarded)
+	})
+	approval.WebhookURL, approval.Key = webhook.URL, []byte("approval-key")
+	defer func() { approval.WebhookURL, approval.Key = "", nil }()
+	t.Run("forwarded once", func(t *testing.T) {
+		c.forward(context.TODO(), tenantRequestTest)
+		util.Equals(t, true, tenantRequestTest.Status.Forwarded)
+		c.forward(context.TODO(), tenantRequestTest)
+		util.Equals(t, 1, len(received))
+		util.Equals(t, approval.KindTenantRequest, received[0].Kind)
+		util.Equals(t, tenantRequestTest.GetUID(), received[0].UID)
+		util.Equals(t, tenantRequestTest.Spec.Contact.Email, received[0].Email)
+	})
+}
+
+func TestPolicies(t *testing.T) {
+	// The policies deny the requests from outside of the academic institutions and approve those of LIP6
+	opa := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := struct {
+			Input struct {
+				Hook   string                            `json:"hook"`
+				Object registrationv1alpha.TenantRequest `json:"object"`
+			} `json:"input"`
+		}{}
+		util.OK(t, json.NewDecoder(r.Body).Decode(&body))
+		util.Equals(t, policy.HookTenantRequestApproval, body.Input.Hook)
+		switch email := body.Input.Object.Spec.Contact.Email; {
+		case strings.HasSuffix(email, "@lip6.fr"):
+			w.Write([]byte(`{"result": {"approve": true}}`))
+		case strings.HasSuffix(email, ".com"):
+			w.Write([]byte(`{"result": {"deny": ["not an academic institution"]}}`))
+		default:
+			w.Write([]byte(`{"result": {}}`))
+		}
+	}))
+	defer opa.Close()
+	util.OK(t, policy.Configure(policy.BackendOPA, opa.URL+"/v1/data/edgenet", ""))
+	defer func() { policy.URL = "" }()
+
+	g := TestGroup{}
+	g.Init()
+	cases := map[string]struct {
+		email    string
+		state    string
+		message  string
+		approved bool
+	}{
+		"approved": {"bob@lip6.fr", approved, messagePolicyApproved, true},
+		"denied":   {"bob@example.com", failure, "Requested Tenant declined by the policies: not an academic institution", false},
+		"left":     {"bob@inria.fr", pending, approvalMessage(0), false},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			tenantRequestTest := g.tenantRequestObj.DeepCopy()
+			tenantRequestTest.SetName(fmt.Sprintf("tenant-request-rego-%s-test", k))
+			tenantRequestTest.SetAnnotations(map[string]string{access.EmailVerifiedAnnotation: "true"})
+			tenantRequestTest.Spec.Contact.Email = tc.email
+			edgenetclientset.RegistrationV1alpha().TenantRequests().Create(context.TODO(), tenantRequestTest, metav1.CreateOptions{})
+			time.Sleep(250 * time.Millisecond)
+
+			tenantRequest, err := edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), tenantRequestTest.GetName(), metav1.GetOptions{})
+			util.OK(t, err)
+			util.Equals(t, tc.state, tenantRequest.Status.State)
+			util.Equals(t, tc.message, tenantRequest.Status.Message)
+			_, err = edgenetclientset.CoreV1alpha().Tenants().Get(context.TODO(), tenantRequestTest.GetName(), metav1.GetOptions{})
+			util.Equals(t, !tc.approved, errors.IsNotFound(err))
+		})
+	}
+}
+
+func TestArchive(t *testing.T) {
+	store := storage.NewMemoryStore()
+	storage.Default = store
+	defer func() { storage.Default = nil }()
+	c := &Controller{edgenetclientset: edgenettestclient.NewSimpleClientset()}
+	g := TestGroup{}
+	g.Init()
+	tenantRequestTest := g.tenantRequestObj.DeepCopy()
+	tenantRequestTest.SetName("tenant-request-archive-test")
+	tenantRequestTest.Status.State = approved
+	tenantRequestTest.Status.Expiry = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+	_, err := c.edgenetclientset.RegistrationV1alpha().TenantRequests().Create(context.TODO(), tenantRequestTest, metav1.CreateOptions{})
+	util.OK(t, err)
+
+	// The request leaves etcd for the archive once its retention period is over
+	c.pruneTenantRequest(context.TODO(), tenantRequestTest)
+	_, err = c.edgenetclientset.RegistrationV1alpha().TenantRequests().Get(context.TODO(), tenantRequestTest.GetName(), metav1.GetOptions{})
+	util.Equals(t, true, errors.IsNotFound(err))
+	records, err := store.List(context.TODO(), storage.KindTenantRequest, tenantRequestTest.GetName())
+	util.OK(t, err)
+	util.Equals(t, 1, len(records))
+	archived := registrationv1alpha.TenantRequest{}
+	util.OK(t, json.Unmarshal(records[0].Object, &archived))
+	util.Equals(t, tenantRequestTest.Spec.Contact.Email, archived.Spec.Contact.Email)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantrequest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// PricingName is the config map in kube-system holding the pricing table the requested allocations
+// are estimated against
+const PricingName = "edgenet-pricing"
+
+// pricingKey is the key of the config map data holding the table, in YAML or JSON
+const pricingKey = "pricing"
+
+// pricingTable prices the resource types per unit and per month, for instance:
+//
+//	currency: EUR
+//	resources:
+//	  cpu:
+//	    unit: "1"
+//	    price: 12
+//	  memory:
+//	    unit: 1Gi
+//	    price: 3.5
+//
+// The prices may as well be weights, the estimate is then a number of credits.
+type pricingTable struct {
+	Currency  string                        `json:"currency,omitempty"`
+	Resources map[corev1.ResourceName]price `json:"resources"`
+}
+
+type price struct {
+	// Unit the price is for, one of the resource type if unset.
+	Unit *resource.Quantity `json:"unit,omitempty"`
+	// Price of the unit for a month.
+	Price float64 `json:"price"`
+}
+
+// pricing reads the pricing table, there is none if the config map does not exist
+func (c *Controller) pricing(ctx context.Context) (*pricingTable, error) {
+	pricing, err := c.kubeclientset.CoreV1().ConfigMaps("kube-system").Get(ctx, PricingName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	table := &pricingTable{}
+	if raw := pricing.Data[pricingKey]; raw != "" {
+		if err := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(raw), len(raw)).Decode(table); err != nil {
+			return nil, fmt.Errorf("invalid pricing table: %s", err)
+		}
+	}
+	for name, price := range table.Resources {
+		if price.Unit != nil && price.Unit.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid pricing table: the unit of %s is not positive", name)
+		}
+	}
+	return table, nil
+}
+
+// estimate prices the requested allocation and weighs it against the allocatable capacity of the
+// schedulable nodes
+func (c *Controller) estimate(ctx context.Context, tenantRequest *registrationv1alpha.TenantRequest) (*registrationv1alpha.CostEstimate, error) {
+	table, err := c.pricing(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := c.nodesLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	capacity := corev1.ResourceList{}
+	for _, node := range nodes {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		for name, quantity := range node.Status.Allocatable {
+			total := capacity[name]
+			total.Add(quantity)
+			capacity[name] = total
+		}
+	}
+
+	estimate := &registrationv1alpha.CostEstimate{}
+	monthly := 0.0
+	for name, quantity := range tenantRequest.Spec.ResourceAllocation {
+		if table != nil {
+			if price, ok := table.Resources[name]; ok {
+				unit := resource.MustParse("1")
+				if price.Unit != nil {
+					unit = *price.Unit
+				}
+				cost := float64(quantity.MilliValue()) / float64(unit.MilliValue()) * price.Price
+				if estimate.Breakdown == nil {
+					estimate.Breakdown = map[corev1.ResourceName]string{}
+				}
+				estimate.Breakdown[name] = fmt.Sprintf("%.2f", cost)
+				monthly += cost
+			}
+		}
+		if total, ok := capacity[name]; ok && total.MilliValue() > 0 {
+			share := int(math.Ceil(float64(quantity.MilliValue()) / float64(total.MilliValue()) * 100))
+			if share > estimate.Share || (share == estimate.Share && name < estimate.DominantResource) {
+				estimate.Share = share
+				estimate.DominantResource = name
+			}
+		}
+	}
+	if table != nil {
+		estimate.Monthly = fmt.Sprintf("%.2f", monthly)
+		estimate.Currency = table.Currency
+	}
+	return estimate, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantrequest
+
+import (
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// expectationTimeout is how long the controller waits for the cache to catch up with its own
+// writes before it trusts the cache again
+const expectationTimeout = 5 * time.Minute
+
+// expectation is the version of a request written by the controller
+type expectation struct {
+	resourceVersion string
+	timestamp       time.Time
+}
+
+// expectations track the writes of the controller that the informer cache has not observed yet.
+// A request with a pending expectation is read from the API server, so that the controller never
+// acts on the state it has already moved the request out of, such as creating the tenant twice.
+type expectations struct {
+	mutex   sync.Mutex
+	pending map[string]expectation
+	now     func() time.Time
+}
+
+func newExpectations() *expectations {
+	return &expectations{pending: make(map[string]expectation), now: time.Now}
+}
+
+// expect records the version of the request that the controller has written
+func (e *expectations) expect(object metav1.Object) {
+	if object.GetResourceVersion() == "" {
+		return
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.pending[object.GetName()] = expectation{resourceVersion: object.GetResourceVersion(), timestamp: e.now()}
+}
+
+// observe clears the expectation once the cache holds the version written. Resource versions are
+// opaque to the clients, they are only compared for equality.
+func (e *expectations) observe(object metav1.Object) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	expected, ok := e.pending[object.GetName()]
+	if ok && object.GetResourceVersion() == expected.resourceVersion {
+		delete(e.pending, object.GetName())
+	}
+}
+
+// forget drops the expectation of a request that no longer exists
+func (e *expectations) forget(name string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	delete(e.pending, name)
+}
+
+// satisfied tells whether the cached version of the request can be trusted
+func (e *expectations) satisfied(object metav1.Object) bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	expected, ok := e.pending[object.GetName()]
+	if !ok {
+		return true
+	}
+	if object.GetResourceVersion() == expected.resourceVersion || e.now().Sub(expected.timestamp) > expectationTimeout {
+		delete(e.pending, object.GetName())
+		return true
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantrequest
+
+import (
+	"context"
+
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/approval"
+
+	"k8s.io/klog/v2"
+)
+
+// forward posts the request awaiting an approval to the external approval system once, the request
+// is queued again with a backoff while the webhook fails
+func (c *Controller) forward(ctx context.Context, tenantRequestCopy *registrationv1alpha.TenantRequest) {
+	if !approval.Enabled() || tenantRequestCopy.Status.Forwarded {
+		return
+	}
+	request := approval.Request{
+		Kind:      approval.KindTenantRequest,
+		Name:      tenantRequestCopy.GetName(),
+		UID:       tenantRequestCopy.GetUID(),
+		Email:     tenantRequestCopy.Spec.Contact.Email,
+		FirstName: tenantRequestCopy.Spec.Contact.FirstName,
+		LastName:  tenantRequestCopy.Spec.Contact.LastName,
+		Details: map[string]string{
+			"fullName":  tenantRequestCopy.Spec.FullName,
+			"shortName": tenantRequestCopy.Spec.ShortName,
+			"url":       tenantRequestCopy.Spec.URL,
+			"country":   tenantRequestCopy.Spec.Address.Country,
+		},
+	}
+	if tenantRequestCopy.Status.Expiry != nil {
+		request.Expiry = &tenantRequestCopy.Status.Expiry.Time
+	}
+	if err := approval.Forward(ctx, request); err != nil {
+		klog.ErrorS(err, "Couldn't forward the request to the external approval system", "tenantRequest", klog.KObj(tenantRequestCopy))
+		c.workqueue.AddRateLimited(tenantRequestCopy.GetName())
+		return
+	}
+	tenantRequestCopy.Status.Forwarded = true
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantrequest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// Limits of the pending tenant requests that share an email address or an email domain, zero lifts the limit
+var (
+	MaxPendingPerEmail  = 1
+	MaxPendingPerDomain = 20
+)
+
+// InvitationKey signs the invitation tokens, the requests go through without a token if it is empty
+var InvitationKey []byte
+
+// The rejected requests are removed after rejectionPeriod instead of lingering for the whole approval timeout
+var rejectionPeriod = time.Hour
+
+// admit lets a new tenant request in unless the requester already has too many requests pending, or
+// the invitation token is missing or invalid when invitations are required. It returns the reason of
+// the refusal, and an error if the pending requests cannot be counted.
+func (c *Controller) admit(tenantRequest *registrationv1alpha.TenantRequest) (string, error) {
+	email := strings.ToLower(tenantRequest.Spec.Contact.Email)
+	if len(InvitationKey) > 0 {
+		if err := VerifyInvitationToken(InvitationKey, email, tenantRequest.Spec.Invitation, time.Now()); err != nil {
+			return err.Error(), nil
+		}
+	}
+	if MaxPendingPerEmail <= 0 && MaxPendingPerDomain <= 0 {
+		return "", nil
+	}
+	domain := email[strings.LastIndex(email, "@")+1:]
+	tenantRequests, err := c.tenantrequestsLister.List(labels.Everything())
+	if err != nil {
+		return "", err
+	}
+	sameEmail, sameDomain := 0, 0
+	for _, tenantRequestRow := range tenantRequests {
+		// Only the earlier requests count, so that the first ones of a burst get through
+		if !earlier(tenantRequestRow, tenantRequest) || (tenantRequestRow.Status.State != "" && tenantRequestRow.Status.State != pending) {
+			continue
+		}
+		otherEmail := strings.ToLower(tenantRequestRow.Spec.Contact.Email)
+		if otherEmail == email {
+			sameEmail++
+		}
+		if otherEmail[strings.LastIndex(otherEmail, "@")+1:] == domain {
+			sameDomain++
+		}
+	}
+	if MaxPendingPerEmail > 0 && sameEmail >= MaxPendingPerEmail {
+		return fmt.Sprintf("%d tenant requests of %s already pending", sameEmail, email), nil
+	}
+	if MaxPendingPerDomain > 0 && sameDomain >= MaxPendingPerDomain {
+		return fmt.Sprintf("%d tenant requests from %s already pending", sameDomain, domain), nil
+	}
+	return "", nil
+}
+
+// earlier tells whether a request came before the other, the name breaks the ties
+func earlier(tenantRequest, other *registrationv1alpha.TenantRequest) bool {
+	if tenantRequest.GetName() == other.GetName() {
+		return false
+	}
+	created, otherCreated := tenantRequest.GetCreationTimestamp(), other.GetCreationTimestamp()
+	if !created.Equal(&otherCreated) {
+		return created.Before(&otherCreated)
+	}
+	return tenantRequest.GetName() < other.GetName()
+}
+
+// NewInvitationToken returns a token inviting the owner of the email address to request a tenant until it expires
+func NewInvitationToken(key []byte, email string, expiry time.Time) string {
+	expires := strconv.FormatInt(expiry.Unix(), 10)
+	return fmt.Sprintf("%s.%s", expires, sign(key, strings.ToLower(email), expires))
+}
+
+// VerifyInvitationToken makes sure the token was issued for the email address and has not expired
+func VerifyInvitationToken(key []byte, email, token string, now time.Time) error {
+	if token == "" {
+		return fmt.Errorf("invitation token required")
+	}
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("malformed invitation token")
+	}
+	expires, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("malformed invitation token")
+	}
+	if !hmac.Equal([]byte(parts[1]), []byte(sign(key, strings.ToLower(email), parts[0]))) {
+		return fmt.Errorf("invalid invitation token")
+	}
+	if now.After(time.Unix(expires, 0)) {
+		return fmt.Errorf("invitation token expired")
+	}
+	return nil
+}
+
+func sign(key []byte, email, expires string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(fmt.Sprintf("%s\n%s", email, expires)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
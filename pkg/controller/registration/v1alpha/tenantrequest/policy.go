@@ -0,0 +1,113 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tenantrequest
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// ApprovalPolicyName is the config map in kube-system holding the rules that approve the tenant
+// requests on behalf of the administrators
+const ApprovalPolicyName = "edgenet-approval-policy"
+
+// approvalPolicyKey is the key of the config map data holding the rules, in YAML or JSON
+const approvalPolicyKey = "rules"
+
+// approvalRule approves the requests of the contacts whose email address matches, as long as the
+// requested allocation stays within the bounds of the rule
+type approvalRule struct {
+	// Name of the rule, it stands for the approver in the audit trail.
+	Name string `json:"name"`
+	// Patterns of the email addresses, such as '*.sorbonne-universite.fr'. A pattern without '@'
+	// matches the domain of the address, one with '@' the whole address.
+	Emails []string `json:"emails"`
+	// Upper bounds of the requested allocation. The request matches only if each resource type it
+	// requests is bounded here, any allocation matches if empty.
+	MaxAllocation corev1.ResourceList `json:"maxAllocation,omitempty"`
+}
+
+// approvalRules reads the rules of the approval policy, there are none if the config map does not exist
+func (c *Controller) approvalRules(ctx context.Context) ([]approvalRule, error) {
+	policy, err := c.kubeclientset.CoreV1().ConfigMaps("kube-system").Get(ctx, ApprovalPolicyName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	rules := []approvalRule{}
+	if raw := policy.Data[approvalPolicyKey]; raw != "" {
+		if err := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(raw), len(raw)).Decode(&rules); err != nil {
+			return nil, fmt.Errorf("invalid approval policy: %s", err)
+		}
+	}
+	return rules, nil
+}
+
+// preapproval returns the name of the first rule of the approval policy that the request matches,
+// empty if none does
+func (c *Controller) preapproval(ctx context.Context, tenantRequest *registrationv1alpha.TenantRequest) (string, error) {
+	rules, err := c.approvalRules(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, rule := range rules {
+		if rule.matches(tenantRequest) {
+			return rule.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// matches tells whether the rule approves the request
+func (r approvalRule) matches(tenantRequest *registrationv1alpha.TenantRequest) bool {
+	email := strings.ToLower(tenantRequest.Spec.Contact.Email)
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	matched := false
+	for _, pattern := range r.Emails {
+		subject := email[at+1:]
+		if strings.Contains(pattern, "@") {
+			subject = email
+		}
+		if ok, err := path.Match(strings.ToLower(pattern), subject); err == nil && ok {
+			matched = true
+			break
+		}
+	}
+	if !matched || len(r.MaxAllocation) == 0 {
+		return matched
+	}
+	for name, quantity := range tenantRequest.Spec.ResourceAllocation {
+		bound, ok := r.MaxAllocation[name]
+		if !ok || quantity.Cmp(bound) > 0 {
+			return false
+		}
+	}
+	return true
+}
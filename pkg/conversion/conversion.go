@@ -0,0 +1,205 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conversion implements the conversion webhook of the custom resources served in several
+// versions. The API server sends the objects to convert in a ConversionReview and expects them
+// back in the desired version.
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	corev1beta1 "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1beta1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+// ConversionReview mirrors the apiextensions.k8s.io/v1 ConversionReview
+type ConversionReview struct {
+	metav1.TypeMeta `json:",inline"`
+	Request         *ConversionRequest  `json:"request,omitempty"`
+	Response        *ConversionResponse `json:"response,omitempty"`
+}
+
+// ConversionRequest holds the objects to convert
+type ConversionRequest struct {
+	UID               types.UID              `json:"uid"`
+	DesiredAPIVersion string                 `json:"desiredAPIVersion"`
+	Objects           []runtime.RawExtension `json:"objects"`
+}
+
+// ConversionResponse holds the objects converted, in the order of the request
+type ConversionResponse struct {
+	UID              types.UID              `json:"uid"`
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	Result           metav1.Status          `json:"result"`
+}
+
+// convertible is a version that converts to and from the hub
+type convertible struct {
+	new  func() runtime.Object
+	to   func(object runtime.Object) runtime.Object
+	from func(hub runtime.Object) runtime.Object
+}
+
+// kinds maps each kind to its hub, the version the other versions convert to and from, and to the
+// other versions
+var kinds = map[string]struct {
+	hub      func() runtime.Object
+	versions map[string]convertible
+}{
+	"Tenant": {
+		hub: func() runtime.Object { return new(corev1alpha.Tenant) },
+		versions: map[string]convertible{
+			corev1beta1.SchemeGroupVersion.String(): {
+				new: func() runtime.Object { return new(corev1beta1.Tenant) },
+				to: func(object runtime.Object) runtime.Object {
+					alpha := new(corev1alpha.Tenant)
+					object.(*corev1beta1.Tenant).ConvertTo(alpha)
+					return alpha
+				},
+				from: func(hub runtime.Object) runtime.Object {
+					beta := new(corev1beta1.Tenant)
+					beta.ConvertFrom(hub.(*corev1alpha.Tenant))
+					return beta
+				},
+			},
+		},
+	},
+	"SubNamespace": {
+		hub: func() runtime.Object { return new(corev1alpha.SubNamespace) },
+		versions: map[string]convertible{
+			corev1beta1.SchemeGroupVersion.String(): {
+				new: func() runtime.Object { return new(corev1beta1.SubNamespace) },
+				to: func(object runtime.Object) runtime.Object {
+					alpha := new(corev1alpha.SubNamespace)
+					object.(*corev1beta1.SubNamespace).ConvertTo(alpha)
+					return alpha
+				},
+				from: func(hub runtime.Object) runtime.Object {
+					beta := new(corev1beta1.SubNamespace)
+					beta.ConvertFrom(hub.(*corev1alpha.SubNamespace))
+					return beta
+				},
+			},
+		},
+	},
+	"TenantResourceQuota": {
+		hub: func() runtime.Object { return new(corev1alpha.TenantResourceQuota) },
+		versions: map[string]convertible{
+			corev1beta1.SchemeGroupVersion.String(): {
+				new: func() runtime.Object { return new(corev1beta1.TenantResourceQuota) },
+				to: func(object runtime.Object) runtime.Object {
+					alpha := new(corev1alpha.TenantResourceQuota)
+					object.(*corev1beta1.TenantResourceQuota).ConvertTo(alpha)
+					return alpha
+				},
+				from: func(hub runtime.Object) runtime.Object {
+					beta := new(corev1beta1.TenantResourceQuota)
+					beta.ConvertFrom(hub.(*corev1alpha.TenantResourceQuota))
+					return beta
+				},
+			},
+		},
+	},
+}
+
+// hubVersion is the version of the hubs, the storage version of the kinds
+var hubVersion = corev1alpha.SchemeGroupVersion.String()
+
+// Convert converts the object in JSON to the desired version, going through the hub
+func Convert(raw []byte, desiredAPIVersion string) ([]byte, error) {
+	typeMeta := metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, &typeMeta); err != nil {
+		return nil, err
+	}
+	if typeMeta.APIVersion == desiredAPIVersion {
+		return raw, nil
+	}
+	kind, ok := kinds[typeMeta.Kind]
+	if !ok {
+		return nil, fmt.Errorf("kind %s is not convertible", typeMeta.Kind)
+	}
+
+	var hubObject runtime.Object
+	if typeMeta.APIVersion == hubVersion {
+		hubObject = kind.hub()
+		if err := json.Unmarshal(raw, hubObject); err != nil {
+			return nil, err
+		}
+	} else {
+		version, ok := kind.versions[typeMeta.APIVersion]
+		if !ok {
+			return nil, fmt.Errorf("version %s of %s is not convertible", typeMeta.APIVersion, typeMeta.Kind)
+		}
+		object := version.new()
+		if err := json.Unmarshal(raw, object); err != nil {
+			return nil, err
+		}
+		hubObject = version.to(object)
+	}
+
+	converted := hubObject
+	if desiredAPIVersion != hubVersion {
+		version, ok := kind.versions[desiredAPIVersion]
+		if !ok {
+			return nil, fmt.Errorf("version %s of %s is not convertible", desiredAPIVersion, typeMeta.Kind)
+		}
+		converted = version.from(hubObject)
+	}
+	gv, err := schema.ParseGroupVersion(desiredAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	converted.GetObjectKind().SetGroupVersionKind(gv.WithKind(typeMeta.Kind))
+	return json.Marshal(converted)
+}
+
+// Handler serves the conversion reviews of the API server
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		review := ConversionReview{}
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil || review.Request == nil {
+			http.Error(w, "invalid conversion review", http.StatusBadRequest)
+			return
+		}
+		response := &ConversionResponse{UID: review.Request.UID, Result: metav1.Status{Status: metav1.StatusSuccess}}
+		for _, object := range review.Request.Objects {
+			converted, err := Convert(object.Raw, review.Request.DesiredAPIVersion)
+			if err != nil {
+				klog.ErrorS(err, "Couldn't convert the object", "desiredAPIVersion", review.Request.DesiredAPIVersion)
+				// The API server rejects the whole review on a failure, the objects converted are dropped
+				response = &ConversionResponse{UID: review.Request.UID,
+					Result: metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}}
+				break
+			}
+			response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: converted})
+		}
+		review.Request = nil
+		review.Response = response
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(review); err != nil {
+			klog.ErrorS(err, "Couldn't write the conversion review")
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package conversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	corev1beta1 "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1beta1"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func alphaTenant() *corev1alpha.Tenant {
+	preemptionPolicy := corev1.PreemptNever
+	return &corev1alpha.Tenant{
+		TypeMeta:   metav1.TypeMeta{APIVersion: corev1alpha.SchemeGroupVersion.String(), Kind: "Tenant"},
+		ObjectMeta: metav1.ObjectMeta{Name: "lip6", Labels: map[string]string{"edge-net.io/tier": "paying"}},
+		Spec: corev1alpha.TenantSpec{
+			FullName:  "LIP6",
+			ShortName: "LIP6",
+			URL:       "https://www.lip6.fr",
+			Address:   corev1alpha.Address{City: "Paris", Country: "France"},
+			Contact:   corev1alpha.Contact{Handle: "johndoe", FirstName: "John", LastName: "Doe", Email: "john.doe@edge-net.org"},
+			Owners:    []corev1alpha.Contact{{Handle: "janedoe", FirstName: "Jane", LastName: "Doe", Email: "jane.doe@edge-net.org"}},
+			Admins:    []corev1alpha.Contact{{Handle: "joesmith", FirstName: "Joe", LastName: "Smith", Email: "joe.smith@edge-net.org"}},
+			ContainerLimits: &corev1alpha.ContainerLimits{
+				Default: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+			},
+			Priority:              &corev1alpha.Priority{Tier: "paying", Weight: 10, PreemptionPolicy: &preemptionPolicy},
+			GroupBindings:         []corev1alpha.GroupBinding{{Group: "lip6-admins", Role: "admin"}},
+			NodePools:             []string{"edge"},
+			PodSecurityExemptions: []string{"sysctls"},
+			Enabled:               true,
+		},
+		Status: corev1alpha.TenantStatus{
+			State:           "Established",
+			NetworkPolicies: []corev1alpha.NamespacePolicySync{{Namespace: "lip6", State: "Synced"}},
+			Operation:       &corev1alpha.OperationReference{Name: "teardown-lip6", Type: "teardown", State: "Running", Progress: 40},
+		},
+	}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	expiry := metav1.NewTime(metav1.Now().Rfc3339Copy().Time)
+	softLimit := 80
+	cases := map[string]runtime.Object{
+		"tenant": alphaTenant(),
+		"subnamespace": &corev1alpha.SubNamespace{
+			TypeMeta:   metav1.TypeMeta{APIVersion: corev1alpha.SchemeGroupVersion.String(), Kind: "SubNamespace"},
+			ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "lip6"},
+			Spec: corev1alpha.SubNamespaceSpec{
+				Workspace: &corev1alpha.Workspace{
+					ResourceAllocation: map[corev1.ResourceName]resource.Quantity{corev1.ResourceMemory: resource.MustParse("2Gi")},
+					Inheritance:        map[string]bool{"rbac": true},
+					Scope:              "local",
+					Owner:              &corev1alpha.Contact{Email: "john.doe@edge-net.org"},
+					Clone:              &corev1alpha.Clone{Source: "base"},
+					Lending: &corev1alpha.QuotaLending{Lend: map[corev1.ResourceName]resource.Quantity{corev1.ResourceMemory: resource.MustParse("1Gi")},
+						ReclaimPolicy: "Immediate"},
+				},
+				Expiry: &expiry,
+			},
+			Status: corev1alpha.SubNamespaceStatus{State: "Established", Cloned: true,
+				Lent:  []corev1alpha.QuotaLoan{{Sibling: "course", Resources: map[corev1.ResourceName]resource.Quantity{corev1.ResourceMemory: resource.MustParse("512Mi")}, Since: expiry}},
+				Child: "team-1a2b3c", Parent: &corev1alpha.SubNamespaceReference{Namespace: "lip6", Name: "lab"},
+				Children: []corev1alpha.SubNamespaceReference{{Namespace: "team-1a2b3c", Name: "course"}}, Depth: 2},
+		},
+		"tenantresourcequota": &corev1alpha.TenantResourceQuota{
+			TypeMeta:   metav1.TypeMeta{APIVersion: corev1alpha.SchemeGroupVersion.String(), Kind: "TenantResourceQuota"},
+			ObjectMeta: metav1.ObjectMeta{Name: "lip6"},
+			Spec: corev1alpha.TenantResourceQuotaSpec{
+				Claim:     map[string]corev1alpha.ResourceTuning{"initial": {ResourceList: map[corev1.ResourceName]resource.Quantity{corev1.ResourceCPU: resource.MustParse("8")}}},
+				Drop:      map[string]corev1alpha.ResourceTuning{"penalty": {ResourceList: map[corev1.ResourceName]resource.Quantity{corev1.ResourceCPU: resource.MustParse("1")}, Expiry: &expiry}},
+				SoftLimit: &softLimit,
+				HardBlock: true,
+			},
+			Status: corev1alpha.TenantResourceQuotaStatus{State: "Applied", Used: map[corev1.ResourceName]resource.Quantity{corev1.ResourceCPU: resource.MustParse("7")},
+				Conditions: []metav1.Condition{{Type: "SoftLimitExceeded", Status: metav1.ConditionTrue, Reason: "Exceeded", LastTransitionTime: expiry}}},
+		},
+	}
+	for name, object := range cases {
+		t.Run(name, func(t *testing.T) {
+			raw, err := json.Marshal(object)
+			util.OK(t, err)
+			beta, err := Convert(raw, corev1beta1.SchemeGroupVersion.String())
+			util.OK(t, err)
+			alpha, err := Convert(beta, corev1alpha.SchemeGroupVersion.String())
+			util.OK(t, err)
+
+			var expected, actual map[string]interface{}
+			util.OK(t, json.Unmarshal(raw, &expected))
+			util.OK(t, json.Unmarshal(alpha, &actual))
+			util.Equals(t, expected, actual)
+		})
+	}
+}
+
+func TestConvertFieldNames(t *testing.T) {
+	raw, err := json.Marshal(alphaTenant())
+	util.OK(t, err)
+	converted, err := Convert(raw, corev1beta1.SchemeGroupVersion.String())
+	util.OK(t, err)
+	beta := new(corev1beta1.Tenant)
+	util.OK(t, json.Unmarshal(converted, beta))
+	util.Equals(t, "core.edgenet.io/v1beta1", beta.APIVersion)
+	util.Equals(t, "LIP6", beta.Spec.FullName)
+	util.Equals(t, "Doe", beta.Spec.Contact.LastName)
+	util.Equals(t, []string{"sysctls"}, beta.Spec.PodSecurityExemptions)
+
+	var fields struct {
+		Spec map[string]interface{} `json:"spec"`
+	}
+	util.OK(t, json.Unmarshal(converted, &fields))
+	_, ok := fields.Spec["fullName"]
+	util.Assert(t, ok, "fullName is missing in %s", converted)
+
+	_, err = Convert([]byte(`{"apiVersion":"core.edgenet.io/v1alpha","kind":"Operation"}`), corev1beta1.SchemeGroupVersion.String())
+	util.Assert(t, err != nil, "a kind without conversion is converted")
+}
+
+func TestHandler(t *testing.T) {
+	raw, err := json.Marshal(alphaTenant())
+	util.OK(t, err)
+	review := ConversionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+		Request: &ConversionRequest{UID: "review-uid", DesiredAPIVersion: corev1beta1.SchemeGroupVersion.String(),
+			Objects: []runtime.RawExtension{{Raw: raw}}},
+	}
+	body, err := json.Marshal(review)
+	util.OK(t, err)
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/convert", bytes.NewReader(body)))
+	util.Equals(t, http.StatusOK, recorder.Code)
+	response := ConversionReview{}
+	util.OK(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	util.Equals(t, "ConversionReview", response.Kind)
+	util.Equals(t, metav1.StatusSuccess, response.Response.Result.Status)
+	util.Equals(t, review.Request.UID, response.Response.UID)
+	util.Equals(t, 1, len(response.Response.ConvertedObjects))
+
+	review.Request.DesiredAPIVersion = "core.edgenet.io/v2"
+	body, err = json.Marshal(review)
+	util.OK(t, err)
+	recorder = httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/convert", bytes.NewReader(body)))
+	response = ConversionReview{}
+	util.OK(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	util.Equals(t, metav1.StatusFailure, response.Response.Result.Status)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+var crdGVR = schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"}
+
+// MigrateStorage rewrites every object of the custom resource so that the API server stores it in
+// the storage version of the definition, then records the storage version as the only version
+// stored. An older version can be dropped from the definition once it is no longer stored.
+func MigrateStorage(ctx context.Context, dynamicclient dynamic.Interface, crdName string) (int, error) {
+	crd, err := dynamicclient.Resource(crdGVR).Get(ctx, crdName, metav1.GetOptions{})
+	if err != nil {
+		return 0, err
+	}
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+	versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	storageVersion := ""
+	for _, version := range versions {
+		if version, ok := version.(map[string]interface{}); ok && version["storage"] == true {
+			storageVersion, _ = version["name"].(string)
+		}
+	}
+	if storageVersion == "" {
+		return 0, fmt.Errorf("%s has no storage version", crdName)
+	}
+
+	resource := dynamicclient.Resource(schema.GroupVersionResource{Group: group, Version: storageVersion, Resource: plural})
+	list, err := resource.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+	migrated := 0
+	for _, item := range list.Items {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			object, err := resource.Namespace(item.GetNamespace()).Get(ctx, item.GetName(), metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			// An update that changes nothing still writes the object in the storage version
+			_, err = resource.Namespace(item.GetNamespace()).Update(ctx, object, metav1.UpdateOptions{})
+			return err
+		})
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return migrated, fmt.Errorf("cannot migrate %s %s/%s: %v", plural, item.GetNamespace(), item.GetName(), err)
+		}
+		klog.V(4).InfoS("Object migrated", "resource", plural, "namespace", item.GetNamespace(), "name", item.GetName(), "version", storageVersion)
+		migrated++
+	}
+
+	patch := []byte(fmt.Sprintf(`{"status":{"storedVersions":[%q]}}`, storageVersion))
+	if _, err := dynamicclient.Resource(crdGVR).Patch(ctx, crdName, types.MergePatchType, patch, metav1.PatchOptions{}, "status"); err != nil {
+		return migrated, err
+	}
+	return migrated, nil
+}
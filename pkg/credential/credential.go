@@ -0,0 +1,195 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package credential implements the exec credential plugin that kubectl runs to obtain the
+// short-lived token of an EdgeNet user. The plugin exchanges the refresh token of the user at
+// the token endpoint of the registration server:
+//
+//	POST <server>/token {"user": "...", "refreshToken": "..."}
+//	200 {"token": "...", "expirationTimestamp": "<RFC 3339>", "refreshToken": "<optional, rotated>"}
+//
+// Tokens are cached until shortly before they expire, so that kubectl does not reach the
+// registration server on every call.
+package credential
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientauthv1beta1 "k8s.io/client-go/pkg/apis/clientauthentication/v1beta1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// PluginCommand is the name of the binary that the generated kubeconfigs invoke
+const PluginCommand = "edgenet-credential"
+
+// The generated credentials are kept in secrets carrying the label, they are revoked and removed
+// once their tenant, the role binding granting the user its rights, or their expiry is gone
+const (
+	LabelCredential       = "edge-net.io/credential"
+	AnnotationUser        = "edge-net.io/credential-user"
+	AnnotationRoleBinding = "edge-net.io/credential-role-binding"
+	AnnotationExpiry      = "edge-net.io/credential-expiry"
+)
+
+// A cached token is renewed when it expires in less than this margin
+const refreshMargin = time.Minute
+
+// Token is the reply of the token endpoint of the registration server
+type Token struct {
+	Token               string    `json:"token"`
+	ExpirationTimestamp time.Time `json:"expirationTimestamp"`
+	RefreshToken        string    `json:"refreshToken,omitempty"`
+}
+
+// tokenRequest is the body posted to the token endpoint
+type tokenRequest struct {
+	User         string `json:"user"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+// Plugin fetches and refreshes the tokens of a user
+type Plugin struct {
+	// Server is the URL of the registration server.
+	Server string
+	// User is the name of the user in EdgeNet.
+	User string
+	// RefreshTokenPath is the file holding the refresh token of the user, it is overwritten
+	// when the registration server rotates the refresh token.
+	RefreshTokenPath string
+	// CacheDir keeps the tokens until they expire.
+	CacheDir string
+	// HTTPClient reaches the registration server, http.DefaultClient if nil.
+	HTTPClient *http.Client
+	// Now returns the current time, time.Now if nil.
+	Now func() time.Time
+}
+
+// Credential returns the exec credential of the user, out of the cache if the token
+// there is still valid
+func (p *Plugin) Credential() (*clientauthv1beta1.ExecCredential, error) {
+	now := time.Now
+	if p.Now != nil {
+		now = p.Now
+	}
+	token, err := p.loadCache()
+	if err != nil || token.ExpirationTimestamp.Before(now().Add(refreshMargin)) {
+		if token, err = p.Fetch(); err != nil {
+			return nil, err
+		}
+		if err := p.storeCache(token); err != nil {
+			return nil, err
+		}
+	}
+	expirationTimestamp := metav1.NewTime(token.ExpirationTimestamp)
+	credential := &clientauthv1beta1.ExecCredential{
+		TypeMeta: metav1.TypeMeta{APIVersion: clientauthv1beta1.SchemeGroupVersion.String(), Kind: "ExecCredential"},
+		Status:   &clientauthv1beta1.ExecCredentialStatus{Token: token.Token, ExpirationTimestamp: &expirationTimestamp},
+	}
+	return credential, nil
+}
+
+// Fetch exchanges the refresh token of the user for a new token at the registration server
+func (p *Plugin) Fetch() (Token, error) {
+	token := Token{}
+	refreshToken, err := ioutil.ReadFile(p.RefreshTokenPath)
+	if err != nil {
+		return token, err
+	}
+	body, err := json.Marshal(tokenRequest{User: p.User, RefreshToken: strings.TrimSpace(string(refreshToken))})
+	if err != nil {
+		return token, err
+	}
+	httpClient := http.DefaultClient
+	if p.HTTPClient != nil {
+		httpClient = p.HTTPClient
+	}
+	resp, err := httpClient.Post(fmt.Sprintf("%s/token", strings.TrimSuffix(p.Server, "/")), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return token, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return token, fmt.Errorf("registration server replied %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return token, err
+	}
+	if token.Token == "" {
+		return token, fmt.Errorf("registration server replied no token")
+	}
+	if token.RefreshToken != "" {
+		if err := ioutil.WriteFile(p.RefreshTokenPath, []byte(token.RefreshToken), 0600); err != nil {
+			return token, err
+		}
+	}
+	return token, nil
+}
+
+// cachePath returns the cache file of the user, one per registration server
+func (p *Plugin) cachePath() string {
+	return filepath.Join(p.CacheDir, fmt.Sprintf("%x.json", sha256.Sum256([]byte(fmt.Sprintf("%s\n%s", p.Server, p.User)))))
+}
+
+func (p *Plugin) loadCache() (Token, error) {
+	token := Token{}
+	cache, err := ioutil.ReadFile(p.cachePath())
+	if err != nil {
+		return token, err
+	}
+	err = json.Unmarshal(cache, &token)
+	return token, err
+}
+
+func (p *Plugin) storeCache(token Token) error {
+	if err := os.MkdirAll(p.CacheDir, 0700); err != nil {
+		return err
+	}
+	// The refresh token lives in its own file only
+	token.RefreshToken = ""
+	cache, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(p.cachePath(), cache, 0600)
+}
+
+// GenerateKubeconfig returns the kubeconfig of a user in which the credentials are obtained
+// through the plugin rather than embedded as a static certificate or token
+func GenerateKubeconfig(cluster, server string, ca []byte, user, registrationServer string) ([]byte, error) {
+	config := clientcmdapi.NewConfig()
+	config.Clusters[cluster] = &clientcmdapi.Cluster{Server: server, CertificateAuthorityData: ca}
+	config.AuthInfos[user] = &clientcmdapi.AuthInfo{Exec: &clientcmdapi.ExecConfig{
+		APIVersion:  clientauthv1beta1.SchemeGroupVersion.String(),
+		Command:     PluginCommand,
+		Args:        []string{"--server", registrationServer, "--user", user},
+		InstallHint: fmt.Sprintf("%s is required to authenticate to EdgeNet, install it with\n\tgo install github.com/EdgeNet-project/edgenet/cmd/%s", PluginCommand, PluginCommand),
+	}}
+	contextName := fmt.Sprintf("%s@%s", user, cluster)
+	config.Contexts[contextName] = &clientcmdapi.Context{Cluster: cluster, AuthInfo: user}
+	config.CurrentContext = contextName
+	return clientcmd.Write(*config)
+}
@@ -0,0 +1,84 @@
+package credential
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestCredential(t *testing.T) {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	requests := 0
+	var lastRequest tokenRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewDecoder(r.Body).Decode(&lastRequest)
+		if r.URL.Path != "/token" || lastRequest.RefreshToken == "revoked" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(Token{Token: "short-lived", ExpirationTimestamp: now.Add(10 * time.Minute), RefreshToken: "rotated"})
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "credential")
+	util.OK(t, err)
+	defer os.RemoveAll(dir)
+	refreshTokenPath := filepath.Join(dir, "refresh-token")
+	ioutil.WriteFile(refreshTokenPath, []byte("initial\n"), 0600)
+	plugin := Plugin{Server: server.URL, User: "johndoe", RefreshTokenPath: refreshTokenPath, CacheDir: filepath.Join(dir, "cache"),
+		Now: func() time.Time { return now }}
+
+	execCredential, err := plugin.Credential()
+	util.OK(t, err)
+	util.Equals(t, "ExecCredential", execCredential.Kind)
+	util.Equals(t, "short-lived", execCredential.Status.Token)
+	util.Equals(t, 1, requests)
+	util.Equals(t, tokenRequest{User: "johndoe", RefreshToken: "initial"}, lastRequest)
+
+	t.Run("rotation", func(t *testing.T) {
+		refreshToken, err := ioutil.ReadFile(refreshTokenPath)
+		util.OK(t, err)
+		util.Equals(t, "rotated", string(refreshToken))
+	})
+	t.Run("cache", func(t *testing.T) {
+		_, err := plugin.Credential()
+		util.OK(t, err)
+		util.Equals(t, 1, requests)
+	})
+	t.Run("expiry", func(t *testing.T) {
+		plugin.Now = func() time.Time { return now.Add(9*time.Minute + 30*time.Second) }
+		_, err := plugin.Credential()
+		util.OK(t, err)
+		util.Equals(t, 2, requests)
+		util.Equals(t, "rotated", lastRequest.RefreshToken)
+	})
+	t.Run("revoked", func(t *testing.T) {
+		plugin.Now = func() time.Time { return now.Add(time.Hour) }
+		ioutil.WriteFile(refreshTokenPath, []byte("revoked"), 0600)
+		_, err := plugin.Credential()
+		util.Assert(t, err != nil, "revoked refresh token is accepted")
+	})
+}
+
+func TestGenerateKubeconfig(t *testing.T) {
+	kubeconfig, err := GenerateKubeconfig("edgenet", "https://api.edge-net.org:6443", []byte("ca"), "johndoe", "https://registration.edge-net.org")
+	util.OK(t, err)
+	config, err := clientcmd.Load(kubeconfig)
+	util.OK(t, err)
+	util.Equals(t, "johndoe@edgenet", config.CurrentContext)
+	authInfo := config.AuthInfos["johndoe"]
+	util.Equals(t, "", authInfo.Token)
+	util.Equals(t, []byte(nil), authInfo.ClientCertificateData)
+	util.Equals(t, PluginCommand, authInfo.Exec.Command)
+	util.Equals(t, []string{"--server", "https://registration.edge-net.org", "--user", "johndoe"}, authInfo.Exec.Args)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dns manages the records of the zone of the cluster through the API of its DNS provider.
+// A provider only has to list and change the records, the ownership of the records is kept in the
+// zone itself so that any provider can be plugged in.
+package dns
+
+import (
+	"context"
+	"strings"
+)
+
+// Record is a record of the zone, the name is fully qualified without the trailing dot
+type Record struct {
+	Name   string
+	Type   string
+	Target string
+}
+
+// Changes are the records to create or update, and the records to delete
+type Changes struct {
+	Upsert []Record
+	Delete []Record
+}
+
+// Empty returns true if there is nothing to change
+func (c Changes) Empty() bool {
+	return len(c.Upsert) == 0 && len(c.Delete) == 0
+}
+
+// Provider is the API of a DNS provider hosting the zone of the cluster
+type Provider interface {
+	// Records returns the records of the zone
+	Records(ctx context.Context) ([]Record, error)
+	// Apply changes the records of the zone, the records not among the changes are left untouched
+	Apply(ctx context.Context, changes Changes) error
+}
+
+// Normalize returns the name in lower case without the trailing dot
+func Normalize(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
+
+// InDomain returns true if the name is the domain or one of its subdomains
+func InDomain(name, domain string) bool {
+	name, domain = Normalize(name), Normalize(domain)
+	return name == domain || strings.HasSuffix(name, "."+domain)
+}
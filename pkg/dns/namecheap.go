@@ -0,0 +1,112 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	namecheap "github.com/billputer/go-namecheap"
+)
+
+// Namecheap hosts the zone at Namecheap, whose API only replaces the hosts of a domain as a whole
+type Namecheap struct {
+	client *namecheap.Client
+	zone   string
+	sld    string
+	tld    string
+}
+
+// NewNamecheap returns the provider of the zone, a second-level domain such as edge-net.io
+func NewNamecheap(client *namecheap.Client, zone string) (*Namecheap, error) {
+	zone = Normalize(zone)
+	labels := strings.SplitN(zone, ".", 2)
+	if len(labels) != 2 || labels[0] == "" || labels[1] == "" {
+		return nil, fmt.Errorf("zone %q is not a second-level domain", zone)
+	}
+	return &Namecheap{client: client, zone: zone, sld: labels[0], tld: labels[1]}, nil
+}
+
+// Records returns the hosts of the domain with their fully qualified names
+func (n *Namecheap) Records(ctx context.Context) ([]Record, error) {
+	hosts, err := n.hosts()
+	if err != nil {
+		return nil, err
+	}
+	records := []Record{}
+	for _, host := range hosts {
+		records = append(records, Record{Name: n.qualify(host.Name), Type: host.Type, Target: host.Address})
+	}
+	return records, nil
+}
+
+// Apply sets the hosts of the domain to the current ones with the changes, the hosts that are not
+// changed are sent back as they are
+func (n *Namecheap) Apply(ctx context.Context, changes Changes) error {
+	if changes.Empty() {
+		return nil
+	}
+	hosts, err := n.hosts()
+	if err != nil {
+		return err
+	}
+	changed := map[Record]bool{}
+	for _, record := range append(append([]Record{}, changes.Delete...), changes.Upsert...) {
+		changed[record] = true
+	}
+	updated := []namecheap.DomainDNSHost{}
+	for _, host := range hosts {
+		if !changed[Record{Name: n.qualify(host.Name), Type: host.Type, Target: host.Address}] {
+			updated = append(updated, host)
+		}
+	}
+	for _, record := range changes.Upsert {
+		updated = append(updated, namecheap.DomainDNSHost{Name: n.relative(record.Name), Type: record.Type, Address: record.Target})
+	}
+	result, err := n.client.DomainDNSSetHosts(n.sld, n.tld, updated)
+	if err != nil {
+		return err
+	}
+	if !result.IsSuccess {
+		return fmt.Errorf("namecheap did not set the hosts of %s", n.zone)
+	}
+	return nil
+}
+
+func (n *Namecheap) hosts() ([]namecheap.DomainDNSHost, error) {
+	result, err := n.client.DomainsDNSGetHosts(n.sld, n.tld)
+	if err != nil {
+		return nil, err
+	}
+	return result.Hosts, nil
+}
+
+// qualify turns the name of a host into the name of a record, @ stands for the domain itself
+func (n *Namecheap) qualify(host string) string {
+	if host == "@" || host == "" {
+		return n.zone
+	}
+	return Normalize(fmt.Sprintf("%s.%s", host, n.zone))
+}
+
+func (n *Namecheap) relative(name string) string {
+	if name = Normalize(name); name == n.zone {
+		return "@"
+	}
+	return strings.TrimSuffix(name, "."+n.zone)
+}
@@ -23,6 +23,7 @@ import (
 
 	appsv1alpha "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/typed/apps/v1alpha"
 	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/typed/core/v1alpha"
+	federationv1alpha "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/typed/federation/v1alpha"
 	networkingv1alpha "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/typed/networking/v1alpha"
 	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/typed/registration/v1alpha"
 	discovery "k8s.io/client-go/discovery"
@@ -34,6 +35,7 @@ type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	AppsV1alpha() appsv1alpha.AppsV1alphaInterface
 	CoreV1alpha() corev1alpha.CoreV1alphaInterface
+	FederationV1alpha() federationv1alpha.FederationV1alphaInterface
 	NetworkingV1alpha() networkingv1alpha.NetworkingV1alphaInterface
 	RegistrationV1alpha() registrationv1alpha.RegistrationV1alphaInterface
 }
@@ -44,6 +46,7 @@ type Clientset struct {
 	*discovery.DiscoveryClient
 	appsV1alpha         *appsv1alpha.AppsV1alphaClient
 	coreV1alpha         *corev1alpha.CoreV1alphaClient
+	federationV1alpha   *federationv1alpha.FederationV1alphaClient
 	networkingV1alpha   *networkingv1alpha.NetworkingV1alphaClient
 	registrationV1alpha *registrationv1alpha.RegistrationV1alphaClient
 }
@@ -58,6 +61,11 @@ func (c *Clientset) CoreV1alpha() corev1alpha.CoreV1alphaInterface {
 	return c.coreV1alpha
 }
 
+// FederationV1alpha retrieves the FederationV1alphaClient
+func (c *Clientset) FederationV1alpha() federationv1alpha.FederationV1alphaInterface {
+	return c.federationV1alpha
+}
+
 // NetworkingV1alpha retrieves the NetworkingV1alphaClient
 func (c *Clientset) NetworkingV1alpha() networkingv1alpha.NetworkingV1alphaInterface {
 	return c.networkingV1alpha
@@ -97,6 +105,10 @@ func NewForConfig(c *rest.Config) (*Clientset, error) {
 	if err != nil {
 		return nil, err
 	}
+	cs.federationV1alpha, err = federationv1alpha.NewForConfig(&configShallowCopy)
+	if err != nil {
+		return nil, err
+	}
 	cs.networkingV1alpha, err = networkingv1alpha.NewForConfig(&configShallowCopy)
 	if err != nil {
 		return nil, err
@@ -119,6 +131,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 	var cs Clientset
 	cs.appsV1alpha = appsv1alpha.NewForConfigOrDie(c)
 	cs.coreV1alpha = corev1alpha.NewForConfigOrDie(c)
+	cs.federationV1alpha = federationv1alpha.NewForConfigOrDie(c)
 	cs.networkingV1alpha = networkingv1alpha.NewForConfigOrDie(c)
 	cs.registrationV1alpha = registrationv1alpha.NewForConfigOrDie(c)
 
@@ -131,6 +144,7 @@ func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.appsV1alpha = appsv1alpha.New(c)
 	cs.coreV1alpha = corev1alpha.New(c)
+	cs.federationV1alpha = federationv1alpha.New(c)
 	cs.networkingV1alpha = networkingv1alpha.New(c)
 	cs.registrationV1alpha = registrationv1alpha.New(c)
 
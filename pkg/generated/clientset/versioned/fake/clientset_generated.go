@@ -24,6 +24,8 @@ import (
 	fakeappsv1alpha "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/typed/apps/v1alpha/fake"
 	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/typed/core/v1alpha"
 	fakecorev1alpha "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/typed/core/v1alpha/fake"
+	federationv1alpha "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/typed/federation/v1alpha"
+	fakefederationv1alpha "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/typed/federation/v1alpha/fake"
 	networkingv1alpha "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/typed/networking/v1alpha"
 	fakenetworkingv1alpha "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/typed/networking/v1alpha/fake"
 	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/typed/registration/v1alpha"
@@ -92,6 +94,11 @@ func (c *Clientset) CoreV1alpha() corev1alpha.CoreV1alphaInterface {
 	return &fakecorev1alpha.FakeCoreV1alpha{Fake: &c.Fake}
 }
 
+// FederationV1alpha retrieves the FederationV1alphaClient
+func (c *Clientset) FederationV1alpha() federationv1alpha.FederationV1alphaInterface {
+	return &fakefederationv1alpha.FakeFederationV1alpha{Fake: &c.Fake}
+}
+
 // NetworkingV1alpha retrieves the NetworkingV1alphaClient
 func (c *Clientset) NetworkingV1alpha() networkingv1alpha.NetworkingV1alphaInterface {
 	return &fakenetworkingv1alpha.FakeNetworkingV1alpha{Fake: &c.Fake}
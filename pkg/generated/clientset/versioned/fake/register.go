@@ -21,6 +21,7 @@ package fake
 import (
 	appsv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/apps/v1alpha"
 	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	federationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/federation/v1alpha"
 	networkingv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/networking/v1alpha"
 	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -36,6 +37,7 @@ var codecs = serializer.NewCodecFactory(scheme)
 var localSchemeBuilder = runtime.SchemeBuilder{
 	appsv1alpha.AddToScheme,
 	corev1alpha.AddToScheme,
+	federationv1alpha.AddToScheme,
 	networkingv1alpha.AddToScheme,
 	registrationv1alpha.AddToScheme,
 }
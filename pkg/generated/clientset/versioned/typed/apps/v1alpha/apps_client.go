@@ -26,7 +26,11 @@ import (
 
 type AppsV1alphaInterface interface {
 	RESTClient() rest.Interface
+	ChartsGetter
+	ExtensionsGetter
+	LoginsGetter
 	SelectiveDeploymentsGetter
+	TenantAppsGetter
 }
 
 // AppsV1alphaClient is used to interact with features provided by the apps.edgenet.io group.
@@ -34,10 +38,26 @@ type AppsV1alphaClient struct {
 	restClient rest.Interface
 }
 
+func (c *AppsV1alphaClient) Charts() ChartInterface {
+	return newCharts(c)
+}
+
+func (c *AppsV1alphaClient) Extensions() ExtensionInterface {
+	return newExtensions(c)
+}
+
+func (c *AppsV1alphaClient) Logins(namespace string) LoginInterface {
+	return newLogins(c, namespace)
+}
+
 func (c *AppsV1alphaClient) SelectiveDeployments(namespace string) SelectiveDeploymentInterface {
 	return newSelectiveDeployments(c, namespace)
 }
 
+func (c *AppsV1alphaClient) TenantApps(namespace string) TenantAppInterface {
+	return newTenantApps(c, namespace)
+}
+
 // NewForConfig creates a new AppsV1alphaClient for the given config.
 func NewForConfig(c *rest.Config) (*AppsV1alphaClient, error) {
 	config := *c
@@ -28,10 +28,26 @@ type FakeAppsV1alpha struct {
 	*testing.Fake
 }
 
+func (c *FakeAppsV1alpha) Charts() v1alpha.ChartInterface {
+	return &FakeCharts{c}
+}
+
+func (c *FakeAppsV1alpha) Extensions() v1alpha.ExtensionInterface {
+	return &FakeExtensions{c}
+}
+
+func (c *FakeAppsV1alpha) Logins(namespace string) v1alpha.LoginInterface {
+	return &FakeLogins{c, namespace}
+}
+
 func (c *FakeAppsV1alpha) SelectiveDeployments(namespace string) v1alpha.SelectiveDeploymentInterface {
 	return &FakeSelectiveDeployments{c, namespace}
 }
 
+func (c *FakeAppsV1alpha) TenantApps(namespace string) v1alpha.TenantAppInterface {
+	return &FakeTenantApps{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeAppsV1alpha) RESTClient() rest.Interface {
@@ -18,4 +18,11 @@ limitations under the License.
 
 package v1alpha
 
+type ChartExpansion interface{}
+
+type ExtensionExpansion interface{}
+
+type LoginExpansion interface{}
+
 type SelectiveDeploymentExpansion interface{}
+type TenantAppExpansion interface{}
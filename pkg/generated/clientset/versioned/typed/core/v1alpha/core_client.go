@@ -26,8 +26,19 @@ import (
 
 type CoreV1alphaInterface interface {
 	RESTClient() rest.Interface
+	ClusterUpgradePlansGetter
+	InstallChecksGetter
+	NodeConfigurationsGetter
 	NodeContributionsGetter
+	NodePoolsGetter
+	OperationsGetter
 	SubNamespacesGetter
+	TenantAuditsGetter
+	TenantMigrationsGetter
+	TenantProfilesGetter
+	TenantSecretStoresGetter
+	TenantServiceAccountsGetter
+	TenantUsagesGetter
 	TenantsGetter
 	TenantResourceQuotasGetter
 }
@@ -37,14 +48,58 @@ type CoreV1alphaClient struct {
 	restClient rest.Interface
 }
 
+func (c *CoreV1alphaClient) ClusterUpgradePlans() ClusterUpgradePlanInterface {
+	return newClusterUpgradePlans(c)
+}
+
+func (c *CoreV1alphaClient) InstallChecks() InstallCheckInterface {
+	return newInstallChecks(c)
+}
+
+func (c *CoreV1alphaClient) NodeConfigurations() NodeConfigurationInterface {
+	return newNodeConfigurations(c)
+}
+
 func (c *CoreV1alphaClient) NodeContributions() NodeContributionInterface {
 	return newNodeContributions(c)
 }
 
+func (c *CoreV1alphaClient) NodePools() NodePoolInterface {
+	return newNodePools(c)
+}
+
+func (c *CoreV1alphaClient) Operations() OperationInterface {
+	return newOperations(c)
+}
+
 func (c *CoreV1alphaClient) SubNamespaces(namespace string) SubNamespaceInterface {
 	return newSubNamespaces(c, namespace)
 }
 
+func (c *CoreV1alphaClient) TenantAudits() TenantAuditInterface {
+	return newTenantAudits(c)
+}
+
+func (c *CoreV1alphaClient) TenantMigrations() TenantMigrationInterface {
+	return newTenantMigrations(c)
+}
+
+func (c *CoreV1alphaClient) TenantProfiles() TenantProfileInterface {
+	return newTenantProfiles(c)
+}
+
+func (c *CoreV1alphaClient) TenantSecretStores(namespace string) TenantSecretStoreInterface {
+	return newTenantSecretStores(c, namespace)
+}
+
+func (c *CoreV1alphaClient) TenantServiceAccounts(namespace string) TenantServiceAccountInterface {
+	return newTenantServiceAccounts(c, namespace)
+}
+
+func (c *CoreV1alphaClient) TenantUsages() TenantUsageInterface {
+	return newTenantUsages(c)
+}
+
 func (c *CoreV1alphaClient) Tenants() TenantInterface {
 	return newTenants(c)
 }
@@ -28,14 +28,58 @@ type FakeCoreV1alpha struct {
 	*testing.Fake
 }
 
+func (c *FakeCoreV1alpha) ClusterUpgradePlans() v1alpha.ClusterUpgradePlanInterface {
+	return &FakeClusterUpgradePlans{c}
+}
+
+func (c *FakeCoreV1alpha) InstallChecks() v1alpha.InstallCheckInterface {
+	return &FakeInstallChecks{c}
+}
+
+func (c *FakeCoreV1alpha) NodeConfigurations() v1alpha.NodeConfigurationInterface {
+	return &FakeNodeConfigurations{c}
+}
+
 func (c *FakeCoreV1alpha) NodeContributions() v1alpha.NodeContributionInterface {
 	return &FakeNodeContributions{c}
 }
 
+func (c *FakeCoreV1alpha) NodePools() v1alpha.NodePoolInterface {
+	return &FakeNodePools{c}
+}
+
+func (c *FakeCoreV1alpha) Operations() v1alpha.OperationInterface {
+	return &FakeOperations{c}
+}
+
 func (c *FakeCoreV1alpha) SubNamespaces(namespace string) v1alpha.SubNamespaceInterface {
 	return &FakeSubNamespaces{c, namespace}
 }
 
+func (c *FakeCoreV1alpha) TenantAudits() v1alpha.TenantAuditInterface {
+	return &FakeTenantAudits{c}
+}
+
+func (c *FakeCoreV1alpha) TenantMigrations() v1alpha.TenantMigrationInterface {
+	return &FakeTenantMigrations{c}
+}
+
+func (c *FakeCoreV1alpha) TenantProfiles() v1alpha.TenantProfileInterface {
+	return &FakeTenantProfiles{c}
+}
+
+func (c *FakeCoreV1alpha) TenantSecretStores(namespace string) v1alpha.TenantSecretStoreInterface {
+	return &FakeTenantSecretStores{c, namespace}
+}
+
+func (c *FakeCoreV1alpha) TenantServiceAccounts(namespace string) v1alpha.TenantServiceAccountInterface {
+	return &FakeTenantServiceAccounts{c, namespace}
+}
+
+func (c *FakeCoreV1alpha) TenantUsages() v1alpha.TenantUsageInterface {
+	return &FakeTenantUsages{c}
+}
+
 func (c *FakeCoreV1alpha) Tenants() v1alpha.TenantInterface {
 	return &FakeTenants{c}
 }
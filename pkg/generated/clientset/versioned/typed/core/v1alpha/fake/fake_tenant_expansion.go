@@ -0,0 +1,34 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+
+	v1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/typed/core/v1alpha"
+)
+
+// Establish marks the tenant as established
+func (c *FakeTenants) Establish(ctx context.Context, name string) (*v1alpha.Tenant, error) {
+	return corev1alpha.EstablishTenant(ctx, c, name)
+}
+
+// Fail marks the tenant as failed
+func (c *FakeTenants) Fail(ctx context.Context, name, message string) (*v1alpha.Tenant, error) {
+	return corev1alpha.FailTenant(ctx, c, name, message)
+}
@@ -18,10 +18,29 @@ limitations under the License.
 
 package v1alpha
 
+type ClusterUpgradePlanExpansion interface{}
+
+type InstallCheckExpansion interface{}
+
+type NodeConfigurationExpansion interface{}
+
 type NodeContributionExpansion interface{}
 
+type NodePoolExpansion interface{}
+
+type OperationExpansion interface{}
+
 type SubNamespaceExpansion interface{}
 
-type TenantExpansion interface{}
+type TenantAuditExpansion interface{}
+
+type TenantMigrationExpansion interface{}
+
+type TenantProfileExpansion interface{}
 
 type TenantResourceQuotaExpansion interface{}
+type TenantSecretStoreExpansion interface{}
+
+type TenantServiceAccountExpansion interface{}
+
+type TenantUsageExpansion interface{}
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha
+
+import (
+	"context"
+	"time"
+
+	v1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// States of a tenant
+const (
+	TenantEstablished = "Established"
+	TenantFailure     = "Failure"
+)
+
+// Now returns the time the status transitions are recorded at, it is replaced in the tests
+var Now = time.Now
+
+// TenantExpansion has the status transitions of a tenant, so that the tools outside the
+// controllers don't reimplement them
+type TenantExpansion interface {
+	// Establish marks the tenant as established and clears the record of its failed syncs.
+	Establish(ctx context.Context, name string) (*v1alpha.Tenant, error)
+	// Fail marks the tenant as failed with the message. The retries are left to the controller,
+	// which counts its own syncs.
+	Fail(ctx context.Context, name, message string) (*v1alpha.Tenant, error)
+}
+
+// Establish marks the tenant as established
+func (c *tenants) Establish(ctx context.Context, name string) (*v1alpha.Tenant, error) {
+	return EstablishTenant(ctx, c, name)
+}
+
+// Fail marks the tenant as failed
+func (c *tenants) Fail(ctx context.Context, name, message string) (*v1alpha.Tenant, error) {
+	return FailTenant(ctx, c, name, message)
+}
+
+// EstablishTenant carries out the establishment of a tenant through the client, the fake
+// clientset shares it
+func EstablishTenant(ctx context.Context, client TenantInterface, name string) (*v1alpha.Tenant, error) {
+	return updateTenantStatus(ctx, client, name, func(tenant *v1alpha.Tenant) {
+		tenant.Status.State = TenantEstablished
+		tenant.Status.Message = "Established"
+		tenant.Status.ObservedGeneration = tenant.GetGeneration()
+		tenant.Status.Retries = 0
+		tenant.Status.LastFailure = nil
+	})
+}
+
+// FailTenant carries out the failure of a tenant through the client, the fake clientset shares it
+func FailTenant(ctx context.Context, client TenantInterface, name, message string) (*v1alpha.Tenant, error) {
+	return updateTenantStatus(ctx, client, name, func(tenant *v1alpha.Tenant) {
+		tenant.Status.State = TenantFailure
+		tenant.Status.Message = message
+		tenant.Status.ObservedGeneration = tenant.GetGeneration()
+		tenant.Status.LastFailure = &v1.Time{Time: Now()}
+	})
+}
+
+// updateTenantStatus applies the transition to the latest version of the tenant, it starts
+// over when the tenant changes in the meantime
+func updateTenantStatus(ctx context.Context, client TenantInterface, name string, transition func(*v1alpha.Tenant)) (*v1alpha.Tenant, error) {
+	var updated *v1alpha.Tenant
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		tenant, err := client.Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		tenantCopy := tenant.DeepCopy()
+		transition(tenantCopy)
+		updated, err = client.UpdateStatus(ctx, tenantCopy, v1.UpdateOptions{})
+		return err
+	})
+	return updated, err
+}
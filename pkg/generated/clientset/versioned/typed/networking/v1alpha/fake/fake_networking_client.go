@@ -28,6 +28,10 @@ type FakeNetworkingV1alpha struct {
 	*testing.Fake
 }
 
+func (c *FakeNetworkingV1alpha) NodeLinks() v1alpha.NodeLinkInterface {
+	return &FakeNodeLinks{c}
+}
+
 func (c *FakeNetworkingV1alpha) VPNPeers() v1alpha.VPNPeerInterface {
 	return &FakeVPNPeers{c}
 }
@@ -18,4 +18,6 @@ limitations under the License.
 
 package v1alpha
 
+type NodeLinkExpansion interface{}
+
 type VPNPeerExpansion interface{}
@@ -26,6 +26,7 @@ import (
 
 type NetworkingV1alphaInterface interface {
 	RESTClient() rest.Interface
+	NodeLinksGetter
 	VPNPeersGetter
 }
 
@@ -34,6 +35,10 @@ type NetworkingV1alphaClient struct {
 	restClient rest.Interface
 }
 
+func (c *NetworkingV1alphaClient) NodeLinks() NodeLinkInterface {
+	return newNodeLinks(c)
+}
+
 func (c *NetworkingV1alphaClient) VPNPeers() VPNPeerInterface {
 	return newVPNPeers(c)
 }
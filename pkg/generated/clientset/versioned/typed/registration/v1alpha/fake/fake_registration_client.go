@@ -32,10 +32,18 @@ func (c *FakeRegistrationV1alpha) ClusterRoleRequests() v1alpha.ClusterRoleReque
 	return &FakeClusterRoleRequests{c}
 }
 
+func (c *FakeRegistrationV1alpha) ExtensionRequests(namespace string) v1alpha.ExtensionRequestInterface {
+	return &FakeExtensionRequests{c, namespace}
+}
+
 func (c *FakeRegistrationV1alpha) RoleRequests(namespace string) v1alpha.RoleRequestInterface {
 	return &FakeRoleRequests{c, namespace}
 }
 
+func (c *FakeRegistrationV1alpha) Rosters(namespace string) v1alpha.RosterInterface {
+	return &FakeRosters{c, namespace}
+}
+
 func (c *FakeRegistrationV1alpha) TenantRequests() v1alpha.TenantRequestInterface {
 	return &FakeTenantRequests{c}
 }
@@ -0,0 +1,34 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+
+	v1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/typed/registration/v1alpha"
+)
+
+// Approve approves the role request on behalf of the approver
+func (c *FakeRoleRequests) Approve(ctx context.Context, name, approver string) (*v1alpha.RoleRequest, error) {
+	return registrationv1alpha.RecordRoleRequestVerdict(ctx, c, name, approver, registrationv1alpha.VerdictApprove)
+}
+
+// Reject rejects the role request on behalf of the approver
+func (c *FakeRoleRequests) Reject(ctx context.Context, name, approver string) (*v1alpha.RoleRequest, error) {
+	return registrationv1alpha.RecordRoleRequestVerdict(ctx, c, name, approver, registrationv1alpha.VerdictReject)
+}
@@ -20,6 +20,6 @@ package v1alpha
 
 type ClusterRoleRequestExpansion interface{}
 
-type RoleRequestExpansion interface{}
+type ExtensionRequestExpansion interface{}
 
-type TenantRequestExpansion interface{}
+type RosterExpansion interface{}
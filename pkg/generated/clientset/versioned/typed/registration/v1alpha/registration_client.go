@@ -27,7 +27,9 @@ import (
 type RegistrationV1alphaInterface interface {
 	RESTClient() rest.Interface
 	ClusterRoleRequestsGetter
+	ExtensionRequestsGetter
 	RoleRequestsGetter
+	RostersGetter
 	TenantRequestsGetter
 }
 
@@ -40,10 +42,18 @@ func (c *RegistrationV1alphaClient) ClusterRoleRequests() ClusterRoleRequestInte
 	return newClusterRoleRequests(c)
 }
 
+func (c *RegistrationV1alphaClient) ExtensionRequests(namespace string) ExtensionRequestInterface {
+	return newExtensionRequests(c, namespace)
+}
+
 func (c *RegistrationV1alphaClient) RoleRequests(namespace string) RoleRequestInterface {
 	return newRoleRequests(c, namespace)
 }
 
+func (c *RegistrationV1alphaClient) Rosters(namespace string) RosterInterface {
+	return newRosters(c, namespace)
+}
+
 func (c *RegistrationV1alphaClient) TenantRequests() TenantRequestInterface {
 	return newTenantRequests(c)
 }
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha
+
+import (
+	"context"
+	"fmt"
+
+	v1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// RoleRequestExpansion has the verdicts on a role request, a single verdict decides it
+type RoleRequestExpansion interface {
+	// Approve approves the role request, the requester is bound to the role afterwards.
+	Approve(ctx context.Context, name, approver string) (*v1alpha.RoleRequest, error)
+	// Reject turns the role request down, it stays rejected until it expires.
+	Reject(ctx context.Context, name, approver string) (*v1alpha.RoleRequest, error)
+}
+
+// Approve approves the role request on behalf of the approver
+func (c *roleRequests) Approve(ctx context.Context, name, approver string) (*v1alpha.RoleRequest, error) {
+	return RecordRoleRequestVerdict(ctx, c, name, approver, VerdictApprove)
+}
+
+// Reject rejects the role request on behalf of the approver
+func (c *roleRequests) Reject(ctx context.Context, name, approver string) (*v1alpha.RoleRequest, error) {
+	return RecordRoleRequestVerdict(ctx, c, name, approver, VerdictReject)
+}
+
+// RecordRoleRequestVerdict decides a role request through the client, the fake clientset shares it.
+// The approval goes into the spec, where the administrators approve the role requests, and the
+// rejection into the status, as nothing else rejects them.
+func RecordRoleRequestVerdict(ctx context.Context, client RoleRequestInterface, name, approver, verdict string) (*v1alpha.RoleRequest, error) {
+	if approver == "" {
+		return nil, fmt.Errorf("the approver is required")
+	}
+	var updated *v1alpha.RoleRequest
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		roleRequest, err := client.Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if roleRequest.Spec.Approved || roleRequest.Status.State == "Approved" || roleRequest.Status.State == "Rejected" {
+			return fmt.Errorf("%w: %s/%s is decided", ErrDecided, roleRequest.GetNamespace(), name)
+		}
+		roleRequestCopy := roleRequest.DeepCopy()
+		if verdict == VerdictApprove {
+			roleRequestCopy.Spec.Approved = true
+			updated, err = client.Update(ctx, roleRequestCopy, v1.UpdateOptions{})
+			return err
+		}
+		roleRequestCopy.Status.State = "Rejected"
+		roleRequestCopy.Status.Message = fmt.Sprintf("Requested Role / Cluster Role declined by %s", approver)
+		updated, err = client.UpdateStatus(ctx, roleRequestCopy, v1.UpdateOptions{})
+		return err
+	})
+	return updated, err
+}
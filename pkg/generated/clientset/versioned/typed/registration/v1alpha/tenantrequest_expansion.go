@@ -0,0 +1,92 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	v1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// Verdicts of the approval records
+const (
+	VerdictApprove = "Approve"
+	VerdictReject  = "Reject"
+)
+
+// ErrDecided is the error of a verdict on a request that has already been decided, or that
+// already holds the same verdict of the approver
+var ErrDecided = errors.New("request is already decided")
+
+// Now returns the time the verdicts are given at, it is replaced in the tests
+var Now = time.Now
+
+// TenantRequestExpansion has the verdicts of the administrators on a tenant request, so that
+// the tools outside the controllers don't reimplement them
+type TenantRequestExpansion interface {
+	// Approve records the approval of the administrator, the request is approved once the
+	// approvals reach the quorum of the cluster.
+	Approve(ctx context.Context, name, approver string) (*v1alpha.TenantRequest, error)
+	// Reject records the rejection of the administrator, a single rejection turns the request down.
+	Reject(ctx context.Context, name, approver string) (*v1alpha.TenantRequest, error)
+}
+
+// Approve records the approval of the administrator on the tenant request
+func (c *tenantRequests) Approve(ctx context.Context, name, approver string) (*v1alpha.TenantRequest, error) {
+	return RecordTenantRequestVerdict(ctx, c, name, approver, VerdictApprove)
+}
+
+// Reject records the rejection of the administrator on the tenant request
+func (c *tenantRequests) Reject(ctx context.Context, name, approver string) (*v1alpha.TenantRequest, error) {
+	return RecordTenantRequestVerdict(ctx, c, name, approver, VerdictReject)
+}
+
+// RecordTenantRequestVerdict appends the verdict of the administrator to the approval records of
+// a tenant request through the client, the fake clientset shares it
+func RecordTenantRequestVerdict(ctx context.Context, client TenantRequestInterface, name, approver, verdict string) (*v1alpha.TenantRequest, error) {
+	if approver == "" {
+		return nil, fmt.Errorf("the approver is required")
+	}
+	var updated *v1alpha.TenantRequest
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		tenantRequest, err := client.Get(ctx, name, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		switch tenantRequest.Status.State {
+		case "Approved", "Rejected":
+			return fmt.Errorf("%w: %s is %s", ErrDecided, name, strings.ToLower(tenantRequest.Status.State))
+		}
+		for _, approval := range tenantRequest.Spec.Approvals {
+			if strings.EqualFold(approval.Approver, approver) && approval.Verdict == verdict {
+				return fmt.Errorf("%w: %s already has the verdict %s of %s", ErrDecided, name, strings.ToLower(verdict), approver)
+			}
+		}
+		tenantRequestCopy := tenantRequest.DeepCopy()
+		tenantRequestCopy.Spec.Approvals = append(tenantRequestCopy.Spec.Approvals,
+			v1alpha.Approval{Approver: approver, Time: v1.NewTime(Now()), Verdict: verdict})
+		updated, err = client.Update(ctx, tenantRequestCopy, v1.UpdateOptions{})
+		return err
+	})
+	return updated, err
+}
@@ -24,8 +24,16 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// Charts returns a ChartInformer.
+	Charts() ChartInformer
+	// Extensions returns a ExtensionInformer.
+	Extensions() ExtensionInformer
+	// Logins returns a LoginInformer.
+	Logins() LoginInformer
 	// SelectiveDeployments returns a SelectiveDeploymentInformer.
 	SelectiveDeployments() SelectiveDeploymentInformer
+	// TenantApps returns a TenantAppInformer.
+	TenantApps() TenantAppInformer
 }
 
 type version struct {
@@ -39,7 +47,27 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// Charts returns a ChartInformer.
+func (v *version) Charts() ChartInformer {
+	return &chartInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// Extensions returns a ExtensionInformer.
+func (v *version) Extensions() ExtensionInformer {
+	return &extensionInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// Logins returns a LoginInformer.
+func (v *version) Logins() LoginInformer {
+	return &loginInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // SelectiveDeployments returns a SelectiveDeploymentInformer.
 func (v *version) SelectiveDeployments() SelectiveDeploymentInformer {
 	return &selectiveDeploymentInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// TenantApps returns a TenantAppInformer.
+func (v *version) TenantApps() TenantAppInformer {
+	return &tenantAppInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
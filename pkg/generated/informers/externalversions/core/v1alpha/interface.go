@@ -24,10 +24,32 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// ClusterUpgradePlans returns a ClusterUpgradePlanInformer.
+	ClusterUpgradePlans() ClusterUpgradePlanInformer
+	// InstallChecks returns a InstallCheckInformer.
+	InstallChecks() InstallCheckInformer
+	// NodeConfigurations returns a NodeConfigurationInformer.
+	NodeConfigurations() NodeConfigurationInformer
 	// NodeContributions returns a NodeContributionInformer.
 	NodeContributions() NodeContributionInformer
+	// NodePools returns a NodePoolInformer.
+	NodePools() NodePoolInformer
+	// Operations returns a OperationInformer.
+	Operations() OperationInformer
 	// SubNamespaces returns a SubNamespaceInformer.
 	SubNamespaces() SubNamespaceInformer
+	// TenantAudits returns a TenantAuditInformer.
+	TenantAudits() TenantAuditInformer
+	// TenantMigrations returns a TenantMigrationInformer.
+	TenantMigrations() TenantMigrationInformer
+	// TenantProfiles returns a TenantProfileInformer.
+	TenantProfiles() TenantProfileInformer
+	// TenantSecretStores returns a TenantSecretStoreInformer.
+	TenantSecretStores() TenantSecretStoreInformer
+	// TenantServiceAccounts returns a TenantServiceAccountInformer.
+	TenantServiceAccounts() TenantServiceAccountInformer
+	// TenantUsages returns a TenantUsageInformer.
+	TenantUsages() TenantUsageInformer
 	// Tenants returns a TenantInformer.
 	Tenants() TenantInformer
 	// TenantResourceQuotas returns a TenantResourceQuotaInformer.
@@ -45,16 +67,71 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// ClusterUpgradePlans returns a ClusterUpgradePlanInformer.
+func (v *version) ClusterUpgradePlans() ClusterUpgradePlanInformer {
+	return &clusterUpgradePlanInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// InstallChecks returns a InstallCheckInformer.
+func (v *version) InstallChecks() InstallCheckInformer {
+	return &installCheckInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// NodeConfigurations returns a NodeConfigurationInformer.
+func (v *version) NodeConfigurations() NodeConfigurationInformer {
+	return &nodeConfigurationInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // NodeContributions returns a NodeContributionInformer.
 func (v *version) NodeContributions() NodeContributionInformer {
 	return &nodeContributionInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// NodePools returns a NodePoolInformer.
+func (v *version) NodePools() NodePoolInformer {
+	return &nodePoolInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// Operations returns a OperationInformer.
+func (v *version) Operations() OperationInformer {
+	return &operationInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // SubNamespaces returns a SubNamespaceInformer.
 func (v *version) SubNamespaces() SubNamespaceInformer {
 	return &subNamespaceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// TenantAudits returns a TenantAuditInformer.
+func (v *version) TenantAudits() TenantAuditInformer {
+	return &tenantAuditInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// TenantMigrations returns a TenantMigrationInformer.
+func (v *version) TenantMigrations() TenantMigrationInformer {
+	return &tenantMigrationInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// TenantProfiles returns a TenantProfileInformer.
+func (v *version) TenantProfiles() TenantProfileInformer {
+	return &tenantProfileInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// TenantSecretStores returns a TenantSecretStoreInformer.
+func (v *version) TenantSecretStores() TenantSecretStoreInformer {
+	return &tenantSecretStoreInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// TenantServiceAccounts returns a TenantServiceAccountInformer.
+func (v *version) TenantServiceAccounts() TenantServiceAccountInformer {
+	return &tenantServiceAccountInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// TenantUsages returns a TenantUsageInformer.
+func (v *version) TenantUsages() TenantUsageInformer {
+	return &tenantUsageInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // Tenants returns a TenantInformer.
 func (v *version) Tenants() TenantInformer {
 	return &tenantInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -23,6 +23,7 @@ import (
 
 	v1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/apps/v1alpha"
 	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	federationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/federation/v1alpha"
 	networkingv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/networking/v1alpha"
 	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
@@ -56,28 +57,72 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=apps.edgenet.io, Version=v1alpha
+	case v1alpha.SchemeGroupVersion.WithResource("charts"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Apps().V1alpha().Charts().Informer()}, nil
+	case v1alpha.SchemeGroupVersion.WithResource("extensions"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Apps().V1alpha().Extensions().Informer()}, nil
+	case v1alpha.SchemeGroupVersion.WithResource("logins"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Apps().V1alpha().Logins().Informer()}, nil
 	case v1alpha.SchemeGroupVersion.WithResource("selectivedeployments"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Apps().V1alpha().SelectiveDeployments().Informer()}, nil
+	case v1alpha.SchemeGroupVersion.WithResource("tenantapps"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Apps().V1alpha().TenantApps().Informer()}, nil
 
 		// Group=core.edgenet.io, Version=v1alpha
+	case corev1alpha.SchemeGroupVersion.WithResource("clusterupgradeplans"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha().ClusterUpgradePlans().Informer()}, nil
+	case corev1alpha.SchemeGroupVersion.WithResource("installchecks"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha().InstallChecks().Informer()}, nil
+	case corev1alpha.SchemeGroupVersion.WithResource("nodeconfigurations"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha().NodeConfigurations().Informer()}, nil
 	case corev1alpha.SchemeGroupVersion.WithResource("nodecontributions"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha().NodeContributions().Informer()}, nil
+	case corev1alpha.SchemeGroupVersion.WithResource("nodepools"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha().NodePools().Informer()}, nil
+	case corev1alpha.SchemeGroupVersion.WithResource("operations"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha().Operations().Informer()}, nil
 	case corev1alpha.SchemeGroupVersion.WithResource("subnamespaces"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha().SubNamespaces().Informer()}, nil
+	case corev1alpha.SchemeGroupVersion.WithResource("tenantaudits"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha().TenantAudits().Informer()}, nil
+	case corev1alpha.SchemeGroupVersion.WithResource("tenantmigrations"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha().TenantMigrations().Informer()}, nil
+	case corev1alpha.SchemeGroupVersion.WithResource("tenantprofiles"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha().TenantProfiles().Informer()}, nil
+	case corev1alpha.SchemeGroupVersion.WithResource("tenantsecretstores"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha().TenantSecretStores().Informer()}, nil
+	case corev1alpha.SchemeGroupVersion.WithResource("tenantserviceaccounts"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha().TenantServiceAccounts().Informer()}, nil
+	case corev1alpha.SchemeGroupVersion.WithResource("tenantusages"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha().TenantUsages().Informer()}, nil
 	case corev1alpha.SchemeGroupVersion.WithResource("tenants"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha().Tenants().Informer()}, nil
 	case corev1alpha.SchemeGroupVersion.WithResource("tenantresourcequotas"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Core().V1alpha().TenantResourceQuotas().Informer()}, nil
 
+		// Group=federation.edgenet.io, Version=v1alpha
+	case federationv1alpha.SchemeGroupVersion.WithResource("clusters"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Federation().V1alpha().Clusters().Informer()}, nil
+	case federationv1alpha.SchemeGroupVersion.WithResource("federatedtenants"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Federation().V1alpha().FederatedTenants().Informer()}, nil
+	case federationv1alpha.SchemeGroupVersion.WithResource("selectivedeploymentanchors"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Federation().V1alpha().SelectiveDeploymentAnchors().Informer()}, nil
+
 		// Group=networking.edgenet.io, Version=v1alpha
+	case networkingv1alpha.SchemeGroupVersion.WithResource("nodelinks"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Networking().V1alpha().NodeLinks().Informer()}, nil
 	case networkingv1alpha.SchemeGroupVersion.WithResource("vpnpeers"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Networking().V1alpha().VPNPeers().Informer()}, nil
 
 		// Group=registration.edgenet.io, Version=v1alpha
 	case registrationv1alpha.SchemeGroupVersion.WithResource("clusterrolerequests"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Registration().V1alpha().ClusterRoleRequests().Informer()}, nil
+	case registrationv1alpha.SchemeGroupVersion.WithResource("extensionrequests"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Registration().V1alpha().ExtensionRequests().Informer()}, nil
 	case registrationv1alpha.SchemeGroupVersion.WithResource("rolerequests"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Registration().V1alpha().RoleRequests().Informer()}, nil
+	case registrationv1alpha.SchemeGroupVersion.WithResource("rosters"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Registration().V1alpha().Rosters().Informer()}, nil
 	case registrationv1alpha.SchemeGroupVersion.WithResource("tenantrequests"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Registration().V1alpha().TenantRequests().Informer()}, nil
 
@@ -24,6 +24,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// NodeLinks returns a NodeLinkInformer.
+	NodeLinks() NodeLinkInformer
 	// VPNPeers returns a VPNPeerInformer.
 	VPNPeers() VPNPeerInformer
 }
@@ -39,6 +41,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// NodeLinks returns a NodeLinkInformer.
+func (v *version) NodeLinks() NodeLinkInformer {
+	return &nodeLinkInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // VPNPeers returns a VPNPeerInformer.
 func (v *version) VPNPeers() VPNPeerInformer {
 	return &vPNPeerInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -26,8 +26,12 @@ import (
 type Interface interface {
 	// ClusterRoleRequests returns a ClusterRoleRequestInformer.
 	ClusterRoleRequests() ClusterRoleRequestInformer
+	// ExtensionRequests returns a ExtensionRequestInformer.
+	ExtensionRequests() ExtensionRequestInformer
 	// RoleRequests returns a RoleRequestInformer.
 	RoleRequests() RoleRequestInformer
+	// Rosters returns a RosterInformer.
+	Rosters() RosterInformer
 	// TenantRequests returns a TenantRequestInformer.
 	TenantRequests() TenantRequestInformer
 }
@@ -48,11 +52,21 @@ func (v *version) ClusterRoleRequests() ClusterRoleRequestInformer {
 	return &clusterRoleRequestInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// ExtensionRequests returns a ExtensionRequestInformer.
+func (v *version) ExtensionRequests() ExtensionRequestInformer {
+	return &extensionRequestInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // RoleRequests returns a RoleRequestInformer.
 func (v *version) RoleRequests() RoleRequestInformer {
 	return &roleRequestInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// Rosters returns a RosterInformer.
+func (v *version) Rosters() RosterInformer {
+	return &rosterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // TenantRequests returns a TenantRequestInformer.
 func (v *version) TenantRequests() TenantRequestInformer {
 	return &tenantRequestInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -18,6 +18,22 @@ limitations under the License.
 
 package v1alpha
 
+// ChartListerExpansion allows custom methods to be added to
+// ChartLister.
+type ChartListerExpansion interface{}
+
+// ExtensionListerExpansion allows custom methods to be added to
+// ExtensionLister.
+type ExtensionListerExpansion interface{}
+
+// LoginListerExpansion allows custom methods to be added to
+// LoginLister.
+type LoginListerExpansion interface{}
+
+// LoginNamespaceListerExpansion allows custom methods to be added to
+// LoginNamespaceLister.
+type LoginNamespaceListerExpansion interface{}
+
 // SelectiveDeploymentListerExpansion allows custom methods to be added to
 // SelectiveDeploymentLister.
 type SelectiveDeploymentListerExpansion interface{}
@@ -25,3 +41,11 @@ type SelectiveDeploymentListerExpansion interface{}
 // SelectiveDeploymentNamespaceListerExpansion allows custom methods to be added to
 // SelectiveDeploymentNamespaceLister.
 type SelectiveDeploymentNamespaceListerExpansion interface{}
+
+// TenantAppListerExpansion allows custom methods to be added to
+// TenantAppLister.
+type TenantAppListerExpansion interface{}
+
+// TenantAppNamespaceListerExpansion allows custom methods to be added to
+// TenantAppNamespaceLister.
+type TenantAppNamespaceListerExpansion interface{}
@@ -18,10 +18,30 @@ limitations under the License.
 
 package v1alpha
 
+// ClusterUpgradePlanListerExpansion allows custom methods to be added to
+// ClusterUpgradePlanLister.
+type ClusterUpgradePlanListerExpansion interface{}
+
+// InstallCheckListerExpansion allows custom methods to be added to
+// InstallCheckLister.
+type InstallCheckListerExpansion interface{}
+
+// NodeConfigurationListerExpansion allows custom methods to be added to
+// NodeConfigurationLister.
+type NodeConfigurationListerExpansion interface{}
+
 // NodeContributionListerExpansion allows custom methods to be added to
 // NodeContributionLister.
 type NodeContributionListerExpansion interface{}
 
+// NodePoolListerExpansion allows custom methods to be added to
+// NodePoolLister.
+type NodePoolListerExpansion interface{}
+
+// OperationListerExpansion allows custom methods to be added to
+// OperationLister.
+type OperationListerExpansion interface{}
+
 // SubNamespaceListerExpansion allows custom methods to be added to
 // SubNamespaceLister.
 type SubNamespaceListerExpansion interface{}
@@ -34,6 +54,38 @@ type SubNamespaceNamespaceListerExpansion interface{}
 // TenantLister.
 type TenantListerExpansion interface{}
 
+// TenantAuditListerExpansion allows custom methods to be added to
+// TenantAuditLister.
+type TenantAuditListerExpansion interface{}
+
+// TenantMigrationListerExpansion allows custom methods to be added to
+// TenantMigrationLister.
+type TenantMigrationListerExpansion interface{}
+
+// TenantProfileListerExpansion allows custom methods to be added to
+// TenantProfileLister.
+type TenantProfileListerExpansion interface{}
+
 // TenantResourceQuotaListerExpansion allows custom methods to be added to
 // TenantResourceQuotaLister.
 type TenantResourceQuotaListerExpansion interface{}
+
+// TenantSecretStoreListerExpansion allows custom methods to be added to
+// TenantSecretStoreLister.
+type TenantSecretStoreListerExpansion interface{}
+
+// TenantServiceAccountListerExpansion allows custom methods to be added to
+// TenantServiceAccountLister.
+type TenantServiceAccountListerExpansion interface{}
+
+// TenantSecretStoreNamespaceListerExpansion allows custom methods to be added to
+// TenantSecretStoreNamespaceLister.
+type TenantSecretStoreNamespaceListerExpansion interface{}
+
+// TenantServiceAccountNamespaceListerExpansion allows custom methods to be added to
+// TenantServiceAccountNamespaceLister.
+type TenantServiceAccountNamespaceListerExpansion interface{}
+
+// TenantUsageListerExpansion allows custom methods to be added to
+// TenantUsageLister.
+type TenantUsageListerExpansion interface{}
@@ -18,6 +18,10 @@ limitations under the License.
 
 package v1alpha
 
+// NodeLinkListerExpansion allows custom methods to be added to
+// NodeLinkLister.
+type NodeLinkListerExpansion interface{}
+
 // VPNPeerListerExpansion allows custom methods to be added to
 // VPNPeerLister.
 type VPNPeerListerExpansion interface{}
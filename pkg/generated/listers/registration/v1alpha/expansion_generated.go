@@ -22,6 +22,14 @@ package v1alpha
 // ClusterRoleRequestLister.
 type ClusterRoleRequestListerExpansion interface{}
 
+// ExtensionRequestListerExpansion allows custom methods to be added to
+// ExtensionRequestLister.
+type ExtensionRequestListerExpansion interface{}
+
+// ExtensionRequestNamespaceListerExpansion allows custom methods to be added to
+// ExtensionRequestNamespaceLister.
+type ExtensionRequestNamespaceListerExpansion interface{}
+
 // RoleRequestListerExpansion allows custom methods to be added to
 // RoleRequestLister.
 type RoleRequestListerExpansion interface{}
@@ -30,6 +38,14 @@ type RoleRequestListerExpansion interface{}
 // RoleRequestNamespaceLister.
 type RoleRequestNamespaceListerExpansion interface{}
 
+// RosterListerExpansion allows custom methods to be added to
+// RosterLister.
+type RosterListerExpansion interface{}
+
+// RosterNamespaceListerExpansion allows custom methods to be added to
+// RosterNamespaceLister.
+type RosterNamespaceListerExpansion interface{}
+
 // TenantRequestListerExpansion allows custom methods to be added to
 // TenantRequestLister.
 type TenantRequestListerExpansion interface{}
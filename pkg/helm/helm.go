@@ -0,0 +1,131 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Release describes a chart to be rendered into a namespace
+type Release struct {
+	Name       string
+	Namespace  string
+	Repository string
+	Chart      string
+	Version    string
+	Values     map[string]interface{}
+}
+
+// Renderer turns a release into its manifests
+type Renderer interface {
+	Render(release Release) ([]byte, error)
+}
+
+// CLI renders the charts by the helm binary. Only the template command is used,
+// so helm never talks to the cluster and the objects are created by the caller.
+type CLI struct {
+	// Path of the helm binary
+	Binary string
+}
+
+// Render runs helm template for the release
+func (c CLI) Render(release Release) ([]byte, error) {
+	valuesFile, err := ioutil.TempFile("", "values-*.json")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(valuesFile.Name())
+	// JSON is a subset of YAML, helm reads it as a values file
+	if err := json.NewEncoder(valuesFile).Encode(release.Values); err != nil {
+		valuesFile.Close()
+		return nil, err
+	}
+	valuesFile.Close()
+
+	// CRDs are cluster-scoped, they are left to the cluster administrators
+	args := []string{"template", release.Name, release.Chart, "--repo", release.Repository, "--version", release.Version,
+		"--namespace", release.Namespace, "--values", valuesFile.Name(), "--skip-crds"}
+	var stderr bytes.Buffer
+	cmd := exec.Command(c.Binary, args...)
+	cmd.Stderr = &stderr
+	manifest, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("helm template failed: %s: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return manifest, nil
+}
+
+// Decode splits a multi-document manifest into objects
+func Decode(manifest []byte) ([]*unstructured.Unstructured, error) {
+	objects := []*unstructured.Unstructured{}
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifest), 4096)
+	for {
+		content := map[string]interface{}{}
+		if err := decoder.Decode(&content); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		// Empty documents come out of templates that are disabled by values
+		if len(content) == 0 {
+			continue
+		}
+		// Round trip through the unstructured scheme so that the numbers are integers as the API server returns
+		raw, err := json.Marshal(content)
+		if err != nil {
+			return nil, err
+		}
+		object := &unstructured.Unstructured{}
+		if err := object.UnmarshalJSON(raw); err != nil {
+			return nil, err
+		}
+		if object.GetKind() == "" || object.GetName() == "" {
+			return nil, fmt.Errorf("manifest contains an object without kind or name")
+		}
+		objects = append(objects, object)
+	}
+	return objects, nil
+}
+
+// MergeValues returns the defaults overridden by the given values, nested maps are merged recursively
+func MergeValues(defaults, values map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{})
+	for key, value := range defaults {
+		merged[key] = value
+	}
+	for key, value := range values {
+		if valueMap, ok := value.(map[string]interface{}); ok {
+			if defaultMap, ok := merged[key].(map[string]interface{}); ok {
+				merged[key] = MergeValues(defaultMap, valueMap)
+				continue
+			}
+		}
+		merged[key] = value
+	}
+	return merged
+}
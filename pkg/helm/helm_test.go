@@ -0,0 +1,56 @@
+package helm
+
+import (
+	"testing"
+
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDecode(t *testing.T) {
+	manifest := []byte(`---
+# Source: app/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: app
+spec:
+  ports:
+  - port: 80
+---
+# Source: app/templates/ingress.yaml
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+`)
+	objects, err := Decode(manifest)
+	util.OK(t, err)
+	util.Equals(t, 2, len(objects))
+	util.Equals(t, "Service", objects[0].GetKind())
+	util.Equals(t, "apps/v1", objects[1].GetAPIVersion())
+	port, _, _ := unstructured.NestedSlice(objects[0].Object, "spec", "ports")
+	util.Equals(t, int64(80), port[0].(map[string]interface{})["port"])
+
+	_, err = Decode([]byte("apiVersion: v1\nkind: ConfigMap\n"))
+	util.Assert(t, err != nil, "object without name is decoded")
+}
+
+func TestMergeValues(t *testing.T) {
+	defaults := map[string]interface{}{
+		"replicas": 1,
+		"image":    map[string]interface{}{"repository": "nginx", "tag": "1.21"},
+	}
+	values := map[string]interface{}{
+		"image":   map[string]interface{}{"tag": "1.22"},
+		"service": "ClusterIP",
+	}
+	merged := MergeValues(defaults, values)
+	util.Equals(t, 1, merged["replicas"])
+	util.Equals(t, "ClusterIP", merged["service"])
+	util.Equals(t, map[string]interface{}{"repository": "nginx", "tag": "1.22"}, merged["image"])
+	// The defaults stay untouched
+	util.Equals(t, "1.21", defaults["image"].(map[string]interface{})["tag"])
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package hierarchy walks the trees that the subnamespaces form below the core namespaces of the
+// tenants, and bounds how deep the workspaces nest.
+package hierarchy
+
+import (
+	"fmt"
+	"strings"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+// DefaultMaxDepth is the number of levels the workspaces nest below the core namespace of a tenant
+const DefaultMaxDepth = 5
+
+// NamespaceGetter returns a namespace by its name, from a lister or from the API server
+type NamespaceGetter func(name string) (*corev1.Namespace, error)
+
+// CycleError is returned when a namespace is found among its own ancestors
+type CycleError struct {
+	Namespace string
+	Path      []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("namespace %s is its own ancestor: %s", e.Namespace, strings.Join(e.Path, " -> "))
+}
+
+// Ancestors returns the namespace followed by the namespaces above it, up to the core namespace
+// of its tenant. The walk follows the parent namespace labels of the subsidiary namespaces, and
+// stops at a parent that is gone.
+func Ancestors(get NamespaceGetter, name string) ([]string, error) {
+	ancestors := []string{}
+	seen := map[string]bool{}
+	for name != "" {
+		if seen[name] {
+			return ancestors, &CycleError{Namespace: name, Path: append(ancestors, name)}
+		}
+		namespace, err := get(name)
+		if errors.IsNotFound(err) {
+			break
+		} else if err != nil {
+			return ancestors, err
+		}
+		seen[name] = true
+		ancestors = append(ancestors, name)
+		// The core namespace of a subtenant is the top of its own tree
+		if namespace.GetLabels()["edge-net.io/kind"] != "sub" {
+			break
+		}
+		name = namespace.GetLabels()["edge-net.io/parent-namespace"]
+	}
+	return ancestors, nil
+}
+
+// Parent returns the subnamespace that formed the namespace, nil if the namespace is not a
+// subsidiary namespace
+func Parent(namespace *corev1.Namespace) *corev1alpha.SubNamespaceReference {
+	namespaceLabels := namespace.GetLabels()
+	if namespaceLabels["edge-net.io/kind"] != "sub" || namespaceLabels["edge-net.io/parent-namespace"] == "" || namespaceLabels["edge-net.io/owner"] == "" {
+		return nil
+	}
+	return &corev1alpha.SubNamespaceReference{Namespace: namespaceLabels["edge-net.io/parent-namespace"], Name: namespaceLabels["edge-net.io/owner"]}
+}
@@ -0,0 +1,159 @@
+package hierarchy
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	kubetestclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+func coreNamespace(name string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name,
+		Labels: map[string]string{"edge-net.io/kind": "core", "edge-net.io/tenant": name, "edge-net.io/cluster-uid": "d9b4c2f5"}}}
+}
+
+func subNamespace(name, parent, owner string) *corev1.Namespace {
+	return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name,
+		Labels: map[string]string{"edge-net.io/kind": "sub", "edge-net.io/tenant": "lip6", "edge-net.io/cluster-uid": "d9b4c2f5",
+			"edge-net.io/parent-namespace": parent, "edge-net.io/owner": owner}}}
+}
+
+func newSubNamespace(namespace, name string, workspace bool) *corev1alpha.SubNamespace {
+	subnamespace := &corev1alpha.SubNamespace{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	if workspace {
+		subnamespace.Spec.Workspace = &corev1alpha.Workspace{Scope: "local"}
+	} else {
+		subnamespace.Spec.Subtenant = &corev1alpha.Subtenant{}
+	}
+	return subnamespace
+}
+
+// upward is the name of the namespace the subnamespace up in the namespace deep forms
+func upward(t *testing.T) string {
+	name, err := newSubNamespace("deep", "up", true).GenerateChildName("d9b4c2f5")
+	util.OK(t, err)
+	return name
+}
+
+func newWebhook(t *testing.T) *Webhook {
+	kubeclientset := kubetestclient.NewSimpleClientset(
+		coreNamespace("lip6"),
+		subNamespace("lab-1a2b3c", "lip6", "lab"),
+		subNamespace("course-4d5e6f", "lab-1a2b3c", "course"),
+		subNamespace("loop-a", "loop-b", "a"),
+		subNamespace("loop-b", "loop-a", "b"),
+		subNamespace("orphan", "gone", "orphan"),
+		coreNamespace(upward(t)),
+		subNamespace("deep", upward(t), "deep"),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+	)
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeclientset, 0)
+	webhook := NewWebhook(kubeInformerFactory.Core().V1().Namespaces(), 2)
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	kubeInformerFactory.Start(stopCh)
+	util.OK(t, webhook.WaitForCacheSync(stopCh))
+	return webhook
+}
+
+func TestAncestors(t *testing.T) {
+	webhook := newWebhook(t)
+	ancestors, err := Ancestors(webhook.get, "course-4d5e6f")
+	util.OK(t, err)
+	util.Equals(t, []string{"course-4d5e6f", "lab-1a2b3c", "lip6"}, ancestors)
+
+	ancestors, err = Ancestors(webhook.get, "orphan")
+	util.OK(t, err)
+	util.Equals(t, []string{"orphan"}, ancestors)
+
+	_, err = Ancestors(webhook.get, "loop-a")
+	cycle, ok := err.(*CycleError)
+	util.Equals(t, true, ok)
+	util.Equals(t, []string{"loop-a", "loop-b", "loop-a"}, cycle.Path)
+	util.Equals(t, "namespace loop-a is its own ancestor: loop-a -> loop-b -> loop-a", cycle.Error())
+}
+
+func TestParent(t *testing.T) {
+	util.Equals(t, &corev1alpha.SubNamespaceReference{Namespace: "lab-1a2b3c", Name: "course"}, Parent(subNamespace("course-4d5e6f", "lab-1a2b3c", "course")))
+	var none *corev1alpha.SubNamespaceReference
+	util.Equals(t, none, Parent(coreNamespace("lip6")))
+}
+
+func TestAdmit(t *testing.T) {
+	webhook := newWebhook(t)
+	cases := map[string]struct {
+		subnamespace *corev1alpha.SubNamespace
+		admitted     bool
+	}{
+		"in the core namespace":       {newSubNamespace("lip6", "lab", true), true},
+		"at the limit":                {newSubNamespace("lab-1a2b3c", "course", true), true},
+		"beyond the limit":            {newSubNamespace("course-4d5e6f", "group", true), false},
+		"subtenant beyond the limit":  {newSubNamespace("course-4d5e6f", "spinoff", false), true},
+		"parent gone":                 {newSubNamespace("orphan", "team", true), true},
+		"in a cycle":                  {newSubNamespace("loop-a", "team", true), false},
+		"forming a namespace above":   {newSubNamespace("deep", "up", true), false},
+		"out of the tenants":          {newSubNamespace("kube-system", "team", true), true},
+		"in a namespace not in cache": {newSubNamespace("unknown", "team", true), true},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			err := webhook.Admit(tc.subnamespace)
+			util.Equals(t, tc.admitted, err == nil)
+		})
+	}
+	util.Equals(t, "subnamespace would form namespace "+upward(t)+", which is above it", webhook.Admit(newSubNamespace("deep", "up", true)).Error())
+	util.Equals(t, "workspace would nest 3 levels below the core namespace of tenant lip6, beyond the limit of 2",
+		webhook.Admit(newSubNamespace("course-4d5e6f", "group", true)).Error())
+
+	webhook.maxDepth = 0
+	util.OK(t, webhook.Admit(newSubNamespace("course-4d5e6f", "group", true)))
+}
+
+func TestHandler(t *testing.T) {
+	server := httptest.NewServer(newWebhook(t).Handler())
+	defer server.Close()
+
+	post := func(subnamespace *corev1alpha.SubNamespace) *admissionv1.AdmissionResponse {
+		namespace := subnamespace.GetNamespace()
+		subnamespace.SetNamespace("")
+		raw, err := json.Marshal(subnamespace)
+		util.OK(t, err)
+		review := admissionv1.AdmissionReview{TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+			Request: &admissionv1.AdmissionRequest{UID: "1", Namespace: namespace, Object: runtime.RawExtension{Raw: raw}}}
+		payload, err := json.Marshal(review)
+		util.OK(t, err)
+		response, err := http.Post(server.URL, "application/json", bytes.NewReader(payload))
+		util.OK(t, err)
+		defer response.Body.Close()
+		util.OK(t, json.NewDecoder(response.Body).Decode(&review))
+		util.Equals(t, "AdmissionReview", review.Kind)
+		return review.Response
+	}
+	response := post(newSubNamespace("course-4d5e6f", "group", true))
+	util.Equals(t, false, response.Allowed)
+	util.Equals(t, int32(http.StatusForbidden), response.Result.Code)
+	response = post(newSubNamespace("lab-1a2b3c", "course", true))
+	util.Equals(t, true, response.Allowed)
+	util.Equals(t, "1", string(response.UID))
+}
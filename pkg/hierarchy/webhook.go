@@ -0,0 +1,129 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hierarchy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// Webhook rejects the subnamespaces that would nest the workspaces of a tenant beyond the maximum
+// depth, or form a namespace that is above them
+type Webhook struct {
+	namespacesLister corelisters.NamespaceLister
+	namespacesSynced cache.InformerSynced
+	// maxDepth is the number of levels the workspaces nest below the core namespace, unbounded if 0
+	maxDepth int
+}
+
+// NewWebhook returns a new webhook
+func NewWebhook(namespaceInformer coreinformers.NamespaceInformer, maxDepth int) *Webhook {
+	return &Webhook{
+		namespacesLister: namespaceInformer.Lister(),
+		namespacesSynced: namespaceInformer.Informer().HasSynced,
+		maxDepth:         maxDepth,
+	}
+}
+
+// WaitForCacheSync blocks until the caches of the webhook are synced or stopCh is closed
+func (w *Webhook) WaitForCacheSync(stopCh <-chan struct{}) error {
+	if ok := cache.WaitForCacheSync(stopCh, w.namespacesSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+	return nil
+}
+
+// Handler serves the admission reviews of the subnamespaces
+func (w *Webhook) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		review := admissionv1.AdmissionReview{}
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil || review.Request == nil {
+			http.Error(rw, "invalid admission review", http.StatusBadRequest)
+			return
+		}
+		response := &admissionv1.AdmissionResponse{UID: review.Request.UID, Allowed: true}
+		subnamespace := new(corev1alpha.SubNamespace)
+		if err := json.Unmarshal(review.Request.Object.Raw, subnamespace); err != nil {
+			response.Allowed = false
+			response.Result = &metav1.Status{Status: metav1.StatusFailure, Code: http.StatusBadRequest, Message: err.Error()}
+		} else {
+			if subnamespace.GetNamespace() == "" {
+				subnamespace.SetNamespace(review.Request.Namespace)
+			}
+			if err := w.Admit(subnamespace); err != nil {
+				response.Allowed = false
+				response.Result = &metav1.Status{Status: metav1.StatusFailure, Code: http.StatusForbidden, Reason: metav1.StatusReasonForbidden, Message: err.Error()}
+			}
+		}
+		review.Request = nil
+		review.Response = response
+		rw.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(rw).Encode(review); err != nil {
+			klog.ErrorS(err, "Couldn't write the admission review")
+		}
+	})
+}
+
+// Admit returns why the subnamespace is rejected, or nil if it is admitted. A subnamespace is
+// rejected if the namespace it is in is its own ancestor, if the namespace it would form is above
+// it, or if it is a workspace that would nest beyond the maximum depth. A subtenant starts a tree
+// of its own, so it is not bound by the depth of its parent.
+func (w *Webhook) Admit(subnamespace *corev1alpha.SubNamespace) error {
+	namespace, err := w.namespacesLister.Get(subnamespace.GetNamespace())
+	if err != nil {
+		return nil
+	}
+	namespaceLabels := namespace.GetLabels()
+	if namespaceLabels["edge-net.io/tenant"] == "" {
+		return nil
+	}
+	ancestors, err := Ancestors(w.get, namespace.GetName())
+	if cycle, ok := err.(*CycleError); ok {
+		return fmt.Errorf("subnamespace cannot be created in a namespace tree with a cycle: %s", cycle)
+	} else if err != nil {
+		klog.ErrorS(err, "Couldn't walk the ancestors of the namespace", "namespace", namespace.GetName())
+		return nil
+	}
+	if childName, err := subnamespace.GenerateChildName(namespaceLabels["edge-net.io/cluster-uid"]); err == nil {
+		for _, ancestor := range ancestors {
+			if ancestor == childName {
+				return fmt.Errorf("subnamespace would form namespace %s, which is above it", childName)
+			}
+		}
+	}
+	// The workspace is a level below the namespace it is in
+	if depth := len(ancestors); subnamespace.GetMode() == "workspace" && w.maxDepth > 0 && depth > w.maxDepth {
+		klog.V(4).InfoS("Rejected the subnamespace beyond the maximum depth", "subNamespace", klog.KObj(subnamespace), "depth", depth)
+		return fmt.Errorf("workspace would nest %d levels below the core namespace of tenant %s, beyond the limit of %d", depth, namespaceLabels["edge-net.io/tenant"], w.maxDepth)
+	}
+	return nil
+}
+
+func (w *Webhook) get(name string) (*corev1.Namespace, error) {
+	return w.namespacesLister.Get(name)
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package index holds the indexers that the controllers add to their shared informers, so that
+// the objects of a tenant are looked up in the cache instead of listed from the API server on
+// every reconcile. The subnamespaces need no index of their own, the listers already reach them
+// by their parent namespace.
+package index
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// ByTenant is the name of the index of the objects by the tenant they are labeled with
+	ByTenant = "byTenant"
+	// LabelTenant is the label naming the tenant of an object
+	LabelTenant = "edge-net.io/tenant"
+)
+
+// Tenant returns the tenant an object is labeled with, the objects of no tenant are left out
+func Tenant(obj interface{}) ([]string, error) {
+	object, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	if tenant, ok := object.GetLabels()[LabelTenant]; ok && tenant != "" {
+		return []string{tenant}, nil
+	}
+	return nil, nil
+}
+
+// AddTenantIndex adds the tenant index to the informer. The informers are shared, so the index
+// is only added once.
+func AddTenantIndex(informer cache.SharedIndexInformer) {
+	if _, ok := informer.GetIndexer().GetIndexers()[ByTenant]; ok {
+		return
+	}
+	utilruntime.Must(informer.AddIndexers(cache.Indexers{ByTenant: Tenant}))
+}
+
+// Namespaces returns the namespaces of the tenant whose labels also match the set
+func Namespaces(indexer cache.Indexer, tenant string, set labels.Set) ([]*corev1.Namespace, error) {
+	objects, err := byTenant(indexer, tenant, set)
+	if err != nil {
+		return nil, err
+	}
+	namespaces := make([]*corev1.Namespace, 0, len(objects))
+	for _, object := range objects {
+		if namespace, ok := object.(*corev1.Namespace); ok {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces, nil
+}
+
+// ClusterRoles returns the cluster roles of the tenant whose labels also match the set
+func ClusterRoles(indexer cache.Indexer, tenant string, set labels.Set) ([]*rbacv1.ClusterRole, error) {
+	objects, err := byTenant(indexer, tenant, set)
+	if err != nil {
+		return nil, err
+	}
+	clusterRoles := make([]*rbacv1.ClusterRole, 0, len(objects))
+	for _, object := range objects {
+		if clusterRole, ok := object.(*rbacv1.ClusterRole); ok {
+			clusterRoles = append(clusterRoles, clusterRole)
+		}
+	}
+	return clusterRoles, nil
+}
+
+// byTenant looks the objects of the tenant up and filters them on the other labels
+func byTenant(indexer cache.Indexer, tenant string, set labels.Set) ([]interface{}, error) {
+	objects, err := indexer.ByIndex(ByTenant, tenant)
+	if err != nil {
+		return nil, err
+	}
+	selector := labels.SelectorFromSet(set)
+	matching := []interface{}{}
+	for _, obj := range objects {
+		object, err := meta.Accessor(obj)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(object.GetLabels())) {
+			matching = append(matching, obj)
+		}
+	}
+	return matching, nil
+}
@@ -0,0 +1,46 @@
+package index
+
+import (
+	"testing"
+
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestTenant(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{ByTenant: Tenant})
+	objects := []interface{}{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "edgenet", Labels: map[string]string{LabelTenant: "edgenet", "edge-net.io/kind": "core"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "edgenet-sub", Labels: map[string]string{LabelTenant: "edgenet", "edge-net.io/kind": "sub"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{LabelTenant: "other", "edge-net.io/kind": "core"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "edgenet:edgenet:tenants:edgenet-owner", Labels: map[string]string{LabelTenant: "edgenet", "edge-net.io/tenant-uid": "uid"}}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "edgenet:other:tenants:other-owner", Labels: map[string]string{LabelTenant: "other", "edge-net.io/tenant-uid": "other-uid"}}},
+	}
+	for _, object := range objects {
+		util.OK(t, indexer.Add(object))
+	}
+
+	namespaces, err := Namespaces(indexer, "edgenet", nil)
+	util.OK(t, err)
+	util.Equals(t, 2, len(namespaces))
+	namespaces, err = Namespaces(indexer, "edgenet", labels.Set{"edge-net.io/kind": "sub"})
+	util.OK(t, err)
+	util.Equals(t, 1, len(namespaces))
+	util.Equals(t, "edgenet-sub", namespaces[0].GetName())
+	namespaces, err = Namespaces(indexer, "missing", nil)
+	util.OK(t, err)
+	util.Equals(t, 0, len(namespaces))
+
+	clusterRoles, err := ClusterRoles(indexer, "edgenet", labels.Set{"edge-net.io/tenant-uid": "uid"})
+	util.OK(t, err)
+	util.Equals(t, 1, len(clusterRoles))
+	clusterRoles, err = ClusterRoles(indexer, "other", labels.Set{"edge-net.io/tenant-uid": "uid"})
+	util.OK(t, err)
+	util.Equals(t, 0, len(clusterRoles))
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mailer
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+	"k8s.io/klog/v2"
+)
+
+// Backends of the chat channels, both are fed through incoming webhooks
+const (
+	slack = "slack"
+	teams = "teams"
+)
+
+// channel is a chat channel that receives the notifications of the events it subscribes to
+type channel struct {
+	Name    string   `yaml:"name"`
+	Backend string   `yaml:"backend"`
+	URL     string   `yaml:"url"`
+	Events  []string `yaml:"events"`
+	// Tenants narrows the channel down to the events concerning these tenants, all if empty
+	Tenants []string `yaml:"tenants"`
+}
+
+// subscribes tells whether the channel receives the notification of the purpose about the tenant
+func (ch channel) subscribes(purpose, tenant string) bool {
+	if !contains(ch.Events, purpose) {
+		return false
+	}
+	return len(ch.Tenants) == 0 || contains(ch.Tenants, tenant)
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	post       = postWebhook
+	httpClient = &http.Client{Timeout: 10 * time.Second}
+)
+
+// getChannels reads the chat channels of the cluster, a cluster without the settings has none
+func getChannels() ([]channel, error) {
+	if flag.Lookup("dir") != nil {
+		dir = flag.Lookup("dir").Value.(flag.Getter).Get().(string)
+	}
+	var path string
+	if flag.Lookup("notification-path") != nil {
+		path = flag.Lookup("notification-path").Value.(flag.Getter).Get().(string)
+	}
+	if path == "" {
+		path = fmt.Sprintf("%s/configs/notification.yaml", dir)
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	settings := struct {
+		Channels []channel `yaml:"channels"`
+	}{}
+	if err := yaml.NewDecoder(file).Decode(&settings); err != nil {
+		return nil, fmt.Errorf("invalid notification settings: %w", err)
+	}
+	for _, ch := range settings.Channels {
+		if ch.Backend != slack && ch.Backend != teams {
+			return nil, fmt.Errorf("unknown backend %q of channel %s", ch.Backend, ch.Name)
+		}
+	}
+	return settings.Channels, nil
+}
+
+// tenant returns the tenant the content is about, if any
+func (c *Content) tenant() string {
+	switch {
+	case c.TenantRequest != nil:
+		return c.TenantRequest.Tenant
+	case c.TenantStatus != nil:
+		return c.TenantStatus.Tenant
+	case c.NetworkIsolation != nil:
+		return c.NetworkIsolation.Tenant
+	case c.QuotaSoftLimit != nil:
+		return c.QuotaSoftLimit.Tenant
+	case c.RoleRequest != nil:
+		return c.RoleRequest.Namespace
+	}
+	return ""
+}
+
+// Notify posts the message of the purpose to the chat channels subscribed to it. The message is
+// the chat template of the purpose, the subject if the template has none.
+func (c *Content) Notify(purpose string) error {
+	channels, err := getChannels()
+	if err != nil {
+		klog.ErrorS(err, "Couldn't read the chat channels")
+		return err
+	}
+	subscribers := []channel{}
+	for _, ch := range channels {
+		if ch.subscribes(purpose, c.tenant()) {
+			subscribers = append(subscribers, ch)
+		}
+	}
+	if len(subscribers) == 0 {
+		return nil
+	}
+	t, err := c.parse(purpose)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't render the chat message", "purpose", purpose)
+		return err
+	}
+	subject := c.Subject
+	if t.Lookup(subjectTemplate) != nil {
+		if subject, err = c.text(t, subjectTemplate); err != nil {
+			return err
+		}
+	}
+	message := ""
+	if t.Lookup(chatTemplate) != nil {
+		if message, err = c.text(t, chatTemplate); err != nil {
+			return err
+		}
+	}
+	for _, ch := range subscribers {
+		if postErr := c.postWithRetries(ch, subject, message); postErr != nil {
+			err = postErr
+		}
+	}
+	return err
+}
+
+// postWithRetries makes up to maxAttempts attempts to post the message to a channel, the backend
+// of the channel is the provider in the delivery metrics
+func (c *Content) postWithRetries(ch channel, subject, message string) error {
+	payload, err := json.Marshal(webhookPayload(ch.Backend, subject, message))
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			sendRetries.WithLabelValues(ch.Backend).Inc()
+			time.Sleep(retryBackoff * time.Duration(attempt-1))
+		}
+		sendAttempts.WithLabelValues(ch.Backend).Inc()
+		if err = post(ch.URL, payload); err == nil {
+			deliveryLatency.Observe(time.Since(start).Seconds())
+			klog.V(4).InfoS("Notification posted", "channel", ch.Name, "subject", subject)
+			return nil
+		}
+		klog.ErrorS(err, "Couldn't post the notification", "channel", ch.Name, "subject", subject)
+		sendFailures.WithLabelValues(ch.Backend).Inc()
+	}
+	return err
+}
+
+// webhookPayload formats the message for the incoming webhook of the backend
+func webhookPayload(backend, subject, message string) interface{} {
+	if backend == teams {
+		return map[string]string{
+			"@type":    "MessageCard",
+			"@context": "http://schema.org/extensions",
+			"summary":  subject,
+			"title":    subject,
+			"text":     message,
+		}
+	}
+	text := fmt.Sprintf("*%s*", subject)
+	if message != "" {
+		text = fmt.Sprintf("%s\n%s", text, message)
+	}
+	return map[string]string{"text": text}
+}
+
+// postWebhook posts the payload to an incoming webhook
+func postWebhook(url string, payload []byte) error {
+	response, err := httpClient.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded %s", response.Status)
+	}
+	return nil
+}
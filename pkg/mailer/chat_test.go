@@ -0,0 +1,125 @@
+package mailer
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// setChannels points the mailer to the notification settings until the test is over
+func setChannels(t *testing.T, settings string) func() {
+	configs, err := ioutil.TempDir("", "notification")
+	util.OK(t, err)
+	path := filepath.Join(configs, "notification.yaml")
+	util.OK(t, ioutil.WriteFile(path, []byte(settings), 0600))
+	if flag.Lookup("notification-path") == nil {
+		flag.String("notification-path", "", "Set the path of the notification settings.")
+	}
+	previous := flag.Lookup("notification-path").Value.String()
+	flag.Set("notification-path", path)
+	return func() {
+		flag.Set("notification-path", previous)
+		os.RemoveAll(configs)
+	}
+}
+
+// webhook records the payloads posted to the channels, keyed by the path of the channel
+type webhook struct {
+	*httptest.Server
+	payloads map[string][]map[string]string
+}
+
+func newWebhook(t *testing.T) *webhook {
+	w := &webhook{payloads: map[string][]map[string]string{}}
+	w.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			http.Error(rw, "channel archived", http.StatusGone)
+			return
+		}
+		payload := map[string]string{}
+		util.OK(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.payloads[r.URL.Path] = append(w.payloads[r.URL.Path], payload)
+	}))
+	return w
+}
+
+func TestNotify(t *testing.T) {
+	server := newWebhook(t)
+	defer server.Close()
+	defer setChannels(t, strings.Replace(`channels:
+- name: admins
+  backend: slack
+  url: URL/admins
+  events: [tenant-request-made, node-down]
+- name: lip6
+  backend: teams
+  url: URL/lip6
+  events: [tenant-request-made, tenant-established]
+  tenants: [lip6]
+`, "URL", server.URL, -1))()
+
+	email := newContent()
+	email.NetworkIsolation = nil
+	email.TenantRequest = &TenantRequest{Tenant: "lip6"}
+	util.OK(t, email.Notify("tenant-request-made"))
+	util.Equals(t, 1, len(server.payloads["/admins"]))
+	util.Equals(t, "*[EdgeNet Admin] A tenant request made*\nJohn Doe (john.doe@edge-net.org) requested the tenant lip6, "+
+		"it awaits the approval of a cluster administrator.", server.payloads["/admins"][0]["text"])
+	util.Equals(t, 1, len(server.payloads["/lip6"]))
+	util.Equals(t, "MessageCard", server.payloads["/lip6"][0]["@type"])
+	util.Equals(t, "[EdgeNet Admin] A tenant request made", server.payloads["/lip6"][0]["title"])
+
+	t.Run("other tenant", func(t *testing.T) {
+		email := newContent()
+		email.NetworkIsolation = nil
+		email.TenantRequest = &TenantRequest{Tenant: "nyu"}
+		util.OK(t, email.Notify("tenant-request-made"))
+		util.Equals(t, 2, len(server.payloads["/admins"]))
+		util.Equals(t, 1, len(server.payloads["/lip6"]))
+	})
+	t.Run("unsubscribed", func(t *testing.T) {
+		util.OK(t, newContent().Notify("role-request-approved"))
+		util.Equals(t, 2, len(server.payloads["/admins"]))
+	})
+	t.Run("chat only", func(t *testing.T) {
+		email := new(Content)
+		email.TenantStatus = &TenantStatus{Tenant: "lip6", State: "Established"}
+		util.OK(t, email.Notify("tenant-established"))
+		util.Equals(t, 2, len(server.payloads["/lip6"]))
+		util.Equals(t, "[EdgeNet] Tenant lip6 established", server.payloads["/lip6"][1]["title"])
+	})
+}
+
+func TestNotifyFailure(t *testing.T) {
+	server := newWebhook(t)
+	defer server.Close()
+	defer setChannels(t, "channels:\n- name: archived\n  backend: slack\n  url: "+server.URL+"/down\n  events: [node-down]\n")()
+	retryBackoff = 0
+	sendAttempts.Reset()
+	sendFailures.Reset()
+
+	err := newContent().Notify("node-down")
+	util.Assert(t, err != nil, "failed post is not reported")
+	util.Equals(t, float64(maxAttempts), testutil.ToFloat64(sendAttempts.WithLabelValues(slack)))
+	util.Equals(t, float64(maxAttempts), testutil.ToFloat64(sendFailures.WithLabelValues(slack)))
+}
+
+func TestChannelSettings(t *testing.T) {
+	defer setChannels(t, "channels:\n- name: irc\n  backend: irc\n  url: irc://edge-net.org\n  events: [node-down]\n")()
+	_, err := getChannels()
+	util.Assert(t, err != nil, "unknown backend is not reported")
+
+	flag.Set("notification-path", "/nonexistent/notification.yaml")
+	channels, err := getChannels()
+	util.OK(t, err)
+	util.Equals(t, 0, len(channels))
+}
@@ -17,17 +17,15 @@ limitations under the License.
 package mailer
 
 import (
-	"bytes"
 	"crypto/tls"
 	"flag"
 	"fmt"
 	"os"
-	"text/template"
 	"time"
 
 	mail "github.com/xhit/go-simple-mail/v2"
 	yaml "gopkg.in/yaml.v2"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 // smtpServer implementation
@@ -51,6 +49,15 @@ type Content struct {
 	TenantRequest       *TenantRequest
 	EmailVerification   *EmailVerification
 	AcceptableUsePolicy *AcceptableUsePolicy
+	NetworkIsolation    *NetworkIsolation
+	NodeContribution    *NodeContribution
+	TenantStatus        *TenantStatus
+	RequestExpiry       *RequestExpiry
+	QuotaSoftLimit      *QuotaSoftLimit
+	// NodeContributions lists the nodes reported together in a digest
+	NodeContributions []NodeContribution
+	// Branding is set from the cluster settings as the email is rendered
+	Branding Branding
 }
 type RoleRequest struct {
 	Name      string
@@ -58,26 +65,122 @@ type RoleRequest struct {
 }
 type TenantRequest struct {
 	Tenant string
+	// Cost is the estimated monthly cost of the requested allocation, with its currency
+	Cost string
+	// Share is the largest share of the cluster capacity that the allocation takes, in percent
+	Share int
+	// DominantResource is the resource type of the largest share
+	DominantResource string
 }
 type EmailVerification struct {
 	Code string
 	URL  string
 }
 type AcceptableUsePolicy struct {
-	Name string
+	Name   string
+	Expiry time.Time
+}
+type NetworkIsolation struct {
+	Tenant string
+	Reason string
+}
+type NodeContribution struct {
+	Name   string
+	Host   string
+	Reason string
+}
+type RequestExpiry struct {
+	Expiry time.Time
+	// Renewable is true until the requester extends the expiry once
+	Renewable bool
+}
+type TenantStatus struct {
+	Tenant  string
+	State   string
+	Message string
+}
+type QuotaSoftLimit struct {
+	Tenant string
+	// SoftLimit is the percentage of the quota the usage crossed
+	SoftLimit int
+	// Resources lists the resources beyond the soft limit with their usage
+	Resources string
 }
 
 var dir = "../.."
 
+// A delivery is attempted maxAttempts times, the attempts are spaced out by a growing backoff
+var (
+	maxAttempts  = 3
+	retryBackoff = 2 * time.Second
+	deliver      = deliverSMTP
+)
+
+// Send notifies the chat channels subscribed to the purpose and emails the recipients
 func (c *Content) Send(purpose string) error {
-	server := mail.NewSMTPClient()
+	notifyErr := c.Notify(purpose)
+	if err := c.email(purpose); err != nil {
+		return err
+	}
+	return notifyErr
+}
 
+// email renders the template of the purpose with the branding of the cluster and delivers it to
+// each recipient, a delivery that keeps failing after the retries is recorded so that it can be
+// resent later
+func (c *Content) email(purpose string) error {
 	// Prepare SMTP server configuration
 	smtpInfo, err := getSMTPInformation()
 	if err != nil {
-		klog.V(4).Infoln(err)
+		klog.ErrorS(err, "Couldn't read the SMTP configuration")
+		return err
+	}
+	_, body, err := c.render(purpose)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't render the email", "purpose", purpose)
 		return err
 	}
+	if c.Branding.Sender != "" {
+		sender := *smtpInfo
+		sender.From = c.Branding.Sender
+		smtpInfo = &sender
+	}
+	if len(c.Recipient) == 0 {
+		c.Recipient = append(c.Recipient, smtpInfo.To)
+	}
+	for _, to := range c.Recipient {
+		if deliveryErr := c.deliverWithRetries(smtpInfo, purpose, to, body); deliveryErr != nil {
+			err = deliveryErr
+		}
+	}
+	return err
+}
+
+// deliverWithRetries makes up to maxAttempts attempts to deliver the email to a recipient
+func (c *Content) deliverWithRetries(smtpInfo *smtpServer, purpose, to string, body []byte) error {
+	start := time.Now()
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			sendRetries.WithLabelValues(smtpInfo.Host).Inc()
+			time.Sleep(retryBackoff * time.Duration(attempt-1))
+		}
+		sendAttempts.WithLabelValues(smtpInfo.Host).Inc()
+		if err = deliver(smtpInfo, to, c.Subject, body); err == nil {
+			deliveryLatency.Observe(time.Since(start).Seconds())
+			klog.V(4).InfoS("Email sent", "recipient", to, "subject", c.Subject)
+			return nil
+		}
+		klog.ErrorS(err, "Couldn't send the email", "recipient", to, "subject", c.Subject)
+		sendFailures.WithLabelValues(smtpInfo.Host).Inc()
+	}
+	failedDeliveries.add(c, purpose, to, smtpInfo.Host, maxAttempts, err)
+	return err
+}
+
+// deliverSMTP sends an email to a recipient through the SMTP server
+func deliverSMTP(smtpInfo *smtpServer, to, subject string, body []byte) error {
+	server := mail.NewSMTPClient()
 	server.Host = smtpInfo.Host
 	server.Port = smtpInfo.Port
 	server.Username = smtpInfo.Username
@@ -87,35 +190,20 @@ func (c *Content) Send(purpose string) error {
 	server.ConnectTimeout = 10 * time.Second
 	server.SendTimeout = 10 * time.Second
 	server.TLSConfig = &tls.Config{InsecureSkipVerify: true}
-	// Prepare SMTP client
+	// Prepare SMTP client, the connection is closed once the email is sent
 	smtpClient, err := server.Connect()
 	if err != nil {
-		klog.V(4).Infoln(err)
 		return err
 	}
-	var htmlBody bytes.Buffer
-	t, _ := template.ParseFiles(fmt.Sprintf("%s/assets/templates/email/%s.html", dir, purpose))
-	t.Execute(&htmlBody, c)
-	if len(c.Recipient) == 0 {
-		c.Recipient = append(c.Recipient, smtpInfo.To)
-	}
-	for _, to := range c.Recipient {
-		email := mail.NewMSG()
-		email.SetFrom(smtpInfo.From).
-			AddTo(to).
-			SetSubject(c.Subject)
-		email.SetBodyData(mail.TextHTML, htmlBody.Bytes())
-		if email.Error != nil {
-			klog.V(4).Infoln(email.Error)
-		}
-		err = email.Send(smtpClient)
-		if err != nil {
-			klog.V(4).Infoln(err)
-		} else {
-			klog.V(4).Infoln(fmt.Sprintf("Email sent to %s: %s", to, c.Subject))
-		}
+	email := mail.NewMSG()
+	email.SetFrom(smtpInfo.From).
+		AddTo(to).
+		SetSubject(subject)
+	email.SetBodyData(mail.TextHTML, body)
+	if email.Error != nil {
+		return email.Error
 	}
-	return err
+	return email.Send(smtpClient)
 }
 
 func getSMTPInformation() (*smtpServer, error) {
@@ -133,31 +221,15 @@ func getSMTPInformation() (*smtpServer, error) {
 	}
 	file, err := os.Open(pathSMTP)
 	if err != nil {
-		klog.V(4).Infof("Mailer: unexpected error executing command: %v", err)
+		klog.ErrorS(err, "Unexpected error executing the mailer")
 		return nil, err
 	}
 	decoder := yaml.NewDecoder(file)
 	var smtpServer smtpServer
 	err = decoder.Decode(&smtpServer)
 	if err != nil {
-		klog.V(4).Infof("Mailer: unexpected error executing command: %v", err)
+		klog.ErrorS(err, "Unexpected error executing the mailer")
 		return nil, err
 	}
 	return &smtpServer, nil
 }
-
-// console implementation
-/*type console struct {
-	URL string `yaml:"url"`
-}
-file, err := os.Open(fmt.Sprintf("%s/configs/console.yaml", dir))
-if err != nil {
-	log.Printf("Mailer: unexpected error executing command: %v", err)
-}
-decoder := yaml.NewDecoder(file)
-var console console
-err = decoder.Decode(&console)
-if err != nil {
-	log.Printf("Mailer: unexpected error executing command: %v", err)
-}
-verificationData.URL = console.URL*/
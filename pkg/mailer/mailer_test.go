@@ -11,7 +11,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	yaml "gopkg.in/yaml.v2"
-	"k8s.io/klog"
+	"k8s.io/klog/v2"
 )
 
 func TestMain(m *testing.M) {
@@ -0,0 +1,157 @@
+/*
+Copyright 2022 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mailer
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/metrics"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Upper bounds of the delivery latency histogram, in seconds
+var latencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// The number of failed deliveries kept for the admin endpoint, the oldest are dropped first
+const failedDeliveryLimit = 100
+
+// The delivery attempts, failures, and retries are counted by SMTP provider or chat backend
+var (
+	sendAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "edgenet_notifier_send_attempts_total", Help: "Number of attempts to deliver an email."}, []string{"provider"})
+	sendFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "edgenet_notifier_send_failures_total", Help: "Number of failed attempts to deliver an email."}, []string{"provider"})
+	sendRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "edgenet_notifier_send_retries_total", Help: "Number of attempts made after a failed one."}, []string{"provider"})
+	deliveryLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name: "edgenet_notifier_delivery_latency_seconds", Help: "Time from the first attempt to the delivery of an email.", Buckets: latencyBuckets})
+)
+
+func init() {
+	metrics.Registry.MustRegister(sendAttempts, sendFailures, sendRetries, deliveryLatency,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: "edgenet_notifier_failed_deliveries", Help: "Number of recent deliveries that failed after all attempts."},
+			func() float64 { return float64(len(failedDeliveries.list())) }))
+}
+
+// ErrDeliveryNotFound is returned when a failed delivery to resend is not in the log anymore
+var ErrDeliveryNotFound = errors.New("failed delivery not found")
+
+// FailedDelivery is an email that could not be delivered to a recipient after all attempts
+type FailedDelivery struct {
+	ID        string    `json:"id"`
+	Purpose   string    `json:"purpose"`
+	Recipient string    `json:"recipient"`
+	Subject   string    `json:"subject"`
+	Provider  string    `json:"provider"`
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error"`
+	Time      time.Time `json:"time"`
+	content   Content
+}
+
+type failedDeliveryLog struct {
+	sync.Mutex
+	deliveries []FailedDelivery
+}
+
+var failedDeliveries = &failedDeliveryLog{}
+
+func (l *failedDeliveryLog) add(c *Content, purpose, to, provider string, attempts int, err error) {
+	l.Lock()
+	defer l.Unlock()
+	delivery := FailedDelivery{ID: uuid.New().String(), Purpose: purpose, Recipient: to, Subject: c.Subject,
+		Provider: provider, Attempts: attempts, Time: time.Now(), content: *c}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	l.deliveries = append(l.deliveries, delivery)
+	if len(l.deliveries) > failedDeliveryLimit {
+		l.deliveries = l.deliveries[len(l.deliveries)-failedDeliveryLimit:]
+	}
+}
+
+// list returns the failed deliveries, the most recent first
+func (l *failedDeliveryLog) list() []FailedDelivery {
+	l.Lock()
+	defer l.Unlock()
+	deliveries := make([]FailedDelivery, 0, len(l.deliveries))
+	for i := len(l.deliveries) - 1; i >= 0; i-- {
+		deliveries = append(deliveries, l.deliveries[i])
+	}
+	return deliveries
+}
+
+// take removes a failed delivery from the log and returns it
+func (l *failedDeliveryLog) take(id string) (FailedDelivery, bool) {
+	l.Lock()
+	defer l.Unlock()
+	for i, delivery := range l.deliveries {
+		if delivery.ID == id {
+			l.deliveries = append(l.deliveries[:i], l.deliveries[i+1:]...)
+			return delivery, true
+		}
+	}
+	return FailedDelivery{}, false
+}
+
+// Resend delivers a failed email again to its recipient, it is recorded anew if it fails again
+func Resend(id string) error {
+	delivery, ok := failedDeliveries.take(id)
+	if !ok {
+		return ErrDeliveryNotFound
+	}
+	content := delivery.content
+	content.Recipient = []string{delivery.Recipient}
+	return content.email(delivery.Purpose)
+}
+
+// AdminHandler lists the recent failed deliveries on GET /deliveries/failed and
+// resends one of them on POST /deliveries/failed/<id>/resend
+func AdminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/deliveries/failed", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(failedDeliveries.list())
+	})
+	mux.HandleFunc("/deliveries/failed/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/deliveries/failed/"), "/resend")
+		if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/resend") || id == "" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := Resend(id); err == ErrDeliveryNotFound {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
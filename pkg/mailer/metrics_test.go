@@ -0,0 +1,126 @@
+package mailer
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/metrics"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+)
+
+func TestDeliveryMetrics(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mailer")
+	util.OK(t, err)
+	defer os.RemoveAll(dir)
+	smtpPath := filepath.Join(dir, "smtp.yaml")
+	ioutil.WriteFile(smtpPath, []byte("host: smtp.edge-net.org\nport: 587\nfrom: no-reply@edge-net.org\nto: support@edge-net.org\n"), 0600)
+	previousPath := flag.Lookup("smtp-path").Value.String()
+	flag.Set("smtp-path", smtpPath)
+	defer flag.Set("smtp-path", previousPath)
+	retryBackoff = 0
+	sendAttempts.Reset()
+	sendFailures.Reset()
+	sendRetries.Reset()
+	failedDeliveries = &failedDeliveryLog{}
+	delivered, _ := latency(t)
+
+	// The mailbox of the recipient is down until it is fixed
+	down := map[string]bool{"joe.public@edge-net.org": true}
+	sent := []string{}
+	deliver = func(smtpInfo *smtpServer, to, subject string, body []byte) error {
+		if down[to] {
+			return fmt.Errorf("mailbox of %s unavailable", to)
+		}
+		sent = append(sent, to)
+		return nil
+	}
+	defer func() { deliver = deliverSMTP }()
+
+	email := new(Content)
+	email.Subject = "Tenant Isolation Degraded"
+	email.Recipient = []string{"john.doe@edge-net.org", "joe.public@edge-net.org"}
+	email.NetworkIsolation = &NetworkIsolation{Tenant: "edgenet", Reason: "no network policy support"}
+	err = email.Send("tenant-isolation-degraded")
+	util.Assert(t, err != nil, "failed delivery is not reported")
+	util.Equals(t, []string{"john.doe@edge-net.org"}, sent)
+
+	t.Run("metrics", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		exposition := recorder.Body.String()
+		for _, expected := range []string{
+			`edgenet_notifier_send_attempts_total{provider="smtp.edge-net.org"} 4`,
+			`edgenet_notifier_send_failures_total{provider="smtp.edge-net.org"} 3`,
+			`edgenet_notifier_send_retries_total{provider="smtp.edge-net.org"} 2`,
+			fmt.Sprintf("edgenet_notifier_delivery_latency_seconds_count %d", delivered+1),
+			`edgenet_notifier_failed_deliveries 1`,
+		} {
+			util.Assert(t, strings.Contains(exposition, expected), fmt.Sprintf("%s is missing", expected))
+		}
+	})
+
+	var deliveries []FailedDelivery
+	t.Run("failed deliveries", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		AdminHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/deliveries/failed", nil))
+		util.Equals(t, http.StatusOK, recorder.Code)
+		util.OK(t, json.NewDecoder(recorder.Body).Decode(&deliveries))
+		util.Equals(t, 1, len(deliveries))
+		util.Equals(t, "joe.public@edge-net.org", deliveries[0].Recipient)
+		util.Equals(t, "tenant-isolation-degraded", deliveries[0].Purpose)
+		util.Equals(t, maxAttempts, deliveries[0].Attempts)
+	})
+	t.Run("resend", func(t *testing.T) {
+		down["joe.public@edge-net.org"] = false
+		recorder := httptest.NewRecorder()
+		AdminHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, fmt.Sprintf("/deliveries/failed/%s/resend", deliveries[0].ID), nil))
+		util.Equals(t, http.StatusNoContent, recorder.Code)
+		util.Equals(t, []string{"john.doe@edge-net.org", "joe.public@edge-net.org"}, sent)
+		util.Equals(t, 0, len(failedDeliveries.list()))
+
+		recorder = httptest.NewRecorder()
+		AdminHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, fmt.Sprintf("/deliveries/failed/%s/resend", deliveries[0].ID), nil))
+		util.Equals(t, http.StatusNotFound, recorder.Code)
+	})
+}
+
+// latency returns the number of deliveries observed by the latency histogram along with the
+// cumulative count of each bucket
+func latency(t *testing.T) (uint64, []uint64) {
+	families, err := metrics.Registry.Gather()
+	util.OK(t, err)
+	for _, family := range families {
+		if family.GetName() == "edgenet_notifier_delivery_latency_seconds" {
+			histogram := family.GetMetric()[0].GetHistogram()
+			buckets := []uint64{}
+			for _, bucket := range histogram.GetBucket() {
+				buckets = append(buckets, bucket.GetCumulativeCount())
+			}
+			return histogram.GetSampleCount(), buckets
+		}
+	}
+	t.Fatal("latency histogram not registered")
+	return 0, nil
+}
+
+func TestLatencyBuckets(t *testing.T) {
+	_, before := latency(t)
+	deliveryLatency.Observe((300 * time.Millisecond).Seconds())
+	deliveryLatency.Observe((3 * time.Second).Seconds())
+	_, after := latency(t)
+	observed := []uint64{}
+	for i := range after {
+		observed = append(observed, after[i]-before[i])
+	}
+	// The buckets are cumulative
+	util.Equals(t, []uint64{0, 0, 1, 1, 1, 2, 2, 2, 2}, observed)
+}
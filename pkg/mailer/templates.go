@@ -0,0 +1,180 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mailer
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"html"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// The templates of an email are looked up in the template directory first, which is where the
+// cluster mounts the ConfigMap of its templates, and then among the built-in ones
+const (
+	layoutTemplate   = "layout.html"
+	brandingFile     = "branding.yaml"
+	contentTemplate  = "content"
+	subjectTemplate  = "subject"
+	chatTemplate     = "chat"
+	builtinTemplates = "assets/templates/email"
+)
+
+// Branding holds the variables that set an email apart from one cluster to another
+type Branding struct {
+	// Name of the cluster as it appears in the emails
+	Name string `yaml:"name"`
+	// Sender address of the emails, the one of the SMTP settings if empty
+	Sender string `yaml:"sender"`
+	// ConsoleURL is where the users manage their tenants and requests
+	ConsoleURL   string `yaml:"consoleurl"`
+	WebsiteURL   string `yaml:"websiteurl"`
+	LogoURL      string `yaml:"logourl"`
+	SupportURL   string `yaml:"supporturl"`
+	SupportEmail string `yaml:"supportemail"`
+	// Signature closes the emails, such as the name of the support team
+	Signature string `yaml:"signature"`
+	// Footer lists the paragraphs at the bottom of the emails
+	Footer []string `yaml:"footer"`
+}
+
+// DefaultBranding returns the branding of EdgeNet, a cluster overrides the parts it needs to
+func DefaultBranding() Branding {
+	return Branding{
+		Name:         "EdgeNet",
+		WebsiteURL:   "https://edge-net.org",
+		LogoURL:      "https://www.edge-net.org/assets/images/edgenet_logo_2020_05_03_w_text_075dpi.png",
+		SupportURL:   "https://edge-net.org/support.html",
+		SupportEmail: "edgenet-support@planet-lab.eu",
+		Signature:    "The EdgeNet Support Team at PlanetLab Europe",
+		Footer: []string{
+			"©2020 Sorbonne University on behalf of the EdgeNet partners.",
+			"EdgeNet is operated by PlanetLab Europe on behalf of the EdgeNet partners.",
+			"EdgeNet is a joint project of US Ignite, the LIP6 lab at Sorbonne University, the NYU Tandon School of Engineering, " +
+				"the Swarm Lab at UC Berkeley, the Computer Science department at the University of Victoria, the University of Vienna, and Cslash.",
+		},
+	}
+}
+
+// templateDir returns the directory of the templates and the branding of the cluster
+func templateDir() string {
+	if flag.Lookup("mail-templates") != nil {
+		if path := flag.Lookup("mail-templates").Value.(flag.Getter).Get().(string); path != "" {
+			return path
+		}
+	}
+	return fmt.Sprintf("%s/mail", dir)
+}
+
+// templatePath returns the template of the cluster if there is one, the built-in template otherwise
+func templatePath(name string) string {
+	path := filepath.Join(templateDir(), name)
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+	return filepath.Join(dir, builtinTemplates, name)
+}
+
+// loadBranding reads the branding of the cluster over the default one, the console URL of the
+// console settings is used unless the branding has its own
+func loadBranding() (Branding, error) {
+	branding := DefaultBranding()
+	if raw, err := ioutil.ReadFile(fmt.Sprintf("%s/configs/console.yaml", dir)); err == nil {
+		console := struct {
+			URL string `yaml:"url"`
+		}{}
+		if err := yaml.Unmarshal(raw, &console); err == nil {
+			branding.ConsoleURL = console.URL
+		}
+	}
+	raw, err := ioutil.ReadFile(filepath.Join(templateDir(), brandingFile))
+	if os.IsNotExist(err) {
+		return branding, nil
+	} else if err != nil {
+		return branding, err
+	}
+	if err := yaml.Unmarshal(raw, &branding); err != nil {
+		return branding, fmt.Errorf("invalid %s: %s", brandingFile, err)
+	}
+	return branding, nil
+}
+
+// parse loads the branding of the cluster into the content and parses the template of the purpose,
+// along with the layout if the template defines its content
+func (c *Content) parse(purpose string) (*template.Template, error) {
+	branding, err := loadBranding()
+	if err != nil {
+		return nil, err
+	}
+	c.Branding = branding
+
+	name := fmt.Sprintf("%s.html", purpose)
+	t, err := template.New(name).ParseFiles(templatePath(name))
+	if err != nil {
+		return nil, err
+	}
+	if t.Lookup(contentTemplate) != nil {
+		if _, err := t.ParseFiles(templatePath(layoutTemplate)); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// text executes a template that renders plain text, such as the subject. The template escapes
+// its output as HTML, which a mail or chat client would show as entities.
+func (c *Content) text(t *template.Template, name string) (string, error) {
+	var buffer bytes.Buffer
+	if err := t.ExecuteTemplate(&buffer, name, c); err != nil {
+		return "", err
+	}
+	return strings.Join(strings.Fields(html.UnescapeString(buffer.String())), " "), nil
+}
+
+// render executes the template of the purpose with the content, and returns the subject and the
+// body of the email. A template that defines its content is wrapped in the layout, otherwise it
+// is a whole document of its own. The subject of the content is kept unless the template defines one.
+func (c *Content) render(purpose string) (string, []byte, error) {
+	t, err := c.parse(purpose)
+	if err != nil {
+		return "", nil, err
+	}
+	entry := t.Name()
+	if t.Lookup(contentTemplate) != nil {
+		entry = layoutTemplate
+	}
+	subject := c.Subject
+	if t.Lookup(subjectTemplate) != nil {
+		if subject, err = c.text(t, subjectTemplate); err != nil {
+			return "", nil, err
+		}
+	}
+	// The layout shows the subject as the title of the document
+	c.Subject = subject
+	var body bytes.Buffer
+	if err := t.ExecuteTemplate(&body, entry, c); err != nil {
+		return "", nil, err
+	}
+	return subject, body.Bytes(), nil
+}
@@ -0,0 +1,138 @@
+package mailer
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+)
+
+func newContent() *Content {
+	email := new(Content)
+	email.Cluster = "cluster-uid"
+	email.User = "john.doe@edge-net.org"
+	email.FirstName = "John"
+	email.LastName = "Doe"
+	email.Subject = "Subject of the caller"
+	email.Recipient = []string{"john.doe@edge-net.org"}
+	email.RoleRequest = &RoleRequest{Name: "johndoe", Namespace: "edgenet"}
+	email.TenantRequest = &TenantRequest{Tenant: "edgenet"}
+	email.AcceptableUsePolicy = &AcceptableUsePolicy{Name: "johndoe", Expiry: time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC)}
+	email.NetworkIsolation = &NetworkIsolation{Tenant: "edgenet", Reason: "no network policy support"}
+	email.QuotaSoftLimit = &QuotaSoftLimit{Tenant: "edgenet", SoftLimit: 80, Resources: "cpu 85%"}
+	email.NodeContribution = &NodeContribution{Name: "ple-1", Host: "10.0.0.1", Reason: "Kubelet stopped posting node status."}
+	email.NodeContributions = []NodeContribution{*email.NodeContribution, {Name: "ple-2", Host: "10.0.0.2", Reason: "Kubelet stopped posting node status."}}
+	email.RequestExpiry = &RequestExpiry{Expiry: time.Date(2021, time.June, 1, 0, 0, 0, 0, time.UTC), Renewable: true}
+	email.EmailVerification = &EmailVerification{Code: "1633338000.c2lnbmF0dXJl", URL: "/verify/tenantrequests/edgenet?code=1633338000.c2lnbmF0dXJl"}
+	return email
+}
+
+// setTemplateDir points the mailer to the templates of a cluster until the test is over
+func setTemplateDir(t *testing.T, files map[string]string) func() {
+	templates, err := ioutil.TempDir("", "mail")
+	util.OK(t, err)
+	for name, content := range files {
+		util.OK(t, ioutil.WriteFile(filepath.Join(templates, name), []byte(content), 0644))
+	}
+	if flag.Lookup("mail-templates") == nil {
+		flag.String("mail-templates", "", "Set the directory of the email templates.")
+	}
+	previous := flag.Lookup("mail-templates").Value.String()
+	flag.Set("mail-templates", templates)
+	return func() {
+		flag.Set("mail-templates", previous)
+		os.RemoveAll(templates)
+	}
+}
+
+func TestBuiltinTemplates(t *testing.T) {
+	cases := map[string]string{
+		"tenant-request-made":             "[EdgeNet Admin] A tenant request made",
+		"tenant-request-approved":         "[EdgeNet] Tenant request approved",
+		"role-request-made":               "[EdgeNet] A role request made",
+		"role-request-approved":           "[EdgeNet] Role request approved",
+		"acceptable-use-policy-reminder":  "[EdgeNet] Acceptable use policy reminder",
+		"node-down":                       "[EdgeNet] Node ple-1 is down",
+		"node-down-digest":                "[EdgeNet] 2 nodes are down",
+		"node-disk-pressure":              "[EdgeNet] Node ple-1 is at disk pressure",
+		"tenant-isolation-degraded":       "[EdgeNet] Tenant network isolation degraded",
+		"tenant-quota-soft-limit":         "[EdgeNet] Tenant quota soft limit exceeded",
+		"tenant-email-verification":       "[EdgeNet] Verify email address",
+		"role-request-email-verification": "[EdgeNet] Verify email address",
+		"tenant-request-expiry-reminder":  "[EdgeNet] Tenant request expiring",
+		"role-request-expiry-reminder":    "[EdgeNet] Role request expiring",
+	}
+	for purpose, expected := range cases {
+		t.Run(purpose, func(t *testing.T) {
+			email := newContent()
+			subject, body, err := email.render(purpose)
+			util.OK(t, err)
+			util.Equals(t, expected, subject)
+			util.Equals(t, expected, email.Subject)
+			util.Assert(t, strings.Contains(string(body), "Dear "), "greeting not rendered")
+			util.Assert(t, strings.Contains(string(body), "edgenet-support@planet-lab.eu"), "branding not rendered")
+		})
+	}
+	t.Run("missing", func(t *testing.T) {
+		_, _, err := newContent().render("unknown-purpose")
+		util.Assert(t, err != nil, "missing template is not reported")
+	})
+}
+
+func TestClusterBranding(t *testing.T) {
+	defer setTemplateDir(t, map[string]string{
+		brandingFile: "name: FabNet\nsender: no-reply@fabnet.org\nconsoleurl: https://console.fabnet.org\n" +
+			"supportemail: support@fabnet.org\nfooter:\n- FabNet is operated by the FabNet team.\n",
+	})()
+
+	email := newContent()
+	subject, body, err := email.render("tenant-request-approved")
+	util.OK(t, err)
+	util.Equals(t, "[FabNet] Tenant request approved", subject)
+	util.Equals(t, "no-reply@fabnet.org", email.Branding.Sender)
+	for _, expected := range []string{"https://console.fabnet.org", "mailto:support@fabnet.org", "FabNet is operated by the FabNet team."} {
+		util.Assert(t, strings.Contains(string(body), expected), "%s not rendered", expected)
+	}
+	util.Assert(t, !strings.Contains(string(body), "PlanetLab Europe on behalf"), "default footer rendered")
+	// The parts the cluster leaves out are kept from the default branding
+	util.Equals(t, DefaultBranding().LogoURL, email.Branding.LogoURL)
+}
+
+func TestClusterTemplate(t *testing.T) {
+	defer setTemplateDir(t, map[string]string{
+		"node-down.html": `{{define "subject"}}{{.NodeContribution.Name}} & co. down{{end}}{{define "content"}}<p>{{.NodeContribution.Reason}}</p>{{end}}`,
+	})()
+
+	subject, body, err := newContent().render("node-down")
+	util.OK(t, err)
+	util.Equals(t, "ple-1 & co. down", subject)
+	util.Assert(t, strings.Contains(string(body), "<p>Kubelet stopped posting node status.</p>"), "template of the cluster not rendered")
+	// The built-in layout wraps the content of the cluster template
+	util.Assert(t, strings.Contains(string(body), "edgenet-support@planet-lab.eu"), "layout not rendered")
+
+	_, _, err = newContent().render("role-request-made")
+	util.OK(t, err)
+}
+
+func TestDocumentTemplate(t *testing.T) {
+	defer setTemplateDir(t, map[string]string{
+		"legacy.html": `<!DOCTYPE html><html><body>Dear {{.FirstName}} {{.LastName}}</body></html>`,
+	})()
+
+	// A template without content is a whole document, the subject of the caller is kept
+	subject, body, err := newContent().render("legacy")
+	util.OK(t, err)
+	util.Equals(t, "Subject of the caller", subject)
+	util.Equals(t, "<!DOCTYPE html><html><body>Dear John Doe</body></html>", string(body))
+}
+
+func TestInvalidBranding(t *testing.T) {
+	defer setTemplateDir(t, map[string]string{brandingFile: "footer: [unclosed"})()
+	_, _, err := newContent().render("node-down")
+	util.Assert(t, err != nil, "invalid branding is not reported")
+}
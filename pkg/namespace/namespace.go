@@ -18,24 +18,24 @@ package namespace
 
 import (
 	"context"
-	"log"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
 )
 
 // Clientset to be synced by the custom resources
 var Clientset kubernetes.Interface
 
 // List uses clientset, this function provides the list of namespaces by eliminating "default", "kube-system", and "kube-public"
-func List() []string {
+func List(ctx context.Context) []string {
 	// FieldSelector allows getting filtered results
-	namespaceRaw, err := Clientset.CoreV1().Namespaces().List(context.TODO(),
+	namespaceRaw, err := Clientset.CoreV1().Namespaces().List(ctx,
 		metav1.ListOptions{FieldSelector: "metadata.name!=default,metadata.name!=kube-system,metadata.name!=kube-public"})
 	if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't list the namespaces")
 		panic(err.Error())
 	}
 	namespaces := make([]string, len(namespaceRaw.Items))
@@ -46,19 +46,19 @@ func List() []string {
 }
 
 // GetNamespace uses clientset to get namespace requested
-func GetNamespace(name string) (*corev1.Namespace, error) {
+func GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
 	// Examples for error handling:
 	// - Use helper functions like e.g. errors.IsNotFound()
 	// - And/or cast to StatusError and use its properties like e.g. ErrStatus.Message
-	namespace, err := Clientset.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+	namespace, err := Clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
 	if errors.IsNotFound(err) {
-		log.Printf("Namespace %s not found", name)
+		klog.InfoS("Namespace not found", "name", name)
 		return nil, err
 	} else if statusError, isStatus := err.(*errors.StatusError); isStatus {
-		log.Printf("Error getting namespace %s: %v", name, statusError.ErrStatus)
+		klog.InfoS("Error getting namespace", "name", name, "errStatus", statusError.ErrStatus)
 		return nil, err
 	} else if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't get the namespace", "namespace", name)
 		panic(err.Error())
 	}
 	return namespace, nil
@@ -42,13 +42,13 @@ func TestOperations(t *testing.T) {
 	}
 	for k, tc := range cases {
 		t.Run(fmt.Sprintf("get case %d", k), func(t *testing.T) {
-			_, err := GetNamespace(tc.namespace)
+			_, err := GetNamespace(context.TODO(), tc.namespace)
 			util.Equals(t, tc.expected, !errors.IsNotFound(err))
 		})
 	}
 
 	t.Run("list", func(t *testing.T) {
-		namespaceList := List()
+		namespaceList := List(context.TODO())
 		util.Equals(t, list, namespaceList)
 	})
 }
@@ -22,7 +22,6 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
@@ -37,6 +36,7 @@ import (
 	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
 	bootstraputil "k8s.io/cluster-bootstrap/token/util"
 
+	"k8s.io/klog/v2"
 	//nodebootstraptokenphase "k8s.io/kubernetes/cmd/kubeadm/app/phases/bootstraptoken/node"
 
 	kubeadmtypes "sigs.k8s.io/cluster-api/bootstrap/kubeadm/types/v1beta1"
@@ -44,15 +44,15 @@ import (
 
 // CreateToken creates the token to be used to add node
 // and return the token
-func CreateToken(clientset kubernetes.Interface, duration time.Duration, hostname string) (string, error) {
+func CreateToken(ctx context.Context, clientset kubernetes.Interface, duration time.Duration, hostname string) (string, error) {
 	tokenStr, err := bootstraputil.GenerateBootstrapToken()
 	if err != nil {
-		log.Printf("Error generating token to upload certs: %s", err)
+		klog.ErrorS(err, "Error generating token to upload certs")
 		return "", err
 	}
 	token, err := kubeadmtypes.NewBootstrapTokenString(tokenStr)
 	if err != nil {
-		log.Printf("Error creating upload certs token: %s", err)
+		klog.ErrorS(err, "Error creating upload certs token")
 		return "", err
 	}
 	bootstrapToken := kubeadmtypes.BootstrapToken{}
@@ -72,7 +72,7 @@ func CreateToken(clientset kubernetes.Interface, duration time.Duration, hostnam
 		Usages: []string{"authentication", "signing"},
 		Groups: []string{"system:bootstrappers:kubeadm:default-node-token"},
 	}}*/
-	secret, err := clientset.CoreV1().Secrets(metav1.NamespaceSystem).Get(context.TODO(), token.ID, metav1.GetOptions{})
+	secret, err := clientset.CoreV1().Secrets(metav1.NamespaceSystem).Get(ctx, token.ID, metav1.GetOptions{})
 	if secret != nil && err == nil {
 		return "", err
 	}
@@ -85,25 +85,25 @@ func CreateToken(clientset kubernetes.Interface, duration time.Duration, hostnam
 		Data: encodeTokenSecretData(bootstrapToken.DeepCopy(), time.Now()),
 	}
 
-	if _, err := clientset.CoreV1().Secrets(secret.ObjectMeta.Namespace).Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil {
+	if _, err := clientset.CoreV1().Secrets(secret.ObjectMeta.Namespace).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
 		if !errors.IsAlreadyExists(err) {
 			return "", err
 		}
 
-		if _, err := clientset.CoreV1().Secrets(secret.ObjectMeta.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		if _, err := clientset.CoreV1().Secrets(secret.ObjectMeta.Namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
 			return "", err
 		}
 	}
 
 	/*if err := nodebootstraptokenphase.CreateNewTokens(clientset, tokens); err != nil {
-		log.Printf("Error creating token: %s", err)
+		klog.ErrorS(err, "Error creating token")
 		return "", err
 	}*/
 
 	// This reads server info of the current context from the config file
 	server, err := util.GetServerOfCurrentContext()
 	if err != nil {
-		log.Println(err)
+		klog.ErrorS(err, "Couldn't read the server of the current context")
 		return "", err
 	}
 	server = strings.Trim(server, "https://")
@@ -118,7 +118,7 @@ func CreateToken(clientset kubernetes.Interface, duration time.Duration, hostnam
 	// This reads CA cert to be hashed
 	certs, err := cert.CertsFromFile(pathCA)
 	if err != nil {
-		log.Println(err)
+		klog.ErrorS(err, "Couldn't read the certificate authority")
 		return "", err
 	}
 	var CA string
@@ -136,12 +136,12 @@ func CreateToken(clientset kubernetes.Interface, duration time.Duration, hostnam
 func getHosts(client *namecheap.Client) namecheap.DomainDNSGetHostsResult {
 	hostsResponse, err := client.DomainsDNSGetHosts("edge-net", "io")
 	if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't get the hosts")
 		return namecheap.DomainDNSGetHostsResult{}
 	}
 	responseJSON, err := json.Marshal(hostsResponse)
 	if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't marshal the hosts")
 		return namecheap.DomainDNSGetHostsResult{}
 	}
 	hostList := namecheap.DomainDNSGetHostsResult{}
@@ -157,7 +157,7 @@ func SetHostname(client *namecheap.Client, hostRecord namecheap.DomainDNSHost) (
 		if host.Name == hostRecord.Name || host.Address == hostRecord.Address {
 			// If the record exist then update it, overwrite it with new name and address
 			hostList.Hosts[key] = hostRecord
-			log.Printf("Update existing host: %s - %s \n Hostname  and ip address changed to: %s - %s", host.Name, host.Address, hostRecord.Name, hostRecord.Address)
+			klog.InfoS("Updating the existing host", "host", host.Name, "address", host.Address, "newHost", hostRecord.Name, "newAddress", hostRecord.Address)
 			exist = true
 			break
 		}
@@ -168,11 +168,11 @@ func SetHostname(client *namecheap.Client, hostRecord namecheap.DomainDNSHost) (
 	}
 	setResponse, err := client.DomainDNSSetHosts("edge-net", "io", hostList.Hosts)
 	if err != nil {
-		log.Println(err.Error())
-		log.Printf("Set host failed: %s - %s", hostRecord.Name, hostRecord.Address)
+		klog.ErrorS(err, "Couldn't set the hosts")
+		klog.InfoS("Set host failed", "host", hostRecord.Name, "address", hostRecord.Address)
 		return false, "failed"
 	} else if setResponse.IsSuccess == false {
-		log.Printf("Set host unknown problem: %s - %s", hostRecord.Name, hostRecord.Address)
+		klog.InfoS("Set host unknown problem", "host", hostRecord.Name, "address", hostRecord.Address)
 		return false, "unknown"
 	}
 	return true, ""
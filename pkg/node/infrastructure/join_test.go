@@ -1,6 +1,7 @@
 package infrastructure
 
 import (
+	"context"
 	"flag"
 	"io/ioutil"
 	"log"
@@ -26,6 +27,6 @@ func TestMain(m *testing.M) {
 func TestCreateJoinToken(t *testing.T) {
 	ttl, err := time.ParseDuration("600s")
 	util.OK(t, err)
-	_, err = CreateToken(testclient.NewSimpleClientset(), ttl, "test.edgenet.io")
+	_, err = CreateToken(context.TODO(), testclient.NewSimpleClientset(), ttl, "test.edgenet.io")
 	util.OK(t, err)
 }
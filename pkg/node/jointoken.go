@@ -0,0 +1,90 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/node/infrastructure"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+// BootstrapTokenTTL is how long the bootstrap token of a join command is valid, the node joins
+// right after the exchange of its join token
+var BootstrapTokenTTL = 15 * time.Minute
+
+// NewJoinToken returns a one-time join token for the node contribution along with its hash. The
+// token names the node contribution, its audience, and only verifies against its hash.
+func NewJoinToken(nodecontribution string) (string, string, error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	token := fmt.Sprintf("%s.%s", nodecontribution, hex.EncodeToString(secret))
+	return token, HashJoinToken(token), nil
+}
+
+// HashJoinToken returns the hash of a join token kept in the status of the node contribution
+func HashJoinToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// CheckJoinToken returns why the join token is not accepted for the node contribution, or nil if
+// it can be exchanged for the join command
+func CheckJoinToken(nodecontribution *corev1alpha.NodeContribution, token string, now time.Time) error {
+	join := nodecontribution.Status.Join
+	if nodecontribution.Spec.Mode != "Token" || join == nil {
+		return fmt.Errorf("node contribution %s does not join with a token", nodecontribution.GetName())
+	}
+	// The token of another node contribution is refused before its hash is even compared
+	if !strings.HasPrefix(token, nodecontribution.GetName()+".") {
+		return fmt.Errorf("token is not issued to node contribution %s", nodecontribution.GetName())
+	}
+	if subtle.ConstantTimeCompare([]byte(HashJoinToken(token)), []byte(join.TokenHash)) != 1 {
+		return fmt.Errorf("invalid token")
+	}
+	if join.Used != nil {
+		return fmt.Errorf("token already used")
+	}
+	if !now.Before(join.Expires.Time) {
+		return fmt.Errorf("token expired")
+	}
+	return nil
+}
+
+// JoinCommand returns the kubeadm join command of the node with a fresh bootstrap token, the node
+// registers under its name in the cluster
+func JoinCommand(ctx context.Context, clientset kubernetes.Interface, nodeName string) (string, error) {
+	command, err := infrastructure.CreateToken(ctx, clientset, BootstrapTokenTTL, nodeName)
+	if err != nil {
+		return "", err
+	}
+	if command == "" {
+		return "", fmt.Errorf("bootstrap token of %s not created", nodeName)
+	}
+	return fmt.Sprintf("%s --node-name %s", command, nodeName), nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubelet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// ConfigPath is the configuration file of the kubelet, reached through the root of the host as the
+// agent shares the process namespace of the host
+var ConfigPath = "/proc/1/root/var/lib/kubelet/config.yaml"
+
+// Interval is the time between two checks of the settings of the node
+var Interval = 30 * time.Second
+
+// backupSuffix is appended to the configuration file found on the node before any configuration,
+// the settings are merged into it and it is put back once the node takes no configuration
+const backupSuffix = ".edgenet-original"
+
+// Restart restarts the kubelet of the host
+var Restart = func(ctx context.Context) error {
+	output, err := exec.CommandContext(ctx, "nsenter", "--target", "1", "--mount", "--uts", "--ipc", "--net", "--pid",
+		"--", "systemctl", "restart", "kubelet").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, output)
+	}
+	return nil
+}
+
+// Agent applies the settings the node is annotated with to the kubelet of the node it runs on
+type Agent struct {
+	// kubeclientset is a standard kubernetes clientset
+	kubeclientset kubernetes.Interface
+	// nodeName is the node the agent runs on
+	nodeName string
+}
+
+// NewAgent returns a new agent for the node
+func NewAgent(kubeclientset kubernetes.Interface, nodeName string) *Agent {
+	return &Agent{kubeclientset: kubeclientset, nodeName: nodeName}
+}
+
+// Run checks the settings of the node at every interval. It will block until stopCh is closed.
+func (a *Agent) Run(stopCh <-chan struct{}) error {
+	ctx := signals.ContextFor(stopCh)
+	klog.V(4).InfoS("Starting kubelet configuration agent", "node", a.nodeName)
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := a.sync(ctx); err != nil {
+			klog.ErrorS(err, "Couldn't configure the kubelet", "node", a.nodeName)
+		}
+	}, Interval)
+	klog.V(4).InfoS("Shutting down kubelet configuration agent")
+	return nil
+}
+
+// sync merges the settings of the node into the configuration of the kubelet and restarts it
+// unless the kubelet already runs with them. The original configuration is put back once the node
+// has no settings anymore.
+func (a *Agent) sync(ctx context.Context) error {
+	node, err := a.kubeclientset.CoreV1().Nodes().Get(ctx, a.nodeName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	settings, configured := node.GetAnnotations()[AnnotationSettings]
+	applied := node.GetAnnotations()[AnnotationApplied]
+	backupPath := ConfigPath + backupSuffix
+
+	if !configured {
+		if applied == "" {
+			return nil
+		}
+		if _, err := os.Stat(backupPath); err == nil {
+			if err := os.Rename(backupPath, ConfigPath); err != nil {
+				return err
+			}
+			klog.InfoS("Restoring the original kubelet configuration", "node", a.nodeName)
+			if err := Restart(ctx); err != nil {
+				return err
+			}
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+		return a.annotate(ctx, nil)
+	}
+
+	hash := Hash(settings)
+	if applied == hash {
+		return nil
+	}
+	if _, err := os.Stat(backupPath); os.IsNotExist(err) {
+		original, err := ioutil.ReadFile(ConfigPath)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(backupPath, original); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	original, err := ioutil.ReadFile(backupPath)
+	if err != nil {
+		return err
+	}
+	merged, err := Merge(original, settings)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(ConfigPath, merged); err != nil {
+		return err
+	}
+	klog.InfoS("Restarting the kubelet with the settings of the node", "node", a.nodeName, "settings", hash)
+	if err := Restart(ctx); err != nil {
+		return err
+	}
+	return a.annotate(ctx, &hash)
+}
+
+// annotate records the hash of the settings the kubelet runs with on the node, the annotation is
+// removed if the hash is nil
+func (a *Agent) annotate(ctx context.Context, hash *string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]*string{AnnotationApplied: hash}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = a.kubeclientset.CoreV1().Nodes().Patch(ctx, a.nodeName, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// writeFile replaces the file in one step, so that the kubelet never reads it half written
+func writeFile(path string, content []byte) error {
+	temporary, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temporary.Name())
+	if _, err := temporary.Write(content); err != nil {
+		temporary.Close()
+		return err
+	}
+	if err := temporary.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(temporary.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(temporary.Name(), path)
+}
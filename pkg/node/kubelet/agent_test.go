@@ -0,0 +1,76 @@
+package kubelet
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func TestAgent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kubelet")
+	util.OK(t, err)
+	defer os.RemoveAll(dir)
+	defer func(path string) { ConfigPath = path }(ConfigPath)
+	ConfigPath = filepath.Join(dir, "config.yaml")
+	original := []byte("kind: KubeletConfiguration\nimageGCHighThresholdPercent: 85\n")
+	util.OK(t, ioutil.WriteFile(ConfigPath, original, 0644))
+	restarts := 0
+	defer func(restart func(context.Context) error) { Restart = restart }(Restart)
+	Restart = func(context.Context) error {
+		restarts++
+		return nil
+	}
+
+	settings := `{"imageGCHighThresholdPercent":70}`
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "paris-1.edge-net.io", Annotations: map[string]string{AnnotationSettings: settings}}}
+	kubeclientset := testclient.NewSimpleClientset(node)
+	agent := NewAgent(kubeclientset, node.GetName())
+	annotations := func() map[string]string {
+		node, err := kubeclientset.CoreV1().Nodes().Get(context.TODO(), node.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		return node.GetAnnotations()
+	}
+
+	util.OK(t, agent.sync(context.TODO()))
+	util.Equals(t, 1, restarts)
+	util.Equals(t, Hash(settings), annotations()[AnnotationApplied])
+	config, err := ioutil.ReadFile(ConfigPath)
+	util.OK(t, err)
+	fields := map[string]interface{}{}
+	util.OK(t, yaml.Unmarshal(config, &fields))
+	util.Equals(t, float64(70), fields["imageGCHighThresholdPercent"])
+	backup, err := ioutil.ReadFile(ConfigPath + backupSuffix)
+	util.OK(t, err)
+	util.Equals(t, original, backup)
+
+	t.Run("applied", func(t *testing.T) {
+		util.OK(t, agent.sync(context.TODO()))
+		util.Equals(t, 1, restarts)
+	})
+	t.Run("released", func(t *testing.T) {
+		node, err := kubeclientset.CoreV1().Nodes().Get(context.TODO(), node.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		delete(node.Annotations, AnnotationSettings)
+		_, err = kubeclientset.CoreV1().Nodes().Update(context.TODO(), node, metav1.UpdateOptions{})
+		util.OK(t, err)
+
+		util.OK(t, agent.sync(context.TODO()))
+		util.Equals(t, 2, restarts)
+		_, ok := annotations()[AnnotationApplied]
+		util.Equals(t, false, ok)
+		config, err := ioutil.ReadFile(ConfigPath)
+		util.OK(t, err)
+		util.Equals(t, original, config)
+		_, err = os.Stat(ConfigPath + backupSuffix)
+		util.Assert(t, os.IsNotExist(err), "backup left")
+	})
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kubelet carries the node configurations to the kubelets. The controller annotates each
+// node with the settings of its configuration, and the agent on the node merges them into the
+// configuration file of the kubelet, restarts it, and annotates the node back once it is done.
+package kubelet
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// Annotations of the nodes, the settings the kubelet is to run with and the hash of the settings
+// it runs with
+const (
+	AnnotationSettings = "edge-net.io/kubelet-configuration"
+	AnnotationApplied  = "edge-net.io/kubelet-configuration-applied"
+)
+
+// evictionSignals are the eviction signals of the kubelet
+var evictionSignals = map[string]bool{
+	"memory.available":   true,
+	"nodefs.available":   true,
+	"nodefs.inodesFree":  true,
+	"imagefs.available":  true,
+	"imagefs.inodesFree": true,
+	"pid.available":      true,
+}
+
+// settingKeys are the fields of the kubelet configuration the settings may hold, the others are
+// left as they are on the node
+var settingKeys = map[string]bool{
+	"evictionHard":                true,
+	"evictionSoft":                true,
+	"evictionSoftGracePeriod":     true,
+	"imageGCHighThresholdPercent": true,
+	"imageGCLowThresholdPercent":  true,
+	"imageMinimumGCAge":           true,
+}
+
+// Validate tells why the kubelet would not run with the configuration, nil if it would
+func Validate(spec corev1alpha.NodeConfigurationSpec) error {
+	for _, thresholds := range []map[string]string{spec.EvictionHard, spec.EvictionSoft} {
+		for signal, threshold := range thresholds {
+			if !evictionSignals[signal] {
+				return fmt.Errorf("unknown eviction signal %s", signal)
+			}
+			if err := validateThreshold(threshold); err != nil {
+				return fmt.Errorf("invalid threshold of %s: %w", signal, err)
+			}
+		}
+	}
+	for signal := range spec.EvictionSoft {
+		if _, ok := spec.EvictionSoftGracePeriod[signal]; !ok {
+			return fmt.Errorf("soft eviction signal %s has no grace period", signal)
+		}
+	}
+	for signal, gracePeriod := range spec.EvictionSoftGracePeriod {
+		if _, ok := spec.EvictionSoft[signal]; !ok {
+			return fmt.Errorf("grace period of %s without a soft eviction threshold", signal)
+		}
+		if _, err := time.ParseDuration(gracePeriod); err != nil {
+			return fmt.Errorf("invalid grace period of %s: %w", signal, err)
+		}
+	}
+	if imageGC := spec.ImageGC; imageGC != nil && imageGC.HighThresholdPercent != nil && imageGC.LowThresholdPercent != nil &&
+		*imageGC.LowThresholdPercent >= *imageGC.HighThresholdPercent {
+		return fmt.Errorf("low threshold of the image garbage collection %d%% is not below the high threshold %d%%",
+			*imageGC.LowThresholdPercent, *imageGC.HighThresholdPercent)
+	}
+	return nil
+}
+
+// validateThreshold checks that the threshold is a quantity or a percentage
+func validateThreshold(threshold string) error {
+	if strings.HasSuffix(threshold, "%") {
+		percentage, err := strconv.ParseFloat(strings.TrimSuffix(threshold, "%"), 64)
+		if err != nil || percentage < 0 || percentage > 100 {
+			return fmt.Errorf("%s is not a percentage", threshold)
+		}
+		return nil
+	}
+	_, err := resource.ParseQuantity(threshold)
+	return err
+}
+
+// Settings returns the fields of the kubelet configuration set by the node configuration, in JSON
+// with the keys sorted so that the same configuration always gives the same settings
+func Settings(spec corev1alpha.NodeConfigurationSpec) (string, error) {
+	settings := map[string]interface{}{}
+	if len(spec.EvictionHard) > 0 {
+		settings["evictionHard"] = spec.EvictionHard
+	}
+	if len(spec.EvictionSoft) > 0 {
+		settings["evictionSoft"] = spec.EvictionSoft
+		settings["evictionSoftGracePeriod"] = spec.EvictionSoftGracePeriod
+	}
+	if imageGC := spec.ImageGC; imageGC != nil {
+		if imageGC.HighThresholdPercent != nil {
+			settings["imageGCHighThresholdPercent"] = *imageGC.HighThresholdPercent
+		}
+		if imageGC.LowThresholdPercent != nil {
+			settings["imageGCLowThresholdPercent"] = *imageGC.LowThresholdPercent
+		}
+		if imageGC.MinimumAge != nil {
+			settings["imageMinimumGCAge"] = imageGC.MinimumAge.Duration.String()
+		}
+	}
+	raw, err := json.Marshal(settings)
+	return string(raw), err
+}
+
+// Hash returns the short hash of the settings the agent annotates the node with once it applied them
+func Hash(settings string) string {
+	hash := sha256.Sum256([]byte(settings))
+	return hex.EncodeToString(hash[:])[:16]
+}
+
+// Merge returns the configuration file of the kubelet overridden by the settings. The agent merges
+// the settings into the file found on the node before any configuration, so that the settings
+// dropped from a node configuration fall back to the ones of the node.
+func Merge(config []byte, settings string) ([]byte, error) {
+	merged := map[string]interface{}{}
+	if err := yaml.Unmarshal(config, &merged); err != nil {
+		return nil, fmt.Errorf("invalid kubelet configuration: %w", err)
+	}
+	if merged == nil {
+		merged = map[string]interface{}{}
+	}
+	overrides := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(settings), &overrides); err != nil {
+		return nil, fmt.Errorf("invalid settings: %w", err)
+	}
+	for key, value := range overrides {
+		if !settingKeys[key] {
+			return nil, fmt.Errorf("setting %s is not managed by the node configurations", key)
+		}
+		merged[key] = value
+	}
+	return yaml.Marshal(merged)
+}
@@ -0,0 +1,79 @@
+package kubelet
+
+import (
+	"testing"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func newSpec() corev1alpha.NodeConfigurationSpec {
+	high, low := int32(70), int32(50)
+	return corev1alpha.NodeConfigurationSpec{NodePool: "satellite",
+		EvictionHard:            map[string]string{"nodefs.available": "10%", "imagefs.available": "500Mi"},
+		EvictionSoft:            map[string]string{"nodefs.available": "15%"},
+		EvictionSoftGracePeriod: map[string]string{"nodefs.available": "1m30s"},
+		ImageGC: &corev1alpha.ImageGC{HighThresholdPercent: &high, LowThresholdPercent: &low,
+			MinimumAge: &metav1.Duration{Duration: 10 * time.Minute}}}
+}
+
+func TestValidate(t *testing.T) {
+	cases := map[string]struct {
+		mutate func(spec *corev1alpha.NodeConfigurationSpec)
+		valid  bool
+	}{
+		"valid":              {func(spec *corev1alpha.NodeConfigurationSpec) {}, true},
+		"unknown signal":     {func(spec *corev1alpha.NodeConfigurationSpec) { spec.EvictionHard["disk.available"] = "1Gi" }, false},
+		"invalid percentage": {func(spec *corev1alpha.NodeConfigurationSpec) { spec.EvictionHard["nodefs.available"] = "110%" }, false},
+		"invalid quantity":   {func(spec *corev1alpha.NodeConfigurationSpec) { spec.EvictionHard["imagefs.available"] = "lots" }, false},
+		"soft without grace": {func(spec *corev1alpha.NodeConfigurationSpec) { spec.EvictionSoftGracePeriod = nil }, false},
+		"grace without soft": {func(spec *corev1alpha.NodeConfigurationSpec) { spec.EvictionSoft = nil }, false},
+		"invalid grace period": {func(spec *corev1alpha.NodeConfigurationSpec) {
+			spec.EvictionSoftGracePeriod["nodefs.available"] = "soon"
+		}, false},
+		"low above high": {func(spec *corev1alpha.NodeConfigurationSpec) { *spec.ImageGC.LowThresholdPercent = 80 }, false},
+		"image collection alone": {func(spec *corev1alpha.NodeConfigurationSpec) {
+			spec.EvictionHard, spec.EvictionSoft, spec.EvictionSoftGracePeriod = nil, nil, nil
+		}, true},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			spec := newSpec()
+			tc.mutate(&spec)
+			util.Equals(t, tc.valid, Validate(spec) == nil)
+		})
+	}
+}
+
+func TestSettings(t *testing.T) {
+	settings, err := Settings(newSpec())
+	util.OK(t, err)
+	util.Equals(t, `{"evictionHard":{"imagefs.available":"500Mi","nodefs.available":"10%"},"evictionSoft":{"nodefs.available":"15%"},`+
+		`"evictionSoftGracePeriod":{"nodefs.available":"1m30s"},"imageGCHighThresholdPercent":70,"imageGCLowThresholdPercent":50,"imageMinimumGCAge":"10m0s"}`, settings)
+	again, err := Settings(newSpec())
+	util.OK(t, err)
+	util.Equals(t, Hash(settings), Hash(again))
+
+	empty, err := Settings(corev1alpha.NodeConfigurationSpec{NodePool: "satellite"})
+	util.OK(t, err)
+	util.Equals(t, "{}", empty)
+}
+
+func TestMerge(t *testing.T) {
+	config := []byte("apiVersion: kubelet.config.k8s.io/v1beta1\nkind: KubeletConfiguration\ncgroupDriver: systemd\nimageGCHighThresholdPercent: 85\n")
+	merged, err := Merge(config, `{"evictionHard":{"nodefs.available":"10%"},"imageGCLowThresholdPercent":50}`)
+	util.OK(t, err)
+	fields := map[string]interface{}{}
+	util.OK(t, yaml.Unmarshal(merged, &fields))
+	util.Equals(t, "systemd", fields["cgroupDriver"])
+	util.Equals(t, float64(85), fields["imageGCHighThresholdPercent"])
+	util.Equals(t, float64(50), fields["imageGCLowThresholdPercent"])
+	util.Equals(t, map[string]interface{}{"nodefs.available": "10%"}, fields["evictionHard"])
+
+	_, err = Merge(config, `{"cgroupDriver":"cgroupfs"}`)
+	util.Assert(t, err != nil, "unmanaged setting merged")
+}
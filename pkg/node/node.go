@@ -23,7 +23,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"math"
 	"net/http"
 	"regexp"
@@ -37,10 +36,12 @@ import (
 
 	namecheap "github.com/billputer/go-namecheap"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
 )
 
 // JSON structure of patch operation
@@ -104,10 +105,10 @@ func Boundbox(points [][]float64) []float64 {
 }
 
 // GetKubeletVersion looks at the head node to decide which version of Kubernetes to install
-func GetKubeletVersion() string {
-	nodeRaw, err := Clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{LabelSelector: "node-role.kubernetes.io/master"})
+func GetKubeletVersion(ctx context.Context) string {
+	nodeRaw, err := Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: "node-role.kubernetes.io/master"})
 	if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't list the master nodes")
 	}
 	kubeletVersion := ""
 	for _, nodeRow := range nodeRaw.Items {
@@ -117,7 +118,7 @@ func GetKubeletVersion() string {
 }
 
 // SetOwnerReferences make the references owner of the node
-func SetOwnerReferences(nodeName string, ownerReferences []metav1.OwnerReference) error {
+func SetOwnerReferences(ctx context.Context, nodeName string, ownerReferences []metav1.OwnerReference) error {
 	// Create a patch slice and initialize it to the size of 1
 	// Append the data existing in the label map to the slice
 	nodePatchArr := make([]interface{}, 1)
@@ -129,12 +130,12 @@ func SetOwnerReferences(nodeName string, ownerReferences []metav1.OwnerReference
 	nodePatchJSON, _ := json.Marshal(nodePatchArr)
 	// Patch the nodes with the arguments:
 	// hostname, patch type, and patch data
-	_, err := Clientset.CoreV1().Nodes().Patch(context.TODO(), nodeName, types.JSONPatchType, nodePatchJSON, metav1.PatchOptions{})
+	_, err := Clientset.CoreV1().Nodes().Patch(ctx, nodeName, types.JSONPatchType, nodePatchJSON, metav1.PatchOptions{})
 	return err
 }
 
 // SetNodeScheduling syncs the node with the node contribution
-func SetNodeScheduling(nodeName string, unschedulable bool) error {
+func SetNodeScheduling(ctx context.Context, nodeName string, unschedulable bool) error {
 	// Create a patch slice and initialize it to the size of 1
 	nodePatchArr := make([]interface{}, 1)
 	nodePatch := patchByBoolValue{}
@@ -145,12 +146,53 @@ func SetNodeScheduling(nodeName string, unschedulable bool) error {
 	nodePatchJSON, _ := json.Marshal(nodePatchArr)
 	// Patch the nodes with the arguments:
 	// hostname, patch type, and patch data
-	_, err := Clientset.CoreV1().Nodes().Patch(context.TODO(), nodeName, types.JSONPatchType, nodePatchJSON, metav1.PatchOptions{})
+	_, err := Clientset.CoreV1().Nodes().Patch(ctx, nodeName, types.JSONPatchType, nodePatchJSON, metav1.PatchOptions{})
 	return err
 }
 
+// Drain evicts the pods running on the node through the eviction API so that the
+// PodDisruptionBudgets are respected, DaemonSet and mirror pods stay in place. It returns
+// the number of pods evicted, and the number of pods waiting for their eviction as a
+// disruption budget blocks it or they are still terminating.
+func Drain(ctx context.Context, nodeName string) (int, int, error) {
+	podRaw, err := Clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{FieldSelector: fmt.Sprintf("spec.nodeName=%s", nodeName)})
+	if err != nil {
+		return 0, 0, err
+	}
+	evicted, remaining := 0, 0
+	for _, podRow := range podRaw.Items {
+		if podRow.Spec.NodeName != nodeName || podRow.Status.Phase == corev1.PodSucceeded || podRow.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if _, mirror := podRow.GetAnnotations()[corev1.MirrorPodAnnotationKey]; mirror {
+			continue
+		}
+		if ownerRef := metav1.GetControllerOf(&podRow); ownerRef != nil && ownerRef.Kind == "DaemonSet" {
+			continue
+		}
+		if podRow.GetDeletionTimestamp() != nil {
+			remaining++
+			continue
+		}
+		eviction := &policyv1beta1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: podRow.GetName(), Namespace: podRow.GetNamespace()}}
+		if err := Clientset.CoreV1().Pods(podRow.GetNamespace()).Evict(ctx, eviction); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			} else if errors.IsTooManyRequests(err) {
+				// A disruption budget does not allow the eviction for now
+				remaining++
+				continue
+			}
+			return evicted, remaining, err
+		}
+		evicted++
+		remaining++
+	}
+	return evicted, remaining, nil
+}
+
 // setNodeLabels uses client-go to patch nodes by processing a labels map
-func setNodeLabels(hostname string, labels map[string]string) bool {
+func setNodeLabels(ctx context.Context, hostname string, labels map[string]string) bool {
 	// Create a patch slice and initialize it to the label size
 	nodePatchArr := make([]patchStringValue, len(labels))
 	nodePatch := patchStringValue{}
@@ -166,9 +208,9 @@ func setNodeLabels(hostname string, labels map[string]string) bool {
 	nodesJSON, _ := json.Marshal(nodePatchArr)
 	// Patch the nodes with the arguments:
 	// hostname, patch type, and patch data
-	_, err := Clientset.CoreV1().Nodes().Patch(context.TODO(), hostname, types.JSONPatchType, nodesJSON, metav1.PatchOptions{})
+	_, err := Clientset.CoreV1().Nodes().Patch(ctx, hostname, types.JSONPatchType, nodesJSON, metav1.PatchOptions{})
 	if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't patch the node", "node", hostname)
 		panic(err.Error())
 	}
 	return true
@@ -213,6 +255,7 @@ func getMaxmindLocation(url string, accountId string, licenseKey string, address
 
 // GetGeolocationByIP returns geolabels from the MaxMind GeoIP2 precision service
 func GetGeolocationByIP(
+	ctx context.Context,
 	maxmindUrl string,
 	maxmindAccountId string,
 	maxmindLicenseKey string,
@@ -222,7 +265,7 @@ func GetGeolocationByIP(
 	// Fetch geolocation information
 	record, err := getMaxmindLocation(maxmindUrl, maxmindAccountId, maxmindLicenseKey, address)
 	if err != nil {
-		log.Println(err)
+		klog.ErrorS(err, "Couldn't fetch the geolocation", "address", address)
 		return false
 	}
 
@@ -263,7 +306,7 @@ func GetGeolocationByIP(
 	}
 
 	// Attach geolabels to the node
-	result := setNodeLabels(hostname, geoLabels)
+	result := setNodeLabels(ctx, hostname, geoLabels)
 
 	// If the result is different than the expected, return false
 	// The expected result is having a different longitude and latitude than zero
@@ -298,15 +341,16 @@ func CompareIPAddresses(oldObj *corev1.Node, newObj *corev1.Node) bool {
 	return updated
 }
 
-// GetNodeIPAddresses picks up the internal and external IP addresses of the Node
+// GetNodeIPAddresses picks up the internal and external IP addresses of the Node. A dual-stack
+// node lists an address of each family, the first one is of the primary family of the node.
 func GetNodeIPAddresses(obj *corev1.Node) (string, string) {
 	internalIP := ""
 	externalIP := ""
 	for _, addressesRow := range obj.Status.Addresses {
-		if addressType := addressesRow.Type; addressType == "InternalIP" {
+		if addressType := addressesRow.Type; addressType == "InternalIP" && internalIP == "" {
 			internalIP = addressesRow.Address
 		}
-		if addressType := addressesRow.Type; addressType == "ExternalIP" {
+		if addressType := addressesRow.Type; addressType == "ExternalIP" && externalIP == "" {
 			externalIP = addressesRow.Address
 		}
 	}
@@ -317,7 +361,7 @@ func GetNodeIPAddresses(obj *corev1.Node) (string, string) {
 func SetHostname(hostRecord namecheap.DomainDNSHost) (bool, string) {
 	client, err := bootstrap.CreateNamecheapClient()
 	if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't create the Namecheap client")
 		return false, "Unknown"
 	}
 	result, state := infrastructure.SetHostname(client, hostRecord)
@@ -325,21 +369,55 @@ func SetHostname(hostRecord namecheap.DomainDNSHost) (bool, string) {
 }
 
 // CreateJoinToken generates token to be used on adding a node onto the cluster
-func CreateJoinToken(ttl string, hostname string) string {
+func CreateJoinToken(ctx context.Context, ttl string, hostname string) string {
 	duration, _ := time.ParseDuration(ttl)
-	token, err := infrastructure.CreateToken(Clientset, duration, hostname)
+	token, err := infrastructure.CreateToken(ctx, Clientset, duration, hostname)
 	if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't create the join token", "node", hostname)
 		return "error"
 	}
 	return token
 }
 
+// Patterns of the versions accepted for an upgrade, they end up in a shell on the node
+var (
+	kubeletVersionPattern    = regexp.MustCompile(`^v?[0-9]+\.[0-9]+\.[0-9]+$`)
+	containerdVersionPattern = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+(-[0-9]+)?$`)
+)
+
+// UpgradeCommands returns the commands that upgrade kubelet, and containerd if a version is given,
+// on a Debian or CentOS node. The commands stop at the first failure.
+func UpgradeCommands(kubeletVersion, containerdVersion string) ([]string, error) {
+	if !kubeletVersionPattern.MatchString(kubeletVersion) {
+		return nil, fmt.Errorf("invalid kubelet version %q", kubeletVersion)
+	}
+	if containerdVersion != "" && !containerdVersionPattern.MatchString(containerdVersion) {
+		return nil, fmt.Errorf("invalid containerd version %q", containerdVersion)
+	}
+	version := strings.TrimPrefix(kubeletVersion, "v")
+	commands := []string{
+		"sudo su",
+		"set -e",
+	}
+	if containerdVersion != "" {
+		commands = append(commands,
+			fmt.Sprintf("if command -v apt-get > /dev/null; then apt-get update && apt-get install -y --allow-change-held-packages containerd.io=%s*; else yum install -y containerd.io-%s; fi", containerdVersion, containerdVersion),
+			"systemctl restart containerd")
+	}
+	commands = append(commands,
+		fmt.Sprintf("if command -v apt-get > /dev/null; then apt-get update && apt-get install -y --allow-change-held-packages kubeadm=%s-00 kubelet=%s-00 kubectl=%s-00; else yum install -y kubeadm-%s kubelet-%s kubectl-%s --disableexcludes=kubernetes; fi", version, version, version, version, version, version),
+		"kubeadm upgrade node",
+		"systemctl daemon-reload",
+		"systemctl restart kubelet",
+		"exit")
+	return commands, nil
+}
+
 // GetList uses clientset to get node list of the cluster
-func GetList() []string {
-	nodesRaw, err := Clientset.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+func GetList(ctx context.Context) []string {
+	nodesRaw, err := Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 	if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't list the nodes")
 		panic(err.Error())
 	}
 	nodes := make([]string, len(nodesRaw.Items))
@@ -363,19 +441,19 @@ func GetConditionReadyStatus(node *corev1.Node) string {
 }
 
 // getNodeByHostname uses clientset to get namespace requested
-func getNodeByHostname(hostname string) (string, error) {
+func getNodeByHostname(ctx context.Context, hostname string) (string, error) {
 	// Examples for error handling:
 	// - Use helper functions like e.g. errors.IsNotFound()
 	// - And/or cast to StatusError and use its properties like e.g. ErrStatus.Message
-	_, err := Clientset.CoreV1().Nodes().Get(context.TODO(), hostname, metav1.GetOptions{})
+	_, err := Clientset.CoreV1().Nodes().Get(ctx, hostname, metav1.GetOptions{})
 	if errors.IsNotFound(err) {
-		log.Printf("Node %s not found", hostname)
+		klog.InfoS("Node not found", "node", hostname)
 		return "false", err
 	} else if statusError, isStatus := err.(*errors.StatusError); isStatus {
-		log.Printf("Error getting node %s: %v", hostname, statusError.ErrStatus)
+		klog.InfoS("Error getting node", "node", hostname, "errStatus", statusError.ErrStatus)
 		return "error", err
 	} else if err != nil {
-		log.Println(err.Error())
+		klog.ErrorS(err, "Couldn't get the node", "node", hostname)
 		panic(err.Error())
 	} else {
 		return "true", nil
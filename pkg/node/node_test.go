@@ -7,17 +7,21 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/EdgeNet-project/edgenet/pkg/util"
 	"github.com/sirupsen/logrus"
 
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	testclient "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
 )
 
 // The main structure of test group
@@ -225,7 +229,7 @@ func TestGetList(t *testing.T) {
 	for _, tc := range cases {
 		_, err := g.client.CoreV1().Nodes().Create(context.TODO(), tc.node.DeepCopy(), metav1.CreateOptions{})
 		util.OK(t, err)
-		util.Equals(t, tc.expected, GetList())
+		util.Equals(t, tc.expected, GetList(context.TODO()))
 	}
 }
 
@@ -271,7 +275,7 @@ func TestGetNodeByHostname(t *testing.T) {
 		_, err := g.client.CoreV1().Nodes().Create(context.TODO(), tc.node.DeepCopy(), metav1.CreateOptions{})
 		util.OK(t, err)
 
-		_, err = getNodeByHostname(tc.name)
+		_, err = getNodeByHostname(context.TODO(), tc.name)
 		util.Equals(t, tc.expected, !errors.IsNotFound(err))
 	}
 }
@@ -327,6 +331,20 @@ func TestGetNodeIPAddresses(t *testing.T) {
 			Address: "10.0.0.4",
 		},
 	}
+	dualStack := g.nodeObj
+	dualStack.SetName("node-dual-stack")
+	dualStack.Status.Addresses = []corev1.NodeAddress{
+		{Type: "InternalIP", Address: "192.168.0.5"},
+		{Type: "InternalIP", Address: "fd00::5"},
+		{Type: "ExternalIP", Address: "10.0.0.5"},
+		{Type: "ExternalIP", Address: "2001:db8::5"},
+	}
+	ipv6Only := g.nodeObj
+	ipv6Only.SetName("node-ipv6-only")
+	ipv6Only.Status.Addresses = []corev1.NodeAddress{
+		{Type: "InternalIP", Address: "fd00::6"},
+		{Type: "ExternalIP", Address: "2001:db8::6"},
+	}
 
 	cases := []struct {
 		node     corev1.Node
@@ -336,6 +354,14 @@ func TestGetNodeIPAddresses(t *testing.T) {
 			node1,
 			[]string{"192.168.0.1", "10.0.0.1"},
 		},
+		{
+			dualStack,
+			[]string{"192.168.0.5", "10.0.0.5"},
+		},
+		{
+			ipv6Only,
+			[]string{"fd00::6", "2001:db8::6"},
+		},
 		{
 			node2,
 			[]string{"192.168.0.2", "10.0.0.2"},
@@ -529,7 +555,7 @@ func TestSetOwnerReferences(t *testing.T) {
 	g.client.CoreV1().Nodes().Create(context.TODO(), node1.DeepCopy(), metav1.CreateOptions{})
 	g.client.CoreV1().Nodes().Create(context.TODO(), node2.DeepCopy(), metav1.CreateOptions{})
 
-	err := SetOwnerReferences(node1.GetName(), ownerReferences)
+	err := SetOwnerReferences(context.TODO(), node1.GetName(), ownerReferences)
 	util.OK(t, err)
 
 	node, err := g.client.CoreV1().Nodes().Get(context.TODO(), node1.GetName(), metav1.GetOptions{})
@@ -556,7 +582,7 @@ func TestSetNodeScheduling(t *testing.T) {
 
 	for k, tc := range cases {
 		t.Run(fmt.Sprintf("%s", k), func(t *testing.T) {
-			err := SetNodeScheduling(node1.GetName(), tc.input)
+			err := SetNodeScheduling(context.TODO(), node1.GetName(), tc.input)
 			util.OK(t, err)
 			node, err := g.client.CoreV1().Nodes().Get(context.TODO(), node1.GetName(), metav1.GetOptions{})
 			util.OK(t, err)
@@ -566,8 +592,76 @@ func TestSetNodeScheduling(t *testing.T) {
 }
 
 func TestCreateJoinToken(t *testing.T) {
-	token := CreateJoinToken("600s", "test.edgenet.io")
+	token := CreateJoinToken(context.TODO(), "600s", "test.edgenet.io")
 	if token == "error" {
 		t.Errorf("Token cannot be created")
 	}
 }
+
+func TestUpgradeCommands(t *testing.T) {
+	cases := map[string]struct {
+		kubelet    string
+		containerd string
+		expected   int
+		valid      bool
+	}{
+		"kubelet":                {"v1.21.3", "", 7, true},
+		"kubelet and containerd": {"1.21.3", "1.4.6-1", 9, true},
+		"invalid kubelet":        {"1.21; reboot", "", 0, false},
+		"invalid containerd":     {"v1.21.3", "latest", 0, false},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			commands, err := UpgradeCommands(tc.kubelet, tc.containerd)
+			util.Equals(t, tc.valid, err == nil)
+			util.Equals(t, tc.expected, len(commands))
+			if tc.valid {
+				util.Assert(t, strings.Contains(commands[len(commands)-5], "kubelet=1.21.3-00"), "kubelet version not pinned")
+			}
+		})
+	}
+}
+
+func TestDrain(t *testing.T) {
+	g := testGroup{}
+	g.Init()
+	// The eviction of a pod protected by a disruption budget is refused, the others are deleted
+	client := g.client.(*testclient.Clientset)
+	client.PrependReactor("create", "pods", func(action ktesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" {
+			return false, nil, nil
+		}
+		eviction := action.(ktesting.CreateAction).GetObject().(*policyv1beta1.Eviction)
+		if eviction.GetName() == "protected" {
+			return true, nil, errors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 10)
+		}
+		return true, nil, client.Tracker().Delete(corev1.SchemeGroupVersion.WithResource("pods"), eviction.GetNamespace(), eviction.GetName())
+	})
+	isController := true
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "workload", Namespace: "edgenet"}, Spec: corev1.PodSpec{NodeName: "node-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "protected", Namespace: "edgenet"}, Spec: corev1.PodSpec{NodeName: "node-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "daemon", Namespace: "edgenet", OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet", Name: "daemon", Controller: &isController}}}, Spec: corev1.PodSpec{NodeName: "node-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "mirror", Namespace: "kube-system", Annotations: map[string]string{corev1.MirrorPodAnnotationKey: "mirror"}}, Spec: corev1.PodSpec{NodeName: "node-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "elsewhere", Namespace: "edgenet"}, Spec: corev1.PodSpec{NodeName: "node-2"}},
+	}
+	for _, pod := range pods {
+		_, err := g.client.CoreV1().Pods(pod.GetNamespace()).Create(context.TODO(), pod.DeepCopy(), metav1.CreateOptions{})
+		util.OK(t, err)
+	}
+
+	evicted, remaining, err := Drain(context.TODO(), "node-1")
+	util.OK(t, err)
+	util.Equals(t, 1, evicted)
+	util.Equals(t, 2, remaining)
+	_, err = g.client.CoreV1().Pods("edgenet").Get(context.TODO(), "workload", metav1.GetOptions{})
+	util.Equals(t, true, errors.IsNotFound(err))
+	_, err = g.client.CoreV1().Pods("edgenet").Get(context.TODO(), "elsewhere", metav1.GetOptions{})
+	util.OK(t, err)
+
+	// Only the pod protected by the disruption budget is left
+	evicted, remaining, err = Drain(context.TODO(), "node-1")
+	util.OK(t, err)
+	util.Equals(t, 0, evicted)
+	util.Equals(t, 1, remaining)
+}
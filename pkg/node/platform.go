@@ -0,0 +1,183 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Architectures of the nodes, named after the Go architectures as in the kubernetes.io/arch label
+const (
+	ArchAMD64 = "amd64"
+	ArchARM64 = "arm64"
+	ArchARM   = "arm"
+)
+
+// Flavors of the operating systems of the nodes
+const (
+	FlavorUbuntu = "ubuntu"
+	FlavorDebian = "debian"
+	FlavorCentOS = "centos"
+)
+
+// Platform is the architecture and the operating system flavor of a node, the packages installed
+// on the node are picked after it
+type Platform struct {
+	Architecture string
+	Flavor       string
+}
+
+// Commands reading the platform of a node
+const (
+	MachineCommand   = "uname -m"
+	OSReleaseCommand = "cat /etc/os-release"
+)
+
+// machines maps the machine types reported by uname to the architectures, the 32-bit ARM boards
+// such as the Raspberry Pi report armv7l, and armv8l when they run a 32-bit system on a 64-bit CPU
+var machines = map[string]string{
+	"x86_64":  ArchAMD64,
+	"amd64":   ArchAMD64,
+	"aarch64": ArchARM64,
+	"arm64":   ArchARM64,
+	"armv7l":  ArchARM,
+	"armv8l":  ArchARM,
+}
+
+// Architecture returns the architecture of the machine type reported by uname
+func Architecture(machine string) (string, error) {
+	if architecture, ok := machines[strings.TrimSpace(machine)]; ok {
+		return architecture, nil
+	}
+	return "", fmt.Errorf("architecture %s is not supported, the nodes run on x86_64, aarch64, or armv7l", strings.TrimSpace(machine))
+}
+
+// Flavor returns the flavor of the operating system described by /etc/os-release, the derivatives
+// such as Raspberry Pi OS or Rocky Linux are installed as the distribution they are like
+func Flavor(osRelease string) (string, error) {
+	fields := map[string]string{}
+	for _, line := range strings.Split(osRelease, "\n") {
+		if key, value := splitOSReleaseLine(line); key != "" {
+			fields[key] = value
+		}
+	}
+	candidates := append([]string{fields["ID"]}, strings.Fields(fields["ID_LIKE"])...)
+	for _, candidate := range candidates {
+		switch candidate {
+		case FlavorUbuntu, FlavorDebian, FlavorCentOS:
+			return candidate, nil
+		case "rhel", "fedora":
+			return FlavorCentOS, nil
+		}
+	}
+	return "", fmt.Errorf("operating system %s is not supported, the nodes run Ubuntu, Debian, or CentOS", fields["ID"])
+}
+
+func splitOSReleaseLine(line string) (string, string) {
+	parts := strings.SplitN(strings.TrimSpace(line), "=", 2)
+	if len(parts) != 2 {
+		return "", ""
+	}
+	return parts[0], strings.Trim(parts[1], `"'`)
+}
+
+// DetectPlatform returns the platform of a node from the outputs of the machine and the os-release
+// commands
+func DetectPlatform(machine, osRelease string) (Platform, error) {
+	architecture, err := Architecture(machine)
+	if err != nil {
+		return Platform{}, err
+	}
+	flavor, err := Flavor(osRelease)
+	if err != nil {
+		return Platform{}, err
+	}
+	return Platform{Architecture: architecture, Flavor: flavor}, nil
+}
+
+// debianArchitectures and rpmArchitectures name the architectures in the package repositories, no
+// containerd package is built for CentOS on 32-bit ARM
+var (
+	debianArchitectures = map[string]string{ArchAMD64: "amd64", ArchARM64: "arm64", ArchARM: "armhf"}
+	rpmArchitectures    = map[string]string{ArchAMD64: "x86_64", ArchARM64: "aarch64"}
+)
+
+// kubeletUnitTemplate is the drop-in of the kubelet unit, the environment file holding the extra
+// arguments of the kubelet is at a different path on each flavor
+var kubeletUnitTemplate = template.Must(template.New("kubelet").Parse(`[Service]
+EnvironmentFile=-{{.EnvironmentFile}}
+Environment="KUBELET_EXTRA_ARGS=--container-runtime=remote --container-runtime-endpoint=unix:///run/containerd/containerd.sock --cgroup-driver=systemd"
+`))
+
+// KubeletUnit returns the drop-in of the kubelet unit on the flavor
+func KubeletUnit(flavor string) string {
+	values := struct{ EnvironmentFile string }{"/etc/default/kubelet"}
+	if flavor == FlavorCentOS {
+		values.EnvironmentFile = "/etc/sysconfig/kubelet"
+	}
+	unit := new(bytes.Buffer)
+	kubeletUnitTemplate.Execute(unit, values)
+	return unit.String()
+}
+
+// InstallCommands returns the commands that install containerd and the kubelet of the given version
+// from the repositories of the platform, and configure them for the kubelet to run containerd with
+// the systemd cgroup driver. They are run as root and stop at the first failure.
+func InstallCommands(platform Platform, kubeletVersion string) ([]string, error) {
+	if !kubeletVersionPattern.MatchString(kubeletVersion) {
+		return nil, fmt.Errorf("invalid kubelet version %q", kubeletVersion)
+	}
+	version := strings.TrimPrefix(kubeletVersion, "v")
+	commands := []string{"set -e"}
+	switch platform.Flavor {
+	case FlavorUbuntu, FlavorDebian:
+		architecture, ok := debianArchitectures[platform.Architecture]
+		if !ok {
+			return nil, fmt.Errorf("architecture %s is not supported on %s", platform.Architecture, platform.Flavor)
+		}
+		commands = append(commands,
+			"apt-get update && apt-get install -y apt-transport-https ca-certificates curl gnupg lsb-release",
+			fmt.Sprintf("curl -fsSL https://download.docker.com/linux/%s/gpg | gpg --dearmor --yes -o /usr/share/keyrings/docker.gpg", platform.Flavor),
+			fmt.Sprintf("echo \"deb [arch=%s signed-by=/usr/share/keyrings/docker.gpg] https://download.docker.com/linux/%s $(lsb_release -cs) stable\" > /etc/apt/sources.list.d/docker.list", architecture, platform.Flavor),
+			"curl -fsSL https://packages.cloud.google.com/apt/doc/apt-key.gpg | gpg --dearmor --yes -o /usr/share/keyrings/kubernetes.gpg",
+			fmt.Sprintf("echo \"deb [arch=%s signed-by=/usr/share/keyrings/kubernetes.gpg] https://apt.kubernetes.io/ kubernetes-xenial main\" > /etc/apt/sources.list.d/kubernetes.list", architecture),
+			fmt.Sprintf("apt-get update && apt-get install -y --allow-change-held-packages containerd.io kubeadm=%s-00 kubelet=%s-00 kubectl=%s-00", version, version, version),
+			"apt-mark hold kubeadm kubelet kubectl")
+	case FlavorCentOS:
+		architecture, ok := rpmArchitectures[platform.Architecture]
+		if !ok {
+			return nil, fmt.Errorf("architecture %s is not supported on %s", platform.Architecture, platform.Flavor)
+		}
+		commands = append(commands,
+			"yum install -y yum-utils && yum-config-manager --add-repo https://download.docker.com/linux/centos/docker-ce.repo",
+			fmt.Sprintf("printf '[kubernetes]\\nname=Kubernetes\\nbaseurl=https://packages.cloud.google.com/yum/repos/kubernetes-el7-%s\\nenabled=1\\ngpgcheck=1\\ngpgkey=https://packages.cloud.google.com/yum/doc/yum-key.gpg https://packages.cloud.google.com/yum/doc/rpm-package-key.gpg\\nexclude=kubelet kubeadm kubectl\\n' > /etc/yum.repos.d/kubernetes.repo", architecture),
+			fmt.Sprintf("yum install -y containerd.io kubeadm-%s kubelet-%s kubectl-%s --disableexcludes=kubernetes", version, version, version))
+	default:
+		return nil, fmt.Errorf("operating system %s is not supported", platform.Flavor)
+	}
+	return append(commands,
+		"mkdir -p /etc/containerd && containerd config default > /etc/containerd/config.toml",
+		"sed -i 's/SystemdCgroup = false/SystemdCgroup = true/' /etc/containerd/config.toml",
+		"mkdir -p /etc/systemd/system/kubelet.service.d",
+		fmt.Sprintf("cat > /etc/systemd/system/kubelet.service.d/20-edgenet.conf << 'EOF'\n%sEOF", KubeletUnit(platform.Flavor)),
+		"systemctl daemon-reload",
+		"systemctl enable --now containerd kubelet",
+		"set +e"), nil
+}
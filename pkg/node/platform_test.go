@@ -0,0 +1,61 @@
+package node
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+)
+
+func TestDetectPlatform(t *testing.T) {
+	cases := map[string]struct {
+		machine   string
+		osRelease string
+		expected  Platform
+		valid     bool
+	}{
+		"ubuntu":        {"x86_64\n", "NAME=\"Ubuntu\"\nID=ubuntu\nID_LIKE=debian\n", Platform{ArchAMD64, FlavorUbuntu}, true},
+		"raspberry pi":  {"armv7l", "ID=raspbian\nID_LIKE=debian", Platform{ArchARM, FlavorDebian}, true},
+		"debian on arm": {"aarch64", "ID=debian", Platform{ArchARM64, FlavorDebian}, true},
+		"rocky linux":   {"x86_64", "ID=\"rocky\"\nID_LIKE=\"rhel centos fedora\"", Platform{ArchAMD64, FlavorCentOS}, true},
+		"unknown os":    {"x86_64", "ID=alpine", Platform{}, false},
+		"unknown arch":  {"mips64", "ID=debian", Platform{}, false},
+		"no os-release": {"x86_64", "", Platform{}, false},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			platform, err := DetectPlatform(tc.machine, tc.osRelease)
+			util.Equals(t, tc.valid, err == nil)
+			util.Equals(t, tc.expected, platform)
+		})
+	}
+}
+
+func TestInstallCommands(t *testing.T) {
+	cases := map[string]struct {
+		platform Platform
+		version  string
+		contains []string
+		valid    bool
+	}{
+		"ubuntu":       {Platform{ArchAMD64, FlavorUbuntu}, "v1.21.3", []string{"[arch=amd64 ", "linux/ubuntu", "kubelet=1.21.3-00", "/etc/default/kubelet"}, true},
+		"raspberry pi": {Platform{ArchARM, FlavorDebian}, "1.21.3", []string{"[arch=armhf ", "linux/debian", "kubeadm=1.21.3-00"}, true},
+		"centos arm64": {Platform{ArchARM64, FlavorCentOS}, "v1.21.3", []string{"kubernetes-el7-aarch64", "kubelet-1.21.3", "/etc/sysconfig/kubelet"}, true},
+		"centos arm":   {Platform{ArchARM, FlavorCentOS}, "v1.21.3", nil, false},
+		"invalid":      {Platform{ArchAMD64, FlavorUbuntu}, "1.21; reboot", nil, false},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			commands, err := InstallCommands(tc.platform, tc.version)
+			util.Equals(t, tc.valid, err == nil)
+			script := strings.Join(commands, "\n")
+			for _, expected := range tc.contains {
+				util.Assert(t, strings.Contains(script, expected), "%q missing from the commands", expected)
+			}
+			if tc.valid {
+				util.Equals(t, "set -e", commands[0])
+				util.Equals(t, "set +e", commands[len(commands)-1])
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy lets the operators encode the rules of their institution in Rego instead of
+// forking the controllers. The controllers consult the policies at their hooks, the approval of
+// the tenant requests and the establishment of the tenants, and the policies may deny the action
+// with their reasons. The approval hook may also approve a request on behalf of the administrators.
+//
+// The policies are evaluated either by an OPA server, such as a sidecar of the controller, through
+// its data API, or by an external data provider of Gatekeeper. With OPA, each hook is a package
+// under the URL, for instance with --policy-url=http://localhost:8181/v1/data/edgenet:
+//
+//	package edgenet.tenantrequest.approval
+//
+//	deny[reason] {
+//		not endswith(input.object.spec.contact.email, ".edu")
+//		reason := "only the academic institutions can request a tenant"
+//	}
+//
+//	approve {
+//		endswith(input.object.spec.contact.email, "@sorbonne-universite.fr")
+//	}
+//
+// An external data provider gets the input marshalled as the single key of the provider request,
+// and replies with the decision as its value.
+package policy
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Hooks the controllers consult the policies at
+const (
+	HookTenantRequestApproval = "tenantrequest/approval"
+	HookTenantEstablishment   = "tenant/establishment"
+)
+
+// Backends evaluating the policies
+const (
+	BackendOPA        = "opa"
+	BackendGatekeeper = "gatekeeper"
+)
+
+// URL of the backend, the policies are not consulted if empty. With OPA, it is the data API path
+// the hooks are appended to. With Gatekeeper, it is the URL of the external data provider.
+var URL string
+
+// Backend evaluating the policies, opa or gatekeeper
+var Backend = BackendOPA
+
+// Client posts the inputs to the backend
+var Client = &http.Client{Timeout: 5 * time.Second}
+
+// Input is what the policies decide on
+type Input struct {
+	Hook string `json:"hook"`
+	// Object is the tenant request or the tenant the action is taken on
+	Object interface{} `json:"object"`
+}
+
+// Decision of the policies, an undefined decision denies nothing and approves nothing
+type Decision struct {
+	// Deny holds the reasons the policies deny the action for
+	Deny []string `json:"deny,omitempty"`
+	// Approve tells that the policies approve the request on behalf of the administrators, it is
+	// only heeded at the approval hooks
+	Approve bool `json:"approve,omitempty"`
+}
+
+// Denied tells whether the policies deny the action
+func (d *Decision) Denied() bool {
+	return len(d.Deny) > 0
+}
+
+// Reason returns the reasons of the denial in a single message
+func (d *Decision) Reason() string {
+	return strings.Join(d.Deny, "; ")
+}
+
+// Enabled tells whether the controllers consult the policies
+func Enabled() bool {
+	return URL != ""
+}
+
+// Configure sets the backend up, the certificate authority is only needed by the backends served
+// over TLS with a certificate the system does not trust, such as the external data providers
+func Configure(backend, url, caPath string) error {
+	if backend != BackendOPA && backend != BackendGatekeeper {
+		return fmt.Errorf("unknown policy backend %q", backend)
+	}
+	Backend, URL = backend, url
+	if caPath == "" {
+		return nil
+	}
+	caPEM, err := ioutil.ReadFile(caPath)
+	if err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return fmt.Errorf("no certificate found in %s", caPath)
+	}
+	Client = &http.Client{Timeout: Client.Timeout, Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}}}
+	return nil
+}
+
+// Evaluate consults the policies at the hook about the object. The decision is empty when the
+// policies are not consulted, an error means the policies could not be consulted and the action
+// should wait.
+func Evaluate(ctx context.Context, hook string, object interface{}) (*Decision, error) {
+	if !Enabled() {
+		return &Decision{}, nil
+	}
+	input := Input{Hook: hook, Object: object}
+	if Backend == BackendGatekeeper {
+		return evaluateProvider(ctx, input)
+	}
+	return evaluateOPA(ctx, input)
+}
+
+// evaluateOPA queries the data API of OPA for the package of the hook
+func evaluateOPA(ctx context.Context, input Input) (*Decision, error) {
+	reply := struct {
+		// Result is missing when the package of the hook is undefined
+		Result *Decision `json:"result"`
+	}{}
+	if err := post(ctx, strings.TrimSuffix(URL, "/")+"/"+input.Hook, struct {
+		Input Input `json:"input"`
+	}{input}, &reply); err != nil {
+		return nil, err
+	}
+	if reply.Result == nil {
+		return &Decision{}, nil
+	}
+	return reply.Result, nil
+}
+
+// providerRequest and providerResponse follow the protocol of the external data providers of
+// Gatekeeper
+type providerRequest struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Request    struct {
+		Keys []string `json:"keys"`
+	} `json:"request"`
+}
+
+type providerResponse struct {
+	Response struct {
+		Items []struct {
+			Key   string          `json:"key"`
+			Value json.RawMessage `json:"value"`
+			Error string          `json:"error"`
+		} `json:"items"`
+		SystemError string `json:"systemError"`
+	} `json:"response"`
+}
+
+// evaluateProvider asks the external data provider for the decision on the input
+func evaluateProvider(ctx context.Context, input Input) (*Decision, error) {
+	key, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	request := providerRequest{APIVersion: "externaldata.gatekeeper.sh/v1beta1", Kind: "ProviderRequest"}
+	request.Request.Keys = []string{string(key)}
+	reply := providerResponse{}
+	if err := post(ctx, URL, request, &reply); err != nil {
+		return nil, err
+	}
+	if reply.Response.SystemError != "" {
+		return nil, fmt.Errorf("policy provider failed: %s", reply.Response.SystemError)
+	}
+	for _, item := range reply.Response.Items {
+		if item.Key != string(key) {
+			continue
+		}
+		if item.Error != "" {
+			return nil, fmt.Errorf("policy provider failed: %s", item.Error)
+		}
+		decision := &Decision{}
+		if len(item.Value) > 0 && string(item.Value) != "null" {
+			if err := json.Unmarshal(item.Value, decision); err != nil {
+				return nil, fmt.Errorf("malformed decision: %v", err)
+			}
+		}
+		return decision, nil
+	}
+	return &Decision{}, nil
+}
+
+// post sends the body to the backend and decodes its reply
+func post(ctx context.Context, url string, body, reply interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := Client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("policy backend replied %s", response.Status)
+	}
+	if err := json.NewDecoder(response.Body).Decode(reply); err != nil {
+		return fmt.Errorf("malformed reply of the policy backend: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+)
+
+type object struct {
+	Email string `json:"email"`
+}
+
+// decide stands for the policies, it denies the requests out of the academic institutions and
+// approves those of Sorbonne Université
+func decide(input Input) *Decision {
+	raw, _ := json.Marshal(input.Object)
+	o := object{}
+	json.Unmarshal(raw, &o)
+	decision := &Decision{}
+	if !strings.HasSuffix(o.Email, ".fr") {
+		decision.Deny = append(decision.Deny, "not an academic institution", "unknown domain")
+	}
+	if strings.HasSuffix(o.Email, "@sorbonne-universite.fr") {
+		decision.Approve = true
+	}
+	return decision
+}
+
+func TestEvaluate(t *testing.T) {
+	defer func() { URL, Backend = "", BackendOPA }()
+
+	decision, err := Evaluate(context.TODO(), HookTenantRequestApproval, object{Email: "john.doe@edge-net.org"})
+	util.OK(t, err)
+	util.Equals(t, &Decision{}, decision)
+
+	opa := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/data/edgenet/tenant/establishment" {
+			// The package of the hook is undefined
+			w.Write([]byte(`{}`))
+			return
+		}
+		util.Equals(t, "/v1/data/edgenet/tenantrequest/approval", r.URL.Path)
+		body := struct {
+			Input Input `json:"input"`
+		}{}
+		util.OK(t, json.NewDecoder(r.Body).Decode(&body))
+		util.Equals(t, HookTenantRequestApproval, body.Input.Hook)
+		json.NewEncoder(w).Encode(map[string]interface{}{"result": decide(body.Input)})
+	}))
+	defer opa.Close()
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := providerRequest{}
+		util.OK(t, json.NewDecoder(r.Body).Decode(&request))
+		util.Equals(t, "ProviderRequest", request.Kind)
+		reply := map[string]interface{}{}
+		items := []map[string]interface{}{}
+		for _, key := range request.Request.Keys {
+			input := Input{}
+			util.OK(t, json.Unmarshal([]byte(key), &input))
+			items = append(items, map[string]interface{}{"key": key, "value": decide(input)})
+		}
+		reply["response"] = map[string]interface{}{"items": items}
+		json.NewEncoder(w).Encode(reply)
+	}))
+	defer provider.Close()
+
+	for backend, url := range map[string]string{BackendOPA: opa.URL + "/v1/data/edgenet/", BackendGatekeeper: provider.URL} {
+		t.Run(backend, func(t *testing.T) {
+			util.OK(t, Configure(backend, url, ""))
+			decision, err := Evaluate(context.TODO(), HookTenantRequestApproval, object{Email: "john.doe@edge-net.org"})
+			util.OK(t, err)
+			util.Equals(t, true, decision.Denied())
+			util.Equals(t, "not an academic institution; unknown domain", decision.Reason())
+			util.Equals(t, false, decision.Approve)
+
+			decision, err = Evaluate(context.TODO(), HookTenantRequestApproval, object{Email: "john.doe@sorbonne-universite.fr"})
+			util.OK(t, err)
+			util.Equals(t, &Decision{Approve: true}, decision)
+
+			decision, err = Evaluate(context.TODO(), HookTenantRequestApproval, object{Email: "john.doe@lip6.fr"})
+			util.OK(t, err)
+			util.Equals(t, &Decision{}, decision)
+		})
+	}
+	t.Run("undefined", func(t *testing.T) {
+		util.OK(t, Configure(BackendOPA, opa.URL+"/v1/data/edgenet", ""))
+		decision, err := Evaluate(context.TODO(), HookTenantEstablishment, object{Email: "john.doe@edge-net.org"})
+		util.OK(t, err)
+		util.Equals(t, &Decision{}, decision)
+	})
+	t.Run("unreachable", func(t *testing.T) {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "policy compilation failed", http.StatusInternalServerError)
+		}))
+		defer failing.Close()
+		util.OK(t, Configure(BackendOPA, failing.URL, ""))
+		_, err := Evaluate(context.TODO(), HookTenantEstablishment, object{})
+		util.Assert(t, err != nil, "failure of the backend ignored")
+	})
+	util.Assert(t, Configure("kyverno", opa.URL, "") != nil, "unknown backend accepted")
+}
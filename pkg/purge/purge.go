@@ -0,0 +1,220 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package purge removes the personal data a deleted tenant leaves behind, and emits a signed
+// deletion report that the cluster keeps as a record of the purge. A tenant is purged on deletion
+// when it carries the purge annotation, the finalizer holds the deletion until the report is out:
+//
+//	kubectl annotate tenant <name> edge-net.io/purge=true
+//	kubectl delete tenant <name>
+//
+// The garbage collector removes the objects of the tenant in any case. The purge removes the
+// objects holding personal data itself beforehand, so that the report can tell they are gone.
+package purge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/audit"
+	clientset "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+	"github.com/EdgeNet-project/edgenet/pkg/signature"
+	"github.com/EdgeNet-project/edgenet/pkg/storage"
+	"github.com/EdgeNet-project/edgenet/pkg/usercert"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// Annotation asks for the tenant to be purged on deletion
+	Annotation = "edge-net.io/purge"
+	// Finalizer holds the deletion of the tenant until it is purged
+	Finalizer = "edge-net.io/purge"
+	// ReportKind is the kind the reports are signed as
+	ReportKind = "DeletionReport"
+	// reportKey is the key of the report in the data of its config map
+	reportKey = "report.json"
+)
+
+// The kinds of the objects a purge removes
+const (
+	KindCertificate               = "Certificate"
+	KindSecret                    = "Secret"
+	KindCertificateSigningRequest = "CertificateSigningRequest"
+	KindTenantAudit               = "TenantAudit"
+	KindArchivedTenantRequest     = "ArchivedTenantRequest"
+	KindArchivedRoleRequest       = "ArchivedRoleRequest"
+	KindArchivedTenantAudit       = "ArchivedTenantAudit"
+)
+
+// ReportNamespace is the namespace of the config maps holding the reports
+var ReportNamespace = "edgenet"
+
+// Signer signs the reports, the reports cannot be emitted without it
+var Signer *signature.Signer
+
+// ErrNoSigningKey is returned when a report is to be emitted without a signing key
+var ErrNoSigningKey = errors.New("no signing key for the deletion reports")
+
+// now is replaced in the tests to give the reports a known time
+var now = time.Now
+
+// Report tells what the purge of a tenant removed. It holds no personal data, only the names of
+// the removed objects.
+type Report struct {
+	Tenant    string      `json:"tenant"`
+	TenantUID string      `json:"tenantUID"`
+	Time      metav1.Time `json:"time"`
+	Removed   []Removed   `json:"removed"`
+}
+
+// Removed lists the objects of a kind the purge removed
+type Removed struct {
+	Kind  string   `json:"kind"`
+	Names []string `json:"names"`
+}
+
+// Requested tells whether the tenant is to be purged on deletion
+func Requested(tenant *corev1alpha.Tenant) bool {
+	return tenant.GetAnnotations()[Annotation] == "true"
+}
+
+// Pending tells whether the tenant still waits for its purge
+func Pending(tenant *corev1alpha.Tenant) bool {
+	for _, finalizer := range tenant.GetFinalizers() {
+		if finalizer == Finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// Purge removes the personal data of the tenant: the client certificates of its users, the
+// secrets generated in its namespaces, the certificate signing requests left over, the audit
+// records naming an actor, and the archived requests and audit records of the tenant. A purge that
+// fails can run again, it picks up what is left.
+func Purge(ctx context.Context, kubeclientset kubernetes.Interface, edgenetclientset clientset.Interface, tenant *corev1alpha.Tenant, namespaces []string) (*Report, error) {
+	report := &Report{Tenant: tenant.GetName(), TenantUID: string(tenant.GetUID()), Time: metav1.NewTime(now())}
+
+	certificates, err := usercert.Purge(ctx, kubeclientset, tenant.GetName())
+	if err != nil {
+		return nil, fmt.Errorf("certificates: %w", err)
+	}
+	report.Removed = append(report.Removed, Removed{Kind: KindCertificate, Names: certificates})
+
+	secrets := []string{}
+	for _, namespace := range namespaces {
+		secretRaw, err := kubeclientset.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{LabelSelector: "edge-net.io/generated=true"})
+		if err != nil {
+			return nil, fmt.Errorf("secrets: %w", err)
+		}
+		for _, secret := range secretRaw.Items {
+			if err := kubeclientset.CoreV1().Secrets(namespace).Delete(ctx, secret.GetName(), metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("secrets: %w", err)
+			}
+			secrets = append(secrets, fmt.Sprintf("%s/%s", namespace, secret.GetName()))
+		}
+	}
+	report.Removed = append(report.Removed, Removed{Kind: KindSecret, Names: secrets})
+
+	// The signing requests of the certificates are named after the tenant
+	csrs := []string{}
+	csrRaw, err := kubeclientset.CertificatesV1().CertificateSigningRequests().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("certificate signing requests: %w", err)
+	}
+	for _, csr := range csrRaw.Items {
+		if !strings.HasPrefix(csr.GetName(), fmt.Sprintf("edgenet-%s-", tenant.GetName())) {
+			continue
+		}
+		if err := kubeclientset.CertificatesV1().CertificateSigningRequests().Delete(ctx, csr.GetName(), metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("certificate signing requests: %w", err)
+		}
+		csrs = append(csrs, csr.GetName())
+	}
+	report.Removed = append(report.Removed, Removed{Kind: KindCertificateSigningRequest, Names: csrs})
+
+	records, err := audit.Forget(ctx, edgenetclientset, tenant.GetName())
+	if err != nil {
+		return nil, fmt.Errorf("audit records: %w", err)
+	}
+	report.Removed = append(report.Removed, Removed{Kind: KindTenantAudit, Names: records})
+
+	// The archive keeps the requests and the audit records once they leave etcd
+	if storage.Enabled() {
+		archived, err := storage.Forget(ctx, tenant.GetName(), storage.KindTenantRequest, storage.KindRoleRequest, storage.KindTenantAudit)
+		if err != nil {
+			return nil, fmt.Errorf("archive: %w", err)
+		}
+		report.Removed = append(report.Removed,
+			Removed{Kind: KindArchivedTenantRequest, Names: archived[storage.KindTenantRequest]},
+			Removed{Kind: KindArchivedRoleRequest, Names: archived[storage.KindRoleRequest]},
+			Removed{Kind: KindArchivedTenantAudit, Names: archived[storage.KindTenantAudit]})
+	}
+	return report, nil
+}
+
+// Emit signs the report and keeps it in a config map of the report namespace. A report already
+// emitted for the tenant is left as is.
+func Emit(ctx context.Context, kubeclientset kubernetes.Interface, report *Report) (*corev1.ConfigMap, error) {
+	if Signer == nil {
+		return nil, ErrNoSigningKey
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: ReportName(report.Tenant, report.TenantUID), Namespace: ReportNamespace,
+		Labels: map[string]string{"edge-net.io/generated": "true", "edge-net.io/deletion-report": "true"}},
+		Data: map[string]string{reportKey: string(data)}}
+	if err := Signer.Sign(ReportKind, configMap, report); err != nil {
+		return nil, err
+	}
+	created, err := kubeclientset.CoreV1().ConfigMaps(ReportNamespace).Create(ctx, configMap, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		return kubeclientset.CoreV1().ConfigMaps(ReportNamespace).Get(ctx, configMap.GetName(), metav1.GetOptions{})
+	}
+	return created, err
+}
+
+// ReportName returns the name of the config map holding the deletion report of the tenant
+func ReportName(tenant, tenantUID string) string {
+	if len(tenantUID) > 8 {
+		tenantUID = tenantUID[:8]
+	}
+	return fmt.Sprintf("deletion-report-%s-%s", tenant, tenantUID)
+}
+
+// Verify checks the signature of the report in the config map against the public key, and
+// returns the report
+func Verify(configMap *corev1.ConfigMap, publicKeyPEM string) (*Report, error) {
+	report := new(Report)
+	if err := json.Unmarshal([]byte(configMap.Data[reportKey]), report); err != nil {
+		return nil, fmt.Errorf("malformed report: %v", err)
+	}
+	if err := signature.VerifyKey(publicKeyPEM, ReportKind, configMap, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
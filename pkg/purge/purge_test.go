@@ -0,0 +1,158 @@
+package purge
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/audit"
+	"github.com/EdgeNet-project/edgenet/pkg/credential"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	"github.com/EdgeNet-project/edgenet/pkg/signature"
+	"github.com/EdgeNet-project/edgenet/pkg/storage"
+	"github.com/EdgeNet-project/edgenet/pkg/usercert"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+)
+
+// newKeys returns a signer and the PEM encoded public key it signs for
+func newKeys(t *testing.T) (*signature.Signer, string) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	util.OK(t, err)
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	util.OK(t, err)
+	signer, err := signature.NewSigner("edgenet-purge", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}))
+	util.OK(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	util.OK(t, err)
+	return signer, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+}
+
+func newSecret(namespace, name string, labels map[string]string) *corev1.Secret {
+	return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels}}
+}
+
+func newRecord(name, tenant, actor string) *corev1alpha.TenantAudit {
+	return &corev1alpha.TenantAudit{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{audit.TenantLabel: tenant}},
+		Spec: corev1alpha.TenantAuditSpec{Tenant: tenant, Action: audit.TenantEnabled, Actor: actor}}
+}
+
+func TestPurge(t *testing.T) {
+	generated := map[string]string{"edge-net.io/generated": "true"}
+	certificate := newSecret("lip6", "certificate-0a1b2c3d", map[string]string{"edge-net.io/generated": "true", credential.LabelCredential: "certificate"})
+	certificate.SetAnnotations(map[string]string{credential.AnnotationUser: "john.doe@edge-net.org", usercert.AnnotationID: "0a1b2c3d"})
+	kubeclientset := testclient.NewSimpleClientset(
+		certificate,
+		newSecret("lip6", "registry-credentials", generated),
+		newSecret("lip6-course", "registry-credentials", generated),
+		newSecret("lip6-course", "database", nil),
+		newSecret("nyu", "registry-credentials", generated),
+		&certificatesv1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{Name: "edgenet-lip6-4e5f6a7b"}},
+		&certificatesv1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{Name: "edgenet-nyu-4e5f6a7b"}},
+	)
+	edgenetclientset := edgenettestclient.NewSimpleClientset(
+		newRecord("lip6-a", "lip6", "admin@edge-net.org"),
+		newRecord("lip6-b", "lip6", ""),
+		newRecord("nyu-a", "nyu", "admin@edge-net.org"),
+	)
+	tenant := &corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "lip6", UID: "8a2c3f9b-0d4e-4b7a-9c1f-2e3d4c5b6a79"}}
+	now = func() time.Time { return time.Date(2021, 10, 1, 9, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	report, err := Purge(context.TODO(), kubeclientset, edgenetclientset, tenant, []string{"lip6", "lip6-course"})
+	util.OK(t, err)
+	removed := map[string][]string{}
+	for _, r := range report.Removed {
+		removed[r.Kind] = r.Names
+	}
+	util.Equals(t, map[string][]string{
+		KindCertificate:               {"certificate-0a1b2c3d"},
+		KindSecret:                    {"lip6/registry-credentials", "lip6-course/registry-credentials"},
+		KindCertificateSigningRequest: {"edgenet-lip6-4e5f6a7b"},
+		KindTenantAudit:               {"lip6-a"},
+	}, removed)
+
+	secrets, err := kubeclientset.CoreV1().Secrets("").List(context.TODO(), metav1.ListOptions{})
+	util.OK(t, err)
+	util.Equals(t, 2, len(secrets.Items))
+	csrs, err := kubeclientset.CertificatesV1().CertificateSigningRequests().List(context.TODO(), metav1.ListOptions{})
+	util.OK(t, err)
+	util.Equals(t, 1, len(csrs.Items))
+	records, err := edgenetclientset.CoreV1alpha().TenantAudits().List(context.TODO(), metav1.ListOptions{})
+	util.OK(t, err)
+	util.Equals(t, 2, len(records.Items))
+
+	// Purging again finds nothing left
+	report, err = Purge(context.TODO(), kubeclientset, edgenetclientset, tenant, []string{"lip6", "lip6-course"})
+	util.OK(t, err)
+	for _, r := range report.Removed {
+		util.Equals(t, 0, len(r.Names))
+	}
+
+	t.Run("archive", func(t *testing.T) {
+		store := storage.NewMemoryStore()
+		storage.Default = store
+		defer func() { storage.Default = nil }()
+		util.OK(t, storage.Archive(context.TODO(), storage.KindTenantRequest, "lip6", "lip6", now(), map[string]string{"email": "john.doe@edge-net.org"}))
+		util.OK(t, storage.Archive(context.TODO(), storage.KindRoleRequest, "lip6-course/john-doe", "lip6", now(), map[string]string{"email": "john.doe@edge-net.org"}))
+		util.OK(t, storage.Archive(context.TODO(), storage.KindTenantAudit, "lip6-c", "lip6", now(), map[string]string{"actor": "admin@edge-net.org"}))
+		util.OK(t, storage.Archive(context.TODO(), storage.KindTenantUsage, "lip6", "lip6", now(), map[string]int{"pods": 2}))
+
+		report, err := Purge(context.TODO(), kubeclientset, edgenetclientset, tenant, []string{"lip6", "lip6-course"})
+		util.OK(t, err)
+		removed := map[string][]string{}
+		for _, r := range report.Removed {
+			removed[r.Kind] = r.Names
+		}
+		util.Equals(t, []string{"lip6"}, removed[KindArchivedTenantRequest])
+		util.Equals(t, []string{"lip6-course/john-doe"}, removed[KindArchivedRoleRequest])
+		util.Equals(t, []string{"lip6-c"}, removed[KindArchivedTenantAudit])
+		// The usage samples hold no personal data
+		usages, err := store.List(context.TODO(), storage.KindTenantUsage, "lip6")
+		util.OK(t, err)
+		util.Equals(t, 1, len(usages))
+	})
+}
+
+func TestEmit(t *testing.T) {
+	kubeclientset := testclient.NewSimpleClientset()
+	report := &Report{Tenant: "lip6", TenantUID: "8a2c3f9b-0d4e-4b7a-9c1f-2e3d4c5b6a79", Time: metav1.Date(2021, 10, 1, 9, 0, 0, 0, time.UTC),
+		Removed: []Removed{{Kind: KindCertificate, Names: []string{"certificate-0a1b2c3d"}}}}
+
+	Signer = nil
+	_, err := Emit(context.TODO(), kubeclientset, report)
+	util.Equals(t, ErrNoSigningKey, err)
+
+	signer, publicKey := newKeys(t)
+	Signer = signer
+	defer func() { Signer = nil }()
+	configMap, err := Emit(context.TODO(), kubeclientset, report)
+	util.OK(t, err)
+	util.Equals(t, "deletion-report-lip6-8a2c3f9b", configMap.GetName())
+	verified, err := Verify(configMap, publicKey)
+	util.OK(t, err)
+	util.Equals(t, report.Removed, verified.Removed)
+
+	// Emitting again leaves the report as is
+	_, err = Emit(context.TODO(), kubeclientset, report)
+	util.OK(t, err)
+
+	// A report changed afterwards does not verify
+	configMap.Data[reportKey] = `{"tenant":"lip6","removed":[]}`
+	_, err = Verify(configMap, publicKey)
+	util.Assert(t, err != nil, "tampered report verified")
+	_, otherKey := newKeys(t)
+	configMap, err = kubeclientset.CoreV1().ConfigMaps(ReportNamespace).Get(context.TODO(), "deletion-report-lip6-8a2c3f9b", metav1.GetOptions{})
+	util.OK(t, err)
+	_, err = Verify(configMap, otherKey)
+	util.Assert(t, err != nil, "report verified with another key")
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package quota measures what the pods of a tenant request against its tenant resource quota,
+// whatever the resource quotas of its namespaces say, and implements the admission webhook that
+// holds the tenants with a hard block to their quota.
+package quota
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Charge returns the resources the pod counts for against a quota, under the names a resource
+// quota knows them by. An init container runs alone so the pod requests the most of its
+// containers together or any of its init containers.
+func Charge(pod *corev1.Pod) corev1.ResourceList {
+	requests, limits := corev1.ResourceList{}, corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResources(requests, container.Resources.Requests)
+		addResources(limits, container.Resources.Limits)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		maxResources(requests, container.Resources.Requests)
+		maxResources(limits, container.Resources.Limits)
+	}
+	charge := corev1.ResourceList{corev1.ResourcePods: *resource.NewQuantity(1, resource.DecimalSI)}
+	for name, quantity := range requests {
+		charge[name] = quantity.DeepCopy()
+		charge[corev1.ResourceName(fmt.Sprintf("requests.%s", name))] = quantity.DeepCopy()
+	}
+	for name, quantity := range limits {
+		charge[corev1.ResourceName(fmt.Sprintf("limits.%s", name))] = quantity.DeepCopy()
+	}
+	return charge
+}
+
+// Usage sums the charges of the pods, the pods that terminated count for nothing
+func Usage(pods []*corev1.Pod) corev1.ResourceList {
+	usage := corev1.ResourceList{}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		addResources(usage, Charge(pod))
+	}
+	return usage
+}
+
+// Exceeding returns the resources of the quota whose usage is beyond the percent of the quota,
+// sorted by name
+func Exceeding(usage, quota corev1.ResourceList, percent int) []corev1.ResourceName {
+	exceeding := []corev1.ResourceName{}
+	for name, quantity := range quota {
+		used := usage[name]
+		if float64(used.MilliValue())*100 > float64(quantity.MilliValue())*float64(percent) {
+			exceeding = append(exceeding, name)
+		}
+	}
+	sort.Slice(exceeding, func(i, j int) bool { return exceeding[i] < exceeding[j] })
+	return exceeding
+}
+
+// Percent returns the usage of the resource as a percentage of its quota
+func Percent(used, quota resource.Quantity) int64 {
+	if quota.MilliValue() <= 0 {
+		return 0
+	}
+	return int64(float64(used.MilliValue()) * 100 / float64(quota.MilliValue()))
+}
+
+func addResources(total, resources corev1.ResourceList) {
+	for name, quantity := range resources {
+		sum := total[name]
+		sum.Add(quantity)
+		total[name] = sum
+	}
+}
+
+func maxResources(total, resources corev1.ResourceList) {
+	for name, quantity := range resources {
+		if current, ok := total[name]; !ok || quantity.Cmp(current) > 0 {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
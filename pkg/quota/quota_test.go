@@ -0,0 +1,173 @@
+package quota
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	kubetestclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+func newPod(namespace, name, cpu string, phase corev1.PodPhase) *corev1.Pod {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}, Status: corev1.PodStatus{Phase: phase}}
+	container := corev1.Container{Name: "nginx"}
+	if cpu != "" {
+		container.Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}
+		container.Resources.Limits = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}
+	}
+	pod.Spec.Containers = []corev1.Container{container}
+	return pod
+}
+
+func newTenantResourceQuota(name, cpu string, hardBlock bool) *corev1alpha.TenantResourceQuota {
+	return &corev1alpha.TenantResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1alpha.TenantResourceQuotaSpec{
+			Claim:     map[string]corev1alpha.ResourceTuning{"initial": {ResourceList: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}}},
+			HardBlock: hardBlock,
+		}}
+}
+
+func TestCharge(t *testing.T) {
+	pod := newPod("lip6-lab", "nginx", "500m", corev1.PodRunning)
+	pod.Spec.Containers = append(pod.Spec.Containers, *newPod("lip6-lab", "nginx", "250m", "").Spec.Containers[0].DeepCopy())
+	pod.Spec.InitContainers = []corev1.Container{newPod("lip6-lab", "nginx", "1", "").Spec.Containers[0]}
+
+	charge := Charge(pod)
+	util.Equals(t, "1", charge.Cpu().String())
+	requests := charge["requests.cpu"]
+	util.Equals(t, "1", requests.String())
+	limits := charge["limits.cpu"]
+	util.Equals(t, "1", limits.String())
+	util.Equals(t, "1", charge.Pods().String())
+
+	usage := Usage([]*corev1.Pod{newPod("lip6-lab", "a", "500m", corev1.PodRunning), newPod("lip6-lab", "b", "250m", corev1.PodPending),
+		newPod("lip6-lab", "c", "2", corev1.PodSucceeded)})
+	util.Equals(t, "750m", usage.Cpu().String())
+	util.Equals(t, "2", usage.Pods().String())
+}
+
+func TestExceeding(t *testing.T) {
+	quota := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("4Gi")}
+	cases := map[string]struct {
+		usage    corev1.ResourceList
+		percent  int
+		expected []corev1.ResourceName
+	}{
+		"within":       {corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}, 80, []corev1.ResourceName{}},
+		"at the limit": {corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1600m")}, 80, []corev1.ResourceName{}},
+		"beyond":       {corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1700m"), corev1.ResourceMemory: resource.MustParse("4Gi")}, 80, []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}},
+		"hard":         {corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("5Gi")}, 100, []corev1.ResourceName{corev1.ResourceMemory}},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			util.Equals(t, tc.expected, Exceeding(tc.usage, quota, tc.percent))
+		})
+	}
+	util.Equals(t, int64(85), Percent(resource.MustParse("1700m"), resource.MustParse("2")))
+}
+
+func newWebhook(t *testing.T) *Webhook {
+	tenantNamespace := func(name, tenant string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"edge-net.io/tenant": tenant}}}
+	}
+	kubeclientset := kubetestclient.NewSimpleClientset(
+		tenantNamespace("lip6-lab", "lip6-lab"),
+		tenantNamespace("lip6-lab-course", "lip6-lab"),
+		tenantNamespace("unipi-lab", "unipi-lab"),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+		newPod("lip6-lab", "a", "1", corev1.PodRunning),
+		newPod("lip6-lab-course", "b", "500m", corev1.PodRunning),
+		newPod("lip6-lab-course", "c", "4", corev1.PodFailed),
+		newPod("unipi-lab", "a", "2", corev1.PodRunning),
+	)
+	edgenetclientset := edgenettestclient.NewSimpleClientset(
+		newTenantResourceQuota("lip6-lab", "2", true),
+		newTenantResourceQuota("unipi-lab", "2", false),
+	)
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeclientset, 0)
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, 0)
+	webhook := NewWebhook(
+		kubeInformerFactory.Core().V1().Namespaces(),
+		kubeInformerFactory.Core().V1().Pods(),
+		edgenetInformerFactory.Core().V1alpha().TenantResourceQuotas(),
+	)
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	kubeInformerFactory.Start(stopCh)
+	edgenetInformerFactory.Start(stopCh)
+	util.OK(t, webhook.WaitForCacheSync(stopCh))
+	return webhook
+}
+
+func TestAdmit(t *testing.T) {
+	webhook := newWebhook(t)
+	cases := map[string]struct {
+		pod      *corev1.Pod
+		admitted bool
+	}{
+		"within the quota":     {newPod("lip6-lab-course", "d", "500m", ""), true},
+		"beyond the quota":     {newPod("lip6-lab", "d", "600m", ""), false},
+		"no request":           {newPod("lip6-lab", "d", "", ""), true},
+		"without a hard block": {newPod("unipi-lab", "b", "1", ""), true},
+		"out of the tenants":   {newPod("kube-system", "d", "8", ""), true},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			err := webhook.Admit(tc.pod)
+			util.Equals(t, tc.admitted, err == nil)
+		})
+	}
+	util.Equals(t, "pod takes tenant lip6-lab beyond its quota: cpu (2100m of 2)", webhook.Admit(newPod("lip6-lab", "d", "600m", "")).Error())
+}
+
+func TestHandler(t *testing.T) {
+	server := httptest.NewServer(newWebhook(t).Handler())
+	defer server.Close()
+
+	post := func(pod *corev1.Pod) *admissionv1.AdmissionResponse {
+		// The namespace of the request is left out of the pod, as it is by kubectl
+		namespace := pod.GetNamespace()
+		pod.SetNamespace("")
+		raw, err := json.Marshal(pod)
+		util.OK(t, err)
+		review := admissionv1.AdmissionReview{TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+			Request: &admissionv1.AdmissionRequest{UID: "1", Namespace: namespace, Object: runtime.RawExtension{Raw: raw}}}
+		payload, err := json.Marshal(review)
+		util.OK(t, err)
+		response, err := http.Post(server.URL, "application/json", bytes.NewReader(payload))
+		util.OK(t, err)
+		defer response.Body.Close()
+		util.OK(t, json.NewDecoder(response.Body).Decode(&review))
+		util.Equals(t, "AdmissionReview", review.Kind)
+		return review.Response
+	}
+	response := post(newPod("lip6-lab", "d", "600m", ""))
+	util.Equals(t, false, response.Allowed)
+	util.Equals(t, int32(http.StatusForbidden), response.Result.Code)
+	response = post(newPod("lip6-lab", "d", "500m", ""))
+	util.Equals(t, true, response.Allowed)
+	util.Equals(t, "1", string(response.UID))
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quota
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// Webhook rejects the pods that would take a tenant with a hard block beyond its tenant resource
+// quota. The usage is summed from the pods of the tenant, the resource quotas of its namespaces
+// may have drifted from the tenant resource quota.
+type Webhook struct {
+	namespacesLister           corelisters.NamespaceLister
+	namespacesSynced           cache.InformerSynced
+	podsLister                 corelisters.PodLister
+	podsSynced                 cache.InformerSynced
+	tenantResourceQuotasLister listers.TenantResourceQuotaLister
+	tenantResourceQuotasSynced cache.InformerSynced
+}
+
+// NewWebhook returns a new webhook
+func NewWebhook(
+	namespaceInformer coreinformers.NamespaceInformer,
+	podInformer coreinformers.PodInformer,
+	tenantResourceQuotaInformer informers.TenantResourceQuotaInformer) *Webhook {
+	return &Webhook{
+		namespacesLister:           namespaceInformer.Lister(),
+		namespacesSynced:           namespaceInformer.Informer().HasSynced,
+		podsLister:                 podInformer.Lister(),
+		podsSynced:                 podInformer.Informer().HasSynced,
+		tenantResourceQuotasLister: tenantResourceQuotaInformer.Lister(),
+		tenantResourceQuotasSynced: tenantResourceQuotaInformer.Informer().HasSynced,
+	}
+}
+
+// WaitForCacheSync blocks until the caches of the webhook are synced or stopCh is closed
+func (w *Webhook) WaitForCacheSync(stopCh <-chan struct{}) error {
+	if ok := cache.WaitForCacheSync(stopCh, w.namespacesSynced, w.podsSynced, w.tenantResourceQuotasSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+	return nil
+}
+
+// Handler serves the admission reviews of the pods
+func (w *Webhook) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		review := admissionv1.AdmissionReview{}
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil || review.Request == nil {
+			http.Error(rw, "invalid admission review", http.StatusBadRequest)
+			return
+		}
+		response := &admissionv1.AdmissionResponse{UID: review.Request.UID, Allowed: true}
+		pod := new(corev1.Pod)
+		if err := json.Unmarshal(review.Request.Object.Raw, pod); err != nil {
+			response.Allowed = false
+			response.Result = &metav1.Status{Status: metav1.StatusFailure, Code: http.StatusBadRequest, Message: err.Error()}
+		} else {
+			if pod.GetNamespace() == "" {
+				pod.SetNamespace(review.Request.Namespace)
+			}
+			if err := w.Admit(pod); err != nil {
+				response.Allowed = false
+				response.Result = &metav1.Status{Status: metav1.StatusFailure, Code: http.StatusForbidden, Reason: metav1.StatusReasonForbidden, Message: err.Error()}
+			}
+		}
+		review.Request = nil
+		review.Response = response
+		rw.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(rw).Encode(review); err != nil {
+			klog.ErrorS(err, "Couldn't write the admission review")
+		}
+	})
+}
+
+// Admit returns why the pod is rejected, or nil if it is admitted. A pod is rejected if its
+// tenant has a hard block and the pod takes the tenant beyond its quota of a resource the pod
+// requests.
+func (w *Webhook) Admit(pod *corev1.Pod) error {
+	namespace, err := w.namespacesLister.Get(pod.GetNamespace())
+	if err != nil {
+		return nil
+	}
+	tenant := namespace.GetLabels()["edge-net.io/tenant"]
+	if tenant == "" {
+		return nil
+	}
+	tenantResourceQuota, err := w.tenantResourceQuotasLister.Get(tenant)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			klog.ErrorS(err, "Couldn't get the tenant resource quota", "tenant", tenant)
+		}
+		return nil
+	}
+	if !tenantResourceQuota.Spec.HardBlock {
+		return nil
+	}
+	pods, err := w.tenantPods(tenant)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't list the pods of the tenant", "tenant", tenant)
+		return nil
+	}
+	charge := Charge(pod)
+	usage := Usage(pods)
+	addResources(usage, charge)
+	_, assignedQuota := tenantResourceQuota.Fetch()
+	beyond := []string{}
+	for _, name := range Exceeding(usage, assignedQuota, 100) {
+		if quantity, ok := charge[name]; ok && !quantity.IsZero() {
+			used, quota := usage[name], assignedQuota[name]
+			beyond = append(beyond, fmt.Sprintf("%s (%s of %s)", name, used.String(), quota.String()))
+		}
+	}
+	if len(beyond) == 0 {
+		return nil
+	}
+	klog.V(4).InfoS("Rejected the pod beyond the quota of its tenant", "pod", klog.KObj(pod), "tenant", tenant)
+	return fmt.Errorf("pod takes tenant %s beyond its quota: %s", tenant, strings.Join(beyond, ", "))
+}
+
+// tenantPods returns the pods in the namespaces of the tenant
+func (w *Webhook) tenantPods(tenant string) ([]*corev1.Pod, error) {
+	namespaces, err := w.namespacesLister.List(labels.SelectorFromSet(labels.Set{"edge-net.io/tenant": tenant}))
+	if err != nil {
+		return nil, err
+	}
+	pods := []*corev1.Pod{}
+	for _, namespace := range namespaces {
+		namespacePods, err := w.podsLister.Pods(namespace.GetName()).List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, namespacePods...)
+	}
+	return pods, nil
+}
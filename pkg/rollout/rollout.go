@@ -0,0 +1,248 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rollout carries a template change, such as a new RBAC archetype or new default
+// network policies, over to the tenants in waves. A canary wave goes first, each wave is
+// verified by probes before the next one starts, and the rollout halts once the failures
+// exceed the tolerated ratio.
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Step applies the new template to a tenant
+type Step func(ctx context.Context, tenant *corev1alpha.Tenant) error
+
+// Probe verifies a tenant once the step is applied
+type Probe func(ctx context.Context, tenant *corev1alpha.Tenant) error
+
+// Options of a rollout
+type Options struct {
+	// Canary names the tenants of the first wave, the first CanarySize tenants by name if empty.
+	Canary []string
+	// CanarySize is the number of tenants in the canary wave when Canary is empty.
+	CanarySize int
+	// WaveSize is the number of tenants in each wave after the canary, all remaining tenants if zero.
+	WaveSize int
+	// Interval lets the controllers settle before a wave is probed, and spaces the waves out.
+	Interval time.Duration
+	// MaxFailureRatio is the ratio of failed tenants among the tenants rolled out so far beyond
+	// which the rollout halts. A single failure in the canary wave halts the rollout regardless.
+	MaxFailureRatio float64
+	// Wait pauses the rollout, time.Sleep if nil.
+	Wait func(time.Duration)
+}
+
+// Result is the outcome of the rollout for a tenant
+type Result struct {
+	Tenant string
+	Wave   int
+	Err    error
+}
+
+// Report summarizes a rollout
+type Report struct {
+	Results []Result
+	// Pending are the tenants left untouched when the rollout halts
+	Pending []string
+	Halted  bool
+	Reason  string
+}
+
+// Failed returns the results of the tenants for which the step or a probe failed
+func (r Report) Failed() []Result {
+	failed := []Result{}
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Run rolls the step out over the tenants in waves, and verifies each wave with the probes. The
+// rollout halts between two tenants once the context is done.
+func Run(ctx context.Context, tenants []corev1alpha.Tenant, step Step, probes []Probe, options Options) Report {
+	report := Report{}
+	wait := time.Sleep
+	if options.Wait != nil {
+		wait = options.Wait
+	}
+	waves := plan(tenants, options)
+	failures, done := 0, 0
+	for i, wave := range waves {
+		if i > 0 {
+			wait(options.Interval)
+		}
+		results := []Result{}
+		for j := range wave {
+			if ctx.Err() != nil {
+				break
+			}
+			results = append(results, Result{Tenant: wave[j].GetName(), Wave: i, Err: step(ctx, &wave[j])})
+		}
+		// The controllers pick the changes up before the probes run
+		wait(options.Interval)
+		for j := range results {
+			if results[j].Err != nil {
+				continue
+			}
+			for _, probe := range probes {
+				if err := probe(ctx, &wave[j]); err != nil {
+					results[j].Err = fmt.Errorf("probe failed: %w", err)
+					break
+				}
+			}
+		}
+		for _, result := range results {
+			if result.Err != nil {
+				failures++
+			}
+		}
+		done += len(wave)
+		report.Results = append(report.Results, results...)
+
+		halted := false
+		if ctx.Err() != nil {
+			halted = true
+			report.Reason = fmt.Sprintf("rollout interrupted: %s", ctx.Err())
+		} else if i == 0 && failures > 0 {
+			halted = true
+			report.Reason = fmt.Sprintf("%d of %d canary tenants failed", failures, len(wave))
+		} else if float64(failures)/float64(done) > options.MaxFailureRatio {
+			halted = true
+			report.Reason = fmt.Sprintf("%d of %d tenants failed, above the tolerated ratio of %.2f", failures, done, options.MaxFailureRatio)
+		}
+		if halted {
+			report.Halted = true
+			for _, tenant := range wave[len(results):] {
+				report.Pending = append(report.Pending, tenant.GetName())
+			}
+			for _, pending := range waves[i+1:] {
+				for _, tenant := range pending {
+					report.Pending = append(report.Pending, tenant.GetName())
+				}
+			}
+			return report
+		}
+	}
+	return report
+}
+
+// plan splits the tenants into the canary wave and the following waves
+func plan(tenants []corev1alpha.Tenant, options Options) [][]corev1alpha.Tenant {
+	sorted := make([]corev1alpha.Tenant, len(tenants))
+	copy(sorted, tenants)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].GetName() < sorted[j].GetName() })
+
+	canary, rest := []corev1alpha.Tenant{}, []corev1alpha.Tenant{}
+	named := make(map[string]bool)
+	for _, name := range options.Canary {
+		named[name] = true
+	}
+	for _, tenant := range sorted {
+		if named[tenant.GetName()] || (len(options.Canary) == 0 && len(canary) < options.CanarySize) {
+			canary = append(canary, tenant)
+		} else {
+			rest = append(rest, tenant)
+		}
+	}
+	waves := [][]corev1alpha.Tenant{}
+	if len(canary) > 0 {
+		waves = append(waves, canary)
+	}
+	waveSize := options.WaveSize
+	if waveSize <= 0 {
+		waveSize = len(rest)
+	}
+	for len(rest) > 0 {
+		if waveSize > len(rest) {
+			waveSize = len(rest)
+		}
+		waves = append(waves, rest[:waveSize])
+		rest = rest[waveSize:]
+	}
+	return waves
+}
+
+// RBACStep brings the RBAC of a tenant in line with the archetype
+func RBACStep(ctx context.Context, tenant *corev1alpha.Tenant) error {
+	plan, err := access.DiffTenantRBAC(ctx, tenant)
+	if err != nil {
+		return err
+	}
+	return access.ApplyPlan(ctx, plan)
+}
+
+// PoliciesStep returns the step that applies the default network policies to a tenant, the
+// tenants that opted out of the baseline keep their policies
+func PoliciesStep(clusterUID string) Step {
+	return func(ctx context.Context, tenant *corev1alpha.Tenant) error {
+		if tenant.GetAnnotations()["edge-net.io/baseline-policies"] == "false" {
+			return nil
+		}
+		ownerReferences := []metav1.OwnerReference{*metav1.NewControllerRef(tenant, corev1alpha.SchemeGroupVersion.WithKind("Tenant"))}
+		return access.ApplyBaselineClusterPolicies(ctx, tenant.GetName(), tenant.GetName(), string(tenant.GetUID()), clusterUID, tenant.Spec.Egress, ownerReferences)
+	}
+}
+
+// RBACProbe makes sure the RBAC of a tenant has converged to the archetype
+func RBACProbe(ctx context.Context, tenant *corev1alpha.Tenant) error {
+	plan, err := access.DiffTenantRBAC(ctx, tenant)
+	if err != nil {
+		return err
+	}
+	if !plan.Empty() {
+		return fmt.Errorf("%d changes pending", len(plan.Changes))
+	}
+	return nil
+}
+
+// OwnerAccessProbe returns the probe that asks the API server whether the tenant owner can
+// still list pods in the core namespace, as the tenant controller does
+func OwnerAccessProbe(kubeclientset kubernetes.Interface) Probe {
+	return func(ctx context.Context, tenant *corev1alpha.Tenant) error {
+		review := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User: tenant.Spec.Contact.Email,
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: tenant.GetName(),
+					Verb:      "list",
+					Resource:  "pods",
+				},
+			},
+		}
+		result, err := kubeclientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+		if err != nil {
+			return err
+		}
+		if !result.Status.Allowed {
+			return fmt.Errorf("tenant owner cannot list pods in %s", tenant.GetName())
+		}
+		return nil
+	}
+}
@@ -0,0 +1,124 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	kubescheme "k8s.io/client-go/kubernetes/scheme"
+)
+
+func tenants(names ...string) []corev1alpha.Tenant {
+	tenantList := []corev1alpha.Tenant{}
+	for _, name := range names {
+		tenant := corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		tenant.Spec.Enabled = true
+		tenant.Spec.Contact = corev1alpha.Contact{Handle: name, Email: fmt.Sprintf("%s@edge-net.org", name)}
+		tenantList = append(tenantList, tenant)
+	}
+	return tenantList
+}
+
+func waves(report Report) map[string]int {
+	tenantWaves := make(map[string]int)
+	for _, result := range report.Results {
+		tenantWaves[result.Tenant] = result.Wave
+	}
+	return tenantWaves
+}
+
+func TestRun(t *testing.T) {
+	applied := []string{}
+	step := func(ctx context.Context, tenant *corev1alpha.Tenant) error {
+		applied = append(applied, tenant.GetName())
+		return nil
+	}
+	waited := time.Duration(0)
+	options := Options{CanarySize: 1, WaveSize: 2, Interval: time.Minute, Wait: func(d time.Duration) { waited += d }}
+
+	report := Run(context.TODO(), tenants("lip6", "edgenet", "nyu", "ple", "gwdg"), step, nil, options)
+	util.Equals(t, false, report.Halted)
+	util.Equals(t, []string{"edgenet", "gwdg", "lip6", "nyu", "ple"}, applied)
+	util.Equals(t, map[string]int{"edgenet": 0, "gwdg": 1, "lip6": 1, "nyu": 2, "ple": 2}, waves(report))
+	// Each wave settles before it is probed, and the waves are spaced out
+	util.Equals(t, 5*time.Minute, waited)
+
+	t.Run("named canary", func(t *testing.T) {
+		options := Options{Canary: []string{"ple"}, Wait: func(time.Duration) {}}
+		report := Run(context.TODO(), tenants("lip6", "edgenet", "ple"), step, nil, options)
+		util.Equals(t, map[string]int{"ple": 0, "edgenet": 1, "lip6": 1}, waves(report))
+	})
+}
+
+func TestHalt(t *testing.T) {
+	broken := map[string]bool{}
+	step := func(ctx context.Context, tenant *corev1alpha.Tenant) error { return nil }
+	probe := func(ctx context.Context, tenant *corev1alpha.Tenant) error {
+		if broken[tenant.GetName()] {
+			return fmt.Errorf("%s is broken", tenant.GetName())
+		}
+		return nil
+	}
+	options := Options{CanarySize: 1, WaveSize: 2, MaxFailureRatio: 0.4, Wait: func(time.Duration) {}}
+
+	cases := map[string]struct {
+		broken  []string
+		halted  bool
+		pending []string
+	}{
+		"canary":        {[]string{"edgenet"}, true, []string{"gwdg", "lip6", "nyu", "ple"}},
+		"below ratio":   {[]string{"gwdg"}, false, nil},
+		"above ratio":   {[]string{"gwdg", "lip6"}, true, []string{"nyu", "ple"}},
+		"no failure":    {[]string{}, false, nil},
+		"last wave":     {[]string{"nyu", "ple"}, false, nil},
+		"last wave too": {[]string{"lip6", "nyu", "ple"}, true, nil},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			broken = map[string]bool{}
+			for _, name := range tc.broken {
+				broken[name] = true
+			}
+			report := Run(context.TODO(), tenants("lip6", "edgenet", "nyu", "ple", "gwdg"), step, []Probe{probe}, options)
+			util.Equals(t, tc.halted, report.Halted)
+			util.Equals(t, tc.pending, report.Pending)
+			util.Equals(t, len(tc.broken), len(report.Failed()))
+		})
+	}
+}
+
+func TestInterrupt(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	step := func(ctx context.Context, tenant *corev1alpha.Tenant) error {
+		if tenant.GetName() == "gwdg" {
+			cancel()
+		}
+		return nil
+	}
+	options := Options{CanarySize: 1, WaveSize: 2, Wait: func(time.Duration) {}}
+	report := Run(ctx, tenants("lip6", "edgenet", "nyu", "ple", "gwdg"), step, nil, options)
+	util.Equals(t, true, report.Halted)
+	util.Equals(t, map[string]int{"edgenet": 0, "gwdg": 1}, waves(report))
+	util.Equals(t, []string{"lip6", "nyu", "ple"}, report.Pending)
+}
+
+func TestRBACStep(t *testing.T) {
+	kubeclientset := testclient.NewSimpleClientset()
+	kubeclientset.PrependReactor("patch", "*", util.ApplyReactor(kubeclientset.Tracker(), kubescheme.Scheme))
+	access.Clientset = kubeclientset
+	access.EdgenetClientset = edgenettestclient.NewSimpleClientset()
+	tenant := tenants("edgenet")[0]
+
+	util.Assert(t, RBACProbe(context.TODO(), &tenant) != nil, "probe passes before the rollout")
+	util.OK(t, RBACStep(context.TODO(), &tenant))
+	util.OK(t, RBACProbe(context.TODO(), &tenant))
+}
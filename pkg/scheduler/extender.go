@@ -0,0 +1,309 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package scheduler implements a scheduler extender that keeps the pods of a tenant on the nodes
+// the tenant is entitled to at scheduling time, whatever the affinity of the pods says. The
+// kube-scheduler sends the pod and the candidate nodes to the filter and the prioritize verbs.
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// AnnotationGeoSelector holds a label selector on the geolocation labels of the nodes, such as
+// "edge-net.io/country-iso in (FR,DE)", the pod is confined to the nodes it selects
+const AnnotationGeoSelector = "edge-net.io/geo-selector"
+
+// MaxExtenderPriority is the score of the nodes contributed by the tenant of the pod
+const MaxExtenderPriority int64 = 10
+
+// ExtenderArgs mirrors the arguments the kube-scheduler sends to the extender, the nodes are sent
+// by name alone if the extender is declared as node cache capable
+type ExtenderArgs struct {
+	Pod       *corev1.Pod      `json:"pod"`
+	Nodes     *corev1.NodeList `json:"nodes,omitempty"`
+	NodeNames *[]string        `json:"nodenames,omitempty"`
+}
+
+// ExtenderFilterResult mirrors the result of the filter verb, the nodes that fit the pod are
+// returned in the form they were sent in
+type ExtenderFilterResult struct {
+	Nodes       *corev1.NodeList  `json:"nodes,omitempty"`
+	NodeNames   *[]string         `json:"nodenames,omitempty"`
+	FailedNodes map[string]string `json:"failedNodes,omitempty"`
+	Error       string            `json:"error,omitempty"`
+}
+
+// HostPriority mirrors the score of a node returned by the prioritize verb
+type HostPriority struct {
+	Host  string `json:"host"`
+	Score int64  `json:"score"`
+}
+
+// Extender filters and scores the nodes after the entitlements of the tenants
+type Extender struct {
+	nodesLister             corelisters.NodeLister
+	nodesSynced             cache.InformerSynced
+	namespacesLister        corelisters.NamespaceLister
+	namespacesSynced        cache.InformerSynced
+	tenantsLister           listers.TenantLister
+	tenantsSynced           cache.InformerSynced
+	nodePoolsLister         listers.NodePoolLister
+	nodePoolsSynced         cache.InformerSynced
+	nodeContributionsLister listers.NodeContributionLister
+	nodeContributionsSynced cache.InformerSynced
+}
+
+// NewExtender returns a new extender
+func NewExtender(
+	nodeInformer coreinformers.NodeInformer,
+	namespaceInformer coreinformers.NamespaceInformer,
+	tenantInformer informers.TenantInformer,
+	nodePoolInformer informers.NodePoolInformer,
+	nodeContributionInformer informers.NodeContributionInformer) *Extender {
+	return &Extender{
+		nodesLister:             nodeInformer.Lister(),
+		nodesSynced:             nodeInformer.Informer().HasSynced,
+		namespacesLister:        namespaceInformer.Lister(),
+		namespacesSynced:        namespaceInformer.Informer().HasSynced,
+		tenantsLister:           tenantInformer.Lister(),
+		tenantsSynced:           tenantInformer.Informer().HasSynced,
+		nodePoolsLister:         nodePoolInformer.Lister(),
+		nodePoolsSynced:         nodePoolInformer.Informer().HasSynced,
+		nodeContributionsLister: nodeContributionInformer.Lister(),
+		nodeContributionsSynced: nodeContributionInformer.Informer().HasSynced,
+	}
+}
+
+// WaitForCacheSync blocks until the caches of the extender are synced or stopCh is closed
+func (e *Extender) WaitForCacheSync(stopCh <-chan struct{}) error {
+	if ok := cache.WaitForCacheSync(stopCh, e.nodesSynced, e.namespacesSynced, e.tenantsSynced, e.nodePoolsSynced, e.nodeContributionsSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+	return nil
+}
+
+// Handler serves the filter and the prioritize verbs
+func (e *Extender) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/filter", func(w http.ResponseWriter, r *http.Request) {
+		args, err := decodeArgs(r)
+		if err != nil {
+			writeJSON(w, &ExtenderFilterResult{Error: err.Error()})
+			return
+		}
+		writeJSON(w, e.Filter(args))
+	})
+	mux.HandleFunc("/prioritize", func(w http.ResponseWriter, r *http.Request) {
+		args, err := decodeArgs(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, e.Prioritize(args))
+	})
+	return mux
+}
+
+func decodeArgs(r *http.Request) (*ExtenderArgs, error) {
+	args := new(ExtenderArgs)
+	if err := json.NewDecoder(r.Body).Decode(args); err != nil {
+		return nil, err
+	}
+	if args.Pod == nil {
+		return nil, fmt.Errorf("no pod to schedule")
+	}
+	return args, nil
+}
+
+func writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		klog.ErrorS(err, "Couldn't write the response of the scheduler extender")
+	}
+}
+
+// entitlement is what the pod is entitled to, the tenant is empty for the pods out of the tenants.
+// A tenant confined to node pools is entitled to the nodes of the pools alone.
+type entitlement struct {
+	tenant      string
+	namespace   string
+	confined    bool
+	nodePools   []*corev1alpha.NodePool
+	geoSelector labels.Selector
+}
+
+// entitlementOf returns the entitlement of the pod, the pools of the tenant that do not exist
+// select no node
+func (e *Extender) entitlementOf(pod *corev1.Pod) (*entitlement, error) {
+	entitled := &entitlement{namespace: pod.GetNamespace(), geoSelector: labels.Everything()}
+	if expression, ok := pod.GetAnnotations()[AnnotationGeoSelector]; ok {
+		selector, err := labels.Parse(expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation: %w", AnnotationGeoSelector, err)
+		}
+		entitled.geoSelector = selector
+	}
+	namespace, err := e.namespacesLister.Get(pod.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	entitled.tenant = namespace.GetLabels()["edge-net.io/tenant"]
+	if entitled.tenant == "" {
+		return entitled, nil
+	}
+	tenant, err := e.tenantsLister.Get(entitled.tenant)
+	if err != nil {
+		return nil, err
+	}
+	entitled.confined = len(tenant.Spec.NodePools) > 0
+	for _, poolName := range tenant.Spec.NodePools {
+		nodePool, err := e.nodePoolsLister.Get(poolName)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		entitled.nodePools = append(entitled.nodePools, nodePool)
+	}
+	return entitled, nil
+}
+
+// fits returns why the pod does not fit the node, or an empty string if it does
+func (e *Extender) fits(entitled *entitlement, node *corev1.Node) string {
+	if !entitled.geoSelector.Matches(labels.Set(node.GetLabels())) {
+		return fmt.Sprintf("node is outside the geolocation %s", entitled.geoSelector.String())
+	}
+	if entitled.tenant == "" {
+		return ""
+	}
+	if entitled.confined {
+		inPool := false
+		for _, nodePool := range entitled.nodePools {
+			selector, err := metav1.LabelSelectorAsSelector(&nodePool.Spec.Selector)
+			if err == nil && selector.Matches(labels.Set(node.GetLabels())) {
+				inPool = true
+				break
+			}
+		}
+		if !inPool {
+			return fmt.Sprintf("node is outside the node pools of tenant %s", entitled.tenant)
+		}
+	}
+	if contribution := e.contributionOf(node); contribution != nil && len(contribution.Spec.Limitations) > 0 {
+		if contribution.Spec.Tenant != nil && *contribution.Spec.Tenant == entitled.tenant {
+			return ""
+		}
+		for _, limitation := range contribution.Spec.Limitations {
+			if (limitation.Kind == "Tenant" && limitation.Indentifier == entitled.tenant) ||
+				(limitation.Kind == "Namespace" && limitation.Indentifier == entitled.namespace) {
+				return ""
+			}
+		}
+		return "node is reserved for other tenants by its contribution"
+	}
+	return ""
+}
+
+// contributionOf returns the contribution the node joined with, nil for the nodes of the cluster
+func (e *Extender) contributionOf(node *corev1.Node) *corev1alpha.NodeContribution {
+	contribution, err := e.nodeContributionsLister.Get(strings.TrimSuffix(node.GetName(), ".edge-net.io"))
+	if err != nil {
+		return nil
+	}
+	return contribution
+}
+
+// nodesOf returns the nodes of the arguments, looked up in the cache if sent by name
+func (e *Extender) nodesOf(args *ExtenderArgs) []*corev1.Node {
+	nodes := []*corev1.Node{}
+	if args.Nodes != nil {
+		for i := range args.Nodes.Items {
+			nodes = append(nodes, &args.Nodes.Items[i])
+		}
+		return nodes
+	}
+	if args.NodeNames != nil {
+		for _, nodeName := range *args.NodeNames {
+			node, err := e.nodesLister.Get(nodeName)
+			if err != nil {
+				node = &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: nodeName}}
+			}
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// Filter keeps the nodes the pod is entitled to
+func (e *Extender) Filter(args *ExtenderArgs) *ExtenderFilterResult {
+	entitled, err := e.entitlementOf(args.Pod)
+	if err != nil {
+		return &ExtenderFilterResult{Error: err.Error()}
+	}
+	result := &ExtenderFilterResult{FailedNodes: map[string]string{}}
+	fitting := []corev1.Node{}
+	fittingNames := []string{}
+	for _, node := range e.nodesOf(args) {
+		if reason := e.fits(entitled, node); reason != "" {
+			result.FailedNodes[node.GetName()] = reason
+			continue
+		}
+		fitting = append(fitting, *node)
+		fittingNames = append(fittingNames, node.GetName())
+	}
+	if args.Nodes != nil {
+		result.Nodes = &corev1.NodeList{Items: fitting}
+	} else {
+		result.NodeNames = &fittingNames
+	}
+	klog.V(4).InfoS("Filtered the nodes", "pod", klog.KObj(args.Pod), "fitting", len(fittingNames), "failed", len(result.FailedNodes))
+	return result
+}
+
+// Prioritize prefers the nodes contributed by the tenant of the pod
+func (e *Extender) Prioritize(args *ExtenderArgs) []HostPriority {
+	priorities := []HostPriority{}
+	tenant := ""
+	if namespace, err := e.namespacesLister.Get(args.Pod.GetNamespace()); err == nil {
+		tenant = namespace.GetLabels()["edge-net.io/tenant"]
+	}
+	for _, node := range e.nodesOf(args) {
+		priority := HostPriority{Host: node.GetName()}
+		if contribution := e.contributionOf(node); tenant != "" && contribution != nil && contribution.Spec.Tenant != nil && *contribution.Spec.Tenant == tenant {
+			priority.Score = MaxExtenderPriority
+		}
+		priorities = append(priorities, priority)
+	}
+	return priorities
+}
@@ -0,0 +1,162 @@
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	kubetestclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+func newNode(name string, labels map[string]string) *corev1.Node {
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+}
+
+func newNamespace(name, tenant string) *corev1.Namespace {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}}}
+	if tenant != "" {
+		namespace.Labels["edge-net.io/tenant"] = tenant
+	}
+	return namespace
+}
+
+func newContribution(name, tenant string, limitations ...corev1alpha.Limitations) *corev1alpha.NodeContribution {
+	return &corev1alpha.NodeContribution{ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1alpha.NodeContributionSpec{Tenant: &tenant, Host: name, Enabled: true, Limitations: limitations}}
+}
+
+func newPod(namespace string, annotations map[string]string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "nginx", Namespace: namespace, Annotations: annotations}}
+}
+
+func newExtender(t *testing.T) *Extender {
+	kubeclientset := kubetestclient.NewSimpleClientset(
+		newNode("paris-1.edge-net.io", map[string]string{"edge-net.io/country-iso": "FR", "edge-net.io/nodepool": "teaching"}),
+		newNode("lyon-1.edge-net.io", map[string]string{"edge-net.io/country-iso": "FR"}),
+		newNode("berlin-1.edge-net.io", map[string]string{"edge-net.io/country-iso": "DE"}),
+		newNamespace("lip6-lab", "lip6-lab"),
+		newNamespace("unipi-lab", "unipi-lab"),
+		newNamespace("unipi-lab-course", "unipi-lab"),
+		newNamespace("kube-system", ""),
+	)
+	edgenetclientset := edgenettestclient.NewSimpleClientset(
+		&corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "lip6-lab"}},
+		&corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "unipi-lab"}, Spec: corev1alpha.TenantSpec{NodePools: []string{"teaching", "gone"}}},
+		&corev1alpha.NodePool{ObjectMeta: metav1.ObjectMeta{Name: "teaching"},
+			Spec: corev1alpha.NodePoolSpec{Selector: metav1.LabelSelector{MatchLabels: map[string]string{"edge-net.io/nodepool": "teaching"}}}},
+		newContribution("lyon-1", "lip6-lab", corev1alpha.Limitations{Kind: "Namespace", Indentifier: "unipi-lab-course"}),
+		newContribution("berlin-1", "lip6-lab"),
+	)
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeclientset, 0)
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, 0)
+	extender := NewExtender(
+		kubeInformerFactory.Core().V1().Nodes(),
+		kubeInformerFactory.Core().V1().Namespaces(),
+		edgenetInformerFactory.Core().V1alpha().Tenants(),
+		edgenetInformerFactory.Core().V1alpha().NodePools(),
+		edgenetInformerFactory.Core().V1alpha().NodeContributions(),
+	)
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	kubeInformerFactory.Start(stopCh)
+	edgenetInformerFactory.Start(stopCh)
+	util.OK(t, extender.WaitForCacheSync(stopCh))
+	return extender
+}
+
+func TestFilter(t *testing.T) {
+	extender := newExtender(t)
+	nodeNames := []string{"paris-1.edge-net.io", "lyon-1.edge-net.io", "berlin-1.edge-net.io"}
+
+	cases := map[string]struct {
+		pod      *corev1.Pod
+		expected []string
+	}{
+		"system pod":                  {newPod("kube-system", nil), nodeNames},
+		"contributor":                 {newPod("lip6-lab", nil), nodeNames},
+		"geo constraint":              {newPod("lip6-lab", map[string]string{AnnotationGeoSelector: "edge-net.io/country-iso in (FR)"}), nodeNames[:2]},
+		"node pools":                  {newPod("unipi-lab", nil), nodeNames[:1]},
+		"system pod in a geolocation": {newPod("kube-system", map[string]string{AnnotationGeoSelector: "edge-net.io/country-iso=DE"}), nodeNames[2:]},
+	}
+	for k, tc := range cases {
+		t.Run(k, func(t *testing.T) {
+			names := append([]string{}, nodeNames...)
+			result := extender.Filter(&ExtenderArgs{Pod: tc.pod, NodeNames: &names})
+			util.Equals(t, "", result.Error)
+			util.Equals(t, tc.expected, *result.NodeNames)
+			util.Equals(t, len(nodeNames)-len(tc.expected), len(result.FailedNodes))
+		})
+	}
+	t.Run("reservation", func(t *testing.T) {
+		nodes := &corev1.NodeList{Items: []corev1.Node{*newNode("lyon-1.edge-net.io", map[string]string{"edge-net.io/nodepool": "teaching"})}}
+		result := extender.Filter(&ExtenderArgs{Pod: newPod("unipi-lab", nil), Nodes: nodes})
+		util.Equals(t, 0, len(result.Nodes.Items))
+		util.Equals(t, "node is reserved for other tenants by its contribution", result.FailedNodes["lyon-1.edge-net.io"])
+		result = extender.Filter(&ExtenderArgs{Pod: newPod("unipi-lab-course", nil), Nodes: nodes})
+		util.Equals(t, 1, len(result.Nodes.Items))
+	})
+	t.Run("invalid geo constraint", func(t *testing.T) {
+		result := extender.Filter(&ExtenderArgs{Pod: newPod("lip6-lab", map[string]string{AnnotationGeoSelector: "edge-net.io/country-iso in FR"}), NodeNames: &nodeNames})
+		util.Assert(t, result.Error != "", "invalid geo constraint accepted")
+	})
+}
+
+func TestPrioritize(t *testing.T) {
+	extender := newExtender(t)
+	nodeNames := []string{"paris-1.edge-net.io", "lyon-1.edge-net.io", "berlin-1.edge-net.io"}
+
+	util.Equals(t, []HostPriority{{"paris-1.edge-net.io", 0}, {"lyon-1.edge-net.io", MaxExtenderPriority}, {"berlin-1.edge-net.io", MaxExtenderPriority}},
+		extender.Prioritize(&ExtenderArgs{Pod: newPod("lip6-lab", nil), NodeNames: &nodeNames}))
+	util.Equals(t, []HostPriority{{"paris-1.edge-net.io", 0}, {"lyon-1.edge-net.io", 0}, {"berlin-1.edge-net.io", 0}},
+		extender.Prioritize(&ExtenderArgs{Pod: newPod("unipi-lab", nil), NodeNames: &nodeNames}))
+}
+
+func TestHandler(t *testing.T) {
+	server := httptest.NewServer(newExtender(t).Handler())
+	defer server.Close()
+
+	post := func(verb string, body interface{}, result interface{}) *http.Response {
+		payload, err := json.Marshal(body)
+		util.OK(t, err)
+		response, err := http.Post(server.URL+verb, "application/json", bytes.NewReader(payload))
+		util.OK(t, err)
+		defer response.Body.Close()
+		if result != nil {
+			util.OK(t, json.NewDecoder(response.Body).Decode(result))
+		}
+		return response
+	}
+	nodeNames := []string{"paris-1.edge-net.io", "berlin-1.edge-net.io"}
+	filtered := new(ExtenderFilterResult)
+	post("/filter", &ExtenderArgs{Pod: newPod("unipi-lab", nil), NodeNames: &nodeNames}, filtered)
+	util.Equals(t, []string{"paris-1.edge-net.io"}, *filtered.NodeNames)
+	util.Equals(t, "node is outside the node pools of tenant unipi-lab", filtered.FailedNodes["berlin-1.edge-net.io"])
+
+	priorities := []HostPriority{}
+	post("/prioritize", &ExtenderArgs{Pod: newPod("lip6-lab", nil), NodeNames: &nodeNames}, &priorities)
+	util.Equals(t, MaxExtenderPriority, priorities[1].Score)
+
+	response := post("/prioritize", map[string]runtime.RawExtension{}, nil)
+	util.Equals(t, http.StatusBadRequest, response.StatusCode)
+}
@@ -14,6 +14,7 @@ limitations under the License.
 package signals
 
 import (
+	"context"
 	"os"
 	"os/signal"
 )
@@ -38,3 +39,14 @@ func SetupSignalHandler() (stopCh <-chan struct{}) {
 
 	return stop
 }
+
+// ContextFor returns a context that is cancelled once the stop channel is closed, so that the
+// requests in flight are abandoned at shutdown.
+func ContextFor(stopCh <-chan struct{}) context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		defer cancel()
+		<-stopCh
+	}()
+	return ctx
+}
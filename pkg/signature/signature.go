@@ -0,0 +1,194 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package signature
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Annotations carrying the signature of a request and the name of the tenant key that made it
+const (
+	SignatureAnnotation = "edge-net.io/signature"
+	KeyAnnotation       = "edge-net.io/signing-key"
+)
+
+// ErrUnsigned is returned for an unsigned request of a tenant that requires signed requests
+var ErrUnsigned = errors.New("the tenant requires signed requests")
+
+// Payload returns the bytes a request is signed over: its kind, namespace, name, and spec. The
+// metadata aside from these is left out so that the labels and the status can change freely.
+func Payload(kind string, object metav1.Object, spec interface{}) ([]byte, error) {
+	return json.Marshal(struct {
+		Kind      string      `json:"kind"`
+		Namespace string      `json:"namespace"`
+		Name      string      `json:"name"`
+		Spec      interface{} `json:"spec"`
+	}{kind, object.GetNamespace(), object.GetName(), spec})
+}
+
+// Signer signs the requests of a tenant with one of its private keys
+type Signer struct {
+	KeyName string
+	key     crypto.Signer
+}
+
+// NewSigner returns a signer of the PEM encoded Ed25519 or ECDSA private key, the key name is
+// the one the tenant lists its public key under
+func NewSigner(keyName string, privateKeyPEM []byte) (*Signer, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, errors.New("no PEM block found in the private key")
+	}
+	var key interface{}
+	var err error
+	if block.Type == "EC PRIVATE KEY" {
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	} else {
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch key := key.(type) {
+	case ed25519.PrivateKey:
+		return &Signer{KeyName: keyName, key: key}, nil
+	case *ecdsa.PrivateKey:
+		return &Signer{KeyName: keyName, key: key}, nil
+	}
+	return nil, fmt.Errorf("unsupported private key type %T", key)
+}
+
+// Sign signs the request and sets the signature annotations on it
+func (s *Signer) Sign(kind string, object metav1.Object, spec interface{}) error {
+	payload, err := Payload(kind, object, spec)
+	if err != nil {
+		return err
+	}
+	var signature []byte
+	if _, ok := s.key.(ed25519.PrivateKey); ok {
+		signature, err = s.key.Sign(rand.Reader, payload, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(payload)
+		signature, err = s.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		return err
+	}
+	annotations := object.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[SignatureAnnotation] = base64.StdEncoding.EncodeToString(signature)
+	annotations[KeyAnnotation] = s.KeyName
+	object.SetAnnotations(annotations)
+	return nil
+}
+
+// Verify checks the signature of a request against the keys of the tenant. An unsigned request
+// passes unless the tenant requires signed requests, a signed one must verify in any case.
+func Verify(tenant *corev1alpha.Tenant, kind string, object metav1.Object, spec interface{}) error {
+	annotations := object.GetAnnotations()
+	encoded, signed := annotations[SignatureAnnotation]
+	if !signed {
+		if tenant.Spec.RequireSignedRequests {
+			return ErrUnsigned
+		}
+		return nil
+	}
+	keyName := annotations[KeyAnnotation]
+	var signingKey *corev1alpha.SigningKey
+	for i := range tenant.Spec.SigningKeys {
+		if tenant.Spec.SigningKeys[i].Name == keyName {
+			signingKey = &tenant.Spec.SigningKeys[i]
+			break
+		}
+	}
+	if signingKey == nil {
+		return fmt.Errorf("the tenant has no signing key %q", keyName)
+	}
+	return verify(signingKey.PublicKey, keyName, encoded, kind, object, spec)
+}
+
+// VerifyKey checks the signature of an object against the public key it was signed for, the
+// object must be signed
+func VerifyKey(publicKeyPEM, kind string, object metav1.Object, spec interface{}) error {
+	annotations := object.GetAnnotations()
+	encoded, signed := annotations[SignatureAnnotation]
+	if !signed {
+		return errors.New("the object is not signed")
+	}
+	return verify(publicKeyPEM, annotations[KeyAnnotation], encoded, kind, object, spec)
+}
+
+// verify checks the base64 encoded signature of the payload of an object against a public key
+func verify(publicKeyPEM, keyName, encoded, kind string, object metav1.Object, spec interface{}) error {
+	publicKey, err := ParsePublicKey(publicKeyPEM)
+	if err != nil {
+		return fmt.Errorf("signing key %q: %v", keyName, err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("malformed signature: %v", err)
+	}
+	payload, err := Payload(kind, object, spec)
+	if err != nil {
+		return err
+	}
+	valid := false
+	switch publicKey := publicKey.(type) {
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(publicKey, payload, signature)
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(payload)
+		valid = ecdsa.VerifyASN1(publicKey, digest[:], signature)
+	}
+	if !valid {
+		return fmt.Errorf("the signature does not match signing key %q", keyName)
+	}
+	return nil
+}
+
+// ParsePublicKey parses a PEM encoded Ed25519 or ECDSA public key
+func ParsePublicKey(publicKeyPEM string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(publicKeyPEM))
+	if block == nil {
+		return nil, errors.New("no PEM block found in the public key")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch publicKey.(type) {
+	case ed25519.PublicKey, *ecdsa.PublicKey:
+		return publicKey, nil
+	}
+	return nil, fmt.Errorf("unsupported public key type %T", publicKey)
+}
@@ -0,0 +1,80 @@
+package signature
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func encodeKeys(t *testing.T, privateKey, publicKey interface{}) ([]byte, string) {
+	privateBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	util.OK(t, err)
+	publicBytes, err := x509.MarshalPKIXPublicKey(publicKey)
+	util.OK(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateBytes}),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicBytes}))
+}
+
+func newSubNamespace() *corev1alpha.SubNamespace {
+	return &corev1alpha.SubNamespace{ObjectMeta: metav1.ObjectMeta{Name: "experiments", Namespace: "lip6"},
+		Spec: corev1alpha.SubNamespaceSpec{Workspace: &corev1alpha.Workspace{
+			ResourceAllocation: map[corev1.ResourceName]resource.Quantity{corev1.ResourceCPU: resource.MustParse("2")}}}}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	util.OK(t, err)
+	edPrivatePEM, edPublicPEM := encodeKeys(t, edPrivate, edPublic)
+	ecPrivate, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	util.OK(t, err)
+	ecPrivatePEM, ecPublicPEM := encodeKeys(t, ecPrivate, &ecPrivate.PublicKey)
+
+	tenant := &corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "lip6"}, Spec: corev1alpha.TenantSpec{
+		SigningKeys: []corev1alpha.SigningKey{{Name: "ci", PublicKey: edPublicPEM}, {Name: "operator", PublicKey: ecPublicPEM}}}}
+
+	for keyName, privateKeyPEM := range map[string][]byte{"ci": edPrivatePEM, "operator": ecPrivatePEM} {
+		t.Run(keyName, func(t *testing.T) {
+			signer, err := NewSigner(keyName, privateKeyPEM)
+			util.OK(t, err)
+			subnamespace := newSubNamespace()
+			util.OK(t, signer.Sign("SubNamespace", subnamespace, subnamespace.Spec))
+			util.Equals(t, keyName, subnamespace.GetAnnotations()[KeyAnnotation])
+			util.OK(t, Verify(tenant, "SubNamespace", subnamespace, subnamespace.Spec))
+
+			subnamespace.SetLabels(map[string]string{"edge-net.io/generated": "true"})
+			util.OK(t, Verify(tenant, "SubNamespace", subnamespace, subnamespace.Spec))
+
+			subnamespace.Spec.Workspace.ResourceAllocation[corev1.ResourceCPU] = resource.MustParse("4")
+			util.Assert(t, Verify(tenant, "SubNamespace", subnamespace, subnamespace.Spec) != nil, "Tampered spec verified")
+		})
+	}
+
+	signer, err := NewSigner("unknown", edPrivatePEM)
+	util.OK(t, err)
+	subnamespace := newSubNamespace()
+	util.OK(t, signer.Sign("SubNamespace", subnamespace, subnamespace.Spec))
+	util.Equals(t, `the tenant has no signing key "unknown"`, Verify(tenant, "SubNamespace", subnamespace, subnamespace.Spec).Error())
+
+	subnamespace = newSubNamespace()
+	util.OK(t, Verify(tenant, "SubNamespace", subnamespace, subnamespace.Spec))
+	tenant.Spec.RequireSignedRequests = true
+	util.Equals(t, ErrUnsigned, Verify(tenant, "SubNamespace", subnamespace, subnamespace.Spec))
+}
+
+func TestParsePublicKey(t *testing.T) {
+	_, err := ParsePublicKey("not a key")
+	util.Equals(t, "no PEM block found in the public key", err.Error())
+	_, err = NewSigner("ci", []byte("not a key"))
+	util.Equals(t, "no PEM block found in the private key", err.Error())
+}
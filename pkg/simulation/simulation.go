@@ -0,0 +1,423 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package simulation tells whether a bundle of objects would fit in a namespace
+// before it is applied, and which constraint would block it otherwise.
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Constraints that can block a bundle
+const (
+	ConstraintQuota      = "ResourceQuota"
+	ConstraintLimitRange = "LimitRange"
+	ConstraintPlacement  = "Placement"
+)
+
+// Blocker is a constraint that would reject an object of the bundle
+type Blocker struct {
+	Constraint string
+	Object     string
+	Message    string
+}
+
+// Result holds the outcome of a simulation
+type Result struct {
+	Blockers []Blocker
+}
+
+// Fits returns true if nothing would block the bundle
+func (r Result) Fits() bool {
+	return len(r.Blockers) == 0
+}
+
+// workload is a pod template with the number of pods expected from it
+type workload struct {
+	object   string
+	template corev1.PodTemplateSpec
+	replicas int64
+}
+
+// Paths of the pod template and the number of pods in the workload kinds
+var workloadPaths = map[string]struct {
+	template []string
+	replicas []string
+}{
+	"Deployment":  {[]string{"spec", "template"}, []string{"spec", "replicas"}},
+	"ReplicaSet":  {[]string{"spec", "template"}, []string{"spec", "replicas"}},
+	"StatefulSet": {[]string{"spec", "template"}, []string{"spec", "replicas"}},
+	"DaemonSet":   {[]string{"spec", "template"}, nil},
+	"Job":         {[]string{"spec", "template"}, []string{"spec", "parallelism"}},
+	"CronJob":     {[]string{"spec", "jobTemplate", "spec", "template"}, []string{"spec", "jobTemplate", "spec", "parallelism"}},
+}
+
+// Simulate checks the objects against the resource quotas and the limit ranges of the namespace
+// and the nodes of the cluster. Usage of the namespace is taken from the quota status.
+func Simulate(ctx context.Context, clientset kubernetes.Interface, namespace string, objects []*unstructured.Unstructured) (Result, error) {
+	result := Result{}
+	limitRangeRaw, err := clientset.CoreV1().LimitRanges(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, err
+	}
+	resourceQuotaRaw, err := clientset.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, err
+	}
+	nodeRaw, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return result, err
+	}
+
+	requested := corev1.ResourceList{}
+	add := func(name corev1.ResourceName, quantity resource.Quantity) {
+		total := requested[name]
+		total.Add(quantity)
+		requested[name] = total
+	}
+	workloads := []workload{}
+	for _, object := range objects {
+		if object.GetNamespace() != "" && object.GetNamespace() != namespace {
+			return result, fmt.Errorf("%s/%s is in namespace %s", object.GetKind(), object.GetName(), object.GetNamespace())
+		}
+		name := fmt.Sprintf("%s/%s", object.GetKind(), object.GetName())
+		switch object.GetKind() {
+		case "Pod":
+			pod := new(corev1.Pod)
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, pod); err != nil {
+				return result, err
+			}
+			workloads = append(workloads, workload{object: name, template: corev1.PodTemplateSpec{ObjectMeta: pod.ObjectMeta, Spec: pod.Spec}, replicas: 1})
+		case "PersistentVolumeClaim":
+			claim := new(corev1.PersistentVolumeClaim)
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, claim); err != nil {
+				return result, err
+			}
+			add(corev1.ResourcePersistentVolumeClaims, resource.MustParse("1"))
+			if storage, ok := claim.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+				add(corev1.ResourceRequestsStorage, storage)
+			}
+		case "Service":
+			add(corev1.ResourceServices, resource.MustParse("1"))
+		case "ConfigMap":
+			add(corev1.ResourceConfigMaps, resource.MustParse("1"))
+		case "Secret":
+			add(corev1.ResourceSecrets, resource.MustParse("1"))
+		default:
+			paths, ok := workloadPaths[object.GetKind()]
+			if !ok {
+				continue
+			}
+			content, found, err := unstructured.NestedMap(object.Object, paths.template...)
+			if err != nil || !found {
+				return result, fmt.Errorf("%s has no pod template", name)
+			}
+			template := corev1.PodTemplateSpec{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(content, &template); err != nil {
+				return result, err
+			}
+			replicas := int64(1)
+			if paths.replicas != nil {
+				if value, found, err := unstructured.NestedInt64(object.Object, paths.replicas...); err == nil && found {
+					replicas = value
+				}
+			} else {
+				// A daemon set runs a pod on every node it can be placed on
+				replicas = int64(len(eligibleNodes(nodeRaw.Items, template.Spec, nil)))
+			}
+			workloads = append(workloads, workload{object: name, template: template, replicas: replicas})
+		}
+	}
+
+	for _, workload := range workloads {
+		requests, limits, blockers := podResources(workload, limitRangeRaw.Items)
+		result.Blockers = append(result.Blockers, blockers...)
+		for name, quantity := range requests {
+			quantity := quantity.DeepCopy()
+			multiply(&quantity, workload.replicas)
+			add(name, quantity)
+			add(corev1.ResourceName("requests."+string(name)), quantity)
+		}
+		for name, quantity := range limits {
+			quantity := quantity.DeepCopy()
+			multiply(&quantity, workload.replicas)
+			add(corev1.ResourceName("limits."+string(name)), quantity)
+		}
+		add(corev1.ResourcePods, *resource.NewQuantity(workload.replicas, resource.DecimalSI))
+
+		if workload.replicas > 0 {
+			reasons := map[string]int{}
+			if len(eligibleNodes(nodeRaw.Items, workload.template.Spec, requests, reasons)) == 0 {
+				result.Blockers = append(result.Blockers, Blocker{Constraint: ConstraintPlacement, Object: workload.object,
+					Message: fmt.Sprintf("no node can run the pods: %s", summarize(reasons))})
+			}
+		}
+	}
+
+	for _, resourceQuota := range resourceQuotaRaw.Items {
+		for name, hard := range resourceQuota.Spec.Hard {
+			quantity, ok := requested[name]
+			if !ok {
+				continue
+			}
+			remaining := hard.DeepCopy()
+			if used, ok := resourceQuota.Status.Used[name]; ok {
+				remaining.Sub(used)
+			}
+			if quantity.Cmp(remaining) == 1 {
+				result.Blockers = append(result.Blockers, Blocker{Constraint: ConstraintQuota, Object: resourceQuota.GetName(),
+					Message: fmt.Sprintf("%s requested %s, %s remaining", name, quantity.String(), remaining.String())})
+			}
+		}
+	}
+	return result, nil
+}
+
+// podResources returns the requests and limits of a pod after the limit range defaults are applied,
+// along with the containers that exceed the maximum of the limit range
+func podResources(workload workload, limitRanges []corev1.LimitRange) (corev1.ResourceList, corev1.ResourceList, []Blocker) {
+	blockers := []Blocker{}
+	containerResources := func(container corev1.Container) (corev1.ResourceList, corev1.ResourceList) {
+		requests := container.Resources.Requests.DeepCopy()
+		limits := container.Resources.Limits.DeepCopy()
+		if requests == nil {
+			requests = corev1.ResourceList{}
+		}
+		if limits == nil {
+			limits = corev1.ResourceList{}
+		}
+		for _, limitRange := range limitRanges {
+			for _, item := range limitRange.Spec.Limits {
+				if item.Type != corev1.LimitTypeContainer {
+					continue
+				}
+				for name, quantity := range item.Default {
+					if _, ok := limits[name]; !ok {
+						limits[name] = quantity.DeepCopy()
+					}
+				}
+				for name, quantity := range item.DefaultRequest {
+					if _, ok := requests[name]; !ok {
+						requests[name] = quantity.DeepCopy()
+					}
+				}
+				for name, max := range item.Max {
+					if limit, ok := limits[name]; !ok {
+						blockers = append(blockers, Blocker{Constraint: ConstraintLimitRange, Object: workload.object,
+							Message: fmt.Sprintf("container %s sets no %s limit while %s caps it at %s", container.Name, name, limitRange.GetName(), max.String())})
+					} else if limit.Cmp(max) == 1 {
+						blockers = append(blockers, Blocker{Constraint: ConstraintLimitRange, Object: workload.object,
+							Message: fmt.Sprintf("container %s %s limit %s is above the maximum %s of %s", container.Name, name, limit.String(), max.String(), limitRange.GetName())})
+					}
+				}
+			}
+		}
+		// The request defaults to the limit when only the limit is set
+		for name, quantity := range limits {
+			if _, ok := requests[name]; !ok {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+		return requests, limits
+	}
+
+	requests, limits := corev1.ResourceList{}, corev1.ResourceList{}
+	for _, container := range workload.template.Spec.Containers {
+		containerRequests, containerLimits := containerResources(container)
+		for name, quantity := range containerRequests {
+			total := requests[name]
+			total.Add(quantity)
+			requests[name] = total
+		}
+		for name, quantity := range containerLimits {
+			total := limits[name]
+			total.Add(quantity)
+			limits[name] = total
+		}
+	}
+	// Init containers run one at a time before the others
+	for _, container := range workload.template.Spec.InitContainers {
+		containerRequests, containerLimits := containerResources(container)
+		for name, quantity := range containerRequests {
+			if current, ok := requests[name]; !ok || quantity.Cmp(current) == 1 {
+				requests[name] = quantity
+			}
+		}
+		for name, quantity := range containerLimits {
+			if current, ok := limits[name]; !ok || quantity.Cmp(current) == 1 {
+				limits[name] = quantity
+			}
+		}
+	}
+	return requests, limits, blockers
+}
+
+// eligibleNodes returns the nodes a pod can be placed on. The reasons of the nodes left out are counted
+// if a map is given.
+func eligibleNodes(nodes []corev1.Node, spec corev1.PodSpec, requests corev1.ResourceList, reasons ...map[string]int) []corev1.Node {
+	count := func(reason string) {
+		for _, tally := range reasons {
+			tally[reason]++
+		}
+	}
+	eligible := []corev1.Node{}
+	for _, node := range nodes {
+		if node.Spec.Unschedulable || !isReady(node) {
+			count("not ready or cordoned")
+			continue
+		}
+		if !labels.SelectorFromSet(spec.NodeSelector).Matches(labels.Set(node.GetLabels())) || !matchesAffinity(node, spec.Affinity) {
+			count("node selector or affinity")
+			continue
+		}
+		if !toleratesTaints(node.Spec.Taints, spec.Tolerations) {
+			count("taints")
+			continue
+		}
+		fits := true
+		for name, quantity := range requests {
+			if allocatable, ok := node.Status.Allocatable[name]; ok && quantity.Cmp(allocatable) == 1 {
+				fits = false
+			}
+		}
+		if !fits {
+			count("allocatable resources")
+			continue
+		}
+		eligible = append(eligible, node)
+	}
+	return eligible
+}
+
+func isReady(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// matchesAffinity checks the node against the node affinity required during scheduling,
+// one of the terms must match
+func matchesAffinity(node corev1.Node, affinity *corev1.Affinity) bool {
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	nodeLabels := labels.Set(node.GetLabels())
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		matches := true
+		for _, expression := range term.MatchExpressions {
+			if !matchesRequirement(nodeLabels, expression) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesRequirement(nodeLabels labels.Set, expression corev1.NodeSelectorRequirement) bool {
+	switch expression.Operator {
+	case corev1.NodeSelectorOpGt, corev1.NodeSelectorOpLt:
+		if !nodeLabels.Has(expression.Key) || len(expression.Values) != 1 {
+			return false
+		}
+		value, err := strconv.ParseInt(nodeLabels.Get(expression.Key), 10, 64)
+		if err != nil {
+			return false
+		}
+		bound, err := strconv.ParseInt(expression.Values[0], 10, 64)
+		if err != nil {
+			return false
+		}
+		if expression.Operator == corev1.NodeSelectorOpGt {
+			return value > bound
+		}
+		return value < bound
+	default:
+		operators := map[corev1.NodeSelectorOperator]selection.Operator{
+			corev1.NodeSelectorOpIn:           selection.In,
+			corev1.NodeSelectorOpNotIn:        selection.NotIn,
+			corev1.NodeSelectorOpExists:       selection.Exists,
+			corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+		}
+		operator, ok := operators[expression.Operator]
+		if !ok {
+			return false
+		}
+		requirement, err := labels.NewRequirement(expression.Key, operator, expression.Values)
+		if err != nil {
+			return false
+		}
+		return requirement.Matches(nodeLabels)
+	}
+}
+
+// toleratesTaints returns true if every taint keeping the pods away is tolerated
+func toleratesTaints(taints []corev1.Taint, tolerations []corev1.Toleration) bool {
+	for i := range taints {
+		if taints[i].Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for j := range tolerations {
+			if tolerations[j].ToleratesTaint(&taints[i]) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+	return true
+}
+
+// multiply scales the quantity by the number of pods
+func multiply(quantity *resource.Quantity, times int64) {
+	*quantity = *resource.NewMilliQuantity(quantity.MilliValue()*times, quantity.Format)
+}
+
+// summarize lists the reasons the nodes are left out in a stable order
+func summarize(reasons map[string]int) string {
+	if len(reasons) == 0 {
+		return "no nodes"
+	}
+	summary := []string{}
+	for reason, count := range reasons {
+		summary = append(summary, fmt.Sprintf("%d %s", count, reason))
+	}
+	sort.Strings(summary)
+	return strings.Join(summary, ", ")
+}
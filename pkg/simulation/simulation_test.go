@@ -0,0 +1,104 @@
+package simulation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/EdgeNet-project/edgenet/pkg/helm"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+)
+
+const bundle = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: experiment
+spec:
+  replicas: 3
+  template:
+    spec:
+      nodeSelector:
+        edge-net.io/city: paris
+      containers:
+      - name: app
+        image: nginx
+        resources:
+          requests:
+            cpu: 500m
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: experiment
+`
+
+func setup() *testclient.Clientset {
+	clientset := testclient.NewSimpleClientset()
+	resourceQuota := &corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: "core-quota"}}
+	resourceQuota.Spec.Hard = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceServices: resource.MustParse("5")}
+	resourceQuota.Status.Used = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}
+	clientset.CoreV1().ResourceQuotas("edgenet").Create(context.TODO(), resourceQuota, metav1.CreateOptions{})
+	limitRange := &corev1.LimitRange{ObjectMeta: metav1.ObjectMeta{Name: "tenant-limits"}}
+	limitRange.Spec.Limits = []corev1.LimitRangeItem{{Type: corev1.LimitTypeContainer,
+		Default: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+		Max:     corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}}}
+	clientset.CoreV1().LimitRanges("edgenet").Create(context.TODO(), limitRange, metav1.CreateOptions{})
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-paris", Labels: map[string]string{"edge-net.io/city": "paris"}}}
+	node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}}
+	node.Status.Allocatable = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")}
+	clientset.CoreV1().Nodes().Create(context.TODO(), node, metav1.CreateOptions{})
+	return clientset
+}
+
+func TestSimulate(t *testing.T) {
+	clientset := setup()
+	objects, err := helm.Decode([]byte(bundle))
+	util.OK(t, err)
+
+	t.Run("quota exceeded", func(t *testing.T) {
+		result, err := Simulate(context.TODO(), clientset, "edgenet", objects)
+		util.OK(t, err)
+		util.Equals(t, false, result.Fits())
+		util.Equals(t, 1, len(result.Blockers))
+		util.Equals(t, ConstraintQuota, result.Blockers[0].Constraint)
+		util.Equals(t, "cpu requested 1500m, 1 remaining", result.Blockers[0].Message)
+	})
+	t.Run("fits", func(t *testing.T) {
+		objects[0].Object["spec"].(map[string]interface{})["replicas"] = int64(2)
+		result, err := Simulate(context.TODO(), clientset, "edgenet", objects)
+		util.OK(t, err)
+		util.Equals(t, true, result.Fits())
+	})
+	t.Run("no node", func(t *testing.T) {
+		objects[0].Object["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})["nodeSelector"] = map[string]interface{}{"edge-net.io/city": "nyc"}
+		result, err := Simulate(context.TODO(), clientset, "edgenet", objects)
+		util.OK(t, err)
+		util.Equals(t, 1, len(result.Blockers))
+		util.Equals(t, ConstraintPlacement, result.Blockers[0].Constraint)
+		util.Equals(t, "no node can run the pods: 1 node selector or affinity", result.Blockers[0].Message)
+	})
+}
+
+func TestLimitRangeMax(t *testing.T) {
+	clientset := setup()
+	objects, err := helm.Decode([]byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: greedy
+spec:
+  containers:
+  - name: app
+    image: nginx
+    resources:
+      limits:
+        cpu: 2
+`))
+	util.OK(t, err)
+	result, err := Simulate(context.TODO(), clientset, "edgenet", objects)
+	util.OK(t, err)
+	util.Equals(t, ConstraintLimitRange, result.Blockers[0].Constraint)
+}
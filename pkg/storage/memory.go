@@ -0,0 +1,102 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storage
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DriverMemory is the name of the in-memory driver
+const DriverMemory = "memory"
+
+func init() {
+	Register(DriverMemory, func(string) (Store, error) { return NewMemoryStore(), nil })
+}
+
+// MemoryStore keeps the records in memory, for the tests and the development clusters. The
+// records are lost with the process.
+type MemoryStore struct {
+	mutex   sync.Mutex
+	records []Record
+}
+
+// NewMemoryStore returns an empty store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Archive appends the record unless it is already there
+func (m *MemoryStore) Archive(ctx context.Context, record Record) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for _, archived := range m.records {
+		if archived.Kind == record.Kind && archived.Name == record.Name && archived.Time.Equal(record.Time) {
+			return nil
+		}
+	}
+	m.records = append(m.records, record)
+	return nil
+}
+
+// List returns the records of the kind about the tenant, the newest first
+func (m *MemoryStore) List(ctx context.Context, kind, tenant string) ([]Record, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	records := []Record{}
+	for _, record := range m.records {
+		if record.Kind == kind && record.Tenant == tenant {
+			records = append(records, record)
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.After(records[j].Time) })
+	return records, nil
+}
+
+// Forget removes the records of the kind about the tenant
+func (m *MemoryStore) Forget(ctx context.Context, kind, tenant string) ([]string, error) {
+	return m.remove(func(record Record) bool { return record.Kind == kind && record.Tenant == tenant }), nil
+}
+
+// Prune removes the records of the kind taken before the time
+func (m *MemoryStore) Prune(ctx context.Context, kind string, before time.Time) (int64, error) {
+	return int64(len(m.remove(func(record Record) bool { return record.Kind == kind && record.Time.Before(before) }))), nil
+}
+
+// Close does nothing, the records stay until the store is dropped
+func (m *MemoryStore) Close() error {
+	return nil
+}
+
+// remove drops the matching records and returns their names
+func (m *MemoryStore) remove(matches func(Record) bool) []string {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	names := []string{}
+	kept := m.records[:0]
+	for _, record := range m.records {
+		if matches(record) {
+			names = append(names, record.Name)
+		} else {
+			kept = append(kept, record)
+		}
+	}
+	m.records = kept
+	return names
+}
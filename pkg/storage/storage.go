@@ -0,0 +1,189 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package storage keeps the history of the registration out of etcd for the large deployments.
+// The controllers archive the tenant and role requests and the audit records before they prune
+// them from etcd, and the usage samples of the tenants as they take them. The archive is pruned on its own
+// retention periods, so that etcd only holds the recent history.
+//
+// The stores are pluggable: a driver registers the function opening its stores under its name,
+// and the PostgreSQL driver is built in.
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// Kinds of the archived records
+const (
+	KindTenantRequest = "TenantRequest"
+	KindRoleRequest   = "RoleRequest"
+	KindTenantAudit   = "TenantAudit"
+	KindTenantUsage   = "TenantUsage"
+)
+
+// Record is an object archived out of etcd
+type Record struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Tenant is the tenant the record is about
+	Tenant string `json:"tenant"`
+	// Time is when the record was taken, the retention periods count from it
+	Time time.Time `json:"time"`
+	// Object is the archived object in JSON
+	Object json.RawMessage `json:"object"`
+}
+
+// Store keeps the archived records
+type Store interface {
+	// Archive stores the record, a record archived again with the same kind, name, and time is
+	// stored once
+	Archive(ctx context.Context, record Record) error
+	// List returns the records of the kind about the tenant, the newest first
+	List(ctx context.Context, kind, tenant string) ([]Record, error)
+	// Forget removes the records of the kind about the tenant and returns their names
+	Forget(ctx context.Context, kind, tenant string) ([]string, error)
+	// Prune removes the records of the kind taken before the time and returns how many it removed
+	Prune(ctx context.Context, kind string, before time.Time) (int64, error)
+	// Close releases the connections of the store
+	Close() error
+}
+
+// Opener opens a store of a driver with its data source name
+type Opener func(dataSource string) (Store, error)
+
+var (
+	driversMutex sync.RWMutex
+	drivers      = map[string]Opener{}
+)
+
+// Default is the store the controllers archive to, the history stays in etcd if it is nil
+var Default Store
+
+// Register makes a driver available under the name, it panics if the name is taken
+func Register(name string, open Opener) {
+	driversMutex.Lock()
+	defer driversMutex.Unlock()
+	if _, ok := drivers[name]; ok {
+		panic(fmt.Sprintf("storage driver %s registered twice", name))
+	}
+	drivers[name] = open
+}
+
+// Drivers returns the names of the registered drivers
+func Drivers() []string {
+	driversMutex.RLock()
+	defer driversMutex.RUnlock()
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open opens a store of the driver
+func Open(driver, dataSource string) (Store, error) {
+	driversMutex.RLock()
+	open, ok := drivers[driver]
+	driversMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown storage driver %q, the drivers are %v", driver, Drivers())
+	}
+	return open(dataSource)
+}
+
+// Configure opens the default store of the driver with the data source name read from the file,
+// so that the credentials it holds stay out of the command line. The history stays in etcd if the
+// driver is empty.
+func Configure(driver, dataSourcePath string) error {
+	if driver == "" {
+		return nil
+	}
+	dataSource, err := ioutil.ReadFile(dataSourcePath)
+	if err != nil {
+		return err
+	}
+	store, err := Open(driver, string(bytes.TrimSpace(dataSource)))
+	if err != nil {
+		return err
+	}
+	Default = store
+	return nil
+}
+
+// Enabled tells whether the controllers archive the history out of etcd
+func Enabled() bool {
+	return Default != nil
+}
+
+// Archive stores the object in the default store, it does nothing if there is none
+func Archive(ctx context.Context, kind, name, tenant string, at time.Time, object interface{}) error {
+	if !Enabled() {
+		return nil
+	}
+	raw, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	return Default.Archive(ctx, Record{Kind: kind, Name: name, Tenant: tenant, Time: at.UTC(), Object: raw})
+}
+
+// Forget removes the records of the kinds about the tenant from the default store, and returns
+// their names by kind
+func Forget(ctx context.Context, tenant string, kinds ...string) (map[string][]string, error) {
+	forgotten := map[string][]string{}
+	if !Enabled() {
+		return forgotten, nil
+	}
+	for _, kind := range kinds {
+		names, err := Default.Forget(ctx, kind, tenant)
+		if err != nil {
+			return nil, err
+		}
+		forgotten[kind] = names
+	}
+	return forgotten, nil
+}
+
+// RunRetention prunes the records of the kind from the store once they are older than the
+// retention period, at every interval until the context is done. The records are kept forever if
+// the retention period is zero.
+func RunRetention(ctx context.Context, store Store, kind string, retention, interval time.Duration) {
+	if store == nil || retention <= 0 {
+		return
+	}
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		pruned, err := store.Prune(ctx, kind, time.Now().Add(-retention))
+		if err != nil {
+			klog.ErrorS(err, "Couldn't prune the archive", "kind", kind)
+			return
+		}
+		if pruned > 0 {
+			klog.InfoS("Pruned the archive", "kind", kind, "records", pruned)
+		}
+	}, interval)
+}
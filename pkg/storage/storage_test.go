@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+)
+
+// testStore runs the same checks against the stores of every driver
+func testStore(t *testing.T, store Store) {
+	at := time.Date(2021, 10, 1, 9, 0, 0, 0, time.UTC)
+	records := []Record{
+		{Kind: KindTenantAudit, Name: "lip6-approved", Tenant: "lip6", Time: at, Object: json.RawMessage(`{"actor":"admin"}`)},
+		{Kind: KindTenantAudit, Name: "lip6-enabled", Tenant: "lip6", Time: at.Add(time.Hour), Object: json.RawMessage(`{}`)},
+		{Kind: KindTenantAudit, Name: "nyu-approved", Tenant: "nyu", Time: at, Object: json.RawMessage(`{}`)},
+		{Kind: KindTenantUsage, Name: "lip6", Tenant: "lip6", Time: at, Object: json.RawMessage(`{"pods":2}`)},
+		{Kind: KindTenantUsage, Name: "lip6", Tenant: "lip6", Time: at.Add(5 * time.Minute), Object: json.RawMessage(`{"pods":3}`)},
+	}
+	for _, record := range records {
+		util.OK(t, store.Archive(context.TODO(), record))
+	}
+	// A record archived again is not duplicated
+	util.OK(t, store.Archive(context.TODO(), records[0]))
+
+	archived, err := store.List(context.TODO(), KindTenantAudit, "lip6")
+	util.OK(t, err)
+	util.Equals(t, 2, len(archived))
+	util.Equals(t, "lip6-enabled", archived[0].Name)
+	util.Equals(t, "lip6-approved", archived[1].Name)
+	util.Equals(t, true, at.Equal(archived[1].Time))
+	object := map[string]string{}
+	util.OK(t, json.Unmarshal(archived[1].Object, &object))
+	util.Equals(t, "admin", object["actor"])
+
+	pruned, err := store.Prune(context.TODO(), KindTenantUsage, at.Add(time.Minute))
+	util.OK(t, err)
+	util.Equals(t, int64(1), pruned)
+	usages, err := store.List(context.TODO(), KindTenantUsage, "lip6")
+	util.OK(t, err)
+	util.Equals(t, 1, len(usages))
+
+	forgotten, err := store.Forget(context.TODO(), KindTenantAudit, "lip6")
+	util.OK(t, err)
+	util.Equals(t, 2, len(forgotten))
+	archived, err = store.List(context.TODO(), KindTenantAudit, "lip6")
+	util.OK(t, err)
+	util.Equals(t, 0, len(archived))
+	archived, err = store.List(context.TODO(), KindTenantAudit, "nyu")
+	util.OK(t, err)
+	util.Equals(t, 1, len(archived))
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestPostgresStore(t *testing.T) {
+	dataSource := os.Getenv("EDGENET_TEST_POSTGRES")
+	if dataSource == "" {
+		t.Skip("No test database, set EDGENET_TEST_POSTGRES to the data source name of an empty PostgreSQL database")
+	}
+	store, err := Open(DriverPostgres, dataSource)
+	util.OK(t, err)
+	defer store.Close()
+	for _, kind := range []string{KindTenantAudit, KindTenantUsage} {
+		_, err := store.Prune(context.TODO(), kind, time.Now())
+		util.OK(t, err)
+	}
+	testStore(t, store)
+}
+
+func TestConfigure(t *testing.T) {
+	defer func() { Default = nil }()
+	util.OK(t, Configure("", ""))
+	util.Equals(t, false, Enabled())
+	util.OK(t, Archive(context.TODO(), KindTenantRequest, "lip6", "lip6", time.Now(), map[string]string{}))
+
+	dir, err := ioutil.TempDir("", "storage")
+	util.OK(t, err)
+	defer os.RemoveAll(dir)
+	dataSourcePath := filepath.Join(dir, "dsn")
+	util.OK(t, ioutil.WriteFile(dataSourcePath, []byte("\n"), 0600))
+	util.Assert(t, Configure("mysql", dataSourcePath) != nil, "unknown driver accepted")
+	util.OK(t, Configure(DriverMemory, dataSourcePath))
+	util.Equals(t, true, Enabled())
+
+	at := time.Date(2021, 10, 1, 9, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	util.OK(t, Archive(context.TODO(), KindTenantRequest, "lip6", "lip6", at, map[string]string{"fullName": "LIP6"}))
+	records, err := Default.List(context.TODO(), KindTenantRequest, "lip6")
+	util.OK(t, err)
+	util.Equals(t, 1, len(records))
+	util.Equals(t, time.UTC, records[0].Time.Location())
+	forgotten, err := Forget(context.TODO(), "lip6", KindTenantRequest, KindTenantAudit)
+	util.OK(t, err)
+	util.Equals(t, map[string][]string{KindTenantRequest: {"lip6"}, KindTenantAudit: {}}, forgotten)
+}
+
+func TestRunRetention(t *testing.T) {
+	store := NewMemoryStore()
+	util.OK(t, store.Archive(context.TODO(), Record{Kind: KindTenantAudit, Name: "old", Time: time.Now().Add(-2 * time.Hour)}))
+	util.OK(t, store.Archive(context.TODO(), Record{Kind: KindTenantAudit, Name: "recent", Time: time.Now()}))
+	ctx, cancel := context.WithTimeout(context.TODO(), 50*time.Millisecond)
+	defer cancel()
+	RunRetention(ctx, store, KindTenantAudit, time.Hour, 10*time.Millisecond)
+	records, err := store.List(context.TODO(), KindTenantAudit, "")
+	util.OK(t, err)
+	util.Equals(t, 1, len(records))
+	util.Equals(t, "recent", records[0].Name)
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testutil runs the controllers against a real API server and etcd, rather than the fake
+// clientsets, so that the tests go through the validation of the CRDs, RBAC, and the finalizers.
+// The control plane binaries are looked up in KUBEBUILDER_ASSETS, the tests are skipped if they
+// are missing.
+//
+// Only the API server and etcd run, there is no kube-controller-manager. Without its garbage
+// collector, the objects are not removed along with their owners and namespaces stay in the
+// terminating phase: the tests check the owner references rather than the cleanup.
+package testutil
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+)
+
+// CRDManifest is the manifest holding the CRDs, relative to the root of the repository
+const CRDManifest = "build/yamls/kubernetes.yaml"
+
+// Defaults of the waits for the conditions
+var (
+	PollInterval = 100 * time.Millisecond
+	PollTimeout  = 30 * time.Second
+)
+
+// Environment is a control plane with the EdgeNet CRDs installed
+type Environment struct {
+	Config  *rest.Config
+	Kube    kubernetes.Interface
+	EdgeNet versioned.Interface
+
+	testEnv *envtest.Environment
+}
+
+// Start starts the control plane and installs the CRDs of the manifest found in the root of the
+// repository
+func Start(root string) (*Environment, error) {
+	crds, err := ReadCRDs(filepath.Join(root, CRDManifest))
+	if err != nil {
+		return nil, err
+	}
+	testEnv := &envtest.Environment{CRDs: crds}
+	config, err := testEnv.Start()
+	if err != nil {
+		return nil, err
+	}
+	env := &Environment{Config: config, testEnv: testEnv}
+	if env.Kube, err = kubernetes.NewForConfig(config); err != nil {
+		testEnv.Stop()
+		return nil, err
+	}
+	if env.EdgeNet, err = versioned.NewForConfig(config); err != nil {
+		testEnv.Stop()
+		return nil, err
+	}
+	return env, nil
+}
+
+// Stop stops the control plane
+func (e *Environment) Stop() error {
+	return e.testEnv.Stop()
+}
+
+// Setup starts an environment that lasts as long as the test, the test is skipped when the
+// control plane binaries are missing
+func Setup(t *testing.T, root string) *Environment {
+	t.Helper()
+	if !assetsAvailable() {
+		t.Skip("The control plane binaries are missing, set KUBEBUILDER_ASSETS to run the integration tests")
+	}
+	env, err := Start(root)
+	if err != nil {
+		t.Fatalf("Couldn't start the control plane: %s", err)
+	}
+	t.Cleanup(func() {
+		if err := env.Stop(); err != nil {
+			t.Errorf("Couldn't stop the control plane: %s", err)
+		}
+	})
+	return env
+}
+
+// assetsAvailable tells whether the binaries of the API server and etcd are there
+func assetsAvailable() bool {
+	assets := os.Getenv("KUBEBUILDER_ASSETS")
+	if assets == "" {
+		assets = "/usr/local/kubebuilder/bin"
+	}
+	for _, binary := range []string{"kube-apiserver", "etcd"} {
+		if _, err := os.Stat(filepath.Join(assets, binary)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// ReadCRDs reads the CRDs of a manifest and leaves out the other objects. The conversion webhook
+// is not running along with the control plane, the CRDs are installed without it, and the objects
+// are only served in their storage version.
+func ReadCRDs(path string) ([]client.Object, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	crds := []client.Object{}
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(raw), 4096)
+	for {
+		object := &unstructured.Unstructured{}
+		if err := decoder.Decode(&object.Object); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid manifest %s: %s", path, err)
+		}
+		if object.GetKind() != "CustomResourceDefinition" {
+			continue
+		}
+		unstructured.RemoveNestedField(object.Object, "spec", "conversion")
+		crds = append(crds, object)
+	}
+	return crds, nil
+}
+
+// Run runs the controller in the background until the end of the test, the function receives the
+// channel that closes at the end to start the informers and the controller with
+func (e *Environment) Run(t *testing.T, run func(stopCh <-chan struct{}) error) {
+	t.Helper()
+	stopCh := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := run(stopCh); err != nil {
+			t.Errorf("Controller failed: %s", err)
+		}
+	}()
+	t.Cleanup(func() {
+		close(stopCh)
+		<-done
+	})
+}
+
+// Eventually waits until the condition holds, the test fails if it doesn't before the timeout
+func Eventually(t *testing.T, condition func() (bool, error), message string, args ...interface{}) {
+	t.Helper()
+	if err := wait.PollImmediate(PollInterval, PollTimeout, condition); err != nil {
+		t.Fatalf("%s: %s", fmt.Sprintf(message, args...), err)
+	}
+}
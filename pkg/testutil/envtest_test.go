@@ -0,0 +1,75 @@
+package testutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestReadCRDs(t *testing.T) {
+	crds, err := ReadCRDs("../../" + CRDManifest)
+	util.OK(t, err)
+	util.Assert(t, len(crds) != 0, "no CRDs read")
+	for _, crd := range crds {
+		object := crd.(*unstructured.Unstructured)
+		util.Equals(t, "CustomResourceDefinition", object.GetKind())
+		_, found, _ := unstructured.NestedFieldNoCopy(object.Object, "spec", "conversion")
+		util.Equals(t, false, found)
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	env := Setup(t, "../..")
+
+	t.Run("custom resources", func(t *testing.T) {
+		tenant := &corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "edgenet"}}
+		_, err := env.EdgeNet.CoreV1alpha().Tenants().Create(context.TODO(), tenant, metav1.CreateOptions{})
+		util.OK(t, err)
+		Eventually(t, func() (bool, error) {
+			_, err := env.EdgeNet.CoreV1alpha().Tenants().Get(context.TODO(), tenant.GetName(), metav1.GetOptions{})
+			return err == nil, nil
+		}, "Tenant %s not served", tenant.GetName())
+	})
+	t.Run("finalizers", func(t *testing.T) {
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "finalizer"}}
+		_, err := env.Kube.CoreV1().Namespaces().Create(context.TODO(), namespace, metav1.CreateOptions{})
+		util.OK(t, err)
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "held", Namespace: namespace.GetName(), Finalizers: []string{"edge-net.io/test"}}}
+		_, err = env.Kube.CoreV1().ConfigMaps(namespace.GetName()).Create(context.TODO(), configMap, metav1.CreateOptions{})
+		util.OK(t, err)
+		util.OK(t, env.Kube.CoreV1().ConfigMaps(namespace.GetName()).Delete(context.TODO(), configMap.GetName(), metav1.DeleteOptions{}))
+		// The finalizer holds the object back until a controller removes it
+		held, err := env.Kube.CoreV1().ConfigMaps(namespace.GetName()).Get(context.TODO(), configMap.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Assert(t, held.GetDeletionTimestamp() != nil, "deletion timestamp not set")
+		env.Run(t, func(stopCh <-chan struct{}) error {
+			ticker := time.NewTicker(PollInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-stopCh:
+					return nil
+				case <-ticker.C:
+					held, err := env.Kube.CoreV1().ConfigMaps(namespace.GetName()).Get(context.TODO(), configMap.GetName(), metav1.GetOptions{})
+					if err != nil {
+						continue
+					}
+					held.SetFinalizers(nil)
+					env.Kube.CoreV1().ConfigMaps(namespace.GetName()).Update(context.TODO(), held, metav1.UpdateOptions{})
+				}
+			}
+		})
+		Eventually(t, func() (bool, error) {
+			_, err := env.Kube.CoreV1().ConfigMaps(namespace.GetName()).Get(context.TODO(), configMap.GetName(), metav1.GetOptions{})
+			return errors.IsNotFound(err), nil
+		}, "Config map %s not removed", configMap.GetName())
+	})
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testutil
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+// NewTenantClientsets returns the fake clientsets with an enabled tenant that has a subsidiary
+// namespace, and the core namespace of another tenant named other
+func NewTenantClientsets(t *testing.T, tenant, subsidiary string) (*testclient.Clientset, *edgenettestclient.Clientset) {
+	t.Helper()
+	kubeclientset := testclient.NewSimpleClientset()
+	edgenetclientset := edgenettestclient.NewSimpleClientset()
+	tenantObj := &corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: tenant}, Spec: corev1alpha.TenantSpec{Enabled: true}}
+	_, err := edgenetclientset.CoreV1alpha().Tenants().Create(context.TODO(), tenantObj, metav1.CreateOptions{})
+	util.OK(t, err)
+	namespaces := []*corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: tenant, Labels: map[string]string{"edge-net.io/kind": "core", "edge-net.io/tenant": tenant}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: subsidiary, Labels: map[string]string{"edge-net.io/kind": "sub", "edge-net.io/tenant": tenant}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{"edge-net.io/kind": "core", "edge-net.io/tenant": "other"}}},
+	}
+	for _, namespace := range namespaces {
+		_, err := kubeclientset.CoreV1().Namespaces().Create(context.TODO(), namespace, metav1.CreateOptions{})
+		util.OK(t, err)
+	}
+	return kubeclientset, edgenetclientset
+}
+
+// IssueTokens makes the fake API server issue the tokens that the service accounts request, for
+// their lifetime. A token reads namespace:serviceaccount:bound object:audiences.
+func IssueTokens(kubeclientset *testclient.Clientset) {
+	kubeclientset.PrependReactor("create", "serviceaccounts", func(action ktesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "token" {
+			return false, nil, nil
+		}
+		tokenRequest := action.(ktesting.CreateAction).GetObject().(*authenticationv1.TokenRequest).DeepCopy()
+		var bound string
+		if tokenRequest.Spec.BoundObjectRef != nil {
+			bound = tokenRequest.Spec.BoundObjectRef.Name
+		}
+		tokenRequest.Status.Token = strings.Join([]string{action.GetNamespace(), action.(ktesting.CreateActionImpl).Name, bound, strings.Join(tokenRequest.Spec.Audiences, ",")}, ":")
+		tokenRequest.Status.ExpirationTimestamp = metav1.NewTime(time.Now().Add(time.Duration(*tokenRequest.Spec.ExpirationSeconds) * time.Second))
+		return true, tokenRequest, nil
+	})
+}
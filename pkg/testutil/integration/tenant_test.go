@@ -0,0 +1,132 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/controller/core/v1alpha/tenant"
+	edgenetscheme "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/scheme"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/signals"
+	"github.com/EdgeNet-project/edgenet/pkg/testutil"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
+	kubeinformers "k8s.io/client-go/informers"
+	kubescheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// runTenantController runs the tenant controller against the control plane until the end of the test
+func runTenantController(t *testing.T, env *testutil.Environment) {
+	t.Helper()
+	// The pods of the connectivity probe never run without nodes
+	tenant.IsolationProbeImage = ""
+	dynamicclient, err := dynamic.NewForConfig(env.Config)
+	util.OK(t, err)
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(env.Kube, 0)
+	edgenetInformerFactory := informers.NewSharedInformerFactory(env.EdgeNet, 0)
+	env.Run(t, func(stopCh <-chan struct{}) error {
+		controller := tenant.NewController(signals.ContextFor(stopCh),
+			env.Kube,
+			env.EdgeNet,
+			dynamicclient,
+			edgenetInformerFactory.Core().V1alpha().Tenants(),
+			edgenetInformerFactory.Core().V1alpha().NodePools(),
+			edgenetInformerFactory.Core().V1alpha().Operations(),
+			kubeInformerFactory.Core().V1().Namespaces(),
+			kubeInformerFactory.Rbac().V1().ClusterRoles(),
+			kubeInformerFactory.Core().V1().Nodes(),
+			kubeInformerFactory.Networking().V1().NetworkPolicies(),
+			true)
+		scheme := runtime.NewScheme()
+		utilruntime.Must(kubescheme.AddToScheme(scheme))
+		utilruntime.Must(edgenetscheme.AddToScheme(scheme))
+		mgr, err := ctrl.NewManager(env.Config, ctrl.Options{
+			Scheme:             scheme,
+			MetricsBindAddress: "0",
+			NewCache:           tenant.NewCache(kubeInformerFactory, edgenetInformerFactory),
+		})
+		if err != nil {
+			return err
+		}
+		if err := controller.SetupWithManager(mgr, 2); err != nil {
+			return err
+		}
+		return mgr.Start(signals.ContextFor(stopCh))
+	})
+}
+
+func TestTenant(t *testing.T) {
+	env := testutil.Setup(t, "../../..")
+	runTenantController(t, env)
+
+	tenantObj := &corev1alpha.Tenant{
+		ObjectMeta: metav1.ObjectMeta{Name: "lip6"},
+		Spec: corev1alpha.TenantSpec{
+			FullName:  "LIP6",
+			ShortName: "LIP6",
+			URL:       "https://www.lip6.fr",
+			Address: corev1alpha.Address{
+				City:    "Paris",
+				Country: "France",
+				Street:  "4 place Jussieu, boite 169",
+				ZIP:     "75005",
+			},
+			Contact: corev1alpha.Contact{
+				Email:     "john.doe@edge-net.org",
+				FirstName: "John",
+				LastName:  "Doe",
+				Phone:     "+33NUMBER",
+				Handle:    "johndoe",
+			},
+			Enabled: true,
+		},
+	}
+	tenantObj, err := env.EdgeNet.CoreV1alpha().Tenants().Create(context.TODO(), tenantObj, metav1.CreateOptions{})
+	util.OK(t, err)
+	ownerRoleName := fmt.Sprintf("edgenet:tenant-owner-%s", tenantObj.Spec.Contact.Handle)
+
+	t.Run("core namespace", func(t *testing.T) {
+		testutil.Eventually(t, func() (bool, error) {
+			_, err := env.Kube.CoreV1().Namespaces().Get(context.TODO(), tenantObj.GetName(), metav1.GetOptions{})
+			return err == nil, nil
+		}, "Core namespace %s not created", tenantObj.GetName())
+		namespace, err := env.Kube.CoreV1().Namespaces().Get(context.TODO(), tenantObj.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, "core", namespace.GetLabels()["edge-net.io/kind"])
+		// The garbage collector is not running, the owner reference is the most that can be checked
+		owner := metav1.GetControllerOf(namespace)
+		util.Assert(t, owner != nil, "core namespace without a controller")
+		util.Equals(t, tenantObj.GetUID(), owner.UID)
+	})
+	t.Run("owner role", func(t *testing.T) {
+		testutil.Eventually(t, func() (bool, error) {
+			_, err := env.Kube.RbacV1().RoleBindings(tenantObj.GetName()).Get(context.TODO(), "edgenet:tenant-owner", metav1.GetOptions{})
+			return err == nil, nil
+		}, "Owner role binding of %s not created", tenantObj.GetName())
+	})
+	t.Run("status", func(t *testing.T) {
+		testutil.Eventually(t, func() (bool, error) {
+			tenantObj, err := env.EdgeNet.CoreV1alpha().Tenants().Get(context.TODO(), tenantObj.GetName(), metav1.GetOptions{})
+			return err == nil && tenantObj.Status.State != "", err
+		}, "Status of %s not updated", tenantObj.GetName())
+	})
+	t.Run("disable", func(t *testing.T) {
+		tenantObj, err := env.EdgeNet.CoreV1alpha().Tenants().Get(context.TODO(), tenantObj.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		tenantObj.Spec.Enabled = false
+		_, err = env.EdgeNet.CoreV1alpha().Tenants().Update(context.TODO(), tenantObj, metav1.UpdateOptions{})
+		util.OK(t, err)
+		testutil.Eventually(t, func() (bool, error) {
+			_, err := env.Kube.RbacV1().Roles(tenantObj.GetName()).Get(context.TODO(), ownerRoleName, metav1.GetOptions{})
+			return errors.IsNotFound(err), nil
+		}, "Owner role of %s not removed", tenantObj.GetName())
+	})
+}
@@ -0,0 +1,116 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/access"
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/controller/registration/v1alpha/tenantrequest"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/testutil"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+)
+
+// runTenantRequestController runs the tenant request controller against the control plane until
+// the end of the test
+func runTenantRequestController(t *testing.T, env *testutil.Environment) {
+	t.Helper()
+	access.Clientset = env.Kube
+	access.EdgenetClientset = env.EdgeNet
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(env.Kube, 0)
+	edgenetInformerFactory := informers.NewSharedInformerFactory(env.EdgeNet, 0)
+	controller := tenantrequest.NewController(env.Kube,
+		env.EdgeNet,
+		edgenetInformerFactory.Registration().V1alpha().TenantRequests(),
+		kubeInformerFactory.Core().V1().Nodes())
+	env.Run(t, func(stopCh <-chan struct{}) error {
+		kubeInformerFactory.Start(stopCh)
+		edgenetInformerFactory.Start(stopCh)
+		return controller.Run(2, stopCh)
+	})
+}
+
+func TestTenantRequest(t *testing.T) {
+	env := testutil.Setup(t, "../../..")
+	runTenantRequestController(t, env)
+
+	tenantRequestObj := &registrationv1alpha.TenantRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "lip6"},
+		Spec: registrationv1alpha.TenantRequestSpec{
+			FullName:  "LIP6",
+			ShortName: "LIP6",
+			URL:       "https://www.lip6.fr",
+			Address: corev1alpha.Address{
+				City:    "Paris",
+				Country: "France",
+				Street:  "4 place Jussieu, boite 169",
+				ZIP:     "75005",
+			},
+			Contact: corev1alpha.Contact{
+				Email:     "tom.public@edge-net.org",
+				FirstName: "Tom",
+				LastName:  "Public",
+				Phone:     "+33NUMBER",
+				Handle:    "tompublic",
+			},
+			ResourceAllocation: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("12000m"),
+				corev1.ResourceMemory: resource.MustParse("12Gi"),
+			},
+		},
+	}
+	_, err := env.EdgeNet.RegistrationV1alpha().TenantRequests().Create(context.TODO(), tenantRequestObj, metav1.CreateOptions{})
+	util.OK(t, err)
+
+	t.Run("pending", func(t *testing.T) {
+		testutil.Eventually(t, func() (bool, error) {
+			tenantRequest, err := env.EdgeNet.RegistrationV1alpha().TenantRequests().Get(context.TODO(), tenantRequestObj.GetName(), metav1.GetOptions{})
+			return err == nil && tenantRequest.Status.State == "Pending" && tenantRequest.Status.Expiry != nil, err
+		}, "Tenant request %s not pending", tenantRequestObj.GetName())
+	})
+	t.Run("approval", func(t *testing.T) {
+		tenantRequest, err := env.EdgeNet.RegistrationV1alpha().TenantRequests().Get(context.TODO(), tenantRequestObj.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		tenantRequest.Spec.Approved = true
+		_, err = env.EdgeNet.RegistrationV1alpha().TenantRequests().Update(context.TODO(), tenantRequest, metav1.UpdateOptions{})
+		util.OK(t, err)
+		testutil.Eventually(t, func() (bool, error) {
+			_, err := env.EdgeNet.CoreV1alpha().Tenants().Get(context.TODO(), tenantRequestObj.GetName(), metav1.GetOptions{})
+			return err == nil, nil
+		}, "Tenant %s not created", tenantRequestObj.GetName())
+		testutil.Eventually(t, func() (bool, error) {
+			tenantResourceQuota, err := env.EdgeNet.CoreV1alpha().TenantResourceQuotas().Get(context.TODO(), tenantRequestObj.GetName(), metav1.GetOptions{})
+			if err != nil {
+				return false, nil
+			}
+			claim := corev1.ResourceList(tenantResourceQuota.Spec.Claim["initial"].ResourceList)
+			return claim.Cpu().Equal(resource.MustParse("12000m")) && claim.Memory().Equal(resource.MustParse("12Gi")), nil
+		}, "Resource quota of %s not claimed", tenantRequestObj.GetName())
+		tenantRequest, err = env.EdgeNet.RegistrationV1alpha().TenantRequests().Get(context.TODO(), tenantRequestObj.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		util.Equals(t, "Approved", tenantRequest.Status.State)
+	})
+	t.Run("expiry", func(t *testing.T) {
+		tenantRequest, err := env.EdgeNet.RegistrationV1alpha().TenantRequests().Get(context.TODO(), tenantRequestObj.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+		tenantRequest.Status.Expiry = &metav1.Time{Time: time.Now().Add(time.Second)}
+		_, err = env.EdgeNet.RegistrationV1alpha().TenantRequests().UpdateStatus(context.TODO(), tenantRequest, metav1.UpdateOptions{})
+		util.OK(t, err)
+		testutil.Eventually(t, func() (bool, error) {
+			_, err := env.EdgeNet.RegistrationV1alpha().TenantRequests().Get(context.TODO(), tenantRequestObj.GetName(), metav1.GetOptions{})
+			return errors.IsNotFound(err), nil
+		}, "Expired tenant request %s not removed", tenantRequestObj.GetName())
+		// The tenant outlives the request, it is not owned by it
+		_, err = env.EdgeNet.CoreV1alpha().Tenants().Get(context.TODO(), tenantRequestObj.GetName(), metav1.GetOptions{})
+		util.OK(t, err)
+	})
+}
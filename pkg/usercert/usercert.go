@@ -0,0 +1,340 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package usercert manages the client certificates of the users of a tenant. The certificates are
+// signed by the API server through the certificate signing requests and kept in secrets of the
+// tenant namespace. Kubernetes has no revocation list, so every certificate carries a group of its
+// own that the roles of its user in the tenant are bound to, and the bindings of the group are
+// removed once the certificate is revoked. The user name of a certificate is prefixed, so that the
+// roles bound to the user do not follow the certificate.
+package usercert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/credential"
+
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// AnnotationID identifies the certificate in the revocation list
+	AnnotationID = "edge-net.io/certificate-id"
+	// AnnotationRevoked records when the certificate was revoked
+	AnnotationRevoked = "edge-net.io/certificate-revoked"
+	// GroupPrefix prefixes the group of its own each certificate carries
+	GroupPrefix = "edge-net.io:certificate:"
+	// UserPrefix prefixes the user name of the certificates
+	UserPrefix = "edge-net.io:certificate-user:"
+	// labelID identifies the certificate the role bindings of its group belong to
+	labelID = "edge-net.io/certificate-id"
+	// credentialKind is the value of the credential label on the secrets of the certificates
+	credentialKind = "certificate"
+)
+
+// RevocationList is the config map listing the revoked certificates by identifier, along with
+// their user
+var RevocationList = struct {
+	Namespace string
+	Name      string
+}{Namespace: "edgenet", Name: "revoked-certificates"}
+
+// SigningTimeout is how long the API server has to sign a certificate
+var SigningTimeout = 30 * time.Second
+
+// Certificate is a client certificate issued to a user of a tenant
+type Certificate struct {
+	// Name of the secret holding the certificate.
+	Name     string
+	User     string
+	ID       string
+	NotAfter time.Time
+	Revoked  bool
+}
+
+// Issue has the API server sign a client certificate with a fresh key for the user, and keeps
+// both in a secret of the tenant namespace
+func Issue(ctx context.Context, kubeclientset kubernetes.Interface, tenant, user string) (*corev1.Secret, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	request, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: UserPrefix + user, Organization: []string{GroupPrefix + id}},
+	}, key)
+	if err != nil {
+		return nil, err
+	}
+	csr := &certificatesv1.CertificateSigningRequest{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("edgenet-%s-%s", tenant, id)},
+		Spec: certificatesv1.CertificateSigningRequestSpec{
+			Request:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: request}),
+			SignerName: certificatesv1.KubeAPIServerClientSignerName,
+			Usages:     []certificatesv1.KeyUsage{certificatesv1.UsageDigitalSignature, certificatesv1.UsageClientAuth},
+		}}
+	csrClient := kubeclientset.CertificatesV1().CertificateSigningRequests()
+	if csr, err = csrClient.Create(ctx, csr, metav1.CreateOptions{}); err != nil {
+		return nil, err
+	}
+	// The request is of no use once the certificate is in the secret
+	defer csrClient.Delete(ctx, csr.GetName(), metav1.DeleteOptions{})
+	csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1.CertificateSigningRequestCondition{
+		Type: certificatesv1.CertificateApproved, Status: corev1.ConditionTrue, Reason: "EdgeNetIssued",
+		Message: fmt.Sprintf("Issued to %s of tenant %s", user, tenant)})
+	if _, err := csrClient.UpdateApproval(ctx, csr.GetName(), csr, metav1.UpdateOptions{}); err != nil {
+		return nil, err
+	}
+	var certificate []byte
+	err = wait.PollImmediate(time.Second, SigningTimeout, func() (bool, error) {
+		signed, err := csrClient.Get(ctx, csr.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		certificate = signed.Status.Certificate
+		return len(certificate) > 0, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("certificate of %s not signed: %w", user, err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("certificate-%s", id), Namespace: tenant,
+		Labels:      map[string]string{"edge-net.io/generated": "true", "edge-net.io/tenant": tenant, credential.LabelCredential: credentialKind},
+		Annotations: map[string]string{credential.AnnotationUser: user, AnnotationID: id}},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certificate,
+			corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		}}
+	if secret, err = kubeclientset.CoreV1().Secrets(tenant).Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		return nil, err
+	}
+	if err := bind(ctx, kubeclientset, tenant, user, id); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// List returns the certificates issued to the users of the tenant, the revoked ones included
+func List(ctx context.Context, kubeclientset kubernetes.Interface, tenant string) ([]Certificate, error) {
+	secrets, err := kubeclientset.CoreV1().Secrets(tenant).List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", credential.LabelCredential, credentialKind)})
+	if err != nil {
+		return nil, err
+	}
+	certificates := []Certificate{}
+	for _, secret := range secrets.Items {
+		annotations := secret.GetAnnotations()
+		certificate := Certificate{Name: secret.GetName(), User: annotations[credential.AnnotationUser], ID: annotations[AnnotationID]}
+		_, certificate.Revoked = annotations[AnnotationRevoked]
+		if block, _ := pem.Decode(secret.Data[corev1.TLSCertKey]); block != nil {
+			if parsed, err := x509.ParseCertificate(block.Bytes); err == nil {
+				certificate.NotAfter = parsed.NotAfter
+			}
+		}
+		certificates = append(certificates, certificate)
+	}
+	sort.Slice(certificates, func(i, j int) bool {
+		if certificates[i].User != certificates[j].User {
+			return certificates[i].User < certificates[j].User
+		}
+		return certificates[i].NotAfter.Before(certificates[j].NotAfter)
+	})
+	return certificates, nil
+}
+
+// Revoke adds the certificate to the revocation list, removes the role bindings of its group and
+// drops its key, the secret stays with the certificate for the records
+func Revoke(ctx context.Context, kubeclientset kubernetes.Interface, tenant, name string) error {
+	secret, err := kubeclientset.CoreV1().Secrets(tenant).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	annotations := secret.GetAnnotations()
+	id := annotations[AnnotationID]
+	if secret.GetLabels()[credential.LabelCredential] != credentialKind || id == "" {
+		return fmt.Errorf("secret %s is not a certificate", name)
+	}
+	if err := addRevocation(ctx, kubeclientset, id, annotations[credential.AnnotationUser]); err != nil {
+		return err
+	}
+	if err := unbind(ctx, kubeclientset, tenant, id); err != nil {
+		return err
+	}
+	if _, ok := annotations[AnnotationRevoked]; ok {
+		return nil
+	}
+	annotations[AnnotationRevoked] = time.Now().UTC().Format(time.RFC3339)
+	secret.SetAnnotations(annotations)
+	delete(secret.Data, corev1.TLSPrivateKeyKey)
+	_, err = kubeclientset.CoreV1().Secrets(tenant).Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
+// Reissue revokes the certificate and issues a new one with a fresh key to its user
+func Reissue(ctx context.Context, kubeclientset kubernetes.Interface, tenant, name string) (*corev1.Secret, error) {
+	secret, err := kubeclientset.CoreV1().Secrets(tenant).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if err := Revoke(ctx, kubeclientset, tenant, name); err != nil {
+		return nil, err
+	}
+	return Issue(ctx, kubeclientset, tenant, secret.GetAnnotations()[credential.AnnotationUser])
+}
+
+// Purge revokes the certificates of the tenant and removes their secrets, for the tenant to leave
+// no personal data behind. The certificates stay in the revocation list without their user, so
+// that they are still denied. It returns the names of the removed secrets.
+func Purge(ctx context.Context, kubeclientset kubernetes.Interface, tenant string) ([]string, error) {
+	certificates, err := List(ctx, kubeclientset, tenant)
+	if err != nil {
+		return nil, err
+	}
+	removed := []string{}
+	for _, certificate := range certificates {
+		if certificate.ID != "" {
+			if err := addRevocation(ctx, kubeclientset, certificate.ID, ""); err != nil {
+				return removed, err
+			}
+			if err := unbind(ctx, kubeclientset, tenant, certificate.ID); err != nil {
+				return removed, err
+			}
+		}
+		if err := kubeclientset.CoreV1().Secrets(tenant).Delete(ctx, certificate.Name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return removed, err
+		}
+		removed = append(removed, certificate.Name)
+	}
+	return removed, nil
+}
+
+// User returns the user a certificate is issued to from its common name
+func User(commonName string) string {
+	return strings.TrimPrefix(commonName, UserPrefix)
+}
+
+// RevokedID returns the identifier of the revoked certificate among the groups of a user, the
+// groups of a certificate user include the group of its certificate
+func RevokedID(groups []string, revocations map[string]string) (string, bool) {
+	for _, group := range groups {
+		if !strings.HasPrefix(group, GroupPrefix) {
+			continue
+		}
+		id := strings.TrimPrefix(group, GroupPrefix)
+		if _, ok := revocations[id]; ok {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// addRevocation records the certificate in the revocation list along with its user, the user
+// of a certificate already listed is replaced
+func addRevocation(ctx context.Context, kubeclientset kubernetes.Interface, id, user string) error {
+	configMaps := kubeclientset.CoreV1().ConfigMaps(RevocationList.Namespace)
+	revocationList, err := configMaps.Get(ctx, RevocationList.Name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		revocationList = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: RevocationList.Name, Namespace: RevocationList.Namespace,
+			Labels: map[string]string{"edge-net.io/generated": "true"}}, Data: map[string]string{id: user}}
+		_, err = configMaps.Create(ctx, revocationList, metav1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+	if listed, ok := revocationList.Data[id]; ok && listed == user {
+		return nil
+	}
+	if revocationList.Data == nil {
+		revocationList.Data = map[string]string{}
+	}
+	revocationList.Data[id] = user
+	_, err = configMaps.Update(ctx, revocationList, metav1.UpdateOptions{})
+	return err
+}
+
+// bind binds the roles of the user in the tenant namespace to the group of the certificate, with
+// a role binding for each of those of the user
+func bind(ctx context.Context, kubeclientset kubernetes.Interface, tenant, user, id string) error {
+	roleBindings, err := kubeclientset.RbacV1().RoleBindings(tenant).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, roleBinding := range roleBindings.Items {
+		bound := false
+		for _, subject := range roleBinding.Subjects {
+			if subject.Kind == rbacv1.UserKind && subject.Name == user {
+				bound = true
+				break
+			}
+		}
+		if !bound {
+			continue
+		}
+		certificateBinding := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("certificate-%s-%s", id, roleBinding.GetName()), Namespace: tenant,
+			Labels: map[string]string{"edge-net.io/generated": "true", "edge-net.io/tenant": tenant, labelID: id}},
+			Subjects: []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: GroupPrefix + id, APIGroup: rbacv1.GroupName}},
+			RoleRef:  roleBinding.RoleRef}
+		if _, err := kubeclientset.RbacV1().RoleBindings(tenant).Create(ctx, certificateBinding, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// unbind removes the role bindings of the group of the certificate
+func unbind(ctx context.Context, kubeclientset kubernetes.Interface, tenant, id string) error {
+	roleBindings, err := kubeclientset.RbacV1().RoleBindings(tenant).List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", labelID, id)})
+	if err != nil {
+		return err
+	}
+	for _, roleBinding := range roleBindings.Items {
+		if err := kubeclientset.RbacV1().RoleBindings(tenant).Delete(ctx, roleBinding.GetName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// newID returns a random identifier of a certificate
+func newID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
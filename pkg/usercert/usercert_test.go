@@ -0,0 +1,201 @@
+package usercert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/EdgeNet-project/edgenet/pkg/credential"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+var notAfter = time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// newClientset returns a clientset that signs the approved certificate signing requests as the
+// API server would
+func newClientset(t *testing.T) *testclient.Clientset {
+	kubeclientset := testclient.NewSimpleClientset()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	util.OK(t, err)
+	ca := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "kubernetes"}, NotAfter: notAfter, IsCA: true, BasicConstraintsValid: true}
+	kubeclientset.PrependReactor("get", "certificatesigningrequests", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		obj, err := kubeclientset.Tracker().Get(action.GetResource(), "", action.(k8stesting.GetAction).GetName())
+		if err != nil {
+			return true, nil, err
+		}
+		csr := obj.(*certificatesv1.CertificateSigningRequest).DeepCopy()
+		block, _ := pem.Decode(csr.Spec.Request)
+		request, err := x509.ParseCertificateRequest(block.Bytes)
+		if err != nil {
+			return true, nil, err
+		}
+		template := &x509.Certificate{SerialNumber: big.NewInt(2), Subject: request.Subject, NotAfter: notAfter, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}
+		certificate, err := x509.CreateCertificate(rand.Reader, template, ca, request.PublicKey, caKey)
+		if err != nil {
+			return true, nil, err
+		}
+		csr.Status.Certificate = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate})
+		return true, csr, nil
+	})
+	return kubeclientset
+}
+
+// certificateBindings returns the role of each role binding of the group of the certificate
+func certificateBindings(t *testing.T, kubeclientset *testclient.Clientset, tenant, id string) map[string]string {
+	roleBindings, err := kubeclientset.RbacV1().RoleBindings(tenant).List(context.TODO(), metav1.ListOptions{})
+	util.OK(t, err)
+	roles := map[string]string{}
+	for _, roleBinding := range roleBindings.Items {
+		for _, subject := range roleBinding.Subjects {
+			if subject.Kind == rbacv1.GroupKind && subject.Name == GroupPrefix+id {
+				roles[roleBinding.GetName()] = roleBinding.RoleRef.Name
+			}
+		}
+	}
+	return roles
+}
+
+func TestIssueAndRevoke(t *testing.T) {
+	kubeclientset := newClientset(t)
+	roleBinding := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "edgenet:tenant-admin", Namespace: "lip6"},
+		Subjects: []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "john.doe@edge-net.org", APIGroup: rbacv1.GroupName}},
+		RoleRef:  rbacv1.RoleRef{Kind: "ClusterRole", Name: "edgenet:tenant-admin", APIGroup: rbacv1.GroupName}}
+	_, err := kubeclientset.RbacV1().RoleBindings("lip6").Create(context.TODO(), roleBinding, metav1.CreateOptions{})
+	util.OK(t, err)
+	roleBinding = &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "edgenet:tenant-collaborator", Namespace: "lip6"},
+		Subjects: []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "jane.doe@edge-net.org", APIGroup: rbacv1.GroupName}},
+		RoleRef:  rbacv1.RoleRef{Kind: "ClusterRole", Name: "edgenet:tenant-collaborator", APIGroup: rbacv1.GroupName}}
+	_, err = kubeclientset.RbacV1().RoleBindings("lip6").Create(context.TODO(), roleBinding, metav1.CreateOptions{})
+	util.OK(t, err)
+
+	secret, err := Issue(context.TODO(), kubeclientset, "lip6", "john.doe@edge-net.org")
+	util.OK(t, err)
+	id := secret.GetAnnotations()[AnnotationID]
+	util.Equals(t, "john.doe@edge-net.org", secret.GetAnnotations()[credential.AnnotationUser])
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	util.OK(t, err)
+	util.Equals(t, UserPrefix+"john.doe@edge-net.org", certificate.Subject.CommonName)
+	util.Equals(t, "john.doe@edge-net.org", User(certificate.Subject.CommonName))
+	util.Equals(t, []string{GroupPrefix + id}, certificate.Subject.Organization)
+	// The certificate takes the roles of its user in the tenant, through its group
+	util.Equals(t, map[string]string{"certificate-" + id + "-edgenet:tenant-admin": "edgenet:tenant-admin"}, certificateBindings(t, kubeclientset, "lip6", id))
+	util.Assert(t, len(secret.Data[corev1.TLSPrivateKeyKey]) > 0, "private key missing")
+	// The signing request is removed once the certificate is in the secret
+	csrs, err := kubeclientset.CertificatesV1().CertificateSigningRequests().List(context.TODO(), metav1.ListOptions{})
+	util.OK(t, err)
+	util.Equals(t, 0, len(csrs.Items))
+
+	reissued, err := Reissue(context.TODO(), kubeclientset, "lip6", secret.GetName())
+	util.OK(t, err)
+	util.Assert(t, reissued.GetName() != secret.GetName(), "certificate not reissued")
+	util.Equals(t, 0, len(certificateBindings(t, kubeclientset, "lip6", id)))
+	util.Equals(t, 1, len(certificateBindings(t, kubeclientset, "lip6", reissued.GetAnnotations()[AnnotationID])))
+
+	certificates, err := List(context.TODO(), kubeclientset, "lip6")
+	util.OK(t, err)
+	util.Equals(t, 2, len(certificates))
+	revoked := map[string]bool{}
+	for _, certificate := range certificates {
+		util.Equals(t, "john.doe@edge-net.org", certificate.User)
+		util.Equals(t, notAfter, certificate.NotAfter.UTC())
+		revoked[certificate.Name] = certificate.Revoked
+	}
+	util.Equals(t, map[string]bool{secret.GetName(): true, reissued.GetName(): false}, revoked)
+
+	secret, err = kubeclientset.CoreV1().Secrets("lip6").Get(context.TODO(), secret.GetName(), metav1.GetOptions{})
+	util.OK(t, err)
+	_, ok := secret.Data[corev1.TLSPrivateKeyKey]
+	util.Equals(t, false, ok)
+	revocationList, err := kubeclientset.CoreV1().ConfigMaps(RevocationList.Namespace).Get(context.TODO(), RevocationList.Name, metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, map[string]string{id: "john.doe@edge-net.org"}, revocationList.Data)
+
+	// Revoking twice leaves the revocation list as is
+	util.OK(t, Revoke(context.TODO(), kubeclientset, "lip6", secret.GetName()))
+	revocationList, err = kubeclientset.CoreV1().ConfigMaps(RevocationList.Namespace).Get(context.TODO(), RevocationList.Name, metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, 1, len(revocationList.Data))
+}
+
+func TestPurge(t *testing.T) {
+	kubeclientset := newClientset(t)
+
+	revoked, err := Issue(context.TODO(), kubeclientset, "lip6", "john.doe@edge-net.org")
+	util.OK(t, err)
+	util.OK(t, Revoke(context.TODO(), kubeclientset, "lip6", revoked.GetName()))
+	valid, err := Issue(context.TODO(), kubeclientset, "lip6", "jane.doe@edge-net.org")
+	util.OK(t, err)
+	other, err := Issue(context.TODO(), kubeclientset, "nyu", "joe.bloggs@edge-net.org")
+	util.OK(t, err)
+
+	roleBinding := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "edgenet:tenant-admin", Namespace: "lip6"},
+		Subjects: []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "jane.doe@edge-net.org", APIGroup: rbacv1.GroupName}},
+		RoleRef:  rbacv1.RoleRef{Kind: "ClusterRole", Name: "edgenet:tenant-admin", APIGroup: rbacv1.GroupName}}
+	_, err = kubeclientset.RbacV1().RoleBindings("lip6").Create(context.TODO(), roleBinding, metav1.CreateOptions{})
+	util.OK(t, err)
+	util.OK(t, bind(context.TODO(), kubeclientset, "lip6", "jane.doe@edge-net.org", valid.GetAnnotations()[AnnotationID]))
+	util.Equals(t, 1, len(certificateBindings(t, kubeclientset, "lip6", valid.GetAnnotations()[AnnotationID])))
+
+	removed, err := Purge(context.TODO(), kubeclientset, "lip6")
+	util.OK(t, err)
+	util.Equals(t, 2, len(removed))
+	util.Equals(t, 0, len(certificateBindings(t, kubeclientset, "lip6", valid.GetAnnotations()[AnnotationID])))
+	certificates, err := List(context.TODO(), kubeclientset, "lip6")
+	util.OK(t, err)
+	util.Equals(t, 0, len(certificates))
+	certificates, err = List(context.TODO(), kubeclientset, "nyu")
+	util.OK(t, err)
+	util.Equals(t, 1, len(certificates))
+
+	// The certificates are still denied, without their user
+	revocationList, err := kubeclientset.CoreV1().ConfigMaps(RevocationList.Namespace).Get(context.TODO(), RevocationList.Name, metav1.GetOptions{})
+	util.OK(t, err)
+	util.Equals(t, map[string]string{revoked.GetAnnotations()[AnnotationID]: "", valid.GetAnnotations()[AnnotationID]: ""}, revocationList.Data)
+	_, ok := revocationList.Data[other.GetAnnotations()[AnnotationID]]
+	util.Equals(t, false, ok)
+}
+
+func TestAdmit(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	w := &Webhook{configMapsLister: corelisters.NewConfigMapLister(indexer)}
+	revoked := authenticationv1.UserInfo{Username: "john.doe@edge-net.org", Groups: []string{GroupPrefix + "0a1b2c3d", "system:authenticated"}}
+	valid := authenticationv1.UserInfo{Username: "jane.doe@edge-net.org", Groups: []string{GroupPrefix + "4e5f6a7b", "system:authenticated"}}
+
+	// Everything is admitted until a certificate is revoked
+	util.OK(t, w.Admit(revoked))
+
+	indexer.Add(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: RevocationList.Name, Namespace: RevocationList.Namespace},
+		Data: map[string]string{"0a1b2c3d": "john.doe@edge-net.org"}})
+	util.Assert(t, w.Admit(revoked) != nil, "revoked certificate admitted")
+	util.OK(t, w.Admit(valid))
+	util.OK(t, w.Admit(authenticationv1.UserInfo{Username: "system:serviceaccount:edgenet:tenant"}))
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usercert
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// Webhook denies the requests made with a revoked certificate. The authorizer already refuses them
+// once the role bindings of the certificate are removed, the webhook guards the resources that
+// grant access, for a certificate not to regain any through a binding left behind.
+type Webhook struct {
+	configMapsLister corelisters.ConfigMapLister
+	configMapsSynced cache.InformerSynced
+}
+
+// NewWebhook returns a new webhook, the config map informer is expected to watch the namespace
+// of the revocation list
+func NewWebhook(configMapInformer coreinformers.ConfigMapInformer) *Webhook {
+	return &Webhook{
+		configMapsLister: configMapInformer.Lister(),
+		configMapsSynced: configMapInformer.Informer().HasSynced,
+	}
+}
+
+// WaitForCacheSync blocks until the caches of the webhook are synced or stopCh is closed
+func (w *Webhook) WaitForCacheSync(stopCh <-chan struct{}) error {
+	if ok := cache.WaitForCacheSync(stopCh, w.configMapsSynced); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+	return nil
+}
+
+// Handler serves the admission reviews of any request
+func (w *Webhook) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		review := admissionv1.AdmissionReview{}
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil || review.Request == nil {
+			http.Error(rw, "invalid admission review", http.StatusBadRequest)
+			return
+		}
+		response := &admissionv1.AdmissionResponse{UID: review.Request.UID, Allowed: true}
+		if err := w.Admit(review.Request.UserInfo); err != nil {
+			response.Allowed = false
+			response.Result = &metav1.Status{Status: metav1.StatusFailure, Code: http.StatusForbidden, Reason: metav1.StatusReasonForbidden, Message: err.Error()}
+		}
+		review.Request = nil
+		review.Response = response
+		rw.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(rw).Encode(review); err != nil {
+			klog.ErrorS(err, "Couldn't write the admission review")
+		}
+	})
+}
+
+// Admit returns why the request of the user is denied, or nil if it is admitted
+func (w *Webhook) Admit(userInfo authenticationv1.UserInfo) error {
+	revocationList, err := w.configMapsLister.ConfigMaps(RevocationList.Namespace).Get(RevocationList.Name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			klog.ErrorS(err, "Couldn't get the revocation list")
+		}
+		return nil
+	}
+	if id, revoked := RevokedID(userInfo.Groups, revocationList.Data); revoked {
+		klog.V(4).InfoS("Denied the request of a revoked certificate", "user", userInfo.Username, "certificate", id)
+		return fmt.Errorf("certificate %s of %s is revoked", id, userInfo.Username)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// ApplyReactor lets the fake clientsets serve the server-side apply patches, which their trackers reject.
+// The object is created if it does not exist, otherwise the applied configuration is merged into it.
+func ApplyReactor(tracker k8stesting.ObjectTracker, scheme *runtime.Scheme) k8stesting.ReactionFunc {
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		patchAction, ok := action.(k8stesting.PatchAction)
+		if !ok || patchAction.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+		typeMeta := metav1.TypeMeta{}
+		if err := json.Unmarshal(patchAction.GetPatch(), &typeMeta); err != nil {
+			return true, nil, err
+		}
+		obj, err := scheme.New(typeMeta.GroupVersionKind())
+		if err != nil {
+			return true, nil, err
+		}
+		gvr, namespace, name := patchAction.GetResource(), patchAction.GetNamespace(), patchAction.GetName()
+		current, err := tracker.Get(gvr, namespace, name)
+		if errors.IsNotFound(err) {
+			if err := json.Unmarshal(patchAction.GetPatch(), obj); err != nil {
+				return true, nil, err
+			}
+			if err := tracker.Create(gvr, obj, namespace); err != nil {
+				return true, nil, err
+			}
+			applied, err := tracker.Get(gvr, namespace, name)
+			return true, applied, err
+		} else if err != nil {
+			return true, nil, err
+		}
+		currentJSON, err := json.Marshal(current)
+		if err != nil {
+			return true, nil, err
+		}
+		mergedJSON, err := strategicpatch.StrategicMergePatch(currentJSON, patchAction.GetPatch(), obj)
+		if err != nil {
+			return true, nil, err
+		}
+		if err := json.Unmarshal(mergedJSON, obj); err != nil {
+			return true, nil, err
+		}
+		if err := tracker.Update(gvr, obj, namespace); err != nil {
+			return true, nil, err
+		}
+		applied, err := tracker.Get(gvr, namespace, name)
+		return true, applied, err
+	}
+}
@@ -19,7 +19,6 @@ package util
 import (
 	"fmt"
 	"hash/adler32"
-	"log"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -33,6 +32,7 @@ import (
 	yaml "gopkg.in/yaml.v2"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
 	//cmdconfig "k8s.io/kubernetes/pkg/kubectl/cmd/config"
 	//cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
 )
@@ -99,7 +99,7 @@ func getConfigView() (api.Config, error) {
 	configCmd.Flags().String("context", "kubernetes-admin@kubernetes", "The name of the kubeconfig context to use")
 	configCmd.Flags().Parse([]string{"--minify", "--output=json", "--raw=true"})
 	if err := configCmd.Execute(); err != nil {
-		log.Printf("unexpected error executing command: %v", err)
+		klog.ErrorS(err, "Unexpected error executing command")
 		return "", err
 	}
 
@@ -112,14 +112,14 @@ func getConfigView() (api.Config, error) {
 func GetClusterServerOfCurrentContext() (string, string, []byte, error) {
 	rawConfig, err := getConfigView()
 	if err != nil {
-		log.Printf("unexpected error executing command: %v", err)
+		klog.ErrorS(err, "Unexpected error executing command")
 		return "", "", nil, err
 	}
 	/*
 		var configViewDet configView
 		err = json.Unmarshal([]byte(configStr), &configViewDet)
 		if err != nil {
-			log.Printf("unexpected error executing command: %v", err)
+			klog.ErrorS(err, "Unexpected error executing command")
 			return "", "", nil, err
 		}*/
 
@@ -146,13 +146,13 @@ func GetClusterServerOfCurrentContext() (string, string, []byte, error) {
 func GetServerOfCurrentContext() (string, error) {
 	rawConfig, err := getConfigView()
 	if err != nil {
-		log.Printf("unexpected error executing command: %v", err)
+		klog.ErrorS(err, "Unexpected error executing command")
 		return "", err
 	}
 	/*var configViewDet configView
 	err = json.Unmarshal([]byte(configStr), &configViewDet)
 	if err != nil {
-		log.Printf("unexpected error executing command: %v", err)
+		klog.ErrorS(err, "Unexpected error executing command")
 		return "", err
 	}*/
 	// currentContext := rawConfig.CurrentContext
@@ -177,7 +177,7 @@ func GetNamecheapCredentials() (string, string, string, error) {
 	// The path of the yaml config file of namecheap
 	file, err := os.Open("../../configs/namecheap.yaml")
 	if err != nil {
-		log.Printf("unexpected error executing command: %v", err)
+		klog.ErrorS(err, "Unexpected error executing command")
 		return "", "", "", err
 	}
 
@@ -185,7 +185,7 @@ func GetNamecheapCredentials() (string, string, string, error) {
 	var namecheap namecheap
 	err = decoder.Decode(&namecheap)
 	if err != nil {
-		log.Printf("unexpected error executing command: %v", err)
+		klog.ErrorS(err, "Unexpected error executing command")
 		return "", "", "", err
 	}
 	return namecheap.APIUser, namecheap.APIToken, namecheap.Username, nil
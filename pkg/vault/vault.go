@@ -0,0 +1,246 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vault lets the tenants keep their credentials in HashiCorp Vault. Each tenant has a path
+// under the KV version 2 secrets engine, for instance secret/edgenet/tenants/lip6, and a role of the
+// Kubernetes auth method whose policy only reads that path. The role is bound to a service account
+// in the core namespace of the tenant, so that the secrets are read with the rights of the tenant
+// rather than those of the controller.
+//
+// The controller manages the policies and the roles with its own token, which needs the following
+// policy:
+//
+//	path "sys/policies/acl/edgenet-*" {
+//		capabilities = ["create", "update", "delete"]
+//	}
+//
+//	path "auth/kubernetes/role/edgenet-*" {
+//		capabilities = ["create", "update", "delete"]
+//	}
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Address of the Vault server, the secrets are not synced if empty
+var Address string
+
+// Token of the controller, it manages the policies and the roles of the tenants
+var Token string
+
+// Client sends the requests to Vault
+var Client = &http.Client{Timeout: 10 * time.Second}
+
+// AuthMount is the path the Kubernetes auth method is enabled at
+var AuthMount = "kubernetes"
+
+// KVMount is the path the KV version 2 secrets engine holding the secrets of the tenants is enabled at
+var KVMount = "secret"
+
+// Prefix of the paths of the tenants in the secrets engine
+var Prefix = "edgenet/tenants"
+
+// Audience of the service account tokens the tenants log in with, the role only accepts it
+var Audience = "vault"
+
+// TokenTTL is the lifetime of the Vault tokens issued to the tenants
+var TokenTTL = 10 * time.Minute
+
+// ErrNotFound tells that the secret does not exist in the path of the tenant
+var ErrNotFound = fmt.Errorf("secret not found in vault")
+
+// Secret is a version of a secret read from Vault
+type Secret struct {
+	Data    map[string]string
+	Version int
+}
+
+// Enabled tells whether the secrets are synced from Vault
+func Enabled() bool {
+	return Address != ""
+}
+
+// Configure sets the server up, the certificate authority is only needed if the server is served
+// with a certificate the system does not trust
+func Configure(address, caPath, tokenPath string) error {
+	Address = strings.TrimSuffix(address, "/")
+	if address == "" {
+		return nil
+	}
+	if tokenPath != "" {
+		token, err := ioutil.ReadFile(tokenPath)
+		if err != nil {
+			return err
+		}
+		Token = strings.TrimSpace(string(token))
+	}
+	if caPath == "" {
+		return nil
+	}
+	caPEM, err := ioutil.ReadFile(caPath)
+	if err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return fmt.Errorf("no certificate found in %s", caPath)
+	}
+	Client = &http.Client{Timeout: Client.Timeout, Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}}}
+	return nil
+}
+
+// RoleName returns the name of the role and of the policy of a tenant
+func RoleName(tenant string) string {
+	return fmt.Sprintf("edgenet-%s", tenant)
+}
+
+// TenantPath returns the path of the secrets of a tenant in the secrets engine
+func TenantPath(tenant string) string {
+	return fmt.Sprintf("%s/%s", Prefix, tenant)
+}
+
+// Policy returns the policy of a tenant, it reads the secrets of the tenant and nothing else
+func Policy(tenant string) string {
+	return fmt.Sprintf("path \"%s/data/%s/*\" {\n  capabilities = [\"read\"]\n}\n", KVMount, TenantPath(tenant))
+}
+
+// ApplyRole writes the policy and the role of a tenant, the role is bound to the service account
+func ApplyRole(ctx context.Context, tenant, serviceAccountNamespace, serviceAccountName string) error {
+	if err := request(ctx, http.MethodPut, "sys/policies/acl/"+RoleName(tenant), Token, map[string]string{"policy": Policy(tenant)}, nil); err != nil {
+		return err
+	}
+	role := map[string]interface{}{
+		"bound_service_account_names":      []string{serviceAccountName},
+		"bound_service_account_namespaces": []string{serviceAccountNamespace},
+		"audience":                         Audience,
+		"token_policies":                   []string{RoleName(tenant)},
+		"token_ttl":                        int(TokenTTL.Seconds()),
+	}
+	return request(ctx, http.MethodPost, fmt.Sprintf("auth/%s/role/%s", AuthMount, RoleName(tenant)), Token, role, nil)
+}
+
+// DeleteRole removes the role and the policy of a tenant, the secrets of the tenant stay in Vault
+func DeleteRole(ctx context.Context, tenant string) error {
+	if err := request(ctx, http.MethodDelete, fmt.Sprintf("auth/%s/role/%s", AuthMount, RoleName(tenant)), Token, nil, nil); err != nil {
+		return err
+	}
+	return request(ctx, http.MethodDelete, "sys/policies/acl/"+RoleName(tenant), Token, nil, nil)
+}
+
+// Login exchanges a service account token of a tenant for a Vault token of its role
+func Login(ctx context.Context, tenant, jwt string) (string, error) {
+	reply := struct {
+		Auth *struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}{}
+	if err := request(ctx, http.MethodPost, fmt.Sprintf("auth/%s/login", AuthMount), "", map[string]string{"role": RoleName(tenant), "jwt": jwt}, &reply); err != nil {
+		return "", err
+	}
+	if reply.Auth == nil || reply.Auth.ClientToken == "" {
+		return "", fmt.Errorf("no token issued for role %s", RoleName(tenant))
+	}
+	return reply.Auth.ClientToken, nil
+}
+
+// Read returns the latest version of a secret in the path of the tenant, the values that are not
+// strings are kept as JSON
+func Read(ctx context.Context, token, tenant, path string) (*Secret, error) {
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return nil, fmt.Errorf("invalid secret path %q", path)
+		}
+	}
+	reply := struct {
+		Data struct {
+			Data     map[string]interface{} `json:"data"`
+			Metadata struct {
+				Version int `json:"version"`
+			} `json:"metadata"`
+		} `json:"data"`
+	}{}
+	if err := request(ctx, http.MethodGet, fmt.Sprintf("%s/data/%s/%s", KVMount, TenantPath(tenant), path), token, nil, &reply); err != nil {
+		return nil, err
+	}
+	secret := &Secret{Data: map[string]string{}, Version: reply.Data.Metadata.Version}
+	for key, value := range reply.Data.Data {
+		if text, ok := value.(string); ok {
+			secret.Data[key] = text
+			continue
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		secret.Data[key] = string(raw)
+	}
+	return secret, nil
+}
+
+// request sends a request to the API of Vault and decodes its reply if any
+func request(ctx context.Context, method, path, token string, body, reply interface{}) error {
+	payload := bytes.NewReader(nil)
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(raw)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/v1/%s", Address, path), payload)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		request.Header.Set("X-Vault-Token", token)
+	}
+	response, err := Client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode == http.StatusNotFound && method == http.MethodGet {
+		return ErrNotFound
+	}
+	if response.StatusCode >= 300 {
+		errors := struct {
+			Errors []string `json:"errors"`
+		}{}
+		json.NewDecoder(response.Body).Decode(&errors)
+		if len(errors.Errors) > 0 {
+			return fmt.Errorf("vault replied %s: %s", response.Status, strings.Join(errors.Errors, "; "))
+		}
+		return fmt.Errorf("vault replied %s", response.Status)
+	}
+	if reply == nil || response.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(response.Body).Decode(reply); err != nil {
+		return fmt.Errorf("malformed reply of vault: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+)
+
+func TestVault(t *testing.T) {
+	defer func() { Address, Token = "", "" }()
+	util.Equals(t, false, Enabled())
+
+	requests := map[string]map[string]interface{}{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&body)
+		requests[r.Method+" "+r.URL.Path] = body
+		switch r.URL.Path {
+		case "/v1/sys/policies/acl/edgenet-lip6", "/v1/auth/kubernetes/role/edgenet-lip6":
+			util.Equals(t, "manager", r.Header.Get("X-Vault-Token"))
+			w.WriteHeader(http.StatusNoContent)
+		case "/v1/auth/kubernetes/login":
+			if body["jwt"] != "lip6-jwt" {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"errors":["permission denied"]}`))
+				return
+			}
+			w.Write([]byte(`{"auth":{"client_token":"lip6-token"}}`))
+		case "/v1/secret/data/edgenet/tenants/lip6/registry":
+			util.Equals(t, "lip6-token", r.Header.Get("X-Vault-Token"))
+			w.Write([]byte(`{"data":{"data":{"username":"lip6","password":"s3cr3t","port":5000},"metadata":{"version":3}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer server.Close()
+	util.OK(t, Configure(server.URL+"/", "", ""))
+	Token = "manager"
+	util.Equals(t, true, Enabled())
+
+	util.OK(t, ApplyRole(context.TODO(), "lip6", "lip6", "edgenet-vault"))
+	util.Equals(t, Policy("lip6"), requests["PUT /v1/sys/policies/acl/edgenet-lip6"]["policy"])
+	role := requests["POST /v1/auth/kubernetes/role/edgenet-lip6"]
+	util.Equals(t, []interface{}{"edgenet-vault"}, role["bound_service_account_names"])
+	util.Equals(t, []interface{}{"lip6"}, role["bound_service_account_namespaces"])
+	util.Equals(t, []interface{}{"edgenet-lip6"}, role["token_policies"])
+
+	_, err := Login(context.TODO(), "lip6", "other-jwt")
+	util.Assert(t, err != nil, "login with a foreign token")
+	token, err := Login(context.TODO(), "lip6", "lip6-jwt")
+	util.OK(t, err)
+	util.Equals(t, "lip6-token", token)
+	util.Equals(t, "edgenet-lip6", requests["POST /v1/auth/kubernetes/login"]["role"])
+
+	secret, err := Read(context.TODO(), token, "lip6", "registry")
+	util.OK(t, err)
+	util.Equals(t, 3, secret.Version)
+	util.Equals(t, map[string]string{"username": "lip6", "password": "s3cr3t", "port": "5000"}, secret.Data)
+
+	_, err = Read(context.TODO(), token, "lip6", "database")
+	util.Equals(t, ErrNotFound, err)
+	_, err = Read(context.TODO(), token, "lip6", "../other/registry")
+	util.Assert(t, err != nil && err != ErrNotFound, "path out of the tenant read")
+}
+
+func TestPolicy(t *testing.T) {
+	util.Equals(t, "path \"secret/data/edgenet/tenants/lip6/*\" {\n  capabilities = [\"read\"]\n}\n", Policy("lip6"))
+}
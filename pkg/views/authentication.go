@@ -0,0 +1,141 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package views
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// AuthenticationConfig is the config map the API server publishes the configuration of its front
+// proxy in, the extension API servers read it to authenticate the requests the API server proxies
+var AuthenticationConfig = types.NamespacedName{Namespace: "kube-system", Name: "extension-apiserver-authentication"}
+
+// RequestHeader is the configuration of the front proxy of the API server, which forwards the
+// user in the request headers
+type RequestHeader struct {
+	// ClientCAs is the authority of the client certificate of the front proxy
+	ClientCAs *x509.CertPool
+	// AllowedNames are the common names the client certificate may have, any if empty
+	AllowedNames []string
+	// UsernameHeaders are the headers carrying the user name, the first one set is used
+	UsernameHeaders []string
+	// GroupHeaders are the headers carrying the groups of the user
+	GroupHeaders []string
+	// ExtraHeaderPrefixes are the prefixes of the headers carrying the extra attributes of the user
+	ExtraHeaderPrefixes []string
+}
+
+// User is the user that the front proxy forwards the request of
+type User struct {
+	Username string
+	Groups   []string
+	Extra    map[string]authorizationv1.ExtraValue
+}
+
+// LoadRequestHeader reads the configuration of the front proxy that the API server publishes
+func LoadRequestHeader(ctx context.Context, kubeclientset kubernetes.Interface) (*RequestHeader, error) {
+	configMap, err := kubeclientset.CoreV1().ConfigMaps(AuthenticationConfig.Namespace).Get(ctx, AuthenticationConfig.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	clientCA, ok := configMap.Data["requestheader-client-ca-file"]
+	if !ok || clientCA == "" {
+		return nil, fmt.Errorf("the API server has no front proxy authority in %s", AuthenticationConfig)
+	}
+	requestHeader := &RequestHeader{ClientCAs: x509.NewCertPool()}
+	if !requestHeader.ClientCAs.AppendCertsFromPEM([]byte(clientCA)) {
+		return nil, fmt.Errorf("invalid front proxy authority in %s", AuthenticationConfig)
+	}
+	// The lists are JSON arrays
+	for key, list := range map[string]*[]string{
+		"requestheader-allowed-names":        &requestHeader.AllowedNames,
+		"requestheader-username-headers":     &requestHeader.UsernameHeaders,
+		"requestheader-group-headers":        &requestHeader.GroupHeaders,
+		"requestheader-extra-headers-prefix": &requestHeader.ExtraHeaderPrefixes,
+	} {
+		if value := configMap.Data[key]; value != "" {
+			if err := json.Unmarshal([]byte(value), list); err != nil {
+				return nil, fmt.Errorf("invalid %s in %s: %s", key, AuthenticationConfig, err)
+			}
+		}
+	}
+	if len(requestHeader.UsernameHeaders) == 0 {
+		return nil, fmt.Errorf("the API server has no user header in %s", AuthenticationConfig)
+	}
+	return requestHeader, nil
+}
+
+// User returns the user the request is forwarded for, if the request comes from the front proxy.
+// The client certificate is verified against the authority of the front proxy by the TLS server,
+// the headers of any other client are not trusted.
+func (h *RequestHeader) User(r *http.Request) (*User, bool) {
+	if h == nil || r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+	if len(h.AllowedNames) > 0 {
+		allowed := false
+		for _, name := range h.AllowedNames {
+			if r.TLS.VerifiedChains[0][0].Subject.CommonName == name {
+				allowed = true
+			}
+		}
+		if !allowed {
+			return nil, false
+		}
+	}
+
+	user := &User{}
+	for _, header := range h.UsernameHeaders {
+		if user.Username = r.Header.Get(header); user.Username != "" {
+			break
+		}
+	}
+	if user.Username == "" {
+		return nil, false
+	}
+	for _, header := range h.GroupHeaders {
+		user.Groups = append(user.Groups, r.Header.Values(header)...)
+	}
+	for header, values := range r.Header {
+		for _, prefix := range h.ExtraHeaderPrefixes {
+			if !strings.HasPrefix(strings.ToLower(header), strings.ToLower(prefix)) {
+				continue
+			}
+			// The keys of the extra attributes are escaped to be valid header names
+			key, err := url.PathUnescape(strings.ToLower(header[len(prefix):]))
+			if err != nil {
+				continue
+			}
+			if user.Extra == nil {
+				user.Extra = map[string]authorizationv1.ExtraValue{}
+			}
+			user.Extra[key] = append(user.Extra[key], values...)
+		}
+	}
+	return user, true
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	viewsv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/views/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/index"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// pending is the state of the requests awaiting a decision
+const pending = "Pending"
+
+// summarize computes the summary of the tenant out of the informer caches
+func (s *Server) summarize(tenant *corev1alpha.Tenant) (*viewsv1alpha.TenantSummary, error) {
+	summary := &viewsv1alpha.TenantSummary{TypeMeta: metav1.TypeMeta{Kind: "TenantSummary", APIVersion: viewsv1alpha.SchemeGroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Name: tenant.GetName(), Namespace: tenant.GetName(), Labels: tenant.GetLabels(),
+			CreationTimestamp: tenant.GetCreationTimestamp()},
+		Status: viewsv1alpha.TenantSummaryStatus{Enabled: tenant.Spec.Enabled, State: tenant.Status.State}}
+
+	namespaces, err := index.Namespaces(s.namespacesIndexer, tenant.GetName(), nil)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := s.tree(tenant.GetName(), namespaces)
+	if err != nil {
+		return nil, err
+	}
+	summary.Status.Namespaces = tree
+	summary.Status.Quota = total(tree)
+
+	nodes, err := s.nodes(tenant.GetName())
+	if err != nil {
+		return nil, err
+	}
+	summary.Status.Nodes = nodes
+
+	requests, err := s.backlog(namespaces)
+	if err != nil {
+		return nil, err
+	}
+	summary.Status.Requests = requests
+	return summary, nil
+}
+
+// tree arranges the namespaces of the tenant by the namespace their subnamespace is in, from the
+// core namespace of the tenant. A namespace whose parent is gone, or out of reach, is put under the
+// core namespace, so that its quota is still accounted for.
+func (s *Server) tree(tenant string, namespaces []*corev1.Namespace) (viewsv1alpha.NamespaceTree, error) {
+	children := map[string][]*corev1.Namespace{}
+	known := map[string]bool{tenant: true}
+	for _, namespace := range namespaces {
+		known[namespace.GetName()] = true
+	}
+	for _, namespace := range namespaces {
+		if namespace.GetName() == tenant {
+			continue
+		}
+		parent := namespace.GetLabels()["edge-net.io/parent-namespace"]
+		if !known[parent] {
+			parent = tenant
+		}
+		children[parent] = append(children[parent], namespace)
+	}
+
+	visited := map[string]bool{}
+	var build func(name, subnamespace string) (viewsv1alpha.NamespaceTree, error)
+	build = func(name, subnamespace string) (viewsv1alpha.NamespaceTree, error) {
+		visited[name] = true
+		node := viewsv1alpha.NamespaceTree{Name: name, SubNamespace: subnamespace}
+		quota, err := s.quota(name)
+		if err != nil {
+			return node, err
+		}
+		node.Quota = quota
+		sort.Slice(children[name], func(i, j int) bool { return children[name][i].GetName() < children[name][j].GetName() })
+		for _, child := range children[name] {
+			// A namespace labeled with a descendant of its own as parent is shown once
+			if visited[child.GetName()] {
+				continue
+			}
+			subtree, err := build(child.GetName(), child.GetLabels()["edge-net.io/owner"])
+			if err != nil {
+				return node, err
+			}
+			node.Children = append(node.Children, subtree)
+		}
+		return node, nil
+	}
+	root, err := build(tenant, "")
+	if err != nil {
+		return root, err
+	}
+	// The namespaces whose parents are labeled with each other are out of reach from the core namespace
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].GetName() < namespaces[j].GetName() })
+	for _, namespace := range namespaces {
+		if !visited[namespace.GetName()] {
+			subtree, err := build(namespace.GetName(), namespace.GetLabels()["edge-net.io/owner"])
+			if err != nil {
+				return root, err
+			}
+			root.Children = append(root.Children, subtree)
+		}
+	}
+	return root, nil
+}
+
+// quota sums the resource quotas of the namespace
+func (s *Server) quota(namespace string) (viewsv1alpha.ResourceUsage, error) {
+	resourceQuotas, err := s.resourceQuotasLister.ResourceQuotas(namespace).List(labels.Everything())
+	if err != nil {
+		return viewsv1alpha.ResourceUsage{}, err
+	}
+	usage := viewsv1alpha.ResourceUsage{}
+	for _, resourceQuota := range resourceQuotas {
+		usage.Hard = addResources(usage.Hard, resourceQuota.Spec.Hard)
+		usage.Used = addResources(usage.Used, resourceQuota.Status.Used)
+	}
+	return usage, nil
+}
+
+// total sums the quotas of the namespaces in the tree. The quota of a subnamespace is taken from
+// its parent, so the sum is the quota of the tenant.
+func total(tree viewsv1alpha.NamespaceTree) viewsv1alpha.ResourceUsage {
+	usage := viewsv1alpha.ResourceUsage{Hard: addResources(nil, tree.Quota.Hard), Used: addResources(nil, tree.Quota.Used)}
+	for _, child := range tree.Children {
+		subtotal := total(child)
+		usage.Hard = addResources(usage.Hard, subtotal.Hard)
+		usage.Used = addResources(usage.Used, subtotal.Used)
+	}
+	return usage
+}
+
+// nodes counts the nodes contributed by the tenant, and those of them that are ready
+func (s *Server) nodes(tenant string) (viewsv1alpha.NodeCount, error) {
+	nodeContributions, err := s.nodeContributionsLister.List(labels.Everything())
+	if err != nil {
+		return viewsv1alpha.NodeCount{}, err
+	}
+	count := viewsv1alpha.NodeCount{}
+	for _, nodeContribution := range nodeContributions {
+		if nodeContribution.Spec.Tenant == nil || *nodeContribution.Spec.Tenant != tenant {
+			continue
+		}
+		count.Contributed++
+		node, err := s.nodesLister.Get(fmt.Sprintf("%s.edge-net.io", nodeContribution.GetName()))
+		if err != nil {
+			continue
+		}
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+				count.Ready++
+			}
+		}
+	}
+	return count, nil
+}
+
+// backlog counts the requests pending in the namespaces of the tenant
+func (s *Server) backlog(namespaces []*corev1.Namespace) (viewsv1alpha.RequestBacklog, error) {
+	backlog := viewsv1alpha.RequestBacklog{}
+	oldest := func(created metav1.Time) {
+		if backlog.Oldest == nil || created.Before(backlog.Oldest) {
+			backlog.Oldest = created.DeepCopy()
+		}
+	}
+	for _, namespace := range namespaces {
+		roleRequests, err := s.roleRequestsLister.RoleRequests(namespace.GetName()).List(labels.Everything())
+		if err != nil {
+			return backlog, err
+		}
+		for _, roleRequest := range roleRequests {
+			// The requests not processed yet have no state
+			if !roleRequest.Spec.Approved && (roleRequest.Status.State == "" || roleRequest.Status.State == pending) {
+				backlog.RoleRequests++
+				oldest(roleRequest.GetCreationTimestamp())
+			}
+		}
+		extensionRequests, err := s.extensionRequestsLister.ExtensionRequests(namespace.GetName()).List(labels.Everything())
+		if err != nil {
+			return backlog, err
+		}
+		for _, extensionRequest := range extensionRequests {
+			if !extensionRequest.Status.Approved && (extensionRequest.Status.State == "" || extensionRequest.Status.State == pending) {
+				backlog.ExtensionRequests++
+				oldest(extensionRequest.GetCreationTimestamp())
+			}
+		}
+	}
+	return backlog, nil
+}
+
+// tableOf returns the table kubectl prints the summaries as
+func tableOf(object runtime.Object) (*metav1.Table, error) {
+	table := &metav1.Table{TypeMeta: metav1.TypeMeta{Kind: "Table", APIVersion: "meta.k8s.io/v1"},
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string", Format: "name"},
+			{Name: "Enabled", Type: "boolean"},
+			{Name: "Namespaces", Type: "integer"},
+			{Name: "Nodes", Type: "string", Description: "Ready nodes out of the contributed ones"},
+			{Name: "CPU", Type: "string", Description: "Quota used out of the hard limit"},
+			{Name: "Memory", Type: "string", Description: "Quota used out of the hard limit"},
+			{Name: "Pending", Type: "integer", Description: "Requests awaiting a decision"},
+			{Name: "Age", Type: "string"},
+		}}
+	var summaries []viewsv1alpha.TenantSummary
+	switch object := object.(type) {
+	case *viewsv1alpha.TenantSummary:
+		summaries = []viewsv1alpha.TenantSummary{*object}
+	case *viewsv1alpha.TenantSummaryList:
+		summaries = object.Items
+	default:
+		return nil, fmt.Errorf("no table for %T", object)
+	}
+	for _, summary := range summaries {
+		// kubectl reads the namespace of the rows in their metadata
+		metadata, err := json.Marshal(&metav1.PartialObjectMetadata{TypeMeta: metav1.TypeMeta{Kind: "PartialObjectMetadata", APIVersion: "meta.k8s.io/v1"},
+			ObjectMeta: summary.ObjectMeta})
+		if err != nil {
+			return nil, err
+		}
+		status := summary.Status
+		table.Rows = append(table.Rows, metav1.TableRow{Object: runtime.RawExtension{Raw: metadata},
+			Cells: []interface{}{summary.GetName(), status.Enabled, count(status.Namespaces),
+				fmt.Sprintf("%d/%d", status.Nodes.Ready, status.Nodes.Contributed),
+				usage(status.Quota, corev1.ResourceCPU), usage(status.Quota, corev1.ResourceMemory),
+				status.Requests.RoleRequests + status.Requests.ExtensionRequests,
+				duration.HumanDuration(time.Since(summary.GetCreationTimestamp().Time))}})
+	}
+	return table, nil
+}
+
+// count returns the number of namespaces in the tree
+func count(tree viewsv1alpha.NamespaceTree) int {
+	namespaces := 1
+	for _, child := range tree.Children {
+		namespaces += count(child)
+	}
+	return namespaces
+}
+
+// usage returns the quota of the resource used out of its hard limit, <none> if not limited
+func usage(quota viewsv1alpha.ResourceUsage, name corev1.ResourceName) string {
+	hard, ok := quota.Hard[name]
+	if !ok {
+		return "<none>"
+	}
+	used := quota.Used[name]
+	return fmt.Sprintf("%s/%s", used.String(), hard.String())
+}
+
+func addResources(total, resources corev1.ResourceList) corev1.ResourceList {
+	for name, quantity := range resources {
+		if total == nil {
+			total = corev1.ResourceList{}
+		}
+		sum := total[name]
+		sum.Add(quantity)
+		total[name] = sum
+	}
+	return total
+}
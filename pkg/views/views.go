@@ -0,0 +1,279 @@
+/*
+Copyright 2021 Contributors to the EdgeNet project.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package views serves the views.edgenet.io API group through the aggregation layer of the
+// Kubernetes API server. Its resources are read-only views computed from the informers of several
+// resources on request, so that the dashboards read a tenant at once instead of joining its
+// namespaces, quotas, nodes, and requests themselves:
+//
+//	GET /apis/views.edgenet.io/v1alpha/tenantsummaries
+//	GET /apis/views.edgenet.io/v1alpha/namespaces/<namespace>/tenantsummaries
+//	GET /apis/views.edgenet.io/v1alpha/namespaces/<namespace>/tenantsummaries/<name>
+//
+// The summary of a tenant lives in its core namespace under its name. The API server proxies the
+// requests with a client certificate of its front proxy and the user in the request headers, and
+// the user is authorized through subject access reviews as for the other resources.
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/EdgeNet-project/edgenet/pkg/apis/views"
+	viewsv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/views/v1alpha"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/core/v1alpha"
+	registrationinformers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions/registration/v1alpha"
+	listers "github.com/EdgeNet-project/edgenet/pkg/generated/listers/core/v1alpha"
+	registrationlisters "github.com/EdgeNet-project/edgenet/pkg/generated/listers/registration/v1alpha"
+	"github.com/EdgeNet-project/edgenet/pkg/index"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// summaryResource is the plural name the tenant summaries are served under
+const summaryResource = "tenantsummaries"
+
+// Server serves the views out of the informer caches
+type Server struct {
+	// kubeclientset is a standard kubernetes clientset, it reviews the access of the users
+	kubeclientset kubernetes.Interface
+	// requestHeader authenticates the front proxy of the API server
+	requestHeader *RequestHeader
+
+	tenantsLister           listers.TenantLister
+	nodeContributionsLister listers.NodeContributionLister
+	roleRequestsLister      registrationlisters.RoleRequestLister
+	extensionRequestsLister registrationlisters.ExtensionRequestLister
+	namespacesIndexer       cache.Indexer
+	resourceQuotasLister    corelisters.ResourceQuotaLister
+	nodesLister             corelisters.NodeLister
+	synced                  []cache.InformerSynced
+}
+
+// NewServer returns a server reading the objects the views are computed from in the informers
+func NewServer(
+	kubeclientset kubernetes.Interface,
+	requestHeader *RequestHeader,
+	tenantInformer informers.TenantInformer,
+	nodeContributionInformer informers.NodeContributionInformer,
+	roleRequestInformer registrationinformers.RoleRequestInformer,
+	extensionRequestInformer registrationinformers.ExtensionRequestInformer,
+	namespaceInformer coreinformers.NamespaceInformer,
+	resourceQuotaInformer coreinformers.ResourceQuotaInformer,
+	nodeInformer coreinformers.NodeInformer) *Server {
+
+	// The namespaces of a tenant are looked up by the label naming it
+	index.AddTenantIndex(namespaceInformer.Informer())
+	return &Server{
+		kubeclientset:           kubeclientset,
+		requestHeader:           requestHeader,
+		tenantsLister:           tenantInformer.Lister(),
+		nodeContributionsLister: nodeContributionInformer.Lister(),
+		roleRequestsLister:      roleRequestInformer.Lister(),
+		extensionRequestsLister: extensionRequestInformer.Lister(),
+		namespacesIndexer:       namespaceInformer.Informer().GetIndexer(),
+		resourceQuotasLister:    resourceQuotaInformer.Lister(),
+		nodesLister:             nodeInformer.Lister(),
+		synced: []cache.InformerSynced{tenantInformer.Informer().HasSynced, nodeContributionInformer.Informer().HasSynced,
+			roleRequestInformer.Informer().HasSynced, extensionRequestInformer.Informer().HasSynced, namespaceInformer.Informer().HasSynced,
+			resourceQuotaInformer.Informer().HasSynced, nodeInformer.Informer().HasSynced},
+	}
+}
+
+// WaitForCacheSync waits for the informer caches to sync, the views are not served before
+func (s *Server) WaitForCacheSync(stopCh <-chan struct{}) error {
+	if ok := cache.WaitForCacheSync(stopCh, s.synced...); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+	return nil
+}
+
+// Handler serves the discovery of the API group, which the API server reads without a user, and
+// the views
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+	mux.HandleFunc("/apis/"+views.GroupName, func(w http.ResponseWriter, r *http.Request) {
+		version := metav1.GroupVersionForDiscovery{GroupVersion: viewsv1alpha.SchemeGroupVersion.String(), Version: viewsv1alpha.SchemeGroupVersion.Version}
+		writeJSON(w, http.StatusOK, &metav1.APIGroup{TypeMeta: metav1.TypeMeta{Kind: "APIGroup", APIVersion: "v1"},
+			Name: views.GroupName, Versions: []metav1.GroupVersionForDiscovery{version}, PreferredVersion: version})
+	})
+	prefix := "/apis/" + viewsv1alpha.SchemeGroupVersion.String()
+	mux.HandleFunc(prefix, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, &metav1.APIResourceList{TypeMeta: metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+			GroupVersion: viewsv1alpha.SchemeGroupVersion.String(),
+			APIResources: []metav1.APIResource{{Name: summaryResource, SingularName: "tenantsummary", Namespaced: true, Kind: "TenantSummary",
+				Verbs: metav1.Verbs{"get", "list"}, ShortNames: []string{"tsum"}}}})
+	})
+	mux.HandleFunc(prefix+"/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, prefix+"/"), "/")
+		switch {
+		case len(parts) == 1 && parts[0] == summaryResource:
+			s.serve(w, r, "", "")
+		case len(parts) == 3 && parts[0] == "namespaces" && parts[1] != "" && parts[2] == summaryResource:
+			s.serve(w, r, parts[1], "")
+		case len(parts) == 4 && parts[0] == "namespaces" && parts[1] != "" && parts[2] == summaryResource && parts[3] != "":
+			s.serve(w, r, parts[1], parts[3])
+		default:
+			writeStatus(w, apierrors.NewNotFound(viewsv1alpha.Resource(summaryResource), ""))
+		}
+	})
+	return mux
+}
+
+// serve replies with the summary of a tenant if the name is given, or the summaries of the
+// tenants in the namespace otherwise, in all namespaces if none is given
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	verb := "get"
+	if name == "" {
+		verb = "list"
+	}
+	if r.Method != http.MethodGet {
+		writeStatus(w, apierrors.NewMethodNotSupported(viewsv1alpha.Resource(summaryResource), strings.ToLower(r.Method)))
+		return
+	}
+	// The views are computed on request, there are no changes to watch
+	if watch := r.URL.Query().Get("watch"); watch == "true" || watch == "1" {
+		writeStatus(w, apierrors.NewMethodNotSupported(viewsv1alpha.Resource(summaryResource), "watch"))
+		return
+	}
+	if err := s.authorize(r, verb, namespace, name); err != nil {
+		writeStatus(w, err)
+		return
+	}
+
+	if name != "" {
+		summary, err := s.get(namespace, name)
+		if err != nil {
+			writeStatus(w, err)
+			return
+		}
+		write(w, r, summary)
+		return
+	}
+	selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		writeStatus(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+	list, statusErr := s.list(namespace, selector)
+	if statusErr != nil {
+		writeStatus(w, statusErr)
+		return
+	}
+	write(w, r, list)
+}
+
+// get returns the summary of the tenant whose core namespace is the given one
+func (s *Server) get(namespace, name string) (*viewsv1alpha.TenantSummary, *apierrors.StatusError) {
+	tenant, err := s.tenantsLister.Get(name)
+	if apierrors.IsNotFound(err) || (err == nil && namespace != name) {
+		return nil, apierrors.NewNotFound(viewsv1alpha.Resource(summaryResource), name)
+	} else if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	summary, err := s.summarize(tenant)
+	if err != nil {
+		klog.ErrorS(err, "Couldn't summarize the tenant", "tenant", klog.KObj(tenant))
+		return nil, apierrors.NewInternalError(err)
+	}
+	return summary, nil
+}
+
+// list returns the summaries of the tenants matching the selector, by name
+func (s *Server) list(namespace string, selector labels.Selector) (*viewsv1alpha.TenantSummaryList, *apierrors.StatusError) {
+	tenants, err := s.tenantsLister.List(selector)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].GetName() < tenants[j].GetName() })
+	list := &viewsv1alpha.TenantSummaryList{TypeMeta: metav1.TypeMeta{Kind: "TenantSummaryList", APIVersion: viewsv1alpha.SchemeGroupVersion.String()},
+		Items: []viewsv1alpha.TenantSummary{}}
+	for _, tenant := range tenants {
+		if namespace != "" && tenant.GetName() != namespace {
+			continue
+		}
+		summary, err := s.summarize(tenant)
+		if err != nil {
+			klog.ErrorS(err, "Couldn't summarize the tenant", "tenant", klog.KObj(tenant))
+			return nil, apierrors.NewInternalError(err)
+		}
+		list.Items = append(list.Items, *summary)
+	}
+	return list, nil
+}
+
+// authorize reviews the access of the user that the front proxy forwards the request of
+func (s *Server) authorize(r *http.Request, verb, namespace, name string) *apierrors.StatusError {
+	user, ok := s.requestHeader.User(r)
+	if !ok {
+		return apierrors.NewUnauthorized("the request wasn't forwarded by the API server")
+	}
+	review := &authorizationv1.SubjectAccessReview{Spec: authorizationv1.SubjectAccessReviewSpec{
+		User:   user.Username,
+		Groups: user.Groups,
+		Extra:  user.Extra,
+		ResourceAttributes: &authorizationv1.ResourceAttributes{Group: views.GroupName, Version: viewsv1alpha.SchemeGroupVersion.Version,
+			Resource: summaryResource, Namespace: namespace, Name: name, Verb: verb},
+	}}
+	review, err := s.kubeclientset.AuthorizationV1().SubjectAccessReviews().Create(r.Context(), review, metav1.CreateOptions{})
+	if err != nil {
+		klog.ErrorS(err, "Couldn't review the access of the user", "user", user.Username)
+		return apierrors.NewInternalError(err)
+	} else if !review.Status.Allowed {
+		return apierrors.NewForbidden(viewsv1alpha.Resource(summaryResource), name, fmt.Errorf("user %q cannot %s %s in namespace %q", user.Username, verb, summaryResource, namespace))
+	}
+	return nil
+}
+
+// write replies with the object, or with its table if kubectl asks for one
+func write(w http.ResponseWriter, r *http.Request, object runtime.Object) {
+	if strings.Contains(r.Header.Get("Accept"), "as=Table") {
+		table, err := tableOf(object)
+		if err != nil {
+			writeStatus(w, apierrors.NewInternalError(err))
+			return
+		}
+		object = table
+	}
+	writeJSON(w, http.StatusOK, object)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeStatus replies with the status of the error, which kubectl shows as it does the errors of
+// the API server
+func writeStatus(w http.ResponseWriter, err *apierrors.StatusError) {
+	status := err.Status()
+	status.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+	writeJSON(w, int(status.Code), &status)
+}
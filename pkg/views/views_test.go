@@ -0,0 +1,300 @@
+package views
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	corev1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/core/v1alpha"
+	registrationv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/registration/v1alpha"
+	viewsv1alpha "github.com/EdgeNet-project/edgenet/pkg/apis/views/v1alpha"
+	edgenettestclient "github.com/EdgeNet-project/edgenet/pkg/generated/clientset/versioned/fake"
+	informers "github.com/EdgeNet-project/edgenet/pkg/generated/informers/externalversions"
+	"github.com/EdgeNet-project/edgenet/pkg/util"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	kubetestclient "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+	"k8s.io/klog/v2"
+)
+
+func TestMain(m *testing.M) {
+	klog.SetOutput(ioutil.Discard)
+	log.SetOutput(ioutil.Discard)
+	os.Exit(m.Run())
+}
+
+var created = metav1.NewTime(time.Date(2021, 10, 4, 9, 0, 0, 0, time.UTC))
+
+func newNamespace(name, tenant, parent, owner string) *corev1.Namespace {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"edge-net.io/tenant": tenant, "edge-net.io/kind": "core"}}}
+	if parent != "" {
+		namespace.Labels["edge-net.io/kind"] = "sub"
+		namespace.Labels["edge-net.io/parent-namespace"] = parent
+		namespace.Labels["edge-net.io/owner"] = owner
+	}
+	return namespace
+}
+
+func newResourceQuota(namespace, name, hard, used string) *corev1.ResourceQuota {
+	return &corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:   corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(hard)}},
+		Status: corev1.ResourceQuotaStatus{Used: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(used)}}}
+}
+
+func newNodeContribution(name, tenant string) *corev1alpha.NodeContribution {
+	return &corev1alpha.NodeContribution{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: corev1alpha.NodeContributionSpec{Tenant: &tenant}}
+}
+
+func newNode(name string, ready corev1.ConditionStatus) *corev1.Node {
+	return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}}}}
+}
+
+func newRoleRequest(namespace, name, state string, approved bool, age time.Duration) *registrationv1alpha.RoleRequest {
+	return &registrationv1alpha.RoleRequest{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, CreationTimestamp: metav1.NewTime(created.Add(age))},
+		Spec: registrationv1alpha.RoleRequestSpec{Approved: approved}, Status: registrationv1alpha.RoleRequestStatus{State: state}}
+}
+
+// newServer returns a server with two tenants, lip6 has a workspace in its core namespace and
+// another in that workspace. The owner of lip6 reads its summary and the administrator all of them.
+func newServer(t *testing.T) *Server {
+	kubeclientset := kubetestclient.NewSimpleClientset(
+		newNamespace("lip6", "lip6", "", ""),
+		newNamespace("lab-1a2b3c", "lip6", "lip6", "lab"),
+		newNamespace("course-4d5e6f", "lip6", "lab-1a2b3c", "course"),
+		newNamespace("unipi", "unipi", "", ""),
+		newResourceQuota("lip6", "core-quota", "6", "1"),
+		newResourceQuota("lab-1a2b3c", "sub-quota", "2", "500m"),
+		newResourceQuota("course-4d5e6f", "sub-quota", "1", "0"),
+		newResourceQuota("unipi", "core-quota", "4", "4"),
+		newNode("paris.edge-net.io", corev1.ConditionTrue),
+		newNode("nice.edge-net.io", corev1.ConditionFalse),
+	)
+	edgenetclientset := edgenettestclient.NewSimpleClientset(
+		&corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "lip6", CreationTimestamp: created, Labels: map[string]string{"edge-net.io/tier": "paying"}},
+			Spec: corev1alpha.TenantSpec{Enabled: true}, Status: corev1alpha.TenantStatus{State: "Established"}},
+		&corev1alpha.Tenant{ObjectMeta: metav1.ObjectMeta{Name: "unipi", CreationTimestamp: created}, Spec: corev1alpha.TenantSpec{Enabled: false}},
+		newNodeContribution("paris", "lip6"),
+		newNodeContribution("nice", "lip6"),
+		newNodeContribution("lyon", "lip6"),
+		newNodeContribution("pisa", "unipi"),
+		newRoleRequest("lip6", "johnsmith", "Pending", false, time.Hour),
+		newRoleRequest("lab-1a2b3c", "janesmith", "", false, 2*time.Hour),
+		newRoleRequest("lip6", "joesmith", "Approved", true, 0),
+		newRoleRequest("unipi", "johnsmith", "Pending", false, 0),
+		&registrationv1alpha.ExtensionRequest{ObjectMeta: metav1.ObjectMeta{Name: "gpu", Namespace: "lip6", CreationTimestamp: created},
+			Status: registrationv1alpha.ExtensionRequestStatus{State: "Pending"}},
+	)
+	kubeclientset.PrependReactor("create", "subjectaccessreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+		review := action.(ktesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = review.Spec.User == "admin@edge-net.org" ||
+			(review.Spec.User == "johndoe@edge-net.org" && attributes.Namespace == "lip6" && attributes.Resource == "tenantsummaries")
+		return true, review, nil
+	})
+
+	kubeInformerFactory := kubeinformers.NewSharedInformerFactory(kubeclientset, 0)
+	edgenetInformerFactory := informers.NewSharedInformerFactory(edgenetclientset, 0)
+	requestHeader := &RequestHeader{AllowedNames: []string{"front-proxy-client"}, UsernameHeaders: []string{"X-Remote-User"},
+		GroupHeaders: []string{"X-Remote-Group"}, ExtraHeaderPrefixes: []string{"X-Remote-Extra-"}}
+	s := NewServer(kubeclientset, requestHeader,
+		edgenetInformerFactory.Core().V1alpha().Tenants(),
+		edgenetInformerFactory.Core().V1alpha().NodeContributions(),
+		edgenetInformerFactory.Registration().V1alpha().RoleRequests(),
+		edgenetInformerFactory.Registration().V1alpha().ExtensionRequests(),
+		kubeInformerFactory.Core().V1().Namespaces(),
+		kubeInformerFactory.Core().V1().ResourceQuotas(),
+		kubeInformerFactory.Core().V1().Nodes(),
+	)
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	kubeInformerFactory.Start(stopCh)
+	edgenetInformerFactory.Start(stopCh)
+	util.OK(t, s.WaitForCacheSync(stopCh))
+	return s
+}
+
+// get serves the request of the user forwarded by the front proxy, or made directly if the user is empty
+func get(s *Server, path, user, accept string, body interface{}) int {
+	request := httptest.NewRequest(http.MethodGet, path, nil)
+	if user != "" {
+		proxy := &x509.Certificate{Subject: pkix.Name{CommonName: "front-proxy-client"}}
+		request.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{proxy}}}
+		request.Header.Set("X-Remote-User", user)
+	}
+	if accept != "" {
+		request.Header.Set("Accept", accept)
+	}
+	recorder := httptest.NewRecorder()
+	s.Handler().ServeHTTP(recorder, request)
+	if body != nil && recorder.Code == http.StatusOK {
+		json.NewDecoder(recorder.Body).Decode(body)
+	}
+	return recorder.Code
+}
+
+func TestSummarize(t *testing.T) {
+	s := newServer(t)
+	tenant, err := s.tenantsLister.Get("lip6")
+	util.OK(t, err)
+	summary, err := s.summarize(tenant)
+	util.OK(t, err)
+
+	util.Equals(t, "lip6", summary.GetNamespace())
+	util.Equals(t, "paying", summary.GetLabels()["edge-net.io/tier"])
+	util.Equals(t, true, summary.Status.Enabled)
+	util.Equals(t, "Established", summary.Status.State)
+	hard, used := summary.Status.Quota.Hard[corev1.ResourceCPU], summary.Status.Quota.Used[corev1.ResourceCPU]
+	util.Equals(t, "9", hard.String())
+	util.Equals(t, "1500m", used.String())
+	util.Equals(t, viewsv1alpha.NodeCount{Contributed: 3, Ready: 1}, summary.Status.Nodes)
+	util.Equals(t, 2, summary.Status.Requests.RoleRequests)
+	util.Equals(t, 1, summary.Status.Requests.ExtensionRequests)
+	util.Equals(t, created.Unix(), summary.Status.Requests.Oldest.Unix())
+
+	tree := summary.Status.Namespaces
+	util.Equals(t, "lip6", tree.Name)
+	util.Equals(t, 1, len(tree.Children))
+	util.Equals(t, "lab-1a2b3c", tree.Children[0].Name)
+	util.Equals(t, "lab", tree.Children[0].SubNamespace)
+	util.Equals(t, 1, len(tree.Children[0].Children))
+	util.Equals(t, "course", tree.Children[0].Children[0].SubNamespace)
+	subHard := tree.Children[0].Quota.Hard[corev1.ResourceCPU]
+	util.Equals(t, "2", subHard.String())
+}
+
+func TestTree(t *testing.T) {
+	t.Run("parent gone", func(t *testing.T) {
+		s := newServer(t)
+		tree, err := s.tree("lip6", []*corev1.Namespace{newNamespace("lip6", "lip6", "", ""), newNamespace("orphan-7g8h9i", "lip6", "deleted-0a0a0a", "orphan")})
+		util.OK(t, err)
+		util.Equals(t, 1, len(tree.Children))
+		util.Equals(t, "orphan-7g8h9i", tree.Children[0].Name)
+	})
+	t.Run("cycle", func(t *testing.T) {
+		s := newServer(t)
+		namespaces := []*corev1.Namespace{newNamespace("lip6", "lip6", "", ""),
+			newNamespace("a-111111", "lip6", "b-222222", "a"), newNamespace("b-222222", "lip6", "a-111111", "b")}
+		tree, err := s.tree("lip6", namespaces)
+		util.OK(t, err)
+		util.Equals(t, 1, len(tree.Children))
+		util.Equals(t, "a-111111", tree.Children[0].Name)
+		util.Equals(t, "b-222222", tree.Children[0].Children[0].Name)
+		util.Equals(t, 3, count(tree))
+	})
+}
+
+func TestHandler(t *testing.T) {
+	s := newServer(t)
+	prefix := "/apis/views.edgenet.io/v1alpha"
+
+	t.Run("discovery", func(t *testing.T) {
+		resources := metav1.APIResourceList{}
+		util.Equals(t, http.StatusOK, get(s, prefix, "", "", &resources))
+		util.Equals(t, "tenantsummaries", resources.APIResources[0].Name)
+		util.Equals(t, true, resources.APIResources[0].Namespaced)
+		group := metav1.APIGroup{}
+		util.Equals(t, http.StatusOK, get(s, "/apis/views.edgenet.io", "", "", &group))
+		util.Equals(t, "views.edgenet.io/v1alpha", group.PreferredVersion.GroupVersion)
+	})
+	t.Run("get", func(t *testing.T) {
+		summary := viewsv1alpha.TenantSummary{}
+		util.Equals(t, http.StatusOK, get(s, prefix+"/namespaces/lip6/tenantsummaries/lip6", "johndoe@edge-net.org", "", &summary))
+		util.Equals(t, "lip6", summary.GetName())
+		util.Equals(t, 3, count(summary.Status.Namespaces))
+		util.Equals(t, http.StatusNotFound, get(s, prefix+"/namespaces/lip6/tenantsummaries/unipi", "admin@edge-net.org", "", nil))
+		util.Equals(t, http.StatusNotFound, get(s, prefix+"/namespaces/lip6/tenantsummaries/lip6/status", "admin@edge-net.org", "", nil))
+	})
+	t.Run("access", func(t *testing.T) {
+		// The headers are only trusted from the front proxy
+		util.Equals(t, http.StatusUnauthorized, get(s, prefix+"/namespaces/lip6/tenantsummaries/lip6", "", "", nil))
+		util.Equals(t, http.StatusForbidden, get(s, prefix+"/namespaces/unipi/tenantsummaries/unipi", "johndoe@edge-net.org", "", nil))
+		util.Equals(t, http.StatusForbidden, get(s, prefix+"/tenantsummaries", "johndoe@edge-net.org", "", nil))
+	})
+	t.Run("list", func(t *testing.T) {
+		list := viewsv1alpha.TenantSummaryList{}
+		util.Equals(t, http.StatusOK, get(s, prefix+"/tenantsummaries", "admin@edge-net.org", "", &list))
+		util.Equals(t, 2, len(list.Items))
+		util.Equals(t, "lip6", list.Items[0].GetName())
+		util.Equals(t, "unipi", list.Items[1].GetName())
+		util.Equals(t, http.StatusOK, get(s, prefix+"/tenantsummaries?labelSelector=edge-net.io/tier%3Dpaying", "admin@edge-net.org", "", &list))
+		util.Equals(t, 1, len(list.Items))
+		util.Equals(t, http.StatusOK, get(s, prefix+"/namespaces/lip6/tenantsummaries", "johndoe@edge-net.org", "", &list))
+		util.Equals(t, 1, len(list.Items))
+		util.Equals(t, http.StatusBadRequest, get(s, prefix+"/tenantsummaries?labelSelector=%3D%3D", "admin@edge-net.org", "", nil))
+		util.Equals(t, http.StatusMethodNotAllowed, get(s, prefix+"/tenantsummaries?watch=true", "admin@edge-net.org", "", nil))
+	})
+	t.Run("table", func(t *testing.T) {
+		table := metav1.Table{}
+		util.Equals(t, http.StatusOK, get(s, prefix+"/tenantsummaries", "admin@edge-net.org", "application/json;as=Table;v=v1;g=meta.k8s.io,application/json", &table))
+		util.Equals(t, 2, len(table.Rows))
+		util.Equals(t, []interface{}{"lip6", true, float64(3), "1/3", "1500m/9", "<none>", float64(3)}, table.Rows[0].Cells[:7])
+		metadata := metav1.PartialObjectMetadata{}
+		util.OK(t, json.Unmarshal(table.Rows[0].Object.Raw, &metadata))
+		util.Equals(t, "lip6", metadata.GetNamespace())
+	})
+}
+
+func TestRequestHeader(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	util.OK(t, err)
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "front-proxy-ca"}, IsCA: true,
+		NotBefore: time.Now(), NotAfter: time.Now().Add(time.Hour), BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	util.OK(t, err)
+	authentication := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: AuthenticationConfig.Name, Namespace: AuthenticationConfig.Namespace},
+		Data: map[string]string{
+			"requestheader-client-ca-file":       string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+			"requestheader-allowed-names":        `["front-proxy-client"]`,
+			"requestheader-username-headers":     `["X-Remote-User"]`,
+			"requestheader-group-headers":        `["X-Remote-Group"]`,
+			"requestheader-extra-headers-prefix": `["X-Remote-Extra-"]`,
+		}}
+	requestHeader, err := LoadRequestHeader(context.TODO(), kubetestclient.NewSimpleClientset(authentication))
+	util.OK(t, err)
+	util.Equals(t, []string{"front-proxy-client"}, requestHeader.AllowedNames)
+	util.Equals(t, []string{"X-Remote-Extra-"}, requestHeader.ExtraHeaderPrefixes)
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("X-Remote-User", "johndoe@edge-net.org")
+	request.Header.Add("X-Remote-Group", "system:authenticated")
+	request.Header.Add("X-Remote-Group", "edgenet:lip6")
+	request.Header.Set("X-Remote-Extra-Scopes%2fconsole", "tenant")
+	_, ok := requestHeader.User(request)
+	util.Equals(t, false, ok)
+
+	request.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "front-proxy-client"}}}}}
+	user, ok := requestHeader.User(request)
+	util.Equals(t, true, ok)
+	util.Equals(t, "johndoe@edge-net.org", user.Username)
+	util.Equals(t, []string{"system:authenticated", "edgenet:lip6"}, user.Groups)
+	util.Equals(t, authorizationv1.ExtraValue{"tenant"}, user.Extra["scopes/console"])
+
+	request.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: "kubelet"}}}}}
+	_, ok = requestHeader.User(request)
+	util.Equals(t, false, ok)
+
+	authentication.Data["requestheader-client-ca-file"] = "invalid"
+	_, err = LoadRequestHeader(context.TODO(), kubetestclient.NewSimpleClientset(authentication))
+	util.Assert(t, err != nil, "invalid authority loaded")
+}